@@ -0,0 +1,60 @@
+// Command replay sends a recorded GraphQL session to a LacyLights test instance.
+//
+// Usage:
+//
+//	replay [-url http://localhost:4000/graphql] [-timing] recording.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/bbernstein/lacylights-go/internal/services/recorder"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:4000/graphql", "GraphQL endpoint of the test instance")
+	timing := flag.Bool("timing", false, "wait between operations as long as the original client did")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] recording.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	content, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to read recording: %v", err)
+	}
+	recording, err := recorder.ParseRecording(content)
+	if err != nil {
+		log.Fatalf("Failed to parse recording: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log.Printf("Replaying %d operations against %s", len(recording.Operations), *url)
+	results, err := recorder.Replay(ctx, *url, recording, recorder.ReplayOptions{PreserveTiming: *timing})
+	failed := 0
+	for _, result := range results {
+		for _, msg := range result.Errors {
+			log.Printf("Operation %d error: %s", result.Seq, msg)
+		}
+		if len(result.Errors) > 0 {
+			failed++
+		}
+	}
+	if err != nil {
+		log.Fatalf("Replay stopped: %v", err)
+	}
+	log.Printf("Replayed %d operations, %d returned errors", len(results), failed)
+}
@@ -0,0 +1,116 @@
+// Command schema shows, checks, applies and reverts the database's schema
+// migrations.
+//
+// Usage:
+//
+//	schema [-target lacylights.db] status
+//	schema check
+//	schema up
+//	schema down VERSION
+//
+// check exits with status 1 when the schema is not the one this build
+// expects, without changing anything. Before running an older release,
+// revert the newer versions with this release's schema down.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+
+	"github.com/bbernstein/lacylights-go/internal/config"
+	"github.com/bbernstein/lacylights-go/internal/database"
+	"github.com/bbernstein/lacylights-go/internal/database/migrations"
+)
+
+func main() {
+	target := flag.String("target", "", "database to migrate (default DATABASE_URL)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] status | check | up | down VERSION\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command := flag.Arg(0)
+	if (command == "down") != (flag.NArg() == 2) || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	cfg := config.Load()
+	if *target == "" {
+		*target = cfg.DatabaseURL
+	}
+
+	db, err := database.Connect(database.Config{
+		Driver:                cfg.DatabaseDriver,
+		URL:                   *target,
+		MaxIdleConn:           1,
+		MaxOpenConn:           1,
+		DisableAutoCheckpoint: !cfg.DatabaseAutoCheckpoint,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	ctx := context.Background()
+	migrator := migrations.New(db)
+	switch command {
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read schema status: %v", err)
+		}
+		fmt.Printf("Schema version %d of %d\n", status.Current, status.Latest)
+		for _, migration := range status.Pending {
+			fmt.Printf("Pending: %s\n", migration)
+		}
+		for _, version := range status.Unknown {
+			fmt.Printf("Unknown to this build: %d\n", version)
+		}
+	case "check":
+		if err := migrator.Check(ctx); err != nil {
+			// Fatal exits with status 1
+			log.Fatalf("Schema check failed: %v", err)
+		}
+		log.Println("✅ Schema is up to date")
+	case "up":
+		applied, err := migrator.Up(ctx)
+		report("Applied", applied)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	case "down":
+		version, err := strconv.Atoi(flag.Arg(1))
+		if err != nil {
+			log.Fatalf("Invalid version %q", flag.Arg(1))
+		}
+		reverted, err := migrator.Down(ctx, version)
+		report("Reverted", reverted)
+		if err != nil {
+			log.Fatalf("Revert failed: %v", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// report lists the migrations applied or reverted.
+func report(verb string, done []migrations.Migration) {
+	for _, migration := range done {
+		fmt.Printf("%s %s\n", verb, migration)
+	}
+	if len(done) == 0 {
+		fmt.Println("Nothing to do")
+	}
+}
@@ -3,13 +3,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -27,18 +30,41 @@ import (
 
 	"github.com/bbernstein/lacylights-go/internal/config"
 	"github.com/bbernstein/lacylights-go/internal/database"
+	"github.com/bbernstein/lacylights-go/internal/database/migrations"
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
 	"github.com/bbernstein/lacylights-go/internal/graphql/resolvers"
+	"github.com/bbernstein/lacylights-go/internal/rest"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"github.com/bbernstein/lacylights-go/internal/services/backup"
+	"github.com/bbernstein/lacylights-go/internal/services/discovery"
 	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/dmxstream"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/health"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/librarysync"
+	"github.com/bbernstein/lacylights-go/internal/services/logging"
 	"github.com/bbernstein/lacylights-go/internal/services/ofl"
+	"github.com/bbernstein/lacylights-go/internal/services/pixelmap"
 	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/presence"
+	"github.com/bbernstein/lacylights-go/internal/services/replication"
+	"github.com/bbernstein/lacylights-go/internal/services/report"
+	"github.com/bbernstein/lacylights-go/internal/services/schemainfo"
+	"github.com/bbernstein/lacylights-go/internal/services/sdnotify"
+	"github.com/bbernstein/lacylights-go/internal/services/stageview"
+	"github.com/bbernstein/lacylights-go/internal/services/tlscert"
 	"github.com/bbernstein/lacylights-go/internal/services/version"
+	"github.com/bbernstein/lacylights-go/internal/services/webui"
 	"gorm.io/gorm"
 )
 
+var log = logging.For(logging.ModuleServer)
+
 // Version information (set at build time)
 var (
 	Version   = "0.1.0"
@@ -50,58 +76,41 @@ func main() {
 	// Set build info for the version package (used by GraphQL resolver)
 	version.SetBuildInfo(Version, GitCommit, BuildTime)
 
-	// Load .env file if present
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
-	}
-
-	// Load configuration
+	// Load .env file if present, then configuration
+	envErr := godotenv.Load()
 	cfg := config.Load()
+	configureLogging(cfg)
+	if envErr != nil {
+		log.Info("No .env file found, using environment variables")
+	}
 
 	// Print startup banner
 	printBanner(cfg)
 
 	// Connect to database
 	db, err := database.Connect(database.Config{
-		URL:         cfg.DatabaseURL,
-		MaxIdleConn: 5,
-		MaxOpenConn: 10,
-		Debug:       cfg.IsDevelopment(),
+		Driver:                cfg.DatabaseDriver,
+		URL:                   cfg.DatabaseURL,
+		MaxIdleConn:           5,
+		MaxOpenConn:           10,
+		Debug:                 cfg.IsDevelopment(),
+		DisableAutoCheckpoint: !cfg.DatabaseAutoCheckpoint,
 	})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 	defer func() { _ = database.Close() }()
 
-	// Auto-migrate database schema
-	log.Println("Running database migrations...")
-	if err := db.AutoMigrate(
-		&models.User{},
-		&models.Project{},
-		&models.ProjectUser{},
-		&models.FixtureDefinition{},
-		&models.ChannelDefinition{},
-		&models.FixtureMode{},
-		&models.ModeChannel{},
-		&models.FixtureInstance{},
-		&models.InstanceChannel{},
-		&models.Scene{},
-		&models.FixtureValue{},
-		&models.CueList{},
-		&models.Cue{},
-		&models.PreviewSession{},
-		&models.Setting{},
-		&models.SceneBoard{},
-		&models.SceneBoardButton{},
-		&models.OFLImportMeta{},
-	); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-	}
-	log.Println("Database migrations complete")
+	// Bring the database schema to this build's version
+	if err := migrateSchema(db, cfg.DatabaseMigrate); err != nil {
+		log.Error("Failed to migrate database", "error", err)
+		os.Exit(1)
+	}
 
 	// Migrate old channelValues to sparse Channels format
 	if err := migrateChannelValuesToSparse(db); err != nil {
-		log.Printf("Warning: sparse channel migration failed: %v", err)
+		log.Warn("sparse channel migration failed", "error", err)
 	}
 
 	// Load Open Fixture Library if enabled and database is empty
@@ -111,53 +120,56 @@ func main() {
 
 		needsImport, err := oflLoader.NeedsImport(context.Background())
 		if err != nil {
-			log.Printf("Warning: failed to check if OFL import needed: %v", err)
+			log.Warn("failed to check if OFL import is needed", "error", err)
 		} else if needsImport {
-			log.Println("📦 No fixture definitions found, importing Open Fixture Library...")
+			log.Info("📦 No fixture definitions found, importing Open Fixture Library")
 			status, err := oflLoader.LoadAll(context.Background())
 			if err != nil {
-				log.Printf("Warning: OFL import failed: %v", err)
-				log.Println("You can still use the app but will need to import fixtures manually")
+				log.Warn("OFL import failed; fixtures will need to be imported manually", "error", err)
 			} else {
-				log.Printf("✅ OFL import complete: %d fixtures imported", status.SuccessfulImports)
+				log.Info("✅ OFL import complete", "fixtures", status.SuccessfulImports)
 			}
 		} else {
 			count, _ := fixtureRepo.CountDefinitions(context.Background())
-			log.Printf("📋 Found %d fixture definitions in database", count)
+			log.Info("📋 Found fixture definitions in database", "definitions", count)
 		}
 	}
 
 	// Create and initialize DMX service
 	dmxService := dmx.NewService(dmx.Config{
-		Enabled:          cfg.ArtNetEnabled,
-		BroadcastAddr:    cfg.ArtNetBroadcast,
-		Port:             cfg.ArtNetPort,
-		RefreshRateHz:    cfg.DMXRefreshRate,
-		IdleRateHz:       cfg.DMXIdleRate,
-		HighRateDuration: cfg.DMXHighRateDuration,
+		Enabled:           cfg.ArtNetEnabled,
+		BroadcastAddr:     cfg.ArtNetBroadcast,
+		Port:              cfg.ArtNetPort,
+		RefreshRateHz:     cfg.DMXRefreshRate,
+		IdleRateHz:        cfg.DMXIdleRate,
+		HighRateDuration:  cfg.DMXHighRateDuration,
+		ArtSync:           cfg.ArtNetSync,
+		KeepAliveInterval: cfg.DMXKeepAlive,
+		Bind:              cfg.ArtNetBind,
 	})
+	holdHandoff(cfg, dmxService)
 	if err := dmxService.Initialize(); err != nil {
-		log.Printf("Warning: DMX service initialization failed: %v", err)
+		log.Warn("DMX service initialization failed", "error", err)
 		// Continue anyway - DMX may be disabled or broadcast address unavailable
 	}
 
 	// Load saved broadcast address from database
 	settingRepo := repositories.NewSettingRepository(db)
-	if savedAddr, err := settingRepo.FindByKey(context.Background(), "artnet_broadcast_address"); err == nil && savedAddr != nil && savedAddr.Value != "" {
-		log.Printf("📡 Loading saved Art-Net broadcast address: %s", savedAddr.Value)
+	if savedAddr, err := settingRepo.FindByKey(context.Background(), dmx.BroadcastAddressSettingKey); err == nil && savedAddr != nil && savedAddr.Value != "" {
+		log.Info("📡 Loading saved Art-Net broadcast address", "address", savedAddr.Value)
 		if err := dmxService.ReloadBroadcastAddress(savedAddr.Value); err != nil {
-			log.Printf("Warning: failed to load saved broadcast address: %v", err)
+			log.Warn("failed to load saved broadcast address", "error", err)
 		}
 	}
 
 	// Create fade engine with configured update rate (or saved rate from database)
 	fadeUpdateRate := cfg.FadeUpdateRateHz
-	if savedRate, err := settingRepo.FindByKey(context.Background(), "fade_update_rate_hz"); err == nil && savedRate != nil && savedRate.Value != "" {
+	if savedRate, err := settingRepo.FindByKey(context.Background(), fade.UpdateRateSettingKey); err == nil && savedRate != nil && savedRate.Value != "" {
 		if rateHz, err := strconv.Atoi(savedRate.Value); err == nil && rateHz > 0 {
-			log.Printf("⚡ Loading saved fade update rate: %d Hz", rateHz)
+			log.Info("⚡ Loading saved fade update rate", "rateHz", rateHz)
 			fadeUpdateRate = rateHz
 		} else {
-			log.Printf("Warning: invalid saved fade update rate: %s", savedRate.Value)
+			log.Warn("invalid saved fade update rate", "value", savedRate.Value)
 		}
 	}
 	fadeEngine := fade.NewEngine(dmxService, fadeUpdateRate)
@@ -166,12 +178,29 @@ func main() {
 	// Create playback service
 	playbackService := playback.NewService(db, dmxService, fadeEngine)
 
+	// Open the playback state journal; the live state it recorded is restored
+	// once the resolver is ready
+	stateJournal := openStateJournal(cfg)
+	if stateJournal != nil {
+		defer func() { _ = stateJournal.Close() }()
+		playbackService.SetJournal(stateJournal)
+	}
+
+	// Report subsystem health; the server is ready once it is listening
+	healthService := health.NewService(Version, GitCommit, BuildTime)
+	healthService.Register("database", true, health.DatabaseCheck(db))
+	healthService.Register("artnet", false, health.ArtNetCheck(dmxService))
+	healthService.Register("fadeEngine", true, health.FadeEngineCheck(fadeEngine))
+	healthService.Register("playback", false, health.PlaybackCheck(playbackService))
+
 	// Create router
 	router := chi.NewRouter()
 
 	// Middleware
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(audit.Middleware)
+	router.Use(presence.Middleware)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	// Note: We intentionally do NOT use middleware.Timeout here because:
@@ -192,6 +221,29 @@ func main() {
 
 	// Create resolver with dependencies
 	resolver := resolvers.NewResolver(db, dmxService, fadeEngine, playbackService, cfg.OFLCachePath)
+	if stateJournal != nil {
+		resolver.SetStateJournal(stateJournal)
+		resumePlayback(cfg, resolver)
+	}
+	if cfg.SnapshotInterval > 0 {
+		resolver.SnapshotService.Start(cfg.SnapshotInterval)
+		log.Info("📸 Project snapshots enabled", "interval", cfg.SnapshotInterval)
+	}
+	enableBackups(cfg, resolver)
+	enableDMXRecordings(cfg, resolver)
+	if cfg.AuditLogRetention > 0 {
+		if pruned, err := resolver.AuditService.Prune(context.Background(), cfg.AuditLogRetention); err != nil {
+			log.Warn("failed to prune audit log", "error", err)
+		} else if pruned > 0 {
+			log.Info("Pruned old audit log entries", "entries", pruned, "olderThan", cfg.AuditLogRetention)
+		}
+	}
+	if cfg.AuthEnabled {
+		enableAuth(cfg, resolver)
+	}
+	router.Use(resolver.AuthService.Middleware)
+	resolver.PresenceService.Start(presence.SweepInterval)
+	startReplication(cfg, resolver)
 
 	// Create GraphQL server
 	srv := handler.New(generated.NewExecutableSchema(generated.Config{
@@ -208,9 +260,22 @@ func main() {
 			WriteBufferSize: 1024,
 		},
 		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc: func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			ctx, _, err := audit.WebsocketInit(ctx, payload)
+			if err != nil {
+				return ctx, nil, err
+			}
+			ctx, _, err = presence.WebsocketInit(ctx, payload)
+			if err != nil {
+				return ctx, nil, err
+			}
+			return resolver.AuthService.WebsocketInit(ctx, payload)
+		},
 	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
+	// Before POST, which would take event-stream requests as plain queries
+	srv.AddTransport(transport.SSE{KeepAlivePingInterval: 10 * time.Second})
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.MultipartForm{})
 
@@ -220,15 +285,49 @@ func main() {
 	srv.Use(extension.AutomaticPersistedQuery{
 		Cache: lru.New[string](100),
 	})
+	srv.Use(resolver.OperationRecorder)
+	srv.Use(resolver.AuditService)
+	srv.Use(resolver.AuthService)
+	srv.Use(resolver.PresenceService)
+	srv.Use(resolver.SchemaInfo)
+	srv.Use(resolver.Loaders)
 
 	// Routes
-	router.Get("/health", healthCheckHandler)
+	router.Get(health.HealthPath, healthService.ServeHealth)
+	router.Get(health.ReadyPath, healthService.ServeReady)
+	router.Get(librarysync.LibraryPath, resolver.LibrarySyncService.ServeLibrary)
+	router.Get(schemainfo.SDLPath, resolver.SchemaInfo.ServeSDL)
+	router.Get(export.ArchivePath, resolver.ArchiveDownloads.ServeArchive)
+	router.Get(report.DownloadPath, resolver.ReportDownloads.ServeReport)
+	router.Get(stageview.ScenePath, resolver.StageViewService.ServeScene)
 	router.Handle("/graphql", srv)
+	router.Mount(rest.BasePath, rest.NewHandler(srv))
+	router.Handle(dmxstream.StreamPath, resolver.DMXStreamService)
+	router.Post(pixelmap.FramePath, resolver.PixelMapService.ServeFrame)
+	router.Get(pixelmap.StreamPath, resolver.PixelMapService.ServeStream)
+	router.Handle(replication.Path, resolver.ReplicationService)
+
+	// Terminate TLS when configured, so tablets can connect over wss://
+	tlsConfig, err := serverTLSConfig(cfg)
+	if err != nil {
+		log.Error("Failed to set up TLS", "error", err)
+		os.Exit(1)
+	}
+	scheme, wsScheme := "http", "ws"
+	if tlsConfig != nil {
+		scheme, wsScheme = "https", "wss"
+	}
+
+	// Web UI at the root, with the Playground beside it when there is one
+	playgroundPath := "/"
+	if serveUI(cfg, router) {
+		playgroundPath = "/playground"
+	}
 
 	// GraphQL Playground (only in development)
 	if cfg.IsDevelopment() {
-		router.Handle("/", playground.Handler("LacyLights GraphQL Playground", "/graphql"))
-		log.Printf("GraphQL Playground available at http://localhost:%s/\n", cfg.Port)
+		router.Handle(playgroundPath, playground.Handler("LacyLights GraphQL Playground", "/graphql"))
+		log.Info("GraphQL Playground available", "url", scheme+"://localhost:"+cfg.Port+playgroundPath)
 	}
 
 	// Create HTTP server
@@ -239,14 +338,37 @@ func main() {
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 120 * time.Second, // Allows for long-running update operations (npm install ~25s)
 		IdleTimeout:  60 * time.Second,
+		TLSConfig:    tlsConfig,
+	}
+
+	// Listen before starting to serve, so readiness means connections are accepted
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		log.Error("Failed to listen", "address", httpServer.Addr, "error", err)
+		os.Exit(1)
 	}
+	dmxService.ReleaseHandoff()
+	healthService.SetReady(true)
+	notifySystemd(sdnotify.Ready)
+	startDiscovery(cfg, resolver, scheme)
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("Server listening on http://localhost:%s\n", cfg.Port)
-		log.Printf("GraphQL endpoint: http://localhost:%s/graphql\n", cfg.Port)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+		log.Info("Server listening",
+			"url", scheme+"://localhost:"+cfg.Port,
+			"graphql", scheme+"://localhost:"+cfg.Port+"/graphql",
+			"rest", scheme+"://localhost:"+cfg.Port+rest.BasePath,
+			"dmxStream", wsScheme+"://localhost:"+cfg.Port+dmxstream.StreamPath)
+		var err error
+		if httpServer.TLSConfig != nil {
+			// Serves HTTP/2 as well as HTTP/1.1
+			err = httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = httpServer.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Error("Server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -254,38 +376,343 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	log.Info("Shutting down server")
+	healthService.SetReady(false)
+	notifySystemd(sdnotify.Stopping)
+	shutdownOutput := shutdownMode(cfg)
+	if shutdownOutput == dmx.ShutdownFade {
+		fadeOutput(dmxService, cfg.ShutdownFadeTime)
+	}
 
 	// Cleanup services in reverse order
+	if resolver.DiscoveryService != nil {
+		resolver.DiscoveryService.Cleanup()
+	}
+	resolver.ReplicationService.Cleanup()
+	resolver.DMXStreamService.Cleanup()
+	resolver.PixelMapService.Cleanup()
+	resolver.PresenceService.Cleanup()
+	resolver.SnapshotService.Cleanup()
+	if resolver.BackupService != nil {
+		resolver.BackupService.Cleanup()
+	}
+	if resolver.DMXRecorder != nil {
+		resolver.DMXRecorder.Cleanup()
+	}
+	resolver.ShowTimerService.Cleanup()
+	resolver.StandbyService.Cleanup()
+	resolver.InputService.Cleanup()
+	resolver.TimecodeService.Cleanup()
+	resolver.AudioService.Cleanup()
+	resolver.SchedulerService.Cleanup()
 	playbackService.Cleanup()
 	fadeEngine.Stop()
-	dmxService.Stop()
+	stopOutput(cfg, shutdownOutput, dmxService)
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatalf("Server shutdown error: %v", err)
+		log.Error("Server shutdown error", "error", err)
+		os.Exit(1)
 	}
 
-	log.Println("Server stopped")
+	log.Info("Server stopped")
 }
 
-// healthCheckHandler returns the server health status.
-func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+// openStateJournal opens the playback state journal, or returns nil if it is
+// disabled or cannot be opened.
+func openStateJournal(cfg *config.Config) *journal.Journal {
+	if cfg.StateJournalPath == "" {
+		return nil
+	}
+	mode, err := journal.ParseSyncMode(cfg.StateJournalSync)
+	if err != nil {
+		log.Warn("invalid journal sync mode", "error", err, "using", journal.SyncAlways)
+		mode = journal.SyncAlways
+	}
+	stateJournal, err := journal.Open(cfg.StateJournalPath, mode, cfg.StateJournalSyncInterval)
+	if err != nil {
+		log.Warn("playback state journal disabled", "error", err)
+		return nil
+	}
+	log.Info("📓 Playback state journal opened", "path", cfg.StateJournalPath, "sync", mode)
+	return stateJournal
+}
+
+// resumePlayback restores the live state the state journal recorded, unless
+// PLAYBACK_RESUME leaves that to the resumePlayback mutation.
+func resumePlayback(cfg *config.Config, resolver *resolvers.Resolver) {
+	switch cfg.PlaybackResume {
+	case "auto":
+	case "manual":
+		log.Info("⏸️ Playback state kept for the resumePlayback mutation")
+		return
+	default:
+		log.Warn("invalid playback resume mode; resuming", "value", cfg.PlaybackResume)
+	}
+	result, err := resolver.ResumePlayback(context.Background())
+	if err != nil {
+		log.Warn("failed to restore playback state", "error", err)
+		return
+	}
+	if result.CueLists > 0 || result.Scenes > 0 {
+		log.Info("🔁 Restored playback from the state journal", "cueLists", result.CueLists, "scenes", result.Scenes)
+	}
+}
+
+// holdHandoff holds the frame a restarting server handed off, so fixtures
+// keep their look until startup completes.
+func holdHandoff(cfg *config.Config, dmxService *dmx.Service) {
+	if cfg.DMXHandoffPath == "" {
+		return
+	}
+	frames, err := dmx.LoadHandoff(cfg.DMXHandoffPath)
+	if err != nil {
+		log.Warn("failed to load DMX handoff", "error", err)
+		return
+	}
+	if frames != nil {
+		dmxService.HoldHandoff(frames)
+		log.Info("🤝 Holding the previous server's output until startup completes", "universes", len(frames))
+	}
+}
+
+// shutdownMode returns the configured shutdown output, falling back to a
+// blackout.
+func shutdownMode(cfg *config.Config) dmx.ShutdownMode {
+	mode, err := dmx.ParseShutdownMode(cfg.ShutdownOutput)
+	if err != nil {
+		log.Warn("invalid shutdown output; blacking out", "error", err)
+		return dmx.ShutdownBlackout
+	}
+	return mode
+}
+
+// fadeOutput fades the intensity channels to black and waits for the fade,
+// asking systemd to allow for it.
+func fadeOutput(dmxService *dmx.Service, fadeTime time.Duration) {
+	fadeTime = min(max(fadeTime, 0), dmx.MaxBlackoutFade)
+	notifySystemd(sdnotify.ExtendTimeout(fadeTime+30*time.Second), sdnotify.Status("Fading out"))
+	if _, err := dmxService.Blackout(fadeTime); err != nil {
+		log.Warn("failed to fade out", "error", err)
+		return
+	}
+	log.Info("Fading output to black", "fade", fadeTime)
+	// Allow the frame that reaches black to go out
+	time.Sleep(fadeTime + 100*time.Millisecond)
+}
 
-	response := fmt.Sprintf(`{
-  "status": "ok",
-  "timestamp": "%s",
-  "version": "%s",
-  "gitCommit": "%s",
-  "buildTime": "%s"
-}`, time.Now().UTC().Format(time.RFC3339), Version, GitCommit, BuildTime)
+// stopOutput stops DMX output. A hold leaves the last frame on the rig and
+// hands it to the next server; otherwise every universe goes to zero.
+func stopOutput(cfg *config.Config, mode dmx.ShutdownMode, dmxService *dmx.Service) {
+	if mode != dmx.ShutdownHold {
+		dmxService.Stop()
+		return
+	}
+	frames := dmxService.StopHolding()
+	if cfg.DMXHandoffPath == "" || frames == nil {
+		return
+	}
+	if err := dmx.SaveHandoff(cfg.DMXHandoffPath, frames); err != nil {
+		log.Warn("failed to save DMX handoff", "error", err)
+	} else {
+		log.Info("🤝 Saved the last frame for the next server", "path", cfg.DMXHandoffPath)
+	}
+}
 
-	_, _ = w.Write([]byte(response))
+// notifySystemd reports state to systemd, when it started the server.
+func notifySystemd(states ...string) {
+	if _, err := sdnotify.Notify(states...); err != nil {
+		log.Warn("failed to notify systemd", "error", err)
+	}
+}
+
+// configureLogging applies the configured log output, levels and buffer,
+// falling back to the defaults for invalid values.
+func configureLogging(cfg *config.Config) {
+	var problems []error
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	levels, err := logging.ParseLevels(cfg.LogLevels)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	format, err := logging.ParseFormat(cfg.LogFormat)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	logging.Configure(logging.Config{
+		Level:      level,
+		Levels:     levels,
+		Format:     format,
+		BufferSize: cfg.LogBufferSize,
+	})
+	for _, problem := range problems {
+		log.Warn("invalid logging configuration; using the default", "error", problem)
+	}
+}
+
+// enableAuth turns on sign-in and role enforcement, creating the configured
+// administrator on first run.
+func enableAuth(cfg *config.Config, resolver *resolvers.Resolver) {
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		log.Warn("JWT_SECRET is not set; sessions will not survive a restart")
+		secret = auth.RandomSecret()
+	}
+	resolver.AuthService.Enable(secret, cfg.AuthTokenTTL)
+	log.Info("🔒 Authentication enabled", "sessionTTL", cfg.AuthTokenTTL)
+
+	if cfg.AuthAdminEmail == "" {
+		return
+	}
+	created, err := resolver.AuthService.Bootstrap(context.Background(), cfg.AuthAdminEmail, cfg.AuthAdminPassword)
+	if err != nil {
+		log.Warn("failed to create administrator", "email", cfg.AuthAdminEmail, "error", err)
+	} else if created {
+		log.Info("Created administrator", "email", cfg.AuthAdminEmail)
+	}
+}
+
+// enableBackups keeps backups in the configured bucket or directory, and
+// schedules them when an interval is set.
+func enableBackups(cfg *config.Config, resolver *resolvers.Resolver) {
+	store, err := backupStore(cfg)
+	if err != nil {
+		log.Warn("invalid backup configuration; backups are disabled", "error", err)
+		return
+	}
+	resolver.EnableBackups(store, cfg.BackupRetention)
+	if cfg.BackupInterval > 0 {
+		resolver.BackupService.Start(cfg.BackupInterval)
+		log.Info("💾 Scheduled backups enabled", "interval", cfg.BackupInterval, "location", store.Location(), "retention", cfg.BackupRetention)
+	}
+}
+
+// enableDMXRecordings keeps DMX output recordings in the configured
+// directory, and loops the configured recording for an unattended
+// installation.
+func enableDMXRecordings(cfg *config.Config, resolver *resolvers.Resolver) {
+	resolver.EnableDMXRecordings(cfg.DMXRecordingDir)
+	if cfg.DMXReplay == "" {
+		return
+	}
+	if _, err := resolver.DMXRecorder.Replay(cfg.DMXReplay, true); err != nil {
+		log.Warn("failed to replay DMX recording at startup", "recording", cfg.DMXReplay, "error", err)
+	}
+}
+
+// backupStore returns the S3 bucket when one is configured, and the backup
+// directory otherwise.
+func backupStore(cfg *config.Config) (backup.Store, error) {
+	if cfg.BackupS3Bucket == "" {
+		return backup.NewDirStore(cfg.BackupDir), nil
+	}
+	return backup.NewS3Store(backup.S3Config{
+		Bucket:       cfg.BackupS3Bucket,
+		Prefix:       cfg.BackupS3Prefix,
+		Region:       cfg.BackupS3Region,
+		Endpoint:     cfg.BackupS3Endpoint,
+		AccessKey:    cfg.BackupS3AccessKey,
+		SecretKey:    cfg.BackupS3SecretKey,
+		SessionToken: cfg.BackupS3SessionToken,
+	})
+}
+
+// startReplication starts the configured tracking backup role.
+func startReplication(cfg *config.Config, resolver *resolvers.Resolver) {
+	role, err := replication.ParseRole(cfg.ReplicationRole)
+	if err != nil {
+		log.Warn("invalid replication role; running standalone", "error", err)
+		return
+	}
+	if role == replication.RoleStandalone {
+		return
+	}
+	err = resolver.StartReplication(replication.Config{
+		Role:              role,
+		PrimaryURL:        cfg.ReplicationPrimaryURL,
+		Token:             cfg.ReplicationToken,
+		HeartbeatInterval: cfg.ReplicationHeartbeat,
+		FailoverTimeout:   cfg.ReplicationFailoverTimeout,
+	})
+	if err != nil {
+		log.Warn("failed to start replication; running standalone", "error", err)
+		return
+	}
+	if role == replication.RoleBackup {
+		log.Info("🔁 Tracking the primary; output is passive until it goes silent", "primary", cfg.ReplicationPrimaryURL, "failoverTimeout", cfg.ReplicationFailoverTimeout)
+	} else {
+		log.Info("🔁 Replicating live state to a tracking backup", "path", replication.Path)
+	}
+}
+
+// startDiscovery advertises the server on the local network, with the
+// scheme it serves, unless mDNS is disabled.
+func startDiscovery(cfg *config.Config, resolver *resolvers.Resolver, scheme string) {
+	if !cfg.MDNSEnabled {
+		return
+	}
+	port, err := strconv.Atoi(cfg.Port)
+	if err != nil {
+		log.Warn("invalid port; not advertising by mDNS", "port", cfg.Port)
+		return
+	}
+	if err := resolver.StartDiscovery(discovery.Config{Name: cfg.MDNSName, Port: port, Scheme: scheme}); err != nil {
+		log.Warn("failed to start mDNS advertisement", "error", err)
+	}
+}
+
+// serveUI serves the web UI from UI_DIR or the embedded build for every
+// path no other route takes, and reports whether there is one to serve.
+func serveUI(cfg *config.Config, router chi.Router) bool {
+	if !cfg.UIEnabled {
+		return false
+	}
+	files, err := webui.Open(cfg.UIDir)
+	if errors.Is(err, webui.ErrNotBuilt) {
+		log.Info("No web UI build embedded; serving the API only")
+		return false
+	}
+	if err != nil {
+		log.Warn("web UI not served", "error", err)
+		return false
+	}
+	router.Handle("/*", webui.NewHandler(files))
+	source := cfg.UIDir
+	if source == "" {
+		source = "embedded"
+	}
+	log.Info("🖥️ Serving web UI", "source", source)
+	return true
+}
+
+// serverTLSConfig returns the configured TLS termination, or nil to serve
+// plain HTTP.
+func serverTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	mode, err := tlscert.ParseMode(cfg.TLSMode)
+	if err != nil {
+		return nil, err
+	}
+	var domains []string
+	for _, domain := range strings.Split(cfg.TLSACMEDomains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return tlscert.ServerConfig(tlscert.Config{
+		Mode:             mode,
+		CertFile:         cfg.TLSCertFile,
+		KeyFile:          cfg.TLSKeyFile,
+		ACMEDomains:      domains,
+		ACMEEmail:        cfg.TLSACMEEmail,
+		ACMECacheDir:     cfg.TLSACMECacheDir,
+		ACMEDirectoryURL: cfg.TLSACMEDirectoryURL,
+	})
 }
 
 // printBanner prints the startup banner.
@@ -298,12 +725,42 @@ func printBanner(cfg *config.Config) {
 	fmt.Println("============================================")
 	fmt.Printf("  Environment: %s\n", cfg.Env)
 	fmt.Printf("  Port:        %s\n", cfg.Port)
-	fmt.Printf("  Database:    %s\n", cfg.DatabaseURL)
+	fmt.Printf("  TLS:         %s\n", cfg.TLSMode)
+	fmt.Printf("  Database:    %s (%s)\n", cfg.DatabaseURL, cfg.DatabaseDriver)
 	fmt.Printf("  Art-Net:     %v\n", cfg.ArtNetEnabled)
 	fmt.Printf("  OFL Import:  %v\n", cfg.OFLImportEnabled)
+	fmt.Printf("  Auth:        %v\n", cfg.AuthEnabled)
+	fmt.Printf("  Replication: %s\n", cfg.ReplicationRole)
 	fmt.Println("============================================")
 }
 
+// migrateSchema applies the pending schema migrations or, in check mode,
+// only confirms that there are none.
+func migrateSchema(db *gorm.DB, mode string) error {
+	migrator := migrations.New(db)
+	switch mode {
+	case "auto":
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			return err
+		}
+		log.Info("Database migrations complete", "applied", len(applied))
+		return nil
+	case "check":
+		err := migrator.Check(context.Background())
+		if errors.Is(err, migrations.ErrPending) {
+			err = fmt.Errorf("%w; apply them with cmd/schema up or DATABASE_MIGRATE=auto", err)
+		}
+		if err != nil {
+			return err
+		}
+		log.Info("Database schema is up to date")
+		return nil
+	default:
+		return fmt.Errorf("unknown DATABASE_MIGRATE %q: use auto or check", mode)
+	}
+}
+
 // migrateChannelValuesToSparse migrates old channelValues arrays to the new sparse Channels format.
 // This is a one-time migration that runs on startup to convert existing data.
 // Uses GORM's migrator to check column existence (database-agnostic), then raw SQL for the
@@ -337,14 +794,14 @@ func migrateChannelValuesToSparse(db *gorm.DB) error {
 		return nil // Nothing to migrate
 	}
 
-	log.Printf("🔄 Migrating %d fixture values from channelValues to sparse Channels format...", len(oldValues))
+	log.Info("🔄 Migrating fixture values from channelValues to sparse Channels format", "fixtureValues", len(oldValues))
 
 	migratedCount := 0
 	for _, fv := range oldValues {
 		// Parse old channelValues array
 		var values []int
 		if err := json.Unmarshal([]byte(fv.ChannelValues), &values); err != nil {
-			log.Printf("  Warning: failed to parse channelValues for fixture value %s: %v", fv.ID, err)
+			log.Warn("failed to parse channelValues", "fixtureValue", fv.ID, "error", err)
 			continue
 		}
 
@@ -364,18 +821,18 @@ func migrateChannelValuesToSparse(db *gorm.DB) error {
 		// Serialize to JSON
 		channelsJSON, err := json.Marshal(channels)
 		if err != nil {
-			log.Printf("  Warning: failed to serialize channels for fixture value %s: %v", fv.ID, err)
+			log.Warn("failed to serialize channels", "fixtureValue", fv.ID, "error", err)
 			continue
 		}
 
 		// Update the record using raw SQL
 		if err := db.Exec("UPDATE fixture_values SET channels = ? WHERE id = ?", string(channelsJSON), fv.ID).Error; err != nil {
-			log.Printf("  Warning: failed to update fixture value %s: %v", fv.ID, err)
+			log.Warn("failed to update fixture value", "fixtureValue", fv.ID, "error", err)
 			continue
 		}
 		migratedCount++
 	}
 
-	log.Printf("✅ Migrated %d fixture values to sparse Channels format", migratedCount)
+	log.Info("✅ Migrated fixture values to sparse Channels format", "fixtureValues", migratedCount)
 	return nil
 }
@@ -2,61 +2,24 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bbernstein/lacylights-go/internal/config"
+	"github.com/bbernstein/lacylights-go/internal/database/migrations"
 	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/go-chi/chi/v5"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func TestHealthCheckHandler(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "/health", nil)
-	w := httptest.NewRecorder()
-
-	healthCheckHandler(w, req)
-
-	resp := w.Result()
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", resp.StatusCode)
-	}
-
-	contentType := resp.Header.Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Expected Content-Type application/json, got %s", contentType)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		t.Fatalf("Failed to read response body: %v", err)
-	}
-
-	bodyStr := string(body)
-	if !strings.Contains(bodyStr, `"status": "ok"`) {
-		t.Error("Expected status ok in response")
-	}
-	if !strings.Contains(bodyStr, `"version":`) {
-		t.Error("Expected version in response")
-	}
-	if !strings.Contains(bodyStr, `"timestamp":`) {
-		t.Error("Expected timestamp in response")
-	}
-	if !strings.Contains(bodyStr, `"gitCommit":`) {
-		t.Error("Expected gitCommit in response")
-	}
-	if !strings.Contains(bodyStr, `"buildTime":`) {
-		t.Error("Expected buildTime in response")
-	}
-}
-
 func TestPrintBanner(t *testing.T) {
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -328,3 +291,109 @@ func TestChannelValueModel(t *testing.T) {
 		t.Errorf("Expected Value 128, got %d", cv.Value)
 	}
 }
+
+func TestMigrateSchema(t *testing.T) {
+	db := setupTestDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := migrateSchema(db, "check"); !errors.Is(err, migrations.ErrPending) {
+		t.Errorf("Expected check to refuse pending migrations, got: %v", err)
+	}
+	if err := migrateSchema(db, "auto"); err != nil {
+		t.Fatalf("Expected auto to apply migrations, got: %v", err)
+	}
+	if err := migrateSchema(db, "check"); err != nil {
+		t.Errorf("Expected check to pass once migrated, got: %v", err)
+	}
+	if err := migrateSchema(db, "sometimes"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+
+	// The old channelValues column survives for the sparse migration
+	if !db.Migrator().HasColumn("fixture_values", "channelValues") {
+		t.Error("Expected migrations to keep the channelValues column")
+	}
+}
+
+func TestBackupStore(t *testing.T) {
+	store, err := backupStore(&config.Config{BackupDir: "/var/backups/lacylights"})
+	if err != nil {
+		t.Fatalf("backupStore() error: %v", err)
+	}
+	if store.Location() != "/var/backups/lacylights" {
+		t.Errorf("Expected the backup directory, got %s", store.Location())
+	}
+
+	store, err = backupStore(&config.Config{
+		BackupDir:         "/var/backups/lacylights",
+		BackupS3Bucket:    "shows",
+		BackupS3Prefix:    "theatre/",
+		BackupS3AccessKey: "AKID",
+		BackupS3SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("backupStore() error: %v", err)
+	}
+	if store.Location() != "s3://shows/theatre/" {
+		t.Errorf("Expected the bucket, got %s", store.Location())
+	}
+
+	if _, err := backupStore(&config.Config{BackupS3Bucket: "shows"}); err == nil {
+		t.Error("Expected a bucket without credentials to be rejected")
+	}
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	tlsConfig, err := serverTLSConfig(&config.Config{TLSMode: "off"})
+	if err != nil || tlsConfig != nil {
+		t.Errorf("serverTLSConfig(off) = %v, %v; want plain HTTP", tlsConfig, err)
+	}
+
+	dir := t.TempDir()
+	tlsConfig, err = serverTLSConfig(&config.Config{
+		TLSMode:     "self-signed",
+		TLSCertFile: filepath.Join(dir, "cert.pem"),
+		TLSKeyFile:  filepath.Join(dir, "key.pem"),
+	})
+	if err != nil || tlsConfig == nil || len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("serverTLSConfig(self-signed) = %v, %v", tlsConfig, err)
+	}
+
+	if _, err := serverTLSConfig(&config.Config{TLSMode: "acme", TLSACMEDomains: " , "}); err == nil {
+		t.Error("Expected ACME without domains to be rejected")
+	}
+	if _, err := serverTLSConfig(&config.Config{TLSMode: "https"}); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestServeUI(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>app</html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	router.Get("/health", func(w http.ResponseWriter, r *http.Request) { _, _ = io.WriteString(w, "ok") })
+	if serveUI(&config.Config{UIEnabled: false, UIDir: dir}, router) {
+		t.Error("Expected no web UI when disabled")
+	}
+	if serveUI(&config.Config{UIEnabled: true, UIDir: filepath.Join(dir, "missing")}, router) {
+		t.Error("Expected no web UI from a directory without a build")
+	}
+	if !serveUI(&config.Config{UIEnabled: true, UIDir: dir}, router) {
+		t.Fatal("Expected the web UI served from its directory")
+	}
+
+	for path, want := range map[string]string{"/": "<html>app</html>", "/scenes/abc": "<html>app</html>", "/health": "ok"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", path, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
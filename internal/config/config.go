@@ -3,24 +3,52 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 )
 
+// PiInstallDir is where Raspberry Pi installs put LacyLights.
+const PiInstallDir = "/opt/lacylights"
+
+// piInstallDir is PiInstallDir, replaced in tests.
+var piInstallDir = PiInstallDir
+
 // Config holds all configuration values for the server.
 type Config struct {
 	// Server configuration
 	Port string
 	Env  string
 
+	// TLS configuration
+	TLSMode             string // off, file, self-signed, or acme
+	TLSCertFile         string // Certificate for file, or where self-signed keeps the one it generates
+	TLSKeyFile          string
+	TLSACMEDomains      string // Comma-separated names certificates are requested for
+	TLSACMEEmail        string
+	TLSACMECacheDir     string // Keeps the ACME account key and issued certificates
+	TLSACMEDirectoryURL string // Authority's directory; Let's Encrypt when empty
+
+	// Web UI configuration
+	UIEnabled bool   // Serve the web UI at the root
+	UIDir     string // Directory of the web UI build; the embedded build when empty
+
+	// Data directory: where the database and other state files go unless
+	// configured otherwise. Empty for the working directory.
+	DataDir string
+
 	// Database configuration
-	DatabaseURL string
+	DatabaseDriver         string // Only sqlite is supported
+	DatabaseURL            string // SQLite file path, optionally prefixed with file: or sqlite:
+	DatabaseAutoCheckpoint bool   // Disable when Litestream checkpoints the WAL
+	DatabaseMigrate        string // auto applies pending migrations on startup; check refuses to start with any
 
 	// DMX configuration
 	DMXUniverseCount    int
 	DMXRefreshRate      int           // Hz (active)
 	DMXIdleRate         int           // Hz (idle)
 	DMXHighRateDuration time.Duration // Duration to stay in high rate after changes
+	DMXKeepAlive        time.Duration // Period between resends of unchanged universes
 
 	// Fade engine configuration
 	FadeUpdateRateHz int // Hz (default 60, for smooth 60fps fades)
@@ -29,6 +57,8 @@ type Config struct {
 	ArtNetEnabled   bool
 	ArtNetPort      int
 	ArtNetBroadcast string
+	ArtNetSync      bool   // Send ArtSync after each frame; a saved setting overrides this
+	ArtNetBind      string // Interface name or IPv4 address output is sent from; a saved setting overrides this
 
 	// Timing monitoring
 	DMXDriftThreshold int // Only warn for drifts > threshold (ms)
@@ -43,23 +73,101 @@ type Config struct {
 	// OFL (Open Fixture Library) import configuration
 	OFLImportEnabled bool   // Enable automatic OFL import on startup
 	OFLCachePath     string // Path to cache downloaded OFL data
+
+	// Playback state journal configuration
+	StateJournalPath         string        // Empty disables the journal
+	StateJournalSync         string        // always, interval, or never
+	StateJournalSyncInterval time.Duration // Flush period for interval sync
+	PlaybackResume           string        // auto restores the journaled state at startup; manual waits for resumePlayback
+
+	// Project snapshot configuration
+	SnapshotInterval time.Duration // Period between scheduled snapshots; zero disables them
+
+	// Backup configuration
+	BackupDir            string        // Directory backups are written to when no bucket is set
+	BackupInterval       time.Duration // Period between scheduled backups; zero disables them
+	BackupRetention      int           // Newest backups kept; zero keeps them all
+	BackupS3Bucket       string        // S3 bucket backups are written to instead of BackupDir
+	BackupS3Prefix       string        // Key prefix of backups in the bucket
+	BackupS3Region       string
+	BackupS3Endpoint     string // S3-compatible endpoint; AWS for the region when empty
+	BackupS3AccessKey    string
+	BackupS3SecretKey    string
+	BackupS3SessionToken string
+
+	// Audit log configuration
+	AuditLogRetention time.Duration // Age at which entries are pruned on startup; zero keeps them all
+
+	// Authentication configuration
+	AuthEnabled       bool          // Require signing in and enforce project roles
+	JWTSecret         string        // Token signing secret; a random one is used if empty
+	AuthTokenTTL      time.Duration // Lifetime of session tokens
+	AuthAdminEmail    string        // Administrator created on startup when there are no users
+	AuthAdminPassword string
+
+	// Logging configuration
+	LogLevel      string // debug, info, warn, or error
+	LogLevels     string // Per-module levels, such as "dmx=debug,fade=warn"
+	LogFormat     string // text or json
+	LogBufferSize int    // Recent entries kept for the diagnostics panel
+
+	// Tracking backup configuration
+	ReplicationRole            string        // standalone, primary, or backup
+	ReplicationPrimaryURL      string        // Primary's replication WebSocket URL, for a backup
+	ReplicationToken           string        // Shared secret the backup presents to the primary
+	ReplicationHeartbeat       time.Duration // Period between heartbeats
+	ReplicationFailoverTimeout time.Duration // Silence after which a backup takes over
+
+	// Local network advertisement
+	MDNSEnabled bool   // Advertise by mDNS and discover other servers
+	MDNSName    string // Instance name shown to clients; empty uses the hostname
+
+	// Shutdown output configuration
+	ShutdownOutput   string        // blackout, fade, or hold
+	ShutdownFadeTime time.Duration // Fade to black time for fade
+	DMXHandoffPath   string        // Last frame held for the next server, for hold; empty disables it
+
+	// DMX output recording configuration
+	DMXRecordingDir string // Directory DMX output recordings are kept in
+	DMXReplay       string // Recording replayed in a loop from startup; empty replays none
 }
 
 // Load loads configuration from environment variables with sensible defaults.
 func Load() *Config {
+	dataDir := getEnv("DATA_DIR", defaultDataDir())
 	return &Config{
 		// Server
 		Port: getEnv("PORT", "4000"),
 		Env:  getEnv("ENV", "development"),
 
+		// Data directory
+		DataDir: dataDir,
+
+		// TLS
+		TLSMode:             getEnv("TLS_MODE", "off"),
+		TLSCertFile:         getEnv("TLS_CERT_FILE", dataPath(dataDir, "tls/cert.pem")),
+		TLSKeyFile:          getEnv("TLS_KEY_FILE", dataPath(dataDir, "tls/key.pem")),
+		TLSACMEDomains:      getEnv("TLS_ACME_DOMAINS", ""),
+		TLSACMEEmail:        getEnv("TLS_ACME_EMAIL", ""),
+		TLSACMECacheDir:     getEnv("TLS_ACME_CACHE_DIR", dataPath(dataDir, "tls/acme")),
+		TLSACMEDirectoryURL: getEnv("TLS_ACME_DIRECTORY_URL", ""),
+
+		// Web UI
+		UIEnabled: getEnvBool("UI_ENABLED", true),
+		UIDir:     getEnv("UI_DIR", ""),
+
 		// Database
-		DatabaseURL: getEnv("DATABASE_URL", "file:./dev.db"),
+		DatabaseDriver:         getEnv("DATABASE_DRIVER", "sqlite"),
+		DatabaseURL:            getEnv("DATABASE_URL", defaultDatabaseURL(dataDir)),
+		DatabaseAutoCheckpoint: getEnvBool("DATABASE_AUTOCHECKPOINT", true),
+		DatabaseMigrate:        getEnv("DATABASE_MIGRATE", "auto"),
 
 		// DMX
 		DMXUniverseCount:    getEnvInt("DMX_UNIVERSE_COUNT", 4),
 		DMXRefreshRate:      getEnvInt("DMX_REFRESH_RATE", 60), // Match fade engine default
 		DMXIdleRate:         getEnvInt("DMX_IDLE_RATE", 1),
 		DMXHighRateDuration: time.Duration(getEnvInt("DMX_HIGH_RATE_DURATION", 2000)) * time.Millisecond,
+		DMXKeepAlive:        time.Duration(getEnvInt("DMX_KEEPALIVE_MS", 1000)) * time.Millisecond,
 
 		// Fade engine
 		FadeUpdateRateHz: getEnvInt("FADE_UPDATE_RATE", 60),
@@ -68,6 +176,8 @@ func Load() *Config {
 		ArtNetEnabled:   getEnvBool("ARTNET_ENABLED", true),
 		ArtNetPort:      getEnvInt("ARTNET_PORT", 6454),
 		ArtNetBroadcast: getEnv("ARTNET_BROADCAST", ""),
+		ArtNetSync:      getEnvBool("ARTNET_SYNC", false),
+		ArtNetBind:      getEnv("ARTNET_BIND", ""),
 
 		// Timing monitoring
 		DMXDriftThreshold: getEnvInt("DMX_DRIFT_THRESHOLD", 50),
@@ -81,8 +191,91 @@ func Load() *Config {
 
 		// OFL Import
 		OFLImportEnabled: getEnvBool("OFL_IMPORT_ENABLED", true),
-		OFLCachePath:     getEnv("OFL_CACHE_PATH", "./.ofl-cache"),
+		OFLCachePath:     getEnv("OFL_CACHE_PATH", dataPath(dataDir, ".ofl-cache")),
+
+		// Playback state journal
+		StateJournalPath:         getEnv("STATE_JOURNAL_PATH", dataPath(dataDir, "playback-state.journal")),
+		StateJournalSync:         getEnv("STATE_JOURNAL_SYNC", "always"),
+		StateJournalSyncInterval: time.Duration(getEnvInt("STATE_JOURNAL_SYNC_INTERVAL", 200)) * time.Millisecond,
+		PlaybackResume:           getEnv("PLAYBACK_RESUME", "auto"),
+
+		// Project snapshots
+		SnapshotInterval: time.Duration(getEnvInt("SNAPSHOT_INTERVAL", 30)) * time.Minute,
+
+		// Backups
+		BackupDir:            getEnv("BACKUP_DIR", dataPath(dataDir, "backups")),
+		BackupInterval:       time.Duration(getEnvInt("BACKUP_INTERVAL_HOURS", 24)) * time.Hour,
+		BackupRetention:      getEnvInt("BACKUP_RETENTION", 14),
+		BackupS3Bucket:       getEnv("BACKUP_S3_BUCKET", ""),
+		BackupS3Prefix:       getEnv("BACKUP_S3_PREFIX", "lacylights/"),
+		BackupS3Region:       getEnv("BACKUP_S3_REGION", "us-east-1"),
+		BackupS3Endpoint:     getEnv("BACKUP_S3_ENDPOINT", ""),
+		BackupS3AccessKey:    getEnv("AWS_ACCESS_KEY_ID", ""),
+		BackupS3SecretKey:    getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		BackupS3SessionToken: getEnv("AWS_SESSION_TOKEN", ""),
+
+		// Audit log
+		AuditLogRetention: time.Duration(getEnvInt("AUDIT_LOG_RETENTION_DAYS", 90)) * 24 * time.Hour,
+
+		// Authentication
+		AuthEnabled:       getEnvBool("AUTH_ENABLED", false),
+		JWTSecret:         getEnv("JWT_SECRET", ""),
+		AuthTokenTTL:      time.Duration(getEnvInt("AUTH_TOKEN_TTL_HOURS", 12)) * time.Hour,
+		AuthAdminEmail:    getEnv("AUTH_ADMIN_EMAIL", ""),
+		AuthAdminPassword: getEnv("AUTH_ADMIN_PASSWORD", ""),
+
+		// Logging
+		LogLevel:      getEnv("LOG_LEVEL", "info"),
+		LogLevels:     getEnv("LOG_LEVELS", ""),
+		LogFormat:     getEnv("LOG_FORMAT", "text"),
+		LogBufferSize: getEnvInt("LOG_BUFFER_SIZE", 1000),
+
+		// Tracking backup
+		ReplicationRole:            getEnv("REPLICATION_ROLE", "standalone"),
+		ReplicationPrimaryURL:      getEnv("REPLICATION_PRIMARY_URL", ""),
+		ReplicationToken:           getEnv("REPLICATION_TOKEN", ""),
+		ReplicationHeartbeat:       time.Duration(getEnvInt("REPLICATION_HEARTBEAT_MS", 1000)) * time.Millisecond,
+		ReplicationFailoverTimeout: time.Duration(getEnvInt("REPLICATION_FAILOVER_MS", 3000)) * time.Millisecond,
+
+		// Local network advertisement
+		MDNSEnabled: getEnvBool("MDNS_ENABLED", true),
+		MDNSName:    getEnv("MDNS_NAME", ""),
+
+		// Shutdown output
+		ShutdownOutput:   getEnv("SHUTDOWN_OUTPUT", "blackout"),
+		ShutdownFadeTime: time.Duration(getEnvInt("SHUTDOWN_FADE_MS", 3000)) * time.Millisecond,
+		DMXHandoffPath:   getEnv("DMX_HANDOFF_PATH", dataPath(dataDir, "dmx-handoff.json")),
+
+		// DMX output recording
+		DMXRecordingDir: getEnv("DMX_RECORDING_DIR", dataPath(dataDir, "recordings")),
+		DMXReplay:       getEnv("DMX_REPLAY", ""),
+	}
+}
+
+// defaultDataDir returns the data directory of a Raspberry Pi install, or
+// empty elsewhere.
+func defaultDataDir() string {
+	if info, err := os.Stat(piInstallDir); err == nil && info.IsDir() {
+		return filepath.Join(piInstallDir, "data")
+	}
+	return ""
+}
+
+// defaultDatabaseURL returns the database in a data directory, or the
+// development database in the working directory.
+func defaultDatabaseURL(dataDir string) string {
+	if dataDir == "" {
+		return "file:./dev.db"
+	}
+	return "file:" + filepath.Join(dataDir, "lacylights.db")
+}
+
+// dataPath returns where a state file goes in a data directory.
+func dataPath(dataDir, name string) string {
+	if dataDir == "" {
+		return "./" + name
 	}
+	return filepath.Join(dataDir, name)
 }
 
 // IsDevelopment returns true if running in development mode.
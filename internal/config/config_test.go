@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -49,14 +50,32 @@ func TestLoad_CustomEnvironment(t *testing.T) {
 	t.Setenv("DMX_REFRESH_RATE", "30")
 	t.Setenv("DMX_IDLE_RATE", "5")
 	t.Setenv("DMX_HIGH_RATE_DURATION", "3000")
+	t.Setenv("DMX_KEEPALIVE_MS", "2500")
 	t.Setenv("ARTNET_ENABLED", "false")
 	t.Setenv("ARTNET_PORT", "6455")
 	t.Setenv("ARTNET_BROADCAST", "192.168.1.255")
+	t.Setenv("ARTNET_SYNC", "true")
+	t.Setenv("ARTNET_BIND", "eth1")
 	t.Setenv("DMX_DRIFT_THRESHOLD", "100")
 	t.Setenv("DMX_DRIFT_THROTTLE", "10000")
 	t.Setenv("NON_INTERACTIVE", "true")
 	t.Setenv("CORS_ORIGIN", "http://example.com")
 	t.Setenv("FADE_UPDATE_RATE", "120")
+	t.Setenv("STATE_JOURNAL_PATH", "/var/lib/lacylights/state.journal")
+	t.Setenv("STATE_JOURNAL_SYNC", "interval")
+	t.Setenv("STATE_JOURNAL_SYNC_INTERVAL", "500")
+	t.Setenv("PLAYBACK_RESUME", "manual")
+	t.Setenv("SNAPSHOT_INTERVAL", "10")
+	t.Setenv("AUDIT_LOG_RETENTION_DAYS", "7")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_TOKEN_TTL_HOURS", "2")
+	t.Setenv("LOG_LEVELS", "dmx=debug")
+	t.Setenv("REPLICATION_ROLE", "backup")
+	t.Setenv("MDNS_ENABLED", "false")
+	t.Setenv("MDNS_NAME", "Booth Console")
+	t.Setenv("REPLICATION_FAILOVER_MS", "5000")
+	t.Setenv("SHUTDOWN_OUTPUT", "hold")
+	t.Setenv("SHUTDOWN_FADE_MS", "1500")
 
 	cfg := Load()
 
@@ -81,6 +100,9 @@ func TestLoad_CustomEnvironment(t *testing.T) {
 	if cfg.DMXHighRateDuration != 3000*time.Millisecond {
 		t.Errorf("Expected DMXHighRateDuration to be 3000ms, got %v", cfg.DMXHighRateDuration)
 	}
+	if cfg.DMXKeepAlive != 2500*time.Millisecond {
+		t.Errorf("Expected DMXKeepAlive to be 2500ms, got %v", cfg.DMXKeepAlive)
+	}
 	if cfg.ArtNetEnabled != false {
 		t.Errorf("Expected ArtNetEnabled to be false, got %v", cfg.ArtNetEnabled)
 	}
@@ -90,6 +112,12 @@ func TestLoad_CustomEnvironment(t *testing.T) {
 	if cfg.ArtNetBroadcast != "192.168.1.255" {
 		t.Errorf("Expected ArtNetBroadcast to be '192.168.1.255', got '%s'", cfg.ArtNetBroadcast)
 	}
+	if !cfg.ArtNetSync {
+		t.Error("Expected ArtNetSync to be true")
+	}
+	if cfg.ArtNetBind != "eth1" {
+		t.Errorf("Expected ArtNetBind to be 'eth1', got '%s'", cfg.ArtNetBind)
+	}
 	if cfg.DMXDriftThreshold != 100 {
 		t.Errorf("Expected DMXDriftThreshold to be 100, got %d", cfg.DMXDriftThreshold)
 	}
@@ -105,6 +133,51 @@ func TestLoad_CustomEnvironment(t *testing.T) {
 	if cfg.FadeUpdateRateHz != 120 {
 		t.Errorf("Expected FadeUpdateRateHz to be 120, got %d", cfg.FadeUpdateRateHz)
 	}
+	if cfg.StateJournalPath != "/var/lib/lacylights/state.journal" {
+		t.Errorf("Expected StateJournalPath to be '/var/lib/lacylights/state.journal', got '%s'", cfg.StateJournalPath)
+	}
+	if cfg.StateJournalSync != "interval" {
+		t.Errorf("Expected StateJournalSync to be 'interval', got '%s'", cfg.StateJournalSync)
+	}
+	if cfg.StateJournalSyncInterval != 500*time.Millisecond {
+		t.Errorf("Expected StateJournalSyncInterval to be 500ms, got %v", cfg.StateJournalSyncInterval)
+	}
+	if cfg.PlaybackResume != "manual" {
+		t.Errorf("Expected PlaybackResume to be 'manual', got '%s'", cfg.PlaybackResume)
+	}
+	if cfg.SnapshotInterval != 10*time.Minute {
+		t.Errorf("Expected SnapshotInterval to be 10m, got %v", cfg.SnapshotInterval)
+	}
+	if cfg.AuditLogRetention != 7*24*time.Hour {
+		t.Errorf("Expected AuditLogRetention to be 7 days, got %v", cfg.AuditLogRetention)
+	}
+	if !cfg.AuthEnabled {
+		t.Error("Expected AuthEnabled to be true")
+	}
+	if cfg.AuthTokenTTL != 2*time.Hour {
+		t.Errorf("Expected AuthTokenTTL to be 2h, got %v", cfg.AuthTokenTTL)
+	}
+	if cfg.LogLevels != "dmx=debug" {
+		t.Errorf("Expected LogLevels to be dmx=debug, got %s", cfg.LogLevels)
+	}
+	if cfg.ReplicationRole != "backup" {
+		t.Errorf("Expected ReplicationRole to be backup, got %s", cfg.ReplicationRole)
+	}
+	if cfg.ReplicationFailoverTimeout != 5*time.Second {
+		t.Errorf("Expected ReplicationFailoverTimeout to be 5s, got %v", cfg.ReplicationFailoverTimeout)
+	}
+	if cfg.MDNSEnabled {
+		t.Error("Expected MDNSEnabled to be false")
+	}
+	if cfg.MDNSName != "Booth Console" {
+		t.Errorf("Expected MDNSName to be Booth Console, got %s", cfg.MDNSName)
+	}
+	if cfg.ShutdownOutput != "hold" {
+		t.Errorf("Expected ShutdownOutput to be hold, got %s", cfg.ShutdownOutput)
+	}
+	if cfg.ShutdownFadeTime != 1500*time.Millisecond {
+		t.Errorf("Expected ShutdownFadeTime to be 1.5s, got %v", cfg.ShutdownFadeTime)
+	}
 }
 
 func TestIsDevelopment(t *testing.T) {
@@ -302,3 +375,91 @@ func TestLoad_FadeUpdateRateHz_DefaultValue(t *testing.T) {
 		t.Errorf("Expected default FadeUpdateRateHz to be 60, got %d", cfg.FadeUpdateRateHz)
 	}
 }
+
+func TestLoad_DataDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DATA_DIR", dir)
+
+	cfg := Load()
+	if cfg.DataDir != dir {
+		t.Errorf("Expected DataDir %s, got %s", dir, cfg.DataDir)
+	}
+	if want := "file:" + filepath.Join(dir, "lacylights.db"); cfg.DatabaseURL != want {
+		t.Errorf("Expected DatabaseURL %s, got %s", want, cfg.DatabaseURL)
+	}
+	if want := filepath.Join(dir, "playback-state.journal"); cfg.StateJournalPath != want {
+		t.Errorf("Expected StateJournalPath %s, got %s", want, cfg.StateJournalPath)
+	}
+	if want := filepath.Join(dir, "dmx-handoff.json"); cfg.DMXHandoffPath != want {
+		t.Errorf("Expected DMXHandoffPath %s, got %s", want, cfg.DMXHandoffPath)
+	}
+	if want := filepath.Join(dir, "backups"); cfg.BackupDir != want {
+		t.Errorf("Expected BackupDir %s, got %s", want, cfg.BackupDir)
+	}
+	if want := filepath.Join(dir, "recordings"); cfg.DMXRecordingDir != want {
+		t.Errorf("Expected DMXRecordingDir %s, got %s", want, cfg.DMXRecordingDir)
+	}
+	if want := filepath.Join(dir, "tls", "cert.pem"); cfg.TLSCertFile != want {
+		t.Errorf("Expected TLSCertFile %s, got %s", want, cfg.TLSCertFile)
+	}
+	if cfg.TLSMode != "off" {
+		t.Errorf("Expected TLS off by default, got %s", cfg.TLSMode)
+	}
+
+	// An explicit database URL wins over the data directory
+	t.Setenv("DATABASE_URL", "file:./other.db")
+	if cfg := Load(); cfg.DatabaseURL != "file:./other.db" {
+		t.Errorf("Expected DATABASE_URL to win, got %s", cfg.DatabaseURL)
+	}
+}
+
+func TestDefaultDataDir(t *testing.T) {
+	original := piInstallDir
+	t.Cleanup(func() { piInstallDir = original })
+
+	piInstallDir = filepath.Join(t.TempDir(), "missing")
+	if dir := defaultDataDir(); dir != "" {
+		t.Errorf("Expected no data directory off a Pi, got %s", dir)
+	}
+
+	piInstallDir = t.TempDir()
+	if want := filepath.Join(piInstallDir, "data"); defaultDataDir() != want {
+		t.Errorf("Expected data directory %s, got %s", want, defaultDataDir())
+	}
+}
+
+func TestLoad_DatabaseDefaults(t *testing.T) {
+	cfg := Load()
+	if cfg.DatabaseDriver != "sqlite" {
+		t.Errorf("Expected default DatabaseDriver sqlite, got %s", cfg.DatabaseDriver)
+	}
+	if !cfg.DatabaseAutoCheckpoint {
+		t.Error("Expected DatabaseAutoCheckpoint to default to true")
+	}
+	if cfg.DatabaseMigrate != "auto" {
+		t.Errorf("Expected default DatabaseMigrate auto, got %s", cfg.DatabaseMigrate)
+	}
+}
+
+func TestLoad_BackupDefaults(t *testing.T) {
+	cfg := Load()
+	if cfg.BackupInterval != 24*time.Hour {
+		t.Errorf("Expected BackupInterval to be 24h, got %v", cfg.BackupInterval)
+	}
+	if cfg.BackupRetention != 14 {
+		t.Errorf("Expected BackupRetention to be 14, got %d", cfg.BackupRetention)
+	}
+	if cfg.BackupS3Bucket != "" || cfg.BackupS3Prefix != "lacylights/" || cfg.BackupS3Region != "us-east-1" {
+		t.Errorf("Expected no bucket with the default prefix and region, got %q %q %q", cfg.BackupS3Bucket, cfg.BackupS3Prefix, cfg.BackupS3Region)
+	}
+
+	t.Setenv("BACKUP_INTERVAL_HOURS", "0")
+	t.Setenv("BACKUP_S3_BUCKET", "shows")
+	cfg = Load()
+	if cfg.BackupInterval != 0 {
+		t.Errorf("Expected scheduled backups disabled, got %v", cfg.BackupInterval)
+	}
+	if cfg.BackupS3Bucket != "shows" {
+		t.Errorf("Expected BackupS3Bucket to be shows, got %s", cfg.BackupS3Bucket)
+	}
+}
@@ -1,9 +1,11 @@
-// Package database provides database connection and management.
+// Package database provides database connection and management. LacyLights
+// stores its data in an embedded SQLite database, kept in WAL mode so that
+// readers do not block the writer and tools like Litestream can replicate it.
 package database
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,22 +19,79 @@ import (
 // DB is the global database connection.
 var DB *gorm.DB
 
+// DriverSQLite is the database driver, and the only one supported.
+const DriverSQLite = "sqlite"
+
+// MemoryPath opens a database that lives in memory, for tests.
+const MemoryPath = ":memory:"
+
+// busyTimeout is how long a connection waits for another's write lock.
+const busyTimeout = 5 * time.Second
+
 // Config holds database configuration.
 type Config struct {
+	Driver      string // sqlite when empty
 	URL         string
 	MaxIdleConn int
 	MaxOpenConn int
 	Debug       bool
+	// DisableAutoCheckpoint leaves checkpointing the WAL into the database
+	// file to an outside tool such as Litestream
+	DisableAutoCheckpoint bool
+}
+
+// SQLitePath returns the file a database URL names: a path, optionally
+// prefixed with file: or sqlite:, and without query parameters. URLs of
+// other databases are rejected.
+func SQLitePath(url string) (string, error) {
+	path := url
+	for _, prefix := range []string{"sqlite://", "sqlite:", "file:"} {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	// Report only the scheme, as the rest may hold credentials
+	if scheme, _, found := strings.Cut(path, "://"); found {
+		return "", fmt.Errorf("unsupported database URL scheme %q: LacyLights stores its data in SQLite, so DATABASE_URL must be a file path", scheme)
+	}
+	path, _, _ = strings.Cut(path, "?")
+	if path == "" {
+		return "", errors.New("database path is empty")
+	}
+	return path, nil
+}
+
+// sqliteDSN returns the driver's data source name for a database file,
+// with the pragmas each connection runs on opening.
+func sqliteDSN(path string, cfg Config) string {
+	pragmas := []string{fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds())}
+	if path != MemoryPath {
+		pragmas = append(pragmas, "journal_mode(WAL)", "synchronous(NORMAL)")
+		if cfg.DisableAutoCheckpoint {
+			pragmas = append(pragmas, "wal_autocheckpoint(0)")
+		}
+	}
+	params := make([]string, len(pragmas))
+	for i, pragma := range pragmas {
+		params[i] = "_pragma=" + pragma
+	}
+	return path + "?" + strings.Join(params, "&")
 }
 
 // Connect establishes a connection to the database.
 func Connect(cfg Config) (*gorm.DB, error) {
-	// Parse the DATABASE_URL (format: "file:./path/to/db" or just path)
-	dbPath := strings.TrimPrefix(cfg.URL, "file:")
+	if cfg.Driver != "" && cfg.Driver != DriverSQLite {
+		return nil, fmt.Errorf("unsupported database driver %q: only %s is supported", cfg.Driver, DriverSQLite)
+	}
+	dbPath, err := SQLitePath(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
 
 	// Ensure the directory exists
 	dir := filepath.Dir(dbPath)
-	if dir != "" && dir != "." {
+	if dbPath != MemoryPath && dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return nil, fmt.Errorf("failed to create database directory: %w", err)
 		}
@@ -47,20 +106,15 @@ func Connect(cfg Config) (*gorm.DB, error) {
 	}
 
 	gormLogger := logger.New(
-		log.New(os.Stdout, "\r\n", log.LstdFlags),
+		gormWriter{},
 		logger.Config{
 			SlowThreshold:             time.Second,
 			LogLevel:                  logLevel,
 			IgnoreRecordNotFoundError: true,
-			Colorful:                  true,
 		},
 	)
 
-	// Open the database with SQLite-specific settings
-	// Note: We use the same pragmas as Prisma for compatibility
-	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000", dbPath)
-
-	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(dbPath, cfg)), &gorm.Config{
 		Logger:                 gormLogger,
 		SkipDefaultTransaction: true, // Better performance for reads
 	})
@@ -79,13 +133,31 @@ func Connect(cfg Config) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConn)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	// A database on a filesystem without shared memory support, such as some
+	// network mounts, stays in its old journal mode
+	var journalMode string
+	if err := db.Raw("PRAGMA journal_mode").Scan(&journalMode).Error; err != nil {
+		return nil, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	if dbPath != MemoryPath && !strings.EqualFold(journalMode, "wal") {
+		log.Warn("Database is not in WAL mode; writes will block reads", "path", dbPath, "journalMode", journalMode)
+	}
+
 	// Store global reference
 	DB = db
 
-	log.Printf("Database connected: %s", dbPath)
+	log.Info("Database connected", "path", dbPath, "journalMode", journalMode)
 	return db, nil
 }
 
+// gormWriter writes GORM's SQL trace and slow query warnings to the
+// database log.
+type gormWriter struct{}
+
+func (gormWriter) Printf(format string, args ...interface{}) {
+	log.Info(fmt.Sprintf(format, args...))
+}
+
 // Close closes the database connection.
 func Close() error {
 	if DB != nil {
@@ -0,0 +1,679 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// baseline creates the schema as releases before versioned migrations left
+// it. A database made by one of them already has some of it; baseline adds
+// the tables, columns and indexes that are missing and keeps the data.
+var baseline = Migration{
+	Version: 1,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range baselineTables {
+			if err := table.create(tx); err != nil {
+				return fmt.Errorf("%s: %w", table.name, err)
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for i := len(baselineTables) - 1; i >= 0; i-- {
+			if err := tx.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %q`, baselineTables[i].name)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// baselineTable is a table of the baseline schema. Its definitions are
+// frozen: a later change to the schema is a new migration, not an edit here.
+type baselineTable struct {
+	name        string
+	columns     []string // Column definitions, the id primary key first
+	constraints []string // Foreign keys
+	indexes     []string // CREATE INDEX IF NOT EXISTS statements
+}
+
+// create creates the table, or adds the columns an existing one lacks, and
+// its indexes.
+func (t baselineTable) create(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(t.name) {
+		definitions := append(append(append([]string{}, t.columns...), `PRIMARY KEY ("id")`), t.constraints...)
+		if err := tx.Exec(fmt.Sprintf(`CREATE TABLE %q (%s)`, t.name, strings.Join(definitions, ","))).Error; err != nil {
+			return err
+		}
+	} else {
+		for _, column := range t.columns {
+			if tx.Migrator().HasColumn(t.name, columnName(column)) {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %q ADD COLUMN %s`, t.name, column)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	for _, index := range t.indexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnName returns the quoted name a column definition starts with.
+func columnName(definition string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(definition, `"`), `"`)
+	return name
+}
+
+// baselineTables are the baseline schema's tables, parents first.
+var baselineTables = []baselineTable{
+	{
+		name: "users",
+		columns: []string{
+			`"id" text`,
+			`"email" text`,
+			`"name" text`,
+			`"role" text DEFAULT "USER"`,
+			`"password_hash" text`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users"("email")`,
+		},
+	},
+	{
+		name: "projects",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+			`"scene_name_pattern" text`,
+			`"cue_name_pattern" text`,
+			`"name_uniqueness" text`,
+			`"naming_variables" text`,
+			`"default_scene_sort" text`,
+			`"default_fade_in" real`,
+			`"default_fade_out" real`,
+		},
+	},
+	{
+		name: "project_users",
+		columns: []string{
+			`"id" text`,
+			`"user_id" text`,
+			`"project_id" text`,
+			`"role" text DEFAULT "VIEWER"`,
+			`"joined_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_project_users_project_id" ON "project_users"("project_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_project_users_user_id" ON "project_users"("user_id")`,
+		},
+	},
+	{
+		name: "fixture_definitions",
+		columns: []string{
+			`"id" text`,
+			`"manufacturer" text`,
+			`"model" text`,
+			`"type" text`,
+			`"is_built_in" numeric DEFAULT false`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+			`"ofl_source_hash" text`,
+			`"ofl_version" text`,
+		},
+	},
+	{
+		name: "channel_definitions",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"type" text`,
+			`"offset" integer`,
+			`"min_value" integer DEFAULT 0`,
+			`"max_value" integer DEFAULT 255`,
+			`"default_value" integer DEFAULT 0`,
+			`"fade_behavior" text DEFAULT "FADE"`,
+			`"is_discrete" numeric DEFAULT false`,
+			`"definition_id" text`,
+			`"attribute" text`,
+			`"color" text`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_fixture_definitions_channels" FOREIGN KEY ("definition_id") REFERENCES "fixture_definitions"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_channel_definitions_definition_id" ON "channel_definitions"("definition_id")`,
+		},
+	},
+	{
+		name: "channel_capabilities",
+		columns: []string{
+			`"id" text`,
+			`"channel_id" text`,
+			`"name" text`,
+			`"min_value" integer`,
+			`"max_value" integer`,
+			`"color" text`,
+			`"image" text`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_channel_capabilities_channel_id" ON "channel_capabilities"("channel_id")`,
+		},
+	},
+	{
+		name: "fixture_modes",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"short_name" text`,
+			`"channel_count" integer`,
+			`"definition_id" text`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_fixture_definitions_modes" FOREIGN KEY ("definition_id") REFERENCES "fixture_definitions"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_modes_definition_id" ON "fixture_modes"("definition_id")`,
+		},
+	},
+	{
+		name: "mode_channels",
+		columns: []string{
+			`"id" text`,
+			`"mode_id" text`,
+			`"channel_id" text`,
+			`"offset" integer`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_fixture_modes_mode_channels" FOREIGN KEY ("mode_id") REFERENCES "fixture_modes"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_mode_channels_channel_id" ON "mode_channels"("channel_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_mode_channels_mode_id" ON "mode_channels"("mode_id")`,
+		},
+	},
+	{
+		name: "fixture_instances",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"definition_id" text`,
+			`"manufacturer" text`,
+			`"model" text`,
+			`"type" text`,
+			`"mode_name" text`,
+			`"channel_count" integer`,
+			`"project_id" text`,
+			`"universe" integer`,
+			`"start_channel" integer`,
+			`"tags" text DEFAULT "[]"`,
+			`"project_order" integer`,
+			`"layout_x" real`,
+			`"layout_y" real`,
+			`"layout_rotation" real`,
+			`"max_intensity" real`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_fixture_instances_definition" FOREIGN KEY ("definition_id") REFERENCES "fixture_definitions"("id")`,
+			`CONSTRAINT "fk_projects_fixtures" FOREIGN KEY ("project_id") REFERENCES "projects"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_instances_project_id" ON "fixture_instances"("project_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_instances_definition_id" ON "fixture_instances"("definition_id")`,
+		},
+	},
+	{
+		name: "instance_channels",
+		columns: []string{
+			`"id" text`,
+			`"fixture_id" text`,
+			`"offset" integer`,
+			`"name" text`,
+			`"type" text`,
+			`"min_value" integer DEFAULT 0`,
+			`"max_value" integer DEFAULT 255`,
+			`"default_value" integer DEFAULT 0`,
+			`"fade_behavior" text DEFAULT "FADE"`,
+			`"is_discrete" numeric DEFAULT false`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_fixture_instances_channels" FOREIGN KEY ("fixture_id") REFERENCES "fixture_instances"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_instance_channels_fixture_id" ON "instance_channels"("fixture_id")`,
+		},
+	},
+	{
+		name: "scenes",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"secondary_label" text`,
+			`"description" text`,
+			`"project_id" text`,
+			`"default_fade_in" real`,
+			`"default_fade_out" real`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_projects_scenes" FOREIGN KEY ("project_id") REFERENCES "projects"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_scenes_project_id" ON "scenes"("project_id")`,
+		},
+	},
+	{
+		name: "fixture_values",
+		columns: []string{
+			`"id" text`,
+			`"scene_id" text`,
+			`"fixture_id" text`,
+			`"channels" text DEFAULT "[]"`,
+			`"scene_order" integer`,
+			`"group_id" text`,
+			`"palette_ids" text DEFAULT "[]"`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_scenes_fixture_values" FOREIGN KEY ("scene_id") REFERENCES "scenes"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_values_group_id" ON "fixture_values"("group_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_values_fixture_id" ON "fixture_values"("fixture_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_values_scene_id" ON "fixture_values"("scene_id")`,
+		},
+	},
+	{
+		name: "cue_lists",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"loop" numeric DEFAULT false`,
+			`"playback_mode" text DEFAULT "TIMED"`,
+			`"tracking" numeric DEFAULT false`,
+			`"project_id" text`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_projects_cue_lists" FOREIGN KEY ("project_id") REFERENCES "projects"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_cue_lists_project_id" ON "cue_lists"("project_id")`,
+		},
+	},
+	{
+		name: "cues",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"secondary_label" text`,
+			`"cue_number" real`,
+			`"cue_list_id" text`,
+			`"scene_id" text`,
+			`"fade_in_time" real DEFAULT 0`,
+			`"fade_out_time" real DEFAULT 0`,
+			`"follow_time" real`,
+			`"follow_quantize" text`,
+			`"easing_type" text`,
+			`"notes" text`,
+			`"timecode" text`,
+			`"block" numeric DEFAULT false`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_cues_scene" FOREIGN KEY ("scene_id") REFERENCES "scenes"("id")`,
+			`CONSTRAINT "fk_cue_lists_cues" FOREIGN KEY ("cue_list_id") REFERENCES "cue_lists"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_cues_scene_id" ON "cues"("scene_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_cues_cue_list_id" ON "cues"("cue_list_id")`,
+		},
+	},
+	{
+		name: "preview_sessions",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"user_id" text`,
+			`"is_active" numeric DEFAULT true`,
+			`"blind" numeric DEFAULT false`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_preview_sessions_user_id" ON "preview_sessions"("user_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_preview_sessions_project_id" ON "preview_sessions"("project_id")`,
+		},
+	},
+	{
+		name: "settings",
+		columns: []string{
+			`"id" text`,
+			`"key" text`,
+			`"value" text`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE UNIQUE INDEX IF NOT EXISTS "idx_settings_key" ON "settings"("key")`,
+		},
+	},
+	{
+		name: "scene_boards",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"project_id" text`,
+			`"default_fade_time" real DEFAULT 3`,
+			`"grid_size" integer DEFAULT 50`,
+			`"canvas_width" integer DEFAULT 2000`,
+			`"canvas_height" integer DEFAULT 2000`,
+			`"hold_ramp_time" real DEFAULT 3`,
+			`"hold_curve" text DEFAULT "LINEAR"`,
+			`"hold_release_mode" text DEFAULT "LATCH"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_scene_boards_project_id" ON "scene_boards"("project_id")`,
+		},
+	},
+	{
+		name: "scene_board_buttons",
+		columns: []string{
+			`"id" text`,
+			`"scene_board_id" text`,
+			`"scene_id" text`,
+			`"layout_x" integer`,
+			`"layout_y" integer`,
+			`"width" integer DEFAULT 200`,
+			`"height" integer DEFAULT 120`,
+			`"color" text`,
+			`"label" text`,
+			`"fade_in_time" real`,
+			`"fade_out_time" real`,
+			`"flash_mode" numeric DEFAULT false`,
+			`"flash_level" real`,
+			`"flash_release_time" real`,
+			`"macro" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		constraints: []string{
+			`CONSTRAINT "fk_scene_board_buttons_scene" FOREIGN KEY ("scene_id") REFERENCES "scenes"("id")`,
+			`CONSTRAINT "fk_scene_boards_buttons" FOREIGN KEY ("scene_board_id") REFERENCES "scene_boards"("id")`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_scene_board_buttons_scene_id" ON "scene_board_buttons"("scene_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_scene_board_buttons_scene_board_id" ON "scene_board_buttons"("scene_board_id")`,
+		},
+	},
+	{
+		name: "effects",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"project_id" text`,
+			`"scene_id" text`,
+			`"type" text`,
+			`"fixture_ids" text DEFAULT "[]"`,
+			`"rate" real`,
+			`"size" real`,
+			`"phase_offset" real`,
+			`"order" text DEFAULT "FORWARD"`,
+			`"low" real`,
+			`"high" real`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_effects_scene_id" ON "effects"("scene_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_effects_project_id" ON "effects"("project_id")`,
+		},
+	},
+	{
+		name: "submasters",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"project_id" text`,
+			`"page" integer DEFAULT 1`,
+			`"slot" integer`,
+			`"scene_id" text`,
+			`"fixture_ids" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_submasters_scene_id" ON "submasters"("scene_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_submasters_project_id" ON "submasters"("project_id")`,
+		},
+	},
+	{
+		name: "fixture_groups",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"project_id" text`,
+			`"fixture_ids" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_fixture_groups_project_id" ON "fixture_groups"("project_id")`,
+		},
+	},
+	{
+		name: "group_values",
+		columns: []string{
+			`"id" text`,
+			`"scene_id" text`,
+			`"group_id" text`,
+			`"channels" text DEFAULT "[]"`,
+			`"scene_order" integer`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_group_values_group_id" ON "group_values"("group_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_group_values_scene_id" ON "group_values"("scene_id")`,
+		},
+	},
+	{
+		name: "palettes",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"project_id" text`,
+			`"kind" text`,
+			`"channels" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_palettes_project_id" ON "palettes"("project_id")`,
+		},
+	},
+	{
+		name: "schedules",
+		columns: []string{
+			`"id" text`,
+			`"name" text`,
+			`"project_id" text`,
+			`"enabled" numeric`,
+			`"trigger" text`,
+			`"cron" text`,
+			`"latitude" real`,
+			`"longitude" real`,
+			`"offset_minutes" integer`,
+			`"days" text DEFAULT "[]"`,
+			`"action" text`,
+			`"scene_id" text`,
+			`"cue_list_id" text`,
+			`"fade_time" real`,
+			`"last_fired_at" datetime`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_schedules_cue_list_id" ON "schedules"("cue_list_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_schedules_scene_id" ON "schedules"("scene_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_schedules_project_id" ON "schedules"("project_id")`,
+		},
+	},
+	{
+		name: "soft_patches",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"universe" integer`,
+			`"channel" integer`,
+			`"targets" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_soft_patches_project_id" ON "soft_patches"("project_id")`,
+		},
+	},
+	{
+		name: "universes",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"number" integer`,
+			`"label" text`,
+			`"protocol" text DEFAULT "ARTNET"`,
+			`"destination" text`,
+			`"enabled" numeric`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE UNIQUE INDEX IF NOT EXISTS "idx_universes_project_number" ON "universes"("project_id","number")`,
+		},
+	},
+	{
+		name: "layout_zones",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"name" text`,
+			`"x" real`,
+			`"y" real`,
+			`"width" real`,
+			`"height" real`,
+			`"color" text`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_layout_zones_project_id" ON "layout_zones"("project_id")`,
+		},
+	},
+	{
+		name: "selection_sets",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"name" text`,
+			`"description" text`,
+			`"fixture_ids" text DEFAULT "[]"`,
+			`"created_at" datetime`,
+			`"updated_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_selection_sets_project_id" ON "selection_sets"("project_id")`,
+		},
+	},
+	{
+		name: "undo_operations",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"seq" integer`,
+			`"description" text`,
+			`"targets" text`,
+			`"before" text`,
+			`"after" text`,
+			`"undone" numeric`,
+			`"created_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_undo_operations_project_id" ON "undo_operations"("project_id")`,
+		},
+	},
+	{
+		name: "project_snapshots",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"project_name" text`,
+			`"reason" text`,
+			`"content" blob`,
+			`"size" integer`,
+			`"checksum" text`,
+			`"created_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_project_snapshots_project_id" ON "project_snapshots"("project_id")`,
+		},
+	},
+	{
+		name: "audit_logs",
+		columns: []string{
+			`"id" text`,
+			`"project_id" text`,
+			`"operator" text`,
+			`"operation" text`,
+			`"entity_type" text`,
+			`"entity_id" text`,
+			`"summary" text`,
+			`"before" text`,
+			`"after" text`,
+			`"error" text`,
+			`"created_at" datetime`,
+		},
+		indexes: []string{
+			`CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs"("created_at")`,
+			`CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity_id" ON "audit_logs"("entity_id")`,
+			`CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity_type" ON "audit_logs"("entity_type")`,
+			`CREATE INDEX IF NOT EXISTS "idx_audit_logs_operator" ON "audit_logs"("operator")`,
+			`CREATE INDEX IF NOT EXISTS "idx_audit_logs_project_id" ON "audit_logs"("project_id")`,
+		},
+	},
+	{
+		name: "ofl_import_meta",
+		columns: []string{
+			`"id" text`,
+			`"ofl_version" text`,
+			`"started_at" datetime`,
+			`"completed_at" datetime`,
+			`"total_fixtures" integer`,
+			`"successful_imports" integer`,
+			`"failed_imports" integer`,
+			`"skipped_duplicates" integer`,
+			`"updated_fixtures" integer`,
+			`"used_bundled_data" numeric`,
+			`"error_message" text`,
+		},
+	},
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// channelCells adds the cell of multi-cell fixtures' channels to their
+// definitions and instances.
+var channelCells = Migration{
+	Version: 5,
+	Name:    "channel_cells",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range []string{"channel_definitions", "instance_channels"} {
+			if tx.Migrator().HasColumn(table, "cell") {
+				continue
+			}
+			if err := tx.Exec(`ALTER TABLE "` + table + `" ADD COLUMN "cell" integer`).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range []string{"channel_definitions", "instance_channels"} {
+			if err := tx.Exec(`ALTER TABLE "` + table + `" DROP COLUMN "cell"`).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// cueMoveInBlack adds the option for a cue to move fixtures into position
+// while they are dark in the cue before it.
+var cueMoveInBlack = Migration{
+	Version: 3,
+	Name:    "cue_move_in_black",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn("cues", "move_in_black") {
+			return nil
+		}
+		return tx.Exec(`ALTER TABLE "cues" ADD COLUMN "move_in_black" numeric DEFAULT false`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec(`ALTER TABLE "cues" DROP COLUMN "move_in_black"`).Error
+	},
+}
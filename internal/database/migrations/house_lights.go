@@ -0,0 +1,32 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// houseLights adds the table of each project's house lights.
+var houseLights = Migration{
+	Version: 2,
+	Name:    "house_lights",
+	Up: func(tx *gorm.DB) error {
+		return baselineTable{
+			name: "house_lights",
+			columns: []string{
+				`"id" text`,
+				`"project_id" text`,
+				`"fixture_ids" text DEFAULT "[]"`,
+				`"group_ids" text DEFAULT "[]"`,
+				`"full_level" real`,
+				`"half_level" real`,
+				`"show_level" real`,
+				`"fade_time" real`,
+				`"created_at" datetime`,
+				`"updated_at" datetime`,
+			},
+			indexes: []string{
+				`CREATE UNIQUE INDEX IF NOT EXISTS "idx_house_lights_project_id" ON "house_lights"("project_id")`,
+			},
+		}.create(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS "house_lights"`).Error
+	},
+}
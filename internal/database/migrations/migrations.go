@@ -0,0 +1,247 @@
+// Package migrations versions the database schema. Each migration moves the
+// schema up one version and back down again, and the schema_versions table
+// records the ones applied, so that a release starts only on the schema it
+// was built for and a downgrade first undoes what the newer release added.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bbernstein/lacylights-go/internal/services/logging"
+)
+
+var log = logging.For(logging.ModuleDatabase)
+
+// Migration is one version of the schema.
+type Migration struct {
+	Version int
+	Name    string
+	// Up moves the schema to this version, Down back to the one before. A
+	// migration without Down cannot be reverted.
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// String names a migration in logs and errors.
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// all are the schema's migrations, oldest first. Append new ones; never
+// change one that has been released.
+var all = []Migration{
+	baseline,
+	houseLights,
+	cueMoveInBlack,
+	pixelMatrices,
+	channelCells,
+	sceneBoardAudioTriggers,
+}
+
+// SchemaVersion records an applied migration.
+type SchemaVersion struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaVersion) TableName() string { return "schema_versions" }
+
+// ErrPending is returned by Check when migrations have yet to be applied.
+var ErrPending = errors.New("database schema has pending migrations")
+
+// ErrNewerSchema is returned when a newer release has migrated the
+// database past the versions this build knows.
+var ErrNewerSchema = errors.New("database schema is newer than this build")
+
+// Status is where a database's schema stands.
+type Status struct {
+	// Current is the newest applied version, 0 for an empty database
+	Current int
+	// Latest is the newest version this build knows
+	Latest int
+	// Pending are the migrations not yet applied, oldest first
+	Pending []Migration
+	// Unknown are applied versions this build does not know
+	Unknown []int
+}
+
+// UpToDate reports whether the schema is exactly the one this build expects.
+func (s *Status) UpToDate() bool {
+	return len(s.Pending) == 0 && len(s.Unknown) == 0
+}
+
+// Migrator applies and reverts migrations.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the schema's migrations.
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{db: db, migrations: all}
+}
+
+// Status reads which migrations a database has applied.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status.Latest = migration.Version
+		if _, ok := applied[migration.Version]; !ok {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	for version := range applied {
+		status.Current = max(status.Current, version)
+		if !known[version] {
+			status.Unknown = append(status.Unknown, version)
+		}
+	}
+	slices.Sort(status.Unknown)
+	return status, nil
+}
+
+// Check is the pre-flight check: it changes nothing, and returns nil only
+// when the schema is the one this build expects.
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if len(status.Unknown) > 0 {
+		return newerSchemaError(status)
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: %d to apply, from %s", ErrPending, len(status.Pending), status.Pending[0])
+	}
+	return nil
+}
+
+// Up applies the pending migrations, oldest first, each in a transaction of
+// its own. It returns the migrations applied, up to any that failed.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(status.Unknown) > 0 {
+		return nil, newerSchemaError(status)
+	}
+
+	db := m.db.WithContext(ctx)
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS "schema_versions" ("version" integer PRIMARY KEY, "name" text NOT NULL, "applied_at" datetime NOT NULL)`).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_versions: %w", err)
+	}
+
+	var done []Migration
+	for _, migration := range status.Pending {
+		log.Info("Applying migration", "migration", migration.String())
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s failed: %w", migration, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the applied migrations newer than version, newest first. It
+// returns the migrations reverted, up to any that failed.
+func (m *Migrator) Down(ctx context.Context, version int) ([]Migration, error) {
+	if version < 0 {
+		return nil, fmt.Errorf("invalid schema version %d", version)
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(status.Unknown, func(v int) bool { return v > version }) {
+		return nil, newerSchemaError(status)
+	}
+
+	var revert []Migration
+	for _, migration := range m.migrations {
+		if migration.Version > version && !slices.ContainsFunc(status.Pending, func(p Migration) bool { return p.Version == migration.Version }) {
+			if migration.Down == nil {
+				return nil, fmt.Errorf("migration %s cannot be reverted", migration)
+			}
+			revert = append(revert, migration)
+		}
+	}
+	slices.Reverse(revert)
+
+	db := m.db.WithContext(ctx)
+	var done []Migration
+	for _, migration := range revert {
+		log.Info("Reverting migration", "migration", migration.String())
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaVersion{}, "version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %s failed: %w", migration, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// applied returns the applied versions. A database without schema_versions
+// has applied none.
+func (m *Migrator) applied(ctx context.Context) (map[int]bool, error) {
+	db := m.db.WithContext(ctx)
+	applied := make(map[int]bool)
+	if !db.Migrator().HasTable(&SchemaVersion{}) {
+		return applied, nil
+	}
+	var versions []int
+	if err := db.Model(&SchemaVersion{}).Pluck("version", &versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema versions: %w", err)
+	}
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+// validate checks that the migrations are in order with distinct versions.
+func (m *Migrator) validate() error {
+	for i, migration := range m.migrations {
+		if migration.Version <= 0 || migration.Up == nil {
+			return fmt.Errorf("migration %s needs a positive version and Up", migration)
+		}
+		if i > 0 && migration.Version <= m.migrations[i-1].Version {
+			return fmt.Errorf("migration %s is out of order", migration)
+		}
+	}
+	return nil
+}
+
+// newerSchemaError explains a database migrated by a newer release.
+func newerSchemaError(status *Status) error {
+	return fmt.Errorf("%w: it has version %d and this build knows up to %d; revert it with the newer release's schema down %d first",
+		ErrNewerSchema, status.Unknown[len(status.Unknown)-1], status.Latest, status.Latest)
+}
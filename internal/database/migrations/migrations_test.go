@@ -0,0 +1,314 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+)
+
+// schemaModels are the models whose tables the migrations must match.
+var schemaModels = []interface{}{
+	&models.User{},
+	&models.Project{},
+	&models.ProjectUser{},
+	&models.FixtureDefinition{},
+	&models.ChannelDefinition{},
+	&models.ChannelCapability{},
+	&models.FixtureMode{},
+	&models.ModeChannel{},
+	&models.FixtureInstance{},
+	&models.InstanceChannel{},
+	&models.Scene{},
+	&models.FixtureValue{},
+	&models.CueList{},
+	&models.Cue{},
+	&models.PreviewSession{},
+	&models.Setting{},
+	&models.SceneBoard{},
+	&models.SceneBoardButton{},
+	&models.Effect{},
+	&models.Submaster{},
+	&models.FixtureGroup{},
+	&models.GroupValue{},
+	&models.Palette{},
+	&models.Schedule{},
+	&models.SoftPatch{},
+	&models.Universe{},
+	&models.LayoutZone{},
+	&models.SelectionSet{},
+	&models.HouseLights{},
+	&models.PixelMatrix{},
+	&models.UndoOperation{},
+	&models.ProjectSnapshot{},
+	&models.AuditLog{},
+	&models.OFLImportMeta{},
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get sql.DB: %v", err)
+	}
+	// Each connection to :memory: is a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+// schemaOf describes a database's tables, columns, indexes and foreign keys,
+// leaving out schema_versions.
+func schemaOf(t *testing.T, db *gorm.DB) map[string][]string {
+	t.Helper()
+	var tables []string
+	db.Raw(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_versions' ORDER BY name`).Scan(&tables)
+
+	schema := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		var columns []struct {
+			Name      string
+			Type      string
+			NotNull   int
+			DfltValue *string
+			Pk        int
+		}
+		db.Raw(fmt.Sprintf(`SELECT name, type, "notnull" AS not_null, dflt_value, pk FROM pragma_table_info(%q) ORDER BY name`, table)).Scan(&columns)
+		var indexes []struct {
+			Name    string
+			Unique  int
+			Columns string
+		}
+		db.Raw(fmt.Sprintf(`SELECT il.name, il."unique", (SELECT group_concat(name) FROM pragma_index_info(il.name)) AS columns FROM pragma_index_list(%q) il WHERE il.origin = 'c' ORDER BY il.name`, table)).Scan(&indexes)
+		var foreignKeys []struct {
+			Table string
+			From  string
+			To    string
+		}
+		db.Raw(fmt.Sprintf(`SELECT "table", "from", "to" FROM pragma_foreign_key_list(%q) ORDER BY "from"`, table)).Scan(&foreignKeys)
+
+		var description []string
+		for _, c := range columns {
+			dflt := "<nil>"
+			if c.DfltValue != nil {
+				dflt = *c.DfltValue
+			}
+			description = append(description, fmt.Sprintf("column %s %s notnull=%d default=%s pk=%d", c.Name, c.Type, c.NotNull, dflt, c.Pk))
+		}
+		for _, i := range indexes {
+			description = append(description, fmt.Sprintf("index %s unique=%d (%s)", i.Name, i.Unique, i.Columns))
+		}
+		for _, fk := range foreignKeys {
+			description = append(description, fmt.Sprintf("foreign key %s -> %s.%s", fk.From, fk.Table, fk.To))
+		}
+		schema[table] = description
+	}
+	return schema
+}
+
+// TestMigrationsMatchModels fails when a model changes without a migration.
+func TestMigrationsMatchModels(t *testing.T) {
+	migrated := openDB(t)
+	if _, err := New(migrated).Up(context.Background()); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	autoMigrated := openDB(t)
+	if err := autoMigrated.AutoMigrate(schemaModels...); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	want := schemaOf(t, autoMigrated)
+	got := schemaOf(t, migrated)
+	for table, description := range want {
+		if !reflect.DeepEqual(got[table], description) {
+			t.Errorf("Migrations give %s\n%v\nbut the models want\n%v", table, got[table], description)
+		}
+	}
+	for table := range got {
+		if _, ok := want[table]; !ok {
+			t.Errorf("Migrations create %s, which no model uses", table)
+		}
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := New(db)
+
+	if err := migrator.Check(ctx); !errors.Is(err, ErrPending) {
+		t.Errorf("Expected an empty database to have pending migrations, got %v", err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("Expected %d migrations applied, got %d", len(all), len(applied))
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Expected the schema to be up to date, got %v", err)
+	}
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Current != status.Latest || !status.UpToDate() {
+		t.Errorf("Expected version %d, got %+v", status.Latest, status)
+	}
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("Expected a second Up to do nothing, got %d applied and %v", len(applied), err)
+	}
+
+	reverted, err := migrator.Down(ctx, 0)
+	if err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if len(reverted) != len(all) {
+		t.Errorf("Expected %d migrations reverted, got %d", len(all), len(reverted))
+	}
+	if schema := schemaOf(t, db); len(schema) != 0 {
+		t.Errorf("Expected no tables after reverting everything, got %d", len(schema))
+	}
+
+	// And back up again
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up after Down failed: %v", err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Expected the schema to be up to date, got %v", err)
+	}
+}
+
+// TestBaselineAdoptsAutoMigratedDatabase upgrades a database made by a
+// release before versioned migrations.
+func TestBaselineAdoptsAutoMigratedDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	// An older release's projects table, before naming and fade defaults
+	if err := db.Exec(`CREATE TABLE "projects" ("id" text,"name" text,"description" text,"created_at" datetime,"updated_at" datetime,PRIMARY KEY ("id"))`).Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO projects (id, name) VALUES ('p1', 'Old Show')`).Error; err != nil {
+		t.Fatalf("Failed to insert project: %v", err)
+	}
+
+	if _, err := New(db).Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var project models.Project
+	if err := db.First(&project, "id = ?", "p1").Error; err != nil || project.Name != "Old Show" {
+		t.Errorf("Expected the project to survive, got %+v and %v", project, err)
+	}
+	autoMigrated := openDB(t)
+	if err := autoMigrated.AutoMigrate(&models.Project{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	// Added columns come last, so compare them by name
+	if got, want := schemaOf(t, db)["projects"], schemaOf(t, autoMigrated)["projects"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the adopted projects table to match the model\ngot  %v\nwant %v", got, want)
+	}
+}
+
+// TestBaselineAdoptsCurrentDatabase upgrades a database made by the last
+// release before versioned migrations, which already has every table.
+func TestBaselineAdoptsCurrentDatabase(t *testing.T) {
+	db := openDB(t)
+	if err := db.AutoMigrate(schemaModels...); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	before := schemaOf(t, db)
+
+	if _, err := New(db).Up(context.Background()); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if after := schemaOf(t, db); !reflect.DeepEqual(after, before) {
+		t.Error("Expected the baseline to leave a current schema unchanged")
+	}
+}
+
+func TestNewerSchemaIsRefused(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := New(db)
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if err := db.Create(&SchemaVersion{Version: 9999, Name: "from_the_future"}).Error; err != nil {
+		t.Fatalf("Failed to record version: %v", err)
+	}
+
+	if err := migrator.Check(ctx); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Expected Check to refuse a newer schema, got %v", err)
+	}
+	if _, err := migrator.Up(ctx); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Expected Up to refuse a newer schema, got %v", err)
+	}
+	if _, err := migrator.Down(ctx, 0); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Expected Down to refuse to revert past an unknown version, got %v", err)
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "create_widgets", Up: func(tx *gorm.DB) error {
+			return tx.Exec(`CREATE TABLE widgets (id text PRIMARY KEY)`).Error
+		}},
+		{Version: 2, Name: "broken", Up: func(tx *gorm.DB) error {
+			if err := tx.Exec(`CREATE TABLE gadgets (id text PRIMARY KEY)`).Error; err != nil {
+				return err
+			}
+			return errors.New("boom")
+		}},
+	}}
+
+	applied, err := migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("Expected the broken migration to fail")
+	}
+	if len(applied) != 1 {
+		t.Errorf("Expected the first migration applied, got %d", len(applied))
+	}
+	if !db.Migrator().HasTable("widgets") || db.Migrator().HasTable("gadgets") {
+		t.Error("Expected the broken migration's changes to be rolled back")
+	}
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Current != 1 || len(status.Pending) != 1 {
+		t.Errorf("Expected version 1 with one pending, got %+v", status)
+	}
+
+	// Without Down, the first cannot be reverted
+	if _, err := migrator.Down(ctx, 0); err == nil {
+		t.Error("Expected reverting a migration without Down to fail")
+	}
+}
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	migrator := &Migrator{db: openDB(t), migrations: []Migration{
+		{Version: 2, Name: "second", Up: func(*gorm.DB) error { return nil }},
+		{Version: 1, Name: "first", Up: func(*gorm.DB) error { return nil }},
+	}}
+	if _, err := migrator.Status(context.Background()); err == nil {
+		t.Error("Expected out of order migrations to be rejected")
+	}
+	if err := New(openDB(t)).validate(); err != nil {
+		t.Errorf("Expected the schema's migrations to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// pixelMatrices adds the table of the grids fixtures are pixel mapped on.
+var pixelMatrices = Migration{
+	Version: 4,
+	Name:    "pixel_matrices",
+	Up: func(tx *gorm.DB) error {
+		return baselineTable{
+			name: "pixel_matrices",
+			columns: []string{
+				`"id" text`,
+				`"project_id" text`,
+				`"name" text`,
+				`"width" integer`,
+				`"height" integer`,
+				`"pixels" text DEFAULT "[]"`,
+				`"created_at" datetime`,
+				`"updated_at" datetime`,
+			},
+			indexes: []string{
+				`CREATE INDEX IF NOT EXISTS "idx_pixel_matrices_project_id" ON "pixel_matrices"("project_id")`,
+			},
+		}.create(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS "pixel_matrices"`).Error
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// sceneBoardAudioTriggers adds the options for scene board buttons to be
+// triggered by beats of the audio input.
+var sceneBoardAudioTriggers = Migration{
+	Version: 6,
+	Name:    "scene_board_audio_triggers",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []struct{ name, definition string }{
+			{"audio_trigger", `numeric DEFAULT false`},
+			{"audio_band", `text`},
+			{"audio_action", `text DEFAULT "BUMP"`},
+		} {
+			if tx.Migrator().HasColumn("scene_board_buttons", column.name) {
+				continue
+			}
+			if err := tx.Exec(`ALTER TABLE "scene_board_buttons" ADD COLUMN "` + column.name + `" ` + column.definition).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, column := range []string{"audio_trigger", "audio_band", "audio_action"} {
+			if err := tx.Exec(`ALTER TABLE "scene_board_buttons" DROP COLUMN "` + column + `"`).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -9,12 +9,14 @@ import (
 // User represents a user in the system.
 // Table: users
 type User struct {
-	ID        string    `gorm:"column:id;primaryKey"`
-	Email     string    `gorm:"column:email;uniqueIndex"`
-	Name      *string   `gorm:"column:name"`
-	Role      string    `gorm:"column:role;default:USER"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID    string  `gorm:"column:id;primaryKey"`
+	Email string  `gorm:"column:email;uniqueIndex"`
+	Name  *string `gorm:"column:name"`
+	Role  string  `gorm:"column:role;default:USER"`
+	// PasswordHash is never exposed through GraphQL or JSON
+	PasswordHash *string   `gorm:"column:password_hash" json:"-"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
 }
 
 func (User) TableName() string { return "users" }
@@ -28,6 +30,17 @@ type Project struct {
 	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
+	// Naming conventions applied when scenes and cues are created (all optional)
+	SceneNamePattern *string `gorm:"column:scene_name_pattern"` // e.g. "Sc {act}.{n}"
+	CueNamePattern   *string `gorm:"column:cue_name_pattern"`
+	NameUniqueness   *string `gorm:"column:name_uniqueness"`   // ALLOW_DUPLICATES (default), REJECT_DUPLICATES, APPEND_SUFFIX
+	NamingVariables  *string `gorm:"column:naming_variables"`  // JSON object of custom pattern tokens
+	DefaultSceneSort *string `gorm:"column:default_scene_sort"` // SceneSortField used when a query gives none
+
+	// Scene fade times used when no call, button, board, or scene sets one (seconds, optional)
+	DefaultFadeIn  *float64 `gorm:"column:default_fade_in"`
+	DefaultFadeOut *float64 `gorm:"column:default_fade_out"`
+
 	// Relations (loaded separately)
 	Fixtures  []FixtureInstance `gorm:"foreignKey:ProjectID"`
 	Scenes    []Scene           `gorm:"foreignKey:ProjectID"`
@@ -83,10 +96,30 @@ type ChannelDefinition struct {
 	FadeBehavior string `gorm:"column:fade_behavior;default:FADE"` // FadeBehavior enum: FADE, SNAP, SNAP_END
 	IsDiscrete   bool   `gorm:"column:is_discrete;default:false"`  // True if channel has multiple discrete DMX ranges
 	DefinitionID string `gorm:"column:definition_id;index"`
+	Cell         *int   `gorm:"column:cell"` // 0-indexed cell of a multi-cell fixture; nil for master channels
+
+	// Metadata from imported GDTF profiles
+	Attribute *string `gorm:"column:attribute"` // GDTF attribute, e.g. ColorAdd_R
+	Color     *string `gorm:"column:color"`     // Hex color of a color mixing emitter
 }
 
 func (ChannelDefinition) TableName() string { return "channel_definitions" }
 
+// ChannelCapability is a DMX range of a channel with its own function, such
+// as a color or gobo wheel slot.
+// Table: channel_capabilities
+type ChannelCapability struct {
+	ID        string  `gorm:"column:id;primaryKey"`
+	ChannelID string  `gorm:"column:channel_id;index"`
+	Name      string  `gorm:"column:name"`
+	MinValue  int     `gorm:"column:min_value"`
+	MaxValue  int     `gorm:"column:max_value"`
+	Color     *string `gorm:"column:color"` // Hex color of a color wheel slot
+	Image     *string `gorm:"column:image"` // Media file of a gobo wheel slot
+}
+
+func (ChannelCapability) TableName() string { return "channel_capabilities" }
+
 // FixtureMode represents a mode within a fixture definition.
 // Table: fixture_modes
 type FixtureMode struct {
@@ -135,6 +168,9 @@ type FixtureInstance struct {
 	LayoutY        *float64 `gorm:"column:layout_y"`
 	LayoutRotation *float64 `gorm:"column:layout_rotation"`
 
+	// Venue-imposed intensity ceiling (0-1), enforced at the DMX output stage
+	MaxIntensity *float64 `gorm:"column:max_intensity"`
+
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
@@ -158,6 +194,7 @@ type InstanceChannel struct {
 	DefaultValue int    `gorm:"column:default_value;default:0"`
 	FadeBehavior string `gorm:"column:fade_behavior;default:FADE"` // FadeBehavior enum: FADE, SNAP, SNAP_END
 	IsDiscrete   bool   `gorm:"column:is_discrete;default:false"`  // True if channel has multiple discrete DMX ranges
+	Cell         *int   `gorm:"column:cell"`                       // 0-indexed cell of a multi-cell fixture; nil for master channels
 }
 
 func (InstanceChannel) TableName() string { return "instance_channels" }
@@ -165,12 +202,15 @@ func (InstanceChannel) TableName() string { return "instance_channels" }
 // Scene represents a lighting scene.
 // Table: scenes
 type Scene struct {
-	ID          string    `gorm:"column:id;primaryKey"`
-	Name        string    `gorm:"column:name"`
-	Description *string   `gorm:"column:description"`
-	ProjectID   string    `gorm:"column:project_id;index"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID             string    `gorm:"column:id;primaryKey"`
+	Name           string    `gorm:"column:name"`
+	SecondaryLabel *string   `gorm:"column:secondary_label"` // Optional alternate name (translation, operator shorthand)
+	Description    *string   `gorm:"column:description"`
+	ProjectID      string    `gorm:"column:project_id;index"`
+	DefaultFadeIn  *float64  `gorm:"column:default_fade_in"`  // Seconds; used when no call, button, or board sets a time
+	DefaultFadeOut *float64  `gorm:"column:default_fade_out"` // Seconds; used when releasing a held button
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	FixtureValues []FixtureValue `gorm:"foreignKey:SceneID"`
@@ -187,11 +227,13 @@ type ChannelValue struct {
 // FixtureValue represents fixture channel values within a scene.
 // Table: fixture_values
 type FixtureValue struct {
-	ID         string `gorm:"column:id;primaryKey"`
-	SceneID    string `gorm:"column:scene_id;index"`
-	FixtureID  string `gorm:"column:fixture_id;index"`
-	Channels   string `gorm:"column:channels;default:[]"` // JSON array of ChannelValue
-	SceneOrder *int   `gorm:"column:scene_order"`
+	ID         string  `gorm:"column:id;primaryKey"`
+	SceneID    string  `gorm:"column:scene_id;index"`
+	FixtureID  string  `gorm:"column:fixture_id;index"`
+	Channels   string  `gorm:"column:channels;default:[]"` // JSON array of ChannelValue
+	SceneOrder *int    `gorm:"column:scene_order"`
+	GroupID    *string `gorm:"column:group_id;index"`         // Set when derived from the scene's value for this fixture group
+	PaletteIDs string  `gorm:"column:palette_ids;default:[]"` // JSON array of palette IDs whose values the channels take
 }
 
 func (FixtureValue) TableName() string { return "fixture_values" }
@@ -199,13 +241,15 @@ func (FixtureValue) TableName() string { return "fixture_values" }
 // CueList represents a cue list (sequence of cues).
 // Table: cue_lists
 type CueList struct {
-	ID          string    `gorm:"column:id;primaryKey"`
-	Name        string    `gorm:"column:name"`
-	Description *string   `gorm:"column:description"`
-	Loop        bool      `gorm:"column:loop;default:false"`
-	ProjectID   string    `gorm:"column:project_id;index"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID           string    `gorm:"column:id;primaryKey"`
+	Name         string    `gorm:"column:name"`
+	Description  *string   `gorm:"column:description"`
+	Loop         bool      `gorm:"column:loop;default:false"`
+	PlaybackMode string    `gorm:"column:playback_mode;default:TIMED"` // TIMED or CROSSFADER
+	Tracking     bool      `gorm:"column:tracking;default:false"`      // Cue values persist through later cues until changed
+	ProjectID    string    `gorm:"column:project_id;index"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	Cues []Cue `gorm:"foreignKey:CueListID"`
@@ -216,18 +260,23 @@ func (CueList) TableName() string { return "cue_lists" }
 // Cue represents a lighting cue within a cue list.
 // Table: cues
 type Cue struct {
-	ID          string    `gorm:"column:id;primaryKey"`
-	Name        string    `gorm:"column:name"`
-	CueNumber   float64   `gorm:"column:cue_number"`
-	CueListID   string    `gorm:"column:cue_list_id;index"`
-	SceneID     string    `gorm:"column:scene_id;index"`
-	FadeInTime  float64   `gorm:"column:fade_in_time;default:0"`
-	FadeOutTime float64   `gorm:"column:fade_out_time;default:0"`
-	FollowTime  *float64  `gorm:"column:follow_time"`
-	EasingType  *string   `gorm:"column:easing_type"`
-	Notes       *string   `gorm:"column:notes"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID             string    `gorm:"column:id;primaryKey"`
+	Name           string    `gorm:"column:name"`
+	SecondaryLabel *string   `gorm:"column:secondary_label"` // Optional alternate name (translation, operator shorthand)
+	CueNumber      float64   `gorm:"column:cue_number"`
+	CueListID      string    `gorm:"column:cue_list_id;index"`
+	SceneID        string    `gorm:"column:scene_id;index"`
+	FadeInTime     float64   `gorm:"column:fade_in_time;default:0"`
+	FadeOutTime    float64   `gorm:"column:fade_out_time;default:0"`
+	FollowTime     *float64  `gorm:"column:follow_time"`
+	FollowQuantize *string   `gorm:"column:follow_quantize"` // BEAT or BAR: delay the auto-follow to the tempo clock
+	EasingType     *string   `gorm:"column:easing_type"`
+	Notes          *string   `gorm:"column:notes"`
+	Timecode       *string   `gorm:"column:timecode"`            // HH:MM:SS:FF position that fires the cue under timecode
+	Block          bool      `gorm:"column:block;default:false"` // Stops earlier values tracking into this cue
+	MoveInBlack    bool      `gorm:"column:move_in_black;default:false"` // Moves fixtures dark in the cue before into this cue's positions
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	Scene *Scene `gorm:"foreignKey:SceneID"`
@@ -242,6 +291,7 @@ type PreviewSession struct {
 	ProjectID string    `gorm:"column:project_id;index"`
 	UserID    string    `gorm:"column:user_id;index"`
 	IsActive  bool      `gorm:"column:is_active;default:true"`
+	Blind     bool      `gorm:"column:blind;default:false"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 }
@@ -271,6 +321,9 @@ type SceneBoard struct {
 	GridSize        *int      `gorm:"column:grid_size;default:50"`
 	CanvasWidth     int       `gorm:"column:canvas_width;default:2000"`
 	CanvasHeight    int       `gorm:"column:canvas_height;default:2000"`
+	HoldRampTime    float64   `gorm:"column:hold_ramp_time;default:3.0"`     // Seconds of hold to reach full level
+	HoldCurve       string    `gorm:"column:hold_curve;default:LINEAR"`      // EasingType applied to the hold ramp
+	HoldReleaseMode string    `gorm:"column:hold_release_mode;default:LATCH"` // LATCH or RELEASE
 	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
@@ -283,17 +336,26 @@ func (SceneBoard) TableName() string { return "scene_boards" }
 // SceneBoardButton represents a button on a scene board.
 // Table: scene_board_buttons
 type SceneBoardButton struct {
-	ID           string    `gorm:"column:id;primaryKey"`
-	SceneBoardID string    `gorm:"column:scene_board_id;index"`
-	SceneID      string    `gorm:"column:scene_id;index"`
-	LayoutX      int       `gorm:"column:layout_x"`
-	LayoutY      int       `gorm:"column:layout_y"`
-	Width        *int      `gorm:"column:width;default:200"`
-	Height       *int      `gorm:"column:height;default:120"`
-	Color        *string   `gorm:"column:color"`
-	Label        *string   `gorm:"column:label"`
-	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID               string    `gorm:"column:id;primaryKey"`
+	SceneBoardID     string    `gorm:"column:scene_board_id;index"`
+	SceneID          string    `gorm:"column:scene_id;index"`
+	LayoutX          int       `gorm:"column:layout_x"`
+	LayoutY          int       `gorm:"column:layout_y"`
+	Width            *int      `gorm:"column:width;default:200"`
+	Height           *int      `gorm:"column:height;default:120"`
+	Color            *string   `gorm:"column:color"`
+	Label            *string   `gorm:"column:label"`
+	FadeInTime       *float64  `gorm:"column:fade_in_time"`             // Overrides the board's default fade time (optional)
+	FadeOutTime      *float64  `gorm:"column:fade_out_time"`            // Overrides the board's default when released (optional)
+	FlashMode        bool      `gorm:"column:flash_mode;default:false"` // Pressing flashes the scene instead of activating it
+	FlashLevel       *float64  `gorm:"column:flash_level"`              // 0-1 level a flash raises the scene to (optional, defaults to full)
+	FlashReleaseTime *float64  `gorm:"column:flash_release_time"`       // Seconds a flash fades out over when released (optional, defaults to 0)
+	Macro            string    `gorm:"column:macro;default:[]"`         // JSON array of macro actions run by executeMacro
+	AudioTrigger     bool      `gorm:"column:audio_trigger;default:false"` // Beats of the audio input trigger the button
+	AudioBand        *string   `gorm:"column:audio_band"`                  // Band whose beats trigger it (optional, defaults to the first)
+	AudioAction      string    `gorm:"column:audio_action;default:BUMP"`   // BUMP flashes the scene; ADVANCE_EFFECTS steps its effects
+	CreatedAt        time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	Scene *Scene `gorm:"foreignKey:SceneID"`
@@ -301,6 +363,271 @@ type SceneBoardButton struct {
 
 func (SceneBoardButton) TableName() string { return "scene_board_buttons" }
 
+// Effect represents a chase or waveform generator over a set of fixtures.
+// Table: effects
+type Effect struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	ProjectID   string    `gorm:"column:project_id;index"`
+	SceneID     *string   `gorm:"column:scene_id;index"`         // Runs while this scene is active (optional)
+	Type        string    `gorm:"column:type"`                   // CHASE, SINE, RAMP, or RAINBOW
+	FixtureIDs  string    `gorm:"column:fixture_ids;default:[]"` // JSON array of fixture IDs, in effect order
+	Rate        float64   `gorm:"column:rate"`                   // Cycles per second
+	Size        float64   `gorm:"column:size"`
+	PhaseOffset float64   `gorm:"column:phase_offset"`
+	Order       string    `gorm:"column:order;default:FORWARD"` // FORWARD, REVERSE, or RANDOM
+	Low         float64   `gorm:"column:low"`
+	High        float64   `gorm:"column:high"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Effect) TableName() string { return "effects" }
+
+// Submaster is a named fader that scales a scene, or the intensity of a
+// fixture selection, by its level. Submasters are laid out on pages of fader
+// slots for a fader wing.
+// Table: submasters
+type Submaster struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	Name       string    `gorm:"column:name"`
+	ProjectID  string    `gorm:"column:project_id;index"`
+	Page       int       `gorm:"column:page;default:1"`         // Fader page, from 1
+	Slot       int       `gorm:"column:slot"`                   // Fader position on the page, from 1
+	SceneID    *string   `gorm:"column:scene_id;index"`         // Scene the fader adds (optional)
+	FixtureIDs string    `gorm:"column:fixture_ids;default:[]"` // JSON array of fixture IDs whose intensity the fader scales
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Submaster) TableName() string { return "submasters" }
+
+// FixtureGroup is a named set of fixtures in a project. Scenes can set values
+// for a group, which its members without values of their own follow.
+// Table: fixture_groups
+type FixtureGroup struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	Description *string   `gorm:"column:description"`
+	ProjectID   string    `gorm:"column:project_id;index"`
+	FixtureIDs  string    `gorm:"column:fixture_ids;default:[]"` // JSON array of fixture IDs
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (FixtureGroup) TableName() string { return "fixture_groups" }
+
+// GroupValue is a scene's channel values for a fixture group, by channel
+// type so they apply to every kind of fixture in the group.
+// Table: group_values
+type GroupValue struct {
+	ID         string `gorm:"column:id;primaryKey"`
+	SceneID    string `gorm:"column:scene_id;index"`
+	GroupID    string `gorm:"column:group_id;index"`
+	Channels   string `gorm:"column:channels;default:[]"` // JSON array of ChannelTypeValue
+	SceneOrder int    `gorm:"column:scene_order"`         // Later groups win for fixtures in several
+}
+
+func (GroupValue) TableName() string { return "group_values" }
+
+// Palette is a reusable preset of color, position, or beam values. Scenes
+// reference palettes per fixture value and take their values on the
+// fixture's channels of each type.
+// Table: palettes
+type Palette struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	ProjectID string    `gorm:"column:project_id;index"`
+	Kind      string    `gorm:"column:kind"`                // COLOR, POSITION, or BEAM
+	Channels  string    `gorm:"column:channels;default:[]"` // JSON array of ChannelTypeValue
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Palette) TableName() string { return "palettes" }
+
+// Schedule activates a scene or goes in a cue list at times set by a cron
+// expression, or at sunrise or sunset. Times are in server local time.
+// Table: schedules
+type Schedule struct {
+	ID            string     `gorm:"column:id;primaryKey"`
+	Name          string     `gorm:"column:name"`
+	ProjectID     string     `gorm:"column:project_id;index"`
+	Enabled       bool       `gorm:"column:enabled"`
+	Trigger       string     `gorm:"column:trigger"`         // CRON, SUNRISE, or SUNSET
+	Cron          *string    `gorm:"column:cron"`            // Cron expression, for CRON
+	Latitude      *float64   `gorm:"column:latitude"`        // Degrees north, for SUNRISE and SUNSET
+	Longitude     *float64   `gorm:"column:longitude"`       // Degrees east, for SUNRISE and SUNSET
+	OffsetMinutes int        `gorm:"column:offset_minutes"`  // Minutes after sunrise or sunset; negative for before
+	Days          string     `gorm:"column:days;default:[]"` // JSON array of weekdays (0 is Sunday) a SUNRISE or SUNSET fires on; empty for every day
+	Action        string     `gorm:"column:action"`          // ACTIVATE_SCENE or CUE_LIST_GO
+	SceneID       *string    `gorm:"column:scene_id;index"`
+	CueListID     *string    `gorm:"column:cue_list_id;index"`
+	FadeTime      *float64   `gorm:"column:fade_time"` // Seconds, overriding the usual fade time (optional)
+	LastFiredAt   *time.Time `gorm:"column:last_fired_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Schedule) TableName() string { return "schedules" }
+
+// SoftPatch re-maps a logical DMX channel, as fixtures address it, to the
+// output addresses that carry its value, without editing fixtures.
+// Table: soft_patches
+type SoftPatch struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	ProjectID string    `gorm:"column:project_id;index"`
+	Universe  int       `gorm:"column:universe"`
+	Channel   int       `gorm:"column:channel"`
+	Targets   string    `gorm:"column:targets;default:[]"` // JSON array of {universe, channel} output addresses; empty parks the channel
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SoftPatch) TableName() string { return "soft_patches" }
+
+// Universe is a project's configuration of a DMX universe: its label and
+// how it is output. Universes that fixtures are patched to are created
+// automatically.
+// Table: universes
+type Universe struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	ProjectID   string    `gorm:"column:project_id;uniqueIndex:idx_universes_project_number"`
+	Number      int       `gorm:"column:number;uniqueIndex:idx_universes_project_number"`
+	Label       *string   `gorm:"column:label"`       // e.g. "FOH Truss"
+	Protocol    string    `gorm:"column:protocol;default:ARTNET"`
+	Destination *string   `gorm:"column:destination"` // Unicast IPv4 address, optionally with a port; broadcast when nil
+	Enabled     bool      `gorm:"column:enabled"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Universe) TableName() string { return "universes" }
+
+// LayoutZone is a named region of a project's 2D stage layout, such as
+// "FOH Truss" or "Stage Left", in the fixtures' normalized coordinates.
+// Table: layout_zones
+type LayoutZone struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	ProjectID string    `gorm:"column:project_id;index"`
+	Name      string    `gorm:"column:name"`
+	X         float64   `gorm:"column:x"` // Left edge, 0-1
+	Y         float64   `gorm:"column:y"` // Top edge, 0-1
+	Width     float64   `gorm:"column:width"`
+	Height    float64   `gorm:"column:height"`
+	Color     *string   `gorm:"column:color"` // e.g. "#336699"
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (LayoutZone) TableName() string { return "layout_zones" }
+
+// SelectionSet is a saved, ordered list of fixtures. Effects chase through
+// fixtures in list order, so a set keeps the order it was built in.
+// Table: selection_sets
+type SelectionSet struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	ProjectID   string    `gorm:"column:project_id;index"`
+	Name        string    `gorm:"column:name"`
+	Description *string   `gorm:"column:description"`
+	FixtureIDs  string    `gorm:"column:fixture_ids;default:[]"` // JSON array of fixture IDs, in order
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SelectionSet) TableName() string { return "selection_sets" }
+
+// HouseLights are the fixtures and groups front of house raises and lowers,
+// and the levels of their presets. A project has at most one.
+// Table: house_lights
+type HouseLights struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	ProjectID  string    `gorm:"column:project_id;uniqueIndex"`
+	FixtureIDs string    `gorm:"column:fixture_ids;default:[]"` // JSON array of fixture IDs
+	GroupIDs   string    `gorm:"column:group_ids;default:[]"`   // JSON array of fixture group IDs
+	FullLevel  float64   `gorm:"column:full_level"`             // 0-1
+	HalfLevel  float64   `gorm:"column:half_level"`             // 0-1
+	ShowLevel  float64   `gorm:"column:show_level"`             // 0-1, house lights during the show
+	FadeTime   float64   `gorm:"column:fade_time"`              // Seconds, when a preset does not set one
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (HouseLights) TableName() string { return "house_lights" }
+
+// PixelMatrix places fixtures, or the cells of multi-cell fixtures, on a
+// grid that images, gradients, and video frames are mapped onto.
+// Table: pixel_matrices
+type PixelMatrix struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	ProjectID string    `gorm:"column:project_id;index"`
+	Name      string    `gorm:"column:name"`
+	Width     int       `gorm:"column:width"`
+	Height    int       `gorm:"column:height"`
+	Pixels    string    `gorm:"column:pixels;default:[]"` // JSON array of {x, y, fixtureId, cell}
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (PixelMatrix) TableName() string { return "pixel_matrices" }
+
+// UndoOperation is one undoable project edit, holding the affected records
+// as they were before and after it.
+// Table: undo_operations
+type UndoOperation struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	ProjectID   string    `gorm:"column:project_id;index"`
+	Seq         int       `gorm:"column:seq"` // Order within the project's history
+	Description string    `gorm:"column:description"`
+	Targets     string    `gorm:"column:targets"` // JSON array of the records the edit touched
+	Before      string    `gorm:"column:before"`  // JSON snapshot of the targets before the edit
+	After       string    `gorm:"column:after"`   // JSON snapshot of the targets after the edit
+	Undone      bool      `gorm:"column:undone"`  // Undone edits make up the redo stack
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (UndoOperation) TableName() string { return "undo_operations" }
+
+// ProjectSnapshot is a saved copy of a project that can be restored.
+// Table: project_snapshots
+type ProjectSnapshot struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	ProjectID   string    `gorm:"column:project_id;index"` // Kept after the project is deleted
+	ProjectName string    `gorm:"column:project_name"`
+	Reason      string    `gorm:"column:reason"`   // MANUAL, SCHEDULED, BEFORE_IMPORT, BEFORE_DELETE
+	Content     []byte    `gorm:"column:content"`  // Gzipped project export JSON
+	Size        int       `gorm:"column:size"`     // Uncompressed export size in bytes
+	Checksum    string    `gorm:"column:checksum"` // SHA-256 of the export's project content
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (ProjectSnapshot) TableName() string { return "project_snapshots" }
+
+// AuditLog records one mutation: who made it, what it touched, and what
+// changed.
+// Table: audit_logs
+type AuditLog struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	ProjectID  *string   `gorm:"column:project_id;index"` // Null for mutations outside any project
+	Operator   string    `gorm:"column:operator;index"`   // Operator name sent by the client, or its address
+	Operation  string    `gorm:"column:operation"`        // Mutation field name
+	EntityType string    `gorm:"column:entity_type;index"`
+	EntityID   *string   `gorm:"column:entity_id;index"`
+	Summary    string    `gorm:"column:summary"`
+	Before     *string   `gorm:"column:before"` // JSON state of the entity before the mutation
+	After      *string   `gorm:"column:after"`  // JSON state of the entity after the mutation
+	Error      *string   `gorm:"column:error"`  // Set when the mutation failed
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime;index"`
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// ChannelTypeValue is a group or palette value for every channel of a type.
+type ChannelTypeValue struct {
+	Type  string `json:"type"`
+	Value int    `json:"value"`
+}
+
 // OFLImportMeta tracks the history of OFL imports.
 // Table: ofl_import_meta
 type OFLImportMeta struct {
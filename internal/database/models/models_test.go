@@ -13,6 +13,7 @@ func TestTableNames(t *testing.T) {
 		{"ProjectUser", ProjectUser{}, "project_users"},
 		{"FixtureDefinition", FixtureDefinition{}, "fixture_definitions"},
 		{"ChannelDefinition", ChannelDefinition{}, "channel_definitions"},
+		{"ChannelCapability", ChannelCapability{}, "channel_capabilities"},
 		{"FixtureMode", FixtureMode{}, "fixture_modes"},
 		{"ModeChannel", ModeChannel{}, "mode_channels"},
 		{"FixtureInstance", FixtureInstance{}, "fixture_instances"},
@@ -25,6 +26,14 @@ func TestTableNames(t *testing.T) {
 		{"Setting", Setting{}, "settings"},
 		{"SceneBoard", SceneBoard{}, "scene_boards"},
 		{"SceneBoardButton", SceneBoardButton{}, "scene_board_buttons"},
+		{"Effect", Effect{}, "effects"},
+		{"Submaster", Submaster{}, "submasters"},
+		{"FixtureGroup", FixtureGroup{}, "fixture_groups"},
+		{"GroupValue", GroupValue{}, "group_values"},
+		{"Palette", Palette{}, "palettes"},
+		{"UndoOperation", UndoOperation{}, "undo_operations"},
+		{"ProjectSnapshot", ProjectSnapshot{}, "project_snapshots"},
+		{"AuditLog", AuditLog{}, "audit_logs"},
 		{"OFLImportMeta", OFLImportMeta{}, "ofl_import_meta"},
 	}
 
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter selects audit log entries. Unset fields match everything.
+type AuditLogFilter struct {
+	ProjectID  *string
+	EntityType *string
+	EntityID   *string
+	Operator   *string
+	Since      *time.Time // Inclusive
+	Until      *time.Time // Exclusive
+}
+
+// AuditRepository handles audit log data access.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new AuditRepository.
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create records an audit log entry.
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	if entry.ID == "" {
+		entry.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// Find returns a page of the entries matching filter, newest first, with the
+// total number of matches.
+func (r *AuditRepository) Find(ctx context.Context, filter AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.EntityType != nil {
+		query = query.Where("entity_type = ?", *filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Operator != nil {
+		query = query.Where("operator = ?", *filter.Operator)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var entries []models.AuditLog
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
+
+// DeleteBefore deletes entries recorded before cutoff, returning how many
+// were deleted.
+func (r *AuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&models.AuditLog{}, "created_at < ?", cutoff)
+	return result.RowsAffected, result.Error
+}
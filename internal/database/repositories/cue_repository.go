@@ -134,3 +134,15 @@ func (r *CueRepository) FindByCueListID(ctx context.Context, cueListID string) (
 		Find(&cues)
 	return cues, result.Error
 }
+
+// UpdateCueNumbers sets the cue numbers of cues by ID, all or none.
+func (r *CueRepository) UpdateCueNumbers(ctx context.Context, numbers map[string]float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, number := range numbers {
+			if err := tx.Model(&models.Cue{}).Where("id = ?", id).Update("cue_number", number).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// FixtureGroupRepository handles fixture group data access.
+type FixtureGroupRepository struct {
+	db *gorm.DB
+}
+
+// NewFixtureGroupRepository creates a new FixtureGroupRepository.
+func NewFixtureGroupRepository(db *gorm.DB) *FixtureGroupRepository {
+	return &FixtureGroupRepository{db: db}
+}
+
+// GroupFixtureIDs decodes a stored group's fixture IDs.
+func GroupFixtureIDs(group *models.FixtureGroup) ([]string, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(group.FixtureIDs), &ids); err != nil {
+		return nil, fmt.Errorf("invalid fixture list for group %s: %w", group.ID, err)
+	}
+	return ids, nil
+}
+
+// FindByProjectID returns all fixture groups in a project, by name.
+func (r *FixtureGroupRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.FixtureGroup, error) {
+	var groups []models.FixtureGroup
+	result := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&groups)
+	return groups, result.Error
+}
+
+// FindByID returns a fixture group by ID.
+func (r *FixtureGroupRepository) FindByID(ctx context.Context, id string) (*models.FixtureGroup, error) {
+	var group models.FixtureGroup
+	result := r.db.WithContext(ctx).First(&group, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &group, nil
+}
+
+// FindByFixtureID returns the groups a fixture belongs to.
+func (r *FixtureGroupRepository) FindByFixtureID(ctx context.Context, fixtureID string) ([]models.FixtureGroup, error) {
+	var candidates []models.FixtureGroup
+	pattern := fmt.Sprintf("%%%q%%", fixtureID)
+	if err := r.db.WithContext(ctx).Where("fixture_ids LIKE ?", pattern).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var groups []models.FixtureGroup
+	for _, group := range candidates {
+		ids, err := GroupFixtureIDs(&group)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if id == fixtureID {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	return groups, nil
+}
+
+// Create creates a new fixture group.
+func (r *FixtureGroupRepository) Create(ctx context.Context, group *models.FixtureGroup) error {
+	if group.ID == "" {
+		group.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+// Update updates an existing fixture group.
+func (r *FixtureGroupRepository) Update(ctx context.Context, group *models.FixtureGroup) error {
+	return r.db.WithContext(ctx).Save(group).Error
+}
+
+// Delete deletes a fixture group and its values in every scene, returning
+// the IDs of the scenes that held values for it.
+func (r *FixtureGroupRepository) Delete(ctx context.Context, id string) ([]string, error) {
+	var sceneIDs []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GroupValue{}).Where("group_id = ?", id).Distinct().Pluck("scene_id", &sceneIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.GroupValue{}, "group_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FixtureGroup{}, "id = ?", id).Error
+	})
+	return sceneIDs, err
+}
+
+// DeleteByProjectID deletes all fixture groups in a project.
+func (r *FixtureGroupRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		groupIDs := tx.Model(&models.FixtureGroup{}).Select("id").Where("project_id = ?", projectID)
+		if err := tx.Where("group_id IN (?)", groupIDs).Delete(&models.GroupValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FixtureGroup{}, "project_id = ?", projectID).Error
+	})
+}
@@ -28,6 +28,16 @@ func (r *FixtureRepository) FindByProjectID(ctx context.Context, projectID strin
 	return fixtures, result.Error
 }
 
+// FindByDefinitionID returns all fixtures using a definition, across projects.
+func (r *FixtureRepository) FindByDefinitionID(ctx context.Context, definitionID string) ([]models.FixtureInstance, error) {
+	var fixtures []models.FixtureInstance
+	result := r.db.WithContext(ctx).
+		Where("definition_id = ?", definitionID).
+		Order("project_id ASC, universe ASC, start_channel ASC").
+		Find(&fixtures)
+	return fixtures, result.Error
+}
+
 // FindByID returns a fixture by ID.
 func (r *FixtureRepository) FindByID(ctx context.Context, id string) (*models.FixtureInstance, error) {
 	var fixture models.FixtureInstance
@@ -106,6 +116,18 @@ func (r *FixtureRepository) GetInstanceChannels(ctx context.Context, fixtureID s
 	return channels, result.Error
 }
 
+// GetProjectInstanceChannels returns the channels of all fixtures in a
+// project, by fixture and offset.
+func (r *FixtureRepository) GetProjectInstanceChannels(ctx context.Context, projectID string) ([]models.InstanceChannel, error) {
+	var channels []models.InstanceChannel
+	fixtureIDs := r.db.Model(&models.FixtureInstance{}).Select("id").Where("project_id = ?", projectID)
+	result := r.db.WithContext(ctx).
+		Where("fixture_id IN (?)", fixtureIDs).
+		Order("fixture_id ASC, offset ASC").
+		Find(&channels)
+	return channels, result.Error
+}
+
 // GetDefinitionChannels returns all channels for a fixture definition.
 func (r *FixtureRepository) GetDefinitionChannels(ctx context.Context, definitionID string) ([]models.ChannelDefinition, error) {
 	var channels []models.ChannelDefinition
@@ -234,8 +256,15 @@ func (r *FixtureRepository) CreateChannelDefinitions(ctx context.Context, channe
 	return r.db.WithContext(ctx).Create(&channels).Error
 }
 
-// DeleteChannelDefinitions deletes all channel definitions for a fixture definition.
+// DeleteChannelDefinitions deletes all channel definitions for a fixture definition
+// along with their capabilities.
 func (r *FixtureRepository) DeleteChannelDefinitions(ctx context.Context, definitionID string) error {
+	subQuery := r.db.Model(&models.ChannelDefinition{}).Select("id").Where("definition_id = ?", definitionID)
+	if err := r.db.WithContext(ctx).
+		Where("channel_id IN (?)", subQuery).
+		Delete(&models.ChannelCapability{}).Error; err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Delete(&models.ChannelDefinition{}, "definition_id = ?", definitionID).Error
 }
 
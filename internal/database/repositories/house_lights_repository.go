@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// HouseLightsRepository handles house lights data access.
+type HouseLightsRepository struct {
+	db *gorm.DB
+}
+
+// NewHouseLightsRepository creates a new HouseLightsRepository.
+func NewHouseLightsRepository(db *gorm.DB) *HouseLightsRepository {
+	return &HouseLightsRepository{db: db}
+}
+
+// HouseLightsTargets decodes stored house lights' fixture and group IDs.
+func HouseLightsTargets(lights *models.HouseLights) (fixtureIDs, groupIDs []string, err error) {
+	if err := json.Unmarshal([]byte(lights.FixtureIDs), &fixtureIDs); err != nil {
+		return nil, nil, fmt.Errorf("invalid fixture list for house lights %s: %w", lights.ID, err)
+	}
+	if err := json.Unmarshal([]byte(lights.GroupIDs), &groupIDs); err != nil {
+		return nil, nil, fmt.Errorf("invalid group list for house lights %s: %w", lights.ID, err)
+	}
+	return fixtureIDs, groupIDs, nil
+}
+
+// FindByProjectID returns a project's house lights, or nil when they have
+// not been set up.
+func (r *HouseLightsRepository) FindByProjectID(ctx context.Context, projectID string) (*models.HouseLights, error) {
+	var lights models.HouseLights
+	result := r.db.WithContext(ctx).First(&lights, "project_id = ?", projectID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &lights, nil
+}
+
+// Save creates house lights without an ID and updates the others.
+func (r *HouseLightsRepository) Save(ctx context.Context, lights *models.HouseLights) error {
+	if lights.ID == "" {
+		lights.ID = cuid.New()
+		return r.db.WithContext(ctx).Create(lights).Error
+	}
+	return r.db.WithContext(ctx).Save(lights).Error
+}
+
+// DeleteByProjectID deletes a project's house lights.
+func (r *HouseLightsRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.HouseLights{}, "project_id = ?", projectID).Error
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// LayoutZoneRepository handles layout zone data access.
+type LayoutZoneRepository struct {
+	db *gorm.DB
+}
+
+// NewLayoutZoneRepository creates a new LayoutZoneRepository.
+func NewLayoutZoneRepository(db *gorm.DB) *LayoutZoneRepository {
+	return &LayoutZoneRepository{db: db}
+}
+
+// FindByProjectID returns a project's layout zones by name.
+func (r *LayoutZoneRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.LayoutZone, error) {
+	var zones []models.LayoutZone
+	result := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&zones)
+	return zones, result.Error
+}
+
+// FindByID returns a layout zone by ID.
+func (r *LayoutZoneRepository) FindByID(ctx context.Context, id string) (*models.LayoutZone, error) {
+	var zone models.LayoutZone
+	result := r.db.WithContext(ctx).First(&zone, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &zone, nil
+}
+
+// Create creates a new layout zone.
+func (r *LayoutZoneRepository) Create(ctx context.Context, zone *models.LayoutZone) error {
+	if zone.ID == "" {
+		zone.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(zone).Error
+}
+
+// Update updates an existing layout zone.
+func (r *LayoutZoneRepository) Update(ctx context.Context, zone *models.LayoutZone) error {
+	return r.db.WithContext(ctx).Save(zone).Error
+}
+
+// Delete deletes a layout zone by ID.
+func (r *LayoutZoneRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.LayoutZone{}, "id = ?", id).Error
+}
+
+// DeleteByProjectID deletes all layout zones in a project.
+func (r *LayoutZoneRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.LayoutZone{}, "project_id = ?", projectID).Error
+}
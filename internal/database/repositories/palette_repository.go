@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// PaletteRepository handles palette data access.
+type PaletteRepository struct {
+	db *gorm.DB
+}
+
+// NewPaletteRepository creates a new PaletteRepository.
+func NewPaletteRepository(db *gorm.DB) *PaletteRepository {
+	return &PaletteRepository{db: db}
+}
+
+// FixtureValuePaletteIDs decodes the palettes a fixture value references.
+func FixtureValuePaletteIDs(value *models.FixtureValue) ([]string, error) {
+	ids := []string{}
+	if value.PaletteIDs == "" {
+		return ids, nil
+	}
+	if err := json.Unmarshal([]byte(value.PaletteIDs), &ids); err != nil {
+		return nil, fmt.Errorf("invalid palette list for fixture value %s: %w", value.ID, err)
+	}
+	return ids, nil
+}
+
+// FindByProjectID returns a project's palettes by name, of one kind if
+// kind is set.
+func (r *PaletteRepository) FindByProjectID(ctx context.Context, projectID string, kind *string) ([]models.Palette, error) {
+	var palettes []models.Palette
+	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	if kind != nil {
+		query = query.Where("kind = ?", *kind)
+	}
+	result := query.Order("name ASC").Find(&palettes)
+	return palettes, result.Error
+}
+
+// FindByID returns a palette by ID.
+func (r *PaletteRepository) FindByID(ctx context.Context, id string) (*models.Palette, error) {
+	var palette models.Palette
+	result := r.db.WithContext(ctx).First(&palette, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &palette, nil
+}
+
+// FindByIDs returns palettes by ID.
+func (r *PaletteRepository) FindByIDs(ctx context.Context, ids []string) (map[string]*models.Palette, error) {
+	var palettes []models.Palette
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&palettes).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Palette, len(palettes))
+	for i := range palettes {
+		byID[palettes[i].ID] = &palettes[i]
+	}
+	return byID, nil
+}
+
+// Create creates a new palette.
+func (r *PaletteRepository) Create(ctx context.Context, palette *models.Palette) error {
+	if palette.ID == "" {
+		palette.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(palette).Error
+}
+
+// Update updates an existing palette.
+func (r *PaletteRepository) Update(ctx context.Context, palette *models.Palette) error {
+	return r.db.WithContext(ctx).Save(palette).Error
+}
+
+// Delete deletes a palette by ID.
+func (r *PaletteRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.Palette{}, "id = ?", id).Error
+}
+
+// DeleteByProjectID deletes all palettes in a project.
+func (r *PaletteRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.Palette{}, "project_id = ?", projectID).Error
+}
+
+// FindFixtureValues returns the fixture values referencing a palette.
+func (r *PaletteRepository) FindFixtureValues(ctx context.Context, paletteID string) ([]models.FixtureValue, error) {
+	var candidates []models.FixtureValue
+	pattern := fmt.Sprintf("%%%q%%", paletteID)
+	if err := r.db.WithContext(ctx).Where("palette_ids LIKE ?", pattern).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var values []models.FixtureValue
+	for _, value := range candidates {
+		ids, err := FixtureValuePaletteIDs(&value)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if id == paletteID {
+				values = append(values, value)
+				break
+			}
+		}
+	}
+	return values, nil
+}
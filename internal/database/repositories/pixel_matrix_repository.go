@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// PixelMatrixRepository handles pixel matrix data access.
+type PixelMatrixRepository struct {
+	db *gorm.DB
+}
+
+// NewPixelMatrixRepository creates a new PixelMatrixRepository.
+func NewPixelMatrixRepository(db *gorm.DB) *PixelMatrixRepository {
+	return &PixelMatrixRepository{db: db}
+}
+
+// FindByProjectID returns a project's pixel matrices by name.
+func (r *PixelMatrixRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.PixelMatrix, error) {
+	var matrices []models.PixelMatrix
+	result := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&matrices)
+	return matrices, result.Error
+}
+
+// FindByID returns a pixel matrix by ID.
+func (r *PixelMatrixRepository) FindByID(ctx context.Context, id string) (*models.PixelMatrix, error) {
+	var matrix models.PixelMatrix
+	result := r.db.WithContext(ctx).First(&matrix, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &matrix, nil
+}
+
+// Create creates a new pixel matrix.
+func (r *PixelMatrixRepository) Create(ctx context.Context, matrix *models.PixelMatrix) error {
+	if matrix.ID == "" {
+		matrix.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(matrix).Error
+}
+
+// Update updates an existing pixel matrix.
+func (r *PixelMatrixRepository) Update(ctx context.Context, matrix *models.PixelMatrix) error {
+	return r.db.WithContext(ctx).Save(matrix).Error
+}
+
+// Delete deletes a pixel matrix by ID.
+func (r *PixelMatrixRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.PixelMatrix{}, "id = ?", id).Error
+}
+
+// DeleteByProjectID deletes all pixel matrices in a project.
+func (r *PixelMatrixRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.PixelMatrix{}, "project_id = ?", projectID).Error
+}
@@ -2,7 +2,10 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/glebarez/sqlite"
@@ -31,17 +34,31 @@ func setupTestDB(t *testing.T) (*testDB, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
 		&models.InstanceChannel{},
 		&models.Scene{},
 		&models.FixtureValue{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
 		&models.CueList{},
 		&models.Cue{},
 		&models.Setting{},
 		&models.SceneBoard{},
 		&models.SceneBoardButton{},
+		&models.Universe{},
+		&models.LayoutZone{},
+		&models.SelectionSet{},
+		&models.HouseLights{},
+		&models.PixelMatrix{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate database: %v", err)
@@ -339,6 +356,38 @@ func TestSettingRepository_CRUD(t *testing.T) {
 	}
 }
 
+// TestSettingRepository_OnChange tests that saved and deleted settings are
+// reported.
+func TestSettingRepository_OnChange(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSettingRepository(testDB.DB)
+	ctx := context.Background()
+	var changes []string
+	repo.OnChange(func(key string, value *string) {
+		if value == nil {
+			changes = append(changes, key+" deleted")
+		} else {
+			changes = append(changes, key+"="+*value)
+		}
+	})
+
+	if _, err := repo.Upsert(ctx, "rate", "30"); err != nil {
+		t.Fatalf("Upsert (create) failed: %v", err)
+	}
+	if _, err := repo.Upsert(ctx, "rate", "60"); err != nil {
+		t.Fatalf("Upsert (update) failed: %v", err)
+	}
+	if err := repo.Delete(ctx, "rate"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	want := []string{"rate=30", "rate=60", "rate deleted"}
+	if len(changes) != len(want) || changes[0] != want[0] || changes[1] != want[1] || changes[2] != want[2] {
+		t.Errorf("Changes = %v, want %v", changes, want)
+	}
+}
+
 // TestNewProjectRepository tests the constructor.
 func TestNewProjectRepository(t *testing.T) {
 	testDB, cleanup := setupTestDB(t)
@@ -2455,3 +2504,321 @@ func TestSceneBoardRepository_Delete_CascadesButtons(t *testing.T) {
 		t.Errorf("Expected 0 buttons in DB after cascade delete, got %d", buttonCountAfter)
 	}
 }
+
+// TestSceneRepository_SyncGroupValues tests deriving fixture values from group values.
+func TestSceneRepository_SyncGroupValues(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	sceneRepo := NewSceneRepository(testDB.DB)
+	groupRepo := NewFixtureGroupRepository(testDB.DB)
+	ctx := context.Background()
+
+	testDB.DB.Create(&models.InstanceChannel{ID: cuid.New(), FixtureID: "fx-1", Offset: 0, Type: "INTENSITY"})
+	testDB.DB.Create(&models.InstanceChannel{ID: cuid.New(), FixtureID: "fx-1", Offset: 1, Type: "RED"})
+	testDB.DB.Create(&models.InstanceChannel{ID: cuid.New(), FixtureID: "fx-2", Offset: 0, Type: "RED"})
+	testDB.DB.Create(&models.FixtureInstance{ID: "fx-1", Name: "One", ProjectID: "p", DefinitionID: "d"})
+	testDB.DB.Create(&models.FixtureInstance{ID: "fx-2", Name: "Two", ProjectID: "p", DefinitionID: "d"})
+
+	all := &models.FixtureGroup{Name: "All", ProjectID: "p", FixtureIDs: `["fx-1","fx-2"]`}
+	reds := &models.FixtureGroup{Name: "Reds", ProjectID: "p", FixtureIDs: `["fx-2"]`}
+	for _, group := range []*models.FixtureGroup{all, reds} {
+		if err := groupRepo.Create(ctx, group); err != nil {
+			t.Fatalf("Create group failed: %v", err)
+		}
+	}
+
+	if err := sceneRepo.ReplaceGroupValues(ctx, "scene", []models.GroupValue{
+		{GroupID: all.ID, Channels: `[{"type":"INTENSITY","value":200},{"type":"RED","value":10}]`},
+		{GroupID: reds.ID, Channels: `[{"type":"RED","value":99}]`},
+	}); err != nil {
+		t.Fatalf("ReplaceGroupValues failed: %v", err)
+	}
+	if err := sceneRepo.SyncGroupValues(ctx, "scene"); err != nil {
+		t.Fatalf("SyncGroupValues failed: %v", err)
+	}
+
+	values, err := sceneRepo.GetFixtureValues(ctx, "scene")
+	if err != nil {
+		t.Fatalf("GetFixtureValues failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, v := range values {
+		got[v.FixtureID] = v.Channels + " " + *v.GroupID
+	}
+	// The later group wins for the fixture in both
+	want := map[string]string{
+		"fx-1": `[{"offset":0,"value":200},{"offset":1,"value":10}] ` + all.ID,
+		"fx-2": `[{"offset":0,"value":99}] ` + reds.ID,
+	}
+	for id, w := range want {
+		if got[id] != w {
+			t.Errorf("%s = %q, want %q", id, got[id], w)
+		}
+	}
+
+	// A saved value is the scene's own and survives the next sync
+	own := values[0]
+	own.Channels = `[{"offset":0,"value":1}]`
+	if err := sceneRepo.UpdateFixtureValue(ctx, &own); err != nil {
+		t.Fatalf("UpdateFixtureValue failed: %v", err)
+	}
+	if err := sceneRepo.SyncGroupValues(ctx, "scene"); err != nil {
+		t.Fatalf("SyncGroupValues failed: %v", err)
+	}
+	kept, err := sceneRepo.GetFixtureValue(ctx, "scene", own.FixtureID)
+	if err != nil || kept == nil || kept.GroupID != nil || kept.Channels != own.Channels {
+		t.Errorf("Own value after sync = %+v (err %v), want %s without a group", kept, err, own.Channels)
+	}
+
+	groups, err := groupRepo.FindByFixtureID(ctx, "fx-2")
+	if err != nil || len(groups) != 2 {
+		t.Errorf("FindByFixtureID returned %d groups (err %v), want 2", len(groups), err)
+	}
+	sceneIDs, err := groupRepo.Delete(ctx, reds.ID)
+	if err != nil || len(sceneIDs) != 1 || sceneIDs[0] != "scene" {
+		t.Errorf("Delete returned scenes %v (err %v), want [scene]", sceneIDs, err)
+	}
+}
+
+func TestUndoRepository_UndoRedo(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUndoRepository(testDB.DB)
+	ctx := context.Background()
+	targets := []UndoTarget{{Kind: UndoTargetScene, ID: "scene"}}
+
+	testDB.DB.Create(&models.Scene{ID: "scene", Name: "Before", ProjectID: "p"})
+	testDB.DB.Create(&models.FixtureValue{ID: "fv", SceneID: "scene", FixtureID: "fx", Channels: `[{"offset":0,"value":10}]`})
+	before, err := repo.Snapshot(ctx, targets)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	testDB.DB.Model(&models.Scene{}).Where("id = ?", "scene").Update("name", "After")
+	testDB.DB.Delete(&models.FixtureValue{}, "id = ?", "fv")
+	after, err := repo.Snapshot(ctx, targets)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if _, err := repo.Push(ctx, "p", "Edit scene", targets, before, after); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if _, err := repo.Undo(ctx, "p"); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	var scene models.Scene
+	testDB.DB.First(&scene, "id = ?", "scene")
+	var count int64
+	testDB.DB.Model(&models.FixtureValue{}).Where("scene_id = ?", "scene").Count(&count)
+	if scene.Name != "Before" || count != 1 {
+		t.Errorf("After undo: name %q with %d values, want Before with 1", scene.Name, count)
+	}
+	if _, err := repo.Undo(ctx, "p"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Second undo error = %v, want ErrNothingToUndo", err)
+	}
+
+	if _, err := repo.Redo(ctx, "p"); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	testDB.DB.First(&scene, "id = ?", "scene")
+	testDB.DB.Model(&models.FixtureValue{}).Where("scene_id = ?", "scene").Count(&count)
+	if scene.Name != "After" || count != 0 {
+		t.Errorf("After redo: name %q with %d values, want After with 0", scene.Name, count)
+	}
+}
+
+func TestUndoRepository_PushBoundsHistory(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUndoRepository(testDB.DB)
+	ctx := context.Background()
+	empty := &UndoSnapshot{}
+
+	for i := 0; i < UndoHistoryLimit+5; i++ {
+		if _, err := repo.Push(ctx, "p", fmt.Sprintf("Edit %d", i), nil, empty, empty); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	undoable, _, err := repo.Status(ctx, "p")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(undoable) != UndoHistoryLimit || undoable[0].Description != fmt.Sprintf("Edit %d", UndoHistoryLimit+4) {
+		t.Fatalf("History has %d edits, want the latest %d", len(undoable), UndoHistoryLimit)
+	}
+
+	// A new edit after an undo clears the redo stack
+	if _, err := repo.Undo(ctx, "p"); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if _, err := repo.Push(ctx, "p", "New edit", nil, empty, empty); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	_, redoable, err := repo.Status(ctx, "p")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(redoable) != 0 {
+		t.Errorf("Redo stack has %d edits after a new edit, want 0", len(redoable))
+	}
+}
+
+func TestSnapshotRepository_PrunesBeyondRetention(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSnapshotRepository(testDB.DB)
+	ctx := context.Background()
+
+	other := &models.ProjectSnapshot{ProjectID: "q", ProjectName: "Other", Reason: "MANUAL", Content: []byte("q")}
+	if err := repo.Create(ctx, other); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var latestID string
+	for i := 0; i < SnapshotRetention+3; i++ {
+		snapshot := &models.ProjectSnapshot{ProjectID: "p", ProjectName: "Show", Reason: "SCHEDULED", Content: []byte(fmt.Sprintf("v%d", i))}
+		if err := repo.Create(ctx, snapshot); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		latestID = snapshot.ID
+	}
+
+	snapshots, err := repo.FindByProjectID(ctx, "p")
+	if err != nil {
+		t.Fatalf("FindByProjectID failed: %v", err)
+	}
+	if len(snapshots) != SnapshotRetention || snapshots[0].ID != latestID {
+		t.Fatalf("Project has %d snapshots, want the latest %d", len(snapshots), SnapshotRetention)
+	}
+	if snapshots[0].Content != nil {
+		t.Error("Listed snapshots should not load their content")
+	}
+	if kept, _ := repo.FindByProjectID(ctx, "q"); len(kept) != 1 {
+		t.Errorf("Other project has %d snapshots, want 1", len(kept))
+	}
+
+	latest, err := repo.FindLatest(ctx, "p")
+	if err != nil || latest == nil || latest.ID != latestID {
+		t.Fatalf("FindLatest = %+v, %v; want %s", latest, err, latestID)
+	}
+	full, err := repo.FindByID(ctx, latestID)
+	if err != nil || full == nil || string(full.Content) != fmt.Sprintf("v%d", SnapshotRetention+2) {
+		t.Fatalf("FindByID = %+v, %v", full, err)
+	}
+
+	if err := repo.Delete(ctx, latestID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if gone, _ := repo.FindByID(ctx, latestID); gone != nil {
+		t.Error("Snapshot still exists after Delete")
+	}
+	if none, _ := repo.FindLatest(ctx, "none"); none != nil {
+		t.Error("FindLatest of a project without snapshots should be nil")
+	}
+}
+
+func TestAuditRepository_FindAndPrune(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAuditRepository(testDB.DB)
+	ctx := context.Background()
+	project, cue, scene := "p", "CUE", "SCENE"
+	old := time.Now().Add(-48 * time.Hour)
+
+	entries := []*models.AuditLog{
+		{ProjectID: &project, Operator: "Alice", Operation: "updateCue", EntityType: cue, CreatedAt: old},
+		{ProjectID: &project, Operator: "Bob", Operation: "updateCue", EntityType: cue},
+		{ProjectID: &project, Operator: "Bob", Operation: "createScene", EntityType: scene},
+		{Operator: "Bob", Operation: "blackout", EntityType: "SYSTEM"},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	found, total, err := repo.Find(ctx, AuditLogFilter{ProjectID: &project, EntityType: &cue}, 0, 10)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if total != 2 || len(found) != 2 || found[0].Operator != "Bob" {
+		t.Fatalf("Find by project and type = %d entries (total %d), want 2 newest first", len(found), total)
+	}
+
+	since := time.Now().Add(-time.Hour)
+	bob := "Bob"
+	found, total, err = repo.Find(ctx, AuditLogFilter{Operator: &bob, Since: &since}, 1, 1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if total != 3 || len(found) != 1 {
+		t.Errorf("Paged find = %d entries (total %d), want 1 of 3", len(found), total)
+	}
+
+	until := time.Now().Add(-time.Hour)
+	if _, total, _ := repo.Find(ctx, AuditLogFilter{Until: &until}, 0, 10); total != 1 {
+		t.Errorf("Find until an hour ago = %d entries, want 1", total)
+	}
+
+	pruned, err := repo.DeleteBefore(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil || pruned != 1 {
+		t.Fatalf("DeleteBefore = %d, %v; want 1 pruned", pruned, err)
+	}
+	if _, total, _ := repo.Find(ctx, AuditLogFilter{}, 0, 10); total != 3 {
+		t.Errorf("Entries after pruning = %d, want 3", total)
+	}
+}
+
+func TestUniverseRepository_CreateMissing(t *testing.T) {
+	testDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUniverseRepository(testDB.DB)
+	ctx := context.Background()
+
+	label := "FOH Truss"
+	configured := &models.Universe{ProjectID: "p1", Number: 1, Label: &label, Enabled: false}
+	if err := repo.Create(ctx, configured); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if configured.Protocol != "ARTNET" {
+		t.Errorf("Protocol = %q, want the ARTNET default", configured.Protocol)
+	}
+	for i, patch := range []struct {
+		project  string
+		universe int
+	}{{"p1", 1}, {"p1", 2}, {"p1", 2}, {"p2", 1}} {
+		fixture := &models.FixtureInstance{ID: fmt.Sprintf("f%d", i), ProjectID: patch.project, Universe: patch.universe, StartChannel: 1 + i*10}
+		if err := testDB.DB.Create(fixture).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	created, err := repo.CreateMissing(ctx)
+	if err != nil || created != 2 {
+		t.Fatalf("CreateMissing() = %d, %v; want 2", created, err)
+	}
+	if created, _ := repo.CreateMissing(ctx); created != 0 {
+		t.Errorf("CreateMissing() again created %d", created)
+	}
+
+	universes, err := repo.FindByProjectID(ctx, "p1")
+	if err != nil || len(universes) != 2 {
+		t.Fatalf("FindByProjectID() = %+v, %v", universes, err)
+	}
+	if universes[0].Enabled || universes[0].Label == nil || !universes[1].Enabled || universes[1].Number != 2 {
+		t.Errorf("Expected the configured universe kept and universe 2 enabled, got %+v", universes)
+	}
+	if count, err := repo.CountFixtures(ctx, "p1", 2); err != nil || count != 2 {
+		t.Errorf("CountFixtures() = %d, %v; want 2", count, err)
+	}
+
+	// A project numbers its universes once
+	if err := repo.Create(ctx, &models.Universe{ProjectID: "p2", Number: 1}); err == nil {
+		t.Error("Expected a duplicate universe number rejected")
+	}
+}
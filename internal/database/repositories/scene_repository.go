@@ -2,6 +2,10 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/lucsky/cuid"
@@ -135,6 +139,11 @@ func (r *SceneRepository) DeleteFixtureValue(ctx context.Context, sceneID, fixtu
 	return r.db.WithContext(ctx).Delete(&models.FixtureValue{}, "scene_id = ? AND fixture_id = ?", sceneID, fixtureID).Error
 }
 
+// DeleteFixtureValuesByFixtureID deletes a fixture's values from every scene.
+func (r *SceneRepository) DeleteFixtureValuesByFixtureID(ctx context.Context, fixtureID string) error {
+	return r.db.WithContext(ctx).Delete(&models.FixtureValue{}, "fixture_id = ?", fixtureID).Error
+}
+
 // GetFixtureValue returns a specific fixture value by scene and fixture ID.
 func (r *SceneRepository) GetFixtureValue(ctx context.Context, sceneID, fixtureID string) (*models.FixtureValue, error) {
 	var value models.FixtureValue
@@ -148,7 +157,190 @@ func (r *SceneRepository) GetFixtureValue(ctx context.Context, sceneID, fixtureI
 	return &value, nil
 }
 
-// UpdateFixtureValue updates a fixture value.
+// UpdateFixtureValue updates a fixture value. A saved value is the scene's
+// own, so it no longer follows a group.
 func (r *SceneRepository) UpdateFixtureValue(ctx context.Context, value *models.FixtureValue) error {
+	value.GroupID = nil
 	return r.db.WithContext(ctx).Save(value).Error
 }
+
+// UpdateFixtureValues saves multiple fixture values in a single transaction.
+func (r *SceneRepository) UpdateFixtureValues(ctx context.Context, values []models.FixtureValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range values {
+			values[i].GroupID = nil
+			if err := tx.Save(&values[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetGroupValues returns a scene's group values in scene order.
+func (r *SceneRepository) GetGroupValues(ctx context.Context, sceneID string) ([]models.GroupValue, error) {
+	var values []models.GroupValue
+	result := r.db.WithContext(ctx).
+		Where("scene_id = ?", sceneID).
+		Order("scene_order ASC").
+		Find(&values)
+	return values, result.Error
+}
+
+// ReplaceGroupValues replaces a scene's group values, numbering them in
+// order. Call SyncGroupValues after to update the scene's fixture values.
+func (r *SceneRepository) ReplaceGroupValues(ctx context.Context, sceneID string, values []models.GroupValue) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.GroupValue{}, "scene_id = ?", sceneID).Error; err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		for i := range values {
+			if values[i].ID == "" {
+				values[i].ID = cuid.New()
+			}
+			values[i].SceneID = sceneID
+			values[i].SceneOrder = i
+		}
+		return tx.Create(&values).Error
+	})
+}
+
+// DeleteGroupValues deletes all group values for a scene.
+func (r *SceneRepository) DeleteGroupValues(ctx context.Context, sceneID string) error {
+	return r.db.WithContext(ctx).Delete(&models.GroupValue{}, "scene_id = ?", sceneID).Error
+}
+
+// FindSceneIDsByFixtureID returns the IDs of scenes with values for a fixture.
+func (r *SceneRepository) FindSceneIDsByFixtureID(ctx context.Context, fixtureID string) ([]string, error) {
+	var sceneIDs []string
+	err := r.db.WithContext(ctx).Model(&models.FixtureValue{}).
+		Where("fixture_id = ?", fixtureID).
+		Distinct().
+		Pluck("scene_id", &sceneIDs).Error
+	return sceneIDs, err
+}
+
+// FindSceneIDsByGroupID returns the IDs of the scenes with values for a group.
+func (r *SceneRepository) FindSceneIDsByGroupID(ctx context.Context, groupID string) ([]string, error) {
+	var sceneIDs []string
+	err := r.db.WithContext(ctx).Model(&models.GroupValue{}).
+		Where("group_id = ?", groupID).
+		Distinct().
+		Pluck("scene_id", &sceneIDs).Error
+	return sceneIDs, err
+}
+
+// groupDerivedValue is a fixture's channel values taken from group values.
+type groupDerivedValue struct {
+	groupID string
+	values  map[int]int // offset -> value
+}
+
+// SyncGroupValues rederives a scene's fixture values from its group values.
+// A group member with a fixture value of its own in the scene keeps it; the
+// others take the group's value on each of their channels of a set type,
+// later groups winning for fixtures in several.
+func (r *SceneRepository) SyncGroupValues(ctx context.Context, sceneID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.FixtureValue{}, "scene_id = ? AND group_id IS NOT NULL", sceneID).Error; err != nil {
+			return err
+		}
+
+		var groupValues []models.GroupValue
+		if err := tx.Where("scene_id = ?", sceneID).Order("scene_order ASC").Find(&groupValues).Error; err != nil {
+			return err
+		}
+		if len(groupValues) == 0 {
+			return nil
+		}
+
+		var ownFixtureIDs []string
+		if err := tx.Model(&models.FixtureValue{}).Where("scene_id = ?", sceneID).Pluck("fixture_id", &ownFixtureIDs).Error; err != nil {
+			return err
+		}
+		own := make(map[string]bool, len(ownFixtureIDs))
+		for _, id := range ownFixtureIDs {
+			own[id] = true
+		}
+
+		derived := make(map[string]*groupDerivedValue)
+		var order []string
+		for _, gv := range groupValues {
+			var group models.FixtureGroup
+			if err := tx.First(&group, "id = ?", gv.GroupID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+			fixtureIDs, err := GroupFixtureIDs(&group)
+			if err != nil {
+				return err
+			}
+			var channels []models.ChannelTypeValue
+			if err := json.Unmarshal([]byte(gv.Channels), &channels); err != nil {
+				return fmt.Errorf("invalid channels for group %s in scene %s: %w", gv.GroupID, sceneID, err)
+			}
+			byType := make(map[string]int, len(channels))
+			for _, ch := range channels {
+				byType[ch.Type] = ch.Value
+			}
+
+			var fixtures []models.FixtureInstance
+			if err := tx.Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures).Error; err != nil {
+				return err
+			}
+			for _, fixture := range fixtures {
+				if own[fixture.ID] {
+					continue
+				}
+				for _, ch := range fixture.Channels {
+					value, ok := byType[ch.Type]
+					if !ok {
+						continue
+					}
+					d := derived[fixture.ID]
+					if d == nil {
+						d = &groupDerivedValue{values: make(map[int]int)}
+						derived[fixture.ID] = d
+						order = append(order, fixture.ID)
+					}
+					d.groupID = gv.GroupID
+					d.values[ch.Offset] = value
+				}
+			}
+		}
+		if len(order) == 0 {
+			return nil
+		}
+
+		values := make([]models.FixtureValue, 0, len(order))
+		for _, fixtureID := range order {
+			d := derived[fixtureID]
+			channels := make([]models.ChannelValue, 0, len(d.values))
+			for offset, value := range d.values {
+				channels = append(channels, models.ChannelValue{Offset: offset, Value: value})
+			}
+			sort.Slice(channels, func(i, j int) bool { return channels[i].Offset < channels[j].Offset })
+			channelsJSON, err := json.Marshal(channels)
+			if err != nil {
+				return err
+			}
+			groupID := d.groupID
+			values = append(values, models.FixtureValue{
+				ID:        cuid.New(),
+				SceneID:   sceneID,
+				FixtureID: fixtureID,
+				Channels:  string(channelsJSON),
+				GroupID:   &groupID,
+			})
+		}
+		return tx.Create(&values).Error
+	})
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// SelectionSetRepository handles selection set data access.
+type SelectionSetRepository struct {
+	db *gorm.DB
+}
+
+// NewSelectionSetRepository creates a new SelectionSetRepository.
+func NewSelectionSetRepository(db *gorm.DB) *SelectionSetRepository {
+	return &SelectionSetRepository{db: db}
+}
+
+// SelectionSetFixtureIDs decodes a stored selection set's fixture IDs.
+func SelectionSetFixtureIDs(set *models.SelectionSet) ([]string, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(set.FixtureIDs), &ids); err != nil {
+		return nil, fmt.Errorf("invalid fixture list for selection set %s: %w", set.ID, err)
+	}
+	return ids, nil
+}
+
+// FindByProjectID returns a project's selection sets by name.
+func (r *SelectionSetRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.SelectionSet, error) {
+	var sets []models.SelectionSet
+	result := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&sets)
+	return sets, result.Error
+}
+
+// FindByID returns a selection set by ID.
+func (r *SelectionSetRepository) FindByID(ctx context.Context, id string) (*models.SelectionSet, error) {
+	var set models.SelectionSet
+	result := r.db.WithContext(ctx).First(&set, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &set, nil
+}
+
+// FindByFixtureID returns the selection sets a fixture is in.
+func (r *SelectionSetRepository) FindByFixtureID(ctx context.Context, fixtureID string) ([]models.SelectionSet, error) {
+	var candidates []models.SelectionSet
+	pattern := fmt.Sprintf("%%%q%%", fixtureID)
+	if err := r.db.WithContext(ctx).Where("fixture_ids LIKE ?", pattern).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var sets []models.SelectionSet
+	for _, set := range candidates {
+		ids, err := SelectionSetFixtureIDs(&set)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(ids, fixtureID) {
+			sets = append(sets, set)
+		}
+	}
+	return sets, nil
+}
+
+// Create creates a new selection set.
+func (r *SelectionSetRepository) Create(ctx context.Context, set *models.SelectionSet) error {
+	if set.ID == "" {
+		set.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(set).Error
+}
+
+// Update updates an existing selection set.
+func (r *SelectionSetRepository) Update(ctx context.Context, set *models.SelectionSet) error {
+	return r.db.WithContext(ctx).Save(set).Error
+}
+
+// Delete deletes a selection set by ID.
+func (r *SelectionSetRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.SelectionSet{}, "id = ?", id).Error
+}
+
+// DeleteByProjectID deletes all selection sets in a project.
+func (r *SelectionSetRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.SelectionSet{}, "project_id = ?", projectID).Error
+}
@@ -10,7 +10,8 @@ import (
 
 // SettingRepository handles setting data access.
 type SettingRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	onChange func(key string, value *string)
 }
 
 // NewSettingRepository creates a new SettingRepository.
@@ -18,6 +19,19 @@ func NewSettingRepository(db *gorm.DB) *SettingRepository {
 	return &SettingRepository{db: db}
 }
 
+// OnChange sets a function called after a setting is saved, or deleted
+// with a nil value (optional).
+func (r *SettingRepository) OnChange(fn func(key string, value *string)) {
+	r.onChange = fn
+}
+
+// changed reports a saved or deleted setting.
+func (r *SettingRepository) changed(key string, value *string) {
+	if r.onChange != nil {
+		r.onChange(key, value)
+	}
+}
+
 // FindAll returns all settings.
 func (r *SettingRepository) FindAll(ctx context.Context) ([]models.Setting, error) {
 	var settings []models.Setting
@@ -57,6 +71,7 @@ func (r *SettingRepository) Upsert(ctx context.Context, key, value string) (*mod
 		if err := r.db.WithContext(ctx).Create(&setting).Error; err != nil {
 			return nil, err
 		}
+		r.changed(key, &value)
 		return &setting, nil
 	} else if result.Error != nil {
 		return nil, result.Error
@@ -67,11 +82,16 @@ func (r *SettingRepository) Upsert(ctx context.Context, key, value string) (*mod
 	if err := r.db.WithContext(ctx).Save(&setting).Error; err != nil {
 		return nil, err
 	}
+	r.changed(key, &value)
 
 	return &setting, nil
 }
 
 // Delete deletes a setting by key.
 func (r *SettingRepository) Delete(ctx context.Context, key string) error {
-	return r.db.WithContext(ctx).Delete(&models.Setting{}, "key = ?", key).Error
+	if err := r.db.WithContext(ctx).Delete(&models.Setting{}, "key = ?", key).Error; err != nil {
+		return err
+	}
+	r.changed(key, nil)
+	return nil
 }
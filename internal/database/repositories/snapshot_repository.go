@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// SnapshotRetention is the number of snapshots kept per project; older ones
+// are pruned as new ones are taken.
+const SnapshotRetention = 20
+
+// snapshotListColumns are the columns loaded when listing snapshots, leaving
+// out their content.
+var snapshotListColumns = []string{"id", "project_id", "project_name", "reason", "size", "checksum", "created_at"}
+
+// SnapshotRepository handles project snapshot data access.
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSnapshotRepository creates a new SnapshotRepository.
+func NewSnapshotRepository(db *gorm.DB) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// Create stores a new snapshot and prunes the project's oldest snapshots
+// beyond the retention limit.
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *models.ProjectSnapshot) error {
+	if snapshot.ID == "" {
+		snapshot.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		var stale []string
+		if err := tx.Model(&models.ProjectSnapshot{}).
+			Where("project_id = ?", snapshot.ProjectID).
+			Order("created_at DESC, id DESC").
+			Offset(SnapshotRetention).
+			Pluck("id", &stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		return tx.Delete(&models.ProjectSnapshot{}, "id IN ?", stale).Error
+	})
+}
+
+// FindByProjectID returns a project's snapshots, newest first, without
+// their content.
+func (r *SnapshotRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.ProjectSnapshot, error) {
+	var snapshots []models.ProjectSnapshot
+	result := r.db.WithContext(ctx).
+		Select(snapshotListColumns).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC").
+		Find(&snapshots)
+	return snapshots, result.Error
+}
+
+// FindByID returns a snapshot by ID, including its content.
+func (r *SnapshotRepository) FindByID(ctx context.Context, id string) (*models.ProjectSnapshot, error) {
+	var snapshot models.ProjectSnapshot
+	result := r.db.WithContext(ctx).First(&snapshot, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &snapshot, nil
+}
+
+// FindLatest returns a project's newest snapshot without its content, or nil
+// if it has none.
+func (r *SnapshotRepository) FindLatest(ctx context.Context, projectID string) (*models.ProjectSnapshot, error) {
+	var snapshots []models.ProjectSnapshot
+	result := r.db.WithContext(ctx).
+		Select(snapshotListColumns).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&snapshots)
+	if result.Error != nil || len(snapshots) == 0 {
+		return nil, result.Error
+	}
+	return &snapshots[0], nil
+}
+
+// Delete deletes a snapshot by ID.
+func (r *SnapshotRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.ProjectSnapshot{}, "id = ?", id).Error
+}
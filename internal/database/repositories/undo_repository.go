@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UndoHistoryLimit is the number of edits kept per project; older ones can
+// no longer be undone.
+const UndoHistoryLimit = 100
+
+// ErrNothingToUndo is returned when a project has no edit to undo.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrNothingToRedo is returned when a project has no undone edit to redo.
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// UndoTargetKind identifies the kind of record an edit touched.
+type UndoTargetKind string
+
+const (
+	// UndoTargetScene is a scene with its fixture and group values.
+	UndoTargetScene UndoTargetKind = "SCENE"
+	// UndoTargetFixture is a fixture instance with its channels and its
+	// values in every scene.
+	UndoTargetFixture UndoTargetKind = "FIXTURE"
+	// UndoTargetCue is a single cue.
+	UndoTargetCue UndoTargetKind = "CUE"
+)
+
+// UndoTarget is a record an undoable edit touched.
+type UndoTarget struct {
+	Kind UndoTargetKind `json:"kind"`
+	ID   string         `json:"id"`
+}
+
+// UndoSnapshot holds the rows making up a set of targets at one point in time.
+type UndoSnapshot struct {
+	Scenes           []models.Scene           `json:"scenes,omitempty"`
+	FixtureValues    []models.FixtureValue    `json:"fixtureValues,omitempty"`
+	GroupValues      []models.GroupValue      `json:"groupValues,omitempty"`
+	Fixtures         []models.FixtureInstance `json:"fixtures,omitempty"`
+	InstanceChannels []models.InstanceChannel `json:"instanceChannels,omitempty"`
+	Cues             []models.Cue             `json:"cues,omitempty"`
+}
+
+// UndoRepository handles the per-project undo history.
+type UndoRepository struct {
+	db *gorm.DB
+}
+
+// NewUndoRepository creates a new UndoRepository.
+func NewUndoRepository(db *gorm.DB) *UndoRepository {
+	return &UndoRepository{db: db}
+}
+
+// Snapshot captures the current rows of the targets.
+func (r *UndoRepository) Snapshot(ctx context.Context, targets []UndoTarget) (*UndoSnapshot, error) {
+	db := r.db.WithContext(ctx)
+	var sceneIDs, fixtureIDs, cueIDs []string
+	for _, t := range targets {
+		switch t.Kind {
+		case UndoTargetScene:
+			sceneIDs = append(sceneIDs, t.ID)
+		case UndoTargetFixture:
+			fixtureIDs = append(fixtureIDs, t.ID)
+		case UndoTargetCue:
+			cueIDs = append(cueIDs, t.ID)
+		default:
+			return nil, fmt.Errorf("unknown undo target kind: %s", t.Kind)
+		}
+	}
+
+	snapshot := &UndoSnapshot{}
+	if len(sceneIDs) > 0 {
+		if err := db.Where("id IN ?", sceneIDs).Find(&snapshot.Scenes).Error; err != nil {
+			return nil, err
+		}
+		if err := db.Where("scene_id IN ?", sceneIDs).Find(&snapshot.GroupValues).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(sceneIDs) > 0 || len(fixtureIDs) > 0 {
+		// A fixture value belongs to both its scene and its fixture
+		if err := db.Where("scene_id IN ? OR fixture_id IN ?", nonNil(sceneIDs), nonNil(fixtureIDs)).Find(&snapshot.FixtureValues).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(fixtureIDs) > 0 {
+		if err := db.Where("id IN ?", fixtureIDs).Find(&snapshot.Fixtures).Error; err != nil {
+			return nil, err
+		}
+		if err := db.Where("fixture_id IN ?", fixtureIDs).Find(&snapshot.InstanceChannels).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(cueIDs) > 0 {
+		if err := db.Where("id IN ?", cueIDs).Find(&snapshot.Cues).Error; err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// nonNil keeps an empty ID list from rendering as NULL in an IN clause.
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
+
+// Push records an edit at the top of a project's history. Undone edits can
+// no longer be redone, and the oldest edits beyond the limit are dropped.
+func (r *UndoRepository) Push(ctx context.Context, projectID, description string, targets []UndoTarget, before, after *UndoSnapshot) (*models.UndoOperation, error) {
+	targetsJSON, err := json.Marshal(targets)
+	if err != nil {
+		return nil, err
+	}
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &models.UndoOperation{
+		ID:          cuid.New(),
+		ProjectID:   projectID,
+		Description: description,
+		Targets:     string(targetsJSON),
+		Before:      string(beforeJSON),
+		After:       string(afterJSON),
+	}
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.UndoOperation{}, "project_id = ? AND undone = ?", projectID, true).Error; err != nil {
+			return err
+		}
+		var maxSeq int
+		if err := tx.Model(&models.UndoOperation{}).Where("project_id = ?", projectID).Select("COALESCE(MAX(seq), 0)").Scan(&maxSeq).Error; err != nil {
+			return err
+		}
+		op.Seq = maxSeq + 1
+		if err := tx.Create(op).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.UndoOperation{}, "project_id = ? AND seq <= ?", projectID, op.Seq-UndoHistoryLimit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Undo restores the targets of a project's latest edit to how they were
+// before it and moves it to the redo stack.
+func (r *UndoRepository) Undo(ctx context.Context, projectID string) (*models.UndoOperation, error) {
+	var op models.UndoOperation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("project_id = ? AND undone = ?", projectID, false).Order("seq DESC").Limit(1).Find(&op)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNothingToUndo
+		}
+		if err := restoreUndoSnapshot(tx, op.Targets, op.Before); err != nil {
+			return err
+		}
+		return tx.Model(&op).Update("undone", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Redo reapplies a project's most recently undone edit.
+func (r *UndoRepository) Redo(ctx context.Context, projectID string) (*models.UndoOperation, error) {
+	var op models.UndoOperation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("project_id = ? AND undone = ?", projectID, true).Order("seq ASC").Limit(1).Find(&op)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNothingToRedo
+		}
+		if err := restoreUndoSnapshot(tx, op.Targets, op.After); err != nil {
+			return err
+		}
+		return tx.Model(&op).Update("undone", false).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// undoTargetRows lists the rows making up each kind of target, children
+// first, by the column holding the target's ID.
+var undoTargetRows = map[UndoTargetKind][]struct {
+	model  interface{}
+	column string
+}{
+	UndoTargetScene:   {{&models.FixtureValue{}, "scene_id"}, {&models.GroupValue{}, "scene_id"}, {&models.Scene{}, "id"}},
+	UndoTargetFixture: {{&models.FixtureValue{}, "fixture_id"}, {&models.InstanceChannel{}, "fixture_id"}, {&models.FixtureInstance{}, "id"}},
+	UndoTargetCue:     {{&models.Cue{}, "id"}},
+}
+
+// restoreUndoSnapshot replaces the current rows of the targets with a snapshot.
+func restoreUndoSnapshot(tx *gorm.DB, targetsJSON, snapshotJSON string) error {
+	var targets []UndoTarget
+	if err := json.Unmarshal([]byte(targetsJSON), &targets); err != nil {
+		return fmt.Errorf("invalid undo targets: %w", err)
+	}
+	var snapshot UndoSnapshot
+	if err := json.Unmarshal([]byte(snapshotJSON), &snapshot); err != nil {
+		return fmt.Errorf("invalid undo snapshot: %w", err)
+	}
+
+	for _, t := range targets {
+		for _, rows := range undoTargetRows[t.Kind] {
+			if err := tx.Delete(rows.model, rows.column+" = ?", t.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// Parents first, so children never reference a missing row
+	tables := []struct {
+		rows  interface{}
+		count int
+	}{
+		{&snapshot.Fixtures, len(snapshot.Fixtures)},
+		{&snapshot.InstanceChannels, len(snapshot.InstanceChannels)},
+		{&snapshot.Scenes, len(snapshot.Scenes)},
+		{&snapshot.FixtureValues, len(snapshot.FixtureValues)},
+		{&snapshot.GroupValues, len(snapshot.GroupValues)},
+		{&snapshot.Cues, len(snapshot.Cues)},
+	}
+	for _, table := range tables {
+		if table.count == 0 {
+			continue
+		}
+		if err := tx.Omit(clause.Associations).Create(table.rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns a project's undoable edits, latest first, and its undone
+// edits, next to redo first. Snapshots are not loaded.
+func (r *UndoRepository) Status(ctx context.Context, projectID string) ([]models.UndoOperation, []models.UndoOperation, error) {
+	columns := []string{"id", "project_id", "seq", "description", "undone", "created_at"}
+	var undoable, redoable []models.UndoOperation
+	if err := r.db.WithContext(ctx).Select(columns).Where("project_id = ? AND undone = ?", projectID, false).Order("seq DESC").Find(&undoable).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := r.db.WithContext(ctx).Select(columns).Where("project_id = ? AND undone = ?", projectID, true).Order("seq ASC").Find(&redoable).Error; err != nil {
+		return nil, nil, err
+	}
+	return undoable, redoable, nil
+}
+
+// DeleteByProjectID deletes a project's undo history.
+func (r *UndoRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.UndoOperation{}, "project_id = ?", projectID).Error
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// UniverseRepository handles universe data access.
+type UniverseRepository struct {
+	db *gorm.DB
+}
+
+// NewUniverseRepository creates a new UniverseRepository.
+func NewUniverseRepository(db *gorm.DB) *UniverseRepository {
+	return &UniverseRepository{db: db}
+}
+
+// FindAll returns the universes of every project.
+func (r *UniverseRepository) FindAll(ctx context.Context) ([]models.Universe, error) {
+	var universes []models.Universe
+	result := r.db.WithContext(ctx).
+		Order("number ASC").
+		Find(&universes)
+	return universes, result.Error
+}
+
+// FindByProjectID returns a project's universes by number.
+func (r *UniverseRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.Universe, error) {
+	var universes []models.Universe
+	result := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("number ASC").
+		Find(&universes)
+	return universes, result.Error
+}
+
+// FindByID returns a universe by ID.
+func (r *UniverseRepository) FindByID(ctx context.Context, id string) (*models.Universe, error) {
+	var universe models.Universe
+	result := r.db.WithContext(ctx).First(&universe, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &universe, nil
+}
+
+// FindByNumber returns a project's universe by number.
+func (r *UniverseRepository) FindByNumber(ctx context.Context, projectID string, number int) (*models.Universe, error) {
+	var universe models.Universe
+	result := r.db.WithContext(ctx).First(&universe, "project_id = ? AND number = ?", projectID, number)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &universe, nil
+}
+
+// Create creates a new universe.
+func (r *UniverseRepository) Create(ctx context.Context, universe *models.Universe) error {
+	if universe.ID == "" {
+		universe.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(universe).Error
+}
+
+// Update updates an existing universe.
+func (r *UniverseRepository) Update(ctx context.Context, universe *models.Universe) error {
+	return r.db.WithContext(ctx).Save(universe).Error
+}
+
+// Delete deletes a universe by ID.
+func (r *UniverseRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.Universe{}, "id = ?", id).Error
+}
+
+// DeleteByProjectID deletes all universes in a project.
+func (r *UniverseRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.Universe{}, "project_id = ?", projectID).Error
+}
+
+// CountFixtures returns how many fixtures are patched to a project's
+// universe.
+func (r *UniverseRepository) CountFixtures(ctx context.Context, projectID string, number int) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).
+		Model(&models.FixtureInstance{}).
+		Where("project_id = ? AND universe = ?", projectID, number).
+		Count(&count)
+	return count, result.Error
+}
+
+// CreateMissing creates an enabled universe for each universe that fixtures
+// are patched to but their project has none for, and returns how many it
+// created.
+func (r *UniverseRepository) CreateMissing(ctx context.Context) (int, error) {
+	var missing []struct {
+		ProjectID string
+		Universe  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.FixtureInstance{}).
+		Distinct("project_id", "universe").
+		Where("universe > 0").
+		Where("NOT EXISTS (SELECT 1 FROM universes WHERE universes.project_id = fixture_instances.project_id AND universes.number = fixture_instances.universe)").
+		Scan(&missing).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, m := range missing {
+		universe := &models.Universe{ProjectID: m.ProjectID, Number: m.Universe, Enabled: true}
+		if err := r.Create(ctx, universe); err != nil {
+			return 0, err
+		}
+	}
+	return len(missing), nil
+}
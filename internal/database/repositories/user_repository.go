@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// UserRepository handles user and project membership data access.
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// FindAll returns all users, ordered by email.
+func (r *UserRepository) FindAll(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	result := r.db.WithContext(ctx).Order("email").Find(&users)
+	return users, result.Error
+}
+
+// FindByID returns a user by ID, or nil if there is none.
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// FindByEmail returns a user by email, or nil if there is none.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	result := r.db.WithContext(ctx).First(&user, "email = ?", email)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// Count returns the number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.User{}).Count(&count)
+	return count, result.Error
+}
+
+// Create creates a new user.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.ID == "" {
+		user.ID = cuid.New()
+	}
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// UpdatePassword replaces a user's password hash.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error
+}
+
+// Delete deletes a user and their project memberships.
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ProjectUser{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", id).Error
+	})
+}
+
+// FindMembership returns a user's membership of a project, or nil if they
+// are not a member.
+func (r *UserRepository) FindMembership(ctx context.Context, userID, projectID string) (*models.ProjectUser, error) {
+	var member models.ProjectUser
+	result := r.db.WithContext(ctx).Limit(1).Find(&member, "user_id = ? AND project_id = ?", userID, projectID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &member, nil
+}
+
+// SetMembership adds a user to a project with role, or changes the role of
+// an existing member.
+func (r *UserRepository) SetMembership(ctx context.Context, userID, projectID, role string) (*models.ProjectUser, error) {
+	member, err := r.FindMembership(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil {
+		member.Role = role
+		return member, r.db.WithContext(ctx).Model(member).Update("role", role).Error
+	}
+	member = &models.ProjectUser{ID: cuid.New(), UserID: userID, ProjectID: projectID, Role: role}
+	return member, r.db.WithContext(ctx).Create(member).Error
+}
+
+// RemoveMembership removes a user from a project, reporting whether they
+// were a member.
+func (r *UserRepository) RemoveMembership(ctx context.Context, userID, projectID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&models.ProjectUser{}, "user_id = ? AND project_id = ?", userID, projectID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountOwners returns the number of owners of a project.
+func (r *UserRepository) CountOwners(ctx context.Context, projectID string) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.ProjectUser{}).
+		Where("project_id = ? AND role = ?", projectID, "OWNER").
+		Count(&count)
+	return count, result.Error
+}
+
+// DeleteMembershipsByProjectID removes every member of a project.
+func (r *UserRepository) DeleteMembershipsByProjectID(ctx context.Context, projectID string) error {
+	return r.db.WithContext(ctx).Delete(&models.ProjectUser{}, "project_id = ?", projectID).Error
+}
@@ -28,6 +28,212 @@ type APConfig struct {
 	MinutesRemaining *int   `json:"minutesRemaining,omitempty"`
 }
 
+// The local interface Art-Net output is sent from
+type ArtNetBinding struct {
+	// The interface name or IPv4 address asked for; null leaves the choice to the system
+	Bind *string `json:"bind,omitempty"`
+	// The address output is sent from, null when unbound
+	Address       *string `json:"address,omitempty"`
+	InterfaceName *string `json:"interfaceName,omitempty"`
+}
+
+// A local interface Art-Net output can be sent from
+type ArtNetInterface struct {
+	Name string `json:"name"`
+	// ethernet, wifi, other, or localhost
+	InterfaceType string `json:"interfaceType"`
+	Address       string `json:"address"`
+	// The interface's subnet broadcast address, to pair with the binding
+	Broadcast *string `json:"broadcast,omitempty"`
+	// Whether output is bound to this address
+	Selected bool `json:"selected"`
+}
+
+type ArtNetNodeInfo struct {
+	IPAddress       string  `json:"ipAddress"`
+	ShortName       *string `json:"shortName,omitempty"`
+	LongName        *string `json:"longName,omitempty"`
+	OutputUniverses []int   `json:"outputUniverses"`
+}
+
+// An Art-Net node as discovered via ArtPoll
+type ArtNetNodeInput struct {
+	IPAddress string                     `json:"ipAddress"`
+	ShortName graphql.Omittable[*string] `json:"shortName,omitempty"`
+	LongName  graphql.Omittable[*string] `json:"longName,omitempty"`
+	// Universes the node outputs (1-based, matching fixture patch universes)
+	OutputUniverses []int `json:"outputUniverses"`
+}
+
+// Patched universes matched against discovered Art-Net nodes
+type ArtNetRoutingReport struct {
+	ProjectID string                 `json:"projectId"`
+	Routes    []*ArtNetUniverseRoute `json:"routes"`
+	// Patched universes that no discovered node outputs
+	UnroutedUniverses []int `json:"unroutedUniverses"`
+	// Discovered nodes that output none of the patched universes
+	UnusedNodes []*ArtNetNodeInfo `json:"unusedNodes"`
+}
+
+// A universe sent directly to specific Art-Net nodes instead of broadcast
+type ArtNetUnicastRoute struct {
+	Universe int `json:"universe"`
+	// IPv4 addresses, with an optional port (default: the Art-Net port)
+	Destinations []string `json:"destinations"`
+}
+
+type ArtNetUnicastRouteInput struct {
+	Universe     int      `json:"universe"`
+	Destinations []string `json:"destinations"`
+}
+
+type ArtNetUniverseRoute struct {
+	Universe     int               `json:"universe"`
+	FixtureCount int               `json:"fixtureCount"`
+	IsRouted     bool              `json:"isRouted"`
+	Nodes        []*ArtNetNodeInfo `json:"nodes"`
+}
+
+// A frequency band beats are detected in
+type AudioBand struct {
+	Name   string  `json:"name"`
+	LowHz  float64 `json:"lowHz"`
+	HighHz float64 `json:"highHz"`
+	// How many times its average over the last second the band's energy must reach to count as a beat
+	Threshold float64 `json:"threshold"`
+	// Fewest seconds between beats
+	MinInterval float64 `json:"minInterval"`
+	// Current RMS level, 0-1
+	Level      float64 `json:"level"`
+	LastBeatAt *string `json:"lastBeatAt,omitempty"`
+	// Beats since the configuration was applied
+	BeatCount int `json:"beatCount"`
+}
+
+type AudioBandInput struct {
+	Name        string                      `json:"name"`
+	LowHz       float64                     `json:"lowHz"`
+	HighHz      float64                     `json:"highHz"`
+	Threshold   graphql.Omittable[*float64] `json:"threshold,omitempty"`
+	MinInterval graphql.Omittable[*float64] `json:"minInterval,omitempty"`
+}
+
+type AudioInputConfigInput struct {
+	Enabled bool        `json:"enabled"`
+	Source  AudioSource `json:"source"`
+	// ALSA device the DEVICE source captures from; defaults to the default device
+	Device graphql.Omittable[*string] `json:"device,omitempty"`
+	// Port the RTP and UDP sources receive on; defaults to 5004
+	Port       graphql.Omittable[*int] `json:"port,omitempty"`
+	SampleRate graphql.Omittable[*int] `json:"sampleRate,omitempty"`
+	Channels   graphql.Omittable[*int] `json:"channels,omitempty"`
+	// Defaults to BASS, MID and HIGH bands
+	Bands graphql.Omittable[[]*AudioBandInput] `json:"bands,omitempty"`
+}
+
+// Audio input analyzed for beats that trigger scene board buttons.
+type AudioInputStatus struct {
+	Enabled bool        `json:"enabled"`
+	Source  AudioSource `json:"source"`
+	// ALSA device captured from, e.g. hw:1,0
+	Device *string `json:"device,omitempty"`
+	// UDP port streams are received on
+	Port       *int `json:"port,omitempty"`
+	SampleRate int  `json:"sampleRate"`
+	// 1 for mono or 2 for stereo, which is mixed down
+	Channels int          `json:"channels"`
+	Bands    []*AudioBand `json:"bands"`
+	// True while the device is capturing or the port is open
+	IsListening bool `json:"isListening"`
+	// Current RMS level of all the audio, 0-1
+	Level       float64 `json:"level"`
+	LastInputAt *string `json:"lastInputAt,omitempty"`
+	// Address the last stream datagram came from
+	LastSource *string `json:"lastSource,omitempty"`
+	// Why the input cannot be read, if it cannot
+	InputError *string `json:"inputError,omitempty"`
+}
+
+// Selects audit log entries. All set fields must match.
+type AuditLogFilterInput struct {
+	ProjectID  graphql.Omittable[*string]          `json:"projectId,omitempty"`
+	EntityType graphql.Omittable[*AuditEntityType] `json:"entityType,omitempty"`
+	EntityID   graphql.Omittable[*string]          `json:"entityId,omitempty"`
+	Operator   graphql.Omittable[*string]          `json:"operator,omitempty"`
+	// Earliest time to include, RFC 3339
+	Since graphql.Omittable[*string] `json:"since,omitempty"`
+	// Time to stop before, RFC 3339
+	Until graphql.Omittable[*string] `json:"until,omitempty"`
+}
+
+type AuditLogPage struct {
+	Entries    []*models.AuditLog `json:"entries"`
+	Pagination PaginationInfo     `json:"pagination"`
+}
+
+type AuthPayload struct {
+	// Send as a bearer token in the Authorization header, or as the authorization websocket connection parameter
+	Token     string      `json:"token"`
+	ExpiresAt string      `json:"expiresAt"`
+	User      models.User `json:"user"`
+}
+
+// An archive of every project and the settings. Backups are made on a schedule
+// and kept in the server's backup directory or S3 bucket.
+type Backup struct {
+	// File name, which identifies the backup
+	Name string `json:"name"`
+	// Compressed size in bytes
+	Size      int    `json:"size"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// What restoring a backup did
+type BackupRestoreResult struct {
+	// Each project restored, as a new project
+	Projects []*ImportResult `json:"projects"`
+	// Number of settings restored
+	SettingsRestored int `json:"settingsRestored"`
+	// Projects that could not be restored
+	Warnings []string `json:"warnings"`
+}
+
+// One step of an executeBatch transaction. Set exactly one operation field.
+// ID fields of later operations may use "$ref:<ref>" to name the entity
+// created by an earlier operation in the same batch.
+type BatchOperationInput struct {
+	// Name later operations use to reference the created entity
+	Ref              graphql.Omittable[*string]                      `json:"ref,omitempty"`
+	CreateScene      graphql.Omittable[*CreateSceneInput]            `json:"createScene,omitempty"`
+	CreateSceneBoard graphql.Omittable[*CreateSceneBoardInput]       `json:"createSceneBoard,omitempty"`
+	AddSceneToBoard  graphql.Omittable[*CreateSceneBoardButtonInput] `json:"addSceneToBoard,omitempty"`
+	CreateCueList    graphql.Omittable[*CreateCueListInput]          `json:"createCueList,omitempty"`
+	CreateCue        graphql.Omittable[*CreateCueInput]              `json:"createCue,omitempty"`
+}
+
+type BatchOperationResult struct {
+	// Position of the operation in the batch (0-based)
+	Index int                `json:"index"`
+	Ref   *string            `json:"ref,omitempty"`
+	Kind  BatchOperationKind `json:"kind"`
+	// ID of the created entity
+	ID string `json:"id"`
+}
+
+// Emergency blackout. It takes every intensity channel (or the additive color
+// channels of fixtures without a dimmer) to zero on top of all other output,
+// overrides included. Playback keeps running underneath, so a restore brings
+// back whatever is live at that point.
+type BlackoutStatus struct {
+	// True from a blackout until the next restore
+	IsBlackout bool `json:"isBlackout"`
+	// Fraction of intensity currently let through, 0 to 1
+	Level    float64 `json:"level"`
+	IsFading bool    `json:"isFading"`
+	// When the blackout began (null when not blacked out)
+	Since *string `json:"since,omitempty"`
+}
+
 // Server build information for version verification
 type BuildInfo struct {
 	// Semantic version (e.g., v0.8.10)
@@ -51,11 +257,14 @@ type BulkCueListUpdateInput struct {
 }
 
 type BulkCueUpdateInput struct {
-	CueIds      []string                       `json:"cueIds"`
-	FadeInTime  graphql.Omittable[*float64]    `json:"fadeInTime,omitempty"`
-	FadeOutTime graphql.Omittable[*float64]    `json:"fadeOutTime,omitempty"`
-	FollowTime  graphql.Omittable[*float64]    `json:"followTime,omitempty"`
-	EasingType  graphql.Omittable[*EasingType] `json:"easingType,omitempty"`
+	CueIds         []string                         `json:"cueIds"`
+	FadeInTime     graphql.Omittable[*float64]      `json:"fadeInTime,omitempty"`
+	FadeOutTime    graphql.Omittable[*float64]      `json:"fadeOutTime,omitempty"`
+	FollowTime     graphql.Omittable[*float64]      `json:"followTime,omitempty"`
+	FollowQuantize graphql.Omittable[*BeatQuantize] `json:"followQuantize,omitempty"`
+	EasingType     graphql.Omittable[*EasingType]   `json:"easingType,omitempty"`
+	Block          graphql.Omittable[*bool]         `json:"block,omitempty"`
+	MoveInBlack    graphql.Omittable[*bool]         `json:"moveInBlack,omitempty"`
 }
 
 type BulkDeleteResult struct {
@@ -111,6 +320,12 @@ type BulkSceneUpdateInput struct {
 	Scenes []*SceneUpdateItem `json:"scenes"`
 }
 
+type CellColorInput struct {
+	// 0-indexed
+	Cell  int        `json:"cell"`
+	Color ColorInput `json:"color"`
+}
+
 type ChannelAssignmentInput struct {
 	ProjectID       string                  `json:"projectId"`
 	Universe        graphql.Omittable[*int] `json:"universe,omitempty"`
@@ -130,6 +345,26 @@ type ChannelFadeBehaviorInput struct {
 	FadeBehavior FadeBehavior `json:"fadeBehavior"`
 }
 
+// Rig protection for one output channel, such as house lights or a scroller.
+// Applied last, after every scene, override and cap: the level follows the
+// curve, then is held at or below maxLevel; inhibited channels output zero.
+type ChannelLimit struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+	// 0 to 1
+	MaxLevel float64     `json:"maxLevel"`
+	Inhibit  bool        `json:"inhibit"`
+	Curve    OutputCurve `json:"curve"`
+}
+
+type ChannelLimitInput struct {
+	Universe int                             `json:"universe"`
+	Channel  int                             `json:"channel"`
+	MaxLevel graphql.Omittable[*float64]     `json:"maxLevel,omitempty"`
+	Inhibit  graphql.Omittable[*bool]        `json:"inhibit,omitempty"`
+	Curve    graphql.Omittable[*OutputCurve] `json:"curve,omitempty"`
+}
+
 type ChannelMapFixture struct {
 	ID           string      `json:"id"`
 	Name         string      `json:"name"`
@@ -155,6 +390,50 @@ type ChannelValueInput struct {
 	Value  int `json:"value"`
 }
 
+// Restricts which scene channel values replaceChannelValue considers. All set fields must match.
+type ChannelValueReplaceFilterInput struct {
+	// Only scenes with these IDs
+	SceneIds graphql.Omittable[[]string] `json:"sceneIds,omitempty"`
+	// Only these fixtures
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Only channels of this type (e.g. GOBO)
+	ChannelType graphql.Omittable[*ChannelType] `json:"channelType,omitempty"`
+	// Only channels with this name (case-insensitive)
+	ChannelName graphql.Omittable[*string] `json:"channelName,omitempty"`
+}
+
+// An abstract color; set exactly one of hex, hsv, or kelvin
+type ColorInput struct {
+	// Hex RGB color, e.g. #FF8000
+	Hex graphql.Omittable[*string]   `json:"hex,omitempty"`
+	Hsv graphql.Omittable[*HSVInput] `json:"hsv,omitempty"`
+	// Color temperature in kelvin (1000-40000)
+	Kelvin graphql.Omittable[*float64] `json:"kelvin,omitempty"`
+}
+
+type ControlSurfaceCueList struct {
+	CueListID string   `json:"cueListId"`
+	CueNumber *float64 `json:"cueNumber,omitempty"`
+	CueName   *string  `json:"cueName,omitempty"`
+	IsFading  bool     `json:"isFading"`
+	IsPaused  bool     `json:"isPaused"`
+}
+
+// What is live, in a compact form for control surfaces such as Bitfocus Companion
+// and Stream Deck to light their buttons from.
+type ControlSurfaceState struct {
+	// The scene activated last, while it is live
+	ActiveSceneID *string `json:"activeSceneId,omitempty"`
+	// Every scene live on the playback stack, by scene activation, scene board or cue
+	LiveSceneIds []string `json:"liveSceneIds"`
+	// Cue lists with a live cue, the most recently gone first
+	CueLists []*ControlSurfaceCueList `json:"cueLists"`
+	// Scene board buttons whose scene is live on their board, held, or flashing
+	LitButtonIds []string `json:"litButtonIds"`
+	GrandMaster  float64  `json:"grandMaster"`
+	IsBlackout   bool     `json:"isBlackout"`
+}
+
 type CreateChannelDefinitionInput struct {
 	Name         string                           `json:"name"`
 	Type         ChannelType                      `json:"type"`
@@ -164,25 +443,57 @@ type CreateChannelDefinitionInput struct {
 	DefaultValue int                              `json:"defaultValue"`
 	FadeBehavior graphql.Omittable[*FadeBehavior] `json:"fadeBehavior,omitempty"`
 	IsDiscrete   graphql.Omittable[*bool]         `json:"isDiscrete,omitempty"`
+	// 0-indexed cell of a multi-cell fixture; leave out for master channels.
+	// Cells are numbered from 0 without gaps.
+	Cell graphql.Omittable[*int] `json:"cell,omitempty"`
 }
 
 type CreateCueInput struct {
-	Name        string                         `json:"name"`
-	CueNumber   float64                        `json:"cueNumber"`
-	CueListID   string                         `json:"cueListId"`
-	SceneID     string                         `json:"sceneId"`
-	FadeInTime  float64                        `json:"fadeInTime"`
-	FadeOutTime float64                        `json:"fadeOutTime"`
-	FollowTime  graphql.Omittable[*float64]    `json:"followTime,omitempty"`
-	EasingType  graphql.Omittable[*EasingType] `json:"easingType,omitempty"`
-	Notes       graphql.Omittable[*string]     `json:"notes,omitempty"`
+	// Leave empty to generate a name from the project's cue pattern
+	Name           string                           `json:"name"`
+	SecondaryLabel graphql.Omittable[*string]       `json:"secondaryLabel,omitempty"`
+	CueNumber      float64                          `json:"cueNumber"`
+	CueListID      string                           `json:"cueListId"`
+	SceneID        string                           `json:"sceneId"`
+	FadeInTime     float64                          `json:"fadeInTime"`
+	FadeOutTime    float64                          `json:"fadeOutTime"`
+	FollowTime     graphql.Omittable[*float64]      `json:"followTime,omitempty"`
+	FollowQuantize graphql.Omittable[*BeatQuantize] `json:"followQuantize,omitempty"`
+	EasingType     graphql.Omittable[*EasingType]   `json:"easingType,omitempty"`
+	Notes          graphql.Omittable[*string]       `json:"notes,omitempty"`
+	// HH:MM:SS:FF; null clears it
+	Timecode    graphql.Omittable[*string] `json:"timecode,omitempty"`
+	Block       graphql.Omittable[*bool]   `json:"block,omitempty"`
+	MoveInBlack graphql.Omittable[*bool]   `json:"moveInBlack,omitempty"`
 }
 
 type CreateCueListInput struct {
 	Name        string                     `json:"name"`
 	Description graphql.Omittable[*string] `json:"description,omitempty"`
 	Loop        graphql.Omittable[*bool]   `json:"loop,omitempty"`
-	ProjectID   string                     `json:"projectId"`
+	// Defaults to TIMED
+	PlaybackMode graphql.Omittable[*CueListPlaybackMode] `json:"playbackMode,omitempty"`
+	Tracking     graphql.Omittable[*bool]                `json:"tracking,omitempty"`
+	ProjectID    string                                  `json:"projectId"`
+}
+
+type CreateEffectInput struct {
+	ProjectID string     `json:"projectId"`
+	Name      string     `json:"name"`
+	Type      EffectType `json:"type"`
+	// Required unless selectionSetId is given
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Take the fixtures of a selection set, in its order, instead of fixtureIds
+	SelectionSetID graphql.Omittable[*string] `json:"selectionSetId,omitempty"`
+	// Reorder the fixtures before saving them
+	SelectionOrder graphql.Omittable[*SelectionOrder] `json:"selectionOrder,omitempty"`
+	SceneID        graphql.Omittable[*string]         `json:"sceneId,omitempty"`
+	Rate           graphql.Omittable[*float64]        `json:"rate,omitempty"`
+	Size           graphql.Omittable[*float64]        `json:"size,omitempty"`
+	PhaseOffset    graphql.Omittable[*float64]        `json:"phaseOffset,omitempty"`
+	Order          graphql.Omittable[*EffectOrder]    `json:"order,omitempty"`
+	Low            graphql.Omittable[*float64]        `json:"low,omitempty"`
+	High           graphql.Omittable[*float64]        `json:"high,omitempty"`
 }
 
 type CreateFixtureDefinitionInput struct {
@@ -193,6 +504,17 @@ type CreateFixtureDefinitionInput struct {
 	Modes        graphql.Omittable[[]*CreateModeInput] `json:"modes,omitempty"`
 }
 
+type CreateFixtureGroupInput struct {
+	ProjectID   string                      `json:"projectId"`
+	Name        string                      `json:"name"`
+	Description graphql.Omittable[*string]  `json:"description,omitempty"`
+	FixtureIds  graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Take the fixtures of a selection set, in its order, instead of fixtureIds
+	SelectionSetID graphql.Omittable[*string] `json:"selectionSetId,omitempty"`
+	// Reorder the fixtures before saving them
+	SelectionOrder graphql.Omittable[*SelectionOrder] `json:"selectionOrder,omitempty"`
+}
+
 type CreateFixtureInstanceInput struct {
 	Name         string                      `json:"name"`
 	Description  graphql.Omittable[*string]  `json:"description,omitempty"`
@@ -202,6 +524,18 @@ type CreateFixtureInstanceInput struct {
 	Universe     int                         `json:"universe"`
 	StartChannel int                         `json:"startChannel"`
 	Tags         graphql.Omittable[[]string] `json:"tags,omitempty"`
+	// Intensity ceiling (0-1) enforced at the DMX output
+	MaxIntensity graphql.Omittable[*float64] `json:"maxIntensity,omitempty"`
+}
+
+type CreateLayoutZoneInput struct {
+	ProjectID string                     `json:"projectId"`
+	Name      string                     `json:"name"`
+	X         float64                    `json:"x"`
+	Y         float64                    `json:"y"`
+	Width     float64                    `json:"width"`
+	Height    float64                    `json:"height"`
+	Color     graphql.Omittable[*string] `json:"color,omitempty"`
 }
 
 type CreateModeInput struct {
@@ -210,37 +544,145 @@ type CreateModeInput struct {
 	Channels  []string                   `json:"channels"`
 }
 
+type CreatePaletteInput struct {
+	ProjectID string      `json:"projectId"`
+	Name      string      `json:"name"`
+	Kind      PaletteKind `json:"kind"`
+	// Channel types must belong to the palette's kind
+	Channels []*TypedChannelValueInput `json:"channels"`
+}
+
+type CreatePixelMatrixInput struct {
+	ProjectID string                                      `json:"projectId"`
+	Name      string                                      `json:"name"`
+	Width     int                                         `json:"width"`
+	Height    int                                         `json:"height"`
+	Pixels    graphql.Omittable[[]*PixelMatrixPixelInput] `json:"pixels,omitempty"`
+}
+
 type CreateProjectInput struct {
-	Name        string                     `json:"name"`
-	Description graphql.Omittable[*string] `json:"description,omitempty"`
+	Name           string                      `json:"name"`
+	Description    graphql.Omittable[*string]  `json:"description,omitempty"`
+	DefaultFadeIn  graphql.Omittable[*float64] `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut graphql.Omittable[*float64] `json:"defaultFadeOut,omitempty"`
 }
 
 type CreateSceneBoardButtonInput struct {
-	SceneBoardID string                     `json:"sceneBoardId"`
-	SceneID      string                     `json:"sceneId"`
-	LayoutX      int                        `json:"layoutX"`
-	LayoutY      int                        `json:"layoutY"`
-	Width        graphql.Omittable[*int]    `json:"width,omitempty"`
-	Height       graphql.Omittable[*int]    `json:"height,omitempty"`
-	Color        graphql.Omittable[*string] `json:"color,omitempty"`
-	Label        graphql.Omittable[*string] `json:"label,omitempty"`
+	SceneBoardID string                      `json:"sceneBoardId"`
+	SceneID      string                      `json:"sceneId"`
+	LayoutX      int                         `json:"layoutX"`
+	LayoutY      int                         `json:"layoutY"`
+	Width        graphql.Omittable[*int]     `json:"width,omitempty"`
+	Height       graphql.Omittable[*int]     `json:"height,omitempty"`
+	Color        graphql.Omittable[*string]  `json:"color,omitempty"`
+	Label        graphql.Omittable[*string]  `json:"label,omitempty"`
+	FadeInTime   graphql.Omittable[*float64] `json:"fadeInTime,omitempty"`
+	FadeOutTime  graphql.Omittable[*float64] `json:"fadeOutTime,omitempty"`
+	FlashMode    graphql.Omittable[*bool]    `json:"flashMode,omitempty"`
+	// 0-1
+	FlashLevel       graphql.Omittable[*float64] `json:"flashLevel,omitempty"`
+	FlashReleaseTime graphql.Omittable[*float64] `json:"flashReleaseTime,omitempty"`
+	AudioTrigger     graphql.Omittable[*bool]    `json:"audioTrigger,omitempty"`
+	// Null for the first configured band
+	AudioBand   graphql.Omittable[*string]             `json:"audioBand,omitempty"`
+	AudioAction graphql.Omittable[*AudioTriggerAction] `json:"audioAction,omitempty"`
 }
 
 type CreateSceneBoardInput struct {
-	Name            string                      `json:"name"`
-	Description     graphql.Omittable[*string]  `json:"description,omitempty"`
-	ProjectID       string                      `json:"projectId"`
-	DefaultFadeTime graphql.Omittable[*float64] `json:"defaultFadeTime,omitempty"`
-	GridSize        graphql.Omittable[*int]     `json:"gridSize,omitempty"`
-	CanvasWidth     graphql.Omittable[*int]     `json:"canvasWidth,omitempty"`
-	CanvasHeight    graphql.Omittable[*int]     `json:"canvasHeight,omitempty"`
+	Name            string                              `json:"name"`
+	Description     graphql.Omittable[*string]          `json:"description,omitempty"`
+	ProjectID       string                              `json:"projectId"`
+	DefaultFadeTime graphql.Omittable[*float64]         `json:"defaultFadeTime,omitempty"`
+	GridSize        graphql.Omittable[*int]             `json:"gridSize,omitempty"`
+	CanvasWidth     graphql.Omittable[*int]             `json:"canvasWidth,omitempty"`
+	CanvasHeight    graphql.Omittable[*int]             `json:"canvasHeight,omitempty"`
+	HoldRampTime    graphql.Omittable[*float64]         `json:"holdRampTime,omitempty"`
+	HoldCurve       graphql.Omittable[*EasingType]      `json:"holdCurve,omitempty"`
+	HoldReleaseMode graphql.Omittable[*HoldReleaseMode] `json:"holdReleaseMode,omitempty"`
 }
 
 type CreateSceneInput struct {
-	Name          string                     `json:"name"`
-	Description   graphql.Omittable[*string] `json:"description,omitempty"`
-	ProjectID     string                     `json:"projectId"`
-	FixtureValues []*FixtureValueInput       `json:"fixtureValues"`
+	// Leave empty to generate a name from the project's scene pattern
+	Name           string                                `json:"name"`
+	SecondaryLabel graphql.Omittable[*string]            `json:"secondaryLabel,omitempty"`
+	Description    graphql.Omittable[*string]            `json:"description,omitempty"`
+	ProjectID      string                                `json:"projectId"`
+	FixtureValues  []*FixtureValueInput                  `json:"fixtureValues"`
+	GroupValues    graphql.Omittable[[]*GroupValueInput] `json:"groupValues,omitempty"`
+	DefaultFadeIn  graphql.Omittable[*float64]           `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut graphql.Omittable[*float64]           `json:"defaultFadeOut,omitempty"`
+}
+
+// CRON schedules take cron; SUNRISE and SUNSET take latitude and longitude.
+// ACTIVATE_SCENE takes sceneId and CUE_LIST_GO takes cueListId.
+type CreateScheduleInput struct {
+	ProjectID     string                         `json:"projectId"`
+	Name          string                         `json:"name"`
+	Enabled       graphql.Omittable[*bool]       `json:"enabled,omitempty"`
+	Trigger       ScheduleTrigger                `json:"trigger"`
+	Cron          graphql.Omittable[*string]     `json:"cron,omitempty"`
+	Latitude      graphql.Omittable[*float64]    `json:"latitude,omitempty"`
+	Longitude     graphql.Omittable[*float64]    `json:"longitude,omitempty"`
+	OffsetMinutes graphql.Omittable[*int]        `json:"offsetMinutes,omitempty"`
+	Days          graphql.Omittable[[]DayOfWeek] `json:"days,omitempty"`
+	Action        ScheduleAction                 `json:"action"`
+	SceneID       graphql.Omittable[*string]     `json:"sceneId,omitempty"`
+	CueListID     graphql.Omittable[*string]     `json:"cueListId,omitempty"`
+	FadeTime      graphql.Omittable[*float64]    `json:"fadeTime,omitempty"`
+}
+
+type CreateSelectionSetInput struct {
+	ProjectID   string                     `json:"projectId"`
+	Name        string                     `json:"name"`
+	Description graphql.Omittable[*string] `json:"description,omitempty"`
+	FixtureIds  []string                   `json:"fixtureIds"`
+	// Reorder the fixtures before saving them
+	Order graphql.Omittable[*SelectionOrder] `json:"order,omitempty"`
+}
+
+type CreateShowTimerInput struct {
+	Name string                            `json:"name"`
+	Kind graphql.Omittable[*ShowTimerKind] `json:"kind,omitempty"`
+	// Required for countdowns
+	DurationSeconds  graphql.Omittable[*float64] `json:"durationSeconds,omitempty"`
+	TriggerCueListID graphql.Omittable[*string]  `json:"triggerCueListId,omitempty"`
+	TriggerCueNumber graphql.Omittable[*float64] `json:"triggerCueNumber,omitempty"`
+}
+
+// A submaster takes a scene or a fixture selection, not both
+type CreateSubmasterInput struct {
+	ProjectID  string                      `json:"projectId"`
+	Name       string                      `json:"name"`
+	Page       graphql.Omittable[*int]     `json:"page,omitempty"`
+	Slot       int                         `json:"slot"`
+	SceneID    graphql.Omittable[*string]  `json:"sceneId,omitempty"`
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+}
+
+type CreateUniverseInput struct {
+	ProjectID string                     `json:"projectId"`
+	Number    int                        `json:"number"`
+	Label     graphql.Omittable[*string] `json:"label,omitempty"`
+	// Defaults to ARTNET
+	Protocol    graphql.Omittable[*UniverseProtocol] `json:"protocol,omitempty"`
+	Destination graphql.Omittable[*string]           `json:"destination,omitempty"`
+	// Defaults to true
+	Enabled graphql.Omittable[*bool] `json:"enabled,omitempty"`
+}
+
+type CreateUserInput struct {
+	Email string                     `json:"email"`
+	Name  graphql.Omittable[*string] `json:"name,omitempty"`
+	// At least 8 characters
+	Password string                       `json:"password"`
+	Role     graphql.Omittable[*UserRole] `json:"role,omitempty"`
+}
+
+// A cue list copied into another project, with the scenes of its cues
+type CueListCopyResult struct {
+	CueList models.CueList `json:"cueList"`
+	// Fixtures with no match in the target project, whose values were left out
+	Warnings []string `json:"warnings"`
 }
 
 type CueListPlaybackStatus struct {
@@ -249,12 +691,21 @@ type CueListPlaybackStatus struct {
 	// True when a scene's values are currently active on DMX fixtures (stays true after fade completes until stopped)
 	IsPlaying bool `json:"isPlaying"`
 	// True when a fade-in transition is in progress
-	IsFading     bool        `json:"isFading"`
-	CurrentCue   *models.Cue `json:"currentCue,omitempty"`
-	NextCue      *models.Cue `json:"nextCue,omitempty"`
-	PreviousCue  *models.Cue `json:"previousCue,omitempty"`
-	FadeProgress *float64    `json:"fadeProgress,omitempty"`
-	LastUpdated  string      `json:"lastUpdated"`
+	IsFading bool `json:"isFading"`
+	// True while the cue list's follow chain is paused
+	IsPaused    bool        `json:"isPaused"`
+	CurrentCue  *models.Cue `json:"currentCue,omitempty"`
+	NextCue     *models.Cue `json:"nextCue,omitempty"`
+	PreviousCue *models.Cue `json:"previousCue,omitempty"`
+	// Fade-in progress of the current cue, 0-100
+	FadeProgress *float64 `json:"fadeProgress,omitempty"`
+	// When the current cue auto-follows to the next; null when no follow is pending
+	FollowAt *string `json:"followAt,omitempty"`
+	// Seconds until the pending auto-follow; updated every second while waiting, held while paused
+	FollowRemaining *float64 `json:"followRemaining,omitempty"`
+	// Last crossfader position (0-1) of a crossfader-mode cue list; null until the crossfader is first moved
+	CrossfadePosition *float64 `json:"crossfadePosition,omitempty"`
+	LastUpdated       string   `json:"lastUpdated"`
 }
 
 type CueListSummary struct {
@@ -268,10 +719,12 @@ type CueListSummary struct {
 }
 
 type CueListUpdateItem struct {
-	CueListID   string                     `json:"cueListId"`
-	Name        graphql.Omittable[*string] `json:"name,omitempty"`
-	Description graphql.Omittable[*string] `json:"description,omitempty"`
-	Loop        graphql.Omittable[*bool]   `json:"loop,omitempty"`
+	CueListID    string                                  `json:"cueListId"`
+	Name         graphql.Omittable[*string]              `json:"name,omitempty"`
+	Description  graphql.Omittable[*string]              `json:"description,omitempty"`
+	Loop         graphql.Omittable[*bool]                `json:"loop,omitempty"`
+	PlaybackMode graphql.Omittable[*CueListPlaybackMode] `json:"playbackMode,omitempty"`
+	Tracking     graphql.Omittable[*bool]                `json:"tracking,omitempty"`
 }
 
 type CueOrderInput struct {
@@ -292,11 +745,147 @@ type CueUsageSummary struct {
 	CueListName string  `json:"cueListName"`
 }
 
+// How often operations have used a deprecated schema element since the server started or the counts were reset
+type DeprecatedFieldUsage struct {
+	// Schema coordinate: Type.field, Type.field(arg:), or Enum.VALUE
+	Coordinate string `json:"coordinate"`
+	Reason     string `json:"reason"`
+	// Operations that used the element
+	Count      int     `json:"count"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+}
+
+// Another LacyLights server advertising on the local network
+type DiscoveredServer struct {
+	// The advertised instance name
+	Name string `json:"name"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// IPv4 addresses of the host
+	Addresses []string `json:"addresses"`
+	Version   *string  `json:"version,omitempty"`
+	// How many projects the server has
+	ProjectCount *int `json:"projectCount,omitempty"`
+	// The name of the project most recently changed on the server
+	Project *string `json:"project,omitempty"`
+	// The server's GraphQL endpoint at its first address, or its host name without one
+	GraphqlURL string `json:"graphqlUrl"`
+	LastSeen   string `json:"lastSeen"`
+}
+
+// How this server advertises itself on the local network by mDNS
+type DiscoveryStatus struct {
+	// Whether the server advertises itself and listens for other servers
+	Enabled bool `json:"enabled"`
+	// The advertised instance name, after any rename to resolve a conflict
+	Name *string `json:"name,omitempty"`
+	// The advertised host name, such as booth.local
+	Host *string `json:"host,omitempty"`
+	Port *int    `json:"port,omitempty"`
+	// The advertised DNS-SD service types
+	ServiceTypes []string `json:"serviceTypes"`
+}
+
+// A DMX channel: a universe and a channel (1-512)
+type DmxAddress struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+}
+
+type DmxAddressInput struct {
+	Universe int `json:"universe"`
+	// 1-512
+	Channel int `json:"channel"`
+}
+
+// A free block of DMX channels
+type DmxAddressSuggestion struct {
+	Universe     int `json:"universe"`
+	StartChannel int `json:"startChannel"`
+	EndChannel   int `json:"endChannel"`
+}
+
+// Result of a short diagnostic capture of Art-Net traffic
+type DmxCaptureResult struct {
+	// Captured universe, or null when all universes were captured
+	Universe        *int    `json:"universe,omitempty"`
+	StartedAt       string  `json:"startedAt"`
+	EndedAt         string  `json:"endedAt"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	PacketCount     int     `json:"packetCount"`
+	// Packets discarded after the capture reached its size limit
+	DroppedCount int `json:"droppedCount"`
+	// JSON artifact with timestamped, hex-encoded raw packets for download
+	CaptureContent string `json:"captureContent"`
+}
+
+// A recording of the DMX output, kept in the server's recording directory and
+// replayable in place of live output
+type DmxRecording struct {
+	// File name, which identifies the recording
+	Name string `json:"name"`
+	// Compressed size in bytes
+	Size      int    `json:"size"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// What the DMX output is being recorded to and replayed from
+type DmxRecordingStatus struct {
+	IsRecording bool `json:"isRecording"`
+	// Recording being written
+	Recording          *string `json:"recording,omitempty"`
+	RecordingStartedAt *string `json:"recordingStartedAt,omitempty"`
+	// Changed frames written to the recording
+	RecordedFrames int `json:"recordedFrames"`
+	// Frames not written because the disk fell behind the output
+	DroppedFrames int  `json:"droppedFrames"`
+	IsReplaying   bool `json:"isReplaying"`
+	// Recording replayed in place of live output
+	Replaying       *string `json:"replaying,omitempty"`
+	ReplayStartedAt *string `json:"replayStartedAt,omitempty"`
+	// Whether the replay starts over at the end of the recording
+	Loop bool `json:"loop"`
+}
+
+// Counts of the universe frames DMX output has sent. Each transmit cycle counts
+// every universe once, so skippedFrames is what sending every universe every
+// cycle would have added.
+type DmxTransmitStats struct {
+	// When counting started: at startup or the last reset
+	Since           string                      `json:"since"`
+	Cycles          int                         `json:"cycles"`
+	ChangedFrames   int                         `json:"changedFrames"`
+	KeepAliveFrames int                         `json:"keepAliveFrames"`
+	SkippedFrames   int                         `json:"skippedFrames"`
+	Universes       []*DmxUniverseTransmitStats `json:"universes"`
+}
+
+// Frames of one universe counted by dmxTransmitStats
+type DmxUniverseTransmitStats struct {
+	Universe int `json:"universe"`
+	// Frames sent because the universe's output changed
+	ChangedFrames int `json:"changedFrames"`
+	// Unchanged frames resent to keep receivers alive
+	KeepAliveFrames int `json:"keepAliveFrames"`
+	// Frames not sent because the output was unchanged
+	SkippedFrames int `json:"skippedFrames"`
+}
+
 type ExportOptionsInput struct {
 	Description     graphql.Omittable[*string] `json:"description,omitempty"`
 	IncludeFixtures graphql.Omittable[*bool]   `json:"includeFixtures,omitempty"`
 	IncludeScenes   graphql.Omittable[*bool]   `json:"includeScenes,omitempty"`
 	IncludeCueLists graphql.Omittable[*bool]   `json:"includeCueLists,omitempty"`
+	// Fixtures to export. With any of fixtureIds, sceneIds or cueListIds set,
+	// the export holds just the listed records and what they depend on: cue
+	// lists bring the scenes their cues play, scenes the fixtures, groups and
+	// palettes they set, and fixtures their definitions. The include flags are
+	// then ignored and scene boards are left out.
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Scenes to export, with their dependencies
+	SceneIds graphql.Omittable[[]string] `json:"sceneIds,omitempty"`
+	// Cue lists to export, with their dependencies
+	CueListIds graphql.Omittable[[]string] `json:"cueListIds,omitempty"`
 }
 
 type ExportResult struct {
@@ -313,6 +902,60 @@ type ExportStats struct {
 	CueListsCount           int `json:"cueListsCount"`
 	CuesCount               int `json:"cuesCount"`
 	SceneBoardsCount        int `json:"sceneBoardsCount"`
+	FixtureGroupsCount      int `json:"fixtureGroupsCount"`
+	PalettesCount           int `json:"palettesCount"`
+	// Fixtures exported, including those a selective export pulled in
+	FixtureIds []string `json:"fixtureIds"`
+	// Scenes exported, including those a selective export pulled in
+	SceneIds []string `json:"sceneIds"`
+	// Cue lists exported
+	CueListIds []string `json:"cueListIds"`
+}
+
+type FaderWingConfigInput struct {
+	Enabled  bool                     `json:"enabled"`
+	Mappings []*FaderWingMappingInput `json:"mappings"`
+}
+
+// Routes one received DMX channel to a control
+type FaderWingMapping struct {
+	// Input universe: the Art-Net port address + 1, or the sACN universe
+	Universe int             `json:"universe"`
+	Channel  int             `json:"channel"`
+	Target   FaderWingTarget `json:"target"`
+	TargetID *string         `json:"targetId,omitempty"`
+}
+
+type FaderWingMappingInput struct {
+	Universe int `json:"universe"`
+	// 1-512
+	Channel int             `json:"channel"`
+	Target  FaderWingTarget `json:"target"`
+	// Submaster or scene board button ID; omitted for the grand master
+	TargetID graphql.Omittable[*string] `json:"targetId,omitempty"`
+}
+
+// Remote fader wing input: DMX received over Art-Net or sACN drives server controls
+type FaderWingStatus struct {
+	Enabled  bool                `json:"enabled"`
+	Mappings []*FaderWingMapping `json:"mappings"`
+	// Whether the Art-Net port is open for input
+	Listening bool `json:"listening"`
+	// Targets this server can map channels to
+	AvailableTargets []FaderWingTarget `json:"availableTargets"`
+	PacketsReceived  int               `json:"packetsReceived"`
+	LastPacketAt     *string           `json:"lastPacketAt,omitempty"`
+	// Address of the last sender
+	LastSource *string `json:"lastSource,omitempty"`
+}
+
+// A cell of a multi-cell fixture definition: the channels one part of the fixture has of its own
+type FixtureCell struct {
+	// 0-indexed
+	Index int `json:"index"`
+	// Cell 1, Cell 2, ...
+	Name     string                      `json:"name"`
+	Channels []*models.ChannelDefinition `json:"channels"`
 }
 
 type FixtureChannelAssignment struct {
@@ -326,6 +969,12 @@ type FixtureChannelAssignment struct {
 	ChannelRange string  `json:"channelRange"`
 }
 
+// The channel values a color maps to on a fixture
+type FixtureColorValues struct {
+	FixtureID string                 `json:"fixtureId"`
+	Channels  []*models.ChannelValue `json:"channels"`
+}
+
 type FixtureDefinitionFilter struct {
 	Manufacturer graphql.Omittable[*string]       `json:"manufacturer,omitempty"`
 	Model        graphql.Omittable[*string]       `json:"model,omitempty"`
@@ -341,6 +990,12 @@ type FixtureDefinitionUpdateItem struct {
 	Type         graphql.Omittable[*FixtureType] `json:"type,omitempty"`
 }
 
+// Fixture instances in one project that use a fixture definition
+type FixtureDefinitionUsage struct {
+	Project  models.Project            `json:"project"`
+	Fixtures []*models.FixtureInstance `json:"fixtures"`
+}
+
 type FixtureFilterInput struct {
 	Type         graphql.Omittable[*FixtureType] `json:"type,omitempty"`
 	Universe     graphql.Omittable[*int]         `json:"universe,omitempty"`
@@ -349,11 +1004,73 @@ type FixtureFilterInput struct {
 	Model        graphql.Omittable[*string]      `json:"model,omitempty"`
 }
 
+// A cell of a multi-cell fixture: the channels one part of it has of its own
+type FixtureInstanceCell struct {
+	// 0-indexed
+	Index int `json:"index"`
+	// Cell 1, Cell 2, ...
+	Name     string                    `json:"name"`
+	Channels []*models.InstanceChannel `json:"channels"`
+}
+
 type FixtureInstancePage struct {
 	Fixtures   []*models.FixtureInstance `json:"fixtures"`
 	Pagination PaginationInfo            `json:"pagination"`
 }
 
+// A fixture's intensity cap and its effect on the current output
+type FixtureIntensityLimit struct {
+	Fixture      models.FixtureInstance `json:"fixture"`
+	MaxIntensity float64                `json:"maxIntensity"`
+	// Highest DMX value the capped channels may output
+	MaxValue int `json:"maxValue"`
+	Universe int `json:"universe"`
+	// Absolute DMX channels the cap applies to (intensity, or additive color channels on fixtures without a dimmer)
+	Channels []int `json:"channels"`
+	// Channels whose requested level is currently above the cap
+	LimitedChannels []int `json:"limitedChannels"`
+}
+
+type FixtureLibraryComparison struct {
+	URL         string                                `json:"url"`
+	GeneratedAt string                                `json:"generatedAt"`
+	Definitions []*FixtureLibraryDefinitionComparison `json:"definitions"`
+	Templates   []*FixtureLibraryTemplateInfo         `json:"templates"`
+}
+
+// A definition in a remote fixture library compared with the local library
+type FixtureLibraryDefinitionComparison struct {
+	Manufacturer string                         `json:"manufacturer"`
+	Model        string                         `json:"model"`
+	Type         FixtureType                    `json:"type"`
+	Status       FixtureLibraryDefinitionStatus `json:"status"`
+	// Content hash of the library definition
+	RemoteVersion   string `json:"remoteVersion"`
+	RemoteUpdatedAt string `json:"remoteUpdatedAt"`
+	// Content hash of the local definition (null if NEW)
+	LocalVersion   *string `json:"localVersion,omitempty"`
+	LocalUpdatedAt *string `json:"localUpdatedAt,omitempty"`
+}
+
+type FixtureLibraryDefinitionKeyInput struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+}
+
+type FixtureLibrarySyncResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	// Projects created from imported templates
+	TemplateProjectIds []string `json:"templateProjectIds"`
+	Warnings           []string `json:"warnings"`
+}
+
+type FixtureLibraryTemplateInfo struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
 type FixtureMapping struct {
 	LacyLightsKey   string `json:"lacyLightsKey"`
 	QlcManufacturer string `json:"qlcManufacturer"`
@@ -416,6 +1133,21 @@ type FixtureValueInput struct {
 	FixtureID  string                  `json:"fixtureId"`
 	Channels   []*ChannelValueInput    `json:"channels"`
 	SceneOrder graphql.Omittable[*int] `json:"sceneOrder,omitempty"`
+	// Sets the fixture's color channels that channels does not set
+	Color graphql.Omittable[*ColorInput] `json:"color,omitempty"`
+	// Sets the color channels of single cells of a multi-cell fixture, over color
+	CellColors graphql.Omittable[[]*CellColorInput] `json:"cellColors,omitempty"`
+	// Palettes to reference; each sets the fixture's channels of its types over
+	// channels and color, later palettes winning
+	PaletteIds graphql.Omittable[[]string] `json:"paletteIds,omitempty"`
+}
+
+type ForceDeleteDefinitionResult struct {
+	// Fixture instances removed along with their scene values
+	DeletedFixtureIds []string `json:"deletedFixtureIds"`
+	// Fixture instances switched to the replacement definition
+	RemappedFixtureIds []string `json:"remappedFixtureIds"`
+	AffectedProjectIds []string `json:"affectedProjectIds"`
 }
 
 // Global playback status - returns which cue list is currently playing (if any)
@@ -439,6 +1171,49 @@ type GlobalPlaybackStatus struct {
 	LastUpdated  string   `json:"lastUpdated"`
 }
 
+// Later groups in a scene win for fixtures in several
+type GroupValueInput struct {
+	GroupID  string                    `json:"groupId"`
+	Channels []*TypedChannelValueInput `json:"channels"`
+}
+
+type HSVInput struct {
+	// Hue in degrees (0-360)
+	H float64 `json:"h"`
+	// Saturation (0-1)
+	S float64 `json:"s"`
+	// Value (0-1)
+	V float64 `json:"v"`
+}
+
+type HouseLightsStatus struct {
+	ProjectID string `json:"projectId"`
+	// Engaged house lights hold their channels; released ones leave them to the stage
+	Engaged bool `json:"engaged"`
+	// Null when released
+	Preset *HouseLightsPreset `json:"preset,omitempty"`
+	// 0 to 1, averaged over the channels while fading
+	Level       float64 `json:"level"`
+	TargetLevel float64 `json:"targetLevel"`
+	Fading      bool    `json:"fading"`
+}
+
+// What an import does with one record of the document. Cues are listed only
+// when they are skipped.
+type ImportChange struct {
+	EntityType ImportEntityType `json:"entityType"`
+	Name       string           `json:"name"`
+	Action     ImportAction     `json:"action"`
+	// Why the record is merged, renamed or skipped
+	Reason *string `json:"reason,omitempty"`
+}
+
+type ImportGDTFFixtureInput struct {
+	// The .gdtf file, base64 encoded
+	GdtfFile string                   `json:"gdtfFile"`
+	Replace  graphql.Omittable[*bool] `json:"replace,omitempty"`
+}
+
 type ImportOFLFixtureInput struct {
 	Manufacturer   string                   `json:"manufacturer"`
 	OflFixtureJSON string                   `json:"oflFixtureJson"`
@@ -451,12 +1226,20 @@ type ImportOptionsInput struct {
 	ProjectName             graphql.Omittable[*string]                  `json:"projectName,omitempty"`
 	FixtureConflictStrategy graphql.Omittable[*FixtureConflictStrategy] `json:"fixtureConflictStrategy,omitempty"`
 	ImportBuiltInFixtures   graphql.Omittable[*bool]                    `json:"importBuiltInFixtures,omitempty"`
+	// Validate the document and report what the import would create, merge,
+	// rename and skip, without changing anything
+	DryRun graphql.Omittable[*bool] `json:"dryRun,omitempty"`
 }
 
 type ImportResult struct {
+	// The project imported into; empty for a dry run that would create one
 	ProjectID string      `json:"projectId"`
 	Stats     ImportStats `json:"stats"`
 	Warnings  []string    `json:"warnings"`
+	// Whether this was a dry run that changed nothing
+	DryRun bool `json:"dryRun"`
+	// What a dry run would do, record by record; empty for an import
+	Changes []*ImportChange `json:"changes"`
 }
 
 type ImportStats struct {
@@ -466,6 +1249,68 @@ type ImportStats struct {
 	CueListsCreated           int `json:"cueListsCreated"`
 	CuesCreated               int `json:"cuesCreated"`
 	SceneBoardsCreated        int `json:"sceneBoardsCreated"`
+	FixtureGroupsCreated      int `json:"fixtureGroupsCreated"`
+	PalettesCreated           int `json:"palettesCreated"`
+}
+
+// Channels held at fixed values until released, whatever cue lists, scenes,
+// effects and the programmer do, like a stage manager's desk light. Masters do
+// not scale them; overrides, blackout, house lights and limits still apply.
+type Independent struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// The project it was set for, if any
+	ProjectID *string `json:"projectId,omitempty"`
+	// In universe and channel order
+	Channels []*IndependentChannel `json:"channels"`
+	// When it was set
+	Since string `json:"since"`
+}
+
+type IndependentChannel struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+	Value    int `json:"value"`
+}
+
+type IndependentChannelInput struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+	Value    int `json:"value"`
+}
+
+type IndependentFixtureInput struct {
+	FixtureID string               `json:"fixtureId"`
+	Channels  []*ChannelValueInput `json:"channels"`
+}
+
+type IndependentInput struct {
+	// Replaces the independent with this ID; a new one is made when left out
+	ID   graphql.Omittable[*string] `json:"id,omitempty"`
+	Name string                     `json:"name"`
+	// Defaults to the project of the fixtures
+	ProjectID graphql.Omittable[*string]                    `json:"projectId,omitempty"`
+	Channels  graphql.Omittable[[]*IndependentChannelInput] `json:"channels,omitempty"`
+	// Fixture channels, by offset from the fixture's address
+	Fixtures graphql.Omittable[[]*IndependentFixtureInput] `json:"fixtures,omitempty"`
+}
+
+// A cue to insert between two others; it is numbered automatically
+type InsertCueInput struct {
+	// Leave empty to generate a name from the project's cue pattern
+	Name           string                           `json:"name"`
+	SecondaryLabel graphql.Omittable[*string]       `json:"secondaryLabel,omitempty"`
+	SceneID        string                           `json:"sceneId"`
+	FadeInTime     float64                          `json:"fadeInTime"`
+	FadeOutTime    float64                          `json:"fadeOutTime"`
+	FollowTime     graphql.Omittable[*float64]      `json:"followTime,omitempty"`
+	FollowQuantize graphql.Omittable[*BeatQuantize] `json:"followQuantize,omitempty"`
+	EasingType     graphql.Omittable[*EasingType]   `json:"easingType,omitempty"`
+	Notes          graphql.Omittable[*string]       `json:"notes,omitempty"`
+	// HH:MM:SS:FF; null clears it
+	Timecode    graphql.Omittable[*string] `json:"timecode,omitempty"`
+	Block       graphql.Omittable[*bool]   `json:"block,omitempty"`
+	MoveInBlack graphql.Omittable[*bool]   `json:"moveInBlack,omitempty"`
 }
 
 type LacyLightsFixture struct {
@@ -473,9 +1318,124 @@ type LacyLightsFixture struct {
 	Model        string `json:"model"`
 }
 
+// A change to a project's stage layout
+type LayoutChange struct {
+	ProjectID string `json:"projectId"`
+	// Fixtures that moved, at their new positions
+	Fixtures []*models.FixtureInstance `json:"fixtures"`
+	// Zones created or changed
+	Zones          []*models.LayoutZone `json:"zones"`
+	DeletedZoneIds []string             `json:"deletedZoneIds"`
+}
+
+// A detail of a log entry. Keys in groups are qualified, as in request.id.
+type LogAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type LogEntry struct {
+	// Increases by one per entry; pass it as afterId to fetch only newer entries
+	ID        string   `json:"id"`
+	Timestamp string   `json:"timestamp"`
+	Level     LogLevel `json:"level"`
+	// The subsystem that logged it, such as dmx, fade, playback or graphql
+	Module     string          `json:"module"`
+	Message    string          `json:"message"`
+	Attributes []*LogAttribute `json:"attributes"`
+}
+
+type LogLevels struct {
+	// The level of modules without their own
+	DefaultLevel LogLevel `json:"defaultLevel"`
+	// Every module that has logged or has its own level, in name order
+	Modules []*ModuleLogLevel `json:"modules"`
+}
+
+// One step of a macro; only the fields of its type are set
+type MacroAction struct {
+	Type MacroActionType `json:"type"`
+	// ACTIVATE_SCENE: scene in the board's project
+	SceneID *string `json:"sceneId,omitempty"`
+	// ACTIVATE_SCENE and CUE_LIST_GO: seconds, overriding the usual fade time
+	FadeTime *float64 `json:"fadeTime,omitempty"`
+	// WAIT: seconds
+	WaitTime *float64 `json:"waitTime,omitempty"`
+	// CUE_LIST_GO: cue list in the board's project
+	CueListID *string `json:"cueListId,omitempty"`
+	// SEND_OSC: destination host name or address
+	OscHost *string `json:"oscHost,omitempty"`
+	// SEND_OSC: destination UDP port
+	OscPort *int `json:"oscPort,omitempty"`
+	// SEND_OSC: address pattern, e.g. /cue/1/go
+	OscAddress *string `json:"oscAddress,omitempty"`
+	// SEND_OSC: message arguments
+	OscArguments []*OscArgument `json:"oscArguments,omitempty"`
+}
+
+// One step of a macro; set the fields its type uses
+type MacroActionInput struct {
+	Type         MacroActionType                        `json:"type"`
+	SceneID      graphql.Omittable[*string]             `json:"sceneId,omitempty"`
+	FadeTime     graphql.Omittable[*float64]            `json:"fadeTime,omitempty"`
+	WaitTime     graphql.Omittable[*float64]            `json:"waitTime,omitempty"`
+	CueListID    graphql.Omittable[*string]             `json:"cueListId,omitempty"`
+	OscHost      graphql.Omittable[*string]             `json:"oscHost,omitempty"`
+	OscPort      graphql.Omittable[*int]                `json:"oscPort,omitempty"`
+	OscAddress   graphql.Omittable[*string]             `json:"oscAddress,omitempty"`
+	OscArguments graphql.Omittable[[]*OscArgumentInput] `json:"oscArguments,omitempty"`
+}
+
+// Output masters. They proportionally scale fading intensity channels (or the
+// additive color channels of fixtures without a dimmer) just before output;
+// snap and discrete channels, and channel overrides, are not scaled.
+type MasterLevels struct {
+	// Fraction of full, 0 to 1; scales every universe
+	GrandMaster float64 `json:"grandMaster"`
+	// Per-universe masters below full, applied on top of the grand master
+	Universes []*UniverseMaster `json:"universes"`
+}
+
+type ModuleLogLevel struct {
+	Module string   `json:"module"`
+	Level  LogLevel `json:"level"`
+}
+
 type Mutation struct {
 }
 
+// How new scenes and cues are named in a project. Patterns use {n} (next number),
+// {project}, and any custom variables such as {act}; cue patterns may also use
+// {list} and {cue}. Names left empty at creation are generated from the pattern.
+type NamingConvention struct {
+	ScenePattern *string              `json:"scenePattern,omitempty"`
+	CuePattern   *string              `json:"cuePattern,omitempty"`
+	Uniqueness   NameUniquenessPolicy `json:"uniqueness"`
+	Variables    []*NamingVariable    `json:"variables"`
+	// Order of the scenes query when no sortBy is given
+	DefaultSceneSort SceneSortField `json:"defaultSceneSort"`
+}
+
+// Omitted fields are unchanged; null clears a pattern
+type NamingConventionInput struct {
+	ScenePattern graphql.Omittable[*string]               `json:"scenePattern,omitempty"`
+	CuePattern   graphql.Omittable[*string]               `json:"cuePattern,omitempty"`
+	Uniqueness   graphql.Omittable[*NameUniquenessPolicy] `json:"uniqueness,omitempty"`
+	// Replaces all custom variables
+	Variables        graphql.Omittable[[]*NamingVariableInput] `json:"variables,omitempty"`
+	DefaultSceneSort graphql.Omittable[*SceneSortField]        `json:"defaultSceneSort,omitempty"`
+}
+
+type NamingVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type NamingVariableInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 type NetworkInterfaceOption struct {
 	Name          string `json:"name"`
 	Address       string `json:"address"`
@@ -588,6 +1548,52 @@ type OFLUpdateCheckResult struct {
 	CheckedAt string `json:"checkedAt"`
 }
 
+// Opening hours on the given days, in server local time
+type OpeningHours struct {
+	Days []DayOfWeek `json:"days"`
+	// Opening time (HH:MM)
+	Open string `json:"open"`
+	// Closing time (HH:MM); earlier than open for hours that run past midnight
+	Close string `json:"close"`
+}
+
+type OpeningHoursInput struct {
+	Days []DayOfWeek `json:"days"`
+	// HH:MM
+	Open string `json:"open"`
+	// HH:MM; earlier than open for hours that run past midnight
+	Close string `json:"close"`
+}
+
+// A finished recording of incoming GraphQL operations
+type OperationRecording struct {
+	StartedAt      string `json:"startedAt"`
+	EndedAt        string `json:"endedAt"`
+	OperationCount int    `json:"operationCount"`
+	DroppedCount   int    `json:"droppedCount"`
+	// JSON artifact with time-ordered operations and variables (secrets redacted), replayable with cmd/replay
+	Content string `json:"content"`
+}
+
+// State of the GraphQL operation recorder used to reproduce field issues
+type OperationRecordingStatus struct {
+	IsRecording    bool    `json:"isRecording"`
+	StartedAt      *string `json:"startedAt,omitempty"`
+	OperationCount int     `json:"operationCount"`
+	// Operations not kept because the recording reached its size limit
+	DroppedCount int `json:"droppedCount"`
+}
+
+type OscArgument struct {
+	Type  OscArgumentType `json:"type"`
+	Value string          `json:"value"`
+}
+
+type OscArgumentInput struct {
+	Type  OscArgumentType `json:"type"`
+	Value string          `json:"value"`
+}
+
 type PaginationInfo struct {
 	Total      int  `json:"total"`
 	Page       int  `json:"page"`
@@ -596,10 +1602,210 @@ type PaginationInfo struct {
 	HasMore    bool `json:"hasMore"`
 }
 
+// Park a channel by address, or by fixture and channel offset
+type ParkChannelInput struct {
+	Universe  graphql.Omittable[*int]    `json:"universe,omitempty"`
+	Channel   graphql.Omittable[*int]    `json:"channel,omitempty"`
+	FixtureID graphql.Omittable[*string] `json:"fixtureId,omitempty"`
+	Offset    graphql.Omittable[*int]    `json:"offset,omitempty"`
+	Value     int                        `json:"value"`
+}
+
+// An output channel pinned at a value, as for focusing or for a broken mover.
+// Parked channels ignore playback, the programmer, independents, overrides,
+// masters, blackout and house lights; only fixture caps and channel limits
+// apply on top. Saved and restored on startup.
+type ParkedChannel struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+	Value    int `json:"value"`
+	// The fixture it was parked for, if any
+	FixtureID *string `json:"fixtureId,omitempty"`
+}
+
+type PatchSheetImportResult struct {
+	Created       []*models.FixtureInstance `json:"created"`
+	Updated       []*models.FixtureInstance `json:"updated"`
+	UnmatchedRows []*PatchSheetUnmatchedRow `json:"unmatchedRows"`
+}
+
+// A patch sheet row that was not imported
+type PatchSheetUnmatchedRow struct {
+	// Line of the row in the sheet, counting the header as 1
+	Line         int    `json:"line"`
+	Name         string `json:"name"`
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	Reason       string `json:"reason"`
+}
+
+// A fixture's cell at a position of a pixel matrix
+type PixelMatrixPixel struct {
+	// 0-indexed from the left
+	X int `json:"x"`
+	// 0-indexed from the top
+	Y         int    `json:"y"`
+	FixtureID string `json:"fixtureId"`
+	// Which of the fixture's cells, 0-indexed. A fixture has one cell per repeat
+	// of its most repeated color channel type, in channel order.
+	Cell int `json:"cell"`
+}
+
+type PixelMatrixPixelInput struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	FixtureID string `json:"fixtureId"`
+	// Defaults to the fixture's first cell
+	Cell graphql.Omittable[*int] `json:"cell,omitempty"`
+}
+
+// Recorded playback commands (GO, goto, stop, channel levels) in execution order
+type PlaybackLog struct {
+	EventCount int `json:"eventCount"`
+	// Oldest events discarded because the log reached its size limit
+	DroppedCount int `json:"droppedCount"`
+	// Timestamp of the first retained event (null if the log is empty)
+	StartedAt *string `json:"startedAt,omitempty"`
+	// JSON log artifact accepted by replayPlaybackLog
+	Content string `json:"content"`
+}
+
+// The live state resumePlayback brought back from the state journal.
+type PlaybackResumeResult struct {
+	// Cue lists whose active cue was restored
+	CueLists int `json:"cueLists"`
+	// Scenes made live again on their playbacks
+	Scenes int `json:"scenes"`
+	// Grand, universe, and submaster levels restored
+	Masters int `json:"masters"`
+	// Independents set again
+	Independents int `json:"independents"`
+	// Whether a blackout was restored
+	Blackout bool `json:"blackout"`
+}
+
+// A playback with a scene live. Live playbacks merge highest-takes-precedence
+// for intensity channels and latest-takes-precedence for every other channel.
+type PlaybackStackEntry struct {
+	PlaybackID string       `json:"playbackId"`
+	Kind       PlaybackKind `json:"kind"`
+	// The scene most recently activated on the playback
+	SceneID string        `json:"sceneId"`
+	Scene   *models.Scene `json:"scene,omitempty"`
+	// Channels the playback holds values for
+	ChannelCount int    `json:"channelCount"`
+	ActivatedAt  string `json:"activatedAt"`
+}
+
+// What one client session is viewing or editing
+type Presence struct {
+	SessionID string `json:"sessionId"`
+	ProjectID string `json:"projectId"`
+	// The signed-in user's email, or the operator name the client gave
+	Operator   string              `json:"operator"`
+	UserID     *string             `json:"userId,omitempty"`
+	EntityType *PresenceEntityType `json:"entityType,omitempty"`
+	EntityID   *string             `json:"entityId,omitempty"`
+	Activity   PresenceActivity    `json:"activity"`
+	// When the session started on its current entity
+	Since     string `json:"since"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// Report what a session is doing. Sessions without a presenceChanged
+// subscription must report at least every 45 seconds to stay present.
+type PresenceInput struct {
+	// Chosen by the client, e.g. one per browser tab. Also send it in the X-LacyLights-Session header so the session's own edit lock does not block its edits.
+	SessionID  string                                 `json:"sessionId"`
+	ProjectID  string                                 `json:"projectId"`
+	EntityType graphql.Omittable[*PresenceEntityType] `json:"entityType,omitempty"`
+	EntityID   graphql.Omittable[*string]             `json:"entityId,omitempty"`
+	Activity   graphql.Omittable[*PresenceActivity]   `json:"activity,omitempty"`
+	// Take over an edit lock held by another session
+	Force graphql.Omittable[*bool] `json:"force,omitempty"`
+}
+
+// A live universe mirrored, with a blind session's edits, to another universe
+type PreviewOutput struct {
+	Universe        int `json:"universe"`
+	PreviewUniverse int `json:"previewUniverse"`
+}
+
+type PreviewOutputInput struct {
+	Universe        int `json:"universe"`
+	PreviewUniverse int `json:"previewUniverse"`
+}
+
+// A channel held in the programmer. Programmer values are set by hand while
+// busking and take priority over playback until the programmer is cleared.
+type ProgrammerChannel struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+	Value    int `json:"value"`
+}
+
+// A one-time link to download a project archive
+type ProjectArchiveDownload struct {
+	// Path to GET the archive from; the link works once
+	URL string `json:"url"`
+	// Suggested file name, with the .llx extension
+	FileName  string `json:"fileName"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// A scene, fixture, cue list, cue or scene board of a project was created,
+// updated or deleted. The field of the entity's type holds it as it is now;
+// all are null for a deletion.
+type ProjectEntityChange struct {
+	ProjectID  string                  `json:"projectId"`
+	EntityType ProjectEntityType       `json:"entityType"`
+	Action     EntityChangeAction      `json:"action"`
+	EntityID   string                  `json:"entityId"`
+	Scene      *models.Scene           `json:"scene,omitempty"`
+	Fixture    *models.FixtureInstance `json:"fixture,omitempty"`
+	CueList    *models.CueList         `json:"cueList,omitempty"`
+	Cue        *models.Cue             `json:"cue,omitempty"`
+	// Also updated when its buttons change
+	SceneBoard *models.SceneBoard `json:"sceneBoard,omitempty"`
+}
+
+// A record with a broken reference
+type ProjectIssue struct {
+	Type ProjectIssueType `json:"type"`
+	// The record with the broken reference
+	EntityID string `json:"entityId"`
+	// Names the record, or what it belongs to
+	EntityName string `json:"entityName"`
+	// The record referenced
+	MissingID string `json:"missingId"`
+	Message   string `json:"message"`
+}
+
+type ProjectPresence struct {
+	ProjectID string      `json:"projectId"`
+	Sessions  []*Presence `json:"sessions"`
+}
+
+type ProjectRepairResult struct {
+	// The issues repaired
+	Repaired []*ProjectIssue `json:"repaired"`
+	// The issues left, of types not selected
+	Remaining []*ProjectIssue `json:"remaining"`
+}
+
 type ProjectUpdateItem struct {
-	ProjectID   string                     `json:"projectId"`
-	Name        graphql.Omittable[*string] `json:"name,omitempty"`
-	Description graphql.Omittable[*string] `json:"description,omitempty"`
+	ProjectID      string                      `json:"projectId"`
+	Name           graphql.Omittable[*string]  `json:"name,omitempty"`
+	Description    graphql.Omittable[*string]  `json:"description,omitempty"`
+	DefaultFadeIn  graphql.Omittable[*float64] `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut graphql.Omittable[*float64] `json:"defaultFadeOut,omitempty"`
+}
+
+type ProjectValidation struct {
+	ProjectID string `json:"projectId"`
+	// Whether the project has no broken references
+	Valid  bool            `json:"valid"`
+	Issues []*ProjectIssue `json:"issues"`
 }
 
 type QLCExportResult struct {
@@ -641,6 +1847,42 @@ type QLCImportResult struct {
 type Query struct {
 }
 
+type ReplaceChannelValueResult struct {
+	DryRun bool `json:"dryRun"`
+	// Total channel values changed (or that would change in a dry run)
+	TotalChanges int `json:"totalChanges"`
+	// Per-scene change counts, only for scenes with at least one change
+	Scenes []*SceneChannelValueChanges `json:"scenes"`
+}
+
+// Tracking backup state. A backup applies the primary's live state with its
+// Art-Net output passive, and takes over output when the primary's heartbeats
+// stop. It keeps output until failed back.
+type ReplicationStatus struct {
+	Role       ReplicationRole `json:"role"`
+	InstanceID string          `json:"instanceId"`
+	// Whether this instance is sending Art-Net output
+	OutputActive bool `json:"outputActive"`
+	// Whether the other instance is connected and sending heartbeats
+	PeerConnected  bool    `json:"peerConnected"`
+	PeerInstanceID *string `json:"peerInstanceId,omitempty"`
+	// When the last heartbeat from the other instance arrived
+	LastHeartbeatAt *string `json:"lastHeartbeatAt,omitempty"`
+	// When this backup took over output (null while it tracks)
+	TakenOverAt *string `json:"takenOverAt,omitempty"`
+	// State entries published by a primary, or tracked by a backup
+	StateEntries int `json:"stateEntries"`
+}
+
+// A one-time link to a printable report
+type ReportDownload struct {
+	// Path to GET the report from, as printable HTML; the link works once
+	URL string `json:"url"`
+	// Suggested file name
+	FileName  string `json:"fileName"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
 type RepositoryVersion struct {
 	Repository      string `json:"repository"`
 	Installed       string `json:"installed"`
@@ -648,6 +1890,35 @@ type RepositoryVersion struct {
 	UpdateAvailable bool   `json:"updateAvailable"`
 }
 
+// Press-and-hold status of a scene board button
+type SceneBoardButtonHoldState struct {
+	ButtonID string `json:"buttonId"`
+	SceneID  string `json:"sceneId"`
+	// Scene level reached along the hold curve (0-1)
+	Level       float64 `json:"level"`
+	IsHeld      bool    `json:"isHeld"`
+	IsLatched   bool    `json:"isLatched"`
+	HeldSeconds float64 `json:"heldSeconds"`
+}
+
+// Live state of a scene board button
+type SceneBoardButtonLiveState struct {
+	ButtonID string `json:"buttonId"`
+	SceneID  string `json:"sceneId"`
+	// The button's scene is live: the board's last activated scene, held, latched, or flashing
+	IsActive bool `json:"isActive"`
+	// Scene level (0-1): the hold, fader, or flash level, or 1 for an activated scene
+	Level     float64 `json:"level"`
+	IsHeld    bool    `json:"isHeld"`
+	IsLatched bool    `json:"isLatched"`
+	// The button is flashing its scene, or fading the flash out
+	IsFlashing bool `json:"isFlashing"`
+	// The button's macro is running
+	IsMacroRunning bool `json:"isMacroRunning"`
+	// Progress of the button's running fade, 0-100; null when not fading
+	FadeProgress *float64 `json:"fadeProgress,omitempty"`
+}
+
 type SceneBoardButtonPositionInput struct {
 	ButtonID string `json:"buttonId"`
 	LayoutX  int    `json:"layoutX"`
@@ -655,13 +1926,47 @@ type SceneBoardButtonPositionInput struct {
 }
 
 type SceneBoardButtonUpdateItem struct {
-	ButtonID string                     `json:"buttonId"`
-	LayoutX  graphql.Omittable[*int]    `json:"layoutX,omitempty"`
-	LayoutY  graphql.Omittable[*int]    `json:"layoutY,omitempty"`
-	Width    graphql.Omittable[*int]    `json:"width,omitempty"`
-	Height   graphql.Omittable[*int]    `json:"height,omitempty"`
-	Color    graphql.Omittable[*string] `json:"color,omitempty"`
-	Label    graphql.Omittable[*string] `json:"label,omitempty"`
+	ButtonID    string                      `json:"buttonId"`
+	LayoutX     graphql.Omittable[*int]     `json:"layoutX,omitempty"`
+	LayoutY     graphql.Omittable[*int]     `json:"layoutY,omitempty"`
+	Width       graphql.Omittable[*int]     `json:"width,omitempty"`
+	Height      graphql.Omittable[*int]     `json:"height,omitempty"`
+	Color       graphql.Omittable[*string]  `json:"color,omitempty"`
+	Label       graphql.Omittable[*string]  `json:"label,omitempty"`
+	FadeInTime  graphql.Omittable[*float64] `json:"fadeInTime,omitempty"`
+	FadeOutTime graphql.Omittable[*float64] `json:"fadeOutTime,omitempty"`
+	FlashMode   graphql.Omittable[*bool]    `json:"flashMode,omitempty"`
+	// 0-1
+	FlashLevel       graphql.Omittable[*float64] `json:"flashLevel,omitempty"`
+	FlashReleaseTime graphql.Omittable[*float64] `json:"flashReleaseTime,omitempty"`
+	AudioTrigger     graphql.Omittable[*bool]    `json:"audioTrigger,omitempty"`
+	// Null for the first configured band
+	AudioBand   graphql.Omittable[*string]             `json:"audioBand,omitempty"`
+	AudioAction graphql.Omittable[*AudioTriggerAction] `json:"audioAction,omitempty"`
+}
+
+// Flash status of a scene board button
+type SceneBoardFlashState struct {
+	ButtonID string `json:"buttonId"`
+	SceneID  string `json:"sceneId"`
+	// Current flash level (0-1)
+	Level float64 `json:"level"`
+	// The button is pressed
+	IsFlashing bool `json:"isFlashing"`
+	// The flash is fading out after release
+	IsReleasing bool `json:"isReleasing"`
+}
+
+// Runtime state of a scene board, for mirroring the console on a touch panel
+type SceneBoardLiveState struct {
+	SceneBoardID string `json:"sceneBoardId"`
+	// The scene last activated on the board, while it is live
+	ActiveSceneID *string `json:"activeSceneId,omitempty"`
+	// Grand master level (0-1) scaling the board's output
+	MasterLevel float64 `json:"masterLevel"`
+	// Buttons in layout order
+	Buttons   []*SceneBoardButtonLiveState `json:"buttons"`
+	UpdatedAt string                       `json:"updatedAt"`
 }
 
 type SceneBoardUpdateItem struct {
@@ -674,6 +1979,24 @@ type SceneBoardUpdateItem struct {
 	CanvasHeight    graphql.Omittable[*int]     `json:"canvasHeight,omitempty"`
 }
 
+type SceneChannelValueChanges struct {
+	SceneID     string `json:"sceneId"`
+	SceneName   string `json:"sceneName"`
+	ChangeCount int    `json:"changeCount"`
+}
+
+// A scene's look in brief, computed from its fixture values and cached until
+// the scene, its fixtures, or the project's groups change
+type SceneColorSummary struct {
+	SceneID string `json:"sceneId"`
+	// Mean brightness (0-1) of the fixtures whose light the scene sets
+	Intensity float64 `json:"intensity"`
+	// Up to three dominant colors as #RRGGBB, the most light first; empty for a dark scene
+	DominantColors []string `json:"dominantColors"`
+	// Each fixture group with fixtures in the scene, by name, then the fixtures in no group
+	Groups []*SceneGroupColor `json:"groups"`
+}
+
 type SceneComparison struct {
 	Scene1                SceneSummary       `json:"scene1"`
 	Scene2                SceneSummary       `json:"scene2"`
@@ -682,6 +2005,13 @@ type SceneComparison struct {
 	DifferentFixtureCount int                `json:"differentFixtureCount"`
 }
 
+// A scene copied into another project
+type SceneCopyResult struct {
+	Scene models.Scene `json:"scene"`
+	// Fixtures of the original scene with no match in the target project, whose values were left out
+	Warnings []string `json:"warnings"`
+}
+
 type SceneDifference struct {
 	FixtureID      string         `json:"fixtureId"`
 	FixtureName    string         `json:"fixtureName"`
@@ -701,24 +2031,42 @@ type SceneFixtureSummary struct {
 	FixtureType FixtureType `json:"fixtureType"`
 }
 
+// The look of a fixture group's fixtures in a scene
+type SceneGroupColor struct {
+	// Null for the fixtures in no group
+	GroupID *string `json:"groupId,omitempty"`
+	Name    *string `json:"name,omitempty"`
+	// Mean color of the lit fixtures as #RRGGBB; null when none is lit
+	Color *string `json:"color,omitempty"`
+	// Mean brightness (0-1) of the fixtures
+	Intensity    float64 `json:"intensity"`
+	FixtureCount int     `json:"fixtureCount"`
+}
+
 type ScenePage struct {
 	Scenes     []*SceneSummary `json:"scenes"`
 	Pagination PaginationInfo  `json:"pagination"`
 }
 
 type SceneSummary struct {
-	ID           string  `json:"id"`
-	Name         string  `json:"name"`
-	Description  *string `json:"description,omitempty"`
-	FixtureCount int     `json:"fixtureCount"`
-	CreatedAt    string  `json:"createdAt"`
-	UpdatedAt    string  `json:"updatedAt"`
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	SecondaryLabel *string `json:"secondaryLabel,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	FixtureCount   int     `json:"fixtureCount"`
+	// The scene's look in brief, as on Scene
+	ColorSummary SceneColorSummary `json:"colorSummary"`
+	CreatedAt    string            `json:"createdAt"`
+	UpdatedAt    string            `json:"updatedAt"`
 }
 
 type SceneUpdateItem struct {
-	SceneID     string                     `json:"sceneId"`
-	Name        graphql.Omittable[*string] `json:"name,omitempty"`
-	Description graphql.Omittable[*string] `json:"description,omitempty"`
+	SceneID        string                      `json:"sceneId"`
+	Name           graphql.Omittable[*string]  `json:"name,omitempty"`
+	SecondaryLabel graphql.Omittable[*string]  `json:"secondaryLabel,omitempty"`
+	Description    graphql.Omittable[*string]  `json:"description,omitempty"`
+	DefaultFadeIn  graphql.Omittable[*float64] `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut graphql.Omittable[*float64] `json:"defaultFadeOut,omitempty"`
 }
 
 type SceneUsage struct {
@@ -727,13 +2075,115 @@ type SceneUsage struct {
 	Cues      []*CueUsageSummary `json:"cues"`
 }
 
+// The same channel values for each of a list of fixtures
+type SceneValueSetInput struct {
+	FixtureIds []string             `json:"fixtureIds"`
+	Channels   []*ChannelValueInput `json:"channels"`
+}
+
+// A schedule fired, on its schedule or from fireSchedule
+type ScheduleFiredEvent struct {
+	ScheduleID   string         `json:"scheduleId"`
+	ScheduleName string         `json:"scheduleName"`
+	ProjectID    string         `json:"projectId"`
+	Action       ScheduleAction `json:"action"`
+	FiredAt      string         `json:"firedAt"`
+	// Why the action failed; null when it succeeded
+	Error *string `json:"error,omitempty"`
+}
+
+// Server-side stage-management timer (house open countdown, intermission, show clock)
+type ShowTimer struct {
+	ID   string        `json:"id"`
+	Name string        `json:"name"`
+	Kind ShowTimerKind `json:"kind"`
+	// Countdown length in seconds (0 for stopwatches)
+	DurationSeconds float64 `json:"durationSeconds"`
+	ElapsedSeconds  float64 `json:"elapsedSeconds"`
+	// Seconds left on a countdown (0 for stopwatches)
+	RemainingSeconds float64 `json:"remainingSeconds"`
+	IsRunning        bool    `json:"isRunning"`
+	// True once a countdown has reached zero; reset to run it again
+	HasExpired bool `json:"hasExpired"`
+	// Cue list started when the countdown reaches zero
+	TriggerCueListID *string `json:"triggerCueListId,omitempty"`
+	// Cue number to start from when triggered (first cue if null)
+	TriggerCueNumber *float64 `json:"triggerCueNumber,omitempty"`
+	UpdatedAt        string   `json:"updatedAt"`
+}
+
+// Patch a logical channel, replacing any entry the project has for it
+type SoftPatchInput struct {
+	ProjectID string `json:"projectId"`
+	Universe  int    `json:"universe"`
+	// 1-512
+	Channel int `json:"channel"`
+	// Output addresses; empty parks the channel
+	Targets []*DmxAddressInput `json:"targets"`
+}
+
+type StandbyConfig struct {
+	// Enter standby at closing time and wake at opening time
+	ScheduleEnabled bool            `json:"scheduleEnabled"`
+	OpeningHours    []*OpeningHours `json:"openingHours"`
+	// Wake when Art-Net DMX arrives from another console during standby
+	WakeOnArtNet bool `json:"wakeOnArtNet"`
+	// What DMX output does during standby
+	Output StandbyOutput `json:"output"`
+}
+
+type StandbyConfigInput struct {
+	ScheduleEnabled bool                 `json:"scheduleEnabled"`
+	OpeningHours    []*OpeningHoursInput `json:"openingHours"`
+	WakeOnArtNet    bool                 `json:"wakeOnArtNet"`
+	// Defaults to ZERO
+	Output graphql.Omittable[*StandbyOutput] `json:"output,omitempty"`
+}
+
+// Low-power standby: fades and cue list follows stop, and DMX output blacks out
+// or holds the last look, until the server wakes
+type StandbyStatus struct {
+	IsStandby bool `json:"isStandby"`
+	// Why standby was entered (null when awake)
+	Reason *StandbyTrigger `json:"reason,omitempty"`
+	// When standby was entered (null when awake)
+	Since *string `json:"since,omitempty"`
+	// What DMX output is doing (null when awake)
+	Output         *StandbyOutput  `json:"output,omitempty"`
+	LastWakeReason *StandbyTrigger `json:"lastWakeReason,omitempty"`
+	LastWakeAt     *string         `json:"lastWakeAt,omitempty"`
+	Config         StandbyConfig   `json:"config"`
+	// Next opening or closing time while the schedule is enabled
+	NextScheduledChange *string `json:"nextScheduledChange,omitempty"`
+}
+
+// A page of submaster faders, in slot order
+type SubmasterPage struct {
+	Page       int                 `json:"page"`
+	Submasters []*models.Submaster `json:"submasters"`
+}
+
 type Subscription struct {
 }
 
+// Options for importing from a remote fixture library
+type SyncFixtureLibraryInput struct {
+	// Another LacyLights server (e.g. http://host:4000) or a published library URL
+	URL string `json:"url"`
+	// Definitions to import (null = all NEW and REMOTE_NEWER definitions)
+	Definitions graphql.Omittable[[]*FixtureLibraryDefinitionKeyInput] `json:"definitions,omitempty"`
+	// Names of template projects to import as new projects
+	Templates graphql.Omittable[[]string] `json:"templates,omitempty"`
+	// Also replace selected definitions whose local copy changed more recently
+	OverwriteLocalChanges graphql.Omittable[*bool] `json:"overwriteLocalChanges,omitempty"`
+}
+
 type SystemInfo struct {
 	ArtnetBroadcastAddress string `json:"artnetBroadcastAddress"`
 	ArtnetEnabled          bool   `json:"artnetEnabled"`
 	FadeUpdateRateHz       int    `json:"fadeUpdateRateHz"`
+	// Hash of the schema SDL served at /graphql/schema.graphql; changes whenever the schema does
+	SchemaVersion string `json:"schemaVersion"`
 }
 
 type SystemVersionInfo struct {
@@ -742,6 +2192,93 @@ type SystemVersionInfo struct {
 	VersionManagementSupported bool                 `json:"versionManagementSupported"`
 }
 
+// Server-wide musical tempo clock used for beat-synced playback
+type TempoState struct {
+	Bpm         float64 `json:"bpm"`
+	BeatsPerBar int     `json:"beatsPerBar"`
+	// A downbeat; later beats fall every 60/bpm seconds, so clients can animate the beat locally
+	DownbeatAt string `json:"downbeatAt"`
+	// Current beat within the bar (1-based) when this state was produced
+	Beat int `json:"beat"`
+	// Bars since downbeatAt (1-based) when this state was produced
+	Bar int `json:"bar"`
+	// Taps in the current tap tempo sequence
+	TapCount int `json:"tapCount"`
+	// Server time this state was produced, for clock offset estimation
+	ServerTime string `json:"serverTime"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+type TimecodeConfigInput struct {
+	Enabled   bool              `json:"enabled"`
+	Source    TimecodeSource    `json:"source"`
+	FrameRate TimecodeFrameRate `json:"frameRate"`
+	// Required for MTC
+	MidiDevice         graphql.Omittable[*string] `json:"midiDevice,omitempty"`
+	CueListID          graphql.Omittable[*string] `json:"cueListId,omitempty"`
+	ChaseToTime        graphql.Omittable[*bool]   `json:"chaseToTime,omitempty"`
+	FallbackToInternal graphql.Omittable[*bool]   `json:"fallbackToInternal,omitempty"`
+}
+
+// Timecode that fires the cues of a cue list at their timecode positions.
+type TimecodeStatus struct {
+	Enabled   bool              `json:"enabled"`
+	Source    TimecodeSource    `json:"source"`
+	FrameRate TimecodeFrameRate `json:"frameRate"`
+	// Raw MIDI device MTC is read from, e.g. /dev/snd/midiC1D0
+	MidiDevice *string `json:"midiDevice,omitempty"`
+	// Cue list whose cue timecodes fire
+	CueListID *string `json:"cueListId,omitempty"`
+	// Jumps in timecode go straight to the cue live at the new position, fading for whatever time it has left
+	ChaseToTime bool `json:"chaseToTime"`
+	// When MTC stops, keep running on the internal clock from the last position
+	FallbackToInternal bool `json:"fallbackToInternal"`
+	// Current position as HH:MM:SS:FF (null until MTC has been received)
+	Position  *string `json:"position,omitempty"`
+	IsRunning bool    `json:"isRunning"`
+	// True while MTC has stopped and the internal clock stands in
+	IsFreewheeling bool    `json:"isFreewheeling"`
+	LastMtcAt      *string `json:"lastMtcAt,omitempty"`
+	// Why the MIDI device cannot be read, if it cannot
+	DeviceError *string `json:"deviceError,omitempty"`
+}
+
+// A fixture channel value in effect at a cue
+type TrackedChannelValue struct {
+	FixtureID string `json:"fixtureId"`
+	Offset    int    `json:"offset"`
+	Value     int    `json:"value"`
+	// The cue whose scene sets the value
+	SourceCueID string `json:"sourceCueId"`
+	// True when the value tracks in from an earlier cue
+	Tracked bool `json:"tracked"`
+}
+
+// A value for each of a fixture's channels of a type
+type TypedChannelValue struct {
+	Type  ChannelType `json:"type"`
+	Value int         `json:"value"`
+}
+
+type TypedChannelValueInput struct {
+	Type  ChannelType `json:"type"`
+	Value int         `json:"value"`
+}
+
+// A project's undo history. Scene edits, fixture patching, and cue changes can
+// be undone; a new edit clears the redo stack.
+type UndoStackStatus struct {
+	ProjectID string `json:"projectId"`
+	CanUndo   bool   `json:"canUndo"`
+	CanRedo   bool   `json:"canRedo"`
+	// The edit the next undo reverts
+	UndoDescription *string `json:"undoDescription,omitempty"`
+	// The edit the next redo reapplies
+	RedoDescription *string `json:"redoDescription,omitempty"`
+	UndoCount       int     `json:"undoCount"`
+	RedoCount       int     `json:"redoCount"`
+}
+
 type UniverseChannelMap struct {
 	Universe          int                  `json:"universe"`
 	Fixtures          []*ChannelMapFixture `json:"fixtures"`
@@ -750,11 +2287,46 @@ type UniverseChannelMap struct {
 	UsedChannels      int                  `json:"usedChannels"`
 }
 
+// A universe master below full
+type UniverseMaster struct {
+	Universe int `json:"universe"`
+	// Fraction of full, 0 to 1
+	Level float64 `json:"level"`
+}
+
 type UniverseOutput struct {
 	Universe int   `json:"universe"`
 	Channels []int `json:"channels"`
 }
 
+type UpdateEffectInput struct {
+	Name       graphql.Omittable[*string]     `json:"name,omitempty"`
+	Type       graphql.Omittable[*EffectType] `json:"type,omitempty"`
+	FixtureIds graphql.Omittable[[]string]    `json:"fixtureIds,omitempty"`
+	// Take the fixtures of a selection set, in its order, instead of fixtureIds
+	SelectionSetID graphql.Omittable[*string] `json:"selectionSetId,omitempty"`
+	// Reorder the new fixtures, or else the saved ones
+	SelectionOrder graphql.Omittable[*SelectionOrder] `json:"selectionOrder,omitempty"`
+	// Set to null to make the effect standalone
+	SceneID     graphql.Omittable[*string]      `json:"sceneId,omitempty"`
+	Rate        graphql.Omittable[*float64]     `json:"rate,omitempty"`
+	Size        graphql.Omittable[*float64]     `json:"size,omitempty"`
+	PhaseOffset graphql.Omittable[*float64]     `json:"phaseOffset,omitempty"`
+	Order       graphql.Omittable[*EffectOrder] `json:"order,omitempty"`
+	Low         graphql.Omittable[*float64]     `json:"low,omitempty"`
+	High        graphql.Omittable[*float64]     `json:"high,omitempty"`
+}
+
+type UpdateFixtureGroupInput struct {
+	Name        graphql.Omittable[*string]  `json:"name,omitempty"`
+	Description graphql.Omittable[*string]  `json:"description,omitempty"`
+	FixtureIds  graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Take the fixtures of a selection set, in its order, instead of fixtureIds
+	SelectionSetID graphql.Omittable[*string] `json:"selectionSetId,omitempty"`
+	// Reorder the new fixtures, or else the saved ones
+	SelectionOrder graphql.Omittable[*SelectionOrder] `json:"selectionOrder,omitempty"`
+}
+
 type UpdateFixtureInstanceInput struct {
 	Name           graphql.Omittable[*string]  `json:"name,omitempty"`
 	Description    graphql.Omittable[*string]  `json:"description,omitempty"`
@@ -767,6 +2339,41 @@ type UpdateFixtureInstanceInput struct {
 	LayoutX        graphql.Omittable[*float64] `json:"layoutX,omitempty"`
 	LayoutY        graphql.Omittable[*float64] `json:"layoutY,omitempty"`
 	LayoutRotation graphql.Omittable[*float64] `json:"layoutRotation,omitempty"`
+	// Intensity ceiling (0-1) enforced at the DMX output; null removes the cap
+	MaxIntensity graphql.Omittable[*float64] `json:"maxIntensity,omitempty"`
+}
+
+type UpdateHouseLightsInput struct {
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	GroupIds   graphql.Omittable[[]string] `json:"groupIds,omitempty"`
+	FullLevel  graphql.Omittable[*float64] `json:"fullLevel,omitempty"`
+	HalfLevel  graphql.Omittable[*float64] `json:"halfLevel,omitempty"`
+	ShowLevel  graphql.Omittable[*float64] `json:"showLevel,omitempty"`
+	FadeTime   graphql.Omittable[*float64] `json:"fadeTime,omitempty"`
+}
+
+// Fields left out are unchanged; a null color clears it
+type UpdateLayoutZoneInput struct {
+	Name   graphql.Omittable[*string]  `json:"name,omitempty"`
+	X      graphql.Omittable[*float64] `json:"x,omitempty"`
+	Y      graphql.Omittable[*float64] `json:"y,omitempty"`
+	Width  graphql.Omittable[*float64] `json:"width,omitempty"`
+	Height graphql.Omittable[*float64] `json:"height,omitempty"`
+	Color  graphql.Omittable[*string]  `json:"color,omitempty"`
+}
+
+type UpdatePaletteInput struct {
+	Name     graphql.Omittable[*string]                   `json:"name,omitempty"`
+	Channels graphql.Omittable[[]*TypedChannelValueInput] `json:"channels,omitempty"`
+}
+
+// Fields left out are unchanged
+type UpdatePixelMatrixInput struct {
+	Name   graphql.Omittable[*string] `json:"name,omitempty"`
+	Width  graphql.Omittable[*int]    `json:"width,omitempty"`
+	Height graphql.Omittable[*int]    `json:"height,omitempty"`
+	// Replaces every pixel
+	Pixels graphql.Omittable[[]*PixelMatrixPixelInput] `json:"pixels,omitempty"`
 }
 
 type UpdateResult struct {
@@ -779,27 +2386,69 @@ type UpdateResult struct {
 }
 
 type UpdateSceneBoardButtonInput struct {
-	LayoutX graphql.Omittable[*int]    `json:"layoutX,omitempty"`
-	LayoutY graphql.Omittable[*int]    `json:"layoutY,omitempty"`
-	Width   graphql.Omittable[*int]    `json:"width,omitempty"`
-	Height  graphql.Omittable[*int]    `json:"height,omitempty"`
-	Color   graphql.Omittable[*string] `json:"color,omitempty"`
-	Label   graphql.Omittable[*string] `json:"label,omitempty"`
+	LayoutX     graphql.Omittable[*int]     `json:"layoutX,omitempty"`
+	LayoutY     graphql.Omittable[*int]     `json:"layoutY,omitempty"`
+	Width       graphql.Omittable[*int]     `json:"width,omitempty"`
+	Height      graphql.Omittable[*int]     `json:"height,omitempty"`
+	Color       graphql.Omittable[*string]  `json:"color,omitempty"`
+	Label       graphql.Omittable[*string]  `json:"label,omitempty"`
+	FadeInTime  graphql.Omittable[*float64] `json:"fadeInTime,omitempty"`
+	FadeOutTime graphql.Omittable[*float64] `json:"fadeOutTime,omitempty"`
+	FlashMode   graphql.Omittable[*bool]    `json:"flashMode,omitempty"`
+	// 0-1
+	FlashLevel       graphql.Omittable[*float64] `json:"flashLevel,omitempty"`
+	FlashReleaseTime graphql.Omittable[*float64] `json:"flashReleaseTime,omitempty"`
+	AudioTrigger     graphql.Omittable[*bool]    `json:"audioTrigger,omitempty"`
+	// Null for the first configured band
+	AudioBand   graphql.Omittable[*string]             `json:"audioBand,omitempty"`
+	AudioAction graphql.Omittable[*AudioTriggerAction] `json:"audioAction,omitempty"`
 }
 
 type UpdateSceneBoardInput struct {
-	Name            graphql.Omittable[*string]  `json:"name,omitempty"`
-	Description     graphql.Omittable[*string]  `json:"description,omitempty"`
-	DefaultFadeTime graphql.Omittable[*float64] `json:"defaultFadeTime,omitempty"`
-	GridSize        graphql.Omittable[*int]     `json:"gridSize,omitempty"`
-	CanvasWidth     graphql.Omittable[*int]     `json:"canvasWidth,omitempty"`
-	CanvasHeight    graphql.Omittable[*int]     `json:"canvasHeight,omitempty"`
+	Name            graphql.Omittable[*string]          `json:"name,omitempty"`
+	Description     graphql.Omittable[*string]          `json:"description,omitempty"`
+	DefaultFadeTime graphql.Omittable[*float64]         `json:"defaultFadeTime,omitempty"`
+	GridSize        graphql.Omittable[*int]             `json:"gridSize,omitempty"`
+	CanvasWidth     graphql.Omittable[*int]             `json:"canvasWidth,omitempty"`
+	CanvasHeight    graphql.Omittable[*int]             `json:"canvasHeight,omitempty"`
+	HoldRampTime    graphql.Omittable[*float64]         `json:"holdRampTime,omitempty"`
+	HoldCurve       graphql.Omittable[*EasingType]      `json:"holdCurve,omitempty"`
+	HoldReleaseMode graphql.Omittable[*HoldReleaseMode] `json:"holdReleaseMode,omitempty"`
 }
 
 type UpdateSceneInput struct {
-	Name          graphql.Omittable[*string]              `json:"name,omitempty"`
-	Description   graphql.Omittable[*string]              `json:"description,omitempty"`
-	FixtureValues graphql.Omittable[[]*FixtureValueInput] `json:"fixtureValues,omitempty"`
+	Name           graphql.Omittable[*string]              `json:"name,omitempty"`
+	SecondaryLabel graphql.Omittable[*string]              `json:"secondaryLabel,omitempty"`
+	Description    graphql.Omittable[*string]              `json:"description,omitempty"`
+	FixtureValues  graphql.Omittable[[]*FixtureValueInput] `json:"fixtureValues,omitempty"`
+	// Replaces the scene's group values
+	GroupValues    graphql.Omittable[[]*GroupValueInput] `json:"groupValues,omitempty"`
+	DefaultFadeIn  graphql.Omittable[*float64]           `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut graphql.Omittable[*float64]           `json:"defaultFadeOut,omitempty"`
+}
+
+type UpdateScheduleInput struct {
+	Name          graphql.Omittable[*string]          `json:"name,omitempty"`
+	Enabled       graphql.Omittable[*bool]            `json:"enabled,omitempty"`
+	Trigger       graphql.Omittable[*ScheduleTrigger] `json:"trigger,omitempty"`
+	Cron          graphql.Omittable[*string]          `json:"cron,omitempty"`
+	Latitude      graphql.Omittable[*float64]         `json:"latitude,omitempty"`
+	Longitude     graphql.Omittable[*float64]         `json:"longitude,omitempty"`
+	OffsetMinutes graphql.Omittable[*int]             `json:"offsetMinutes,omitempty"`
+	Days          graphql.Omittable[[]DayOfWeek]      `json:"days,omitempty"`
+	Action        graphql.Omittable[*ScheduleAction]  `json:"action,omitempty"`
+	SceneID       graphql.Omittable[*string]          `json:"sceneId,omitempty"`
+	CueListID     graphql.Omittable[*string]          `json:"cueListId,omitempty"`
+	// Set to null to use the usual fade time
+	FadeTime graphql.Omittable[*float64] `json:"fadeTime,omitempty"`
+}
+
+type UpdateSelectionSetInput struct {
+	Name        graphql.Omittable[*string]  `json:"name,omitempty"`
+	Description graphql.Omittable[*string]  `json:"description,omitempty"`
+	FixtureIds  graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+	// Reorder the new fixtures, or else the saved ones
+	Order graphql.Omittable[*SelectionOrder] `json:"order,omitempty"`
 }
 
 type UpdateSettingInput struct {
@@ -807,6 +2456,23 @@ type UpdateSettingInput struct {
 	Value string `json:"value"`
 }
 
+type UpdateSubmasterInput struct {
+	Name graphql.Omittable[*string] `json:"name,omitempty"`
+	Page graphql.Omittable[*int]    `json:"page,omitempty"`
+	Slot graphql.Omittable[*int]    `json:"slot,omitempty"`
+	// Set to null to remove the scene
+	SceneID    graphql.Omittable[*string]  `json:"sceneId,omitempty"`
+	FixtureIds graphql.Omittable[[]string] `json:"fixtureIds,omitempty"`
+}
+
+// Fields left out are unchanged; a null label or destination clears it
+type UpdateUniverseInput struct {
+	Label       graphql.Omittable[*string]           `json:"label,omitempty"`
+	Protocol    graphql.Omittable[*UniverseProtocol] `json:"protocol,omitempty"`
+	Destination graphql.Omittable[*string]           `json:"destination,omitempty"`
+	Enabled     graphql.Omittable[*bool]             `json:"enabled,omitempty"`
+}
+
 type WiFiConnectionResult struct {
 	Success   bool    `json:"success"`
 	Message   *string `json:"message,omitempty"`
@@ -842,56 +2508,390 @@ type WiFiStatus struct {
 	ConnectedClients []*APClient `json:"connectedClients,omitempty"`
 }
 
-type ChannelType string
+type AudioSource string
 
 const (
-	ChannelTypeIntensity  ChannelType = "INTENSITY"
-	ChannelTypeRed        ChannelType = "RED"
-	ChannelTypeGreen      ChannelType = "GREEN"
-	ChannelTypeBlue       ChannelType = "BLUE"
-	ChannelTypeWhite      ChannelType = "WHITE"
-	ChannelTypeAmber      ChannelType = "AMBER"
-	ChannelTypeUv         ChannelType = "UV"
-	ChannelTypeCyan       ChannelType = "CYAN"
-	ChannelTypeMagenta    ChannelType = "MAGENTA"
-	ChannelTypeYellow     ChannelType = "YELLOW"
-	ChannelTypeLime       ChannelType = "LIME"
-	ChannelTypeIndigo     ChannelType = "INDIGO"
-	ChannelTypeColdWhite  ChannelType = "COLD_WHITE"
-	ChannelTypeWarmWhite  ChannelType = "WARM_WHITE"
-	ChannelTypePan        ChannelType = "PAN"
-	ChannelTypeTilt       ChannelType = "TILT"
-	ChannelTypeZoom       ChannelType = "ZOOM"
-	ChannelTypeFocus      ChannelType = "FOCUS"
-	ChannelTypeIris       ChannelType = "IRIS"
-	ChannelTypeGobo       ChannelType = "GOBO"
-	ChannelTypeColorWheel ChannelType = "COLOR_WHEEL"
-	ChannelTypeEffect     ChannelType = "EFFECT"
-	ChannelTypeStrobe     ChannelType = "STROBE"
-	ChannelTypeMacro      ChannelType = "MACRO"
-	ChannelTypeOther      ChannelType = "OTHER"
+	// Sound device captured with arecord
+	AudioSourceDevice AudioSource = "DEVICE"
+	// RTP stream of L16 audio
+	AudioSourceRtp AudioSource = "RTP"
+	// Bare UDP datagrams of 16-bit little-endian PCM
+	AudioSourceUDP AudioSource = "UDP"
 )
 
-var AllChannelType = []ChannelType{
-	ChannelTypeIntensity,
-	ChannelTypeRed,
-	ChannelTypeGreen,
-	ChannelTypeBlue,
-	ChannelTypeWhite,
-	ChannelTypeAmber,
-	ChannelTypeUv,
-	ChannelTypeCyan,
-	ChannelTypeMagenta,
-	ChannelTypeYellow,
-	ChannelTypeLime,
-	ChannelTypeIndigo,
-	ChannelTypeColdWhite,
-	ChannelTypeWarmWhite,
-	ChannelTypePan,
-	ChannelTypeTilt,
-	ChannelTypeZoom,
-	ChannelTypeFocus,
-	ChannelTypeIris,
+var AllAudioSource = []AudioSource{
+	AudioSourceDevice,
+	AudioSourceRtp,
+	AudioSourceUDP,
+}
+
+func (e AudioSource) IsValid() bool {
+	switch e {
+	case AudioSourceDevice, AudioSourceRtp, AudioSourceUDP:
+		return true
+	}
+	return false
+}
+
+func (e AudioSource) String() string {
+	return string(e)
+}
+
+func (e *AudioSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AudioSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AudioSource", str)
+	}
+	return nil
+}
+
+func (e AudioSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AudioSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AudioSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What a beat does to a scene board button triggered by audio
+type AudioTriggerAction string
+
+const (
+	// Flash the button's scene at its flashLevel, fading out over its flashReleaseTime (a quarter second when unset)
+	AudioTriggerActionBump AudioTriggerAction = "BUMP"
+	// Step the effects attached to the button's scene one fixture along
+	AudioTriggerActionAdvanceEffects AudioTriggerAction = "ADVANCE_EFFECTS"
+)
+
+var AllAudioTriggerAction = []AudioTriggerAction{
+	AudioTriggerActionBump,
+	AudioTriggerActionAdvanceEffects,
+}
+
+func (e AudioTriggerAction) IsValid() bool {
+	switch e {
+	case AudioTriggerActionBump, AudioTriggerActionAdvanceEffects:
+		return true
+	}
+	return false
+}
+
+func (e AudioTriggerAction) String() string {
+	return string(e)
+}
+
+func (e *AudioTriggerAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AudioTriggerAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AudioTriggerAction", str)
+	}
+	return nil
+}
+
+func (e AudioTriggerAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AudioTriggerAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AudioTriggerAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What an audited mutation changed
+type AuditEntityType string
+
+const (
+	AuditEntityTypeProject           AuditEntityType = "PROJECT"
+	AuditEntityTypeFixtureDefinition AuditEntityType = "FIXTURE_DEFINITION"
+	AuditEntityTypeFixture           AuditEntityType = "FIXTURE"
+	AuditEntityTypeFixtureGroup      AuditEntityType = "FIXTURE_GROUP"
+	AuditEntityTypeScene             AuditEntityType = "SCENE"
+	AuditEntityTypeSceneBoard        AuditEntityType = "SCENE_BOARD"
+	AuditEntityTypeSceneBoardButton  AuditEntityType = "SCENE_BOARD_BUTTON"
+	AuditEntityTypeCueList           AuditEntityType = "CUE_LIST"
+	AuditEntityTypeCue               AuditEntityType = "CUE"
+	AuditEntityTypeEffect            AuditEntityType = "EFFECT"
+	AuditEntityTypeSubmaster         AuditEntityType = "SUBMASTER"
+	AuditEntityTypePalette           AuditEntityType = "PALETTE"
+	AuditEntityTypeSnapshot          AuditEntityType = "SNAPSHOT"
+	AuditEntityTypeShowTimer         AuditEntityType = "SHOW_TIMER"
+	AuditEntityTypeSchedule          AuditEntityType = "SCHEDULE"
+	AuditEntityTypeSoftPatch         AuditEntityType = "SOFT_PATCH"
+	AuditEntityTypeUniverse          AuditEntityType = "UNIVERSE"
+	AuditEntityTypeLayoutZone        AuditEntityType = "LAYOUT_ZONE"
+	AuditEntityTypeSelectionSet      AuditEntityType = "SELECTION_SET"
+	AuditEntityTypePixelMatrix       AuditEntityType = "PIXEL_MATRIX"
+	// Server-wide state such as DMX output, network settings, and playback control
+	AuditEntityTypeSystem AuditEntityType = "SYSTEM"
+)
+
+var AllAuditEntityType = []AuditEntityType{
+	AuditEntityTypeProject,
+	AuditEntityTypeFixtureDefinition,
+	AuditEntityTypeFixture,
+	AuditEntityTypeFixtureGroup,
+	AuditEntityTypeScene,
+	AuditEntityTypeSceneBoard,
+	AuditEntityTypeSceneBoardButton,
+	AuditEntityTypeCueList,
+	AuditEntityTypeCue,
+	AuditEntityTypeEffect,
+	AuditEntityTypeSubmaster,
+	AuditEntityTypePalette,
+	AuditEntityTypeSnapshot,
+	AuditEntityTypeShowTimer,
+	AuditEntityTypeSchedule,
+	AuditEntityTypeSoftPatch,
+	AuditEntityTypeUniverse,
+	AuditEntityTypeLayoutZone,
+	AuditEntityTypeSelectionSet,
+	AuditEntityTypePixelMatrix,
+	AuditEntityTypeSystem,
+}
+
+func (e AuditEntityType) IsValid() bool {
+	switch e {
+	case AuditEntityTypeProject, AuditEntityTypeFixtureDefinition, AuditEntityTypeFixture, AuditEntityTypeFixtureGroup, AuditEntityTypeScene, AuditEntityTypeSceneBoard, AuditEntityTypeSceneBoardButton, AuditEntityTypeCueList, AuditEntityTypeCue, AuditEntityTypeEffect, AuditEntityTypeSubmaster, AuditEntityTypePalette, AuditEntityTypeSnapshot, AuditEntityTypeShowTimer, AuditEntityTypeSchedule, AuditEntityTypeSoftPatch, AuditEntityTypeUniverse, AuditEntityTypeLayoutZone, AuditEntityTypeSelectionSet, AuditEntityTypePixelMatrix, AuditEntityTypeSystem:
+		return true
+	}
+	return false
+}
+
+func (e AuditEntityType) String() string {
+	return string(e)
+}
+
+func (e *AuditEntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditEntityType", str)
+	}
+	return nil
+}
+
+func (e AuditEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AuditEntityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AuditEntityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BatchOperationKind string
+
+const (
+	BatchOperationKindCreateScene      BatchOperationKind = "CREATE_SCENE"
+	BatchOperationKindCreateSceneBoard BatchOperationKind = "CREATE_SCENE_BOARD"
+	BatchOperationKindAddSceneToBoard  BatchOperationKind = "ADD_SCENE_TO_BOARD"
+	BatchOperationKindCreateCueList    BatchOperationKind = "CREATE_CUE_LIST"
+	BatchOperationKindCreateCue        BatchOperationKind = "CREATE_CUE"
+)
+
+var AllBatchOperationKind = []BatchOperationKind{
+	BatchOperationKindCreateScene,
+	BatchOperationKindCreateSceneBoard,
+	BatchOperationKindAddSceneToBoard,
+	BatchOperationKindCreateCueList,
+	BatchOperationKindCreateCue,
+}
+
+func (e BatchOperationKind) IsValid() bool {
+	switch e {
+	case BatchOperationKindCreateScene, BatchOperationKindCreateSceneBoard, BatchOperationKindAddSceneToBoard, BatchOperationKindCreateCueList, BatchOperationKindCreateCue:
+		return true
+	}
+	return false
+}
+
+func (e BatchOperationKind) String() string {
+	return string(e)
+}
+
+func (e *BatchOperationKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BatchOperationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BatchOperationKind", str)
+	}
+	return nil
+}
+
+func (e BatchOperationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BatchOperationKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BatchOperationKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BeatQuantize string
+
+const (
+	// Fire immediately
+	BeatQuantizeNone BeatQuantize = "NONE"
+	// Wait for the next beat
+	BeatQuantizeBeat BeatQuantize = "BEAT"
+	// Wait for the next downbeat (first beat of a bar)
+	BeatQuantizeBar BeatQuantize = "BAR"
+)
+
+var AllBeatQuantize = []BeatQuantize{
+	BeatQuantizeNone,
+	BeatQuantizeBeat,
+	BeatQuantizeBar,
+}
+
+func (e BeatQuantize) IsValid() bool {
+	switch e {
+	case BeatQuantizeNone, BeatQuantizeBeat, BeatQuantizeBar:
+		return true
+	}
+	return false
+}
+
+func (e BeatQuantize) String() string {
+	return string(e)
+}
+
+func (e *BeatQuantize) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BeatQuantize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BeatQuantize", str)
+	}
+	return nil
+}
+
+func (e BeatQuantize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BeatQuantize) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BeatQuantize) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ChannelType string
+
+const (
+	ChannelTypeIntensity  ChannelType = "INTENSITY"
+	ChannelTypeRed        ChannelType = "RED"
+	ChannelTypeGreen      ChannelType = "GREEN"
+	ChannelTypeBlue       ChannelType = "BLUE"
+	ChannelTypeWhite      ChannelType = "WHITE"
+	ChannelTypeAmber      ChannelType = "AMBER"
+	ChannelTypeUv         ChannelType = "UV"
+	ChannelTypeCyan       ChannelType = "CYAN"
+	ChannelTypeMagenta    ChannelType = "MAGENTA"
+	ChannelTypeYellow     ChannelType = "YELLOW"
+	ChannelTypeLime       ChannelType = "LIME"
+	ChannelTypeIndigo     ChannelType = "INDIGO"
+	ChannelTypeColdWhite  ChannelType = "COLD_WHITE"
+	ChannelTypeWarmWhite  ChannelType = "WARM_WHITE"
+	ChannelTypePan        ChannelType = "PAN"
+	ChannelTypeTilt       ChannelType = "TILT"
+	ChannelTypeZoom       ChannelType = "ZOOM"
+	ChannelTypeFocus      ChannelType = "FOCUS"
+	ChannelTypeIris       ChannelType = "IRIS"
+	ChannelTypeGobo       ChannelType = "GOBO"
+	ChannelTypeColorWheel ChannelType = "COLOR_WHEEL"
+	ChannelTypeEffect     ChannelType = "EFFECT"
+	ChannelTypeStrobe     ChannelType = "STROBE"
+	ChannelTypeMacro      ChannelType = "MACRO"
+	ChannelTypeOther      ChannelType = "OTHER"
+)
+
+var AllChannelType = []ChannelType{
+	ChannelTypeIntensity,
+	ChannelTypeRed,
+	ChannelTypeGreen,
+	ChannelTypeBlue,
+	ChannelTypeWhite,
+	ChannelTypeAmber,
+	ChannelTypeUv,
+	ChannelTypeCyan,
+	ChannelTypeMagenta,
+	ChannelTypeYellow,
+	ChannelTypeLime,
+	ChannelTypeIndigo,
+	ChannelTypeColdWhite,
+	ChannelTypeWarmWhite,
+	ChannelTypePan,
+	ChannelTypeTilt,
+	ChannelTypeZoom,
+	ChannelTypeFocus,
+	ChannelTypeIris,
 	ChannelTypeGobo,
 	ChannelTypeColorWheel,
 	ChannelTypeEffect,
@@ -900,36 +2900,2205 @@ var AllChannelType = []ChannelType{
 	ChannelTypeOther,
 }
 
-func (e ChannelType) IsValid() bool {
+func (e ChannelType) IsValid() bool {
+	switch e {
+	case ChannelTypeIntensity, ChannelTypeRed, ChannelTypeGreen, ChannelTypeBlue, ChannelTypeWhite, ChannelTypeAmber, ChannelTypeUv, ChannelTypeCyan, ChannelTypeMagenta, ChannelTypeYellow, ChannelTypeLime, ChannelTypeIndigo, ChannelTypeColdWhite, ChannelTypeWarmWhite, ChannelTypePan, ChannelTypeTilt, ChannelTypeZoom, ChannelTypeFocus, ChannelTypeIris, ChannelTypeGobo, ChannelTypeColorWheel, ChannelTypeEffect, ChannelTypeStrobe, ChannelTypeMacro, ChannelTypeOther:
+		return true
+	}
+	return false
+}
+
+func (e ChannelType) String() string {
+	return string(e)
+}
+
+func (e *ChannelType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChannelType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChannelType", str)
+	}
+	return nil
+}
+
+func (e ChannelType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ChannelType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ChannelType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How a cue list moves from one cue to the next
+type CueListPlaybackMode string
+
+const (
+	// Cues fade in over their fade times
+	CueListPlaybackModeTimed CueListPlaybackMode = "TIMED"
+	// A manual crossfader (setCueListCrossfade) moves between the current and next cue; GO commands still run timed fades
+	CueListPlaybackModeCrossfader CueListPlaybackMode = "CROSSFADER"
+)
+
+var AllCueListPlaybackMode = []CueListPlaybackMode{
+	CueListPlaybackModeTimed,
+	CueListPlaybackModeCrossfader,
+}
+
+func (e CueListPlaybackMode) IsValid() bool {
+	switch e {
+	case CueListPlaybackModeTimed, CueListPlaybackModeCrossfader:
+		return true
+	}
+	return false
+}
+
+func (e CueListPlaybackMode) String() string {
+	return string(e)
+}
+
+func (e *CueListPlaybackMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CueListPlaybackMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CueListPlaybackMode", str)
+	}
+	return nil
+}
+
+func (e CueListPlaybackMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CueListPlaybackMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CueListPlaybackMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DayOfWeek string
+
+const (
+	DayOfWeekSunday    DayOfWeek = "SUNDAY"
+	DayOfWeekMonday    DayOfWeek = "MONDAY"
+	DayOfWeekTuesday   DayOfWeek = "TUESDAY"
+	DayOfWeekWednesday DayOfWeek = "WEDNESDAY"
+	DayOfWeekThursday  DayOfWeek = "THURSDAY"
+	DayOfWeekFriday    DayOfWeek = "FRIDAY"
+	DayOfWeekSaturday  DayOfWeek = "SATURDAY"
+)
+
+var AllDayOfWeek = []DayOfWeek{
+	DayOfWeekSunday,
+	DayOfWeekMonday,
+	DayOfWeekTuesday,
+	DayOfWeekWednesday,
+	DayOfWeekThursday,
+	DayOfWeekFriday,
+	DayOfWeekSaturday,
+}
+
+func (e DayOfWeek) IsValid() bool {
+	switch e {
+	case DayOfWeekSunday, DayOfWeekMonday, DayOfWeekTuesday, DayOfWeekWednesday, DayOfWeekThursday, DayOfWeekFriday, DayOfWeekSaturday:
+		return true
+	}
+	return false
+}
+
+func (e DayOfWeek) String() string {
+	return string(e)
+}
+
+func (e *DayOfWeek) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DayOfWeek(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DayOfWeek", str)
+	}
+	return nil
+}
+
+func (e DayOfWeek) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DayOfWeek) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DayOfWeek) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DifferenceType string
+
+const (
+	DifferenceTypeValuesChanged DifferenceType = "VALUES_CHANGED"
+	DifferenceTypeOnlyInScene1  DifferenceType = "ONLY_IN_SCENE1"
+	DifferenceTypeOnlyInScene2  DifferenceType = "ONLY_IN_SCENE2"
+)
+
+var AllDifferenceType = []DifferenceType{
+	DifferenceTypeValuesChanged,
+	DifferenceTypeOnlyInScene1,
+	DifferenceTypeOnlyInScene2,
+}
+
+func (e DifferenceType) IsValid() bool {
+	switch e {
+	case DifferenceTypeValuesChanged, DifferenceTypeOnlyInScene1, DifferenceTypeOnlyInScene2:
+		return true
+	}
+	return false
+}
+
+func (e DifferenceType) String() string {
+	return string(e)
+}
+
+func (e *DifferenceType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DifferenceType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DifferenceType", str)
+	}
+	return nil
+}
+
+func (e DifferenceType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DifferenceType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DifferenceType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type EasingType string
+
+const (
+	EasingTypeLinear             EasingType = "LINEAR"
+	EasingTypeEaseInOutCubic     EasingType = "EASE_IN_OUT_CUBIC"
+	EasingTypeEaseInOutSine      EasingType = "EASE_IN_OUT_SINE"
+	EasingTypeEaseOutExponential EasingType = "EASE_OUT_EXPONENTIAL"
+	EasingTypeBezier             EasingType = "BEZIER"
+	EasingTypeSCurve             EasingType = "S_CURVE"
+)
+
+var AllEasingType = []EasingType{
+	EasingTypeLinear,
+	EasingTypeEaseInOutCubic,
+	EasingTypeEaseInOutSine,
+	EasingTypeEaseOutExponential,
+	EasingTypeBezier,
+	EasingTypeSCurve,
+}
+
+func (e EasingType) IsValid() bool {
+	switch e {
+	case EasingTypeLinear, EasingTypeEaseInOutCubic, EasingTypeEaseInOutSine, EasingTypeEaseOutExponential, EasingTypeBezier, EasingTypeSCurve:
+		return true
+	}
+	return false
+}
+
+func (e EasingType) String() string {
+	return string(e)
+}
+
+func (e *EasingType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EasingType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EasingType", str)
+	}
+	return nil
+}
+
+func (e EasingType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EasingType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EasingType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// The order an effect moves through its fixtures
+type EffectOrder string
+
+const (
+	EffectOrderForward EffectOrder = "FORWARD"
+	EffectOrderReverse EffectOrder = "REVERSE"
+	// Shuffled each time the effect starts
+	EffectOrderRandom EffectOrder = "RANDOM"
+)
+
+var AllEffectOrder = []EffectOrder{
+	EffectOrderForward,
+	EffectOrderReverse,
+	EffectOrderRandom,
+}
+
+func (e EffectOrder) IsValid() bool {
+	switch e {
+	case EffectOrderForward, EffectOrderReverse, EffectOrderRandom:
+		return true
+	}
+	return false
+}
+
+func (e EffectOrder) String() string {
+	return string(e)
+}
+
+func (e *EffectOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EffectOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EffectOrder", str)
+	}
+	return nil
+}
+
+func (e EffectOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EffectOrder) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EffectOrder) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What an effect renders
+type EffectType string
+
+const (
+	// Steps a lit window through the fixtures
+	EffectTypeChase EffectType = "CHASE"
+	// Swings fixture intensity along a sine wave
+	EffectTypeSine EffectType = "SINE"
+	// Raises fixture intensity from low to high, then snaps back
+	EffectTypeRAMP EffectType = "RAMP"
+	// Cycles fixture color through the hue wheel (fixtures need red, green, and blue)
+	EffectTypeRainbow EffectType = "RAINBOW"
+)
+
+var AllEffectType = []EffectType{
+	EffectTypeChase,
+	EffectTypeSine,
+	EffectTypeRAMP,
+	EffectTypeRainbow,
+}
+
+func (e EffectType) IsValid() bool {
+	switch e {
+	case EffectTypeChase, EffectTypeSine, EffectTypeRAMP, EffectTypeRainbow:
+		return true
+	}
+	return false
+}
+
+func (e EffectType) String() string {
+	return string(e)
+}
+
+func (e *EffectType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EffectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EffectType", str)
+	}
+	return nil
+}
+
+func (e EffectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EffectType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EffectType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type EntityChangeAction string
+
+const (
+	EntityChangeActionCreated EntityChangeAction = "CREATED"
+	EntityChangeActionUpdated EntityChangeAction = "UPDATED"
+	EntityChangeActionDeleted EntityChangeAction = "DELETED"
+)
+
+var AllEntityChangeAction = []EntityChangeAction{
+	EntityChangeActionCreated,
+	EntityChangeActionUpdated,
+	EntityChangeActionDeleted,
+}
+
+func (e EntityChangeAction) IsValid() bool {
+	switch e {
+	case EntityChangeActionCreated, EntityChangeActionUpdated, EntityChangeActionDeleted:
+		return true
+	}
+	return false
+}
+
+func (e EntityChangeAction) String() string {
+	return string(e)
+}
+
+func (e *EntityChangeAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntityChangeAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntityChangeAction", str)
+	}
+	return nil
+}
+
+func (e EntityChangeAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EntityChangeAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EntityChangeAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Determines how a channel behaves during scene transitions.
+// FADE - Interpolate smoothly between values (default for intensity, colors)
+// SNAP - Jump to target value at start of transition (for gobos, macros, effects)
+// SNAP_END - Jump to target value at end of transition
+type FadeBehavior string
+
+const (
+	FadeBehaviorFade    FadeBehavior = "FADE"
+	FadeBehaviorSnap    FadeBehavior = "SNAP"
+	FadeBehaviorSnapEnd FadeBehavior = "SNAP_END"
+)
+
+var AllFadeBehavior = []FadeBehavior{
+	FadeBehaviorFade,
+	FadeBehaviorSnap,
+	FadeBehaviorSnapEnd,
+}
+
+func (e FadeBehavior) IsValid() bool {
+	switch e {
+	case FadeBehaviorFade, FadeBehaviorSnap, FadeBehaviorSnapEnd:
+		return true
+	}
+	return false
+}
+
+func (e FadeBehavior) String() string {
+	return string(e)
+}
+
+func (e *FadeBehavior) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FadeBehavior(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FadeBehavior", str)
+	}
+	return nil
+}
+
+func (e FadeBehavior) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FadeBehavior) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FadeBehavior) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// A control a fader wing channel drives
+type FaderWingTarget string
+
+const (
+	FaderWingTargetGrandMaster FaderWingTarget = "GRAND_MASTER"
+	// A submaster level; targetId is the submaster ID
+	FaderWingTargetSubmaster FaderWingTarget = "SUBMASTER"
+	// The intensity of a scene board button's scene; targetId is the button ID
+	FaderWingTargetSceneBoardButton FaderWingTarget = "SCENE_BOARD_BUTTON"
+)
+
+var AllFaderWingTarget = []FaderWingTarget{
+	FaderWingTargetGrandMaster,
+	FaderWingTargetSubmaster,
+	FaderWingTargetSceneBoardButton,
+}
+
+func (e FaderWingTarget) IsValid() bool {
+	switch e {
+	case FaderWingTargetGrandMaster, FaderWingTargetSubmaster, FaderWingTargetSceneBoardButton:
+		return true
+	}
+	return false
+}
+
+func (e FaderWingTarget) String() string {
+	return string(e)
+}
+
+func (e *FaderWingTarget) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FaderWingTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FaderWingTarget", str)
+	}
+	return nil
+}
+
+func (e FaderWingTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FaderWingTarget) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FaderWingTarget) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FixtureConflictStrategy string
+
+const (
+	FixtureConflictStrategySkip    FixtureConflictStrategy = "SKIP"
+	FixtureConflictStrategyReplace FixtureConflictStrategy = "REPLACE"
+	FixtureConflictStrategyError   FixtureConflictStrategy = "ERROR"
+)
+
+var AllFixtureConflictStrategy = []FixtureConflictStrategy{
+	FixtureConflictStrategySkip,
+	FixtureConflictStrategyReplace,
+	FixtureConflictStrategyError,
+}
+
+func (e FixtureConflictStrategy) IsValid() bool {
+	switch e {
+	case FixtureConflictStrategySkip, FixtureConflictStrategyReplace, FixtureConflictStrategyError:
+		return true
+	}
+	return false
+}
+
+func (e FixtureConflictStrategy) String() string {
+	return string(e)
+}
+
+func (e *FixtureConflictStrategy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FixtureConflictStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FixtureConflictStrategy", str)
+	}
+	return nil
+}
+
+func (e FixtureConflictStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FixtureConflictStrategy) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FixtureConflictStrategy) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How a fixture library definition relates to the local definition with the same manufacturer and model
+type FixtureLibraryDefinitionStatus string
+
+const (
+	FixtureLibraryDefinitionStatusNew         FixtureLibraryDefinitionStatus = "NEW"
+	FixtureLibraryDefinitionStatusUpToDate    FixtureLibraryDefinitionStatus = "UP_TO_DATE"
+	FixtureLibraryDefinitionStatusRemoteNewer FixtureLibraryDefinitionStatus = "REMOTE_NEWER"
+	FixtureLibraryDefinitionStatusLocalNewer  FixtureLibraryDefinitionStatus = "LOCAL_NEWER"
+)
+
+var AllFixtureLibraryDefinitionStatus = []FixtureLibraryDefinitionStatus{
+	FixtureLibraryDefinitionStatusNew,
+	FixtureLibraryDefinitionStatusUpToDate,
+	FixtureLibraryDefinitionStatusRemoteNewer,
+	FixtureLibraryDefinitionStatusLocalNewer,
+}
+
+func (e FixtureLibraryDefinitionStatus) IsValid() bool {
+	switch e {
+	case FixtureLibraryDefinitionStatusNew, FixtureLibraryDefinitionStatusUpToDate, FixtureLibraryDefinitionStatusRemoteNewer, FixtureLibraryDefinitionStatusLocalNewer:
+		return true
+	}
+	return false
+}
+
+func (e FixtureLibraryDefinitionStatus) String() string {
+	return string(e)
+}
+
+func (e *FixtureLibraryDefinitionStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FixtureLibraryDefinitionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FixtureLibraryDefinitionStatus", str)
+	}
+	return nil
+}
+
+func (e FixtureLibraryDefinitionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FixtureLibraryDefinitionStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FixtureLibraryDefinitionStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FixtureType string
+
+const (
+	FixtureTypeLedPar     FixtureType = "LED_PAR"
+	FixtureTypeMovingHead FixtureType = "MOVING_HEAD"
+	FixtureTypeStrobe     FixtureType = "STROBE"
+	FixtureTypeDimmer     FixtureType = "DIMMER"
+	FixtureTypeOther      FixtureType = "OTHER"
+)
+
+var AllFixtureType = []FixtureType{
+	FixtureTypeLedPar,
+	FixtureTypeMovingHead,
+	FixtureTypeStrobe,
+	FixtureTypeDimmer,
+	FixtureTypeOther,
+}
+
+func (e FixtureType) IsValid() bool {
+	switch e {
+	case FixtureTypeLedPar, FixtureTypeMovingHead, FixtureTypeStrobe, FixtureTypeDimmer, FixtureTypeOther:
+		return true
+	}
+	return false
+}
+
+func (e FixtureType) String() string {
+	return string(e)
+}
+
+func (e *FixtureType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FixtureType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FixtureType", str)
+	}
+	return nil
+}
+
+func (e FixtureType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FixtureType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FixtureType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What happens to a held scene board button's scene when the button is released.
+type HoldReleaseMode string
+
+const (
+	// Keep the scene at the level reached when released
+	HoldReleaseModeLatch HoldReleaseMode = "LATCH"
+	// Fade the scene back out using the board's default fade time
+	HoldReleaseModeRelease HoldReleaseMode = "RELEASE"
+)
+
+var AllHoldReleaseMode = []HoldReleaseMode{
+	HoldReleaseModeLatch,
+	HoldReleaseModeRelease,
+}
+
+func (e HoldReleaseMode) IsValid() bool {
+	switch e {
+	case HoldReleaseModeLatch, HoldReleaseModeRelease:
+		return true
+	}
+	return false
+}
+
+func (e HoldReleaseMode) String() string {
+	return string(e)
+}
+
+func (e *HoldReleaseMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = HoldReleaseMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid HoldReleaseMode", str)
+	}
+	return nil
+}
+
+func (e HoldReleaseMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *HoldReleaseMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e HoldReleaseMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type HouseLightsPreset string
+
+const (
+	HouseLightsPresetFull HouseLightsPreset = "FULL"
+	HouseLightsPresetHalf HouseLightsPreset = "HALF"
+	HouseLightsPresetShow HouseLightsPreset = "SHOW"
+	// Dark, but still held by the house lights
+	HouseLightsPresetOff HouseLightsPreset = "OFF"
+)
+
+var AllHouseLightsPreset = []HouseLightsPreset{
+	HouseLightsPresetFull,
+	HouseLightsPresetHalf,
+	HouseLightsPresetShow,
+	HouseLightsPresetOff,
+}
+
+func (e HouseLightsPreset) IsValid() bool {
+	switch e {
+	case HouseLightsPresetFull, HouseLightsPresetHalf, HouseLightsPresetShow, HouseLightsPresetOff:
+		return true
+	}
+	return false
+}
+
+func (e HouseLightsPreset) String() string {
+	return string(e)
+}
+
+func (e *HouseLightsPreset) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = HouseLightsPreset(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid HouseLightsPreset", str)
+	}
+	return nil
+}
+
+func (e HouseLightsPreset) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *HouseLightsPreset) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e HouseLightsPreset) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What an import does with a record
+type ImportAction string
+
+const (
+	// Added as a new record
+	ImportActionCreate ImportAction = "CREATE"
+	// Folded into an existing record
+	ImportActionMerge ImportAction = "MERGE"
+	// Added alongside an existing record of the same name
+	ImportActionRename ImportAction = "RENAME"
+	// Left out, or an existing record used as it is
+	ImportActionSkip ImportAction = "SKIP"
+)
+
+var AllImportAction = []ImportAction{
+	ImportActionCreate,
+	ImportActionMerge,
+	ImportActionRename,
+	ImportActionSkip,
+}
+
+func (e ImportAction) IsValid() bool {
+	switch e {
+	case ImportActionCreate, ImportActionMerge, ImportActionRename, ImportActionSkip:
+		return true
+	}
+	return false
+}
+
+func (e ImportAction) String() string {
+	return string(e)
+}
+
+func (e *ImportAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportAction", str)
+	}
+	return nil
+}
+
+func (e ImportAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportEntityType string
+
+const (
+	ImportEntityTypeProject           ImportEntityType = "PROJECT"
+	ImportEntityTypeFixtureDefinition ImportEntityType = "FIXTURE_DEFINITION"
+	ImportEntityTypeFixture           ImportEntityType = "FIXTURE"
+	ImportEntityTypeFixtureGroup      ImportEntityType = "FIXTURE_GROUP"
+	ImportEntityTypeUniverse          ImportEntityType = "UNIVERSE"
+	ImportEntityTypePalette           ImportEntityType = "PALETTE"
+	ImportEntityTypeScene             ImportEntityType = "SCENE"
+	ImportEntityTypeCueList           ImportEntityType = "CUE_LIST"
+	ImportEntityTypeCue               ImportEntityType = "CUE"
+	ImportEntityTypeSceneBoard        ImportEntityType = "SCENE_BOARD"
+)
+
+var AllImportEntityType = []ImportEntityType{
+	ImportEntityTypeProject,
+	ImportEntityTypeFixtureDefinition,
+	ImportEntityTypeFixture,
+	ImportEntityTypeFixtureGroup,
+	ImportEntityTypeUniverse,
+	ImportEntityTypePalette,
+	ImportEntityTypeScene,
+	ImportEntityTypeCueList,
+	ImportEntityTypeCue,
+	ImportEntityTypeSceneBoard,
+}
+
+func (e ImportEntityType) IsValid() bool {
+	switch e {
+	case ImportEntityTypeProject, ImportEntityTypeFixtureDefinition, ImportEntityTypeFixture, ImportEntityTypeFixtureGroup, ImportEntityTypeUniverse, ImportEntityTypePalette, ImportEntityTypeScene, ImportEntityTypeCueList, ImportEntityTypeCue, ImportEntityTypeSceneBoard:
+		return true
+	}
+	return false
+}
+
+func (e ImportEntityType) String() string {
+	return string(e)
+}
+
+func (e *ImportEntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportEntityType", str)
+	}
+	return nil
+}
+
+func (e ImportEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportEntityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportEntityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportMode string
+
+const (
+	ImportModeCreate ImportMode = "CREATE"
+	ImportModeMerge  ImportMode = "MERGE"
+)
+
+var AllImportMode = []ImportMode{
+	ImportModeCreate,
+	ImportModeMerge,
+}
+
+func (e ImportMode) IsValid() bool {
+	switch e {
+	case ImportModeCreate, ImportModeMerge:
+		return true
+	}
+	return false
+}
+
+func (e ImportMode) String() string {
+	return string(e)
+}
+
+func (e *ImportMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportMode", str)
+	}
+	return nil
+}
+
+func (e ImportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Where alignFixtures lines fixtures up
+type LayoutAlignment string
+
+const (
+	LayoutAlignmentLeft LayoutAlignment = "LEFT"
+	// Horizontal center
+	LayoutAlignmentCenter LayoutAlignment = "CENTER"
+	LayoutAlignmentRight  LayoutAlignment = "RIGHT"
+	LayoutAlignmentTop    LayoutAlignment = "TOP"
+	// Vertical center
+	LayoutAlignmentMiddle LayoutAlignment = "MIDDLE"
+	LayoutAlignmentBottom LayoutAlignment = "BOTTOM"
+)
+
+var AllLayoutAlignment = []LayoutAlignment{
+	LayoutAlignmentLeft,
+	LayoutAlignmentCenter,
+	LayoutAlignmentRight,
+	LayoutAlignmentTop,
+	LayoutAlignmentMiddle,
+	LayoutAlignmentBottom,
+}
+
+func (e LayoutAlignment) IsValid() bool {
+	switch e {
+	case LayoutAlignmentLeft, LayoutAlignmentCenter, LayoutAlignmentRight, LayoutAlignmentTop, LayoutAlignmentMiddle, LayoutAlignmentBottom:
+		return true
+	}
+	return false
+}
+
+func (e LayoutAlignment) String() string {
+	return string(e)
+}
+
+func (e *LayoutAlignment) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LayoutAlignment(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LayoutAlignment", str)
+	}
+	return nil
+}
+
+func (e LayoutAlignment) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LayoutAlignment) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LayoutAlignment) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LayoutAxis string
+
+const (
+	LayoutAxisHorizontal LayoutAxis = "HORIZONTAL"
+	LayoutAxisVertical   LayoutAxis = "VERTICAL"
+)
+
+var AllLayoutAxis = []LayoutAxis{
+	LayoutAxisHorizontal,
+	LayoutAxisVertical,
+}
+
+func (e LayoutAxis) IsValid() bool {
+	switch e {
+	case LayoutAxisHorizontal, LayoutAxisVertical:
+		return true
+	}
+	return false
+}
+
+func (e LayoutAxis) String() string {
+	return string(e)
+}
+
+func (e *LayoutAxis) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LayoutAxis(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LayoutAxis", str)
+	}
+	return nil
+}
+
+func (e LayoutAxis) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LayoutAxis) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LayoutAxis) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LogLevel string
+
+const (
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelError LogLevel = "ERROR"
+)
+
+var AllLogLevel = []LogLevel{
+	LogLevelDebug,
+	LogLevelInfo,
+	LogLevelWarn,
+	LogLevelError,
+}
+
+func (e LogLevel) IsValid() bool {
+	switch e {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	}
+	return false
+}
+
+func (e LogLevel) String() string {
+	return string(e)
+}
+
+func (e *LogLevel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LogLevel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LogLevel", str)
+	}
+	return nil
+}
+
+func (e LogLevel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LogLevel) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LogLevel) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// A step of a scene board button macro
+type MacroActionType string
+
+const (
+	// Activate a scene on the button's scene board
+	MacroActionTypeActivateScene MacroActionType = "ACTIVATE_SCENE"
+	// Pause for waitTime seconds
+	MacroActionTypeWait MacroActionType = "WAIT"
+	// Go to the next cue of a cue list, starting it if it is stopped
+	MacroActionTypeCueListGo MacroActionType = "CUE_LIST_GO"
+	// Send an OSC message over UDP
+	MacroActionTypeSendOsc MacroActionType = "SEND_OSC"
+)
+
+var AllMacroActionType = []MacroActionType{
+	MacroActionTypeActivateScene,
+	MacroActionTypeWait,
+	MacroActionTypeCueListGo,
+	MacroActionTypeSendOsc,
+}
+
+func (e MacroActionType) IsValid() bool {
+	switch e {
+	case MacroActionTypeActivateScene, MacroActionTypeWait, MacroActionTypeCueListGo, MacroActionTypeSendOsc:
+		return true
+	}
+	return false
+}
+
+func (e MacroActionType) String() string {
+	return string(e)
+}
+
+func (e *MacroActionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MacroActionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MacroActionType", str)
+	}
+	return nil
+}
+
+func (e MacroActionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MacroActionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MacroActionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NameUniquenessPolicy string
+
+const (
+	NameUniquenessPolicyAllowDuplicates  NameUniquenessPolicy = "ALLOW_DUPLICATES"
+	NameUniquenessPolicyRejectDuplicates NameUniquenessPolicy = "REJECT_DUPLICATES"
+	// Add " (2)", " (3)", ... to a name that is already taken
+	NameUniquenessPolicyAppendSuffix NameUniquenessPolicy = "APPEND_SUFFIX"
+)
+
+var AllNameUniquenessPolicy = []NameUniquenessPolicy{
+	NameUniquenessPolicyAllowDuplicates,
+	NameUniquenessPolicyRejectDuplicates,
+	NameUniquenessPolicyAppendSuffix,
+}
+
+func (e NameUniquenessPolicy) IsValid() bool {
+	switch e {
+	case NameUniquenessPolicyAllowDuplicates, NameUniquenessPolicyRejectDuplicates, NameUniquenessPolicyAppendSuffix:
+		return true
+	}
+	return false
+}
+
+func (e NameUniquenessPolicy) String() string {
+	return string(e)
+}
+
+func (e *NameUniquenessPolicy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NameUniquenessPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NameUniquenessPolicy", str)
+	}
+	return nil
+}
+
+func (e NameUniquenessPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NameUniquenessPolicy) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NameUniquenessPolicy) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Type of fixture change detected during OFL update check
+type OFLFixtureChangeType string
+
+const (
+	OFLFixtureChangeTypeNew       OFLFixtureChangeType = "NEW"
+	OFLFixtureChangeTypeUpdated   OFLFixtureChangeType = "UPDATED"
+	OFLFixtureChangeTypeUnchanged OFLFixtureChangeType = "UNCHANGED"
+)
+
+var AllOFLFixtureChangeType = []OFLFixtureChangeType{
+	OFLFixtureChangeTypeNew,
+	OFLFixtureChangeTypeUpdated,
+	OFLFixtureChangeTypeUnchanged,
+}
+
+func (e OFLFixtureChangeType) IsValid() bool {
+	switch e {
+	case OFLFixtureChangeTypeNew, OFLFixtureChangeTypeUpdated, OFLFixtureChangeTypeUnchanged:
+		return true
+	}
+	return false
+}
+
+func (e OFLFixtureChangeType) String() string {
+	return string(e)
+}
+
+func (e *OFLFixtureChangeType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OFLFixtureChangeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OFLFixtureChangeType", str)
+	}
+	return nil
+}
+
+func (e OFLFixtureChangeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OFLFixtureChangeType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OFLFixtureChangeType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Phases of the OFL import process
+type OFLImportPhase string
+
+const (
+	OFLImportPhaseIdle        OFLImportPhase = "IDLE"
+	OFLImportPhaseDownloading OFLImportPhase = "DOWNLOADING"
+	OFLImportPhaseExtracting  OFLImportPhase = "EXTRACTING"
+	OFLImportPhaseParsing     OFLImportPhase = "PARSING"
+	OFLImportPhaseImporting   OFLImportPhase = "IMPORTING"
+	OFLImportPhaseComplete    OFLImportPhase = "COMPLETE"
+	OFLImportPhaseFailed      OFLImportPhase = "FAILED"
+	OFLImportPhaseCancelled   OFLImportPhase = "CANCELLED"
+)
+
+var AllOFLImportPhase = []OFLImportPhase{
+	OFLImportPhaseIdle,
+	OFLImportPhaseDownloading,
+	OFLImportPhaseExtracting,
+	OFLImportPhaseParsing,
+	OFLImportPhaseImporting,
+	OFLImportPhaseComplete,
+	OFLImportPhaseFailed,
+	OFLImportPhaseCancelled,
+}
+
+func (e OFLImportPhase) IsValid() bool {
+	switch e {
+	case OFLImportPhaseIdle, OFLImportPhaseDownloading, OFLImportPhaseExtracting, OFLImportPhaseParsing, OFLImportPhaseImporting, OFLImportPhaseComplete, OFLImportPhaseFailed, OFLImportPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+func (e OFLImportPhase) String() string {
+	return string(e)
+}
+
+func (e *OFLImportPhase) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OFLImportPhase(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OFLImportPhase", str)
+	}
+	return nil
+}
+
+func (e OFLImportPhase) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OFLImportPhase) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OFLImportPhase) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OscArgumentType string
+
+const (
+	OscArgumentTypeInt    OscArgumentType = "INT"
+	OscArgumentTypeFloat  OscArgumentType = "FLOAT"
+	OscArgumentTypeString OscArgumentType = "STRING"
+)
+
+var AllOscArgumentType = []OscArgumentType{
+	OscArgumentTypeInt,
+	OscArgumentTypeFloat,
+	OscArgumentTypeString,
+}
+
+func (e OscArgumentType) IsValid() bool {
+	switch e {
+	case OscArgumentTypeInt, OscArgumentTypeFloat, OscArgumentTypeString:
+		return true
+	}
+	return false
+}
+
+func (e OscArgumentType) String() string {
+	return string(e)
+}
+
+func (e *OscArgumentType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OscArgumentType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OscArgumentType", str)
+	}
+	return nil
+}
+
+func (e OscArgumentType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OscArgumentType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OscArgumentType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How a limited channel's output follows the level sent to it
+type OutputCurve string
+
+const (
+	OutputCurveLinear OutputCurve = "LINEAR"
+	// Squared, for finer control at the bottom
+	OutputCurveSquare OutputCurve = "SQUARE"
+	// Square root, for a faster rise
+	OutputCurveSquareRoot OutputCurve = "SQUARE_ROOT"
+)
+
+var AllOutputCurve = []OutputCurve{
+	OutputCurveLinear,
+	OutputCurveSquare,
+	OutputCurveSquareRoot,
+}
+
+func (e OutputCurve) IsValid() bool {
+	switch e {
+	case OutputCurveLinear, OutputCurveSquare, OutputCurveSquareRoot:
+		return true
+	}
+	return false
+}
+
+func (e OutputCurve) String() string {
+	return string(e)
+}
+
+func (e *OutputCurve) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OutputCurve(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OutputCurve", str)
+	}
+	return nil
+}
+
+func (e OutputCurve) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OutputCurve) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OutputCurve) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// The attributes a palette sets
+type PaletteKind string
+
+const (
+	// Color channels and color wheels
+	PaletteKindColor PaletteKind = "COLOR"
+	// Pan and tilt
+	PaletteKindPosition PaletteKind = "POSITION"
+	// Zoom, focus, iris, gobo, and strobe
+	PaletteKindBeam PaletteKind = "BEAM"
+)
+
+var AllPaletteKind = []PaletteKind{
+	PaletteKindColor,
+	PaletteKindPosition,
+	PaletteKindBeam,
+}
+
+func (e PaletteKind) IsValid() bool {
+	switch e {
+	case PaletteKindColor, PaletteKindPosition, PaletteKindBeam:
+		return true
+	}
+	return false
+}
+
+func (e PaletteKind) String() string {
+	return string(e)
+}
+
+func (e *PaletteKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaletteKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaletteKind", str)
+	}
+	return nil
+}
+
+func (e PaletteKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaletteKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaletteKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// The type of playback a scene is live on
+type PlaybackKind string
+
+const (
+	// A scene activated on its own
+	PlaybackKindScene PlaybackKind = "SCENE"
+	// A scene activated from a scene board; each board is one playback
+	PlaybackKindSceneBoard PlaybackKind = "SCENE_BOARD"
+	// A cue list's current cue
+	PlaybackKindCueList PlaybackKind = "CUE_LIST"
+)
+
+var AllPlaybackKind = []PlaybackKind{
+	PlaybackKindScene,
+	PlaybackKindSceneBoard,
+	PlaybackKindCueList,
+}
+
+func (e PlaybackKind) IsValid() bool {
+	switch e {
+	case PlaybackKindScene, PlaybackKindSceneBoard, PlaybackKindCueList:
+		return true
+	}
+	return false
+}
+
+func (e PlaybackKind) String() string {
+	return string(e)
+}
+
+func (e *PlaybackKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PlaybackKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PlaybackKind", str)
+	}
+	return nil
+}
+
+func (e PlaybackKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PlaybackKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PlaybackKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PresenceActivity string
+
+const (
+	PresenceActivityViewing PresenceActivity = "VIEWING"
+	// Holds the entity's edit lock
+	PresenceActivityEditing PresenceActivity = "EDITING"
+)
+
+var AllPresenceActivity = []PresenceActivity{
+	PresenceActivityViewing,
+	PresenceActivityEditing,
+}
+
+func (e PresenceActivity) IsValid() bool {
+	switch e {
+	case PresenceActivityViewing, PresenceActivityEditing:
+		return true
+	}
+	return false
+}
+
+func (e PresenceActivity) String() string {
+	return string(e)
+}
+
+func (e *PresenceActivity) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PresenceActivity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PresenceActivity", str)
+	}
+	return nil
+}
+
+func (e PresenceActivity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PresenceActivity) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PresenceActivity) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PresenceEntityType string
+
+const (
+	PresenceEntityTypeScene   PresenceEntityType = "SCENE"
+	PresenceEntityTypeCueList PresenceEntityType = "CUE_LIST"
+	PresenceEntityTypeCue     PresenceEntityType = "CUE"
+)
+
+var AllPresenceEntityType = []PresenceEntityType{
+	PresenceEntityTypeScene,
+	PresenceEntityTypeCueList,
+	PresenceEntityTypeCue,
+}
+
+func (e PresenceEntityType) IsValid() bool {
+	switch e {
+	case PresenceEntityTypeScene, PresenceEntityTypeCueList, PresenceEntityTypeCue:
+		return true
+	}
+	return false
+}
+
+func (e PresenceEntityType) String() string {
+	return string(e)
+}
+
+func (e *PresenceEntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PresenceEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PresenceEntityType", str)
+	}
+	return nil
+}
+
+func (e PresenceEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PresenceEntityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PresenceEntityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// The kinds of record projectEntityChanged reports
+type ProjectEntityType string
+
+const (
+	ProjectEntityTypeScene      ProjectEntityType = "SCENE"
+	ProjectEntityTypeFixture    ProjectEntityType = "FIXTURE"
+	ProjectEntityTypeCueList    ProjectEntityType = "CUE_LIST"
+	ProjectEntityTypeCue        ProjectEntityType = "CUE"
+	ProjectEntityTypeSceneBoard ProjectEntityType = "SCENE_BOARD"
+)
+
+var AllProjectEntityType = []ProjectEntityType{
+	ProjectEntityTypeScene,
+	ProjectEntityTypeFixture,
+	ProjectEntityTypeCueList,
+	ProjectEntityTypeCue,
+	ProjectEntityTypeSceneBoard,
+}
+
+func (e ProjectEntityType) IsValid() bool {
+	switch e {
+	case ProjectEntityTypeScene, ProjectEntityTypeFixture, ProjectEntityTypeCueList, ProjectEntityTypeCue, ProjectEntityTypeSceneBoard:
+		return true
+	}
+	return false
+}
+
+func (e ProjectEntityType) String() string {
+	return string(e)
+}
+
+func (e *ProjectEntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectEntityType", str)
+	}
+	return nil
+}
+
+func (e ProjectEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProjectEntityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProjectEntityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// A kind of broken reference in a project
+type ProjectIssueType string
+
+const (
+	// A cue playing a scene that is deleted or in another project; repaired by deleting the cue
+	ProjectIssueTypeCueMissingScene ProjectIssueType = "CUE_MISSING_SCENE"
+	// A scene's values for a fixture that is deleted or in another project; repaired by deleting the values
+	ProjectIssueTypeFixtureValueMissingFixture ProjectIssueType = "FIXTURE_VALUE_MISSING_FIXTURE"
+	// A channel of a fixture mode that is not a channel of its definition;
+	// repaired by deleting the mode channel, for every project using the mode
+	ProjectIssueTypeModeChannelMissingChannel ProjectIssueType = "MODE_CHANNEL_MISSING_CHANNEL"
+)
+
+var AllProjectIssueType = []ProjectIssueType{
+	ProjectIssueTypeCueMissingScene,
+	ProjectIssueTypeFixtureValueMissingFixture,
+	ProjectIssueTypeModeChannelMissingChannel,
+}
+
+func (e ProjectIssueType) IsValid() bool {
+	switch e {
+	case ProjectIssueTypeCueMissingScene, ProjectIssueTypeFixtureValueMissingFixture, ProjectIssueTypeModeChannelMissingChannel:
+		return true
+	}
+	return false
+}
+
+func (e ProjectIssueType) String() string {
+	return string(e)
+}
+
+func (e *ProjectIssueType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectIssueType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectIssueType", str)
+	}
+	return nil
+}
+
+func (e ProjectIssueType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProjectIssueType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProjectIssueType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProjectRole string
+
+const (
+	ProjectRoleOwner  ProjectRole = "OWNER"
+	ProjectRoleEditor ProjectRole = "EDITOR"
+	ProjectRoleViewer ProjectRole = "VIEWER"
+)
+
+var AllProjectRole = []ProjectRole{
+	ProjectRoleOwner,
+	ProjectRoleEditor,
+	ProjectRoleViewer,
+}
+
+func (e ProjectRole) IsValid() bool {
+	switch e {
+	case ProjectRoleOwner, ProjectRoleEditor, ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e ProjectRole) String() string {
+	return string(e)
+}
+
+func (e *ProjectRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectRole", str)
+	}
+	return nil
+}
+
+func (e ProjectRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProjectRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProjectRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// This instance's part in tracking backup replication
+type ReplicationRole string
+
+const (
+	// Not replicating
+	ReplicationRoleStandalone ReplicationRole = "STANDALONE"
+	// Streams its live state to a tracking backup
+	ReplicationRolePrimary ReplicationRole = "PRIMARY"
+	// Tracks a primary, and takes over its output if the primary fails
+	ReplicationRoleBackup ReplicationRole = "BACKUP"
+)
+
+var AllReplicationRole = []ReplicationRole{
+	ReplicationRoleStandalone,
+	ReplicationRolePrimary,
+	ReplicationRoleBackup,
+}
+
+func (e ReplicationRole) IsValid() bool {
+	switch e {
+	case ReplicationRoleStandalone, ReplicationRolePrimary, ReplicationRoleBackup:
+		return true
+	}
+	return false
+}
+
+func (e ReplicationRole) String() string {
+	return string(e)
+}
+
+func (e *ReplicationRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReplicationRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReplicationRole", str)
+	}
+	return nil
+}
+
+func (e ReplicationRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReplicationRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReplicationRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportType string
+
+const (
+	// Each cue list's cues with their scenes, times and notes
+	ReportTypeCueSheet ReportType = "CUE_SHEET"
+	// Fixtures in DMX address order
+	ReportTypeChannelHookup ReportType = "CHANNEL_HOOKUP"
+	// Fixtures in project order with their instrument, mode and address
+	ReportTypeInstrumentSchedule ReportType = "INSTRUMENT_SCHEDULE"
+)
+
+var AllReportType = []ReportType{
+	ReportTypeCueSheet,
+	ReportTypeChannelHookup,
+	ReportTypeInstrumentSchedule,
+}
+
+func (e ReportType) IsValid() bool {
+	switch e {
+	case ReportTypeCueSheet, ReportTypeChannelHookup, ReportTypeInstrumentSchedule:
+		return true
+	}
+	return false
+}
+
+func (e ReportType) String() string {
+	return string(e)
+}
+
+func (e *ReportType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportType", str)
+	}
+	return nil
+}
+
+func (e ReportType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SceneSortField string
+
+const (
+	SceneSortFieldName      SceneSortField = "NAME"
+	SceneSortFieldCreatedAt SceneSortField = "CREATED_AT"
+	SceneSortFieldUpdatedAt SceneSortField = "UPDATED_AT"
+)
+
+var AllSceneSortField = []SceneSortField{
+	SceneSortFieldName,
+	SceneSortFieldCreatedAt,
+	SceneSortFieldUpdatedAt,
+}
+
+func (e SceneSortField) IsValid() bool {
 	switch e {
-	case ChannelTypeIntensity, ChannelTypeRed, ChannelTypeGreen, ChannelTypeBlue, ChannelTypeWhite, ChannelTypeAmber, ChannelTypeUv, ChannelTypeCyan, ChannelTypeMagenta, ChannelTypeYellow, ChannelTypeLime, ChannelTypeIndigo, ChannelTypeColdWhite, ChannelTypeWarmWhite, ChannelTypePan, ChannelTypeTilt, ChannelTypeZoom, ChannelTypeFocus, ChannelTypeIris, ChannelTypeGobo, ChannelTypeColorWheel, ChannelTypeEffect, ChannelTypeStrobe, ChannelTypeMacro, ChannelTypeOther:
+	case SceneSortFieldName, SceneSortFieldCreatedAt, SceneSortFieldUpdatedAt:
 		return true
 	}
 	return false
 }
 
-func (e ChannelType) String() string {
+func (e SceneSortField) String() string {
 	return string(e)
 }
 
-func (e *ChannelType) UnmarshalGQL(v any) error {
+func (e *SceneSortField) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ChannelType(str)
+	*e = SceneSortField(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ChannelType", str)
+		return fmt.Errorf("%s is not a valid SceneSortField", str)
 	}
 	return nil
 }
 
-func (e ChannelType) MarshalGQL(w io.Writer) {
+func (e SceneSortField) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ChannelType) UnmarshalJSON(b []byte) error {
+func (e *SceneSortField) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -937,56 +5106,57 @@ func (e *ChannelType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ChannelType) MarshalJSON() ([]byte, error) {
+func (e SceneSortField) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type DifferenceType string
+// What a schedule does when it fires
+type ScheduleAction string
 
 const (
-	DifferenceTypeValuesChanged DifferenceType = "VALUES_CHANGED"
-	DifferenceTypeOnlyInScene1  DifferenceType = "ONLY_IN_SCENE1"
-	DifferenceTypeOnlyInScene2  DifferenceType = "ONLY_IN_SCENE2"
+	// Fade to a scene
+	ScheduleActionActivateScene ScheduleAction = "ACTIVATE_SCENE"
+	// Go to the next cue of a cue list, starting it if it is stopped
+	ScheduleActionCueListGo ScheduleAction = "CUE_LIST_GO"
 )
 
-var AllDifferenceType = []DifferenceType{
-	DifferenceTypeValuesChanged,
-	DifferenceTypeOnlyInScene1,
-	DifferenceTypeOnlyInScene2,
+var AllScheduleAction = []ScheduleAction{
+	ScheduleActionActivateScene,
+	ScheduleActionCueListGo,
 }
 
-func (e DifferenceType) IsValid() bool {
+func (e ScheduleAction) IsValid() bool {
 	switch e {
-	case DifferenceTypeValuesChanged, DifferenceTypeOnlyInScene1, DifferenceTypeOnlyInScene2:
+	case ScheduleActionActivateScene, ScheduleActionCueListGo:
 		return true
 	}
 	return false
 }
 
-func (e DifferenceType) String() string {
+func (e ScheduleAction) String() string {
 	return string(e)
 }
 
-func (e *DifferenceType) UnmarshalGQL(v any) error {
+func (e *ScheduleAction) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = DifferenceType(str)
+	*e = ScheduleAction(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid DifferenceType", str)
+		return fmt.Errorf("%s is not a valid ScheduleAction", str)
 	}
 	return nil
 }
 
-func (e DifferenceType) MarshalGQL(w io.Writer) {
+func (e ScheduleAction) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *DifferenceType) UnmarshalJSON(b []byte) error {
+func (e *ScheduleAction) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -994,62 +5164,60 @@ func (e *DifferenceType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e DifferenceType) MarshalJSON() ([]byte, error) {
+func (e ScheduleAction) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type EasingType string
+// What sets the times a schedule fires
+type ScheduleTrigger string
 
 const (
-	EasingTypeLinear             EasingType = "LINEAR"
-	EasingTypeEaseInOutCubic     EasingType = "EASE_IN_OUT_CUBIC"
-	EasingTypeEaseInOutSine      EasingType = "EASE_IN_OUT_SINE"
-	EasingTypeEaseOutExponential EasingType = "EASE_OUT_EXPONENTIAL"
-	EasingTypeBezier             EasingType = "BEZIER"
-	EasingTypeSCurve             EasingType = "S_CURVE"
+	// A cron expression
+	ScheduleTriggerCron ScheduleTrigger = "CRON"
+	// Sunrise at the schedule's latitude and longitude
+	ScheduleTriggerSunrise ScheduleTrigger = "SUNRISE"
+	// Sunset at the schedule's latitude and longitude
+	ScheduleTriggerSunset ScheduleTrigger = "SUNSET"
 )
 
-var AllEasingType = []EasingType{
-	EasingTypeLinear,
-	EasingTypeEaseInOutCubic,
-	EasingTypeEaseInOutSine,
-	EasingTypeEaseOutExponential,
-	EasingTypeBezier,
-	EasingTypeSCurve,
+var AllScheduleTrigger = []ScheduleTrigger{
+	ScheduleTriggerCron,
+	ScheduleTriggerSunrise,
+	ScheduleTriggerSunset,
 }
 
-func (e EasingType) IsValid() bool {
+func (e ScheduleTrigger) IsValid() bool {
 	switch e {
-	case EasingTypeLinear, EasingTypeEaseInOutCubic, EasingTypeEaseInOutSine, EasingTypeEaseOutExponential, EasingTypeBezier, EasingTypeSCurve:
+	case ScheduleTriggerCron, ScheduleTriggerSunrise, ScheduleTriggerSunset:
 		return true
 	}
 	return false
 }
 
-func (e EasingType) String() string {
+func (e ScheduleTrigger) String() string {
 	return string(e)
 }
 
-func (e *EasingType) UnmarshalGQL(v any) error {
+func (e *ScheduleTrigger) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = EasingType(str)
+	*e = ScheduleTrigger(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid EasingType", str)
+		return fmt.Errorf("%s is not a valid ScheduleTrigger", str)
 	}
 	return nil
 }
 
-func (e EasingType) MarshalGQL(w io.Writer) {
+func (e ScheduleTrigger) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *EasingType) UnmarshalJSON(b []byte) error {
+func (e *ScheduleTrigger) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1057,60 +5225,65 @@ func (e *EasingType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e EasingType) MarshalJSON() ([]byte, error) {
+func (e ScheduleTrigger) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Determines how a channel behaves during scene transitions.
-// FADE - Interpolate smoothly between values (default for intensity, colors)
-// SNAP - Jump to target value at start of transition (for gobos, macros, effects)
-// SNAP_END - Jump to target value at end of transition
-type FadeBehavior string
+// A way of reordering a fixture selection
+type SelectionOrder string
 
 const (
-	FadeBehaviorFade    FadeBehavior = "FADE"
-	FadeBehaviorSnap    FadeBehavior = "SNAP"
-	FadeBehaviorSnapEnd FadeBehavior = "SNAP_END"
+	SelectionOrderReverse SelectionOrder = "REVERSE"
+	// The first, third, fifth... then the second, fourth...
+	SelectionOrderOddEven SelectionOrder = "ODD_EVEN"
+	// Alternating from the ends inward: the first, the last, the second, the second-last...
+	SelectionOrderInterleave SelectionOrder = "INTERLEAVE"
+	// By layout position across, then down; fixtures without a position last
+	SelectionOrderLeftToRight SelectionOrder = "LEFT_TO_RIGHT"
+	// By layout position down, then across; fixtures without a position last
+	SelectionOrderTopToBottom SelectionOrder = "TOP_TO_BOTTOM"
 )
 
-var AllFadeBehavior = []FadeBehavior{
-	FadeBehaviorFade,
-	FadeBehaviorSnap,
-	FadeBehaviorSnapEnd,
+var AllSelectionOrder = []SelectionOrder{
+	SelectionOrderReverse,
+	SelectionOrderOddEven,
+	SelectionOrderInterleave,
+	SelectionOrderLeftToRight,
+	SelectionOrderTopToBottom,
 }
 
-func (e FadeBehavior) IsValid() bool {
+func (e SelectionOrder) IsValid() bool {
 	switch e {
-	case FadeBehaviorFade, FadeBehaviorSnap, FadeBehaviorSnapEnd:
+	case SelectionOrderReverse, SelectionOrderOddEven, SelectionOrderInterleave, SelectionOrderLeftToRight, SelectionOrderTopToBottom:
 		return true
 	}
 	return false
 }
 
-func (e FadeBehavior) String() string {
+func (e SelectionOrder) String() string {
 	return string(e)
 }
 
-func (e *FadeBehavior) UnmarshalGQL(v any) error {
+func (e *SelectionOrder) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = FadeBehavior(str)
+	*e = SelectionOrder(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid FadeBehavior", str)
+		return fmt.Errorf("%s is not a valid SelectionOrder", str)
 	}
 	return nil
 }
 
-func (e FadeBehavior) MarshalGQL(w io.Writer) {
+func (e SelectionOrder) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *FadeBehavior) UnmarshalJSON(b []byte) error {
+func (e *SelectionOrder) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1118,56 +5291,59 @@ func (e *FadeBehavior) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e FadeBehavior) MarshalJSON() ([]byte, error) {
+func (e SelectionOrder) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type FixtureConflictStrategy string
+// Type of a setting's value. Every value is sent and stored as a string.
+type SettingType string
 
 const (
-	FixtureConflictStrategySkip    FixtureConflictStrategy = "SKIP"
-	FixtureConflictStrategyReplace FixtureConflictStrategy = "REPLACE"
-	FixtureConflictStrategyError   FixtureConflictStrategy = "ERROR"
+	SettingTypeString  SettingType = "STRING"
+	SettingTypeInteger SettingType = "INTEGER"
+	SettingTypeBoolean SettingType = "BOOLEAN"
+	SettingTypeJSON    SettingType = "JSON"
 )
 
-var AllFixtureConflictStrategy = []FixtureConflictStrategy{
-	FixtureConflictStrategySkip,
-	FixtureConflictStrategyReplace,
-	FixtureConflictStrategyError,
+var AllSettingType = []SettingType{
+	SettingTypeString,
+	SettingTypeInteger,
+	SettingTypeBoolean,
+	SettingTypeJSON,
 }
 
-func (e FixtureConflictStrategy) IsValid() bool {
+func (e SettingType) IsValid() bool {
 	switch e {
-	case FixtureConflictStrategySkip, FixtureConflictStrategyReplace, FixtureConflictStrategyError:
+	case SettingTypeString, SettingTypeInteger, SettingTypeBoolean, SettingTypeJSON:
 		return true
 	}
 	return false
 }
 
-func (e FixtureConflictStrategy) String() string {
+func (e SettingType) String() string {
 	return string(e)
 }
 
-func (e *FixtureConflictStrategy) UnmarshalGQL(v any) error {
+func (e *SettingType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = FixtureConflictStrategy(str)
+	*e = SettingType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid FixtureConflictStrategy", str)
+		return fmt.Errorf("%s is not a valid SettingType", str)
 	}
 	return nil
 }
 
-func (e FixtureConflictStrategy) MarshalGQL(w io.Writer) {
+func (e SettingType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *FixtureConflictStrategy) UnmarshalJSON(b []byte) error {
+func (e *SettingType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1175,60 +5351,56 @@ func (e *FixtureConflictStrategy) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e FixtureConflictStrategy) MarshalJSON() ([]byte, error) {
+func (e SettingType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type FixtureType string
+type ShowTimerKind string
 
 const (
-	FixtureTypeLedPar     FixtureType = "LED_PAR"
-	FixtureTypeMovingHead FixtureType = "MOVING_HEAD"
-	FixtureTypeStrobe     FixtureType = "STROBE"
-	FixtureTypeDimmer     FixtureType = "DIMMER"
-	FixtureTypeOther      FixtureType = "OTHER"
+	// Counts down from a duration and can trigger a cue list at zero
+	ShowTimerKindCountdown ShowTimerKind = "COUNTDOWN"
+	// Counts up from zero, e.g. a running show clock
+	ShowTimerKindStopwatch ShowTimerKind = "STOPWATCH"
 )
 
-var AllFixtureType = []FixtureType{
-	FixtureTypeLedPar,
-	FixtureTypeMovingHead,
-	FixtureTypeStrobe,
-	FixtureTypeDimmer,
-	FixtureTypeOther,
+var AllShowTimerKind = []ShowTimerKind{
+	ShowTimerKindCountdown,
+	ShowTimerKindStopwatch,
 }
 
-func (e FixtureType) IsValid() bool {
+func (e ShowTimerKind) IsValid() bool {
 	switch e {
-	case FixtureTypeLedPar, FixtureTypeMovingHead, FixtureTypeStrobe, FixtureTypeDimmer, FixtureTypeOther:
+	case ShowTimerKindCountdown, ShowTimerKindStopwatch:
 		return true
 	}
 	return false
 }
 
-func (e FixtureType) String() string {
+func (e ShowTimerKind) String() string {
 	return string(e)
 }
 
-func (e *FixtureType) UnmarshalGQL(v any) error {
+func (e *ShowTimerKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = FixtureType(str)
+	*e = ShowTimerKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid FixtureType", str)
+		return fmt.Errorf("%s is not a valid ShowTimerKind", str)
 	}
 	return nil
 }
 
-func (e FixtureType) MarshalGQL(w io.Writer) {
+func (e ShowTimerKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *FixtureType) UnmarshalJSON(b []byte) error {
+func (e *ShowTimerKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1236,54 +5408,59 @@ func (e *FixtureType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e FixtureType) MarshalJSON() ([]byte, error) {
+func (e ShowTimerKind) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type ImportMode string
+// Why a project snapshot was taken
+type SnapshotReason string
 
 const (
-	ImportModeCreate ImportMode = "CREATE"
-	ImportModeMerge  ImportMode = "MERGE"
+	SnapshotReasonManual       SnapshotReason = "MANUAL"
+	SnapshotReasonScheduled    SnapshotReason = "SCHEDULED"
+	SnapshotReasonBeforeImport SnapshotReason = "BEFORE_IMPORT"
+	SnapshotReasonBeforeDelete SnapshotReason = "BEFORE_DELETE"
 )
 
-var AllImportMode = []ImportMode{
-	ImportModeCreate,
-	ImportModeMerge,
+var AllSnapshotReason = []SnapshotReason{
+	SnapshotReasonManual,
+	SnapshotReasonScheduled,
+	SnapshotReasonBeforeImport,
+	SnapshotReasonBeforeDelete,
 }
 
-func (e ImportMode) IsValid() bool {
+func (e SnapshotReason) IsValid() bool {
 	switch e {
-	case ImportModeCreate, ImportModeMerge:
+	case SnapshotReasonManual, SnapshotReasonScheduled, SnapshotReasonBeforeImport, SnapshotReasonBeforeDelete:
 		return true
 	}
 	return false
 }
 
-func (e ImportMode) String() string {
+func (e SnapshotReason) String() string {
 	return string(e)
 }
 
-func (e *ImportMode) UnmarshalGQL(v any) error {
+func (e *SnapshotReason) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ImportMode(str)
+	*e = SnapshotReason(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ImportMode", str)
+		return fmt.Errorf("%s is not a valid SnapshotReason", str)
 	}
 	return nil
 }
 
-func (e ImportMode) MarshalGQL(w io.Writer) {
+func (e SnapshotReason) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ImportMode) UnmarshalJSON(b []byte) error {
+func (e *SnapshotReason) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1291,57 +5468,57 @@ func (e *ImportMode) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ImportMode) MarshalJSON() ([]byte, error) {
+func (e SnapshotReason) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Type of fixture change detected during OFL update check
-type OFLFixtureChangeType string
+// What DMX output does during standby
+type StandbyOutput string
 
 const (
-	OFLFixtureChangeTypeNew       OFLFixtureChangeType = "NEW"
-	OFLFixtureChangeTypeUpdated   OFLFixtureChangeType = "UPDATED"
-	OFLFixtureChangeTypeUnchanged OFLFixtureChangeType = "UNCHANGED"
+	// Black out every universe once and stop transmitting
+	StandbyOutputZero StandbyOutput = "ZERO"
+	// Keep transmitting the last look, for fixtures that reset when DMX is lost
+	StandbyOutputHold StandbyOutput = "HOLD"
 )
 
-var AllOFLFixtureChangeType = []OFLFixtureChangeType{
-	OFLFixtureChangeTypeNew,
-	OFLFixtureChangeTypeUpdated,
-	OFLFixtureChangeTypeUnchanged,
+var AllStandbyOutput = []StandbyOutput{
+	StandbyOutputZero,
+	StandbyOutputHold,
 }
 
-func (e OFLFixtureChangeType) IsValid() bool {
+func (e StandbyOutput) IsValid() bool {
 	switch e {
-	case OFLFixtureChangeTypeNew, OFLFixtureChangeTypeUpdated, OFLFixtureChangeTypeUnchanged:
+	case StandbyOutputZero, StandbyOutputHold:
 		return true
 	}
 	return false
 }
 
-func (e OFLFixtureChangeType) String() string {
+func (e StandbyOutput) String() string {
 	return string(e)
 }
 
-func (e *OFLFixtureChangeType) UnmarshalGQL(v any) error {
+func (e *StandbyOutput) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = OFLFixtureChangeType(str)
+	*e = StandbyOutput(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid OFLFixtureChangeType", str)
+		return fmt.Errorf("%s is not a valid StandbyOutput", str)
 	}
 	return nil
 }
 
-func (e OFLFixtureChangeType) MarshalGQL(w io.Writer) {
+func (e StandbyOutput) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *OFLFixtureChangeType) UnmarshalJSON(b []byte) error {
+func (e *StandbyOutput) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1349,67 +5526,60 @@ func (e *OFLFixtureChangeType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e OFLFixtureChangeType) MarshalJSON() ([]byte, error) {
+func (e StandbyOutput) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Phases of the OFL import process
-type OFLImportPhase string
+// What put the server in standby or woke it
+type StandbyTrigger string
 
 const (
-	OFLImportPhaseIdle        OFLImportPhase = "IDLE"
-	OFLImportPhaseDownloading OFLImportPhase = "DOWNLOADING"
-	OFLImportPhaseExtracting  OFLImportPhase = "EXTRACTING"
-	OFLImportPhaseParsing     OFLImportPhase = "PARSING"
-	OFLImportPhaseImporting   OFLImportPhase = "IMPORTING"
-	OFLImportPhaseComplete    OFLImportPhase = "COMPLETE"
-	OFLImportPhaseFailed      OFLImportPhase = "FAILED"
-	OFLImportPhaseCancelled   OFLImportPhase = "CANCELLED"
+	// An enterStandby or wakeFromStandby request
+	StandbyTriggerManual StandbyTrigger = "MANUAL"
+	// An opening or closing time in the standby schedule
+	StandbyTriggerSchedule StandbyTrigger = "SCHEDULE"
+	// Art-Net DMX from another console (wake only)
+	StandbyTriggerArtnet StandbyTrigger = "ARTNET"
 )
 
-var AllOFLImportPhase = []OFLImportPhase{
-	OFLImportPhaseIdle,
-	OFLImportPhaseDownloading,
-	OFLImportPhaseExtracting,
-	OFLImportPhaseParsing,
-	OFLImportPhaseImporting,
-	OFLImportPhaseComplete,
-	OFLImportPhaseFailed,
-	OFLImportPhaseCancelled,
+var AllStandbyTrigger = []StandbyTrigger{
+	StandbyTriggerManual,
+	StandbyTriggerSchedule,
+	StandbyTriggerArtnet,
 }
 
-func (e OFLImportPhase) IsValid() bool {
+func (e StandbyTrigger) IsValid() bool {
 	switch e {
-	case OFLImportPhaseIdle, OFLImportPhaseDownloading, OFLImportPhaseExtracting, OFLImportPhaseParsing, OFLImportPhaseImporting, OFLImportPhaseComplete, OFLImportPhaseFailed, OFLImportPhaseCancelled:
+	case StandbyTriggerManual, StandbyTriggerSchedule, StandbyTriggerArtnet:
 		return true
 	}
 	return false
 }
 
-func (e OFLImportPhase) String() string {
+func (e StandbyTrigger) String() string {
 	return string(e)
 }
 
-func (e *OFLImportPhase) UnmarshalGQL(v any) error {
+func (e *StandbyTrigger) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = OFLImportPhase(str)
+	*e = StandbyTrigger(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid OFLImportPhase", str)
+		return fmt.Errorf("%s is not a valid StandbyTrigger", str)
 	}
 	return nil
 }
 
-func (e OFLImportPhase) MarshalGQL(w io.Writer) {
+func (e StandbyTrigger) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *OFLImportPhase) UnmarshalJSON(b []byte) error {
+func (e *StandbyTrigger) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1417,56 +5587,58 @@ func (e *OFLImportPhase) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e OFLImportPhase) MarshalJSON() ([]byte, error) {
+func (e StandbyTrigger) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type ProjectRole string
+type TimecodeFrameRate string
 
 const (
-	ProjectRoleOwner  ProjectRole = "OWNER"
-	ProjectRoleEditor ProjectRole = "EDITOR"
-	ProjectRoleViewer ProjectRole = "VIEWER"
+	TimecodeFrameRateFps24        TimecodeFrameRate = "FPS_24"
+	TimecodeFrameRateFps25        TimecodeFrameRate = "FPS_25"
+	TimecodeFrameRateFps29_97Drop TimecodeFrameRate = "FPS_29_97_DROP"
+	TimecodeFrameRateFps30        TimecodeFrameRate = "FPS_30"
 )
 
-var AllProjectRole = []ProjectRole{
-	ProjectRoleOwner,
-	ProjectRoleEditor,
-	ProjectRoleViewer,
+var AllTimecodeFrameRate = []TimecodeFrameRate{
+	TimecodeFrameRateFps24,
+	TimecodeFrameRateFps25,
+	TimecodeFrameRateFps29_97Drop,
+	TimecodeFrameRateFps30,
 }
 
-func (e ProjectRole) IsValid() bool {
+func (e TimecodeFrameRate) IsValid() bool {
 	switch e {
-	case ProjectRoleOwner, ProjectRoleEditor, ProjectRoleViewer:
+	case TimecodeFrameRateFps24, TimecodeFrameRateFps25, TimecodeFrameRateFps29_97Drop, TimecodeFrameRateFps30:
 		return true
 	}
 	return false
 }
 
-func (e ProjectRole) String() string {
+func (e TimecodeFrameRate) String() string {
 	return string(e)
 }
 
-func (e *ProjectRole) UnmarshalGQL(v any) error {
+func (e *TimecodeFrameRate) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ProjectRole(str)
+	*e = TimecodeFrameRate(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ProjectRole", str)
+		return fmt.Errorf("%s is not a valid TimecodeFrameRate", str)
 	}
 	return nil
 }
 
-func (e ProjectRole) MarshalGQL(w io.Writer) {
+func (e TimecodeFrameRate) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ProjectRole) UnmarshalJSON(b []byte) error {
+func (e *TimecodeFrameRate) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1474,56 +5646,59 @@ func (e *ProjectRole) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ProjectRole) MarshalJSON() ([]byte, error) {
+func (e TimecodeFrameRate) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SceneSortField string
+type TimecodeSource string
 
 const (
-	SceneSortFieldName      SceneSortField = "NAME"
-	SceneSortFieldCreatedAt SceneSortField = "CREATED_AT"
-	SceneSortFieldUpdatedAt SceneSortField = "UPDATED_AT"
+	// MIDI Timecode read from a raw MIDI device
+	TimecodeSourceMtc TimecodeSource = "MTC"
+	// Internal clock driven by startTimecode, stopTimecode and locateTimecode
+	TimecodeSourceInternal TimecodeSource = "INTERNAL"
+	// System time of day
+	TimecodeSourceSystemClock TimecodeSource = "SYSTEM_CLOCK"
 )
 
-var AllSceneSortField = []SceneSortField{
-	SceneSortFieldName,
-	SceneSortFieldCreatedAt,
-	SceneSortFieldUpdatedAt,
+var AllTimecodeSource = []TimecodeSource{
+	TimecodeSourceMtc,
+	TimecodeSourceInternal,
+	TimecodeSourceSystemClock,
 }
 
-func (e SceneSortField) IsValid() bool {
+func (e TimecodeSource) IsValid() bool {
 	switch e {
-	case SceneSortFieldName, SceneSortFieldCreatedAt, SceneSortFieldUpdatedAt:
+	case TimecodeSourceMtc, TimecodeSourceInternal, TimecodeSourceSystemClock:
 		return true
 	}
 	return false
 }
 
-func (e SceneSortField) String() string {
+func (e TimecodeSource) String() string {
 	return string(e)
 }
 
-func (e *SceneSortField) UnmarshalGQL(v any) error {
+func (e *TimecodeSource) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SceneSortField(str)
+	*e = TimecodeSource(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SceneSortField", str)
+		return fmt.Errorf("%s is not a valid TimecodeSource", str)
 	}
 	return nil
 }
 
-func (e SceneSortField) MarshalGQL(w io.Writer) {
+func (e TimecodeSource) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SceneSortField) UnmarshalJSON(b []byte) error {
+func (e *TimecodeSource) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1531,7 +5706,61 @@ func (e *SceneSortField) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SceneSortField) MarshalJSON() ([]byte, error) {
+func (e TimecodeSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How a universe is output
+type UniverseProtocol string
+
+const (
+	UniverseProtocolArtnet UniverseProtocol = "ARTNET"
+)
+
+var AllUniverseProtocol = []UniverseProtocol{
+	UniverseProtocolArtnet,
+}
+
+func (e UniverseProtocol) IsValid() bool {
+	switch e {
+	case UniverseProtocolArtnet:
+		return true
+	}
+	return false
+}
+
+func (e UniverseProtocol) String() string {
+	return string(e)
+}
+
+func (e *UniverseProtocol) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UniverseProtocol(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UniverseProtocol", str)
+	}
+	return nil
+}
+
+func (e UniverseProtocol) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UniverseProtocol) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UniverseProtocol) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -1542,16 +5771,19 @@ type UserRole string
 const (
 	UserRoleAdmin UserRole = "ADMIN"
 	UserRoleUser  UserRole = "USER"
+	// Front of house staff, who may run the house lights of any project and nothing else
+	UserRoleHouse UserRole = "HOUSE"
 )
 
 var AllUserRole = []UserRole{
 	UserRoleAdmin,
 	UserRoleUser,
+	UserRoleHouse,
 }
 
 func (e UserRole) IsValid() bool {
 	switch e {
-	case UserRoleAdmin, UserRoleUser:
+	case UserRoleAdmin, UserRoleUser, UserRoleHouse:
 		return true
 	}
 	return false
@@ -0,0 +1,83 @@
+package loaders
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader batches loads of one kind of record. Loads that arrive within its
+// wait of the first one in a batch are fetched together by a single call of
+// its fetch function. Results are not cached between batches, so a load
+// never sees data older than the batch it joined.
+type Loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	batch *batch[K, V]
+}
+
+// batch is one group of keys being fetched together.
+type batch[K comparable, V any] struct {
+	keys    []K
+	seen    map[K]bool
+	once    sync.Once
+	done    chan struct{}
+	results map[K]V
+	err     error
+}
+
+// NewLoader creates a loader that waits up to wait to gather each batch and
+// fetches as soon as maxBatch keys are waiting.
+func NewLoader[K comparable, V any](wait time.Duration, maxBatch int, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, wait: wait, maxBatch: maxBatch}
+}
+
+// Load returns the record for a key, or the zero value when there is none.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b := l.batch
+	if b == nil {
+		b = &batch[K, V]{seen: make(map[K]bool), done: make(chan struct{})}
+		l.batch = b
+		go func() {
+			time.Sleep(l.wait)
+			l.run(ctx, b)
+		}()
+	}
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.keys = append(b.keys, key)
+	}
+	if len(b.keys) >= l.maxBatch {
+		// Full batches take no more keys and are fetched without waiting
+		l.batch = nil
+		go l.run(ctx, b)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.results[key], b.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// run fetches a batch, once, closing it to new keys first.
+func (l *Loader[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		keys := b.keys
+		l.mu.Unlock()
+
+		b.results, b.err = l.fetch(context.WithoutCancel(ctx), keys)
+		close(b.done)
+	})
+}
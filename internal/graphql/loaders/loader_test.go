@@ -0,0 +1,127 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingFetch returns each key doubled, recording the batches it was
+// called with.
+func recordingFetch() (func(context.Context, []int) (map[int]int, error), func() [][]int) {
+	var mu sync.Mutex
+	var batches [][]int
+	fetch := func(_ context.Context, keys []int) (map[int]int, error) {
+		mu.Lock()
+		batches = append(batches, slices.Clone(keys))
+		mu.Unlock()
+		result := make(map[int]int, len(keys))
+		for _, k := range keys {
+			result[k] = k * 2
+		}
+		return result, nil
+	}
+	return fetch, func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+// loadAll loads keys concurrently, returning the values by key.
+func loadAll(t *testing.T, l *Loader[int, int], keys []int) map[int]int {
+	t.Helper()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	values := make(map[int]int)
+	for _, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k)
+			if err != nil {
+				t.Errorf("Load(%d) error = %v", k, err)
+			}
+			mu.Lock()
+			values[k] = v
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	fetch, batches := recordingFetch()
+	l := NewLoader(20*time.Millisecond, 100, fetch)
+
+	values := loadAll(t, l, []int{1, 2, 3, 2, 1})
+	for k, v := range values {
+		if v != k*2 {
+			t.Errorf("Load(%d) = %d, want %d", k, v, k*2)
+		}
+	}
+	if got := batches(); len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("batches = %v, want one batch of the 3 distinct keys", got)
+	}
+
+	// A later load starts a new batch rather than reusing results
+	if v, _ := l.Load(context.Background(), 1); v != 2 {
+		t.Errorf("Load(1) = %d, want 2", v)
+	}
+	if got := batches(); len(got) != 2 {
+		t.Errorf("batches = %v, want a second batch", got)
+	}
+}
+
+func TestLoaderSplitsAtMaxBatch(t *testing.T) {
+	fetch, batches := recordingFetch()
+	l := NewLoader(time.Hour, 2, fetch)
+
+	done := make(chan map[int]int)
+	go func() { done <- loadAll(t, l, []int{1, 2, 3, 4}) }()
+	select {
+	case values := <-done:
+		if len(values) != 4 {
+			t.Errorf("values = %v, want 4", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("full batches were not fetched without waiting")
+	}
+	for _, b := range batches() {
+		if len(b) != 2 {
+			t.Errorf("batch %v, want 2 keys", b)
+		}
+	}
+}
+
+func TestLoaderReturnsFetchErrors(t *testing.T) {
+	l := NewLoader(time.Millisecond, 10, func(context.Context, []string) (map[string]int, error) {
+		return nil, errors.New("database is down")
+	})
+	if _, err := l.Load(context.Background(), "a"); err == nil {
+		t.Error("Load() error = nil, want the fetch error")
+	}
+
+	missing := NewLoader(time.Millisecond, 10, func(context.Context, []string) (map[string]*int, error) {
+		return map[string]*int{}, nil
+	})
+	if v, err := missing.Load(context.Background(), "a"); err != nil || v != nil {
+		t.Errorf("Load() of a missing key = %v, %v, want nil", v, err)
+	}
+}
+
+func TestLoaderStopsWaitingWhenCanceled(t *testing.T) {
+	l := NewLoader(time.Hour, 10, func(_ context.Context, keys []int) (map[int]int, error) {
+		return nil, fmt.Errorf("fetched %v", keys)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want context.Canceled", err)
+	}
+}
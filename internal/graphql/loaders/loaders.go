@@ -0,0 +1,162 @@
+// Package loaders batches the database reads of GraphQL field resolvers.
+// Resolving a list of projects, fixtures, scenes, or cue lists runs the
+// fields of every item concurrently; instead of a query per item, the
+// loaders gather the items' keys and fetch them with one query per field.
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// batchWait is how long a batch gathers keys before it is fetched
+	batchWait = 2 * time.Millisecond
+	// maxBatch bounds the keys fetched by one query
+	maxBatch = 500
+)
+
+// Loaders are the batch loaders of one GraphQL operation.
+type Loaders struct {
+	// Parts of fixture definitions, by the ID of what they belong to or
+	// their own
+	DefinitionChannels  *Loader[string, []*models.ChannelDefinition]
+	DefinitionModes     *Loader[string, []*models.FixtureMode]
+	ModeChannels        *Loader[string, []*models.ModeChannel]
+	ChannelDefinitions  *Loader[string, *models.ChannelDefinition]
+	ChannelCapabilities *Loader[string, []*models.ChannelCapability]
+
+	// Fixtures and their channels, by fixture ID
+	Fixtures         *Loader[string, *models.FixtureInstance]
+	InstanceChannels *Loader[string, []*models.InstanceChannel]
+
+	// Scenes and their fixture values, by scene ID
+	Scenes             *Loader[string, *models.Scene]
+	SceneFixtureValues *Loader[string, []*models.FixtureValue]
+
+	// Cues of cue lists, by cue list ID
+	CueListCues *Loader[string, []*models.Cue]
+
+	// Fixture groups, by project ID
+	ProjectFixtureGroups *Loader[string, []*models.FixtureGroup]
+}
+
+// New creates loaders reading from a database.
+func New(db *gorm.DB) *Loaders {
+	return &Loaders{
+		DefinitionChannels: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.ChannelDefinition, error) {
+			return groupBy(ctx, db, "definition_id", `"offset" ASC`, ids, func(c *models.ChannelDefinition) string { return c.DefinitionID })
+		}),
+		DefinitionModes: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.FixtureMode, error) {
+			return groupBy(ctx, db, "definition_id", "name ASC", ids, func(m *models.FixtureMode) string { return m.DefinitionID })
+		}),
+		ModeChannels: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.ModeChannel, error) {
+			return groupBy(ctx, db, "mode_id", `"offset" ASC`, ids, func(c *models.ModeChannel) string { return c.ModeID })
+		}),
+		ChannelDefinitions: newLoader(func(ctx context.Context, ids []string) (map[string]*models.ChannelDefinition, error) {
+			return byID(ctx, db, ids, func(c *models.ChannelDefinition) string { return c.ID })
+		}),
+		ChannelCapabilities: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.ChannelCapability, error) {
+			return groupBy(ctx, db, "channel_id", "min_value ASC", ids, func(c *models.ChannelCapability) string { return c.ChannelID })
+		}),
+		Fixtures: newLoader(func(ctx context.Context, ids []string) (map[string]*models.FixtureInstance, error) {
+			return byID(ctx, db, ids, func(f *models.FixtureInstance) string { return f.ID })
+		}),
+		InstanceChannels: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.InstanceChannel, error) {
+			return groupBy(ctx, db, "fixture_id", `"offset" ASC`, ids, func(c *models.InstanceChannel) string { return c.FixtureID })
+		}),
+		Scenes: newLoader(func(ctx context.Context, ids []string) (map[string]*models.Scene, error) {
+			return byID(ctx, db, ids, func(s *models.Scene) string { return s.ID })
+		}),
+		SceneFixtureValues: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.FixtureValue, error) {
+			return groupBy(ctx, db, "scene_id", "scene_order ASC", ids, func(v *models.FixtureValue) string { return v.SceneID })
+		}),
+		CueListCues: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.Cue, error) {
+			return groupBy(ctx, db, "cue_list_id", "cue_number ASC", ids, func(c *models.Cue) string { return c.CueListID })
+		}),
+		ProjectFixtureGroups: newLoader(func(ctx context.Context, ids []string) (map[string][]*models.FixtureGroup, error) {
+			return groupBy(ctx, db, "project_id", "name ASC", ids, func(g *models.FixtureGroup) string { return g.ProjectID })
+		}),
+	}
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *Loader[string, V] {
+	return NewLoader(batchWait, maxBatch, fetch)
+}
+
+// byID loads records by ID.
+func byID[V any](ctx context.Context, db *gorm.DB, ids []string, id func(*V) string) (map[string]*V, error) {
+	var rows []V
+	if err := db.WithContext(ctx).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]*V, len(rows))
+	for i := range rows {
+		result[id(&rows[i])] = &rows[i]
+	}
+	return result, nil
+}
+
+// groupBy loads the records whose column holds one of keys, sorted, and
+// groups them by it. Keys without records get an empty list.
+func groupBy[V any](ctx context.Context, db *gorm.DB, column, order string, keys []string, key func(*V) string) (map[string][]*V, error) {
+	var rows []V
+	if err := db.WithContext(ctx).Where(fmt.Sprintf("%s IN ?", column), keys).Order(order).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*V, len(keys))
+	for _, k := range keys {
+		result[k] = []*V{}
+	}
+	for i := range rows {
+		k := key(&rows[i])
+		result[k] = append(result[k], &rows[i])
+	}
+	return result, nil
+}
+
+type contextKey struct{}
+
+// For returns the loaders of the operation a context belongs to, or nil
+// outside of one.
+func For(ctx context.Context) *Loaders {
+	l, _ := ctx.Value(contextKey{}).(*Loaders)
+	return l
+}
+
+// WithLoaders returns a context carrying loaders.
+func WithLoaders(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// Extension gives each GraphQL operation its own loaders. It is a gqlgen
+// handler extension; register it with the server's Use method.
+type Extension struct {
+	db *gorm.DB
+}
+
+var (
+	_ graphql.HandlerExtension     = (*Extension)(nil)
+	_ graphql.OperationInterceptor = (*Extension)(nil)
+)
+
+// NewExtension creates the extension for a database.
+func NewExtension(db *gorm.DB) *Extension {
+	return &Extension{db: db}
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (e *Extension) ExtensionName() string { return "Loaders" }
+
+// Validate implements graphql.HandlerExtension.
+func (e *Extension) Validate(graphql.ExecutableSchema) error { return nil }
+
+// InterceptOperation implements graphql.OperationInterceptor.
+func (e *Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(WithLoaders(ctx, New(e.db)))
+}
@@ -0,0 +1,137 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/snapshot"
+)
+
+// exportOptions converts GraphQL export options, which default to
+// exporting everything. ID lists select the records to export.
+func exportOptions(options *generated.ExportOptionsInput) export.ExportOptions {
+	opts := export.DefaultExportOptions()
+	if options == nil {
+		return opts
+	}
+	if options.IncludeFixtures.IsSet() && options.IncludeFixtures.Value() != nil {
+		opts.IncludeFixtures = *options.IncludeFixtures.Value()
+	}
+	if options.IncludeScenes.IsSet() && options.IncludeScenes.Value() != nil {
+		opts.IncludeScenes = *options.IncludeScenes.Value()
+	}
+	if options.IncludeCueLists.IsSet() && options.IncludeCueLists.Value() != nil {
+		opts.IncludeCueLists = *options.IncludeCueLists.Value()
+	}
+	if options.FixtureIds.IsSet() {
+		opts.FixtureIDs = options.FixtureIds.Value()
+	}
+	if options.SceneIds.IsSet() {
+		opts.SceneIDs = options.SceneIds.Value()
+	}
+	if options.CueListIds.IsSet() {
+		opts.CueListIDs = options.CueListIds.Value()
+	}
+	return opts
+}
+
+// importOptions converts GraphQL import options. Fixture conflicts are
+// skipped and built-in fixtures are not imported unless asked for.
+func importOptions(options generated.ImportOptionsInput) importservice.ImportOptions {
+	opts := importservice.ImportOptions{
+		Mode:                    importservice.ImportMode(options.Mode),
+		ImportBuiltInFixtures:   false,
+		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+	}
+	if options.TargetProjectID.IsSet() {
+		opts.TargetProjectID = options.TargetProjectID.Value()
+	}
+	if options.ProjectName.IsSet() {
+		opts.ProjectName = options.ProjectName.Value()
+	}
+	if options.FixtureConflictStrategy.IsSet() && options.FixtureConflictStrategy.Value() != nil {
+		opts.FixtureConflictStrategy = importservice.FixtureConflictStrategy(*options.FixtureConflictStrategy.Value())
+	}
+	if options.ImportBuiltInFixtures.IsSet() && options.ImportBuiltInFixtures.Value() != nil {
+		opts.ImportBuiltInFixtures = *options.ImportBuiltInFixtures.Value()
+	}
+	if options.DryRun.IsSet() && options.DryRun.Value() != nil {
+		opts.DryRun = *options.DryRun.Value()
+	}
+	return opts
+}
+
+// importFunc runs an import with the options it is given.
+type importFunc func(opts importservice.ImportOptions) (string, *importservice.ImportStats, []string, error)
+
+// runImport runs an import, saving a project it imports into first and
+// making the user the owner of a project it creates. A dry run only reports
+// what the import would do.
+func (r *Resolver) runImport(ctx context.Context, options generated.ImportOptionsInput, run importFunc) (*generated.ImportResult, error) {
+	opts := importOptions(options)
+	if opts.DryRun {
+		projectID, stats, warnings, err := run(opts)
+		if err != nil {
+			return nil, err
+		}
+		result := importResult(projectID, stats, warnings)
+		result.DryRun = true
+		return result, nil
+	}
+
+	// Importing into an existing project changes it, so save it first
+	var existing map[string]interface{}
+	if opts.Mode != importservice.ImportModeCreate && opts.TargetProjectID != nil {
+		r.snapshotBefore(ctx, *opts.TargetProjectID, snapshot.ReasonBeforeImport)
+		var err error
+		if existing, err = r.projectEntities(ctx, *opts.TargetProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	projectID, stats, warnings, err := run(opts)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("target project not found")
+	}
+	if opts.Mode == importservice.ImportModeCreate {
+		r.AuthService.GrantOwner(ctx, projectID)
+	}
+	r.refreshOutputLimits(ctx)
+	r.publishCreatedEntities(ctx, projectID, existing)
+
+	return importResult(projectID, stats, warnings), nil
+}
+
+// createProjectArchiveDownload issues a one-time link to download a project
+// as an archive.
+func (r *Resolver) createProjectArchiveDownload(ctx context.Context, projectID string, options *generated.ExportOptionsInput) (*generated.ProjectArchiveDownload, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	url, expiresAt := r.ArchiveDownloads.Issue(projectID, exportOptions(options))
+	fileName := (&export.ExportedProject{Project: &export.ExportProjectInfo{Name: project.Name}}).ArchiveFileName()
+	return &generated.ProjectArchiveDownload{
+		URL:       url,
+		FileName:  fileName,
+		ExpiresAt: expiresAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}, nil
+}
+
+// importProjectArchive imports an uploaded project archive.
+func (r *Resolver) importProjectArchive(ctx context.Context, file graphql.Upload, options generated.ImportOptionsInput) (*generated.ImportResult, error) {
+	return r.runImport(ctx, options, func(opts importservice.ImportOptions) (string, *importservice.ImportStats, []string, error) {
+		return r.ImportService.ImportArchive(ctx, file.File, opts)
+	})
+}
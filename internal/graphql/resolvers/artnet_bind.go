@@ -0,0 +1,78 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/network"
+)
+
+// validateArtNetBind checks that a bind names a local interface or address.
+// Empty restores the configured bind.
+func validateArtNetBind(bind string) error {
+	if bind == "" {
+		return nil
+	}
+	_, _, err := network.ResolveLocalAddress(bind)
+	return err
+}
+
+// loadArtNetBind applies the saved Art-Net bind, if any, over the configured
+// one. A saved interface that is not up leaves the binding as it was.
+func (r *Resolver) loadArtNetBind(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.BindSettingKey)
+	if err != nil || setting == nil {
+		return
+	}
+	if err := r.DMXService.SetBinding(setting.Value); err != nil {
+		log.Warn("cannot apply saved Art-Net bind", "bind", setting.Value, "error", err)
+	}
+}
+
+// updateArtNetBind applies and saves the Art-Net bind.
+func (r *Resolver) updateArtNetBind(ctx context.Context, bind string) (*generated.ArtNetBinding, error) {
+	// Apply first, so a bind that fails is not saved
+	if err := r.DMXService.SetBinding(bind); err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, dmx.BindSettingKey, bind); err != nil {
+		return nil, fmt.Errorf("failed to save Art-Net bind: %w", err)
+	}
+	return convertArtNetBinding(r.DMXService.Binding()), nil
+}
+
+// convertArtNetBinding converts a dmx.Binding to the GraphQL type.
+func convertArtNetBinding(b dmx.Binding) *generated.ArtNetBinding {
+	binding := &generated.ArtNetBinding{
+		Bind:          stringToPointer(b.Bind),
+		InterfaceName: stringToPointer(b.Interface),
+	}
+	if b.Address != nil {
+		binding.Address = stringPtr(b.Address.String())
+	}
+	return binding
+}
+
+// artNetInterfaces lists the local addresses output can be bound to,
+// marking the bound one.
+func (r *Resolver) artNetInterfaces() ([]*generated.ArtNetInterface, error) {
+	addresses, err := network.GetLocalAddresses()
+	if err != nil {
+		return nil, err
+	}
+	bound := r.DMXService.Binding().Address
+
+	interfaces := make([]*generated.ArtNetInterface, len(addresses))
+	for i, a := range addresses {
+		interfaces[i] = &generated.ArtNetInterface{
+			Name:          a.Interface,
+			InterfaceType: a.InterfaceType,
+			Address:       a.Address,
+			Broadcast:     stringToPointer(a.Broadcast),
+			Selected:      bound != nil && bound.String() == a.Address,
+		}
+	}
+	return interfaces, nil
+}
@@ -0,0 +1,243 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/audio"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// bumpReleaseTime is how long an audio bump fades out over when its button
+// has no flashReleaseTime, so the bump is seen.
+const bumpReleaseTime = 250 * time.Millisecond
+
+// wireAudio triggers scene board buttons on beats and publishes status
+// changes.
+func (r *Resolver) wireAudio() {
+	r.AudioService.SetBeatHandler(func(beat audio.Beat) {
+		r.triggerAudioButtons(context.Background(), beat)
+	})
+	r.AudioService.SetUpdateCallback(func(status *audio.Status) {
+		r.PubSub.Publish(pubsub.TopicAudioInput, "", convertAudioStatus(status))
+	})
+}
+
+// loadAudioConfig applies the saved audio input configuration, if any.
+func (r *Resolver) loadAudioConfig(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, audio.SettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var config audio.Config
+	if err := json.Unmarshal([]byte(setting.Value), &config); err != nil {
+		log.Warn("invalid saved audio input configuration", "error", err)
+		return
+	}
+	if _, err := r.AudioService.SetConfig(config); err != nil {
+		log.Warn("invalid saved audio input configuration", "error", err)
+	}
+}
+
+// updateAudioConfig validates, saves, and applies an audio input
+// configuration.
+func (r *Resolver) updateAudioConfig(ctx context.Context, in generated.AudioInputConfigInput) (*generated.AudioInputStatus, error) {
+	config := audio.DefaultConfig()
+	config.Enabled = in.Enabled
+	config.Source = audio.Source(in.Source)
+	if device := in.Device.Value(); device != nil {
+		config.Device = *device
+	}
+	if port := in.Port.Value(); port != nil {
+		config.Port = *port
+	}
+	if sampleRate := in.SampleRate.Value(); sampleRate != nil {
+		config.SampleRate = *sampleRate
+	}
+	if channels := in.Channels.Value(); channels != nil {
+		config.Channels = *channels
+	}
+	if bands := in.Bands.Value(); bands != nil {
+		config.Bands = make([]audio.Band, len(bands))
+		for i, band := range bands {
+			config.Bands[i] = audio.Band{
+				Name:        band.Name,
+				LowHz:       band.LowHz,
+				HighHz:      band.HighHz,
+				Threshold:   audio.DefaultThreshold,
+				MinInterval: audio.DefaultMinInterval,
+			}
+			if threshold := band.Threshold.Value(); threshold != nil {
+				config.Bands[i].Threshold = *threshold
+			}
+			if minInterval := band.MinInterval.Value(); minInterval != nil {
+				config.Bands[i].MinInterval = *minInterval
+			}
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	status, err := r.AudioService.SetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, audio.SettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save audio input configuration: %w", err)
+	}
+	return convertAudioStatus(status), nil
+}
+
+// setAudioTrigger applies a scene board button's optional audio trigger
+// inputs, rejecting bands the audio input does not analyze.
+func (r *Resolver) setAudioTrigger(button *models.SceneBoardButton, trigger graphql.Omittable[*bool], band graphql.Omittable[*string], action graphql.Omittable[*generated.AudioTriggerAction]) error {
+	if trigger.IsSet() && trigger.Value() != nil {
+		button.AudioTrigger = *trigger.Value()
+	}
+	if band.IsSet() {
+		if value := band.Value(); value != nil {
+			config := r.AudioService.Config()
+			if !config.HasBand(*value) {
+				return fmt.Errorf("unknown audio band: %s", *value)
+			}
+		}
+		button.AudioBand = band.Value()
+	}
+	if action.IsSet() && action.Value() != nil {
+		button.AudioAction = string(*action.Value())
+	}
+	if button.AudioAction == "" {
+		button.AudioAction = string(generated.AudioTriggerActionBump)
+	}
+	return nil
+}
+
+// triggerAudioButtons runs the action of every audio-triggered scene board
+// button listening to the beat's band.
+func (r *Resolver) triggerAudioButtons(ctx context.Context, beat audio.Beat) {
+	var buttons []models.SceneBoardButton
+	if err := r.db.WithContext(ctx).Where("audio_trigger = ?", true).Find(&buttons).Error; err != nil {
+		log.Warn("failed to load audio-triggered buttons", "error", err)
+		return
+	}
+
+	config := r.AudioService.Config()
+	for _, button := range buttons {
+		band := ""
+		if button.AudioBand != nil {
+			band = *button.AudioBand
+		} else if len(config.Bands) > 0 {
+			band = config.Bands[0].Name
+		}
+		if band != beat.Band {
+			continue
+		}
+
+		var err error
+		switch generated.AudioTriggerAction(button.AudioAction) {
+		case generated.AudioTriggerActionAdvanceEffects:
+			err = r.advanceSceneEffects(ctx, button.SceneID)
+		default:
+			err = r.bumpButton(ctx, button)
+		}
+		if err != nil {
+			log.Warn("audio trigger failed", "button", button.ID, "error", err)
+		}
+	}
+}
+
+// bumpButton flashes a button's scene and releases it at once, fading out
+// over the button's flashReleaseTime. A button held by hand is left alone.
+func (r *Resolver) bumpButton(ctx context.Context, button models.SceneBoardButton) error {
+	if state := r.FlashService.State(button.ID); state != nil && state.IsFlashing {
+		return nil
+	}
+
+	intensity, other, err := r.sceneLayerValues(ctx, button.SceneID)
+	if err != nil {
+		return err
+	}
+	level := 1.0
+	if button.FlashLevel != nil {
+		level = *button.FlashLevel
+	}
+	if _, err := r.FlashService.Start(button.ID, button.SceneID, dmx.Flash{Intensity: intensity, Other: other}, level); err != nil {
+		return err
+	}
+
+	releaseTime := bumpReleaseTime
+	if button.FlashReleaseTime != nil && *button.FlashReleaseTime > 0 {
+		releaseTime = time.Duration(*button.FlashReleaseTime * float64(time.Second))
+	}
+	_, err = r.FlashService.End(button.ID, releaseTime)
+	return err
+}
+
+// advanceSceneEffects steps the running effects attached to a scene.
+func (r *Resolver) advanceSceneEffects(ctx context.Context, sceneID string) error {
+	var effectIDs []string
+	if err := r.db.WithContext(ctx).Model(&models.Effect{}).Where("scene_id = ?", sceneID).Pluck("id", &effectIDs).Error; err != nil {
+		return err
+	}
+	for _, effectID := range effectIDs {
+		r.EffectService.Advance(effectID)
+	}
+	return nil
+}
+
+// convertAudioStatus converts an audio.Status to generated.AudioInputStatus.
+func convertAudioStatus(status *audio.Status) *generated.AudioInputStatus {
+	result := &generated.AudioInputStatus{
+		Enabled:     status.Config.Enabled,
+		Source:      generated.AudioSource(status.Config.Source),
+		SampleRate:  status.Config.SampleRate,
+		Channels:    status.Config.Channels,
+		Bands:       make([]*generated.AudioBand, len(status.Bands)),
+		IsListening: status.Listening,
+		Level:       status.Level,
+		LastSource:  status.LastSource,
+		InputError:  status.Error,
+	}
+	if status.Config.Device != "" {
+		device := status.Config.Device
+		result.Device = &device
+	}
+	if status.Config.Port != 0 {
+		port := status.Config.Port
+		result.Port = &port
+	}
+	for i, band := range status.Bands {
+		result.Bands[i] = &generated.AudioBand{
+			Name:        band.Name,
+			LowHz:       band.LowHz,
+			HighHz:      band.HighHz,
+			Threshold:   band.Threshold,
+			MinInterval: band.MinInterval,
+			Level:       band.Level,
+			BeatCount:   band.Beats,
+		}
+		if band.LastBeatAt != nil {
+			lastBeatAt := band.LastBeatAt.Format("2006-01-02T15:04:05.000Z")
+			result.Bands[i].LastBeatAt = &lastBeatAt
+		}
+	}
+	if status.LastInputAt != nil {
+		lastInputAt := status.LastInputAt.Format("2006-01-02T15:04:05.000Z")
+		result.LastInputAt = &lastInputAt
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+)
+
+// auditModels lists the model each audited entity type is stored as.
+var auditModels = map[string]func() interface{}{
+	audit.EntityProject:           func() interface{} { return &models.Project{} },
+	audit.EntityFixtureDefinition: func() interface{} { return &models.FixtureDefinition{} },
+	audit.EntityFixture:           func() interface{} { return &models.FixtureInstance{} },
+	audit.EntityFixtureGroup:      func() interface{} { return &models.FixtureGroup{} },
+	audit.EntityScene:             func() interface{} { return &models.Scene{} },
+	audit.EntitySceneBoard:        func() interface{} { return &models.SceneBoard{} },
+	audit.EntitySceneBoardButton:  func() interface{} { return &models.SceneBoardButton{} },
+	audit.EntityCueList:           func() interface{} { return &models.CueList{} },
+	audit.EntityCue:               func() interface{} { return &models.Cue{} },
+	audit.EntityEffect:            func() interface{} { return &models.Effect{} },
+	audit.EntitySubmaster:         func() interface{} { return &models.Submaster{} },
+	audit.EntityPalette:           func() interface{} { return &models.Palette{} },
+	audit.EntitySnapshot:          func() interface{} { return &models.ProjectSnapshot{} },
+	audit.EntitySchedule:          func() interface{} { return &models.Schedule{} },
+	audit.EntitySoftPatch:         func() interface{} { return &models.SoftPatch{} },
+	audit.EntityUniverse:          func() interface{} { return &models.Universe{} },
+	audit.EntityLayoutZone:        func() interface{} { return &models.LayoutZone{} },
+	audit.EntitySelectionSet:      func() interface{} { return &models.SelectionSet{} },
+	audit.EntityPixelMatrix:       func() interface{} { return &models.PixelMatrix{} },
+}
+
+// loadAuditEntity returns the current state of an audited record and the
+// project it belongs to, or nil if it does not exist or is not stored.
+func (r *Resolver) loadAuditEntity(ctx context.Context, entityType, id string) (*audit.Entity, error) {
+	newModel, ok := auditModels[entityType]
+	if !ok {
+		return nil, nil
+	}
+	model := newModel()
+	query := r.db.WithContext(ctx)
+	if entityType == audit.EntitySnapshot {
+		query = query.Omit("content")
+	}
+	result := query.Limit(1).Find(model, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	state, err := auditState(model)
+	if err != nil {
+		return nil, err
+	}
+	entity := &audit.Entity{State: state}
+
+	switch m := model.(type) {
+	case *models.Project:
+		entity.ProjectID = m.ID
+	case *models.Scene:
+		// A scene's look is its fixture values, which are stored separately
+		values, err := r.SceneRepo.GetFixtureValues(ctx, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		looks := make([]map[string]string, len(values))
+		for i, v := range values {
+			looks[i] = map[string]string{"fixtureId": v.FixtureID, "channels": v.Channels}
+		}
+		state["FixtureValues"] = looks
+		entity.ProjectID = m.ProjectID
+	case *models.Cue:
+		var cueList models.CueList
+		if err := r.db.WithContext(ctx).Select("project_id").Limit(1).Find(&cueList, "id = ?", m.CueListID).Error; err != nil {
+			return nil, err
+		}
+		entity.ProjectID = cueList.ProjectID
+	case *models.SceneBoardButton:
+		var board models.SceneBoard
+		if err := r.db.WithContext(ctx).Select("project_id").Limit(1).Find(&board, "id = ?", m.SceneBoardID).Error; err != nil {
+			return nil, err
+		}
+		entity.ProjectID = board.ProjectID
+	default:
+		if projectID, ok := state["ProjectID"].(string); ok {
+			entity.ProjectID = projectID
+		}
+	}
+
+	// Round-trip the added values so they compare like the rest
+	normalized, err := auditState(state)
+	if err != nil {
+		return nil, err
+	}
+	entity.State = normalized
+	return entity, nil
+}
+
+// auditState converts a record to a field map, leaving out empty fields and
+// unloaded relations.
+func auditState(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	for k, value := range state {
+		if value == nil {
+			delete(state, k)
+		}
+	}
+	return state, nil
+}
+
+// auditLogFilter converts an audit log filter from GraphQL.
+func auditLogFilter(input *generated.AuditLogFilterInput) (repositories.AuditLogFilter, error) {
+	var filter repositories.AuditLogFilter
+	if input == nil {
+		return filter, nil
+	}
+	filter.ProjectID = input.ProjectID.Value()
+	filter.EntityID = input.EntityID.Value()
+	filter.Operator = input.Operator.Value()
+	if entityType := input.EntityType.Value(); entityType != nil {
+		value := string(*entityType)
+		filter.EntityType = &value
+	}
+
+	var err error
+	if filter.Since, err = auditTime("since", input.Since.Value()); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = auditTime("until", input.Until.Value()); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// auditTime parses an RFC 3339 time filter. Entries are stored in local
+// time, so the filter is converted to match.
+func auditTime(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s time %q: expected RFC 3339", name, *value)
+	}
+	t = t.Local()
+	return &t, nil
+}
@@ -0,0 +1,77 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"gorm.io/gorm"
+)
+
+// entityProjectIDs returns the projects that records of one type belong to,
+// for checking the roles a request needs.
+func (r *Resolver) entityProjectIDs(ctx context.Context, entityType string, ids []string) ([]string, error) {
+	db := r.db.WithContext(ctx)
+	var query *gorm.DB
+	switch entityType {
+	case audit.EntityCue:
+		query = db.Model(&models.CueList{}).
+			Where("id IN (?)", db.Model(&models.Cue{}).Select("cue_list_id").Where("id IN ?", ids))
+	case audit.EntitySceneBoardButton:
+		query = db.Model(&models.SceneBoard{}).
+			Where("id IN (?)", db.Model(&models.SceneBoardButton{}).Select("scene_board_id").Where("id IN ?", ids))
+	case audit.EntityFixtureDefinition:
+		// Shared by every project; changing one touches the projects using it
+		query = db.Model(&models.FixtureInstance{}).Where("definition_id IN ?", ids)
+	case auth.EntityInstanceChannel:
+		query = db.Model(&models.FixtureInstance{}).
+			Where("id IN (?)", db.Model(&models.InstanceChannel{}).Select("fixture_id").Where("id IN ?", ids))
+	default:
+		newModel, ok := auditModels[entityType]
+		if !ok {
+			return nil, nil
+		}
+		query = db.Model(newModel()).Where("id IN ?", ids)
+	}
+
+	var projectIDs []string
+	err := query.Distinct("project_id").Pluck("project_id", &projectIDs).Error
+	return projectIDs, err
+}
+
+// checkMembershipChange validates giving a user a role on a project, or
+// removing them when role is empty, so that every project keeps an owner.
+func (r *Resolver) checkMembershipChange(ctx context.Context, projectID, userID, role string) error {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	user, err := r.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	member, err := r.UserRepo.FindMembership(ctx, userID, projectID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.Role != auth.RoleOwner || role == auth.RoleOwner {
+		return nil
+	}
+	owners, err := r.UserRepo.CountOwners(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return fmt.Errorf("project %s must keep at least one owner", project.Name)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/backup"
+)
+
+// EnableBackups keeps backups of every project and the settings in store,
+// up to retention of them; zero keeps them all. Scheduling is left to the
+// caller, through BackupService.Start.
+func (r *Resolver) EnableBackups(store backup.Store, retention int) {
+	r.BackupService = backup.NewService(store, retention, r.ProjectRepo, r.SettingRepo, r.ExportService, r.ImportService)
+}
+
+// backups returns the backup service, or an error when backups are off.
+func (r *Resolver) backups() (*backup.Service, error) {
+	if r.BackupService == nil {
+		return nil, fmt.Errorf("backups are not enabled on this server")
+	}
+	return r.BackupService, nil
+}
+
+// restoreBackup restores a backup, then gives the caller the restored
+// projects and applies the restored settings live.
+func (r *Resolver) restoreBackup(ctx context.Context, name string, restoreSettings bool) (*generated.BackupRestoreResult, error) {
+	service, err := r.backups()
+	if err != nil {
+		return nil, err
+	}
+	restored, err := service.Restore(ctx, name, backup.RestoreOptions{Settings: restoreSettings})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &generated.BackupRestoreResult{
+		Projects:         make([]*generated.ImportResult, len(restored.Projects)),
+		SettingsRestored: len(restored.Settings),
+		Warnings:         restored.Warnings,
+	}
+	for i, project := range restored.Projects {
+		r.AuthService.GrantOwner(ctx, project.ProjectID)
+		result.Projects[i] = importResult(project.ProjectID, project.Stats, project.Warnings)
+	}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	for _, key := range restored.Settings {
+		r.SettingsService.Notify(ctx, key)
+	}
+	r.refreshOutputLimits(ctx)
+	return result, nil
+}
+
+// convertBackup converts a backup's description for GraphQL.
+func convertBackup(info backup.Info) *generated.Backup {
+	return &generated.Backup{
+		Name:      info.Name,
+		Size:      int(info.Size),
+		CreatedAt: info.CreatedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
@@ -0,0 +1,215 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"gorm.io/gorm"
+)
+
+// batchRefPrefix marks an ID that names an entity created earlier in the same batch.
+const batchRefPrefix = "$ref:"
+
+// withTx returns a copy of the resolver whose database access goes through tx.
+func (r *Resolver) withTx(tx *gorm.DB) *Resolver {
+	txResolver := *r
+	txResolver.db = tx
+	txResolver.ProjectRepo = repositories.NewProjectRepository(tx)
+	txResolver.SettingRepo = repositories.NewSettingRepository(tx)
+	txResolver.FixtureRepo = repositories.NewFixtureRepository(tx)
+	txResolver.FixtureGroupRepo = repositories.NewFixtureGroupRepository(tx)
+	txResolver.PaletteRepo = repositories.NewPaletteRepository(tx)
+	txResolver.UndoRepo = repositories.NewUndoRepository(tx)
+	txResolver.SnapshotRepo = repositories.NewSnapshotRepository(tx)
+	txResolver.AuditRepo = repositories.NewAuditRepository(tx)
+	txResolver.UserRepo = repositories.NewUserRepository(tx)
+	txResolver.SceneRepo = repositories.NewSceneRepository(tx)
+	txResolver.CueListRepo = repositories.NewCueListRepository(tx)
+	txResolver.CueRepo = repositories.NewCueRepository(tx)
+	txResolver.SceneBoardRepo = repositories.NewSceneBoardRepository(tx)
+	txResolver.UniverseRepo = repositories.NewUniverseRepository(tx)
+	txResolver.LayoutZoneRepo = repositories.NewLayoutZoneRepository(tx)
+	txResolver.SelectionSetRepo = repositories.NewSelectionSetRepository(tx)
+	txResolver.HouseLightsRepo = repositories.NewHouseLightsRepository(tx)
+	txResolver.PixelMatrixRepo = repositories.NewPixelMatrixRepository(tx)
+	return &txResolver
+}
+
+// transaction runs fn with a resolver whose database access goes through a
+// single transaction. Entity changes and scene updates published inside it
+// are sent once the outermost transaction commits, and dropped if it rolls
+// back, as are timecode trigger reloads.
+func (r *Resolver) transaction(ctx context.Context, fn func(tx *Resolver) error) error {
+	var events *[]pendingEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txResolver := r.withTx(tx)
+		if txResolver.pendingEvents == nil {
+			txResolver.pendingEvents = &[]pendingEvent{}
+		}
+		events = txResolver.pendingEvents
+		return fn(txResolver)
+	})
+	if err != nil {
+		return err
+	}
+	if r.pendingEvents == nil {
+		for _, event := range *events {
+			if event.run != nil {
+				event.run(r)
+				continue
+			}
+			r.PubSub.Publish(event.topic, event.filter, event.message)
+		}
+	}
+	return nil
+}
+
+// executeBatch runs the operations in a single transaction, rolling back
+// everything if any operation fails.
+func (r *Resolver) executeBatch(ctx context.Context, operations []*generated.BatchOperationInput) ([]*generated.BatchOperationResult, error) {
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("batch must contain at least one operation")
+	}
+
+	var results []*generated.BatchOperationResult
+	err := r.transaction(ctx, func(tx *Resolver) error {
+		m := &mutationResolver{tx}
+		refs := make(map[string]string)
+		results = make([]*generated.BatchOperationResult, 0, len(operations))
+
+		for i, op := range operations {
+			var ref *string
+			if op.Ref.IsSet() && op.Ref.Value() != nil && *op.Ref.Value() != "" {
+				ref = op.Ref.Value()
+				if _, exists := refs[*ref]; exists {
+					return fmt.Errorf("operation %d: duplicate ref %q", i, *ref)
+				}
+			}
+
+			kind, id, err := m.runBatchOperation(ctx, op, refs)
+			if err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			if ref != nil {
+				refs[*ref] = id
+			}
+			results = append(results, &generated.BatchOperationResult{
+				Index: i,
+				Ref:   ref,
+				Kind:  kind,
+				ID:    id,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// runBatchOperation executes the single operation set on op and returns the
+// ID of the created entity.
+func (m *mutationResolver) runBatchOperation(ctx context.Context, op *generated.BatchOperationInput, refs map[string]string) (generated.BatchOperationKind, string, error) {
+	var (
+		kind  generated.BatchOperationKind
+		count int
+	)
+	if op.CreateScene.Value() != nil {
+		kind, count = generated.BatchOperationKindCreateScene, count+1
+	}
+	if op.CreateSceneBoard.Value() != nil {
+		kind, count = generated.BatchOperationKindCreateSceneBoard, count+1
+	}
+	if op.AddSceneToBoard.Value() != nil {
+		kind, count = generated.BatchOperationKindAddSceneToBoard, count+1
+	}
+	if op.CreateCueList.Value() != nil {
+		kind, count = generated.BatchOperationKindCreateCueList, count+1
+	}
+	if op.CreateCue.Value() != nil {
+		kind, count = generated.BatchOperationKindCreateCue, count+1
+	}
+	if count != 1 {
+		return "", "", fmt.Errorf("exactly one operation must be set, got %d", count)
+	}
+
+	resolve := func(id string) (string, error) {
+		if !strings.HasPrefix(id, batchRefPrefix) {
+			return id, nil
+		}
+		name := strings.TrimPrefix(id, batchRefPrefix)
+		resolved, ok := refs[name]
+		if !ok {
+			return "", fmt.Errorf("unknown batch ref: %s", name)
+		}
+		return resolved, nil
+	}
+
+	var err error
+	switch kind {
+	case generated.BatchOperationKindCreateScene:
+		input := *op.CreateScene.Value()
+		if input.ProjectID, err = resolve(input.ProjectID); err != nil {
+			return kind, "", err
+		}
+		scene, err := m.CreateScene(ctx, input)
+		if err != nil {
+			return kind, "", err
+		}
+		return kind, scene.ID, nil
+
+	case generated.BatchOperationKindCreateSceneBoard:
+		input := *op.CreateSceneBoard.Value()
+		if input.ProjectID, err = resolve(input.ProjectID); err != nil {
+			return kind, "", err
+		}
+		board, err := m.CreateSceneBoard(ctx, input)
+		if err != nil {
+			return kind, "", err
+		}
+		return kind, board.ID, nil
+
+	case generated.BatchOperationKindAddSceneToBoard:
+		input := *op.AddSceneToBoard.Value()
+		if input.SceneBoardID, err = resolve(input.SceneBoardID); err != nil {
+			return kind, "", err
+		}
+		if input.SceneID, err = resolve(input.SceneID); err != nil {
+			return kind, "", err
+		}
+		button, err := m.AddSceneToBoard(ctx, input)
+		if err != nil {
+			return kind, "", err
+		}
+		return kind, button.ID, nil
+
+	case generated.BatchOperationKindCreateCueList:
+		input := *op.CreateCueList.Value()
+		if input.ProjectID, err = resolve(input.ProjectID); err != nil {
+			return kind, "", err
+		}
+		cueList, err := m.CreateCueList(ctx, input)
+		if err != nil {
+			return kind, "", err
+		}
+		return kind, cueList.ID, nil
+
+	default:
+		input := *op.CreateCue.Value()
+		if input.CueListID, err = resolve(input.CueListID); err != nil {
+			return kind, "", err
+		}
+		if input.SceneID, err = resolve(input.SceneID); err != nil {
+			return kind, "", err
+		}
+		cue, err := m.CreateCue(ctx, input)
+		if err != nil {
+			return kind, "", err
+		}
+		return kind, cue.ID, nil
+	}
+}
@@ -0,0 +1,97 @@
+package resolvers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// journalKindBlackout is the journal kind recording an active blackout, so a
+// restart during a blackout comes back dark.
+const journalKindBlackout = "blackout"
+
+const journalKeyBlackout = "active"
+
+// blackoutFade converts an optional fade time in seconds.
+func blackoutFade(fadeTime *float64) time.Duration {
+	if fadeTime == nil {
+		return 0
+	}
+	return time.Duration(*fadeTime * float64(time.Second))
+}
+
+// setBlackout blacks out or restores, then journals and publishes the change.
+func (r *Resolver) setBlackout(active bool, fadeTime *float64) (*generated.BlackoutStatus, error) {
+	if fadeTime != nil && math.IsNaN(*fadeTime) {
+		return nil, fmt.Errorf("fadeTime must be a number")
+	}
+
+	var status dmx.BlackoutStatus
+	var err error
+	if active {
+		status, err = r.DMXService.Blackout(blackoutFade(fadeTime))
+	} else {
+		status, err = r.DMXService.RestoreFromBlackout(blackoutFade(fadeTime))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StateJournal != nil {
+		if status.Active {
+			err = r.StateJournal.Put(journalKindBlackout, journalKeyBlackout, status.Since.UTC())
+		} else {
+			err = r.StateJournal.Delete(journalKindBlackout, journalKeyBlackout)
+		}
+		if err != nil {
+			log.Warn("failed to journal blackout", "error", err)
+		}
+	}
+	if status.Active {
+		r.replicate(journalKindBlackout, journalKeyBlackout, status.Since.UTC())
+	} else {
+		r.replicate(journalKindBlackout, journalKeyBlackout, nil)
+	}
+
+	gqlStatus := convertBlackoutStatus(status)
+	r.PubSub.Publish(pubsub.TopicBlackout, "", gqlStatus)
+	return gqlStatus, nil
+}
+
+// restoreBlackout blacks out immediately if the journal recorded an active
+// blackout, reporting whether it did.
+func (r *Resolver) restoreBlackout(j *journal.Journal) bool {
+	var since time.Time
+	found, err := j.Get(journalKindBlackout, journalKeyBlackout, &since)
+	if err != nil {
+		log.Warn("failed to read the journaled blackout", "error", err)
+	}
+	if !found {
+		return false
+	}
+	if _, err := r.DMXService.Blackout(0); err != nil {
+		log.Warn("cannot restore blackout", "error", err)
+		return false
+	}
+	log.Info("⬛ Restored blackout from the state journal", "since", since.Format(time.RFC3339))
+	return true
+}
+
+// convertBlackoutStatus converts a blackout status to the GraphQL type.
+func convertBlackoutStatus(status dmx.BlackoutStatus) *generated.BlackoutStatus {
+	result := &generated.BlackoutStatus{
+		IsBlackout: status.Active,
+		Level:      status.Level,
+		IsFading:   status.Fading,
+	}
+	if status.Since != nil {
+		since := status.Since.UTC().Format("2006-01-02T15:04:05.000Z")
+		result.Since = &since
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/patch"
+)
+
+// capturedValue is a fixture's channel values read from the output.
+type capturedValue struct {
+	fixtureID string
+	channels  []models.ChannelValue
+}
+
+// captureFixtureValues reads a project's fixtures' channel values from the
+// live output, or from a preview session's view of it. Without fixtureIDs,
+// fixtures with every channel at zero are left out.
+func (r *Resolver) captureFixtureValues(ctx context.Context, projectID string, sessionID *string, fixtureIDs []string) ([]capturedValue, error) {
+	universeChannels := r.DMXService.GetUniverse
+	if sessionID != nil {
+		session := r.PreviewService.GetSession(*sessionID)
+		if session == nil || session.ProjectID != projectID {
+			return nil, fmt.Errorf("preview session not found: %s", *sessionID)
+		}
+		universeChannels = func(universe int) []int {
+			return r.PreviewService.GetUniverseChannels(*sessionID, universe)
+		}
+	}
+
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(fixtureIDs))
+	for _, id := range fixtureIDs {
+		selected[id] = true
+	}
+
+	universes := make(map[int][]int)
+	var captured []capturedValue
+	for i := range fixtures {
+		fixture := patch.FromInstance(&fixtures[i])
+		if fixtureIDs != nil && !selected[fixture.ID] {
+			continue
+		}
+		output, ok := universes[fixture.Universe]
+		if !ok {
+			output = universeChannels(fixture.Universe)
+			universes[fixture.Universe] = output
+		}
+
+		channels := make([]models.ChannelValue, 0, fixture.ChannelCount)
+		lit := false
+		for offset := 0; offset < fixture.ChannelCount; offset++ {
+			channel := fixture.StartChannel + offset
+			if channel < 1 || channel > dmx.UniverseSize {
+				continue
+			}
+			value := output[channel-1]
+			lit = lit || value > 0
+			channels = append(channels, models.ChannelValue{Offset: offset, Value: value})
+		}
+		if fixtureIDs == nil && !lit {
+			continue
+		}
+		captured = append(captured, capturedValue{fixtureID: fixture.ID, channels: channels})
+	}
+	return captured, nil
+}
+
+// captureActiveOutput captures the output into a new scene, or merges it
+// into an existing one.
+func (r *Resolver) captureActiveOutput(ctx context.Context, projectID string, sceneID, name, sessionID *string, fixtureIDs []string) (*models.Scene, error) {
+	if err := r.validateCaptureTarget(ctx, projectID, sceneID, name); err != nil {
+		return nil, err
+	}
+	captured, err := r.captureFixtureValues(ctx, projectID, sessionID, fixtureIDs)
+	if err != nil {
+		return nil, err
+	}
+	return r.saveCapturedScene(ctx, projectID, sceneID, name, "Capture", captured)
+}
+
+// validateCaptureTarget checks the project, and that a new scene is named.
+func (r *Resolver) validateCaptureTarget(ctx context.Context, projectID string, sceneID, name *string) error {
+	if sceneID == nil && (name == nil || *name == "") {
+		return fmt.Errorf("name is required to capture into a new scene")
+	}
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	return nil
+}
+
+// saveCapturedScene saves captured values as a new scene called name, or
+// merges them by channel into the project's scene sceneID. action names the
+// edit in the undo history.
+func (r *Resolver) saveCapturedScene(ctx context.Context, projectID string, sceneID, name *string, action string, captured []capturedValue) (*models.Scene, error) {
+	if sceneID == nil {
+		values := make([]models.FixtureValue, len(captured))
+		for i, c := range captured {
+			channelsJSON, err := json.Marshal(c.channels)
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize channels: %w", err)
+			}
+			values[i] = models.FixtureValue{FixtureID: c.fixtureID, Channels: string(channelsJSON)}
+		}
+		scene := &models.Scene{Name: *name, ProjectID: projectID}
+		if err := r.SceneRepo.CreateWithFixtureValues(ctx, scene, values); err != nil {
+			return nil, err
+		}
+		r.commitUndoCreate(ctx, projectID, action+" scene "+scene.Name, undoScene(scene.ID))
+		r.publishEntityChange(ctx, generated.EntityChangeActionCreated, scene)
+		return scene, nil
+	}
+
+	scene, err := r.SceneRepo.FindByID(ctx, *sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil || scene.ProjectID != projectID {
+		return nil, fmt.Errorf("scene not found: %s", *sceneID)
+	}
+	undo, err := r.beginUndo(ctx, projectID, action+" into scene "+scene.Name, undoScene(scene.ID))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range captured {
+		if err := r.mergeSceneChannels(ctx, scene.ID, c.fixtureID, c.channels); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.SceneRepo.SyncGroupValues(ctx, scene.ID); err != nil {
+		return nil, err
+	}
+
+	if err := r.reapplyActiveSceneIfNeeded(ctx, scene.ID); err != nil {
+		log.Warn("failed to re-apply active scene after capture", "error", err)
+	}
+	r.refreshSubmasters(ctx)
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
+
+	return scene, nil
+}
@@ -0,0 +1,112 @@
+package resolvers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// maxCells bounds the cells of a multi-cell fixture, one per DMX channel.
+const maxCells = 512
+
+// validateChannelCells checks that a definition's cells are numbered from
+// 0 without gaps.
+func validateChannelCells(channels []*generated.CreateChannelDefinitionInput) error {
+	used := make(map[int]bool)
+	for _, ch := range channels {
+		cell := ch.Cell.Value()
+		if cell == nil {
+			continue
+		}
+		if *cell < 0 || *cell >= maxCells {
+			return fmt.Errorf("channel %s: cell must be between 0 and %d", ch.Name, maxCells-1)
+		}
+		used[*cell] = true
+	}
+	for cell := range used {
+		if cell > 0 && !used[cell-1] {
+			return fmt.Errorf("cells must be numbered from 0 without gaps: no channel is in cell %d", cell-1)
+		}
+	}
+	return nil
+}
+
+// cellName names a cell for display.
+func cellName(index int) string {
+	return fmt.Sprintf("Cell %d", index+1)
+}
+
+// sortedCells returns the cell indexes channels belong to, in order.
+func sortedCells(cells map[int]bool) []int {
+	indexes := make([]int, 0, len(cells))
+	for index := range cells {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// definitionCells groups a definition's channels into its cells.
+func definitionCells(channels []*models.ChannelDefinition) []*generated.FixtureCell {
+	byCell := make(map[int][]*models.ChannelDefinition)
+	used := make(map[int]bool)
+	for _, ch := range channels {
+		if ch.Cell != nil {
+			byCell[*ch.Cell] = append(byCell[*ch.Cell], ch)
+			used[*ch.Cell] = true
+		}
+	}
+	cells := []*generated.FixtureCell{}
+	for _, index := range sortedCells(used) {
+		cells = append(cells, &generated.FixtureCell{Index: index, Name: cellName(index), Channels: byCell[index]})
+	}
+	return cells
+}
+
+// instanceCells groups a fixture's channels into its cells.
+func instanceCells(channels []*models.InstanceChannel) []*generated.FixtureInstanceCell {
+	byCell := make(map[int][]*models.InstanceChannel)
+	used := make(map[int]bool)
+	for _, ch := range channels {
+		if ch.Cell != nil {
+			byCell[*ch.Cell] = append(byCell[*ch.Cell], ch)
+			used[*ch.Cell] = true
+		}
+	}
+	cells := []*generated.FixtureInstanceCell{}
+	for _, index := range sortedCells(used) {
+		cells = append(cells, &generated.FixtureInstanceCell{Index: index, Name: cellName(index), Channels: byCell[index]})
+	}
+	return cells
+}
+
+// masterChannels returns a fixture's channels that belong to no cell.
+func masterChannels(channels []*models.InstanceChannel) []*models.InstanceChannel {
+	result := []*models.InstanceChannel{}
+	for _, ch := range channels {
+		if ch.Cell == nil {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// cellChannels returns a fixture's channels in one cell; every channel when
+// cell is nil.
+func cellChannels(fixture *models.FixtureInstance, cell *int) ([]models.InstanceChannel, error) {
+	if cell == nil {
+		return fixture.Channels, nil
+	}
+	var channels []models.InstanceChannel
+	for _, ch := range fixture.Channels {
+		if ch.Cell != nil && *ch.Cell == *cell {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("fixture %s has no cell %d", fixture.Name, *cell)
+	}
+	return channels, nil
+}
@@ -0,0 +1,105 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// loadChannelLimits applies the saved output channel limits, if any.
+func (r *Resolver) loadChannelLimits(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.ChannelLimitsSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var limits []dmx.ChannelLimit
+	if err := json.Unmarshal([]byte(setting.Value), &limits); err != nil {
+		log.Warn("invalid saved channel limits", "error", err)
+		return
+	}
+	if err := r.DMXService.SetChannelLimits(limits); err != nil {
+		log.Warn("invalid saved channel limits", "error", err)
+	}
+}
+
+// updateChannelLimits applies and saves the channel limits.
+func (r *Resolver) updateChannelLimits(ctx context.Context, limits []dmx.ChannelLimit) ([]*generated.ChannelLimit, error) {
+	if limits == nil {
+		limits = []dmx.ChannelLimit{}
+	}
+	// Validate before touching live output or the saved limits
+	if err := dmx.ValidateChannelLimits(limits); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(limits)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, dmx.ChannelLimitsSettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save channel limits: %w", err)
+	}
+	if err := r.DMXService.SetChannelLimits(limits); err != nil {
+		return nil, err
+	}
+	return convertChannelLimits(r.DMXService.ChannelLimits()), nil
+}
+
+// replaceChannelLimit returns the current channel limits with one channel's
+// limit replaced, or removed when limit is nil.
+func (r *Resolver) replaceChannelLimit(universe, channel int, limit *dmx.ChannelLimit) []dmx.ChannelLimit {
+	limits := make([]dmx.ChannelLimit, 0)
+	for _, l := range r.DMXService.ChannelLimits() {
+		if l.Universe != universe || l.Channel != channel {
+			limits = append(limits, l)
+		}
+	}
+	if limit != nil {
+		limits = append(limits, *limit)
+	}
+	return limits
+}
+
+// channelLimitFromInput converts a generated.ChannelLimitInput to a
+// dmx.ChannelLimit, defaulting to a linear channel at full.
+func channelLimitFromInput(in *generated.ChannelLimitInput) dmx.ChannelLimit {
+	limit := dmx.ChannelLimit{
+		Universe: in.Universe,
+		Channel:  in.Channel,
+		MaxLevel: 1,
+		Curve:    dmx.CurveLinear,
+	}
+	if v := in.MaxLevel.Value(); v != nil {
+		limit.MaxLevel = *v
+	}
+	if v := in.Inhibit.Value(); v != nil {
+		limit.Inhibit = *v
+	}
+	if v := in.Curve.Value(); v != nil {
+		limit.Curve = dmx.Curve(*v)
+	}
+	return limit
+}
+
+// convertChannelLimits converts dmx.ChannelLimit values to generated.ChannelLimit.
+func convertChannelLimits(limits []dmx.ChannelLimit) []*generated.ChannelLimit {
+	result := make([]*generated.ChannelLimit, 0, len(limits))
+	for _, limit := range limits {
+		curve := generated.OutputCurveLinear
+		if limit.Curve != "" {
+			curve = generated.OutputCurve(limit.Curve)
+		}
+		result = append(result, &generated.ChannelLimit{
+			Universe: limit.Universe,
+			Channel:  limit.Channel,
+			MaxLevel: limit.MaxLevel,
+			Inhibit:  limit.Inhibit,
+			Curve:    curve,
+		})
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/channelvalue"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// channelValueCode marks channel value range errors in their extensions.
+const channelValueCode = "CHANNEL_VALUE_OUT_OF_RANGE"
+
+// channelValueMode returns the saved channel value validation mode.
+func (r *Resolver) channelValueMode(ctx context.Context) channelvalue.Mode {
+	setting, err := r.SettingRepo.FindByKey(ctx, channelvalue.SettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return channelvalue.DefaultMode
+	}
+	mode, err := channelvalue.ParseMode(setting.Value)
+	if err != nil {
+		log.Warn("invalid saved channel value validation mode", "value", setting.Value)
+		return channelvalue.DefaultMode
+	}
+	return mode
+}
+
+// channelValueFixtures loads the channel ranges of fixtures by ID. Fixtures
+// that do not exist are left out.
+func (r *Resolver) channelValueFixtures(ctx context.Context, fixtureIDs []string) (map[string]channelvalue.Fixture, error) {
+	var fixtures []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures).Error; err != nil {
+		return nil, err
+	}
+	return r.channelValueRanges(ctx, fixtures)
+}
+
+// channelValueRanges describes the channels of fixtures, with the
+// capabilities of their discrete channels taken from their definitions.
+func (r *Resolver) channelValueRanges(ctx context.Context, fixtures []models.FixtureInstance) (map[string]channelvalue.Fixture, error) {
+	ranges, err := r.discreteChannelRanges(ctx, fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]channelvalue.Fixture, len(fixtures))
+	for _, fixture := range fixtures {
+		channels := make(map[int]channelvalue.Channel, len(fixture.Channels))
+		for _, ch := range fixture.Channels {
+			channel := channelvalue.Channel{
+				Name:     ch.Name,
+				Min:      ch.MinValue,
+				Max:      ch.MaxValue,
+				Discrete: ch.IsDiscrete,
+			}
+			if ch.IsDiscrete {
+				channel.Ranges = ranges[fixture.DefinitionID][ch.Name]
+			}
+			channels[ch.Offset] = channel
+		}
+		result[fixture.ID] = channelvalue.Fixture{ID: fixture.ID, Name: fixture.Name, Channels: channels}
+	}
+	return result, nil
+}
+
+// discreteChannelRanges returns the capabilities of the fixtures' discrete
+// channels by definition and channel name, which instance channels are
+// copied with.
+func (r *Resolver) discreteChannelRanges(ctx context.Context, fixtures []models.FixtureInstance) (map[string]map[string][]channelvalue.Range, error) {
+	var definitionIDs []string
+	seen := make(map[string]bool)
+	for _, fixture := range fixtures {
+		for _, ch := range fixture.Channels {
+			if ch.IsDiscrete && !seen[fixture.DefinitionID] {
+				seen[fixture.DefinitionID] = true
+				definitionIDs = append(definitionIDs, fixture.DefinitionID)
+			}
+		}
+	}
+	if len(definitionIDs) == 0 {
+		return nil, nil
+	}
+
+	var channels []models.ChannelDefinition
+	err := r.db.WithContext(ctx).Where("definition_id IN ? AND is_discrete = ?", definitionIDs, true).Find(&channels).Error
+	if err != nil || len(channels) == 0 {
+		return nil, err
+	}
+	channelIDs := make([]string, len(channels))
+	for i, ch := range channels {
+		channelIDs[i] = ch.ID
+	}
+	var capabilities []models.ChannelCapability
+	if err := r.db.WithContext(ctx).Where("channel_id IN ?", channelIDs).Find(&capabilities).Error; err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string][]channelvalue.Range)
+	for _, capability := range capabilities {
+		byChannel[capability.ChannelID] = append(byChannel[capability.ChannelID],
+			channelvalue.Range{Min: capability.MinValue, Max: capability.MaxValue})
+	}
+
+	result := make(map[string]map[string][]channelvalue.Range)
+	for _, ch := range channels {
+		if len(byChannel[ch.ID]) == 0 {
+			continue
+		}
+		if result[ch.DefinitionID] == nil {
+			result[ch.DefinitionID] = make(map[string][]channelvalue.Range)
+		}
+		result[ch.DefinitionID][ch.Name] = byChannel[ch.ID]
+	}
+	return result, nil
+}
+
+// checkFixtureValues checks fixture values against their channels' ranges,
+// clamping or rejecting those out of range as the saved mode says.
+func (r *Resolver) checkFixtureValues(ctx context.Context, fixtureValues []*generated.FixtureValueInput) error {
+	mode := r.channelValueMode(ctx)
+	if mode == channelvalue.ModeOff || len(fixtureValues) == 0 {
+		return nil
+	}
+	fixtureIDs := make([]string, len(fixtureValues))
+	for i, fv := range fixtureValues {
+		fixtureIDs[i] = fv.FixtureID
+	}
+	fixtures, err := r.channelValueFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return err
+	}
+
+	var violations []channelvalue.Violation
+	for _, fv := range fixtureValues {
+		fixture, ok := fixtures[fv.FixtureID]
+		if !ok {
+			continue
+		}
+		for _, ch := range fv.Channels {
+			violation, ok := fixture.Check(ch.Offset, ch.Value)
+			if ok {
+				continue
+			}
+			if mode == channelvalue.ModeClamp {
+				ch.Value = violation.Nearest
+				continue
+			}
+			violations = append(violations, violation)
+		}
+	}
+	if len(violations) > 0 {
+		return channelValueError(ctx, &channelvalue.Error{Violations: violations})
+	}
+	return nil
+}
+
+// checkProgrammerValue checks a value set on a DMX address against the
+// channels of the fixtures patched there, returning the value to set.
+func (r *Resolver) checkProgrammerValue(ctx context.Context, universe, channel, value int) (int, error) {
+	mode := r.channelValueMode(ctx)
+	if mode == channelvalue.ModeOff {
+		return value, nil
+	}
+	var fixtures []models.FixtureInstance
+	err := r.db.WithContext(ctx).Preload("Channels").
+		Where("universe = ? AND start_channel <= ?", universe, channel).Find(&fixtures).Error
+	if err != nil {
+		return 0, err
+	}
+	ranges, err := r.channelValueRanges(ctx, fixtures)
+	if err != nil {
+		return 0, err
+	}
+
+	var violations []channelvalue.Violation
+	for _, fixture := range fixtures {
+		violation, ok := ranges[fixture.ID].Check(channel-fixture.StartChannel, value)
+		if ok {
+			continue
+		}
+		if mode == channelvalue.ModeClamp {
+			return violation.Nearest, nil
+		}
+		violations = append(violations, violation)
+	}
+	if len(violations) > 0 {
+		return 0, channelValueError(ctx, &channelvalue.Error{Violations: violations})
+	}
+	return value, nil
+}
+
+// channelValueError gives values out of range their details as error
+// extensions so clients can point at the channels.
+func channelValueError(ctx context.Context, err error) error {
+	var rangeErr *channelvalue.Error
+	if !errors.As(err, &rangeErr) {
+		return err
+	}
+	violations := make([]map[string]interface{}, len(rangeErr.Violations))
+	for i, v := range rangeErr.Violations {
+		lo, hi := v.Channel.Span()
+		violations[i] = map[string]interface{}{
+			"fixtureId":    v.FixtureID,
+			"fixtureName":  v.FixtureName,
+			"offset":       v.Offset,
+			"channelName":  v.Channel.Name,
+			"value":        v.Value,
+			"minValue":     lo,
+			"maxValue":     hi,
+			"isDiscrete":   v.Channel.Discrete,
+			"nearestValue": v.Nearest,
+		}
+	}
+	return &gqlerror.Error{
+		Err:     err,
+		Message: err.Error(),
+		Path:    graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{
+			"code":       channelValueCode,
+			"violations": violations,
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+	"github.com/bbernstein/lacylights-go/internal/services/playback"
+)
+
+// parseColorInput converts an abstract color, given as exactly one of hex,
+// HSV, or color temperature.
+func parseColorInput(input *generated.ColorInput) (color.Color, error) {
+	var (
+		c   color.Color
+		err error
+		set int
+	)
+	if input.Hex.IsSet() && input.Hex.Value() != nil {
+		set++
+		c, err = color.ParseHex(*input.Hex.Value())
+	}
+	if input.Hsv.IsSet() && input.Hsv.Value() != nil {
+		set++
+		hsv := input.Hsv.Value()
+		c, err = color.FromHSV(hsv.H, hsv.S, hsv.V)
+	}
+	if input.Kelvin.IsSet() && input.Kelvin.Value() != nil {
+		set++
+		c, err = color.FromKelvin(*input.Kelvin.Value())
+	}
+	if set != 1 {
+		return color.Color{}, fmt.Errorf("color must set exactly one of hex, hsv, or kelvin")
+	}
+	return c, err
+}
+
+// colorFixtures loads fixtures with their channels by ID.
+func (r *Resolver) colorFixtures(ctx context.Context, fixtureIDs []string) (map[string]*models.FixtureInstance, error) {
+	var fixtures []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.FixtureInstance, len(fixtures))
+	for i := range fixtures {
+		byID[fixtures[i].ID] = &fixtures[i]
+	}
+	for _, id := range fixtureIDs {
+		if byID[id] == nil {
+			return nil, fmt.Errorf("fixture not found: %s", id)
+		}
+	}
+	return byID, nil
+}
+
+// fixtureColorValues maps a color onto a fixture's color channels, by the
+// color model their channel types make up.
+func fixtureColorValues(fixtureChannels []models.InstanceChannel, c color.Color) []color.Value {
+	channels := make([]color.Channel, len(fixtureChannels))
+	for i, ch := range fixtureChannels {
+		channels[i] = color.Channel{Offset: ch.Offset, Type: ch.Type}
+	}
+	return c.ChannelValues(channels)
+}
+
+// applyFixtureColors fills in the color channels of fixture values given a
+// color or cell colors, keeping the channels they set themselves. Cell
+// colors override the fixture's color within their cells.
+func (r *Resolver) applyFixtureColors(ctx context.Context, fixtureValues []*generated.FixtureValueInput) error {
+	var fixtureIDs []string
+	for _, fv := range fixtureValues {
+		if (fv.Color.IsSet() && fv.Color.Value() != nil) || len(fv.CellColors.Value()) > 0 {
+			fixtureIDs = append(fixtureIDs, fv.FixtureID)
+		}
+	}
+	if len(fixtureIDs) == 0 {
+		return nil
+	}
+
+	fixtures, err := r.colorFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return err
+	}
+	for _, fv := range fixtureValues {
+		fixture := fixtures[fv.FixtureID]
+		if fixture == nil {
+			continue
+		}
+		values := make(map[int]int)
+		if fv.Color.IsSet() && fv.Color.Value() != nil {
+			c, err := parseColorInput(fv.Color.Value())
+			if err != nil {
+				return fmt.Errorf("fixture %s: %w", fv.FixtureID, err)
+			}
+			for _, v := range fixtureColorValues(fixture.Channels, c) {
+				values[v.Offset] = v.Value
+			}
+		}
+		for _, cc := range fv.CellColors.Value() {
+			c, err := parseColorInput(&cc.Color)
+			if err != nil {
+				return fmt.Errorf("fixture %s cell %d: %w", fv.FixtureID, cc.Cell, err)
+			}
+			channels, err := cellChannels(fixture, &cc.Cell)
+			if err != nil {
+				return err
+			}
+			for _, v := range fixtureColorValues(channels, c) {
+				values[v.Offset] = v.Value
+			}
+		}
+
+		explicit := make(map[int]bool, len(fv.Channels))
+		for _, ch := range fv.Channels {
+			explicit[ch.Offset] = true
+		}
+		for _, ch := range fixture.Channels {
+			if value, ok := values[ch.Offset]; ok && !explicit[ch.Offset] {
+				fv.Channels = append(fv.Channels, &generated.ChannelValueInput{Offset: ch.Offset, Value: value})
+			}
+		}
+	}
+	return nil
+}
+
+// setFixtureColor sets fixtures' color channels in the programmer from an
+// abstract color, in one cell of each fixture when cell is set.
+func (r *Resolver) setFixtureColor(ctx context.Context, fixtureIDs []string, input generated.ColorInput, cell *int) ([]*generated.FixtureColorValues, error) {
+	c, err := parseColorInput(&input)
+	if err != nil {
+		return nil, err
+	}
+	fixtures, err := r.colorFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	channelsByFixture := make(map[string][]models.InstanceChannel, len(fixtureIDs))
+	for _, id := range fixtureIDs {
+		if channelsByFixture[id], err = cellChannels(fixtures[id], cell); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*generated.FixtureColorValues, 0, len(fixtureIDs))
+	for _, id := range fixtureIDs {
+		fixture := fixtures[id]
+		result := &generated.FixtureColorValues{FixtureID: id, Channels: []*models.ChannelValue{}}
+		for _, v := range fixtureColorValues(channelsByFixture[id], c) {
+			channel := fixture.StartChannel + v.Offset
+			if channel < 1 || channel > 512 {
+				continue
+			}
+			r.DMXService.SetProgrammerValue(fixture.Universe, channel, byte(v.Value))
+			r.PlaybackService.RecordEvent(playback.Event{Type: playback.EventSetChannel, Universe: fixture.Universe, Channel: channel, Value: v.Value})
+			result.Channels = append(result.Channels, &models.ChannelValue{Offset: v.Offset, Value: v.Value})
+		}
+		results = append(results, result)
+	}
+	r.replicateProgrammer()
+	return results, nil
+}
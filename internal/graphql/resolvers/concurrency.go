@@ -0,0 +1,163 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// editConflictCode marks updates refused because another client changed the
+// record first.
+const editConflictCode = "EDIT_CONFLICT"
+
+// formatUpdatedAt formats an updatedAt as the API returns it.
+func formatUpdatedAt(updatedAt time.Time) string {
+	return updatedAt.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// changedSince reports whether a record last updated at updatedAt has
+// changed since the updatedAt a client expects, if it gave one.
+func changedSince(updatedAt time.Time, expectedUpdatedAt *string) bool {
+	return expectedUpdatedAt != nil && formatUpdatedAt(updatedAt) != *expectedUpdatedAt
+}
+
+// writeUnchanged runs write in a transaction. When the client gave an
+// expectedUpdatedAt, the transaction first claims the record with an update
+// conditional on updatedAt, the version checked against it, so that of two
+// updates expecting the same version the second is refused with the record
+// as the first left it.
+func (r *Resolver) writeUnchanged(ctx context.Context, entityType generated.ProjectEntityType, id string, updatedAt time.Time, expectedUpdatedAt *string, write func(tx *Resolver) error) error {
+	return r.transaction(ctx, func(tx *Resolver) error {
+		if expectedUpdatedAt != nil {
+			var model interface{}
+			switch entityType {
+			case generated.ProjectEntityTypeScene:
+				model = &models.Scene{}
+			case generated.ProjectEntityTypeCue:
+				model = &models.Cue{}
+			case generated.ProjectEntityTypeFixture:
+				model = &models.FixtureInstance{}
+			default:
+				return fmt.Errorf("no edit conflicts for %s", entityType)
+			}
+			// Compare instants, not text: the stored updated_at carries the
+			// offset of the zone the server wrote it in
+			result := tx.db.WithContext(ctx).Model(model).
+				Where("id = ? AND julianday(updated_at) = julianday(?)", id, updatedAt.UTC()).
+				UpdateColumn("updated_at", time.Now().UTC())
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return tx.latestConflict(ctx, entityType, id)
+			}
+		}
+		return write(tx)
+	})
+}
+
+// latestConflict refuses an update to a record that another update changed
+// first, with the record as it is now.
+func (r *Resolver) latestConflict(ctx context.Context, entityType generated.ProjectEntityType, id string) error {
+	entity, err := r.findEntity(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	switch e := entity.(type) {
+	case *models.Scene:
+		return r.sceneConflict(ctx, e)
+	case *models.Cue:
+		return cueConflict(ctx, e)
+	case *models.FixtureInstance:
+		return fixtureConflict(ctx, e)
+	}
+	return fmt.Errorf("%s not found: %s", strings.ToLower(string(entityType)), id)
+}
+
+// editConflictError refuses an update to a record that changed since the
+// client read it, giving the record's latest state as error extensions so
+// the client can merge or retry.
+func editConflictError(ctx context.Context, kind, id string, updatedAt time.Time, latest map[string]interface{}) error {
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("%s %s was changed by another client at %s", kind, id, formatUpdatedAt(updatedAt)),
+		Path:    graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{
+			"code":      editConflictCode,
+			"kind":      kind,
+			"id":        id,
+			"updatedAt": formatUpdatedAt(updatedAt),
+			"latest":    latest,
+		},
+	}
+}
+
+// sceneConflict refuses an update to a scene, with its fields and fixture
+// values as they are now.
+func (r *Resolver) sceneConflict(ctx context.Context, scene *models.Scene) error {
+	values, err := r.SceneRepo.GetFixtureValues(ctx, scene.ID)
+	if err != nil {
+		return err
+	}
+	fixtureValues := make([]map[string]interface{}, len(values))
+	for i, fv := range values {
+		var channels []models.ChannelValue
+		if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
+			return fmt.Errorf("failed to deserialize channels: %w", err)
+		}
+		fixtureValues[i] = map[string]interface{}{
+			"fixtureId": fv.FixtureID,
+			"channels":  channels,
+		}
+	}
+	return editConflictError(ctx, "scene", scene.ID, scene.UpdatedAt, map[string]interface{}{
+		"id":             scene.ID,
+		"name":           scene.Name,
+		"secondaryLabel": scene.SecondaryLabel,
+		"description":    scene.Description,
+		"defaultFadeIn":  scene.DefaultFadeIn,
+		"defaultFadeOut": scene.DefaultFadeOut,
+		"fixtureValues":  fixtureValues,
+		"updatedAt":      formatUpdatedAt(scene.UpdatedAt),
+	})
+}
+
+// cueConflict refuses an update to a cue, with its fields as they are now.
+func cueConflict(ctx context.Context, cue *models.Cue) error {
+	return editConflictError(ctx, "cue", cue.ID, cue.UpdatedAt, map[string]interface{}{
+		"id":             cue.ID,
+		"name":           cue.Name,
+		"secondaryLabel": cue.SecondaryLabel,
+		"cueNumber":      cue.CueNumber,
+		"sceneId":        cue.SceneID,
+		"fadeInTime":     cue.FadeInTime,
+		"fadeOutTime":    cue.FadeOutTime,
+		"followTime":     cue.FollowTime,
+		"notes":          cue.Notes,
+		"block":          cue.Block,
+		"moveInBlack":    cue.MoveInBlack,
+		"updatedAt":      formatUpdatedAt(cue.UpdatedAt),
+	})
+}
+
+// fixtureConflict refuses an update to a fixture, with its fields as they
+// are now.
+func fixtureConflict(ctx context.Context, fixture *models.FixtureInstance) error {
+	return editConflictError(ctx, "fixture", fixture.ID, fixture.UpdatedAt, map[string]interface{}{
+		"id":             fixture.ID,
+		"name":           fixture.Name,
+		"description":    fixture.Description,
+		"universe":       fixture.Universe,
+		"startChannel":   fixture.StartChannel,
+		"layoutX":        fixture.LayoutX,
+		"layoutY":        fixture.LayoutY,
+		"layoutRotation": fixture.LayoutRotation,
+		"updatedAt":      formatUpdatedAt(fixture.UpdatedAt),
+	})
+}
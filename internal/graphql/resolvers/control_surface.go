@@ -0,0 +1,145 @@
+package resolvers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+)
+
+// controlSurfaceInterval is how often control surface state is checked for
+// changes no topic announces, such as a scene activated on its own.
+const controlSurfaceInterval = 250 * time.Millisecond
+
+// controlSurfaceTopics announce changes to control surface state.
+var controlSurfaceTopics = []pubsub.Topic{
+	pubsub.TopicGlobalPlaybackStatus,
+	pubsub.TopicCueListPlayback,
+	pubsub.TopicSceneBoardState,
+	pubsub.TopicMasterLevel,
+	pubsub.TopicBlackout,
+}
+
+// controlSurfaceState returns what is live, for control surfaces to light
+// their buttons from.
+func (r *Resolver) controlSurfaceState(ctx context.Context) (*generated.ControlSurfaceState, error) {
+	state := &generated.ControlSurfaceState{
+		LiveSceneIds: []string{},
+		CueLists:     []*generated.ControlSurfaceCueList{},
+		LitButtonIds: []string{},
+		GrandMaster:  r.DMXService.MasterLevels().GrandMaster,
+		IsBlackout:   r.DMXService.BlackoutStatus().Active,
+	}
+	if sceneID := r.DMXService.GetActiveSceneID(); sceneID != nil {
+		id := *sceneID
+		state.ActiveSceneID = &id
+	}
+
+	// Playback ID -> live scene
+	live := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, e := range r.StackService.Entries() {
+		live[e.PlaybackID] = e.SceneID
+		if !seen[e.SceneID] {
+			seen[e.SceneID] = true
+			state.LiveSceneIds = append(state.LiveSceneIds, e.SceneID)
+		}
+	}
+
+	for _, status := range r.PlaybackService.ActiveCueLists() {
+		cueList := &generated.ControlSurfaceCueList{
+			CueListID: status.CueListID,
+			IsFading:  status.IsFading,
+			IsPaused:  status.IsPaused,
+		}
+		if status.CurrentCue != nil {
+			cueNumber, cueName := status.CurrentCue.CueNumber, status.CurrentCue.Name
+			cueList.CueNumber, cueList.CueName = &cueNumber, &cueName
+		}
+		state.CueLists = append(state.CueLists, cueList)
+	}
+
+	var buttons []models.SceneBoardButton
+	if err := r.db.WithContext(ctx).Select("id", "scene_board_id", "scene_id").Order("id").Find(&buttons).Error; err != nil {
+		return nil, err
+	}
+	for _, button := range buttons {
+		sceneID, onBoard := live[stack.SceneBoardPlayback(button.SceneBoardID)]
+		if (onBoard && sceneID == button.SceneID) || r.HoldService.State(button.ID) != nil || r.FlashService.State(button.ID) != nil {
+			state.LitButtonIds = append(state.LitButtonIds, button.ID)
+		}
+	}
+	return state, nil
+}
+
+// subscribeControlSurfaceState sends control surface state now and whenever
+// it changes.
+func (r *Resolver) subscribeControlSurfaceState(ctx context.Context) (<-chan *generated.ControlSurfaceState, error) {
+	state, err := r.controlSurfaceState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any change wakes the loop; the state is only sent when it differs
+	changed := make(chan struct{}, 1)
+	subs := make([]*pubsub.Subscriber, len(controlSurfaceTopics))
+	for i, topic := range controlSurfaceTopics {
+		subs[i] = r.PubSub.Subscribe(topic, "", 10)
+		go func(sub *pubsub.Subscriber) {
+			for range sub.Channel {
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}(subs[i])
+	}
+
+	outputChan := make(chan *generated.ControlSurfaceState, 10)
+
+	go func() {
+		defer close(outputChan)
+		defer func() {
+			for _, sub := range subs {
+				r.PubSub.Unsubscribe(sub)
+			}
+		}()
+
+		ticker := time.NewTicker(controlSurfaceInterval)
+		defer ticker.Stop()
+
+		sent := state
+		select {
+		case outputChan <- state:
+		case <-ctx.Done():
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			case <-ticker.C:
+			}
+
+			if state, err = r.controlSurfaceState(ctx); err != nil {
+				return
+			}
+			if reflect.DeepEqual(state, sent) {
+				continue
+			}
+			sent = state
+			select {
+			case outputChan <- state:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
@@ -0,0 +1,215 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// fixtureMap matches the fixtures of one project to those of another.
+type fixtureMap struct {
+	targets  map[string]string // Source fixture ID to target fixture ID
+	unmapped map[string]string // Source fixture ID to name, for fixtures with no match
+}
+
+// name returns the name of an unmatched source fixture.
+func (m *fixtureMap) name(fixtureID string) string {
+	if name, ok := m.unmapped[fixtureID]; ok {
+		return name
+	}
+	return fixtureID
+}
+
+// mapProjectFixtures matches each source fixture to the target fixture of
+// the same name, or else the one at the same universe and start channel. No
+// target fixture is matched twice.
+func (r *Resolver) mapProjectFixtures(ctx context.Context, sourceProjectID, targetProjectID string) (*fixtureMap, error) {
+	sources, err := r.FixtureRepo.FindByProjectID(ctx, sourceProjectID)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := r.FixtureRepo.FindByProjectID(ctx, targetProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]string)
+	byAddress := make(map[[2]int]string)
+	for _, f := range targets {
+		if _, ok := byName[f.Name]; !ok {
+			byName[f.Name] = f.ID
+		}
+		address := [2]int{f.Universe, f.StartChannel}
+		if _, ok := byAddress[address]; !ok {
+			byAddress[address] = f.ID
+		}
+	}
+
+	m := &fixtureMap{targets: make(map[string]string), unmapped: make(map[string]string)}
+	used := make(map[string]bool)
+	// Name matches go first so an address match can't take a fixture that
+	// another source fixture matches by name
+	for _, f := range sources {
+		if id, ok := byName[f.Name]; ok && !used[id] {
+			m.targets[f.ID] = id
+			used[id] = true
+		}
+	}
+	for _, f := range sources {
+		if _, ok := m.targets[f.ID]; ok {
+			continue
+		}
+		if id, ok := byAddress[[2]int{f.Universe, f.StartChannel}]; ok && !used[id] {
+			m.targets[f.ID] = id
+			used[id] = true
+			continue
+		}
+		m.unmapped[f.ID] = f.Name
+	}
+	return m, nil
+}
+
+// copyScene copies a scene into a project, moving its fixture values to the
+// fixtures fixtures maps them to. Group values and palette references belong
+// to the source project and stay behind; values keep their channels. It
+// returns a warning for each fixture whose value was left out.
+func (r *Resolver) copyScene(ctx context.Context, original *models.Scene, projectID, name string, fixtures *fixtureMap) (*models.Scene, []string, error) {
+	originalValues, err := r.SceneRepo.GetFixtureValues(ctx, original.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newScene := &models.Scene{
+		Name:           name,
+		SecondaryLabel: original.SecondaryLabel,
+		Description:    original.Description,
+		ProjectID:      projectID,
+		DefaultFadeIn:  original.DefaultFadeIn,
+		DefaultFadeOut: original.DefaultFadeOut,
+	}
+
+	var newValues []models.FixtureValue
+	var warnings []string
+	for _, v := range originalValues {
+		fixtureID, ok := fixtures.targets[v.FixtureID]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("Scene %s: no fixture matches %s in the target project", original.Name, fixtures.name(v.FixtureID)))
+			continue
+		}
+		newValues = append(newValues, models.FixtureValue{
+			FixtureID:  fixtureID,
+			Channels:   v.Channels,
+			SceneOrder: v.SceneOrder,
+		})
+	}
+
+	if err := r.SceneRepo.CreateWithFixtureValues(ctx, newScene, newValues); err != nil {
+		return nil, nil, err
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, newScene)
+	return newScene, warnings, nil
+}
+
+// findTargetProject checks that a copy's target project exists.
+func (r *Resolver) findTargetProject(ctx context.Context, projectID string) error {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	return nil
+}
+
+// copyCues gives a cue list copies of another list's cues. sceneIDs maps
+// the original cues' scenes to the ones the copies play; cues whose scene
+// is not in it keep their scene.
+func (r *Resolver) copyCues(ctx context.Context, fromCueListID, toCueListID string, sceneIDs map[string]string) error {
+	cues, err := r.CueRepo.FindByCueListID(ctx, fromCueListID)
+	if err != nil {
+		return err
+	}
+	for _, cue := range cues {
+		sceneID := cue.SceneID
+		if id, ok := sceneIDs[sceneID]; ok {
+			sceneID = id
+		}
+		newCue := &models.Cue{
+			Name:           cue.Name,
+			SecondaryLabel: cue.SecondaryLabel,
+			CueNumber:      cue.CueNumber,
+			CueListID:      toCueListID,
+			SceneID:        sceneID,
+			FadeInTime:     cue.FadeInTime,
+			FadeOutTime:    cue.FadeOutTime,
+			FollowTime:     cue.FollowTime,
+			FollowQuantize: cue.FollowQuantize,
+			EasingType:     cue.EasingType,
+			Notes:          cue.Notes,
+			Timecode:       cue.Timecode,
+			Block:          cue.Block,
+			MoveInBlack:    cue.MoveInBlack,
+		}
+		if err := r.CueRepo.Create(ctx, newCue); err != nil {
+			return err
+		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionCreated, newCue)
+	}
+	return nil
+}
+
+// copyCueList copies a cue list and its cues into a project. Copying within
+// the cue list's project shares its scenes; copying to another project
+// copies each scene once, mapping its fixtures, and returns the warnings.
+func (r *Resolver) copyCueList(ctx context.Context, original *models.CueList, projectID, name string) (*generated.CueListCopyResult, error) {
+	sceneIDs := make(map[string]string)
+	warnings := []string{}
+	if projectID != original.ProjectID {
+		fixtures, err := r.mapProjectFixtures(ctx, original.ProjectID, projectID)
+		if err != nil {
+			return nil, err
+		}
+		cues, err := r.CueRepo.FindByCueListID(ctx, original.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, cue := range cues {
+			if _, ok := sceneIDs[cue.SceneID]; ok {
+				continue
+			}
+			scene, err := r.SceneRepo.FindByID(ctx, cue.SceneID)
+			if err != nil {
+				return nil, err
+			}
+			if scene == nil {
+				return nil, fmt.Errorf("scene not found: %s", cue.SceneID)
+			}
+			copied, sceneWarnings, err := r.copyScene(ctx, scene, projectID, scene.Name, fixtures)
+			if err != nil {
+				return nil, err
+			}
+			sceneIDs[cue.SceneID] = copied.ID
+			warnings = append(warnings, sceneWarnings...)
+		}
+	}
+
+	cueList := &models.CueList{
+		Name:         name,
+		Description:  original.Description,
+		Loop:         original.Loop,
+		PlaybackMode: original.PlaybackMode,
+		Tracking:     original.Tracking,
+		ProjectID:    projectID,
+	}
+	if err := r.CueListRepo.Create(ctx, cueList); err != nil {
+		return nil, err
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, cueList)
+	if err := r.copyCues(ctx, original.ID, cueList.ID, sceneIDs); err != nil {
+		return nil, err
+	}
+	return &generated.CueListCopyResult{CueList: *cueList, Warnings: warnings}, nil
+}
@@ -1,10 +1,31 @@
 package resolvers
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/client"
-
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/lucsky/cuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
 	"github.com/bbernstein/lacylights-go/internal/services/version"
 )
 
@@ -557,6 +578,129 @@ func TestFixtureInstance_Update(t *testing.T) {
 	}
 }
 
+func TestFixtureInstance_AddressConflicts(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	var projectResp struct {
+		CreateProject struct {
+			ID string `json:"id"`
+		} `json:"createProject"`
+	}
+	err := c.Post(`mutation { createProject(input: { name: "Test Project" }) { id } }`, &projectResp)
+	if err != nil {
+		t.Fatalf("CreateProject mutation failed: %v", err)
+	}
+	projectID := projectResp.CreateProject.ID
+
+	var defResp struct {
+		CreateFixtureDefinition struct {
+			ID string `json:"id"`
+		} `json:"createFixtureDefinition"`
+	}
+	err = c.Post(`mutation {
+		createFixtureDefinition(input: {
+			manufacturer: "Test"
+			model: "TestPar"
+			type: LED_PAR
+			channels: [
+				{ name: "Red", type: RED, offset: 0, minValue: 0, maxValue: 255, defaultValue: 0 }
+				{ name: "Green", type: GREEN, offset: 1, minValue: 0, maxValue: 255, defaultValue: 0 }
+				{ name: "Blue", type: BLUE, offset: 2, minValue: 0, maxValue: 255, defaultValue: 0 }
+			]
+		}) {
+			id
+		}
+	}`, &defResp)
+	if err != nil {
+		t.Fatalf("CreateFixtureDefinition mutation failed: %v", err)
+	}
+
+	create := func(name string, startChannel int) (string, error) {
+		var resp struct {
+			CreateFixtureInstance struct {
+				ID string `json:"id"`
+			} `json:"createFixtureInstance"`
+		}
+		err := c.Post(`mutation($projectId: ID!, $defId: ID!, $name: String!, $start: Int!) {
+			createFixtureInstance(input: {
+				name: $name, projectId: $projectId, definitionId: $defId, universe: 1, startChannel: $start
+			}) { id }
+		}`, &resp,
+			client.Var("projectId", projectID),
+			client.Var("defId", defResp.CreateFixtureDefinition.ID),
+			client.Var("name", name),
+			client.Var("start", startChannel))
+		return resp.CreateFixtureInstance.ID, err
+	}
+	first, err := create("Par 1", 1)
+	if err != nil {
+		t.Fatalf("CreateFixtureInstance mutation failed: %v", err)
+	}
+	second, err := create("Par 2", 4)
+	if err != nil {
+		t.Fatalf("CreateFixtureInstance mutation failed: %v", err)
+	}
+
+	// Overlapping addresses are rejected with the fixtures in the way
+	_, err = create("Par 3", 3)
+	if err == nil {
+		t.Fatal("Expected an address conflict creating Par 3 at channel 3")
+	}
+	if !strings.Contains(err.Error(), "DMX_ADDRESS_CONFLICT") || !strings.Contains(err.Error(), first) {
+		t.Errorf("Expected a structured conflict naming Par 1, got %v", err)
+	}
+
+	var updateResp struct {
+		UpdateFixtureInstance struct {
+			ID string `json:"id"`
+		} `json:"updateFixtureInstance"`
+	}
+	err = c.Post(`mutation($id: ID!) { updateFixtureInstance(id: $id, input: { startChannel: 2 }) { id } }`,
+		&updateResp, client.Var("id", second))
+	if err == nil {
+		t.Error("Expected an address conflict moving Par 2 onto Par 1")
+	}
+	err = c.Post(`mutation($id: ID!) { updateFixtureInstance(id: $id, input: { name: "Renamed", startChannel: 1 }) { id } }`,
+		&updateResp, client.Var("id", first))
+	if err != nil {
+		t.Errorf("Expected an unmoved fixture to update, got %v", err)
+	}
+
+	// Fixtures can swap addresses in one bulk update
+	var bulkResp struct {
+		BulkUpdateFixtures []struct {
+			StartChannel int `json:"startChannel"`
+		} `json:"bulkUpdateFixtures"`
+	}
+	err = c.Post(`mutation($first: ID!, $second: ID!) {
+		bulkUpdateFixtures(input: { fixtures: [
+			{ fixtureId: $first, startChannel: 4 }
+			{ fixtureId: $second, startChannel: 1 }
+		] }) { startChannel }
+	}`, &bulkResp, client.Var("first", first), client.Var("second", second))
+	if err != nil {
+		t.Fatalf("Expected fixtures to swap addresses, got %v", err)
+	}
+
+	var suggestResp struct {
+		SuggestNextAddress *struct {
+			Universe     int `json:"universe"`
+			StartChannel int `json:"startChannel"`
+			EndChannel   int `json:"endChannel"`
+		} `json:"suggestNextAddress"`
+	}
+	err = c.Post(`query($projectId: ID!) {
+		suggestNextAddress(projectId: $projectId, channelCount: 3) { universe startChannel endChannel }
+	}`, &suggestResp, client.Var("projectId", projectID))
+	if err != nil {
+		t.Fatalf("suggestNextAddress query failed: %v", err)
+	}
+	if got := suggestResp.SuggestNextAddress; got == nil || got.Universe != 1 || got.StartChannel != 7 || got.EndChannel != 9 {
+		t.Errorf("suggestNextAddress = %+v, want universe 1 channels 7-9", got)
+	}
+}
+
 func TestFixtureInstance_Delete(t *testing.T) {
 	c, _, cleanup := testSetup(t)
 	defer cleanup()
@@ -1606,6 +1750,130 @@ func TestSceneBoardButton_Delete(t *testing.T) {
 	}
 }
 
+func TestSceneBoardButton_PressAndRelease(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	var projectResp struct {
+		CreateProject struct {
+			ID string `json:"id"`
+		} `json:"createProject"`
+	}
+	_ = c.Post(`mutation { createProject(input: { name: "Test Project" }) { id } }`, &projectResp)
+
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	_ = c.Post(`mutation($projectId: ID!) {
+		createScene(input: {
+			name: "Test Scene"
+			projectId: $projectId
+			fixtureValues: []
+		}) {
+			id
+		}
+	}`, &sceneResp, client.Var("projectId", projectResp.CreateProject.ID))
+
+	var boardResp struct {
+		CreateSceneBoard struct {
+			ID              string  `json:"id"`
+			HoldRampTime    float64 `json:"holdRampTime"`
+			HoldCurve       string  `json:"holdCurve"`
+			HoldReleaseMode string  `json:"holdReleaseMode"`
+		} `json:"createSceneBoard"`
+	}
+	err := c.Post(`mutation($projectId: ID!) {
+		createSceneBoard(input: {
+			name: "Hold Board"
+			projectId: $projectId
+			holdRampTime: 2.5
+			holdCurve: EASE_IN_OUT_SINE
+		}) {
+			id
+			holdRampTime
+			holdCurve
+			holdReleaseMode
+		}
+	}`, &boardResp, client.Var("projectId", projectResp.CreateProject.ID))
+	if err != nil {
+		t.Fatalf("CreateSceneBoard mutation failed: %v", err)
+	}
+	if boardResp.CreateSceneBoard.HoldRampTime != 2.5 {
+		t.Errorf("Expected holdRampTime 2.5, got %v", boardResp.CreateSceneBoard.HoldRampTime)
+	}
+	if boardResp.CreateSceneBoard.HoldCurve != "EASE_IN_OUT_SINE" {
+		t.Errorf("Expected holdCurve EASE_IN_OUT_SINE, got %s", boardResp.CreateSceneBoard.HoldCurve)
+	}
+	if boardResp.CreateSceneBoard.HoldReleaseMode != "LATCH" {
+		t.Errorf("Expected default holdReleaseMode LATCH, got %s", boardResp.CreateSceneBoard.HoldReleaseMode)
+	}
+
+	var buttonResp struct {
+		AddSceneToBoard struct {
+			ID string `json:"id"`
+		} `json:"addSceneToBoard"`
+	}
+	_ = c.Post(`mutation($boardId: ID!, $sceneId: ID!) {
+		addSceneToBoard(input: {
+			sceneBoardId: $boardId
+			sceneId: $sceneId
+			layoutX: 0
+			layoutY: 0
+		}) {
+			id
+		}
+	}`, &buttonResp,
+		client.Var("boardId", boardResp.CreateSceneBoard.ID),
+		client.Var("sceneId", sceneResp.CreateScene.ID))
+
+	type holdState struct {
+		ButtonID  string  `json:"buttonId"`
+		SceneID   string  `json:"sceneId"`
+		Level     float64 `json:"level"`
+		IsHeld    bool    `json:"isHeld"`
+		IsLatched bool    `json:"isLatched"`
+	}
+
+	var pressResp struct {
+		PressSceneBoardButton holdState `json:"pressSceneBoardButton"`
+	}
+	err = c.Post(`mutation($buttonId: ID!) {
+		pressSceneBoardButton(buttonId: $buttonId) { buttonId sceneId level isHeld isLatched }
+	}`, &pressResp, client.Var("buttonId", buttonResp.AddSceneToBoard.ID))
+	if err != nil {
+		t.Fatalf("PressSceneBoardButton mutation failed: %v", err)
+	}
+	if !pressResp.PressSceneBoardButton.IsHeld {
+		t.Error("Expected button to be held after press")
+	}
+	if pressResp.PressSceneBoardButton.SceneID != sceneResp.CreateScene.ID {
+		t.Errorf("Expected sceneId %s, got %s", sceneResp.CreateScene.ID, pressResp.PressSceneBoardButton.SceneID)
+	}
+
+	var releaseResp struct {
+		ReleaseSceneBoardButton holdState `json:"releaseSceneBoardButton"`
+	}
+	err = c.Post(`mutation($buttonId: ID!) {
+		releaseSceneBoardButton(buttonId: $buttonId, releaseMode: RELEASE) { buttonId level isHeld isLatched }
+	}`, &releaseResp, client.Var("buttonId", buttonResp.AddSceneToBoard.ID))
+	if err != nil {
+		t.Fatalf("ReleaseSceneBoardButton mutation failed: %v", err)
+	}
+	if releaseResp.ReleaseSceneBoardButton.IsHeld || releaseResp.ReleaseSceneBoardButton.IsLatched {
+		t.Error("Expected button to be neither held nor latched after RELEASE")
+	}
+
+	// Releasing again fails because the button is no longer held
+	err = c.Post(`mutation($buttonId: ID!) {
+		releaseSceneBoardButton(buttonId: $buttonId) { buttonId }
+	}`, &releaseResp, client.Var("buttonId", buttonResp.AddSceneToBoard.ID))
+	if err == nil {
+		t.Error("Expected error releasing a button that is not held")
+	}
+}
+
 // =============================================================================
 // Bulk Operations Tests
 // =============================================================================
@@ -1869,6 +2137,312 @@ func TestProjectsByIds(t *testing.T) {
 	}
 }
 
+const executeBatchMutation = `mutation($operations: [BatchOperationInput!]!) {
+	executeBatch(operations: $operations) {
+		index
+		ref
+		kind
+		id
+	}
+}`
+
+type executeBatchResponse struct {
+	ExecuteBatch []struct {
+		Index int     `json:"index"`
+		Ref   *string `json:"ref"`
+		Kind  string  `json:"kind"`
+		ID    string  `json:"id"`
+	} `json:"executeBatch"`
+}
+
+// batchOperations builds a scene + board button + cue batch for the given project.
+func batchOperations(projectID, cueListSceneRef string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{"ref": "scene", "createScene": map[string]interface{}{"name": "Batch Scene", "projectId": projectID, "fixtureValues": []interface{}{}}},
+		{"ref": "board", "createSceneBoard": map[string]interface{}{"name": "Batch Board", "projectId": projectID}},
+		{"addSceneToBoard": map[string]interface{}{"sceneBoardId": "$ref:board", "sceneId": "$ref:scene", "layoutX": 0, "layoutY": 0}},
+		{"ref": "cues", "createCueList": map[string]interface{}{"name": "Batch Cues", "projectId": projectID}},
+		{"createCue": map[string]interface{}{"name": "Cue 1", "cueNumber": 1, "cueListId": "$ref:cues", "sceneId": cueListSceneRef, "fadeInTime": 3, "fadeOutTime": 3}},
+	}
+}
+
+func TestExecuteBatch_CreatesLinkedEntities(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: cuid.New(), Name: "Batch Project"}
+	resolver.db.Create(project)
+
+	var resp executeBatchResponse
+	err := c.Post(executeBatchMutation, &resp, client.Var("operations", batchOperations(project.ID, "$ref:scene")))
+	if err != nil {
+		t.Fatalf("executeBatch failed: %v", err)
+	}
+
+	wantKinds := []string{"CREATE_SCENE", "CREATE_SCENE_BOARD", "ADD_SCENE_TO_BOARD", "CREATE_CUE_LIST", "CREATE_CUE"}
+	if len(resp.ExecuteBatch) != len(wantKinds) {
+		t.Fatalf("Expected %d results, got %d", len(wantKinds), len(resp.ExecuteBatch))
+	}
+	for i, result := range resp.ExecuteBatch {
+		if result.Index != i || result.Kind != wantKinds[i] || result.ID == "" {
+			t.Errorf("Unexpected result %d: %+v", i, result)
+		}
+	}
+
+	sceneID := resp.ExecuteBatch[0].ID
+	var button models.SceneBoardButton
+	if err := resolver.db.First(&button, "id = ?", resp.ExecuteBatch[2].ID).Error; err != nil {
+		t.Fatalf("Button not created: %v", err)
+	}
+	if button.SceneID != sceneID || button.SceneBoardID != resp.ExecuteBatch[1].ID {
+		t.Errorf("Button references not resolved: %+v", button)
+	}
+	var cue models.Cue
+	if err := resolver.db.First(&cue, "id = ?", resp.ExecuteBatch[4].ID).Error; err != nil {
+		t.Fatalf("Cue not created: %v", err)
+	}
+	if cue.SceneID != sceneID || cue.CueListID != resp.ExecuteBatch[3].ID {
+		t.Errorf("Cue references not resolved: %+v", cue)
+	}
+}
+
+func TestExecuteBatch_RollsBackOnFailure(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: cuid.New(), Name: "Batch Project"}
+	resolver.db.Create(project)
+
+	// The final cue names a scene that does not exist, so nothing should be kept
+	var resp executeBatchResponse
+	err := c.Post(executeBatchMutation, &resp, client.Var("operations", batchOperations(project.ID, "missing-scene")))
+	if err == nil {
+		t.Fatal("Expected executeBatch to fail")
+	}
+
+	for _, model := range []interface{}{&models.Scene{}, &models.SceneBoard{}, &models.SceneBoardButton{}, &models.CueList{}, &models.Cue{}} {
+		var count int64
+		resolver.db.Model(model).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no %T rows after rollback, got %d", model, count)
+		}
+	}
+
+	// Unknown references and ambiguous operations are rejected
+	badBatches := [][]map[string]interface{}{
+		{{"createCueList": map[string]interface{}{"name": "Cues", "projectId": "$ref:nope"}}},
+		{{"createCueList": map[string]interface{}{"name": "Cues", "projectId": project.ID}, "createSceneBoard": map[string]interface{}{"name": "Board", "projectId": project.ID}}},
+		{},
+	}
+	for _, ops := range badBatches {
+		if err := c.Post(executeBatchMutation, &resp, client.Var("operations", ops)); err == nil {
+			t.Errorf("Expected error for batch %v", ops)
+		}
+	}
+}
+
+// TestTransaction_RollsBackHouseLights tests that house lights saved in a
+// batch transaction are not kept when it rolls back.
+func TestTransaction_RollsBackHouseLights(t *testing.T) {
+	_, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: cuid.New(), Name: "Batch Project"}
+	resolver.db.Create(project)
+
+	fadeTime := 5.0
+	err := resolver.transaction(ctx, func(tx *Resolver) error {
+		input := generated.UpdateHouseLightsInput{FadeTime: graphql.OmittableOf(&fadeTime)}
+		if _, err := tx.updateHouseLights(ctx, project.ID, input); err != nil {
+			return err
+		}
+		return errors.New("later operation failed")
+	})
+	if err == nil || err.Error() != "later operation failed" {
+		t.Fatalf("Expected the later operation to fail the transaction, got %v", err)
+	}
+
+	var count int64
+	resolver.db.Model(&models.HouseLights{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected no house lights after rollback, got %d", count)
+	}
+}
+
+func TestNamingConvention_GeneratesNames(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: cuid.New(), Name: "Hamlet"}
+	resolver.db.Create(project)
+
+	var convResp struct {
+		UpdateProjectNamingConvention struct {
+			ScenePattern     *string `json:"scenePattern"`
+			Uniqueness       string  `json:"uniqueness"`
+			DefaultSceneSort string  `json:"defaultSceneSort"`
+			Variables        []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"variables"`
+		} `json:"updateProjectNamingConvention"`
+	}
+	err := c.Post(`mutation($projectId: ID!) {
+		updateProjectNamingConvention(projectId: $projectId, input: {
+			scenePattern: "Sc {act}.{n}"
+			cuePattern: "{list} Q{cue}"
+			uniqueness: APPEND_SUFFIX
+			variables: [{ key: "act", value: "2" }]
+			defaultSceneSort: NAME
+		}) {
+			scenePattern
+			uniqueness
+			defaultSceneSort
+			variables { key value }
+		}
+	}`, &convResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("updateProjectNamingConvention failed: %v", err)
+	}
+	conv := convResp.UpdateProjectNamingConvention
+	if conv.ScenePattern == nil || *conv.ScenePattern != "Sc {act}.{n}" || conv.Uniqueness != "APPEND_SUFFIX" || conv.DefaultSceneSort != "NAME" || len(conv.Variables) != 1 {
+		t.Fatalf("Unexpected convention: %+v", conv)
+	}
+
+	createScene := func(name string) string {
+		var resp struct {
+			CreateScene struct {
+				Name string `json:"name"`
+			} `json:"createScene"`
+		}
+		err := c.Post(`mutation($projectId: ID!, $name: String!) {
+			createScene(input: { name: $name, projectId: $projectId, fixtureValues: [] }) { name }
+		}`, &resp, client.Var("projectId", project.ID), client.Var("name", name))
+		if err != nil {
+			t.Fatalf("createScene failed: %v", err)
+		}
+		return resp.CreateScene.Name
+	}
+
+	for _, want := range []string{"Sc 2.1", "Sc 2.2"} {
+		if got := createScene(""); got != want {
+			t.Errorf("Generated scene name = %q, want %q", got, want)
+		}
+	}
+	if got := createScene("Sc 2.1"); got != "Sc 2.1 (2)" {
+		t.Errorf("Duplicate scene name = %q, want suffixed name", got)
+	}
+	for i := 0; i < 8; i++ {
+		createScene("")
+	}
+
+	var nextResp struct {
+		NextSceneName string `json:"nextSceneName"`
+	}
+	if err := c.Post(`query($projectId: ID!) { nextSceneName(projectId: $projectId) }`, &nextResp, client.Var("projectId", project.ID)); err != nil {
+		t.Fatalf("nextSceneName failed: %v", err)
+	}
+	if nextResp.NextSceneName != "Sc 2.11" {
+		t.Errorf("nextSceneName = %q, want Sc 2.11", nextResp.NextSceneName)
+	}
+
+	// The project's default sort orders numbered names naturally
+	var scenesResp struct {
+		Scenes struct {
+			Scenes []struct {
+				Name string `json:"name"`
+			} `json:"scenes"`
+		} `json:"scenes"`
+	}
+	if err := c.Post(`query($projectId: ID!) { scenes(projectId: $projectId) { scenes { name } } }`, &scenesResp, client.Var("projectId", project.ID)); err != nil {
+		t.Fatalf("scenes query failed: %v", err)
+	}
+	names := scenesResp.Scenes.Scenes
+	if len(names) != 11 || names[0].Name != "Sc 2.1" || names[1].Name != "Sc 2.1 (2)" || names[10].Name != "Sc 2.10" {
+		t.Errorf("Unexpected natural scene order: %+v", names)
+	}
+
+	// Cue names use the cue list and cue number
+	cueList := &models.CueList{ID: cuid.New(), Name: "Main", ProjectID: project.ID}
+	resolver.db.Create(cueList)
+	var sceneRow models.Scene
+	resolver.db.First(&sceneRow, "project_id = ?", project.ID)
+
+	var cueResp struct {
+		CreateCue struct {
+			Name string `json:"name"`
+		} `json:"createCue"`
+	}
+	err = c.Post(`mutation($cueListId: ID!, $sceneId: ID!) {
+		createCue(input: { name: "", cueNumber: 1.5, cueListId: $cueListId, sceneId: $sceneId, fadeInTime: 3, fadeOutTime: 3 }) { name }
+	}`, &cueResp, client.Var("cueListId", cueList.ID), client.Var("sceneId", sceneRow.ID))
+	if err != nil {
+		t.Fatalf("createCue failed: %v", err)
+	}
+	if cueResp.CreateCue.Name != "Main Q1.5" {
+		t.Errorf("Generated cue name = %q, want Main Q1.5", cueResp.CreateCue.Name)
+	}
+
+	var nextCueResp struct {
+		NextCueName string `json:"nextCueName"`
+	}
+	if err := c.Post(`query($cueListId: ID!) { nextCueName(cueListId: $cueListId) }`, &nextCueResp, client.Var("cueListId", cueList.ID)); err != nil {
+		t.Fatalf("nextCueName failed: %v", err)
+	}
+	if nextCueResp.NextCueName != "Main Q2" {
+		t.Errorf("nextCueName = %q, want Main Q2", nextCueResp.NextCueName)
+	}
+}
+
+func TestNamingConvention_Validation(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: cuid.New(), Name: "Hamlet"}
+	resolver.db.Create(project)
+
+	var resp struct {
+		UpdateProjectNamingConvention struct {
+			Uniqueness string `json:"uniqueness"`
+		} `json:"updateProjectNamingConvention"`
+	}
+	invalid := []string{
+		`{ scenePattern: "Sc {act}.{n}" }`,
+		`{ scenePattern: "Q{cue}" }`,
+		`{ variables: [{ key: "n", value: "1" }] }`,
+	}
+	for _, input := range invalid {
+		err := c.Post(`mutation($projectId: ID!) {
+			updateProjectNamingConvention(projectId: $projectId, input: `+input+`) { uniqueness }
+		}`, &resp, client.Var("projectId", project.ID))
+		if err == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+
+	// Rejecting duplicates fails the create
+	err := c.Post(`mutation($projectId: ID!) {
+		updateProjectNamingConvention(projectId: $projectId, input: { uniqueness: REJECT_DUPLICATES }) { uniqueness }
+	}`, &resp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("updateProjectNamingConvention failed: %v", err)
+	}
+	resolver.db.Create(&models.Scene{ID: cuid.New(), Name: "Wash", ProjectID: project.ID})
+
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($projectId: ID!) {
+		createScene(input: { name: "Wash", projectId: $projectId, fixtureValues: [] }) { id }
+	}`, &sceneResp, client.Var("projectId", project.ID))
+	if err == nil {
+		t.Error("Expected duplicate scene name to be rejected")
+	}
+}
+
 func TestScenesByIds(t *testing.T) {
 	c, _, cleanup := testSetup(t)
 	defer cleanup()
@@ -2018,3 +2592,1705 @@ func TestBuildInfo_DefaultValues(t *testing.T) {
 		t.Errorf("Expected default buildTime 'unknown', got %q", resp.BuildInfo.BuildTime)
 	}
 }
+
+func TestFixtureDefinition_DeleteReferenceIntegrity(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	oldDef := &models.FixtureDefinition{ID: cuid.New(), Manufacturer: "Acme", Model: "Par Old", Type: "LED_PAR"}
+	resolver.db.Create(oldDef)
+	newDef := &models.FixtureDefinition{ID: cuid.New(), Manufacturer: "Acme", Model: "Par New", Type: "LED_PAR"}
+	resolver.db.Create(newDef)
+	dimmer := &models.ChannelDefinition{ID: cuid.New(), Name: "Dimmer", Type: "INTENSITY", Offset: 0, DefinitionID: newDef.ID}
+	red := &models.ChannelDefinition{ID: cuid.New(), Name: "Red", Type: "RED", Offset: 1, DefinitionID: newDef.ID}
+	resolver.db.Create(dimmer)
+	resolver.db.Create(red)
+	mode := &models.FixtureMode{ID: cuid.New(), Name: "2ch", ChannelCount: 2, DefinitionID: newDef.ID}
+	resolver.db.Create(mode)
+	resolver.db.Create(&models.ModeChannel{ID: cuid.New(), ModeID: mode.ID, ChannelID: dimmer.ID, Offset: 0})
+	resolver.db.Create(&models.ModeChannel{ID: cuid.New(), ModeID: mode.ID, ChannelID: red.ID, Offset: 1})
+
+	modeName := "2CH"
+	hamlet := &models.Project{ID: cuid.New(), Name: "Hamlet"}
+	macbeth := &models.Project{ID: cuid.New(), Name: "Macbeth"}
+	resolver.db.Create(hamlet)
+	resolver.db.Create(macbeth)
+	fixtures := []*models.FixtureInstance{
+		{ID: cuid.New(), Name: "Par 1", ProjectID: hamlet.ID, DefinitionID: oldDef.ID, ModeName: &modeName, Universe: 1, StartChannel: 1},
+		{ID: cuid.New(), Name: "Par 2", ProjectID: hamlet.ID, DefinitionID: oldDef.ID, ModeName: &modeName, Universe: 1, StartChannel: 3},
+		{ID: cuid.New(), Name: "Wash", ProjectID: macbeth.ID, DefinitionID: oldDef.ID, Universe: 1, StartChannel: 1},
+	}
+	for _, fixture := range fixtures {
+		resolver.db.Create(fixture)
+	}
+	scene := &models.Scene{ID: cuid.New(), Name: "Look", ProjectID: macbeth.ID}
+	resolver.db.Create(scene)
+	resolver.db.Create(&models.FixtureValue{ID: cuid.New(), SceneID: scene.ID, FixtureID: fixtures[2].ID, Channels: `[{"offset":0,"value":255}]`})
+
+	var usageResp struct {
+		FixtureDefinitionUsage []struct {
+			Project struct {
+				Name string `json:"name"`
+			} `json:"project"`
+			Fixtures []struct {
+				Name string `json:"name"`
+			} `json:"fixtures"`
+		} `json:"fixtureDefinitionUsage"`
+	}
+	if err := c.Post(`query($id: ID!) { fixtureDefinitionUsage(id: $id) { project { name } fixtures { name } } }`, &usageResp, client.Var("id", oldDef.ID)); err != nil {
+		t.Fatalf("fixtureDefinitionUsage failed: %v", err)
+	}
+	if len(usageResp.FixtureDefinitionUsage) != 2 {
+		t.Fatalf("Expected usage in 2 projects, got %+v", usageResp.FixtureDefinitionUsage)
+	}
+
+	var deleteResp struct {
+		DeleteFixtureDefinition bool `json:"deleteFixtureDefinition"`
+	}
+	err := c.Post(`mutation($id: ID!) { deleteFixtureDefinition(id: $id) }`, &deleteResp, client.Var("id", oldDef.ID))
+	if err == nil {
+		t.Fatal("Expected delete of a definition in use to fail")
+	}
+	for _, want := range []string{"3 fixture instances in 2 projects", "Hamlet: Par 1, Par 2", "Macbeth: Wash", "forceDeleteDefinition"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+
+	var forceResp struct {
+		ForceDeleteDefinition struct {
+			DeletedFixtureIds  []string `json:"deletedFixtureIds"`
+			RemappedFixtureIds []string `json:"remappedFixtureIds"`
+			AffectedProjectIds []string `json:"affectedProjectIds"`
+		} `json:"forceDeleteDefinition"`
+	}
+	err = c.Post(`mutation($id: ID!) { forceDeleteDefinition(id: $id, remapToDefinitionId: $id) { deletedFixtureIds } }`, &forceResp, client.Var("id", oldDef.ID))
+	if err == nil {
+		t.Error("Expected error remapping to the definition being deleted")
+	}
+
+	err = c.Post(`mutation($id: ID!, $target: ID!) {
+		forceDeleteDefinition(id: $id, remapToDefinitionId: $target) { deletedFixtureIds remappedFixtureIds affectedProjectIds }
+	}`, &forceResp, client.Var("id", oldDef.ID), client.Var("target", newDef.ID))
+	if err != nil {
+		t.Fatalf("forceDeleteDefinition failed: %v", err)
+	}
+	result := forceResp.ForceDeleteDefinition
+	if len(result.RemappedFixtureIds) != 3 || len(result.DeletedFixtureIds) != 0 || len(result.AffectedProjectIds) != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// Matching mode names carry over; fixtures without a mode use the definition channels
+	var remapped models.FixtureInstance
+	resolver.db.Preload("Channels").First(&remapped, "id = ?", fixtures[0].ID)
+	if remapped.DefinitionID != newDef.ID || remapped.ModeName == nil || *remapped.ModeName != "2ch" || len(remapped.Channels) != 2 {
+		t.Errorf("Unexpected remapped fixture: %+v", remapped)
+	}
+	var count int64
+	resolver.db.Model(&models.FixtureValue{}).Where("fixture_id = ?", fixtures[2].ID).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected remapped fixture to keep its scene values, got %d", count)
+	}
+	resolver.db.Model(&models.FixtureDefinition{}).Where("id = ?", oldDef.ID).Count(&count)
+	if count != 0 {
+		t.Error("Expected old definition to be deleted")
+	}
+
+	// Without a replacement the dependents and their scene values are removed
+	err = c.Post(`mutation($id: ID!) { forceDeleteDefinition(id: $id) { deletedFixtureIds affectedProjectIds } }`, &forceResp, client.Var("id", newDef.ID))
+	if err != nil {
+		t.Fatalf("forceDeleteDefinition failed: %v", err)
+	}
+	if len(forceResp.ForceDeleteDefinition.DeletedFixtureIds) != 3 {
+		t.Errorf("Expected 3 deleted fixtures, got %+v", forceResp.ForceDeleteDefinition)
+	}
+	resolver.db.Model(&models.FixtureValue{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected scene values of deleted fixtures to be removed, got %d", count)
+	}
+	resolver.db.Model(&models.FixtureMode{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected definition modes to be deleted, got %d", count)
+	}
+}
+
+func TestCopySceneAndCueListToProject(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	createProject := func(name string) string {
+		var resp struct {
+			CreateProject struct {
+				ID string `json:"id"`
+			} `json:"createProject"`
+		}
+		if err := c.Post(`mutation($name: String!) { createProject(input: { name: $name }) { id } }`,
+			&resp, client.Var("name", name)); err != nil {
+			t.Fatalf("CreateProject mutation failed: %v", err)
+		}
+		return resp.CreateProject.ID
+	}
+	source, target := createProject("Tour"), createProject("Venue")
+
+	var defResp struct {
+		CreateFixtureDefinition struct {
+			ID string `json:"id"`
+		} `json:"createFixtureDefinition"`
+	}
+	err := c.Post(`mutation {
+		createFixtureDefinition(input: {
+			manufacturer: "Test"
+			model: "CopyPar"
+			type: LED_PAR
+			channels: [
+				{ name: "Dimmer", type: INTENSITY, offset: 0, minValue: 0, maxValue: 255, defaultValue: 0 }
+			]
+		}) {
+			id
+		}
+	}`, &defResp)
+	if err != nil {
+		t.Fatalf("CreateFixtureDefinition mutation failed: %v", err)
+	}
+	createFixture := func(projectID, name string, startChannel int) string {
+		var resp struct {
+			CreateFixtureInstance struct {
+				ID string `json:"id"`
+			} `json:"createFixtureInstance"`
+		}
+		err := c.Post(`mutation($projectId: ID!, $defId: ID!, $name: String!, $start: Int!) {
+			createFixtureInstance(input: {
+				name: $name, projectId: $projectId, definitionId: $defId, universe: 1, startChannel: $start
+			}) { id }
+		}`, &resp,
+			client.Var("projectId", projectID),
+			client.Var("defId", defResp.CreateFixtureDefinition.ID),
+			client.Var("name", name),
+			client.Var("start", startChannel))
+		if err != nil {
+			t.Fatalf("CreateFixtureInstance mutation failed: %v", err)
+		}
+		return resp.CreateFixtureInstance.ID
+	}
+	// Par 1 matches by name, Par 2 by address, and Spot not at all
+	sourceFixtures := []string{createFixture(source, "Par 1", 1), createFixture(source, "Par 2", 2), createFixture(source, "Spot", 3)}
+	byName := createFixture(target, "Par 1", 10)
+	byAddress := createFixture(target, "House Left", 2)
+
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($projectId: ID!, $a: ID!, $b: ID!, $c: ID!) {
+		createScene(input: {
+			name: "Look"
+			projectId: $projectId
+			fixtureValues: [
+				{ fixtureId: $a, channels: [{ offset: 0, value: 100 }] }
+				{ fixtureId: $b, channels: [{ offset: 0, value: 150 }] }
+				{ fixtureId: $c, channels: [{ offset: 0, value: 200 }] }
+			]
+		}) { id }
+	}`, &sceneResp,
+		client.Var("projectId", source),
+		client.Var("a", sourceFixtures[0]),
+		client.Var("b", sourceFixtures[1]),
+		client.Var("c", sourceFixtures[2]))
+	if err != nil {
+		t.Fatalf("CreateScene mutation failed: %v", err)
+	}
+	sceneID := sceneResp.CreateScene.ID
+
+	var copyResp struct {
+		CopySceneToProject struct {
+			Scene struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"scene"`
+			Warnings []string `json:"warnings"`
+		} `json:"copySceneToProject"`
+	}
+	err = c.Post(`mutation($sceneId: ID!, $projectId: ID!) {
+		copySceneToProject(sceneId: $sceneId, targetProjectId: $projectId) { scene { id name } warnings }
+	}`, &copyResp, client.Var("sceneId", sceneID), client.Var("projectId", target))
+	if err != nil {
+		t.Fatalf("CopySceneToProject mutation failed: %v", err)
+	}
+	copied := copyResp.CopySceneToProject
+	if copied.Scene.Name != "Look" {
+		t.Errorf("Copied scene name = %q, want Look", copied.Scene.Name)
+	}
+	if len(copied.Warnings) != 1 || !strings.Contains(copied.Warnings[0], "Spot") {
+		t.Errorf("Warnings = %v, want one naming Spot", copied.Warnings)
+	}
+	values, err := resolver.SceneRepo.GetFixtureValues(ctx, copied.Scene.ID)
+	if err != nil {
+		t.Fatalf("GetFixtureValues() error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, v := range values {
+		got[v.FixtureID] = v.Channels
+	}
+	if len(got) != 2 || !strings.Contains(got[byName], "100") || !strings.Contains(got[byAddress], "150") {
+		t.Errorf("Copied values = %v, want Par 1 on %s and Par 2 on %s", got, byName, byAddress)
+	}
+
+	var cueListResp struct {
+		CreateCueList struct {
+			ID string `json:"id"`
+		} `json:"createCueList"`
+	}
+	err = c.Post(`mutation($projectId: ID!) {
+		createCueList(input: { name: "Show", projectId: $projectId, tracking: true }) { id }
+	}`, &cueListResp, client.Var("projectId", source))
+	if err != nil {
+		t.Fatalf("CreateCueList mutation failed: %v", err)
+	}
+	cueListID := cueListResp.CreateCueList.ID
+	for _, number := range []float64{1, 2} {
+		var cueResp struct {
+			CreateCue struct {
+				ID string `json:"id"`
+			} `json:"createCue"`
+		}
+		err = c.Post(`mutation($cueListId: ID!, $sceneId: ID!, $number: Float!) {
+			createCue(input: {
+				name: "Cue", cueNumber: $number, cueListId: $cueListId, sceneId: $sceneId, fadeInTime: 2, fadeOutTime: 1
+			}) { id }
+		}`, &cueResp, client.Var("cueListId", cueListID), client.Var("sceneId", sceneID), client.Var("number", number))
+		if err != nil {
+			t.Fatalf("CreateCue mutation failed: %v", err)
+		}
+	}
+
+	// Duplicating in the project shares the scenes
+	var duplicateResp struct {
+		DuplicateCueList struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Tracking bool   `json:"tracking"`
+		} `json:"duplicateCueList"`
+	}
+	err = c.Post(`mutation($id: ID!) { duplicateCueList(id: $id) { id name tracking } }`,
+		&duplicateResp, client.Var("id", cueListID))
+	if err != nil {
+		t.Fatalf("DuplicateCueList mutation failed: %v", err)
+	}
+	if duplicateResp.DuplicateCueList.Name != "Show (Copy)" || !duplicateResp.DuplicateCueList.Tracking {
+		t.Errorf("Duplicate = %+v, want a tracking Show (Copy)", duplicateResp.DuplicateCueList)
+	}
+	cues, err := resolver.CueRepo.FindByCueListID(ctx, duplicateResp.DuplicateCueList.ID)
+	if err != nil {
+		t.Fatalf("FindByCueListID() error: %v", err)
+	}
+	if len(cues) != 2 || cues[0].SceneID != sceneID || cues[1].FadeInTime != 2 {
+		t.Errorf("Duplicated cues = %+v, want two cues on the original scene", cues)
+	}
+
+	// Copying to another project copies the shared scene once
+	var copyListResp struct {
+		CopyCueListToProject struct {
+			CueList struct {
+				ID string `json:"id"`
+			} `json:"cueList"`
+			Warnings []string `json:"warnings"`
+		} `json:"copyCueListToProject"`
+	}
+	err = c.Post(`mutation($id: ID!, $projectId: ID!) {
+		copyCueListToProject(cueListId: $id, targetProjectId: $projectId, newName: "Venue Show") { cueList { id } warnings }
+	}`, &copyListResp, client.Var("id", cueListID), client.Var("projectId", target))
+	if err != nil {
+		t.Fatalf("CopyCueListToProject mutation failed: %v", err)
+	}
+	if warnings := copyListResp.CopyCueListToProject.Warnings; len(warnings) != 1 || !strings.Contains(warnings[0], "Spot") {
+		t.Errorf("Warnings = %v, want one naming Spot", warnings)
+	}
+	cues, err = resolver.CueRepo.FindByCueListID(ctx, copyListResp.CopyCueListToProject.CueList.ID)
+	if err != nil {
+		t.Fatalf("FindByCueListID() error: %v", err)
+	}
+	if len(cues) != 2 || cues[0].SceneID == sceneID || cues[0].SceneID != cues[1].SceneID {
+		t.Errorf("Copied cues = %+v, want both on one copied scene", cues)
+	}
+	scenes, err := resolver.SceneRepo.FindByProjectID(ctx, target)
+	if err != nil {
+		t.Fatalf("FindByProjectID() error: %v", err)
+	}
+	if len(scenes) != 2 {
+		t.Errorf("Target project has %d scenes, want 2", len(scenes))
+	}
+
+	err = c.Post(`mutation($sceneId: ID!) {
+		copySceneToProject(sceneId: $sceneId, targetProjectId: "missing") { warnings }
+	}`, &copyResp, client.Var("sceneId", sceneID))
+	if err == nil || !strings.Contains(err.Error(), "project not found") {
+		t.Errorf("Expected project not found, got %v", err)
+	}
+}
+
+func TestRenumberAndInsertCueBetween(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-numbering", Name: "Numbering Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "num-scene", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.CueList{ID: "num-list", Name: "List", ProjectID: project.ID})
+	for i, number := range []float64{2, 3, 3.001} {
+		id := fmt.Sprintf("num-%d", i+1)
+		resolver.db.Create(&models.Cue{ID: id, Name: id, CueListID: "num-list", SceneID: "num-scene", CueNumber: number})
+	}
+
+	type cue struct {
+		ID        string  `json:"id"`
+		CueNumber float64 `json:"cueNumber"`
+	}
+	insert := func(afterCueID *string) cue {
+		t.Helper()
+		var resp struct {
+			InsertCueBetween cue `json:"insertCueBetween"`
+		}
+		err := c.Post(`mutation($after: ID) {
+			insertCueBetween(cueListId: "num-list", afterCueId: $after, input: { name: "", sceneId: "num-scene", fadeInTime: 1, fadeOutTime: 1 }) { id cueNumber }
+		}`, &resp, client.Var("after", afterCueID))
+		if err != nil {
+			t.Fatalf("insertCueBetween mutation failed: %v", err)
+		}
+		return resp.InsertCueBetween
+	}
+	numbers := func() []float64 {
+		t.Helper()
+		cues, err := resolver.CueRepo.FindByCueListID(context.Background(), "num-list")
+		if err != nil {
+			t.Fatalf("Failed to load cues: %v", err)
+		}
+		var result []float64
+		for _, c := range cues {
+			result = append(result, c.CueNumber)
+		}
+		return result
+	}
+
+	after := "num-1"
+	if got := insert(&after); got.CueNumber != 2.5 {
+		t.Errorf("Expected cue 2.5 between 2 and 3, got %v", got.CueNumber)
+	}
+	if got := insert(nil); got.CueNumber != 1 {
+		t.Errorf("Expected cue 1 before cue 2, got %v", got.CueNumber)
+	}
+
+	// No number fits between 3 and 3.001, so the list is renumbered first,
+	// and back again if the cue then fails to save
+	after = "num-2"
+	var undoEntries int64
+	resolver.db.Model(&models.UndoOperation{}).Where("project_id = ?", project.ID).Count(&undoEntries)
+	err := resolver.db.Callback().Create().Before("gorm:create").Register("test:fail_cue", func(db *gorm.DB) {
+		if db.Statement.Table == "cues" {
+			_ = db.AddError(errors.New("disk full"))
+		}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register create callback: %v", err)
+	}
+	var failedResp map[string]interface{}
+	err = c.Post(`mutation($after: ID) {
+		insertCueBetween(cueListId: "num-list", afterCueId: $after, input: { name: "", sceneId: "num-scene", fadeInTime: 1, fadeOutTime: 1 }) { id }
+	}`, &failedResp, client.Var("after", after))
+	if err == nil {
+		t.Error("Expected the failed save to fail the insert")
+	}
+	if err := resolver.db.Callback().Create().Remove("test:fail_cue"); err != nil {
+		t.Fatalf("Failed to remove create callback: %v", err)
+	}
+	if want := []float64{1, 2, 2.5, 3, 3.001}; fmt.Sprint(numbers()) != fmt.Sprint(want) {
+		t.Errorf("Expected cue numbers %v after the failed insert, got %v", want, numbers())
+	}
+	var undoEntriesAfter int64
+	resolver.db.Model(&models.UndoOperation{}).Where("project_id = ?", project.ID).Count(&undoEntriesAfter)
+	if undoEntriesAfter != undoEntries {
+		t.Errorf("Undo entries = %d after the failed insert, want %d", undoEntriesAfter, undoEntries)
+	}
+	inserted := insert(&after)
+	if want := []float64{1, 2, 3, 4, 4.5, 5}; fmt.Sprint(numbers()) != fmt.Sprint(want) {
+		t.Errorf("Expected cue numbers %v after resequencing, got %v", want, numbers())
+	}
+	if inserted.CueNumber != 4.5 {
+		t.Errorf("Expected the inserted cue at 4.5, got %v", inserted.CueNumber)
+	}
+
+	var renumberResp struct {
+		RenumberCues []cue `json:"renumberCues"`
+	}
+	if err := c.Post(`mutation { renumberCues(cueListId: "num-list", startNumber: 10, increment: 0.1) { id cueNumber } }`, &renumberResp); err != nil {
+		t.Fatalf("renumberCues mutation failed: %v", err)
+	}
+	if want := []float64{10, 10.1, 10.2, 10.3, 10.4, 10.5}; fmt.Sprint(numbers()) != fmt.Sprint(want) {
+		t.Errorf("Expected cue numbers %v, got %v", want, numbers())
+	}
+	if len(renumberResp.RenumberCues) != 6 || renumberResp.RenumberCues[4].ID != inserted.ID {
+		t.Errorf("Expected the renumbered cues in order, got %+v", renumberResp.RenumberCues)
+	}
+	if err := c.Post(`mutation { renumberCues(cueListId: "num-list", increment: 0) { id } }`, &renumberResp); err == nil {
+		t.Error("Expected a zero increment to be rejected")
+	}
+}
+
+func TestProjectArchive_DownloadAndImport(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-archive", Name: "Archive Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "archive-scene", Name: "Warm Wash", ProjectID: project.ID})
+
+	var resp struct {
+		CreateProjectArchiveDownload struct {
+			URL       string `json:"url"`
+			FileName  string `json:"fileName"`
+			ExpiresAt string `json:"expiresAt"`
+		} `json:"createProjectArchiveDownload"`
+	}
+	err := c.Post(`mutation {
+		createProjectArchiveDownload(projectId: "test-project-archive") { url fileName expiresAt }
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("createProjectArchiveDownload mutation failed: %v", err)
+	}
+	if resp.CreateProjectArchiveDownload.FileName != "Archive Project.llx" {
+		t.Errorf("Expected file name 'Archive Project.llx', got %q", resp.CreateProjectArchiveDownload.FileName)
+	}
+
+	rec := httptest.NewRecorder()
+	resolver.ArchiveDownloads.ServeArchive(rec, httptest.NewRequest(http.MethodGet, resp.CreateProjectArchiveDownload.URL, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	name := "Imported Archive"
+	result, err := resolver.Mutation().ImportProjectArchive(context.Background(),
+		graphql.Upload{File: bytes.NewReader(rec.Body.Bytes()), Filename: resp.CreateProjectArchiveDownload.FileName},
+		generated.ImportOptionsInput{Mode: generated.ImportModeCreate, ProjectName: graphql.OmittableOf(&name)})
+	if err != nil {
+		t.Fatalf("ImportProjectArchive failed: %v", err)
+	}
+	if result.ProjectID == project.ID || result.Stats.ScenesCreated != 1 {
+		t.Errorf("Expected a new project with 1 scene, got %+v", result)
+	}
+	scenes, err := resolver.SceneRepo.FindByProjectID(context.Background(), result.ProjectID)
+	if err != nil {
+		t.Fatalf("Failed to load scenes: %v", err)
+	}
+	if len(scenes) != 1 || scenes[0].Name != "Warm Wash" {
+		t.Errorf("Expected the imported scene 'Warm Wash', got %+v", scenes)
+	}
+
+	if _, err := resolver.Mutation().ImportProjectArchive(context.Background(),
+		graphql.Upload{File: strings.NewReader("not an archive")},
+		generated.ImportOptionsInput{Mode: generated.ImportModeCreate}); err == nil {
+		t.Error("Expected an invalid archive to be rejected")
+	}
+}
+
+func TestExportProject_SelectedScenes(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-selective", Name: "Selective Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "selective-scene-1", Name: "One", ProjectID: project.ID})
+	resolver.db.Create(&models.Scene{ID: "selective-scene-2", Name: "Two", ProjectID: project.ID})
+
+	var resp struct {
+		ExportProject struct {
+			Stats struct {
+				ScenesCount int      `json:"scenesCount"`
+				SceneIds    []string `json:"sceneIds"`
+				CueListIds  []string `json:"cueListIds"`
+			} `json:"stats"`
+		} `json:"exportProject"`
+	}
+	err := c.Post(`mutation {
+		exportProject(projectId: "test-project-selective", options: { sceneIds: ["selective-scene-2"] }) {
+			stats { scenesCount sceneIds cueListIds }
+		}
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("exportProject mutation failed: %v", err)
+	}
+	stats := resp.ExportProject.Stats
+	if stats.ScenesCount != 1 || len(stats.SceneIds) != 1 || stats.SceneIds[0] != "selective-scene-2" {
+		t.Errorf("Expected only scene selective-scene-2, got %+v", stats)
+	}
+	if stats.CueListIds == nil || len(stats.CueListIds) != 0 {
+		t.Errorf("Expected an empty cue list selection, got %v", stats.CueListIds)
+	}
+}
+
+func TestImportProject_DryRun(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var resp struct {
+		ImportProject struct {
+			ProjectID string `json:"projectId"`
+			DryRun    bool   `json:"dryRun"`
+			Changes   []struct {
+				EntityType string `json:"entityType"`
+				Name       string `json:"name"`
+				Action     string `json:"action"`
+			} `json:"changes"`
+		} `json:"importProject"`
+	}
+	err := c.Post(`mutation($json: String!) {
+		importProject(jsonContent: $json, options: { mode: CREATE, dryRun: true }) {
+			projectId dryRun changes { entityType name action }
+		}
+	}`, &resp, client.Var("json", `{"version":"1.0","project":{"name":"Preview"},"scenes":[{"refId":"s1","name":"Look"}]}`))
+	if err != nil {
+		t.Fatalf("importProject mutation failed: %v", err)
+	}
+	result := resp.ImportProject
+	if !result.DryRun || result.ProjectID != "" {
+		t.Errorf("Expected a dry run without a project, got %+v", result)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].EntityType != "PROJECT" || result.Changes[1].Name != "Look" || result.Changes[1].Action != "CREATE" {
+		t.Errorf("Expected the project and scene to be created, got %+v", result.Changes)
+	}
+
+	var count int64
+	resolver.db.Model(&models.Project{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the dry run to create no project, found %d", count)
+	}
+}
+
+func TestPatchSheet_ImportAndExport(t *testing.T) {
+	_, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: "test-project-sheet", Name: "Sheet Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "sheet-dimmer", Manufacturer: "SheetMfg", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.ChannelDefinition{ID: "sheet-dimmer-ch", Name: "Intensity", Type: "INTENSITY", DefinitionID: "sheet-dimmer"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "sheet-spot", Manufacturer: "SheetMfg", Model: "Spot", Type: "MOVING_HEAD"})
+	for i, name := range []string{"Intensity", "Pan", "Tilt"} {
+		resolver.db.Create(&models.ChannelDefinition{ID: fmt.Sprintf("sheet-spot-ch-%d", i), Name: name, Type: "OTHER", Offset: i, DefinitionID: "sheet-spot"})
+	}
+	resolver.db.Create(&models.FixtureMode{ID: "sheet-spot-basic", Name: "Basic", ChannelCount: 1, DefinitionID: "sheet-spot"})
+	resolver.db.Create(&models.ModeChannel{ID: "sheet-spot-mc", ModeID: "sheet-spot-basic", ChannelID: "sheet-spot-ch-0"})
+	for name, address := range map[string]int{"Front": 1, "Side": 2, "Back": 3, "Spare": 50} {
+		resolver.db.Create(&models.FixtureInstance{
+			ID: "sheet-fixture-" + name, Name: name, DefinitionID: "sheet-dimmer", ProjectID: project.ID,
+			Universe: 1, StartChannel: address, ChannelCount: intPtr(1),
+		})
+	}
+
+	// Front moves out of the way of a new spot, Side and Back swap, Spare is
+	// left alone, and the last three rows can't be imported
+	result, err := resolver.Mutation().ImportPatchSheet(ctx, project.ID, `name,manufacturer,model,mode,universe,address,tags,x
+Front,SheetMfg,Dimmer,,1,10,"front, wash",0.25
+Side,SheetMfg,Dimmer,,1,3,,
+Back,SheetMfg,Dimmer,,1,2,,
+Spot,sheetmfg,SPOT,basic,1,1,,
+Mystery,OtherMfg,Par,,1,20,,
+Wrong Mode,SheetMfg,Spot,16ch,1,30,,
+Clash,SheetMfg,Dimmer,,1,50,,
+`)
+	if err != nil {
+		t.Fatalf("importPatchSheet failed: %v", err)
+	}
+	if len(result.Created) != 1 || result.Created[0].Name != "Spot" || *result.Created[0].ChannelCount != 1 {
+		t.Errorf("Expected Spot to be created with 1 channel, got %+v", result.Created)
+	}
+	if len(result.Updated) != 3 {
+		t.Errorf("Expected 3 fixtures updated, got %d", len(result.Updated))
+	}
+	var unmatched []string
+	for _, row := range result.UnmatchedRows {
+		unmatched = append(unmatched, fmt.Sprintf("%d: %s", row.Line, row.Reason))
+	}
+	if len(unmatched) != 3 || !strings.HasPrefix(unmatched[0], "6: no fixture definition") ||
+		!strings.HasPrefix(unmatched[1], "7: SheetMfg Spot has no mode") || !strings.HasPrefix(unmatched[2], "8: DMX address conflict") {
+		t.Errorf("Expected lines 6-8 unmatched, got %v", unmatched)
+	}
+
+	front, _ := resolver.FixtureRepo.FindByID(ctx, "sheet-fixture-Front")
+	if front.StartChannel != 10 || front.Tags == nil || *front.Tags != `["front","wash"]` || front.LayoutX == nil || *front.LayoutX != 0.25 {
+		t.Errorf("Unexpected Front %+v", front)
+	}
+	back, _ := resolver.FixtureRepo.FindByID(ctx, "sheet-fixture-Back")
+	if back.StartChannel != 2 {
+		t.Errorf("Expected Back to move to channel 2, got %d", back.StartChannel)
+	}
+
+	sheet, err := resolver.Query().ExportPatchSheet(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("exportPatchSheet failed: %v", err)
+	}
+	for _, line := range []string{
+		"Spot,SheetMfg,Spot,Basic,1,1,,,,",
+		`Front,SheetMfg,Dimmer,,1,10,"front, wash",0.25,,`,
+	} {
+		if !strings.Contains(sheet, line+"\n") {
+			t.Errorf("Expected %q in the sheet:\n%s", line, sheet)
+		}
+	}
+
+	// Importing the exported sheet changes nothing
+	result, err = resolver.Mutation().ImportPatchSheet(ctx, project.ID, sheet)
+	if err != nil {
+		t.Fatalf("importPatchSheet failed: %v", err)
+	}
+	if len(result.Created)+len(result.Updated)+len(result.UnmatchedRows) != 0 {
+		t.Errorf("Expected no changes, got %+v", result)
+	}
+}
+
+func TestGenerateReport(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-report", Name: "Report Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "report-scene", Name: "Warm Wash", ProjectID: project.ID})
+	resolver.db.Create(&models.CueList{ID: "report-list", Name: "Main", ProjectID: project.ID})
+	resolver.db.Create(&models.Cue{ID: "report-cue", Name: "Preset", CueNumber: 1, CueListID: "report-list", SceneID: "report-scene"})
+
+	var resp struct {
+		GenerateReport struct {
+			URL       string `json:"url"`
+			FileName  string `json:"fileName"`
+			ExpiresAt string `json:"expiresAt"`
+		} `json:"generateReport"`
+	}
+	err := c.Post(`mutation {
+		generateReport(projectId: "test-project-report", type: CUE_SHEET, cueListId: "report-list") { url fileName expiresAt }
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("generateReport mutation failed: %v", err)
+	}
+	if resp.GenerateReport.FileName != "Report Project - Cue Sheet.html" {
+		t.Errorf("Expected file name 'Report Project - Cue Sheet.html', got %q", resp.GenerateReport.FileName)
+	}
+
+	rec := httptest.NewRecorder()
+	resolver.ReportDownloads.ServeReport(rec, httptest.NewRequest(http.MethodGet, resp.GenerateReport.URL, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "<td>Preset</td><td>Warm Wash</td>") {
+		t.Errorf("Expected the cue in the report, got %s", rec.Body.String())
+	}
+
+	if _, err := resolver.Mutation().GenerateReport(context.Background(), "missing-project", generated.ReportTypeChannelHookup, nil); err == nil {
+		t.Error("Expected an error for a missing project")
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	defer resolver.SchedulerService.Cleanup()
+
+	project := &models.Project{ID: "test-project-schedule", Name: "Schedule Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Project{ID: "other-project-schedule", Name: "Other Project"})
+	resolver.db.Create(&models.Scene{ID: "schedule-scene", Name: "Facade", ProjectID: project.ID})
+	resolver.db.Create(&models.Scene{ID: "other-scene", Name: "Other", ProjectID: "other-project-schedule"})
+
+	var resp struct {
+		CreateSchedule struct {
+			ID         string   `json:"id"`
+			Trigger    string   `json:"trigger"`
+			Days       []string `json:"days"`
+			NextFireAt *string  `json:"nextFireAt"`
+			Scene      struct {
+				Name string `json:"name"`
+			} `json:"scene"`
+		} `json:"createSchedule"`
+	}
+	err := c.Post(`mutation {
+		createSchedule(input: {
+			projectId: "test-project-schedule", name: "Facade at dusk", trigger: SUNSET,
+			latitude: 40.71, longitude: -74.01, offsetMinutes: -15, days: [FRIDAY, SATURDAY],
+			action: ACTIVATE_SCENE, sceneId: "schedule-scene", fadeTime: 10
+		}) { id trigger days nextFireAt scene { name } }
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("createSchedule mutation failed: %v", err)
+	}
+	created := resp.CreateSchedule
+	if created.Trigger != "SUNSET" || len(created.Days) != 2 || created.Days[0] != "FRIDAY" || created.Scene.Name != "Facade" {
+		t.Errorf("Unexpected schedule %+v", created)
+	}
+	if created.NextFireAt == nil {
+		t.Error("Expected an enabled schedule to have a next fire time")
+	}
+
+	ctx := context.Background()
+	if _, err := resolver.Mutation().CreateSchedule(ctx, generated.CreateScheduleInput{
+		ProjectID: project.ID, Name: "Wrong scene", Trigger: generated.ScheduleTriggerCron,
+		Cron: graphql.OmittableOf(stringPtr("0 19 * * *")), Action: generated.ScheduleActionActivateScene,
+		SceneID: graphql.OmittableOf(stringPtr("other-scene")),
+	}); err == nil {
+		t.Error("Expected a scene from another project to be rejected")
+	}
+	if _, err := resolver.Mutation().CreateSchedule(ctx, generated.CreateScheduleInput{
+		ProjectID: project.ID, Name: "Bad cron", Trigger: generated.ScheduleTriggerCron,
+		Cron: graphql.OmittableOf(stringPtr("at seven")), Action: generated.ScheduleActionActivateScene,
+		SceneID: graphql.OmittableOf(stringPtr("schedule-scene")),
+	}); err == nil {
+		t.Error("Expected an invalid cron expression to be rejected")
+	}
+
+	disabled := false
+	schedule, err := resolver.Mutation().UpdateSchedule(ctx, created.ID, generated.UpdateScheduleInput{Enabled: graphql.OmittableOf(&disabled)})
+	if err != nil {
+		t.Fatalf("UpdateSchedule failed: %v", err)
+	}
+	if next, _ := resolver.Schedule().NextFireAt(ctx, schedule); next != nil {
+		t.Errorf("Expected a disabled schedule not to fire, got %s", *next)
+	}
+
+	sub := resolver.PubSub.Subscribe(pubsub.TopicScheduleFired, project.ID, 1)
+	defer resolver.PubSub.Unsubscribe(sub)
+	event, err := resolver.Mutation().FireSchedule(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("FireSchedule failed: %v", err)
+	}
+	if event.Error != nil || event.ScheduleName != "Facade at dusk" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	select {
+	case msg := <-sub.Channel:
+		if published, ok := msg.(*generated.ScheduleFiredEvent); !ok || published.ScheduleID != created.ID {
+			t.Errorf("Unexpected published event %+v", msg)
+		}
+	default:
+		t.Error("Expected the firing to be published")
+	}
+	schedule, _ = resolver.Query().Schedule(ctx, created.ID)
+	if schedule == nil || schedule.LastFiredAt == nil {
+		t.Errorf("Expected the firing to be recorded, got %+v", schedule)
+	}
+
+	if _, err := resolver.Mutation().DeleteProject(ctx, project.ID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	schedules, err := resolver.Query().Schedules(ctx, project.ID)
+	if err != nil || len(schedules) != 0 {
+		t.Errorf("Expected the project's schedules to be deleted, got %d (%v)", len(schedules), err)
+	}
+}
+
+func TestSoftPatch(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-softpatch", Name: "Softpatch Project"}
+	resolver.db.Create(project)
+	resolver.DMXService.SetChannelValue(1, 1, 200)
+
+	type softPatchResp struct {
+		ID       string `json:"id"`
+		Universe int    `json:"universe"`
+		Channel  int    `json:"channel"`
+		Targets  []struct {
+			Universe int `json:"universe"`
+			Channel  int `json:"channel"`
+		} `json:"targets"`
+	}
+	const setMutation = `mutation Set($input: SoftPatchInput!) {
+		setSoftPatch(input: $input) { id universe channel targets { universe channel } }
+	}`
+	var resp struct {
+		SetSoftPatch softPatchResp `json:"setSoftPatch"`
+	}
+	input := map[string]interface{}{
+		"projectId": project.ID, "universe": 1, "channel": 1,
+		"targets": []map[string]interface{}{{"universe": 1, "channel": 5}, {"universe": 2, "channel": 5}},
+	}
+	if err := c.Post(setMutation, &resp, client.Var("input", input)); err != nil {
+		t.Fatalf("setSoftPatch mutation failed: %v", err)
+	}
+	created := resp.SetSoftPatch
+	if len(created.Targets) != 2 || created.Targets[1].Universe != 2 {
+		t.Errorf("Unexpected softpatch entry %+v", created)
+	}
+
+	var outputResp struct {
+		PatchedDmxOutput []int `json:"patchedDmxOutput"`
+		DmxOutput        []int `json:"dmxOutput"`
+	}
+	if err := c.Post(`query { patchedDmxOutput(universe: 2) dmxOutput(universe: 1) }`, &outputResp); err != nil {
+		t.Fatalf("patchedDmxOutput query failed: %v", err)
+	}
+	if outputResp.PatchedDmxOutput[4] != 200 || outputResp.DmxOutput[0] != 200 {
+		t.Errorf("Expected channel 1 patched to 2/5 and kept logically, got %d and %d", outputResp.PatchedDmxOutput[4], outputResp.DmxOutput[0])
+	}
+	if patched := resolver.DMXService.PatchedUniverse(1); patched[0] != 0 || patched[4] != 200 {
+		t.Errorf("Expected channel 1 moved to 5, got %v", patched[:5])
+	}
+
+	// Setting the same channel again replaces its entry
+	input["targets"] = []map[string]interface{}{}
+	if err := c.Post(setMutation, &resp, client.Var("input", input)); err != nil {
+		t.Fatalf("setSoftPatch mutation failed: %v", err)
+	}
+	if resp.SetSoftPatch.ID != created.ID || len(resp.SetSoftPatch.Targets) != 0 {
+		t.Errorf("Expected the entry to be replaced, got %+v", resp.SetSoftPatch)
+	}
+	if patched := resolver.DMXService.PatchedUniverse(1); patched[0] != 0 || patched[4] != 0 {
+		t.Errorf("Expected channel 1 parked, got %v", patched[:5])
+	}
+
+	input["targets"] = []map[string]interface{}{{"universe": 1, "channel": 513}}
+	if err := c.Post(setMutation, &resp, client.Var("input", input)); err == nil {
+		t.Error("Expected an invalid target to be rejected")
+	}
+
+	var listResp struct {
+		SoftPatches []softPatchResp `json:"softPatches"`
+	}
+	if err := c.Post(`query { softPatches(projectId: "test-project-softpatch") { id universe channel targets { universe channel } } }`, &listResp); err != nil {
+		t.Fatalf("softPatches query failed: %v", err)
+	}
+	if len(listResp.SoftPatches) != 1 {
+		t.Fatalf("Expected 1 softpatch entry, got %d", len(listResp.SoftPatches))
+	}
+
+	var clearResp struct {
+		ClearSoftPatch int `json:"clearSoftPatch"`
+	}
+	if err := c.Post(`mutation { clearSoftPatch(projectId: "test-project-softpatch") }`, &clearResp); err != nil {
+		t.Fatalf("clearSoftPatch mutation failed: %v", err)
+	}
+	if clearResp.ClearSoftPatch != 1 {
+		t.Errorf("Expected 1 entry cleared, got %d", clearResp.ClearSoftPatch)
+	}
+	if patched := resolver.DMXService.PatchedUniverse(1); patched[0] != 200 {
+		t.Errorf("Expected the direct mapping restored, got %d", patched[0])
+	}
+
+	var deleteResp struct {
+		DeleteSoftPatch bool `json:"deleteSoftPatch"`
+	}
+	if err := c.Post(`mutation { deleteSoftPatch(id: "missing") }`, &deleteResp); err == nil {
+		t.Error("Expected deleting a missing entry to fail")
+	}
+}
+
+func TestUniverses(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: "test-project-universes", Name: "Universe Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "universe-dimmer", Manufacturer: "UniverseMfg", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{
+		ID: "universe-fixture", Name: "Front", DefinitionID: "universe-dimmer", ProjectID: project.ID,
+		Universe: 2, StartChannel: 1, ChannelCount: intPtr(1),
+	})
+
+	type universeResp struct {
+		ID           string  `json:"id"`
+		Number       int     `json:"number"`
+		Label        *string `json:"label"`
+		Protocol     string  `json:"protocol"`
+		Destination  *string `json:"destination"`
+		Enabled      bool    `json:"enabled"`
+		FixtureCount int     `json:"fixtureCount"`
+	}
+	var createResp struct {
+		CreateUniverse universeResp `json:"createUniverse"`
+	}
+	const createMutation = `mutation Create($input: CreateUniverseInput!) {
+		createUniverse(input: $input) { id number label protocol destination enabled fixtureCount }
+	}`
+	input := map[string]interface{}{"projectId": project.ID, "number": 1, "label": " FOH Truss "}
+	if err := c.Post(createMutation, &createResp, client.Var("input", input)); err != nil {
+		t.Fatalf("createUniverse mutation failed: %v", err)
+	}
+	created := createResp.CreateUniverse
+	if created.Label == nil || *created.Label != "FOH Truss" || created.Protocol != "ARTNET" || !created.Enabled {
+		t.Errorf("Unexpected universe %+v", created)
+	}
+	if err := c.Post(createMutation, &createResp, client.Var("input", input)); err == nil {
+		t.Error("Expected a duplicate universe to be rejected")
+	}
+	input["number"] = dmx.MaxUniverses + 1
+	if err := c.Post(createMutation, &createResp, client.Var("input", input)); err == nil {
+		t.Error("Expected an out of range universe to be rejected")
+	}
+
+	const updateMutation = `mutation Update($id: ID!, $input: UpdateUniverseInput!) {
+		updateUniverse(id: $id, input: $input) { id enabled destination }
+	}`
+	var updateResp struct {
+		UpdateUniverse universeResp `json:"updateUniverse"`
+	}
+	if err := c.Post(updateMutation, &updateResp, client.Var("id", created.ID),
+		client.Var("input", map[string]interface{}{"destination": "not an address"})); err == nil {
+		t.Error("Expected an invalid destination to be rejected")
+	}
+	if err := c.Post(updateMutation, &updateResp, client.Var("id", created.ID),
+		client.Var("input", map[string]interface{}{"enabled": false, "destination": "10.0.0.5"})); err != nil {
+		t.Fatalf("updateUniverse mutation failed: %v", err)
+	}
+	if updateResp.UpdateUniverse.Enabled || updateResp.UpdateUniverse.Destination == nil {
+		t.Errorf("Unexpected universe %+v", updateResp.UpdateUniverse)
+	}
+
+	// The universe a fixture is patched to is configured for it
+	var listResp struct {
+		Universes []universeResp `json:"universes"`
+	}
+	if err := c.Post(`query { universes(projectId: "test-project-universes") { id number label enabled fixtureCount } }`, &listResp); err != nil {
+		t.Fatalf("universes query failed: %v", err)
+	}
+	if len(listResp.Universes) != 2 || listResp.Universes[1].Number != 2 || !listResp.Universes[1].Enabled || listResp.Universes[1].FixtureCount != 1 {
+		t.Fatalf("Expected universes 1 and 2, got %+v", listResp.Universes)
+	}
+	patched := listResp.Universes[1]
+
+	var fixtureResp struct {
+		FixtureInstance struct {
+			UniverseConfig *universeResp `json:"universeConfig"`
+		} `json:"fixtureInstance"`
+	}
+	if err := c.Post(`query { fixtureInstance(id: "universe-fixture") { universeConfig { id number } } }`, &fixtureResp); err != nil {
+		t.Fatalf("fixtureInstance query failed: %v", err)
+	}
+	if config := fixtureResp.FixtureInstance.UniverseConfig; config == nil || config.ID != patched.ID {
+		t.Errorf("Expected the fixture's universe %s, got %+v", patched.ID, config)
+	}
+
+	var deleteResp struct {
+		DeleteUniverse bool `json:"deleteUniverse"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deleteUniverse(id: $id) }`, &deleteResp, client.Var("id", patched.ID)); err == nil {
+		t.Error("Expected deleting a universe with patched fixtures to be rejected")
+	}
+
+	// Universes travel with the project's fixtures
+	exported, _, err := resolver.ExportService.ExportProjectWithOptions(ctx, project.ID, export.DefaultExportOptions())
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(exported.Universes) != 2 || exported.Universes[0].Label == nil || exported.Universes[0].Enabled {
+		t.Fatalf("Unexpected exported universes %+v", exported.Universes)
+	}
+	newProjectID, _, _, err := resolver.ImportService.ImportExported(ctx, exported, importservice.ImportOptions{Mode: importservice.ImportModeCreate})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	imported, _ := resolver.UniverseRepo.FindByNumber(ctx, newProjectID, 1)
+	if imported == nil || *imported.Label != "FOH Truss" || imported.Enabled || *imported.Destination != "10.0.0.5" {
+		t.Errorf("Unexpected imported universe %+v", imported)
+	}
+
+	if err := c.Post(`mutation($id: ID!) { deleteUniverse(id: $id) }`, &deleteResp, client.Var("id", created.ID)); err != nil || !deleteResp.DeleteUniverse {
+		t.Errorf("deleteUniverse mutation failed: %v", err)
+	}
+}
+
+func TestDuplicateFixtureInstance(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: "test-project-duplicate", Name: "Duplicate Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "duplicate-par", Manufacturer: "DuplicateMfg", Model: "Par", Type: "LED_PAR"})
+	layoutX, maxIntensity := 0.9, 0.8
+	resolver.db.Create(&models.FixtureInstance{
+		ID: "duplicate-fixture", Name: "Par 3", DefinitionID: "duplicate-par", ProjectID: project.ID,
+		Universe: 1, StartChannel: 1, ChannelCount: intPtr(4), LayoutX: &layoutX, MaxIntensity: &maxIntensity,
+	})
+	for i, name := range []string{"Red", "Green", "Blue", "Dimmer"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("duplicate-ch-%d", i), FixtureID: "duplicate-fixture", Offset: i, Name: name, Type: "OTHER"})
+	}
+	resolver.db.Create(&models.FixtureInstance{
+		ID: "duplicate-blocker", Name: "Par 5", DefinitionID: "duplicate-par", ProjectID: project.ID,
+		Universe: 1, StartChannel: 9, ChannelCount: intPtr(2),
+	})
+
+	var resp struct {
+		DuplicateFixtureInstance []struct {
+			ID           string   `json:"id"`
+			Name         string   `json:"name"`
+			Universe     int      `json:"universe"`
+			StartChannel int      `json:"startChannel"`
+			LayoutX      *float64 `json:"layoutX"`
+			LayoutY      *float64 `json:"layoutY"`
+			MaxIntensity *float64 `json:"maxIntensity"`
+			Channels     []struct {
+				Name string `json:"name"`
+			} `json:"channels"`
+		} `json:"duplicateFixtureInstance"`
+	}
+	if err := c.Post(`mutation { duplicateFixtureInstance(id: "duplicate-fixture", count: 2) {
+		id name universe startChannel layoutX layoutY maxIntensity channels { name }
+	} }`, &resp); err != nil {
+		t.Fatalf("duplicateFixtureInstance mutation failed: %v", err)
+	}
+	copies := resp.DuplicateFixtureInstance
+	if len(copies) != 2 {
+		t.Fatalf("Expected 2 copies, got %d", len(copies))
+	}
+
+	// Par 5 is taken, in name and at channels 9-10
+	for i, want := range []struct {
+		name    string
+		channel int
+		layoutX float64
+	}{{"Par 4", 5, 0.95}, {"Par 6", 11, 1}} {
+		got := copies[i]
+		if got.Name != want.name || got.Universe != 1 || got.StartChannel != want.channel {
+			t.Errorf("Expected %s at 1/%d, got %s at %d/%d", want.name, want.channel, got.Name, got.Universe, got.StartChannel)
+		}
+		if got.LayoutX == nil || *got.LayoutX < want.layoutX-1e-9 || *got.LayoutX > want.layoutX+1e-9 || got.LayoutY != nil {
+			t.Errorf("Expected %s at x %.2f with no y, got %v, %v", want.name, want.layoutX, got.LayoutX, got.LayoutY)
+		}
+		if got.MaxIntensity == nil || *got.MaxIntensity != maxIntensity || len(got.Channels) != 4 || got.Channels[3].Name != "Dimmer" {
+			t.Errorf("Expected %s to copy the fixture's settings and channels, got %+v", want.name, got)
+		}
+	}
+	if channels, _ := resolver.FixtureRepo.GetInstanceChannels(ctx, "duplicate-fixture"); len(channels) != 4 {
+		t.Errorf("Expected the original to keep its 4 channels, got %d", len(channels))
+	}
+
+	if err := c.Post(`mutation { duplicateFixtureInstance(id: "duplicate-fixture", count: 0) { id } }`, &resp); err == nil {
+		t.Error("Expected a count of 0 to be rejected")
+	}
+}
+
+// TestDuplicateFixtureInstance_FailureCreatesNothing tests that a copy that
+// fails to save leaves none of the earlier copies behind.
+func TestDuplicateFixtureInstance_FailureCreatesNothing(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-duplicate-fail", Name: "Duplicate Failure Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "duplicate-fail-par", Manufacturer: "DuplicateMfg", Model: "Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{
+		ID: "duplicate-fail-fixture", Name: "Par 1", DefinitionID: "duplicate-fail-par", ProjectID: project.ID,
+		Universe: 1, StartChannel: 1, ChannelCount: intPtr(2),
+	})
+	resolver.db.Create(&models.InstanceChannel{ID: "duplicate-fail-ch", FixtureID: "duplicate-fail-fixture", Offset: 0, Name: "Dimmer", Type: "INTENSITY"})
+
+	// The third copy fails to save
+	var creates int
+	err := resolver.db.Callback().Create().Before("gorm:create").Register("test:fail_third_fixture", func(db *gorm.DB) {
+		if db.Statement.Table == "fixture_instances" {
+			if creates++; creates == 3 {
+				_ = db.AddError(errors.New("disk full"))
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register create callback: %v", err)
+	}
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation { duplicateFixtureInstance(id: "duplicate-fail-fixture", count: 4) { id } }`, &resp); err == nil {
+		t.Fatal("Expected the failed copy to fail the duplication")
+	}
+	var fixtures, channels, undoEntries int64
+	resolver.db.Model(&models.FixtureInstance{}).Where("project_id = ?", project.ID).Count(&fixtures)
+	resolver.db.Model(&models.InstanceChannel{}).Count(&channels)
+	resolver.db.Model(&models.UndoOperation{}).Where("project_id = ?", project.ID).Count(&undoEntries)
+	if fixtures != 1 || channels != 1 || undoEntries != 0 {
+		t.Errorf("Expected only the original fixture and channel and no undo step, got %d fixtures, %d channels, %d undo steps", fixtures, channels, undoEntries)
+	}
+}
+
+func TestLayoutZonesAndAlignment(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: "test-project-layout", Name: "Layout Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "layout-par", Manufacturer: "LayoutMfg", Model: "Par", Type: "LED_PAR"})
+	for i, position := range [][2]float64{{0.1, 0.2}, {0.5, 0.25}, {0.3, 0.3}} {
+		x, y := position[0], position[1]
+		resolver.db.Create(&models.FixtureInstance{
+			ID: fmt.Sprintf("layout-fixture-%d", i), Name: fmt.Sprintf("Par %d", i+1), DefinitionID: "layout-par",
+			ProjectID: project.ID, Universe: 1, StartChannel: i + 1, LayoutX: &x, LayoutY: &y,
+		})
+	}
+	resolver.db.Create(&models.FixtureInstance{ID: "layout-unplaced", Name: "Unplaced", DefinitionID: "layout-par", ProjectID: project.ID, Universe: 1, StartChannel: 10})
+	fixtureIDs := []string{"layout-fixture-0", "layout-fixture-1", "layout-fixture-2"}
+
+	sub := resolver.PubSub.Subscribe(pubsub.TopicLayoutChanged, project.ID, 10)
+	defer resolver.PubSub.Unsubscribe(sub)
+
+	var zoneResp struct {
+		CreateLayoutZone struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Fixtures []struct {
+				ID string `json:"id"`
+			} `json:"fixtures"`
+		} `json:"createLayoutZone"`
+	}
+	const createMutation = `mutation Create($input: CreateLayoutZoneInput!) {
+		createLayoutZone(input: $input) { id name fixtures { id } }
+	}`
+	input := map[string]interface{}{"projectId": project.ID, "name": "FOH Truss", "x": 0, "y": 0.1, "width": 0.4, "height": 0.3}
+	if err := c.Post(createMutation, &zoneResp, client.Var("input", input)); err != nil {
+		t.Fatalf("createLayoutZone mutation failed: %v", err)
+	}
+	zone := zoneResp.CreateLayoutZone
+	if zone.Name != "FOH Truss" || len(zone.Fixtures) != 2 {
+		t.Errorf("Expected FOH Truss to hold Par 1 and Par 3, got %+v", zone)
+	}
+	input["width"] = 1.5
+	if err := c.Post(createMutation, &zoneResp, client.Var("input", input)); err == nil {
+		t.Error("Expected a zone outside the layout to be rejected")
+	}
+
+	aligned, err := resolver.Mutation().AlignFixtures(ctx, fixtureIDs, generated.LayoutAlignmentTop, nil)
+	if err != nil {
+		t.Fatalf("AlignFixtures failed: %v", err)
+	}
+	for _, f := range aligned {
+		if *f.LayoutY != 0.2 {
+			t.Errorf("Expected %s aligned to the top at 0.2, got %v", f.Name, *f.LayoutY)
+		}
+	}
+	if _, err := resolver.Mutation().AlignFixtures(ctx, []string{"layout-fixture-0", "layout-unplaced"}, generated.LayoutAlignmentLeft, nil); err == nil {
+		t.Error("Expected a fixture without a position to be rejected")
+	}
+
+	// Par 1, Par 3 and Par 2 are evenly spaced from 0.1 to 0.5
+	distributed, err := resolver.Mutation().DistributeFixtures(ctx, fixtureIDs, generated.LayoutAxisHorizontal, nil)
+	if err != nil {
+		t.Fatalf("DistributeFixtures failed: %v", err)
+	}
+	for i, want := range []struct {
+		name string
+		x    float64
+	}{{"Par 1", 0.1}, {"Par 3", 0.3}, {"Par 2", 0.5}} {
+		if f := distributed[i]; f.Name != want.name || *f.LayoutX < want.x-1e-9 || *f.LayoutX > want.x+1e-9 {
+			t.Errorf("Expected %s at x %.1f, got %s at %v", want.name, want.x, f.Name, *f.LayoutX)
+		}
+	}
+
+	// Across the zone, each takes the middle of a third of it
+	distributed, err = resolver.Mutation().DistributeFixtures(ctx, fixtureIDs, generated.LayoutAxisHorizontal, &zone.ID)
+	if err != nil {
+		t.Fatalf("DistributeFixtures in a zone failed: %v", err)
+	}
+	if x := *distributed[2].LayoutX; x < 0.4*5/6-1e-9 || x > 0.4*5/6+1e-9 {
+		t.Errorf("Expected the last fixture at %v, got %v", 0.4*5/6, x)
+	}
+
+	// Each change reached the layout's subscribers
+	var changes []*generated.LayoutChange
+	for len(sub.Channel) > 0 {
+		changes = append(changes, (<-sub.Channel).(*generated.LayoutChange))
+	}
+	if len(changes) != 4 || len(changes[0].Zones) != 1 || len(changes[1].Fixtures) != 3 {
+		t.Errorf("Expected a zone and three fixture changes published, got %d", len(changes))
+	}
+
+	undo, _, _ := resolver.UndoRepo.Status(ctx, project.ID)
+	if len(undo) == 0 || undo[0].Description != "Distribute fixtures" {
+		t.Errorf("Expected the distribution to be undoable, got %+v", undo)
+	}
+
+	if ok, err := resolver.Mutation().DeleteLayoutZone(ctx, zone.ID); err != nil || !ok {
+		t.Errorf("DeleteLayoutZone failed: %v", err)
+	}
+	if change := (<-sub.Channel).(*generated.LayoutChange); len(change.DeletedZoneIds) != 1 {
+		t.Errorf("Expected the deletion to be published, got %+v", change)
+	}
+}
+
+func TestSelectionSets(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project := &models.Project{ID: "test-project-selection", Name: "Selection Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "selection-par", Manufacturer: "SelectionMfg", Model: "Par", Type: "LED_PAR"})
+	var fixtureIDs []string
+	for i, x := range []float64{0.4, 0.1, 0.3, 0.2} {
+		x, y := x, 0.5
+		id := fmt.Sprintf("selection-fixture-%d", i)
+		resolver.db.Create(&models.FixtureInstance{
+			ID: id, Name: fmt.Sprintf("Par %d", i+1), DefinitionID: "selection-par",
+			ProjectID: project.ID, Universe: 1, StartChannel: i + 1, LayoutX: &x, LayoutY: &y,
+		})
+		resolver.db.Create(&models.InstanceChannel{ID: id + "-dimmer", FixtureID: id, Name: "Dimmer", Type: "INTENSITY"})
+		fixtureIDs = append(fixtureIDs, id)
+	}
+
+	var createResp struct {
+		CreateSelectionSet struct {
+			ID         string   `json:"id"`
+			Name       string   `json:"name"`
+			FixtureIds []string `json:"fixtureIds"`
+			Fixtures   []struct {
+				Name string `json:"name"`
+			} `json:"fixtures"`
+		} `json:"createSelectionSet"`
+	}
+	err := c.Post(`mutation Create($input: CreateSelectionSetInput!) {
+		createSelectionSet(input: $input) { id name fixtureIds fixtures { name } }
+	}`, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": project.ID, "name": "Front wash", "fixtureIds": fixtureIDs, "order": "LEFT_TO_RIGHT",
+	}))
+	if err != nil {
+		t.Fatalf("createSelectionSet mutation failed: %v", err)
+	}
+	set := createResp.CreateSelectionSet
+	if want := []string{"selection-fixture-1", "selection-fixture-3", "selection-fixture-2", "selection-fixture-0"}; !slices.Equal(set.FixtureIds, want) {
+		t.Errorf("Expected the set in layout order %v, got %v", want, set.FixtureIds)
+	}
+	if len(set.Fixtures) != 4 || set.Fixtures[0].Name != "Par 2" {
+		t.Errorf("Expected the set's fixtures in order, got %+v", set.Fixtures)
+	}
+
+	ordered, err := resolver.Query().OrderFixtures(ctx, fixtureIDs, generated.SelectionOrderInterleave)
+	if err != nil {
+		t.Fatalf("OrderFixtures failed: %v", err)
+	}
+	if want := []string{"selection-fixture-0", "selection-fixture-3", "selection-fixture-1", "selection-fixture-2"}; !slices.Equal(ordered, want) {
+		t.Errorf("Expected interleaved order %v, got %v", want, ordered)
+	}
+
+	// An effect takes a copy of the set's fixtures, reordered
+	order := generated.SelectionOrderReverse
+	effect, err := resolver.Mutation().CreateEffect(ctx, generated.CreateEffectInput{
+		ProjectID: project.ID, Name: "Chase", Type: generated.EffectTypeChase,
+		SelectionSetID: graphql.OmittableOf(&set.ID), SelectionOrder: graphql.OmittableOf(&order),
+	})
+	if err != nil {
+		t.Fatalf("CreateEffect from a selection set failed: %v", err)
+	}
+	if want := `["selection-fixture-0","selection-fixture-2","selection-fixture-3","selection-fixture-1"]`; effect.FixtureIDs != want {
+		t.Errorf("Expected the effect's fixtures %s, got %s", want, effect.FixtureIDs)
+	}
+	if _, err := resolver.Mutation().CreateEffect(ctx, generated.CreateEffectInput{
+		ProjectID: project.ID, Name: "Empty", Type: generated.EffectTypeChase,
+	}); err == nil {
+		t.Error("Expected an effect without fixtures or a selection set to be rejected")
+	}
+
+	group, err := resolver.Mutation().CreateFixtureGroup(ctx, generated.CreateFixtureGroupInput{
+		ProjectID: project.ID, Name: "Wash", SelectionSetID: graphql.OmittableOf(&set.ID),
+	})
+	if err != nil {
+		t.Fatalf("CreateFixtureGroup from a selection set failed: %v", err)
+	}
+	if ids, _ := repositories.GroupFixtureIDs(group); !slices.Equal(ids, set.FixtureIds) {
+		t.Errorf("Expected the group to hold the set's fixtures, got %v", ids)
+	}
+
+	// Odd-even reorders the saved list when no new fixtures are given
+	oddEven := generated.SelectionOrderOddEven
+	updated, err := resolver.Mutation().UpdateSelectionSet(ctx, set.ID, generated.UpdateSelectionSetInput{Order: graphql.OmittableOf(&oddEven)})
+	if err != nil {
+		t.Fatalf("UpdateSelectionSet failed: %v", err)
+	}
+	if ids, _ := repositories.SelectionSetFixtureIDs(updated); !slices.Equal(ids, []string{"selection-fixture-1", "selection-fixture-2", "selection-fixture-3", "selection-fixture-0"}) {
+		t.Errorf("Expected odd-even order, got %v", ids)
+	}
+
+	// Deleting a fixture drops it from the set
+	if _, err := resolver.Mutation().DeleteFixtureInstance(ctx, "selection-fixture-2"); err != nil {
+		t.Fatalf("DeleteFixtureInstance failed: %v", err)
+	}
+	reloaded, _ := resolver.SelectionSetRepo.FindByID(ctx, set.ID)
+	if ids, _ := repositories.SelectionSetFixtureIDs(reloaded); len(ids) != 3 || slices.Contains(ids, "selection-fixture-2") {
+		t.Errorf("Expected the deleted fixture dropped from the set, got %v", ids)
+	}
+
+	if ok, err := resolver.Mutation().DeleteSelectionSet(ctx, set.ID); err != nil || !ok {
+		t.Errorf("DeleteSelectionSet failed: %v", err)
+	}
+	if sets, _ := resolver.Query().SelectionSets(ctx, project.ID); len(sets) != 0 {
+		t.Errorf("Expected no selection sets left, got %d", len(sets))
+	}
+}
+
+func TestProjectQueryBatchesNestedLoads(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-loaders", Name: "Loader Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "loader-par", Manufacturer: "LoaderMfg", Model: "Par", Type: "LED_PAR"})
+	const fixtureCount, sceneCount, cueCount = 8, 3, 4
+	for i := range fixtureCount {
+		id := fmt.Sprintf("loader-fixture-%d", i)
+		resolver.db.Create(&models.FixtureInstance{ID: id, Name: fmt.Sprintf("Par %d", i+1), DefinitionID: "loader-par", ProjectID: project.ID, Universe: 1, StartChannel: i*2 + 1})
+		for offset, name := range []string{"Dimmer", "Strobe"} {
+			resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("%s-ch-%d", id, offset), FixtureID: id, Offset: offset, Name: name, Type: "OTHER"})
+		}
+	}
+	resolver.db.Create(&models.CueList{ID: "loader-cue-list", Name: "Main", ProjectID: project.ID})
+	for s := range sceneCount {
+		sceneID := fmt.Sprintf("loader-scene-%d", s)
+		resolver.db.Create(&models.Scene{ID: sceneID, Name: fmt.Sprintf("Look %d", s+1), ProjectID: project.ID})
+		for i := range fixtureCount {
+			resolver.db.Create(&models.FixtureValue{ID: fmt.Sprintf("%s-value-%d", sceneID, i), SceneID: sceneID, FixtureID: fmt.Sprintf("loader-fixture-%d", i), Channels: `[]`})
+		}
+	}
+	for q := range cueCount {
+		resolver.db.Create(&models.Cue{ID: fmt.Sprintf("loader-cue-%d", q), Name: fmt.Sprintf("Cue %d", q+1), CueNumber: float64(q + 1), CueListID: "loader-cue-list", SceneID: fmt.Sprintf("loader-scene-%d", q%sceneCount)})
+	}
+
+	var queries atomic.Int64
+	if err := resolver.db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries.Add(1) }); err != nil {
+		t.Fatalf("Failed to register query counter: %v", err)
+	}
+
+	var resp struct {
+		Project struct {
+			Fixtures []struct {
+				Channels []struct {
+					Name string `json:"name"`
+				} `json:"channels"`
+			} `json:"fixtures"`
+			Scenes []struct {
+				FixtureValues []struct {
+					Fixture struct {
+						Name string `json:"name"`
+					} `json:"fixture"`
+				} `json:"fixtureValues"`
+			} `json:"scenes"`
+			CueLists []struct {
+				Cues []struct {
+					Scene struct {
+						Name string `json:"name"`
+					} `json:"scene"`
+				} `json:"cues"`
+			} `json:"cueLists"`
+		} `json:"project"`
+	}
+	err := c.Post(`query Project($id: ID!) {
+		project(id: $id) {
+			fixtures { channels { name } }
+			scenes { fixtureValues { fixture { name } } }
+			cueLists { cues { scene { name } } }
+		}
+	}`, &resp, client.Var("id", project.ID))
+	if err != nil {
+		t.Fatalf("project query failed: %v", err)
+	}
+
+	p := resp.Project
+	if len(p.Fixtures) != fixtureCount || len(p.Fixtures[0].Channels) != 2 || p.Fixtures[0].Channels[1].Name != "Strobe" {
+		t.Errorf("Expected %d fixtures with their channels in order, got %+v", fixtureCount, p.Fixtures)
+	}
+	if len(p.Scenes) != sceneCount || len(p.Scenes[0].FixtureValues) != fixtureCount || p.Scenes[0].FixtureValues[0].Fixture.Name == "" {
+		t.Errorf("Expected %d scenes with a value for every fixture, got %+v", sceneCount, p.Scenes)
+	}
+	if len(p.CueLists) != 1 || len(p.CueLists[0].Cues) != cueCount || p.CueLists[0].Cues[3].Scene.Name != "Look 1" {
+		t.Errorf("Expected %d cues with their scenes, got %+v", cueCount, p.CueLists)
+	}
+
+	// Loading row by row takes a query for each fixture's channels, each
+	// scene's values and each value's fixture, and each cue's scene. Batched,
+	// each field takes one: the project, its fixtures, scenes and cue lists,
+	// then channels, values, their fixtures, cues, and their scenes.
+	perRow := fixtureCount + sceneCount + sceneCount*fixtureCount + cueCount
+	if n := queries.Load(); n > 12 {
+		t.Errorf("Expected the nested fields to be batched, got %d queries (%d row by row)", n, perRow)
+	}
+}
+
+func TestUpdateConflicts(t *testing.T) {
+	// A server outside UTC stores its times with a local offset
+	local := time.Local
+	time.Local = time.FixedZone("EDT", -4*60*60)
+	defer func() { time.Local = local }()
+
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	earlier := time.Now().Add(-time.Minute)
+	resolver.db.Create(&models.Project{ID: "conflict-project", Name: "Conflicts"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "conflict-def", Manufacturer: "Test", Model: "Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "conflict-fixture", Name: "Par", ProjectID: "conflict-project", DefinitionID: "conflict-def", Universe: 1, StartChannel: 1, UpdatedAt: earlier})
+	resolver.db.Create(&models.Scene{ID: "conflict-scene", Name: "Look", ProjectID: "conflict-project", UpdatedAt: earlier})
+	resolver.db.Create(&models.Scene{ID: "conflict-partial", Name: "Wash", ProjectID: "conflict-project", UpdatedAt: earlier})
+	resolver.db.Create(&models.CueList{ID: "conflict-list", Name: "Main", ProjectID: "conflict-project"})
+	resolver.db.Create(&models.Cue{ID: "conflict-cue", Name: "One", CueNumber: 1, CueListID: "conflict-list", SceneID: "conflict-scene", FadeInTime: 3, FadeOutTime: 3, UpdatedAt: earlier})
+
+	var readResp struct {
+		Scene           struct{ UpdatedAt string } `json:"scene"`
+		Partial         struct{ UpdatedAt string } `json:"partial"`
+		Cue             struct{ UpdatedAt string } `json:"cue"`
+		FixtureInstance struct{ UpdatedAt string } `json:"fixtureInstance"`
+	}
+	err := c.Post(`query {
+		scene(id: "conflict-scene") { updatedAt }
+		partial: scene(id: "conflict-partial") { updatedAt }
+		cue(id: "conflict-cue") { updatedAt }
+		fixtureInstance(id: "conflict-fixture") { updatedAt }
+	}`, &readResp)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		mutation string
+		read     string
+	}{
+		{"scene", `mutation($at: String) { updateScene(id: "conflict-scene", input: { name: "Edited" }, expectedUpdatedAt: $at) { id } }`, readResp.Scene.UpdatedAt},
+		{"partial scene", `mutation($at: String) { updateScenePartial(sceneId: "conflict-partial", name: "Edited", expectedUpdatedAt: $at) { id } }`, readResp.Partial.UpdatedAt},
+		{"cue", `mutation($at: String) { updateCue(id: "conflict-cue", input: { name: "Edited", cueNumber: 1, cueListId: "conflict-list", sceneId: "conflict-scene", fadeInTime: 1, fadeOutTime: 1 }, expectedUpdatedAt: $at) { id } }`, readResp.Cue.UpdatedAt},
+		{"fixture", `mutation($at: String) { updateFixtureInstance(id: "conflict-fixture", input: { name: "Edited" }, expectedUpdatedAt: $at) { id } }`, readResp.FixtureInstance.UpdatedAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp map[string]interface{}
+			// An update from the state the client read goes through, once
+			if err := c.Post(tt.mutation, &resp, client.Var("at", tt.read)); err != nil {
+				t.Fatalf("Update from the read state failed: %v", err)
+			}
+			err := c.Post(tt.mutation, &resp, client.Var("at", tt.read))
+			if err == nil {
+				t.Fatal("Expected a conflict updating from a stale updatedAt")
+			}
+			if !strings.Contains(err.Error(), "EDIT_CONFLICT") || !strings.Contains(err.Error(), `"latest"`) || !strings.Contains(err.Error(), "Edited") {
+				t.Errorf("Expected a conflict with the latest state, got %v", err)
+			}
+			// Without expectedUpdatedAt, the last write wins as before
+			if err := c.Post(tt.mutation, &resp, client.Var("at", nil)); err != nil {
+				t.Errorf("Unconditional update failed: %v", err)
+			}
+		})
+	}
+}
+
+// TestUpdateConflicts_ConditionalWrite tests that an update whose check
+// passed is still refused if another update writes the record before it.
+func TestUpdateConflicts_ConditionalWrite(t *testing.T) {
+	_, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	resolver.db.Create(&models.Project{ID: "race-project", Name: "Race"})
+	resolver.db.Create(&models.Scene{ID: "race-scene", Name: "Look", ProjectID: "race-project", UpdatedAt: time.Now().Add(-time.Minute)})
+
+	// Both clients read the same version and pass the check
+	read, _ := resolver.SceneRepo.FindByID(ctx, "race-scene")
+	expected := formatUpdatedAt(read.UpdatedAt)
+	write := func(name string) error {
+		return resolver.writeUnchanged(ctx, generated.ProjectEntityTypeScene, "race-scene", read.UpdatedAt, &expected, func(tx *Resolver) error {
+			scene, err := tx.SceneRepo.FindByID(ctx, "race-scene")
+			if err != nil {
+				return err
+			}
+			scene.Name = name
+			return tx.SceneRepo.Update(ctx, scene)
+		})
+	}
+	if err := write("First"); err != nil {
+		t.Fatalf("First write failed: %v", err)
+	}
+	var conflict *gqlerror.Error
+	if err := write("Second"); !errors.As(err, &conflict) || conflict.Extensions["code"] != editConflictCode {
+		t.Fatalf("Expected the second write refused with a conflict, got %v", err)
+	}
+	if scene, _ := resolver.SceneRepo.FindByID(ctx, "race-scene"); scene.Name != "First" {
+		t.Errorf("Expected the first write kept, got %q", scene.Name)
+	}
+
+	// Times in any zone format as UTC
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	if got := formatUpdatedAt(at); got != "2026-03-01T17:00:00.000Z" {
+		t.Errorf("formatUpdatedAt = %s, want 2026-03-01T17:00:00.000Z", got)
+	}
+}
+
+// TestProjectEntityChanged tests that edits reach projectEntityChanged
+// subscribers of the project, filtered by type, and that a batch sends its
+// changes only once it commits.
+func TestProjectEntityChanged(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "changes-project", Name: "Changes"})
+	resolver.db.Create(&models.Project{ID: "changes-other", Name: "Other"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subs := &subscriptionResolver{resolver}
+	all, err := subs.ProjectEntityChanged(ctx, "changes-project", nil)
+	if err != nil {
+		t.Fatalf("ProjectEntityChanged subscription failed: %v", err)
+	}
+	cueLists, err := subs.ProjectEntityChanged(ctx, "changes-project", []generated.ProjectEntityType{generated.ProjectEntityTypeCueList})
+	if err != nil {
+		t.Fatalf("ProjectEntityChanged subscription failed: %v", err)
+	}
+
+	next := func(changes <-chan *generated.ProjectEntityChange) *generated.ProjectEntityChange {
+		t.Helper()
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for projectEntityChanged")
+			return nil
+		}
+	}
+	expectNone := func(changes <-chan *generated.ProjectEntityChange) {
+		t.Helper()
+		select {
+		case change := <-changes:
+			t.Errorf("Expected no more changes, got %+v", change)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	var createResp struct {
+		CreateScene struct{ ID string } `json:"createScene"`
+	}
+	err = c.Post(`mutation { createScene(input: { name: "Look", projectId: "changes-project", fixtureValues: [] }) { id } }`, &createResp)
+	if err != nil {
+		t.Fatalf("createScene failed: %v", err)
+	}
+	sceneID := createResp.CreateScene.ID
+	change := next(all)
+	if change.EntityType != generated.ProjectEntityTypeScene || change.Action != generated.EntityChangeActionCreated || change.EntityID != sceneID || change.Scene == nil {
+		t.Errorf("Unexpected change for createScene: %+v", change)
+	}
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation($id: ID!) { updateScene(id: $id, input: { name: "Edited" }) { id } }`, &resp, client.Var("id", sceneID)); err != nil {
+		t.Fatalf("updateScene failed: %v", err)
+	}
+	change = next(all)
+	if change.Action != generated.EntityChangeActionUpdated || change.Scene == nil || change.Scene.Name != "Edited" {
+		t.Errorf("Unexpected change for updateScene: %+v", change)
+	}
+
+	if err := c.Post(`mutation($id: ID!) { deleteScene(id: $id) }`, &resp, client.Var("id", sceneID)); err != nil {
+		t.Fatalf("deleteScene failed: %v", err)
+	}
+	change = next(all)
+	if change.Action != generated.EntityChangeActionDeleted || change.EntityID != sceneID || change.Scene != nil {
+		t.Errorf("Unexpected change for deleteScene: %+v", change)
+	}
+
+	// Another project's changes are not sent
+	if err := c.Post(`mutation { createCueList(input: { name: "Elsewhere", projectId: "changes-other" }) { id } }`, &resp); err != nil {
+		t.Fatalf("createCueList failed: %v", err)
+	}
+	expectNone(all)
+	expectNone(cueLists)
+
+	// A failed batch rolls back without sending anything
+	var batchResp executeBatchResponse
+	if err := c.Post(executeBatchMutation, &batchResp, client.Var("operations", batchOperations("changes-project", "missing-scene"))); err == nil {
+		t.Fatal("Expected executeBatch to fail")
+	}
+	expectNone(all)
+
+	if err := c.Post(executeBatchMutation, &batchResp, client.Var("operations", batchOperations("changes-project", "$ref:scene"))); err != nil {
+		t.Fatalf("executeBatch failed: %v", err)
+	}
+	want := []struct {
+		entityType generated.ProjectEntityType
+		action     generated.EntityChangeAction
+	}{
+		{generated.ProjectEntityTypeScene, generated.EntityChangeActionCreated},
+		{generated.ProjectEntityTypeSceneBoard, generated.EntityChangeActionCreated},
+		{generated.ProjectEntityTypeSceneBoard, generated.EntityChangeActionUpdated},
+		{generated.ProjectEntityTypeCueList, generated.EntityChangeActionCreated},
+		{generated.ProjectEntityTypeCue, generated.EntityChangeActionCreated},
+	}
+	for i, w := range want {
+		change := next(all)
+		if change.EntityType != w.entityType || change.Action != w.action || change.ProjectID != "changes-project" {
+			t.Errorf("Batch change %d = %s %s, want %s %s", i, change.Action, change.EntityType, w.action, w.entityType)
+		}
+	}
+	expectNone(all)
+
+	change = next(cueLists)
+	if change.EntityType != generated.ProjectEntityTypeCueList || change.EntityID != batchResp.ExecuteBatch[3].ID {
+		t.Errorf("Unexpected change for the cue list subscriber: %+v", change)
+	}
+	expectNone(cueLists)
+}
+
+// TestProjectEntityChanged_ProjectWideOperations tests that imports, repairs
+// and forced definition deletes report the records they change.
+func TestProjectEntityChanged_ProjectWideOperations(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "wide-project", Name: "Wide"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "wide-def", Manufacturer: "Test", Model: "Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "wide-fixture", Name: "Par", ProjectID: "wide-project", DefinitionID: "wide-def", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.Scene{ID: "wide-scene", Name: "Look", ProjectID: "wide-project"})
+	resolver.db.Create(&models.FixtureValue{ID: "wide-value", SceneID: "wide-scene", FixtureID: "wide-fixture"})
+	resolver.db.Create(&models.Scene{ID: "wide-broken-scene", Name: "Broken", ProjectID: "wide-project"})
+	resolver.db.Create(&models.FixtureValue{ID: "wide-orphan-value", SceneID: "wide-broken-scene", FixtureID: "gone-fixture"})
+	resolver.db.Create(&models.CueList{ID: "wide-list", Name: "Main", ProjectID: "wide-project"})
+	resolver.db.Create(&models.Cue{ID: "wide-orphan-cue", Name: "Orphan", CueNumber: 1, CueListID: "wide-list", SceneID: "gone-scene"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := (&subscriptionResolver{resolver}).ProjectEntityChanged(ctx, "wide-project", nil)
+	if err != nil {
+		t.Fatalf("ProjectEntityChanged subscription failed: %v", err)
+	}
+	// drain returns the changes sent until none arrive for a while
+	drain := func() map[string]generated.EntityChangeAction {
+		t.Helper()
+		got := make(map[string]generated.EntityChangeAction)
+		for {
+			select {
+			case change := <-changes:
+				got[change.EntityID] = change.Action
+			case <-time.After(200 * time.Millisecond):
+				return got
+			}
+		}
+	}
+
+	var resp map[string]interface{}
+	err = c.Post(`mutation($json: String!) {
+		importProject(jsonContent: $json, options: { mode: MERGE, targetProjectId: "wide-project" }) { projectId }
+	}`, &resp, client.Var("json", `{"version":"1.0","project":{"name":"Extra"},"scenes":[{"refId":"s1","name":"Imported"}]}`))
+	if err != nil {
+		t.Fatalf("importProject failed: %v", err)
+	}
+	var imported models.Scene
+	resolver.db.Where("project_id = ? AND name = ?", "wide-project", "Imported").First(&imported)
+	if got := drain(); len(got) != 1 || got[imported.ID] != generated.EntityChangeActionCreated {
+		t.Errorf("Expected the merged scene to be created, got %v", got)
+	}
+
+	err = c.Post(`mutation { repairProject(projectId: "wide-project", fixes: [CUE_MISSING_SCENE, FIXTURE_VALUE_MISSING_FIXTURE]) { remaining { type } } }`, &resp)
+	if err != nil {
+		t.Fatalf("repairProject failed: %v", err)
+	}
+	if got := drain(); len(got) != 2 || got["wide-orphan-cue"] != generated.EntityChangeActionDeleted || got["wide-broken-scene"] != generated.EntityChangeActionUpdated {
+		t.Errorf("Expected the repaired cue deleted and scene updated, got %v", got)
+	}
+
+	if err := c.Post(`mutation { forceDeleteDefinition(id: "wide-def") { deletedFixtureIds } }`, &resp); err != nil {
+		t.Fatalf("forceDeleteDefinition failed: %v", err)
+	}
+	if got := drain(); len(got) != 2 || got["wide-fixture"] != generated.EntityChangeActionDeleted || got["wide-scene"] != generated.EntityChangeActionUpdated {
+		t.Errorf("Expected the fixture deleted and its scene updated, got %v", got)
+	}
+}
@@ -0,0 +1,171 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// cueNumberDecimals is the most decimal places an automatic cue number has.
+const cueNumberDecimals = 3
+
+// roundCueNumber rounds a cue number to cueNumberDecimals places, so sums
+// of increments don't pick up float noise such as 0.30000000000000004.
+func roundCueNumber(n float64) float64 {
+	scale := math.Pow10(cueNumberDecimals)
+	return math.Round(n*scale) / scale
+}
+
+// cueNumberBetween returns the number for a cue between cue numbers after
+// and before: the one nearest their midpoint with the fewest decimal places,
+// up to cueNumberDecimals. Without before it is the next whole number. It
+// returns false when no number fits.
+func cueNumberBetween(after float64, before *float64) (float64, bool) {
+	if before == nil {
+		return math.Floor(after) + 1, true
+	}
+	mid := (after + *before) / 2
+	for places := 0; places <= cueNumberDecimals; places++ {
+		scale := math.Pow10(places)
+		n := math.Round(mid*scale) / scale
+		if n > after && n < *before {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// renumberCues renumbers a cue list's cues in order, from start by
+// increment, recording the change for undo.
+func (r *Resolver) renumberCues(ctx context.Context, cueList *models.CueList, start, increment float64) ([]*models.Cue, error) {
+	if increment < math.Pow10(-cueNumberDecimals) {
+		return nil, fmt.Errorf("increment must be at least %g", math.Pow10(-cueNumberDecimals))
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("startNumber must not be negative")
+	}
+
+	cues, err := r.CueRepo.FindByCueListID(ctx, cueList.ID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]repositories.UndoTarget, len(cues))
+	numbers := make(map[string]float64, len(cues))
+	result := make([]*models.Cue, len(cues))
+	for i := range cues {
+		targets[i] = undoCue(cues[i].ID)
+		cues[i].CueNumber = roundCueNumber(start + float64(i)*increment)
+		numbers[cues[i].ID] = cues[i].CueNumber
+		result[i] = &cues[i]
+	}
+
+	undo, err := r.beginUndo(ctx, cueList.ProjectID, "Renumber cues in "+cueList.Name, targets...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.CueRepo.UpdateCueNumbers(ctx, numbers); err != nil {
+		return nil, err
+	}
+	r.refreshTimecodeTriggers(ctx)
+	r.commitUndo(ctx, undo)
+	for _, cue := range result {
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cue)
+	}
+
+	return result, nil
+}
+
+// insertCueNumber returns the number for a cue inserted after afterCueID,
+// or first in the list without it, renumbering the list when no number fits
+// between its neighbours.
+func (r *Resolver) insertCueNumber(ctx context.Context, cueList *models.CueList, afterCueID *string) (float64, error) {
+	for attempt := 0; ; attempt++ {
+		cues, err := r.CueRepo.FindByCueListID(ctx, cueList.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		next := 0
+		after := 0.0
+		if afterCueID != nil {
+			next = -1
+			for i, cue := range cues {
+				if cue.ID == *afterCueID {
+					next = i + 1
+					after = cue.CueNumber
+					break
+				}
+			}
+			if next < 0 {
+				return 0, fmt.Errorf("cue not found in cue list: %s", *afterCueID)
+			}
+		}
+		var before *float64
+		if next < len(cues) {
+			before = &cues[next].CueNumber
+		}
+
+		if number, ok := cueNumberBetween(after, before); ok {
+			return number, nil
+		}
+		if attempt > 0 {
+			return 0, fmt.Errorf("no cue number fits after %g", after)
+		}
+		if _, err := r.renumberCues(ctx, cueList, 1, 1); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// insertCueBetween creates a cue numbered between the cue afterCueID and
+// the one after it. Any renumbering it needs is rolled back with the create
+// if that fails.
+func (r *Resolver) insertCueBetween(ctx context.Context, cueListID string, afterCueID *string, input generated.InsertCueInput) (*models.Cue, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
+	if err != nil {
+		return nil, err
+	}
+	if cueList == nil {
+		return nil, fmt.Errorf("cue list not found: %s", cueListID)
+	}
+	scene, err := r.SceneRepo.FindByID(ctx, input.SceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene not found: %s", input.SceneID)
+	}
+
+	var cue *models.Cue
+	err = r.transaction(ctx, func(tx *Resolver) error {
+		number, err := tx.insertCueNumber(ctx, cueList, afterCueID)
+		if err != nil {
+			return err
+		}
+		cue, err = tx.Mutation().CreateCue(ctx, generated.CreateCueInput{
+			Name:           input.Name,
+			SecondaryLabel: input.SecondaryLabel,
+			CueNumber:      number,
+			CueListID:      cueListID,
+			SceneID:        input.SceneID,
+			FadeInTime:     input.FadeInTime,
+			FadeOutTime:    input.FadeOutTime,
+			FollowTime:     input.FollowTime,
+			FollowQuantize: input.FollowQuantize,
+			EasingType:     input.EasingType,
+			Notes:          input.Notes,
+			Timecode:       input.Timecode,
+			Block:          input.Block,
+			MoveInBlack:    input.MoveInBlack,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cue, nil
+}
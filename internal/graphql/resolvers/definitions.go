@@ -0,0 +1,222 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// maxListedDependents caps how many fixture names per project appear in a
+// definition-in-use error.
+const maxListedDependents = 5
+
+// definitionUsage groups the fixture instances using a definition by project.
+func (r *Resolver) definitionUsage(ctx context.Context, definitionID string) ([]*generated.FixtureDefinitionUsage, error) {
+	fixtures, err := r.FixtureRepo.FindByDefinitionID(ctx, definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []*generated.FixtureDefinitionUsage{}
+	byProject := make(map[string]*generated.FixtureDefinitionUsage)
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		entry, ok := byProject[fixture.ProjectID]
+		if !ok {
+			project, err := r.ProjectRepo.FindByID(ctx, fixture.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			if project == nil {
+				// Orphaned instance; report it under its project ID
+				project = &models.Project{ID: fixture.ProjectID, Name: fixture.ProjectID}
+			}
+			entry = &generated.FixtureDefinitionUsage{Project: *project}
+			byProject[fixture.ProjectID] = entry
+			usage = append(usage, entry)
+		}
+		entry.Fixtures = append(entry.Fixtures, fixture)
+	}
+	return usage, nil
+}
+
+// definitionInUseError describes the projects and fixtures that block a delete.
+func definitionInUseError(definition *models.FixtureDefinition, usage []*generated.FixtureDefinitionUsage) error {
+	count := 0
+	projects := make([]string, 0, len(usage))
+	for _, entry := range usage {
+		count += len(entry.Fixtures)
+
+		names := make([]string, 0, maxListedDependents)
+		for i, fixture := range entry.Fixtures {
+			if i == maxListedDependents {
+				names = append(names, fmt.Sprintf("and %d more", len(entry.Fixtures)-maxListedDependents))
+				break
+			}
+			names = append(names, fixture.Name)
+		}
+		projects = append(projects, fmt.Sprintf("%s: %s", entry.Project.Name, strings.Join(names, ", ")))
+	}
+
+	return fmt.Errorf("cannot delete fixture definition %s %s: used by %d fixture instances in %d projects (%s); use forceDeleteDefinition to remove or remap them",
+		definition.Manufacturer, definition.Model, count, len(usage), strings.Join(projects, "; "))
+}
+
+// deleteDefinitionRecords removes a definition with its channels and modes.
+func (r *Resolver) deleteDefinitionRecords(ctx context.Context, definitionID string) error {
+	if err := r.FixtureRepo.DeleteDefinitionModes(ctx, definitionID); err != nil {
+		return err
+	}
+	if err := r.FixtureRepo.DeleteChannelDefinitions(ctx, definitionID); err != nil {
+		return err
+	}
+	return r.FixtureRepo.DeleteDefinition(ctx, definitionID)
+}
+
+// remapModeID picks the mode a remapped fixture uses on the replacement
+// definition: the requested mode, else the mode with the fixture's current
+// mode name, else none (the definition's own channels).
+func remapModeID(fixture *models.FixtureInstance, requested *string, modes []models.FixtureMode) *string {
+	if requested != nil {
+		return requested
+	}
+	if fixture.ModeName == nil {
+		return nil
+	}
+	for i := range modes {
+		if strings.EqualFold(modes[i].Name, *fixture.ModeName) {
+			return &modes[i].ID
+		}
+	}
+	return nil
+}
+
+// forceDeleteDefinition deletes a definition and removes or remaps its
+// dependent fixtures in a single transaction.
+func (r *Resolver) forceDeleteDefinition(ctx context.Context, id string, remapToDefinitionID, remapToModeID *string) (*generated.ForceDeleteDefinitionResult, error) {
+	definition, err := r.FixtureRepo.FindDefinitionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("fixture definition not found: %s", id)
+	}
+
+	var targetModes []models.FixtureMode
+	if remapToDefinitionID != nil {
+		if *remapToDefinitionID == id {
+			return nil, fmt.Errorf("cannot remap fixtures to the definition being deleted")
+		}
+		target, err := r.FixtureRepo.FindDefinitionByID(ctx, *remapToDefinitionID)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return nil, fmt.Errorf("replacement fixture definition not found: %s", *remapToDefinitionID)
+		}
+		targetModes, err = r.FixtureRepo.GetDefinitionModes(ctx, target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if remapToModeID != nil {
+			found := false
+			for _, mode := range targetModes {
+				if mode.ID == *remapToModeID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("mode %s does not belong to fixture definition %s", *remapToModeID, target.ID)
+			}
+		}
+	} else if remapToModeID != nil {
+		return nil, fmt.Errorf("remapToModeId requires remapToDefinitionId")
+	}
+
+	result := &generated.ForceDeleteDefinitionResult{
+		DeletedFixtureIds:  []string{},
+		RemappedFixtureIds: []string{},
+		AffectedProjectIds: []string{},
+	}
+	err = r.transaction(ctx, func(tx *Resolver) error {
+		m := &mutationResolver{tx}
+
+		fixtures, err := m.FixtureRepo.FindByDefinitionID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		affected := make(map[string]bool)
+		changedScenes := make(map[string]bool)
+		for i := range fixtures {
+			fixture := &fixtures[i]
+			if !affected[fixture.ProjectID] {
+				affected[fixture.ProjectID] = true
+				result.AffectedProjectIds = append(result.AffectedProjectIds, fixture.ProjectID)
+			}
+
+			if remapToDefinitionID != nil {
+				input := generated.UpdateFixtureInstanceInput{
+					DefinitionID: graphql.OmittableOf(remapToDefinitionID),
+					ModeID:       graphql.OmittableOf(remapModeID(fixture, remapToModeID, targetModes)),
+				}
+				if _, err := m.UpdateFixtureInstance(ctx, fixture.ID, input, nil); err != nil {
+					return fmt.Errorf("failed to remap fixture %s: %w", fixture.Name, err)
+				}
+				result.RemappedFixtureIds = append(result.RemappedFixtureIds, fixture.ID)
+				continue
+			}
+
+			sceneIDs, err := m.SceneRepo.FindSceneIDsByFixtureID(ctx, fixture.ID)
+			if err != nil {
+				return err
+			}
+			for _, sceneID := range sceneIDs {
+				changedScenes[sceneID] = true
+			}
+			if err := m.SceneRepo.DeleteFixtureValuesByFixtureID(ctx, fixture.ID); err != nil {
+				return err
+			}
+			if _, err := m.DeleteFixtureInstance(ctx, fixture.ID); err != nil {
+				return fmt.Errorf("failed to delete fixture %s: %w", fixture.Name, err)
+			}
+			result.DeletedFixtureIds = append(result.DeletedFixtureIds, fixture.ID)
+		}
+
+		// Scenes lose the deleted fixtures' values
+		for sceneID := range changedScenes {
+			m.publishEntityUpdated(ctx, generated.ProjectEntityTypeScene, sceneID)
+		}
+
+		return m.deleteDefinitionRecords(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Caps of removed or re-patched fixtures no longer apply
+	r.refreshOutputLimits(ctx)
+
+	return result, nil
+}
+
+// importGDTFFixture imports a fixture definition from a base64-encoded .gdtf
+// file.
+func (r *Resolver) importGDTFFixture(ctx context.Context, input generated.ImportGDTFFixtureInput) (*models.FixtureDefinition, error) {
+	data, err := base64.StdEncoding.DecodeString(input.GdtfFile)
+	if err != nil {
+		return nil, fmt.Errorf("gdtfFile must be base64 encoded: %w", err)
+	}
+
+	replace := false
+	if replacePtr := input.Replace.Value(); replacePtr != nil {
+		replace = *replacePtr
+	}
+	return r.GDTFService.ImportFixture(ctx, data, replace)
+}
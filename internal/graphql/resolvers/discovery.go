@@ -0,0 +1,123 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/discovery"
+	"github.com/bbernstein/lacylights-go/internal/services/version"
+)
+
+// graphqlPath is advertised so clients know where the API is.
+const graphqlPath = "/graphql"
+
+// StartDiscovery advertises the server by mDNS, with its version and
+// projects in the TXT records, and listens for other servers.
+func (r *Resolver) StartDiscovery(cfg discovery.Config) error {
+	if cfg.Info == nil {
+		cfg.Info = r.discoveryInfo
+	}
+	service := discovery.NewService(cfg)
+	if err := service.Start(); err != nil {
+		return err
+	}
+	r.DiscoveryService = service
+	return nil
+}
+
+// discoveryInfo is the TXT record of the advertised services: the version,
+// the number of projects, and the project most recently changed.
+func (r *Resolver) discoveryInfo() map[string]string {
+	info := map[string]string{
+		"version": version.GetBuildInfo().Version,
+		"path":    graphqlPath,
+	}
+	projects, err := r.ProjectRepo.FindAll(context.Background())
+	if err != nil {
+		log.Warn("cannot list projects to advertise", "error", err)
+		return info
+	}
+	info["projects"] = strconv.Itoa(len(projects))
+	var latest time.Time
+	for _, project := range projects {
+		if project.UpdatedAt.After(latest) {
+			latest = project.UpdatedAt
+			info["project"] = project.Name
+		}
+	}
+	return info
+}
+
+// discoveryStatus describes mDNS advertisement.
+func (r *Resolver) discoveryStatus() *generated.DiscoveryStatus {
+	status := &generated.DiscoveryStatus{ServiceTypes: discovery.ServiceTypes()}
+	if r.DiscoveryService == nil {
+		return status
+	}
+	s := r.DiscoveryService.Status()
+	status.Enabled = s.Running
+	status.Name = stringPtr(s.Name)
+	status.Host = stringPtr(s.Host)
+	status.Port = &s.Port
+	return status
+}
+
+// discoveredServers lists the other servers advertising, first asking for
+// them when refresh is set.
+func (r *Resolver) discoveredServers(refresh bool) ([]*generated.DiscoveredServer, error) {
+	if r.DiscoveryService == nil {
+		return nil, fmt.Errorf("mDNS discovery is not enabled on this server")
+	}
+	peers := r.DiscoveryService.Peers()
+	if refresh {
+		var err error
+		if peers, err = r.DiscoveryService.Browse(discovery.DefaultBrowseTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	servers := make([]*generated.DiscoveredServer, len(peers))
+	for i, peer := range peers {
+		servers[i] = convertDiscoveredServer(peer)
+	}
+	return servers, nil
+}
+
+// convertDiscoveredServer converts a peer and its TXT record to the GraphQL
+// type.
+func convertDiscoveredServer(peer discovery.Peer) *generated.DiscoveredServer {
+	server := &generated.DiscoveredServer{
+		Name:      peer.Name,
+		Host:      peer.Host,
+		Port:      peer.Port,
+		Addresses: make([]string, len(peer.Addresses)),
+		Version:   stringToPointer(peer.Info["version"]),
+		Project:   stringToPointer(peer.Info["project"]),
+		LastSeen:  peer.LastSeen.UTC().Format(time.RFC3339),
+	}
+	for i, ip := range peer.Addresses {
+		server.Addresses[i] = ip.String()
+	}
+	if count, err := strconv.Atoi(peer.Info["projects"]); err == nil {
+		server.ProjectCount = &count
+	}
+
+	host := peer.Host
+	if len(server.Addresses) > 0 {
+		host = server.Addresses[0]
+	}
+	path := peer.Info["path"]
+	if path == "" {
+		path = graphqlPath
+	}
+	scheme := peer.Info["scheme"]
+	if scheme == "" {
+		scheme = "http"
+	}
+	server.GraphqlURL = scheme + "://" + net.JoinHostPort(host, strconv.Itoa(peer.Port)) + path
+	return server
+}
@@ -1,27 +1,70 @@
 package resolvers
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	imagecolor "image/color"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/99designs/gqlgen/client"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/glebarez/sqlite" // Pure Go SQLite driver (no CGO required)
+	"github.com/lucsky/cuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/rest"
+	"github.com/bbernstein/lacylights-go/internal/services/audio"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"github.com/bbernstein/lacylights-go/internal/services/backup"
+	"github.com/bbernstein/lacylights-go/internal/services/discovery"
 	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx/dmxtest"
+	"github.com/bbernstein/lacylights-go/internal/services/effects"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/input"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/librarysync"
+	"github.com/bbernstein/lacylights-go/internal/services/logging"
 	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/presence"
+	"github.com/bbernstein/lacylights-go/internal/services/recorder"
+	"github.com/bbernstein/lacylights-go/internal/services/replication"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+	"github.com/bbernstein/lacylights-go/internal/services/stageview"
+	"github.com/bbernstein/lacylights-go/internal/services/standby"
 )
 
 // testSetup creates a test GraphQL server with an in-memory database
 func testSetup(t *testing.T) (*client.Client, *Resolver, func()) {
 	t.Helper()
+	c, resolver, _, cleanup := testSetupWithOutput(t)
+	return c, resolver, cleanup
+}
+
+// testSetupWithOutput is testSetup with DMX output running into a mock sink,
+// for tests that assert on transmitted frames.
+func testSetupWithOutput(t *testing.T) (*client.Client, *Resolver, *dmxtest.Sink, func()) {
+	t.Helper()
 
 	// Create in-memory SQLite database
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
@@ -36,6 +79,7 @@ func testSetup(t *testing.T) (*client.Client, *Resolver, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
@@ -46,6 +90,24 @@ func testSetup(t *testing.T) (*client.Client, *Resolver, func()) {
 		&models.Cue{},
 		&models.SceneBoard{},
 		&models.SceneBoardButton{},
+		&models.Effect{},
+		&models.Submaster{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.Schedule{},
+		&models.SoftPatch{},
+		&models.Universe{},
+		&models.LayoutZone{},
+		&models.SelectionSet{},
+		&models.HouseLights{},
+		&models.PixelMatrix{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
+		&models.PreviewSession{},
 		&models.Setting{},
 	)
 	if err != nil {
@@ -56,6 +118,10 @@ func testSetup(t *testing.T) (*client.Client, *Resolver, func()) {
 	dmxCfg := dmx.DefaultConfig()
 	dmxCfg.Enabled = false
 	dmxService := dmx.NewService(dmxCfg)
+	sink := dmxtest.Attach(dmxService)
+	if err := dmxService.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize DMX service: %v", err)
+	}
 
 	// Create and start fade engine (60Hz for testing)
 	fadeEngine := fade.NewEngine(dmxService, 60)
@@ -71,17 +137,26 @@ func testSetup(t *testing.T) (*client.Client, *Resolver, func()) {
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: resolver,
 	}))
+	srv.Use(resolver.OperationRecorder)
+	srv.Use(resolver.SchemaInfo)
+	srv.Use(resolver.AuditService)
+	srv.Use(resolver.AuthService)
+	srv.Use(resolver.PresenceService)
+	srv.Use(resolver.Loaders)
 
 	// Create test client
-	c := client.New(srv)
+	c := client.New(audit.Middleware(presence.Middleware(resolver.AuthService.Middleware(srv))))
 
 	// Cleanup function
 	cleanup := func() {
+		resolver.StandbyService.Cleanup()
+		resolver.InputService.Cleanup()
+		resolver.TimecodeService.Cleanup()
 		fadeEngine.Stop()
 		dmxService.Stop()
 	}
 
-	return c, resolver, cleanup
+	return c, resolver, sink, cleanup
 }
 
 func TestSystemInfo_Query(t *testing.T) {
@@ -112,6 +187,32 @@ func TestSystemInfo_Query(t *testing.T) {
 	}
 }
 
+func TestSystemInfo_SchemaVersion(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var resp struct {
+		SystemInfo struct {
+			SchemaVersion string `json:"schemaVersion"`
+		} `json:"systemInfo"`
+		DeprecatedFieldUsage []struct {
+			Coordinate string `json:"coordinate"`
+		} `json:"deprecatedFieldUsage"`
+	}
+	if err := c.Post(`query { systemInfo { schemaVersion } deprecatedFieldUsage { coordinate } }`, &resp); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if resp.SystemInfo.SchemaVersion == "" || resp.SystemInfo.SchemaVersion != resolver.SchemaInfo.Version() {
+		t.Errorf("Expected schemaVersion %q, got %q", resolver.SchemaInfo.Version(), resp.SystemInfo.SchemaVersion)
+	}
+	if !strings.Contains(resolver.SchemaInfo.SDL(), "schemaVersion: String!") {
+		t.Error("Expected SDL to describe the served schema")
+	}
+	if len(resp.DeprecatedFieldUsage) != len(resolver.SchemaInfo.Usage()) {
+		t.Errorf("Expected one entry per deprecated element, got %d", len(resp.DeprecatedFieldUsage))
+	}
+}
+
 func TestSetChannelValue_Mutation(t *testing.T) {
 	c, resolver, cleanup := testSetup(t)
 	defer cleanup()
@@ -134,12 +235,33 @@ func TestSetChannelValue_Mutation(t *testing.T) {
 	}
 
 	// Verify the value was set
-	value := resolver.DMXService.GetChannelValue(1, 1)
+	value := resolver.DMXService.GetUniverse(1)[0]
 	if value != 128 {
 		t.Errorf("Expected channel value 128, got %d", value)
 	}
 }
 
+func TestSetChannelValue_Output(t *testing.T) {
+	c, _, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation {
+		setChannelValue(universe: 2, channel: 5, value: 200)
+	}`, &resp); err != nil {
+		t.Fatalf("setChannelValue mutation failed: %v", err)
+	}
+	// Output idles at 1Hz, so the first frame can take up to a second
+	sink.ExpectChannel(t, 2, 5, 200, 2*time.Second)
+
+	if err := c.Post(`mutation {
+		fadeToBlack(fadeOutTime: 0.2)
+	}`, &resp); err != nil {
+		t.Fatalf("fadeToBlack mutation failed: %v", err)
+	}
+	sink.ExpectChannel(t, 2, 5, 0, 2*time.Second)
+}
+
 func TestSetChannelValue_ClampValues(t *testing.T) {
 	c, resolver, cleanup := testSetup(t)
 	defer cleanup()
@@ -157,7 +279,7 @@ func TestSetChannelValue_ClampValues(t *testing.T) {
 		t.Fatalf("SetChannelValue mutation failed: %v", err)
 	}
 
-	value := resolver.DMXService.GetChannelValue(1, 1)
+	value := resolver.DMXService.GetUniverse(1)[0]
 	if value != 255 {
 		t.Errorf("Expected channel value to be clamped to 255, got %d", value)
 	}
@@ -171,12 +293,475 @@ func TestSetChannelValue_ClampValues(t *testing.T) {
 		t.Fatalf("SetChannelValue mutation failed: %v", err)
 	}
 
-	value = resolver.DMXService.GetChannelValue(1, 2)
+	value = resolver.DMXService.GetUniverse(1)[1]
 	if value != 0 {
 		t.Errorf("Expected channel value to be clamped to 0, got %d", value)
 	}
 }
 
+func TestChannelValueValidation(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-ranges", Name: "Range Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-spot", Manufacturer: "Test", Model: "Spot", Type: "MOVING_HEAD"})
+	resolver.db.Create(&models.ChannelDefinition{ID: "spot-color", Name: "Color", Type: "COLOR_WHEEL", Offset: 2, MaxValue: 9, IsDiscrete: true, DefinitionID: "test-def-spot"})
+	resolver.db.Create(&models.ChannelCapability{ID: "spot-open", ChannelID: "spot-color", Name: "Open", MinValue: 0, MaxValue: 9})
+	resolver.db.Create(&models.ChannelCapability{ID: "spot-red", ChannelID: "spot-color", Name: "Red", MinValue: 20, MaxValue: 29})
+	resolver.db.Create(&models.FixtureInstance{ID: "spot-fx", Name: "Spot 1", ProjectID: project.ID, DefinitionID: "test-def-spot", Universe: 1, StartChannel: 20})
+	resolver.db.Create(&models.InstanceChannel{ID: "spot-fx-0", FixtureID: "spot-fx", Offset: 0, Name: "Dimmer", Type: "INTENSITY", MaxValue: 255})
+	resolver.db.Create(&models.InstanceChannel{ID: "spot-fx-1", FixtureID: "spot-fx", Offset: 1, Name: "Shutter", Type: "STROBE", MaxValue: 31})
+	resolver.db.Create(&models.InstanceChannel{ID: "spot-fx-2", FixtureID: "spot-fx", Offset: 2, Name: "Color", Type: "COLOR_WHEEL", MaxValue: 9, IsDiscrete: true})
+
+	createScene := func(name string, channels map[int]int) (string, error) {
+		var values []map[string]interface{}
+		for offset, value := range channels {
+			values = append(values, map[string]interface{}{"offset": offset, "value": value})
+		}
+		var resp struct {
+			CreateScene struct {
+				ID string `json:"id"`
+			} `json:"createScene"`
+		}
+		err := c.Post(`mutation($input: CreateSceneInput!) { createScene(input: $input) { id } }`, &resp, client.Var("input", map[string]interface{}{
+			"name": name, "projectId": project.ID,
+			"fixtureValues": []map[string]interface{}{{"fixtureId": "spot-fx", "channels": values}},
+		}))
+		return resp.CreateScene.ID, err
+	}
+	sceneValues := func(sceneID string) map[int]int {
+		var fv models.FixtureValue
+		resolver.db.First(&fv, "scene_id = ? AND fixture_id = ?", sceneID, "spot-fx")
+		var channels []models.ChannelValue
+		if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
+			t.Fatalf("Failed to parse scene channels: %v", err)
+		}
+		values := make(map[int]int)
+		for _, ch := range channels {
+			values[ch.Offset] = ch.Value
+		}
+		return values
+	}
+	setMode := func(mode string) {
+		var resp struct {
+			UpdateSetting struct {
+				ID string `json:"id"`
+			} `json:"updateSetting"`
+		}
+		err := c.Post(`mutation($key: String!, $value: String!) { updateSetting(input: {key: $key, value: $value}) { id } }`,
+			&resp, client.Var("key", "channel_value_validation"), client.Var("value", mode))
+		if err != nil {
+			t.Fatalf("updateSetting mutation failed: %v", err)
+		}
+	}
+
+	// Values out of range are rejected by default, with their details
+	if _, err := createScene("In Range", map[int]int{0: 255, 1: 31, 2: 25}); err != nil {
+		t.Fatalf("Expected values in range to be accepted, got %v", err)
+	}
+	_, err := createScene("Out Of Range", map[int]int{1: 40, 2: 13})
+	if err == nil {
+		t.Fatal("Expected values out of range to be rejected")
+	}
+	for _, want := range []string{"CHANNEL_VALUE_OUT_OF_RANGE", `"channelName":"Shutter"`, `"nearestValue":31`, `"nearestValue":9`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to include %s, got %v", want, err)
+		}
+	}
+	var resp struct {
+		SetChannelValue bool `json:"setChannelValue"`
+	}
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 21, value: 40) }`, &resp); err == nil {
+		t.Error("Expected a programmer value out of range to be rejected")
+	}
+
+	// Clamping stores the nearest value each channel takes
+	setMode("clamp")
+	sceneID, err := createScene("Clamped", map[int]int{1: 40, 2: 13})
+	if err != nil {
+		t.Fatalf("Expected values to be clamped, got %v", err)
+	}
+	if values := sceneValues(sceneID); values[1] != 31 || values[2] != 9 {
+		t.Errorf("Expected clamped values 31 and 9, got %v", values)
+	}
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 21, value: 40) }`, &resp); err != nil {
+		t.Fatalf("setChannelValue mutation failed: %v", err)
+	}
+	if value := resolver.DMXService.GetUniverse(1)[20]; value != 31 {
+		t.Errorf("Expected the programmer value clamped to 31, got %d", value)
+	}
+
+	// With validation off, values are stored as they are
+	setMode("off")
+	sceneID, err = createScene("Unchecked", map[int]int{1: 40})
+	if err != nil {
+		t.Fatalf("Expected values to be stored, got %v", err)
+	}
+	if values := sceneValues(sceneID); values[1] != 40 {
+		t.Errorf("Expected the value stored as given, got %v", values)
+	}
+}
+
+func TestOverrideDmxChannel_Expires(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var resp struct {
+		OverrideDmxChannel bool `json:"overrideDmxChannel"`
+	}
+
+	err := c.Post(`mutation {
+		overrideDmxChannel(universe: 3, channel: 101, value: 255, ttlSeconds: 0.1)
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("overrideDmxChannel mutation failed: %v", err)
+	}
+	if !resp.OverrideDmxChannel {
+		t.Error("Expected overrideDmxChannel to return true")
+	}
+
+	if got := resolver.DMXService.GetUniverse(3)[100]; got != 255 {
+		t.Errorf("Expected output 255 on 3/101, got %d", got)
+	}
+	// The override does not touch the underlying channel value
+	if got := resolver.DMXService.GetChannelValue(3, 101); got != 0 {
+		t.Errorf("Expected base value 0 on 3/101, got %d", got)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if got := resolver.DMXService.GetUniverse(3)[100]; got != 0 {
+		t.Errorf("Expected override to expire, got %d", got)
+	}
+}
+
+func TestOverrideDmxChannel_Validation(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	var resp struct {
+		OverrideDmxChannel bool `json:"overrideDmxChannel"`
+	}
+
+	queries := []string{
+		`mutation { overrideDmxChannel(universe: 1, channel: 1, value: 256, ttlSeconds: 10) }`,
+		`mutation { overrideDmxChannel(universe: 1, channel: 513, value: 255, ttlSeconds: 10) }`,
+		`mutation { overrideDmxChannel(universe: 1, channel: 1, value: 255, ttlSeconds: 0) }`,
+		`mutation { overrideDmxChannel(universe: 1, channel: 1, value: 255, ttlSeconds: 3600) }`,
+	}
+	for _, q := range queries {
+		if err := c.Post(q, &resp); err == nil {
+			t.Errorf("Expected error for %s", q)
+		}
+	}
+}
+
+func TestFixtureMaxIntensity_LimitsOutput(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-caps", Name: "Caps Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-caps", Manufacturer: "Test", Model: "Rail Par", Type: "LED_PAR"})
+
+	// A dimmer fixture and an RGB fixture without a dimmer
+	resolver.db.Create(&models.FixtureInstance{ID: "caps-dimmer", Name: "Rail 1", ProjectID: project.ID, DefinitionID: "test-def-caps", Universe: 1, StartChannel: 10})
+	resolver.db.Create(&models.InstanceChannel{ID: "caps-dimmer-0", FixtureID: "caps-dimmer", Offset: 0, Name: "Dimmer", Type: "INTENSITY"})
+	resolver.db.Create(&models.InstanceChannel{ID: "caps-dimmer-1", FixtureID: "caps-dimmer", Offset: 1, Name: "Red", Type: "RED"})
+	resolver.db.Create(&models.FixtureInstance{ID: "caps-rgb", Name: "Rail 2", ProjectID: project.ID, DefinitionID: "test-def-caps", Universe: 1, StartChannel: 20})
+	resolver.db.Create(&models.InstanceChannel{ID: "caps-rgb-0", FixtureID: "caps-rgb", Offset: 0, Name: "Red", Type: "RED"})
+	resolver.db.Create(&models.InstanceChannel{ID: "caps-rgb-1", FixtureID: "caps-rgb", Offset: 1, Name: "Strobe", Type: "STROBE"})
+
+	var updateResp struct {
+		UpdateFixtureInstance struct {
+			MaxIntensity *float64 `json:"maxIntensity"`
+		} `json:"updateFixtureInstance"`
+	}
+	for _, id := range []string{"caps-dimmer", "caps-rgb"} {
+		err := c.Post(`mutation($id: ID!) {
+			updateFixtureInstance(id: $id, input: { maxIntensity: 0.7 }) { maxIntensity }
+		}`, &updateResp, client.Var("id", id))
+		if err != nil {
+			t.Fatalf("updateFixtureInstance failed: %v", err)
+		}
+		if updateResp.UpdateFixtureInstance.MaxIntensity == nil || *updateResp.UpdateFixtureInstance.MaxIntensity != 0.7 {
+			t.Errorf("Expected maxIntensity 0.7, got %v", updateResp.UpdateFixtureInstance.MaxIntensity)
+		}
+	}
+
+	for _, channel := range []int{10, 11, 20, 21} {
+		resolver.DMXService.SetChannelValue(1, channel, 255)
+	}
+	// Overrides are limited too
+	if err := resolver.DMXService.SetTimedOverride(1, 10, 255, time.Minute); err != nil {
+		t.Fatalf("SetTimedOverride() error: %v", err)
+	}
+
+	output := resolver.DMXService.GetUniverse(1)
+	want := map[int]int{10: 179, 11: 255, 20: 179, 21: 255}
+	for channel, value := range want {
+		if output[channel-1] != value {
+			t.Errorf("Channel %d output = %d, want %d", channel, output[channel-1], value)
+		}
+	}
+
+	var reportResp struct {
+		IntensityLimitReport []struct {
+			Fixture struct {
+				ID string `json:"id"`
+			} `json:"fixture"`
+			MaxValue        int   `json:"maxValue"`
+			Channels        []int `json:"channels"`
+			LimitedChannels []int `json:"limitedChannels"`
+		} `json:"intensityLimitReport"`
+	}
+	err := c.Post(`query($projectId: ID!) {
+		intensityLimitReport(projectId: $projectId) { fixture { id } maxValue channels limitedChannels }
+	}`, &reportResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("intensityLimitReport failed: %v", err)
+	}
+	report := reportResp.IntensityLimitReport
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 capped fixtures, got %d", len(report))
+	}
+	if report[0].Fixture.ID != "caps-dimmer" || report[0].MaxValue != 179 || len(report[0].Channels) != 1 || report[0].Channels[0] != 10 {
+		t.Errorf("Unexpected dimmer report: %+v", report[0])
+	}
+	if report[1].Fixture.ID != "caps-rgb" || len(report[1].LimitedChannels) != 1 || report[1].LimitedChannels[0] != 20 {
+		t.Errorf("Unexpected RGB report: %+v", report[1])
+	}
+
+	// Removing the cap restores full output
+	err = c.Post(`mutation { updateFixtureInstance(id: "caps-rgb", input: { maxIntensity: null }) { maxIntensity } }`, &updateResp)
+	if err != nil {
+		t.Fatalf("updateFixtureInstance failed: %v", err)
+	}
+	if got := resolver.DMXService.GetUniverse(1)[19]; got != 255 {
+		t.Errorf("Expected uncapped output 255, got %d", got)
+	}
+
+	err = c.Post(`mutation { updateFixtureInstance(id: "caps-rgb", input: { maxIntensity: 1.5 }) { maxIntensity } }`, &updateResp)
+	if err == nil {
+		t.Error("Expected error for maxIntensity above 1")
+	}
+}
+
+func TestTempo_TapAndQuantizedFollow(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var setResp struct {
+		SetTempo struct {
+			Bpm         float64 `json:"bpm"`
+			BeatsPerBar int     `json:"beatsPerBar"`
+		} `json:"setTempo"`
+	}
+	if err := c.Post(`mutation { setTempo(bpm: 128) { bpm beatsPerBar } }`, &setResp); err != nil {
+		t.Fatalf("setTempo failed: %v", err)
+	}
+	if setResp.SetTempo.Bpm != 128 || setResp.SetTempo.BeatsPerBar != 4 {
+		t.Errorf("Unexpected tempo: %+v", setResp.SetTempo)
+	}
+	if err := c.Post(`mutation { setTempo(bpm: 5) { bpm } }`, &setResp); err == nil {
+		t.Error("Expected error for BPM out of range")
+	}
+
+	var tapResp struct {
+		TapTempo struct {
+			TapCount int `json:"tapCount"`
+			Beat     int `json:"beat"`
+		} `json:"tapTempo"`
+	}
+	if err := c.Post(`mutation { tapTempo { tapCount beat } }`, &tapResp); err != nil {
+		t.Fatalf("tapTempo failed: %v", err)
+	}
+	if tapResp.TapTempo.TapCount != 1 || tapResp.TapTempo.Beat != 1 {
+		t.Errorf("Expected first tap on a downbeat, got %+v", tapResp.TapTempo)
+	}
+
+	project := &models.Project{ID: cuid.New(), Name: "Club Night"}
+	resolver.db.Create(project)
+	scene := &models.Scene{ID: cuid.New(), Name: "Chase", ProjectID: project.ID}
+	resolver.db.Create(scene)
+	cueList := &models.CueList{ID: cuid.New(), Name: "Set", ProjectID: project.ID}
+	resolver.db.Create(cueList)
+
+	var cueResp struct {
+		CreateCue struct {
+			ID             string  `json:"id"`
+			FollowQuantize *string `json:"followQuantize"`
+		} `json:"createCue"`
+	}
+	err := c.Post(`mutation($cueListId: ID!, $sceneId: ID!) {
+		createCue(input: { name: "Drop", cueNumber: 1, cueListId: $cueListId, sceneId: $sceneId, fadeInTime: 0, fadeOutTime: 0, followTime: 1, followQuantize: BAR }) {
+			id
+			followQuantize
+		}
+	}`, &cueResp, client.Var("cueListId", cueList.ID), client.Var("sceneId", scene.ID))
+	if err != nil {
+		t.Fatalf("createCue failed: %v", err)
+	}
+	if cueResp.CreateCue.FollowQuantize == nil || *cueResp.CreateCue.FollowQuantize != "BAR" {
+		t.Errorf("Expected BAR follow quantize, got %v", cueResp.CreateCue.FollowQuantize)
+	}
+
+	var bulkResp struct {
+		BulkUpdateCues []struct {
+			FollowQuantize *string `json:"followQuantize"`
+		} `json:"bulkUpdateCues"`
+	}
+	err = c.Post(`mutation($cueId: ID!) {
+		bulkUpdateCues(input: { cueIds: [$cueId], followQuantize: NONE }) { followQuantize }
+	}`, &bulkResp, client.Var("cueId", cueResp.CreateCue.ID))
+	if err != nil {
+		t.Fatalf("bulkUpdateCues failed: %v", err)
+	}
+	if len(bulkResp.BulkUpdateCues) != 1 || bulkResp.BulkUpdateCues[0].FollowQuantize != nil {
+		t.Errorf("Expected NONE to clear follow quantize, got %+v", bulkResp.BulkUpdateCues)
+	}
+}
+
+func TestOperationRecording(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	var setResp struct {
+		SetChannelValue bool `json:"setChannelValue"`
+	}
+	// Not recorded: the recorder is off by default
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 1, value: 10) }`, &setResp); err != nil {
+		t.Fatalf("setChannelValue failed: %v", err)
+	}
+
+	var startResp struct {
+		StartOperationRecording struct {
+			IsRecording bool    `json:"isRecording"`
+			StartedAt   *string `json:"startedAt"`
+		} `json:"startOperationRecording"`
+	}
+	if err := c.Post(`mutation { startOperationRecording { isRecording startedAt } }`, &startResp); err != nil {
+		t.Fatalf("startOperationRecording failed: %v", err)
+	}
+	if !startResp.StartOperationRecording.IsRecording || startResp.StartOperationRecording.StartedAt == nil {
+		t.Fatalf("Expected recording to start, got %+v", startResp.StartOperationRecording)
+	}
+
+	err := c.Post(`mutation SetLevel($universe: Int!, $value: Int!) { setChannelValue(universe: $universe, channel: 5, value: $value) }`,
+		&setResp, client.Var("universe", 1), client.Var("value", 200))
+	if err != nil {
+		t.Fatalf("setChannelValue failed: %v", err)
+	}
+	var outputResp struct {
+		DmxOutput []int `json:"dmxOutput"`
+	}
+	if err := c.Post(`query { dmxOutput(universe: 1) }`, &outputResp); err != nil {
+		t.Fatalf("dmxOutput failed: %v", err)
+	}
+
+	var statusResp struct {
+		OperationRecordingStatus struct {
+			OperationCount int `json:"operationCount"`
+		} `json:"operationRecordingStatus"`
+	}
+	if err := c.Post(`query { operationRecordingStatus { operationCount } }`, &statusResp); err != nil {
+		t.Fatalf("operationRecordingStatus failed: %v", err)
+	}
+	if statusResp.OperationRecordingStatus.OperationCount != 2 {
+		t.Errorf("Expected 2 recorded operations, got %d", statusResp.OperationRecordingStatus.OperationCount)
+	}
+
+	var stopResp struct {
+		StopOperationRecording struct {
+			OperationCount int    `json:"operationCount"`
+			Content        string `json:"content"`
+		} `json:"stopOperationRecording"`
+	}
+	if err := c.Post(`mutation { stopOperationRecording { operationCount content } }`, &stopResp); err != nil {
+		t.Fatalf("stopOperationRecording failed: %v", err)
+	}
+
+	recording, err := recorder.ParseRecording([]byte(stopResp.StopOperationRecording.Content))
+	if err != nil {
+		t.Fatalf("ParseRecording() error: %v", err)
+	}
+	if len(recording.Operations) != 2 {
+		t.Fatalf("Expected 2 operations in recording, got %d", len(recording.Operations))
+	}
+	first := recording.Operations[0]
+	if first.Type != "mutation" || first.OperationName != "SetLevel" || first.Variables["value"] != float64(200) {
+		t.Errorf("Unexpected first operation: %+v", first)
+	}
+	if recording.Operations[1].Type != "query" {
+		t.Errorf("Expected second operation to be a query, got %s", recording.Operations[1].Type)
+	}
+
+	if err := c.Post(`mutation { stopOperationRecording { operationCount } }`, &stopResp); err == nil {
+		t.Error("Expected error stopping when no recording is active")
+	}
+}
+
+func TestPlaybackLog_RecordAndReplay(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var setResp struct {
+		SetChannelValue bool `json:"setChannelValue"`
+	}
+	err := c.Post(`mutation {
+		setChannelValue(universe: 1, channel: 5, value: 90)
+	}`, &setResp)
+	if err != nil {
+		t.Fatalf("SetChannelValue mutation failed: %v", err)
+	}
+
+	var logResp struct {
+		PlaybackLog struct {
+			EventCount int     `json:"eventCount"`
+			StartedAt  *string `json:"startedAt"`
+			Content    string  `json:"content"`
+		} `json:"playbackLog"`
+	}
+	err = c.Post(`query {
+		playbackLog { eventCount startedAt content }
+	}`, &logResp)
+	if err != nil {
+		t.Fatalf("playbackLog query failed: %v", err)
+	}
+	if logResp.PlaybackLog.EventCount != 1 || logResp.PlaybackLog.StartedAt == nil {
+		t.Fatalf("Expected one recorded event, got %+v", logResp.PlaybackLog)
+	}
+
+	resolver.DMXService.SetChannelValue(1, 5, 0)
+	resolver.DMXService.SetChannelValue(1, 6, 200)
+
+	var replayResp struct {
+		ReplayPlaybackLog int `json:"replayPlaybackLog"`
+	}
+	err = c.Post(`mutation($content: String!) {
+		replayPlaybackLog(content: $content)
+	}`, &replayResp, client.Var("content", logResp.PlaybackLog.Content))
+	if err != nil {
+		t.Fatalf("replayPlaybackLog mutation failed: %v", err)
+	}
+	if replayResp.ReplayPlaybackLog != 1 {
+		t.Errorf("Expected 1 replayed event, got %d", replayResp.ReplayPlaybackLog)
+	}
+	if value := resolver.DMXService.GetUniverse(1)[4]; value != 90 {
+		t.Errorf("Expected channel 5 = 90 after replay, got %d", value)
+	}
+	if value := resolver.DMXService.GetChannelValue(1, 6); value != 0 {
+		t.Errorf("Expected replay to reset channel 6 to 0, got %d", value)
+	}
+
+	err = c.Post(`mutation {
+		replayPlaybackLog(content: "{}")
+	}`, &replayResp)
+	if err == nil {
+		t.Error("Expected error replaying an invalid log")
+	}
+}
+
 func TestFadeToBlack_Mutation(t *testing.T) {
 	c, resolver, cleanup := testSetup(t)
 	defer cleanup()
@@ -472,8 +1057,8 @@ func TestMultipleChannelOperations(t *testing.T) {
 
 	// Verify all channels were set
 	for i := 1; i <= 10; i++ {
-		value := resolver.DMXService.GetChannelValue(1, i)
-		expected := byte(i * 10)
+		value := resolver.DMXService.GetUniverse(1)[i-1]
+		expected := i * 10
 		if value != expected {
 			t.Errorf("Channel %d: expected %d, got %d", i, expected, value)
 		}
@@ -826,3 +1411,4786 @@ func TestFadeUpdateRate_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestCaptureDmxTraffic_ValidationErrors(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"zero seconds", `mutation { captureDmxTraffic(universe: 1, seconds: 0) { packetCount } }`},
+		{"too long", `mutation { captureDmxTraffic(universe: 1, seconds: 120) { packetCount } }`},
+		{"invalid universe", `mutation { captureDmxTraffic(universe: 99, seconds: 1) { packetCount } }`},
+		{"art-net disabled", `mutation { captureDmxTraffic(seconds: 0.1) { packetCount } }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp struct {
+				CaptureDmxTraffic struct {
+					PacketCount int `json:"packetCount"`
+				} `json:"captureDmxTraffic"`
+			}
+
+			if err := c.Post(tt.query, &resp); err == nil {
+				t.Errorf("Expected error for %s, got none", tt.name)
+			}
+		})
+	}
+}
+
+func TestArtNetRoutingReport_Query(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "routing-project", Name: "Routing Project"}
+	resolver.db.Create(project)
+
+	fixtureDef := &models.FixtureDefinition{
+		ID:           "routing-fixture-def",
+		Manufacturer: "Test",
+		Model:        "RoutingPar",
+		Type:         "LED_PAR",
+	}
+	resolver.db.Create(fixtureDef)
+
+	for i, universe := range []int{1, 1, 2} {
+		resolver.db.Create(&models.FixtureInstance{
+			ID:           fmt.Sprintf("routing-fixture-%d", i),
+			Name:         fmt.Sprintf("Par %d", i),
+			ProjectID:    project.ID,
+			DefinitionID: fixtureDef.ID,
+			Universe:     universe,
+			StartChannel: 1 + i*10,
+		})
+	}
+
+	var resp struct {
+		ArtNetRoutingReport struct {
+			Routes []struct {
+				Universe     int  `json:"universe"`
+				FixtureCount int  `json:"fixtureCount"`
+				IsRouted     bool `json:"isRouted"`
+				Nodes        []struct {
+					IPAddress string `json:"ipAddress"`
+				} `json:"nodes"`
+			} `json:"routes"`
+			UnroutedUniverses []int `json:"unroutedUniverses"`
+			UnusedNodes       []struct {
+				ShortName *string `json:"shortName"`
+			} `json:"unusedNodes"`
+		} `json:"artNetRoutingReport"`
+	}
+
+	err := c.Post(`query {
+		artNetRoutingReport(projectId: "routing-project", nodes: [
+			{ ipAddress: "10.0.0.10", shortName: "Stage Left", outputUniverses: [1] }
+			{ ipAddress: "10.0.0.11", shortName: "Spare", outputUniverses: [8] }
+		]) {
+			routes { universe fixtureCount isRouted nodes { ipAddress } }
+			unroutedUniverses
+			unusedNodes { shortName }
+		}
+	}`, &resp)
+	if err != nil {
+		t.Fatalf("artNetRoutingReport query failed: %v", err)
+	}
+
+	report := resp.ArtNetRoutingReport
+	if len(report.Routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(report.Routes))
+	}
+	if report.Routes[0].Universe != 1 || report.Routes[0].FixtureCount != 2 || !report.Routes[0].IsRouted {
+		t.Errorf("Unexpected universe 1 route: %+v", report.Routes[0])
+	}
+	if len(report.Routes[0].Nodes) != 1 || report.Routes[0].Nodes[0].IPAddress != "10.0.0.10" {
+		t.Errorf("Expected universe 1 routed to 10.0.0.10, got %+v", report.Routes[0].Nodes)
+	}
+	if report.Routes[1].IsRouted {
+		t.Error("Expected universe 2 to be unrouted")
+	}
+	if len(report.UnroutedUniverses) != 1 || report.UnroutedUniverses[0] != 2 {
+		t.Errorf("Expected unrouted universes [2], got %v", report.UnroutedUniverses)
+	}
+	if len(report.UnusedNodes) != 1 || report.UnusedNodes[0].ShortName == nil || *report.UnusedNodes[0].ShortName != "Spare" {
+		t.Errorf("Expected unused node 'Spare', got %+v", report.UnusedNodes)
+	}
+}
+
+func TestShowTimer_CountdownTriggersCueList(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+	defer resolver.ShowTimerService.Cleanup()
+
+	project := &models.Project{ID: "test-project-timer", Name: "Timer Project"}
+	resolver.db.Create(project)
+	scene := &models.Scene{ID: "test-scene-timer", Name: "Preshow", ProjectID: project.ID}
+	resolver.db.Create(scene)
+	cueList := &models.CueList{ID: "test-cuelist-timer", Name: "Main", ProjectID: project.ID}
+	resolver.db.Create(cueList)
+	resolver.db.Create(&models.Cue{ID: "test-cue-timer", Name: "Preshow", CueNumber: 1, CueListID: cueList.ID, SceneID: scene.ID})
+
+	type showTimer struct {
+		ID               string  `json:"id"`
+		Kind             string  `json:"kind"`
+		RemainingSeconds float64 `json:"remainingSeconds"`
+		IsRunning        bool    `json:"isRunning"`
+	}
+
+	var createResp struct {
+		CreateShowTimer showTimer `json:"createShowTimer"`
+	}
+	err := c.Post(`mutation($cueListId: ID!) {
+		createShowTimer(input: { name: "House Open", durationSeconds: 0.1, triggerCueListId: $cueListId }) {
+			id kind remainingSeconds isRunning
+		}
+	}`, &createResp, client.Var("cueListId", cueList.ID))
+	if err != nil {
+		t.Fatalf("createShowTimer mutation failed: %v", err)
+	}
+	timer := createResp.CreateShowTimer
+	if timer.Kind != "COUNTDOWN" || timer.RemainingSeconds != 0.1 || timer.IsRunning {
+		t.Fatalf("Unexpected new timer: %+v", timer)
+	}
+
+	var adjustResp struct {
+		AdjustShowTimer showTimer `json:"adjustShowTimer"`
+	}
+	err = c.Post(`mutation($id: ID!) {
+		adjustShowTimer(id: $id, deltaSeconds: 0.1) { id remainingSeconds }
+	}`, &adjustResp, client.Var("id", timer.ID))
+	if err != nil {
+		t.Fatalf("adjustShowTimer mutation failed: %v", err)
+	}
+	if adjustResp.AdjustShowTimer.RemainingSeconds != 0.2 {
+		t.Errorf("Expected 0.2 seconds remaining after adjust, got %v", adjustResp.AdjustShowTimer.RemainingSeconds)
+	}
+
+	var startResp struct {
+		StartShowTimer showTimer `json:"startShowTimer"`
+	}
+	err = c.Post(`mutation($id: ID!) {
+		startShowTimer(id: $id) { id isRunning }
+	}`, &startResp, client.Var("id", timer.ID))
+	if err != nil {
+		t.Fatalf("startShowTimer mutation failed: %v", err)
+	}
+	if !startResp.StartShowTimer.IsRunning {
+		t.Error("Expected timer to be running")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for resolver.PlaybackService.GetPlaybackState(cueList.ID) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for countdown to start the cue list")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var listResp struct {
+		ShowTimers []struct {
+			ID         string `json:"id"`
+			HasExpired bool   `json:"hasExpired"`
+		} `json:"showTimers"`
+	}
+	err = c.Post(`query { showTimers { id hasExpired } }`, &listResp)
+	if err != nil {
+		t.Fatalf("showTimers query failed: %v", err)
+	}
+	if len(listResp.ShowTimers) != 1 || !listResp.ShowTimers[0].HasExpired {
+		t.Errorf("Expected one expired timer, got %+v", listResp.ShowTimers)
+	}
+
+	var deleteResp struct {
+		DeleteShowTimer bool `json:"deleteShowTimer"`
+	}
+	err = c.Post(`mutation($id: ID!) { deleteShowTimer(id: $id) }`, &deleteResp, client.Var("id", timer.ID))
+	if err != nil || !deleteResp.DeleteShowTimer {
+		t.Errorf("deleteShowTimer failed: %v", err)
+	}
+}
+
+func TestShowTimer_ValidationErrors(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	var resp struct{}
+	err := c.Post(`mutation {
+		createShowTimer(input: { name: "No Duration" }) { id }
+	}`, &resp)
+	if err == nil {
+		t.Error("Expected error for countdown without duration")
+	}
+
+	err = c.Post(`mutation {
+		createShowTimer(input: { name: "Bad Trigger", durationSeconds: 10, triggerCueListId: "missing" }) { id }
+	}`, &resp)
+	if err == nil {
+		t.Error("Expected error for unknown trigger cue list")
+	}
+
+	err = c.Post(`mutation { startShowTimer(id: "missing") { id } }`, &resp)
+	if err == nil {
+		t.Error("Expected error starting unknown timer")
+	}
+}
+
+func TestStandby_ConfigAndManualWake(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	const updateMutation = `mutation Update($input: StandbyConfigInput!) {
+		updateStandbyConfig(input: $input) {
+			isStandby
+			config { scheduleEnabled wakeOnArtNet openingHours { days open close } }
+		}
+	}`
+
+	var updateResp struct {
+		UpdateStandbyConfig struct {
+			IsStandby bool `json:"isStandby"`
+			Config    struct {
+				ScheduleEnabled bool `json:"scheduleEnabled"`
+				WakeOnArtNet    bool `json:"wakeOnArtNet"`
+				OpeningHours    []struct {
+					Days  []string `json:"days"`
+					Open  string   `json:"open"`
+					Close string   `json:"close"`
+				} `json:"openingHours"`
+			} `json:"config"`
+		} `json:"updateStandbyConfig"`
+	}
+	invalid := map[string]interface{}{
+		"scheduleEnabled": true,
+		"wakeOnArtNet":    false,
+		"openingHours":    []map[string]interface{}{{"days": []string{"MONDAY"}, "open": "9am", "close": "17:00"}},
+	}
+	if err := c.Post(updateMutation, &updateResp, client.Var("input", invalid)); err == nil {
+		t.Error("Expected error for invalid opening time")
+	}
+
+	input := map[string]interface{}{
+		"scheduleEnabled": false,
+		"wakeOnArtNet":    false,
+		"openingHours":    []map[string]interface{}{{"days": []string{"SATURDAY", "SUNDAY"}, "open": "10:00", "close": "18:00"}},
+	}
+	if err := c.Post(updateMutation, &updateResp, client.Var("input", input)); err != nil {
+		t.Fatalf("updateStandbyConfig failed: %v", err)
+	}
+	config := updateResp.UpdateStandbyConfig.Config
+	if updateResp.UpdateStandbyConfig.IsStandby || config.WakeOnArtNet || len(config.OpeningHours) != 1 {
+		t.Fatalf("Unexpected standby status: %+v", updateResp.UpdateStandbyConfig)
+	}
+	if days := config.OpeningHours[0].Days; len(days) != 2 || days[0] != "SATURDAY" || days[1] != "SUNDAY" {
+		t.Errorf("Expected weekend opening hours, got %v", days)
+	}
+
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), standby.SettingKey)
+	if err != nil || setting == nil || !strings.Contains(setting.Value, `"open":"10:00"`) {
+		t.Errorf("Expected standby configuration to be saved, got %+v, %v", setting, err)
+	}
+
+	resolver.DMXService.SetChannelValue(1, 1, 255)
+
+	var enterResp struct {
+		EnterStandby struct {
+			IsStandby bool    `json:"isStandby"`
+			Reason    *string `json:"reason"`
+			Since     *string `json:"since"`
+		} `json:"enterStandby"`
+	}
+	if err := c.Post(`mutation { enterStandby { isStandby reason since } }`, &enterResp); err != nil {
+		t.Fatalf("enterStandby failed: %v", err)
+	}
+	if !enterResp.EnterStandby.IsStandby || enterResp.EnterStandby.Reason == nil || *enterResp.EnterStandby.Reason != "MANUAL" || enterResp.EnterStandby.Since == nil {
+		t.Errorf("Unexpected standby status: %+v", enterResp.EnterStandby)
+	}
+	if !resolver.DMXService.IsStandby() || resolver.FadeEngine.IsRunning() {
+		t.Error("Expected DMX output and fades suspended")
+	}
+
+	var wakeResp struct {
+		WakeFromStandby struct {
+			IsStandby      bool    `json:"isStandby"`
+			Reason         *string `json:"reason"`
+			LastWakeReason *string `json:"lastWakeReason"`
+		} `json:"wakeFromStandby"`
+	}
+	if err := c.Post(`mutation { wakeFromStandby { isStandby reason lastWakeReason } }`, &wakeResp); err != nil {
+		t.Fatalf("wakeFromStandby failed: %v", err)
+	}
+	if wakeResp.WakeFromStandby.IsStandby || wakeResp.WakeFromStandby.Reason != nil ||
+		wakeResp.WakeFromStandby.LastWakeReason == nil || *wakeResp.WakeFromStandby.LastWakeReason != "MANUAL" {
+		t.Errorf("Unexpected status after wake: %+v", wakeResp.WakeFromStandby)
+	}
+	if resolver.DMXService.IsStandby() || !resolver.FadeEngine.IsRunning() {
+		t.Error("Expected DMX output and fades resumed")
+	}
+	if got := resolver.DMXService.GetChannelValue(1, 1); got != 255 {
+		t.Errorf("Expected channel value kept through standby, got %d", got)
+	}
+}
+
+func TestServerStandby(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type statusResp struct {
+		IsStandby bool    `json:"isStandby"`
+		Output    *string `json:"output"`
+		Config    struct {
+			Output string `json:"output"`
+		} `json:"config"`
+	}
+	const mutation = `mutation Standby($enabled: Boolean!, $output: StandbyOutput) {
+		serverStandby(enabled: $enabled, output: $output) { isStandby output config { output } }
+	}`
+
+	var resp struct {
+		ServerStandby statusResp `json:"serverStandby"`
+	}
+	if err := c.Post(mutation, &resp, client.Var("enabled", true), client.Var("output", "HOLD")); err != nil {
+		t.Fatalf("serverStandby failed: %v", err)
+	}
+	status := resp.ServerStandby
+	if !status.IsStandby || status.Output == nil || *status.Output != "HOLD" || status.Config.Output != "ZERO" {
+		t.Errorf("Expected a held standby over the default config, got %+v", status)
+	}
+	if !resolver.DMXService.IsStandby() || resolver.FadeEngine.IsRunning() {
+		t.Error("Expected DMX output and fades suspended")
+	}
+
+	if err := c.Post(mutation, &resp, client.Var("enabled", false)); err != nil {
+		t.Fatalf("serverStandby failed: %v", err)
+	}
+	if resp.ServerStandby.IsStandby || resp.ServerStandby.Output != nil || resolver.DMXService.IsStandby() {
+		t.Errorf("Expected wake, got %+v", resp.ServerStandby)
+	}
+
+	// The configured output is used when none is given
+	var updateResp struct {
+		UpdateStandbyConfig statusResp `json:"updateStandbyConfig"`
+	}
+	input := map[string]interface{}{"scheduleEnabled": false, "wakeOnArtNet": false, "openingHours": []interface{}{}, "output": "HOLD"}
+	if err := c.Post(`mutation Update($input: StandbyConfigInput!) { updateStandbyConfig(input: $input) { isStandby output config { output } } }`,
+		&updateResp, client.Var("input", input)); err != nil {
+		t.Fatalf("updateStandbyConfig failed: %v", err)
+	}
+	if updateResp.UpdateStandbyConfig.Config.Output != "HOLD" {
+		t.Errorf("Expected the HOLD output saved, got %+v", updateResp.UpdateStandbyConfig)
+	}
+	if err := c.Post(mutation, &resp, client.Var("enabled", true)); err != nil {
+		t.Fatalf("serverStandby failed: %v", err)
+	}
+	if resp.ServerStandby.Output == nil || *resp.ServerStandby.Output != "HOLD" {
+		t.Errorf("Expected the configured output, got %+v", resp.ServerStandby)
+	}
+	resolver.StandbyService.Wake()
+}
+
+func TestFaderWing_ButtonMapping(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "wing-project", Name: "Fader Wing"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "wing-def", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "wing-fixture", Name: "Dimmer", ProjectID: project.ID, DefinitionID: "wing-def", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "wing-ch", FixtureID: "wing-fixture", Name: "Dimmer", Type: "INTENSITY", Offset: 0, FadeBehavior: "FADE"})
+	resolver.db.Create(&models.Scene{ID: "wing-scene", Name: "Wash", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "wing-fv", SceneID: "wing-scene", FixtureID: "wing-fixture", Channels: `[{"offset":0,"value":200}]`})
+	resolver.db.Create(&models.SceneBoard{ID: "wing-board", Name: "Board", ProjectID: project.ID, DefaultFadeTime: 1})
+	resolver.db.Create(&models.SceneBoardButton{ID: "wing-button", SceneBoardID: "wing-board", SceneID: "wing-scene"})
+
+	const updateMutation = `mutation Update($input: FaderWingConfigInput!) {
+		updateFaderWingConfig(input: $input) {
+			enabled
+			availableTargets
+			mappings { universe channel target targetId }
+		}
+	}`
+
+	var resp struct {
+		UpdateFaderWingConfig struct {
+			Enabled          bool     `json:"enabled"`
+			AvailableTargets []string `json:"availableTargets"`
+			Mappings         []struct {
+				Universe int     `json:"universe"`
+				Channel  int     `json:"channel"`
+				Target   string  `json:"target"`
+				TargetID *string `json:"targetId"`
+			} `json:"mappings"`
+		} `json:"updateFaderWingConfig"`
+	}
+
+	mapping := func(channel int, target string, targetID interface{}) map[string]interface{} {
+		return map[string]interface{}{"universe": 16, "channel": channel, "target": target, "targetId": targetID}
+	}
+	for name, m := range map[string]map[string]interface{}{
+		"unknown button":       mapping(1, "SCENE_BOARD_BUTTON", "missing"),
+		"unavailable target":   mapping(1, "SUBMASTER", "sub-1"),
+		"channel out of range": mapping(600, "SCENE_BOARD_BUTTON", "wing-button"),
+	} {
+		config := map[string]interface{}{"enabled": false, "mappings": []interface{}{m}}
+		if err := c.Post(updateMutation, &resp, client.Var("input", config)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	config := map[string]interface{}{"enabled": false, "mappings": []interface{}{mapping(3, "SCENE_BOARD_BUTTON", "wing-button")}}
+	if err := c.Post(updateMutation, &resp, client.Var("input", config)); err != nil {
+		t.Fatalf("updateFaderWingConfig failed: %v", err)
+	}
+	got := resp.UpdateFaderWingConfig
+	if len(got.Mappings) != 1 || got.Mappings[0].TargetID == nil || *got.Mappings[0].TargetID != "wing-button" {
+		t.Fatalf("Unexpected mappings: %+v", got.Mappings)
+	}
+	if len(got.AvailableTargets) != 2 || got.AvailableTargets[0] != "GRAND_MASTER" || got.AvailableTargets[1] != "SCENE_BOARD_BUTTON" {
+		t.Errorf("AvailableTargets = %v, want [GRAND_MASTER SCENE_BOARD_BUTTON]", got.AvailableTargets)
+	}
+
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), input.SettingKey)
+	if err != nil || setting == nil || !strings.Contains(setting.Value, `"targetId":"wing-button"`) {
+		t.Errorf("Expected fader wing configuration to be saved, got %+v, %v", setting, err)
+	}
+
+	// Fader moves scale the button's scene
+	handler := resolver.faderWingButtonHandler()
+	handler("wing-button", 0.5)
+	if got := resolver.DMXService.GetChannelValue(1, 1); got != 100 {
+		t.Errorf("Dimmer at half fader = %d, want 100", got)
+	}
+	if state := resolver.HoldService.State("wing-button"); state == nil || !state.IsLatched {
+		t.Errorf("Expected button latched at fader level, got %+v", state)
+	}
+	handler("wing-button", 0)
+	if got := resolver.DMXService.GetChannelValue(1, 1); got != 0 {
+		t.Errorf("Dimmer with fader down = %d, want 0", got)
+	}
+}
+
+func TestArtNetUnicastRoutes(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type route struct {
+		Universe     int      `json:"universe"`
+		Destinations []string `json:"destinations"`
+	}
+
+	var setResp struct {
+		SetArtNetUnicastRoute []route `json:"setArtNetUnicastRoute"`
+	}
+	err := c.Post(`mutation {
+		setArtNetUnicastRoute(universe: 2, destinations: ["10.0.0.5", "10.0.0.6:6455"]) { universe destinations }
+	}`, &setResp)
+	if err != nil {
+		t.Fatalf("setArtNetUnicastRoute mutation failed: %v", err)
+	}
+	if len(setResp.SetArtNetUnicastRoute) != 1 || len(setResp.SetArtNetUnicastRoute[0].Destinations) != 2 {
+		t.Fatalf("Unexpected routes: %+v", setResp.SetArtNetUnicastRoute)
+	}
+
+	// Invalid destinations are rejected and leave the table alone
+	err = c.Post(`mutation {
+		setArtNetUnicastRoute(universe: 1, destinations: ["node.local"]) { universe }
+	}`, &setResp)
+	if err == nil {
+		t.Error("Expected error for a hostname destination")
+	}
+	if routes := resolver.DMXService.UnicastRoutes(); len(routes) != 1 || routes[0].Universe != 2 {
+		t.Errorf("Expected only universe 2 routed, got %+v", routes)
+	}
+
+	// The table is saved and restored on startup
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), dmx.UnicastRoutesSettingKey)
+	if err != nil || setting == nil || !strings.Contains(setting.Value, "10.0.0.6:6455") {
+		t.Fatalf("Expected saved routing table, got %+v (%v)", setting, err)
+	}
+	if err := resolver.DMXService.SetUnicastRoutes(nil); err != nil {
+		t.Fatalf("SetUnicastRoutes() error: %v", err)
+	}
+	resolver.loadUnicastRoutes(context.Background())
+
+	var queryResp struct {
+		ArtNetUnicastRoutes []route `json:"artNetUnicastRoutes"`
+	}
+	if err := c.Post(`query { artNetUnicastRoutes { universe destinations } }`, &queryResp); err != nil {
+		t.Fatalf("artNetUnicastRoutes query failed: %v", err)
+	}
+	if len(queryResp.ArtNetUnicastRoutes) != 1 || queryResp.ArtNetUnicastRoutes[0].Universe != 2 {
+		t.Errorf("Expected restored route for universe 2, got %+v", queryResp.ArtNetUnicastRoutes)
+	}
+
+	var removeResp struct {
+		RemoveArtNetUnicastRoute []route `json:"removeArtNetUnicastRoute"`
+	}
+	if err := c.Post(`mutation { removeArtNetUnicastRoute(universe: 2) { universe } }`, &removeResp); err != nil {
+		t.Fatalf("removeArtNetUnicastRoute mutation failed: %v", err)
+	}
+	if len(removeResp.RemoveArtNetUnicastRoute) != 0 || len(resolver.DMXService.UnicastRoutes()) != 0 {
+		t.Error("Expected all universes to be broadcast after removing the route")
+	}
+}
+
+func TestChannelLimits(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type limit struct {
+		Universe int     `json:"universe"`
+		Channel  int     `json:"channel"`
+		MaxLevel float64 `json:"maxLevel"`
+		Inhibit  bool    `json:"inhibit"`
+		Curve    string  `json:"curve"`
+	}
+
+	var setResp struct {
+		SetChannelLimits []limit `json:"setChannelLimits"`
+	}
+	err := c.Post(`mutation {
+		setChannelLimits(limits: [
+			{universe: 1, channel: 20, inhibit: true}
+			{universe: 1, channel: 10, maxLevel: 0.5, curve: SQUARE}
+		]) { universe channel maxLevel inhibit curve }
+	}`, &setResp)
+	if err != nil {
+		t.Fatalf("setChannelLimits mutation failed: %v", err)
+	}
+	if len(setResp.SetChannelLimits) != 2 {
+		t.Fatalf("Expected 2 limits, got %+v", setResp.SetChannelLimits)
+	}
+	if got := setResp.SetChannelLimits[0]; got.Channel != 10 || got.MaxLevel != 0.5 || got.Curve != "SQUARE" {
+		t.Errorf("Unexpected first limit: %+v", got)
+	}
+	if got := setResp.SetChannelLimits[1]; got.Channel != 20 || !got.Inhibit || got.MaxLevel != 1 || got.Curve != "LINEAR" {
+		t.Errorf("Unexpected second limit: %+v", got)
+	}
+
+	// Limits apply to the output regardless of what is sent
+	resolver.DMXService.SetChannelValue(1, 10, 255)
+	resolver.DMXService.SetChannelValue(1, 20, 255)
+	out := resolver.DMXService.GetUniverse(1)
+	if out[9] != 128 || out[19] != 0 {
+		t.Errorf("Expected channels 10 and 20 limited to 128 and 0, got %d and %d", out[9], out[19])
+	}
+
+	// Invalid limits are rejected and leave the limits alone
+	var oneResp struct {
+		SetChannelLimit []limit `json:"setChannelLimit"`
+	}
+	err = c.Post(`mutation {
+		setChannelLimit(input: {universe: 1, channel: 30, maxLevel: 2}) { channel }
+	}`, &oneResp)
+	if err == nil {
+		t.Error("Expected error for a max level above 1")
+	}
+	if limits := resolver.DMXService.ChannelLimits(); len(limits) != 2 {
+		t.Errorf("Expected 2 limits, got %+v", limits)
+	}
+
+	// Setting a channel's limit replaces it
+	err = c.Post(`mutation {
+		setChannelLimit(input: {universe: 1, channel: 20, maxLevel: 0.8}) { channel inhibit maxLevel }
+	}`, &oneResp)
+	if err != nil {
+		t.Fatalf("setChannelLimit mutation failed: %v", err)
+	}
+	if len(oneResp.SetChannelLimit) != 2 || oneResp.SetChannelLimit[1].Inhibit || oneResp.SetChannelLimit[1].MaxLevel != 0.8 {
+		t.Errorf("Expected channel 20 replaced, got %+v", oneResp.SetChannelLimit)
+	}
+
+	// The limits are saved and restored on startup
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), dmx.ChannelLimitsSettingKey)
+	if err != nil || setting == nil || !strings.Contains(setting.Value, `"SQUARE"`) {
+		t.Fatalf("Expected saved channel limits, got %+v (%v)", setting, err)
+	}
+	if err := resolver.DMXService.SetChannelLimits(nil); err != nil {
+		t.Fatalf("SetChannelLimits() error: %v", err)
+	}
+	resolver.loadChannelLimits(context.Background())
+
+	var queryResp struct {
+		ChannelLimits []limit `json:"channelLimits"`
+	}
+	if err := c.Post(`query { channelLimits { universe channel maxLevel } }`, &queryResp); err != nil {
+		t.Fatalf("channelLimits query failed: %v", err)
+	}
+	if len(queryResp.ChannelLimits) != 2 || queryResp.ChannelLimits[0].MaxLevel != 0.5 {
+		t.Errorf("Expected restored limits, got %+v", queryResp.ChannelLimits)
+	}
+
+	var removeResp struct {
+		RemoveChannelLimit []limit `json:"removeChannelLimit"`
+	}
+	if err := c.Post(`mutation { removeChannelLimit(universe: 1, channel: 10) { channel } }`, &removeResp); err != nil {
+		t.Fatalf("removeChannelLimit mutation failed: %v", err)
+	}
+	if len(removeResp.RemoveChannelLimit) != 1 || resolver.DMXService.GetUniverse(1)[9] != 255 {
+		t.Errorf("Expected channel 10 unlimited, got %+v", removeResp.RemoveChannelLimit)
+	}
+}
+
+func TestArtNetSync(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var setResp struct {
+		SetArtNetSync bool `json:"setArtNetSync"`
+	}
+	if err := c.Post(`mutation { setArtNetSync(enabled: true) }`, &setResp); err != nil {
+		t.Fatalf("setArtNetSync mutation failed: %v", err)
+	}
+	if !setResp.SetArtNetSync || !resolver.DMXService.ArtSyncEnabled() {
+		t.Error("Expected ArtSync enabled")
+	}
+
+	// The setting is saved and restored on startup
+	resolver.DMXService.SetArtSync(false)
+	resolver.loadArtSync(context.Background())
+
+	var queryResp struct {
+		ArtNetSync bool `json:"artNetSync"`
+	}
+	if err := c.Post(`query { artNetSync }`, &queryResp); err != nil {
+		t.Fatalf("artNetSync query failed: %v", err)
+	}
+	if !queryResp.ArtNetSync {
+		t.Error("Expected ArtSync restored from the saved setting")
+	}
+}
+
+func TestArtNetBind(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var setResp struct {
+		SetArtNetBind struct {
+			Bind          *string `json:"bind"`
+			Address       *string `json:"address"`
+			InterfaceName *string `json:"interfaceName"`
+		} `json:"setArtNetBind"`
+	}
+	if err := c.Post(`mutation { setArtNetBind(bind: "127.0.0.1") { bind address interfaceName } }`, &setResp); err != nil {
+		t.Fatalf("setArtNetBind mutation failed: %v", err)
+	}
+	binding := setResp.SetArtNetBind
+	if binding.Address == nil || *binding.Address != "127.0.0.1" || binding.InterfaceName == nil {
+		t.Fatalf("Unexpected binding %+v", binding)
+	}
+
+	var listResp struct {
+		ArtNetInterfaces []struct {
+			Name     string `json:"name"`
+			Address  string `json:"address"`
+			Selected bool   `json:"selected"`
+		} `json:"artNetInterfaces"`
+	}
+	if err := c.Post(`query { artNetInterfaces { name address selected } }`, &listResp); err != nil {
+		t.Fatalf("artNetInterfaces query failed: %v", err)
+	}
+	selected := 0
+	for _, iface := range listResp.ArtNetInterfaces {
+		if iface.Selected {
+			selected++
+			if iface.Address != "127.0.0.1" || iface.Name != *binding.InterfaceName {
+				t.Errorf("Unexpected selected interface %+v", iface)
+			}
+		}
+	}
+	if selected != 1 {
+		t.Errorf("Expected one selected interface, got %+v", listResp.ArtNetInterfaces)
+	}
+
+	// An address no interface has is rejected and not saved
+	if err := c.Post(`mutation { setArtNetBind(bind: "203.0.113.7") { bind } }`, &setResp); err == nil {
+		t.Error("Expected an error binding to an address no interface has")
+	}
+
+	// The setting is saved and restored on startup
+	if err := resolver.DMXService.SetBinding("0.0.0.0"); err != nil {
+		t.Fatalf("SetBinding() error: %v", err)
+	}
+	resolver.loadArtNetBind(context.Background())
+
+	var queryResp struct {
+		ArtNetBinding struct {
+			Address *string `json:"address"`
+		} `json:"artNetBinding"`
+	}
+	if err := c.Post(`query { artNetBinding { address } }`, &queryResp); err != nil {
+		t.Fatalf("artNetBinding query failed: %v", err)
+	}
+	if queryResp.ArtNetBinding.Address == nil || *queryResp.ArtNetBinding.Address != "127.0.0.1" {
+		t.Errorf("Expected the binding restored from the saved setting, got %+v", queryResp.ArtNetBinding)
+	}
+
+	// An empty bind restores the configured one, here unbound
+	if err := c.Post(`mutation { setArtNetBind(bind: "") { bind } }`, &setResp); err != nil {
+		t.Fatalf("setArtNetBind mutation failed: %v", err)
+	}
+	if got := resolver.DMXService.Binding(); got.Address != nil {
+		t.Errorf("Binding() = %+v, want unbound", got)
+	}
+}
+
+func TestDmxTransmitStats(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	resolver.DMXService.SetChannelValue(2, 1, 200)
+	sink.ExpectChannel(t, 2, 1, 200, 2*time.Second)
+
+	type transmitStats struct {
+		Cycles          int `json:"cycles"`
+		ChangedFrames   int `json:"changedFrames"`
+		KeepAliveFrames int `json:"keepAliveFrames"`
+		SkippedFrames   int `json:"skippedFrames"`
+		Universes       []struct {
+			Universe      int `json:"universe"`
+			ChangedFrames int `json:"changedFrames"`
+		} `json:"universes"`
+	}
+	var queryResp struct {
+		DmxTransmitStats transmitStats `json:"dmxTransmitStats"`
+	}
+	const fields = `{ cycles changedFrames keepAliveFrames skippedFrames universes { universe changedFrames } }`
+	if err := c.Post(`query { dmxTransmitStats `+fields+` }`, &queryResp); err != nil {
+		t.Fatalf("dmxTransmitStats query failed: %v", err)
+	}
+	stats := queryResp.DmxTransmitStats
+	if stats.Cycles == 0 || len(stats.Universes) != 4 || stats.Universes[1].Universe != 2 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+	if stats.Universes[1].ChangedFrames == 0 {
+		t.Errorf("Expected universe 2 sent for its change, got %+v", stats.Universes[1])
+	}
+	if total := stats.ChangedFrames + stats.KeepAliveFrames + stats.SkippedFrames; total != stats.Cycles*4 {
+		t.Errorf("Expected every universe counted each cycle, got %d frames over %d cycles", total, stats.Cycles)
+	}
+
+	resetAt := time.Now()
+	var resetResp struct {
+		ResetDmxTransmitStats transmitStats `json:"resetDmxTransmitStats"`
+	}
+	if err := c.Post(`mutation { resetDmxTransmitStats `+fields+` }`, &resetResp); err != nil {
+		t.Fatalf("resetDmxTransmitStats mutation failed: %v", err)
+	}
+	if resetResp.ResetDmxTransmitStats.Cycles < stats.Cycles {
+		t.Errorf("Expected the counters before the reset, got %+v", resetResp.ResetDmxTransmitStats)
+	}
+	if after := resolver.DMXService.TransmitStats(); after.Since.Before(resetAt) {
+		t.Errorf("Expected the counters reset, got %+v", after)
+	}
+}
+
+func TestMasterLevels_ScaleOutput(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-masters", Name: "Masters Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-masters", Manufacturer: "Test", Model: "Par", Type: "LED_PAR"})
+
+	// A dimmer with a snap shutter, an RGB fixture without a dimmer, and a
+	// relay dimmer that only snaps
+	resolver.db.Create(&models.FixtureInstance{ID: "gm-dimmer", Name: "Par 1", ProjectID: project.ID, DefinitionID: "test-def-masters", Universe: 1, StartChannel: 10})
+	resolver.db.Create(&models.InstanceChannel{ID: "gm-dimmer-0", FixtureID: "gm-dimmer", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	resolver.db.Create(&models.InstanceChannel{ID: "gm-dimmer-1", FixtureID: "gm-dimmer", Offset: 1, Name: "Strobe", Type: "STROBE", FadeBehavior: "SNAP"})
+	resolver.db.Create(&models.FixtureInstance{ID: "gm-rgb", Name: "Par 2", ProjectID: project.ID, DefinitionID: "test-def-masters", Universe: 1, StartChannel: 20})
+	resolver.db.Create(&models.InstanceChannel{ID: "gm-rgb-0", FixtureID: "gm-rgb", Offset: 0, Name: "Red", Type: "RED", FadeBehavior: "FADE"})
+	resolver.db.Create(&models.FixtureInstance{ID: "gm-relay", Name: "Relay", ProjectID: project.ID, DefinitionID: "test-def-masters", Universe: 1, StartChannel: 30})
+	resolver.db.Create(&models.InstanceChannel{ID: "gm-relay-0", FixtureID: "gm-relay", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "SNAP"})
+	resolver.refreshOutputLimits(context.Background())
+
+	for _, channel := range []int{10, 11, 20, 30} {
+		resolver.DMXService.SetChannelValue(1, channel, 200)
+	}
+
+	type levels struct {
+		GrandMaster float64 `json:"grandMaster"`
+		Universes   []struct {
+			Universe int     `json:"universe"`
+			Level    float64 `json:"level"`
+		} `json:"universes"`
+	}
+	var setResp struct {
+		SetMasterLevel levels `json:"setMasterLevel"`
+	}
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5) { grandMaster universes { universe level } } }`, &setResp); err != nil {
+		t.Fatalf("setMasterLevel mutation failed: %v", err)
+	}
+	if setResp.SetMasterLevel.GrandMaster != 0.5 || len(setResp.SetMasterLevel.Universes) != 0 {
+		t.Errorf("Unexpected levels: %+v", setResp.SetMasterLevel)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{10: 100, 11: 200, 20: 100, 30: 200}, 2*time.Second)
+
+	// Universe masters stack on the grand master
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5, universe: 1) { grandMaster universes { universe level } } }`, &setResp); err != nil {
+		t.Fatalf("setMasterLevel mutation failed: %v", err)
+	}
+	if len(setResp.SetMasterLevel.Universes) != 1 || setResp.SetMasterLevel.Universes[0].Level != 0.5 {
+		t.Errorf("Expected universe 1 master at 0.5, got %+v", setResp.SetMasterLevel)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{10: 50, 20: 50}, 2*time.Second)
+
+	for _, mutation := range []string{
+		`mutation { setMasterLevel(level: 1.5) { grandMaster } }`,
+		`mutation { setMasterLevel(level: 0.5, universe: 9) { grandMaster } }`,
+	} {
+		if err := c.Post(mutation, &setResp); err == nil {
+			t.Errorf("Expected error for %s", mutation)
+		}
+	}
+
+	var queryResp struct {
+		MasterLevels levels `json:"masterLevels"`
+	}
+	if err := c.Post(`query { masterLevels { grandMaster universes { universe level } } }`, &queryResp); err != nil {
+		t.Fatalf("masterLevels query failed: %v", err)
+	}
+	if queryResp.MasterLevels.GrandMaster != 0.5 || len(queryResp.MasterLevels.Universes) != 1 {
+		t.Errorf("Unexpected levels: %+v", queryResp.MasterLevels)
+	}
+}
+
+func TestMasterLevels_Journal(t *testing.T) {
+	_, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "state.journal"), journal.SyncNever, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	resolver.SetStateJournal(j)
+
+	universe := 2
+	if _, err := resolver.setMasterLevel(nil, 0.25); err != nil {
+		t.Fatalf("setMasterLevel() error: %v", err)
+	}
+	if _, err := resolver.setMasterLevel(&universe, 0.75); err != nil {
+		t.Fatalf("setMasterLevel() error: %v", err)
+	}
+
+	// A restart restores the journaled levels
+	_ = resolver.DMXService.SetGrandMaster(1)
+	_ = resolver.DMXService.SetUniverseMaster(2, 1)
+	if _, err := resolver.ResumePlayback(context.Background()); err != nil {
+		t.Fatalf("ResumePlayback() error: %v", err)
+	}
+	levels := resolver.DMXService.MasterLevels()
+	if levels.GrandMaster != 0.25 || len(levels.Universes) != 1 || levels.Universes[0].Level != 0.75 {
+		t.Errorf("Expected restored masters, got %+v", levels)
+	}
+
+	// Returning to full clears the entry
+	if _, err := resolver.setMasterLevel(nil, 1); err != nil {
+		t.Fatalf("setMasterLevel() error: %v", err)
+	}
+	if _, ok := j.Entries(journalKindMaster)[journalKeyGrandMaster]; ok {
+		t.Error("Expected the grand master entry to be removed at full")
+	}
+}
+
+func TestResumePlayback(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type resumeResult struct {
+		CueLists int  `json:"cueLists"`
+		Scenes   int  `json:"scenes"`
+		Masters  int  `json:"masters"`
+		Blackout bool `json:"blackout"`
+	}
+	var resp struct {
+		ResumePlayback resumeResult `json:"resumePlayback"`
+	}
+	const resume = `mutation { resumePlayback { cueLists scenes masters blackout } }`
+	if err := c.Post(resume, &resp); err == nil {
+		t.Error("Expected resuming without a state journal to fail")
+	}
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "state.journal"), journal.SyncNever, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	resolver.SetStateJournal(j)
+
+	project := &models.Project{ID: "test-project-resume", Name: "Resume Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "rs-wash", Name: "Wash", ProjectID: project.ID})
+	resolver.db.Create(&models.Scene{ID: "rs-special", Name: "Special", ProjectID: project.ID})
+	resolver.db.Create(&models.Scene{ID: "rs-gone", Name: "Gone", ProjectID: project.ID})
+
+	var liveResp struct {
+		SetSceneLive bool `json:"setSceneLive"`
+	}
+	for _, id := range []string{"rs-wash", "rs-special", "rs-gone"} {
+		if err := c.Post(fmt.Sprintf(`mutation { setSceneLive(sceneId: "%s") }`, id), &liveResp); err != nil {
+			t.Fatalf("setSceneLive mutation failed: %v", err)
+		}
+	}
+	var releaseResp struct {
+		ReleasePlayback []struct {
+			PlaybackID string `json:"playbackId"`
+		} `json:"releasePlayback"`
+	}
+	if err := c.Post(`mutation { releasePlayback(playbackId: "scene:rs-special", fadeOutTime: 0) { playbackId } }`, &releaseResp); err != nil {
+		t.Fatalf("releasePlayback mutation failed: %v", err)
+	}
+	if _, err := resolver.setMasterLevel(nil, 0.5); err != nil {
+		t.Fatalf("setMasterLevel() error: %v", err)
+	}
+
+	// A restart comes up with nothing live; a scene was deleted meanwhile
+	resolver.StackService.Clear()
+	resolver.DMXService.ClearActiveScene()
+	_ = resolver.DMXService.SetGrandMaster(1)
+	resolver.db.Delete(&models.Scene{}, "id = ?", "rs-gone")
+
+	if err := c.Post(resume, &resp); err != nil {
+		t.Fatalf("resumePlayback mutation failed: %v", err)
+	}
+	if want := (resumeResult{Scenes: 1, Masters: 1}); resp.ResumePlayback != want {
+		t.Errorf("Expected %+v restored, got %+v", want, resp.ResumePlayback)
+	}
+	entries := resolver.StackService.Entries()
+	if len(entries) != 1 || entries[0].PlaybackID != stack.ScenePlayback("rs-wash") {
+		t.Errorf("Expected the wash live again, got %+v", entries)
+	}
+	if active := resolver.DMXService.GetActiveSceneID(); active == nil || *active != "rs-wash" {
+		t.Errorf("Expected the wash active, got %v", active)
+	}
+	if level := resolver.DMXService.MasterLevels().GrandMaster; level != 0.5 {
+		t.Errorf("Expected the grand master at 0.5, got %v", level)
+	}
+	if _, ok := j.Entries(journalKindLiveScene)[stack.ScenePlayback("rs-gone")]; ok {
+		t.Error("Expected the deleted scene's entry to be dropped")
+	}
+
+	// Fading to black clears the live scenes
+	var fadeResp struct {
+		FadeToBlack bool `json:"fadeToBlack"`
+	}
+	if err := c.Post(`mutation { fadeToBlack(fadeOutTime: 0) }`, &fadeResp); err != nil {
+		t.Fatalf("fadeToBlack mutation failed: %v", err)
+	}
+	if entries := j.Entries(journalKindLiveScene); len(entries) != 0 {
+		t.Errorf("Expected no live scenes journaled after a fade to black, got %v", entries)
+	}
+}
+
+func TestBlackout_AndRestore(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "state.journal"), journal.SyncNever, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	resolver.SetStateJournal(j)
+
+	project := &models.Project{ID: "test-project-blackout", Name: "Blackout Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-blackout", Manufacturer: "Test", Model: "Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "bo-par", Name: "Par", ProjectID: project.ID, DefinitionID: "test-def-blackout", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "bo-par-0", FixtureID: "bo-par", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	resolver.db.Create(&models.InstanceChannel{ID: "bo-par-1", FixtureID: "bo-par", Offset: 1, Name: "Pan", Type: "PAN", FadeBehavior: "FADE"})
+	resolver.refreshOutputLimits(context.Background())
+
+	resolver.DMXService.SetChannelValue(1, 1, 255)
+	resolver.DMXService.SetChannelValue(1, 2, 128)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 255, 2: 128}, 2*time.Second)
+
+	type blackoutStatus struct {
+		IsBlackout bool    `json:"isBlackout"`
+		Level      float64 `json:"level"`
+		Since      *string `json:"since"`
+	}
+	var blackoutResp struct {
+		Blackout blackoutStatus `json:"blackout"`
+	}
+	if err := c.Post(`mutation { blackout { isBlackout level since } }`, &blackoutResp); err != nil {
+		t.Fatalf("blackout mutation failed: %v", err)
+	}
+	if !blackoutResp.Blackout.IsBlackout || blackoutResp.Blackout.Level != 0 || blackoutResp.Blackout.Since == nil {
+		t.Errorf("Unexpected blackout status: %+v", blackoutResp.Blackout)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 0, 2: 128}, 2*time.Second)
+
+	// Journaled, so a restart comes back dark
+	if _, ok := j.Entries(journalKindBlackout)[journalKeyBlackout]; !ok {
+		t.Error("Expected the blackout to be journaled")
+	}
+
+	if err := c.Post(`mutation { blackout(fadeTime: 90) { isBlackout } }`, &blackoutResp); err == nil {
+		t.Error("Expected error for a fade above the maximum")
+	}
+
+	var restoreResp struct {
+		RestoreFromBlackout blackoutStatus `json:"restoreFromBlackout"`
+	}
+	if err := c.Post(`mutation { restoreFromBlackout(fadeTime: 0.2) { isBlackout level since } }`, &restoreResp); err != nil {
+		t.Fatalf("restoreFromBlackout mutation failed: %v", err)
+	}
+	if restoreResp.RestoreFromBlackout.IsBlackout || restoreResp.RestoreFromBlackout.Since != nil {
+		t.Errorf("Unexpected status after restore: %+v", restoreResp.RestoreFromBlackout)
+	}
+	sink.ExpectChannel(t, 1, 1, 255, 2*time.Second)
+	if _, ok := j.Entries(journalKindBlackout)[journalKeyBlackout]; ok {
+		t.Error("Expected the journal entry to be cleared on restore")
+	}
+
+	var statusResp struct {
+		BlackoutStatus blackoutStatus `json:"blackoutStatus"`
+	}
+	if err := c.Post(`query { blackoutStatus { isBlackout level since } }`, &statusResp); err != nil {
+		t.Fatalf("blackoutStatus query failed: %v", err)
+	}
+	if statusResp.BlackoutStatus.IsBlackout || statusResp.BlackoutStatus.Level != 1 {
+		t.Errorf("Unexpected blackout status: %+v", statusResp.BlackoutStatus)
+	}
+}
+
+func TestEffects_ChaseAndSceneAttachment(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-effects", Name: "Effects Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-effects", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	fixtureIDs := []string{"fx-1", "fx-2", "fx-3", "fx-4"}
+	for i, id := range fixtureIDs {
+		resolver.db.Create(&models.FixtureInstance{ID: id, Name: id, ProjectID: project.ID, DefinitionID: "test-def-effects", Universe: 1, StartChannel: i + 1})
+		resolver.db.Create(&models.InstanceChannel{ID: id + "-0", FixtureID: id, Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+		resolver.DMXService.SetChannelValue(1, i+1, 100)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100, 2: 100, 3: 100, 4: 100}, 2*time.Second)
+
+	type effect struct {
+		ID         string   `json:"id"`
+		Type       string   `json:"type"`
+		FixtureIds []string `json:"fixtureIds"`
+		Rate       float64  `json:"rate"`
+		Size       float64  `json:"size"`
+		Order      string   `json:"order"`
+		IsRunning  bool     `json:"isRunning"`
+	}
+	var createResp struct {
+		CreateEffect effect `json:"createEffect"`
+	}
+	// A slow chase lighting one fixture in four stays on its first step
+	err := c.Post(`mutation($input: CreateEffectInput!) {
+		createEffect(input: $input) { id type fixtureIds rate size order isRunning }
+	}`, &createResp, client.Var("input", map[string]interface{}{
+		"name":       "Chase",
+		"projectId":  project.ID,
+		"type":       "CHASE",
+		"fixtureIds": fixtureIDs,
+		"rate":       0.05,
+		"size":       0.25,
+	}))
+	if err != nil {
+		t.Fatalf("createEffect mutation failed: %v", err)
+	}
+	created := createResp.CreateEffect
+	if created.Type != "CHASE" || created.Order != "FORWARD" || created.Size != 0.25 || created.IsRunning || len(created.FixtureIds) != 4 {
+		t.Errorf("Unexpected effect: %+v", created)
+	}
+
+	var startResp struct {
+		StartEffect effect `json:"startEffect"`
+	}
+	if err := c.Post(`mutation($id: ID!) { startEffect(id: $id) { id isRunning } }`, &startResp, client.Var("id", created.ID)); err != nil {
+		t.Fatalf("startEffect mutation failed: %v", err)
+	}
+	if !startResp.StartEffect.IsRunning {
+		t.Error("Expected the effect to be running")
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 255, 2: 0, 3: 0, 4: 0}, 2*time.Second)
+
+	// Stopping reveals the values underneath
+	var stopResp struct {
+		StopEffect effect `json:"stopEffect"`
+	}
+	if err := c.Post(`mutation($id: ID!) { stopEffect(id: $id) { id isRunning } }`, &stopResp, client.Var("id", created.ID)); err != nil {
+		t.Fatalf("stopEffect mutation failed: %v", err)
+	}
+	if stopResp.StopEffect.IsRunning {
+		t.Error("Expected the effect to be stopped")
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100, 2: 100, 3: 100, 4: 100}, 2*time.Second)
+
+	// Rejects invalid parameters and fixtures from elsewhere
+	var updateResp struct {
+		UpdateEffect effect `json:"updateEffect"`
+	}
+	if err := c.Post(`mutation($id: ID!) { updateEffect(id: $id, input: { rate: 0 }) { id } }`, &updateResp, client.Var("id", created.ID)); err == nil {
+		t.Error("Expected error for a zero rate")
+	}
+	if err := c.Post(`mutation($id: ID!) { updateEffect(id: $id, input: { fixtureIds: ["missing"] }) { id } }`, &updateResp, client.Var("id", created.ID)); err == nil {
+		t.Error("Expected error for an unknown fixture")
+	}
+
+	// Attached to a scene, the effect runs while that scene is live
+	resolver.db.Create(&models.Scene{ID: "effects-scene", Name: "Effects Scene", ProjectID: project.ID})
+	err = c.Post(`mutation($id: ID!) { updateEffect(id: $id, input: { sceneId: "effects-scene", order: REVERSE }) { id order } }`,
+		&updateResp, client.Var("id", created.ID))
+	if err != nil {
+		t.Fatalf("updateEffect mutation failed: %v", err)
+	}
+	if updateResp.UpdateEffect.Order != "REVERSE" {
+		t.Errorf("Expected order REVERSE, got %s", updateResp.UpdateEffect.Order)
+	}
+
+	var liveResp struct {
+		SetSceneLive bool `json:"setSceneLive"`
+	}
+	if err := c.Post(`mutation { setSceneLive(sceneId: "effects-scene") }`, &liveResp); err != nil {
+		t.Fatalf("setSceneLive mutation failed: %v", err)
+	}
+	sink.ExpectChannel(t, 1, 4, 255, 2*time.Second)
+	sink.ExpectChannel(t, 1, 1, 0, 2*time.Second)
+
+	var effectsResp struct {
+		Effects []effect `json:"effects"`
+	}
+	if err := c.Post(`query($projectId: ID!) { effects(projectId: $projectId) { id isRunning } }`, &effectsResp, client.Var("projectId", project.ID)); err != nil {
+		t.Fatalf("effects query failed: %v", err)
+	}
+	if len(effectsResp.Effects) != 1 || !effectsResp.Effects[0].IsRunning {
+		t.Errorf("Expected the scene effect to be running, got %+v", effectsResp.Effects)
+	}
+
+	var deleteResp struct {
+		DeleteEffect bool `json:"deleteEffect"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deleteEffect(id: $id) }`, &deleteResp, client.Var("id", created.ID)); err != nil {
+		t.Fatalf("deleteEffect mutation failed: %v", err)
+	}
+	if resolver.EffectService.IsRunning(created.ID) {
+		t.Error("Expected deleting the effect to stop it")
+	}
+}
+
+func TestTimecode_FiresCues(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-timecode", Name: "Timecode Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-timecode", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "tc-dimmer", Name: "Dimmer", ProjectID: project.ID, DefinitionID: "test-def-timecode", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "tc-dimmer-0", FixtureID: "tc-dimmer", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	for i, level := range []int{100, 200} {
+		sceneID := fmt.Sprintf("tc-scene-%d", i+1)
+		resolver.db.Create(&models.Scene{ID: sceneID, Name: sceneID, ProjectID: project.ID})
+		resolver.db.Create(&models.FixtureValue{ID: sceneID + "-fv", SceneID: sceneID, FixtureID: "tc-dimmer", Channels: fmt.Sprintf(`[{"offset":0,"value":%d}]`, level)})
+	}
+	resolver.db.Create(&models.CueList{ID: "tc-list", Name: "Timecode List", ProjectID: project.ID})
+
+	var cueResp struct {
+		CreateCue struct {
+			ID       string  `json:"id"`
+			Timecode *string `json:"timecode"`
+		} `json:"createCue"`
+	}
+	createCue := `mutation($input: CreateCueInput!) { createCue(input: $input) { id timecode } }`
+	cueInput := func(number float64, sceneID, tc string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": sceneID, "cueNumber": number, "cueListId": "tc-list", "sceneId": sceneID,
+			"fadeInTime": 0, "fadeOutTime": 0, "timecode": tc,
+		}
+	}
+	if err := c.Post(createCue, &cueResp, client.Var("input", cueInput(1, "tc-scene-1", "00:00:00;05"))); err != nil {
+		t.Fatalf("createCue mutation failed: %v", err)
+	}
+	if cueResp.CreateCue.Timecode == nil || *cueResp.CreateCue.Timecode != "00:00:00:05" {
+		t.Errorf("Expected the timecode to be normalized, got %v", cueResp.CreateCue.Timecode)
+	}
+	if err := c.Post(createCue, &cueResp, client.Var("input", cueInput(2, "tc-scene-2", "00:05:00:00"))); err != nil {
+		t.Fatalf("createCue mutation failed: %v", err)
+	}
+	if err := c.Post(createCue, &cueResp, client.Var("input", cueInput(3, "tc-scene-2", "25:00:00:00"))); err == nil {
+		t.Error("Expected error for an invalid cue timecode")
+	}
+
+	type timecodeStatus struct {
+		Enabled   bool    `json:"enabled"`
+		Source    string  `json:"source"`
+		Position  *string `json:"position"`
+		IsRunning bool    `json:"isRunning"`
+	}
+	var configResp struct {
+		UpdateTimecodeConfig timecodeStatus `json:"updateTimecodeConfig"`
+	}
+	err := c.Post(`mutation { updateTimecodeConfig(input: {
+		enabled: true, source: INTERNAL, frameRate: FPS_25, cueListId: "tc-list", chaseToTime: true
+	}) { enabled source position isRunning } }`, &configResp)
+	if err != nil {
+		t.Fatalf("updateTimecodeConfig mutation failed: %v", err)
+	}
+	if !configResp.UpdateTimecodeConfig.Enabled || configResp.UpdateTimecodeConfig.IsRunning ||
+		configResp.UpdateTimecodeConfig.Position == nil || *configResp.UpdateTimecodeConfig.Position != "00:00:00:00" {
+		t.Errorf("Unexpected timecode status: %+v", configResp.UpdateTimecodeConfig)
+	}
+	if err := c.Post(`mutation { updateTimecodeConfig(input: { enabled: true, source: MTC, frameRate: FPS_25 }) { enabled } }`, &configResp); err == nil {
+		t.Error("Expected error for MTC without a MIDI device")
+	}
+
+	// Locating past cue 2 chases straight to it
+	var locateResp struct {
+		LocateTimecode timecodeStatus `json:"locateTimecode"`
+	}
+	if err := c.Post(`mutation { locateTimecode(position: "00:10:00:00") { position } }`, &locateResp); err != nil {
+		t.Fatalf("locateTimecode mutation failed: %v", err)
+	}
+	sink.ExpectChannel(t, 1, 1, 200, 2*time.Second)
+
+	// Playing from the top fires cue 1 as the clock passes it
+	if err := c.Post(`mutation { locateTimecode(position: "00:00:00:00") { position } }`, &locateResp); err != nil {
+		t.Fatalf("locateTimecode mutation failed: %v", err)
+	}
+	var startResp struct {
+		StartTimecode timecodeStatus `json:"startTimecode"`
+	}
+	if err := c.Post(`mutation { startTimecode { isRunning } }`, &startResp); err != nil {
+		t.Fatalf("startTimecode mutation failed: %v", err)
+	}
+	if !startResp.StartTimecode.IsRunning {
+		t.Error("Expected the timecode to be running")
+	}
+	sink.ExpectChannel(t, 1, 1, 100, 2*time.Second)
+
+	var stopResp struct {
+		StopTimecode timecodeStatus `json:"stopTimecode"`
+	}
+	if err := c.Post(`mutation { stopTimecode { isRunning } }`, &stopResp); err != nil {
+		t.Fatalf("stopTimecode mutation failed: %v", err)
+	}
+	if stopResp.StopTimecode.IsRunning {
+		t.Error("Expected the timecode to be stopped")
+	}
+
+	// Saved, so a restart follows the same cue list
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), "timecode_config")
+	if err != nil || setting == nil || !strings.Contains(setting.Value, `"cueListId":"tc-list"`) {
+		t.Errorf("Expected the configuration to be saved, got %+v (%v)", setting, err)
+	}
+}
+
+func TestTracking_CueOnlyAndBlock(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-tracking", Name: "Tracking Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-tracking", Manufacturer: "Test", Model: "Two Channel", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "trk-fixture", Name: "Fixture", ProjectID: project.ID, DefinitionID: "test-def-tracking", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.CueList{ID: "trk-list", Name: "Tracking List", ProjectID: project.ID, Tracking: true})
+	scenes := []string{
+		`[{"offset":0,"value":100},{"offset":1,"value":50}]`,
+		`[{"offset":0,"value":200}]`,
+		`[{"offset":1,"value":80}]`,
+		`[{"offset":0,"value":30}]`,
+	}
+	for i, channels := range scenes {
+		id := fmt.Sprintf("trk-%d", i+1)
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: project.ID})
+		resolver.db.Create(&models.FixtureValue{ID: id + "-fv", SceneID: id, FixtureID: "trk-fixture", Channels: channels})
+		resolver.db.Create(&models.Cue{ID: id, Name: id, CueListID: "trk-list", SceneID: id, CueNumber: float64(i + 1), Block: i == 3})
+	}
+
+	goToCue := func(index int) {
+		t.Helper()
+		var resp struct {
+			GoToCue bool `json:"goToCue"`
+		}
+		if err := c.Post(`mutation($index: Int!) { goToCue(cueListId: "trk-list", cueIndex: $index, fadeInTime: 0) }`, &resp, client.Var("index", index)); err != nil {
+			t.Fatalf("goToCue mutation failed: %v", err)
+		}
+	}
+
+	// Cue 2 keeps cue 1's second channel
+	goToCue(1)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 200, 2: 50}, 2*time.Second)
+	// The block cue releases what it does not set
+	goToCue(3)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 30, 2: 0}, 2*time.Second)
+
+	type trackedValue struct {
+		Offset      int    `json:"offset"`
+		Value       int    `json:"value"`
+		SourceCueID string `json:"sourceCueId"`
+		Tracked     bool   `json:"tracked"`
+	}
+	var cueResp struct {
+		Cue struct {
+			TrackedValues []trackedValue `json:"trackedValues"`
+		} `json:"cue"`
+	}
+	if err := c.Post(`{ cue(id: "trk-3") { trackedValues { offset value sourceCueId tracked } } }`, &cueResp); err != nil {
+		t.Fatalf("cue query failed: %v", err)
+	}
+	want := []trackedValue{{0, 200, "trk-2", true}, {1, 80, "trk-3", false}}
+	if got := cueResp.Cue.TrackedValues; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("trackedValues = %+v, want %+v", cueResp.Cue.TrackedValues, want)
+	}
+
+	// A cue-only change to cue 1 stops at cue 2
+	var updateResp struct {
+		UpdateCueValues struct {
+			ID string `json:"id"`
+		} `json:"updateCueValues"`
+	}
+	err := c.Post(`mutation { updateCueValues(cueId: "trk-1", cueOnly: true, fixtureValues: [
+		{fixtureId: "trk-fixture", channels: [{offset: 1, value: 70}]}
+	]) { id } }`, &updateResp)
+	if err != nil {
+		t.Fatalf("updateCueValues mutation failed: %v", err)
+	}
+	goToCue(0)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100, 2: 70}, 2*time.Second)
+	goToCue(1)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 200, 2: 50}, 2*time.Second)
+
+	// A tracking change carries on to later cues
+	err = c.Post(`mutation { updateCueValues(cueId: "trk-2", fixtureValues: [
+		{fixtureId: "trk-fixture", channels: [{offset: 0, value: 180}]}
+	]) { id } }`, &updateResp)
+	if err != nil {
+		t.Fatalf("updateCueValues mutation failed: %v", err)
+	}
+	goToCue(2)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 180, 2: 80}, 2*time.Second)
+}
+
+func TestSubmasters_SceneAndSelection(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-subs", Name: "Submaster Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-subs", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	for i, id := range []string{"sub-fx-1", "sub-fx-2"} {
+		resolver.db.Create(&models.FixtureInstance{ID: id, Name: id, ProjectID: project.ID, DefinitionID: "test-def-subs", Universe: 1, StartChannel: i + 1})
+		resolver.db.Create(&models.InstanceChannel{ID: id + "-0", FixtureID: id, Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	}
+	resolver.db.Create(&models.Scene{ID: "sub-scene", Name: "Sub Scene", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "sub-scene-fv", SceneID: "sub-scene", FixtureID: "sub-fx-1", Channels: `[{"offset":0,"value":200}]`})
+	resolver.DMXService.SetChannelValue(1, 2, 100)
+
+	type submaster struct {
+		ID         string   `json:"id"`
+		Page       int      `json:"page"`
+		Slot       int      `json:"slot"`
+		FixtureIds []string `json:"fixtureIds"`
+		Level      float64  `json:"level"`
+	}
+	var createResp struct {
+		CreateSubmaster submaster `json:"createSubmaster"`
+	}
+	create := `mutation($input: CreateSubmasterInput!) { createSubmaster(input: $input) { id page slot fixtureIds level } }`
+	err := c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": project.ID, "name": "Scene Sub", "slot": 1, "sceneId": "sub-scene",
+	}))
+	if err != nil {
+		t.Fatalf("createSubmaster mutation failed: %v", err)
+	}
+	sceneSub := createResp.CreateSubmaster
+	if sceneSub.Page != 1 || sceneSub.Level != 0 {
+		t.Errorf("Expected a scene submaster on page 1 at zero, got %+v", sceneSub)
+	}
+
+	err = c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": project.ID, "name": "Selection Sub", "page": 2, "slot": 1, "fixtureIds": []string{"sub-fx-2"},
+	}))
+	if err != nil {
+		t.Fatalf("createSubmaster mutation failed: %v", err)
+	}
+	selectionSub := createResp.CreateSubmaster
+	if selectionSub.Level != 1 || len(selectionSub.FixtureIds) != 1 {
+		t.Errorf("Expected a selection submaster at full, got %+v", selectionSub)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 0, 2: 100}, 2*time.Second)
+
+	// Raising the scene adds it; lowering the selection scales its fixture
+	var levelResp struct {
+		SetSubmasterLevel submaster `json:"setSubmasterLevel"`
+	}
+	setLevel := `mutation($id: ID!, $level: Float!) { setSubmasterLevel(id: $id, level: $level) { id level } }`
+	if err := c.Post(setLevel, &levelResp, client.Var("id", sceneSub.ID), client.Var("level", 0.5)); err != nil {
+		t.Fatalf("setSubmasterLevel mutation failed: %v", err)
+	}
+	if levelResp.SetSubmasterLevel.Level != 0.5 {
+		t.Errorf("Expected level 0.5, got %v", levelResp.SetSubmasterLevel.Level)
+	}
+	if err := c.Post(setLevel, &levelResp, client.Var("id", selectionSub.ID), client.Var("level", 0.5)); err != nil {
+		t.Fatalf("setSubmasterLevel mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100, 2: 50}, 2*time.Second)
+
+	if err := c.Post(setLevel, &levelResp, client.Var("id", sceneSub.ID), client.Var("level", 1.5)); err == nil {
+		t.Error("Expected error for a level above 1")
+	}
+	err = c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": project.ID, "name": "Taken", "slot": 1,
+	}))
+	if err == nil {
+		t.Error("Expected error for a slot that already has a submaster")
+	}
+	err = c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": project.ID, "name": "Both", "slot": 2, "sceneId": "sub-scene", "fixtureIds": []string{"sub-fx-2"},
+	}))
+	if err == nil {
+		t.Error("Expected error for a scene and a fixture selection")
+	}
+
+	var pagesResp struct {
+		SubmasterPages []struct {
+			Page       int         `json:"page"`
+			Submasters []submaster `json:"submasters"`
+		} `json:"submasterPages"`
+	}
+	err = c.Post(`query($projectId: ID!) { submasterPages(projectId: $projectId) { page submasters { id level } } }`,
+		&pagesResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("submasterPages query failed: %v", err)
+	}
+	if len(pagesResp.SubmasterPages) != 2 || pagesResp.SubmasterPages[1].Page != 2 || pagesResp.SubmasterPages[1].Submasters[0].ID != selectionSub.ID {
+		t.Errorf("Unexpected pages: %+v", pagesResp.SubmasterPages)
+	}
+
+	// Deleting the scene submaster removes what it added
+	var deleteResp struct {
+		DeleteSubmaster bool `json:"deleteSubmaster"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deleteSubmaster(id: $id) }`, &deleteResp, client.Var("id", sceneSub.ID)); err != nil {
+		t.Fatalf("deleteSubmaster mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 0, 2: 50}, 2*time.Second)
+}
+
+func TestPreview_BlindCommitToScene(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-blind", Name: "Blind Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-blind", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "blind-fx", Name: "blind-fx", ProjectID: project.ID, DefinitionID: "test-def-blind", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.Scene{ID: "blind-scene", Name: "Blind Scene", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "blind-scene-fv", SceneID: "blind-scene", FixtureID: "blind-fx", Channels: `[{"offset":0,"value":100}]`})
+	resolver.DMXService.SetChannelValue(1, 1, 30)
+
+	var startResp struct {
+		StartPreviewSession struct {
+			ID             string `json:"id"`
+			Blind          bool   `json:"blind"`
+			PreviewOutputs []struct {
+				Universe        int `json:"universe"`
+				PreviewUniverse int `json:"previewUniverse"`
+			} `json:"previewOutputs"`
+		} `json:"startPreviewSession"`
+	}
+	err := c.Post(`mutation($projectId: ID!) {
+		startPreviewSession(projectId: $projectId, blind: true, previewOutputs: [{universe: 1, previewUniverse: 2}]) {
+			id blind previewOutputs { universe previewUniverse }
+		}
+	}`, &startResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("startPreviewSession mutation failed: %v", err)
+	}
+	session := startResp.StartPreviewSession
+	if !session.Blind || len(session.PreviewOutputs) != 1 || session.PreviewOutputs[0].PreviewUniverse != 2 {
+		t.Fatalf("Expected a blind session previewing universe 1 on 2, got %+v", session)
+	}
+
+	var okResp map[string]interface{}
+	if err := c.Post(`mutation($id: ID!) { initializePreviewWithScene(sessionId: $id, sceneId: "blind-scene") }`, &okResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("initializePreviewWithScene mutation failed: %v", err)
+	}
+	if err := c.Post(`mutation($id: ID!) { updatePreviewChannel(sessionId: $id, fixtureId: "blind-fx", channelIndex: 0, value: 180) }`, &okResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("updatePreviewChannel mutation failed: %v", err)
+	}
+
+	// The edit renders to the session buffer and preview universe only
+	sink.ExpectChannels(t, 2, map[int]byte{1: 180}, 2*time.Second)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 30}, 2*time.Second)
+	var sessionResp struct {
+		PreviewSession struct {
+			SceneID   *string `json:"sceneId"`
+			DmxOutput []struct {
+				Universe int   `json:"universe"`
+				Channels []int `json:"channels"`
+			} `json:"dmxOutput"`
+		} `json:"previewSession"`
+	}
+	if err := c.Post(`query($id: ID!) { previewSession(sessionId: $id) { sceneId dmxOutput { universe channels } } }`, &sessionResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("previewSession query failed: %v", err)
+	}
+	preview := sessionResp.PreviewSession
+	if preview.SceneID == nil || *preview.SceneID != "blind-scene" {
+		t.Errorf("Expected sceneId blind-scene, got %v", preview.SceneID)
+	}
+	if len(preview.DmxOutput) != 1 || preview.DmxOutput[0].Channels[0] != 180 {
+		t.Errorf("Expected the session buffer to hold channel 1 at 180, got %+v", preview.DmxOutput)
+	}
+
+	// Committing writes the edit into the scene and frees the preview universe
+	if err := c.Post(`mutation($id: ID!) { commitPreviewSession(sessionId: $id) }`, &okResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("commitPreviewSession mutation failed: %v", err)
+	}
+	var fv models.FixtureValue
+	resolver.db.First(&fv, "id = ?", "blind-scene-fv")
+	if fv.Channels != `[{"offset":0,"value":180}]` {
+		t.Errorf("Expected the scene to hold the committed value, got %s", fv.Channels)
+	}
+	sink.ExpectChannels(t, 2, map[int]byte{1: 0}, 2*time.Second)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 30}, 2*time.Second)
+}
+
+// TestPreview_VisualizerShadowUniverses tests that a blind session can
+// mirror the project's universes by an offset to shadow universes sent only
+// to a visualizer.
+func TestPreview_VisualizerShadowUniverses(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-shadow", Name: "Shadow Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-shadow", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "shadow-fx-1", Name: "shadow-fx-1", ProjectID: project.ID, DefinitionID: "test-def-shadow", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.FixtureInstance{ID: "shadow-fx-2", Name: "shadow-fx-2", ProjectID: project.ID, DefinitionID: "test-def-shadow", Universe: 2, StartChannel: 1})
+	resolver.DMXService.SetChannelValue(1, 1, 30)
+
+	start := `mutation($projectId: ID!, $blind: Boolean, $offset: Int, $address: String) {
+		startPreviewSession(projectId: $projectId, blind: $blind, previewOutputs: [{universe: 2, previewUniverse: 150}], previewUniverseOffset: $offset, visualizerAddress: $address) {
+			id visualizerAddress previewOutputs { universe previewUniverse }
+		}
+	}`
+	var startResp struct {
+		StartPreviewSession struct {
+			ID                string  `json:"id"`
+			VisualizerAddress *string `json:"visualizerAddress"`
+			PreviewOutputs    []struct {
+				Universe        int `json:"universe"`
+				PreviewUniverse int `json:"previewUniverse"`
+			} `json:"previewOutputs"`
+		} `json:"startPreviewSession"`
+	}
+	vars := func(blind bool, offset int) []client.Option {
+		return []client.Option{
+			client.Var("projectId", project.ID), client.Var("blind", blind),
+			client.Var("offset", offset), client.Var("address", "127.0.0.1"),
+		}
+	}
+	if err := c.Post(start, &startResp, vars(false, 100)...); err == nil {
+		t.Error("Expected error for a visualizer without a blind session")
+	}
+	if err := c.Post(start, &startResp, vars(true, 0)...); err == nil {
+		t.Error("Expected error for a zero offset")
+	}
+	if err := c.Post(start, &startResp, vars(true, 100)...); err != nil {
+		t.Fatalf("startPreviewSession mutation failed: %v", err)
+	}
+	session := startResp.StartPreviewSession
+	if session.VisualizerAddress == nil || *session.VisualizerAddress != "127.0.0.1" {
+		t.Errorf("Expected visualizerAddress 127.0.0.1, got %v", session.VisualizerAddress)
+	}
+	outputs := session.PreviewOutputs
+	if len(outputs) != 2 || outputs[0].PreviewUniverse != 101 || outputs[1].PreviewUniverse != 150 {
+		t.Fatalf("Expected universe 1 offset to 101 and 2 named to 150, got %+v", outputs)
+	}
+
+	var okResp map[string]interface{}
+	if err := c.Post(`mutation($id: ID!) { updatePreviewChannel(sessionId: $id, fixtureId: "shadow-fx-1", channelIndex: 0, value: 180) }`, &okResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("updatePreviewChannel mutation failed: %v", err)
+	}
+	if got := resolver.DMXService.GetUniverse(101)[0]; got != 180 {
+		t.Errorf("Shadow universe 101 channel 1 = %d, want 180", got)
+	}
+	if got := resolver.DMXService.GetChannelValue(1, 1); got != 30 {
+		t.Errorf("Live channel 1 = %d, want 30", got)
+	}
+
+	if err := c.Post(`mutation($id: ID!) { cancelPreviewSession(sessionId: $id) }`, &okResp, client.Var("id", session.ID)); err != nil {
+		t.Fatalf("cancelPreviewSession mutation failed: %v", err)
+	}
+	if got := resolver.DMXService.GetUniverse(101)[0]; got != 0 {
+		t.Errorf("Shadow universe 101 channel 1 after cancel = %d, want 0", got)
+	}
+}
+
+// TestSetSceneFixtureValues tests that value sets merge into a scene in one
+// step with one sceneUpdated event, and that a bad set changes nothing.
+func TestSetSceneFixtureValues(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	three := 3
+	project := &models.Project{ID: "batch-project", Name: "Batch"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Project{ID: "batch-other", Name: "Other"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "batch-def", Manufacturer: "Test", Model: "RGB", Type: "LED_PAR"})
+	for i := 0; i < 20; i++ {
+		resolver.db.Create(&models.FixtureInstance{ID: fmt.Sprintf("batch-%d", i), Name: fmt.Sprintf("batch-%d", i), ProjectID: project.ID, DefinitionID: "batch-def", Universe: 1, StartChannel: 1 + 3*i, ChannelCount: &three})
+	}
+	resolver.db.Create(&models.FixtureInstance{ID: "batch-foreign", Name: "foreign", ProjectID: "batch-other", DefinitionID: "batch-def", Universe: 1, StartChannel: 1, ChannelCount: &three})
+	resolver.db.Create(&models.Scene{ID: "batch-scene", Name: "Wash", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "batch-fv", SceneID: "batch-scene", FixtureID: "batch-0", Channels: `[{"offset":0,"value":10},{"offset":2,"value":30}]`, PaletteIDs: "[]"})
+
+	channelsOf := func(fixtureID string) string {
+		t.Helper()
+		var fv models.FixtureValue
+		if err := resolver.db.First(&fv, "scene_id = ? AND fixture_id = ?", "batch-scene", fixtureID).Error; err != nil {
+			return ""
+		}
+		return fv.Channels
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subs := &subscriptionResolver{resolver}
+	updates, err := subs.SceneUpdated(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("SceneUpdated subscription failed: %v", err)
+	}
+
+	fixtureIDs := make([]string, 20)
+	for i := range fixtureIDs {
+		fixtureIDs[i] = fmt.Sprintf("batch-%d", i)
+	}
+	var resp struct {
+		SetSceneFixtureValues struct {
+			ID string `json:"id"`
+		} `json:"setSceneFixtureValues"`
+	}
+	err = c.Post(`mutation($fixtureIds: [ID!]!) {
+		setSceneFixtureValues(sceneId: "batch-scene", valueSets: [
+			{ fixtureIds: $fixtureIds, channels: [{ offset: 0, value: 255 }, { offset: 1, value: 100 }] }
+			{ fixtureIds: ["batch-1"], channels: [{ offset: 1, value: 7 }] }
+		]) { id }
+	}`, &resp, client.Var("fixtureIds", fixtureIDs))
+	if err != nil {
+		t.Fatalf("setSceneFixtureValues failed: %v", err)
+	}
+	if got := channelsOf("batch-0"); got != `[{"offset":0,"value":255},{"offset":1,"value":100},{"offset":2,"value":30}]` {
+		t.Errorf("Merged batch-0 = %s", got)
+	}
+	if got := channelsOf("batch-1"); got != `[{"offset":0,"value":255},{"offset":1,"value":7}]` {
+		t.Errorf("Added batch-1 = %s, want the later set to win", got)
+	}
+	if got := channelsOf("batch-19"); got == "" {
+		t.Error("Expected batch-19 added to the scene")
+	}
+
+	select {
+	case scene := <-updates:
+		if scene.ID != "batch-scene" {
+			t.Errorf("sceneUpdated scene = %s, want batch-scene", scene.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for sceneUpdated")
+	}
+	select {
+	case scene := <-updates:
+		t.Errorf("Expected one sceneUpdated event, got another for %s", scene.ID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	var undoable int64
+	resolver.db.Model(&models.UndoOperation{}).Where("project_id = ?", project.ID).Count(&undoable)
+	if undoable != 1 {
+		t.Errorf("Undo entries = %d, want one for the whole batch", undoable)
+	}
+
+	// A fixture from another project fails the batch before anything changes
+	err = c.Post(`mutation {
+		setSceneFixtureValues(sceneId: "batch-scene", valueSets: [
+			{ fixtureIds: ["batch-0", "batch-foreign"], channels: [{ offset: 0, value: 1 }] }
+		]) { id }
+	}`, &resp)
+	if err == nil {
+		t.Error("Expected error for a fixture of another project")
+	}
+	if got := channelsOf("batch-0"); !strings.HasPrefix(got, `[{"offset":0,"value":255}`) {
+		t.Errorf("Expected batch-0 unchanged by the failed batch, got %s", got)
+	}
+
+	// Inside a transaction the event waits for the commit, and a rollback
+	// drops it
+	scene, _ := resolver.SceneRepo.FindByID(ctx, "batch-scene")
+	err = resolver.transaction(ctx, func(tx *Resolver) error {
+		tx.publishSceneUpdated(ctx, scene)
+		return errors.New("rolled back")
+	})
+	if err == nil {
+		t.Fatal("Expected the transaction to fail")
+	}
+	err = resolver.transaction(ctx, func(tx *Resolver) error {
+		tx.publishSceneUpdated(ctx, scene)
+		select {
+		case <-updates:
+			t.Error("Expected sceneUpdated to wait for the commit")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	select {
+	case <-updates:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for sceneUpdated after the commit")
+	}
+	select {
+	case <-updates:
+		t.Error("Expected no sceneUpdated from the rolled back transaction")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestBulkUpdateCues_Atomic tests that bulkUpdateCues changes no cue when one
+// is not found.
+func TestBulkUpdateCues_Atomic(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "bulk-project", Name: "Bulk"})
+	resolver.db.Create(&models.Scene{ID: "bulk-scene", Name: "Look", ProjectID: "bulk-project"})
+	resolver.db.Create(&models.CueList{ID: "bulk-list", Name: "Main", ProjectID: "bulk-project"})
+	resolver.db.Create(&models.Cue{ID: "bulk-cue", Name: "One", CueNumber: 1, CueListID: "bulk-list", SceneID: "bulk-scene", FadeInTime: 3, FadeOutTime: 3})
+
+	var resp map[string]interface{}
+	err := c.Post(`mutation { bulkUpdateCues(input: { cueIds: ["bulk-cue", "missing-cue"], fadeInTime: 9 }) { id } }`, &resp)
+	if err == nil {
+		t.Fatal("Expected error for a missing cue")
+	}
+	var cue models.Cue
+	resolver.db.First(&cue, "id = ?", "bulk-cue")
+	if cue.FadeInTime != 3 {
+		t.Errorf("FadeInTime = %v, want the failed update rolled back", cue.FadeInTime)
+	}
+}
+
+func TestBulkUpdateCues_RecordsUndo(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	for _, id := range []string{"bulk-a", "bulk-b"} {
+		resolver.db.Create(&models.Project{ID: id, Name: id})
+		resolver.db.Create(&models.Scene{ID: id + "-scene", Name: "Look", ProjectID: id})
+		resolver.db.Create(&models.CueList{ID: id + "-list", Name: "Main", ProjectID: id})
+		resolver.db.Create(&models.Cue{ID: id + "-cue", Name: "One", CueNumber: 1, CueListID: id + "-list", SceneID: id + "-scene", FadeInTime: 3, FadeOutTime: 3})
+	}
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation { bulkUpdateCues(input: { cueIds: ["bulk-a-cue", "bulk-b-cue"], fadeInTime: 9 }) { id } }`, &resp); err != nil {
+		t.Fatalf("bulkUpdateCues mutation failed: %v", err)
+	}
+
+	// Each project gets its own undo entry for its cues
+	var undoResp struct {
+		Undo struct {
+			CanUndo bool `json:"canUndo"`
+		} `json:"undo"`
+	}
+	for _, id := range []string{"bulk-a", "bulk-b"} {
+		if err := c.Post(`mutation($projectId: ID!) { undo(projectId: $projectId) { canUndo } }`, &undoResp, client.Var("projectId", id)); err != nil {
+			t.Fatalf("undo mutation failed for %s: %v", id, err)
+		}
+		var cue models.Cue
+		resolver.db.First(&cue, "id = ?", id+"-cue")
+		if cue.FadeInTime != 3 {
+			t.Errorf("%s FadeInTime = %v after undo, want 3", id, cue.FadeInTime)
+		}
+	}
+}
+
+func TestCaptureActiveOutput_AndPartialMerge(t *testing.T) {
+	c, resolver, _, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	three, two := 3, 2
+	project := &models.Project{ID: "test-project-capture", Name: "Capture Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-capture", Manufacturer: "Test", Model: "RGB", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "cap-a", Name: "cap-a", ProjectID: project.ID, DefinitionID: "test-def-capture", Universe: 1, StartChannel: 1, ChannelCount: &three})
+	resolver.db.Create(&models.FixtureInstance{ID: "cap-b", Name: "cap-b", ProjectID: project.ID, DefinitionID: "test-def-capture", Universe: 1, StartChannel: 4, ChannelCount: &two})
+	resolver.db.Create(&models.FixtureInstance{ID: "cap-dark", Name: "cap-dark", ProjectID: project.ID, DefinitionID: "test-def-capture", Universe: 1, StartChannel: 10, ChannelCount: &two})
+	resolver.DMXService.SetChannelValue(1, 1, 10)
+	resolver.DMXService.SetChannelValue(1, 3, 30)
+	resolver.DMXService.SetChannelValue(1, 5, 50)
+
+	channelsOf := func(sceneID, fixtureID string) string {
+		t.Helper()
+		var fv models.FixtureValue
+		if err := resolver.db.First(&fv, "scene_id = ? AND fixture_id = ?", sceneID, fixtureID).Error; err != nil {
+			return ""
+		}
+		return fv.Channels
+	}
+
+	// Lit fixtures are captured into a new scene with every channel
+	var captureResp struct {
+		CaptureActiveOutput struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"captureActiveOutput"`
+	}
+	err := c.Post(`mutation($projectId: ID!) { captureActiveOutput(projectId: $projectId, name: "Captured") { id name } }`,
+		&captureResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("captureActiveOutput mutation failed: %v", err)
+	}
+	sceneID := captureResp.CaptureActiveOutput.ID
+	if got := channelsOf(sceneID, "cap-a"); got != `[{"offset":0,"value":10},{"offset":1,"value":0},{"offset":2,"value":30}]` {
+		t.Errorf("Captured cap-a = %s", got)
+	}
+	if got := channelsOf(sceneID, "cap-b"); got != `[{"offset":0,"value":0},{"offset":1,"value":50}]` {
+		t.Errorf("Captured cap-b = %s", got)
+	}
+	if got := channelsOf(sceneID, "cap-dark"); got != "" {
+		t.Errorf("Expected the dark fixture left out, got %s", got)
+	}
+
+	// A partial update merges by channel
+	var updateResp map[string]interface{}
+	err = c.Post(`mutation($sceneId: ID!) {
+		updateScenePartial(sceneId: $sceneId, fixtureValues: [{ fixtureId: "cap-a", channels: [{ offset: 1, value: 99 }] }]) { id }
+	}`, &updateResp, client.Var("sceneId", sceneID))
+	if err != nil {
+		t.Fatalf("updateScenePartial mutation failed: %v", err)
+	}
+	if got := channelsOf(sceneID, "cap-a"); got != `[{"offset":0,"value":10},{"offset":1,"value":99},{"offset":2,"value":30}]` {
+		t.Errorf("Merged cap-a = %s", got)
+	}
+	if got := channelsOf(sceneID, "cap-b"); got == "" {
+		t.Error("Expected cap-b kept by a partial update")
+	}
+
+	// A blind session's edits are captured into the existing scene
+	var startResp struct {
+		StartPreviewSession struct {
+			ID string `json:"id"`
+		} `json:"startPreviewSession"`
+	}
+	if err := c.Post(`mutation($projectId: ID!) { startPreviewSession(projectId: $projectId, blind: true) { id } }`,
+		&startResp, client.Var("projectId", project.ID)); err != nil {
+		t.Fatalf("startPreviewSession mutation failed: %v", err)
+	}
+	sessionID := startResp.StartPreviewSession.ID
+	var okResp map[string]interface{}
+	if err := c.Post(`mutation($id: ID!) { updatePreviewChannel(sessionId: $id, fixtureId: "cap-dark", channelIndex: 1, value: 90) }`,
+		&okResp, client.Var("id", sessionID)); err != nil {
+		t.Fatalf("updatePreviewChannel mutation failed: %v", err)
+	}
+	err = c.Post(`mutation($projectId: ID!, $sceneId: ID!, $sessionId: ID!) {
+		captureActiveOutput(projectId: $projectId, sceneId: $sceneId, sessionId: $sessionId, fixtureIds: ["cap-dark"]) { id }
+	}`, &captureResp, client.Var("projectId", project.ID), client.Var("sceneId", sceneID), client.Var("sessionId", sessionID))
+	if err != nil {
+		t.Fatalf("captureActiveOutput mutation failed: %v", err)
+	}
+	if got := channelsOf(sceneID, "cap-dark"); got != `[{"offset":0,"value":0},{"offset":1,"value":90}]` {
+		t.Errorf("Captured cap-dark = %s", got)
+	}
+	if got := channelsOf(sceneID, "cap-a"); !strings.Contains(got, `"value":99`) {
+		t.Errorf("Expected cap-a kept when capturing into the scene, got %s", got)
+	}
+
+	err = c.Post(`mutation($projectId: ID!) { captureActiveOutput(projectId: $projectId) { id } }`,
+		&captureResp, client.Var("projectId", project.ID))
+	if err == nil || !strings.Contains(err.Error(), "name is required") {
+		t.Errorf("Expected a name to be required, got %v", err)
+	}
+}
+
+func TestProgrammer_OverridesPlaybackAndRecords(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	four := 4
+	project := &models.Project{ID: "test-project-programmer", Name: "Programmer Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-programmer", Manufacturer: "Test", Model: "RGBW", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "prog-fx", Name: "prog-fx", ProjectID: project.ID, DefinitionID: "test-def-programmer", Universe: 1, StartChannel: 1, ChannelCount: &four})
+	resolver.db.Create(&models.Scene{ID: "prog-look", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "prog-look-fv", SceneID: "prog-look", FixtureID: "prog-fx", Channels: `[{"offset":0,"value":50},{"offset":3,"value":70}]`})
+
+	var okResp map[string]interface{}
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 2, value: 200) }`, &okResp); err != nil {
+		t.Fatalf("setChannelValue mutation failed: %v", err)
+	}
+
+	// Playback runs underneath the programmer
+	if err := c.Post(`mutation { setSceneLive(sceneId: "prog-look") }`, &okResp); err != nil {
+		t.Fatalf("setSceneLive mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 50, 2: 200, 4: 70}, 2*time.Second)
+
+	var programmerResp struct {
+		Programmer []struct {
+			Universe int `json:"universe"`
+			Channel  int `json:"channel"`
+			Value    int `json:"value"`
+		} `json:"programmer"`
+	}
+	if err := c.Post(`query { programmer { universe channel value } }`, &programmerResp); err != nil {
+		t.Fatalf("programmer query failed: %v", err)
+	}
+	if len(programmerResp.Programmer) != 1 || programmerResp.Programmer[0].Channel != 2 || programmerResp.Programmer[0].Value != 200 {
+		t.Errorf("Expected channel 2 at 200 in the programmer, got %+v", programmerResp.Programmer)
+	}
+
+	// Recording merges the programmed channels into the scene and clears them
+	var recordResp struct {
+		RecordProgrammerToScene struct {
+			ID string `json:"id"`
+		} `json:"recordProgrammerToScene"`
+	}
+	err := c.Post(`mutation($projectId: ID!) { recordProgrammerToScene(projectId: $projectId, sceneId: "prog-look", clear: true) { id } }`,
+		&recordResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("recordProgrammerToScene mutation failed: %v", err)
+	}
+	var fv models.FixtureValue
+	resolver.db.First(&fv, "id = ?", "prog-look-fv")
+	if fv.Channels != `[{"offset":0,"value":50},{"offset":1,"value":200},{"offset":3,"value":70}]` {
+		t.Errorf("Expected the programmed channel recorded into the scene, got %s", fv.Channels)
+	}
+	if len(resolver.DMXService.ProgrammerValues()) != 0 {
+		t.Error("Expected the programmer cleared after recording")
+	}
+	// The scene, now holding the value, is re-applied live
+	sink.ExpectChannels(t, 1, map[int]byte{1: 50, 2: 200, 4: 70}, 2*time.Second)
+
+	// Recording a new scene takes only programmed channels
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 3, value: 33) }`, &okResp); err != nil {
+		t.Fatalf("setChannelValue mutation failed: %v", err)
+	}
+	err = c.Post(`mutation($projectId: ID!) { recordProgrammerToScene(projectId: $projectId, name: "Busked") { id } }`,
+		&recordResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("recordProgrammerToScene mutation failed: %v", err)
+	}
+	var recorded models.FixtureValue
+	resolver.db.First(&recorded, "scene_id = ?", recordResp.RecordProgrammerToScene.ID)
+	if recorded.Channels != `[{"offset":2,"value":33}]` {
+		t.Errorf("Expected only the programmed channel recorded, got %s", recorded.Channels)
+	}
+
+	var clearResp struct {
+		ClearProgrammer bool `json:"clearProgrammer"`
+	}
+	if err := c.Post(`mutation { clearProgrammer }`, &clearResp); err != nil {
+		t.Fatalf("clearProgrammer mutation failed: %v", err)
+	}
+	if !clearResp.ClearProgrammer {
+		t.Error("Expected clearProgrammer to report values released")
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{3: 0}, 2*time.Second)
+}
+
+func TestFixtureColor_SetAndCreateScene(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-color", Name: "Color Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-rgbw", Manufacturer: "Test", Model: "RGBW", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "color-fx", Name: "color-fx", ProjectID: project.ID, DefinitionID: "test-def-rgbw", Universe: 1, StartChannel: 10})
+	for i, channelType := range []string{"INTENSITY", "RED", "GREEN", "BLUE", "WHITE"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("color-fx-%d", i), FixtureID: "color-fx", Offset: i, Name: channelType, Type: channelType, FadeBehavior: "FADE"})
+	}
+
+	var setResp struct {
+		SetFixtureColor []struct {
+			FixtureID string `json:"fixtureId"`
+			Channels  []struct {
+				Offset int `json:"offset"`
+				Value  int `json:"value"`
+			} `json:"channels"`
+		} `json:"setFixtureColor"`
+	}
+	err := c.Post(`mutation { setFixtureColor(fixtureIds: ["color-fx"], color: {hex: "#FF8080"}) { fixtureId channels { offset value } } }`, &setResp)
+	if err != nil {
+		t.Fatalf("setFixtureColor mutation failed: %v", err)
+	}
+	if len(setResp.SetFixtureColor) != 1 || len(setResp.SetFixtureColor[0].Channels) != 4 {
+		t.Fatalf("Expected the four color channels, got %+v", setResp.SetFixtureColor)
+	}
+	// White takes the share of the color red, green, and blue have in common
+	sink.ExpectChannels(t, 1, map[int]byte{10: 0, 11: 127, 12: 0, 13: 0, 14: 128}, 2*time.Second)
+
+	if err := c.Post(`mutation { setFixtureColor(fixtureIds: ["color-fx"], color: {hex: "#FF0000", kelvin: 3200}) { fixtureId } }`, &setResp); err == nil {
+		t.Error("Expected error for a color with both hex and kelvin")
+	}
+
+	// A color fills the channels the fixture value does not set
+	var createResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($input: CreateSceneInput!) { createScene(input: $input) { id } }`, &createResp, client.Var("input", map[string]interface{}{
+		"name": "Blue Wash", "projectId": project.ID,
+		"fixtureValues": []map[string]interface{}{{
+			"fixtureId": "color-fx",
+			"channels":  []map[string]interface{}{{"offset": 0, "value": 255}, {"offset": 3, "value": 10}},
+			"color":     map[string]interface{}{"hsv": map[string]interface{}{"h": 240, "s": 1, "v": 1}},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	var fv models.FixtureValue
+	resolver.db.First(&fv, "scene_id = ? AND fixture_id = ?", createResp.CreateScene.ID, "color-fx")
+	var channels []models.ChannelValue
+	if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
+		t.Fatalf("Failed to parse scene channels: %v", err)
+	}
+	want := map[int]int{0: 255, 1: 0, 2: 0, 3: 10, 4: 0}
+	if len(channels) != len(want) {
+		t.Fatalf("Scene channels = %+v, want %v", channels, want)
+	}
+	for _, ch := range channels {
+		if want[ch.Offset] != ch.Value {
+			t.Errorf("Scene channel %d = %d, want %d", ch.Offset, ch.Value, want[ch.Offset])
+		}
+	}
+}
+
+func TestFixtureGroup_SceneFollowsMembers(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-group", Name: "Group Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-group", Manufacturer: "Test", Model: "Dimmer RGB", Type: "LED_PAR"})
+	for _, f := range []struct {
+		id    string
+		start int
+		types []string
+	}{
+		{"group-a", 20, []string{"INTENSITY", "RED"}},
+		{"group-b", 30, []string{"RED", "INTENSITY"}},
+		{"group-c", 40, []string{"INTENSITY", "RED"}},
+	} {
+		resolver.db.Create(&models.FixtureInstance{ID: f.id, Name: f.id, ProjectID: project.ID, DefinitionID: "test-def-group", Universe: 1, StartChannel: f.start})
+		for i, channelType := range f.types {
+			resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("%s-%d", f.id, i), FixtureID: f.id, Offset: i, Name: channelType, Type: channelType, FadeBehavior: "FADE"})
+		}
+	}
+
+	var groupResp struct {
+		CreateFixtureGroup struct {
+			ID         string   `json:"id"`
+			FixtureIds []string `json:"fixtureIds"`
+		} `json:"createFixtureGroup"`
+	}
+	err := c.Post(`mutation { createFixtureGroup(input: {projectId: "test-project-group", name: "Wash", fixtureIds: ["group-a", "group-c"]}) { id fixtureIds } }`, &groupResp)
+	if err != nil {
+		t.Fatalf("createFixtureGroup mutation failed: %v", err)
+	}
+	groupID := groupResp.CreateFixtureGroup.ID
+
+	// group-c has a value of its own, which the group value does not change
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($input: CreateSceneInput!) { createScene(input: $input) { id } }`, &sceneResp, client.Var("input", map[string]interface{}{
+		"name": "Red Wash", "projectId": project.ID,
+		"fixtureValues": []map[string]interface{}{{
+			"fixtureId": "group-c",
+			"channels":  []map[string]interface{}{{"offset": 0, "value": 10}},
+		}},
+		"groupValues": []map[string]interface{}{{
+			"groupId":  groupID,
+			"channels": []map[string]interface{}{{"type": "INTENSITY", "value": 200}, {"type": "RED", "value": 50}},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	sceneID := sceneResp.CreateScene.ID
+
+	var liveResp struct {
+		SetSceneLive bool `json:"setSceneLive"`
+	}
+	if err := c.Post(`mutation($sceneId: ID!) { setSceneLive(sceneId: $sceneId) }`, &liveResp, client.Var("sceneId", sceneID)); err != nil {
+		t.Fatalf("setSceneLive mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{20: 200, 21: 50, 40: 10, 41: 0}, 2*time.Second)
+
+	// A fixture added to the group joins the live scene on its channels by type
+	var updateResp struct {
+		UpdateFixtureGroup struct {
+			ID string `json:"id"`
+		} `json:"updateFixtureGroup"`
+	}
+	err = c.Post(`mutation($id: ID!) { updateFixtureGroup(id: $id, input: {fixtureIds: ["group-a", "group-b", "group-c"]}) { id } }`, &updateResp, client.Var("id", groupID))
+	if err != nil {
+		t.Fatalf("updateFixtureGroup mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{30: 50, 31: 200}, 2*time.Second)
+
+	var queryResp struct {
+		Scene struct {
+			FixtureValues []struct {
+				Fixture struct {
+					ID string `json:"id"`
+				} `json:"fixture"`
+				GroupID *string `json:"groupId"`
+			} `json:"fixtureValues"`
+			GroupValues []struct {
+				Group struct {
+					Name string `json:"name"`
+				} `json:"group"`
+				Channels []struct {
+					Type  string `json:"type"`
+					Value int    `json:"value"`
+				} `json:"channels"`
+			} `json:"groupValues"`
+		} `json:"scene"`
+	}
+	err = c.Post(`query($id: ID!) { scene(id: $id) { fixtureValues { fixture { id } groupId } groupValues { group { name } channels { type value } } } }`, &queryResp, client.Var("id", sceneID))
+	if err != nil {
+		t.Fatalf("scene query failed: %v", err)
+	}
+	following := make(map[string]bool)
+	for _, fv := range queryResp.Scene.FixtureValues {
+		following[fv.Fixture.ID] = fv.GroupID != nil && *fv.GroupID == groupID
+	}
+	if len(following) != 3 || !following["group-a"] || !following["group-b"] || following["group-c"] {
+		t.Errorf("Fixture values following the group = %v, want group-a and group-b", following)
+	}
+	if len(queryResp.Scene.GroupValues) != 1 || queryResp.Scene.GroupValues[0].Group.Name != "Wash" || len(queryResp.Scene.GroupValues[0].Channels) != 2 {
+		t.Errorf("Group values = %+v, want the Wash value with two channels", queryResp.Scene.GroupValues)
+	}
+
+	// Deleting a member drops it from the group and the scene
+	var deleteResp struct {
+		DeleteFixtureInstance bool `json:"deleteFixtureInstance"`
+	}
+	if err := c.Post(`mutation { deleteFixtureInstance(id: "group-a") }`, &deleteResp); err != nil {
+		t.Fatalf("deleteFixtureInstance mutation failed: %v", err)
+	}
+	var group models.FixtureGroup
+	resolver.db.First(&group, "id = ?", groupID)
+	if group.FixtureIDs != `["group-b","group-c"]` {
+		t.Errorf("Group fixtures = %s, want group-b and group-c", group.FixtureIDs)
+	}
+	var count int64
+	resolver.db.Model(&models.FixtureValue{}).Where("scene_id = ? AND fixture_id = ?", sceneID, "group-a").Count(&count)
+	if count != 0 {
+		t.Errorf("Deleted fixture still has %d values in the scene", count)
+	}
+
+	// Deleting the group leaves only the scene's own values
+	var deleteGroupResp struct {
+		DeleteFixtureGroup bool `json:"deleteFixtureGroup"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deleteFixtureGroup(id: $id) }`, &deleteGroupResp, client.Var("id", groupID)); err != nil {
+		t.Fatalf("deleteFixtureGroup mutation failed: %v", err)
+	}
+	resolver.db.Model(&models.FixtureValue{}).Where("scene_id = ?", sceneID).Count(&count)
+	if count != 1 {
+		t.Errorf("Scene has %d fixture values after deleting the group, want 1", count)
+	}
+}
+
+func TestPalette_UpdatePropagatesToScenes(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-palette", Name: "Palette Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-palette", Manufacturer: "Test", Model: "Mover", Type: "MOVING_HEAD"})
+	resolver.db.Create(&models.FixtureInstance{ID: "palette-mover", Name: "Mover", ProjectID: project.ID, DefinitionID: "test-def-palette", Universe: 1, StartChannel: 50})
+	for i, channelType := range []string{"INTENSITY", "PAN", "TILT"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("palette-mover-%d", i), FixtureID: "palette-mover", Offset: i, Name: channelType, Type: channelType, FadeBehavior: "FADE"})
+	}
+
+	// A palette only takes channel types of its kind
+	var paletteResp struct {
+		CreatePalette struct {
+			ID string `json:"id"`
+		} `json:"createPalette"`
+	}
+	err := c.Post(`mutation { createPalette(input: {projectId: "test-project-palette", name: "Bad", kind: POSITION, channels: [{type: RED, value: 1}]}) { id } }`, &paletteResp)
+	if err == nil {
+		t.Error("Expected an error for a color channel in a position palette")
+	}
+	err = c.Post(`mutation { createPalette(input: {projectId: "test-project-palette", name: "Down Center", kind: POSITION, channels: [{type: PAN, value: 100}, {type: TILT, value: 60}]}) { id } }`, &paletteResp)
+	if err != nil {
+		t.Fatalf("createPalette mutation failed: %v", err)
+	}
+	paletteID := paletteResp.CreatePalette.ID
+
+	// The palette sets pan and tilt over the fixture's own values
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($input: CreateSceneInput!) { createScene(input: $input) { id } }`, &sceneResp, client.Var("input", map[string]interface{}{
+		"name": "Focus", "projectId": project.ID,
+		"fixtureValues": []map[string]interface{}{{
+			"fixtureId":  "palette-mover",
+			"channels":   []map[string]interface{}{{"offset": 0, "value": 255}, {"offset": 1, "value": 5}},
+			"paletteIds": []string{paletteID},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	sceneID := sceneResp.CreateScene.ID
+
+	var liveResp struct {
+		SetSceneLive bool `json:"setSceneLive"`
+	}
+	if err := c.Post(`mutation($sceneId: ID!) { setSceneLive(sceneId: $sceneId) }`, &liveResp, client.Var("sceneId", sceneID)); err != nil {
+		t.Fatalf("setSceneLive mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{50: 255, 51: 100, 52: 60}, 2*time.Second)
+
+	// Updating the palette updates the live scene
+	var updateResp struct {
+		UpdatePalette struct {
+			ID string `json:"id"`
+		} `json:"updatePalette"`
+	}
+	err = c.Post(`mutation($id: ID!) { updatePalette(id: $id, input: {channels: [{type: PAN, value: 200}, {type: TILT, value: 30}]}) { id } }`, &updateResp, client.Var("id", paletteID))
+	if err != nil {
+		t.Fatalf("updatePalette mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{50: 255, 51: 200, 52: 30}, 2*time.Second)
+
+	var queryResp struct {
+		Scene struct {
+			FixtureValues []struct {
+				PaletteIds []string `json:"paletteIds"`
+			} `json:"fixtureValues"`
+		} `json:"scene"`
+	}
+	err = c.Post(`query($id: ID!) { scene(id: $id) { fixtureValues { paletteIds } } }`, &queryResp, client.Var("id", sceneID))
+	if err != nil {
+		t.Fatalf("scene query failed: %v", err)
+	}
+	if len(queryResp.Scene.FixtureValues) != 1 || len(queryResp.Scene.FixtureValues[0].PaletteIds) != 1 || queryResp.Scene.FixtureValues[0].PaletteIds[0] != paletteID {
+		t.Errorf("Fixture values = %+v, want one referencing the palette", queryResp.Scene.FixtureValues)
+	}
+
+	// Deleting the palette keeps its values in the scene
+	var deleteResp struct {
+		DeletePalette bool `json:"deletePalette"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deletePalette(id: $id) }`, &deleteResp, client.Var("id", paletteID)); err != nil {
+		t.Fatalf("deletePalette mutation failed: %v", err)
+	}
+	var value models.FixtureValue
+	resolver.db.First(&value, "scene_id = ?", sceneID)
+	if value.PaletteIDs != "[]" || value.Channels != `[{"offset":0,"value":255},{"offset":1,"value":200},{"offset":2,"value":30}]` {
+		t.Errorf("Fixture value after deleting the palette = %s with palettes %s", value.Channels, value.PaletteIDs)
+	}
+}
+
+func TestUndoRedo_SceneAndFixtureEdits(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-undo", Name: "Undo Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-undo", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "undo-dimmer", Name: "Dimmer", ProjectID: project.ID, DefinitionID: "test-def-undo", Universe: 1, StartChannel: 60})
+	resolver.db.Create(&models.InstanceChannel{ID: "undo-dimmer-0", FixtureID: "undo-dimmer", Offset: 0, Name: "Intensity", Type: "INTENSITY", FadeBehavior: "FADE"})
+
+	var sceneResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err := c.Post(`mutation { createScene(input: {name: "Look", projectId: "test-project-undo", fixtureValues: [{fixtureId: "undo-dimmer", channels: [{offset: 0, value: 100}]}]}) { id } }`, &sceneResp)
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	sceneID := sceneResp.CreateScene.ID
+
+	var liveResp struct {
+		SetSceneLive bool `json:"setSceneLive"`
+	}
+	if err := c.Post(`mutation($sceneId: ID!) { setSceneLive(sceneId: $sceneId) }`, &liveResp, client.Var("sceneId", sceneID)); err != nil {
+		t.Fatalf("setSceneLive mutation failed: %v", err)
+	}
+	var updateResp struct {
+		UpdateScene struct {
+			ID string `json:"id"`
+		} `json:"updateScene"`
+	}
+	err = c.Post(`mutation($id: ID!) { updateScene(id: $id, input: {fixtureValues: [{fixtureId: "undo-dimmer", channels: [{offset: 0, value: 220}]}]}) { id } }`, &updateResp, client.Var("id", sceneID))
+	if err != nil {
+		t.Fatalf("updateScene mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{60: 220}, 2*time.Second)
+
+	type undoStatus struct {
+		CanUndo         bool    `json:"canUndo"`
+		CanRedo         bool    `json:"canRedo"`
+		UndoDescription *string `json:"undoDescription"`
+		UndoCount       int     `json:"undoCount"`
+		RedoCount       int     `json:"redoCount"`
+	}
+	var stackResp struct {
+		UndoStack undoStatus `json:"undoStack"`
+	}
+	if err := c.Post(`query { undoStack(projectId: "test-project-undo") { canUndo canRedo undoDescription undoCount redoCount } }`, &stackResp); err != nil {
+		t.Fatalf("undoStack query failed: %v", err)
+	}
+	if stackResp.UndoStack.UndoCount != 2 || stackResp.UndoStack.UndoDescription == nil || *stackResp.UndoStack.UndoDescription != "Update scene Look" {
+		t.Errorf("Undo stack = %+v, want the scene update on top of its creation", stackResp.UndoStack)
+	}
+
+	// Undoing the update restores the live scene's previous value
+	var undoResp struct {
+		Undo undoStatus `json:"undo"`
+	}
+	if err := c.Post(`mutation { undo(projectId: "test-project-undo") { canUndo canRedo undoCount redoCount } }`, &undoResp); err != nil {
+		t.Fatalf("undo mutation failed: %v", err)
+	}
+	if !undoResp.Undo.CanRedo || undoResp.Undo.UndoCount != 1 || undoResp.Undo.RedoCount != 1 {
+		t.Errorf("Status after undo = %+v, want one edit each way", undoResp.Undo)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{60: 100}, 2*time.Second)
+
+	var redoResp struct {
+		Redo undoStatus `json:"redo"`
+	}
+	if err := c.Post(`mutation { redo(projectId: "test-project-undo") { canRedo } }`, &redoResp); err != nil {
+		t.Fatalf("redo mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{60: 220}, 2*time.Second)
+
+	// Undoing a fixture deletion brings back the fixture and its scene values
+	var deleteResp struct {
+		DeleteFixtureInstance bool `json:"deleteFixtureInstance"`
+	}
+	if err := c.Post(`mutation { deleteFixtureInstance(id: "undo-dimmer") }`, &deleteResp); err != nil {
+		t.Fatalf("deleteFixtureInstance mutation failed: %v", err)
+	}
+	if redoResp.Redo.CanRedo {
+		t.Error("Redo stack not empty after redoing the only undone edit")
+	}
+	if err := c.Post(`mutation { undo(projectId: "test-project-undo") { canUndo } }`, &undoResp); err != nil {
+		t.Fatalf("undo mutation failed: %v", err)
+	}
+	var fixtureCount, channelCount, valueCount int64
+	resolver.db.Model(&models.FixtureInstance{}).Where("id = ?", "undo-dimmer").Count(&fixtureCount)
+	resolver.db.Model(&models.InstanceChannel{}).Where("fixture_id = ?", "undo-dimmer").Count(&channelCount)
+	resolver.db.Model(&models.FixtureValue{}).Where("fixture_id = ? AND scene_id = ?", "undo-dimmer", sceneID).Count(&valueCount)
+	if fixtureCount != 1 || channelCount != 1 || valueCount != 1 {
+		t.Errorf("After undoing the delete: %d fixtures, %d channels, %d scene values, want 1 each", fixtureCount, channelCount, valueCount)
+	}
+
+	// Undoing the scene's creation removes it
+	for i := 0; i < 2; i++ {
+		if err := c.Post(`mutation { undo(projectId: "test-project-undo") { canUndo } }`, &undoResp); err != nil {
+			t.Fatalf("undo mutation failed: %v", err)
+		}
+	}
+	var sceneCount int64
+	resolver.db.Model(&models.Scene{}).Where("id = ?", sceneID).Count(&sceneCount)
+	if sceneCount != 0 || undoResp.Undo.CanUndo {
+		t.Errorf("Scene count %d and canUndo %v after undoing everything, want 0 and false", sceneCount, undoResp.Undo.CanUndo)
+	}
+	if err := c.Post(`mutation { undo(projectId: "test-project-undo") { canUndo } }`, &undoResp); err == nil {
+		t.Error("Expected an error undoing with an empty history")
+	}
+}
+
+func TestProjectSnapshot_RestoreAfterCueListDelete(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-snapshot", Name: "Snapshot Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "snapshot-scene", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.CueList{ID: "snapshot-cue-list", Name: "Act One", ProjectID: project.ID})
+	resolver.db.Create(&models.Cue{ID: "snapshot-cue", Name: "Opening", CueNumber: 1, CueListID: "snapshot-cue-list", SceneID: "snapshot-scene"})
+
+	var deleteResp struct {
+		DeleteCueList bool `json:"deleteCueList"`
+	}
+	if err := c.Post(`mutation { deleteCueList(id: "snapshot-cue-list") }`, &deleteResp); err != nil {
+		t.Fatalf("deleteCueList mutation failed: %v", err)
+	}
+
+	var listResp struct {
+		ProjectSnapshots []struct {
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
+		} `json:"projectSnapshots"`
+	}
+	if err := c.Post(`query { projectSnapshots(projectId: "test-project-snapshot") { id reason } }`, &listResp); err != nil {
+		t.Fatalf("projectSnapshots query failed: %v", err)
+	}
+	if len(listResp.ProjectSnapshots) != 1 || listResp.ProjectSnapshots[0].Reason != "BEFORE_DELETE" {
+		t.Fatalf("Expected one BEFORE_DELETE snapshot, got %+v", listResp.ProjectSnapshots)
+	}
+
+	var restoreResp struct {
+		RestoreSnapshot struct {
+			ProjectID string `json:"projectId"`
+			Stats     struct {
+				CueListsCreated int `json:"cueListsCreated"`
+				CuesCreated     int `json:"cuesCreated"`
+			} `json:"stats"`
+		} `json:"restoreSnapshot"`
+	}
+	err := c.Post(`mutation($id: ID!) { restoreSnapshot(id: $id, projectName: "Recovered") { projectId stats { cueListsCreated cuesCreated } } }`,
+		&restoreResp, client.Var("id", listResp.ProjectSnapshots[0].ID))
+	if err != nil {
+		t.Fatalf("restoreSnapshot mutation failed: %v", err)
+	}
+	restored := restoreResp.RestoreSnapshot
+	if restored.ProjectID == project.ID || restored.Stats.CueListsCreated != 1 || restored.Stats.CuesCreated != 1 {
+		t.Errorf("Expected a new project with the deleted cue list, got %+v", restored)
+	}
+
+	var manualResp struct {
+		CreateProjectSnapshot struct {
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
+		} `json:"createProjectSnapshot"`
+	}
+	if err := c.Post(`mutation { createProjectSnapshot(projectId: "test-project-snapshot") { id reason } }`, &manualResp); err != nil {
+		t.Fatalf("createProjectSnapshot mutation failed: %v", err)
+	}
+	if manualResp.CreateProjectSnapshot.Reason != "MANUAL" {
+		t.Errorf("Expected MANUAL snapshot, got %+v", manualResp.CreateProjectSnapshot)
+	}
+
+	var deleteSnapshotResp struct {
+		DeleteProjectSnapshot bool `json:"deleteProjectSnapshot"`
+	}
+	err = c.Post(`mutation($id: ID!) { deleteProjectSnapshot(id: $id) }`, &deleteSnapshotResp, client.Var("id", manualResp.CreateProjectSnapshot.ID))
+	if err != nil || !deleteSnapshotResp.DeleteProjectSnapshot {
+		t.Fatalf("deleteProjectSnapshot mutation failed: %v", err)
+	}
+}
+
+func TestValidateAndRepairProject(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-integrity", Name: "Integrity Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "integrity-scene", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "integrity-value", SceneID: "integrity-scene", FixtureID: "deleted-fixture"})
+	resolver.db.Create(&models.CueList{ID: "integrity-cue-list", Name: "Act One", ProjectID: project.ID})
+	resolver.db.Create(&models.Cue{ID: "integrity-cue", Name: "Storm", CueNumber: 1, CueListID: "integrity-cue-list", SceneID: "deleted-scene"})
+
+	var validateResp struct {
+		ValidateProject struct {
+			Valid  bool `json:"valid"`
+			Issues []struct {
+				Type      string `json:"type"`
+				EntityID  string `json:"entityId"`
+				MissingID string `json:"missingId"`
+			} `json:"issues"`
+		} `json:"validateProject"`
+	}
+	if err := c.Post(`query { validateProject(projectId: "test-project-integrity") { valid issues { type entityId missingId } } }`, &validateResp); err != nil {
+		t.Fatalf("validateProject query failed: %v", err)
+	}
+	issues := validateResp.ValidateProject.Issues
+	if validateResp.ValidateProject.Valid || len(issues) != 2 {
+		t.Fatalf("Expected two issues, got %+v", validateResp.ValidateProject)
+	}
+	if issues[0].Type != "CUE_MISSING_SCENE" || issues[0].MissingID != "deleted-scene" || issues[1].Type != "FIXTURE_VALUE_MISSING_FIXTURE" {
+		t.Errorf("Unexpected issues %+v", issues)
+	}
+
+	var repairResp struct {
+		RepairProject struct {
+			Repaired []struct {
+				EntityID string `json:"entityId"`
+			} `json:"repaired"`
+			Remaining []struct {
+				Type string `json:"type"`
+			} `json:"remaining"`
+		} `json:"repairProject"`
+	}
+	if err := c.Post(`mutation { repairProject(projectId: "test-project-integrity", fixes: [CUE_MISSING_SCENE]) { repaired { entityId } remaining { type } } }`, &repairResp); err != nil {
+		t.Fatalf("repairProject mutation failed: %v", err)
+	}
+	result := repairResp.RepairProject
+	if len(result.Repaired) != 1 || result.Repaired[0].EntityID != "integrity-cue" {
+		t.Errorf("Expected the cue repaired, got %+v", result.Repaired)
+	}
+	if len(result.Remaining) != 1 || result.Remaining[0].Type != "FIXTURE_VALUE_MISSING_FIXTURE" {
+		t.Errorf("Expected the fixture value left, got %+v", result.Remaining)
+	}
+	var count int64
+	resolver.db.Model(&models.Cue{}).Where("id = ?", "integrity-cue").Count(&count)
+	if count != 0 {
+		t.Error("Expected the cue deleted")
+	}
+}
+
+func TestBackups_CreateListRestore(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var disabled struct {
+		Backups []struct{ Name string } `json:"backups"`
+	}
+	if err := c.Post(`query { backups { name } }`, &disabled); err == nil {
+		t.Error("Expected backups to fail before they are enabled")
+	}
+
+	resolver.EnableBackups(backup.NewDirStore(t.TempDir()), 0)
+	project := &models.Project{ID: "test-project-backup", Name: "Backup Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "backup-scene", Name: "Look", ProjectID: project.ID})
+
+	var createResp struct {
+		CreateBackup struct {
+			Name string `json:"name"`
+			Size int    `json:"size"`
+		} `json:"createBackup"`
+	}
+	if err := c.Post(`mutation { createBackup { name size } }`, &createResp); err != nil {
+		t.Fatalf("createBackup mutation failed: %v", err)
+	}
+	if createResp.CreateBackup.Name == "" || createResp.CreateBackup.Size == 0 {
+		t.Fatalf("Unexpected backup %+v", createResp.CreateBackup)
+	}
+
+	var listResp struct {
+		Backups []struct {
+			Name      string `json:"name"`
+			CreatedAt string `json:"createdAt"`
+		} `json:"backups"`
+	}
+	if err := c.Post(`query { backups { name createdAt } }`, &listResp); err != nil {
+		t.Fatalf("backups query failed: %v", err)
+	}
+	if len(listResp.Backups) != 1 || listResp.Backups[0].Name != createResp.CreateBackup.Name {
+		t.Fatalf("Expected the backup listed, got %+v", listResp.Backups)
+	}
+
+	var restoreResp struct {
+		RestoreBackup struct {
+			Projects []struct {
+				ProjectID string `json:"projectId"`
+				Stats     struct {
+					ScenesCreated int `json:"scenesCreated"`
+				} `json:"stats"`
+			} `json:"projects"`
+			SettingsRestored int      `json:"settingsRestored"`
+			Warnings         []string `json:"warnings"`
+		} `json:"restoreBackup"`
+	}
+	err := c.Post(`mutation($name: String!) { restoreBackup(name: $name) { projects { projectId stats { scenesCreated } } settingsRestored warnings } }`,
+		&restoreResp, client.Var("name", createResp.CreateBackup.Name))
+	if err != nil {
+		t.Fatalf("restoreBackup mutation failed: %v", err)
+	}
+	restored := restoreResp.RestoreBackup
+	if len(restored.Projects) != 1 || restored.Projects[0].ProjectID == project.ID || restored.Projects[0].Stats.ScenesCreated != 1 {
+		t.Errorf("Expected the project restored as a new one, got %+v", restored)
+	}
+	if restored.SettingsRestored != 0 || len(restored.Warnings) != 0 {
+		t.Errorf("Expected no settings or warnings, got %+v", restored)
+	}
+
+	var deleteResp struct {
+		DeleteBackup bool `json:"deleteBackup"`
+	}
+	err = c.Post(`mutation($name: String!) { deleteBackup(name: $name) }`, &deleteResp, client.Var("name", createResp.CreateBackup.Name))
+	if err != nil || !deleteResp.DeleteBackup {
+		t.Fatalf("deleteBackup mutation failed: %v", err)
+	}
+	if err := c.Post(`mutation { deleteBackup(name: "../lacylights.db") }`, &deleteResp); err == nil {
+		t.Error("Expected a path to be rejected as a backup name")
+	}
+}
+
+func TestAuditLog_RecordsMutationsWithOperator(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-audit", Name: "Audit Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "audit-scene", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.CueList{ID: "audit-cue-list", Name: "Main", ProjectID: project.ID})
+	resolver.db.Create(&models.Cue{ID: "audit-cue", Name: "Opening", CueNumber: 1, CueListID: "audit-cue-list", SceneID: "audit-scene", FadeInTime: 3})
+
+	var updateResp struct {
+		UpdateCue struct {
+			ID string `json:"id"`
+		} `json:"updateCue"`
+	}
+	err := c.Post(`mutation { updateCue(id: "audit-cue", input: {name: "Opening", cueNumber: 1, cueListId: "audit-cue-list", sceneId: "audit-scene", fadeInTime: 5, fadeOutTime: 0}) { id } }`, &updateResp,
+		client.AddHeader(audit.OperatorHeader, "Board Op"))
+	if err != nil {
+		t.Fatalf("updateCue mutation failed: %v", err)
+	}
+	var createResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation { createScene(input: {name: "Look 2", projectId: "test-project-audit", fixtureValues: []}) { id } }`, &createResp)
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	var deleteResp struct {
+		DeleteCue bool `json:"deleteCue"`
+	}
+	_ = c.Post(`mutation { deleteCue(id: "missing-cue") }`, &deleteResp)
+
+	type auditEntry struct {
+		Operator   string  `json:"operator"`
+		Operation  string  `json:"operation"`
+		EntityType string  `json:"entityType"`
+		EntityID   *string `json:"entityId"`
+		ProjectID  *string `json:"projectId"`
+		Summary    string  `json:"summary"`
+		Error      *string `json:"error"`
+	}
+	var logResp struct {
+		AuditLog struct {
+			Entries    []auditEntry `json:"entries"`
+			Pagination struct {
+				Total int `json:"total"`
+			} `json:"pagination"`
+		} `json:"auditLog"`
+	}
+	query := `query($filter: AuditLogFilterInput) {
+		auditLog(filter: $filter) {
+			entries { operator operation entityType entityId projectId summary error }
+			pagination { total }
+		}
+	}`
+	err = c.Post(query, &logResp, client.Var("filter", map[string]interface{}{"projectId": project.ID, "entityType": "CUE"}))
+	if err != nil {
+		t.Fatalf("auditLog query failed: %v", err)
+	}
+	if logResp.AuditLog.Pagination.Total != 1 {
+		t.Fatalf("Expected 1 cue entry in the project, got %+v", logResp.AuditLog)
+	}
+	entry := logResp.AuditLog.Entries[0]
+	if entry.Operator != "Board Op" || entry.Operation != "updateCue" || entry.EntityID == nil || *entry.EntityID != "audit-cue" {
+		t.Errorf("Unexpected cue entry: %+v", entry)
+	}
+	if entry.Summary != "fadeInTime: 3 → 5" {
+		t.Errorf("Expected fade time change in summary, got %q", entry.Summary)
+	}
+
+	err = c.Post(query, &logResp, client.Var("filter", map[string]interface{}{"entityType": "SCENE"}))
+	if err != nil {
+		t.Fatalf("auditLog query failed: %v", err)
+	}
+	if len(logResp.AuditLog.Entries) != 1 {
+		t.Fatalf("Expected 1 scene entry, got %+v", logResp.AuditLog.Entries)
+	}
+	created := logResp.AuditLog.Entries[0]
+	if created.Summary != "created" || created.EntityID == nil || *created.EntityID != createResp.CreateScene.ID ||
+		created.ProjectID == nil || *created.ProjectID != project.ID {
+		t.Errorf("Unexpected scene entry: %+v", created)
+	}
+
+	err = c.Post(query, &logResp, client.Var("filter", map[string]interface{}{"entityId": "missing-cue"}))
+	if err != nil {
+		t.Fatalf("auditLog query failed: %v", err)
+	}
+	if len(logResp.AuditLog.Entries) != 1 || logResp.AuditLog.Entries[0].Error == nil {
+		t.Errorf("Expected failed delete to be recorded with its error, got %+v", logResp.AuditLog.Entries)
+	}
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	err = c.Post(query, &logResp, client.Var("filter", map[string]interface{}{"since": future}))
+	if err != nil {
+		t.Fatalf("auditLog query failed: %v", err)
+	}
+	if logResp.AuditLog.Pagination.Total != 0 {
+		t.Errorf("Expected no entries after %s, got %d", future, logResp.AuditLog.Pagination.Total)
+	}
+	if err := c.Post(query, &logResp, client.Var("filter", map[string]interface{}{"since": "yesterday"})); err == nil {
+		t.Error("Expected error for a malformed time filter")
+	}
+}
+
+func TestAuth_EnforcesProjectRoles(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	resolver.AuthService.Enable([]byte("test-secret"), time.Hour)
+	for _, u := range []struct{ email, role string }{
+		{"admin@example.com", auth.RoleAdmin},
+		{"editor@example.com", auth.RoleUser},
+		{"viewer@example.com", auth.RoleUser},
+	} {
+		if _, err := resolver.AuthService.CreateUser(ctx, u.email, nil, "stage-password", u.role); err != nil {
+			t.Fatalf("CreateUser(%s) failed: %v", u.email, err)
+		}
+	}
+	editor, _ := resolver.UserRepo.FindByEmail(ctx, "editor@example.com")
+	viewer, _ := resolver.UserRepo.FindByEmail(ctx, "viewer@example.com")
+
+	project := &models.Project{ID: "test-project-auth", Name: "Auth Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.CueList{ID: "auth-cue-list", Name: "Main", ProjectID: project.ID})
+	if _, err := resolver.UserRepo.SetMembership(ctx, editor.ID, project.ID, auth.RoleEditor); err != nil {
+		t.Fatalf("SetMembership failed: %v", err)
+	}
+	if _, err := resolver.UserRepo.SetMembership(ctx, viewer.ID, project.ID, auth.RoleViewer); err != nil {
+		t.Fatalf("SetMembership failed: %v", err)
+	}
+
+	login := func(email string) client.Option {
+		var resp struct {
+			Login struct {
+				Token string `json:"token"`
+			} `json:"login"`
+		}
+		err := c.Post(`mutation($email: String!) { login(email: $email, password: "stage-password") { token } }`, &resp,
+			client.Var("email", email))
+		if err != nil {
+			t.Fatalf("login as %s failed: %v", email, err)
+		}
+		return client.AddHeader("Authorization", "Bearer "+resp.Login.Token)
+	}
+	asAdmin, asEditor, asViewer := login("admin@example.com"), login("Editor@Example.com"), login("viewer@example.com")
+
+	var resp map[string]interface{}
+	rename := `mutation { updateCueList(id: "auth-cue-list", input: {name: "Renamed", projectId: "test-project-auth"}) { id } }`
+	if err := c.Post(rename, &resp); err == nil || !strings.Contains(err.Error(), "authentication required") {
+		t.Errorf("Expected anonymous edit to need authentication, got %v", err)
+	}
+	if err := c.Post(rename, &resp, asViewer); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected viewer edit to be denied, got %v", err)
+	}
+	if err := c.Post(rename, &resp, asEditor); err != nil {
+		t.Errorf("Expected editor edit to succeed, got %v", err)
+	}
+	if err := c.Post(`mutation { deleteProject(id: "test-project-auth") }`, &resp, asEditor); err == nil {
+		t.Error("Expected editor to be unable to delete the project")
+	}
+	if err := c.Post(`mutation { login(email: "editor@example.com", password: "wrong-password") { token } }`, &resp); err == nil {
+		t.Error("Expected login with a wrong password to fail")
+	}
+	if err := c.Post(rename, &resp, client.AddHeader("Authorization", "Bearer forged.token.value")); err == nil {
+		t.Error("Expected a forged token to be rejected")
+	}
+
+	// Creating a project makes the editor its owner
+	var createResp struct {
+		CreateProject struct {
+			ID string `json:"id"`
+		} `json:"createProject"`
+	}
+	if err := c.Post(`mutation { createProject(input: {name: "Editor's Show"}) { id } }`, &createResp, asEditor); err != nil {
+		t.Fatalf("createProject failed: %v", err)
+	}
+	member, err := resolver.UserRepo.FindMembership(ctx, editor.ID, createResp.CreateProject.ID)
+	if err != nil || member == nil || member.Role != auth.RoleOwner {
+		t.Errorf("Expected editor to own the new project, got %+v, %v", member, err)
+	}
+
+	var meResp struct {
+		Me struct {
+			Email string `json:"email"`
+		} `json:"me"`
+	}
+	if err := c.Post(`query { me { email } }`, &meResp, asEditor); err != nil || meResp.Me.Email != "editor@example.com" {
+		t.Errorf("me = %+v, %v; want editor", meResp.Me, err)
+	}
+
+	// Edits are attributed to the signed-in user
+	entries, _, err := resolver.AuditRepo.Find(ctx, repositories.AuditLogFilter{ProjectID: &project.ID}, 0, 10)
+	if err != nil {
+		t.Fatalf("Find audit entries failed: %v", err)
+	}
+	var attributed bool
+	for _, entry := range entries {
+		if entry.Operation == "updateCueList" && entry.Error == nil && entry.Operator == "editor@example.com" {
+			attributed = true
+		}
+	}
+	if !attributed {
+		t.Errorf("Expected the editor's rename in the audit log, got %+v", entries)
+	}
+
+	// Only owners manage members, and the last owner cannot leave
+	addViewer := fmt.Sprintf(`mutation { setProjectMember(projectId: "%s", userId: "%s", role: EDITOR) { role } }`,
+		createResp.CreateProject.ID, viewer.ID)
+	if err := c.Post(addViewer, &resp, asViewer); err == nil {
+		t.Error("Expected a non-member to be unable to add members")
+	}
+	if err := c.Post(addViewer, &resp, asEditor); err != nil {
+		t.Errorf("Expected the owner to add a member, got %v", err)
+	}
+	demote := fmt.Sprintf(`mutation { setProjectMember(projectId: "%s", userId: "%s", role: VIEWER) { role } }`,
+		createResp.CreateProject.ID, editor.ID)
+	if err := c.Post(demote, &resp, asEditor); err == nil || !strings.Contains(err.Error(), "at least one owner") {
+		t.Errorf("Expected the last owner to be kept, got %v", err)
+	}
+
+	// Server-wide controls are for administrators
+	if err := c.Post(`mutation { blackout { isBlackout } }`, &resp, asEditor); err == nil || !strings.Contains(err.Error(), "limited to administrators") {
+		t.Errorf("Expected an editor to be unable to black out, got %v", err)
+	}
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5) { grandMaster } }`, &resp, asViewer); err == nil || !strings.Contains(err.Error(), "limited to administrators") {
+		t.Errorf("Expected a viewer to be unable to set the master, got %v", err)
+	}
+	if err := c.Post(`mutation { blackout { isBlackout } }`, &resp, asAdmin); err != nil {
+		t.Errorf("Expected admin to black out, got %v", err)
+	}
+
+	// Changing a definition needs a role on the projects whose fixtures use it
+	other := &models.Project{ID: "test-project-other", Name: "Other Project"}
+	resolver.db.Create(other)
+	resolver.db.Create(&models.FixtureDefinition{ID: "auth-def", Manufacturer: "Test", Model: "Shared Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "auth-unused-def", Manufacturer: "Test", Model: "Spare Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "auth-other-fixture", Name: "Par", ProjectID: other.ID, DefinitionID: "auth-def", Universe: 1, StartChannel: 1})
+	if err := c.Post(`mutation { deleteFixtureDefinition(id: "auth-def") }`, &resp, asEditor); err == nil || !strings.Contains(err.Error(), "test-project-other") {
+		t.Errorf("Expected deleting another project's definition to be denied, got %v", err)
+	}
+	if err := c.Post(`mutation { forceDeleteDefinition(id: "auth-def") { deletedFixtureIds } }`, &resp, asEditor); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected force deleting another project's definition to be denied, got %v", err)
+	}
+	if err := c.Post(`mutation { deleteFixtureDefinition(id: "auth-unused-def") }`, &resp, asEditor); err != nil {
+		t.Errorf("Expected an editor to delete an unused definition, got %v", err)
+	}
+
+	// Library templates are exported only to their projects' members
+	serveLibrary := func(user *models.User, projectID string) int {
+		req := httptest.NewRequest(http.MethodGet, librarysync.LibraryPath+"?templateProjectIds="+projectID, nil)
+		if user != nil {
+			token, _, err := resolver.AuthService.IssueToken(user)
+			if err != nil {
+				t.Fatalf("IssueToken failed: %v", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		resolver.AuthService.Middleware(http.HandlerFunc(resolver.LibrarySyncService.ServeLibrary)).ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := serveLibrary(nil, project.ID); code != http.StatusUnauthorized {
+		t.Errorf("Anonymous library status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := serveLibrary(editor, other.ID); code != http.StatusUnauthorized {
+		t.Errorf("Non-member template status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := serveLibrary(viewer, project.ID); code != http.StatusOK {
+		t.Errorf("Member template status = %d, want %d", code, http.StatusOK)
+	}
+
+	if err := c.Post(`mutation { deleteProject(id: "test-project-auth") }`, &resp, asAdmin); err != nil {
+		t.Errorf("Expected admin to delete the project, got %v", err)
+	}
+}
+
+func TestPresence_EditLockBlocksOtherSessions(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-presence", Name: "Presence Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.CueList{ID: "presence-cue-list", Name: "Main", ProjectID: project.ID})
+
+	var resp map[string]interface{}
+	err := c.Post(`mutation { updatePresence(input: {sessionId: "tab-a", projectId: "test-project-presence", entityType: CUE_LIST, entityId: "presence-cue-list", activity: EDITING}) { activity } }`,
+		&resp, client.AddHeader(audit.OperatorHeader, "Programmer"))
+	if err != nil {
+		t.Fatalf("updatePresence failed: %v", err)
+	}
+
+	var presenceResp struct {
+		ProjectPresence []struct {
+			SessionID string `json:"sessionId"`
+			Operator  string `json:"operator"`
+			Activity  string `json:"activity"`
+		} `json:"projectPresence"`
+	}
+	if err := c.Post(`query { projectPresence(projectId: "test-project-presence") { sessionId operator activity } }`, &presenceResp); err != nil {
+		t.Fatalf("projectPresence query failed: %v", err)
+	}
+	if len(presenceResp.ProjectPresence) != 1 || presenceResp.ProjectPresence[0].Operator != "Programmer" ||
+		presenceResp.ProjectPresence[0].Activity != "EDITING" {
+		t.Errorf("Unexpected presence: %+v", presenceResp.ProjectPresence)
+	}
+
+	rename := `mutation { updateCueList(id: "presence-cue-list", input: {name: "Renamed", projectId: "test-project-presence"}) { id } }`
+	err = c.Post(rename, &resp, client.AddHeader(presence.SessionHeader, "tab-b"))
+	if err == nil || !strings.Contains(err.Error(), "being edited by Programmer") {
+		t.Errorf("Expected another session's edit to be blocked, got %v", err)
+	}
+	if err := c.Post(rename, &resp, client.AddHeader(presence.SessionHeader, "tab-a")); err != nil {
+		t.Errorf("Expected the lock holder's edit to succeed, got %v", err)
+	}
+	if err := c.Post(`mutation { nextCue(cueListId: "presence-cue-list") }`, &resp); err != nil && strings.Contains(err.Error(), "being edited") {
+		t.Errorf("Expected playback to ignore edit locks, got %v", err)
+	}
+
+	if err := c.Post(`mutation { leavePresence(sessionId: "tab-a") }`, &resp); err != nil {
+		t.Fatalf("leavePresence failed: %v", err)
+	}
+	if err := c.Post(rename, &resp, client.AddHeader(presence.SessionHeader, "tab-b")); err != nil {
+		t.Errorf("Expected the edit to succeed once the lock was released, got %v", err)
+	}
+}
+
+// TestSceneBoardStateChanged_MirrorsBoard tests that the live state
+// subscription follows activations, holds, and the grand master.
+func TestSceneBoardStateChanged_MirrorsBoard(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "test-project-live", Name: "Live Board"})
+	resolver.db.Create(&models.SceneBoard{ID: "live-board", Name: "Board", ProjectID: "test-project-live", HoldRampTime: 60})
+	for i, id := range []string{"live-scene-1", "live-scene-2"} {
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: "test-project-live"})
+		resolver.db.Create(&models.SceneBoardButton{ID: fmt.Sprintf("live-btn-%d", i+1), SceneBoardID: "live-board", SceneID: id, LayoutX: i * 200})
+	}
+
+	subs := &subscriptionResolver{resolver}
+	if _, err := subs.SceneBoardStateChanged(context.Background(), "missing-board"); err == nil {
+		t.Error("Expected an error subscribing to a missing board")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	states, err := subs.SceneBoardStateChanged(ctx, "live-board")
+	if err != nil {
+		t.Fatalf("SceneBoardStateChanged() error: %v", err)
+	}
+	waitFor := func(what string, match func(*generated.SceneBoardLiveState) bool) *generated.SceneBoardLiveState {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case state := <-states:
+				if match(state) {
+					return state
+				}
+			case <-timeout:
+				t.Fatalf("Timed out waiting for %s", what)
+			}
+		}
+	}
+
+	initial := waitFor("the initial state", func(*generated.SceneBoardLiveState) bool { return true })
+	if initial.ActiveSceneID != nil || len(initial.Buttons) != 2 || initial.Buttons[0].IsActive || initial.MasterLevel != 1 {
+		t.Errorf("Unexpected initial state: %+v", initial)
+	}
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation { activateSceneFromBoard(sceneBoardId: "live-board", sceneId: "live-scene-1", fadeTimeOverride: 0) }`, &resp); err != nil {
+		t.Fatalf("activateSceneFromBoard failed: %v", err)
+	}
+	activated := waitFor("the activation", func(s *generated.SceneBoardLiveState) bool { return s.ActiveSceneID != nil })
+	if *activated.ActiveSceneID != "live-scene-1" || !activated.Buttons[0].IsActive || activated.Buttons[0].Level != 1 {
+		t.Errorf("Unexpected state after activation: %+v", activated.Buttons[0])
+	}
+
+	if err := c.Post(`mutation { pressSceneBoardButton(buttonId: "live-btn-2") { isHeld } }`, &resp); err != nil {
+		t.Fatalf("pressSceneBoardButton failed: %v", err)
+	}
+	held := waitFor("the hold", func(s *generated.SceneBoardLiveState) bool { return s.Buttons[1].IsHeld })
+	if !held.Buttons[1].IsActive || held.Buttons[1].FadeProgress == nil {
+		t.Errorf("Expected the held button to be active and ramping: %+v", held.Buttons[1])
+	}
+	// The ramp keeps the state coming while it runs
+	waitFor("a fade progress update", func(s *generated.SceneBoardLiveState) bool {
+		return s.Buttons[1].FadeProgress != nil && s.UpdatedAt != held.UpdatedAt
+	})
+
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5) { grandMaster } }`, &resp); err != nil {
+		t.Fatalf("setMasterLevel failed: %v", err)
+	}
+	waitFor("the master level", func(s *generated.SceneBoardLiveState) bool { return s.MasterLevel == 0.5 })
+
+	if err := c.Post(`mutation { fadeToBlack(fadeOutTime: 0) }`, &resp); err != nil {
+		t.Fatalf("fadeToBlack failed: %v", err)
+	}
+	waitFor("the board to clear", func(s *generated.SceneBoardLiveState) bool { return s.ActiveSceneID == nil })
+}
+
+// TestSceneBoardFlash_LayersAboveContent tests that a flash button's scene
+// sits above activated scenes while pressed and reveals them on release.
+func TestSceneBoardFlash_LayersAboveContent(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "test-project-flash", Name: "Flash Project"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-flash", Manufacturer: "Test", Model: "Par", Type: "LED_PAR"})
+	resolver.db.Create(&models.FixtureInstance{ID: "flash-fx", Name: "Par", ProjectID: "test-project-flash", DefinitionID: "test-def-flash", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "flash-fx-0", FixtureID: "flash-fx", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	resolver.db.Create(&models.InstanceChannel{ID: "flash-fx-1", FixtureID: "flash-fx", Offset: 1, Name: "Red", Type: "RED", FadeBehavior: "FADE"})
+	for id, channels := range map[string]string{
+		"flash-base":  `[{"offset":0,"value":100},{"offset":1,"value":50}]`,
+		"flash-other": `[{"offset":0,"value":60},{"offset":1,"value":10}]`,
+		"flash-bump":  `[{"offset":0,"value":200},{"offset":1,"value":120}]`,
+	} {
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: "test-project-flash"})
+		resolver.db.Create(&models.FixtureValue{ID: id + "-fv", SceneID: id, FixtureID: "flash-fx", Channels: channels})
+	}
+	resolver.db.Create(&models.SceneBoard{ID: "flash-board", Name: "Board", ProjectID: "test-project-flash"})
+
+	var buttonResp struct {
+		AddSceneToBoard struct {
+			ID               string   `json:"id"`
+			FlashMode        bool     `json:"flashMode"`
+			FlashLevel       *float64 `json:"flashLevel"`
+			FlashReleaseTime *float64 `json:"flashReleaseTime"`
+		} `json:"addSceneToBoard"`
+	}
+	add := `mutation($input: CreateSceneBoardButtonInput!) { addSceneToBoard(input: $input) { id flashMode flashLevel flashReleaseTime } }`
+	err := c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "flash-board", "sceneId": "flash-bump", "layoutX": 0, "layoutY": 0, "flashMode": true, "flashLevel": 1.5,
+	}))
+	if err == nil {
+		t.Error("Expected an error for a flash level above 1")
+	}
+	err = c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "flash-board", "sceneId": "flash-bump", "layoutX": 0, "layoutY": 0, "flashMode": true,
+	}))
+	if err != nil {
+		t.Fatalf("addSceneToBoard mutation failed: %v", err)
+	}
+	flashButton := buttonResp.AddSceneToBoard
+	if !flashButton.FlashMode || flashButton.FlashLevel != nil || flashButton.FlashReleaseTime != nil {
+		t.Errorf("Unexpected flash button: %+v", flashButton)
+	}
+	if err := c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "flash-board", "sceneId": "flash-base", "layoutX": 200, "layoutY": 0,
+	})); err != nil {
+		t.Fatalf("addSceneToBoard mutation failed: %v", err)
+	}
+	plainButton := buttonResp.AddSceneToBoard
+
+	var resp map[string]interface{}
+	activate := `mutation($sceneId: ID!) { activateSceneFromBoard(sceneBoardId: "flash-board", sceneId: $sceneId, fadeTimeOverride: 0) }`
+	if err := c.Post(activate, &resp, client.Var("sceneId", "flash-base")); err != nil {
+		t.Fatalf("activateSceneFromBoard failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100, 2: 50}, 2*time.Second)
+
+	type flashState struct {
+		Level       float64 `json:"level"`
+		IsFlashing  bool    `json:"isFlashing"`
+		IsReleasing bool    `json:"isReleasing"`
+	}
+	var startResp struct {
+		FlashSceneStart flashState `json:"flashSceneStart"`
+	}
+	start := `mutation($buttonId: ID!) { flashSceneStart(buttonId: $buttonId) { level isFlashing isReleasing } }`
+	if err := c.Post(start, &startResp, client.Var("buttonId", plainButton.ID)); err == nil {
+		t.Error("Expected an error flashing a button that is not in flash mode")
+	}
+	if err := c.Post(start, &startResp, client.Var("buttonId", flashButton.ID)); err != nil {
+		t.Fatalf("flashSceneStart failed: %v", err)
+	}
+	if !startResp.FlashSceneStart.IsFlashing || startResp.FlashSceneStart.Level != 1 {
+		t.Errorf("Unexpected flash state: %+v", startResp.FlashSceneStart)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 200, 2: 120}, 2*time.Second)
+
+	// Content changing underneath stays below the flash
+	if err := c.Post(activate, &resp, client.Var("sceneId", "flash-other")); err != nil {
+		t.Fatalf("activateSceneFromBoard failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 200, 2: 120}, 2*time.Second)
+
+	var endResp struct {
+		FlashSceneEnd flashState `json:"flashSceneEnd"`
+	}
+	end := `mutation($buttonId: ID!) { flashSceneEnd(buttonId: $buttonId) { level isFlashing isReleasing } }`
+	if err := c.Post(end, &endResp, client.Var("buttonId", flashButton.ID)); err != nil {
+		t.Fatalf("flashSceneEnd failed: %v", err)
+	}
+	if endResp.FlashSceneEnd.IsFlashing || endResp.FlashSceneEnd.IsReleasing {
+		t.Errorf("Unexpected state after an instant release: %+v", endResp.FlashSceneEnd)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 60, 2: 10}, 2*time.Second)
+
+	// A release fade takes the flash back down over time
+	if err := c.Post(start, &startResp, client.Var("buttonId", flashButton.ID)); err != nil {
+		t.Fatalf("flashSceneStart failed: %v", err)
+	}
+	err = c.Post(`mutation($buttonId: ID!) { flashSceneEnd(buttonId: $buttonId, releaseTime: 0.3) { level isFlashing isReleasing } }`,
+		&endResp, client.Var("buttonId", flashButton.ID))
+	if err != nil {
+		t.Fatalf("flashSceneEnd failed: %v", err)
+	}
+	if !endResp.FlashSceneEnd.IsReleasing {
+		t.Errorf("Expected the flash to be releasing: %+v", endResp.FlashSceneEnd)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 60, 2: 10}, 2*time.Second)
+	if err := c.Post(end, &endResp, client.Var("buttonId", flashButton.ID)); err == nil {
+		t.Error("Expected an error ending a flash that already ended")
+	}
+}
+
+// TestAudioTriggers tests that beats bump the scenes of audio-triggered
+// scene board buttons and step the effects of their scenes.
+func TestAudioTriggers(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	var configResp struct {
+		UpdateAudioInputConfig struct {
+			Source string `json:"source"`
+			Port   *int   `json:"port"`
+			Bands  []struct {
+				Name      string  `json:"name"`
+				Threshold float64 `json:"threshold"`
+			} `json:"bands"`
+			IsListening bool `json:"isListening"`
+		} `json:"updateAudioInputConfig"`
+	}
+	update := `mutation($input: AudioInputConfigInput!) {
+		updateAudioInputConfig(input: $input) { source port bands { name threshold } isListening }
+	}`
+	for name, input := range map[string]map[string]interface{}{
+		"port out of range":    {"enabled": false, "source": "RTP", "port": 70000},
+		"threshold too low":    {"enabled": false, "source": "UDP", "bands": []map[string]interface{}{{"name": "KICK", "lowHz": 40, "highHz": 150, "threshold": 1}}},
+		"band above nyquist":   {"enabled": false, "source": "UDP", "sampleRate": 8000, "bands": []map[string]interface{}{{"name": "KICK", "lowHz": 40, "highHz": 5000}}},
+		"option as the device": {"enabled": false, "source": "DEVICE", "device": "-v"},
+	} {
+		if err := c.Post(update, &configResp, client.Var("input", input)); err == nil {
+			t.Errorf("%s: expected updateAudioInputConfig to fail", name)
+		}
+	}
+	err := c.Post(update, &configResp, client.Var("input", map[string]interface{}{
+		"enabled": false, "source": "UDP",
+		"bands": []map[string]interface{}{{"name": "KICK", "lowHz": 40, "highHz": 150}, {"name": "HATS", "lowHz": 6000, "highHz": 12000}},
+	}))
+	if err != nil {
+		t.Fatalf("updateAudioInputConfig failed: %v", err)
+	}
+	status := configResp.UpdateAudioInputConfig
+	if status.Source != "UDP" || status.Port == nil || *status.Port != audio.DefaultPort || len(status.Bands) != 2 ||
+		status.Bands[0].Threshold != audio.DefaultThreshold || status.IsListening {
+		t.Errorf("Unexpected audio input status: %+v", status)
+	}
+	setting, _ := resolver.SettingRepo.FindByKey(context.Background(), audio.SettingKey)
+	if setting == nil || !strings.Contains(setting.Value, `"HATS"`) {
+		t.Errorf("Expected the configuration to be saved, got %+v", setting)
+	}
+
+	resolver.db.Create(&models.Project{ID: "audio-project", Name: "Audio Project"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "audio-def", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "audio-fx", Name: "Dimmer", ProjectID: "audio-project", DefinitionID: "audio-def", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "audio-fx-0", FixtureID: "audio-fx", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	resolver.db.Create(&models.Scene{ID: "audio-bump", Name: "Bump", ProjectID: "audio-project"})
+	resolver.db.Create(&models.FixtureValue{ID: "audio-bump-fv", SceneID: "audio-bump", FixtureID: "audio-fx", Channels: `[{"offset":0,"value":200}]`})
+	resolver.db.Create(&models.Scene{ID: "audio-chase", Name: "Chase", ProjectID: "audio-project"})
+	resolver.db.Create(&models.SceneBoard{ID: "audio-board", Name: "Board", ProjectID: "audio-project"})
+
+	type audioButton struct {
+		ID           string  `json:"id"`
+		AudioTrigger bool    `json:"audioTrigger"`
+		AudioBand    *string `json:"audioBand"`
+		AudioAction  string  `json:"audioAction"`
+	}
+	var buttonResp struct {
+		AddSceneToBoard audioButton `json:"addSceneToBoard"`
+	}
+	add := `mutation($input: CreateSceneBoardButtonInput!) { addSceneToBoard(input: $input) { id audioTrigger audioBand audioAction } }`
+	err = c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "audio-board", "sceneId": "audio-bump", "layoutX": 0, "layoutY": 0, "audioTrigger": true, "audioBand": "SNARE",
+	}))
+	if err == nil {
+		t.Error("Expected an error for a band the audio input does not analyze")
+	}
+	if err := c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "audio-board", "sceneId": "audio-bump", "layoutX": 0, "layoutY": 0, "audioTrigger": true,
+	})); err != nil {
+		t.Fatalf("addSceneToBoard mutation failed: %v", err)
+	}
+	bumpButton := buttonResp.AddSceneToBoard
+	if !bumpButton.AudioTrigger || bumpButton.AudioBand != nil || bumpButton.AudioAction != "BUMP" {
+		t.Errorf("Unexpected bump button: %+v", bumpButton)
+	}
+	if err := c.Post(add, &buttonResp, client.Var("input", map[string]interface{}{
+		"sceneBoardId": "audio-board", "sceneId": "audio-chase", "layoutX": 200, "layoutY": 0,
+	})); err != nil {
+		t.Fatalf("addSceneToBoard mutation failed: %v", err)
+	}
+	var updateResp struct {
+		UpdateSceneBoardButton audioButton `json:"updateSceneBoardButton"`
+	}
+	err = c.Post(`mutation($id: ID!) {
+		updateSceneBoardButton(id: $id, input: { audioTrigger: true, audioBand: "HATS", audioAction: ADVANCE_EFFECTS }) { id audioTrigger audioBand audioAction }
+	}`, &updateResp, client.Var("id", buttonResp.AddSceneToBoard.ID))
+	if err != nil {
+		t.Fatalf("updateSceneBoardButton mutation failed: %v", err)
+	}
+	chaseButton := updateResp.UpdateSceneBoardButton
+	if chaseButton.AudioBand == nil || *chaseButton.AudioBand != "HATS" || chaseButton.AudioAction != "ADVANCE_EFFECTS" {
+		t.Errorf("Unexpected chase button: %+v", chaseButton)
+	}
+
+	// A chase too slow to move by itself, attached to the chase button's scene
+	sceneID := "audio-chase"
+	resolver.db.Create(&models.Effect{ID: "audio-effect", Name: "Chase", ProjectID: "audio-project", SceneID: &sceneID, Type: "CHASE"})
+	params := effects.DefaultParams()
+	params.Rate, params.Size = 0.001, 0.25
+	fixtures := make([]effects.Fixture, 4)
+	for i := range fixtures {
+		fixtures[i] = effects.Fixture{ID: fmt.Sprintf("chase-%d", i), Universe: 1, Intensity: []int{11 + i}}
+	}
+	if err := resolver.EffectService.Start(effects.Effect{ID: "audio-effect", Type: effects.TypeChase, Fixtures: fixtures, Params: params}); err != nil {
+		t.Fatalf("Failed to start the effect: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{11: 255, 12: 0}, 2*time.Second)
+
+	// KICK is the first band, which the bump button listens to
+	resolver.triggerAudioButtons(context.Background(), audio.Beat{Band: "KICK", Level: 0.5})
+	if state := resolver.FlashService.State(bumpButton.ID); state == nil || !state.IsReleasing {
+		t.Errorf("Expected the bump to be fading out, got %+v", state)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for resolver.FlashService.State(bumpButton.ID) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the bump to fade out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 0, 11: 255}, 2*time.Second)
+
+	resolver.triggerAudioButtons(context.Background(), audio.Beat{Band: "HATS", Level: 0.2})
+	sink.ExpectChannels(t, 1, map[int]byte{11: 0, 12: 255}, 2*time.Second)
+	if state := resolver.FlashService.State(bumpButton.ID); state != nil {
+		t.Errorf("Expected HATS not to bump the KICK button, got %+v", state)
+	}
+}
+
+// TestControlSurfaceState tests that control surfaces see scenes go live,
+// scene board buttons light, and the master move, over GraphQL and the REST
+// event stream.
+func TestControlSurfaceState(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "surface-project", Name: "Surface"})
+	resolver.db.Create(&models.SceneBoard{ID: "surface-board", Name: "Board", ProjectID: "surface-project"})
+	for i, id := range []string{"surface-scene-1", "surface-scene-2"} {
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: "surface-project"})
+		resolver.db.Create(&models.SceneBoardButton{ID: fmt.Sprintf("surface-btn-%d", i+1), SceneBoardID: "surface-board", SceneID: id, LayoutX: i * 200})
+	}
+
+	var resp struct {
+		ControlSurfaceState struct {
+			ActiveSceneID *string  `json:"activeSceneId"`
+			LitButtonIds  []string `json:"litButtonIds"`
+			GrandMaster   float64  `json:"grandMaster"`
+		} `json:"controlSurfaceState"`
+	}
+	if err := c.Post(`query { controlSurfaceState { activeSceneId litButtonIds grandMaster } }`, &resp); err != nil {
+		t.Fatalf("controlSurfaceState query failed: %v", err)
+	}
+	if resp.ControlSurfaceState.ActiveSceneID != nil || len(resp.ControlSurfaceState.LitButtonIds) != 0 || resp.ControlSurfaceState.GrandMaster != 1 {
+		t.Errorf("Unexpected initial state: %+v", resp.ControlSurfaceState)
+	}
+
+	// The REST event stream relays the subscription over the SSE transport
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.SSE{})
+	srv.AddTransport(transport.POST{})
+	server := httptest.NewServer(rest.NewHandler(srv))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open the event stream: %v", err)
+	}
+	defer stream.Body.Close()
+	if stream.StatusCode != http.StatusOK {
+		t.Fatalf("Event stream status = %d, want 200", stream.StatusCode)
+	}
+	type surfaceState struct {
+		ActiveSceneID *string  `json:"activeSceneId"`
+		LiveSceneIds  []string `json:"liveSceneIds"`
+		LitButtonIds  []string `json:"litButtonIds"`
+		GrandMaster   float64  `json:"grandMaster"`
+	}
+	events := bufio.NewScanner(stream.Body)
+	waitFor := func(what string, match func(surfaceState) bool) {
+		t.Helper()
+		for events.Scan() {
+			line := events.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var state surfaceState
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &state); err != nil {
+				t.Fatalf("Invalid state event %q: %v", line, err)
+			}
+			if match(state) {
+				return
+			}
+		}
+		t.Fatalf("Event stream ended waiting for %s: %v", what, events.Err())
+	}
+	waitFor("the initial state", func(s surfaceState) bool { return s.ActiveSceneID == nil && s.GrandMaster == 1 })
+
+	var mutationResp map[string]interface{}
+	err = c.Post(`mutation { activateSceneFromBoard(sceneBoardId: "surface-board", sceneId: "surface-scene-2", fadeTimeOverride: 0) }`, &mutationResp)
+	if err != nil {
+		t.Fatalf("activateSceneFromBoard failed: %v", err)
+	}
+	waitFor("the board's scene to light its button", func(s surfaceState) bool {
+		return s.ActiveSceneID != nil && *s.ActiveSceneID == "surface-scene-2" &&
+			len(s.LiveSceneIds) == 1 && len(s.LitButtonIds) == 1 && s.LitButtonIds[0] == "surface-btn-2"
+	})
+
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5) { grandMaster } }`, &mutationResp); err != nil {
+		t.Fatalf("setMasterLevel failed: %v", err)
+	}
+	waitFor("the master level", func(s surfaceState) bool { return s.GrandMaster == 0.5 })
+}
+
+// TestSceneBoardMacro_RunsActions tests that a button's macro activates a
+// scene, waits, goes in a cue list, and sends an OSC message, in order.
+func TestSceneBoardMacro_RunsActions(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	// The macro queries from its own goroutine; a second connection would
+	// open a new, empty in-memory database
+	sqlDB, err := resolver.db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	oscConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Failed to listen for OSC: %v", err)
+	}
+	defer oscConn.Close()
+	oscPort := oscConn.LocalAddr().(*net.UDPAddr).Port
+
+	resolver.db.Create(&models.Project{ID: "test-project-macro", Name: "Macro Project"})
+	resolver.db.Create(&models.Project{ID: "test-project-other", Name: "Other Project"})
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-macro", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "macro-fx", Name: "Dimmer", ProjectID: "test-project-macro", DefinitionID: "test-def-macro", Universe: 1, StartChannel: 1})
+	resolver.db.Create(&models.InstanceChannel{ID: "macro-fx-0", FixtureID: "macro-fx", Offset: 0, Name: "Dimmer", Type: "INTENSITY", FadeBehavior: "FADE"})
+	for id, value := range map[string]int{"macro-preset": 80, "macro-cue": 150} {
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: "test-project-macro"})
+		resolver.db.Create(&models.FixtureValue{ID: id + "-fv", SceneID: id, FixtureID: "macro-fx", Channels: fmt.Sprintf(`[{"offset":0,"value":%d}]`, value)})
+	}
+	resolver.db.Create(&models.Scene{ID: "macro-foreign", Name: "Foreign", ProjectID: "test-project-other"})
+	resolver.db.Create(&models.CueList{ID: "macro-list", Name: "Main", ProjectID: "test-project-macro"})
+	resolver.db.Create(&models.Cue{ID: "macro-cue-1", Name: "One", CueNumber: 1, CueListID: "macro-list", SceneID: "macro-cue"})
+	resolver.db.Create(&models.SceneBoard{ID: "macro-board", Name: "Board", ProjectID: "test-project-macro"})
+	resolver.db.Create(&models.SceneBoardButton{ID: "macro-btn", SceneBoardID: "macro-board", SceneID: "macro-preset"})
+
+	var resp map[string]interface{}
+	if err := c.Post(`mutation { executeMacro(buttonId: "macro-btn") }`, &resp); err == nil {
+		t.Error("Expected an error executing a button without a macro")
+	}
+
+	setMacro := `mutation($actions: [MacroActionInput!]!) {
+		setSceneBoardButtonMacro(buttonId: "macro-btn", actions: $actions) {
+			macro { type sceneId fadeTime waitTime cueListId oscHost oscPort oscAddress oscArguments { type value } }
+		}
+	}`
+	err = c.Post(setMacro, &resp, client.Var("actions", []map[string]interface{}{
+		{"type": "ACTIVATE_SCENE", "sceneId": "macro-foreign"},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "scene not found") {
+		t.Errorf("Expected a scene from another project to be rejected, got %v", err)
+	}
+	err = c.Post(setMacro, &resp, client.Var("actions", []map[string]interface{}{
+		{"type": "WAIT", "waitTime": 0},
+	}))
+	if err == nil {
+		t.Error("Expected a zero wait to be rejected")
+	}
+
+	var macroResp struct {
+		SetSceneBoardButtonMacro struct {
+			Macro []struct {
+				Type         string   `json:"type"`
+				SceneID      *string  `json:"sceneId"`
+				FadeTime     *float64 `json:"fadeTime"`
+				WaitTime     *float64 `json:"waitTime"`
+				CueListID    *string  `json:"cueListId"`
+				OscHost      *string  `json:"oscHost"`
+				OscPort      *int     `json:"oscPort"`
+				OscAddress   *string  `json:"oscAddress"`
+				OscArguments []struct {
+					Type  string `json:"type"`
+					Value string `json:"value"`
+				} `json:"oscArguments"`
+			} `json:"macro"`
+		} `json:"setSceneBoardButtonMacro"`
+	}
+	err = c.Post(setMacro, &macroResp, client.Var("actions", []map[string]interface{}{
+		{"type": "ACTIVATE_SCENE", "sceneId": "macro-preset", "fadeTime": 0},
+		{"type": "WAIT", "waitTime": 1.5},
+		{"type": "CUE_LIST_GO", "cueListId": "macro-list", "fadeTime": 0},
+		{"type": "SEND_OSC", "oscHost": "127.0.0.1", "oscPort": oscPort, "oscAddress": "/lights/go", "oscArguments": []map[string]interface{}{
+			{"type": "INT", "value": "1"},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("setSceneBoardButtonMacro failed: %v", err)
+	}
+	actions := macroResp.SetSceneBoardButtonMacro.Macro
+	if len(actions) != 4 {
+		t.Fatalf("Expected 4 macro actions, got %d", len(actions))
+	}
+	if actions[0].Type != "ACTIVATE_SCENE" || *actions[0].SceneID != "macro-preset" || *actions[0].FadeTime != 0 || actions[0].WaitTime != nil {
+		t.Errorf("Unexpected first action: %+v", actions[0])
+	}
+	if *actions[1].WaitTime != 1.5 || *actions[2].CueListID != "macro-list" || *actions[3].OscAddress != "/lights/go" || *actions[3].OscPort != oscPort {
+		t.Errorf("Unexpected macro: %+v", actions)
+	}
+	if len(actions[3].OscArguments) != 1 || actions[3].OscArguments[0].Type != "INT" {
+		t.Errorf("Unexpected OSC arguments: %+v", actions[3].OscArguments)
+	}
+
+	if err := c.Post(`mutation { executeMacro(buttonId: "macro-btn") }`, &resp); err != nil {
+		t.Fatalf("executeMacro failed: %v", err)
+	}
+	if !resolver.MacroService.IsRunning("macro-btn") {
+		t.Error("Expected the macro to be running")
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 80}, time.Second)
+	sink.ExpectChannels(t, 1, map[int]byte{1: 150}, 3*time.Second)
+
+	buf := make([]byte, 64)
+	_ = oscConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := oscConn.Read(buf)
+	if err != nil {
+		t.Fatalf("No OSC message received: %v", err)
+	}
+	if !strings.HasPrefix(string(buf[:n]), "/lights/go\x00") {
+		t.Errorf("Unexpected OSC message: %q", buf[:n])
+	}
+
+	status := resolver.PlaybackService.GetPlaybackState("macro-list")
+	if status == nil || status.CurrentCueIndex == nil || *status.CurrentCueIndex != 0 {
+		t.Error("Expected the macro to start the cue list")
+	}
+
+	var stopResp struct {
+		StopMacro bool `json:"stopMacro"`
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := c.Post(`mutation { stopMacro(buttonId: "macro-btn") }`, &stopResp); err != nil {
+		t.Fatalf("stopMacro failed: %v", err)
+	}
+	if stopResp.StopMacro {
+		t.Error("Expected no macro running after it finished")
+	}
+}
+
+func TestCueListTransport_PauseResumeRelease(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	follow := 0.3
+	project := &models.Project{ID: "test-project-transport", Name: "Transport Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureDefinition{ID: "test-def-transport", Manufacturer: "Test", Model: "Dimmer", Type: "DIMMER"})
+	resolver.db.Create(&models.FixtureInstance{ID: "tr-fixture", Name: "Fixture", ProjectID: project.ID, DefinitionID: "test-def-transport", Universe: 1, StartChannel: 1})
+	for _, list := range []string{"tr-a", "tr-b"} {
+		resolver.db.Create(&models.CueList{ID: list, Name: list, ProjectID: project.ID})
+	}
+	for i, value := range []int{100, 200, 60} {
+		id := fmt.Sprintf("tr-%d", i+1)
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: project.ID})
+		resolver.db.Create(&models.FixtureValue{ID: id + "-fv", SceneID: id, FixtureID: "tr-fixture", Channels: fmt.Sprintf(`[{"offset":0,"value":%d}]`, value)})
+	}
+	resolver.db.Create(&models.Cue{ID: "tr-a-1", Name: "One", CueListID: "tr-a", SceneID: "tr-1", CueNumber: 1, FollowTime: &follow})
+	resolver.db.Create(&models.Cue{ID: "tr-a-2", Name: "Two", CueListID: "tr-a", SceneID: "tr-2", CueNumber: 2.5})
+	resolver.db.Create(&models.Cue{ID: "tr-b-1", Name: "Other", CueListID: "tr-b", SceneID: "tr-3", CueNumber: 1})
+
+	var goResp struct {
+		GoToCueNumber bool `json:"goToCueNumber"`
+	}
+	if err := c.Post(`mutation { goToCueNumber(cueListId: "tr-a", cueNumber: 1, fadeInTime: 0) }`, &goResp); err != nil {
+		t.Fatalf("goToCueNumber mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 100}, 2*time.Second)
+
+	type status struct {
+		CueListID       string   `json:"cueListId"`
+		CurrentCueIndex *int     `json:"currentCueIndex"`
+		IsPlaying       bool     `json:"isPlaying"`
+		IsPaused        bool     `json:"isPaused"`
+		FollowRemaining *float64 `json:"followRemaining"`
+	}
+	var pauseResp struct {
+		PauseCueList status `json:"pauseCueList"`
+	}
+	if err := c.Post(`mutation { pauseCueList(cueListId: "tr-a") { cueListId currentCueIndex isPlaying isPaused followRemaining } }`, &pauseResp); err != nil {
+		t.Fatalf("pauseCueList mutation failed: %v", err)
+	}
+	if !pauseResp.PauseCueList.IsPaused || pauseResp.PauseCueList.FollowRemaining == nil {
+		t.Errorf("Expected a paused status holding the follow, got %+v", pauseResp.PauseCueList)
+	}
+
+	// The second list plays alongside the paused one
+	var startResp struct {
+		StartCueList bool `json:"startCueList"`
+	}
+	if err := c.Post(`mutation { startCueList(cueListId: "tr-b", fadeInTime: 0) }`, &startResp); err != nil {
+		t.Fatalf("startCueList mutation failed: %v", err)
+	}
+	var activeResp struct {
+		ActiveCueListPlaybacks []status `json:"activeCueListPlaybacks"`
+	}
+	if err := c.Post(`query { activeCueListPlaybacks { cueListId isPlaying isPaused } }`, &activeResp); err != nil {
+		t.Fatalf("activeCueListPlaybacks query failed: %v", err)
+	}
+	active := activeResp.ActiveCueListPlaybacks
+	if len(active) != 2 || active[0].CueListID != "tr-b" || active[1].CueListID != "tr-a" || !active[1].IsPaused {
+		t.Errorf("Expected both cue lists active, the paused one last, got %+v", active)
+	}
+
+	// Held past its follow time, the paused list stays on its cue
+	time.Sleep(500 * time.Millisecond)
+	if state := resolver.PlaybackService.GetPlaybackState("tr-a"); *state.CurrentCueIndex != 0 {
+		t.Fatalf("Expected the paused cue list to hold cue index 0, got %d", *state.CurrentCueIndex)
+	}
+
+	var resumeResp struct {
+		ResumeCueList status `json:"resumeCueList"`
+	}
+	if err := c.Post(`mutation { resumeCueList(cueListId: "tr-a") { isPaused } }`, &resumeResp); err != nil {
+		t.Fatalf("resumeCueList mutation failed: %v", err)
+	}
+	if resumeResp.ResumeCueList.IsPaused {
+		t.Error("Expected the cue list to resume")
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{1: 200}, 2*time.Second)
+
+	var releaseResp struct {
+		ReleaseCueList status `json:"releaseCueList"`
+	}
+	if err := c.Post(`mutation { releaseCueList(cueListId: "tr-a", fadeOutTime: 0) { isPlaying } }`, &releaseResp); err != nil {
+		t.Fatalf("releaseCueList mutation failed: %v", err)
+	}
+	if releaseResp.ReleaseCueList.IsPlaying {
+		t.Error("Expected the released cue list to stop")
+	}
+	for _, entry := range resolver.StackService.Entries() {
+		if entry.PlaybackID == stack.CueListPlayback("tr-a") {
+			t.Error("Expected the released cue list off the playback stack")
+		}
+	}
+
+	if err := c.Post(`mutation { releaseCueList(cueListId: "tr-b", fadeOutTime: -1) { isPlaying } }`, &releaseResp); err == nil {
+		t.Error("Expected a negative fadeOutTime to be rejected")
+	}
+}
+
+func TestNextCue_FadeOverrides(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-go-fades", Name: "GO Fades Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.Scene{ID: "gf-scene", Name: "Look", ProjectID: project.ID})
+	resolver.db.Create(&models.CueList{ID: "gf-list", Name: "List", ProjectID: project.ID})
+	resolver.db.Create(&models.Cue{ID: "gf-1", Name: "One", CueListID: "gf-list", SceneID: "gf-scene", CueNumber: 1, FadeInTime: 4, FadeOutTime: 2})
+
+	var goResp struct {
+		NextCue bool `json:"nextCue"`
+	}
+	if err := c.Post(`mutation { nextCue(cueListId: "gf-list", fadeInTime: -1) }`, &goResp); err == nil {
+		t.Error("Expected a negative fadeInTime to be rejected")
+	}
+	if err := c.Post(`mutation { nextCue(cueListId: "gf-list", fadeInTime: 0, fadeOutTime: 6) }`, &goResp); err != nil {
+		t.Fatalf("nextCue mutation failed: %v", err)
+	}
+
+	var statusResp struct {
+		CueListPlaybackStatus struct {
+			CurrentCue struct {
+				FadeInTime  float64 `json:"fadeInTime"`
+				FadeOutTime float64 `json:"fadeOutTime"`
+			} `json:"currentCue"`
+		} `json:"cueListPlaybackStatus"`
+	}
+	if err := c.Post(`query { cueListPlaybackStatus(cueListId: "gf-list") { currentCue { fadeInTime fadeOutTime } } }`, &statusResp); err != nil {
+		t.Fatalf("cueListPlaybackStatus query failed: %v", err)
+	}
+	if cue := statusResp.CueListPlaybackStatus.CurrentCue; cue.FadeInTime != 0 || cue.FadeOutTime != 6 {
+		t.Errorf("Expected the GO's fade times in the status, got %+v", cue)
+	}
+
+	var stored models.Cue
+	resolver.db.First(&stored, "id = ?", "gf-1")
+	if stored.FadeInTime != 4 || stored.FadeOutTime != 2 {
+		t.Errorf("Expected the stored cue unchanged, got in %v out %v", stored.FadeInTime, stored.FadeOutTime)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	c, primary, cleanup := testSetup(t)
+	defer cleanup()
+	backupClient, backup, backupCleanup := testSetup(t)
+	defer backupCleanup()
+
+	type statusResp struct {
+		Role          string  `json:"role"`
+		OutputActive  bool    `json:"outputActive"`
+		PeerConnected bool    `json:"peerConnected"`
+		TakenOverAt   *string `json:"takenOverAt"`
+	}
+	var resp struct {
+		ReplicationStatus statusResp `json:"replicationStatus"`
+	}
+	if err := backupClient.Post(`{ replicationStatus { role outputActive peerConnected takenOverAt } }`, &resp); err != nil {
+		t.Fatalf("replicationStatus query failed: %v", err)
+	}
+	if got := resp.ReplicationStatus; got.Role != "STANDALONE" || !got.OutputActive || got.PeerConnected {
+		t.Errorf("Unexpected standalone status: %+v", got)
+	}
+	var failbackResp struct {
+		ReplicationFailback statusResp `json:"replicationFailback"`
+	}
+	if err := backupClient.Post(`mutation { replicationFailback { role } }`, &failbackResp); err == nil {
+		t.Error("Expected a standalone server to refuse to fail back")
+	}
+
+	// State set on the primary before and after the backup connects is
+	// tracked by the backup without it driving output
+	timing := replication.Config{Token: "secret", HeartbeatInterval: 20 * time.Millisecond, FailoverTimeout: 2 * time.Second}
+	primaryCfg := timing
+	primaryCfg.Role = replication.RolePrimary
+	var ignored map[string]interface{}
+	if err := c.Post(`mutation { setMasterLevel(level: 0.5) { grandMaster } }`, &ignored); err != nil {
+		t.Fatalf("setMasterLevel mutation failed: %v", err)
+	}
+	if err := primary.StartReplication(primaryCfg); err != nil {
+		t.Fatalf("StartReplication() error: %v", err)
+	}
+	defer primary.ReplicationService.Cleanup()
+	server := httptest.NewServer(primary.ReplicationService)
+	defer server.Close()
+
+	backupCfg := timing
+	backupCfg.Role = replication.RoleBackup
+	backupCfg.PrimaryURL = "ws" + strings.TrimPrefix(server.URL, "http") + replication.Path
+	if err := backup.StartReplication(backupCfg); err != nil {
+		t.Fatalf("StartReplication() error: %v", err)
+	}
+	defer backup.ReplicationService.Cleanup()
+
+	if err := c.Post(`mutation { setChannelValue(universe: 1, channel: 5, value: 200) }`, &ignored); err != nil {
+		t.Fatalf("setChannelValue mutation failed: %v", err)
+	}
+	if err := c.Post(`mutation { setChannelLimits(limits: [{universe: 1, channel: 9, inhibit: true}]) { channel } }`, &ignored); err != nil {
+		t.Fatalf("setChannelLimits mutation failed: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		tracked := backup.DMXService.MasterLevels().GrandMaster == 0.5 &&
+			backup.DMXService.ProgrammerValues()[1][5] == 200 &&
+			len(backup.DMXService.ChannelLimits()) == 1
+		if tracked {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the backup to track the primary: master %v, programmer %v, limits %v",
+				backup.DMXService.MasterLevels().GrandMaster, backup.DMXService.ProgrammerValues(), backup.DMXService.ChannelLimits())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !backup.DMXService.IsPassive() || primary.DMXService.IsPassive() {
+		t.Error("Expected only the backup's output to be passive")
+	}
+
+	if err := backupClient.Post(`{ replicationStatus { role outputActive peerConnected takenOverAt } }`, &resp); err != nil {
+		t.Fatalf("replicationStatus query failed: %v", err)
+	}
+	if got := resp.ReplicationStatus; got.Role != "BACKUP" || got.OutputActive || got.TakenOverAt != nil {
+		t.Errorf("Unexpected backup status: %+v", got)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	c, _, cleanup := testSetup(t)
+	defer cleanup()
+
+	type logEntry struct {
+		ID         string
+		Level      string
+		Module     string
+		Message    string
+		Attributes []struct{ Key, Value string }
+	}
+	var levelsResp struct {
+		SetLogLevel struct {
+			Modules []struct{ Module, Level string }
+		}
+	}
+	if err := c.Post(`mutation { setLogLevel(module: "resolvertest", level: DEBUG) { modules { module level } } }`, &levelsResp); err != nil {
+		t.Fatalf("setLogLevel mutation failed: %v", err)
+	}
+	found := false
+	for _, module := range levelsResp.SetLogLevel.Modules {
+		if module.Module == "resolvertest" {
+			found = module.Level == "DEBUG"
+		}
+	}
+	if !found {
+		t.Errorf("Expected resolvertest at DEBUG, got %+v", levelsResp.SetLogLevel.Modules)
+	}
+
+	logger := logging.For("resolvertest")
+	logger.Debug("first", "universe", 1)
+	logger.Warn("second")
+
+	var resp struct{ LogEntries []logEntry }
+	if err := c.Post(`{ logEntries(module: "resolvertest") { id level module message attributes { key value } } }`, &resp); err != nil {
+		t.Fatalf("logEntries query failed: %v", err)
+	}
+	if len(resp.LogEntries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", resp.LogEntries)
+	}
+	first := resp.LogEntries[0]
+	if first.Level != "DEBUG" || first.Message != "first" || len(first.Attributes) != 1 || first.Attributes[0].Key != "universe" || first.Attributes[0].Value != "1" {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+
+	if err := c.Post(`query($after: ID) { logEntries(module: "resolvertest", afterId: $after) { id level module message } }`, &resp, client.Var("after", first.ID)); err != nil {
+		t.Fatalf("logEntries query failed: %v", err)
+	}
+	if len(resp.LogEntries) != 1 || resp.LogEntries[0].Message != "second" || resp.LogEntries[0].Level != "WARN" {
+		t.Errorf("Expected only the newer entry, got %+v", resp.LogEntries)
+	}
+	if err := c.Post(`{ logEntries(module: "resolvertest", minLevel: WARN) { id message } }`, &resp); err != nil {
+		t.Fatalf("logEntries query failed: %v", err)
+	}
+	if len(resp.LogEntries) != 1 || resp.LogEntries[0].Message != "second" {
+		t.Errorf("Expected only the warning, got %+v", resp.LogEntries)
+	}
+	if err := c.Post(`{ logEntries(afterId: "latest") { id } }`, &resp); err == nil {
+		t.Error("Expected an invalid entry ID to be rejected")
+	}
+}
+
+func TestSettings(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type setting struct {
+		ID           string
+		Key          string
+		Value        string
+		Type         string
+		DefaultValue *string
+		IsDefault    bool
+	}
+	var resp struct{ Settings []setting }
+	if err := c.Post(`{ settings { id key value type defaultValue isDefault } }`, &resp); err != nil {
+		t.Fatalf("settings query failed: %v", err)
+	}
+	byKey := make(map[string]setting)
+	for _, s := range resp.Settings {
+		byKey[s.Key] = s
+	}
+	if s := byKey[dmx.ArtSyncSettingKey]; !s.IsDefault || s.Type != "BOOLEAN" || s.ID != "" {
+		t.Errorf("Expected an unsaved BOOLEAN setting, got %+v", s)
+	}
+	if s := byKey[dmx.UnicastRoutesSettingKey]; s.Type != "JSON" || s.Value != "[]" || s.DefaultValue == nil || *s.DefaultValue != "[]" {
+		t.Errorf("Expected an empty routing table by default, got %+v", s)
+	}
+
+	// Saving a setting applies it
+	var updateResp struct{ UpdateSetting setting }
+	if err := c.Post(`mutation($key: String!, $value: String!) { updateSetting(input: {key: $key, value: $value}) { id key value type isDefault } }`,
+		&updateResp, client.Var("key", dmx.ArtSyncSettingKey), client.Var("value", " TRUE ")); err != nil {
+		t.Fatalf("updateSetting mutation failed: %v", err)
+	}
+	if s := updateResp.UpdateSetting; s.Value != "true" || s.IsDefault || s.ID == "" {
+		t.Errorf("Expected the saved, normalized value, got %+v", s)
+	}
+	if !resolver.DMXService.ArtSyncEnabled() {
+		t.Error("Expected ArtSync applied")
+	}
+
+	for _, tt := range []struct{ key, value string }{
+		{"unknown_setting", "1"},
+		{fade.UpdateRateSettingKey, "500"},
+		{dmx.ArtSyncSettingKey, "sometimes"},
+		{dmx.BroadcastAddressSettingKey, "not-an-address"},
+		{dmx.ChannelLimitsSettingKey, `[{"universe": 0, "channel": 1}]`},
+	} {
+		err := c.Post(`mutation($key: String!, $value: String!) { updateSetting(input: {key: $key, value: $value}) { id } }`,
+			&updateResp, client.Var("key", tt.key), client.Var("value", tt.value))
+		if err == nil {
+			t.Errorf("Expected %q = %q rejected", tt.key, tt.value)
+		}
+	}
+	if setting, _ := resolver.SettingRepo.FindByKey(context.Background(), fade.UpdateRateSettingKey); setting != nil {
+		t.Errorf("Expected a rejected setting not saved, got %+v", setting)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	var statusResp struct {
+		DiscoveryStatus struct {
+			Enabled      bool     `json:"enabled"`
+			ServiceTypes []string `json:"serviceTypes"`
+		} `json:"discoveryStatus"`
+	}
+	if err := c.Post(`query { discoveryStatus { enabled serviceTypes } }`, &statusResp); err != nil {
+		t.Fatalf("discoveryStatus query failed: %v", err)
+	}
+	if statusResp.DiscoveryStatus.Enabled || len(statusResp.DiscoveryStatus.ServiceTypes) != 2 {
+		t.Errorf("Unexpected status %+v", statusResp.DiscoveryStatus)
+	}
+	var serversResp struct {
+		DiscoveredServers []struct{ Name string } `json:"discoveredServers"`
+	}
+	if err := c.Post(`query { discoveredServers { name } }`, &serversResp); err == nil {
+		t.Error("Expected discoveredServers to fail before discovery is started")
+	}
+
+	// The TXT record carries the project count and the latest project
+	resolver.db.Create(&models.Project{ID: "discovery-old", Name: "Old Show", UpdatedAt: time.Now().Add(-time.Hour)})
+	resolver.db.Create(&models.Project{ID: "discovery-new", Name: "Hamlet"})
+	info := resolver.discoveryInfo()
+	if info["projects"] != "2" || info["project"] != "Hamlet" || info["path"] != "/graphql" || info["version"] == "" {
+		t.Errorf("discoveryInfo() = %v", info)
+	}
+
+	server := convertDiscoveredServer(discovery.Peer{
+		Name:      "Stage",
+		Host:      "stage.local",
+		Port:      4000,
+		Addresses: []net.IP{net.ParseIP("192.168.1.30")},
+		Info:      map[string]string{"version": "1.4.0", "projects": "3", "project": "Macbeth"},
+	})
+	if server.GraphqlURL != "http://192.168.1.30:4000/graphql" || *server.ProjectCount != 3 || *server.Project != "Macbeth" {
+		t.Errorf("Unexpected server %+v", server)
+	}
+	if server := convertDiscoveredServer(discovery.Peer{Host: "stage.local", Port: 4000}); server.GraphqlURL != "http://stage.local:4000/graphql" || server.ProjectCount != nil {
+		t.Errorf("Unexpected server without addresses %+v", server)
+	}
+	secure := discovery.Peer{Host: "stage.local", Port: 443, Info: map[string]string{"scheme": "https"}}
+	if server := convertDiscoveredServer(secure); server.GraphqlURL != "https://stage.local:443/graphql" {
+		t.Errorf("Unexpected server serving TLS %+v", server)
+	}
+}
+
+func TestSceneColorSummary(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "look-project", Name: "Look Project"}
+	resolver.db.Create(project)
+	resolver.db.Create(&models.FixtureInstance{ID: "look-rgb", Name: "Wash 1", ProjectID: project.ID, Universe: 1, StartChannel: 1})
+	for i, channelType := range []string{"INTENSITY", "RED", "GREEN", "BLUE"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("look-rgb-%d", i), FixtureID: "look-rgb", Offset: i, Name: channelType, Type: channelType, MaxValue: 255})
+	}
+	resolver.db.Create(&models.FixtureInstance{ID: "look-dimmer", Name: "Dimmer 1", ProjectID: project.ID, Universe: 1, StartChannel: 10})
+	resolver.db.Create(&models.InstanceChannel{ID: "look-dimmer-0", FixtureID: "look-dimmer", Offset: 0, Name: "Dimmer", Type: "INTENSITY", MaxValue: 255})
+	resolver.db.Create(&models.FixtureGroup{ID: "look-front", Name: "Front", ProjectID: project.ID, FixtureIDs: `["look-rgb"]`})
+	resolver.db.Create(&models.Scene{ID: "look-scene", Name: "Sunset", ProjectID: project.ID})
+	resolver.db.Create(&models.FixtureValue{ID: "look-fv-1", SceneID: "look-scene", FixtureID: "look-rgb", Channels: `[{"offset":0,"value":255},{"offset":1,"value":255},{"offset":2,"value":0},{"offset":3,"value":0}]`})
+	resolver.db.Create(&models.FixtureValue{ID: "look-fv-2", SceneID: "look-scene", FixtureID: "look-dimmer", Channels: `[{"offset":0,"value":0}]`})
+
+	type colorSummary struct {
+		Intensity      float64  `json:"intensity"`
+		DominantColors []string `json:"dominantColors"`
+		Groups         []struct {
+			GroupID      *string `json:"groupId"`
+			Color        *string `json:"color"`
+			FixtureCount int     `json:"fixtureCount"`
+		} `json:"groups"`
+	}
+	query := func() colorSummary {
+		var resp struct {
+			Scene struct {
+				ColorSummary colorSummary `json:"colorSummary"`
+			} `json:"scene"`
+		}
+		if err := c.Post(`query { scene(id: "look-scene") { colorSummary { intensity dominantColors groups { groupId color fixtureCount } } } }`, &resp); err != nil {
+			t.Fatalf("scene query failed: %v", err)
+		}
+		return resp.Scene.ColorSummary
+	}
+
+	summary := query()
+	if summary.Intensity != 0.5 || len(summary.DominantColors) != 1 || summary.DominantColors[0] != "#FF0000" {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if len(summary.Groups) != 2 || summary.Groups[0].GroupID == nil || *summary.Groups[0].GroupID != "look-front" ||
+		summary.Groups[1].GroupID != nil || summary.Groups[1].Color != nil {
+		t.Errorf("Unexpected groups %+v", summary.Groups)
+	}
+	if _, _, ok := resolver.SceneSummaries.Get("look-scene"); !ok {
+		t.Error("Expected the summary to be cached")
+	}
+
+	// Updating the scene drops the cached summary
+	var updateResp struct {
+		UpdateScene struct{ ID string } `json:"updateScene"`
+	}
+	if err := c.Post(`mutation { updateScene(id: "look-scene", input: {fixtureValues: [{fixtureId: "look-rgb", channels: [{offset: 0, value: 255}, {offset: 1, value: 0}, {offset: 2, value: 0}, {offset: 3, value: 255}]}]}) { id } }`, &updateResp); err != nil {
+		t.Fatalf("updateScene failed: %v", err)
+	}
+	if summary := query(); len(summary.DominantColors) != 1 || summary.DominantColors[0] != "#0000FF" {
+		t.Errorf("Summary after update = %+v, want blue", summary)
+	}
+
+	// Scene pages carry the summary too
+	var pageResp struct {
+		Scenes struct {
+			Scenes []struct {
+				ColorSummary colorSummary `json:"colorSummary"`
+			} `json:"scenes"`
+		} `json:"scenes"`
+	}
+	if err := c.Post(`query { scenes(projectId: "look-project") { scenes { colorSummary { intensity dominantColors groups { groupId color fixtureCount } } } } }`, &pageResp); err != nil {
+		t.Fatalf("scenes query failed: %v", err)
+	}
+	if scenes := pageResp.Scenes.Scenes; len(scenes) != 1 || len(scenes[0].ColorSummary.DominantColors) != 1 {
+		t.Errorf("Unexpected scene page %+v", scenes)
+	}
+}
+
+func TestStageView(t *testing.T) {
+	_, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	x, y, rotation := 0.5, 0.25, 90.0
+	resolver.db.Create(&models.Project{ID: "view-project", Name: "View Project"})
+	resolver.db.Create(&models.FixtureInstance{ID: "view-rgb", Name: "Wash 1", ProjectID: "view-project", Universe: 1, StartChannel: 1, LayoutX: &x, LayoutY: &y, LayoutRotation: &rotation})
+	for i, channelType := range []string{"INTENSITY", "RED", "GREEN", "BLUE"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("view-rgb-%d", i), FixtureID: "view-rgb", Offset: i, Name: channelType, Type: channelType, MaxValue: 255})
+	}
+	resolver.db.Create(&models.FixtureInstance{ID: "view-spare", Name: "Spare", ProjectID: "view-project", Universe: 1, StartChannel: 10})
+	resolver.db.Create(&models.LayoutZone{ID: "view-zone", ProjectID: "view-project", Name: "Stage Left", X: 0, Y: 0, Width: 0.3, Height: 1})
+	resolver.db.Create(&models.Scene{ID: "view-scene", Name: "Blue Wash", ProjectID: "view-project"})
+	resolver.db.Create(&models.FixtureValue{ID: "view-fv", SceneID: "view-scene", FixtureID: "view-rgb", Channels: `[{"offset":0,"value":255},{"offset":3,"value":255}]`})
+
+	server := httptest.NewServer(http.HandlerFunc(resolver.StageViewService.ServeScene))
+	defer server.Close()
+	get := func(query string) (int, string, string) {
+		resp, err := http.Get(server.URL + stageview.ScenePath + "?" + query)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	status, contentType, svg := get("id=view-scene&width=200&height=100")
+	if status != http.StatusOK || contentType != stageview.ContentType {
+		t.Fatalf("GET = %d %s: %s", status, contentType, svg)
+	}
+	for _, want := range []string{`width="200" height="100"`, "<title>Blue Wash</title>", ">Stage Left</text>", ">Spare</text>", `fill="#0000FF"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected %q in %s", want, svg)
+		}
+	}
+	// Rotated a quarter turn, the beam points to the left of the fixture
+	if !strings.Contains(svg, `<polygon points="100.0,25.0 78.5,`) {
+		t.Errorf("Expected the beam to point left in %s", svg)
+	}
+
+	if status, _, _ := get("id=missing"); status != http.StatusNotFound {
+		t.Errorf("Missing scene status = %d, want 404", status)
+	}
+	if status, _, _ := get("id=view-scene&width=1"); status != http.StatusBadRequest {
+		t.Errorf("Bad width status = %d, want 400", status)
+	}
+}
+
+func TestHouseLights(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	resolver.db.Create(&models.Project{ID: "house-project", Name: "House Project"})
+	resolver.db.Create(&models.FixtureInstance{ID: "house-dimmer", Name: "House 1", ProjectID: "house-project", Universe: 1, StartChannel: 20})
+	resolver.db.Create(&models.InstanceChannel{ID: "house-dimmer-0", FixtureID: "house-dimmer", Offset: 0, Name: "Dimmer", Type: "INTENSITY", MaxValue: 255})
+	resolver.db.Create(&models.FixtureInstance{ID: "house-rgb", Name: "House 2", ProjectID: "house-project", Universe: 1, StartChannel: 30})
+	for i, channelType := range []string{"RED", "GREEN", "BLUE"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("house-rgb-%d", i), FixtureID: "house-rgb", Offset: i, Name: channelType, Type: channelType, MaxValue: 255})
+	}
+	resolver.db.Create(&models.FixtureGroup{ID: "house-group", Name: "Auditorium", ProjectID: "house-project", FixtureIDs: `["house-rgb"]`})
+	resolver.db.Create(&models.Project{ID: "other-house-project", Name: "Other"})
+	resolver.db.Create(&models.FixtureInstance{ID: "other-fixture", Name: "Other", ProjectID: "other-house-project", Universe: 1, StartChannel: 1})
+
+	var setResp struct {
+		SetHouseLights struct {
+			Engaged bool    `json:"engaged"`
+			Preset  *string `json:"preset"`
+			Level   float64 `json:"level"`
+		} `json:"setHouseLights"`
+	}
+	setHalf := `mutation { setHouseLights(projectId: "house-project", preset: HALF, fadeTime: 0) { engaged preset level } }`
+	if err := c.Post(setHalf, &setResp); err == nil || !strings.Contains(err.Error(), "no house lights") {
+		t.Errorf("Expected house lights to need fixtures, got %v", err)
+	}
+
+	var updateResp struct {
+		UpdateHouseLights struct {
+			Fixtures []struct {
+				ID string `json:"id"`
+			} `json:"fixtures"`
+			Groups []struct {
+				Name string `json:"name"`
+			} `json:"groups"`
+			HalfLevel float64 `json:"halfLevel"`
+			FadeTime  float64 `json:"fadeTime"`
+		} `json:"updateHouseLights"`
+	}
+	update := `mutation($input: UpdateHouseLightsInput!) {
+		updateHouseLights(projectId: "house-project", input: $input) { fixtures { id } groups { name } halfLevel fadeTime }
+	}`
+	err := c.Post(update, &updateResp, client.Var("input", map[string]interface{}{
+		"fixtureIds": []string{"house-dimmer"}, "groupIds": []string{"house-group"}, "halfLevel": 0.4,
+	}))
+	if err != nil {
+		t.Fatalf("updateHouseLights failed: %v", err)
+	}
+	got := updateResp.UpdateHouseLights
+	if len(got.Fixtures) != 2 || len(got.Groups) != 1 || got.Groups[0].Name != "Auditorium" || got.HalfLevel != 0.4 || got.FadeTime != 3 {
+		t.Errorf("updateHouseLights = %+v", got)
+	}
+	if err := c.Post(update, &updateResp, client.Var("input", map[string]interface{}{"fixtureIds": []string{"other-fixture"}})); err == nil {
+		t.Error("Expected another project's fixture to be rejected")
+	}
+	if err := c.Post(update, &updateResp, client.Var("input", map[string]interface{}{"fullLevel": 1.5})); err == nil {
+		t.Error("Expected a level above 1 to be rejected")
+	}
+
+	// House lights stay up through a blackout
+	if _, err := resolver.DMXService.Blackout(0); err != nil {
+		t.Fatalf("Blackout failed: %v", err)
+	}
+	if err := c.Post(setHalf, &setResp); err != nil {
+		t.Fatalf("setHouseLights failed: %v", err)
+	}
+	if !setResp.SetHouseLights.Engaged || setResp.SetHouseLights.Preset == nil || *setResp.SetHouseLights.Preset != "HALF" {
+		t.Errorf("setHouseLights = %+v", setResp.SetHouseLights)
+	}
+	output := resolver.DMXService.GetUniverse(1)
+	if output[19] != 102 || output[29] != 102 || output[31] != 102 {
+		t.Errorf("Expected house channels at 40%%, got %d %d %d", output[19], output[29], output[31])
+	}
+
+	// Changing the levels moves engaged house lights at once
+	if err := c.Post(update, &updateResp, client.Var("input", map[string]interface{}{"halfLevel": 0.6})); err != nil {
+		t.Fatalf("updateHouseLights failed: %v", err)
+	}
+	if output := resolver.DMXService.GetUniverse(1); output[19] != 153 {
+		t.Errorf("Expected the dimmer at 60%%, got %d", output[19])
+	}
+
+	var releaseResp struct {
+		ReleaseHouseLights struct {
+			Engaged bool `json:"engaged"`
+		} `json:"releaseHouseLights"`
+	}
+	if err := c.Post(`mutation { releaseHouseLights(projectId: "house-project") { engaged } }`, &releaseResp); err != nil {
+		t.Fatalf("releaseHouseLights failed: %v", err)
+	}
+	if output := resolver.DMXService.GetUniverse(1); releaseResp.ReleaseHouseLights.Engaged || output[19] != 0 {
+		t.Errorf("Expected release to hand back the channels, got %+v and %d", releaseResp.ReleaseHouseLights, output[19])
+	}
+	if _, err := resolver.DMXService.RestoreFromBlackout(0); err != nil {
+		t.Fatalf("RestoreFromBlackout failed: %v", err)
+	}
+
+	// Front of house users run the house lights and nothing else
+	resolver.AuthService.Enable([]byte("test-secret"), time.Hour)
+	if _, err := resolver.AuthService.CreateUser(ctx, "foh@example.com", nil, "house-password", auth.RoleHouse); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var loginResp struct {
+		Login struct {
+			Token string `json:"token"`
+		} `json:"login"`
+	}
+	if err := c.Post(`mutation { login(email: "foh@example.com", password: "house-password") { token } }`, &loginResp); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	asHouse := client.AddHeader("Authorization", "Bearer "+loginResp.Login.Token)
+	if err := c.Post(setHalf, &setResp, asHouse); err != nil {
+		t.Errorf("Expected front of house to set the house lights, got %v", err)
+	}
+	if err := c.Post(update, &updateResp, asHouse, client.Var("input", map[string]interface{}{"halfLevel": 1})); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected front of house to be unable to change the house lights, got %v", err)
+	}
+	if err := c.Post(`mutation { blackout { isBlackout } }`, &map[string]interface{}{}, asHouse); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected front of house to be unable to black out, got %v", err)
+	}
+}
+
+func TestIndependents(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "state.journal"), journal.SyncNever, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	resolver.SetStateJournal(j)
+
+	resolver.db.Create(&models.Project{ID: "ind-project", Name: "Independents"})
+	resolver.db.Create(&models.FixtureInstance{ID: "ind-desk", Name: "SM Desk", ProjectID: "ind-project", Universe: 1, StartChannel: 40})
+	resolver.db.Create(&models.InstanceChannel{ID: "ind-desk-0", FixtureID: "ind-desk", Offset: 0, Name: "Dimmer", Type: "INTENSITY", MaxValue: 255})
+	resolver.db.Create(&models.Project{ID: "ind-other", Name: "Other"})
+
+	type independent struct {
+		ID        string  `json:"id"`
+		Name      string  `json:"name"`
+		ProjectID *string `json:"projectId"`
+		Channels  []struct {
+			Universe int `json:"universe"`
+			Channel  int `json:"channel"`
+			Value    int `json:"value"`
+		} `json:"channels"`
+	}
+	var setResp struct {
+		SetIndependent independent `json:"setIndependent"`
+	}
+	set := `mutation($input: IndependentInput!) { setIndependent(input: $input) { id name projectId channels { universe channel value } } }`
+	err = c.Post(set, &setResp, client.Var("input", map[string]interface{}{
+		"name":     "Desk light",
+		"fixtures": []map[string]interface{}{{"fixtureId": "ind-desk", "channels": []map[string]interface{}{{"offset": 0, "value": 200}}}},
+		"channels": []map[string]interface{}{{"universe": 1, "channel": 100, "value": 255}},
+	}))
+	if err != nil {
+		t.Fatalf("setIndependent failed: %v", err)
+	}
+	desk := setResp.SetIndependent
+	if desk.Name != "Desk light" || desk.ProjectID == nil || *desk.ProjectID != "ind-project" || len(desk.Channels) != 2 ||
+		desk.Channels[0].Channel != 40 || desk.Channels[0].Value != 200 || desk.Channels[1].Channel != 100 {
+		t.Errorf("setIndependent = %+v", desk)
+	}
+
+	for _, input := range []map[string]interface{}{
+		{"name": "Empty"},
+		{"name": "Too bright", "channels": []map[string]interface{}{{"universe": 1, "channel": 1, "value": 256}}},
+		{"name": "No channel", "fixtures": []map[string]interface{}{{"fixtureId": "ind-desk", "channels": []map[string]interface{}{{"offset": 3, "value": 1}}}}},
+		{"name": "Wrong project", "projectId": "ind-other", "fixtures": []map[string]interface{}{{"fixtureId": "ind-desk", "channels": []map[string]interface{}{{"offset": 0, "value": 1}}}}},
+	} {
+		if err := c.Post(set, &setResp, client.Var("input", input)); err == nil {
+			t.Errorf("Expected setIndependent(%v) to fail", input["name"])
+		}
+	}
+
+	// Independents stay up when the stage goes to black
+	var fadeResp struct {
+		FadeToBlack bool `json:"fadeToBlack"`
+	}
+	if err := c.Post(`mutation { fadeToBlack(fadeOutTime: 0) }`, &fadeResp); err != nil {
+		t.Fatalf("fadeToBlack mutation failed: %v", err)
+	}
+	if output := resolver.DMXService.GetUniverse(1); output[39] != 200 || output[99] != 255 {
+		t.Errorf("Expected the independent after a fade to black, got %d %d", output[39], output[99])
+	}
+
+	var listResp struct {
+		Independents []independent `json:"independents"`
+	}
+	if err := c.Post(`query { independents(projectId: "ind-other") { id } }`, &listResp); err != nil || len(listResp.Independents) != 0 {
+		t.Errorf("independents(ind-other) = %+v, %v; want none", listResp.Independents, err)
+	}
+	if err := c.Post(`query { independents { id } }`, &listResp); err != nil || len(listResp.Independents) != 1 || listResp.Independents[0].ID != desk.ID {
+		t.Errorf("independents = %+v, %v; want the desk light", listResp.Independents, err)
+	}
+
+	// A restart sets the journaled independents again
+	resolver.DMXService.ReleaseIndependent(desk.ID)
+	var resumeResp struct {
+		ResumePlayback struct {
+			Independents int `json:"independents"`
+		} `json:"resumePlayback"`
+	}
+	if err := c.Post(`mutation { resumePlayback { independents } }`, &resumeResp); err != nil {
+		t.Fatalf("resumePlayback failed: %v", err)
+	}
+	if resumeResp.ResumePlayback.Independents != 1 || resolver.DMXService.GetUniverse(1)[39] != 200 {
+		t.Errorf("Expected the desk light restored, got %+v", resumeResp.ResumePlayback)
+	}
+
+	var releaseResp struct {
+		ReleaseIndependents int `json:"releaseIndependents"`
+	}
+	if err := c.Post(`mutation { releaseIndependents(projectId: "ind-project") }`, &releaseResp); err != nil || releaseResp.ReleaseIndependents != 1 {
+		t.Errorf("releaseIndependents = %d, %v; want 1", releaseResp.ReleaseIndependents, err)
+	}
+	if output := resolver.DMXService.GetUniverse(1); output[39] != 0 {
+		t.Errorf("Expected the channel released, got %d", output[39])
+	}
+	if entries := j.Entries(journalKindIndependent); len(entries) != 0 {
+		t.Errorf("Expected no independents journaled after release, got %v", entries)
+	}
+	var releaseOne struct {
+		ReleaseIndependent bool `json:"releaseIndependent"`
+	}
+	if err := c.Post(`mutation { releaseIndependent(id: "missing") }`, &releaseOne); err != nil || releaseOne.ReleaseIndependent {
+		t.Errorf("releaseIndependent(missing) = %v, %v; want false", releaseOne.ReleaseIndependent, err)
+	}
+}
+
+func TestParkedChannels(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "park-project", Name: "Park"})
+	resolver.db.Create(&models.FixtureInstance{ID: "park-mover", Name: "Mover 3", ProjectID: "park-project", Universe: 2, StartChannel: 100})
+	resolver.db.Create(&models.InstanceChannel{ID: "park-mover-0", FixtureID: "park-mover", Offset: 0, Name: "Pan", Type: "PAN", MaxValue: 255})
+	resolver.db.Create(&models.InstanceChannel{ID: "park-mover-1", FixtureID: "park-mover", Offset: 1, Name: "Tilt", Type: "TILT", MaxValue: 255})
+
+	type parked struct {
+		Universe  int     `json:"universe"`
+		Channel   int     `json:"channel"`
+		Value     int     `json:"value"`
+		FixtureID *string `json:"fixtureId"`
+	}
+	var parkResp struct {
+		ParkChannel []parked `json:"parkChannel"`
+	}
+	err := c.Post(`mutation {
+		parkChannel(input: {universe: 1, channel: 5, value: 200}) { universe channel value fixtureId }
+	}`, &parkResp)
+	if err != nil {
+		t.Fatalf("parkChannel mutation failed: %v", err)
+	}
+	err = c.Post(`mutation {
+		parkChannel(input: {fixtureId: "park-mover", offset: 1, value: 64}) { universe channel value fixtureId }
+	}`, &parkResp)
+	if err != nil {
+		t.Fatalf("parkChannel by fixture failed: %v", err)
+	}
+	if len(parkResp.ParkChannel) != 2 {
+		t.Fatalf("Expected 2 parked channels, got %+v", parkResp.ParkChannel)
+	}
+	if got := parkResp.ParkChannel[1]; got.Universe != 2 || got.Channel != 101 || got.Value != 64 || got.FixtureID == nil || *got.FixtureID != "park-mover" {
+		t.Errorf("Unexpected fixture parked channel: %+v", got)
+	}
+
+	// Parked channels ignore playback and blackout
+	resolver.DMXService.SetChannelValue(1, 5, 10)
+	resolver.DMXService.SetChannelValue(2, 101, 255)
+	if _, err := resolver.DMXService.Blackout(0); err != nil {
+		t.Fatalf("Blackout() error: %v", err)
+	}
+	out1, out2 := resolver.DMXService.GetUniverse(1), resolver.DMXService.GetUniverse(2)
+	if out1[4] != 200 || out2[100] != 64 {
+		t.Errorf("Expected parked values 200 and 64, got %d and %d", out1[4], out2[100])
+	}
+	if _, err := resolver.DMXService.RestoreFromBlackout(0); err != nil {
+		t.Fatalf("RestoreFromBlackout() error: %v", err)
+	}
+
+	// Parking a parked channel again changes its value
+	err = c.Post(`mutation {
+		parkChannel(input: {universe: 1, channel: 5, value: 90}) { universe channel value fixtureId }
+	}`, &parkResp)
+	if err != nil {
+		t.Fatalf("parkChannel mutation failed: %v", err)
+	}
+	if len(parkResp.ParkChannel) != 2 || parkResp.ParkChannel[0].Value != 90 {
+		t.Errorf("Expected channel 5 re-parked at 90, got %+v", parkResp.ParkChannel)
+	}
+
+	// Invalid requests are rejected
+	for _, q := range []string{
+		`mutation { parkChannel(input: {universe: 1, channel: 5, value: 300}) { channel } }`,
+		`mutation { parkChannel(input: {universe: 1, value: 10}) { channel } }`,
+		`mutation { parkChannel(input: {fixtureId: "park-mover", offset: 7, value: 10}) { channel } }`,
+		`mutation { parkChannel(input: {fixtureId: "park-mover", value: 10}) { channel } }`,
+	} {
+		if err := c.Post(q, &parkResp); err == nil {
+			t.Errorf("Expected error for %s", q)
+		}
+	}
+
+	// The parked channels are saved
+	setting, err := resolver.SettingRepo.FindByKey(context.Background(), dmx.ParkedChannelsSettingKey)
+	if err != nil || setting == nil || !strings.Contains(setting.Value, `"channel":101`) {
+		t.Errorf("Expected parked channels saved, got %+v (%v)", setting, err)
+	}
+
+	var unparkResp struct {
+		UnparkChannel []parked `json:"unparkChannel"`
+	}
+	err = c.Post(`mutation { unparkChannel(universe: 1, channel: 5) { channel } }`, &unparkResp)
+	if err != nil {
+		t.Fatalf("unparkChannel mutation failed: %v", err)
+	}
+	if len(unparkResp.UnparkChannel) != 1 || unparkResp.UnparkChannel[0].Channel != 101 {
+		t.Errorf("Expected only channel 101 parked, got %+v", unparkResp.UnparkChannel)
+	}
+	if out := resolver.DMXService.GetUniverse(1); out[4] != 10 {
+		t.Errorf("Expected unparked channel back at 10, got %d", out[4])
+	}
+
+	var listResp struct {
+		ParkedChannels []parked `json:"parkedChannels"`
+	}
+	if err := c.Post(`query { parkedChannels { universe channel value } }`, &listResp); err != nil {
+		t.Fatalf("parkedChannels query failed: %v", err)
+	}
+	if len(listResp.ParkedChannels) != 1 || listResp.ParkedChannels[0].Value != 64 {
+		t.Errorf("Unexpected parked channels: %+v", listResp.ParkedChannels)
+	}
+}
+
+func TestDMXRecordings(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	type status struct {
+		IsRecording bool    `json:"isRecording"`
+		Recording   *string `json:"recording"`
+		IsReplaying bool    `json:"isReplaying"`
+		Replaying   *string `json:"replaying"`
+		Loop        bool    `json:"loop"`
+	}
+	var startResp struct {
+		StartDmxRecording status `json:"startDmxRecording"`
+	}
+	if err := c.Post(`mutation { startDmxRecording { isRecording } }`, &startResp); err == nil {
+		t.Error("Expected an error while recording is not enabled")
+	}
+
+	resolver.EnableDMXRecordings(t.TempDir())
+	defer resolver.DMXRecorder.Cleanup()
+	resolver.DMXService.SetChannelValue(1, 1, 180)
+	if err := c.Post(`mutation { startDmxRecording { isRecording recording } }`, &startResp); err != nil {
+		t.Fatalf("startDmxRecording mutation failed: %v", err)
+	}
+	if !startResp.StartDmxRecording.IsRecording || startResp.StartDmxRecording.Recording == nil {
+		t.Fatalf("Expected a recording in progress, got %+v", startResp.StartDmxRecording)
+	}
+
+	var stopResp struct {
+		StopDmxRecording struct {
+			Name string `json:"name"`
+			Size int    `json:"size"`
+		} `json:"stopDmxRecording"`
+	}
+	if err := c.Post(`mutation { stopDmxRecording { name size } }`, &stopResp); err != nil {
+		t.Fatalf("stopDmxRecording mutation failed: %v", err)
+	}
+	name := stopResp.StopDmxRecording.Name
+	if name != *startResp.StartDmxRecording.Recording || stopResp.StopDmxRecording.Size == 0 {
+		t.Errorf("Unexpected finished recording: %+v", stopResp.StopDmxRecording)
+	}
+
+	var listResp struct {
+		DmxRecordings []struct {
+			Name string `json:"name"`
+		} `json:"dmxRecordings"`
+	}
+	if err := c.Post(`query { dmxRecordings { name } }`, &listResp); err != nil {
+		t.Fatalf("dmxRecordings query failed: %v", err)
+	}
+	if len(listResp.DmxRecordings) != 1 || listResp.DmxRecordings[0].Name != name {
+		t.Errorf("Unexpected recordings: %+v", listResp.DmxRecordings)
+	}
+
+	// The replay replaces the live output
+	var replayResp struct {
+		ReplayDmxRecording status `json:"replayDmxRecording"`
+	}
+	err := c.Post(`mutation($name: String!) { replayDmxRecording(name: $name, loop: true) { isReplaying replaying loop } }`,
+		&replayResp, client.Var("name", name))
+	if err != nil {
+		t.Fatalf("replayDmxRecording mutation failed: %v", err)
+	}
+	if got := replayResp.ReplayDmxRecording; !got.IsReplaying || got.Replaying == nil || *got.Replaying != name || !got.Loop {
+		t.Errorf("Unexpected replay status: %+v", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !resolver.DMXService.IsReplaying() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the replay to reach the output")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var deleteResp struct {
+		DeleteDmxRecording bool `json:"deleteDmxRecording"`
+	}
+	err = c.Post(`mutation($name: String!) { deleteDmxRecording(name: $name) }`, &deleteResp, client.Var("name", name))
+	if err == nil {
+		t.Error("Expected an error deleting the recording being replayed")
+	}
+
+	var stopReplayResp struct {
+		StopDmxReplay status `json:"stopDmxReplay"`
+	}
+	if err := c.Post(`mutation { stopDmxReplay { isReplaying } }`, &stopReplayResp); err != nil {
+		t.Fatalf("stopDmxReplay mutation failed: %v", err)
+	}
+	if stopReplayResp.StopDmxReplay.IsReplaying || resolver.DMXService.IsReplaying() {
+		t.Error("Expected the replay stopped")
+	}
+
+	err = c.Post(`mutation($name: String!) { deleteDmxRecording(name: $name) }`, &deleteResp, client.Var("name", name))
+	if err != nil || !deleteResp.DeleteDmxRecording {
+		t.Errorf("deleteDmxRecording mutation failed: %v", err)
+	}
+}
+
+func TestCueMoveInBlack(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: cuid.New(), Name: "Movers"}
+	resolver.db.Create(project)
+	scene := &models.Scene{ID: cuid.New(), Name: "Look", ProjectID: project.ID}
+	resolver.db.Create(scene)
+	cueList := &models.CueList{ID: cuid.New(), Name: "Main", ProjectID: project.ID}
+	resolver.db.Create(cueList)
+
+	var cueResp struct {
+		CreateCue struct {
+			ID          string `json:"id"`
+			MoveInBlack bool   `json:"moveInBlack"`
+		} `json:"createCue"`
+	}
+	err := c.Post(`mutation($cueListId: ID!, $sceneId: ID!) {
+		createCue(input: { name: "Sweep", cueNumber: 1, cueListId: $cueListId, sceneId: $sceneId, fadeInTime: 0, fadeOutTime: 0, moveInBlack: true }) {
+			id
+			moveInBlack
+		}
+	}`, &cueResp, client.Var("cueListId", cueList.ID), client.Var("sceneId", scene.ID))
+	if err != nil {
+		t.Fatalf("createCue failed: %v", err)
+	}
+	if !cueResp.CreateCue.MoveInBlack {
+		t.Error("Expected the cue to move in black")
+	}
+
+	var bulkResp struct {
+		BulkUpdateCues []struct {
+			MoveInBlack bool `json:"moveInBlack"`
+		} `json:"bulkUpdateCues"`
+	}
+	err = c.Post(`mutation($cueId: ID!) {
+		bulkUpdateCues(input: { cueIds: [$cueId], moveInBlack: false }) { moveInBlack }
+	}`, &bulkResp, client.Var("cueId", cueResp.CreateCue.ID))
+	if err != nil {
+		t.Fatalf("bulkUpdateCues failed: %v", err)
+	}
+	if len(bulkResp.BulkUpdateCues) != 1 || bulkResp.BulkUpdateCues[0].MoveInBlack {
+		t.Errorf("Expected move in black turned off, got %+v", bulkResp.BulkUpdateCues)
+	}
+}
+
+func TestPixelMatrices(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	resolver.db.Create(&models.Project{ID: "pixel-project", Name: "Pixel Project"})
+	resolver.db.Create(&models.FixtureInstance{ID: "pixel-bar", Name: "Bar", ProjectID: "pixel-project", Universe: 1, StartChannel: 10})
+	for i, channelType := range []string{"RED", "GREEN", "BLUE", "RED", "GREEN", "BLUE"} {
+		resolver.db.Create(&models.InstanceChannel{ID: fmt.Sprintf("pixel-bar-%d", i), FixtureID: "pixel-bar", Offset: i, Name: channelType, Type: channelType, MaxValue: 255})
+	}
+	resolver.db.Create(&models.Project{ID: "other-pixel-project", Name: "Other"})
+	resolver.db.Create(&models.FixtureInstance{ID: "other-bar", Name: "Other", ProjectID: "other-pixel-project", Universe: 1, StartChannel: 100})
+
+	type matrixResult struct {
+		ID     string `json:"id"`
+		Width  int    `json:"width"`
+		Pixels []struct {
+			X    int `json:"x"`
+			Cell int `json:"cell"`
+		} `json:"pixels"`
+		IsActive bool `json:"isActive"`
+	}
+	var createResp struct {
+		CreatePixelMatrix matrixResult `json:"createPixelMatrix"`
+	}
+	create := `mutation($input: CreatePixelMatrixInput!) { createPixelMatrix(input: $input) { id width pixels { x cell } isActive } }`
+	pixels := []map[string]interface{}{
+		{"x": 0, "y": 0, "fixtureId": "pixel-bar"},
+		{"x": 1, "y": 0, "fixtureId": "pixel-bar", "cell": 1},
+	}
+	err := c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+		"projectId": "pixel-project", "name": "Bar", "width": 2, "height": 1, "pixels": pixels,
+	}))
+	if err != nil {
+		t.Fatalf("createPixelMatrix failed: %v", err)
+	}
+	matrix := createResp.CreatePixelMatrix
+	if matrix.Width != 2 || len(matrix.Pixels) != 2 || matrix.Pixels[1].Cell != 1 || matrix.IsActive {
+		t.Errorf("createPixelMatrix = %+v", matrix)
+	}
+
+	for name, pixel := range map[string]map[string]interface{}{
+		"outside":         {"x": 2, "y": 0, "fixtureId": "pixel-bar"},
+		"unknown cell":    {"x": 0, "y": 0, "fixtureId": "pixel-bar", "cell": 2},
+		"another project": {"x": 0, "y": 0, "fixtureId": "other-bar"},
+	} {
+		err := c.Post(create, &createResp, client.Var("input", map[string]interface{}{
+			"projectId": "pixel-project", "name": "Bad", "width": 2, "height": 1, "pixels": []map[string]interface{}{pixel},
+		}))
+		if err == nil {
+			t.Errorf("Expected a pixel %s to be rejected", name)
+		}
+	}
+
+	// Left pixel red, right pixel blue
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, imagecolor.RGBA{R: 255, A: 255})
+	img.Set(1, 0, imagecolor.RGBA{B: 255, A: 255})
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		t.Fatal(err)
+	}
+	var showResp struct {
+		ShowPixelMatrixImage matrixResult `json:"showPixelMatrixImage"`
+	}
+	err = c.Post(`mutation($id: ID!, $image: String!) { showPixelMatrixImage(matrixId: $id, image: $image) { id isActive } }`, &showResp,
+		client.Var("id", matrix.ID), client.Var("image", base64.StdEncoding.EncodeToString(encoded.Bytes())))
+	if err != nil {
+		t.Fatalf("showPixelMatrixImage failed: %v", err)
+	}
+	if !showResp.ShowPixelMatrixImage.IsActive {
+		t.Error("Expected the matrix to be showing")
+	}
+	output := resolver.DMXService.GetUniverse(1)
+	if output[9] != 255 || output[11] != 0 || output[12] != 0 || output[14] != 255 {
+		t.Errorf("Expected red then blue, got %v", output[9:15])
+	}
+
+	var gradientResp struct {
+		ShowPixelMatrixGradient matrixResult `json:"showPixelMatrixGradient"`
+	}
+	gradient := `mutation($id: ID!, $colors: [String!]!) { showPixelMatrixGradient(matrixId: $id, colors: $colors) { id } }`
+	if err := c.Post(gradient, &gradientResp, client.Var("id", matrix.ID), client.Var("colors", []string{"#00FF00"})); err != nil {
+		t.Fatalf("showPixelMatrixGradient failed: %v", err)
+	}
+	output = resolver.DMXService.GetUniverse(1)
+	if output[10] != 255 || output[13] != 255 || output[9] != 0 {
+		t.Errorf("Expected both cells green, got %v", output[9:15])
+	}
+	if err := c.Post(gradient, &gradientResp, client.Var("id", matrix.ID), client.Var("colors", []string{"green"})); err == nil {
+		t.Error("Expected an invalid color to be rejected")
+	}
+
+	// Editing a showing matrix releases it
+	var updateResp struct {
+		UpdatePixelMatrix matrixResult `json:"updatePixelMatrix"`
+	}
+	err = c.Post(`mutation($id: ID!) { updatePixelMatrix(id: $id, input: { width: 3 }) { id width isActive } }`, &updateResp, client.Var("id", matrix.ID))
+	if err != nil {
+		t.Fatalf("updatePixelMatrix failed: %v", err)
+	}
+	if updateResp.UpdatePixelMatrix.Width != 3 || updateResp.UpdatePixelMatrix.IsActive {
+		t.Errorf("updatePixelMatrix = %+v", updateResp.UpdatePixelMatrix)
+	}
+	if output := resolver.DMXService.GetUniverse(1); output[10] != 0 {
+		t.Errorf("Expected the released matrix to leave the output, got %v", output[9:15])
+	}
+
+	if err := c.Post(gradient, &gradientResp, client.Var("id", matrix.ID), client.Var("colors", []string{"#FFFFFF"})); err != nil {
+		t.Fatalf("showPixelMatrixGradient failed: %v", err)
+	}
+	var releaseResp struct {
+		ReleasePixelMatrix matrixResult `json:"releasePixelMatrix"`
+	}
+	if err := c.Post(`mutation($id: ID!) { releasePixelMatrix(matrixId: $id) { id isActive } }`, &releaseResp, client.Var("id", matrix.ID)); err != nil {
+		t.Fatalf("releasePixelMatrix failed: %v", err)
+	}
+	if releaseResp.ReleasePixelMatrix.IsActive || resolver.DMXService.GetUniverse(1)[9] != 0 {
+		t.Error("Expected releasePixelMatrix to clear the output")
+	}
+
+	var listResp struct {
+		PixelMatrices []matrixResult `json:"pixelMatrices"`
+	}
+	if err := c.Post(`query { pixelMatrices(projectId: "pixel-project") { id width pixels { x cell } isActive } }`, &listResp); err != nil {
+		t.Fatalf("pixelMatrices failed: %v", err)
+	}
+	if len(listResp.PixelMatrices) != 1 {
+		t.Errorf("Expected 1 pixel matrix, got %d", len(listResp.PixelMatrices))
+	}
+
+	var deleteResp struct {
+		DeletePixelMatrix bool `json:"deletePixelMatrix"`
+	}
+	if err := c.Post(`mutation($id: ID!) { deletePixelMatrix(id: $id) }`, &deleteResp, client.Var("id", matrix.ID)); err != nil || !deleteResp.DeletePixelMatrix {
+		t.Fatalf("deletePixelMatrix failed: %v", err)
+	}
+	if err := c.Post(gradient, &gradientResp, client.Var("id", matrix.ID), client.Var("colors", []string{"#FFFFFF"})); err == nil {
+		t.Error("Expected a deleted matrix to be rejected")
+	}
+}
+
+func TestFixtureCells(t *testing.T) {
+	c, resolver, sink, cleanup := testSetupWithOutput(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-cells", Name: "Cells Project"}
+	resolver.db.Create(project)
+
+	var defResp struct {
+		CreateFixtureDefinition struct {
+			ID    string `json:"id"`
+			Cells []struct {
+				Index    int    `json:"index"`
+				Name     string `json:"name"`
+				Channels []struct {
+					Offset int `json:"offset"`
+				} `json:"channels"`
+			} `json:"cells"`
+		} `json:"createFixtureDefinition"`
+	}
+	err := c.Post(`mutation {
+		createFixtureDefinition(input: {
+			manufacturer: "Test"
+			model: "Batten"
+			type: OTHER
+			channels: [
+				{ name: "Dimmer", type: INTENSITY, offset: 0, minValue: 0, maxValue: 255, defaultValue: 0 }
+				{ name: "Cell 1 Red", type: RED, offset: 1, minValue: 0, maxValue: 255, defaultValue: 0, cell: 0 }
+				{ name: "Cell 1 Blue", type: BLUE, offset: 2, minValue: 0, maxValue: 255, defaultValue: 0, cell: 0 }
+				{ name: "Cell 2 Red", type: RED, offset: 3, minValue: 0, maxValue: 255, defaultValue: 0, cell: 1 }
+				{ name: "Cell 2 Blue", type: BLUE, offset: 4, minValue: 0, maxValue: 255, defaultValue: 0, cell: 1 }
+			]
+		}) { id cells { index name channels { offset } } }
+	}`, &defResp)
+	if err != nil {
+		t.Fatalf("createFixtureDefinition mutation failed: %v", err)
+	}
+	definitionID, cells := defResp.CreateFixtureDefinition.ID, defResp.CreateFixtureDefinition.Cells
+	if len(cells) != 2 || cells[1].Index != 1 || cells[1].Name != "Cell 2" || len(cells[1].Channels) != 2 || cells[1].Channels[0].Offset != 3 {
+		t.Fatalf("Definition cells = %+v, want two cells of two channels", cells)
+	}
+
+	err = c.Post(`mutation {
+		createFixtureDefinition(input: {
+			manufacturer: "Test", model: "Gappy", type: OTHER
+			channels: [{ name: "Red", type: RED, offset: 0, minValue: 0, maxValue: 255, defaultValue: 0, cell: 1 }]
+		}) { id }
+	}`, &defResp)
+	if err == nil {
+		t.Error("Expected error for cells not numbered from 0")
+	}
+
+	var instanceResp struct {
+		CreateFixtureInstance struct {
+			ID    string `json:"id"`
+			Cells []struct {
+				Index    int `json:"index"`
+				Channels []struct {
+					Offset int  `json:"offset"`
+					Cell   *int `json:"cell"`
+				} `json:"channels"`
+			} `json:"cells"`
+			MasterChannels []struct {
+				Offset int `json:"offset"`
+			} `json:"masterChannels"`
+		} `json:"createFixtureInstance"`
+	}
+	err = c.Post(`mutation($projectId: ID!, $defId: ID!) {
+		createFixtureInstance(input: { name: "Batten 1", projectId: $projectId, definitionId: $defId, universe: 1, startChannel: 1 }) {
+			id cells { index channels { offset cell } } masterChannels { offset }
+		}
+	}`, &instanceResp, client.Var("projectId", project.ID), client.Var("defId", definitionID))
+	if err != nil {
+		t.Fatalf("createFixtureInstance mutation failed: %v", err)
+	}
+	instance := instanceResp.CreateFixtureInstance
+	if len(instance.Cells) != 2 || instance.Cells[0].Channels[1].Cell == nil || *instance.Cells[0].Channels[1].Cell != 0 {
+		t.Errorf("Instance cells = %+v, want two cells", instance.Cells)
+	}
+	if len(instance.MasterChannels) != 1 || instance.MasterChannels[0].Offset != 0 {
+		t.Errorf("Master channels = %+v, want the dimmer", instance.MasterChannels)
+	}
+
+	// A cell's color leaves the other cell alone
+	var setResp struct {
+		SetFixtureColor []struct {
+			FixtureID string `json:"fixtureId"`
+		} `json:"setFixtureColor"`
+	}
+	err = c.Post(`mutation($id: ID!) { setFixtureColor(fixtureIds: [$id], color: {hex: "#0000FF"}, cell: 1) { fixtureId } }`,
+		&setResp, client.Var("id", instance.ID))
+	if err != nil {
+		t.Fatalf("setFixtureColor mutation failed: %v", err)
+	}
+	sink.ExpectChannels(t, 1, map[int]byte{2: 0, 3: 0, 4: 0, 5: 255}, 2*time.Second)
+	if len(resolver.DMXService.ProgrammerValues()[1]) != 2 {
+		t.Errorf("Programmer = %v, want only cell 2's channels", resolver.DMXService.ProgrammerValues())
+	}
+	err = c.Post(`mutation($id: ID!) { setFixtureColor(fixtureIds: [$id], color: {hex: "#0000FF"}, cell: 2) { fixtureId } }`,
+		&setResp, client.Var("id", instance.ID))
+	if err == nil {
+		t.Error("Expected error for a cell the fixture does not have")
+	}
+
+	// Cell colors override the fixture's color within their cells
+	var createResp struct {
+		CreateScene struct {
+			ID string `json:"id"`
+		} `json:"createScene"`
+	}
+	err = c.Post(`mutation($input: CreateSceneInput!) { createScene(input: $input) { id } }`, &createResp, client.Var("input", map[string]interface{}{
+		"name": "Split", "projectId": project.ID,
+		"fixtureValues": []map[string]interface{}{{
+			"fixtureId":  instance.ID,
+			"channels":   []map[string]interface{}{{"offset": 0, "value": 255}},
+			"color":      map[string]interface{}{"hex": "#FF0000"},
+			"cellColors": []map[string]interface{}{{"cell": 1, "color": map[string]interface{}{"hex": "#0000FF"}}},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("createScene mutation failed: %v", err)
+	}
+	var fv models.FixtureValue
+	resolver.db.First(&fv, "scene_id = ? AND fixture_id = ?", createResp.CreateScene.ID, instance.ID)
+	var channels []models.ChannelValue
+	if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
+		t.Fatalf("Failed to parse scene channels: %v", err)
+	}
+	want := map[int]int{0: 255, 1: 255, 2: 0, 3: 0, 4: 255}
+	if len(channels) != len(want) {
+		t.Fatalf("Scene channels = %+v, want %v", channels, want)
+	}
+	for _, ch := range channels {
+		if want[ch.Offset] != ch.Value {
+			t.Errorf("Scene channel %d = %d, want %d", ch.Offset, ch.Value, want[ch.Offset])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package resolvers
+
+import (
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmxrecord"
+)
+
+// EnableDMXRecordings keeps recordings of the DMX output in dir.
+func (r *Resolver) EnableDMXRecordings(dir string) {
+	r.DMXRecorder = dmxrecord.NewService(dir, r.DMXService)
+}
+
+// dmxRecorder returns the recording service, or an error when recording is
+// off.
+func (r *Resolver) dmxRecorder() (*dmxrecord.Service, error) {
+	if r.DMXRecorder == nil {
+		return nil, fmt.Errorf("DMX recording is not enabled on this server")
+	}
+	return r.DMXRecorder, nil
+}
+
+// convertDMXRecording converts a recording's description for GraphQL.
+func convertDMXRecording(info dmxrecord.Info) *generated.DmxRecording {
+	return &generated.DmxRecording{
+		Name:      info.Name,
+		Size:      int(info.Size),
+		CreatedAt: info.CreatedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// convertDMXRecordingStatus converts a dmxrecord.Status to
+// generated.DmxRecordingStatus.
+func convertDMXRecordingStatus(status dmxrecord.Status) *generated.DmxRecordingStatus {
+	result := &generated.DmxRecordingStatus{
+		IsRecording:    status.Recording != "",
+		RecordedFrames: status.RecordedFrames,
+		DroppedFrames:  status.DroppedFrames,
+		IsReplaying:    status.Replaying != "",
+		Loop:           status.Loop,
+	}
+	if result.IsRecording {
+		result.Recording = stringPtr(status.Recording)
+		result.RecordingStartedAt = stringPtr(status.RecordingStartedAt.UTC().Format("2006-01-02T15:04:05.000Z"))
+	}
+	if result.IsReplaying {
+		result.Replaying = stringPtr(status.Replaying)
+		result.ReplayStartedAt = stringPtr(status.ReplayStartedAt.UTC().Format("2006-01-02T15:04:05.000Z"))
+	}
+	return result
+}
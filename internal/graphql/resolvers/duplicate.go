@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/patch"
+)
+
+// maxFixtureDuplicates bounds how many copies one duplication makes.
+const maxFixtureDuplicates = 100
+
+// numberedName splits a name like "Par 3" into "Par" and 3.
+var numberedName = regexp.MustCompile(`^(.*?)\s*(\d+)$`)
+
+// duplicateFixture copies a fixture count times, readdressing and renaming
+// each copy and moving it by the layout offsets.
+func (r *Resolver) duplicateFixture(ctx context.Context, id string, count int, offsetX, offsetY float64) ([]*models.FixtureInstance, error) {
+	if count < 1 || count > maxFixtureDuplicates {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxFixtureDuplicates)
+	}
+	original, err := r.FixtureRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, fmt.Errorf("fixture not found: %s", id)
+	}
+	channels, err := r.FixtureRepo.GetInstanceChannels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	projectFixtures, err := r.FixtureRepo.FindByProjectID(ctx, original.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(projectFixtures))
+	patched := make([]patch.Fixture, len(projectFixtures))
+	for i := range projectFixtures {
+		names[projectFixtures[i].Name] = true
+		patched[i] = patch.FromInstance(&projectFixtures[i])
+	}
+
+	// Plan every copy before creating any, so that a fixture with no room
+	// left is not partly duplicated
+	base, number := splitNumberedName(original.Name)
+	previous := patch.FromInstance(original)
+	copies := make([]*models.FixtureInstance, count)
+	for i := range copies {
+		universe, startChannel, ok := patch.NextFreeAddress(patched, previous.Universe, previous.EndChannel()+1, previous.ChannelCount)
+		if !ok {
+			return nil, fmt.Errorf("no free addresses for copy %d of %s", i+1, original.Name)
+		}
+		number++
+		for names[fmt.Sprintf("%s %d", base, number)] {
+			number++
+		}
+		name := fmt.Sprintf("%s %d", base, number)
+		names[name] = true
+
+		duplicate := *original
+		duplicate.ID = ""
+		duplicate.Name = name
+		duplicate.Universe = universe
+		duplicate.StartChannel = startChannel
+		duplicate.ProjectOrder = nil
+		duplicate.Definition = nil
+		duplicate.Channels = nil
+		duplicate.LayoutX = offsetLayout(original.LayoutX, float64(i+1)*offsetX)
+		duplicate.LayoutY = offsetLayout(original.LayoutY, float64(i+1)*offsetY)
+		copies[i] = &duplicate
+
+		previous = patch.FromInstance(&duplicate)
+		patched = append(patched, previous)
+	}
+
+	// Create the copies together, so that a failed one leaves none behind
+	err = r.transaction(ctx, func(txResolver *Resolver) error {
+		for _, duplicate := range copies {
+			duplicateChannels := make([]models.InstanceChannel, len(channels))
+			for i, ch := range channels {
+				ch.ID = ""
+				ch.FixtureID = ""
+				duplicateChannels[i] = ch
+			}
+			if err := txResolver.FixtureRepo.CreateWithChannels(ctx, duplicate, duplicateChannels); err != nil {
+				return err
+			}
+		}
+
+		targets := make([]repositories.UndoTarget, len(copies))
+		for i, duplicate := range copies {
+			targets[i] = undoFixture(duplicate.ID)
+		}
+		txResolver.commitUndoCreate(ctx, original.ProjectID, "Duplicate fixture "+original.Name, targets...)
+		for _, duplicate := range copies {
+			txResolver.publishEntityChange(ctx, generated.EntityChangeActionCreated, duplicate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if original.MaxIntensity != nil {
+		r.refreshOutputLimits(ctx)
+	}
+
+	return copies, nil
+}
+
+// splitNumberedName returns a name without its trailing number, and the
+// number, which is 1 for a name without one.
+func splitNumberedName(name string) (string, int) {
+	name = strings.TrimSpace(name)
+	if match := numberedName.FindStringSubmatch(name); match != nil && match[1] != "" {
+		if number, err := strconv.Atoi(match[2]); err == nil {
+			return match[1], number
+		}
+	}
+	return name, 1
+}
+
+// offsetLayout moves a normalized layout coordinate, keeping it within 0-1.
+// A fixture without a position stays without one.
+func offsetLayout(value *float64, offset float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	moved := min(max(*value+offset, 0), 1)
+	return &moved
+}
@@ -0,0 +1,185 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/effects"
+	"gorm.io/gorm"
+)
+
+// effectParams returns the parameters of a stored effect.
+func effectParams(effect *models.Effect) effects.Params {
+	return effects.Params{
+		Rate:        effect.Rate,
+		Size:        effect.Size,
+		PhaseOffset: effect.PhaseOffset,
+		Order:       effects.Order(effect.Order),
+		Low:         effect.Low,
+		High:        effect.High,
+	}
+}
+
+// effectFixtureIDs decodes a stored effect's fixture list.
+func effectFixtureIDs(effect *models.Effect) ([]string, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(effect.FixtureIDs), &ids); err != nil {
+		return nil, fmt.Errorf("invalid fixture list for effect %s: %w", effect.ID, err)
+	}
+	return ids, nil
+}
+
+// loadEffectFixtures loads the channels an effect can drive on each fixture,
+// in the given order. Every fixture must belong to the project.
+func (r *Resolver) loadEffectFixtures(ctx context.Context, projectID string, ids []string) ([]effects.Fixture, error) {
+	var instances []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Preload("Channels").Where("id IN ? AND project_id = ?", ids, projectID).Find(&instances).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.FixtureInstance, len(instances))
+	for i := range instances {
+		byID[instances[i].ID] = &instances[i]
+	}
+
+	seen := make(map[string]bool, len(ids))
+	fixtures := make([]effects.Fixture, 0, len(ids))
+	for _, id := range ids {
+		instance, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("fixture not found in project: %s", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("fixture listed more than once: %s", id)
+		}
+		seen[id] = true
+
+		fixture := effects.Fixture{ID: id, Universe: instance.Universe, Intensity: intensityLimitChannels(instance)}
+		for _, ch := range instance.Channels {
+			absolute := instance.StartChannel + ch.Offset
+			if absolute < 1 || absolute > 512 {
+				continue
+			}
+			switch ch.Type {
+			case string(generated.ChannelTypeRed):
+				fixture.Red = absolute
+			case string(generated.ChannelTypeGreen):
+				fixture.Green = absolute
+			case string(generated.ChannelTypeBlue):
+				fixture.Blue = absolute
+			}
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// buildEffect resolves a stored effect into one the effects service can run.
+func (r *Resolver) buildEffect(ctx context.Context, effect *models.Effect) (effects.Effect, error) {
+	ids, err := effectFixtureIDs(effect)
+	if err != nil {
+		return effects.Effect{}, err
+	}
+	fixtures, err := r.loadEffectFixtures(ctx, effect.ProjectID, ids)
+	if err != nil {
+		return effects.Effect{}, err
+	}
+	built := effects.Effect{
+		ID:       effect.ID,
+		Type:     effects.Type(effect.Type),
+		Fixtures: fixtures,
+		Params:   effectParams(effect),
+	}
+	if err := built.Validate(); err != nil {
+		return effects.Effect{}, err
+	}
+	return built, nil
+}
+
+// saveEffect validates and stores an effect, then applies it to the running
+// effect and the scene-attached effects.
+func (r *Resolver) saveEffect(ctx context.Context, effect *models.Effect, create bool) error {
+	if effect.SceneID != nil {
+		scene, err := r.SceneRepo.FindByID(ctx, *effect.SceneID)
+		if err != nil {
+			return err
+		}
+		if scene == nil || scene.ProjectID != effect.ProjectID {
+			return fmt.Errorf("scene not found in project: %s", *effect.SceneID)
+		}
+	}
+	built, err := r.buildEffect(ctx, effect)
+	if err != nil {
+		return err
+	}
+
+	if create {
+		err = r.db.WithContext(ctx).Create(effect).Error
+	} else {
+		err = r.db.WithContext(ctx).Save(effect).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.EffectService.IsRunning(effect.ID) {
+		if err := r.EffectService.Start(built); err != nil {
+			return err
+		}
+	}
+	r.refreshSceneEffects(ctx)
+	return nil
+}
+
+// refreshSceneEffects hands every scene-attached effect to the effects
+// service, which runs those of the active scene.
+func (r *Resolver) refreshSceneEffects(ctx context.Context) {
+	var stored []models.Effect
+	if err := r.db.WithContext(ctx).Where("scene_id IS NOT NULL").Find(&stored).Error; err != nil {
+		log.Warn("failed to load scene effects", "error", err)
+		return
+	}
+
+	sceneEffects := make(map[string][]effects.Effect)
+	for i := range stored {
+		effect := &stored[i]
+		built, err := r.buildEffect(ctx, effect)
+		if err != nil {
+			log.Warn("effect cannot run", "effect", effect.ID, "error", err)
+			continue
+		}
+		sceneEffects[*effect.SceneID] = append(sceneEffects[*effect.SceneID], built)
+	}
+	r.EffectService.SetSceneEffects(sceneEffects)
+}
+
+// deleteEffects stops and deletes the effects matching a query.
+func (r *Resolver) deleteEffects(ctx context.Context, query string, args ...interface{}) error {
+	var stored []models.Effect
+	if err := r.db.WithContext(ctx).Where(query, args...).Find(&stored).Error; err != nil {
+		return err
+	}
+	for _, effect := range stored {
+		r.EffectService.Stop(effect.ID)
+	}
+	if err := r.db.WithContext(ctx).Where(query, args...).Delete(&models.Effect{}).Error; err != nil {
+		return err
+	}
+	r.refreshSceneEffects(ctx)
+	return nil
+}
+
+// findEffect loads an effect by ID.
+func (r *Resolver) findEffect(ctx context.Context, id string) (*models.Effect, error) {
+	var effect models.Effect
+	if err := r.db.WithContext(ctx).First(&effect, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("effect not found: %s", id)
+		}
+		return nil, err
+	}
+	return &effect, nil
+}
@@ -0,0 +1,230 @@
+package resolvers
+
+import (
+	"context"
+	"slices"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// publishEntityChange tells subscribers to a project that one of its scenes,
+// fixtures, cue lists, cues or scene boards was created, updated or deleted.
+// Inside a transaction the change waits for the commit.
+func (r *Resolver) publishEntityChange(ctx context.Context, action generated.EntityChangeAction, entity interface{}) {
+	change := &generated.ProjectEntityChange{Action: action}
+	switch e := entity.(type) {
+	case *models.Scene:
+		change.ProjectID, change.EntityType, change.EntityID = e.ProjectID, generated.ProjectEntityTypeScene, e.ID
+		change.Scene = e
+	case *models.FixtureInstance:
+		change.ProjectID, change.EntityType, change.EntityID = e.ProjectID, generated.ProjectEntityTypeFixture, e.ID
+		change.Fixture = e
+	case *models.CueList:
+		change.ProjectID, change.EntityType, change.EntityID = e.ProjectID, generated.ProjectEntityTypeCueList, e.ID
+		change.CueList = e
+	case *models.Cue:
+		projectID, err := r.cueProjectID(ctx, e.CueListID)
+		if err != nil {
+			log.Warn("failed to find the project of a changed cue", "cue", e.ID, "error", err)
+			return
+		}
+		change.ProjectID, change.EntityType, change.EntityID = projectID, generated.ProjectEntityTypeCue, e.ID
+		change.Cue = e
+	case *models.SceneBoard:
+		change.ProjectID, change.EntityType, change.EntityID = e.ProjectID, generated.ProjectEntityTypeSceneBoard, e.ID
+		change.SceneBoard = e
+	default:
+		return
+	}
+	if action == generated.EntityChangeActionDeleted {
+		change.Scene, change.Fixture, change.CueList, change.Cue, change.SceneBoard = nil, nil, nil, nil, nil
+	}
+	r.emitEntityChange(change)
+}
+
+// emitEntityChange sends an entity change, or queues it until the open
+// transaction commits.
+func (r *Resolver) emitEntityChange(change *generated.ProjectEntityChange) {
+	r.publishAfterCommit(pubsub.TopicProjectEntityChanged, change.ProjectID, change)
+}
+
+// pendingEvent is an event published inside a transaction, or with run
+// set, work to do on the committed data.
+type pendingEvent struct {
+	topic   pubsub.Topic
+	filter  string
+	message interface{}
+	run     func(r *Resolver)
+}
+
+// publishAfterCommit publishes an event, or queues it until the open
+// transaction commits.
+func (r *Resolver) publishAfterCommit(topic pubsub.Topic, filter string, message interface{}) {
+	if r.pendingEvents != nil {
+		*r.pendingEvents = append(*r.pendingEvents, pendingEvent{topic: topic, filter: filter, message: message})
+		return
+	}
+	r.PubSub.Publish(topic, filter, message)
+}
+
+// findEntity loads a record by type and ID, returning nil if it doesn't
+// exist.
+func (r *Resolver) findEntity(ctx context.Context, entityType generated.ProjectEntityType, id string) (interface{}, error) {
+	switch entityType {
+	case generated.ProjectEntityTypeScene:
+		scene, err := r.SceneRepo.FindByID(ctx, id)
+		if scene == nil {
+			return nil, err
+		}
+		return scene, nil
+	case generated.ProjectEntityTypeFixture:
+		fixture, err := r.FixtureRepo.FindByID(ctx, id)
+		if fixture == nil {
+			return nil, err
+		}
+		return fixture, nil
+	case generated.ProjectEntityTypeCueList:
+		cueList, err := r.CueListRepo.FindByID(ctx, id)
+		if cueList == nil {
+			return nil, err
+		}
+		return cueList, nil
+	case generated.ProjectEntityTypeCue:
+		cue, err := r.CueRepo.FindByID(ctx, id)
+		if cue == nil {
+			return nil, err
+		}
+		return cue, nil
+	case generated.ProjectEntityTypeSceneBoard:
+		board, err := r.SceneBoardRepo.FindByID(ctx, id)
+		if board == nil {
+			return nil, err
+		}
+		return board, nil
+	}
+	return nil, nil
+}
+
+// publishEntityUpdated loads a record changed by ID and publishes it as
+// updated. A record that no longer exists is left out.
+func (r *Resolver) publishEntityUpdated(ctx context.Context, entityType generated.ProjectEntityType, id string) {
+	entity, err := r.findEntity(ctx, entityType, id)
+	if err != nil {
+		log.Warn("failed to load a changed record", "type", entityType, "id", id, "error", err)
+		return
+	}
+	if entity != nil {
+		r.publishLoadedEntity(ctx, entity)
+	}
+}
+
+// publishLoadedEntity publishes a record loaded by findEntity as updated.
+func (r *Resolver) publishLoadedEntity(ctx context.Context, entity interface{}) {
+	if scene, ok := entity.(*models.Scene); ok {
+		r.publishSceneUpdated(ctx, scene)
+		return
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, entity)
+}
+
+// projectEntities loads a project's scenes, fixtures, cue lists, cues and
+// scene boards, by ID.
+func (r *Resolver) projectEntities(ctx context.Context, projectID string) (map[string]interface{}, error) {
+	db := r.db.WithContext(ctx)
+	var scenes []models.Scene
+	var fixtures []models.FixtureInstance
+	var cueLists []models.CueList
+	var cues []models.Cue
+	var boards []models.SceneBoard
+	for _, find := range []func() error{
+		func() error { return db.Where("project_id = ?", projectID).Find(&scenes).Error },
+		func() error { return db.Where("project_id = ?", projectID).Find(&fixtures).Error },
+		func() error { return db.Where("project_id = ?", projectID).Find(&cueLists).Error },
+		func() error {
+			return db.Where("cue_list_id IN (?)", db.Model(&models.CueList{}).Select("id").Where("project_id = ?", projectID)).Find(&cues).Error
+		},
+		func() error { return db.Where("project_id = ?", projectID).Find(&boards).Error },
+	} {
+		if err := find(); err != nil {
+			return nil, err
+		}
+	}
+
+	entities := make(map[string]interface{})
+	for i := range scenes {
+		entities[scenes[i].ID] = &scenes[i]
+	}
+	for i := range fixtures {
+		entities[fixtures[i].ID] = &fixtures[i]
+	}
+	for i := range cueLists {
+		entities[cueLists[i].ID] = &cueLists[i]
+	}
+	for i := range cues {
+		entities[cues[i].ID] = &cues[i]
+	}
+	for i := range boards {
+		entities[boards[i].ID] = &boards[i]
+	}
+	return entities, nil
+}
+
+// publishCreatedEntities publishes the records of a project that are not
+// among existing, as loaded by projectEntities, as created. Imports and
+// restores, which create records outside the resolvers, call it once they
+// commit.
+func (r *Resolver) publishCreatedEntities(ctx context.Context, projectID string, existing map[string]interface{}) {
+	entities, err := r.projectEntities(ctx, projectID)
+	if err != nil {
+		log.Warn("failed to load the records of a changed project", "project", projectID, "error", err)
+		return
+	}
+	for id, entity := range entities {
+		if _, ok := existing[id]; !ok {
+			r.publishEntityChange(ctx, generated.EntityChangeActionCreated, entity)
+		}
+	}
+}
+
+// undoEntityTypes maps the kinds of undo target to the entity types they
+// restore.
+var undoEntityTypes = map[repositories.UndoTargetKind]generated.ProjectEntityType{
+	repositories.UndoTargetScene:   generated.ProjectEntityTypeScene,
+	repositories.UndoTargetFixture: generated.ProjectEntityTypeFixture,
+	repositories.UndoTargetCue:     generated.ProjectEntityTypeCue,
+}
+
+// publishUndoneEntities publishes the records an undo or redo restored: as
+// updated if they exist afterwards, as deleted if not.
+func (r *Resolver) publishUndoneEntities(ctx context.Context, projectID string, targets []repositories.UndoTarget) {
+	for _, t := range targets {
+		entityType, ok := undoEntityTypes[t.Kind]
+		if !ok {
+			continue
+		}
+		entity, err := r.findEntity(ctx, entityType, t.ID)
+		if err != nil {
+			log.Warn("failed to load a restored record", "type", entityType, "id", t.ID, "error", err)
+			continue
+		}
+		if entity == nil {
+			r.emitEntityChange(&generated.ProjectEntityChange{
+				ProjectID:  projectID,
+				EntityType: entityType,
+				Action:     generated.EntityChangeActionDeleted,
+				EntityID:   t.ID,
+			})
+			continue
+		}
+		r.publishLoadedEntity(ctx, entity)
+	}
+}
+
+// wantsEntityChange reports whether a subscriber asking for entityTypes, or
+// all types if none, wants a change.
+func wantsEntityChange(entityTypes []generated.ProjectEntityType, change *generated.ProjectEntityChange) bool {
+	return len(entityTypes) == 0 || slices.Contains(entityTypes, change.EntityType)
+}
@@ -0,0 +1,160 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/input"
+)
+
+// wireFaderWing registers the controls fader wing channels can drive.
+func (r *Resolver) wireFaderWing() {
+	r.InputService.SetIgnoreSource(r.DMXService.IsOwnPacket)
+	r.InputService.SetArtNetCallback(r.StandbyService.ArtNetReceived)
+	r.InputService.SetHandler(input.TargetSceneBoardButton, r.faderWingButtonHandler())
+	r.InputService.SetHandler(input.TargetGrandMaster, func(_ string, level float64) {
+		if _, err := r.setMasterLevel(nil, level); err != nil {
+			log.Warn("fader wing grand master", "error", err)
+		}
+	})
+}
+
+// faderWingButtonHandler returns a handler that sets a scene board button's
+// scene to the fader level. Scenes are cached per button so fader moves do
+// not hit the database; the cache reloads each time a fader leaves zero, so
+// scene edits apply from the next time it is raised.
+func (r *Resolver) faderWingButtonHandler() input.Handler {
+	type buttonScene struct {
+		sceneID  string
+		channels []fade.SceneChannel
+		level    float64
+	}
+	var mu sync.Mutex
+	buttons := make(map[string]*buttonScene)
+
+	return func(buttonID string, level float64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		cached := buttons[buttonID]
+		if cached == nil || (cached.level == 0 && level > 0) {
+			ctx := context.Background()
+			var button models.SceneBoardButton
+			if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+				log.Warn("fader wing scene board button failed", "button", buttonID, "error", err)
+				return
+			}
+			channels, err := r.loadSceneChannels(ctx, button.SceneID)
+			if err != nil {
+				log.Warn("fader wing scene failed", "scene", button.SceneID, "error", err)
+				return
+			}
+			cached = &buttonScene{sceneID: button.SceneID, channels: channels}
+			buttons[buttonID] = cached
+		}
+
+		cached.level = level
+		r.HoldService.SetLevel(buttonID, cached.sceneID, cached.channels, level)
+		if level > 0 {
+			r.DMXService.SetActiveScene(cached.sceneID)
+		}
+	}
+}
+
+// loadFaderWingConfig applies the saved fader wing configuration, if any.
+func (r *Resolver) loadFaderWingConfig(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, input.SettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var config input.Config
+	if err := json.Unmarshal([]byte(setting.Value), &config); err != nil {
+		log.Warn("invalid saved fader wing configuration", "error", err)
+		return
+	}
+	if _, err := r.InputService.SetConfig(config); err != nil {
+		log.Warn("invalid saved fader wing configuration", "error", err)
+	}
+}
+
+// updateFaderWingConfig validates, saves, and applies a fader wing mapping table.
+func (r *Resolver) updateFaderWingConfig(ctx context.Context, in generated.FaderWingConfigInput) (*generated.FaderWingStatus, error) {
+	config := input.Config{
+		Enabled:  in.Enabled,
+		Mappings: make([]input.Mapping, 0, len(in.Mappings)),
+	}
+	for i, m := range in.Mappings {
+		mapping := input.Mapping{
+			Universe: m.Universe,
+			Channel:  m.Channel,
+			Target:   input.Target(m.Target),
+		}
+		if id := m.TargetID.Value(); id != nil {
+			mapping.TargetID = *id
+		}
+		if mapping.Target == input.TargetSceneBoardButton && mapping.TargetID != "" {
+			var count int64
+			r.db.WithContext(ctx).Model(&models.SceneBoardButton{}).Where("id = ?", mapping.TargetID).Count(&count)
+			if count == 0 {
+				return nil, fmt.Errorf("mapping %d: scene board button not found: %s", i+1, mapping.TargetID)
+			}
+		}
+		config.Mappings = append(config.Mappings, mapping)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Apply before saving so targets this server cannot drive are rejected
+	status, err := r.InputService.SetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, input.SettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save fader wing configuration: %w", err)
+	}
+	return convertFaderWingStatus(status), nil
+}
+
+// convertFaderWingStatus converts an input.Status to generated.FaderWingStatus.
+func convertFaderWingStatus(status *input.Status) *generated.FaderWingStatus {
+	result := &generated.FaderWingStatus{
+		Enabled:          status.Config.Enabled,
+		Mappings:         make([]*generated.FaderWingMapping, 0, len(status.Config.Mappings)),
+		Listening:        status.Listening,
+		AvailableTargets: make([]generated.FaderWingTarget, 0, len(status.AvailableTargets)),
+		PacketsReceived:  status.PacketsReceived,
+		LastSource:       status.LastSource,
+	}
+	for _, m := range status.Config.Mappings {
+		mapping := &generated.FaderWingMapping{
+			Universe: m.Universe,
+			Channel:  m.Channel,
+			Target:   generated.FaderWingTarget(m.Target),
+		}
+		if m.TargetID != "" {
+			targetID := m.TargetID
+			mapping.TargetID = &targetID
+		}
+		result.Mappings = append(result.Mappings, mapping)
+	}
+	for _, target := range status.AvailableTargets {
+		result.AvailableTargets = append(result.AvailableTargets, generated.FaderWingTarget(target))
+	}
+	if status.LastPacketAt != nil {
+		lastPacketAt := status.LastPacketAt.Format("2006-01-02T15:04:05.000Z")
+		result.LastPacketAt = &lastPacketAt
+	}
+	return result
+}
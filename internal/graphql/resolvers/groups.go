@@ -0,0 +1,151 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// findFixtureGroup loads a fixture group by ID.
+func (r *Resolver) findFixtureGroup(ctx context.Context, id string) (*models.FixtureGroup, error) {
+	group, err := r.FixtureGroupRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("fixture group not found: %s", id)
+	}
+	return group, nil
+}
+
+// setGroupFixtures validates and stores a group's members.
+func (r *Resolver) setGroupFixtures(ctx context.Context, group *models.FixtureGroup, fixtureIDs []string) error {
+	if fixtureIDs == nil {
+		fixtureIDs = []string{}
+	}
+	if err := r.validateProjectFixtures(ctx, group.ProjectID, fixtureIDs); err != nil {
+		return err
+	}
+	fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+	if err != nil {
+		return err
+	}
+	group.FixtureIDs = string(fixtureIDsJSON)
+	return nil
+}
+
+// validateProjectFixtures checks that IDs name distinct fixtures of a project.
+func (r *Resolver) validateProjectFixtures(ctx context.Context, projectID string, fixtureIDs []string) error {
+	if len(fixtureIDs) == 0 {
+		return nil
+	}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.FixtureInstance{}).Where("id IN ? AND project_id = ?", fixtureIDs, projectID).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(fixtureIDs) {
+		return fmt.Errorf("fixtures must be distinct fixtures of the project")
+	}
+	return nil
+}
+
+// saveSceneGroupValues replaces a scene's group values and rederives its
+// fixture values from them.
+func (r *Resolver) saveSceneGroupValues(ctx context.Context, scene *models.Scene, inputs []*generated.GroupValueInput) error {
+	values := make([]models.GroupValue, 0, len(inputs))
+	for _, input := range inputs {
+		group, err := r.findFixtureGroup(ctx, input.GroupID)
+		if err != nil {
+			return err
+		}
+		if group.ProjectID != scene.ProjectID {
+			return fmt.Errorf("fixture group not found in project: %s", input.GroupID)
+		}
+
+		channelsJSON, err := serializeTypedChannels(input.Channels)
+		if err != nil {
+			return fmt.Errorf("group %s: %w", input.GroupID, err)
+		}
+		values = append(values, models.GroupValue{GroupID: group.ID, Channels: string(channelsJSON)})
+	}
+
+	if err := r.SceneRepo.ReplaceGroupValues(ctx, scene.ID, values); err != nil {
+		return err
+	}
+	return r.SceneRepo.SyncGroupValues(ctx, scene.ID)
+}
+
+// syncGroupScenes rederives the fixture values of scenes with group values
+// after a group changes, updating any of them that are live.
+func (r *Resolver) syncGroupScenes(ctx context.Context, sceneIDs []string) error {
+	for _, sceneID := range sceneIDs {
+		if err := r.SceneRepo.SyncGroupValues(ctx, sceneID); err != nil {
+			return err
+		}
+		if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
+			log.Warn("failed to re-apply active scene after group change", "error", err)
+		}
+	}
+	if len(sceneIDs) > 0 {
+		r.refreshSubmasters(ctx)
+	}
+	return nil
+}
+
+// removeFixtureFromGroups drops a deleted fixture from the groups it was in.
+func (r *Resolver) removeFixtureFromGroups(ctx context.Context, fixtureID string) error {
+	groups, err := r.FixtureGroupRepo.FindByFixtureID(ctx, fixtureID)
+	if err != nil {
+		return err
+	}
+
+	var sceneIDs []string
+	for i := range groups {
+		group := &groups[i]
+		ids, err := repositories.GroupFixtureIDs(group)
+		if err != nil {
+			return err
+		}
+		kept := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if id != fixtureID {
+				kept = append(kept, id)
+			}
+		}
+		fixtureIDsJSON, err := json.Marshal(kept)
+		if err != nil {
+			return err
+		}
+		group.FixtureIDs = string(fixtureIDsJSON)
+		if err := r.FixtureGroupRepo.Update(ctx, group); err != nil {
+			return err
+		}
+
+		groupSceneIDs, err := r.SceneRepo.FindSceneIDsByGroupID(ctx, group.ID)
+		if err != nil {
+			return err
+		}
+		sceneIDs = append(sceneIDs, groupSceneIDs...)
+	}
+	return r.syncGroupScenes(ctx, sceneIDs)
+}
+
+// copySceneGroupValues gives a copied scene the group values of the original.
+func (r *Resolver) copySceneGroupValues(ctx context.Context, fromSceneID, toSceneID string) error {
+	values, err := r.SceneRepo.GetGroupValues(ctx, fromSceneID)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	copies := make([]models.GroupValue, len(values))
+	for i, v := range values {
+		copies[i] = models.GroupValue{GroupID: v.GroupID, Channels: v.Channels}
+	}
+	return r.SceneRepo.ReplaceGroupValues(ctx, toSceneID, copies)
+}
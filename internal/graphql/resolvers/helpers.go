@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
 )
 
 // Helper function to convert int to *int
@@ -15,6 +17,42 @@ func intPtr(i int) *int {
 	return &i
 }
 
+// setFadeTime applies an optional fade time input, rejecting negative times.
+func setFadeTime(dst **float64, field string, input graphql.Omittable[*float64]) error {
+	if !input.IsSet() {
+		return nil
+	}
+	if value := input.Value(); value != nil && *value < 0 {
+		return fmt.Errorf("%s must not be negative", field)
+	}
+	*dst = input.Value()
+	return nil
+}
+
+// validateFadeOverrides rejects negative fade times given at GO time.
+func validateFadeOverrides(fadeInTime, fadeOutTime *float64) error {
+	if fadeInTime != nil && *fadeInTime < 0 {
+		return fmt.Errorf("fadeInTime must not be negative")
+	}
+	if fadeOutTime != nil && *fadeOutTime < 0 {
+		return fmt.Errorf("fadeOutTime must not be negative")
+	}
+	return nil
+}
+
+// setFlashLevel applies an optional flash level input, rejecting levels
+// outside 0-1.
+func setFlashLevel(dst **float64, input graphql.Omittable[*float64]) error {
+	if !input.IsSet() {
+		return nil
+	}
+	if value := input.Value(); value != nil && (*value < 0 || *value > 1) {
+		return fmt.Errorf("flashLevel must be between 0 and 1")
+	}
+	*dst = input.Value()
+	return nil
+}
+
 // Helper function to convert string to *string
 func stringPtr(s string) *string {
 	return &s
@@ -67,6 +105,80 @@ func serializeSparseChannels(channels []*generated.ChannelValueInput) (string, e
 	return string(jsonData), nil
 }
 
+// mergeSparseChannels sets channel values over stored sparse channels,
+// keeping the stored channels that changes leaves out.
+func mergeSparseChannels(channelsJSON string, changes []models.ChannelValue) (string, error) {
+	var stored []models.ChannelValue
+	if channelsJSON != "" {
+		if err := json.Unmarshal([]byte(channelsJSON), &stored); err != nil {
+			return "", fmt.Errorf("failed to deserialize channels: %w", err)
+		}
+	}
+	values := make(map[int]int, len(stored)+len(changes))
+	for _, ch := range stored {
+		values[ch.Offset] = ch.Value
+	}
+	for _, ch := range changes {
+		values[ch.Offset] = ch.Value
+	}
+
+	merged := make([]models.ChannelValue, 0, len(values))
+	for offset, value := range values {
+		merged = append(merged, models.ChannelValue{Offset: offset, Value: value})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Offset < merged[j].Offset })
+	jsonData, err := json.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize channels: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// sparseChannelValues converts channel value inputs to stored values.
+func sparseChannelValues(channels []*generated.ChannelValueInput) []models.ChannelValue {
+	values := make([]models.ChannelValue, len(channels))
+	for i, ch := range channels {
+		values[i] = models.ChannelValue{Offset: ch.Offset, Value: ch.Value}
+	}
+	return values
+}
+
+// serializeTypedChannels validates channel values given by channel type and
+// converts them to JSON for storage.
+func serializeTypedChannels(channels []*generated.TypedChannelValueInput) (string, error) {
+	seen := make(map[generated.ChannelType]bool)
+	values := make([]models.ChannelTypeValue, 0, len(channels))
+	for _, ch := range channels {
+		if ch.Value < 0 || ch.Value > 255 {
+			return "", fmt.Errorf("invalid DMX value %d for %s: must be 0-255", ch.Value, ch.Type)
+		}
+		if seen[ch.Type] {
+			return "", fmt.Errorf("duplicate channel type %s found in input", ch.Type)
+		}
+		seen[ch.Type] = true
+		values = append(values, models.ChannelTypeValue{Type: string(ch.Type), Value: ch.Value})
+	}
+
+	jsonData, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize channels: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// typedChannelValues decodes stored channel values given by channel type.
+func typedChannelValues(channelsJSON string) ([]*generated.TypedChannelValue, error) {
+	var channels []models.ChannelTypeValue
+	if err := json.Unmarshal([]byte(channelsJSON), &channels); err != nil {
+		return nil, fmt.Errorf("failed to deserialize channels: %w", err)
+	}
+	result := make([]*generated.TypedChannelValue, len(channels))
+	for i, ch := range channels {
+		result[i] = &generated.TypedChannelValue{Type: generated.ChannelType(ch.Type), Value: ch.Value}
+	}
+	return result, nil
+}
+
 // sparseChannelsToDenseArray converts sparse channel JSON to a dense int array.
 // Used for backward-compatible output like CompareScenes.
 // The resulting array is sized to (maxOffset + 1), which is bounded by DMX constraints
@@ -102,8 +214,7 @@ func sparseChannelsToDenseArray(channelsJSON string) []int {
 // DMX channels are 1-512 per universe
 func validateDMXChannel(dmxChannel, universe int, fixtureID string, offset int) bool {
 	if dmxChannel < 1 || dmxChannel > 512 {
-		log.Printf("Warning: DMX channel %d out of bounds for fixture %s (universe %d, offset %d). Skipping.",
-			dmxChannel, fixtureID, universe, offset)
+		log.Warn("DMX channel out of bounds; skipping", "channel", dmxChannel, "fixture", fixtureID, "universe", universe, "offset", offset)
 		return false
 	}
 	return true
@@ -116,11 +227,11 @@ func sparseChannelsEqual(channelsJSON1, channelsJSON2 string) bool {
 	var sparse1, sparse2 []models.ChannelValue
 
 	if err := json.Unmarshal([]byte(channelsJSON1), &sparse1); err != nil {
-		log.Printf("Warning: sparseChannelsEqual received invalid JSON for channels1: %v", err)
+		log.Warn("sparseChannelsEqual received invalid JSON for channels1", "error", err)
 		return false
 	}
 	if err := json.Unmarshal([]byte(channelsJSON2), &sparse2); err != nil {
-		log.Printf("Warning: sparseChannelsEqual received invalid JSON for channels2: %v", err)
+		log.Warn("sparseChannelsEqual received invalid JSON for channels2", "error", err)
 		return false
 	}
 
@@ -128,7 +239,7 @@ func sparseChannelsEqual(channelsJSON1, channelsJSON2 string) bool {
 	map1 := make(map[int]int, len(sparse1))
 	for _, ch := range sparse1 {
 		if _, exists := map1[ch.Offset]; exists {
-			log.Printf("Warning: sparseChannelsEqual detected duplicate offset %d in channels1", ch.Offset)
+			log.Warn("sparseChannelsEqual detected a duplicate offset in channels1", "offset", ch.Offset)
 			return false
 		}
 		map1[ch.Offset] = ch.Value
@@ -137,7 +248,7 @@ func sparseChannelsEqual(channelsJSON1, channelsJSON2 string) bool {
 	map2 := make(map[int]int, len(sparse2))
 	for _, ch := range sparse2 {
 		if _, exists := map2[ch.Offset]; exists {
-			log.Printf("Warning: sparseChannelsEqual detected duplicate offset %d in channels2", ch.Offset)
+			log.Warn("sparseChannelsEqual detected a duplicate offset in channels2", "offset", ch.Offset)
 			return false
 		}
 		map2[ch.Offset] = ch.Value
@@ -215,7 +326,7 @@ func (r *Resolver) reapplyActiveSceneIfNeeded(ctx context.Context, sceneID strin
 		// Parse sparse channel values from JSON
 		var channels []models.ChannelValue
 		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
-			log.Printf("Warning: failed to unmarshal channels for fixtureID %s in sceneID %s: %v", fixtureValue.FixtureID, sceneID, err)
+			log.Warn("failed to unmarshal fixture channels", "fixture", fixtureValue.FixtureID, "scene", sceneID, "error", err)
 			continue
 		}
 
@@ -235,6 +346,81 @@ func (r *Resolver) reapplyActiveSceneIfNeeded(ctx context.Context, sceneID strin
 	// Force immediate transmission
 	r.DMXService.TriggerChangeDetection()
 
-	log.Printf("Re-applied active scene %s after update", sceneID)
+	log.Debug("re-applied active scene after update", "scene", sceneID)
 	return nil
 }
+
+// loadSceneChannels loads a scene's fixture values and resolves them to absolute
+// DMX channels, carrying each channel's fade behavior from the fixture instance.
+func (r *Resolver) loadSceneChannels(ctx context.Context, sceneID string) ([]fade.SceneChannel, error) {
+	var scene models.Scene
+	result := r.db.WithContext(ctx).Preload("FixtureValues").First(&scene, "id = ?", sceneID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Load fixtures for the scene's fixture values
+	var fixtureIDs []string
+	for _, fv := range scene.FixtureValues {
+		fixtureIDs = append(fixtureIDs, fv.FixtureID)
+	}
+
+	var fixtures []models.FixtureInstance
+	if len(fixtureIDs) > 0 {
+		// Load fixtures with their channels to get fadeBehavior
+		r.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures)
+	}
+
+	// Create fixture lookup map
+	fixtureMap := make(map[string]*models.FixtureInstance)
+	for i := range fixtures {
+		fixtureMap[fixtures[i].ID] = &fixtures[i]
+	}
+
+	var sceneChannels []fade.SceneChannel
+	for _, fixtureValue := range scene.FixtureValues {
+		fixture := fixtureMap[fixtureValue.FixtureID]
+		if fixture == nil {
+			continue
+		}
+
+		// Parse sparse channel values from JSON (Channels field)
+		var channels []models.ChannelValue
+		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
+			log.Warn("failed to unmarshal fixture channels", "fixture", fixtureValue.FixtureID, "scene", sceneID, "error", err)
+			continue
+		}
+
+		// Build a map of channel offset -> fade behavior for efficient lookup
+		fadeBehaviorMap := make(map[int]string)
+		for _, chanDef := range fixture.Channels {
+			if chanDef.FadeBehavior != "" {
+				fadeBehaviorMap[chanDef.Offset] = chanDef.FadeBehavior
+			}
+		}
+
+		// Only process channels that exist in the sparse array
+		for _, ch := range channels {
+			dmxChannel := fixture.StartChannel + ch.Offset
+
+			// Validate DMX channel is within bounds
+			if !validateDMXChannel(dmxChannel, fixture.Universe, fixture.ID, ch.Offset) {
+				continue
+			}
+
+			fadeBehavior := fade.FadeBehaviorFade // Default to FADE
+			if fb, ok := fadeBehaviorMap[ch.Offset]; ok {
+				fadeBehavior = fb
+			}
+
+			sceneChannels = append(sceneChannels, fade.SceneChannel{
+				Universe:     fixture.Universe,
+				Channel:      dmxChannel,
+				Value:        ch.Value,
+				FadeBehavior: fadeBehavior,
+			})
+		}
+	}
+
+	return sceneChannels, nil
+}
@@ -483,3 +483,43 @@ func TestReapplyActiveSceneIfNeeded_InvalidChannelJSON(t *testing.T) {
 		t.Errorf("Expected function to succeed despite invalid JSON (with warning logged), got: %v", err)
 	}
 }
+
+func TestMergeSparseChannels(t *testing.T) {
+	merged, err := mergeSparseChannels(`[{"offset":2,"value":30},{"offset":0,"value":10}]`,
+		[]models.ChannelValue{{Offset: 2, Value: 99}, {Offset: 1, Value: 5}})
+	if err != nil {
+		t.Fatalf("mergeSparseChannels() error: %v", err)
+	}
+	if want := `[{"offset":0,"value":10},{"offset":1,"value":5},{"offset":2,"value":99}]`; merged != want {
+		t.Errorf("mergeSparseChannels() = %s, want %s", merged, want)
+	}
+
+	if _, err := mergeSparseChannels("not json", nil); err == nil {
+		t.Error("Expected an error for invalid stored channels")
+	}
+}
+
+func TestCueNumberBetween(t *testing.T) {
+	f := func(n float64) *float64 { return &n }
+	tests := []struct {
+		after  float64
+		before *float64
+		want   float64
+		ok     bool
+	}{
+		{2, f(3), 2.5, true},
+		{1, f(5), 3, true},
+		{2, f(2.5), 2.3, true},
+		{2.5, f(3), 2.8, true},
+		{0, f(1), 0.5, true},
+		{2.5, nil, 3, true},
+		{4, nil, 5, true},
+		{2.001, f(2.002), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := cueNumberBetween(tt.after, tt.before)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("cueNumberBetween(%v, %v) = %v, %v; want %v, %v", tt.after, tt.before, got, ok, tt.want, tt.ok)
+		}
+	}
+}
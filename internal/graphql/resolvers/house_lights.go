@@ -0,0 +1,261 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/houselights"
+)
+
+// maxHouseLightsFade bounds the fade time of house lights.
+const maxHouseLightsFade = 60 * time.Second
+
+// findHouseLights loads a project's house lights, or unsaved defaults when
+// they have not been chosen.
+func (r *Resolver) findHouseLights(ctx context.Context, projectID string) (*models.HouseLights, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	lights, err := r.HouseLightsRepo.FindByProjectID(ctx, projectID)
+	if err != nil || lights != nil {
+		return lights, err
+	}
+	return &models.HouseLights{
+		ProjectID:  projectID,
+		FixtureIDs: "[]",
+		GroupIDs:   "[]",
+		FullLevel:  houselights.DefaultLevels.Full,
+		HalfLevel:  houselights.DefaultLevels.Half,
+		ShowLevel:  houselights.DefaultLevels.Show,
+		FadeTime:   houselights.DefaultFadeTime.Seconds(),
+	}, nil
+}
+
+// updateHouseLights chooses a project's house lights and their levels.
+// Engaged house lights move to the new fixtures and levels at once.
+func (r *Resolver) updateHouseLights(ctx context.Context, projectID string, input generated.UpdateHouseLightsInput) (*models.HouseLights, error) {
+	lights, err := r.findHouseLights(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ids := input.FixtureIds.Value(); ids != nil {
+		if err := r.validateProjectFixtures(ctx, projectID, ids); err != nil {
+			return nil, err
+		}
+		idsJSON, err := json.Marshal(ids)
+		if err != nil {
+			return nil, err
+		}
+		lights.FixtureIDs = string(idsJSON)
+	}
+	if ids := input.GroupIds.Value(); ids != nil {
+		if err := r.validateProjectGroups(ctx, projectID, ids); err != nil {
+			return nil, err
+		}
+		idsJSON, err := json.Marshal(ids)
+		if err != nil {
+			return nil, err
+		}
+		lights.GroupIDs = string(idsJSON)
+	}
+	for _, level := range []struct {
+		name  string
+		value *float64
+		field *float64
+	}{
+		{"fullLevel", input.FullLevel.Value(), &lights.FullLevel},
+		{"halfLevel", input.HalfLevel.Value(), &lights.HalfLevel},
+		{"showLevel", input.ShowLevel.Value(), &lights.ShowLevel},
+	} {
+		if level.value == nil {
+			continue
+		}
+		if math.IsNaN(*level.value) || *level.value < 0 || *level.value > 1 {
+			return nil, fmt.Errorf("%s must be between 0 and 1", level.name)
+		}
+		*level.field = *level.value
+	}
+	if v := input.FadeTime.Value(); v != nil {
+		if _, err := houseLightsFade(v); err != nil {
+			return nil, err
+		}
+		lights.FadeTime = *v
+	}
+
+	if err := r.HouseLightsRepo.Save(ctx, lights); err != nil {
+		return nil, err
+	}
+
+	if status := r.HouseLightsService.Status(projectID); status.Engaged {
+		channels, err := r.houseLightsChannels(ctx, lights)
+		if err != nil {
+			return nil, err
+		}
+		level, err := houseLightsLevels(lights).Level(status.Preset)
+		if err != nil {
+			return nil, err
+		}
+		r.HouseLightsService.Retarget(projectID, channels, level)
+	}
+	return lights, nil
+}
+
+// setHouseLights fades a project's house lights to a preset.
+func (r *Resolver) setHouseLights(ctx context.Context, projectID string, preset generated.HouseLightsPreset, fadeTime *float64) (*generated.HouseLightsStatus, error) {
+	lights, err := r.findHouseLights(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if fadeTime == nil {
+		fadeTime = &lights.FadeTime
+	}
+	fade, err := houseLightsFade(fadeTime)
+	if err != nil {
+		return nil, err
+	}
+	level, err := houseLightsLevels(lights).Level(houselights.Preset(preset))
+	if err != nil {
+		return nil, err
+	}
+	channels, err := r.houseLightsChannels(ctx, lights)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no house lights are chosen for project %s", projectID)
+	}
+
+	status := r.HouseLightsService.Set(projectID, channels, houselights.Preset(preset), level, fade)
+	return convertHouseLightsStatus(status), nil
+}
+
+// houseLightsFade converts a fade time in seconds, checking its range.
+func houseLightsFade(seconds *float64) (time.Duration, error) {
+	fade := time.Duration(*seconds * float64(time.Second))
+	if math.IsNaN(*seconds) || fade < 0 || fade > maxHouseLightsFade {
+		return 0, fmt.Errorf("fade time must be between 0 and %v", maxHouseLightsFade)
+	}
+	return fade, nil
+}
+
+// houseLightsLevels returns the preset levels of house lights.
+func houseLightsLevels(lights *models.HouseLights) houselights.Levels {
+	return houselights.Levels{Full: lights.FullLevel, Half: lights.HalfLevel, Show: lights.ShowLevel}
+}
+
+// houseLightsFixtures returns the fixtures of house lights, chosen directly
+// or through their groups, skipping any since deleted.
+func (r *Resolver) houseLightsFixtures(ctx context.Context, lights *models.HouseLights) ([]*models.FixtureInstance, error) {
+	fixtureIDs, _, err := repositories.HouseLightsTargets(lights)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := r.houseLightsGroups(ctx, lights)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		members, err := repositories.GroupFixtureIDs(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range members {
+			if !slices.Contains(fixtureIDs, id) {
+				fixtureIDs = append(fixtureIDs, id)
+			}
+		}
+	}
+	return r.orderedFixtures(ctx, fixtureIDs)
+}
+
+// houseLightsGroups returns the groups of house lights, skipping any since
+// deleted.
+func (r *Resolver) houseLightsGroups(ctx context.Context, lights *models.HouseLights) ([]*models.FixtureGroup, error) {
+	_, groupIDs, err := repositories.HouseLightsTargets(lights)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*models.FixtureGroup, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		group, err := r.FixtureGroupRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// houseLightsChannels returns the DMX channels that set the level of house
+// lights' fixtures.
+func (r *Resolver) houseLightsChannels(ctx context.Context, lights *models.HouseLights) ([]houselights.Channel, error) {
+	fixtures, err := r.houseLightsFixtures(ctx, lights)
+	if err != nil || len(fixtures) == 0 {
+		return nil, err
+	}
+	instanceChannels, err := r.FixtureRepo.GetProjectInstanceChannels(ctx, lights.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	channelsByFixture := make(map[string][]houselights.FixtureChannel)
+	for _, c := range instanceChannels {
+		channelsByFixture[c.FixtureID] = append(channelsByFixture[c.FixtureID], houselights.FixtureChannel{Offset: c.Offset, Type: c.Type})
+	}
+
+	houseFixtures := make([]houselights.Fixture, len(fixtures))
+	for i, fixture := range fixtures {
+		houseFixtures[i] = houselights.Fixture{
+			Universe:     fixture.Universe,
+			StartChannel: fixture.StartChannel,
+			Channels:     channelsByFixture[fixture.ID],
+		}
+	}
+	return houselights.Channels(houseFixtures), nil
+}
+
+// validateProjectGroups checks that IDs name distinct fixture groups of a
+// project.
+func (r *Resolver) validateProjectGroups(ctx context.Context, projectID string, groupIDs []string) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.FixtureGroup{}).Where("id IN ? AND project_id = ?", groupIDs, projectID).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(groupIDs) {
+		return fmt.Errorf("groups must be distinct fixture groups of the project")
+	}
+	return nil
+}
+
+// convertHouseLightsStatus converts a house lights status to the GraphQL
+// type.
+func convertHouseLightsStatus(status houselights.Status) *generated.HouseLightsStatus {
+	result := &generated.HouseLightsStatus{
+		ProjectID:   status.ProjectID,
+		Engaged:     status.Engaged,
+		Level:       status.Level,
+		TargetLevel: status.TargetLevel,
+		Fading:      status.Fading,
+	}
+	if status.Engaged {
+		preset := generated.HouseLightsPreset(status.Preset)
+		result.Preset = &preset
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/lucsky/cuid"
+)
+
+// journalKindIndependent is the journal kind holding the independents, keyed
+// by ID, so they survive a restart.
+const journalKindIndependent = "independent"
+
+// setIndependent resolves an independent's channels and holds them.
+func (r *Resolver) setIndependent(ctx context.Context, input generated.IndependentInput) (*generated.Independent, error) {
+	independent := dmx.Independent{
+		ID:     cuid.New(),
+		Name:   strings.TrimSpace(input.Name),
+		Values: make(map[int]map[int]byte),
+		Since:  time.Now().UTC(),
+	}
+	if id := input.ID.Value(); id != nil {
+		independent.ID = *id
+	}
+	if independent.Name == "" {
+		return nil, errors.New("independent name is required")
+	}
+	set := func(universe, channel, value int) error {
+		if value < 0 || value > 255 {
+			return fmt.Errorf("value for universe %d channel %d must be between 0 and 255", universe, channel)
+		}
+		if independent.Values[universe] == nil {
+			independent.Values[universe] = make(map[int]byte)
+		}
+		independent.Values[universe][channel] = byte(value)
+		return nil
+	}
+
+	if projectID := input.ProjectID.Value(); projectID != nil {
+		project, err := r.ProjectRepo.FindByID(ctx, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, fmt.Errorf("project not found: %s", *projectID)
+		}
+		independent.ProjectID = projectID
+	}
+	for _, c := range input.Channels.Value() {
+		if err := set(c.Universe, c.Channel, c.Value); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range input.Fixtures.Value() {
+		fixture, err := r.FixtureRepo.FindByID(ctx, f.FixtureID)
+		if err != nil {
+			return nil, err
+		}
+		if fixture == nil {
+			return nil, fmt.Errorf("fixture not found: %s", f.FixtureID)
+		}
+		if independent.ProjectID == nil {
+			independent.ProjectID = &fixture.ProjectID
+		} else if *independent.ProjectID != fixture.ProjectID {
+			return nil, fmt.Errorf("fixture %s is not in project %s", fixture.Name, *independent.ProjectID)
+		}
+		channels, err := r.FixtureRepo.GetInstanceChannels(ctx, fixture.ID)
+		if err != nil {
+			return nil, err
+		}
+		offsets := make(map[int]bool, len(channels))
+		for _, channel := range channels {
+			offsets[channel.Offset] = true
+		}
+		for _, c := range f.Channels {
+			if !offsets[c.Offset] {
+				return nil, fmt.Errorf("fixture %s has no channel at offset %d", fixture.Name, c.Offset)
+			}
+			if err := set(fixture.Universe, fixture.StartChannel+c.Offset, c.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(independent.Values) == 0 {
+		return nil, errors.New("an independent needs at least one channel")
+	}
+
+	if err := r.DMXService.SetIndependent(independent); err != nil {
+		return nil, err
+	}
+	if r.StateJournal != nil {
+		if err := r.StateJournal.Put(journalKindIndependent, independent.ID, independent); err != nil {
+			log.Warn("failed to journal independent", "independent", independent.ID, "error", err)
+		}
+	}
+	return convertIndependent(independent), nil
+}
+
+// independents lists the independents, of one project when projectID is
+// given.
+func (r *Resolver) independents(projectID *string) []*generated.Independent {
+	result := []*generated.Independent{}
+	for _, independent := range r.DMXService.Independents() {
+		if projectID == nil || (independent.ProjectID != nil && *independent.ProjectID == *projectID) {
+			result = append(result, convertIndependent(independent))
+		}
+	}
+	return result
+}
+
+// releaseIndependent hands an independent's channels back, reporting
+// whether it was set.
+func (r *Resolver) releaseIndependent(id string) bool {
+	if !r.DMXService.ReleaseIndependent(id) {
+		return false
+	}
+	if r.StateJournal != nil {
+		if err := r.StateJournal.Delete(journalKindIndependent, id); err != nil {
+			log.Warn("failed to journal released independent", "independent", id, "error", err)
+		}
+	}
+	return true
+}
+
+// releaseIndependents releases every independent, or those of one project,
+// returning how many it released.
+func (r *Resolver) releaseIndependents(projectID *string) int {
+	released := 0
+	for _, independent := range r.independents(projectID) {
+		if r.releaseIndependent(independent.ID) {
+			released++
+		}
+	}
+	return released
+}
+
+// restoreIndependents sets the independents held in a journal again,
+// returning how many it restored.
+func (r *Resolver) restoreIndependents(j *journal.Journal) int {
+	restored := 0
+	for id, raw := range j.Entries(journalKindIndependent) {
+		var independent dmx.Independent
+		err := json.Unmarshal(raw, &independent)
+		if err == nil {
+			err = r.DMXService.SetIndependent(independent)
+		}
+		if err != nil {
+			log.Warn("cannot restore independent", "independent", id, "error", err)
+			_ = j.Delete(journalKindIndependent, id)
+			continue
+		}
+		restored++
+	}
+	return restored
+}
+
+// convertIndependent converts an independent to the GraphQL type.
+func convertIndependent(independent dmx.Independent) *generated.Independent {
+	result := &generated.Independent{
+		ID:        independent.ID,
+		Name:      independent.Name,
+		ProjectID: independent.ProjectID,
+		Channels:  []*generated.IndependentChannel{},
+		Since:     independent.Since.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	for universe, channels := range independent.Values {
+		for channel, value := range channels {
+			result.Channels = append(result.Channels, &generated.IndependentChannel{Universe: universe, Channel: channel, Value: int(value)})
+		}
+	}
+	sort.Slice(result.Channels, func(i, j int) bool {
+		a, b := result.Channels[i], result.Channels[j]
+		if a.Universe != b.Universe {
+			return a.Universe < b.Universe
+		}
+		return a.Channel < b.Channel
+	})
+	return result
+}
@@ -0,0 +1,45 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/integrity"
+)
+
+// convertProjectIssues converts broken references for GraphQL.
+func convertProjectIssues(issues []integrity.Issue) []*generated.ProjectIssue {
+	result := make([]*generated.ProjectIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = &generated.ProjectIssue{
+			Type:       generated.ProjectIssueType(issue.Type),
+			EntityID:   issue.EntityID,
+			EntityName: issue.EntityName,
+			MissingID:  issue.MissingID,
+			Message:    issue.Message,
+		}
+	}
+	return result
+}
+
+// publishRepairedEntities publishes what a repair changed: its deleted cues,
+// and the scenes it deleted values from.
+func (r *Resolver) publishRepairedEntities(ctx context.Context, projectID string, repaired []integrity.Issue) {
+	scenes := make(map[string]bool)
+	for _, issue := range repaired {
+		switch issue.Type {
+		case integrity.IssueCueMissingScene:
+			r.emitEntityChange(&generated.ProjectEntityChange{
+				ProjectID:  projectID,
+				EntityType: generated.ProjectEntityTypeCue,
+				Action:     generated.EntityChangeActionDeleted,
+				EntityID:   issue.EntityID,
+			})
+		case integrity.IssueFixtureValueMissingFixture:
+			if !scenes[issue.ParentID] {
+				scenes[issue.ParentID] = true
+				r.publishEntityUpdated(ctx, generated.ProjectEntityTypeScene, issue.ParentID)
+			}
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// validateLayoutZone checks that a zone is named and lies within the layout.
+func validateLayoutZone(zone *models.LayoutZone) error {
+	if strings.TrimSpace(zone.Name) == "" {
+		return errors.New("layout zone name is required")
+	}
+	if zone.X < 0 || zone.Y < 0 || zone.Width <= 0 || zone.Height <= 0 ||
+		zone.X+zone.Width > 1 || zone.Y+zone.Height > 1 {
+		return errors.New("layout zone must have a positive size and lie within the layout's 0-1 coordinates")
+	}
+	return nil
+}
+
+// createLayoutZone adds a zone to a project's layout.
+func (r *Resolver) createLayoutZone(ctx context.Context, input generated.CreateLayoutZoneInput) (*models.LayoutZone, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	zone := &models.LayoutZone{
+		ProjectID: input.ProjectID,
+		Name:      strings.TrimSpace(input.Name),
+		X:         input.X,
+		Y:         input.Y,
+		Width:     input.Width,
+		Height:    input.Height,
+		Color:     trimmedOrNil(input.Color.Value()),
+	}
+	if err := validateLayoutZone(zone); err != nil {
+		return nil, err
+	}
+	if err := r.LayoutZoneRepo.Create(ctx, zone); err != nil {
+		return nil, err
+	}
+	r.publishLayoutChange(zone.ProjectID, nil, []*models.LayoutZone{zone}, nil)
+	return zone, nil
+}
+
+// updateLayoutZone changes a zone's name, bounds, or color.
+func (r *Resolver) updateLayoutZone(ctx context.Context, id string, input generated.UpdateLayoutZoneInput) (*models.LayoutZone, error) {
+	zone, err := r.findLayoutZone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v := input.Name.Value(); v != nil {
+		zone.Name = strings.TrimSpace(*v)
+	}
+	for _, field := range []struct {
+		value *float64
+		dest  *float64
+	}{
+		{input.X.Value(), &zone.X},
+		{input.Y.Value(), &zone.Y},
+		{input.Width.Value(), &zone.Width},
+		{input.Height.Value(), &zone.Height},
+	} {
+		if field.value != nil {
+			*field.dest = *field.value
+		}
+	}
+	if input.Color.IsSet() {
+		zone.Color = trimmedOrNil(input.Color.Value())
+	}
+	if err := validateLayoutZone(zone); err != nil {
+		return nil, err
+	}
+	if err := r.LayoutZoneRepo.Update(ctx, zone); err != nil {
+		return nil, err
+	}
+	r.publishLayoutChange(zone.ProjectID, nil, []*models.LayoutZone{zone}, nil)
+	return zone, nil
+}
+
+// deleteLayoutZone removes a zone, leaving its fixtures where they are.
+func (r *Resolver) deleteLayoutZone(ctx context.Context, id string) error {
+	zone, err := r.findLayoutZone(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.LayoutZoneRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.publishLayoutChange(zone.ProjectID, nil, nil, []string{id})
+	return nil
+}
+
+// findLayoutZone loads a layout zone by ID.
+func (r *Resolver) findLayoutZone(ctx context.Context, id string) (*models.LayoutZone, error) {
+	zone, err := r.LayoutZoneRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("layout zone not found: %s", id)
+	}
+	return zone, nil
+}
+
+// zoneFixtures returns the fixtures positioned inside a zone, edges
+// included.
+func (r *Resolver) zoneFixtures(ctx context.Context, zone *models.LayoutZone) ([]*models.FixtureInstance, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, zone.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	result := []*models.FixtureInstance{}
+	for i := range fixtures {
+		f := &fixtures[i]
+		if f.LayoutX == nil || f.LayoutY == nil {
+			continue
+		}
+		if *f.LayoutX >= zone.X && *f.LayoutX <= zone.X+zone.Width &&
+			*f.LayoutY >= zone.Y && *f.LayoutY <= zone.Y+zone.Height {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// layoutBounds is a box in layout coordinates.
+type layoutBounds struct {
+	minX, minY, maxX, maxY float64
+}
+
+// layoutSelection loads the fixtures an alignment or distribution moves and
+// the box it works within: the zone's, or else the one around the fixtures.
+func (r *Resolver) layoutSelection(ctx context.Context, fixtureIDs []string, zoneID *string) ([]*models.FixtureInstance, layoutBounds, error) {
+	var bounds layoutBounds
+	if len(fixtureIDs) == 0 {
+		return nil, bounds, errors.New("no fixtures given")
+	}
+	fixtures := make([]*models.FixtureInstance, 0, len(fixtureIDs))
+	for i, id := range fixtureIDs {
+		fixture, err := r.FixtureRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, bounds, err
+		}
+		if fixture == nil {
+			return nil, bounds, fmt.Errorf("fixture not found: %s", id)
+		}
+		if len(fixtures) > 0 && fixture.ProjectID != fixtures[0].ProjectID {
+			return nil, bounds, errors.New("fixtures must belong to one project")
+		}
+		if fixture.LayoutX == nil || fixture.LayoutY == nil {
+			return nil, bounds, fmt.Errorf("fixture %s has no layout position", fixture.Name)
+		}
+		if i == 0 {
+			bounds = layoutBounds{*fixture.LayoutX, *fixture.LayoutY, *fixture.LayoutX, *fixture.LayoutY}
+		}
+		bounds.minX = min(bounds.minX, *fixture.LayoutX)
+		bounds.minY = min(bounds.minY, *fixture.LayoutY)
+		bounds.maxX = max(bounds.maxX, *fixture.LayoutX)
+		bounds.maxY = max(bounds.maxY, *fixture.LayoutY)
+		fixtures = append(fixtures, fixture)
+	}
+
+	if zoneID != nil {
+		zone, err := r.findLayoutZone(ctx, *zoneID)
+		if err != nil {
+			return nil, bounds, err
+		}
+		if zone.ProjectID != fixtures[0].ProjectID {
+			return nil, bounds, errors.New("layout zone belongs to another project")
+		}
+		bounds = layoutBounds{zone.X, zone.Y, zone.X + zone.Width, zone.Y + zone.Height}
+	}
+	return fixtures, bounds, nil
+}
+
+// alignFixtures lines fixtures up on an edge or center of their box.
+func (r *Resolver) alignFixtures(ctx context.Context, fixtureIDs []string, alignment generated.LayoutAlignment, zoneID *string) ([]*models.FixtureInstance, error) {
+	fixtures, bounds, err := r.layoutSelection(ctx, fixtureIDs, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	var value float64
+	horizontal := true
+	switch alignment {
+	case generated.LayoutAlignmentLeft:
+		value = bounds.minX
+	case generated.LayoutAlignmentCenter:
+		value = (bounds.minX + bounds.maxX) / 2
+	case generated.LayoutAlignmentRight:
+		value = bounds.maxX
+	case generated.LayoutAlignmentTop:
+		value, horizontal = bounds.minY, false
+	case generated.LayoutAlignmentMiddle:
+		value, horizontal = (bounds.minY+bounds.maxY)/2, false
+	case generated.LayoutAlignmentBottom:
+		value, horizontal = bounds.maxY, false
+	default:
+		return nil, fmt.Errorf("unknown alignment: %s", alignment)
+	}
+
+	positions := make([]float64, len(fixtures))
+	for i := range positions {
+		positions[i] = value
+	}
+	return r.moveFixtures(ctx, "Align fixtures", fixtures, horizontal, positions)
+}
+
+// distributeFixtures spaces fixtures evenly along an axis, in the order
+// they already have along it. Across a zone, each fixture takes the middle
+// of an equal share of it.
+func (r *Resolver) distributeFixtures(ctx context.Context, fixtureIDs []string, axis generated.LayoutAxis, zoneID *string) ([]*models.FixtureInstance, error) {
+	fixtures, bounds, err := r.layoutSelection(ctx, fixtureIDs, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	horizontal := axis == generated.LayoutAxisHorizontal
+	coordinate := func(f *models.FixtureInstance) float64 {
+		if horizontal {
+			return *f.LayoutX
+		}
+		return *f.LayoutY
+	}
+	sort.SliceStable(fixtures, func(i, j int) bool { return coordinate(fixtures[i]) < coordinate(fixtures[j]) })
+
+	from, to := bounds.minY, bounds.maxY
+	if horizontal {
+		from, to = bounds.minX, bounds.maxX
+	}
+	n := float64(len(fixtures))
+	positions := make([]float64, len(fixtures))
+	for i := range positions {
+		switch {
+		case zoneID != nil:
+			positions[i] = from + (to-from)*(float64(i)+0.5)/n
+		case len(fixtures) == 1:
+			positions[i] = from
+		default:
+			positions[i] = from + (to-from)*float64(i)/(n-1)
+		}
+	}
+	return r.moveFixtures(ctx, "Distribute fixtures", fixtures, horizontal, positions)
+}
+
+// moveFixtures sets one layout coordinate of each fixture, recording the
+// move for undo and telling the project's layout editors.
+func (r *Resolver) moveFixtures(ctx context.Context, description string, fixtures []*models.FixtureInstance, horizontal bool, positions []float64) ([]*models.FixtureInstance, error) {
+	projectID := fixtures[0].ProjectID
+	targets := make([]repositories.UndoTarget, len(fixtures))
+	for i, fixture := range fixtures {
+		targets[i] = undoFixture(fixture.ID)
+	}
+	undo, err := r.beginUndo(ctx, projectID, description, targets...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fixture := range fixtures {
+		position := positions[i]
+		if horizontal {
+			fixture.LayoutX = &position
+		} else {
+			fixture.LayoutY = &position
+		}
+		if err := r.FixtureRepo.Update(ctx, fixture); err != nil {
+			return nil, err
+		}
+	}
+
+	r.commitUndo(ctx, undo)
+	r.publishLayoutChange(projectID, fixtures, nil, nil)
+	for _, fixture := range fixtures {
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, fixture)
+	}
+	return fixtures, nil
+}
+
+// publishLayoutChange tells subscribers to a project's layout what changed.
+func (r *Resolver) publishLayoutChange(projectID string, fixtures []*models.FixtureInstance, zones []*models.LayoutZone, deletedZoneIDs []string) {
+	if fixtures == nil {
+		fixtures = []*models.FixtureInstance{}
+	}
+	if zones == nil {
+		zones = []*models.LayoutZone{}
+	}
+	if deletedZoneIDs == nil {
+		deletedZoneIDs = []string{}
+	}
+	r.PubSub.Publish(pubsub.TopicLayoutChanged, projectID, &generated.LayoutChange{
+		ProjectID:      projectID,
+		Fixtures:       fixtures,
+		Zones:          zones,
+		DeletedZoneIds: deletedZoneIDs,
+	})
+}
@@ -0,0 +1,129 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/logging"
+)
+
+// logFilter builds a logging.Filter from query arguments.
+func logFilter(module *string, minLevel *generated.LogLevel, afterID *string, limit *int) (logging.Filter, error) {
+	var filter logging.Filter
+	if module != nil {
+		filter.Module = *module
+	}
+	if minLevel != nil {
+		filter.MinLevel = toSlogLevel(*minLevel)
+	}
+	if afterID != nil {
+		id, err := strconv.ParseInt(*afterID, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid log entry ID %q", *afterID)
+		}
+		filter.AfterID = id
+	}
+	if limit != nil {
+		if *limit < 0 {
+			return filter, fmt.Errorf("limit must not be negative")
+		}
+		filter.Limit = *limit
+	}
+	return filter, nil
+}
+
+// subscribeLogEntries sends each new log entry matching filter until ctx is
+// done. The logging callback never blocks: entries are dropped when the
+// subscriber falls behind.
+func subscribeLogEntries(ctx context.Context, filter logging.Filter) <-chan *generated.LogEntry {
+	// The callback may still run after unsubscribing, so this channel is
+	// never closed
+	entries := make(chan logging.Entry, 100)
+	unsubscribe := logging.Subscribe(func(entry logging.Entry) {
+		if !filter.Matches(entry) {
+			return
+		}
+		select {
+		case entries <- entry:
+		default:
+		}
+	})
+
+	outputChan := make(chan *generated.LogEntry, 10)
+	go func() {
+		defer close(outputChan)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry := <-entries:
+				select {
+				case outputChan <- convertLogEntry(entry):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return outputChan
+}
+
+// toSlogLevel converts a generated.LogLevel to a slog.Level.
+func toSlogLevel(level generated.LogLevel) slog.Level {
+	switch level {
+	case generated.LogLevelDebug:
+		return slog.LevelDebug
+	case generated.LogLevelWarn:
+		return slog.LevelWarn
+	case generated.LogLevelError:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// convertLogLevel converts a slog.Level to generated.LogLevel, rounding
+// levels between the named ones down.
+func convertLogLevel(level slog.Level) generated.LogLevel {
+	switch {
+	case level >= slog.LevelError:
+		return generated.LogLevelError
+	case level >= slog.LevelWarn:
+		return generated.LogLevelWarn
+	case level >= slog.LevelInfo:
+		return generated.LogLevelInfo
+	}
+	return generated.LogLevelDebug
+}
+
+// convertLogEntry converts a logging.Entry to generated.LogEntry.
+func convertLogEntry(entry logging.Entry) *generated.LogEntry {
+	attributes := make([]*generated.LogAttribute, len(entry.Attrs))
+	for i, attr := range entry.Attrs {
+		attributes[i] = &generated.LogAttribute{Key: attr.Key, Value: attr.Value}
+	}
+	return &generated.LogEntry{
+		ID:         strconv.FormatInt(entry.ID, 10),
+		Timestamp:  entry.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+		Level:      convertLogLevel(entry.Level),
+		Module:     entry.Module,
+		Message:    entry.Message,
+		Attributes: attributes,
+	}
+}
+
+// currentLogLevels returns the default log level and each module's level.
+func currentLogLevels() *generated.LogLevels {
+	modules := logging.ModuleLevels()
+	result := &generated.LogLevels{
+		DefaultLevel: convertLogLevel(logging.DefaultLevel()),
+		Modules:      make([]*generated.ModuleLogLevel, len(modules)),
+	}
+	for i, module := range modules {
+		result.Modules[i] = &generated.ModuleLogLevel{Module: module.Module, Level: convertLogLevel(module.Level)}
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/macro"
+)
+
+// wireMacros carries out scene board button macro actions. Macros run under
+// their button's ID.
+func (r *Resolver) wireMacros() {
+	r.MacroService.SetHandlers(macro.Handlers{
+		ActivateScene: func(ctx context.Context, buttonID, sceneID string, fadeTime *float64) error {
+			var button models.SceneBoardButton
+			if err := r.db.WithContext(ctx).Select("scene_board_id").First(&button, "id = ?", buttonID).Error; err != nil {
+				return fmt.Errorf("scene board button not found: %w", err)
+			}
+			_, err := r.Mutation().ActivateSceneFromBoard(ctx, button.SceneBoardID, sceneID, fadeTime)
+			return err
+		},
+		CueListGo: func(ctx context.Context, cueListID string, fadeTime *float64) error {
+			return r.PlaybackService.NextCue(ctx, cueListID, fadeTime, nil)
+		},
+	})
+}
+
+// setSceneBoardButtonMacro validates and saves a button's macro. The scenes
+// and cue lists it names must belong to the board's project.
+func (r *Resolver) setSceneBoardButtonMacro(ctx context.Context, buttonID string, input []*generated.MacroActionInput) (*models.SceneBoardButton, error) {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return nil, fmt.Errorf("scene board button not found: %w", err)
+	}
+	var board models.SceneBoard
+	if err := r.db.WithContext(ctx).First(&board, "id = ?", button.SceneBoardID).Error; err != nil {
+		return nil, fmt.Errorf("scene board not found: %w", err)
+	}
+
+	actions := macroActions(input)
+	if err := macro.Validate(actions); err != nil {
+		return nil, err
+	}
+	for i, action := range actions {
+		switch action.Type {
+		case macro.ActionActivateScene:
+			scene, err := r.SceneRepo.FindByID(ctx, action.SceneID)
+			if err != nil {
+				return nil, err
+			}
+			if scene == nil || scene.ProjectID != board.ProjectID {
+				return nil, fmt.Errorf("action %d: scene not found: %s", i+1, action.SceneID)
+			}
+		case macro.ActionCueListGo:
+			cueList, err := r.CueListRepo.FindByID(ctx, action.CueListID)
+			if err != nil {
+				return nil, err
+			}
+			if cueList == nil || cueList.ProjectID != board.ProjectID {
+				return nil, fmt.Errorf("action %d: cue list not found: %s", i+1, action.CueListID)
+			}
+		}
+	}
+
+	value, err := macro.Encode(actions)
+	if err != nil {
+		return nil, err
+	}
+	button.Macro = value
+	if err := r.db.WithContext(ctx).Save(&button).Error; err != nil {
+		return nil, err
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, &board)
+	return &button, nil
+}
+
+// executeMacro starts running a button's macro.
+func (r *Resolver) executeMacro(ctx context.Context, buttonID string) error {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return fmt.Errorf("scene board button not found: %w", err)
+	}
+	actions, err := macro.Parse(button.Macro)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		return fmt.Errorf("scene board button has no macro: %s", buttonID)
+	}
+	return r.MacroService.Execute(button.ID, actions)
+}
+
+// macroActions converts macro input to the actions it describes.
+func macroActions(input []*generated.MacroActionInput) []macro.Action {
+	actions := make([]macro.Action, len(input))
+	for i, in := range input {
+		action := macro.Action{Type: macro.ActionType(in.Type), FadeTime: in.FadeTime.Value()}
+		if v := in.SceneID.Value(); v != nil {
+			action.SceneID = *v
+		}
+		if v := in.WaitTime.Value(); v != nil {
+			action.WaitTime = *v
+		}
+		if v := in.CueListID.Value(); v != nil {
+			action.CueListID = *v
+		}
+		if v := in.OscHost.Value(); v != nil {
+			action.OSCHost = *v
+		}
+		if v := in.OscPort.Value(); v != nil {
+			action.OSCPort = *v
+		}
+		if v := in.OscAddress.Value(); v != nil {
+			action.OSCAddress = *v
+		}
+		for _, arg := range in.OscArguments.Value() {
+			action.OSCArguments = append(action.OSCArguments, macro.OSCArgument{
+				Type:  macro.OSCArgumentType(arg.Type),
+				Value: arg.Value,
+			})
+		}
+		actions[i] = action
+	}
+	return actions
+}
+
+// convertMacroActions converts a stored macro to GraphQL actions.
+func convertMacroActions(value string) ([]*generated.MacroAction, error) {
+	actions, err := macro.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.MacroAction, len(actions))
+	for i, action := range actions {
+		out := &generated.MacroAction{Type: generated.MacroActionType(action.Type), FadeTime: action.FadeTime}
+		switch action.Type {
+		case macro.ActionActivateScene:
+			out.SceneID = &action.SceneID
+		case macro.ActionWait:
+			out.WaitTime = &action.WaitTime
+		case macro.ActionCueListGo:
+			out.CueListID = &action.CueListID
+		case macro.ActionSendOSC:
+			out.OscHost = &action.OSCHost
+			out.OscPort = &action.OSCPort
+			out.OscAddress = &action.OSCAddress
+			out.OscArguments = make([]*generated.OscArgument, len(action.OSCArguments))
+			for j, arg := range action.OSCArguments {
+				out.OscArguments[j] = &generated.OscArgument{
+					Type:  generated.OscArgumentType(arg.Type),
+					Value: arg.Value,
+				}
+			}
+		}
+		result[i] = out
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// journalKindMaster is the journal kind holding master levels below full,
+// keyed by journalKeyGrandMaster or the universe number.
+const journalKindMaster = "master"
+
+const journalKeyGrandMaster = "grand"
+
+// masterChannel reports whether the masters scale a channel: only fading,
+// continuous channels are scaled, so snap channels such as shutters and
+// discrete selectors keep their values.
+func masterChannel(ch *models.InstanceChannel) bool {
+	return ch.FadeBehavior == string(generated.FadeBehaviorFade) && !ch.IsDiscrete
+}
+
+// refreshMasterChannels pushes the channels the masters scale, and the
+// channels a blackout darkens, from the fixtures of every project to the DMX
+// output stage. The channels a blackout darkens are also those the playback
+// stack merges HTP.
+func (r *Resolver) refreshMasterChannels(ctx context.Context) {
+	var fixtures []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Preload("Channels").Find(&fixtures).Error; err != nil {
+		log.Warn("failed to load fixture channels for masters", "error", err)
+		return
+	}
+
+	channels := make(map[int][]int)
+	htp := make(map[int][]int)
+	blackout := make(map[int]map[int]bool)
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		fading := intensityChannels(fixture, masterChannel)
+		channels[fixture.Universe] = append(channels[fixture.Universe], fading...)
+
+		if blackout[fixture.Universe] == nil {
+			blackout[fixture.Universe] = make(map[int]bool)
+		}
+		for _, channel := range intensityChannels(fixture, nil) {
+			blackout[fixture.Universe][channel] = false
+			htp[fixture.Universe] = append(htp[fixture.Universe], channel)
+		}
+		for _, channel := range fading {
+			blackout[fixture.Universe][channel] = true
+		}
+	}
+	r.DMXService.SetMasterChannels(channels)
+	r.DMXService.SetBlackoutChannels(blackout)
+	r.StackService.SetHTPChannels(htp)
+}
+
+// SetStateJournal sets the journal master and submaster levels, blackouts,
+// and live scenes are recorded in. resumePlayback restores the state it holds.
+func (r *Resolver) SetStateJournal(j *journal.Journal) {
+	r.StateJournal = j
+}
+
+// restoreMasterLevels applies the master levels held in a journal, returning
+// how many it restored.
+func (r *Resolver) restoreMasterLevels(j *journal.Journal) int {
+	restored := 0
+	for key, raw := range j.Entries(journalKindMaster) {
+		var level float64
+		err := json.Unmarshal(raw, &level)
+		if err == nil {
+			err = r.applyMasterLevel(key, level)
+		}
+		if err != nil {
+			log.Warn("cannot restore master", "master", key, "error", err)
+			_ = j.Delete(journalKindMaster, key)
+			continue
+		}
+		restored++
+	}
+	return restored
+}
+
+// applyMasterLevel sets the master a journal key names.
+func (r *Resolver) applyMasterLevel(key string, level float64) error {
+	if key == journalKeyGrandMaster {
+		return r.DMXService.SetGrandMaster(level)
+	}
+	universe, err := strconv.Atoi(key)
+	if err != nil {
+		return err
+	}
+	return r.DMXService.SetUniverseMaster(universe, level)
+}
+
+// setMasterLevel sets the grand master, or a universe master when universe
+// is given, then journals and publishes the change.
+func (r *Resolver) setMasterLevel(universe *int, level float64) (*generated.MasterLevels, error) {
+	key := journalKeyGrandMaster
+	var err error
+	if universe != nil {
+		key = strconv.Itoa(*universe)
+		err = r.DMXService.SetUniverseMaster(*universe, level)
+	} else {
+		err = r.DMXService.SetGrandMaster(level)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StateJournal != nil {
+		if level == 1 {
+			err = r.StateJournal.Delete(journalKindMaster, key)
+		} else {
+			err = r.StateJournal.Put(journalKindMaster, key, level)
+		}
+		if err != nil {
+			log.Warn("failed to journal master", "master", key, "error", err)
+		}
+	}
+	if level == 1 {
+		r.replicate(journalKindMaster, key, nil)
+	} else {
+		r.replicate(journalKindMaster, key, level)
+	}
+
+	levels := convertMasterLevels(r.DMXService.MasterLevels())
+	r.PubSub.Publish(pubsub.TopicMasterLevel, "", levels)
+	return levels, nil
+}
+
+// convertMasterLevels converts master levels to the GraphQL type.
+func convertMasterLevels(levels dmx.MasterLevels) *generated.MasterLevels {
+	result := &generated.MasterLevels{
+		GrandMaster: levels.GrandMaster,
+		Universes:   make([]*generated.UniverseMaster, 0, len(levels.Universes)),
+	}
+	for _, master := range levels.Universes {
+		result.Universes = append(result.Universes, &generated.UniverseMaster{
+			Universe: master.Universe,
+			Level:    master.Level,
+		})
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/naming"
+)
+
+// projectNamingConvention reads the naming convention stored on a project.
+func projectNamingConvention(project *models.Project) naming.Convention {
+	convention := naming.Convention{
+		ScenePattern: project.SceneNamePattern,
+		CuePattern:   project.CueNamePattern,
+		Uniqueness:   naming.AllowDuplicates,
+		Variables:    map[string]string{},
+	}
+	if project.NameUniqueness != nil {
+		convention.Uniqueness = naming.UniquenessPolicy(*project.NameUniqueness)
+	}
+	if project.NamingVariables != nil && *project.NamingVariables != "" {
+		_ = json.Unmarshal([]byte(*project.NamingVariables), &convention.Variables)
+	}
+	return convention
+}
+
+// projectDefaultSceneSort returns the scene order used when a query gives none.
+func projectDefaultSceneSort(project *models.Project) generated.SceneSortField {
+	if project != nil && project.DefaultSceneSort != nil {
+		if field := generated.SceneSortField(*project.DefaultSceneSort); field.IsValid() {
+			return field
+		}
+	}
+	return generated.SceneSortFieldCreatedAt
+}
+
+func convertNamingConvention(project *models.Project) *generated.NamingConvention {
+	convention := projectNamingConvention(project)
+
+	keys := make([]string, 0, len(convention.Variables))
+	for key := range convention.Variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	variables := make([]*generated.NamingVariable, len(keys))
+	for i, key := range keys {
+		variables[i] = &generated.NamingVariable{Key: key, Value: convention.Variables[key]}
+	}
+
+	return &generated.NamingConvention{
+		ScenePattern:     convention.ScenePattern,
+		CuePattern:       convention.CuePattern,
+		Uniqueness:       generated.NameUniquenessPolicy(convention.Uniqueness),
+		Variables:        variables,
+		DefaultSceneSort: projectDefaultSceneSort(project),
+	}
+}
+
+// namingValues returns the token values shared by scene and cue patterns.
+func namingValues(project *models.Project, convention naming.Convention) map[string]string {
+	values := make(map[string]string, len(convention.Variables)+1)
+	for key, value := range convention.Variables {
+		values[key] = value
+	}
+	values[naming.TokenProject] = project.Name
+	return values
+}
+
+// sceneNameForCreate applies the project's naming convention to a new scene.
+// An empty name is generated from the scene pattern when one is configured.
+func (r *Resolver) sceneNameForCreate(ctx context.Context, projectID, name string) (string, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if project == nil {
+		return name, nil
+	}
+	convention := projectNamingConvention(project)
+
+	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	existing := make([]string, len(scenes))
+	for i, scene := range scenes {
+		existing[i] = scene.Name
+	}
+
+	if strings.TrimSpace(name) == "" && convention.ScenePattern != nil {
+		name = naming.Generate(*convention.ScenePattern, namingValues(project, convention), existing)
+	}
+	return naming.ApplyUniqueness(name, existing, convention.Uniqueness)
+}
+
+// cueNameForCreate applies the project's naming convention to a new cue.
+// When cueNumber is nil, {cue} renders as the next whole cue number.
+func (r *Resolver) cueNameForCreate(ctx context.Context, cueList *models.CueList, cueNumber *float64, name string) (string, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, cueList.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	if project == nil {
+		return name, nil
+	}
+	convention := projectNamingConvention(project)
+
+	cues, err := r.CueRepo.FindByCueListID(ctx, cueList.ID)
+	if err != nil {
+		return "", err
+	}
+	existing := make([]string, len(cues))
+	highestCueNumber := 0.0
+	for i, cue := range cues {
+		existing[i] = cue.Name
+		highestCueNumber = math.Max(highestCueNumber, cue.CueNumber)
+	}
+
+	if strings.TrimSpace(name) == "" && convention.CuePattern != nil {
+		number := math.Floor(highestCueNumber) + 1
+		if cueNumber != nil {
+			number = *cueNumber
+		}
+		values := namingValues(project, convention)
+		values[naming.TokenCueList] = cueList.Name
+		values[naming.TokenCueNumber] = strconv.FormatFloat(number, 'f', -1, 64)
+		name = naming.Generate(*convention.CuePattern, values, existing)
+	}
+	return naming.ApplyUniqueness(name, existing, convention.Uniqueness)
+}
+
+// applyNamingConventionInput validates input and stores it on the project.
+func applyNamingConventionInput(project *models.Project, input generated.NamingConventionInput) error {
+	convention := projectNamingConvention(project)
+
+	if input.Variables.IsSet() {
+		convention.Variables = map[string]string{}
+		for _, v := range input.Variables.Value() {
+			if _, exists := convention.Variables[v.Key]; exists {
+				return fmt.Errorf("duplicate naming variable: %s", v.Key)
+			}
+			convention.Variables[v.Key] = v.Value
+		}
+		if err := naming.ValidateVariables(convention.Variables); err != nil {
+			return err
+		}
+	}
+	if input.ScenePattern.IsSet() {
+		convention.ScenePattern = input.ScenePattern.Value()
+	}
+	if input.CuePattern.IsSet() {
+		convention.CuePattern = input.CuePattern.Value()
+	}
+	if input.Uniqueness.IsSet() && input.Uniqueness.Value() != nil {
+		convention.Uniqueness = naming.UniquenessPolicy(*input.Uniqueness.Value())
+	}
+
+	// Patterns are rechecked so removing a variable cannot leave a dangling token
+	if convention.ScenePattern != nil {
+		if err := naming.ValidatePattern(*convention.ScenePattern, naming.SceneTokens, convention.Variables); err != nil {
+			return fmt.Errorf("scene pattern: %w", err)
+		}
+	}
+	if convention.CuePattern != nil {
+		if err := naming.ValidatePattern(*convention.CuePattern, naming.CueTokens, convention.Variables); err != nil {
+			return fmt.Errorf("cue pattern: %w", err)
+		}
+	}
+	if err := naming.ValidatePolicy(convention.Uniqueness); err != nil {
+		return err
+	}
+
+	variablesJSON, err := json.Marshal(convention.Variables)
+	if err != nil {
+		return err
+	}
+	project.SceneNamePattern = convention.ScenePattern
+	project.CueNamePattern = convention.CuePattern
+	project.NameUniqueness = stringPtr(string(convention.Uniqueness))
+	project.NamingVariables = stringPtr(string(variablesJSON))
+
+	if input.DefaultSceneSort.IsSet() {
+		if field := input.DefaultSceneSort.Value(); field != nil {
+			project.DefaultSceneSort = stringPtr(string(*field))
+		} else {
+			project.DefaultSceneSort = nil
+		}
+	}
+	return nil
+}
+
+// sortScenes orders scenes for the scenes query. The repository already
+// returns them newest first, which is the CREATED_AT order.
+func sortScenes(scenes []models.Scene, field generated.SceneSortField) {
+	switch field {
+	case generated.SceneSortFieldName:
+		sort.SliceStable(scenes, func(i, j int) bool { return naming.NaturalLess(scenes[i].Name, scenes[j].Name) })
+	case generated.SceneSortFieldUpdatedAt:
+		sort.SliceStable(scenes, func(i, j int) bool { return scenes[i].UpdatedAt.After(scenes[j].UpdatedAt) })
+	}
+}
@@ -0,0 +1,166 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// additiveColorTypes are the channels that set brightness on fixtures
+// without a dedicated intensity channel.
+var additiveColorTypes = map[string]bool{
+	string(generated.ChannelTypeRed):       true,
+	string(generated.ChannelTypeGreen):     true,
+	string(generated.ChannelTypeBlue):      true,
+	string(generated.ChannelTypeWhite):     true,
+	string(generated.ChannelTypeAmber):     true,
+	string(generated.ChannelTypeUv):        true,
+	string(generated.ChannelTypeLime):      true,
+	string(generated.ChannelTypeIndigo):    true,
+	string(generated.ChannelTypeColdWhite): true,
+	string(generated.ChannelTypeWarmWhite): true,
+}
+
+// validateMaxIntensity checks an intensity cap is a fraction between 0 and 1.
+func validateMaxIntensity(maxIntensity *float64) error {
+	if maxIntensity != nil && (*maxIntensity < 0 || *maxIntensity > 1 || math.IsNaN(*maxIntensity)) {
+		return fmt.Errorf("maxIntensity must be between 0 and 1, got %v", *maxIntensity)
+	}
+	return nil
+}
+
+// intensityLimitValue converts an intensity cap to a DMX value.
+func intensityLimitValue(maxIntensity float64) byte {
+	return byte(math.Round(maxIntensity * 255))
+}
+
+// intensityLimitChannels returns the absolute DMX channels a fixture's cap
+// applies to: its intensity channels, or its additive color channels when it
+// has no dimmer. Channels must be loaded on the fixture.
+func intensityLimitChannels(fixture *models.FixtureInstance) []int {
+	return intensityChannels(fixture, nil)
+}
+
+// intensityChannels returns a fixture's intensity channels, or its additive
+// color channels when it has no dimmer, leaving out those include rejects.
+func intensityChannels(fixture *models.FixtureInstance, include func(ch *models.InstanceChannel) bool) []int {
+	var intensity, color []*models.InstanceChannel
+	for i := range fixture.Channels {
+		ch := &fixture.Channels[i]
+		absolute := fixture.StartChannel + ch.Offset
+		if absolute < 1 || absolute > 512 {
+			continue
+		}
+		switch {
+		case ch.Type == string(generated.ChannelTypeIntensity):
+			intensity = append(intensity, ch)
+		case additiveColorTypes[ch.Type]:
+			color = append(color, ch)
+		}
+	}
+
+	selected := intensity
+	if len(selected) == 0 {
+		selected = color
+	}
+	channels := make([]int, 0, len(selected))
+	for _, ch := range selected {
+		if include == nil || include(ch) {
+			channels = append(channels, fixture.StartChannel+ch.Offset)
+		}
+	}
+	sort.Ints(channels)
+	return channels
+}
+
+// findCappedFixtures loads fixtures that have an intensity cap, with their
+// channels. An empty projectID loads them from every project.
+func (r *Resolver) findCappedFixtures(ctx context.Context, projectID string) ([]models.FixtureInstance, error) {
+	query := r.db.WithContext(ctx).Preload("Channels").Where("max_intensity IS NOT NULL")
+	if projectID != "" {
+		query = query.Where("project_id = ?", projectID)
+	}
+
+	var fixtures []models.FixtureInstance
+	if err := query.Order("universe ASC, start_channel ASC").Find(&fixtures).Error; err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// refreshOutputLimits pushes every fixture's intensity cap, the channels the
+// masters scale, the submasters, whose channels follow the fixtures, and the
+// universes fixtures are patched to, to the DMX output stage. Caps from all
+// projects apply, since they describe the venue; where fixtures overlap the
+// lowest cap wins.
+func (r *Resolver) refreshOutputLimits(ctx context.Context) {
+	fixtures, err := r.findCappedFixtures(ctx, "")
+	if err != nil {
+		log.Warn("failed to load fixture intensity caps", "error", err)
+		return
+	}
+
+	limits := make(map[int]map[int]byte)
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		max := intensityLimitValue(*fixture.MaxIntensity)
+		if limits[fixture.Universe] == nil {
+			limits[fixture.Universe] = make(map[int]byte)
+		}
+		for _, channel := range intensityLimitChannels(fixture) {
+			if existing, ok := limits[fixture.Universe][channel]; !ok || max < existing {
+				limits[fixture.Universe][channel] = max
+			}
+		}
+	}
+
+	r.DMXService.SetOutputLimits(limits)
+	r.refreshMasterChannels(ctx)
+	r.refreshSubmasters(ctx)
+	r.refreshUniverses(ctx)
+}
+
+// intensityLimitReport describes the capped fixtures in a project.
+func (r *Resolver) intensityLimitReport(ctx context.Context, projectID string) ([]*generated.FixtureIntensityLimit, error) {
+	fixtures, err := r.findCappedFixtures(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	limitedByUniverse := make(map[int]map[int]bool)
+	report := make([]*generated.FixtureIntensityLimit, 0, len(fixtures))
+	for i := range fixtures {
+		fixture := &fixtures[i]
+
+		limited, ok := limitedByUniverse[fixture.Universe]
+		if !ok {
+			limited = make(map[int]bool)
+			for _, channel := range r.DMXService.LimitedChannels(fixture.Universe) {
+				limited[channel] = true
+			}
+			limitedByUniverse[fixture.Universe] = limited
+		}
+
+		channels := intensityLimitChannels(fixture)
+		limitedChannels := []int{}
+		for _, channel := range channels {
+			if limited[channel] {
+				limitedChannels = append(limitedChannels, channel)
+			}
+		}
+
+		report = append(report, &generated.FixtureIntensityLimit{
+			Fixture:         *fixture,
+			MaxIntensity:    *fixture.MaxIntensity,
+			MaxValue:        int(intensityLimitValue(*fixture.MaxIntensity)),
+			Universe:        fixture.Universe,
+			Channels:        channels,
+			LimitedChannels: limitedChannels,
+		})
+	}
+	return report, nil
+}
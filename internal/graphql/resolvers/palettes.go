@@ -0,0 +1,261 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// paletteKindChannelTypes lists the channel types each kind of palette sets.
+var paletteKindChannelTypes = map[generated.PaletteKind][]generated.ChannelType{
+	generated.PaletteKindColor: {
+		generated.ChannelTypeRed, generated.ChannelTypeGreen, generated.ChannelTypeBlue,
+		generated.ChannelTypeWhite, generated.ChannelTypeAmber, generated.ChannelTypeUv,
+		generated.ChannelTypeCyan, generated.ChannelTypeMagenta, generated.ChannelTypeYellow,
+		generated.ChannelTypeLime, generated.ChannelTypeIndigo, generated.ChannelTypeColdWhite,
+		generated.ChannelTypeWarmWhite, generated.ChannelTypeColorWheel,
+	},
+	generated.PaletteKindPosition: {
+		generated.ChannelTypePan, generated.ChannelTypeTilt,
+	},
+	generated.PaletteKindBeam: {
+		generated.ChannelTypeZoom, generated.ChannelTypeFocus, generated.ChannelTypeIris,
+		generated.ChannelTypeGobo, generated.ChannelTypeStrobe,
+	},
+}
+
+// findPalette loads a palette by ID.
+func (r *Resolver) findPalette(ctx context.Context, id string) (*models.Palette, error) {
+	palette, err := r.PaletteRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if palette == nil {
+		return nil, fmt.Errorf("palette not found: %s", id)
+	}
+	return palette, nil
+}
+
+// serializePaletteChannels validates a palette's values against its kind and
+// converts them to JSON for storage.
+func serializePaletteChannels(kind generated.PaletteKind, channels []*generated.TypedChannelValueInput) (string, error) {
+	allowed := make(map[generated.ChannelType]bool)
+	for _, t := range paletteKindChannelTypes[kind] {
+		allowed[t] = true
+	}
+	for _, ch := range channels {
+		if !allowed[ch.Type] {
+			return "", fmt.Errorf("channel type %s does not belong in a %s palette", ch.Type, kind)
+		}
+	}
+	return serializeTypedChannels(channels)
+}
+
+// applyPalettes sets a fixture's channels of each palette's types, by offset,
+// later palettes winning.
+func applyPalettes(fixture *models.FixtureInstance, palettes []*models.Palette, values map[int]int) error {
+	for _, palette := range palettes {
+		var channels []models.ChannelTypeValue
+		if err := json.Unmarshal([]byte(palette.Channels), &channels); err != nil {
+			return fmt.Errorf("invalid channels for palette %s: %w", palette.ID, err)
+		}
+		byType := make(map[string]int, len(channels))
+		for _, ch := range channels {
+			byType[ch.Type] = ch.Value
+		}
+		for _, ch := range fixture.Channels {
+			if value, ok := byType[string(ch.Type)]; ok {
+				values[ch.Offset] = value
+			}
+		}
+	}
+	return nil
+}
+
+// loadPalettes loads palettes by ID, checking that each exists in the project.
+func (r *Resolver) loadPalettes(ctx context.Context, projectID string, ids []string) ([]*models.Palette, error) {
+	byID, err := r.PaletteRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	palettes := make([]*models.Palette, len(ids))
+	for i, id := range ids {
+		palette := byID[id]
+		if palette == nil || palette.ProjectID != projectID {
+			return nil, fmt.Errorf("palette not found in project: %s", id)
+		}
+		palettes[i] = palette
+	}
+	return palettes, nil
+}
+
+// applyFixturePalettes sets the channels of fixture values referencing
+// palettes to the palettes' values, over the channels they set themselves.
+func (r *Resolver) applyFixturePalettes(ctx context.Context, fixtureValues []*generated.FixtureValueInput) error {
+	var fixtureIDs []string
+	for _, fv := range fixtureValues {
+		if len(fv.PaletteIds.Value()) > 0 {
+			fixtureIDs = append(fixtureIDs, fv.FixtureID)
+		}
+	}
+	if len(fixtureIDs) == 0 {
+		return nil
+	}
+
+	fixtures, err := r.colorFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return err
+	}
+	for _, fv := range fixtureValues {
+		if len(fv.PaletteIds.Value()) == 0 {
+			continue
+		}
+		fixture := fixtures[fv.FixtureID]
+		palettes, err := r.loadPalettes(ctx, fixture.ProjectID, fv.PaletteIds.Value())
+		if err != nil {
+			return fmt.Errorf("fixture %s: %w", fv.FixtureID, err)
+		}
+
+		values := make(map[int]int)
+		if err := applyPalettes(fixture, palettes, values); err != nil {
+			return err
+		}
+		for _, ch := range fv.Channels {
+			if value, ok := values[ch.Offset]; ok {
+				ch.Value = value
+				delete(values, ch.Offset)
+			}
+		}
+		for offset, value := range values {
+			fv.Channels = append(fv.Channels, &generated.ChannelValueInput{Offset: offset, Value: value})
+		}
+	}
+	return nil
+}
+
+// fixturePaletteIDs returns the palette references of a fixture value input
+// as JSON for storage.
+func fixturePaletteIDs(fv *generated.FixtureValueInput) string {
+	ids := fv.PaletteIds.Value()
+	if len(ids) == 0 {
+		return "[]"
+	}
+	idsJSON, err := json.Marshal(ids)
+	if err != nil {
+		return "[]"
+	}
+	return string(idsJSON)
+}
+
+// refreshPaletteScenes reapplies a changed palette to the fixture values
+// referencing it, updating any of their scenes that are live.
+func (r *Resolver) refreshPaletteScenes(ctx context.Context, paletteID string) error {
+	values, err := r.PaletteRepo.FindFixtureValues(ctx, paletteID)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	fixtureIDs := make([]string, len(values))
+	for i, v := range values {
+		fixtureIDs[i] = v.FixtureID
+	}
+	fixtures, err := r.colorFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return err
+	}
+
+	var sceneIDs []string
+	seen := make(map[string]bool)
+	for i := range values {
+		value := &values[i]
+		fixture := fixtures[value.FixtureID]
+		ids, err := repositories.FixtureValuePaletteIDs(value)
+		if err != nil {
+			return err
+		}
+		byID, err := r.PaletteRepo.FindByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		// Palettes deleted since are no longer referenced, so skip any missing
+		var palettes []*models.Palette
+		for _, id := range ids {
+			if palette := byID[id]; palette != nil {
+				palettes = append(palettes, palette)
+			}
+		}
+
+		var channels []models.ChannelValue
+		if err := json.Unmarshal([]byte(value.Channels), &channels); err != nil {
+			return fmt.Errorf("failed to deserialize channels: %w", err)
+		}
+		merged := make(map[int]int, len(channels))
+		for _, ch := range channels {
+			merged[ch.Offset] = ch.Value
+		}
+		if err := applyPalettes(fixture, palettes, merged); err != nil {
+			return err
+		}
+		channels = channels[:0]
+		for offset, v := range merged {
+			channels = append(channels, models.ChannelValue{Offset: offset, Value: v})
+		}
+		sort.Slice(channels, func(i, j int) bool { return channels[i].Offset < channels[j].Offset })
+		channelsJSON, err := json.Marshal(channels)
+		if err != nil {
+			return fmt.Errorf("failed to serialize channels: %w", err)
+		}
+		value.Channels = string(channelsJSON)
+
+		if !seen[value.SceneID] {
+			seen[value.SceneID] = true
+			sceneIDs = append(sceneIDs, value.SceneID)
+		}
+	}
+	if err := r.SceneRepo.UpdateFixtureValues(ctx, values); err != nil {
+		return err
+	}
+
+	for _, sceneID := range sceneIDs {
+		if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
+			log.Warn("failed to re-apply active scene after palette change", "error", err)
+		}
+	}
+	r.refreshSubmasters(ctx)
+	return nil
+}
+
+// removePaletteReferences drops a deleted palette from the fixture values
+// referencing it. They keep the palette's values as their own.
+func (r *Resolver) removePaletteReferences(ctx context.Context, paletteID string) error {
+	values, err := r.PaletteRepo.FindFixtureValues(ctx, paletteID)
+	if err != nil {
+		return err
+	}
+	for i := range values {
+		ids, err := repositories.FixtureValuePaletteIDs(&values[i])
+		if err != nil {
+			return err
+		}
+		kept := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if id != paletteID {
+				kept = append(kept, id)
+			}
+		}
+		idsJSON, err := json.Marshal(kept)
+		if err != nil {
+			return err
+		}
+		values[i].PaletteIDs = string(idsJSON)
+	}
+	return r.SceneRepo.UpdateFixtureValues(ctx, values)
+}
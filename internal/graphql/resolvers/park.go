@@ -0,0 +1,123 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// loadParkedChannels applies the saved parked channels, if any.
+func (r *Resolver) loadParkedChannels(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.ParkedChannelsSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var parked []dmx.ParkedChannel
+	if err := json.Unmarshal([]byte(setting.Value), &parked); err != nil {
+		log.Warn("invalid saved parked channels", "error", err)
+		return
+	}
+	if err := r.DMXService.SetParkedChannels(parked); err != nil {
+		log.Warn("invalid saved parked channels", "error", err)
+	}
+}
+
+// updateParkedChannels applies and saves the parked channels.
+func (r *Resolver) updateParkedChannels(ctx context.Context, parked []dmx.ParkedChannel) ([]*generated.ParkedChannel, error) {
+	if parked == nil {
+		parked = []dmx.ParkedChannel{}
+	}
+	// Validate before touching live output or the saved channels
+	if err := dmx.ValidateParkedChannels(parked); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(parked)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, dmx.ParkedChannelsSettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save parked channels: %w", err)
+	}
+	if err := r.DMXService.SetParkedChannels(parked); err != nil {
+		return nil, err
+	}
+	return convertParkedChannels(r.DMXService.ParkedChannels()), nil
+}
+
+// replaceParkedChannel returns the current parked channels with one
+// channel's replaced, or removed when p is nil.
+func (r *Resolver) replaceParkedChannel(universe, channel int, p *dmx.ParkedChannel) []dmx.ParkedChannel {
+	parked := make([]dmx.ParkedChannel, 0)
+	for _, existing := range r.DMXService.ParkedChannels() {
+		if existing.Universe != universe || existing.Channel != channel {
+			parked = append(parked, existing)
+		}
+	}
+	if p != nil {
+		parked = append(parked, *p)
+	}
+	return parked
+}
+
+// parkedChannelFromInput resolves a park request to an output address,
+// from a fixture's address and a channel offset when a fixture is given.
+func (r *Resolver) parkedChannelFromInput(ctx context.Context, in generated.ParkChannelInput) (dmx.ParkedChannel, error) {
+	parked := dmx.ParkedChannel{Value: in.Value}
+	fixtureID := in.FixtureID.Value()
+	if fixtureID == nil {
+		universe, channel := in.Universe.Value(), in.Channel.Value()
+		if universe == nil || channel == nil {
+			return parked, errors.New("park a channel by universe and channel, or by fixtureId and offset")
+		}
+		parked.Universe, parked.Channel = *universe, *channel
+		return parked, nil
+	}
+
+	offset := in.Offset.Value()
+	if offset == nil {
+		return parked, errors.New("offset is required to park a fixture channel")
+	}
+	fixture, err := r.FixtureRepo.FindByID(ctx, *fixtureID)
+	if err != nil {
+		return parked, err
+	}
+	if fixture == nil {
+		return parked, fmt.Errorf("fixture not found: %s", *fixtureID)
+	}
+	channels, err := r.FixtureRepo.GetInstanceChannels(ctx, fixture.ID)
+	if err != nil {
+		return parked, err
+	}
+	found := false
+	for _, channel := range channels {
+		found = found || channel.Offset == *offset
+	}
+	if !found {
+		return parked, fmt.Errorf("fixture %s has no channel at offset %d", fixture.Name, *offset)
+	}
+	parked.Universe = fixture.Universe
+	parked.Channel = fixture.StartChannel + *offset
+	parked.FixtureID = &fixture.ID
+	return parked, nil
+}
+
+// convertParkedChannels converts dmx.ParkedChannel values to
+// generated.ParkedChannel.
+func convertParkedChannels(parked []dmx.ParkedChannel) []*generated.ParkedChannel {
+	result := make([]*generated.ParkedChannel, 0, len(parked))
+	for _, p := range parked {
+		result = append(result, &generated.ParkedChannel{
+			Universe:  p.Universe,
+			Channel:   p.Channel,
+			Value:     p.Value,
+			FixtureID: p.FixtureID,
+		})
+	}
+	return result
+}
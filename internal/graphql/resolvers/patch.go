@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/patch"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// addressConflictCode marks address conflict errors in their extensions.
+const addressConflictCode = "DMX_ADDRESS_CONFLICT"
+
+// projectPatch returns the patch of a project's fixtures.
+func (r *Resolver) projectPatch(ctx context.Context, projectID string) ([]patch.Fixture, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]patch.Fixture, len(fixtures))
+	for i := range fixtures {
+		result[i] = patch.FromInstance(&fixtures[i])
+	}
+	return result, nil
+}
+
+// validatePatch checks that a fixture's channels are free in its project.
+func (r *Resolver) validatePatch(ctx context.Context, fixture *models.FixtureInstance) error {
+	patched, err := r.projectPatch(ctx, fixture.ProjectID)
+	if err != nil {
+		return err
+	}
+	return patchError(ctx, patch.Validate(patch.FromInstance(fixture), patched))
+}
+
+// patchError gives address conflicts their details as error extensions so
+// clients can point at the fixtures in the way.
+func patchError(ctx context.Context, err error) error {
+	var conflict *patch.ConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
+	conflicts := make([]map[string]interface{}, len(conflict.Conflicts))
+	for i, c := range conflict.Conflicts {
+		conflicts[i] = map[string]interface{}{
+			"fixtureId":    c.FixtureID,
+			"fixtureName":  c.FixtureName,
+			"startChannel": c.StartChannel,
+			"endChannel":   c.EndChannel,
+		}
+	}
+	return &gqlerror.Error{
+		Err:     err,
+		Message: err.Error(),
+		Path:    graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{
+			"code":         addressConflictCode,
+			"universe":     conflict.Universe,
+			"startChannel": conflict.StartChannel,
+			"endChannel":   conflict.EndChannel,
+			"conflicts":    conflicts,
+		},
+	}
+}
+
+// suggestNextAddress finds the next free block of channels in a project.
+func (r *Resolver) suggestNextAddress(ctx context.Context, projectID string, channelCount int, universe, startChannel *int) (*generated.DmxAddressSuggestion, error) {
+	if channelCount < 1 || channelCount > dmx.UniverseSize {
+		return nil, fmt.Errorf("channel count must be between 1 and %d", dmx.UniverseSize)
+	}
+	from, fromChannel := 1, 1
+	if universe != nil {
+		from = *universe
+	}
+	if startChannel != nil {
+		fromChannel = *startChannel
+	}
+	if from < 1 || from > patch.MaxUniverse {
+		return nil, fmt.Errorf("universe must be between 1 and %d", patch.MaxUniverse)
+	}
+	if fromChannel < 1 || fromChannel > dmx.UniverseSize {
+		return nil, fmt.Errorf("start channel must be between 1 and %d", dmx.UniverseSize)
+	}
+
+	patched, err := r.projectPatch(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	foundUniverse, foundChannel, ok := patch.NextFreeAddress(patched, from, fromChannel, channelCount)
+	if !ok {
+		return nil, nil
+	}
+	return &generated.DmxAddressSuggestion{
+		Universe:     foundUniverse,
+		StartChannel: foundChannel,
+		EndChannel:   foundChannel + channelCount - 1,
+	}, nil
+}
+
+// validateBulkPatch checks the moved fixtures of a bulk update against the
+// patch as it will be once every fixture is updated.
+func (r *Resolver) validateBulkPatch(ctx context.Context, fixtures []*models.FixtureInstance, moved map[string]bool) error {
+	updated := make(map[string]patch.Fixture)
+	projects := make(map[string][]patch.Fixture)
+	for _, fixture := range fixtures {
+		updated[fixture.ID] = patch.FromInstance(fixture)
+		if _, ok := projects[fixture.ProjectID]; ok {
+			continue
+		}
+		patched, err := r.projectPatch(ctx, fixture.ProjectID)
+		if err != nil {
+			return err
+		}
+		projects[fixture.ProjectID] = patched
+	}
+	for _, patched := range projects {
+		for i, f := range patched {
+			if u, ok := updated[f.ID]; ok {
+				patched[i] = u
+			}
+		}
+	}
+
+	for _, fixture := range fixtures {
+		if !moved[fixture.ID] {
+			continue
+		}
+		if err := patch.Validate(updated[fixture.ID], projects[fixture.ProjectID]); err != nil {
+			return patchError(ctx, err)
+		}
+	}
+	return nil
+}
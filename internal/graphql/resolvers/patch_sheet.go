@@ -0,0 +1,463 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/patch"
+)
+
+// exportPatchSheet writes a project's fixtures as a CSV patch sheet.
+func (r *Resolver) exportPatchSheet(ctx context.Context, projectID string) (string, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if project == nil {
+		return "", fmt.Errorf("project not found: %s", projectID)
+	}
+
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	definitions := make(map[string]*models.FixtureDefinition)
+	rows := make([]patch.SheetRow, len(fixtures))
+	for i := range fixtures {
+		f := &fixtures[i]
+		def, ok := definitions[f.DefinitionID]
+		if !ok {
+			if def, err = r.FixtureRepo.FindDefinitionByID(ctx, f.DefinitionID); err != nil {
+				return "", err
+			}
+			definitions[f.DefinitionID] = def
+		}
+
+		row := patch.SheetRow{
+			Name:     f.Name,
+			Universe: f.Universe,
+			Address:  f.StartChannel,
+			Tags:     fixtureTags(f),
+			X:        f.LayoutX,
+			Y:        f.LayoutY,
+			Rotation: f.LayoutRotation,
+		}
+		if def != nil {
+			row.Manufacturer, row.Model = def.Manufacturer, def.Model
+		} else if f.Manufacturer != nil && f.Model != nil {
+			row.Manufacturer, row.Model = *f.Manufacturer, *f.Model
+		}
+		if f.ModeName != nil {
+			row.Mode = *f.ModeName
+		}
+		rows[i] = row
+	}
+
+	var b strings.Builder
+	if err := patch.WriteSheet(&b, rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// fixtureTags decodes a fixture's tags.
+func fixtureTags(f *models.FixtureInstance) []string {
+	if f.Tags == nil {
+		return nil
+	}
+	var tags []string
+	_ = json.Unmarshal([]byte(*f.Tags), &tags)
+	return tags
+}
+
+// patchSheetPlan is what importing a patch sheet row does.
+type patchSheetPlan struct {
+	row        patch.SheetRow
+	fixture    *models.FixtureInstance // The fixture the row updates; nil to create one
+	definition *models.FixtureDefinition
+	modeID     *string
+	setMode    bool // The fixture takes the definition and mode, rebuilding its channels
+	patched    patch.Fixture
+	moved      bool // The fixture's channels change, so must be checked for conflicts
+}
+
+// patchSheetImport matches a patch sheet to a project.
+type patchSheetImport struct {
+	r         *Resolver
+	projectID string
+	sheet     *patch.Sheet
+	result    *generated.PatchSheetImportResult
+
+	definitions map[string][]*models.FixtureDefinition
+	modes       map[string][]models.FixtureMode
+}
+
+// importPatchSheet applies a CSV patch sheet to a project. Rows that can't be
+// matched are reported and the rest are imported in one transaction.
+func (r *Resolver) importPatchSheet(ctx context.Context, projectID, csvContent string) (*generated.PatchSheetImportResult, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	sheet, err := patch.ReadSheet(strings.NewReader(csvContent))
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &patchSheetImport{
+		r:         r,
+		projectID: projectID,
+		sheet:     sheet,
+		result: &generated.PatchSheetImportResult{
+			Created:       []*models.FixtureInstance{},
+			Updated:       []*models.FixtureInstance{},
+			UnmatchedRows: []*generated.PatchSheetUnmatchedRow{},
+		},
+		modes: make(map[string][]models.FixtureMode),
+	}
+	for _, invalid := range sheet.Invalid {
+		imp.result.UnmatchedRows = append(imp.result.UnmatchedRows, &generated.PatchSheetUnmatchedRow{
+			Line:         invalid.Line,
+			Name:         invalid.Name,
+			Manufacturer: invalid.Manufacturer,
+			Model:        invalid.Model,
+			Reason:       invalid.Reason,
+		})
+	}
+
+	plans, existing, err := imp.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plans = imp.checkConflicts(plans, existing)
+
+	err = r.transaction(ctx, func(tx *Resolver) error {
+		return imp.apply(ctx, &mutationResolver{tx}, plans)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.refreshOutputLimits(ctx)
+
+	sort.SliceStable(imp.result.UnmatchedRows, func(i, j int) bool {
+		return imp.result.UnmatchedRows[i].Line < imp.result.UnmatchedRows[j].Line
+	})
+	return imp.result, nil
+}
+
+// unmatched reports a row that is not imported.
+func (imp *patchSheetImport) unmatched(row patch.SheetRow, reason string, args ...interface{}) {
+	imp.result.UnmatchedRows = append(imp.result.UnmatchedRows, &generated.PatchSheetUnmatchedRow{
+		Line:         row.Line,
+		Name:         row.Name,
+		Manufacturer: row.Manufacturer,
+		Model:        row.Model,
+		Reason:       fmt.Sprintf(reason, args...),
+	})
+}
+
+// plan matches each row to a fixture, definition and mode. It also returns
+// the project's fixtures.
+func (imp *patchSheetImport) plan(ctx context.Context) ([]*patchSheetPlan, []models.FixtureInstance, error) {
+	fixtures, err := imp.r.FixtureRepo.FindByProjectID(ctx, imp.projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string][]*models.FixtureInstance)
+	for i := range fixtures {
+		byName[fixtures[i].Name] = append(byName[fixtures[i].Name], &fixtures[i])
+	}
+
+	seen := make(map[string]bool)
+	var plans []*patchSheetPlan
+	for _, row := range imp.sheet.Rows {
+		if seen[row.Name] {
+			imp.unmatched(row, "%s appears more than once in the sheet", row.Name)
+			continue
+		}
+		seen[row.Name] = true
+
+		if len(byName[row.Name]) > 1 {
+			imp.unmatched(row, "the project has %d fixtures named %s", len(byName[row.Name]), row.Name)
+			continue
+		}
+		def, err := imp.findDefinition(ctx, row.Manufacturer, row.Model)
+		if err != nil {
+			return nil, nil, err
+		}
+		if def == nil {
+			imp.unmatched(row, "no fixture definition for %s %s", row.Manufacturer, row.Model)
+			continue
+		}
+
+		p := &patchSheetPlan{row: row, definition: def}
+		if len(byName[row.Name]) == 1 {
+			p.fixture = byName[row.Name][0]
+		}
+		var mode *models.FixtureMode
+		if row.Mode != "" {
+			if mode, err = imp.findMode(ctx, def.ID, row.Mode); err != nil {
+				return nil, nil, err
+			}
+			if mode == nil {
+				imp.unmatched(row, "%s %s has no mode %s", def.Manufacturer, def.Model, row.Mode)
+				continue
+			}
+			p.modeID = &mode.ID
+		}
+
+		channelCount := 0
+		if p.fixture != nil {
+			p.setMode = p.fixture.DefinitionID != def.ID
+			if imp.sheet.Has(patch.ColumnMode) && !sameMode(mode, p.fixture.ModeName) {
+				p.setMode = true
+			}
+			if !p.setMode {
+				p.modeID = nil
+				channelCount = patch.FromInstance(p.fixture).ChannelCount
+			}
+		}
+		if p.fixture == nil || p.setMode {
+			if channelCount, err = imp.channelCount(ctx, def.ID, p.modeID); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		p.patched = patch.Fixture{
+			ID:           fmt.Sprintf("sheet-line-%d", row.Line),
+			Name:         row.Name,
+			Universe:     row.Universe,
+			StartChannel: row.Address,
+			ChannelCount: max(channelCount, 1),
+		}
+		p.moved = true
+		if p.fixture != nil {
+			current := patch.FromInstance(p.fixture)
+			p.patched.ID = current.ID
+			p.moved = p.patched != current
+		}
+		plans = append(plans, p)
+	}
+	return plans, fixtures, nil
+}
+
+// checkConflicts leaves out rows whose channels would overlap another
+// fixture's. Leaving a row out keeps its fixture where it is, which can
+// block others in turn, so rows are checked until none conflict.
+func (imp *patchSheetImport) checkConflicts(plans []*patchSheetPlan, fixtures []models.FixtureInstance) []*patchSheetPlan {
+	for {
+		planned := make(map[string]bool)
+		var layout []patch.Fixture
+		for _, p := range plans {
+			layout = append(layout, p.patched)
+			planned[p.patched.ID] = true
+		}
+		for i := range fixtures {
+			if !planned[fixtures[i].ID] {
+				layout = append(layout, patch.FromInstance(&fixtures[i]))
+			}
+		}
+
+		var accepted []*patchSheetPlan
+		for _, p := range plans {
+			if p.moved {
+				if err := patch.Validate(p.patched, layout); err != nil {
+					imp.unmatched(p.row, "%s", err.Error())
+					continue
+				}
+			}
+			accepted = append(accepted, p)
+		}
+		if len(accepted) == len(plans) {
+			return accepted
+		}
+		plans = accepted
+	}
+}
+
+// apply imports the planned rows. Moves come first, all at once so fixtures
+// can swap addresses, then the other changes, then new fixtures, which may
+// take addresses the moves freed.
+func (imp *patchSheetImport) apply(ctx context.Context, m *mutationResolver, plans []*patchSheetPlan) error {
+	var moves []*generated.FixtureUpdateItem
+	changed := make(map[string]bool)
+	for _, p := range plans {
+		if p.fixture == nil || (p.fixture.Universe == p.row.Universe && p.fixture.StartChannel == p.row.Address) {
+			continue
+		}
+		moves = append(moves, &generated.FixtureUpdateItem{
+			FixtureID:    p.fixture.ID,
+			Universe:     graphql.OmittableOf(&p.row.Universe),
+			StartChannel: graphql.OmittableOf(&p.row.Address),
+		})
+		changed[p.fixture.ID] = true
+	}
+	if len(moves) > 0 {
+		if _, err := m.BulkUpdateFixtures(ctx, generated.BulkFixtureUpdateInput{Fixtures: moves}); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range plans {
+		if p.fixture == nil {
+			continue
+		}
+		input, ok := imp.updateInput(p)
+		if ok {
+			if _, err := m.UpdateFixtureInstance(ctx, p.fixture.ID, input, nil); err != nil {
+				return fmt.Errorf("line %d: %w", p.row.Line, err)
+			}
+			changed[p.fixture.ID] = true
+		}
+		if changed[p.fixture.ID] {
+			fixture, err := m.FixtureRepo.FindByID(ctx, p.fixture.ID)
+			if err != nil {
+				return err
+			}
+			imp.result.Updated = append(imp.result.Updated, fixture)
+		}
+	}
+
+	for _, p := range plans {
+		if p.fixture != nil {
+			continue
+		}
+		input := generated.CreateFixtureInstanceInput{
+			Name:         p.row.Name,
+			DefinitionID: p.definition.ID,
+			ModeID:       graphql.OmittableOf(p.modeID),
+			ProjectID:    imp.projectID,
+			Universe:     p.row.Universe,
+			StartChannel: p.row.Address,
+			Tags:         graphql.OmittableOf(p.row.Tags),
+		}
+		fixture, err := m.CreateFixtureInstance(ctx, input)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", p.row.Line, err)
+		}
+		if p.row.X != nil || p.row.Y != nil || p.row.Rotation != nil {
+			fixture.LayoutX, fixture.LayoutY, fixture.LayoutRotation = p.row.X, p.row.Y, p.row.Rotation
+			if err := m.FixtureRepo.Update(ctx, fixture); err != nil {
+				return err
+			}
+		}
+		imp.result.Created = append(imp.result.Created, fixture)
+	}
+	return nil
+}
+
+// updateInput builds the update a row makes to its fixture, other than
+// moving it. It reports false if the row changes nothing.
+func (imp *patchSheetImport) updateInput(p *patchSheetPlan) (generated.UpdateFixtureInstanceInput, bool) {
+	var input generated.UpdateFixtureInstanceInput
+	ok := false
+	if p.setMode {
+		input.DefinitionID = graphql.OmittableOf(&p.definition.ID)
+		input.ModeID = graphql.OmittableOf(p.modeID)
+		ok = true
+	}
+	if imp.sheet.Has(patch.ColumnTags) && strings.Join(p.row.Tags, "\x00") != strings.Join(fixtureTags(p.fixture), "\x00") {
+		input.Tags = graphql.OmittableOf(p.row.Tags)
+		ok = true
+	}
+	position := []struct {
+		column  string
+		value   *float64
+		current *float64
+		field   *graphql.Omittable[*float64]
+	}{
+		{patch.ColumnX, p.row.X, p.fixture.LayoutX, &input.LayoutX},
+		{patch.ColumnY, p.row.Y, p.fixture.LayoutY, &input.LayoutY},
+		{patch.ColumnRotation, p.row.Rotation, p.fixture.LayoutRotation, &input.LayoutRotation},
+	}
+	for _, pos := range position {
+		if imp.sheet.Has(pos.column) && !sameFloat(pos.value, pos.current) {
+			*pos.field = graphql.OmittableOf(pos.value)
+			ok = true
+		}
+	}
+	return input, ok
+}
+
+// findDefinition finds a definition by manufacturer and model, ignoring
+// case if there is no exact match.
+func (imp *patchSheetImport) findDefinition(ctx context.Context, manufacturer, model string) (*models.FixtureDefinition, error) {
+	if imp.definitions == nil {
+		all, err := imp.r.FixtureRepo.FindAllDefinitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		imp.definitions = make(map[string][]*models.FixtureDefinition)
+		for i := range all {
+			key := definitionKey(all[i].Manufacturer, all[i].Model)
+			imp.definitions[key] = append(imp.definitions[key], &all[i])
+		}
+	}
+
+	candidates := imp.definitions[definitionKey(manufacturer, model)]
+	for _, def := range candidates {
+		if def.Manufacturer == manufacturer && def.Model == model {
+			return def, nil
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return nil, nil
+}
+
+func definitionKey(manufacturer, model string) string {
+	return strings.ToLower(manufacturer) + "\x00" + strings.ToLower(model)
+}
+
+// findMode finds a definition's mode by name, ignoring case.
+func (imp *patchSheetImport) findMode(ctx context.Context, definitionID, name string) (*models.FixtureMode, error) {
+	modes, ok := imp.modes[definitionID]
+	if !ok {
+		var err error
+		if modes, err = imp.r.FixtureRepo.GetDefinitionModes(ctx, definitionID); err != nil {
+			return nil, err
+		}
+		imp.modes[definitionID] = modes
+	}
+	for i := range modes {
+		if strings.EqualFold(modes[i].Name, name) {
+			return &modes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// channelCount returns the channels a fixture of a definition and mode has.
+func (imp *patchSheetImport) channelCount(ctx context.Context, definitionID string, modeID *string) (int, error) {
+	if modeID != nil {
+		channels, err := imp.r.FixtureRepo.GetModeChannels(ctx, *modeID)
+		return len(channels), err
+	}
+	channels, err := imp.r.FixtureRepo.GetDefinitionChannels(ctx, definitionID)
+	return len(channels), err
+}
+
+// sameMode reports whether a fixture's mode name is a mode.
+func sameMode(mode *models.FixtureMode, name *string) bool {
+	if mode == nil || name == nil {
+		return mode == nil && name == nil
+	}
+	return strings.EqualFold(mode.Name, *name)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,293 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+	"github.com/bbernstein/lacylights-go/internal/services/pixelmap"
+)
+
+// createPixelMatrix adds a pixel matrix to a project.
+func (r *Resolver) createPixelMatrix(ctx context.Context, input generated.CreatePixelMatrixInput) (*models.PixelMatrix, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	matrix := &models.PixelMatrix{
+		ProjectID: input.ProjectID,
+		Name:      strings.TrimSpace(input.Name),
+		Width:     input.Width,
+		Height:    input.Height,
+	}
+	if err := r.setPixelMatrixPixels(ctx, matrix, pixelsFromInput(input.Pixels.Value())); err != nil {
+		return nil, err
+	}
+	if err := r.PixelMatrixRepo.Create(ctx, matrix); err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// updatePixelMatrix changes a pixel matrix's name, size, or pixels,
+// releasing it if it is showing.
+func (r *Resolver) updatePixelMatrix(ctx context.Context, id string, input generated.UpdatePixelMatrixInput) (*models.PixelMatrix, error) {
+	matrix, err := r.findPixelMatrix(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v := input.Name.Value(); v != nil {
+		matrix.Name = strings.TrimSpace(*v)
+	}
+	if v := input.Width.Value(); v != nil {
+		matrix.Width = *v
+	}
+	if v := input.Height.Value(); v != nil {
+		matrix.Height = *v
+	}
+	pixels, err := matrixPixels(matrix)
+	if err != nil {
+		return nil, err
+	}
+	if v := input.Pixels.Value(); v != nil {
+		pixels = pixelsFromInput(v)
+	}
+	if err := r.setPixelMatrixPixels(ctx, matrix, pixels); err != nil {
+		return nil, err
+	}
+
+	if err := r.PixelMatrixRepo.Update(ctx, matrix); err != nil {
+		return nil, err
+	}
+	r.PixelMapService.Forget(id)
+	return matrix, nil
+}
+
+// deletePixelMatrix removes a pixel matrix, releasing it if it is showing.
+func (r *Resolver) deletePixelMatrix(ctx context.Context, id string) error {
+	if _, err := r.findPixelMatrix(ctx, id); err != nil {
+		return err
+	}
+	if err := r.PixelMatrixRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.PixelMapService.Forget(id)
+	return nil
+}
+
+// deleteProjectPixelMatrices removes a project's pixel matrices, releasing
+// those showing.
+func (r *Resolver) deleteProjectPixelMatrices(ctx context.Context, projectID string) error {
+	matrices, err := r.PixelMatrixRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := r.PixelMatrixRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+	for _, matrix := range matrices {
+		r.PixelMapService.Forget(matrix.ID)
+	}
+	return nil
+}
+
+// showPixelMatrixImage shows a base64 encoded image on a pixel matrix.
+func (r *Resolver) showPixelMatrixImage(ctx context.Context, matrixID, image string) (*models.PixelMatrix, error) {
+	matrix, err := r.findPixelMatrix(ctx, matrixID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(image)
+	if err != nil {
+		return nil, fmt.Errorf("image must be base64 encoded: %w", err)
+	}
+	img, err := pixelmap.DecodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.PixelMapService.Show(ctx, matrixID, pixelmap.Image(img)); err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// showPixelMatrixGradient shows a gradient through hex colors on a pixel
+// matrix.
+func (r *Resolver) showPixelMatrixGradient(ctx context.Context, matrixID string, hexColors []string, angle *float64) (*models.PixelMatrix, error) {
+	matrix, err := r.findPixelMatrix(ctx, matrixID)
+	if err != nil {
+		return nil, err
+	}
+	colors := make([]color.Color, len(hexColors))
+	for i, hex := range hexColors {
+		if colors[i], err = color.ParseHex(hex); err != nil {
+			return nil, err
+		}
+	}
+	degrees := 0.0
+	if angle != nil {
+		degrees = *angle
+	}
+	if err := r.PixelMapService.Show(ctx, matrixID, pixelmap.Gradient(colors, degrees)); err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// releasePixelMatrix hands a pixel matrix's fixtures back to the live look.
+func (r *Resolver) releasePixelMatrix(ctx context.Context, matrixID string) (*models.PixelMatrix, error) {
+	matrix, err := r.findPixelMatrix(ctx, matrixID)
+	if err != nil {
+		return nil, err
+	}
+	r.PixelMapService.Release(matrixID)
+	return matrix, nil
+}
+
+// findPixelMatrix loads a pixel matrix by ID.
+func (r *Resolver) findPixelMatrix(ctx context.Context, id string) (*models.PixelMatrix, error) {
+	matrix, err := r.PixelMatrixRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if matrix == nil {
+		return nil, fmt.Errorf("pixel matrix not found: %s", id)
+	}
+	return matrix, nil
+}
+
+// setPixelMatrixPixels checks a matrix's name, size, and pixels against
+// its project's fixtures and stores the pixels on it.
+func (r *Resolver) setPixelMatrixPixels(ctx context.Context, matrix *models.PixelMatrix, pixels []pixelmap.Pixel) error {
+	if matrix.Name == "" {
+		return errors.New("pixel matrix name is required")
+	}
+	fixtures, err := r.pixelMapFixtures(ctx, matrix.ProjectID)
+	if err != nil {
+		return err
+	}
+	if _, err := pixelmap.NewMapping(matrix.ID, matrix.Width, matrix.Height, pixels, fixtures); err != nil {
+		return err
+	}
+	pixelsJSON, err := json.Marshal(pixels)
+	if err != nil {
+		return err
+	}
+	matrix.Pixels = string(pixelsJSON)
+	return nil
+}
+
+// pixelMatrixMapping loads a matrix's mapping for the pixel map service.
+// Pixels of fixtures deleted or repatched with fewer cells since the matrix
+// was saved are left out.
+func (r *Resolver) pixelMatrixMapping(ctx context.Context, matrixID string) (*pixelmap.Mapping, error) {
+	matrix, err := r.PixelMatrixRepo.FindByID(ctx, matrixID)
+	if err != nil {
+		return nil, err
+	}
+	if matrix == nil {
+		return nil, pixelmap.ErrMatrixNotFound
+	}
+	pixels, err := matrixPixels(matrix)
+	if err != nil {
+		return nil, err
+	}
+	fixtures, err := r.pixelMapFixtures(ctx, matrix.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	cellCounts := make(map[string]int, len(fixtures))
+	for _, fixture := range fixtures {
+		cellCounts[fixture.ID] = len(fixture.Cells())
+	}
+	valid := pixels[:0]
+	for _, p := range pixels {
+		if p.Cell < cellCounts[p.FixtureID] {
+			valid = append(valid, p)
+		}
+	}
+	return pixelmap.NewMapping(matrix.ID, matrix.Width, matrix.Height, valid, fixtures)
+}
+
+// pixelMapFixtures returns the addresses, channels, and defined cells of a
+// project's fixtures.
+func (r *Resolver) pixelMapFixtures(ctx context.Context, projectID string) ([]pixelmap.Fixture, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	instanceChannels, err := r.FixtureRepo.GetProjectInstanceChannels(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	channelsByFixture := make(map[string][]color.Channel)
+	cellsByFixture := make(map[string]map[int]int)
+	for _, c := range instanceChannels {
+		channelsByFixture[c.FixtureID] = append(channelsByFixture[c.FixtureID], color.Channel{Offset: c.Offset, Type: c.Type})
+		if c.Cell != nil {
+			if cellsByFixture[c.FixtureID] == nil {
+				cellsByFixture[c.FixtureID] = make(map[int]int)
+			}
+			cellsByFixture[c.FixtureID][c.Offset] = *c.Cell
+		}
+	}
+
+	result := make([]pixelmap.Fixture, len(fixtures))
+	for i, fixture := range fixtures {
+		result[i] = pixelmap.Fixture{
+			ID:           fixture.ID,
+			Universe:     fixture.Universe,
+			StartChannel: fixture.StartChannel,
+			Channels:     channelsByFixture[fixture.ID],
+			ChannelCells: cellsByFixture[fixture.ID],
+		}
+	}
+	return result, nil
+}
+
+// matrixPixels decodes a stored matrix's pixels.
+func matrixPixels(matrix *models.PixelMatrix) ([]pixelmap.Pixel, error) {
+	var pixels []pixelmap.Pixel
+	if err := json.Unmarshal([]byte(matrix.Pixels), &pixels); err != nil {
+		return nil, fmt.Errorf("invalid pixels for pixel matrix %s: %w", matrix.ID, err)
+	}
+	return pixels, nil
+}
+
+// pixelsFromInput converts pixel inputs, each defaulting to its fixture's
+// first cell.
+func pixelsFromInput(inputs []*generated.PixelMatrixPixelInput) []pixelmap.Pixel {
+	pixels := make([]pixelmap.Pixel, len(inputs))
+	for i, input := range inputs {
+		pixels[i] = pixelmap.Pixel{X: input.X, Y: input.Y, FixtureID: input.FixtureID}
+		if cell := input.Cell.Value(); cell != nil {
+			pixels[i].Cell = *cell
+		}
+	}
+	return pixels
+}
+
+// convertPixelMatrixPixels converts a stored matrix's pixels to the
+// GraphQL type.
+func convertPixelMatrixPixels(matrix *models.PixelMatrix) ([]*generated.PixelMatrixPixel, error) {
+	pixels, err := matrixPixels(matrix)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.PixelMatrixPixel, len(pixels))
+	for i, p := range pixels {
+		result[i] = &generated.PixelMatrixPixel{X: p.X, Y: p.Y, FixtureID: p.FixtureID, Cell: p.Cell}
+	}
+	return result, nil
+}
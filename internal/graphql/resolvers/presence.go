@@ -0,0 +1,46 @@
+package resolvers
+
+import (
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/presence"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// wirePresence publishes each project's sessions whenever they change.
+func (r *Resolver) wirePresence() {
+	r.PresenceService.SetChangeCallback(func(projectID string) {
+		r.PubSub.Publish(pubsub.TopicPresence, projectID, r.projectPresence(projectID))
+	})
+}
+
+// projectPresence returns the sessions present in a project.
+func (r *Resolver) projectPresence(projectID string) *generated.ProjectPresence {
+	sessions := r.PresenceService.List(projectID)
+	result := &generated.ProjectPresence{
+		ProjectID: projectID,
+		Sessions:  make([]*generated.Presence, len(sessions)),
+	}
+	for i, p := range sessions {
+		result.Sessions[i] = convertPresence(p)
+	}
+	return result
+}
+
+// convertPresence converts a presence.Presence to generated.Presence.
+func convertPresence(p *presence.Presence) *generated.Presence {
+	result := &generated.Presence{
+		SessionID: p.SessionID,
+		ProjectID: p.ProjectID,
+		Operator:  p.Operator,
+		UserID:    p.UserID,
+		EntityID:  p.EntityID,
+		Activity:  generated.PresenceActivity(p.Activity),
+		Since:     p.Since.Format("2006-01-02T15:04:05.000Z"),
+		UpdatedAt: formatUpdatedAt(p.UpdatedAt),
+	}
+	if p.EntityType != nil {
+		entityType := generated.PresenceEntityType(*p.EntityType)
+		result.EntityType = &entityType
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/preview"
+)
+
+// startPreviewSession starts a preview session, blind if requested. Preview
+// outputs only apply to blind sessions, whose edits are otherwise invisible.
+// An offset mirrors each universe the project uses that no preview output
+// names; a visualizer address makes the preview universes shadow universes
+// sent only there.
+func (r *Resolver) startPreviewSession(ctx context.Context, projectID string, blind bool, previewOutputs []*generated.PreviewOutputInput, previewUniverseOffset *int, visualizerAddress *string) (*preview.Session, error) {
+	if !blind {
+		if len(previewOutputs) > 0 || previewUniverseOffset != nil || visualizerAddress != nil {
+			return nil, fmt.Errorf("preview outputs require a blind session")
+		}
+		return r.PreviewService.StartSession(ctx, projectID, nil)
+	}
+
+	previewUniverses := make(map[int]int, len(previewOutputs))
+	used := make(map[int]bool, len(previewOutputs))
+	for _, output := range previewOutputs {
+		if _, ok := previewUniverses[output.Universe]; ok {
+			return nil, fmt.Errorf("universe %d has more than one preview output", output.Universe)
+		}
+		if used[output.PreviewUniverse] {
+			return nil, fmt.Errorf("preview universe %d is used more than once", output.PreviewUniverse)
+		}
+		previewUniverses[output.Universe] = output.PreviewUniverse
+		used[output.PreviewUniverse] = true
+	}
+	if previewUniverseOffset != nil {
+		if err := r.offsetPreviewUniverses(ctx, projectID, *previewUniverseOffset, previewUniverses, used); err != nil {
+			return nil, err
+		}
+	}
+
+	if visualizerAddress == nil {
+		return r.PreviewService.StartBlindSession(ctx, projectID, nil, previewUniverses)
+	}
+	if len(previewUniverses) == 0 {
+		return nil, fmt.Errorf("a visualizer address requires preview outputs or a preview universe offset")
+	}
+	return r.PreviewService.StartVisualizerSession(ctx, projectID, nil, previewUniverses, *visualizerAddress)
+}
+
+// offsetPreviewUniverses adds a preview universe offset from each universe
+// the project's fixtures use to previewUniverses, skipping universes that
+// already have one.
+func (r *Resolver) offsetPreviewUniverses(ctx context.Context, projectID string, offset int, previewUniverses map[int]int, used map[int]bool) error {
+	if offset == 0 {
+		return fmt.Errorf("preview universe offset must not be 0")
+	}
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	universes := make(map[int]bool)
+	for _, fixture := range fixtures {
+		universes[fixture.Universe] = true
+	}
+	sorted := make([]int, 0, len(universes))
+	for universe := range universes {
+		sorted = append(sorted, universe)
+	}
+	sort.Ints(sorted)
+
+	for _, universe := range sorted {
+		if _, ok := previewUniverses[universe]; ok {
+			continue
+		}
+		previewUniverse := universe + offset
+		if previewUniverse < 1 {
+			return fmt.Errorf("universe %d offset by %d is not a universe", universe, offset)
+		}
+		if used[previewUniverse] {
+			return fmt.Errorf("preview universe %d is used more than once", previewUniverse)
+		}
+		previewUniverses[universe] = previewUniverse
+		used[previewUniverse] = true
+	}
+	return nil
+}
+
+// commitPreviewSession commits a preview session. A blind session's edits go
+// into the scene it was initialized with, or else to the live output.
+func (r *Resolver) commitPreviewSession(ctx context.Context, sessionID string) (bool, error) {
+	session := r.PreviewService.GetSession(sessionID)
+	if session != nil && session.Blind {
+		edits := r.PreviewService.GetEdits(sessionID)
+		if session.SceneID != nil {
+			if err := r.commitPreviewToScene(ctx, *session.SceneID, edits); err != nil {
+				return false, err
+			}
+		} else {
+			for _, edit := range edits {
+				r.DMXService.SetChannelValue(edit.Universe, edit.Channel, byte(edit.Value))
+			}
+		}
+	}
+	return r.PreviewService.CommitSession(ctx, sessionID)
+}
+
+// commitPreviewToScene merges a blind session's edits into a scene.
+func (r *Resolver) commitPreviewToScene(ctx context.Context, sceneID string, edits []preview.Edit) error {
+	channels := make(map[string][]models.ChannelValue)
+	for _, edit := range edits {
+		channels[edit.FixtureID] = append(channels[edit.FixtureID], models.ChannelValue{Offset: edit.Offset, Value: edit.Value})
+	}
+	for fixtureID, values := range channels {
+		if err := r.mergeSceneChannels(ctx, sceneID, fixtureID, values); err != nil {
+			return err
+		}
+	}
+
+	if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
+		log.Warn("failed to re-apply active scene after committing preview", "error", err)
+	}
+	r.refreshSubmasters(ctx)
+	return nil
+}
+
+// previewOutputs returns a preview session's preview outputs by universe.
+func (r *Resolver) previewOutputs(sessionID string) []*generated.PreviewOutput {
+	session := r.PreviewService.GetSession(sessionID)
+	if session == nil {
+		return []*generated.PreviewOutput{}
+	}
+
+	outputs := make([]*generated.PreviewOutput, 0, len(session.PreviewUniverses))
+	for universe, previewUniverse := range session.PreviewUniverses {
+		outputs = append(outputs, &generated.PreviewOutput{Universe: universe, PreviewUniverse: previewUniverse})
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Universe < outputs[j].Universe })
+	return outputs
+}
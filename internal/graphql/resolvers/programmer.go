@@ -0,0 +1,74 @@
+package resolvers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/patch"
+)
+
+// programmerChannels lists the programmer in universe and channel order.
+func (r *Resolver) programmerChannels() []*generated.ProgrammerChannel {
+	result := []*generated.ProgrammerChannel{}
+	for universe, channels := range r.DMXService.ProgrammerValues() {
+		for channel, value := range channels {
+			result = append(result, &generated.ProgrammerChannel{Universe: universe, Channel: channel, Value: int(value)})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Universe != result[j].Universe {
+			return result[i].Universe < result[j].Universe
+		}
+		return result[i].Channel < result[j].Channel
+	})
+	return result
+}
+
+// programmerFixtureValues maps the programmer onto a project's fixtures,
+// leaving out fixtures with nothing programmed.
+func (r *Resolver) programmerFixtureValues(ctx context.Context, projectID string) ([]capturedValue, error) {
+	programmer := r.DMXService.ProgrammerValues()
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var captured []capturedValue
+	for i := range fixtures {
+		fixture := patch.FromInstance(&fixtures[i])
+		var channels []models.ChannelValue
+		for offset := 0; offset < fixture.ChannelCount; offset++ {
+			if value, ok := programmer[fixture.Universe][fixture.StartChannel+offset]; ok {
+				channels = append(channels, models.ChannelValue{Offset: offset, Value: int(value)})
+			}
+		}
+		if len(channels) > 0 {
+			captured = append(captured, capturedValue{fixtureID: fixture.ID, channels: channels})
+		}
+	}
+	return captured, nil
+}
+
+// recordProgrammerToScene saves the programmer's values on a project's
+// fixtures into a new or existing scene, clearing the programmer after if
+// asked.
+func (r *Resolver) recordProgrammerToScene(ctx context.Context, projectID string, sceneID, name *string, clear bool) (*models.Scene, error) {
+	if err := r.validateCaptureTarget(ctx, projectID, sceneID, name); err != nil {
+		return nil, err
+	}
+	captured, err := r.programmerFixtureValues(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	scene, err := r.saveCapturedScene(ctx, projectID, sceneID, name, "Record", captured)
+	if err != nil {
+		return nil, err
+	}
+	if clear {
+		r.DMXService.ClearProgrammer()
+		r.replicateProgrammer()
+	}
+	return scene, nil
+}
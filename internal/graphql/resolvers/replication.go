@@ -0,0 +1,207 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/replication"
+)
+
+// replicationKeyProgrammer is the key the programmer is replicated under.
+const replicationKeyProgrammer = "values"
+
+// wireReplication records live state for a tracking backup, and applies the
+// primary's state when this instance is the backup. Cue list follows hold
+// while this instance's output is passive.
+func (r *Resolver) wireReplication() {
+	r.ReplicationService.SetTracker(&replicationTracker{r: r})
+	r.ReplicationService.SetActiveCallback(func(active bool) {
+		if active {
+			r.PlaybackService.Resume()
+		} else {
+			r.PlaybackService.Suspend()
+		}
+	})
+	r.SettingRepo.OnChange(func(key string, value *string) {
+		if value == nil {
+			r.replicate(replication.KindSetting, key, nil)
+		} else {
+			r.replicate(replication.KindSetting, key, *value)
+		}
+	})
+	r.PlaybackService.SetReplicator(r.ReplicationService)
+	// Backups would otherwise bypass signing in
+	r.ReplicationService.SetTokenRequired(r.AuthService.Enabled)
+}
+
+// StartReplication starts replicating in the configured role, first
+// recording the live state a backup starts from: settings, masters,
+// blackout, and the programmer. Active cues are recorded as they change.
+func (r *Resolver) StartReplication(cfg replication.Config) error {
+	settings, err := r.SettingRepo.FindAll(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	for _, setting := range settings {
+		r.replicate(replication.KindSetting, setting.Key, setting.Value)
+	}
+
+	levels := r.DMXService.MasterLevels()
+	if levels.GrandMaster != 1 {
+		r.replicate(journalKindMaster, journalKeyGrandMaster, levels.GrandMaster)
+	}
+	for _, master := range levels.Universes {
+		if master.Level != 1 {
+			r.replicate(journalKindMaster, strconv.Itoa(master.Universe), master.Level)
+		}
+	}
+	if status := r.DMXService.BlackoutStatus(); status.Active {
+		r.replicate(journalKindBlackout, journalKeyBlackout, status.Since.UTC())
+	}
+	r.replicateProgrammer()
+
+	return r.ReplicationService.Start(cfg)
+}
+
+// replicate records state for a tracking backup, or removes it when value
+// is nil.
+func (r *Resolver) replicate(kind, key string, value any) {
+	var err error
+	if value == nil {
+		err = r.ReplicationService.Delete(kind, key)
+	} else {
+		err = r.ReplicationService.Put(kind, key, value)
+	}
+	if err != nil {
+		log.Warn("failed to replicate state", "kind", kind, "key", key, "error", err)
+	}
+}
+
+// replicateProgrammer records the programmer for a tracking backup.
+func (r *Resolver) replicateProgrammer() {
+	r.replicate(replication.KindProgrammer, replicationKeyProgrammer, r.DMXService.ProgrammerValues())
+}
+
+// replicationTracker applies a primary's state on a tracking backup.
+type replicationTracker struct {
+	r *Resolver
+}
+
+// Track implements replication.Tracker.
+func (t *replicationTracker) Track(kind, key string, value json.RawMessage) error {
+	ctx := context.Background()
+	switch kind {
+	case playback.JournalKindActiveCue:
+		var cue playback.JournalCue
+		if err := json.Unmarshal(value, &cue); err != nil {
+			return err
+		}
+		return t.r.PlaybackService.TrackCue(ctx, key, cue)
+	case journalKindMaster:
+		var level float64
+		if err := json.Unmarshal(value, &level); err != nil {
+			return err
+		}
+		return t.r.applyMasterLevel(key, level)
+	case journalKindBlackout:
+		_, err := t.r.DMXService.Blackout(0)
+		return err
+	case replication.KindProgrammer:
+		var values map[int]map[int]byte
+		if err := json.Unmarshal(value, &values); err != nil {
+			return err
+		}
+		t.r.DMXService.SetProgrammer(values)
+		return nil
+	case replication.KindSetting:
+		var setting string
+		if err := json.Unmarshal(value, &setting); err != nil {
+			return err
+		}
+		if _, err := t.r.SettingRepo.Upsert(ctx, key, setting); err != nil {
+			return err
+		}
+		t.r.SettingsService.Notify(ctx, key)
+		return nil
+	}
+	return fmt.Errorf("unknown state kind %q", kind)
+}
+
+// Untrack implements replication.Tracker.
+func (t *replicationTracker) Untrack(kind, key string) error {
+	ctx := context.Background()
+	switch kind {
+	case playback.JournalKindActiveCue:
+		instant := 0.0
+		t.r.PlaybackService.ReleaseCueList(key, &instant)
+		return nil
+	case journalKindMaster:
+		return t.r.applyMasterLevel(key, 1)
+	case journalKindBlackout:
+		_, err := t.r.DMXService.RestoreFromBlackout(0)
+		return err
+	case replication.KindProgrammer:
+		t.r.DMXService.ClearProgrammer()
+		return nil
+	case replication.KindSetting:
+		if err := t.r.SettingRepo.Delete(ctx, key); err != nil {
+			return err
+		}
+		t.r.SettingsService.Notify(ctx, key)
+		return nil
+	}
+	return fmt.Errorf("unknown state kind %q", kind)
+}
+
+// Reset implements replication.Tracker, releasing every cue list and
+// returning masters, blackout, and the programmer to normal. Settings are
+// kept; the primary's snapshot replaces them.
+func (t *replicationTracker) Reset() {
+	instant := 0.0
+	for _, status := range t.r.PlaybackService.ActiveCueLists() {
+		t.r.PlaybackService.ReleaseCueList(status.CueListID, &instant)
+	}
+	if err := t.r.DMXService.SetGrandMaster(1); err != nil {
+		log.Warn("failed to reset the grand master", "error", err)
+	}
+	for _, master := range t.r.DMXService.MasterLevels().Universes {
+		if err := t.r.DMXService.SetUniverseMaster(master.Universe, 1); err != nil {
+			log.Warn("failed to reset a universe master", "universe", master.Universe, "error", err)
+		}
+	}
+	if t.r.DMXService.BlackoutStatus().Active {
+		if _, err := t.r.DMXService.RestoreFromBlackout(0); err != nil {
+			log.Warn("failed to restore from blackout", "error", err)
+		}
+	}
+	t.r.DMXService.ClearProgrammer()
+}
+
+// convertReplicationStatus converts a replication.Status to
+// generated.ReplicationStatus.
+func convertReplicationStatus(status *replication.Status) *generated.ReplicationStatus {
+	result := &generated.ReplicationStatus{
+		Role:          generated.ReplicationRole(status.Role),
+		InstanceID:    status.InstanceID,
+		OutputActive:  status.OutputActive,
+		PeerConnected: status.PeerConnected,
+		StateEntries:  status.StateEntries,
+	}
+	if status.PeerInstanceID != "" {
+		peerID := status.PeerInstanceID
+		result.PeerInstanceID = &peerID
+	}
+	if status.LastHeartbeat != nil {
+		lastHeartbeat := status.LastHeartbeat.UTC().Format("2006-01-02T15:04:05.000Z")
+		result.LastHeartbeatAt = &lastHeartbeat
+	}
+	if status.TakenOverAt != nil {
+		takenOverAt := status.TakenOverAt.UTC().Format("2006-01-02T15:04:05.000Z")
+		result.TakenOverAt = &takenOverAt
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/report"
+)
+
+// generateReport renders a report of a project and issues a one-time link
+// to it.
+func (r *Resolver) generateReport(ctx context.Context, projectID string, reportType generated.ReportType, cueListID *string) (*generated.ReportDownload, error) {
+	rendered, err := r.ReportService.Generate(ctx, projectID, report.Type(reportType), report.Options{CueListID: cueListID})
+	if err != nil {
+		return nil, err
+	}
+	url, expiresAt := r.ReportDownloads.Issue(rendered)
+	return &generated.ReportDownload{
+		URL:       url,
+		FileName:  rendered.FileName,
+		ExpiresAt: expiresAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}, nil
+}
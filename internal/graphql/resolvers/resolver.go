@@ -2,19 +2,53 @@
 package resolvers
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/graphql/loaders"
+	"github.com/bbernstein/lacylights-go/internal/services/audio"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"github.com/bbernstein/lacylights-go/internal/services/backup"
+	"github.com/bbernstein/lacylights-go/internal/services/discovery"
 	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/dmxrecord"
+	"github.com/bbernstein/lacylights-go/internal/services/dmxstream"
+	"github.com/bbernstein/lacylights-go/internal/services/effects"
 	"github.com/bbernstein/lacylights-go/internal/services/export"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/gdtf"
+	"github.com/bbernstein/lacylights-go/internal/services/houselights"
 	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/input"
+	"github.com/bbernstein/lacylights-go/internal/services/integrity"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/librarysync"
+	"github.com/bbernstein/lacylights-go/internal/services/macro"
 	"github.com/bbernstein/lacylights-go/internal/services/ofl"
+	"github.com/bbernstein/lacylights-go/internal/services/pixelmap"
 	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/presence"
 	"github.com/bbernstein/lacylights-go/internal/services/preview"
 	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/recorder"
+	"github.com/bbernstein/lacylights-go/internal/services/replication"
+	"github.com/bbernstein/lacylights-go/internal/services/report"
+	"github.com/bbernstein/lacylights-go/internal/services/sceneboard"
+	"github.com/bbernstein/lacylights-go/internal/services/scenesummary"
+	"github.com/bbernstein/lacylights-go/internal/services/scheduler"
+	"github.com/bbernstein/lacylights-go/internal/services/schemainfo"
+	"github.com/bbernstein/lacylights-go/internal/services/settings"
+	"github.com/bbernstein/lacylights-go/internal/services/showtimer"
+	"github.com/bbernstein/lacylights-go/internal/services/snapshot"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+	"github.com/bbernstein/lacylights-go/internal/services/stageview"
+	"github.com/bbernstein/lacylights-go/internal/services/standby"
+	"github.com/bbernstein/lacylights-go/internal/services/tempo"
+	"github.com/bbernstein/lacylights-go/internal/services/timecode"
 	"github.com/bbernstein/lacylights-go/internal/services/version"
 	"github.com/bbernstein/lacylights-go/internal/services/wifi"
 	"gorm.io/gorm"
@@ -25,27 +59,99 @@ import (
 type Resolver struct {
 	db *gorm.DB
 
+	// pendingEvents holds the events published in an open transaction until
+	// it commits; nil outside one
+	pendingEvents *[]pendingEvent
+
 	// Repositories
-	ProjectRepo    *repositories.ProjectRepository
-	SettingRepo    *repositories.SettingRepository
-	FixtureRepo    *repositories.FixtureRepository
-	SceneRepo      *repositories.SceneRepository
-	CueListRepo    *repositories.CueListRepository
-	CueRepo        *repositories.CueRepository
-	SceneBoardRepo *repositories.SceneBoardRepository
+	ProjectRepo      *repositories.ProjectRepository
+	SettingRepo      *repositories.SettingRepository
+	FixtureRepo      *repositories.FixtureRepository
+	FixtureGroupRepo *repositories.FixtureGroupRepository
+	PaletteRepo      *repositories.PaletteRepository
+	UndoRepo         *repositories.UndoRepository
+	SnapshotRepo     *repositories.SnapshotRepository
+	AuditRepo        *repositories.AuditRepository
+	UserRepo         *repositories.UserRepository
+	SceneRepo        *repositories.SceneRepository
+	CueListRepo      *repositories.CueListRepository
+	CueRepo          *repositories.CueRepository
+	SceneBoardRepo   *repositories.SceneBoardRepository
+	UniverseRepo     *repositories.UniverseRepository
+	LayoutZoneRepo   *repositories.LayoutZoneRepository
+	SelectionSetRepo *repositories.SelectionSetRepository
+	HouseLightsRepo  *repositories.HouseLightsRepository
+	PixelMatrixRepo  *repositories.PixelMatrixRepository
 
 	// Services
-	DMXService      *dmx.Service
-	FadeEngine      *fade.Engine
-	PlaybackService *playback.Service
-	ExportService   *export.Service
-	ImportService   *importservice.Service
-	OFLService      *ofl.Service
-	OFLManager      *ofl.Manager
-	PreviewService  *preview.Service
-	VersionService  *version.Service
-	WiFiService     *wifi.Service
-	PubSub          *pubsub.PubSub
+	DMXService         *dmx.Service
+	FadeEngine         *fade.Engine
+	EffectService      *effects.Service
+	PlaybackService    *playback.Service
+	ExportService      *export.Service
+	ArchiveDownloads   *export.Downloads
+	ReportService      *report.Service
+	ReportDownloads    *report.Downloads
+	ImportService      *importservice.Service
+	IntegrityService   *integrity.Service
+	OFLService         *ofl.Service
+	GDTFService        *gdtf.Service
+	OFLManager         *ofl.Manager
+	PreviewService     *preview.Service
+	VersionService     *version.Service
+	WiFiService        *wifi.Service
+	PubSub             *pubsub.PubSub
+	HoldService        *sceneboard.Service
+	FlashService       *sceneboard.FlashService
+	MacroService       *macro.Service
+	StackService       *stack.Service
+	ShowTimerService   *showtimer.Service
+	TempoService       *tempo.Service
+	StandbyService     *standby.Service
+	InputService       *input.Service
+	TimecodeService    *timecode.Service
+	AudioService       *audio.Service
+	SchedulerService   *scheduler.Service
+	LibrarySyncService *librarysync.Service
+	OperationRecorder  *recorder.Service
+	SchemaInfo         *schemainfo.Service
+	Loaders            *loaders.Extension
+	SnapshotService    *snapshot.Service
+	AuditService       *audit.Service
+	AuthService        *auth.Service
+	PresenceService    *presence.Service
+	DMXStreamService   *dmxstream.Service
+	ReplicationService *replication.Service
+	SettingsService    *settings.Service
+	SceneSummaries     *scenesummary.Cache
+	StageViewService   *stageview.Service
+	HouseLightsService *houselights.Service
+	PixelMapService    *pixelmap.Service
+
+	// BackupService archives the server's projects and settings, once
+	// enabled by EnableBackups (optional)
+	BackupService *backup.Service
+
+	// DMXRecorder records the DMX output and replays recordings, once
+	// enabled by EnableDMXRecordings (optional)
+	DMXRecorder *dmxrecord.Service
+
+	// DiscoveryService advertises the server by mDNS and finds other
+	// servers, once started by StartDiscovery (optional)
+	DiscoveryService *discovery.Service
+
+	// StateJournal records masters, blackouts, and live scenes so they survive
+	// a restart (optional)
+	StateJournal *journal.Journal
+}
+
+// loadersFor returns the batch loaders of the operation being resolved, or
+// unshared ones outside of an operation.
+func (r *Resolver) loadersFor(ctx context.Context) *loaders.Loaders {
+	if l := loaders.For(ctx); l != nil {
+		return l
+	}
+	return loaders.New(r.db)
 }
 
 // NewResolver creates a new Resolver instance with all dependencies.
@@ -56,36 +162,172 @@ func NewResolver(db *gorm.DB, dmxService *dmx.Service, fadeEngine *fade.Engine,
 	cueListRepo := repositories.NewCueListRepository(db)
 	cueRepo := repositories.NewCueRepository(db)
 	sceneBoardRepo := repositories.NewSceneBoardRepository(db)
+	fixtureGroupRepo := repositories.NewFixtureGroupRepository(db)
+	paletteRepo := repositories.NewPaletteRepository(db)
+	snapshotRepo := repositories.NewSnapshotRepository(db)
+	universeRepo := repositories.NewUniverseRepository(db)
+	layoutZoneRepo := repositories.NewLayoutZoneRepository(db)
 
 	ps := pubsub.New()
 
 	// Create PubSub first so it can be passed to OFLManager
 	oflManager := ofl.NewManager(db, fixtureRepo, ps, oflCachePath)
 
+	exportService := export.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	importService := importservice.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	exportService.SetFixtureGroupRepo(fixtureGroupRepo)
+	importService.SetFixtureGroupRepo(fixtureGroupRepo)
+	exportService.SetPaletteRepo(paletteRepo)
+	importService.SetPaletteRepo(paletteRepo)
+	exportService.SetUniverseRepo(universeRepo)
+	importService.SetUniverseRepo(universeRepo)
+
 	r := &Resolver{
-		db:              db,
-		ProjectRepo:     projectRepo,
-		SettingRepo:     repositories.NewSettingRepository(db),
-		FixtureRepo:     fixtureRepo,
-		SceneRepo:       sceneRepo,
-		CueListRepo:     cueListRepo,
-		CueRepo:         cueRepo,
-		SceneBoardRepo:  sceneBoardRepo,
-		DMXService:      dmxService,
-		FadeEngine:      fadeEngine,
-		PlaybackService: playbackService,
-		ExportService:   export.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo),
-		ImportService:   importservice.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo),
-		OFLService:      ofl.NewService(db, fixtureRepo),
-		OFLManager:      oflManager,
-		PreviewService:  preview.NewService(fixtureRepo, sceneRepo, dmxService),
-		VersionService:  version.NewService(),
-		WiFiService:     wifi.NewService(),
-		PubSub:          ps,
+		db:                 db,
+		ProjectRepo:        projectRepo,
+		SettingRepo:        repositories.NewSettingRepository(db),
+		FixtureRepo:        fixtureRepo,
+		FixtureGroupRepo:   fixtureGroupRepo,
+		PaletteRepo:        paletteRepo,
+		UndoRepo:           repositories.NewUndoRepository(db),
+		SnapshotRepo:       snapshotRepo,
+		AuditRepo:          repositories.NewAuditRepository(db),
+		UserRepo:           repositories.NewUserRepository(db),
+		SceneRepo:          sceneRepo,
+		CueListRepo:        cueListRepo,
+		CueRepo:            cueRepo,
+		SceneBoardRepo:     sceneBoardRepo,
+		UniverseRepo:       universeRepo,
+		LayoutZoneRepo:     layoutZoneRepo,
+		SelectionSetRepo:   repositories.NewSelectionSetRepository(db),
+		HouseLightsRepo:    repositories.NewHouseLightsRepository(db),
+		PixelMatrixRepo:    repositories.NewPixelMatrixRepository(db),
+		DMXService:         dmxService,
+		FadeEngine:         fadeEngine,
+		EffectService:      effects.NewService(dmxService),
+		PlaybackService:    playbackService,
+		ExportService:      exportService,
+		ArchiveDownloads:   export.NewDownloads(exportService),
+		ReportService:      report.NewService(projectRepo, fixtureRepo, sceneRepo, cueListRepo),
+		ReportDownloads:    report.NewDownloads(),
+		ImportService:      importService,
+		IntegrityService:   integrity.NewService(db),
+		OFLService:         ofl.NewService(db, fixtureRepo),
+		GDTFService:        gdtf.NewService(db, fixtureRepo),
+		OFLManager:         oflManager,
+		PreviewService:     preview.NewService(fixtureRepo, sceneRepo, dmxService),
+		VersionService:     version.NewService(),
+		WiFiService:        wifi.NewService(),
+		PubSub:             ps,
+		HoldService:        sceneboard.NewService(fadeEngine),
+		FlashService:       sceneboard.NewFlashService(dmxService),
+		MacroService:       macro.NewService(),
+		StackService:       stack.NewService(fadeEngine),
+		ShowTimerService:   showtimer.NewService(),
+		TempoService:       tempo.NewService(),
+		StandbyService:     standby.NewService(dmxService, fadeEngine, dmxService.GetPort()),
+		InputService:       input.NewService(dmxService.GetPort()),
+		TimecodeService:    timecode.NewService(),
+		AudioService:       audio.NewService(),
+		SchedulerService:   scheduler.NewService(),
+		LibrarySyncService: librarysync.NewService(db, fixtureRepo, exportService, importService),
+		OperationRecorder:  recorder.NewService(),
+		SchemaInfo:         schemainfo.NewService(generated.NewExecutableSchema(generated.Config{}).Schema()),
+		Loaders:            loaders.NewExtension(db),
+		SnapshotService:    snapshot.NewService(snapshotRepo, projectRepo, exportService, importService),
+		PresenceService:    presence.NewService(),
+		DMXStreamService:   dmxstream.NewService(dmxService),
+		ReplicationService: replication.NewService(dmxService),
+		SceneSummaries:     scenesummary.NewCache(),
+		StageViewService:   stageview.NewService(sceneRepo, fixtureRepo, layoutZoneRepo),
+		HouseLightsService: houselights.NewService(dmxService),
+		PixelMapService:    pixelmap.NewService(dmxService),
 	}
 
+	// Scene summaries are dropped when the data they are computed from is
+	// written
+	if err := r.SceneSummaries.Watch(db); err != nil {
+		log.Error("cannot watch writes for scene summaries", "error", err)
+	}
+
+	// Settings are validated and applied live by the services they configure
+	r.SettingsService = settings.NewService(r.SettingRepo)
+	r.registerSettings()
+
+	// Audited mutations are described by the records they change
+	r.AuditService = audit.NewService(r.AuditRepo, r.loadAuditEntity)
+
+	// Roles are checked against the projects that requests name
+	r.AuthService = auth.NewService(r.UserRepo, r.entityProjectIDs)
+
+	// Visualizer streams, stage views, pixel map frames and the published
+	// library need a signed-in user like the rest of the API, and library
+	// templates a role on their projects
+	r.DMXStreamService.SetAuthorizer(r.AuthService.RequireUser)
+	r.StageViewService.SetAuthorizer(r.AuthService.RequireUser)
+	r.PixelMapService.SetAuthorizer(r.AuthService.RequireUser)
+	r.LibrarySyncService.SetAuthorizer(r.AuthService.RequireViewer)
+
+	// Pixel matrices are mapped from their saved definitions
+	r.PixelMapService.SetLoader(r.pixelMatrixMapping)
+
+	// Quantized auto-follows use the shared tempo clock
+	playbackService.SetTempoService(r.TempoService)
+
+	// Cues merge with other live scenes through the playback stack
+	playbackService.SetStackService(r.StackService)
+
+	// Cue list follows hold while the server is in standby
+	r.StandbyService.SetPlayback(playbackService)
+
 	// Wire up PubSub publishing from services
 	r.wirePubSub()
+	r.wirePresence()
+	r.wireSceneBoardState()
+	r.wireMacros()
+
+	// Apply stored fixture intensity caps before any output is sent
+	r.refreshOutputLimits(context.Background())
+	r.refreshSoftPatch(context.Background())
+
+	// Render effects, scene board flash releases and house lights fades on
+	// every fade engine tick, after fades
+	fadeEngine.OnTick(r.EffectService.Tick)
+	fadeEngine.OnTick(r.FlashService.Tick)
+	fadeEngine.OnTick(r.HouseLightsService.Tick)
+	r.refreshSceneEffects(context.Background())
+
+	// Restore the saved Art-Net bind, unicast routing table and ArtSync
+	// setting
+	r.loadArtNetBind(context.Background())
+	r.loadUnicastRoutes(context.Background())
+	r.loadArtSync(context.Background())
+
+	// Restore the saved output channel limits and parked channels
+	r.loadChannelLimits(context.Background())
+	r.loadParkedChannels(context.Background())
+
+	// Resume the saved standby schedule
+	r.loadStandbyConfig(context.Background())
+
+	// Start receiving from a configured fader wing
+	r.wireFaderWing()
+	r.loadFaderWingConfig(context.Background())
+
+	// Follow the saved timecode configuration
+	r.wireTimecode()
+	r.loadTimecodeConfig(context.Background())
+
+	// Listen to the saved audio input for beats
+	r.wireAudio()
+	r.loadAudioConfig(context.Background())
+
+	// Fire the saved schedules
+	r.wireScheduler()
+	r.loadSchedules(context.Background())
+
+	// Record live state for a tracking backup, started by StartReplication
+	r.wireReplication()
 
 	return r
 }
@@ -94,29 +336,7 @@ func NewResolver(db *gorm.DB, dmxService *dmx.Service, fadeEngine *fade.Engine,
 func (r *Resolver) wirePubSub() {
 	// Wire up PlaybackService to publish cue list playback updates
 	r.PlaybackService.SetUpdateCallback(func(status *playback.CueListPlaybackStatus) {
-		// Convert playback status to generated type
-		fadeProgress := status.FadeProgress
-		gqlStatus := &generated.CueListPlaybackStatus{
-			CueListID:       status.CueListID,
-			CurrentCueIndex: status.CurrentCueIndex,
-			IsPlaying:       status.IsPlaying,
-			IsFading:        status.IsFading,
-			FadeProgress:    &fadeProgress,
-			LastUpdated:     status.LastUpdated,
-		}
-
-		// Convert current cue if present (to models.Cue as expected by generated type)
-		if status.CurrentCue != nil {
-			gqlStatus.CurrentCue = &models.Cue{
-				ID:          status.CurrentCue.ID,
-				Name:        status.CurrentCue.Name,
-				CueNumber:   status.CurrentCue.CueNumber,
-				FadeInTime:  status.CurrentCue.FadeInTime,
-				FadeOutTime: status.CurrentCue.FadeOutTime,
-				FollowTime:  status.CurrentCue.FollowTime,
-			}
-		}
-
+		gqlStatus := convertCueListPlaybackStatus(status)
 		r.PubSub.Publish(pubsub.TopicCueListPlayback, status.CueListID, gqlStatus)
 	})
 
@@ -153,11 +373,17 @@ func (r *Resolver) wirePubSub() {
 			ProjectID: session.ProjectID,
 			UserID:    userID,
 			IsActive:  session.IsActive,
+			Blind:     session.Blind,
 			CreatedAt: session.CreatedAt,
 		}
 
 		r.PubSub.Publish(pubsub.TopicPreviewSession, session.ProjectID, modelSession)
 
+		// A blind session's output is not live
+		if session.Blind {
+			return
+		}
+
 		// Also publish DMX output changes for each universe affected
 		for _, output := range dmxOutput {
 			universeOutput := &generated.UniverseOutput{
@@ -168,6 +394,36 @@ func (r *Resolver) wirePubSub() {
 		}
 	})
 
+	// Wire up show timers to publish updates and fire their cue list triggers
+	r.ShowTimerService.SetUpdateCallback(func(timer *showtimer.Timer) {
+		r.PubSub.Publish(pubsub.TopicShowTimer, timer.ID, convertShowTimer(timer))
+	})
+
+	r.ShowTimerService.SetExpireCallback(func(timer *showtimer.Timer) {
+		if timer.TriggerCueListID == nil {
+			return
+		}
+		if err := r.PlaybackService.StartCueList(context.Background(), *timer.TriggerCueListID, timer.TriggerCueNumber, nil, nil); err != nil {
+			log.Warn("show timer failed to start cue list", "timer", timer.ID, "cueList", *timer.TriggerCueListID, "error", err)
+		}
+	})
+
+	// Wire up house lights to publish their status
+	r.HouseLightsService.SetChangeCallback(func(projectID string) {
+		status := r.HouseLightsService.Status(projectID)
+		r.PubSub.Publish(pubsub.TopicHouseLights, projectID, convertHouseLightsStatus(status))
+	})
+
+	// Wire up the tempo clock to publish tempo changes
+	r.TempoService.SetUpdateCallback(func(state *tempo.State) {
+		r.PubSub.Publish(pubsub.TopicTempo, "", convertTempoState(state))
+	})
+
+	// Wire up standby to publish state changes
+	r.StandbyService.SetUpdateCallback(func(status *standby.Status) {
+		r.PubSub.Publish(pubsub.TopicStandby, "", convertStandbyStatus(status))
+	})
+
 	// Wire up WiFi service callbacks
 	r.WiFiService.SetModeCallback(func(mode wifi.Mode) {
 		r.PubSub.Publish(pubsub.TopicWiFiModeChanged, "", generated.WiFiMode(mode))
@@ -180,6 +436,137 @@ func (r *Resolver) wirePubSub() {
 	})
 }
 
+// convertCueListPlaybackStatus converts a playback.CueListPlaybackStatus to generated.CueListPlaybackStatus.
+func convertCueListPlaybackStatus(status *playback.CueListPlaybackStatus) *generated.CueListPlaybackStatus {
+	fadeProgress := status.FadeProgress
+	result := &generated.CueListPlaybackStatus{
+		CueListID:         status.CueListID,
+		CurrentCueIndex:   status.CurrentCueIndex,
+		IsPlaying:         status.IsPlaying,
+		IsFading:          status.IsFading,
+		IsPaused:          status.IsPaused,
+		FadeProgress:      &fadeProgress,
+		FollowAt:          status.FollowAt,
+		FollowRemaining:   status.FollowRemaining,
+		CrossfadePosition: status.CrossfadePosition,
+		LastUpdated:       status.LastUpdated,
+	}
+
+	// Convert current cue if present (to models.Cue as expected by generated type)
+	if status.CurrentCue != nil {
+		result.CurrentCue = &models.Cue{
+			ID:             status.CurrentCue.ID,
+			Name:           status.CurrentCue.Name,
+			CueNumber:      status.CurrentCue.CueNumber,
+			FadeInTime:     status.CurrentCue.FadeInTime,
+			FadeOutTime:    status.CurrentCue.FadeOutTime,
+			FollowTime:     status.CurrentCue.FollowTime,
+			FollowQuantize: status.CurrentCue.FollowQuantize,
+		}
+	}
+	return result
+}
+
+// convertOperationRecordingStatus converts a recorder.Status to generated.OperationRecordingStatus.
+func convertOperationRecordingStatus(status *recorder.Status) *generated.OperationRecordingStatus {
+	result := &generated.OperationRecordingStatus{
+		IsRecording:    status.IsRecording,
+		OperationCount: status.OperationCount,
+		DroppedCount:   status.Dropped,
+	}
+	if status.StartedAt != nil {
+		startedAt := status.StartedAt.Format("2006-01-02T15:04:05.000Z")
+		result.StartedAt = &startedAt
+	}
+	return result
+}
+
+// convertShowTimer converts a showtimer.Timer to generated.ShowTimer.
+func convertShowTimer(timer *showtimer.Timer) *generated.ShowTimer {
+	return &generated.ShowTimer{
+		ID:               timer.ID,
+		Name:             timer.Name,
+		Kind:             generated.ShowTimerKind(timer.Kind),
+		DurationSeconds:  timer.Duration.Seconds(),
+		ElapsedSeconds:   timer.Elapsed.Seconds(),
+		RemainingSeconds: timer.Remaining.Seconds(),
+		IsRunning:        timer.IsRunning,
+		HasExpired:       timer.HasExpired,
+		TriggerCueListID: timer.TriggerCueListID,
+		TriggerCueNumber: timer.TriggerCueNumber,
+		UpdatedAt:        formatUpdatedAt(timer.UpdatedAt),
+	}
+}
+
+// convertTempoState converts a tempo.State to generated.TempoState.
+func convertTempoState(state *tempo.State) *generated.TempoState {
+	return &generated.TempoState{
+		Bpm:         state.BPM,
+		BeatsPerBar: state.BeatsPerBar,
+		DownbeatAt:  state.DownbeatAt.Format("2006-01-02T15:04:05.000Z"),
+		Beat:        state.Beat,
+		Bar:         state.Bar,
+		TapCount:    state.TapCount,
+		ServerTime:  state.Time.Format("2006-01-02T15:04:05.000Z"),
+		UpdatedAt:   formatUpdatedAt(state.UpdatedAt),
+	}
+}
+
+// convertStandbyStatus converts a standby.Status to generated.StandbyStatus.
+func convertStandbyStatus(status *standby.Status) *generated.StandbyStatus {
+	result := &generated.StandbyStatus{
+		IsStandby: status.IsStandby,
+		Config: generated.StandbyConfig{
+			ScheduleEnabled: status.Config.ScheduleEnabled,
+			OpeningHours:    make([]*generated.OpeningHours, 0, len(status.Config.OpeningHours)),
+			WakeOnArtNet:    status.Config.WakeOnArtNet,
+			Output:          generated.StandbyOutput(status.Config.OutputMode()),
+		},
+	}
+	if status.Reason != nil {
+		reason := generated.StandbyTrigger(*status.Reason)
+		result.Reason = &reason
+	}
+	if status.Since != nil {
+		since := status.Since.Format("2006-01-02T15:04:05.000Z")
+		result.Since = &since
+	}
+	if status.Output != nil {
+		output := generated.StandbyOutput(*status.Output)
+		result.Output = &output
+	}
+	if status.LastWakeReason != nil {
+		reason := generated.StandbyTrigger(*status.LastWakeReason)
+		result.LastWakeReason = &reason
+	}
+	if status.LastWakeAt != nil {
+		lastWakeAt := status.LastWakeAt.Format("2006-01-02T15:04:05.000Z")
+		result.LastWakeAt = &lastWakeAt
+	}
+	if status.NextScheduledChange != nil {
+		next := status.NextScheduledChange.Format("2006-01-02T15:04:05.000Z")
+		result.NextScheduledChange = &next
+	}
+	for _, window := range status.Config.OpeningHours {
+		hours := &generated.OpeningHours{Days: []generated.DayOfWeek{}, Open: window.Open, Close: window.Close}
+		for _, day := range window.Days {
+			hours.Days = append(hours.Days, generated.AllDayOfWeek[day])
+		}
+		result.Config.OpeningHours = append(result.Config.OpeningHours, hours)
+	}
+	return result
+}
+
+// followQuantizeValue converts a cue's follow quantize input for storage;
+// NONE and null both clear it.
+func followQuantizeValue(q *generated.BeatQuantize) *string {
+	if q == nil || *q == generated.BeatQuantizeNone {
+		return nil
+	}
+	value := string(*q)
+	return &value
+}
+
 // convertWiFiStatus converts a wifi.Status to generated.WiFiStatus.
 func convertWiFiStatus(status *wifi.Status) *generated.WiFiStatus {
 	if status == nil {
@@ -242,3 +629,26 @@ func convertWiFiStatus(status *wifi.Status) *generated.WiFiStatus {
 
 	return gqlStatus
 }
+
+// convertFlashState converts a scene board flash state to the GraphQL type.
+func convertFlashState(state *sceneboard.FlashState) *generated.SceneBoardFlashState {
+	return &generated.SceneBoardFlashState{
+		ButtonID:    state.ButtonID,
+		SceneID:     state.SceneID,
+		Level:       state.Level,
+		IsFlashing:  state.IsFlashing,
+		IsReleasing: state.IsReleasing,
+	}
+}
+
+// convertHoldState converts a scene board hold state to the GraphQL type.
+func convertHoldState(state *sceneboard.HoldState) *generated.SceneBoardButtonHoldState {
+	return &generated.SceneBoardButtonHoldState{
+		ButtonID:    state.ButtonID,
+		SceneID:     state.SceneID,
+		Level:       state.Level,
+		IsHeld:      state.IsHeld,
+		IsLatched:   state.IsLatched,
+		HeldSeconds: state.HeldSeconds,
+	}
+}
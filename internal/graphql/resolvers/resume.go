@@ -0,0 +1,129 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+)
+
+// journalKindLiveScene is the journal kind holding the scenes live on the
+// playback stack, keyed by playback ID. Cue lists are journaled by their
+// active cue instead.
+const journalKindLiveScene = "live_scene"
+
+// journalLiveScene is the journaled scene of a playback.
+type journalLiveScene struct {
+	SceneID     string     `json:"sceneId"`
+	Kind        stack.Kind `json:"kind"`
+	ActivatedAt time.Time  `json:"activatedAt"`
+}
+
+// journalSceneLive records a scene made live on a playback.
+func (r *Resolver) journalSceneLive(a stack.Activation) {
+	if r.StateJournal == nil {
+		return
+	}
+	entry := journalLiveScene{SceneID: a.SceneID, Kind: a.Kind, ActivatedAt: time.Now().UTC()}
+	if err := r.StateJournal.Put(journalKindLiveScene, a.PlaybackID, entry); err != nil {
+		log.Warn("failed to journal live scene", "playback", a.PlaybackID, "error", err)
+	}
+}
+
+// journalSceneReleased removes a released playback's scene, or every
+// playback's when playbackID is empty.
+func (r *Resolver) journalSceneReleased(playbackID string) {
+	if r.StateJournal == nil {
+		return
+	}
+	keys := []string{playbackID}
+	if playbackID == "" {
+		keys = keys[:0]
+		for key := range r.StateJournal.Entries(journalKindLiveScene) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		if err := r.StateJournal.Delete(journalKindLiveScene, key); err != nil {
+			log.Warn("failed to journal released scene", "playback", key, "error", err)
+		}
+	}
+}
+
+// restoreLiveScenes makes the journaled scenes live again without a fade,
+// in the order they were activated, returning how many it restored. Entries
+// whose scene no longer exists are dropped.
+func (r *Resolver) restoreLiveScenes(ctx context.Context, j *journal.Journal) int {
+	type liveScene struct {
+		playbackID string
+		journalLiveScene
+	}
+	var scenes []liveScene
+	for playbackID, raw := range j.Entries(journalKindLiveScene) {
+		var entry journalLiveScene
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			log.Warn("invalid journaled scene", "playback", playbackID, "error", err)
+			_ = j.Delete(journalKindLiveScene, playbackID)
+			continue
+		}
+		scenes = append(scenes, liveScene{playbackID, entry})
+	}
+	sort.Slice(scenes, func(i, k int) bool {
+		return scenes[i].ActivatedAt.Before(scenes[k].ActivatedAt)
+	})
+
+	restored := 0
+	for _, scene := range scenes {
+		sceneChannels, err := r.loadSceneChannels(ctx, scene.SceneID)
+		if err != nil {
+			log.Warn("cannot restore live scene", "playback", scene.playbackID, "scene", scene.SceneID, "error", err)
+			_ = j.Delete(journalKindLiveScene, scene.playbackID)
+			continue
+		}
+		fadeID := fmt.Sprintf("scene-%s", scene.SceneID)
+		if scene.Kind == stack.KindSceneBoard {
+			fadeID = sceneBoardFadeID(scene.SceneID)
+		}
+		r.StackService.Activate(stack.Activation{
+			PlaybackID: scene.playbackID,
+			Kind:       scene.Kind,
+			SceneID:    scene.SceneID,
+			Channels:   sceneChannels,
+		}, 0, fadeID, fade.EasingInOutSine)
+		r.DMXService.SetActiveScene(scene.SceneID)
+		if scene.Kind == stack.KindSceneBoard {
+			r.sceneBoardStateChanged(strings.TrimPrefix(scene.playbackID, stack.SceneBoardPlayback("")))
+		}
+		restored++
+	}
+	return restored
+}
+
+// ResumePlayback restores the live state the state journal recorded: live
+// scenes, then the active cue of each cue list, then masters, independents
+// and blackout.
+func (r *Resolver) ResumePlayback(ctx context.Context) (*generated.PlaybackResumeResult, error) {
+	j := r.StateJournal
+	if j == nil {
+		return nil, fmt.Errorf("the playback state journal is disabled")
+	}
+
+	result := &generated.PlaybackResumeResult{}
+	result.Scenes = r.restoreLiveScenes(ctx, j)
+	cueLists, err := r.PlaybackService.RestoreFromJournal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.CueLists = cueLists
+	result.Masters = r.restoreMasterLevels(j) + r.restoreSubmasterLevels(j)
+	result.Independents = r.restoreIndependents(j)
+	result.Blackout = r.restoreBlackout(j)
+	return result, nil
+}
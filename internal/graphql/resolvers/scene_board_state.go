@@ -0,0 +1,188 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+)
+
+// sceneBoardStateInterval is how often a board's live state is resent while
+// one of its buttons is fading.
+const sceneBoardStateInterval = 100 * time.Millisecond
+
+// sceneBoardFadeID returns the fade engine ID used when a board activates a
+// scene.
+func sceneBoardFadeID(sceneID string) string {
+	return fmt.Sprintf("scene-board-%s", sceneID)
+}
+
+// wireSceneBoardState announces a button's board whenever its hold, flash,
+// or macro changes.
+func (r *Resolver) wireSceneBoardState() {
+	buttonChanged := func(buttonID string) {
+		if r.PubSub.SubscriberCount(pubsub.TopicSceneBoardState) == 0 {
+			return
+		}
+		var button models.SceneBoardButton
+		if err := r.db.Select("scene_board_id").First(&button, "id = ?", buttonID).Error; err != nil {
+			// The button was removed; let every board check its state
+			r.sceneBoardStateChanged("")
+			return
+		}
+		r.sceneBoardStateChanged(button.SceneBoardID)
+	}
+	r.HoldService.SetChangeCallback(buttonChanged)
+	r.FlashService.SetChangeCallback(buttonChanged)
+	r.MacroService.SetChangeCallback(buttonChanged)
+}
+
+// sceneBoardStateChanged tells subscribers to a board that its live state
+// changed. An empty ID reaches every board's subscribers.
+func (r *Resolver) sceneBoardStateChanged(sceneBoardID string) {
+	r.PubSub.Publish(pubsub.TopicSceneBoardState, sceneBoardID, sceneBoardID)
+}
+
+// sceneBoardLiveState returns the runtime state of a board's buttons.
+func (r *Resolver) sceneBoardLiveState(ctx context.Context, sceneBoardID string) (*generated.SceneBoardLiveState, error) {
+	board, err := r.SceneBoardRepo.FindByID(ctx, sceneBoardID)
+	if err != nil {
+		return nil, err
+	}
+	if board == nil {
+		return nil, fmt.Errorf("scene board not found: %s", sceneBoardID)
+	}
+	buttons, err := r.SceneBoardRepo.GetButtons(ctx, board.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &generated.SceneBoardLiveState{
+		SceneBoardID: board.ID,
+		MasterLevel:  r.DMXService.MasterLevels().GrandMaster,
+		Buttons:      make([]*generated.SceneBoardButtonLiveState, len(buttons)),
+		UpdatedAt:    time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	for _, e := range r.StackService.Entries() {
+		if e.PlaybackID == stack.SceneBoardPlayback(board.ID) {
+			sceneID := e.SceneID
+			state.ActiveSceneID = &sceneID
+		}
+	}
+
+	for i, button := range buttons {
+		live := &generated.SceneBoardButtonLiveState{
+			ButtonID:       button.ID,
+			SceneID:        button.SceneID,
+			IsMacroRunning: r.MacroService.IsRunning(button.ID),
+		}
+		if state.ActiveSceneID != nil && button.SceneID == *state.ActiveSceneID {
+			live.IsActive = true
+			live.Level = 1
+			if progress, ok := r.FadeEngine.FadeProgress(sceneBoardFadeID(button.SceneID)); ok {
+				percent := progress * 100
+				live.FadeProgress = &percent
+			}
+		}
+		if hold := r.HoldService.State(button.ID); hold != nil {
+			live.IsActive = true
+			live.Level = hold.Level
+			live.IsHeld = hold.IsHeld
+			live.IsLatched = hold.IsLatched
+		}
+		// Hold ramps and release fades take precedence over an activation
+		if progress, ok := r.HoldService.FadeProgress(button.ID); ok {
+			percent := progress * 100
+			live.FadeProgress = &percent
+		}
+		// A flash is above everything else
+		if flash := r.FlashService.State(button.ID); flash != nil {
+			live.IsActive = true
+			live.IsFlashing = true
+			live.Level = flash.Level
+			live.FadeProgress = nil
+			if progress, ok := r.FlashService.ReleaseProgress(button.ID); ok {
+				percent := progress * 100
+				live.FadeProgress = &percent
+			}
+		}
+		state.Buttons[i] = live
+	}
+	return state, nil
+}
+
+// subscribeSceneBoardState sends a board's live state now, whenever it or the
+// grand master changes, and every sceneBoardStateInterval while a button is
+// fading. The subscription ends if the board is deleted.
+func (r *Resolver) subscribeSceneBoardState(ctx context.Context, sceneBoardID string) (<-chan *generated.SceneBoardLiveState, error) {
+	state, err := r.sceneBoardLiveState(ctx, sceneBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := r.PubSub.Subscribe(pubsub.TopicSceneBoardState, sceneBoardID, 10)
+	masters := r.PubSub.Subscribe(pubsub.TopicMasterLevel, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.SceneBoardLiveState, 10)
+
+	go func() {
+		defer close(outputChan)
+		defer r.PubSub.Unsubscribe(sub)
+		defer r.PubSub.Unsubscribe(masters)
+
+		ticker := time.NewTicker(sceneBoardStateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case outputChan <- state:
+			case <-ctx.Done():
+				return
+			}
+
+			fading := sceneBoardFading(state)
+		wait:
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-sub.Channel:
+					if !ok {
+						return
+					}
+					break wait
+				case _, ok := <-masters.Channel:
+					if !ok {
+						return
+					}
+					break wait
+				case <-ticker.C:
+					if fading {
+						break wait
+					}
+				}
+			}
+
+			if state, err = r.sceneBoardLiveState(ctx, sceneBoardID); err != nil {
+				return
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// sceneBoardFading reports whether any of a board's buttons is fading.
+func sceneBoardFading(state *generated.SceneBoardLiveState) bool {
+	for _, button := range state.Buttons {
+		if button.FadeProgress != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+	"github.com/bbernstein/lacylights-go/internal/services/scenesummary"
+)
+
+// sceneColorSummary returns a scene's look in brief, from the cache or
+// computed and cached.
+func (r *Resolver) sceneColorSummary(ctx context.Context, sceneID, projectID string) (*generated.SceneColorSummary, error) {
+	summary, generation, ok := r.SceneSummaries.Get(sceneID)
+	if !ok {
+		var err error
+		if summary, err = r.summarizeScene(ctx, sceneID, projectID); err != nil {
+			return nil, err
+		}
+		r.SceneSummaries.Put(sceneID, generation, summary)
+	}
+	return convertSceneColorSummary(sceneID, summary), nil
+}
+
+// summarizeScene computes a scene's look from its fixture values, the types
+// of its fixtures' channels, and the project's groups.
+func (r *Resolver) summarizeScene(ctx context.Context, sceneID, projectID string) (scenesummary.Summary, error) {
+	l := r.loadersFor(ctx)
+	values, err := l.SceneFixtureValues.Load(ctx, sceneID)
+	if err != nil {
+		return scenesummary.Summary{}, err
+	}
+
+	// Channels are loaded together, in one batch
+	fixtures := make([]scenesummary.Fixture, len(values))
+	errs := make([]error, len(values))
+	var wg sync.WaitGroup
+	for i, value := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fixtures[i], errs[i] = summaryFixture(ctx, l.InstanceChannels.Load, value)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return scenesummary.Summary{}, err
+		}
+	}
+
+	stored, err := l.ProjectFixtureGroups.Load(ctx, projectID)
+	if err != nil {
+		return scenesummary.Summary{}, err
+	}
+	groups := make([]scenesummary.Group, len(stored))
+	for i, group := range stored {
+		ids, err := repositories.GroupFixtureIDs(group)
+		if err != nil {
+			return scenesummary.Summary{}, err
+		}
+		groups[i] = scenesummary.Group{ID: group.ID, Name: group.Name, FixtureIDs: ids}
+	}
+	return scenesummary.Summarize(fixtures, groups), nil
+}
+
+// summaryFixture pairs a fixture value with the types of its channels.
+func summaryFixture(ctx context.Context, loadChannels func(context.Context, string) ([]*models.InstanceChannel, error), value *models.FixtureValue) (scenesummary.Fixture, error) {
+	fixture := scenesummary.Fixture{ID: value.FixtureID}
+	channels, err := loadChannels(ctx, value.FixtureID)
+	if err != nil {
+		return fixture, err
+	}
+	for _, ch := range channels {
+		fixture.Channels = append(fixture.Channels, color.Channel{Offset: ch.Offset, Type: ch.Type})
+	}
+
+	var stored []models.ChannelValue
+	if value.Channels != "" {
+		if err := json.Unmarshal([]byte(value.Channels), &stored); err != nil {
+			return fixture, fmt.Errorf("failed to deserialize channels: %w", err)
+		}
+	}
+	for _, ch := range stored {
+		fixture.Values = append(fixture.Values, color.Value{Offset: ch.Offset, Value: ch.Value})
+	}
+	return fixture, nil
+}
+
+// convertSceneColorSummary converts a summary to the GraphQL type.
+func convertSceneColorSummary(sceneID string, summary scenesummary.Summary) *generated.SceneColorSummary {
+	result := &generated.SceneColorSummary{
+		SceneID:        sceneID,
+		Intensity:      summary.Intensity,
+		DominantColors: summary.Colors,
+		Groups:         make([]*generated.SceneGroupColor, len(summary.Groups)),
+	}
+	for i, group := range summary.Groups {
+		result.Groups[i] = &generated.SceneGroupColor{
+			GroupID:      stringToPointer(group.GroupID),
+			Name:         stringToPointer(group.Name),
+			Color:        stringToPointer(group.Color),
+			Intensity:    group.Intensity,
+			FixtureCount: group.FixtureCount,
+		}
+	}
+	return result
+}
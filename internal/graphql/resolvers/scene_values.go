@@ -0,0 +1,171 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// setSceneFixtureValues merges sets of channel values into a scene's fixtures
+// in one transaction, recording one undo step and publishing one update.
+func (r *Resolver) setSceneFixtureValues(ctx context.Context, sceneID string, valueSets []*generated.SceneValueSetInput) (*models.Scene, error) {
+	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene not found: %s", sceneID)
+	}
+
+	fixtureValues := expandValueSets(valueSets)
+	if len(fixtureValues) == 0 {
+		return nil, fmt.Errorf("value sets must name at least one fixture")
+	}
+	if err := r.checkProjectFixtures(ctx, scene.ProjectID, fixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.checkFixtureValues(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	changes := make(map[string][]models.ChannelValue, len(fixtureValues))
+	for _, fv := range fixtureValues {
+		// Validates the offsets and values
+		if _, err := serializeSparseChannels(fv.Channels); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", fv.FixtureID, err)
+		}
+		changes[fv.FixtureID] = sparseChannelValues(fv.Channels)
+	}
+
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Set values in scene "+scene.Name, undoScene(sceneID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.transaction(ctx, func(txResolver *Resolver) error {
+		existing, err := txResolver.SceneRepo.GetFixtureValues(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		var updated []models.FixtureValue
+		for _, value := range existing {
+			channels, ok := changes[value.FixtureID]
+			if !ok {
+				continue
+			}
+			delete(changes, value.FixtureID)
+			if value.Channels, err = mergeSparseChannels(value.Channels, channels); err != nil {
+				return err
+			}
+			updated = append(updated, value)
+		}
+		if err := txResolver.SceneRepo.UpdateFixtureValues(ctx, updated); err != nil {
+			return err
+		}
+
+		var created []models.FixtureValue
+		for _, fv := range fixtureValues {
+			channels, ok := changes[fv.FixtureID]
+			if !ok {
+				continue
+			}
+			channelsJSON, err := mergeSparseChannels("", channels)
+			if err != nil {
+				return err
+			}
+			created = append(created, models.FixtureValue{
+				SceneID:    sceneID,
+				FixtureID:  fv.FixtureID,
+				Channels:   channelsJSON,
+				PaletteIDs: "[]",
+			})
+		}
+		if err := txResolver.SceneRepo.CreateFixtureValues(ctx, created); err != nil {
+			return err
+		}
+
+		if err := txResolver.SceneRepo.SyncGroupValues(ctx, sceneID); err != nil {
+			return err
+		}
+		return txResolver.SceneRepo.Update(ctx, scene)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
+		log.Warn("failed to re-apply active scene after setting values", "error", err)
+	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
+
+	return scene, nil
+}
+
+// expandValueSets turns value sets into one fixture value per fixture, in the
+// order fixtures are first named. Later sets win where they overlap.
+func expandValueSets(valueSets []*generated.SceneValueSetInput) []*generated.FixtureValueInput {
+	var fixtureValues []*generated.FixtureValueInput
+	byFixture := make(map[string]*generated.FixtureValueInput)
+	for _, set := range valueSets {
+		for _, fixtureID := range set.FixtureIds {
+			fv, ok := byFixture[fixtureID]
+			if !ok {
+				fv = &generated.FixtureValueInput{FixtureID: fixtureID}
+				byFixture[fixtureID] = fv
+				fixtureValues = append(fixtureValues, fv)
+			}
+			for _, ch := range set.Channels {
+				channel := &generated.ChannelValueInput{Offset: ch.Offset, Value: ch.Value}
+				replaced := false
+				for i, existing := range fv.Channels {
+					if existing.Offset == ch.Offset {
+						fv.Channels[i], replaced = channel, true
+						break
+					}
+				}
+				if !replaced {
+					fv.Channels = append(fv.Channels, channel)
+				}
+			}
+		}
+	}
+	return fixtureValues
+}
+
+// checkProjectFixtures rejects fixture values of fixtures that are not in the
+// project.
+func (r *Resolver) checkProjectFixtures(ctx context.Context, projectID string, fixtureValues []*generated.FixtureValueInput) error {
+	fixtureIDs := make([]string, len(fixtureValues))
+	for i, fv := range fixtureValues {
+		fixtureIDs[i] = fv.FixtureID
+	}
+	var found []string
+	err := r.db.WithContext(ctx).Model(&models.FixtureInstance{}).
+		Where("id IN ? AND project_id = ?", fixtureIDs, projectID).Pluck("id", &found).Error
+	if err != nil {
+		return err
+	}
+	inProject := make(map[string]bool, len(found))
+	for _, id := range found {
+		inProject[id] = true
+	}
+	for _, id := range fixtureIDs {
+		if !inProject[id] {
+			return fmt.Errorf("fixture not found in the scene's project: %s", id)
+		}
+	}
+	return nil
+}
+
+// publishSceneUpdated tells subscribers to the scene's project that it was
+// edited, once the open transaction, if any, commits.
+func (r *Resolver) publishSceneUpdated(ctx context.Context, scene *models.Scene) {
+	r.publishAfterCommit(pubsub.TopicSceneUpdated, scene.ProjectID, scene)
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, scene)
+}
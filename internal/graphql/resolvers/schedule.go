@@ -0,0 +1,261 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/scheduler"
+	"gorm.io/gorm"
+)
+
+// wireScheduler carries out schedule actions, records when schedules fire,
+// and publishes each firing.
+func (r *Resolver) wireScheduler() {
+	r.SchedulerService.SetHandlers(scheduler.Handlers{
+		ActivateScene: func(ctx context.Context, sceneID string, fadeTime *float64) error {
+			_, err := r.Mutation().ActivateScene(ctx, sceneID, fadeTime)
+			return err
+		},
+		CueListGo: func(ctx context.Context, cueListID string, fadeTime *float64) error {
+			return r.PlaybackService.NextCue(ctx, cueListID, fadeTime, nil)
+		},
+	})
+	r.SchedulerService.SetFireCallback(func(event *scheduler.Event) {
+		if err := r.db.Model(&models.Schedule{}).Where("id = ?", event.Rule.ID).
+			UpdateColumn("last_fired_at", event.FiredAt).Error; err != nil {
+			log.Warn("failed to record schedule firing", "schedule", event.Rule.ID, "error", err)
+		}
+		r.PubSub.Publish(pubsub.TopicScheduleFired, event.Rule.ProjectID, convertScheduleFiredEvent(event))
+	})
+}
+
+// loadSchedules hands every enabled schedule to the scheduler.
+func (r *Resolver) loadSchedules(ctx context.Context) {
+	var stored []models.Schedule
+	if err := r.db.WithContext(ctx).Where("enabled = ?", true).Find(&stored).Error; err != nil {
+		log.Warn("failed to load schedules", "error", err)
+		return
+	}
+	rules := make([]scheduler.Rule, 0, len(stored))
+	for i := range stored {
+		rule, err := scheduleRule(&stored[i])
+		if err != nil {
+			log.Warn("schedule is invalid", "schedule", stored[i].ID, "error", err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	r.SchedulerService.SetRules(rules)
+}
+
+// saveSchedule validates and stores a schedule, then starts or stops firing
+// it. Its scene or cue list must belong to its project.
+func (r *Resolver) saveSchedule(ctx context.Context, schedule *models.Schedule, create bool) error {
+	rule, err := scheduleRule(schedule)
+	if err != nil {
+		return err
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	switch rule.Action {
+	case scheduler.ActionActivateScene:
+		scene, err := r.SceneRepo.FindByID(ctx, rule.SceneID)
+		if err != nil {
+			return err
+		}
+		if scene == nil || scene.ProjectID != schedule.ProjectID {
+			return fmt.Errorf("scene not found in project: %s", rule.SceneID)
+		}
+		schedule.CueListID = nil
+	case scheduler.ActionCueListGo:
+		cueList, err := r.CueListRepo.FindByID(ctx, rule.CueListID)
+		if err != nil {
+			return err
+		}
+		if cueList == nil || cueList.ProjectID != schedule.ProjectID {
+			return fmt.Errorf("cue list not found in project: %s", rule.CueListID)
+		}
+		schedule.SceneID = nil
+	}
+
+	if create {
+		err = r.db.WithContext(ctx).Create(schedule).Error
+	} else {
+		err = r.db.WithContext(ctx).Save(schedule).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	if !schedule.Enabled {
+		r.SchedulerService.Remove(schedule.ID)
+		return nil
+	}
+	return r.SchedulerService.Put(rule)
+}
+
+// deleteSchedules deletes the schedules matching a query and stops firing
+// them.
+func (r *Resolver) deleteSchedules(ctx context.Context, query string, args ...interface{}) error {
+	var ids []string
+	if err := r.db.WithContext(ctx).Model(&models.Schedule{}).Where(query, args...).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where(query, args...).Delete(&models.Schedule{}).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		r.SchedulerService.Remove(id)
+	}
+	return nil
+}
+
+// findSchedule loads a schedule by ID.
+func (r *Resolver) findSchedule(ctx context.Context, id string) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := r.db.WithContext(ctx).First(&schedule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("schedule not found: %s", id)
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// fireSchedule carries out a schedule's action now.
+func (r *Resolver) fireSchedule(ctx context.Context, id string) (*generated.ScheduleFiredEvent, error) {
+	schedule, err := r.findSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := scheduleRule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	return convertScheduleFiredEvent(r.SchedulerService.Fire(ctx, rule)), nil
+}
+
+// applyScheduleInput sets the fields of a schedule that an input sets.
+// Create and update inputs share these fields.
+func applyScheduleInput(schedule *models.Schedule, in generated.UpdateScheduleInput) error {
+	if v := in.Name.Value(); v != nil {
+		schedule.Name = *v
+	}
+	if v := in.Enabled.Value(); v != nil {
+		schedule.Enabled = *v
+	}
+	if v := in.Trigger.Value(); v != nil {
+		schedule.Trigger = string(*v)
+	}
+	if in.Cron.IsSet() {
+		schedule.Cron = in.Cron.Value()
+	}
+	if in.Latitude.IsSet() {
+		schedule.Latitude = in.Latitude.Value()
+	}
+	if in.Longitude.IsSet() {
+		schedule.Longitude = in.Longitude.Value()
+	}
+	if v := in.OffsetMinutes.Value(); v != nil {
+		schedule.OffsetMinutes = *v
+	}
+	if in.Days.IsSet() {
+		days := make([]time.Weekday, 0, len(in.Days.Value()))
+		for _, day := range in.Days.Value() {
+			days = append(days, weekday(day))
+		}
+		value, err := json.Marshal(days)
+		if err != nil {
+			return err
+		}
+		schedule.Days = string(value)
+	}
+	if v := in.Action.Value(); v != nil {
+		schedule.Action = string(*v)
+	}
+	if in.SceneID.IsSet() {
+		schedule.SceneID = in.SceneID.Value()
+	}
+	if in.CueListID.IsSet() {
+		schedule.CueListID = in.CueListID.Value()
+	}
+	if in.FadeTime.IsSet() {
+		schedule.FadeTime = in.FadeTime.Value()
+	}
+	return nil
+}
+
+// scheduleRule converts a stored schedule to the rule the scheduler fires.
+func scheduleRule(schedule *models.Schedule) (scheduler.Rule, error) {
+	rule := scheduler.Rule{
+		ID:            schedule.ID,
+		ProjectID:     schedule.ProjectID,
+		Name:          schedule.Name,
+		Trigger:       scheduler.TriggerType(schedule.Trigger),
+		OffsetMinutes: schedule.OffsetMinutes,
+		Action:        scheduler.ActionType(schedule.Action),
+		FadeTime:      schedule.FadeTime,
+	}
+	if schedule.Cron != nil {
+		rule.Cron = *schedule.Cron
+	}
+	if rule.Trigger == scheduler.TriggerSunrise || rule.Trigger == scheduler.TriggerSunset {
+		if schedule.Latitude == nil || schedule.Longitude == nil {
+			return rule, fmt.Errorf("%s requires a latitude and longitude", rule.Trigger)
+		}
+		rule.Latitude, rule.Longitude = *schedule.Latitude, *schedule.Longitude
+	}
+	days, err := scheduleDays(schedule)
+	if err != nil {
+		return rule, err
+	}
+	rule.Days = days
+	if schedule.SceneID != nil {
+		rule.SceneID = *schedule.SceneID
+	}
+	if schedule.CueListID != nil {
+		rule.CueListID = *schedule.CueListID
+	}
+	return rule, nil
+}
+
+// scheduleDays decodes the days a stored schedule fires on.
+func scheduleDays(schedule *models.Schedule) ([]time.Weekday, error) {
+	if schedule.Days == "" {
+		return nil, nil
+	}
+	var days []time.Weekday
+	if err := json.Unmarshal([]byte(schedule.Days), &days); err != nil {
+		return nil, fmt.Errorf("invalid days for schedule %s: %w", schedule.ID, err)
+	}
+	for _, day := range days {
+		if day < time.Sunday || day > time.Saturday {
+			return nil, fmt.Errorf("invalid day %d for schedule %s", day, schedule.ID)
+		}
+	}
+	return days, nil
+}
+
+// convertScheduleFiredEvent converts a scheduler.Event to
+// generated.ScheduleFiredEvent.
+func convertScheduleFiredEvent(event *scheduler.Event) *generated.ScheduleFiredEvent {
+	result := &generated.ScheduleFiredEvent{
+		ScheduleID:   event.Rule.ID,
+		ScheduleName: event.Rule.Name,
+		ProjectID:    event.Rule.ProjectID,
+		Action:       generated.ScheduleAction(event.Rule.Action),
+		FiredAt:      event.FiredAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	if event.Error != nil {
+		message := event.Error.Error()
+		result.Error = &message
+	}
+	return result
+}
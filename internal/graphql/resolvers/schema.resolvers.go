@@ -8,23 +8,52 @@ package resolvers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"github.com/bbernstein/lacylights-go/internal/services/backup"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/effects"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
 	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/integrity"
+	"github.com/bbernstein/lacylights-go/internal/services/librarysync"
+	"github.com/bbernstein/lacylights-go/internal/services/logging"
 	"github.com/bbernstein/lacylights-go/internal/services/network"
 	"github.com/bbernstein/lacylights-go/internal/services/ofl"
+	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/presence"
 	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/sceneboard"
+	"github.com/bbernstein/lacylights-go/internal/services/showtimer"
+	"github.com/bbernstein/lacylights-go/internal/services/snapshot"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+	"github.com/bbernstein/lacylights-go/internal/services/timecode"
 	"github.com/bbernstein/lacylights-go/internal/services/version"
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
 	"github.com/lucsky/cuid"
 	"gorm.io/gorm"
 )
 
+// EntityType is the resolver for the entityType field.
+func (r *auditLogResolver) EntityType(ctx context.Context, obj *models.AuditLog) (generated.AuditEntityType, error) {
+	return generated.AuditEntityType(obj.EntityType), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *auditLogResolver) CreatedAt(ctx context.Context, obj *models.AuditLog) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
 // Type is the resolver for the type field.
 func (r *channelDefinitionResolver) Type(ctx context.Context, obj *models.ChannelDefinition) (generated.ChannelType, error) {
 	return generated.ChannelType(obj.Type), nil
@@ -39,9 +68,14 @@ func (r *channelDefinitionResolver) FadeBehavior(ctx context.Context, obj *model
 	return generated.FadeBehavior(obj.FadeBehavior), nil
 }
 
+// Capabilities is the resolver for the capabilities field.
+func (r *channelDefinitionResolver) Capabilities(ctx context.Context, obj *models.ChannelDefinition) ([]*models.ChannelCapability, error) {
+	return r.loadersFor(ctx).ChannelCapabilities.Load(ctx, obj.ID)
+}
+
 // Scene is the resolver for the scene field.
 func (r *cueResolver) Scene(ctx context.Context, obj *models.Cue) (*models.Scene, error) {
-	return r.SceneRepo.FindByID(ctx, obj.SceneID)
+	return r.loadersFor(ctx).Scenes.Load(ctx, obj.SceneID)
 }
 
 // CueList is the resolver for the cueList field.
@@ -49,6 +83,15 @@ func (r *cueResolver) CueList(ctx context.Context, obj *models.Cue) (*models.Cue
 	return r.CueListRepo.FindByID(ctx, obj.CueListID)
 }
 
+// FollowQuantize is the resolver for the followQuantize field.
+func (r *cueResolver) FollowQuantize(ctx context.Context, obj *models.Cue) (*generated.BeatQuantize, error) {
+	if obj.FollowQuantize == nil {
+		return nil, nil
+	}
+	q := generated.BeatQuantize(*obj.FollowQuantize)
+	return &q, nil
+}
+
 // EasingType is the resolver for the easingType field.
 func (r *cueResolver) EasingType(ctx context.Context, obj *models.Cue) (*generated.EasingType, error) {
 	if obj.EasingType == nil {
@@ -58,6 +101,38 @@ func (r *cueResolver) EasingType(ctx context.Context, obj *models.Cue) (*generat
 	return &et, nil
 }
 
+// TrackedValues is the resolver for the trackedValues field.
+func (r *cueResolver) TrackedValues(ctx context.Context, obj *models.Cue) ([]*generated.TrackedChannelValue, error) {
+	values, err := r.PlaybackService.TrackedValues(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.TrackedChannelValue, len(values))
+	for i, v := range values {
+		result[i] = &generated.TrackedChannelValue{
+			FixtureID:   v.FixtureID,
+			Offset:      v.Offset,
+			Value:       v.Value,
+			SourceCueID: v.SourceCueID,
+			Tracked:     v.SourceCueID != obj.ID,
+		}
+	}
+	return result, nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *cueResolver) UpdatedAt(ctx context.Context, obj *models.Cue) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// PlaybackMode is the resolver for the playbackMode field.
+func (r *cueListResolver) PlaybackMode(ctx context.Context, obj *models.CueList) (generated.CueListPlaybackMode, error) {
+	if obj.PlaybackMode == "" {
+		return generated.CueListPlaybackModeTimed, nil
+	}
+	return generated.CueListPlaybackMode(obj.PlaybackMode), nil
+}
+
 // Project is the resolver for the project field.
 func (r *cueListResolver) Project(ctx context.Context, obj *models.CueList) (*models.Project, error) {
 	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
@@ -65,15 +140,7 @@ func (r *cueListResolver) Project(ctx context.Context, obj *models.CueList) (*mo
 
 // Cues is the resolver for the cues field.
 func (r *cueListResolver) Cues(ctx context.Context, obj *models.CueList) ([]*models.Cue, error) {
-	cues, err := r.CueRepo.FindByCueListID(ctx, obj.ID)
-	if err != nil {
-		return nil, err
-	}
-	pointers := make([]*models.Cue, len(cues))
-	for i := range cues {
-		pointers[i] = &cues[i]
-	}
-	return pointers, nil
+	return r.loadersFor(ctx).CueListCues.Load(ctx, obj.ID)
 }
 
 // CueCount is the resolver for the cueCount field.
@@ -105,7 +172,37 @@ func (r *cueListResolver) CreatedAt(ctx context.Context, obj *models.CueList) (s
 
 // UpdatedAt is the resolver for the updatedAt field.
 func (r *cueListResolver) UpdatedAt(ctx context.Context, obj *models.CueList) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Type is the resolver for the type field.
+func (r *effectResolver) Type(ctx context.Context, obj *models.Effect) (generated.EffectType, error) {
+	return generated.EffectType(obj.Type), nil
+}
+
+// FixtureIds is the resolver for the fixtureIds field.
+func (r *effectResolver) FixtureIds(ctx context.Context, obj *models.Effect) ([]string, error) {
+	return effectFixtureIDs(obj)
+}
+
+// Order is the resolver for the order field.
+func (r *effectResolver) Order(ctx context.Context, obj *models.Effect) (generated.EffectOrder, error) {
+	return generated.EffectOrder(obj.Order), nil
+}
+
+// IsRunning is the resolver for the isRunning field.
+func (r *effectResolver) IsRunning(ctx context.Context, obj *models.Effect) (bool, error) {
+	return r.EffectService.IsRunning(obj.ID), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *effectResolver) CreatedAt(ctx context.Context, obj *models.Effect) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *effectResolver) UpdatedAt(ctx context.Context, obj *models.Effect) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
 }
 
 // Type is the resolver for the type field.
@@ -115,37 +212,66 @@ func (r *fixtureDefinitionResolver) Type(ctx context.Context, obj *models.Fixtur
 
 // Channels is the resolver for the channels field.
 func (r *fixtureDefinitionResolver) Channels(ctx context.Context, obj *models.FixtureDefinition) ([]*models.ChannelDefinition, error) {
-	var channels []models.ChannelDefinition
-	result := r.db.Where("definition_id = ?", obj.ID).Order("\"offset\" ASC").Find(&channels)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	pointers := make([]*models.ChannelDefinition, len(channels))
-	for i := range channels {
-		pointers[i] = &channels[i]
+	return r.loadersFor(ctx).DefinitionChannels.Load(ctx, obj.ID)
+}
+
+// Cells is the resolver for the cells field.
+func (r *fixtureDefinitionResolver) Cells(ctx context.Context, obj *models.FixtureDefinition) ([]*generated.FixtureCell, error) {
+	channels, err := r.loadersFor(ctx).DefinitionChannels.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
 	}
-	return pointers, nil
+	return definitionCells(channels), nil
 }
 
 // Modes is the resolver for the modes field.
 func (r *fixtureDefinitionResolver) Modes(ctx context.Context, obj *models.FixtureDefinition) ([]*models.FixtureMode, error) {
-	var modes []models.FixtureMode
-	result := r.db.Where("definition_id = ?", obj.ID).Order("name ASC").Find(&modes)
-	if result.Error != nil {
-		return nil, result.Error
+	return r.loadersFor(ctx).DefinitionModes.Load(ctx, obj.ID)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *fixtureDefinitionResolver) CreatedAt(ctx context.Context, obj *models.FixtureDefinition) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// FixtureIds is the resolver for the fixtureIds field.
+func (r *fixtureGroupResolver) FixtureIds(ctx context.Context, obj *models.FixtureGroup) ([]string, error) {
+	return repositories.GroupFixtureIDs(obj)
+}
+
+// Fixtures is the resolver for the fixtures field.
+func (r *fixtureGroupResolver) Fixtures(ctx context.Context, obj *models.FixtureGroup) ([]*models.FixtureInstance, error) {
+	ids, err := repositories.GroupFixtureIDs(obj)
+	if err != nil {
+		return nil, err
 	}
-	pointers := make([]*models.FixtureMode, len(modes))
-	for i := range modes {
-		pointers[i] = &modes[i]
+	var fixtures []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&fixtures).Error; err != nil {
+		return nil, err
 	}
-	return pointers, nil
+	byID := make(map[string]*models.FixtureInstance, len(fixtures))
+	for i := range fixtures {
+		byID[fixtures[i].ID] = &fixtures[i]
+	}
+	result := make([]*models.FixtureInstance, 0, len(ids))
+	for _, id := range ids {
+		if fixture, ok := byID[id]; ok {
+			result = append(result, fixture)
+		}
+	}
+	return result, nil
 }
 
 // CreatedAt is the resolver for the createdAt field.
-func (r *fixtureDefinitionResolver) CreatedAt(ctx context.Context, obj *models.FixtureDefinition) (string, error) {
+func (r *fixtureGroupResolver) CreatedAt(ctx context.Context, obj *models.FixtureGroup) (string, error) {
 	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
 }
 
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *fixtureGroupResolver) UpdatedAt(ctx context.Context, obj *models.FixtureGroup) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
 // Manufacturer is the resolver for the manufacturer field.
 func (r *fixtureInstanceResolver) Manufacturer(ctx context.Context, obj *models.FixtureInstance) (string, error) {
 	if obj.Manufacturer != nil {
@@ -188,16 +314,25 @@ func (r *fixtureInstanceResolver) ChannelCount(ctx context.Context, obj *models.
 
 // Channels is the resolver for the channels field on FixtureInstance.
 func (r *fixtureInstanceResolver) Channels(ctx context.Context, obj *models.FixtureInstance) ([]*models.InstanceChannel, error) {
-	var channels []models.InstanceChannel
-	result := r.db.Where("fixture_id = ?", obj.ID).Order("\"offset\" ASC").Find(&channels)
-	if result.Error != nil {
-		return nil, result.Error
+	return r.loadersFor(ctx).InstanceChannels.Load(ctx, obj.ID)
+}
+
+// Cells is the resolver for the cells field.
+func (r *fixtureInstanceResolver) Cells(ctx context.Context, obj *models.FixtureInstance) ([]*generated.FixtureInstanceCell, error) {
+	channels, err := r.loadersFor(ctx).InstanceChannels.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
 	}
-	pointers := make([]*models.InstanceChannel, len(channels))
-	for i := range channels {
-		pointers[i] = &channels[i]
+	return instanceCells(channels), nil
+}
+
+// MasterChannels is the resolver for the masterChannels field.
+func (r *fixtureInstanceResolver) MasterChannels(ctx context.Context, obj *models.FixtureInstance) ([]*models.InstanceChannel, error) {
+	channels, err := r.loadersFor(ctx).InstanceChannels.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
 	}
-	return pointers, nil
+	return masterChannels(channels), nil
 }
 
 // Project is the resolver for the project field.
@@ -205,6 +340,11 @@ func (r *fixtureInstanceResolver) Project(ctx context.Context, obj *models.Fixtu
 	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
 }
 
+// UniverseConfig is the resolver for the universeConfig field.
+func (r *fixtureInstanceResolver) UniverseConfig(ctx context.Context, obj *models.FixtureInstance) (*models.Universe, error) {
+	return r.UniverseRepo.FindByNumber(ctx, obj.ProjectID, obj.Universe)
+}
+
 // Tags is the resolver for the tags field.
 func (r *fixtureInstanceResolver) Tags(ctx context.Context, obj *models.FixtureInstance) ([]string, error) {
 	if obj.Tags == nil || *obj.Tags == "" || *obj.Tags == "[]" {
@@ -212,7 +352,7 @@ func (r *fixtureInstanceResolver) Tags(ctx context.Context, obj *models.FixtureI
 	}
 	var tags []string
 	if err := json.Unmarshal([]byte(*obj.Tags), &tags); err != nil {
-		log.Printf("Warning: failed to unmarshal tags for fixture %s: %v", obj.ID, err)
+		log.Warn("failed to unmarshal fixture tags", "fixture", obj.ID, "error", err)
 		return []string{}, nil
 	}
 	return tags, nil
@@ -223,23 +363,19 @@ func (r *fixtureInstanceResolver) CreatedAt(ctx context.Context, obj *models.Fix
 	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
 }
 
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *fixtureInstanceResolver) UpdatedAt(ctx context.Context, obj *models.FixtureInstance) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
 // Channels is the resolver for the channels field.
 func (r *fixtureModeResolver) Channels(ctx context.Context, obj *models.FixtureMode) ([]*models.ModeChannel, error) {
-	var channels []models.ModeChannel
-	result := r.db.Where("mode_id = ?", obj.ID).Order("\"offset\" ASC").Find(&channels)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	pointers := make([]*models.ModeChannel, len(channels))
-	for i := range channels {
-		pointers[i] = &channels[i]
-	}
-	return pointers, nil
+	return r.loadersFor(ctx).ModeChannels.Load(ctx, obj.ID)
 }
 
 // Fixture is the resolver for the fixture field.
 func (r *fixtureValueResolver) Fixture(ctx context.Context, obj *models.FixtureValue) (*models.FixtureInstance, error) {
-	return r.FixtureRepo.FindByID(ctx, obj.FixtureID)
+	return r.loadersFor(ctx).Fixtures.Load(ctx, obj.FixtureID)
 }
 
 // Channels is the resolver for the channels field.
@@ -259,6 +395,36 @@ func (r *fixtureValueResolver) Channels(ctx context.Context, obj *models.Fixture
 	return result, nil
 }
 
+// PaletteIds is the resolver for the paletteIds field.
+func (r *fixtureValueResolver) PaletteIds(ctx context.Context, obj *models.FixtureValue) ([]string, error) {
+	return repositories.FixtureValuePaletteIDs(obj)
+}
+
+// Group is the resolver for the group field.
+func (r *groupValueResolver) Group(ctx context.Context, obj *models.GroupValue) (*models.FixtureGroup, error) {
+	return r.findFixtureGroup(ctx, obj.GroupID)
+}
+
+// Channels is the resolver for the channels field.
+func (r *groupValueResolver) Channels(ctx context.Context, obj *models.GroupValue) ([]*generated.TypedChannelValue, error) {
+	return typedChannelValues(obj.Channels)
+}
+
+// Fixtures is the resolver for the fixtures field.
+func (r *houseLightsResolver) Fixtures(ctx context.Context, obj *models.HouseLights) ([]*models.FixtureInstance, error) {
+	return r.houseLightsFixtures(ctx, obj)
+}
+
+// Groups is the resolver for the groups field.
+func (r *houseLightsResolver) Groups(ctx context.Context, obj *models.HouseLights) ([]*models.FixtureGroup, error) {
+	return r.houseLightsGroups(ctx, obj)
+}
+
+// Status is the resolver for the status field.
+func (r *houseLightsResolver) Status(ctx context.Context, obj *models.HouseLights) (*generated.HouseLightsStatus, error) {
+	return convertHouseLightsStatus(r.HouseLightsService.Status(obj.ProjectID)), nil
+}
+
 // Type is the resolver for the type field.
 func (r *instanceChannelResolver) Type(ctx context.Context, obj *models.InstanceChannel) (generated.ChannelType, error) {
 	return generated.ChannelType(obj.Type), nil
@@ -273,14 +439,28 @@ func (r *instanceChannelResolver) FadeBehavior(ctx context.Context, obj *models.
 	return generated.FadeBehavior(obj.FadeBehavior), nil
 }
 
+// Fixtures is the resolver for the fixtures field.
+func (r *layoutZoneResolver) Fixtures(ctx context.Context, obj *models.LayoutZone) ([]*models.FixtureInstance, error) {
+	return r.zoneFixtures(ctx, obj)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *layoutZoneResolver) CreatedAt(ctx context.Context, obj *models.LayoutZone) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *layoutZoneResolver) UpdatedAt(ctx context.Context, obj *models.LayoutZone) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
 // Channel is the resolver for the channel field.
 func (r *modeChannelResolver) Channel(ctx context.Context, obj *models.ModeChannel) (*models.ChannelDefinition, error) {
-	var channel models.ChannelDefinition
-	result := r.db.First(&channel, "id = ?", obj.ChannelID)
-	if result.Error != nil {
-		return nil, result.Error
+	channel, err := r.loadersFor(ctx).ChannelDefinitions.Load(ctx, obj.ChannelID)
+	if err == nil && channel == nil {
+		err = fmt.Errorf("channel definition not found: %s", obj.ChannelID)
 	}
-	return &channel, nil
+	return channel, err
 }
 
 // CreateProject is the resolver for the createProject field.
@@ -291,9 +471,16 @@ func (r *mutationResolver) CreateProject(ctx context.Context, input generated.Cr
 	if input.Description.IsSet() {
 		project.Description = input.Description.Value()
 	}
+	if err := setFadeTime(&project.DefaultFadeIn, "defaultFadeIn", input.DefaultFadeIn); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&project.DefaultFadeOut, "defaultFadeOut", input.DefaultFadeOut); err != nil {
+		return nil, err
+	}
 	if err := r.ProjectRepo.Create(ctx, project); err != nil {
 		return nil, err
 	}
+	r.AuthService.GrantOwner(ctx, project.ID)
 	return project, nil
 }
 
@@ -310,6 +497,12 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input g
 	if input.Description.IsSet() {
 		project.Description = input.Description.Value()
 	}
+	if err := setFadeTime(&project.DefaultFadeIn, "defaultFadeIn", input.DefaultFadeIn); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&project.DefaultFadeOut, "defaultFadeOut", input.DefaultFadeOut); err != nil {
+		return nil, err
+	}
 	if err := r.ProjectRepo.Update(ctx, project); err != nil {
 		return nil, err
 	}
@@ -318,9 +511,51 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input g
 
 // DeleteProject is the resolver for the deleteProject field.
 func (r *mutationResolver) DeleteProject(ctx context.Context, id string) (bool, error) {
+	r.snapshotBefore(ctx, id, snapshot.ReasonBeforeDelete)
 	if err := r.ProjectRepo.Delete(ctx, id); err != nil {
 		return false, err
 	}
+	if err := r.deleteEffects(ctx, "project_id = ?", id); err != nil {
+		return false, err
+	}
+	if err := r.deleteSubmasters(ctx, "project_id = ?", id); err != nil {
+		return false, err
+	}
+	if err := r.deleteSchedules(ctx, "project_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := r.deleteSoftPatches(ctx, "project_id = ?", id); err != nil {
+		return false, err
+	}
+	if err := r.UniverseRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.LayoutZoneRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.SelectionSetRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.HouseLightsRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	r.HouseLightsService.Release(id)
+	if err := r.deleteProjectPixelMatrices(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.FixtureGroupRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.PaletteRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.UndoRepo.DeleteByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.UserRepo.DeleteMembershipsByProjectID(ctx, id); err != nil {
+		return false, err
+	}
+	r.refreshOutputLimits(ctx)
 	return true, nil
 }
 
@@ -360,6 +595,13 @@ func (r *mutationResolver) BulkUpdateProjects(ctx context.Context, input generat
 			project.Description = item.Description.Value()
 		}
 
+		if err := setFadeTime(&project.DefaultFadeIn, "defaultFadeIn", item.DefaultFadeIn); err != nil {
+			return nil, err
+		}
+		if err := setFadeTime(&project.DefaultFadeOut, "defaultFadeOut", item.DefaultFadeOut); err != nil {
+			return nil, err
+		}
+
 		if err := r.ProjectRepo.Update(ctx, project); err != nil {
 			return nil, err
 		}
@@ -380,6 +622,7 @@ func (r *mutationResolver) BulkDeleteProjects(ctx context.Context, projectIds []
 		}
 		deletedIds = append(deletedIds, projectID)
 	}
+	r.refreshOutputLimits(ctx)
 
 	return &generated.BulkDeleteResult{
 		DeletedCount: len(deletedIds),
@@ -387,8 +630,32 @@ func (r *mutationResolver) BulkDeleteProjects(ctx context.Context, projectIds []
 	}, nil
 }
 
+// UpdateProjectNamingConvention is the resolver for the updateProjectNamingConvention field.
+func (r *mutationResolver) UpdateProjectNamingConvention(ctx context.Context, projectID string, input generated.NamingConventionInput) (*generated.NamingConvention, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	if err := applyNamingConventionInput(project, input); err != nil {
+		return nil, err
+	}
+	if err := r.ProjectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return convertNamingConvention(project), nil
+}
+
 // CreateFixtureDefinition is the resolver for the createFixtureDefinition field.
 func (r *mutationResolver) CreateFixtureDefinition(ctx context.Context, input generated.CreateFixtureDefinitionInput) (*models.FixtureDefinition, error) {
+	if err := validateChannelCells(input.Channels); err != nil {
+		return nil, err
+	}
+
 	// Check if definition with same manufacturer/model already exists
 	existing, err := r.FixtureRepo.FindDefinitionByManufacturerModel(ctx, input.Manufacturer, input.Model)
 	if err != nil {
@@ -427,6 +694,7 @@ func (r *mutationResolver) CreateFixtureDefinition(ctx context.Context, input ge
 		if ch.IsDiscrete.IsSet() && ch.IsDiscrete.Value() != nil {
 			channelDef.IsDiscrete = *ch.IsDiscrete.Value()
 		}
+		channelDef.Cell = ch.Cell.Value()
 
 		// Apply FadeBehavior if provided, otherwise auto-detect based on IsDiscrete
 		if ch.FadeBehavior.IsSet() && ch.FadeBehavior.Value() != nil {
@@ -496,8 +764,17 @@ func (r *mutationResolver) ImportOFLFixture(ctx context.Context, input generated
 	return r.OFLService.ImportFixture(ctx, input.Manufacturer, input.OflFixtureJSON, replace)
 }
 
+// ImportGDTFFixture is the resolver for the importGDTFFixture field.
+func (r *mutationResolver) ImportGDTFFixture(ctx context.Context, input generated.ImportGDTFFixtureInput) (*models.FixtureDefinition, error) {
+	return r.importGDTFFixture(ctx, input)
+}
+
 // UpdateFixtureDefinition is the resolver for the updateFixtureDefinition field.
 func (r *mutationResolver) UpdateFixtureDefinition(ctx context.Context, id string, input generated.CreateFixtureDefinitionInput) (*models.FixtureDefinition, error) {
+	if err := validateChannelCells(input.Channels); err != nil {
+		return nil, err
+	}
+
 	// Find existing definition
 	definition, err := r.FixtureRepo.FindDefinitionByID(ctx, id)
 	if err != nil {
@@ -546,6 +823,7 @@ func (r *mutationResolver) UpdateFixtureDefinition(ctx context.Context, id strin
 		if ch.IsDiscrete.IsSet() && ch.IsDiscrete.Value() != nil {
 			channelDef.IsDiscrete = *ch.IsDiscrete.Value()
 		}
+		channelDef.Cell = ch.Cell.Value()
 
 		// Apply FadeBehavior if provided, otherwise auto-detect based on IsDiscrete
 		if ch.FadeBehavior.IsSet() && ch.FadeBehavior.Value() != nil {
@@ -580,28 +858,28 @@ func (r *mutationResolver) DeleteFixtureDefinition(ctx context.Context, id strin
 		return false, fmt.Errorf("fixture definition not found: %s", id)
 	}
 
-	// Check if any fixture instances use this definition
-	count, err := r.FixtureRepo.CountInstancesByDefinitionID(ctx, id)
+	// Refuse while any project still uses this definition
+	usage, err := r.definitionUsage(ctx, id)
 	if err != nil {
 		return false, err
 	}
-	if count > 0 {
-		return false, fmt.Errorf("cannot delete fixture definition: %d fixture instances are using it", count)
-	}
-
-	// Delete channel definitions first
-	if err := r.FixtureRepo.DeleteChannelDefinitions(ctx, id); err != nil {
-		return false, err
+	if len(usage) > 0 {
+		return false, definitionInUseError(definition, usage)
 	}
 
-	// Delete the definition
-	if err := r.FixtureRepo.DeleteDefinition(ctx, id); err != nil {
+	// Delete the definition with its channels and modes
+	if err := r.deleteDefinitionRecords(ctx, id); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
 
+// ForceDeleteDefinition is the resolver for the forceDeleteDefinition field.
+func (r *mutationResolver) ForceDeleteDefinition(ctx context.Context, id string, remapToDefinitionID *string, remapToModeID *string) (*generated.ForceDeleteDefinitionResult, error) {
+	return r.forceDeleteDefinition(ctx, id, remapToDefinitionID, remapToModeID)
+}
+
 // BulkCreateFixtureDefinitions is the resolver for the bulkCreateFixtureDefinitions field.
 func (r *mutationResolver) BulkCreateFixtureDefinitions(ctx context.Context, input generated.BulkFixtureDefinitionCreateInput) ([]*models.FixtureDefinition, error) {
 	var createdDefinitions []*models.FixtureDefinition
@@ -697,6 +975,13 @@ func (r *mutationResolver) CreateFixtureInstance(ctx context.Context, input gene
 		fixture.Description = input.Description.Value()
 	}
 
+	if input.MaxIntensity.IsSet() {
+		if err := validateMaxIntensity(input.MaxIntensity.Value()); err != nil {
+			return nil, err
+		}
+		fixture.MaxIntensity = input.MaxIntensity.Value()
+	}
+
 	// Handle mode - store mode name if ModeID is provided
 	var modeID *string
 	if input.ModeID.IsSet() && input.ModeID.Value() != nil {
@@ -747,6 +1032,7 @@ func (r *mutationResolver) CreateFixtureInstance(ctx context.Context, input gene
 					MinValue:     channelDef.MinValue,
 					MaxValue:     channelDef.MaxValue,
 					DefaultValue: channelDef.DefaultValue,
+					Cell:         channelDef.Cell,
 				})
 			}
 		}
@@ -768,20 +1054,33 @@ func (r *mutationResolver) CreateFixtureInstance(ctx context.Context, input gene
 				MinValue:     dc.MinValue,
 				MaxValue:     dc.MaxValue,
 				DefaultValue: dc.DefaultValue,
+				Cell:         dc.Cell,
 			})
 		}
 	}
 
+	// The fixture's channels must be free
+	if err := r.validatePatch(ctx, fixture); err != nil {
+		return nil, err
+	}
+
 	// Create fixture with channels in a transaction
 	if err := r.FixtureRepo.CreateWithChannels(ctx, fixture, instanceChannels); err != nil {
 		return nil, err
 	}
 
+	if fixture.MaxIntensity != nil {
+		r.refreshOutputLimits(ctx)
+	}
+
+	r.commitUndoCreate(ctx, fixture.ProjectID, "Create fixture "+fixture.Name, undoFixture(fixture.ID))
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, fixture)
+
 	return fixture, nil
 }
 
 // UpdateFixtureInstance is the resolver for the updateFixtureInstance field.
-func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string, input generated.UpdateFixtureInstanceInput) (*models.FixtureInstance, error) {
+func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string, input generated.UpdateFixtureInstanceInput, expectedUpdatedAt *string) (*models.FixtureInstance, error) {
 	// Get existing fixture
 	fixture, err := r.FixtureRepo.FindByID(ctx, id)
 	if err != nil {
@@ -790,6 +1089,14 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 	if fixture == nil {
 		return nil, fmt.Errorf("fixture not found: %s", id)
 	}
+	if changedSince(fixture.UpdatedAt, expectedUpdatedAt) {
+		return nil, fixtureConflict(ctx, fixture)
+	}
+
+	undo, err := r.beginUndo(ctx, fixture.ProjectID, "Update fixture "+fixture.Name, undoFixture(id))
+	if err != nil {
+		return nil, err
+	}
 
 	// Update fields if provided
 	if input.Name.IsSet() && input.Name.Value() != nil {
@@ -800,11 +1107,14 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 		fixture.Description = input.Description.Value()
 	}
 
+	addressChanged := false
 	if input.Universe.IsSet() && input.Universe.Value() != nil {
+		addressChanged = addressChanged || fixture.Universe != *input.Universe.Value()
 		fixture.Universe = *input.Universe.Value()
 	}
 
 	if input.StartChannel.IsSet() && input.StartChannel.Value() != nil {
+		addressChanged = addressChanged || fixture.StartChannel != *input.StartChannel.Value()
 		fixture.StartChannel = *input.StartChannel.Value()
 	}
 
@@ -824,6 +1134,13 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 		fixture.LayoutRotation = input.LayoutRotation.Value()
 	}
 
+	if input.MaxIntensity.IsSet() {
+		if err := validateMaxIntensity(input.MaxIntensity.Value()); err != nil {
+			return nil, err
+		}
+		fixture.MaxIntensity = input.MaxIntensity.Value()
+	}
+
 	// Handle tags update
 	if input.Tags.IsSet() {
 		if len(input.Tags.Value()) > 0 {
@@ -878,14 +1195,8 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 	}
 
 	// If definition or mode changed, rebuild channels
+	var instanceChannels []models.InstanceChannel
 	if needsChannelRebuild {
-		// Delete existing instance channels
-		if err := r.FixtureRepo.DeleteInstanceChannels(ctx, fixture.ID); err != nil {
-			return nil, err
-		}
-
-		// Create new instance channels
-		var instanceChannels []models.InstanceChannel
 		if newModeID != nil {
 			modeChannels, err := r.FixtureRepo.GetModeChannels(ctx, *newModeID)
 			if err != nil {
@@ -904,6 +1215,7 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 						Offset:    mc.Offset,
 						Name:      channelDef.Name,
 						Type:      channelDef.Type,
+						Cell:      channelDef.Cell,
 					})
 				}
 			}
@@ -920,26 +1232,54 @@ func (r *mutationResolver) UpdateFixtureInstance(ctx context.Context, id string,
 					Offset:    dc.Offset,
 					Name:      dc.Name,
 					Type:      dc.Type,
+					Cell:      dc.Cell,
 				})
 			}
 		}
+	}
 
-		if err := r.FixtureRepo.CreateInstanceChannels(ctx, instanceChannels); err != nil {
+	// A moved or resized fixture must stay clear of the others; existing
+	// overlaps don't block unrelated edits
+	if addressChanged || needsChannelRebuild {
+		if err := r.validatePatch(ctx, fixture); err != nil {
 			return nil, err
 		}
 	}
 
-	// Save the fixture
-	if err := r.FixtureRepo.Update(ctx, fixture); err != nil {
+	err = r.writeUnchanged(ctx, generated.ProjectEntityTypeFixture, id, fixture.UpdatedAt, expectedUpdatedAt, func(tx *Resolver) error {
+		if needsChannelRebuild {
+			// Replace the instance channels
+			if err := tx.FixtureRepo.DeleteInstanceChannels(ctx, fixture.ID); err != nil {
+				return err
+			}
+			if err := tx.FixtureRepo.CreateInstanceChannels(ctx, instanceChannels); err != nil {
+				return err
+			}
+		}
+
+		return tx.FixtureRepo.Update(ctx, fixture)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	// Address, channel, or cap changes all move the output limits
+	r.refreshOutputLimits(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, fixture)
+
+	if input.LayoutX.IsSet() || input.LayoutY.IsSet() || input.LayoutRotation.IsSet() {
+		r.publishLayoutChange(fixture.ProjectID, []*models.FixtureInstance{fixture}, nil, nil)
+	}
+
 	return fixture, nil
 }
 
 // BulkUpdateFixtures is the resolver for the bulkUpdateFixtures field.
 func (r *mutationResolver) BulkUpdateFixtures(ctx context.Context, input generated.BulkFixtureUpdateInput) ([]*models.FixtureInstance, error) {
 	var updatedFixtures []*models.FixtureInstance
+	moved := make(map[string]bool)
 
 	for _, item := range input.Fixtures {
 		fixture, err := r.FixtureRepo.FindByID(ctx, item.FixtureID)
@@ -960,10 +1300,12 @@ func (r *mutationResolver) BulkUpdateFixtures(ctx context.Context, input generat
 		}
 
 		if item.Universe.IsSet() && item.Universe.Value() != nil {
+			moved[fixture.ID] = moved[fixture.ID] || fixture.Universe != *item.Universe.Value()
 			fixture.Universe = *item.Universe.Value()
 		}
 
 		if item.StartChannel.IsSet() && item.StartChannel.Value() != nil {
+			moved[fixture.ID] = moved[fixture.ID] || fixture.StartChannel != *item.StartChannel.Value()
 			fixture.StartChannel = *item.StartChannel.Value()
 		}
 
@@ -988,11 +1330,22 @@ func (r *mutationResolver) BulkUpdateFixtures(ctx context.Context, input generat
 			fixture.LayoutRotation = item.LayoutRotation.Value()
 		}
 
+		updatedFixtures = append(updatedFixtures, fixture)
+	}
+
+	// Moves are checked against where every fixture ends up, so fixtures
+	// can swap addresses
+	if err := r.validateBulkPatch(ctx, updatedFixtures, moved); err != nil {
+		return nil, err
+	}
+	for _, fixture := range updatedFixtures {
 		if err := r.FixtureRepo.Update(ctx, fixture); err != nil {
 			return nil, err
 		}
-
-		updatedFixtures = append(updatedFixtures, fixture)
+	}
+	r.refreshOutputLimits(ctx)
+	for _, fixture := range updatedFixtures {
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, fixture)
 	}
 
 	return updatedFixtures, nil
@@ -1025,6 +1378,11 @@ func (r *mutationResolver) DeleteFixtureInstance(ctx context.Context, id string)
 		return false, fmt.Errorf("fixture not found: %s", id)
 	}
 
+	undo, err := r.beginUndo(ctx, fixture.ProjectID, "Delete fixture "+fixture.Name, undoFixture(id))
+	if err != nil {
+		return false, err
+	}
+
 	// Delete instance channels first
 	if err := r.FixtureRepo.DeleteInstanceChannels(ctx, id); err != nil {
 		return false, err
@@ -1034,9 +1392,37 @@ func (r *mutationResolver) DeleteFixtureInstance(ctx context.Context, id string)
 	if err := r.FixtureRepo.Delete(ctx, id); err != nil {
 		return false, err
 	}
+	if err := r.removeFixtureFromGroups(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.removeFixtureFromSelectionSets(ctx, id); err != nil {
+		return false, err
+	}
 
-	return true, nil
-}
+	if fixture.MaxIntensity != nil {
+		r.refreshOutputLimits(ctx)
+	}
+
+	r.commitUndo(ctx, undo)
+	r.publishEntityChange(ctx, generated.EntityChangeActionDeleted, fixture)
+
+	return true, nil
+}
+
+// DuplicateFixtureInstance is the resolver for the duplicateFixtureInstance field.
+func (r *mutationResolver) DuplicateFixtureInstance(ctx context.Context, id string, count *int, layoutOffsetX *float64, layoutOffsetY *float64) ([]*models.FixtureInstance, error) {
+	n, offsetX, offsetY := 1, 0.0, 0.0
+	if count != nil {
+		n = *count
+	}
+	if layoutOffsetX != nil {
+		offsetX = *layoutOffsetX
+	}
+	if layoutOffsetY != nil {
+		offsetY = *layoutOffsetY
+	}
+	return r.duplicateFixture(ctx, id, n, offsetX, offsetY)
+}
 
 // BulkDeleteFixtures is the resolver for the bulkDeleteFixtures field.
 func (r *mutationResolver) BulkDeleteFixtures(ctx context.Context, fixtureIds []string) (*generated.BulkDeleteResult, error) {
@@ -1056,6 +1442,11 @@ func (r *mutationResolver) BulkDeleteFixtures(ctx context.Context, fixtureIds []
 	}, nil
 }
 
+// ImportPatchSheet is the resolver for the importPatchSheet field.
+func (r *mutationResolver) ImportPatchSheet(ctx context.Context, projectID string, csvContent string) (*generated.PatchSheetImportResult, error) {
+	return r.importPatchSheet(ctx, projectID, csvContent)
+}
+
 // UpdateInstanceChannelFadeBehavior is the resolver for the updateInstanceChannelFadeBehavior field.
 // Updates the fade behavior for a single instance channel.
 func (r *mutationResolver) UpdateInstanceChannelFadeBehavior(ctx context.Context, channelID string, fadeBehavior generated.FadeBehavior) (*models.InstanceChannel, error) {
@@ -1070,6 +1461,7 @@ func (r *mutationResolver) UpdateInstanceChannelFadeBehavior(ctx context.Context
 	if err := r.db.WithContext(ctx).Save(&channel).Error; err != nil {
 		return nil, fmt.Errorf("failed to update channel fade behavior: %w", err)
 	}
+	r.publishEntityUpdated(ctx, generated.ProjectEntityTypeFixture, channel.FixtureID)
 
 	return &channel, nil
 }
@@ -1129,6 +1521,7 @@ func (r *mutationResolver) ReorderProjectFixtures(ctx context.Context, projectID
 		if err := r.FixtureRepo.Update(ctx, fixture); err != nil {
 			return false, err
 		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, fixture)
 	}
 
 	return true, nil
@@ -1160,12 +1553,14 @@ func (r *mutationResolver) ReorderSceneFixtures(ctx context.Context, sceneID str
 			return false, err
 		}
 	}
+	r.publishEntityUpdated(ctx, generated.ProjectEntityTypeScene, sceneID)
 
 	return true, nil
 }
 
 // UpdateFixturePositions is the resolver for the updateFixturePositions field.
 func (r *mutationResolver) UpdateFixturePositions(ctx context.Context, positions []*generated.FixturePositionInput) (bool, error) {
+	moved := make(map[string][]*models.FixtureInstance)
 	for _, position := range positions {
 		fixture, err := r.FixtureRepo.FindByID(ctx, position.FixtureID)
 		if err != nil {
@@ -1184,23 +1579,53 @@ func (r *mutationResolver) UpdateFixturePositions(ctx context.Context, positions
 		if err := r.FixtureRepo.Update(ctx, fixture); err != nil {
 			return false, err
 		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, fixture)
+		moved[fixture.ProjectID] = append(moved[fixture.ProjectID], fixture)
 	}
 
+	for projectID, fixtures := range moved {
+		r.publishLayoutChange(projectID, fixtures, nil, nil)
+	}
 	return true, nil
 }
 
 // CreateScene is the resolver for the createScene field.
 func (r *mutationResolver) CreateScene(ctx context.Context, input generated.CreateSceneInput) (*models.Scene, error) {
+	name, err := r.sceneNameForCreate(ctx, input.ProjectID, input.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	scene := &models.Scene{
-		Name:      input.Name,
+		Name:      name,
 		ProjectID: input.ProjectID,
 	}
 
+	if input.SecondaryLabel.IsSet() {
+		scene.SecondaryLabel = input.SecondaryLabel.Value()
+	}
+
 	if input.Description.IsSet() {
 		scene.Description = input.Description.Value()
 	}
 
+	if err := setFadeTime(&scene.DefaultFadeIn, "defaultFadeIn", input.DefaultFadeIn); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&scene.DefaultFadeOut, "defaultFadeOut", input.DefaultFadeOut); err != nil {
+		return nil, err
+	}
+
 	// Convert fixture values
+	if err := r.applyFixtureColors(ctx, input.FixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.applyFixturePalettes(ctx, input.FixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.checkFixtureValues(ctx, input.FixtureValues); err != nil {
+		return nil, err
+	}
 	var fixtureValues []models.FixtureValue
 	for _, fv := range input.FixtureValues {
 		channelsJSON, err := serializeSparseChannels(fv.Channels)
@@ -1208,8 +1633,9 @@ func (r *mutationResolver) CreateScene(ctx context.Context, input generated.Crea
 			return nil, err
 		}
 		value := models.FixtureValue{
-			FixtureID: fv.FixtureID,
-			Channels:  channelsJSON,
+			FixtureID:  fv.FixtureID,
+			Channels:   channelsJSON,
+			PaletteIDs: fixturePaletteIDs(fv),
 		}
 		if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
 			value.SceneOrder = fv.SceneOrder.Value()
@@ -1221,12 +1647,20 @@ func (r *mutationResolver) CreateScene(ctx context.Context, input generated.Crea
 	if err := r.SceneRepo.CreateWithFixtureValues(ctx, scene, fixtureValues); err != nil {
 		return nil, err
 	}
+	if groupValues := input.GroupValues.Value(); len(groupValues) > 0 {
+		if err := r.saveSceneGroupValues(ctx, scene, groupValues); err != nil {
+			return nil, err
+		}
+	}
+
+	r.commitUndoCreate(ctx, scene.ProjectID, "Create scene "+scene.Name, undoScene(scene.ID))
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, scene)
 
 	return scene, nil
 }
 
 // UpdateScene is the resolver for the updateScene field.
-func (r *mutationResolver) UpdateScene(ctx context.Context, id string, input generated.UpdateSceneInput) (*models.Scene, error) {
+func (r *mutationResolver) UpdateScene(ctx context.Context, id string, input generated.UpdateSceneInput, expectedUpdatedAt *string) (*models.Scene, error) {
 	scene, err := r.SceneRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -1234,48 +1668,91 @@ func (r *mutationResolver) UpdateScene(ctx context.Context, id string, input gen
 	if scene == nil {
 		return nil, fmt.Errorf("scene not found: %s", id)
 	}
+	if changedSince(scene.UpdatedAt, expectedUpdatedAt) {
+		return nil, r.sceneConflict(ctx, scene)
+	}
+
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Update scene "+scene.Name, undoScene(id))
+	if err != nil {
+		return nil, err
+	}
 
 	// Update fields if provided
 	if input.Name.IsSet() && input.Name.Value() != nil {
 		scene.Name = *input.Name.Value()
 	}
 
+	if input.SecondaryLabel.IsSet() {
+		scene.SecondaryLabel = input.SecondaryLabel.Value()
+	}
+
 	if input.Description.IsSet() {
 		scene.Description = input.Description.Value()
 	}
 
-	// Update fixture values if provided
-	if input.FixtureValues.IsSet() {
-		// Delete existing fixture values
-		if err := r.SceneRepo.DeleteFixtureValues(ctx, id); err != nil {
-			return nil, err
-		}
+	if err := setFadeTime(&scene.DefaultFadeIn, "defaultFadeIn", input.DefaultFadeIn); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&scene.DefaultFadeOut, "defaultFadeOut", input.DefaultFadeOut); err != nil {
+		return nil, err
+	}
 
-		// Create new fixture values
-		var fixtureValues []models.FixtureValue
-		for _, fv := range input.FixtureValues.Value() {
-			channelsJSON, err := serializeSparseChannels(fv.Channels)
-			if err != nil {
-				return nil, err
+	err = r.writeUnchanged(ctx, generated.ProjectEntityTypeScene, id, scene.UpdatedAt, expectedUpdatedAt, func(tx *Resolver) error {
+		// Update fixture values if provided
+		if input.FixtureValues.IsSet() {
+			if err := tx.applyFixtureColors(ctx, input.FixtureValues.Value()); err != nil {
+				return err
 			}
-			value := models.FixtureValue{
-				SceneID:   id,
-				FixtureID: fv.FixtureID,
-				Channels:  channelsJSON,
+			if err := tx.applyFixturePalettes(ctx, input.FixtureValues.Value()); err != nil {
+				return err
 			}
-			if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
-				value.SceneOrder = fv.SceneOrder.Value()
+			if err := tx.checkFixtureValues(ctx, input.FixtureValues.Value()); err != nil {
+				return err
+			}
+
+			// Delete existing fixture values
+			if err := tx.SceneRepo.DeleteFixtureValues(ctx, id); err != nil {
+				return err
+			}
+
+			// Create new fixture values
+			var fixtureValues []models.FixtureValue
+			for _, fv := range input.FixtureValues.Value() {
+				channelsJSON, err := serializeSparseChannels(fv.Channels)
+				if err != nil {
+					return err
+				}
+				value := models.FixtureValue{
+					SceneID:    id,
+					FixtureID:  fv.FixtureID,
+					Channels:   channelsJSON,
+					PaletteIDs: fixturePaletteIDs(fv),
+				}
+				if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
+					value.SceneOrder = fv.SceneOrder.Value()
+				}
+				fixtureValues = append(fixtureValues, value)
+			}
+
+			if err := tx.SceneRepo.CreateFixtureValues(ctx, fixtureValues); err != nil {
+				return err
 			}
-			fixtureValues = append(fixtureValues, value)
 		}
 
-		if err := r.SceneRepo.CreateFixtureValues(ctx, fixtureValues); err != nil {
-			return nil, err
+		// Group members without their own values follow the group values
+		if input.GroupValues.IsSet() {
+			if err := tx.saveSceneGroupValues(ctx, scene, input.GroupValues.Value()); err != nil {
+				return err
+			}
+		} else if input.FixtureValues.IsSet() {
+			if err := tx.SceneRepo.SyncGroupValues(ctx, id); err != nil {
+				return err
+			}
 		}
-	}
 
-	// Save the scene
-	if err := r.SceneRepo.Update(ctx, scene); err != nil {
+		return tx.SceneRepo.Update(ctx, scene)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -1283,8 +1760,12 @@ func (r *mutationResolver) UpdateScene(ctx context.Context, id string, input gen
 	// so changes are immediately reflected in the output
 	if err := r.reapplyActiveSceneIfNeeded(ctx, id); err != nil {
 		// Log the error but don't fail the update - the scene was saved successfully
-		log.Printf("Warning: failed to re-apply active scene after update: %v", err)
+		log.Warn("failed to re-apply active scene after update", "error", err)
 	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
 
 	return scene, nil
 }
@@ -1308,9 +1789,12 @@ func (r *mutationResolver) DuplicateScene(ctx context.Context, id string) (*mode
 
 	// Create new scene with "(Copy)" suffix
 	newScene := &models.Scene{
-		Name:        original.Name + " (Copy)",
-		Description: original.Description,
-		ProjectID:   original.ProjectID,
+		Name:           original.Name + " (Copy)",
+		SecondaryLabel: original.SecondaryLabel,
+		Description:    original.Description,
+		ProjectID:      original.ProjectID,
+		DefaultFadeIn:  original.DefaultFadeIn,
+		DefaultFadeOut: original.DefaultFadeOut,
 	}
 
 	// Prepare new fixture values
@@ -1320,6 +1804,8 @@ func (r *mutationResolver) DuplicateScene(ctx context.Context, id string) (*mode
 			FixtureID:  v.FixtureID,
 			Channels:   v.Channels,
 			SceneOrder: v.SceneOrder,
+			GroupID:    v.GroupID,
+			PaletteIDs: v.PaletteIDs,
 		})
 	}
 
@@ -1327,6 +1813,12 @@ func (r *mutationResolver) DuplicateScene(ctx context.Context, id string) (*mode
 	if err := r.SceneRepo.CreateWithFixtureValues(ctx, newScene, newValues); err != nil {
 		return nil, err
 	}
+	if err := r.copySceneGroupValues(ctx, original.ID, newScene.ID); err != nil {
+		return nil, err
+	}
+
+	r.commitUndoCreate(ctx, newScene.ProjectID, "Duplicate scene "+original.Name, undoScene(newScene.ID))
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, newScene)
 
 	return newScene, nil
 }
@@ -1350,9 +1842,12 @@ func (r *mutationResolver) CloneScene(ctx context.Context, sceneID string, newNa
 
 	// Create new scene with provided name
 	newScene := &models.Scene{
-		Name:        newName,
-		Description: original.Description,
-		ProjectID:   original.ProjectID,
+		Name:           newName,
+		SecondaryLabel: original.SecondaryLabel,
+		Description:    original.Description,
+		ProjectID:      original.ProjectID,
+		DefaultFadeIn:  original.DefaultFadeIn,
+		DefaultFadeOut: original.DefaultFadeOut,
 	}
 
 	// Prepare new fixture values
@@ -1362,6 +1857,8 @@ func (r *mutationResolver) CloneScene(ctx context.Context, sceneID string, newNa
 			FixtureID:  v.FixtureID,
 			Channels:   v.Channels,
 			SceneOrder: v.SceneOrder,
+			GroupID:    v.GroupID,
+			PaletteIDs: v.PaletteIDs,
 		})
 	}
 
@@ -1369,10 +1866,50 @@ func (r *mutationResolver) CloneScene(ctx context.Context, sceneID string, newNa
 	if err := r.SceneRepo.CreateWithFixtureValues(ctx, newScene, newValues); err != nil {
 		return nil, err
 	}
+	if err := r.copySceneGroupValues(ctx, original.ID, newScene.ID); err != nil {
+		return nil, err
+	}
+
+	r.commitUndoCreate(ctx, newScene.ProjectID, "Clone scene "+original.Name, undoScene(newScene.ID))
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, newScene)
 
 	return newScene, nil
 }
 
+// CopySceneToProject is the resolver for the copySceneToProject field.
+func (r *mutationResolver) CopySceneToProject(ctx context.Context, sceneID string, targetProjectID string, newName *string) (*generated.SceneCopyResult, error) {
+	original, err := r.SceneRepo.FindByID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, fmt.Errorf("scene not found: %s", sceneID)
+	}
+	if err := r.findTargetProject(ctx, targetProjectID); err != nil {
+		return nil, err
+	}
+
+	fixtures, err := r.mapProjectFixtures(ctx, original.ProjectID, targetProjectID)
+	if err != nil {
+		return nil, err
+	}
+	name := original.Name
+	if newName != nil && *newName != "" {
+		name = *newName
+	}
+	newScene, warnings, err := r.copyScene(ctx, original, targetProjectID, name, fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	r.commitUndoCreate(ctx, targetProjectID, "Copy scene "+original.Name, undoScene(newScene.ID))
+
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &generated.SceneCopyResult{Scene: *newScene, Warnings: warnings}, nil
+}
+
 // DeleteScene is the resolver for the deleteScene field.
 func (r *mutationResolver) DeleteScene(ctx context.Context, id string) (bool, error) {
 	// Check if scene exists
@@ -1384,16 +1921,39 @@ func (r *mutationResolver) DeleteScene(ctx context.Context, id string) (bool, er
 		return false, fmt.Errorf("scene not found: %s", id)
 	}
 
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Delete scene "+scene.Name, undoScene(id))
+	if err != nil {
+		return false, err
+	}
+
 	// Delete fixture values first
 	if err := r.SceneRepo.DeleteFixtureValues(ctx, id); err != nil {
 		return false, err
 	}
+	if err := r.SceneRepo.DeleteGroupValues(ctx, id); err != nil {
+		return false, err
+	}
 
 	// Delete the scene
 	if err := r.SceneRepo.Delete(ctx, id); err != nil {
 		return false, err
 	}
 
+	// Effects attached to it become standalone
+	if err := r.db.WithContext(ctx).Model(&models.Effect{}).Where("scene_id = ?", id).Update("scene_id", nil).Error; err != nil {
+		return false, err
+	}
+	r.refreshSceneEffects(ctx)
+
+	// Submasters holding it keep their faders, without a scene
+	if err := r.db.WithContext(ctx).Model(&models.Submaster{}).Where("scene_id = ?", id).Update("scene_id", nil).Error; err != nil {
+		return false, err
+	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishEntityChange(ctx, generated.EntityChangeActionDeleted, scene)
+
 	return true, nil
 }
 
@@ -1429,14 +1989,26 @@ func (r *mutationResolver) BulkUpdateScenes(ctx context.Context, input generated
 			scene.Name = *item.Name.Value()
 		}
 
+		if item.SecondaryLabel.IsSet() {
+			scene.SecondaryLabel = item.SecondaryLabel.Value()
+		}
+
 		if item.Description.IsSet() {
 			scene.Description = item.Description.Value()
 		}
 
+		if err := setFadeTime(&scene.DefaultFadeIn, "defaultFadeIn", item.DefaultFadeIn); err != nil {
+			return nil, err
+		}
+		if err := setFadeTime(&scene.DefaultFadeOut, "defaultFadeOut", item.DefaultFadeOut); err != nil {
+			return nil, err
+		}
+
 		if err := r.SceneRepo.Update(ctx, scene); err != nil {
 			return nil, err
 		}
 
+		r.publishSceneUpdated(ctx, scene)
 		updatedScenes = append(updatedScenes, scene)
 	}
 
@@ -1471,11 +2043,25 @@ func (r *mutationResolver) AddFixturesToScene(ctx context.Context, sceneID strin
 		return nil, fmt.Errorf("scene not found: %s", sceneID)
 	}
 
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Add fixtures to scene "+scene.Name, undoScene(sceneID))
+	if err != nil {
+		return nil, err
+	}
+
 	overwrite := false
 	if overwriteExisting != nil {
 		overwrite = *overwriteExisting
 	}
 
+	if err := r.applyFixtureColors(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.applyFixturePalettes(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.checkFixtureValues(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
 	for _, fv := range fixtureValues {
 		channelsJSON, err := serializeSparseChannels(fv.Channels)
 		if err != nil {
@@ -1489,8 +2075,10 @@ func (r *mutationResolver) AddFixturesToScene(ctx context.Context, sceneID strin
 		}
 
 		if existing != nil {
-			if overwrite {
+			// A value following a group gives way to the scene's own
+			if overwrite || existing.GroupID != nil {
 				existing.Channels = channelsJSON
+				existing.PaletteIDs = fixturePaletteIDs(fv)
 				if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
 					existing.SceneOrder = fv.SceneOrder.Value()
 				}
@@ -1502,9 +2090,10 @@ func (r *mutationResolver) AddFixturesToScene(ctx context.Context, sceneID strin
 		} else {
 			// Create new fixture value
 			value := &models.FixtureValue{
-				SceneID:   sceneID,
-				FixtureID: fv.FixtureID,
-				Channels:  channelsJSON,
+				SceneID:    sceneID,
+				FixtureID:  fv.FixtureID,
+				Channels:   channelsJSON,
+				PaletteIDs: fixturePaletteIDs(fv),
 			}
 			if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
 				value.SceneOrder = fv.SceneOrder.Value()
@@ -1515,12 +2104,25 @@ func (r *mutationResolver) AddFixturesToScene(ctx context.Context, sceneID strin
 		}
 	}
 
+	if err := r.SceneRepo.SyncGroupValues(ctx, sceneID); err != nil {
+		return nil, err
+	}
+
+	// Move the scene's updatedAt, so edits made before this one conflict
+	if err := r.SceneRepo.Update(ctx, scene); err != nil {
+		return nil, err
+	}
+
 	// If this scene is currently active (displayed on DMX), re-apply its values
 	// so changes are immediately reflected in the output
 	if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
 		// Log the error but don't fail the update - the scene was saved successfully
-		log.Printf("Warning: failed to re-apply active scene after adding fixtures: %v", err)
+		log.Warn("failed to re-apply active scene after adding fixtures", "error", err)
 	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
 
 	return scene, nil
 }
@@ -1535,6 +2137,11 @@ func (r *mutationResolver) RemoveFixturesFromScene(ctx context.Context, sceneID
 		return nil, fmt.Errorf("scene not found: %s", sceneID)
 	}
 
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Remove fixtures from scene "+scene.Name, undoScene(sceneID))
+	if err != nil {
+		return nil, err
+	}
+
 	// Delete each fixture value
 	for _, fixtureID := range fixtureIds {
 		if err := r.SceneRepo.DeleteFixtureValue(ctx, sceneID, fixtureID); err != nil {
@@ -1542,18 +2149,32 @@ func (r *mutationResolver) RemoveFixturesFromScene(ctx context.Context, sceneID
 		}
 	}
 
+	// Removed fixtures in a group with a value in the scene follow it again
+	if err := r.SceneRepo.SyncGroupValues(ctx, sceneID); err != nil {
+		return nil, err
+	}
+
+	// Move the scene's updatedAt, so edits made before this one conflict
+	if err := r.SceneRepo.Update(ctx, scene); err != nil {
+		return nil, err
+	}
+
 	// If this scene is currently active (displayed on DMX), re-apply its values
 	// so changes are immediately reflected in the output
 	if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
 		// Log the error but don't fail the update - the scene was saved successfully
-		log.Printf("Warning: failed to re-apply active scene after removing fixtures: %v", err)
+		log.Warn("failed to re-apply active scene after removing fixtures", "error", err)
 	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
 
 	return scene, nil
 }
 
 // UpdateScenePartial is the resolver for the updateScenePartial field.
-func (r *mutationResolver) UpdateScenePartial(ctx context.Context, sceneID string, name *string, description *string, fixtureValues []*generated.FixtureValueInput, mergeFixtures *bool) (*models.Scene, error) {
+func (r *mutationResolver) UpdateScenePartial(ctx context.Context, sceneID string, name *string, description *string, fixtureValues []*generated.FixtureValueInput, mergeFixtures *bool, expectedUpdatedAt *string) (*models.Scene, error) {
 	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
 	if err != nil {
 		return nil, err
@@ -1561,6 +2182,14 @@ func (r *mutationResolver) UpdateScenePartial(ctx context.Context, sceneID strin
 	if scene == nil {
 		return nil, fmt.Errorf("scene not found: %s", sceneID)
 	}
+	if changedSince(scene.UpdatedAt, expectedUpdatedAt) {
+		return nil, r.sceneConflict(ctx, scene)
+	}
+
+	undo, err := r.beginUndo(ctx, scene.ProjectID, "Update scene "+scene.Name, undoScene(sceneID))
+	if err != nil {
+		return nil, err
+	}
 
 	// Update name if provided
 	if name != nil {
@@ -1572,75 +2201,102 @@ func (r *mutationResolver) UpdateScenePartial(ctx context.Context, sceneID strin
 		scene.Description = description
 	}
 
-	// Handle fixture values
-	if fixtureValues != nil {
-		merge := true
-		if mergeFixtures != nil {
-			merge = *mergeFixtures
-		}
+	err = r.writeUnchanged(ctx, generated.ProjectEntityTypeScene, sceneID, scene.UpdatedAt, expectedUpdatedAt, func(tx *Resolver) error {
+		// Handle fixture values
+		if fixtureValues != nil {
+			merge := true
+			if mergeFixtures != nil {
+				merge = *mergeFixtures
+			}
 
-		if !merge {
-			// Replace all fixture values
-			if err := r.SceneRepo.DeleteFixtureValues(ctx, sceneID); err != nil {
-				return nil, err
+			if err := tx.applyFixtureColors(ctx, fixtureValues); err != nil {
+				return err
+			}
+			if err := tx.applyFixturePalettes(ctx, fixtureValues); err != nil {
+				return err
+			}
+			if err := tx.checkFixtureValues(ctx, fixtureValues); err != nil {
+				return err
 			}
-		}
 
-		for _, fv := range fixtureValues {
-			channelsJSON, err := serializeSparseChannels(fv.Channels)
-			if err != nil {
-				return nil, err
+			if !merge {
+				// Replace all fixture values
+				if err := tx.SceneRepo.DeleteFixtureValues(ctx, sceneID); err != nil {
+					return err
+				}
 			}
 
-			if merge {
-				// Check if fixture already exists
-				existing, err := r.SceneRepo.GetFixtureValue(ctx, sceneID, fv.FixtureID)
+			for _, fv := range fixtureValues {
+				channelsJSON, err := serializeSparseChannels(fv.Channels)
 				if err != nil {
-					return nil, err
+					return err
 				}
 
-				if existing != nil {
-					// Update existing
-					existing.Channels = channelsJSON
-					if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
-						existing.SceneOrder = fv.SceneOrder.Value()
+				if merge {
+					// Check if fixture already exists
+					existing, err := tx.SceneRepo.GetFixtureValue(ctx, sceneID, fv.FixtureID)
+					if err != nil {
+						return err
 					}
-					if err := r.SceneRepo.UpdateFixtureValue(ctx, existing); err != nil {
-						return nil, err
+
+					if existing != nil {
+						// Merge by channel, so channels left out keep their values
+						merged, err := mergeSparseChannels(existing.Channels, sparseChannelValues(fv.Channels))
+						if err != nil {
+							return err
+						}
+						existing.Channels = merged
+						// A value following a group becomes the scene's own
+						existing.GroupID = nil
+						if fv.PaletteIds.IsSet() {
+							existing.PaletteIDs = fixturePaletteIDs(fv)
+						}
+						if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
+							existing.SceneOrder = fv.SceneOrder.Value()
+						}
+						if err := tx.SceneRepo.UpdateFixtureValue(ctx, existing); err != nil {
+							return err
+						}
+					} else {
+						// Create new
+						value := &models.FixtureValue{
+							SceneID:    sceneID,
+							FixtureID:  fv.FixtureID,
+							Channels:   channelsJSON,
+							PaletteIDs: fixturePaletteIDs(fv),
+						}
+						if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
+							value.SceneOrder = fv.SceneOrder.Value()
+						}
+						if err := tx.SceneRepo.CreateFixtureValue(ctx, value); err != nil {
+							return err
+						}
 					}
 				} else {
-					// Create new
+					// Create new fixture values
 					value := &models.FixtureValue{
-						SceneID:   sceneID,
-						FixtureID: fv.FixtureID,
-						Channels:  channelsJSON,
+						SceneID:    sceneID,
+						FixtureID:  fv.FixtureID,
+						Channels:   channelsJSON,
+						PaletteIDs: fixturePaletteIDs(fv),
 					}
 					if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
 						value.SceneOrder = fv.SceneOrder.Value()
 					}
-					if err := r.SceneRepo.CreateFixtureValue(ctx, value); err != nil {
-						return nil, err
+					if err := tx.SceneRepo.CreateFixtureValue(ctx, value); err != nil {
+						return err
 					}
 				}
-			} else {
-				// Create new fixture values
-				value := &models.FixtureValue{
-					SceneID:   sceneID,
-					FixtureID: fv.FixtureID,
-					Channels:  channelsJSON,
-				}
-				if fv.SceneOrder.IsSet() && fv.SceneOrder.Value() != nil {
-					value.SceneOrder = fv.SceneOrder.Value()
-				}
-				if err := r.SceneRepo.CreateFixtureValue(ctx, value); err != nil {
-					return nil, err
-				}
+			}
+
+			if err := tx.SceneRepo.SyncGroupValues(ctx, sceneID); err != nil {
+				return err
 			}
 		}
-	}
 
-	// Save the scene
-	if err := r.SceneRepo.Update(ctx, scene); err != nil {
+		return tx.SceneRepo.Update(ctx, scene)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -1648,68 +2304,265 @@ func (r *mutationResolver) UpdateScenePartial(ctx context.Context, sceneID strin
 	// so changes are immediately reflected in the output
 	if err := r.reapplyActiveSceneIfNeeded(ctx, sceneID); err != nil {
 		// Log the error but don't fail the update - the scene was saved successfully
-		log.Printf("Warning: failed to re-apply active scene after update: %v", err)
+		log.Warn("failed to re-apply active scene after update", "error", err)
 	}
+	r.refreshSubmasters(ctx)
+
+	r.commitUndo(ctx, undo)
+	r.publishSceneUpdated(ctx, scene)
 
 	return scene, nil
 }
 
-// CreateSceneBoard is the resolver for the createSceneBoard field.
-func (r *mutationResolver) CreateSceneBoard(ctx context.Context, input generated.CreateSceneBoardInput) (*models.SceneBoard, error) {
-	// Verify project exists
-	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+// SetSceneFixtureValues is the resolver for the setSceneFixtureValues field.
+func (r *mutationResolver) SetSceneFixtureValues(ctx context.Context, sceneID string, valueSets []*generated.SceneValueSetInput) (*models.Scene, error) {
+	return r.setSceneFixtureValues(ctx, sceneID, valueSets)
+}
+
+// CaptureActiveOutput is the resolver for the captureActiveOutput field.
+func (r *mutationResolver) CaptureActiveOutput(ctx context.Context, projectID string, sceneID *string, name *string, sessionID *string, fixtureIds []string) (*models.Scene, error) {
+	return r.captureActiveOutput(ctx, projectID, sceneID, name, sessionID, fixtureIds)
+}
+
+// ReplaceChannelValue is the resolver for the replaceChannelValue field.
+func (r *mutationResolver) ReplaceChannelValue(ctx context.Context, projectID string, filter *generated.ChannelValueReplaceFilterInput, fromValue int, toValue int, dryRun *bool) (*generated.ReplaceChannelValueResult, error) {
+	if fromValue < 0 || fromValue > 255 || toValue < 0 || toValue > 255 {
+		return nil, fmt.Errorf("channel values must be between 0 and 255")
+	}
+
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 	if project == nil {
-		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
-	}
-
-	board := &models.SceneBoard{
-		ID:              cuid.New(),
-		Name:            input.Name,
-		ProjectID:       input.ProjectID,
-		DefaultFadeTime: 3.0,
-		GridSize:        intPtr(50),
-		CanvasWidth:     2000,
-		CanvasHeight:    2000,
+		return nil, fmt.Errorf("project not found: %s", projectID)
 	}
 
-	if input.Description.IsSet() {
-		board.Description = input.Description.Value()
+	isDryRun := dryRun != nil && *dryRun
+	result := &generated.ReplaceChannelValueResult{
+		DryRun: isDryRun,
+		Scenes: []*generated.SceneChannelValueChanges{},
 	}
-
-	if input.DefaultFadeTime.IsSet() && input.DefaultFadeTime.Value() != nil {
-		board.DefaultFadeTime = *input.DefaultFadeTime.Value()
+	if fromValue == toValue {
+		return result, nil
 	}
 
-	if input.GridSize.IsSet() && input.GridSize.Value() != nil {
-		board.GridSize = input.GridSize.Value()
+	// Build filter sets
+	var sceneFilter, fixtureFilter map[string]bool
+	var channelType, channelName string
+	if filter != nil {
+		if filter.SceneIds.IsSet() && filter.SceneIds.Value() != nil {
+			sceneFilter = make(map[string]bool)
+			for _, id := range filter.SceneIds.Value() {
+				sceneFilter[id] = true
+			}
+		}
+		if filter.FixtureIds.IsSet() && filter.FixtureIds.Value() != nil {
+			fixtureFilter = make(map[string]bool)
+			for _, id := range filter.FixtureIds.Value() {
+				fixtureFilter[id] = true
+			}
+		}
+		if filter.ChannelType.IsSet() && filter.ChannelType.Value() != nil {
+			channelType = string(*filter.ChannelType.Value())
+		}
+		if filter.ChannelName.IsSet() && filter.ChannelName.Value() != nil {
+			channelName = *filter.ChannelName.Value()
+		}
 	}
 
-	if input.CanvasWidth.IsSet() && input.CanvasWidth.Value() != nil {
-		board.CanvasWidth = *input.CanvasWidth.Value()
+	// Channel type/name filters need each fixture's instance channels by offset
+	instanceChannels := make(map[string]map[int]models.InstanceChannel)
+	if channelType != "" || channelName != "" {
+		fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		var fixtureIDs []string
+		for _, f := range fixtures {
+			fixtureIDs = append(fixtureIDs, f.ID)
+		}
+		var channels []models.InstanceChannel
+		if len(fixtureIDs) > 0 {
+			if err := r.db.WithContext(ctx).Where("fixture_id IN ?", fixtureIDs).Find(&channels).Error; err != nil {
+				return nil, err
+			}
+		}
+		for _, ch := range channels {
+			if instanceChannels[ch.FixtureID] == nil {
+				instanceChannels[ch.FixtureID] = make(map[int]models.InstanceChannel)
+			}
+			instanceChannels[ch.FixtureID][ch.Offset] = ch
+		}
 	}
 
-	if input.CanvasHeight.IsSet() && input.CanvasHeight.Value() != nil {
-		board.CanvasHeight = *input.CanvasHeight.Value()
+	channelMatches := func(fixtureID string, offset int) bool {
+		if channelType == "" && channelName == "" {
+			return true
+		}
+		ch, ok := instanceChannels[fixtureID][offset]
+		if !ok {
+			return false
+		}
+		if channelType != "" && ch.Type != channelType {
+			return false
+		}
+		if channelName != "" && !strings.EqualFold(ch.Name, channelName) {
+			return false
+		}
+		return true
 	}
 
-	result := r.db.WithContext(ctx).Create(board)
-	if result.Error != nil {
-		return nil, result.Error
+	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
 
-	return board, nil
-}
+	for _, scene := range scenes {
+		if sceneFilter != nil && !sceneFilter[scene.ID] {
+			continue
+		}
 
-// UpdateSceneBoard is the resolver for the updateSceneBoard field.
-func (r *mutationResolver) UpdateSceneBoard(ctx context.Context, id string, input generated.UpdateSceneBoardInput) (*models.SceneBoard, error) {
-	var board models.SceneBoard
-	result := r.db.WithContext(ctx).First(&board, "id = ?", id)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+		fixtureValues, err := r.SceneRepo.GetFixtureValues(ctx, scene.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		changeCount := 0
+		var changedValues []models.FixtureValue
+		for _, fv := range fixtureValues {
+			if fixtureFilter != nil && !fixtureFilter[fv.FixtureID] {
+				continue
+			}
+
+			var channels []models.ChannelValue
+			if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
+				log.Warn("failed to unmarshal fixture channels", "fixture", fv.FixtureID, "scene", scene.ID, "error", err)
+				continue
+			}
+
+			changed := 0
+			for i := range channels {
+				if channels[i].Value == fromValue && channelMatches(fv.FixtureID, channels[i].Offset) {
+					channels[i].Value = toValue
+					changed++
+				}
+			}
+			if changed == 0 {
+				continue
+			}
+
+			channelsJSON, err := json.Marshal(channels)
+			if err != nil {
+				return nil, err
+			}
+			fv.Channels = string(channelsJSON)
+			changedValues = append(changedValues, fv)
+			changeCount += changed
+		}
+
+		if changeCount == 0 {
+			continue
+		}
+
+		if !isDryRun {
+			if err := r.SceneRepo.UpdateFixtureValues(ctx, changedValues); err != nil {
+				return nil, err
+			}
+			if err := r.reapplyActiveSceneIfNeeded(ctx, scene.ID); err != nil {
+				// Log the error but don't fail the replace - the scene was saved successfully
+				log.Warn("failed to re-apply active scene after replacing channel values", "error", err)
+			}
+			r.publishEntityUpdated(ctx, generated.ProjectEntityTypeScene, scene.ID)
+			r.refreshSubmasters(ctx)
+		}
+
+		result.TotalChanges += changeCount
+		result.Scenes = append(result.Scenes, &generated.SceneChannelValueChanges{
+			SceneID:     scene.ID,
+			SceneName:   scene.Name,
+			ChangeCount: changeCount,
+		})
+	}
+
+	return result, nil
+}
+
+// CreateSceneBoard is the resolver for the createSceneBoard field.
+func (r *mutationResolver) CreateSceneBoard(ctx context.Context, input generated.CreateSceneBoardInput) (*models.SceneBoard, error) {
+	// Verify project exists
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	board := &models.SceneBoard{
+		ID:              cuid.New(),
+		Name:            input.Name,
+		ProjectID:       input.ProjectID,
+		DefaultFadeTime: 3.0,
+		GridSize:        intPtr(50),
+		CanvasWidth:     2000,
+		CanvasHeight:    2000,
+		HoldRampTime:    3.0,
+		HoldCurve:       string(generated.EasingTypeLinear),
+		HoldReleaseMode: string(generated.HoldReleaseModeLatch),
+	}
+
+	if input.Description.IsSet() {
+		board.Description = input.Description.Value()
+	}
+
+	if input.DefaultFadeTime.IsSet() && input.DefaultFadeTime.Value() != nil {
+		board.DefaultFadeTime = *input.DefaultFadeTime.Value()
+	}
+
+	if input.GridSize.IsSet() && input.GridSize.Value() != nil {
+		board.GridSize = input.GridSize.Value()
+	}
+
+	if input.CanvasWidth.IsSet() && input.CanvasWidth.Value() != nil {
+		board.CanvasWidth = *input.CanvasWidth.Value()
+	}
+
+	if input.CanvasHeight.IsSet() && input.CanvasHeight.Value() != nil {
+		board.CanvasHeight = *input.CanvasHeight.Value()
+	}
+
+	if input.HoldRampTime.IsSet() && input.HoldRampTime.Value() != nil {
+		if *input.HoldRampTime.Value() < 0 {
+			return nil, fmt.Errorf("holdRampTime must not be negative")
+		}
+		board.HoldRampTime = *input.HoldRampTime.Value()
+	}
+
+	if input.HoldCurve.IsSet() && input.HoldCurve.Value() != nil {
+		board.HoldCurve = string(*input.HoldCurve.Value())
+	}
+
+	if input.HoldReleaseMode.IsSet() && input.HoldReleaseMode.Value() != nil {
+		board.HoldReleaseMode = string(*input.HoldReleaseMode.Value())
+	}
+
+	result := r.db.WithContext(ctx).Create(board)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, board)
+
+	return board, nil
+}
+
+// UpdateSceneBoard is the resolver for the updateSceneBoard field.
+func (r *mutationResolver) UpdateSceneBoard(ctx context.Context, id string, input generated.UpdateSceneBoardInput) (*models.SceneBoard, error) {
+	var board models.SceneBoard
+	result := r.db.WithContext(ctx).First(&board, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
 
 	if input.Name.IsSet() && input.Name.Value() != nil {
 		board.Name = *input.Name.Value()
@@ -1735,16 +2588,37 @@ func (r *mutationResolver) UpdateSceneBoard(ctx context.Context, id string, inpu
 		board.CanvasHeight = *input.CanvasHeight.Value()
 	}
 
+	if input.HoldRampTime.IsSet() && input.HoldRampTime.Value() != nil {
+		if *input.HoldRampTime.Value() < 0 {
+			return nil, fmt.Errorf("holdRampTime must not be negative")
+		}
+		board.HoldRampTime = *input.HoldRampTime.Value()
+	}
+
+	if input.HoldCurve.IsSet() && input.HoldCurve.Value() != nil {
+		board.HoldCurve = string(*input.HoldCurve.Value())
+	}
+
+	if input.HoldReleaseMode.IsSet() && input.HoldReleaseMode.Value() != nil {
+		board.HoldReleaseMode = string(*input.HoldReleaseMode.Value())
+	}
+
 	result = r.db.WithContext(ctx).Save(&board)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, &board)
 
 	return &board, nil
 }
 
 // DeleteSceneBoard is the resolver for the deleteSceneBoard field.
 func (r *mutationResolver) DeleteSceneBoard(ctx context.Context, id string) (bool, error) {
+	board, err := r.SceneBoardRepo.FindByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
 	// Delete all buttons first
 	result := r.db.WithContext(ctx).Where("scene_board_id = ?", id).Delete(&models.SceneBoardButton{})
 	if result.Error != nil {
@@ -1756,6 +2630,9 @@ func (r *mutationResolver) DeleteSceneBoard(ctx context.Context, id string) (boo
 	if result.Error != nil {
 		return false, result.Error
 	}
+	if board != nil {
+		r.publishEntityChange(ctx, generated.EntityChangeActionDeleted, board)
+	}
 
 	return true, nil
 }
@@ -1814,6 +2691,7 @@ func (r *mutationResolver) BulkUpdateSceneBoards(ctx context.Context, input gene
 		if result.Error != nil {
 			return nil, result.Error
 		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, &board)
 
 		updatedBoards = append(updatedBoards, &board)
 	}
@@ -1883,10 +2761,30 @@ func (r *mutationResolver) AddSceneToBoard(ctx context.Context, input generated.
 		button.Label = input.Label.Value()
 	}
 
+	if err := setFadeTime(&button.FadeInTime, "fadeInTime", input.FadeInTime); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&button.FadeOutTime, "fadeOutTime", input.FadeOutTime); err != nil {
+		return nil, err
+	}
+	if input.FlashMode.IsSet() && input.FlashMode.Value() != nil {
+		button.FlashMode = *input.FlashMode.Value()
+	}
+	if err := setFlashLevel(&button.FlashLevel, input.FlashLevel); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&button.FlashReleaseTime, "flashReleaseTime", input.FlashReleaseTime); err != nil {
+		return nil, err
+	}
+	if err := r.setAudioTrigger(button, input.AudioTrigger, input.AudioBand, input.AudioAction); err != nil {
+		return nil, err
+	}
+
 	result = r.db.WithContext(ctx).Create(button)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, &board)
 
 	return button, nil
 }
@@ -1923,16 +2821,40 @@ func (r *mutationResolver) UpdateSceneBoardButton(ctx context.Context, id string
 		button.Label = input.Label.Value()
 	}
 
+	if err := setFadeTime(&button.FadeInTime, "fadeInTime", input.FadeInTime); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&button.FadeOutTime, "fadeOutTime", input.FadeOutTime); err != nil {
+		return nil, err
+	}
+	if input.FlashMode.IsSet() && input.FlashMode.Value() != nil {
+		button.FlashMode = *input.FlashMode.Value()
+	}
+	if err := setFlashLevel(&button.FlashLevel, input.FlashLevel); err != nil {
+		return nil, err
+	}
+	if err := setFadeTime(&button.FlashReleaseTime, "flashReleaseTime", input.FlashReleaseTime); err != nil {
+		return nil, err
+	}
+	if err := r.setAudioTrigger(&button, input.AudioTrigger, input.AudioBand, input.AudioAction); err != nil {
+		return nil, err
+	}
+
 	result = r.db.WithContext(ctx).Save(&button)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	r.publishEntityUpdated(ctx, generated.ProjectEntityTypeSceneBoard, button.SceneBoardID)
 
 	return &button, nil
 }
 
 // RemoveSceneFromBoard is the resolver for the removeSceneFromBoard field.
 func (r *mutationResolver) RemoveSceneFromBoard(ctx context.Context, buttonID string) (bool, error) {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).Where("id = ?", buttonID).Limit(1).Find(&button).Error; err != nil {
+		return false, err
+	}
 	result := r.db.WithContext(ctx).Delete(&models.SceneBoardButton{}, "id = ?", buttonID)
 	if result.Error != nil {
 		return false, result.Error
@@ -1940,6 +2862,10 @@ func (r *mutationResolver) RemoveSceneFromBoard(ctx context.Context, buttonID st
 	if result.RowsAffected == 0 {
 		return false, fmt.Errorf("button not found: %s", buttonID)
 	}
+	r.publishEntityUpdated(ctx, generated.ProjectEntityTypeSceneBoard, button.SceneBoardID)
+	r.HoldService.Clear(buttonID)
+	r.FlashService.Clear(buttonID)
+	r.MacroService.Stop(buttonID)
 	return true, nil
 }
 
@@ -1959,6 +2885,7 @@ func (r *mutationResolver) UpdateSceneBoardButtonPositions(ctx context.Context,
 		if result.Error != nil {
 			return false, result.Error
 		}
+		r.publishEntityUpdated(ctx, generated.ProjectEntityTypeSceneBoard, button.SceneBoardID)
 	}
 	return true, nil
 }
@@ -2013,10 +2940,30 @@ func (r *mutationResolver) BulkUpdateSceneBoardButtons(ctx context.Context, inpu
 			button.Label = item.Label.Value()
 		}
 
+		if err := setFadeTime(&button.FadeInTime, "fadeInTime", item.FadeInTime); err != nil {
+			return nil, err
+		}
+		if err := setFadeTime(&button.FadeOutTime, "fadeOutTime", item.FadeOutTime); err != nil {
+			return nil, err
+		}
+		if item.FlashMode.IsSet() && item.FlashMode.Value() != nil {
+			button.FlashMode = *item.FlashMode.Value()
+		}
+		if err := setFlashLevel(&button.FlashLevel, item.FlashLevel); err != nil {
+			return nil, err
+		}
+		if err := setFadeTime(&button.FlashReleaseTime, "flashReleaseTime", item.FlashReleaseTime); err != nil {
+			return nil, err
+		}
+		if err := r.setAudioTrigger(&button, item.AudioTrigger, item.AudioBand, item.AudioAction); err != nil {
+			return nil, err
+		}
+
 		result = r.db.WithContext(ctx).Save(&button)
 		if result.Error != nil {
 			return nil, result.Error
 		}
+		r.publishEntityUpdated(ctx, generated.ProjectEntityTypeSceneBoard, button.SceneBoardID)
 
 		updatedButtons = append(updatedButtons, &button)
 	}
@@ -2042,2638 +2989,5786 @@ func (r *mutationResolver) BulkDeleteSceneBoardButtons(ctx context.Context, butt
 	}, nil
 }
 
-// ActivateSceneFromBoard is the resolver for the activateSceneFromBoard field.
-func (r *mutationResolver) ActivateSceneFromBoard(ctx context.Context, sceneBoardID string, sceneID string, fadeTimeOverride *float64) (bool, error) {
-	// Verify scene board exists
-	var board models.SceneBoard
-	result := r.db.WithContext(ctx).First(&board, "id = ?", sceneBoardID)
-	if result.Error != nil {
-		return false, fmt.Errorf("scene board not found: %w", result.Error)
-	}
+// SetSceneBoardButtonMacro is the resolver for the setSceneBoardButtonMacro field.
+func (r *mutationResolver) SetSceneBoardButtonMacro(ctx context.Context, buttonID string, actions []*generated.MacroActionInput) (*models.SceneBoardButton, error) {
+	return r.setSceneBoardButtonMacro(ctx, buttonID, actions)
+}
 
-	// Verify scene exists
-	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
+// CreateEffect is the resolver for the createEffect field.
+func (r *mutationResolver) CreateEffect(ctx context.Context, input generated.CreateEffectInput) (*models.Effect, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if scene == nil {
-		return false, fmt.Errorf("scene not found: %s", sceneID)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
 	}
 
-	// Use fade time override if provided, otherwise use board default
-	fadeTime := board.DefaultFadeTime
-	if fadeTimeOverride != nil {
-		fadeTime = *fadeTimeOverride
+	if input.FixtureIds.Value() == nil && input.SelectionSetID.Value() == nil {
+		return nil, errors.New("fixtureIds or selectionSetId is required")
 	}
-
-	// Load scene with fixture values
-	var fullScene models.Scene
-	result = r.db.WithContext(ctx).Preload("FixtureValues").First(&fullScene, "id = ?", sceneID)
-	if result.Error != nil {
-		return false, result.Error
+	fixtureIDs, err := r.selectFixtures(ctx, input.ProjectID, input.FixtureIds.Value(), input.SelectionSetID.Value(), input.SelectionOrder.Value(), nil)
+	if err != nil {
+		return nil, err
 	}
-
-	// Load fixtures for the scene's fixture values
-	var fixtureIDs []string
-	for _, fv := range fullScene.FixtureValues {
-		fixtureIDs = append(fixtureIDs, fv.FixtureID)
+	fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	var fixtures []models.FixtureInstance
-	if len(fixtureIDs) > 0 {
-		// Load fixtures with their channels to get fadeBehavior
-		r.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures)
+	params := effects.DefaultParams()
+	if input.Rate.Value() != nil {
+		params.Rate = *input.Rate.Value()
 	}
-
-	// Create fixture lookup map
-	fixtureMap := make(map[string]*models.FixtureInstance)
-	for i := range fixtures {
-		fixtureMap[fixtures[i].ID] = &fixtures[i]
+	if input.Size.Value() != nil {
+		params.Size = *input.Size.Value()
 	}
-
-	// Build scene channels for fade
-	var sceneChannels []fade.SceneChannel
-	for _, fixtureValue := range fullScene.FixtureValues {
-		fixture := fixtureMap[fixtureValue.FixtureID]
-		if fixture == nil {
-			continue
-		}
-
-		// Parse sparse channel values from JSON (Channels field)
-		var channels []models.ChannelValue
-		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
-			log.Printf("Warning: failed to unmarshal channels for fixtureID %s in sceneID %s: %v", fixtureValue.FixtureID, sceneID, err)
-			continue
-		}
-
-		// Build a map of channel offset -> fade behavior for efficient lookup
-		fadeBehaviorMap := make(map[int]string)
-		for _, chanDef := range fixture.Channels {
-			if chanDef.FadeBehavior != "" {
-				fadeBehaviorMap[chanDef.Offset] = chanDef.FadeBehavior
-			}
-		}
-
-		// Build channel targets with fade behavior from channel definitions
-		// Only process channels that exist in the sparse array
-		for _, ch := range channels {
-			dmxChannel := fixture.StartChannel + ch.Offset
-
-			// Validate DMX channel is within bounds
-			if !validateDMXChannel(dmxChannel, fixture.Universe, fixture.ID, ch.Offset) {
-				continue
-			}
-
-			// Get fade behavior from channel definition (if available)
-			fadeBehavior := fade.FadeBehaviorFade // Default to FADE
-			if fb, ok := fadeBehaviorMap[ch.Offset]; ok {
-				fadeBehavior = fb
-			}
-
-			sceneChannels = append(sceneChannels, fade.SceneChannel{
-				Universe:     fixture.Universe,
-				Channel:      dmxChannel,
-				Value:        ch.Value,
-				FadeBehavior: fadeBehavior,
-			})
-		}
+	if input.PhaseOffset.Value() != nil {
+		params.PhaseOffset = *input.PhaseOffset.Value()
+	}
+	if input.Order.Value() != nil {
+		params.Order = effects.Order(*input.Order.Value())
+	}
+	if input.Low.Value() != nil {
+		params.Low = *input.Low.Value()
+	}
+	if input.High.Value() != nil {
+		params.High = *input.High.Value()
 	}
 
-	// Execute fade
-	fadeID := fmt.Sprintf("scene-board-%s", sceneID)
-	fadeDuration := time.Duration(fadeTime * float64(time.Second))
-	r.FadeEngine.FadeToScene(sceneChannels, fadeDuration, fadeID, fade.EasingInOutSine)
-
-	// Track the active scene
-	r.DMXService.SetActiveScene(sceneID)
-
-	return true, nil
+	effect := &models.Effect{
+		ID:          cuid.New(),
+		Name:        input.Name,
+		ProjectID:   input.ProjectID,
+		SceneID:     input.SceneID.Value(),
+		Type:        string(input.Type),
+		FixtureIDs:  string(fixtureIDsJSON),
+		Rate:        params.Rate,
+		Size:        params.Size,
+		PhaseOffset: params.PhaseOffset,
+		Order:       string(params.Order),
+		Low:         params.Low,
+		High:        params.High,
+	}
+	if err := r.saveEffect(ctx, effect, true); err != nil {
+		return nil, err
+	}
+	return effect, nil
 }
 
-// CreateCueList is the resolver for the createCueList field.
-func (r *mutationResolver) CreateCueList(ctx context.Context, input generated.CreateCueListInput) (*models.CueList, error) {
-	cueList := &models.CueList{
-		Name:      input.Name,
-		ProjectID: input.ProjectID,
+// UpdateEffect is the resolver for the updateEffect field.
+func (r *mutationResolver) UpdateEffect(ctx context.Context, id string, input generated.UpdateEffectInput) (*models.Effect, error) {
+	effect, err := r.findEffect(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if input.Description.IsSet() {
-		cueList.Description = input.Description.Value()
+	if input.Name.IsSet() && input.Name.Value() != nil {
+		effect.Name = *input.Name.Value()
 	}
-
-	if input.Loop.IsSet() && input.Loop.Value() != nil {
-		cueList.Loop = *input.Loop.Value()
+	if input.Type.IsSet() && input.Type.Value() != nil {
+		effect.Type = string(*input.Type.Value())
 	}
-
-	if err := r.CueListRepo.Create(ctx, cueList); err != nil {
+	current, err := effectFixtureIDs(effect)
+	if err != nil {
 		return nil, err
 	}
-
-	return cueList, nil
-}
-
-// UpdateCueList is the resolver for the updateCueList field.
-func (r *mutationResolver) UpdateCueList(ctx context.Context, id string, input generated.CreateCueListInput) (*models.CueList, error) {
-	cueList, err := r.CueListRepo.FindByID(ctx, id)
+	fixtureIDs, err := r.selectFixtures(ctx, effect.ProjectID, input.FixtureIds.Value(), input.SelectionSetID.Value(), input.SelectionOrder.Value(), current)
 	if err != nil {
 		return nil, err
 	}
-	if cueList == nil {
-		return nil, fmt.Errorf("cue list not found: %s", id)
+	if fixtureIDs != nil {
+		fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+		if err != nil {
+			return nil, err
+		}
+		effect.FixtureIDs = string(fixtureIDsJSON)
 	}
-
-	// Update fields
-	cueList.Name = input.Name
-
-	if input.Description.IsSet() {
-		cueList.Description = input.Description.Value()
+	if input.SceneID.IsSet() {
+		effect.SceneID = input.SceneID.Value()
 	}
-
-	if input.Loop.IsSet() && input.Loop.Value() != nil {
-		cueList.Loop = *input.Loop.Value()
+	if input.Rate.IsSet() && input.Rate.Value() != nil {
+		effect.Rate = *input.Rate.Value()
+	}
+	if input.Size.IsSet() && input.Size.Value() != nil {
+		effect.Size = *input.Size.Value()
+	}
+	if input.PhaseOffset.IsSet() && input.PhaseOffset.Value() != nil {
+		effect.PhaseOffset = *input.PhaseOffset.Value()
+	}
+	if input.Order.IsSet() && input.Order.Value() != nil {
+		effect.Order = string(*input.Order.Value())
+	}
+	if input.Low.IsSet() && input.Low.Value() != nil {
+		effect.Low = *input.Low.Value()
+	}
+	if input.High.IsSet() && input.High.Value() != nil {
+		effect.High = *input.High.Value()
 	}
 
-	if err := r.CueListRepo.Update(ctx, cueList); err != nil {
+	if err := r.saveEffect(ctx, effect, false); err != nil {
 		return nil, err
 	}
-
-	return cueList, nil
+	return effect, nil
 }
 
-// DeleteCueList is the resolver for the deleteCueList field.
-func (r *mutationResolver) DeleteCueList(ctx context.Context, id string) (bool, error) {
-	cueList, err := r.CueListRepo.FindByID(ctx, id)
-	if err != nil {
+// DeleteEffect is the resolver for the deleteEffect field.
+func (r *mutationResolver) DeleteEffect(ctx context.Context, id string) (bool, error) {
+	if _, err := r.findEffect(ctx, id); err != nil {
 		return false, err
 	}
-	if cueList == nil {
-		return false, fmt.Errorf("cue list not found: %s", id)
-	}
-
-	// Delete all cues first
-	if err := r.CueRepo.DeleteByCueListID(ctx, id); err != nil {
-		return false, err
-	}
-
-	// Delete the cue list
-	if err := r.CueListRepo.Delete(ctx, id); err != nil {
+	if err := r.deleteEffects(ctx, "id = ?", id); err != nil {
 		return false, err
 	}
-
 	return true, nil
 }
 
-// BulkCreateCueLists is the resolver for the bulkCreateCueLists field.
-func (r *mutationResolver) BulkCreateCueLists(ctx context.Context, input generated.BulkCueListCreateInput) ([]*models.CueList, error) {
-	var createdCueLists []*models.CueList
-
-	for _, cueListInput := range input.CueLists {
-		cueList, err := r.CreateCueList(ctx, *cueListInput)
-		if err != nil {
-			return nil, err
-		}
-		createdCueLists = append(createdCueLists, cueList)
+// StartEffect is the resolver for the startEffect field.
+func (r *mutationResolver) StartEffect(ctx context.Context, id string) (*models.Effect, error) {
+	effect, err := r.findEffect(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-
-	return createdCueLists, nil
-}
-
-// BulkUpdateCueLists is the resolver for the bulkUpdateCueLists field.
-func (r *mutationResolver) BulkUpdateCueLists(ctx context.Context, input generated.BulkCueListUpdateInput) ([]*models.CueList, error) {
-	var updatedCueLists []*models.CueList
-
-	for _, item := range input.CueLists {
-		cueList, err := r.CueListRepo.FindByID(ctx, item.CueListID)
-		if err != nil {
-			return nil, err
-		}
-		if cueList == nil {
-			return nil, fmt.Errorf("cue list not found: %s", item.CueListID)
-		}
-
-		if item.Name.IsSet() && item.Name.Value() != nil {
-			cueList.Name = *item.Name.Value()
-		}
-
-		if item.Description.IsSet() {
-			cueList.Description = item.Description.Value()
-		}
-
-		if item.Loop.IsSet() && item.Loop.Value() != nil {
-			cueList.Loop = *item.Loop.Value()
-		}
-
-		if err := r.CueListRepo.Update(ctx, cueList); err != nil {
-			return nil, err
-		}
-
-		updatedCueLists = append(updatedCueLists, cueList)
+	built, err := r.buildEffect(ctx, effect)
+	if err != nil {
+		return nil, err
 	}
-
-	return updatedCueLists, nil
-}
-
-// BulkDeleteCueLists is the resolver for the bulkDeleteCueLists field.
-func (r *mutationResolver) BulkDeleteCueLists(ctx context.Context, cueListIds []string) (*generated.BulkDeleteResult, error) {
-	var deletedIds []string
-
-	for _, cueListID := range cueListIds {
-		_, err := r.DeleteCueList(ctx, cueListID)
-		if err != nil {
-			return nil, err
-		}
-		deletedIds = append(deletedIds, cueListID)
+	if err := r.EffectService.Start(built); err != nil {
+		return nil, err
 	}
-
-	return &generated.BulkDeleteResult{
-		DeletedCount: len(deletedIds),
-		DeletedIds:   deletedIds,
-	}, nil
+	return effect, nil
 }
 
-// CreateCue is the resolver for the createCue field.
-func (r *mutationResolver) CreateCue(ctx context.Context, input generated.CreateCueInput) (*models.Cue, error) {
-	// Verify cue list exists
-	cueList, err := r.CueListRepo.FindByID(ctx, input.CueListID)
+// StopEffect is the resolver for the stopEffect field.
+func (r *mutationResolver) StopEffect(ctx context.Context, id string) (*models.Effect, error) {
+	effect, err := r.findEffect(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if cueList == nil {
-		return nil, fmt.Errorf("cue list not found: %s", input.CueListID)
-	}
+	r.EffectService.Stop(id)
+	return effect, nil
+}
 
-	// Verify scene exists
-	scene, err := r.SceneRepo.FindByID(ctx, input.SceneID)
+// StopAllEffects is the resolver for the stopAllEffects field.
+func (r *mutationResolver) StopAllEffects(ctx context.Context) (bool, error) {
+	r.EffectService.StopAll()
+	return true, nil
+}
+
+// CreateSubmaster is the resolver for the createSubmaster field.
+func (r *mutationResolver) CreateSubmaster(ctx context.Context, input generated.CreateSubmasterInput) (*models.Submaster, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
 	if err != nil {
 		return nil, err
 	}
-	if scene == nil {
-		return nil, fmt.Errorf("scene not found: %s", input.SceneID)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
 	}
 
-	cue := &models.Cue{
-		Name:        input.Name,
-		CueNumber:   input.CueNumber,
-		CueListID:   input.CueListID,
-		SceneID:     input.SceneID,
-		FadeInTime:  input.FadeInTime,
-		FadeOutTime: input.FadeOutTime,
+	fixtureIDs := input.FixtureIds.Value()
+	if fixtureIDs == nil {
+		fixtureIDs = []string{}
 	}
-
-	if input.FollowTime.IsSet() {
-		cue.FollowTime = input.FollowTime.Value()
+	fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	if input.EasingType.IsSet() && input.EasingType.Value() != nil {
-		easingStr := string(*input.EasingType.Value())
-		cue.EasingType = &easingStr
+	sub := &models.Submaster{
+		ID:         cuid.New(),
+		Name:       input.Name,
+		ProjectID:  input.ProjectID,
+		Page:       1,
+		Slot:       input.Slot,
+		SceneID:    input.SceneID.Value(),
+		FixtureIDs: string(fixtureIDsJSON),
 	}
-
-	if input.Notes.IsSet() {
-		cue.Notes = input.Notes.Value()
+	if input.Page.IsSet() && input.Page.Value() != nil {
+		sub.Page = *input.Page.Value()
 	}
-
-	if err := r.CueRepo.Create(ctx, cue); err != nil {
+	if err := r.saveSubmaster(ctx, sub, true); err != nil {
 		return nil, err
 	}
-
-	return cue, nil
+	return sub, nil
 }
 
-// UpdateCue is the resolver for the updateCue field.
-func (r *mutationResolver) UpdateCue(ctx context.Context, id string, input generated.CreateCueInput) (*models.Cue, error) {
-	cue, err := r.CueRepo.FindByID(ctx, id)
+// UpdateSubmaster is the resolver for the updateSubmaster field.
+func (r *mutationResolver) UpdateSubmaster(ctx context.Context, id string, input generated.UpdateSubmasterInput) (*models.Submaster, error) {
+	sub, err := r.findSubmaster(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if cue == nil {
-		return nil, fmt.Errorf("cue not found: %s", id)
-	}
+	previousDefault := defaultSubmasterLevel(sub)
 
-	// Verify scene exists if being changed
-	if input.SceneID != cue.SceneID {
-		scene, err := r.SceneRepo.FindByID(ctx, input.SceneID)
+	if input.Name.IsSet() && input.Name.Value() != nil {
+		sub.Name = *input.Name.Value()
+	}
+	if input.Page.IsSet() && input.Page.Value() != nil {
+		sub.Page = *input.Page.Value()
+	}
+	if input.Slot.IsSet() && input.Slot.Value() != nil {
+		sub.Slot = *input.Slot.Value()
+	}
+	if input.SceneID.IsSet() {
+		sub.SceneID = input.SceneID.Value()
+	}
+	if input.FixtureIds.IsSet() && input.FixtureIds.Value() != nil {
+		fixtureIDsJSON, err := json.Marshal(input.FixtureIds.Value())
 		if err != nil {
 			return nil, err
 		}
-		if scene == nil {
-			return nil, fmt.Errorf("scene not found: %s", input.SceneID)
-		}
+		sub.FixtureIDs = string(fixtureIDsJSON)
 	}
 
-	// Update fields
-	cue.Name = input.Name
-	cue.CueNumber = input.CueNumber
-	cue.SceneID = input.SceneID
-	cue.FadeInTime = input.FadeInTime
-	cue.FadeOutTime = input.FadeOutTime
-
-	if input.FollowTime.IsSet() {
-		cue.FollowTime = input.FollowTime.Value()
+	if err := r.saveSubmaster(ctx, sub, false); err != nil {
+		return nil, err
 	}
 
-	if input.EasingType.IsSet() && input.EasingType.Value() != nil {
-		easingStr := string(*input.EasingType.Value())
-		cue.EasingType = &easingStr
+	// Switching between a scene and a fixture selection starts the fader over
+	if level := defaultSubmasterLevel(sub); level != previousDefault {
+		return r.setSubmasterLevel(ctx, sub.ID, level)
 	}
+	return sub, nil
+}
 
-	if input.Notes.IsSet() {
-		cue.Notes = input.Notes.Value()
+// DeleteSubmaster is the resolver for the deleteSubmaster field.
+func (r *mutationResolver) DeleteSubmaster(ctx context.Context, id string) (bool, error) {
+	if _, err := r.findSubmaster(ctx, id); err != nil {
+		return false, err
 	}
-
-	if err := r.CueRepo.Update(ctx, cue); err != nil {
-		return nil, err
+	if err := r.deleteSubmasters(ctx, "id = ?", id); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return cue, nil
+// SetSubmasterLevel is the resolver for the setSubmasterLevel field.
+func (r *mutationResolver) SetSubmasterLevel(ctx context.Context, id string, level float64) (*models.Submaster, error) {
+	return r.setSubmasterLevel(ctx, id, level)
 }
 
-// DeleteCue is the resolver for the deleteCue field.
-func (r *mutationResolver) DeleteCue(ctx context.Context, id string) (bool, error) {
-	cue, err := r.CueRepo.FindByID(ctx, id)
+// CreateSchedule is the resolver for the createSchedule field.
+func (r *mutationResolver) CreateSchedule(ctx context.Context, input generated.CreateScheduleInput) (*models.Schedule, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if cue == nil {
-		return false, fmt.Errorf("cue not found: %s", id)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
 	}
 
-	if err := r.CueRepo.Delete(ctx, id); err != nil {
-		return false, err
+	schedule := &models.Schedule{
+		ID:        cuid.New(),
+		ProjectID: input.ProjectID,
+		Enabled:   true,
+	}
+	err = applyScheduleInput(schedule, generated.UpdateScheduleInput{
+		Name:          graphql.OmittableOf(&input.Name),
+		Enabled:       input.Enabled,
+		Trigger:       graphql.OmittableOf(&input.Trigger),
+		Cron:          input.Cron,
+		Latitude:      input.Latitude,
+		Longitude:     input.Longitude,
+		OffsetMinutes: input.OffsetMinutes,
+		Days:          input.Days,
+		Action:        graphql.OmittableOf(&input.Action),
+		SceneID:       input.SceneID,
+		CueListID:     input.CueListID,
+		FadeTime:      input.FadeTime,
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return true, nil
+	if err := r.saveSchedule(ctx, schedule, true); err != nil {
+		return nil, err
+	}
+	return schedule, nil
 }
 
-// ReorderCues is the resolver for the reorderCues field.
-func (r *mutationResolver) ReorderCues(ctx context.Context, cueListID string, cueOrders []*generated.CueOrderInput) (bool, error) {
-	// Verify cue list exists
-	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
+// UpdateSchedule is the resolver for the updateSchedule field.
+func (r *mutationResolver) UpdateSchedule(ctx context.Context, id string, input generated.UpdateScheduleInput) (*models.Schedule, error) {
+	schedule, err := r.findSchedule(ctx, id)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if cueList == nil {
-		return false, fmt.Errorf("cue list not found: %s", cueListID)
+	if err := applyScheduleInput(schedule, input); err != nil {
+		return nil, err
 	}
-
-	// Update each cue's number
-	for _, order := range cueOrders {
-		cue, err := r.CueRepo.FindByID(ctx, order.CueID)
-		if err != nil {
-			return false, err
-		}
-		if cue == nil {
-			return false, fmt.Errorf("cue not found: %s", order.CueID)
-		}
-
-		cue.CueNumber = order.CueNumber
-		if err := r.CueRepo.Update(ctx, cue); err != nil {
-			return false, err
-		}
+	if err := r.saveSchedule(ctx, schedule, false); err != nil {
+		return nil, err
 	}
+	return schedule, nil
+}
 
+// DeleteSchedule is the resolver for the deleteSchedule field.
+func (r *mutationResolver) DeleteSchedule(ctx context.Context, id string) (bool, error) {
+	if _, err := r.findSchedule(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.deleteSchedules(ctx, "id = ?", id); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
-// BulkCreateCues is the resolver for the bulkCreateCues field.
-func (r *mutationResolver) BulkCreateCues(ctx context.Context, input generated.BulkCueCreateInput) ([]*models.Cue, error) {
-	var createdCues []*models.Cue
+// FireSchedule is the resolver for the fireSchedule field.
+func (r *mutationResolver) FireSchedule(ctx context.Context, id string) (*generated.ScheduleFiredEvent, error) {
+	return r.fireSchedule(ctx, id)
+}
 
-	for _, cueInput := range input.Cues {
-		cue, err := r.CreateCue(ctx, *cueInput)
-		if err != nil {
-			return nil, err
-		}
-		createdCues = append(createdCues, cue)
-	}
+// CreateUniverse is the resolver for the createUniverse field.
+func (r *mutationResolver) CreateUniverse(ctx context.Context, input generated.CreateUniverseInput) (*models.Universe, error) {
+	return r.createUniverse(ctx, input)
+}
 
-	return createdCues, nil
+// UpdateUniverse is the resolver for the updateUniverse field.
+func (r *mutationResolver) UpdateUniverse(ctx context.Context, id string, input generated.UpdateUniverseInput) (*models.Universe, error) {
+	return r.updateUniverse(ctx, id, input)
 }
 
-// BulkUpdateCues is the resolver for the bulkUpdateCues field.
-func (r *mutationResolver) BulkUpdateCues(ctx context.Context, input generated.BulkCueUpdateInput) ([]*models.Cue, error) {
-	var updatedCues []*models.Cue
+// DeleteUniverse is the resolver for the deleteUniverse field.
+func (r *mutationResolver) DeleteUniverse(ctx context.Context, id string) (bool, error) {
+	if err := r.deleteUniverse(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	for _, cueID := range input.CueIds {
-		cue, err := r.CueRepo.FindByID(ctx, cueID)
-		if err != nil {
-			return nil, err
-		}
-		if cue == nil {
-			return nil, fmt.Errorf("cue not found: %s", cueID)
-		}
+// CreateLayoutZone is the resolver for the createLayoutZone field.
+func (r *mutationResolver) CreateLayoutZone(ctx context.Context, input generated.CreateLayoutZoneInput) (*models.LayoutZone, error) {
+	return r.createLayoutZone(ctx, input)
+}
 
-		// Update fade in time if provided
-		if input.FadeInTime.IsSet() && input.FadeInTime.Value() != nil {
-			cue.FadeInTime = *input.FadeInTime.Value()
-		}
+// UpdateLayoutZone is the resolver for the updateLayoutZone field.
+func (r *mutationResolver) UpdateLayoutZone(ctx context.Context, id string, input generated.UpdateLayoutZoneInput) (*models.LayoutZone, error) {
+	return r.updateLayoutZone(ctx, id, input)
+}
 
-		// Update fade out time if provided
-		if input.FadeOutTime.IsSet() && input.FadeOutTime.Value() != nil {
-			cue.FadeOutTime = *input.FadeOutTime.Value()
-		}
+// DeleteLayoutZone is the resolver for the deleteLayoutZone field.
+func (r *mutationResolver) DeleteLayoutZone(ctx context.Context, id string) (bool, error) {
+	if err := r.deleteLayoutZone(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-		// Update follow time if provided
-		if input.FollowTime.IsSet() {
-			cue.FollowTime = input.FollowTime.Value()
-		}
+// AlignFixtures is the resolver for the alignFixtures field.
+func (r *mutationResolver) AlignFixtures(ctx context.Context, fixtureIds []string, alignment generated.LayoutAlignment, zoneID *string) ([]*models.FixtureInstance, error) {
+	return r.alignFixtures(ctx, fixtureIds, alignment, zoneID)
+}
 
-		// Update easing type if provided
-		if input.EasingType.IsSet() && input.EasingType.Value() != nil {
-			easingStr := string(*input.EasingType.Value())
-			cue.EasingType = &easingStr
-		}
+// DistributeFixtures is the resolver for the distributeFixtures field.
+func (r *mutationResolver) DistributeFixtures(ctx context.Context, fixtureIds []string, axis generated.LayoutAxis, zoneID *string) ([]*models.FixtureInstance, error) {
+	return r.distributeFixtures(ctx, fixtureIds, axis, zoneID)
+}
 
-		if err := r.CueRepo.Update(ctx, cue); err != nil {
-			return nil, err
-		}
+// CreateSelectionSet is the resolver for the createSelectionSet field.
+func (r *mutationResolver) CreateSelectionSet(ctx context.Context, input generated.CreateSelectionSetInput) (*models.SelectionSet, error) {
+	return r.createSelectionSet(ctx, input)
+}
+
+// UpdateSelectionSet is the resolver for the updateSelectionSet field.
+func (r *mutationResolver) UpdateSelectionSet(ctx context.Context, id string, input generated.UpdateSelectionSetInput) (*models.SelectionSet, error) {
+	return r.updateSelectionSet(ctx, id, input)
+}
 
-		updatedCues = append(updatedCues, cue)
+// DeleteSelectionSet is the resolver for the deleteSelectionSet field.
+func (r *mutationResolver) DeleteSelectionSet(ctx context.Context, id string) (bool, error) {
+	if err := r.deleteSelectionSet(ctx, id); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return updatedCues, nil
+// UpdateHouseLights is the resolver for the updateHouseLights field.
+func (r *mutationResolver) UpdateHouseLights(ctx context.Context, projectID string, input generated.UpdateHouseLightsInput) (*models.HouseLights, error) {
+	return r.updateHouseLights(ctx, projectID, input)
 }
 
-// BulkDeleteCues is the resolver for the bulkDeleteCues field.
-func (r *mutationResolver) BulkDeleteCues(ctx context.Context, cueIds []string) (*generated.BulkDeleteResult, error) {
-	var deletedIds []string
-
-	for _, cueID := range cueIds {
-		_, err := r.DeleteCue(ctx, cueID)
-		if err != nil {
-			return nil, err
-		}
-		deletedIds = append(deletedIds, cueID)
-	}
-
-	return &generated.BulkDeleteResult{
-		DeletedCount: len(deletedIds),
-		DeletedIds:   deletedIds,
-	}, nil
+// SetHouseLights is the resolver for the setHouseLights field.
+func (r *mutationResolver) SetHouseLights(ctx context.Context, projectID string, preset generated.HouseLightsPreset, fadeTime *float64) (*generated.HouseLightsStatus, error) {
+	return r.setHouseLights(ctx, projectID, preset, fadeTime)
 }
 
-// StartPreviewSession is the resolver for the startPreviewSession field.
-func (r *mutationResolver) StartPreviewSession(ctx context.Context, projectID string) (*models.PreviewSession, error) {
-	session, err := r.PreviewService.StartSession(ctx, projectID, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert to models.PreviewSession and persist to database
-	var userID string
-	if session.UserID != nil {
-		userID = *session.UserID
-	}
-
-	dbSession := &models.PreviewSession{
-		ID:        session.ID,
-		ProjectID: session.ProjectID,
-		UserID:    userID,
-		IsActive:  session.IsActive,
-		CreatedAt: session.CreatedAt,
-	}
-
-	// Persist to database so it can be queried
-	if err := r.db.Create(dbSession).Error; err != nil {
-		// Rollback in-memory session if database creation fails
-		_, _ = r.PreviewService.CancelSession(ctx, session.ID)
+// ReleaseHouseLights is the resolver for the releaseHouseLights field.
+func (r *mutationResolver) ReleaseHouseLights(ctx context.Context, projectID string) (*generated.HouseLightsStatus, error) {
+	if _, err := r.findHouseLights(ctx, projectID); err != nil {
 		return nil, err
 	}
+	return convertHouseLightsStatus(r.HouseLightsService.Release(projectID)), nil
+}
 
-	return dbSession, nil
+// CreatePixelMatrix is the resolver for the createPixelMatrix field.
+func (r *mutationResolver) CreatePixelMatrix(ctx context.Context, input generated.CreatePixelMatrixInput) (*models.PixelMatrix, error) {
+	return r.createPixelMatrix(ctx, input)
 }
 
-// CommitPreviewSession is the resolver for the commitPreviewSession field.
-func (r *mutationResolver) CommitPreviewSession(ctx context.Context, sessionID string) (bool, error) {
-	return r.PreviewService.CommitSession(ctx, sessionID)
+// UpdatePixelMatrix is the resolver for the updatePixelMatrix field.
+func (r *mutationResolver) UpdatePixelMatrix(ctx context.Context, id string, input generated.UpdatePixelMatrixInput) (*models.PixelMatrix, error) {
+	return r.updatePixelMatrix(ctx, id, input)
 }
 
-// CancelPreviewSession is the resolver for the cancelPreviewSession field.
-func (r *mutationResolver) CancelPreviewSession(ctx context.Context, sessionID string) (bool, error) {
-	success, err := r.PreviewService.CancelSession(ctx, sessionID)
-	if err != nil {
+// DeletePixelMatrix is the resolver for the deletePixelMatrix field.
+func (r *mutationResolver) DeletePixelMatrix(ctx context.Context, id string) (bool, error) {
+	if err := r.deletePixelMatrix(ctx, id); err != nil {
 		return false, err
 	}
+	return true, nil
+}
 
-	// Also delete from database
-	if success {
-		if err := r.db.Where("id = ?", sessionID).Delete(&models.PreviewSession{}).Error; err != nil {
-			return false, err
-		}
-	}
+// ShowPixelMatrixImage is the resolver for the showPixelMatrixImage field.
+func (r *mutationResolver) ShowPixelMatrixImage(ctx context.Context, matrixID string, image string) (*models.PixelMatrix, error) {
+	return r.showPixelMatrixImage(ctx, matrixID, image)
+}
 
-	return success, nil
+// ShowPixelMatrixGradient is the resolver for the showPixelMatrixGradient field.
+func (r *mutationResolver) ShowPixelMatrixGradient(ctx context.Context, matrixID string, colors []string, angle *float64) (*models.PixelMatrix, error) {
+	return r.showPixelMatrixGradient(ctx, matrixID, colors, angle)
 }
 
-// UpdatePreviewChannel is the resolver for the updatePreviewChannel field.
-func (r *mutationResolver) UpdatePreviewChannel(ctx context.Context, sessionID string, fixtureID string, channelIndex int, value int) (bool, error) {
-	return r.PreviewService.UpdateChannelValue(ctx, sessionID, fixtureID, channelIndex, value)
+// ReleasePixelMatrix is the resolver for the releasePixelMatrix field.
+func (r *mutationResolver) ReleasePixelMatrix(ctx context.Context, matrixID string) (*models.PixelMatrix, error) {
+	return r.releasePixelMatrix(ctx, matrixID)
 }
 
-// InitializePreviewWithScene is the resolver for the initializePreviewWithScene field.
-func (r *mutationResolver) InitializePreviewWithScene(ctx context.Context, sessionID string, sceneID string) (bool, error) {
-	return r.PreviewService.InitializeWithScene(ctx, sessionID, sceneID)
+// SetSoftPatch is the resolver for the setSoftPatch field.
+func (r *mutationResolver) SetSoftPatch(ctx context.Context, input generated.SoftPatchInput) (*models.SoftPatch, error) {
+	return r.setSoftPatch(ctx, input)
 }
 
-// SetChannelValue is the resolver for the setChannelValue field.
-func (r *mutationResolver) SetChannelValue(ctx context.Context, universe int, channel int, value int) (bool, error) {
-	// Clamp value to 0-255
-	if value < 0 {
-		value = 0
+// DeleteSoftPatch is the resolver for the deleteSoftPatch field.
+func (r *mutationResolver) DeleteSoftPatch(ctx context.Context, id string) (bool, error) {
+	deleted, err := r.deleteSoftPatches(ctx, "id = ?", id)
+	if err != nil {
+		return false, err
 	}
-	if value > 255 {
-		value = 255
+	if deleted == 0 {
+		return false, fmt.Errorf("softpatch entry not found: %s", id)
 	}
-	// DMX service expects 1-indexed universe and channel
-	r.DMXService.SetChannelValue(universe, channel, byte(value))
 	return true, nil
 }
 
-// SetSceneLive is the resolver for the setSceneLive field.
-func (r *mutationResolver) SetSceneLive(ctx context.Context, sceneID string) (bool, error) {
-	// Load scene with fixture values
-	var scene models.Scene
-	result := r.db.Preload("FixtureValues").First(&scene, "id = ?", sceneID)
-	if result.Error != nil {
-		return false, fmt.Errorf("scene not found: %w", result.Error)
-	}
+// ClearSoftPatch is the resolver for the clearSoftPatch field.
+func (r *mutationResolver) ClearSoftPatch(ctx context.Context, projectID string) (int, error) {
+	deleted, err := r.deleteSoftPatches(ctx, "project_id = ?", projectID)
+	return int(deleted), err
+}
 
-	// Load fixtures for the scene's fixture values
-	var fixtureIDs []string
-	for _, fv := range scene.FixtureValues {
-		fixtureIDs = append(fixtureIDs, fv.FixtureID)
+// CreateFixtureGroup is the resolver for the createFixtureGroup field.
+func (r *mutationResolver) CreateFixtureGroup(ctx context.Context, input generated.CreateFixtureGroupInput) (*models.FixtureGroup, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
 	}
-
-	var fixtures []models.FixtureInstance
-	if len(fixtureIDs) > 0 {
-		r.db.Where("id IN ?", fixtureIDs).Find(&fixtures)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
 	}
 
-	// Create fixture lookup map
-	fixtureMap := make(map[string]*models.FixtureInstance)
-	for i := range fixtures {
-		fixtureMap[fixtures[i].ID] = &fixtures[i]
+	group := &models.FixtureGroup{
+		Name:        input.Name,
+		Description: input.Description.Value(),
+		ProjectID:   input.ProjectID,
 	}
-
-	// Set channel values directly (no fade)
-	for _, fixtureValue := range scene.FixtureValues {
-		fixture := fixtureMap[fixtureValue.FixtureID]
-		if fixture == nil {
-			continue
-		}
-
-		// Parse sparse channel values from JSON (Channels field)
-		var channels []models.ChannelValue
-		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
-			log.Printf("Warning: failed to unmarshal channels for fixtureID %s in sceneID %s: %v", fixtureValue.FixtureID, sceneID, err)
-			continue
-		}
-
-		// Set channel values - only channels that exist in the sparse array
-		for _, ch := range channels {
-			dmxChannel := fixture.StartChannel + ch.Offset
-
-			// Validate DMX channel is within bounds
-			if !validateDMXChannel(dmxChannel, fixture.Universe, fixture.ID, ch.Offset) {
-				continue
-			}
-
-			r.DMXService.SetChannelValue(fixture.Universe, dmxChannel, byte(ch.Value))
-		}
+	fixtureIDs, err := r.selectFixtures(ctx, input.ProjectID, input.FixtureIds.Value(), input.SelectionSetID.Value(), input.SelectionOrder.Value(), nil)
+	if err != nil {
+		return nil, err
 	}
-
-	// Force immediate transmission
-	r.DMXService.TriggerChangeDetection()
-
-	// Track the active scene
-	r.DMXService.SetActiveScene(sceneID)
-
-	return true, nil
-}
-
-// PlayCue is the resolver for the playCue field.
-func (r *mutationResolver) PlayCue(ctx context.Context, cueID string, fadeInTime *float64) (bool, error) {
-	if err := r.PlaybackService.ExecuteCueDmx(ctx, cueID, fadeInTime); err != nil {
-		return false, err
+	if err := r.setGroupFixtures(ctx, group, fixtureIDs); err != nil {
+		return nil, err
 	}
-	return true, nil
+	if err := r.FixtureGroupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
 }
 
-// FadeToBlack is the resolver for the fadeToBlack field.
-func (r *mutationResolver) FadeToBlack(ctx context.Context, fadeOutTime float64) (bool, error) {
-	// Convert fadeOutTime from seconds to duration
-	duration := time.Duration(fadeOutTime * float64(time.Second))
-
-	// Use the fade engine to smoothly fade to black
-	fadeID := r.FadeEngine.FadeToBlack(duration, "")
-
-	// For instant fades (0 duration), also immediately clear DMX state
-	// For timed fades, the fade engine will handle the gradual transition,
-	// but we still need to ensure the DMX service state is cleared at the end
-	if duration == 0 {
-		r.DMXService.FadeToBlack()
-	} else {
-		// After fade completes, ensure DMX service clears all channels
-		// This handles channels set directly via SetChannelValue that may not be tracked by fade engine
-		// Use the fadeID to detect if a new fade has started and skip cleanup if so
-		go func(currentFadeID string) {
-			time.Sleep(duration + 100*time.Millisecond) // Wait for fade to complete
-			// Only clear if this is still the active fade (no new fade has started)
-			if r.FadeEngine.ActiveFadeCount() == 0 {
-				r.DMXService.FadeToBlack()
-			}
-		}(fadeID)
+// UpdateFixtureGroup is the resolver for the updateFixtureGroup field.
+func (r *mutationResolver) UpdateFixtureGroup(ctx context.Context, id string, input generated.UpdateFixtureGroupInput) (*models.FixtureGroup, error) {
+	group, err := r.findFixtureGroup(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	// Clear active scene tracking
-	r.DMXService.ClearActiveScene()
-
-	_ = fadeID // suppress unused variable warning
-	return true, nil
-}
+	if input.Name.IsSet() && input.Name.Value() != nil {
+		group.Name = *input.Name.Value()
+	}
+	if input.Description.IsSet() {
+		group.Description = input.Description.Value()
+	}
+	current, err := repositories.GroupFixtureIDs(group)
+	if err != nil {
+		return nil, err
+	}
+	fixtureIDs, err := r.selectFixtures(ctx, group.ProjectID, input.FixtureIds.Value(), input.SelectionSetID.Value(), input.SelectionOrder.Value(), current)
+	if err != nil {
+		return nil, err
+	}
+	membersChanged := fixtureIDs != nil
+	if membersChanged {
+		if err := r.setGroupFixtures(ctx, group, fixtureIDs); err != nil {
+			return nil, err
+		}
+	}
 
-// StartCueList is the resolver for the startCueList field.
-func (r *mutationResolver) StartCueList(ctx context.Context, cueListID string, startFromCue *int, fadeInTime *float64) (bool, error) {
-	var startFromCueNumber *float64
-	if startFromCue != nil {
-		cueNum := float64(*startFromCue)
-		startFromCueNumber = &cueNum
+	if err := r.FixtureGroupRepo.Update(ctx, group); err != nil {
+		return nil, err
 	}
-	if err := r.PlaybackService.StartCueList(ctx, cueListID, startFromCueNumber, fadeInTime); err != nil {
-		return false, err
+
+	// Scenes with values for the group pick up its new members
+	if membersChanged {
+		sceneIDs, err := r.SceneRepo.FindSceneIDsByGroupID(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.syncGroupScenes(ctx, sceneIDs); err != nil {
+			return nil, err
+		}
 	}
-	return true, nil
+	return group, nil
 }
 
-// NextCue is the resolver for the nextCue field.
-func (r *mutationResolver) NextCue(ctx context.Context, cueListID string, fadeInTime *float64) (bool, error) {
-	if err := r.PlaybackService.NextCue(ctx, cueListID, fadeInTime); err != nil {
+// DeleteFixtureGroup is the resolver for the deleteFixtureGroup field.
+func (r *mutationResolver) DeleteFixtureGroup(ctx context.Context, id string) (bool, error) {
+	if _, err := r.findFixtureGroup(ctx, id); err != nil {
 		return false, err
 	}
-	return true, nil
-}
-
-// PreviousCue is the resolver for the previousCue field.
-func (r *mutationResolver) PreviousCue(ctx context.Context, cueListID string, fadeInTime *float64) (bool, error) {
-	if err := r.PlaybackService.PreviousCue(ctx, cueListID, fadeInTime); err != nil {
+	sceneIDs, err := r.FixtureGroupRepo.Delete(ctx, id)
+	if err != nil {
 		return false, err
 	}
-	return true, nil
-}
-
-// GoToCue is the resolver for the goToCue field.
-func (r *mutationResolver) GoToCue(ctx context.Context, cueListID string, cueIndex int, fadeInTime *float64) (bool, error) {
-	if err := r.PlaybackService.JumpToCue(ctx, cueListID, cueIndex, fadeInTime); err != nil {
+	if err := r.syncGroupScenes(ctx, sceneIDs); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// StopCueList is the resolver for the stopCueList field.
-func (r *mutationResolver) StopCueList(ctx context.Context, cueListID string) (bool, error) {
-	r.PlaybackService.StopCueList(cueListID)
-	return true, nil
-}
-
-// ExportProject is the resolver for the exportProject field.
-func (r *mutationResolver) ExportProject(ctx context.Context, projectID string, options *generated.ExportOptionsInput) (*generated.ExportResult, error) {
-	// Get project first to get name
-	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+// CreatePalette is the resolver for the createPalette field.
+func (r *mutationResolver) CreatePalette(ctx context.Context, input generated.CreatePaletteInput) (*models.Palette, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
 	if err != nil {
 		return nil, err
 	}
 	if project == nil {
-		return nil, fmt.Errorf("project not found: %s", projectID)
-	}
-
-	// Parse options
-	includeFixtures := true
-	includeScenes := true
-	includeCueLists := true
-
-	if options != nil {
-		if options.IncludeFixtures.IsSet() && options.IncludeFixtures.Value() != nil {
-			includeFixtures = *options.IncludeFixtures.Value()
-		}
-		if options.IncludeScenes.IsSet() && options.IncludeScenes.Value() != nil {
-			includeScenes = *options.IncludeScenes.Value()
-		}
-		if options.IncludeCueLists.IsSet() && options.IncludeCueLists.Value() != nil {
-			includeCueLists = *options.IncludeCueLists.Value()
-		}
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
 	}
 
-	// Export project
-	exported, stats, err := r.ExportService.ExportProject(ctx, projectID, includeFixtures, includeScenes, includeCueLists)
+	channelsJSON, err := serializePaletteChannels(input.Kind, input.Channels)
 	if err != nil {
 		return nil, err
 	}
-	if exported == nil {
-		return nil, fmt.Errorf("project not found: %s", projectID)
+	palette := &models.Palette{
+		Name:      input.Name,
+		ProjectID: input.ProjectID,
+		Kind:      string(input.Kind),
+		Channels:  channelsJSON,
 	}
-
-	// Convert to JSON
-	jsonContent, err := exported.ToJSON()
-	if err != nil {
+	if err := r.PaletteRepo.Create(ctx, palette); err != nil {
 		return nil, err
 	}
-
-	return &generated.ExportResult{
-		ProjectID:   projectID,
-		ProjectName: project.Name,
-		JSONContent: jsonContent,
-		Stats: generated.ExportStats{
-			FixtureDefinitionsCount: stats.FixtureDefinitionsCount,
-			FixtureInstancesCount:   stats.FixtureInstancesCount,
-			ScenesCount:             stats.ScenesCount,
-			CueListsCount:           stats.CueListsCount,
-			CuesCount:               stats.CuesCount,
-			SceneBoardsCount:        stats.SceneBoardsCount,
-		},
-	}, nil
+	return palette, nil
 }
 
-// ImportProject is the resolver for the importProject field.
-func (r *mutationResolver) ImportProject(ctx context.Context, jsonContent string, options generated.ImportOptionsInput) (*generated.ImportResult, error) {
-	// Build import options
-	importOpts := importservice.ImportOptions{
-		Mode:                    importservice.ImportMode(options.Mode),
-		ImportBuiltInFixtures:   false,
-		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+// UpdatePalette is the resolver for the updatePalette field.
+func (r *mutationResolver) UpdatePalette(ctx context.Context, id string, input generated.UpdatePaletteInput) (*models.Palette, error) {
+	palette, err := r.findPalette(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if options.TargetProjectID.IsSet() {
-		importOpts.TargetProjectID = options.TargetProjectID.Value()
+	if input.Name.IsSet() && input.Name.Value() != nil {
+		palette.Name = *input.Name.Value()
 	}
-
-	if options.ProjectName.IsSet() {
-		importOpts.ProjectName = options.ProjectName.Value()
+	channelsChanged := input.Channels.IsSet() && input.Channels.Value() != nil
+	if channelsChanged {
+		channelsJSON, err := serializePaletteChannels(generated.PaletteKind(palette.Kind), input.Channels.Value())
+		if err != nil {
+			return nil, err
+		}
+		palette.Channels = channelsJSON
 	}
 
-	if options.FixtureConflictStrategy.IsSet() && options.FixtureConflictStrategy.Value() != nil {
-		importOpts.FixtureConflictStrategy = importservice.FixtureConflictStrategy(*options.FixtureConflictStrategy.Value())
+	if err := r.PaletteRepo.Update(ctx, palette); err != nil {
+		return nil, err
 	}
 
-	if options.ImportBuiltInFixtures.IsSet() && options.ImportBuiltInFixtures.Value() != nil {
-		importOpts.ImportBuiltInFixtures = *options.ImportBuiltInFixtures.Value()
+	// Scenes referencing the palette pick up its new values
+	if channelsChanged {
+		if err := r.refreshPaletteScenes(ctx, palette.ID); err != nil {
+			return nil, err
+		}
 	}
+	return palette, nil
+}
 
-	// Import project
-	projectID, stats, warnings, err := r.ImportService.ImportProject(ctx, jsonContent, importOpts)
-	if err != nil {
-		return nil, err
+// DeletePalette is the resolver for the deletePalette field.
+func (r *mutationResolver) DeletePalette(ctx context.Context, id string) (bool, error) {
+	if _, err := r.findPalette(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.removePaletteReferences(ctx, id); err != nil {
+		return false, err
+	}
+	if err := r.PaletteRepo.Delete(ctx, id); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return &generated.ImportResult{
-		ProjectID: projectID,
-		Stats: generated.ImportStats{
-			FixtureDefinitionsCreated: stats.FixtureDefinitionsCreated,
-			FixtureInstancesCreated:   stats.FixtureInstancesCreated,
-			ScenesCreated:             stats.ScenesCreated,
-			CueListsCreated:           stats.CueListsCreated,
-			CuesCreated:               stats.CuesCreated,
-			SceneBoardsCreated:        stats.SceneBoardsCreated,
-		},
-		Warnings: warnings,
-	}, nil
+// Undo is the resolver for the undo field.
+func (r *mutationResolver) Undo(ctx context.Context, projectID string) (*generated.UndoStackStatus, error) {
+	return r.applyUndo(ctx, projectID, false)
 }
 
-// ImportProjectFromQlc is the resolver for the importProjectFromQLC field.
-// Returns error - QLC+ import not available on this platform
-func (r *mutationResolver) ImportProjectFromQlc(ctx context.Context, xmlContent string, originalFileName string) (*generated.QLCImportResult, error) {
-	return nil, fmt.Errorf("QLC+ import not available on this platform")
+// Redo is the resolver for the redo field.
+func (r *mutationResolver) Redo(ctx context.Context, projectID string) (*generated.UndoStackStatus, error) {
+	return r.applyUndo(ctx, projectID, true)
 }
 
-// ExportProjectToQlc is the resolver for the exportProjectToQLC field.
-// Returns error - QLC+ export not available on this platform
-func (r *mutationResolver) ExportProjectToQlc(ctx context.Context, projectID string, fixtureMappings []*generated.FixtureMappingInput) (*generated.QLCExportResult, error) {
-	return nil, fmt.Errorf("QLC+ export not available on this platform")
-}
+// ActivateSceneFromBoard is the resolver for the activateSceneFromBoard field.
+func (r *mutationResolver) ActivateSceneFromBoard(ctx context.Context, sceneBoardID string, sceneID string, fadeTimeOverride *float64) (bool, error) {
+	// Verify scene board exists
+	var board models.SceneBoard
+	result := r.db.WithContext(ctx).First(&board, "id = ?", sceneBoardID)
+	if result.Error != nil {
+		return false, fmt.Errorf("scene board not found: %w", result.Error)
+	}
 
-// UpdateSetting is the resolver for the updateSetting field.
-func (r *mutationResolver) UpdateSetting(ctx context.Context, input generated.UpdateSettingInput) (*models.Setting, error) {
-	setting, err := r.SettingRepo.Upsert(ctx, input.Key, input.Value)
+	// Verify scene exists
+	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-
-	// If the artnet broadcast address is being updated, reload the DMX service
-	if input.Key == "artnet_broadcast_address" {
-		if err := r.DMXService.ReloadBroadcastAddress(input.Value); err != nil {
-			// Log but don't fail - the setting was saved
-			fmt.Printf("Warning: failed to reload Art-Net broadcast address: %v\n", err)
-		}
+	if scene == nil {
+		return false, fmt.Errorf("scene not found: %s", sceneID)
 	}
 
-	return setting, nil
-}
-
-// UpdateFadeUpdateRate is the resolver for the updateFadeUpdateRate field.
-func (r *mutationResolver) UpdateFadeUpdateRate(ctx context.Context, rateHz int) (bool, error) {
-	// Validate the rate
-	if rateHz < 1 || rateHz > 240 {
-		return false, fmt.Errorf("fade update rate must be between 1 and 240 Hz")
+	// The scene's button on this board may set its own fade time
+	levels := playback.SceneFadeLevels{Call: fadeTimeOverride, Board: &board.DefaultFadeTime}
+	var button models.SceneBoardButton
+	err = r.db.WithContext(ctx).Where("scene_board_id = ? AND scene_id = ?", sceneBoardID, sceneID).First(&button).Error
+	if err == nil {
+		levels.Button = button.FadeInTime
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	fadeTime, err := r.PlaybackService.ResolveSceneFadeIn(ctx, sceneID, levels)
+	if err != nil {
+		return false, err
 	}
 
-	// Update the fade engine
-	r.FadeEngine.SetUpdateRate(rateHz)
-
-	// Save the setting to the database
-	_, err := r.SettingRepo.Upsert(ctx, "fade_update_rate_hz", fmt.Sprintf("%d", rateHz))
+	sceneChannels, err := r.loadSceneChannels(ctx, sceneID)
 	if err != nil {
-		return false, fmt.Errorf("failed to save fade update rate setting: %w", err)
+		return false, err
+	}
+
+	// Execute fade, merged with the scenes live on other playbacks
+	fadeDuration := time.Duration(fadeTime * float64(time.Second))
+	activation := stack.Activation{
+		PlaybackID: stack.SceneBoardPlayback(sceneBoardID),
+		Kind:       stack.KindSceneBoard,
+		SceneID:    sceneID,
+		Channels:   sceneChannels,
 	}
+	r.StackService.Activate(activation, fadeDuration, sceneBoardFadeID(sceneID), fade.EasingInOutSine)
+	r.journalSceneLive(activation)
+
+	// Track the active scene
+	r.DMXService.SetActiveScene(sceneID)
+	r.sceneBoardStateChanged(sceneBoardID)
 
 	return true, nil
 }
 
-// ConnectWiFi is the resolver for the connectWiFi field.
-func (r *mutationResolver) ConnectWiFi(ctx context.Context, ssid string, password *string) (*generated.WiFiConnectionResult, error) {
-	result, err := r.WiFiService.ConnectToNetwork(ctx, ssid, password)
-	if err != nil {
-		return nil, err
+// PressSceneBoardButton is the resolver for the pressSceneBoardButton field.
+func (r *mutationResolver) PressSceneBoardButton(ctx context.Context, buttonID string) (*generated.SceneBoardButtonHoldState, error) {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return nil, fmt.Errorf("scene board button not found: %w", err)
 	}
-	return &generated.WiFiConnectionResult{
-		Success:   result.Success,
-		Message:   result.Message,
-		Connected: result.Connected,
-	}, nil
-}
 
-// DisconnectWiFi is the resolver for the disconnectWiFi field.
-func (r *mutationResolver) DisconnectWiFi(ctx context.Context) (*generated.WiFiConnectionResult, error) {
-	result, err := r.WiFiService.Disconnect(ctx)
-	if err != nil {
-		return nil, err
+	var board models.SceneBoard
+	if err := r.db.WithContext(ctx).First(&board, "id = ?", button.SceneBoardID).Error; err != nil {
+		return nil, fmt.Errorf("scene board not found: %w", err)
 	}
-	return &generated.WiFiConnectionResult{
-		Success:   result.Success,
-		Message:   result.Message,
-		Connected: result.Connected,
-	}, nil
-}
 
-// SetWiFiEnabled is the resolver for the setWiFiEnabled field.
-func (r *mutationResolver) SetWiFiEnabled(ctx context.Context, enabled bool) (*generated.WiFiStatus, error) {
-	status, err := r.WiFiService.SetWiFiEnabled(ctx, enabled)
+	sceneChannels, err := r.loadSceneChannels(ctx, button.SceneID)
 	if err != nil {
 		return nil, err
 	}
-	return convertWiFiStatus(status), nil
-}
 
-// ForgetWiFiNetwork is the resolver for the forgetWiFiNetwork field.
-func (r *mutationResolver) ForgetWiFiNetwork(ctx context.Context, ssid string) (bool, error) {
-	return r.WiFiService.ForgetNetwork(ctx, ssid)
+	rampTime := time.Duration(board.HoldRampTime * float64(time.Second))
+	state := r.HoldService.Press(button.ID, button.SceneID, sceneChannels, rampTime, fade.EasingType(board.HoldCurve))
+
+	// Track the active scene
+	r.DMXService.SetActiveScene(button.SceneID)
+
+	return convertHoldState(state), nil
 }
 
-// StartAPMode is the resolver for the startAPMode field.
-func (r *mutationResolver) StartAPMode(ctx context.Context) (*generated.WiFiModeResult, error) {
-	result, err := r.WiFiService.StartAPMode(ctx)
-	if err != nil {
-		return nil, err
+// ReleaseSceneBoardButton is the resolver for the releaseSceneBoardButton field.
+func (r *mutationResolver) ReleaseSceneBoardButton(ctx context.Context, buttonID string, releaseMode *generated.HoldReleaseMode) (*generated.SceneBoardButtonHoldState, error) {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return nil, fmt.Errorf("scene board button not found: %w", err)
 	}
 
-	return &generated.WiFiModeResult{
-		Success: result.Success,
-		Message: result.Message,
-		Mode:    generated.WiFiMode(result.Mode),
-	}, nil
-}
+	var board models.SceneBoard
+	if err := r.db.WithContext(ctx).First(&board, "id = ?", button.SceneBoardID).Error; err != nil {
+		return nil, fmt.Errorf("scene board not found: %w", err)
+	}
 
-// StopAPMode is the resolver for the stopAPMode field.
-func (r *mutationResolver) StopAPMode(ctx context.Context, connectToSsid *string) (*generated.WiFiModeResult, error) {
-	result, err := r.WiFiService.StopAPMode(ctx, connectToSsid)
+	mode := sceneboard.ReleaseMode(board.HoldReleaseMode)
+	if releaseMode != nil {
+		mode = sceneboard.ReleaseMode(*releaseMode)
+	}
+
+	fadeOut, err := r.PlaybackService.ResolveSceneFadeOut(ctx, button.SceneID, playback.SceneFadeLevels{
+		Button: button.FadeOutTime,
+		Board:  &board.DefaultFadeTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	releaseTime := time.Duration(fadeOut * float64(time.Second))
+	state, err := r.HoldService.Release(button.ID, mode, releaseTime)
 	if err != nil {
 		return nil, err
 	}
 
-	return &generated.WiFiModeResult{
-		Success: result.Success,
-		Message: result.Message,
-		Mode:    generated.WiFiMode(result.Mode),
-	}, nil
-}
+	if !state.IsLatched {
+		r.DMXService.ClearActiveScene()
+	}
 
-// ResetAPTimeout is the resolver for the resetAPTimeout field.
-func (r *mutationResolver) ResetAPTimeout(ctx context.Context) (bool, error) {
-	return r.WiFiService.ResetAPTimeout(ctx)
+	return convertHoldState(state), nil
 }
 
-// UpdateRepository is the resolver for the updateRepository field.
-// Updates a specific repository to a specific version
-func (r *mutationResolver) UpdateRepository(ctx context.Context, repository string, version *string) (*generated.UpdateResult, error) {
-	result, err := r.VersionService.UpdateRepository(repository, version)
-	if err != nil {
-		errMsg := err.Error()
-		return &generated.UpdateResult{
-			Success:         false,
-			Repository:      repository,
-			PreviousVersion: "",
-			NewVersion:      "",
-			Error:           &errMsg,
-		}, nil
+// FlashSceneStart is the resolver for the flashSceneStart field.
+func (r *mutationResolver) FlashSceneStart(ctx context.Context, buttonID string) (*generated.SceneBoardFlashState, error) {
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return nil, fmt.Errorf("scene board button not found: %w", err)
+	}
+	if !button.FlashMode {
+		return nil, fmt.Errorf("scene board button is not in flash mode: %s", buttonID)
 	}
 
-	return &generated.UpdateResult{
-		Success:         result.Success,
-		Repository:      result.Repository,
-		PreviousVersion: result.PreviousVersion,
-		NewVersion:      result.NewVersion,
-		Message:         stringToPointer(result.Message),
-		Error:           stringToPointer(result.Error),
-	}, nil
-}
-
-// UpdateAllRepositories is the resolver for the updateAllRepositories field.
-// Updates all repositories to their latest versions
-func (r *mutationResolver) UpdateAllRepositories(ctx context.Context) ([]*generated.UpdateResult, error) {
-	results, err := r.VersionService.UpdateAllRepositories()
+	intensity, other, err := r.sceneLayerValues(ctx, button.SceneID)
 	if err != nil {
 		return nil, err
 	}
 
-	var gqlResults []*generated.UpdateResult
-	for _, result := range results {
-		gqlResults = append(gqlResults, &generated.UpdateResult{
-			Success:         result.Success,
-			Repository:      result.Repository,
-			PreviousVersion: result.PreviousVersion,
-			NewVersion:      result.NewVersion,
-			Message:         stringToPointer(result.Message),
-			Error:           stringToPointer(result.Error),
-		})
+	level := 1.0
+	if button.FlashLevel != nil {
+		level = *button.FlashLevel
+	}
+	state, err := r.FlashService.Start(button.ID, button.SceneID, dmx.Flash{Intensity: intensity, Other: other}, level)
+	if err != nil {
+		return nil, err
 	}
 
-	return gqlResults, nil
+	return convertFlashState(state), nil
 }
 
-// TriggerOFLImport is the resolver for the triggerOFLImport field.
-func (r *mutationResolver) TriggerOFLImport(ctx context.Context, options *generated.OFLImportOptionsInput) (*generated.OFLImportResult, error) {
-	// Convert GraphQL input to service options
-	var opts *ofl.ImportOptions
-	if options != nil {
-		opts = &ofl.ImportOptions{}
-		// Handle Omittable types - get the underlying value if set
-		if v := options.ForceReimport.Value(); v != nil {
-			opts.ForceReimport = *v
-		}
-		if v := options.UpdateInUseFixtures.Value(); v != nil {
-			opts.UpdateInUseFixtures = *v
-		}
-		if v := options.PreferBundled.Value(); v != nil {
-			opts.PreferBundled = *v
-		}
-		if v := options.Manufacturers.Value(); v != nil {
-			opts.Manufacturers = v
-		}
+// FlashSceneEnd is the resolver for the flashSceneEnd field.
+func (r *mutationResolver) FlashSceneEnd(ctx context.Context, buttonID string, releaseTime *float64) (*generated.SceneBoardFlashState, error) {
+	if releaseTime != nil && *releaseTime < 0 {
+		return nil, fmt.Errorf("releaseTime must not be negative")
 	}
 
-	// Trigger the import
-	result, err := r.OFLManager.TriggerImport(ctx, opts)
+	var button models.SceneBoardButton
+	if err := r.db.WithContext(ctx).First(&button, "id = ?", buttonID).Error; err != nil {
+		return nil, fmt.Errorf("scene board button not found: %w", err)
+	}
+
+	seconds := 0.0
+	switch {
+	case releaseTime != nil:
+		seconds = *releaseTime
+	case button.FlashReleaseTime != nil:
+		seconds = *button.FlashReleaseTime
+	}
+	state, err := r.FlashService.End(button.ID, time.Duration(seconds*float64(time.Second)))
 	if err != nil {
-		return &generated.OFLImportResult{
-			Success:      false,
-			ErrorMessage: stringPtr(err.Error()),
-			OflVersion:   "unknown",
-			Stats: generated.OFLImportStats{
-				TotalProcessed:    0,
-				SuccessfulImports: 0,
-				FailedImports:     0,
-				SkippedDuplicates: 0,
-				UpdatedFixtures:   0,
-				DurationSeconds:   0,
-			},
-		}, nil
+		return nil, err
 	}
 
-	return &generated.OFLImportResult{
-		Success:    result.Success,
-		OflVersion: result.OFLVersion,
-		Stats: generated.OFLImportStats{
-			TotalProcessed:    result.Stats.TotalProcessed,
-			SuccessfulImports: result.Stats.SuccessfulImports,
-			FailedImports:     result.Stats.FailedImports,
-			SkippedDuplicates: result.Stats.SkippedDuplicates,
-			UpdatedFixtures:   result.Stats.UpdatedFixtures,
-			DurationSeconds:   result.Stats.DurationSeconds,
-		},
-	}, nil
+	return convertFlashState(state), nil
 }
 
-// CancelOFLImport is the resolver for the cancelOFLImport field.
-func (r *mutationResolver) CancelOFLImport(ctx context.Context) (bool, error) {
-	return r.OFLManager.CancelImport(), nil
+// ExecuteMacro is the resolver for the executeMacro field.
+func (r *mutationResolver) ExecuteMacro(ctx context.Context, buttonID string) (bool, error) {
+	if err := r.executeMacro(ctx, buttonID); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// Project is the resolver for the project field.
-func (r *previewSessionResolver) Project(ctx context.Context, obj *models.PreviewSession) (*models.Project, error) {
-	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+// StopMacro is the resolver for the stopMacro field.
+func (r *mutationResolver) StopMacro(ctx context.Context, buttonID string) (bool, error) {
+	return r.MacroService.Stop(buttonID), nil
 }
 
-// User is the resolver for the user field.
-func (r *previewSessionResolver) User(ctx context.Context, obj *models.PreviewSession) (*models.User, error) {
-	if obj.UserID == "" {
-		return nil, nil
+// CreateCueList is the resolver for the createCueList field.
+func (r *mutationResolver) CreateCueList(ctx context.Context, input generated.CreateCueListInput) (*models.CueList, error) {
+	cueList := &models.CueList{
+		Name:      input.Name,
+		ProjectID: input.ProjectID,
 	}
-	var user models.User
-	result := r.db.First(&user, "id = ?", obj.UserID)
-	if result.Error != nil {
-		return nil, result.Error
+
+	if input.Description.IsSet() {
+		cueList.Description = input.Description.Value()
 	}
-	return &user, nil
-}
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *previewSessionResolver) CreatedAt(ctx context.Context, obj *models.PreviewSession) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
-}
+	if input.Loop.IsSet() && input.Loop.Value() != nil {
+		cueList.Loop = *input.Loop.Value()
+	}
 
-// DmxOutput is the resolver for the dmxOutput field.
-func (r *previewSessionResolver) DmxOutput(ctx context.Context, obj *models.PreviewSession) ([]*generated.UniverseOutput, error) {
-	// Get DMX output from preview service
-	previewOutput := r.PreviewService.GetDMXOutput(obj.ID)
-	if previewOutput == nil {
-		return []*generated.UniverseOutput{}, nil
+	if input.PlaybackMode.IsSet() && input.PlaybackMode.Value() != nil {
+		cueList.PlaybackMode = string(*input.PlaybackMode.Value())
 	}
 
-	// Convert to GraphQL type
-	result := make([]*generated.UniverseOutput, len(previewOutput))
-	for i, output := range previewOutput {
-		result[i] = &generated.UniverseOutput{
-			Universe: output.Universe,
-			Channels: output.Channels,
-		}
+	if input.Tracking.IsSet() && input.Tracking.Value() != nil {
+		cueList.Tracking = *input.Tracking.Value()
 	}
-	return result, nil
-}
-
-// FixtureCount is the resolver for the fixtureCount field.
-func (r *projectResolver) FixtureCount(ctx context.Context, obj *models.Project) (int, error) {
-	count, err := r.ProjectRepo.CountFixtures(ctx, obj.ID)
-	return int(count), err
-}
-
-// SceneCount is the resolver for the sceneCount field.
-func (r *projectResolver) SceneCount(ctx context.Context, obj *models.Project) (int, error) {
-	count, err := r.ProjectRepo.CountScenes(ctx, obj.ID)
-	return int(count), err
-}
-
-// CueListCount is the resolver for the cueListCount field.
-func (r *projectResolver) CueListCount(ctx context.Context, obj *models.Project) (int, error) {
-	count, err := r.ProjectRepo.CountCueLists(ctx, obj.ID)
-	return int(count), err
-}
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *projectResolver) CreatedAt(ctx context.Context, obj *models.Project) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
-}
+	if err := r.CueListRepo.Create(ctx, cueList); err != nil {
+		return nil, err
+	}
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, cueList)
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *projectResolver) UpdatedAt(ctx context.Context, obj *models.Project) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+	return cueList, nil
 }
 
-// Fixtures is the resolver for the fixtures field.
-func (r *projectResolver) Fixtures(ctx context.Context, obj *models.Project) ([]*models.FixtureInstance, error) {
-	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, obj.ID)
+// UpdateCueList is the resolver for the updateCueList field.
+func (r *mutationResolver) UpdateCueList(ctx context.Context, id string, input generated.CreateCueListInput) (*models.CueList, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	pointers := make([]*models.FixtureInstance, len(fixtures))
-	for i := range fixtures {
-		pointers[i] = &fixtures[i]
+	if cueList == nil {
+		return nil, fmt.Errorf("cue list not found: %s", id)
 	}
-	return pointers, nil
-}
 
-// Scenes is the resolver for the scenes field.
-func (r *projectResolver) Scenes(ctx context.Context, obj *models.Project) ([]*models.Scene, error) {
-	scenes, err := r.SceneRepo.FindByProjectID(ctx, obj.ID)
-	if err != nil {
-		return nil, err
-	}
-	pointers := make([]*models.Scene, len(scenes))
-	for i := range scenes {
-		pointers[i] = &scenes[i]
+	// Update fields
+	cueList.Name = input.Name
+
+	if input.Description.IsSet() {
+		cueList.Description = input.Description.Value()
 	}
-	return pointers, nil
-}
 
-// CueLists is the resolver for the cueLists field.
-func (r *projectResolver) CueLists(ctx context.Context, obj *models.Project) ([]*models.CueList, error) {
-	cueLists, err := r.CueListRepo.FindByProjectID(ctx, obj.ID)
-	if err != nil {
-		return nil, err
+	if input.Loop.IsSet() && input.Loop.Value() != nil {
+		cueList.Loop = *input.Loop.Value()
 	}
-	pointers := make([]*models.CueList, len(cueLists))
-	for i := range cueLists {
-		pointers[i] = &cueLists[i]
+
+	if input.PlaybackMode.IsSet() && input.PlaybackMode.Value() != nil {
+		cueList.PlaybackMode = string(*input.PlaybackMode.Value())
 	}
-	return pointers, nil
-}
 
-// SceneBoards is the resolver for the sceneBoards field.
-func (r *projectResolver) SceneBoards(ctx context.Context, obj *models.Project) ([]*models.SceneBoard, error) {
-	var boards []models.SceneBoard
-	result := r.db.Where("project_id = ?", obj.ID).Order("created_at DESC").Find(&boards)
-	if result.Error != nil {
-		return nil, result.Error
+	if input.Tracking.IsSet() && input.Tracking.Value() != nil {
+		cueList.Tracking = *input.Tracking.Value()
 	}
-	pointers := make([]*models.SceneBoard, len(boards))
-	for i := range boards {
-		pointers[i] = &boards[i]
+
+	if err := r.CueListRepo.Update(ctx, cueList); err != nil {
+		return nil, err
 	}
-	return pointers, nil
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cueList)
+
+	return cueList, nil
 }
 
-// Users is the resolver for the users field.
-func (r *projectResolver) Users(ctx context.Context, obj *models.Project) ([]*models.ProjectUser, error) {
-	var users []models.ProjectUser
-	result := r.db.Where("project_id = ?", obj.ID).Find(&users)
-	if result.Error != nil {
-		return nil, result.Error
+// DeleteCueList is the resolver for the deleteCueList field.
+func (r *mutationResolver) DeleteCueList(ctx context.Context, id string) (bool, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, id)
+	if err != nil {
+		return false, err
 	}
-	pointers := make([]*models.ProjectUser, len(users))
-	for i := range users {
-		pointers[i] = &users[i]
+	if cueList == nil {
+		return false, fmt.Errorf("cue list not found: %s", id)
 	}
-	return pointers, nil
-}
+	r.snapshotBefore(ctx, cueList.ProjectID, snapshot.ReasonBeforeDelete)
 
-// User is the resolver for the user field.
-func (r *projectUserResolver) User(ctx context.Context, obj *models.ProjectUser) (*models.User, error) {
-	var user models.User
-	result := r.db.First(&user, "id = ?", obj.UserID)
-	if result.Error != nil {
-		return nil, result.Error
+	// Delete all cues first
+	if err := r.CueRepo.DeleteByCueListID(ctx, id); err != nil {
+		return false, err
 	}
-	return &user, nil
-}
-
-// Project is the resolver for the project field.
-func (r *projectUserResolver) Project(ctx context.Context, obj *models.ProjectUser) (*models.Project, error) {
-	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
-}
 
-// Role is the resolver for the role field.
-func (r *projectUserResolver) Role(ctx context.Context, obj *models.ProjectUser) (generated.ProjectRole, error) {
-	if obj.Role != "" {
-		return generated.ProjectRole(obj.Role), nil
+	// Delete the cue list
+	if err := r.CueListRepo.Delete(ctx, id); err != nil {
+		return false, err
 	}
-	return generated.ProjectRoleViewer, nil
-}
+	r.refreshTimecodeTriggers(ctx)
+	r.publishEntityChange(ctx, generated.EntityChangeActionDeleted, cueList)
 
-// JoinedAt is the resolver for the joinedAt field.
-func (r *projectUserResolver) JoinedAt(ctx context.Context, obj *models.ProjectUser) (string, error) {
-	return obj.JoinedAt.Format("2006-01-02T15:04:05.000Z"), nil
+	return true, nil
 }
 
-// Projects is the resolver for the projects field.
-func (r *queryResolver) Projects(ctx context.Context) ([]*models.Project, error) {
-	projects, err := r.ProjectRepo.FindAll(ctx)
+// DuplicateCueList is the resolver for the duplicateCueList field.
+func (r *mutationResolver) DuplicateCueList(ctx context.Context, id string) (*models.CueList, error) {
+	original, err := r.CueListRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	// Convert to pointers
-	result := make([]*models.Project, len(projects))
-	for i := range projects {
-		result[i] = &projects[i]
+	if original == nil {
+		return nil, fmt.Errorf("cue list not found: %s", id)
 	}
-	return result, nil
-}
 
-// Project is the resolver for the project field.
-func (r *queryResolver) Project(ctx context.Context, id string) (*models.Project, error) {
-	return r.ProjectRepo.FindByID(ctx, id)
-}
-
-// FixtureDefinitions is the resolver for the fixtureDefinitions field.
-func (r *queryResolver) FixtureDefinitions(ctx context.Context, filter *generated.FixtureDefinitionFilter) ([]*models.FixtureDefinition, error) {
-	defs, err := r.FixtureRepo.FindAllDefinitions(ctx)
+	result, err := r.copyCueList(ctx, original, original.ProjectID, original.Name+" (Copy)")
 	if err != nil {
 		return nil, err
 	}
-
-	// Apply filters if provided
-	var filtered []models.FixtureDefinition
-	for _, def := range defs {
-		// Apply manufacturer filter (case-insensitive partial match for autocomplete)
-		if filter != nil && filter.Manufacturer.IsSet() {
-			if mfr := filter.Manufacturer.Value(); mfr != nil && *mfr != "" {
-				if !strings.Contains(strings.ToLower(def.Manufacturer), strings.ToLower(*mfr)) {
-					continue
-				}
-			}
-		}
-		// Apply model filter (case-insensitive partial match for autocomplete)
-		if filter != nil && filter.Model.IsSet() {
-			if model := filter.Model.Value(); model != nil && *model != "" {
-				if !strings.Contains(strings.ToLower(def.Model), strings.ToLower(*model)) {
-					continue
-				}
-			}
-		}
-		// Apply type filter
-		if filter != nil && filter.Type.IsSet() {
-			if fixtureType := filter.Type.Value(); fixtureType != nil {
-				if def.Type != string(*fixtureType) {
-					continue
-				}
-			}
-		}
-		// Apply isBuiltIn filter
-		if filter != nil && filter.IsBuiltIn.IsSet() {
-			if isBuiltIn := filter.IsBuiltIn.Value(); isBuiltIn != nil {
-				if def.IsBuiltIn != *isBuiltIn {
-					continue
-				}
-			}
-		}
-		filtered = append(filtered, def)
-	}
-
-	// Convert to pointers
-	result := make([]*models.FixtureDefinition, len(filtered))
-	for i := range filtered {
-		result[i] = &filtered[i]
-	}
-	return result, nil
-}
-
-// FixtureDefinition is the resolver for the fixtureDefinition field.
-func (r *queryResolver) FixtureDefinition(ctx context.Context, id string) (*models.FixtureDefinition, error) {
-	return r.FixtureRepo.FindDefinitionByID(ctx, id)
+	return &result.CueList, nil
 }
 
-// FixtureInstances is the resolver for the fixtureInstances field.
-func (r *queryResolver) FixtureInstances(ctx context.Context, projectID string, page *int, perPage *int, filter *generated.FixtureFilterInput) (*generated.FixtureInstancePage, error) {
-	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+// CopyCueListToProject is the resolver for the copyCueListToProject field.
+func (r *mutationResolver) CopyCueListToProject(ctx context.Context, cueListID string, targetProjectID string, newName *string) (*generated.CueListCopyResult, error) {
+	original, err := r.CueListRepo.FindByID(ctx, cueListID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Apply pagination
-	pageNum := 1
-	pageSize := 50
-	if page != nil {
-		pageNum = *page
+	if original == nil {
+		return nil, fmt.Errorf("cue list not found: %s", cueListID)
 	}
-	if perPage != nil {
-		pageSize = *perPage
+	if err := r.findTargetProject(ctx, targetProjectID); err != nil {
+		return nil, err
 	}
 
-	total := len(fixtures)
-	start := (pageNum - 1) * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
+	name := original.Name
+	if newName != nil && *newName != "" {
+		name = *newName
 	}
+	return r.copyCueList(ctx, original, targetProjectID, name)
+}
 
-	// Convert to pointers
-	items := make([]*models.FixtureInstance, end-start)
-	for i := start; i < end; i++ {
-		items[i-start] = &fixtures[i]
+// BulkCreateCueLists is the resolver for the bulkCreateCueLists field.
+func (r *mutationResolver) BulkCreateCueLists(ctx context.Context, input generated.BulkCueListCreateInput) ([]*models.CueList, error) {
+	var createdCueLists []*models.CueList
+
+	for _, cueListInput := range input.CueLists {
+		cueList, err := r.CreateCueList(ctx, *cueListInput)
+		if err != nil {
+			return nil, err
+		}
+		createdCueLists = append(createdCueLists, cueList)
 	}
 
-	return &generated.FixtureInstancePage{
-		Fixtures: items,
-		Pagination: generated.PaginationInfo{
-			Total:   total,
-			Page:    pageNum,
-			PerPage: pageSize,
-			HasMore: end < total,
-		},
-	}, nil
+	return createdCueLists, nil
 }
 
-// FixtureInstance is the resolver for the fixtureInstance field.
-func (r *queryResolver) FixtureInstance(ctx context.Context, id string) (*models.FixtureInstance, error) {
-	return r.FixtureRepo.FindByID(ctx, id)
-}
+// BulkUpdateCueLists is the resolver for the bulkUpdateCueLists field.
+func (r *mutationResolver) BulkUpdateCueLists(ctx context.Context, input generated.BulkCueListUpdateInput) ([]*models.CueList, error) {
+	var updatedCueLists []*models.CueList
 
-// SearchFixtures is the resolver for the searchFixtures field.
-func (r *queryResolver) SearchFixtures(ctx context.Context, projectID string, query string, filter *generated.FixtureFilterInput, page *int, perPage *int) (*generated.FixtureInstancePage, error) {
-	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
-	if err != nil {
-		return nil, err
-	}
+	for _, item := range input.CueLists {
+		cueList, err := r.CueListRepo.FindByID(ctx, item.CueListID)
+		if err != nil {
+			return nil, err
+		}
+		if cueList == nil {
+			return nil, fmt.Errorf("cue list not found: %s", item.CueListID)
+		}
 
-	// Filter by search query (name, manufacturer, model)
-	var filtered []models.FixtureInstance
-	queryLower := strings.ToLower(query)
-	for _, f := range fixtures {
-		if strings.Contains(strings.ToLower(f.Name), queryLower) {
-			filtered = append(filtered, f)
-			continue
+		if item.Name.IsSet() && item.Name.Value() != nil {
+			cueList.Name = *item.Name.Value()
 		}
-		if f.Manufacturer != nil && strings.Contains(strings.ToLower(*f.Manufacturer), queryLower) {
-			filtered = append(filtered, f)
-			continue
+
+		if item.Description.IsSet() {
+			cueList.Description = item.Description.Value()
 		}
-		if f.Model != nil && strings.Contains(strings.ToLower(*f.Model), queryLower) {
-			filtered = append(filtered, f)
-			continue
+
+		if item.Loop.IsSet() && item.Loop.Value() != nil {
+			cueList.Loop = *item.Loop.Value()
+		}
+		if item.PlaybackMode.IsSet() && item.PlaybackMode.Value() != nil {
+			cueList.PlaybackMode = string(*item.PlaybackMode.Value())
+		}
+		if item.Tracking.IsSet() && item.Tracking.Value() != nil {
+			cueList.Tracking = *item.Tracking.Value()
 		}
-	}
 
-	// Apply pagination
-	pageNum := 1
-	pageSize := 50
-	if page != nil {
-		pageNum = *page
-	}
-	if perPage != nil {
-		pageSize = *perPage
-	}
+		if err := r.CueListRepo.Update(ctx, cueList); err != nil {
+			return nil, err
+		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cueList)
 
-	total := len(filtered)
-	start := (pageNum - 1) * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
+		updatedCueLists = append(updatedCueLists, cueList)
 	}
 
-	items := make([]*models.FixtureInstance, end-start)
-	for i := start; i < end; i++ {
-		items[i-start] = &filtered[i]
+	return updatedCueLists, nil
+}
+
+// BulkDeleteCueLists is the resolver for the bulkDeleteCueLists field.
+func (r *mutationResolver) BulkDeleteCueLists(ctx context.Context, cueListIds []string) (*generated.BulkDeleteResult, error) {
+	var deletedIds []string
+
+	for _, cueListID := range cueListIds {
+		_, err := r.DeleteCueList(ctx, cueListID)
+		if err != nil {
+			return nil, err
+		}
+		deletedIds = append(deletedIds, cueListID)
 	}
 
-	return &generated.FixtureInstancePage{
-		Fixtures: items,
-		Pagination: generated.PaginationInfo{
-			Total:   total,
-			Page:    pageNum,
-			PerPage: pageSize,
-			HasMore: end < total,
-		},
+	return &generated.BulkDeleteResult{
+		DeletedCount: len(deletedIds),
+		DeletedIds:   deletedIds,
 	}, nil
 }
 
-// ChannelMap is the resolver for the channelMap field.
-func (r *queryResolver) ChannelMap(ctx context.Context, projectID string, universe *int) (*generated.ChannelMapResult, error) {
-	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+// CreateCue is the resolver for the createCue field.
+func (r *mutationResolver) CreateCue(ctx context.Context, input generated.CreateCueInput) (*models.Cue, error) {
+	// Verify cue list exists
+	cueList, err := r.CueListRepo.FindByID(ctx, input.CueListID)
 	if err != nil {
 		return nil, err
 	}
+	if cueList == nil {
+		return nil, fmt.Errorf("cue list not found: %s", input.CueListID)
+	}
 
-	// Build fixtures per universe
-	universeFixtures := make(map[int][]*generated.ChannelMapFixture)
-	universeUsedChannels := make(map[int]int)
+	// Verify scene exists
+	scene, err := r.SceneRepo.FindByID(ctx, input.SceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene not found: %s", input.SceneID)
+	}
 
-	for _, f := range fixtures {
-		// Skip if filtering by universe and this fixture doesn't match
-		if universe != nil && f.Universe != *universe {
-			continue
-		}
+	name, err := r.cueNameForCreate(ctx, cueList, &input.CueNumber, input.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		channelCount := 1
-		if f.ChannelCount != nil {
-			channelCount = *f.ChannelCount
-		}
+	cue := &models.Cue{
+		Name:        name,
+		CueNumber:   input.CueNumber,
+		CueListID:   input.CueListID,
+		SceneID:     input.SceneID,
+		FadeInTime:  input.FadeInTime,
+		FadeOutTime: input.FadeOutTime,
+	}
 
-		fixtureType := generated.FixtureTypeOther
-		if f.Type != nil {
-			fixtureType = generated.FixtureType(*f.Type)
-		}
+	if input.FollowTime.IsSet() {
+		cue.FollowTime = input.FollowTime.Value()
+	}
 
-		fixture := &generated.ChannelMapFixture{
-			ID:           f.ID,
-			Name:         f.Name,
-			Type:         fixtureType,
-			StartChannel: f.StartChannel,
-			EndChannel:   f.StartChannel + channelCount - 1,
-			ChannelCount: channelCount,
+	if input.FollowQuantize.IsSet() {
+		cue.FollowQuantize = followQuantizeValue(input.FollowQuantize.Value())
+	}
+
+	if input.EasingType.IsSet() && input.EasingType.Value() != nil {
+		easingStr := string(*input.EasingType.Value())
+		cue.EasingType = &easingStr
+	}
+
+	if input.Notes.IsSet() {
+		cue.Notes = input.Notes.Value()
+	}
+
+	if input.SecondaryLabel.IsSet() {
+		cue.SecondaryLabel = input.SecondaryLabel.Value()
+	}
+
+	if input.Timecode.IsSet() {
+		if cue.Timecode, err = cueTimecodeValue(input.Timecode.Value()); err != nil {
+			return nil, err
 		}
-		universeFixtures[f.Universe] = append(universeFixtures[f.Universe], fixture)
-		universeUsedChannels[f.Universe] += channelCount
 	}
 
-	// Build universe outputs
-	var universes []*generated.UniverseChannelMap
-	for uni, fixtureList := range universeFixtures {
-		usedChannels := universeUsedChannels[uni]
-		universes = append(universes, &generated.UniverseChannelMap{
-			Universe:          uni,
-			Fixtures:          fixtureList,
-			ChannelUsage:      []*generated.ChannelUsage{}, // TODO: Add channel usage details
-			AvailableChannels: 512 - usedChannels,
-			UsedChannels:      usedChannels,
-		})
+	if input.Block.IsSet() && input.Block.Value() != nil {
+		cue.Block = *input.Block.Value()
 	}
 
-	return &generated.ChannelMapResult{
-		ProjectID: projectID,
-		Universes: universes,
-	}, nil
+	if input.MoveInBlack.IsSet() && input.MoveInBlack.Value() != nil {
+		cue.MoveInBlack = *input.MoveInBlack.Value()
+	}
+
+	if err := r.CueRepo.Create(ctx, cue); err != nil {
+		return nil, err
+	}
+	r.refreshTimecodeTriggers(ctx)
+
+	r.commitUndoCreate(ctx, cueList.ProjectID, "Create cue "+cue.Name, undoCue(cue.ID))
+	r.publishEntityChange(ctx, generated.EntityChangeActionCreated, cue)
+
+	return cue, nil
 }
 
-// SuggestChannelAssignment is the resolver for the suggestChannelAssignment field.
-func (r *queryResolver) SuggestChannelAssignment(ctx context.Context, input generated.ChannelAssignmentInput) (*generated.ChannelAssignmentSuggestion, error) {
-	// Get existing fixtures to find used channels
-	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, input.ProjectID)
+// UpdateCue is the resolver for the updateCue field.
+func (r *mutationResolver) UpdateCue(ctx context.Context, id string, input generated.CreateCueInput, expectedUpdatedAt *string) (*models.Cue, error) {
+	cue, err := r.CueRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if cue == nil {
+		return nil, fmt.Errorf("cue not found: %s", id)
+	}
+	if changedSince(cue.UpdatedAt, expectedUpdatedAt) {
+		return nil, cueConflict(ctx, cue)
+	}
 
-	// Build a map of used channels per universe
-	usedChannels := make(map[int]map[int]bool)
-	for _, f := range fixtures {
-		if usedChannels[f.Universe] == nil {
-			usedChannels[f.Universe] = make(map[int]bool)
-		}
-		channelCount := 1
-		if f.ChannelCount != nil {
-			channelCount = *f.ChannelCount
+	projectID, err := r.cueProjectID(ctx, cue.CueListID)
+	if err != nil {
+		return nil, err
+	}
+	undo, err := r.beginUndo(ctx, projectID, "Update cue "+cue.Name, undoCue(id))
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify scene exists if being changed
+	if input.SceneID != cue.SceneID {
+		scene, err := r.SceneRepo.FindByID(ctx, input.SceneID)
+		if err != nil {
+			return nil, err
 		}
-		for ch := 0; ch < channelCount; ch++ {
-			usedChannels[f.Universe][f.StartChannel+ch] = true
+		if scene == nil {
+			return nil, fmt.Errorf("scene not found: %s", input.SceneID)
 		}
 	}
 
-	// Find suggested assignments for each fixture spec
-	var assignments []*generated.FixtureChannelAssignment
-	universe := 1
-	if input.Universe.IsSet() && input.Universe.Value() != nil {
-		universe = *input.Universe.Value()
+	// Update fields
+	cue.Name = input.Name
+	cue.CueNumber = input.CueNumber
+	cue.SceneID = input.SceneID
+	cue.FadeInTime = input.FadeInTime
+	cue.FadeOutTime = input.FadeOutTime
+
+	if input.FollowTime.IsSet() {
+		cue.FollowTime = input.FollowTime.Value()
 	}
-	startChannel := 1
-	if input.StartingChannel.IsSet() && input.StartingChannel.Value() != nil {
-		startChannel = *input.StartingChannel.Value()
+
+	if input.FollowQuantize.IsSet() {
+		cue.FollowQuantize = followQuantizeValue(input.FollowQuantize.Value())
 	}
 
-	totalChannelsNeeded := 0
-	for _, spec := range input.FixtureSpecs {
-		channelCount := 1
-		if spec.ChannelCount.IsSet() && spec.ChannelCount.Value() != nil {
-			channelCount = *spec.ChannelCount.Value()
+	if input.EasingType.IsSet() && input.EasingType.Value() != nil {
+		easingStr := string(*input.EasingType.Value())
+		cue.EasingType = &easingStr
+	}
+
+	if input.Notes.IsSet() {
+		cue.Notes = input.Notes.Value()
+	}
+
+	if input.SecondaryLabel.IsSet() {
+		cue.SecondaryLabel = input.SecondaryLabel.Value()
+	}
+
+	if input.Timecode.IsSet() {
+		if cue.Timecode, err = cueTimecodeValue(input.Timecode.Value()); err != nil {
+			return nil, err
 		}
-		totalChannelsNeeded += channelCount
+	}
 
-		// Find next available block of channels
-		for {
-			available := true
-			for ch := 0; ch < channelCount; ch++ {
-				if startChannel+ch > 512 {
-					// Move to next universe
-					universe++
-					startChannel = 1
-					if usedChannels[universe] == nil {
-						usedChannels[universe] = make(map[int]bool)
-					}
-					available = false
-					break
-				}
-				if usedChannels[universe][startChannel+ch] {
-					available = false
-					break
-				}
-			}
-			if available {
-				break
-			}
-			startChannel++
-		}
-
-		endChannel := startChannel + channelCount - 1
-		channelRange := fmt.Sprintf("%d-%d", startChannel, endChannel)
-
-		// Mode is Omittable[*string], need to extract the *string
-		var mode *string
-		if spec.Mode.IsSet() {
-			mode = spec.Mode.Value()
-		}
+	if input.Block.IsSet() && input.Block.Value() != nil {
+		cue.Block = *input.Block.Value()
+	}
 
-		assignments = append(assignments, &generated.FixtureChannelAssignment{
-			FixtureName:  spec.Name,
-			Manufacturer: spec.Manufacturer,
-			Model:        spec.Model,
-			Mode:         mode,
-			StartChannel: startChannel,
-			EndChannel:   endChannel,
-			ChannelCount: channelCount,
-			ChannelRange: channelRange,
-		})
+	if input.MoveInBlack.IsSet() && input.MoveInBlack.Value() != nil {
+		cue.MoveInBlack = *input.MoveInBlack.Value()
+	}
 
-		// Mark these channels as used
-		for ch := 0; ch < channelCount; ch++ {
-			usedChannels[universe][startChannel+ch] = true
-		}
-		startChannel += channelCount
+	err = r.writeUnchanged(ctx, generated.ProjectEntityTypeCue, id, cue.UpdatedAt, expectedUpdatedAt, func(tx *Resolver) error {
+		return tx.CueRepo.Update(ctx, cue)
+	})
+	if err != nil {
+		return nil, err
 	}
+	r.refreshTimecodeTriggers(ctx)
 
-	// Calculate available channels remaining
-	availableRemaining := 512 - len(usedChannels[universe])
+	r.commitUndo(ctx, undo)
+	r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cue)
 
-	return &generated.ChannelAssignmentSuggestion{
-		Universe:                   universe,
-		Assignments:                assignments,
-		TotalChannelsNeeded:        totalChannelsNeeded,
-		AvailableChannelsRemaining: availableRemaining,
-	}, nil
+	return cue, nil
 }
 
-// Scenes is the resolver for the scenes field.
-func (r *queryResolver) Scenes(ctx context.Context, projectID string, page *int, perPage *int, filter *generated.SceneFilterInput, sortBy *generated.SceneSortField) (*generated.ScenePage, error) {
-	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
-	if err != nil {
-		return nil, err
-	}
+// UpdateCueValues is the resolver for the updateCueValues field.
+func (r *mutationResolver) UpdateCueValues(ctx context.Context, cueID string, fixtureValues []*generated.FixtureValueInput, cueOnly *bool) (*models.Cue, error) {
+	return r.updateCueValues(ctx, cueID, fixtureValues, cueOnly != nil && *cueOnly)
+}
 
-	// Apply pagination
-	pageNum := 1
-	pageSize := 50
-	if page != nil {
-		pageNum = *page
+// DeleteCue is the resolver for the deleteCue field.
+func (r *mutationResolver) DeleteCue(ctx context.Context, id string) (bool, error) {
+	cue, err := r.CueRepo.FindByID(ctx, id)
+	if err != nil {
+		return false, err
 	}
-	if perPage != nil {
-		pageSize = *perPage
+	if cue == nil {
+		return false, fmt.Errorf("cue not found: %s", id)
 	}
 
-	total := len(scenes)
-	start := (pageNum - 1) * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
+	projectID, err := r.cueProjectID(ctx, cue.CueListID)
+	if err != nil {
+		return false, err
 	}
-	if end > total {
-		end = total
+	undo, err := r.beginUndo(ctx, projectID, "Delete cue "+cue.Name, undoCue(id))
+	if err != nil {
+		return false, err
 	}
 
-	// Convert to SceneSummary
-	items := make([]*generated.SceneSummary, end-start)
-	for i := start; i < end; i++ {
-		scene := &scenes[i]
-		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
-		items[i-start] = &generated.SceneSummary{
-			ID:           scene.ID,
-			Name:         scene.Name,
-			Description:  scene.Description,
-			FixtureCount: int(fixtureCount),
-			CreatedAt:    scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-			UpdatedAt:    scene.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
-		}
+	if err := r.CueRepo.Delete(ctx, id); err != nil {
+		return false, err
 	}
+	r.refreshTimecodeTriggers(ctx)
 
-	return &generated.ScenePage{
-		Scenes: items,
-		Pagination: generated.PaginationInfo{
-			Total:   total,
-			Page:    pageNum,
-			PerPage: pageSize,
-			HasMore: end < total,
-		},
-	}, nil
-}
+	r.commitUndo(ctx, undo)
+	r.publishEntityChange(ctx, generated.EntityChangeActionDeleted, cue)
 
-// Scene is the resolver for the scene field.
-func (r *queryResolver) Scene(ctx context.Context, id string, includeFixtureValues *bool) (*models.Scene, error) {
-	return r.SceneRepo.FindByID(ctx, id)
+	return true, nil
 }
 
-// SceneFixtures is the resolver for the sceneFixtures field.
-func (r *queryResolver) SceneFixtures(ctx context.Context, sceneID string) ([]*generated.SceneFixtureSummary, error) {
-	// Get fixture values for the scene
-	var fixtureValues []models.FixtureValue
-	result := r.db.Where("scene_id = ?", sceneID).Find(&fixtureValues)
-	if result.Error != nil {
-		return nil, result.Error
+// ReorderCues is the resolver for the reorderCues field.
+func (r *mutationResolver) ReorderCues(ctx context.Context, cueListID string, cueOrders []*generated.CueOrderInput) (bool, error) {
+	// Verify cue list exists
+	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
+	if err != nil {
+		return false, err
+	}
+	if cueList == nil {
+		return false, fmt.Errorf("cue list not found: %s", cueListID)
 	}
 
-	summaries := make([]*generated.SceneFixtureSummary, 0, len(fixtureValues))
-	for _, fv := range fixtureValues {
-		fixture, err := r.FixtureRepo.FindByID(ctx, fv.FixtureID)
-		if err != nil || fixture == nil {
-			continue
+	// Update each cue's number
+	for _, order := range cueOrders {
+		cue, err := r.CueRepo.FindByID(ctx, order.CueID)
+		if err != nil {
+			return false, err
 		}
-
-		fixtureType := generated.FixtureTypeOther
-		if fixture.Type != nil {
-			fixtureType = generated.FixtureType(*fixture.Type)
+		if cue == nil {
+			return false, fmt.Errorf("cue not found: %s", order.CueID)
 		}
 
-		summaries = append(summaries, &generated.SceneFixtureSummary{
-			FixtureID:   fixture.ID,
-			FixtureName: fixture.Name,
-			FixtureType: fixtureType,
-		})
+		cue.CueNumber = order.CueNumber
+		if err := r.CueRepo.Update(ctx, cue); err != nil {
+			return false, err
+		}
+		r.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cue)
 	}
+	r.refreshTimecodeTriggers(ctx)
 
-	return summaries, nil
+	return true, nil
 }
 
-// SearchScenes is the resolver for the searchScenes field.
-func (r *queryResolver) SearchScenes(ctx context.Context, projectID string, query string, filter *generated.SceneFilterInput, page *int, perPage *int) (*generated.ScenePage, error) {
-	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
+// RenumberCues is the resolver for the renumberCues field.
+func (r *mutationResolver) RenumberCues(ctx context.Context, cueListID string, startNumber *float64, increment *float64) ([]*models.Cue, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Filter by search query (name, description)
-	var filtered []models.Scene
-	queryLower := strings.ToLower(query)
-	for _, s := range scenes {
-		if strings.Contains(strings.ToLower(s.Name), queryLower) {
-			filtered = append(filtered, s)
-			continue
-		}
-		if s.Description != nil && strings.Contains(strings.ToLower(*s.Description), queryLower) {
-			filtered = append(filtered, s)
-			continue
-		}
-	}
-
-	// Apply pagination
-	pageNum := 1
-	pageSize := 50
-	if page != nil {
-		pageNum = *page
-	}
-	if perPage != nil {
-		pageSize = *perPage
-	}
-
-	total := len(filtered)
-	start := (pageNum - 1) * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
+	if cueList == nil {
+		return nil, fmt.Errorf("cue list not found: %s", cueListID)
 	}
-	if end > total {
-		end = total
+	start, step := 1.0, 1.0
+	if startNumber != nil {
+		start = *startNumber
 	}
-
-	items := make([]*generated.SceneSummary, end-start)
-	for i := start; i < end; i++ {
-		scene := &filtered[i]
-		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
-		items[i-start] = &generated.SceneSummary{
-			ID:           scene.ID,
-			Name:         scene.Name,
-			Description:  scene.Description,
-			FixtureCount: int(fixtureCount),
-			CreatedAt:    scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-			UpdatedAt:    scene.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
-		}
+	if increment != nil {
+		step = *increment
 	}
-
-	return &generated.ScenePage{
-		Scenes: items,
-		Pagination: generated.PaginationInfo{
-			Total:   total,
-			Page:    pageNum,
-			PerPage: pageSize,
-			HasMore: end < total,
-		},
-	}, nil
+	return r.renumberCues(ctx, cueList, start, step)
 }
 
-// SceneBoards is the resolver for the sceneBoards field.
-func (r *queryResolver) SceneBoards(ctx context.Context, projectID string) ([]*models.SceneBoard, error) {
-	var boards []models.SceneBoard
-	result := r.db.Where("project_id = ?", projectID).Order("created_at DESC").Find(&boards)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	// Convert to pointers
-	pointers := make([]*models.SceneBoard, len(boards))
-	for i := range boards {
-		pointers[i] = &boards[i]
-	}
-	return pointers, nil
+// InsertCueBetween is the resolver for the insertCueBetween field.
+func (r *mutationResolver) InsertCueBetween(ctx context.Context, cueListID string, afterCueID *string, input generated.InsertCueInput) (*models.Cue, error) {
+	return r.insertCueBetween(ctx, cueListID, afterCueID, input)
 }
 
-// SceneBoard is the resolver for the sceneBoard field.
-func (r *queryResolver) SceneBoard(ctx context.Context, id string) (*models.SceneBoard, error) {
-	var board models.SceneBoard
-	result := r.db.Preload("Buttons").First(&board, "id = ?", id)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &board, nil
-}
+// BulkCreateCues is the resolver for the bulkCreateCues field.
+func (r *mutationResolver) BulkCreateCues(ctx context.Context, input generated.BulkCueCreateInput) ([]*models.Cue, error) {
+	var createdCues []*models.Cue
 
-// SceneBoardButton is the resolver for the sceneBoardButton field.
-func (r *queryResolver) SceneBoardButton(ctx context.Context, id string) (*models.SceneBoardButton, error) {
-	var button models.SceneBoardButton
-	result := r.db.First(&button, "id = ?", id)
-	if result.Error != nil {
-		return nil, result.Error
+	for _, cueInput := range input.Cues {
+		cue, err := r.CreateCue(ctx, *cueInput)
+		if err != nil {
+			return nil, err
+		}
+		createdCues = append(createdCues, cue)
 	}
-	return &button, nil
+
+	return createdCues, nil
 }
 
-// FixtureUsage is the resolver for the fixtureUsage field.
-func (r *queryResolver) FixtureUsage(ctx context.Context, fixtureID string) (*generated.FixtureUsage, error) {
-	// Get fixture
-	fixture, err := r.FixtureRepo.FindByID(ctx, fixtureID)
+// BulkUpdateCues is the resolver for the bulkUpdateCues field.
+func (r *mutationResolver) BulkUpdateCues(ctx context.Context, input generated.BulkCueUpdateInput) ([]*models.Cue, error) {
+	undos, err := r.beginBulkCueUndo(ctx, input.CueIds)
 	if err != nil {
 		return nil, err
 	}
-	if fixture == nil {
-		return nil, fmt.Errorf("fixture not found: %s", fixtureID)
-	}
 
-	// Find scenes that use this fixture
-	var fixtureValues []models.FixtureValue
-	r.db.Where("fixture_id = ?", fixtureID).Find(&fixtureValues)
+	var updatedCues []*models.Cue
+	err = r.transaction(ctx, func(txResolver *Resolver) error {
+		for _, cueID := range input.CueIds {
+			cue, err := txResolver.CueRepo.FindByID(ctx, cueID)
+			if err != nil {
+				return err
+			}
+			if cue == nil {
+				return fmt.Errorf("cue not found: %s", cueID)
+			}
 
-	sceneIDs := make([]string, 0, len(fixtureValues))
-	for _, fv := range fixtureValues {
-		sceneIDs = append(sceneIDs, fv.SceneID)
-	}
+			// Update fade in time if provided
+			if input.FadeInTime.IsSet() && input.FadeInTime.Value() != nil {
+				cue.FadeInTime = *input.FadeInTime.Value()
+			}
 
-	// Get unique scenes and convert to SceneSummary
-	var scenes []models.Scene
-	if len(sceneIDs) > 0 {
-		r.db.Where("id IN ?", sceneIDs).Find(&scenes)
-	}
+			// Update fade out time if provided
+			if input.FadeOutTime.IsSet() && input.FadeOutTime.Value() != nil {
+				cue.FadeOutTime = *input.FadeOutTime.Value()
+			}
 
-	sceneSummaries := make([]*generated.SceneSummary, len(scenes))
-	for i, scene := range scenes {
-		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
-		sceneSummaries[i] = &generated.SceneSummary{
-			ID:           scene.ID,
-			Name:         scene.Name,
-			Description:  scene.Description,
-			FixtureCount: int(fixtureCount),
-			CreatedAt:    scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-			UpdatedAt:    scene.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
-		}
-	}
+			// Update follow time if provided
+			if input.FollowTime.IsSet() {
+				cue.FollowTime = input.FollowTime.Value()
+			}
 
-	// Find cues that use this fixture (through scenes)
-	var cueUsageSummaries []*generated.CueUsageSummary
-	if len(sceneIDs) > 0 {
-		var cues []models.Cue
-		r.db.Where("scene_id IN ?", sceneIDs).Find(&cues)
-		for _, cue := range cues {
-			cueList, _ := r.CueListRepo.FindByID(ctx, cue.CueListID)
-			cueListName := ""
-			if cueList != nil {
-				cueListName = cueList.Name
+			// Update follow quantize if provided
+			if input.FollowQuantize.IsSet() {
+				cue.FollowQuantize = followQuantizeValue(input.FollowQuantize.Value())
 			}
-			cueUsageSummaries = append(cueUsageSummaries, &generated.CueUsageSummary{
-				CueID:       cue.ID,
-				CueNumber:   cue.CueNumber,
-				CueName:     cue.Name,
-				CueListID:   cue.CueListID,
-				CueListName: cueListName,
-			})
-		}
-	}
 
-	return &generated.FixtureUsage{
-		FixtureID:   fixture.ID,
-		FixtureName: fixture.Name,
-		Scenes:      sceneSummaries,
-		Cues:        cueUsageSummaries,
-	}, nil
-}
+			// Update easing type if provided
+			if input.EasingType.IsSet() && input.EasingType.Value() != nil {
+				easingStr := string(*input.EasingType.Value())
+				cue.EasingType = &easingStr
+			}
 
-// SceneUsage is the resolver for the sceneUsage field.
-func (r *queryResolver) SceneUsage(ctx context.Context, sceneID string) (*generated.SceneUsage, error) {
-	// Get scene
-	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
+			if input.Block.IsSet() && input.Block.Value() != nil {
+				cue.Block = *input.Block.Value()
+			}
+
+			if input.MoveInBlack.IsSet() && input.MoveInBlack.Value() != nil {
+				cue.MoveInBlack = *input.MoveInBlack.Value()
+			}
+
+			if err := txResolver.CueRepo.Update(ctx, cue); err != nil {
+				return err
+			}
+			txResolver.publishEntityChange(ctx, generated.EntityChangeActionUpdated, cue)
+
+			updatedCues = append(updatedCues, cue)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if scene == nil {
-		return nil, fmt.Errorf("scene not found: %s", sceneID)
+	r.refreshTimecodeTriggers(ctx)
+	for _, undo := range undos {
+		r.commitUndo(ctx, undo)
 	}
 
-	// Find cues that use this scene
-	var cues []models.Cue
-	r.db.Where("scene_id = ?", sceneID).Find(&cues)
+	return updatedCues, nil
+}
 
-	// Convert to CueUsageSummary
-	cueUsageSummaries := make([]*generated.CueUsageSummary, 0, len(cues))
-	for _, cue := range cues {
-		cueList, _ := r.CueListRepo.FindByID(ctx, cue.CueListID)
-		cueListName := ""
-		if cueList != nil {
-			cueListName = cueList.Name
+// BulkDeleteCues is the resolver for the bulkDeleteCues field.
+func (r *mutationResolver) BulkDeleteCues(ctx context.Context, cueIds []string) (*generated.BulkDeleteResult, error) {
+	var deletedIds []string
+
+	for _, cueID := range cueIds {
+		_, err := r.DeleteCue(ctx, cueID)
+		if err != nil {
+			return nil, err
 		}
-		cueUsageSummaries = append(cueUsageSummaries, &generated.CueUsageSummary{
-			CueID:       cue.ID,
-			CueNumber:   cue.CueNumber,
-			CueName:     cue.Name,
-			CueListID:   cue.CueListID,
-			CueListName: cueListName,
-		})
+		deletedIds = append(deletedIds, cueID)
 	}
 
-	return &generated.SceneUsage{
-		SceneID:   scene.ID,
-		SceneName: scene.Name,
-		Cues:      cueUsageSummaries,
+	return &generated.BulkDeleteResult{
+		DeletedCount: len(deletedIds),
+		DeletedIds:   deletedIds,
 	}, nil
 }
 
-// CompareScenes is the resolver for the compareScenes field.
-func (r *queryResolver) CompareScenes(ctx context.Context, sceneID1 string, sceneID2 string) (*generated.SceneComparison, error) {
-	// Get scenes
-	scene1, err := r.SceneRepo.FindByID(ctx, sceneID1)
-	if err != nil {
-		return nil, err
-	}
-	scene2, err := r.SceneRepo.FindByID(ctx, sceneID2)
+// StartPreviewSession is the resolver for the startPreviewSession field.
+func (r *mutationResolver) StartPreviewSession(ctx context.Context, projectID string, blind *bool, previewOutputs []*generated.PreviewOutputInput, previewUniverseOffset *int, visualizerAddress *string) (*models.PreviewSession, error) {
+	session, err := r.startPreviewSession(ctx, projectID, blind != nil && *blind, previewOutputs, previewUniverseOffset, visualizerAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get fixture values for each scene
-	var values1, values2 []models.FixtureValue
-	r.db.Where("scene_id = ?", sceneID1).Find(&values1)
-	r.db.Where("scene_id = ?", sceneID2).Find(&values2)
-
-	// Build maps for comparison
-	fixtures1 := make(map[string]models.FixtureValue)
-	fixtures2 := make(map[string]models.FixtureValue)
+	// Convert to models.PreviewSession and persist to database
+	var userID string
+	if session.UserID != nil {
+		userID = *session.UserID
+	}
 
-	for _, fv := range values1 {
-		fixtures1[fv.FixtureID] = fv
+	dbSession := &models.PreviewSession{
+		ID:        session.ID,
+		ProjectID: session.ProjectID,
+		UserID:    userID,
+		IsActive:  session.IsActive,
+		Blind:     session.Blind,
+		CreatedAt: session.CreatedAt,
 	}
-	for _, fv := range values2 {
-		fixtures2[fv.FixtureID] = fv
+
+	// Persist to database so it can be queried
+	if err := r.db.Create(dbSession).Error; err != nil {
+		// Rollback in-memory session if database creation fails
+		_, _ = r.PreviewService.CancelSession(ctx, session.ID)
+		return nil, err
 	}
 
-	// Find differences
-	var differences []*generated.SceneDifference
-	identicalCount := 0
-	differentCount := 0
+	return dbSession, nil
+}
 
-	for fixtureID, fv1 := range fixtures1 {
-		fixture, _ := r.FixtureRepo.FindByID(ctx, fixtureID)
-		fixtureName := ""
-		if fixture != nil {
-			fixtureName = fixture.Name
-		}
+// CommitPreviewSession is the resolver for the commitPreviewSession field.
+func (r *mutationResolver) CommitPreviewSession(ctx context.Context, sessionID string) (bool, error) {
+	return r.commitPreviewSession(ctx, sessionID)
+}
 
-		if fv2, ok := fixtures2[fixtureID]; ok {
-			// Fixture in both scenes - compare semantically, not by JSON string
-			if !sparseChannelsEqual(fv1.Channels, fv2.Channels) {
-				vals1 := sparseChannelsToDenseArray(fv1.Channels)
-				vals2 := sparseChannelsToDenseArray(fv2.Channels)
-				differences = append(differences, &generated.SceneDifference{
-					FixtureID:      fixtureID,
-					FixtureName:    fixtureName,
-					DifferenceType: generated.DifferenceTypeValuesChanged,
-					Scene1Values:   vals1,
-					Scene2Values:   vals2,
-				})
-				differentCount++
-			} else {
-				identicalCount++
-			}
-		} else {
-			// Fixture only in scene1
-			vals1 := sparseChannelsToDenseArray(fv1.Channels)
-			differences = append(differences, &generated.SceneDifference{
-				FixtureID:      fixtureID,
-				FixtureName:    fixtureName,
-				DifferenceType: generated.DifferenceTypeOnlyInScene1,
-				Scene1Values:   vals1,
-			})
-			differentCount++
-		}
+// CancelPreviewSession is the resolver for the cancelPreviewSession field.
+func (r *mutationResolver) CancelPreviewSession(ctx context.Context, sessionID string) (bool, error) {
+	success, err := r.PreviewService.CancelSession(ctx, sessionID)
+	if err != nil {
+		return false, err
 	}
 
-	for fixtureID, fv2 := range fixtures2 {
-		if _, ok := fixtures1[fixtureID]; !ok {
-			// Fixture only in scene2
-			fixture, _ := r.FixtureRepo.FindByID(ctx, fixtureID)
-			fixtureName := ""
-			if fixture != nil {
-				fixtureName = fixture.Name
-			}
-			vals2 := sparseChannelsToDenseArray(fv2.Channels)
-			differences = append(differences, &generated.SceneDifference{
-				FixtureID:      fixtureID,
-				FixtureName:    fixtureName,
-				DifferenceType: generated.DifferenceTypeOnlyInScene2,
-				Scene2Values:   vals2,
-			})
-			differentCount++
+	// Also delete from database
+	if success {
+		if err := r.db.Where("id = ?", sessionID).Delete(&models.PreviewSession{}).Error; err != nil {
+			return false, err
 		}
 	}
 
-	// Convert scenes to SceneSummary
-	fixtureCount1, _ := r.SceneRepo.CountFixtures(ctx, scene1.ID)
-	fixtureCount2, _ := r.SceneRepo.CountFixtures(ctx, scene2.ID)
-
-	scene1Summary := generated.SceneSummary{
-		ID:           scene1.ID,
-		Name:         scene1.Name,
-		Description:  scene1.Description,
-		FixtureCount: int(fixtureCount1),
-		CreatedAt:    scene1.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-		UpdatedAt:    scene1.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
-	}
+	return success, nil
+}
 
-	scene2Summary := generated.SceneSummary{
-		ID:           scene2.ID,
-		Name:         scene2.Name,
-		Description:  scene2.Description,
-		FixtureCount: int(fixtureCount2),
-		CreatedAt:    scene2.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-		UpdatedAt:    scene2.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
-	}
+// UpdatePreviewChannel is the resolver for the updatePreviewChannel field.
+func (r *mutationResolver) UpdatePreviewChannel(ctx context.Context, sessionID string, fixtureID string, channelIndex int, value int) (bool, error) {
+	return r.PreviewService.UpdateChannelValue(ctx, sessionID, fixtureID, channelIndex, value)
+}
 
-	return &generated.SceneComparison{
-		Scene1:                scene1Summary,
-		Scene2:                scene2Summary,
-		Differences:           differences,
-		IdenticalFixtureCount: identicalCount,
-		DifferentFixtureCount: differentCount,
-	}, nil
+// InitializePreviewWithScene is the resolver for the initializePreviewWithScene field.
+func (r *mutationResolver) InitializePreviewWithScene(ctx context.Context, sessionID string, sceneID string) (bool, error) {
+	return r.PreviewService.InitializeWithScene(ctx, sessionID, sceneID)
 }
 
-// CueLists is the resolver for the cueLists field.
-func (r *queryResolver) CueLists(ctx context.Context, projectID string) ([]*generated.CueListSummary, error) {
-	cueLists, err := r.CueListRepo.FindByProjectID(ctx, projectID)
+// SetChannelValue is the resolver for the setChannelValue field.
+func (r *mutationResolver) SetChannelValue(ctx context.Context, universe int, channel int, value int) (bool, error) {
+	// Clamp value to 0-255
+	if value < 0 {
+		value = 0
+	}
+	if value > 255 {
+		value = 255
+	}
+	value, err := r.checkProgrammerValue(ctx, universe, channel, value)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
+	// DMX service expects 1-indexed universe and channel
+	r.DMXService.SetProgrammerValue(universe, channel, byte(value))
+	r.PlaybackService.RecordEvent(playback.Event{Type: playback.EventSetChannel, Universe: universe, Channel: channel, Value: value})
+	r.replicateProgrammer()
+	return true, nil
+}
 
-	result := make([]*generated.CueListSummary, len(cueLists))
-	for i, cl := range cueLists {
-		cueCount, _ := r.CueListRepo.CountCues(ctx, cl.ID)
-		// Calculate total duration
-		cues, _ := r.CueListRepo.GetCues(ctx, cl.ID)
-		var totalDuration float64
-		for _, cue := range cues {
-			totalDuration += cue.FadeInTime + cue.FadeOutTime
-			if cue.FollowTime != nil {
-				totalDuration += *cue.FollowTime
-			}
-		}
-		result[i] = &generated.CueListSummary{
-			ID:            cl.ID,
-			Name:          cl.Name,
-			Description:   cl.Description,
-			CueCount:      int(cueCount),
-			TotalDuration: totalDuration,
-			Loop:          cl.Loop,
-			CreatedAt:     cl.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
-		}
-	}
-	return result, nil
+// SetFixtureColor is the resolver for the setFixtureColor field.
+func (r *mutationResolver) SetFixtureColor(ctx context.Context, fixtureIds []string, color generated.ColorInput, cell *int) ([]*generated.FixtureColorValues, error) {
+	return r.setFixtureColor(ctx, fixtureIds, color, cell)
 }
 
-// CueList is the resolver for the cueList field.
-func (r *queryResolver) CueList(ctx context.Context, id string, page *int, perPage *int, includeSceneDetails *bool) (*models.CueList, error) {
-	return r.CueListRepo.FindByID(ctx, id)
+// ClearProgrammer is the resolver for the clearProgrammer field.
+func (r *mutationResolver) ClearProgrammer(ctx context.Context) (bool, error) {
+	cleared := r.DMXService.ClearProgrammer()
+	r.replicateProgrammer()
+	return cleared, nil
 }
 
-// CueListPlaybackStatus is the resolver for the cueListPlaybackStatus field.
-func (r *queryResolver) CueListPlaybackStatus(ctx context.Context, cueListID string) (*generated.CueListPlaybackStatus, error) {
-	// Get the current playback status from the PlaybackService
-	status := r.PlaybackService.GetFormattedStatus(cueListID)
+// RecordProgrammerToScene is the resolver for the recordProgrammerToScene field.
+func (r *mutationResolver) RecordProgrammerToScene(ctx context.Context, projectID string, sceneID *string, name *string, clear *bool) (*models.Scene, error) {
+	return r.recordProgrammerToScene(ctx, projectID, sceneID, name, clear != nil && *clear)
+}
 
-	// Convert playback service status to generated GraphQL type
-	fadeProgress := status.FadeProgress
-	gqlStatus := &generated.CueListPlaybackStatus{
-		CueListID:       status.CueListID,
-		CurrentCueIndex: status.CurrentCueIndex,
-		IsPlaying:       status.IsPlaying,
-		IsFading:        status.IsFading,
-		FadeProgress:    &fadeProgress,
-		LastUpdated:     status.LastUpdated,
+// OverrideDmxChannel is the resolver for the overrideDmxChannel field.
+func (r *mutationResolver) OverrideDmxChannel(ctx context.Context, universe int, channel int, value int, ttlSeconds float64) (bool, error) {
+	if value < 0 || value > 255 {
+		return false, fmt.Errorf("invalid DMX value: %d", value)
+	}
+	ttl := time.Duration(ttlSeconds * float64(time.Second))
+	if err := r.DMXService.SetTimedOverride(universe, channel, byte(value), ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CaptureDmxTraffic is the resolver for the captureDmxTraffic field.
+func (r *mutationResolver) CaptureDmxTraffic(ctx context.Context, universe *int, seconds float64) (*generated.DmxCaptureResult, error) {
+	duration := time.Duration(seconds * float64(time.Second))
+	if duration <= 0 || duration > dmx.MaxCaptureDuration {
+		return nil, fmt.Errorf("capture duration must be between 0 and %.0f seconds", dmx.MaxCaptureDuration.Seconds())
 	}
 
-	// Convert current cue if present
-	if status.CurrentCue != nil {
-		gqlStatus.CurrentCue = &models.Cue{
-			ID:          status.CurrentCue.ID,
-			Name:        status.CurrentCue.Name,
-			CueNumber:   status.CurrentCue.CueNumber,
-			FadeInTime:  status.CurrentCue.FadeInTime,
-			FadeOutTime: status.CurrentCue.FadeOutTime,
-			FollowTime:  status.CurrentCue.FollowTime,
+	captureUniverse := 0
+	if universe != nil {
+		if *universe < 1 || *universe > dmx.MaxUniverses {
+			return nil, fmt.Errorf("invalid universe: %d", *universe)
 		}
+		captureUniverse = *universe
 	}
 
-	return gqlStatus, nil
-}
+	if !r.DMXService.IsEnabled() {
+		return nil, fmt.Errorf("cannot capture DMX traffic: Art-Net output is disabled")
+	}
 
-// GlobalPlaybackStatus is the resolver for the globalPlaybackStatus field.
-func (r *queryResolver) GlobalPlaybackStatus(ctx context.Context) (*generated.GlobalPlaybackStatus, error) {
-	status := r.PlaybackService.GetGlobalPlaybackStatus(ctx)
+	capture := r.DMXService.StartCapture(captureUniverse)
+	timer := time.NewTimer(duration)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+	}
+	r.DMXService.StopCapture(capture)
 
-	fadeProgress := status.FadeProgress
-	return &generated.GlobalPlaybackStatus{
-		IsPlaying:       status.IsPlaying,
-		IsFading:        status.IsFading,
-		CueListID:       status.CueListID,
-		CueListName:     status.CueListName,
-		CurrentCueIndex: status.CurrentCueIndex,
-		CueCount:        status.CueCount,
-		CurrentCueName:  status.CurrentCueName,
-		FadeProgress:    &fadeProgress,
-		LastUpdated:     status.LastUpdated,
+	content, err := capture.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build capture artifact: %w", err)
+	}
+
+	return &generated.DmxCaptureResult{
+		Universe:        universe,
+		StartedAt:       capture.StartedAt().Format("2006-01-02T15:04:05.000Z"),
+		EndedAt:         capture.EndedAt().Format("2006-01-02T15:04:05.000Z"),
+		DurationSeconds: capture.EndedAt().Sub(capture.StartedAt()).Seconds(),
+		PacketCount:     len(capture.Packets()),
+		DroppedCount:    capture.Dropped(),
+		CaptureContent:  content,
 	}, nil
 }
 
-// Cue is the resolver for the cue field.
-func (r *queryResolver) Cue(ctx context.Context, id string) (*models.Cue, error) {
-	return r.CueRepo.FindByID(ctx, id)
+// StartDmxRecording is the resolver for the startDmxRecording field.
+func (r *mutationResolver) StartDmxRecording(ctx context.Context) (*generated.DmxRecordingStatus, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return nil, err
+	}
+	status, err := service.StartRecording()
+	if err != nil {
+		return nil, err
+	}
+	return convertDMXRecordingStatus(status), nil
 }
 
-// SearchCues is the resolver for the searchCues field.
-func (r *queryResolver) SearchCues(ctx context.Context, cueListID string, query string, page *int, perPage *int) (*generated.CuePage, error) {
-	cues, err := r.CueListRepo.GetCues(ctx, cueListID)
+// StopDmxRecording is the resolver for the stopDmxRecording field.
+func (r *mutationResolver) StopDmxRecording(ctx context.Context) (*generated.DmxRecording, error) {
+	service, err := r.dmxRecorder()
 	if err != nil {
 		return nil, err
 	}
-
-	// Filter by search query (name, notes)
-	var filtered []models.Cue
-	queryLower := strings.ToLower(query)
-	for _, c := range cues {
-		if strings.Contains(strings.ToLower(c.Name), queryLower) {
-			filtered = append(filtered, c)
-			continue
-		}
-		if c.Notes != nil && strings.Contains(strings.ToLower(*c.Notes), queryLower) {
-			filtered = append(filtered, c)
-			continue
-		}
+	info, err := service.StopRecording()
+	if err != nil {
+		return nil, err
 	}
+	return convertDMXRecording(info), nil
+}
 
-	// Apply pagination
-	pageNum := 1
-	pageSize := 50
-	if page != nil {
-		pageNum = *page
+// ReplayDmxRecording is the resolver for the replayDmxRecording field.
+func (r *mutationResolver) ReplayDmxRecording(ctx context.Context, name string, loop *bool) (*generated.DmxRecordingStatus, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return nil, err
 	}
-	if perPage != nil {
-		pageSize = *perPage
+	status, err := service.Replay(name, loop != nil && *loop)
+	if err != nil {
+		return nil, err
 	}
+	return convertDMXRecordingStatus(status), nil
+}
 
-	total := len(filtered)
-	start := (pageNum - 1) * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
+// StopDmxReplay is the resolver for the stopDmxReplay field.
+func (r *mutationResolver) StopDmxReplay(ctx context.Context) (*generated.DmxRecordingStatus, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return nil, err
 	}
+	service.StopReplay()
+	return convertDMXRecordingStatus(service.Status()), nil
+}
 
-	items := make([]*models.Cue, end-start)
-	for i := start; i < end; i++ {
-		items[i-start] = &filtered[i]
+// DeleteDmxRecording is the resolver for the deleteDmxRecording field.
+func (r *mutationResolver) DeleteDmxRecording(ctx context.Context, name string) (bool, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return false, err
 	}
+	if err := service.DeleteRecording(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	return &generated.CuePage{
-		Cues: items,
-		Pagination: generated.PaginationInfo{
-			Total:   total,
-			Page:    pageNum,
-			PerPage: pageSize,
-			HasMore: end < total,
-		},
-	}, nil
+// SetArtNetUnicastRoute is the resolver for the setArtNetUnicastRoute field.
+func (r *mutationResolver) SetArtNetUnicastRoute(ctx context.Context, universe int, destinations []string) ([]*generated.ArtNetUnicastRoute, error) {
+	if destinations == nil {
+		destinations = []string{}
+	}
+	return r.updateUnicastRoutes(ctx, r.replaceUnicastRoute(universe, destinations))
 }
 
-// DmxOutput is the resolver for the dmxOutput field.
-func (r *queryResolver) DmxOutput(ctx context.Context, universe int) ([]int, error) {
-	// DMX service expects 1-indexed universe
-	return r.DMXService.GetUniverse(universe), nil
+// RemoveArtNetUnicastRoute is the resolver for the removeArtNetUnicastRoute field.
+func (r *mutationResolver) RemoveArtNetUnicastRoute(ctx context.Context, universe int) ([]*generated.ArtNetUnicastRoute, error) {
+	return r.updateUnicastRoutes(ctx, r.replaceUnicastRoute(universe, nil))
 }
 
-// AllDmxOutput is the resolver for the allDmxOutput field.
-func (r *queryResolver) AllDmxOutput(ctx context.Context) ([]*generated.UniverseOutput, error) {
-	allUniverses := r.DMXService.GetAllUniverses()
-	result := make([]*generated.UniverseOutput, 0, len(allUniverses))
-	for universe, channels := range allUniverses {
-		result = append(result, &generated.UniverseOutput{
-			Universe: universe,
-			Channels: channels,
-		})
+// SetArtNetUnicastRoutes is the resolver for the setArtNetUnicastRoutes field.
+func (r *mutationResolver) SetArtNetUnicastRoutes(ctx context.Context, routes []*generated.ArtNetUnicastRouteInput) ([]*generated.ArtNetUnicastRoute, error) {
+	table := make([]dmx.UnicastRoute, 0, len(routes))
+	for _, route := range routes {
+		table = append(table, dmx.UnicastRoute{Universe: route.Universe, Destinations: route.Destinations})
 	}
-	return result, nil
+	return r.updateUnicastRoutes(ctx, table)
 }
 
-// PreviewSession is the resolver for the previewSession field.
-func (r *queryResolver) PreviewSession(ctx context.Context, sessionID string) (*models.PreviewSession, error) {
-	var session models.PreviewSession
-	result := r.db.First(&session, "id = ?", sessionID)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &session, nil
+// SetArtNetSync is the resolver for the setArtNetSync field.
+func (r *mutationResolver) SetArtNetSync(ctx context.Context, enabled bool) (bool, error) {
+	return r.updateArtSync(ctx, enabled)
 }
 
-// CurrentActiveScene is the resolver for the currentActiveScene field.
-func (r *queryResolver) CurrentActiveScene(ctx context.Context) (*models.Scene, error) {
-	// TODO: Implement tracking of currently active scene
-	// For now, return nil (no active scene)
-	return nil, nil
+// SetArtNetBind is the resolver for the setArtNetBind field.
+func (r *mutationResolver) SetArtNetBind(ctx context.Context, bind string) (*generated.ArtNetBinding, error) {
+	return r.updateArtNetBind(ctx, bind)
 }
 
-// Settings is the resolver for the settings field.
-func (r *queryResolver) Settings(ctx context.Context) ([]*models.Setting, error) {
-	settings, err := r.SettingRepo.FindAll(ctx)
-	if err != nil {
-		return nil, err
-	}
-	result := make([]*models.Setting, len(settings))
-	for i := range settings {
-		result[i] = &settings[i]
-	}
-	return result, nil
+// ResetDmxTransmitStats is the resolver for the resetDmxTransmitStats field.
+func (r *mutationResolver) ResetDmxTransmitStats(ctx context.Context) (*generated.DmxTransmitStats, error) {
+	return convertTransmitStats(r.DMXService.ResetTransmitStats()), nil
 }
 
-// Setting is the resolver for the setting field.
-func (r *queryResolver) Setting(ctx context.Context, key string) (*models.Setting, error) {
-	return r.SettingRepo.FindByKey(ctx, key)
+// SetChannelLimit is the resolver for the setChannelLimit field.
+func (r *mutationResolver) SetChannelLimit(ctx context.Context, input generated.ChannelLimitInput) ([]*generated.ChannelLimit, error) {
+	limit := channelLimitFromInput(&input)
+	return r.updateChannelLimits(ctx, r.replaceChannelLimit(input.Universe, input.Channel, &limit))
 }
 
-// SystemInfo is the resolver for the systemInfo field.
-func (r *queryResolver) SystemInfo(ctx context.Context) (*generated.SystemInfo, error) {
-	return &generated.SystemInfo{
-		ArtnetEnabled:          r.DMXService.IsEnabled(),
-		ArtnetBroadcastAddress: r.DMXService.GetBroadcastAddress(),
-		FadeUpdateRateHz:       r.FadeEngine.GetUpdateRateHz(),
-	}, nil
+// RemoveChannelLimit is the resolver for the removeChannelLimit field.
+func (r *mutationResolver) RemoveChannelLimit(ctx context.Context, universe int, channel int) ([]*generated.ChannelLimit, error) {
+	return r.updateChannelLimits(ctx, r.replaceChannelLimit(universe, channel, nil))
 }
 
-// NetworkInterfaceOptions is the resolver for the networkInterfaceOptions field.
-// Returns available network interfaces for Art-Net broadcast selection
-func (r *queryResolver) NetworkInterfaceOptions(ctx context.Context) ([]*generated.NetworkInterfaceOption, error) {
-	interfaces, err := network.GetNetworkInterfaces()
+// SetChannelLimits is the resolver for the setChannelLimits field.
+func (r *mutationResolver) SetChannelLimits(ctx context.Context, limits []*generated.ChannelLimitInput) ([]*generated.ChannelLimit, error) {
+	table := make([]dmx.ChannelLimit, 0, len(limits))
+	for _, limit := range limits {
+		table = append(table, channelLimitFromInput(limit))
+	}
+	return r.updateChannelLimits(ctx, table)
+}
+
+// ParkChannel is the resolver for the parkChannel field.
+func (r *mutationResolver) ParkChannel(ctx context.Context, input generated.ParkChannelInput) ([]*generated.ParkedChannel, error) {
+	parked, err := r.parkedChannelFromInput(ctx, input)
 	if err != nil {
 		return nil, err
 	}
+	return r.updateParkedChannels(ctx, r.replaceParkedChannel(parked.Universe, parked.Channel, &parked))
+}
 
-	options := make([]*generated.NetworkInterfaceOption, len(interfaces))
-	for i, iface := range interfaces {
-		options[i] = &generated.NetworkInterfaceOption{
-			Name:          iface.Name,
-			Address:       iface.Address,
-			Broadcast:     iface.Broadcast,
-			Description:   iface.Description,
-			InterfaceType: iface.InterfaceType,
-		}
-	}
+// UnparkChannel is the resolver for the unparkChannel field.
+func (r *mutationResolver) UnparkChannel(ctx context.Context, universe int, channel int) ([]*generated.ParkedChannel, error) {
+	return r.updateParkedChannels(ctx, r.replaceParkedChannel(universe, channel, nil))
+}
 
-	return options, nil
+// SetMasterLevel is the resolver for the setMasterLevel field.
+func (r *mutationResolver) SetMasterLevel(ctx context.Context, level float64, universe *int) (*generated.MasterLevels, error) {
+	return r.setMasterLevel(universe, level)
 }
 
-// WifiNetworks is the resolver for the wifiNetworks field.
-func (r *queryResolver) WifiNetworks(ctx context.Context, rescan *bool, deduplicate *bool) ([]*generated.WiFiNetwork, error) {
-	doRescan := rescan != nil && *rescan
-	doDeduplicate := deduplicate == nil || *deduplicate // Default to true
+// Blackout is the resolver for the blackout field.
+func (r *mutationResolver) Blackout(ctx context.Context, fadeTime *float64) (*generated.BlackoutStatus, error) {
+	return r.setBlackout(true, fadeTime)
+}
 
-	networks, err := r.WiFiService.ScanNetworks(ctx, doRescan, doDeduplicate)
+// RestoreFromBlackout is the resolver for the restoreFromBlackout field.
+func (r *mutationResolver) RestoreFromBlackout(ctx context.Context, fadeTime *float64) (*generated.BlackoutStatus, error) {
+	return r.setBlackout(false, fadeTime)
+}
+
+// SetIndependent is the resolver for the setIndependent field.
+func (r *mutationResolver) SetIndependent(ctx context.Context, input generated.IndependentInput) (*generated.Independent, error) {
+	return r.setIndependent(ctx, input)
+}
+
+// ReleaseIndependent is the resolver for the releaseIndependent field.
+func (r *mutationResolver) ReleaseIndependent(ctx context.Context, id string) (bool, error) {
+	return r.releaseIndependent(id), nil
+}
+
+// ReleaseIndependents is the resolver for the releaseIndependents field.
+func (r *mutationResolver) ReleaseIndependents(ctx context.Context, projectID *string) (int, error) {
+	return r.releaseIndependents(projectID), nil
+}
+
+// UpdateTimecodeConfig is the resolver for the updateTimecodeConfig field.
+func (r *mutationResolver) UpdateTimecodeConfig(ctx context.Context, input generated.TimecodeConfigInput) (*generated.TimecodeStatus, error) {
+	return r.updateTimecodeConfig(ctx, input)
+}
+
+// StartTimecode is the resolver for the startTimecode field.
+func (r *mutationResolver) StartTimecode(ctx context.Context) (*generated.TimecodeStatus, error) {
+	status, err := r.TimecodeService.Play()
 	if err != nil {
 		return nil, err
 	}
+	return convertTimecodeStatus(status), nil
+}
 
-	var result []*generated.WiFiNetwork
-	for _, n := range networks {
-		result = append(result, &generated.WiFiNetwork{
-			Ssid:           n.SSID,
-			SignalStrength: n.SignalStrength,
-			Frequency:      n.Frequency,
-			Security:       generated.WiFiSecurityType(n.Security),
-			InUse:          n.InUse,
-			Saved:          n.Saved,
-		})
+// StopTimecode is the resolver for the stopTimecode field.
+func (r *mutationResolver) StopTimecode(ctx context.Context) (*generated.TimecodeStatus, error) {
+	status, err := r.TimecodeService.Stop()
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return convertTimecodeStatus(status), nil
 }
 
-// WifiStatus is the resolver for the wifiStatus field.
-func (r *queryResolver) WifiStatus(ctx context.Context) (*generated.WiFiStatus, error) {
-	status, err := r.WiFiService.GetStatus(ctx)
+// LocateTimecode is the resolver for the locateTimecode field.
+func (r *mutationResolver) LocateTimecode(ctx context.Context, position string) (*generated.TimecodeStatus, error) {
+	tc, err := timecode.Parse(position)
 	if err != nil {
 		return nil, err
 	}
-	return convertWiFiStatus(status), nil
+	status, err := r.TimecodeService.Locate(tc)
+	if err != nil {
+		return nil, err
+	}
+	return convertTimecodeStatus(status), nil
 }
 
-// SavedWifiNetworks is the resolver for the savedWifiNetworks field.
-// Returns empty list - WiFi not available on this platform
-func (r *queryResolver) SavedWifiNetworks(ctx context.Context) ([]*generated.WiFiNetwork, error) {
-	return []*generated.WiFiNetwork{}, nil
+// UpdateAudioInputConfig is the resolver for the updateAudioInputConfig field.
+func (r *mutationResolver) UpdateAudioInputConfig(ctx context.Context, input generated.AudioInputConfigInput) (*generated.AudioInputStatus, error) {
+	return r.updateAudioConfig(ctx, input)
 }
 
-// WifiMode is the resolver for the wifiMode field.
-func (r *queryResolver) WifiMode(ctx context.Context) (generated.WiFiMode, error) {
-	mode := r.WiFiService.GetMode()
-	return generated.WiFiMode(mode), nil
-}
+// SetSceneLive is the resolver for the setSceneLive field.
+func (r *mutationResolver) SetSceneLive(ctx context.Context, sceneID string) (bool, error) {
+	sceneChannels, err := r.loadSceneChannels(ctx, sceneID)
+	if err != nil {
+		return false, fmt.Errorf("scene not found: %w", err)
+	}
 
-// ApConfig is the resolver for the apConfig field.
-func (r *queryResolver) ApConfig(ctx context.Context) (*generated.APConfig, error) {
-	config := r.WiFiService.GetAPConfig()
-	if config == nil {
-		return nil, nil
+	// Set channel values immediately (no fade), merged with the scenes live
+	// on other playbacks
+	activation := stack.Activation{
+		PlaybackID: stack.ScenePlayback(sceneID),
+		Kind:       stack.KindScene,
+		SceneID:    sceneID,
+		Channels:   sceneChannels,
 	}
+	r.StackService.Activate(activation, 0, fmt.Sprintf("scene-%s", sceneID), fade.EasingInOutSine)
+	r.journalSceneLive(activation)
 
-	return &generated.APConfig{
-		Ssid:             config.SSID,
-		IPAddress:        config.IPAddress,
-		Channel:          config.Channel,
-		ClientCount:      config.ClientCount,
-		TimeoutMinutes:   config.TimeoutMinutes,
-		MinutesRemaining: config.MinutesRemaining,
-	}, nil
+	// Track the active scene
+	r.DMXService.SetActiveScene(sceneID)
+
+	return true, nil
 }
 
-// ApClients is the resolver for the apClients field.
-func (r *queryResolver) ApClients(ctx context.Context) ([]*generated.APClient, error) {
-	clients := r.WiFiService.GetAPClients()
-	if clients == nil {
-		return []*generated.APClient{}, nil
+// ActivateScene is the resolver for the activateScene field.
+func (r *mutationResolver) ActivateScene(ctx context.Context, sceneID string, fadeInTime *float64) (bool, error) {
+	if fadeInTime != nil && *fadeInTime < 0 {
+		return false, fmt.Errorf("fadeInTime must not be negative")
 	}
 
-	var result []*generated.APClient
-	for _, client := range clients {
-		result = append(result, &generated.APClient{
-			MacAddress:  client.MACAddress,
-			IPAddress:   client.IPAddress,
-			Hostname:    client.Hostname,
-			ConnectedAt: client.ConnectedAt.Format("2006-01-02T15:04:05Z07:00"),
-		})
+	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
+	if err != nil {
+		return false, err
+	}
+	if scene == nil {
+		return false, fmt.Errorf("scene not found: %s", sceneID)
 	}
 
-	return result, nil
-}
+	fadeTime, err := r.PlaybackService.ResolveSceneFadeIn(ctx, sceneID, playback.SceneFadeLevels{Call: fadeInTime})
+	if err != nil {
+		return false, err
+	}
 
-// GetQLCFixtureMappingSuggestions is the resolver for the getQLCFixtureMappingSuggestions field.
-// Returns empty result - QLC+ integration not available
-func (r *queryResolver) GetQLCFixtureMappingSuggestions(ctx context.Context, projectID string) (*generated.QLCFixtureMappingResult, error) {
-	return &generated.QLCFixtureMappingResult{
-		ProjectID:          projectID,
-		LacyLightsFixtures: []*generated.LacyLightsFixture{},
-		Suggestions:        []*generated.FixtureMappingSuggestion{},
-		DefaultMappings:    []*generated.FixtureMapping{},
-	}, nil
+	sceneChannels, err := r.loadSceneChannels(ctx, sceneID)
+	if err != nil {
+		return false, err
+	}
+
+	fadeID := fmt.Sprintf("scene-%s", sceneID)
+	activation := stack.Activation{
+		PlaybackID: stack.ScenePlayback(sceneID),
+		Kind:       stack.KindScene,
+		SceneID:    sceneID,
+		Channels:   sceneChannels,
+	}
+	r.StackService.Activate(activation, time.Duration(fadeTime*float64(time.Second)), fadeID, fade.EasingInOutSine)
+	r.journalSceneLive(activation)
+
+	// Track the active scene
+	r.DMXService.SetActiveScene(sceneID)
+
+	return true, nil
 }
 
-// SystemVersions is the resolver for the systemVersions field.
-// Returns version information for all LacyLights components
-func (r *queryResolver) SystemVersions(ctx context.Context) (*generated.SystemVersionInfo, error) {
-	info, err := r.VersionService.GetSystemVersions()
-	if err != nil {
-		return nil, err
+// PlayCue is the resolver for the playCue field.
+func (r *mutationResolver) PlayCue(ctx context.Context, cueID string, fadeInTime *float64) (bool, error) {
+	if err := r.PlaybackService.ExecuteCueDmx(ctx, cueID, fadeInTime); err != nil {
+		return false, err
 	}
+	r.PlaybackService.RecordEvent(playback.Event{Type: playback.EventPlayCue, CueID: cueID, FadeTime: fadeInTime})
+	return true, nil
+}
 
-	// Convert to GraphQL types
-	var repos []*generated.RepositoryVersion
-	for _, repo := range info.Repositories {
-		repos = append(repos, &generated.RepositoryVersion{
-			Repository:      repo.Repository,
-			Installed:       repo.Installed,
-			Latest:          repo.Latest,
-			UpdateAvailable: repo.UpdateAvailable,
-		})
+// FadeToBlack is the resolver for the fadeToBlack field.
+func (r *mutationResolver) FadeToBlack(ctx context.Context, fadeOutTime float64) (bool, error) {
+	// Convert fadeOutTime from seconds to duration
+	duration := time.Duration(fadeOutTime * float64(time.Second))
+
+	// Use the fade engine to smoothly fade to black
+	fadeID := r.FadeEngine.FadeToBlack(duration, "")
+
+	// For instant fades (0 duration), also immediately clear DMX state
+	// For timed fades, the fade engine will handle the gradual transition,
+	// but we still need to ensure the DMX service state is cleared at the end
+	if duration == 0 {
+		r.DMXService.FadeToBlack()
+	} else {
+		// After fade completes, ensure DMX service clears all channels
+		// This handles the programmer and other channels the fade engine does not track
+		// Use the fadeID to detect if a new fade has started and skip cleanup if so
+		go func(currentFadeID string) {
+			time.Sleep(duration + 100*time.Millisecond) // Wait for fade to complete
+			// Only clear if this is still the active fade (no new fade has started)
+			if r.FadeEngine.ActiveFadeCount() == 0 {
+				r.DMXService.FadeToBlack()
+			}
+		}(fadeID)
 	}
 
-	return &generated.SystemVersionInfo{
-		Repositories:               repos,
-		LastChecked:                info.LastChecked,
-		VersionManagementSupported: info.VersionManagementSupported,
-	}, nil
+	// Stop macros so they bring nothing back up, then clear active scene
+	// tracking and the playback stack
+	r.MacroService.StopAll()
+	r.DMXService.ClearActiveScene()
+	r.StackService.Clear()
+	r.journalSceneReleased("")
+	r.sceneBoardStateChanged("")
+	r.PlaybackService.RecordEvent(playback.Event{Type: playback.EventFadeToBlack, FadeTime: &fadeOutTime})
+
+	_ = fadeID // suppress unused variable warning
+	return true, nil
 }
 
-// AvailableVersions is the resolver for the availableVersions field.
-// Returns available versions for a specific repository
-func (r *queryResolver) AvailableVersions(ctx context.Context, repository string) ([]string, error) {
-	return r.VersionService.GetAvailableVersions(repository)
+// ReleasePlayback is the resolver for the releasePlayback field.
+func (r *mutationResolver) ReleasePlayback(ctx context.Context, playbackID string, fadeOutTime *float64) ([]*generated.PlaybackStackEntry, error) {
+	return r.releasePlayback(ctx, playbackID, fadeOutTime)
 }
 
-// BuildInfo is the resolver for the buildInfo field.
-func (r *queryResolver) BuildInfo(ctx context.Context) (*generated.BuildInfo, error) {
-	info := version.GetBuildInfo()
-	return &generated.BuildInfo{
-		Version:   info.Version,
-		GitCommit: info.GitCommit,
-		BuildTime: info.BuildTime,
-	}, nil
+// ResumePlayback is the resolver for the resumePlayback field.
+func (r *mutationResolver) ResumePlayback(ctx context.Context) (*generated.PlaybackResumeResult, error) {
+	return r.Resolver.ResumePlayback(ctx)
 }
 
-// OflImportStatus is the resolver for the oflImportStatus field.
-func (r *queryResolver) OflImportStatus(ctx context.Context) (*generated.OFLImportStatus, error) {
-	status := r.OFLManager.GetStatus()
+// StartCueList is the resolver for the startCueList field.
+func (r *mutationResolver) StartCueList(ctx context.Context, cueListID string, startFromCue *int, fadeInTime *float64, fadeOutTime *float64) (bool, error) {
+	var startFromCueNumber *float64
+	if startFromCue != nil {
+		cueNum := float64(*startFromCue)
+		startFromCueNumber = &cueNum
+	}
+	if err := validateFadeOverrides(fadeInTime, fadeOutTime); err != nil {
+		return false, err
+	}
+	if err := r.PlaybackService.StartCueList(ctx, cueListID, startFromCueNumber, fadeInTime, fadeOutTime); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	var startedAt, completedAt *string
-	if status.StartedAt != nil {
-		s := status.StartedAt.Format("2006-01-02T15:04:05Z07:00")
-		startedAt = &s
+// NextCue is the resolver for the nextCue field.
+func (r *mutationResolver) NextCue(ctx context.Context, cueListID string, fadeInTime *float64, fadeOutTime *float64) (bool, error) {
+	if err := validateFadeOverrides(fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
-	if status.CompletedAt != nil {
-		s := status.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
-		completedAt = &s
+	if err := r.PlaybackService.NextCue(ctx, cueListID, fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	var errorMessage *string
-	if status.ErrorMessage != "" {
-		errorMessage = &status.ErrorMessage
+// PreviousCue is the resolver for the previousCue field.
+func (r *mutationResolver) PreviousCue(ctx context.Context, cueListID string, fadeInTime *float64, fadeOutTime *float64) (bool, error) {
+	if err := validateFadeOverrides(fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
+	if err := r.PlaybackService.PreviousCue(ctx, cueListID, fadeInTime, fadeOutTime); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	var currentFixture, currentManufacturer *string
-	if status.CurrentFixture != "" {
-		currentFixture = &status.CurrentFixture
+// GoToCue is the resolver for the goToCue field.
+func (r *mutationResolver) GoToCue(ctx context.Context, cueListID string, cueIndex int, fadeInTime *float64, fadeOutTime *float64) (bool, error) {
+	if err := validateFadeOverrides(fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
-	if status.CurrentManufacturer != "" {
-		currentManufacturer = &status.CurrentManufacturer
+	if err := r.PlaybackService.JumpToCue(ctx, cueListID, cueIndex, fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	var oflVersion *string
-	if status.OFLVersion != "" {
-		oflVersion = &status.OFLVersion
+// GoToCueNumber is the resolver for the goToCueNumber field.
+func (r *mutationResolver) GoToCueNumber(ctx context.Context, cueListID string, cueNumber float64, fadeInTime *float64, fadeOutTime *float64) (bool, error) {
+	if err := validateFadeOverrides(fadeInTime, fadeOutTime); err != nil {
+		return false, err
+	}
+	if err := r.PlaybackService.GoToCueNumber(ctx, cueListID, cueNumber, fadeInTime, fadeOutTime); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return &generated.OFLImportStatus{
-		IsImporting:               status.IsImporting,
-		Phase:                     generated.OFLImportPhase(status.Phase),
-		TotalFixtures:             status.TotalFixtures,
-		ImportedCount:             status.ImportedCount,
-		FailedCount:               status.FailedCount,
-		SkippedCount:              status.SkippedCount,
-		PercentComplete:           status.PercentComplete,
-		CurrentFixture:            currentFixture,
-		CurrentManufacturer:       currentManufacturer,
-		EstimatedSecondsRemaining: status.EstimatedSecondsRemaining,
-		ErrorMessage:              errorMessage,
-		StartedAt:                 startedAt,
-		CompletedAt:               completedAt,
-		OflVersion:                oflVersion,
-		UsingBundledData:          status.UsingBundledData,
-	}, nil
+// StopCueList is the resolver for the stopCueList field.
+func (r *mutationResolver) StopCueList(ctx context.Context, cueListID string) (bool, error) {
+	r.PlaybackService.StopCueList(cueListID)
+	return true, nil
 }
 
-// CheckOFLUpdates is the resolver for the checkOFLUpdates field.
-func (r *queryResolver) CheckOFLUpdates(ctx context.Context) (*generated.OFLUpdateCheckResult, error) {
-	result, err := r.OFLManager.CheckForUpdates(ctx)
-	if err != nil {
+// PauseCueList is the resolver for the pauseCueList field.
+func (r *mutationResolver) PauseCueList(ctx context.Context, cueListID string) (*generated.CueListPlaybackStatus, error) {
+	if err := r.PlaybackService.PauseCueList(cueListID); err != nil {
 		return nil, err
 	}
+	return convertCueListPlaybackStatus(r.PlaybackService.GetFormattedStatus(cueListID)), nil
+}
 
-	// Convert fixture updates to GraphQL type
-	var updates []*generated.OFLFixtureUpdate
-	for _, u := range result.FixtureUpdates {
-		update := &generated.OFLFixtureUpdate{
-			FixtureKey:    u.FixtureKey,
-			Manufacturer:  u.Manufacturer,
-			Model:         u.Model,
-			ChangeType:    generated.OFLFixtureChangeType(u.ChangeType),
-			IsInUse:       u.IsInUse,
-			InstanceCount: u.InstanceCount,
-			CurrentHash:   u.CurrentHash,
-			NewHash:       u.NewHash,
-		}
-		updates = append(updates, update)
+// ResumeCueList is the resolver for the resumeCueList field.
+func (r *mutationResolver) ResumeCueList(ctx context.Context, cueListID string) (*generated.CueListPlaybackStatus, error) {
+	r.PlaybackService.ResumeCueList(cueListID)
+	return convertCueListPlaybackStatus(r.PlaybackService.GetFormattedStatus(cueListID)), nil
+}
+
+// ReleaseCueList is the resolver for the releaseCueList field.
+func (r *mutationResolver) ReleaseCueList(ctx context.Context, cueListID string, fadeOutTime *float64) (*generated.CueListPlaybackStatus, error) {
+	if fadeOutTime != nil && *fadeOutTime < 0 {
+		return nil, fmt.Errorf("fadeOutTime must not be negative")
 	}
+	r.PlaybackService.ReleaseCueList(cueListID, fadeOutTime)
+	return convertCueListPlaybackStatus(r.PlaybackService.GetFormattedStatus(cueListID)), nil
+}
 
-	return &generated.OFLUpdateCheckResult{
-		CurrentFixtureCount: result.CurrentFixtureCount,
-		OflFixtureCount:     result.OFLFixtureCount,
-		NewFixtureCount:     result.NewFixtureCount,
-		ChangedFixtureCount: result.ChangedFixtureCount,
-		ChangedInUseCount:   result.ChangedInUseCount,
-		FixtureUpdates:      updates,
-		OflVersion:          result.OFLVersion,
-		CheckedAt:           result.CheckedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}, nil
+// SetCueListCrossfade is the resolver for the setCueListCrossfade field.
+func (r *mutationResolver) SetCueListCrossfade(ctx context.Context, cueListID string, position float64) (*generated.CueListPlaybackStatus, error) {
+	if err := r.PlaybackService.SetCrossfadePosition(ctx, cueListID, position); err != nil {
+		return nil, err
+	}
+	return convertCueListPlaybackStatus(r.PlaybackService.GetFormattedStatus(cueListID)), nil
 }
 
-// FixturesByIds is the resolver for the fixturesByIds field.
-func (r *queryResolver) FixturesByIds(ctx context.Context, ids []string) ([]*models.FixtureInstance, error) {
-	var fixtures []*models.FixtureInstance
+// ClearPlaybackLog is the resolver for the clearPlaybackLog field.
+func (r *mutationResolver) ClearPlaybackLog(ctx context.Context) (bool, error) {
+	r.PlaybackService.ClearEventLog()
+	return true, nil
+}
 
-	for _, id := range ids {
-		fixture, err := r.FixtureRepo.FindByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if fixture != nil {
-			fixtures = append(fixtures, fixture)
-		}
+// ReplayPlaybackLog is the resolver for the replayPlaybackLog field.
+func (r *mutationResolver) ReplayPlaybackLog(ctx context.Context, content string, instant *bool) (int, error) {
+	eventLog, err := playback.ParseEventLog(content)
+	if err != nil {
+		return 0, err
 	}
 
-	return fixtures, nil
+	isInstant := instant == nil || *instant
+	if err := r.PlaybackService.Replay(ctx, eventLog, isInstant); err != nil {
+		return 0, err
+	}
+	return len(eventLog.Events), nil
 }
 
-// ScenesByIds is the resolver for the scenesByIds field.
-func (r *queryResolver) ScenesByIds(ctx context.Context, ids []string) ([]*models.Scene, error) {
-	var scenes []*models.Scene
+// StartOperationRecording is the resolver for the startOperationRecording field.
+func (r *mutationResolver) StartOperationRecording(ctx context.Context) (*generated.OperationRecordingStatus, error) {
+	return convertOperationRecordingStatus(r.OperationRecorder.Start()), nil
+}
 
-	for _, id := range ids {
-		scene, err := r.SceneRepo.FindByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if scene != nil {
-			scenes = append(scenes, scene)
-		}
+// StopOperationRecording is the resolver for the stopOperationRecording field.
+func (r *mutationResolver) StopOperationRecording(ctx context.Context) (*generated.OperationRecording, error) {
+	recording, err := r.OperationRecorder.Stop()
+	if err != nil {
+		return nil, err
+	}
+	content, err := recording.ToJSON()
+	if err != nil {
+		return nil, err
 	}
 
-	return scenes, nil
+	return &generated.OperationRecording{
+		StartedAt:      recording.StartedAt.Format("2006-01-02T15:04:05.000Z"),
+		EndedAt:        recording.EndedAt.Format("2006-01-02T15:04:05.000Z"),
+		OperationCount: len(recording.Operations),
+		DroppedCount:   recording.Dropped,
+		Content:        content,
+	}, nil
 }
 
-// CuesByIds is the resolver for the cuesByIds field.
-func (r *queryResolver) CuesByIds(ctx context.Context, ids []string) ([]*models.Cue, error) {
-	var cues []*models.Cue
+// SetTempo is the resolver for the setTempo field.
+func (r *mutationResolver) SetTempo(ctx context.Context, bpm float64) (*generated.TempoState, error) {
+	state, err := r.TempoService.SetBPM(bpm)
+	if err != nil {
+		return nil, err
+	}
+	return convertTempoState(state), nil
+}
 
-	for _, id := range ids {
-		cue, err := r.CueRepo.FindByID(ctx, id)
+// TapTempo is the resolver for the tapTempo field.
+func (r *mutationResolver) TapTempo(ctx context.Context) (*generated.TempoState, error) {
+	return convertTempoState(r.TempoService.Tap()), nil
+}
+
+// SetBeatsPerBar is the resolver for the setBeatsPerBar field.
+func (r *mutationResolver) SetBeatsPerBar(ctx context.Context, beatsPerBar int) (*generated.TempoState, error) {
+	state, err := r.TempoService.SetBeatsPerBar(beatsPerBar)
+	if err != nil {
+		return nil, err
+	}
+	return convertTempoState(state), nil
+}
+
+// ResyncTempo is the resolver for the resyncTempo field.
+func (r *mutationResolver) ResyncTempo(ctx context.Context) (*generated.TempoState, error) {
+	return convertTempoState(r.TempoService.Resync()), nil
+}
+
+// CreateShowTimer is the resolver for the createShowTimer field.
+func (r *mutationResolver) CreateShowTimer(ctx context.Context, input generated.CreateShowTimerInput) (*generated.ShowTimer, error) {
+	opts := showtimer.CreateOptions{
+		Name: input.Name,
+		Kind: showtimer.KindCountdown,
+	}
+	if input.Kind.IsSet() && input.Kind.Value() != nil {
+		opts.Kind = showtimer.Kind(*input.Kind.Value())
+	}
+	if input.DurationSeconds.IsSet() && input.DurationSeconds.Value() != nil {
+		opts.Duration = time.Duration(*input.DurationSeconds.Value() * float64(time.Second))
+	}
+	if input.TriggerCueListID.IsSet() && input.TriggerCueListID.Value() != nil {
+		cueListID := *input.TriggerCueListID.Value()
+		cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
 		if err != nil {
 			return nil, err
 		}
-		if cue != nil {
-			cues = append(cues, cue)
+		if cueList == nil {
+			return nil, fmt.Errorf("cue list not found: %s", cueListID)
+		}
+		opts.TriggerCueListID = &cueListID
+		if input.TriggerCueNumber.IsSet() {
+			opts.TriggerCueNumber = input.TriggerCueNumber.Value()
 		}
 	}
 
-	return cues, nil
+	timer, err := r.ShowTimerService.Create(opts)
+	if err != nil {
+		return nil, err
+	}
+	return convertShowTimer(timer), nil
 }
 
-// CueListsByIds is the resolver for the cueListsByIds field.
-func (r *queryResolver) CueListsByIds(ctx context.Context, ids []string) ([]*models.CueList, error) {
-	var cueLists []*models.CueList
+// StartShowTimer is the resolver for the startShowTimer field.
+func (r *mutationResolver) StartShowTimer(ctx context.Context, id string) (*generated.ShowTimer, error) {
+	timer, err := r.ShowTimerService.Start(id)
+	if err != nil {
+		return nil, err
+	}
+	return convertShowTimer(timer), nil
+}
 
-	for _, id := range ids {
-		cueList, err := r.CueListRepo.FindByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if cueList != nil {
-			cueLists = append(cueLists, cueList)
-		}
+// StopShowTimer is the resolver for the stopShowTimer field.
+func (r *mutationResolver) StopShowTimer(ctx context.Context, id string) (*generated.ShowTimer, error) {
+	timer, err := r.ShowTimerService.Stop(id)
+	if err != nil {
+		return nil, err
 	}
+	return convertShowTimer(timer), nil
+}
 
-	return cueLists, nil
+// ResetShowTimer is the resolver for the resetShowTimer field.
+func (r *mutationResolver) ResetShowTimer(ctx context.Context, id string) (*generated.ShowTimer, error) {
+	timer, err := r.ShowTimerService.Reset(id)
+	if err != nil {
+		return nil, err
+	}
+	return convertShowTimer(timer), nil
 }
 
-// SceneBoardsByIds is the resolver for the sceneBoardsByIds field.
-func (r *queryResolver) SceneBoardsByIds(ctx context.Context, ids []string) ([]*models.SceneBoard, error) {
-	var sceneBoards []*models.SceneBoard
+// AdjustShowTimer is the resolver for the adjustShowTimer field.
+func (r *mutationResolver) AdjustShowTimer(ctx context.Context, id string, deltaSeconds float64) (*generated.ShowTimer, error) {
+	timer, err := r.ShowTimerService.Adjust(id, time.Duration(deltaSeconds*float64(time.Second)))
+	if err != nil {
+		return nil, err
+	}
+	return convertShowTimer(timer), nil
+}
 
-	for _, id := range ids {
-		var board models.SceneBoard
-		result := r.db.WithContext(ctx).First(&board, "id = ?", id)
-		if result.Error == nil {
-			sceneBoards = append(sceneBoards, &board)
-		}
+// DeleteShowTimer is the resolver for the deleteShowTimer field.
+func (r *mutationResolver) DeleteShowTimer(ctx context.Context, id string) (bool, error) {
+	if err := r.ShowTimerService.Delete(id); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return sceneBoards, nil
+// EnterStandby is the resolver for the enterStandby field.
+func (r *mutationResolver) EnterStandby(ctx context.Context) (*generated.StandbyStatus, error) {
+	return convertStandbyStatus(r.StandbyService.Enter()), nil
 }
 
-// FixtureDefinitionsByIds is the resolver for the fixtureDefinitionsByIds field.
-func (r *queryResolver) FixtureDefinitionsByIds(ctx context.Context, ids []string) ([]*models.FixtureDefinition, error) {
-	var definitions []*models.FixtureDefinition
+// WakeFromStandby is the resolver for the wakeFromStandby field.
+func (r *mutationResolver) WakeFromStandby(ctx context.Context) (*generated.StandbyStatus, error) {
+	return convertStandbyStatus(r.StandbyService.Wake()), nil
+}
 
-	for _, id := range ids {
-		definition, err := r.FixtureRepo.FindDefinitionByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if definition != nil {
-			definitions = append(definitions, definition)
-		}
+// ServerStandby is the resolver for the serverStandby field.
+func (r *mutationResolver) ServerStandby(ctx context.Context, enabled bool, output *generated.StandbyOutput) (*generated.StandbyStatus, error) {
+	return r.serverStandby(enabled, output)
+}
+
+// UpdateStandbyConfig is the resolver for the updateStandbyConfig field.
+func (r *mutationResolver) UpdateStandbyConfig(ctx context.Context, input generated.StandbyConfigInput) (*generated.StandbyStatus, error) {
+	return r.updateStandbyConfig(ctx, input)
+}
+
+// ReplicationFailback is the resolver for the replicationFailback field.
+func (r *mutationResolver) ReplicationFailback(ctx context.Context) (*generated.ReplicationStatus, error) {
+	status, err := r.ReplicationService.Failback()
+	if err != nil {
+		return nil, err
 	}
+	return convertReplicationStatus(status), nil
+}
 
-	return definitions, nil
+// SetLogLevel is the resolver for the setLogLevel field.
+func (r *mutationResolver) SetLogLevel(ctx context.Context, module *string, level generated.LogLevel) (*generated.LogLevels, error) {
+	name := ""
+	if module != nil {
+		name = *module
+	}
+	logging.SetLevel(name, toSlogLevel(level))
+	return currentLogLevels(), nil
 }
 
-// ProjectsByIds is the resolver for the projectsByIds field.
-func (r *queryResolver) ProjectsByIds(ctx context.Context, ids []string) ([]*models.Project, error) {
-	var projects []*models.Project
+// UpdateFaderWingConfig is the resolver for the updateFaderWingConfig field.
+func (r *mutationResolver) UpdateFaderWingConfig(ctx context.Context, input generated.FaderWingConfigInput) (*generated.FaderWingStatus, error) {
+	return r.updateFaderWingConfig(ctx, input)
+}
 
-	for _, id := range ids {
-		project, err := r.ProjectRepo.FindByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if project != nil {
-			projects = append(projects, project)
-		}
+// ExportProject is the resolver for the exportProject field.
+func (r *mutationResolver) ExportProject(ctx context.Context, projectID string, options *generated.ExportOptionsInput) (*generated.ExportResult, error) {
+	// Get project first to get name
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
 	}
 
-	return projects, nil
+	// Export project
+	exported, stats, err := r.ExportService.ExportProjectWithOptions(ctx, projectID, exportOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	if exported == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	// Convert to JSON
+	jsonContent, err := exported.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.ExportResult{
+		ProjectID:   projectID,
+		ProjectName: project.Name,
+		JSONContent: jsonContent,
+		Stats: generated.ExportStats{
+			FixtureDefinitionsCount: stats.FixtureDefinitionsCount,
+			FixtureInstancesCount:   stats.FixtureInstancesCount,
+			ScenesCount:             stats.ScenesCount,
+			CueListsCount:           stats.CueListsCount,
+			CuesCount:               stats.CuesCount,
+			SceneBoardsCount:        stats.SceneBoardsCount,
+			FixtureGroupsCount:      stats.FixtureGroupsCount,
+			PalettesCount:           stats.PalettesCount,
+			FixtureIds:              stats.FixtureIDs,
+			SceneIds:                stats.SceneIDs,
+			CueListIds:              stats.CueListIDs,
+		},
+	}, nil
 }
 
-// Project is the resolver for the project field.
-func (r *sceneResolver) Project(ctx context.Context, obj *models.Scene) (*models.Project, error) {
-	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+// ImportProject is the resolver for the importProject field.
+func (r *mutationResolver) ImportProject(ctx context.Context, jsonContent string, options generated.ImportOptionsInput) (*generated.ImportResult, error) {
+	return r.runImport(ctx, options, func(opts importservice.ImportOptions) (string, *importservice.ImportStats, []string, error) {
+		return r.ImportService.ImportProject(ctx, jsonContent, opts)
+	})
 }
 
-// FixtureValues is the resolver for the fixtureValues field.
-func (r *sceneResolver) FixtureValues(ctx context.Context, obj *models.Scene) ([]*models.FixtureValue, error) {
-	values, err := r.SceneRepo.GetFixtureValues(ctx, obj.ID)
+// CreateProjectArchiveDownload is the resolver for the createProjectArchiveDownload field.
+func (r *mutationResolver) CreateProjectArchiveDownload(ctx context.Context, projectID string, options *generated.ExportOptionsInput) (*generated.ProjectArchiveDownload, error) {
+	return r.createProjectArchiveDownload(ctx, projectID, options)
+}
+
+// ImportProjectArchive is the resolver for the importProjectArchive field.
+func (r *mutationResolver) ImportProjectArchive(ctx context.Context, file graphql.Upload, options generated.ImportOptionsInput) (*generated.ImportResult, error) {
+	return r.importProjectArchive(ctx, file, options)
+}
+
+// GenerateReport is the resolver for the generateReport field.
+func (r *mutationResolver) GenerateReport(ctx context.Context, projectID string, typeArg generated.ReportType, cueListID *string) (*generated.ReportDownload, error) {
+	return r.generateReport(ctx, projectID, typeArg, cueListID)
+}
+
+// CreateProjectSnapshot is the resolver for the createProjectSnapshot field.
+func (r *mutationResolver) CreateProjectSnapshot(ctx context.Context, projectID string) (*models.ProjectSnapshot, error) {
+	return r.SnapshotService.Take(ctx, projectID, snapshot.ReasonManual)
+}
+
+// RestoreSnapshot is the resolver for the restoreSnapshot field.
+func (r *mutationResolver) RestoreSnapshot(ctx context.Context, id string, projectName *string) (*generated.ImportResult, error) {
+	projectID, stats, warnings, err := r.SnapshotService.Restore(ctx, id, projectName)
 	if err != nil {
 		return nil, err
 	}
-	pointers := make([]*models.FixtureValue, len(values))
-	for i := range values {
-		pointers[i] = &values[i]
+	r.AuthService.GrantOwner(ctx, projectID)
+	r.refreshOutputLimits(ctx)
+	r.publishCreatedEntities(ctx, projectID, nil)
+
+	return importResult(projectID, stats, warnings), nil
+}
+
+// DeleteProjectSnapshot is the resolver for the deleteProjectSnapshot field.
+func (r *mutationResolver) DeleteProjectSnapshot(ctx context.Context, id string) (bool, error) {
+	if err := r.SnapshotRepo.Delete(ctx, id); err != nil {
+		return false, err
 	}
-	return pointers, nil
+	return true, nil
 }
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *sceneResolver) CreatedAt(ctx context.Context, obj *models.Scene) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+// RepairProject is the resolver for the repairProject field.
+func (r *mutationResolver) RepairProject(ctx context.Context, projectID string, fixes []generated.ProjectIssueType) (*generated.ProjectRepairResult, error) {
+	types := make([]integrity.IssueType, len(fixes))
+	for i, fix := range fixes {
+		types[i] = integrity.IssueType(fix)
+	}
+	repaired, err := r.IntegrityService.Repair(ctx, projectID, types)
+	if err != nil {
+		return nil, err
+	}
+	r.publishRepairedEntities(ctx, projectID, repaired)
+	remaining, err := r.IntegrityService.Validate(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.ProjectRepairResult{
+		Repaired:  convertProjectIssues(repaired),
+		Remaining: convertProjectIssues(remaining),
+	}, nil
 }
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *sceneResolver) UpdatedAt(ctx context.Context, obj *models.Scene) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+// CreateBackup is the resolver for the createBackup field.
+func (r *mutationResolver) CreateBackup(ctx context.Context) (*generated.Backup, error) {
+	service, err := r.backups()
+	if err != nil {
+		return nil, err
+	}
+	info, err := service.Create(ctx, backup.ReasonManual)
+	if err != nil {
+		return nil, err
+	}
+	return convertBackup(*info), nil
 }
 
-// Project is the resolver for the project field.
-func (r *sceneBoardResolver) Project(ctx context.Context, obj *models.SceneBoard) (*models.Project, error) {
-	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+// RestoreBackup is the resolver for the restoreBackup field.
+func (r *mutationResolver) RestoreBackup(ctx context.Context, name string, restoreSettings *bool) (*generated.BackupRestoreResult, error) {
+	return r.restoreBackup(ctx, name, restoreSettings != nil && *restoreSettings)
 }
 
-// Buttons is the resolver for the buttons field.
-func (r *sceneBoardResolver) Buttons(ctx context.Context, obj *models.SceneBoard) ([]*models.SceneBoardButton, error) {
-	var buttons []models.SceneBoardButton
-	result := r.db.WithContext(ctx).Where("scene_board_id = ?", obj.ID).Find(&buttons)
-	if result.Error != nil {
-		return nil, result.Error
+// DeleteBackup is the resolver for the deleteBackup field.
+func (r *mutationResolver) DeleteBackup(ctx context.Context, name string) (bool, error) {
+	service, err := r.backups()
+	if err != nil {
+		return false, err
 	}
-	pointers := make([]*models.SceneBoardButton, len(buttons))
-	for i := range buttons {
-		pointers[i] = &buttons[i]
+	if err := service.Delete(ctx, name); err != nil {
+		return false, err
 	}
-	return pointers, nil
+	return true, nil
 }
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *sceneBoardResolver) CreatedAt(ctx context.Context, obj *models.SceneBoard) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, email string, password string) (*generated.AuthPayload, error) {
+	token, expiresAt, user, err := r.AuthService.Login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.AuthPayload{
+		Token:     token,
+		ExpiresAt: expiresAt.Format("2006-01-02T15:04:05.000Z"),
+		User:      *user,
+	}, nil
 }
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *sceneBoardResolver) UpdatedAt(ctx context.Context, obj *models.SceneBoard) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	if err := r.AuthService.ChangePassword(ctx, currentPassword, newPassword); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// SceneBoard is the resolver for the sceneBoard field.
-func (r *sceneBoardButtonResolver) SceneBoard(ctx context.Context, obj *models.SceneBoardButton) (*models.SceneBoard, error) {
-	var board models.SceneBoard
-	result := r.db.First(&board, "id = ?", obj.SceneBoardID)
-	if result.Error != nil {
-		return nil, result.Error
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input generated.CreateUserInput) (*models.User, error) {
+	role := generated.UserRoleUser
+	if input.Role.IsSet() && input.Role.Value() != nil {
+		role = *input.Role.Value()
 	}
-	return &board, nil
+	return r.AuthService.CreateUser(ctx, input.Email, input.Name.Value(), input.Password, string(role))
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
+	user, err := r.UserRepo.FindByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, fmt.Errorf("user not found: %s", id)
+	}
+	if current := auth.UserFromContext(ctx); current != nil && current.ID == id {
+		return false, fmt.Errorf("you cannot delete your own account")
+	}
+	if err := r.UserRepo.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetProjectMember is the resolver for the setProjectMember field.
+func (r *mutationResolver) SetProjectMember(ctx context.Context, projectID string, userID string, role generated.ProjectRole) (*models.ProjectUser, error) {
+	if err := r.checkMembershipChange(ctx, projectID, userID, string(role)); err != nil {
+		return nil, err
+	}
+	return r.UserRepo.SetMembership(ctx, userID, projectID, string(role))
+}
+
+// RemoveProjectMember is the resolver for the removeProjectMember field.
+func (r *mutationResolver) RemoveProjectMember(ctx context.Context, projectID string, userID string) (bool, error) {
+	if err := r.checkMembershipChange(ctx, projectID, userID, ""); err != nil {
+		return false, err
+	}
+	return r.UserRepo.RemoveMembership(ctx, userID, projectID)
 }
 
-// Scene is the resolver for the scene field.
-func (r *sceneBoardButtonResolver) Scene(ctx context.Context, obj *models.SceneBoardButton) (*models.Scene, error) {
-	return r.SceneRepo.FindByID(ctx, obj.SceneID)
+// UpdatePresence is the resolver for the updatePresence field.
+func (r *mutationResolver) UpdatePresence(ctx context.Context, input generated.PresenceInput) (*generated.Presence, error) {
+	update := presence.Update{
+		SessionID: input.SessionID,
+		ProjectID: input.ProjectID,
+		Operator:  audit.OperatorFromContext(ctx),
+		Activity:  presence.ActivityViewing,
+	}
+	if user := auth.UserFromContext(ctx); user != nil {
+		update.UserID = &user.ID
+	}
+	if entityType := input.EntityType.Value(); entityType != nil {
+		update.EntityType = string(*entityType)
+	}
+	if entityID := input.EntityID.Value(); entityID != nil {
+		update.EntityID = *entityID
+	}
+	if activity := input.Activity.Value(); activity != nil {
+		update.Activity = presence.Activity(*activity)
+	}
+	if force := input.Force.Value(); force != nil {
+		update.Force = *force
+	}
+
+	p, err := r.PresenceService.Update(update)
+	if err != nil {
+		return nil, err
+	}
+	return convertPresence(p), nil
+}
+
+// LeavePresence is the resolver for the leavePresence field.
+func (r *mutationResolver) LeavePresence(ctx context.Context, sessionID string) (bool, error) {
+	return r.PresenceService.Leave(sessionID), nil
+}
+
+// ImportProjectFromQlc is the resolver for the importProjectFromQLC field.
+// Returns error - QLC+ import not available on this platform
+func (r *mutationResolver) ImportProjectFromQlc(ctx context.Context, xmlContent string, originalFileName string) (*generated.QLCImportResult, error) {
+	return nil, fmt.Errorf("QLC+ import not available on this platform")
+}
+
+// ExportProjectToQlc is the resolver for the exportProjectToQLC field.
+// Returns error - QLC+ export not available on this platform
+func (r *mutationResolver) ExportProjectToQlc(ctx context.Context, projectID string, fixtureMappings []*generated.FixtureMappingInput) (*generated.QLCExportResult, error) {
+	return nil, fmt.Errorf("QLC+ export not available on this platform")
+}
+
+// UpdateSetting is the resolver for the updateSetting field.
+func (r *mutationResolver) UpdateSetting(ctx context.Context, input generated.UpdateSettingInput) (*models.Setting, error) {
+	return r.SettingsService.Set(ctx, input.Key, input.Value)
+}
+
+// UpdateFadeUpdateRate is the resolver for the updateFadeUpdateRate field.
+func (r *mutationResolver) UpdateFadeUpdateRate(ctx context.Context, rateHz int) (bool, error) {
+	// Validate the rate
+	if err := validateFadeUpdateRate(rateHz); err != nil {
+		return false, err
+	}
+
+	// Update the fade engine
+	r.FadeEngine.SetUpdateRate(rateHz)
+
+	// Save the setting to the database
+	_, err := r.SettingRepo.Upsert(ctx, fade.UpdateRateSettingKey, fmt.Sprintf("%d", rateHz))
+	if err != nil {
+		return false, fmt.Errorf("failed to save fade update rate setting: %w", err)
+	}
+
+	return true, nil
+}
+
+// ResetDeprecatedFieldUsage is the resolver for the resetDeprecatedFieldUsage field.
+func (r *mutationResolver) ResetDeprecatedFieldUsage(ctx context.Context) (bool, error) {
+	r.SchemaInfo.Reset()
+	return true, nil
+}
+
+// ConnectWiFi is the resolver for the connectWiFi field.
+func (r *mutationResolver) ConnectWiFi(ctx context.Context, ssid string, password *string) (*generated.WiFiConnectionResult, error) {
+	result, err := r.WiFiService.ConnectToNetwork(ctx, ssid, password)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.WiFiConnectionResult{
+		Success:   result.Success,
+		Message:   result.Message,
+		Connected: result.Connected,
+	}, nil
+}
+
+// DisconnectWiFi is the resolver for the disconnectWiFi field.
+func (r *mutationResolver) DisconnectWiFi(ctx context.Context) (*generated.WiFiConnectionResult, error) {
+	result, err := r.WiFiService.Disconnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.WiFiConnectionResult{
+		Success:   result.Success,
+		Message:   result.Message,
+		Connected: result.Connected,
+	}, nil
+}
+
+// SetWiFiEnabled is the resolver for the setWiFiEnabled field.
+func (r *mutationResolver) SetWiFiEnabled(ctx context.Context, enabled bool) (*generated.WiFiStatus, error) {
+	status, err := r.WiFiService.SetWiFiEnabled(ctx, enabled)
+	if err != nil {
+		return nil, err
+	}
+	return convertWiFiStatus(status), nil
+}
+
+// ForgetWiFiNetwork is the resolver for the forgetWiFiNetwork field.
+func (r *mutationResolver) ForgetWiFiNetwork(ctx context.Context, ssid string) (bool, error) {
+	return r.WiFiService.ForgetNetwork(ctx, ssid)
+}
+
+// StartAPMode is the resolver for the startAPMode field.
+func (r *mutationResolver) StartAPMode(ctx context.Context) (*generated.WiFiModeResult, error) {
+	result, err := r.WiFiService.StartAPMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.WiFiModeResult{
+		Success: result.Success,
+		Message: result.Message,
+		Mode:    generated.WiFiMode(result.Mode),
+	}, nil
+}
+
+// StopAPMode is the resolver for the stopAPMode field.
+func (r *mutationResolver) StopAPMode(ctx context.Context, connectToSsid *string) (*generated.WiFiModeResult, error) {
+	result, err := r.WiFiService.StopAPMode(ctx, connectToSsid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.WiFiModeResult{
+		Success: result.Success,
+		Message: result.Message,
+		Mode:    generated.WiFiMode(result.Mode),
+	}, nil
+}
+
+// ResetAPTimeout is the resolver for the resetAPTimeout field.
+func (r *mutationResolver) ResetAPTimeout(ctx context.Context) (bool, error) {
+	return r.WiFiService.ResetAPTimeout(ctx)
+}
+
+// UpdateRepository is the resolver for the updateRepository field.
+// Updates a specific repository to a specific version
+func (r *mutationResolver) UpdateRepository(ctx context.Context, repository string, version *string) (*generated.UpdateResult, error) {
+	result, err := r.VersionService.UpdateRepository(repository, version)
+	if err != nil {
+		errMsg := err.Error()
+		return &generated.UpdateResult{
+			Success:         false,
+			Repository:      repository,
+			PreviousVersion: "",
+			NewVersion:      "",
+			Error:           &errMsg,
+		}, nil
+	}
+
+	return &generated.UpdateResult{
+		Success:         result.Success,
+		Repository:      result.Repository,
+		PreviousVersion: result.PreviousVersion,
+		NewVersion:      result.NewVersion,
+		Message:         stringToPointer(result.Message),
+		Error:           stringToPointer(result.Error),
+	}, nil
+}
+
+// UpdateAllRepositories is the resolver for the updateAllRepositories field.
+// Updates all repositories to their latest versions
+func (r *mutationResolver) UpdateAllRepositories(ctx context.Context) ([]*generated.UpdateResult, error) {
+	results, err := r.VersionService.UpdateAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	var gqlResults []*generated.UpdateResult
+	for _, result := range results {
+		gqlResults = append(gqlResults, &generated.UpdateResult{
+			Success:         result.Success,
+			Repository:      result.Repository,
+			PreviousVersion: result.PreviousVersion,
+			NewVersion:      result.NewVersion,
+			Message:         stringToPointer(result.Message),
+			Error:           stringToPointer(result.Error),
+		})
+	}
+
+	return gqlResults, nil
+}
+
+// TriggerOFLImport is the resolver for the triggerOFLImport field.
+func (r *mutationResolver) TriggerOFLImport(ctx context.Context, options *generated.OFLImportOptionsInput) (*generated.OFLImportResult, error) {
+	// Convert GraphQL input to service options
+	var opts *ofl.ImportOptions
+	if options != nil {
+		opts = &ofl.ImportOptions{}
+		// Handle Omittable types - get the underlying value if set
+		if v := options.ForceReimport.Value(); v != nil {
+			opts.ForceReimport = *v
+		}
+		if v := options.UpdateInUseFixtures.Value(); v != nil {
+			opts.UpdateInUseFixtures = *v
+		}
+		if v := options.PreferBundled.Value(); v != nil {
+			opts.PreferBundled = *v
+		}
+		if v := options.Manufacturers.Value(); v != nil {
+			opts.Manufacturers = v
+		}
+	}
+
+	// Trigger the import
+	result, err := r.OFLManager.TriggerImport(ctx, opts)
+	if err != nil {
+		return &generated.OFLImportResult{
+			Success:      false,
+			ErrorMessage: stringPtr(err.Error()),
+			OflVersion:   "unknown",
+			Stats: generated.OFLImportStats{
+				TotalProcessed:    0,
+				SuccessfulImports: 0,
+				FailedImports:     0,
+				SkippedDuplicates: 0,
+				UpdatedFixtures:   0,
+				DurationSeconds:   0,
+			},
+		}, nil
+	}
+
+	return &generated.OFLImportResult{
+		Success:    result.Success,
+		OflVersion: result.OFLVersion,
+		Stats: generated.OFLImportStats{
+			TotalProcessed:    result.Stats.TotalProcessed,
+			SuccessfulImports: result.Stats.SuccessfulImports,
+			FailedImports:     result.Stats.FailedImports,
+			SkippedDuplicates: result.Stats.SkippedDuplicates,
+			UpdatedFixtures:   result.Stats.UpdatedFixtures,
+			DurationSeconds:   result.Stats.DurationSeconds,
+		},
+	}, nil
+}
+
+// CancelOFLImport is the resolver for the cancelOFLImport field.
+func (r *mutationResolver) CancelOFLImport(ctx context.Context) (bool, error) {
+	return r.OFLManager.CancelImport(), nil
+}
+
+// SyncFixtureLibrary is the resolver for the syncFixtureLibrary field.
+func (r *mutationResolver) SyncFixtureLibrary(ctx context.Context, input generated.SyncFixtureLibraryInput) (*generated.FixtureLibrarySyncResult, error) {
+	library, err := r.LibrarySyncService.Fetch(ctx, input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := librarysync.SyncOptions{}
+	if input.Definitions.IsSet() && input.Definitions.Value() != nil {
+		opts.Definitions = make([]librarysync.DefinitionKey, 0, len(input.Definitions.Value()))
+		for _, key := range input.Definitions.Value() {
+			opts.Definitions = append(opts.Definitions, librarysync.DefinitionKey{
+				Manufacturer: key.Manufacturer,
+				Model:        key.Model,
+			})
+		}
+	}
+	if input.Templates.IsSet() {
+		opts.Templates = input.Templates.Value()
+	}
+	if input.OverwriteLocalChanges.IsSet() && input.OverwriteLocalChanges.Value() != nil {
+		opts.OverwriteLocalChanges = *input.OverwriteLocalChanges.Value()
+	}
+
+	result, err := r.LibrarySyncService.Sync(ctx, library, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.FixtureLibrarySyncResult{
+		Created:            result.Created,
+		Updated:            result.Updated,
+		Skipped:            result.Skipped,
+		TemplateProjectIds: result.TemplateProjectIDs,
+		Warnings:           result.Warnings,
+	}, nil
+}
+
+// ExecuteBatch is the resolver for the executeBatch field.
+func (r *mutationResolver) ExecuteBatch(ctx context.Context, operations []*generated.BatchOperationInput) ([]*generated.BatchOperationResult, error) {
+	return r.executeBatch(ctx, operations)
+}
+
+// Kind is the resolver for the kind field.
+func (r *paletteResolver) Kind(ctx context.Context, obj *models.Palette) (generated.PaletteKind, error) {
+	return generated.PaletteKind(obj.Kind), nil
+}
+
+// Channels is the resolver for the channels field.
+func (r *paletteResolver) Channels(ctx context.Context, obj *models.Palette) ([]*generated.TypedChannelValue, error) {
+	return typedChannelValues(obj.Channels)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *paletteResolver) CreatedAt(ctx context.Context, obj *models.Palette) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *paletteResolver) UpdatedAt(ctx context.Context, obj *models.Palette) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Pixels is the resolver for the pixels field.
+func (r *pixelMatrixResolver) Pixels(ctx context.Context, obj *models.PixelMatrix) ([]*generated.PixelMatrixPixel, error) {
+	return convertPixelMatrixPixels(obj)
+}
+
+// IsActive is the resolver for the isActive field.
+func (r *pixelMatrixResolver) IsActive(ctx context.Context, obj *models.PixelMatrix) (bool, error) {
+	return r.PixelMapService.IsActive(obj.ID), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *pixelMatrixResolver) CreatedAt(ctx context.Context, obj *models.PixelMatrix) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *pixelMatrixResolver) UpdatedAt(ctx context.Context, obj *models.PixelMatrix) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Project is the resolver for the project field.
+func (r *previewSessionResolver) Project(ctx context.Context, obj *models.PreviewSession) (*models.Project, error) {
+	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+}
+
+// User is the resolver for the user field.
+func (r *previewSessionResolver) User(ctx context.Context, obj *models.PreviewSession) (*models.User, error) {
+	if obj.UserID == "" {
+		return nil, nil
+	}
+	var user models.User
+	result := r.db.First(&user, "id = ?", obj.UserID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// SceneID is the resolver for the sceneId field.
+func (r *previewSessionResolver) SceneID(ctx context.Context, obj *models.PreviewSession) (*string, error) {
+	if session := r.PreviewService.GetSession(obj.ID); session != nil {
+		return session.SceneID, nil
+	}
+	return nil, nil
+}
+
+// PreviewOutputs is the resolver for the previewOutputs field.
+func (r *previewSessionResolver) PreviewOutputs(ctx context.Context, obj *models.PreviewSession) ([]*generated.PreviewOutput, error) {
+	return r.previewOutputs(obj.ID), nil
+}
+
+// VisualizerAddress is the resolver for the visualizerAddress field.
+func (r *previewSessionResolver) VisualizerAddress(ctx context.Context, obj *models.PreviewSession) (*string, error) {
+	if session := r.PreviewService.GetSession(obj.ID); session != nil && session.VisualizerAddress != "" {
+		return &session.VisualizerAddress, nil
+	}
+	return nil, nil
+}
+
+// DmxOutput is the resolver for the dmxOutput field.
+func (r *previewSessionResolver) DmxOutput(ctx context.Context, obj *models.PreviewSession) ([]*generated.UniverseOutput, error) {
+	// Get DMX output from preview service
+	previewOutput := r.PreviewService.GetDMXOutput(obj.ID)
+	if previewOutput == nil {
+		return []*generated.UniverseOutput{}, nil
+	}
+
+	// Convert to GraphQL type
+	result := make([]*generated.UniverseOutput, len(previewOutput))
+	for i, output := range previewOutput {
+		result[i] = &generated.UniverseOutput{
+			Universe: output.Universe,
+			Channels: output.Channels,
+		}
+	}
+	return result, nil
+}
+
+// FixtureCount is the resolver for the fixtureCount field.
+func (r *projectResolver) FixtureCount(ctx context.Context, obj *models.Project) (int, error) {
+	count, err := r.ProjectRepo.CountFixtures(ctx, obj.ID)
+	return int(count), err
+}
+
+// SceneCount is the resolver for the sceneCount field.
+func (r *projectResolver) SceneCount(ctx context.Context, obj *models.Project) (int, error) {
+	count, err := r.ProjectRepo.CountScenes(ctx, obj.ID)
+	return int(count), err
+}
+
+// CueListCount is the resolver for the cueListCount field.
+func (r *projectResolver) CueListCount(ctx context.Context, obj *models.Project) (int, error) {
+	count, err := r.ProjectRepo.CountCueLists(ctx, obj.ID)
+	return int(count), err
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *projectResolver) CreatedAt(ctx context.Context, obj *models.Project) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *projectResolver) UpdatedAt(ctx context.Context, obj *models.Project) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Fixtures is the resolver for the fixtures field.
+func (r *projectResolver) Fixtures(ctx context.Context, obj *models.Project) ([]*models.FixtureInstance, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.FixtureInstance, len(fixtures))
+	for i := range fixtures {
+		pointers[i] = &fixtures[i]
+	}
+	return pointers, nil
+}
+
+// Scenes is the resolver for the scenes field.
+func (r *projectResolver) Scenes(ctx context.Context, obj *models.Project) ([]*models.Scene, error) {
+	scenes, err := r.SceneRepo.FindByProjectID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.Scene, len(scenes))
+	for i := range scenes {
+		pointers[i] = &scenes[i]
+	}
+	return pointers, nil
+}
+
+// CueLists is the resolver for the cueLists field.
+func (r *projectResolver) CueLists(ctx context.Context, obj *models.Project) ([]*models.CueList, error) {
+	cueLists, err := r.CueListRepo.FindByProjectID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.CueList, len(cueLists))
+	for i := range cueLists {
+		pointers[i] = &cueLists[i]
+	}
+	return pointers, nil
+}
+
+// SceneBoards is the resolver for the sceneBoards field.
+func (r *projectResolver) SceneBoards(ctx context.Context, obj *models.Project) ([]*models.SceneBoard, error) {
+	var boards []models.SceneBoard
+	result := r.db.Where("project_id = ?", obj.ID).Order("created_at DESC").Find(&boards)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.SceneBoard, len(boards))
+	for i := range boards {
+		pointers[i] = &boards[i]
+	}
+	return pointers, nil
+}
+
+// Users is the resolver for the users field.
+func (r *projectResolver) Users(ctx context.Context, obj *models.Project) ([]*models.ProjectUser, error) {
+	var users []models.ProjectUser
+	result := r.db.Where("project_id = ?", obj.ID).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.ProjectUser, len(users))
+	for i := range users {
+		pointers[i] = &users[i]
+	}
+	return pointers, nil
+}
+
+// NamingConvention is the resolver for the namingConvention field.
+func (r *projectResolver) NamingConvention(ctx context.Context, obj *models.Project) (*generated.NamingConvention, error) {
+	return convertNamingConvention(obj), nil
+}
+
+// Reason is the resolver for the reason field.
+func (r *projectSnapshotResolver) Reason(ctx context.Context, obj *models.ProjectSnapshot) (generated.SnapshotReason, error) {
+	return generated.SnapshotReason(obj.Reason), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *projectSnapshotResolver) CreatedAt(ctx context.Context, obj *models.ProjectSnapshot) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// User is the resolver for the user field.
+func (r *projectUserResolver) User(ctx context.Context, obj *models.ProjectUser) (*models.User, error) {
+	var user models.User
+	result := r.db.First(&user, "id = ?", obj.UserID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// Project is the resolver for the project field.
+func (r *projectUserResolver) Project(ctx context.Context, obj *models.ProjectUser) (*models.Project, error) {
+	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+}
+
+// Role is the resolver for the role field.
+func (r *projectUserResolver) Role(ctx context.Context, obj *models.ProjectUser) (generated.ProjectRole, error) {
+	if obj.Role != "" {
+		return generated.ProjectRole(obj.Role), nil
+	}
+	return generated.ProjectRoleViewer, nil
+}
+
+// JoinedAt is the resolver for the joinedAt field.
+func (r *projectUserResolver) JoinedAt(ctx context.Context, obj *models.ProjectUser) (string, error) {
+	return obj.JoinedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// Projects is the resolver for the projects field.
+func (r *queryResolver) Projects(ctx context.Context) ([]*models.Project, error) {
+	projects, err := r.ProjectRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Convert to pointers
+	result := make([]*models.Project, len(projects))
+	for i := range projects {
+		result[i] = &projects[i]
+	}
+	return result, nil
+}
+
+// Project is the resolver for the project field.
+func (r *queryResolver) Project(ctx context.Context, id string) (*models.Project, error) {
+	return r.ProjectRepo.FindByID(ctx, id)
+}
+
+// NextSceneName is the resolver for the nextSceneName field.
+func (r *queryResolver) NextSceneName(ctx context.Context, projectID string) (string, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if project == nil {
+		return "", fmt.Errorf("project not found: %s", projectID)
+	}
+	return r.sceneNameForCreate(ctx, projectID, "")
+}
+
+// NextCueName is the resolver for the nextCueName field.
+func (r *queryResolver) NextCueName(ctx context.Context, cueListID string, cueNumber *float64) (string, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
+	if err != nil {
+		return "", err
+	}
+	if cueList == nil {
+		return "", fmt.Errorf("cue list not found: %s", cueListID)
+	}
+	return r.cueNameForCreate(ctx, cueList, cueNumber, "")
+}
+
+// FixtureDefinitions is the resolver for the fixtureDefinitions field.
+func (r *queryResolver) FixtureDefinitions(ctx context.Context, filter *generated.FixtureDefinitionFilter) ([]*models.FixtureDefinition, error) {
+	defs, err := r.FixtureRepo.FindAllDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply filters if provided
+	var filtered []models.FixtureDefinition
+	for _, def := range defs {
+		// Apply manufacturer filter (case-insensitive partial match for autocomplete)
+		if filter != nil && filter.Manufacturer.IsSet() {
+			if mfr := filter.Manufacturer.Value(); mfr != nil && *mfr != "" {
+				if !strings.Contains(strings.ToLower(def.Manufacturer), strings.ToLower(*mfr)) {
+					continue
+				}
+			}
+		}
+		// Apply model filter (case-insensitive partial match for autocomplete)
+		if filter != nil && filter.Model.IsSet() {
+			if model := filter.Model.Value(); model != nil && *model != "" {
+				if !strings.Contains(strings.ToLower(def.Model), strings.ToLower(*model)) {
+					continue
+				}
+			}
+		}
+		// Apply type filter
+		if filter != nil && filter.Type.IsSet() {
+			if fixtureType := filter.Type.Value(); fixtureType != nil {
+				if def.Type != string(*fixtureType) {
+					continue
+				}
+			}
+		}
+		// Apply isBuiltIn filter
+		if filter != nil && filter.IsBuiltIn.IsSet() {
+			if isBuiltIn := filter.IsBuiltIn.Value(); isBuiltIn != nil {
+				if def.IsBuiltIn != *isBuiltIn {
+					continue
+				}
+			}
+		}
+		filtered = append(filtered, def)
+	}
+
+	// Convert to pointers
+	result := make([]*models.FixtureDefinition, len(filtered))
+	for i := range filtered {
+		result[i] = &filtered[i]
+	}
+	return result, nil
+}
+
+// FixtureDefinition is the resolver for the fixtureDefinition field.
+func (r *queryResolver) FixtureDefinition(ctx context.Context, id string) (*models.FixtureDefinition, error) {
+	return r.FixtureRepo.FindDefinitionByID(ctx, id)
+}
+
+// FixtureDefinitionUsage is the resolver for the fixtureDefinitionUsage field.
+func (r *queryResolver) FixtureDefinitionUsage(ctx context.Context, id string) ([]*generated.FixtureDefinitionUsage, error) {
+	definition, err := r.FixtureRepo.FindDefinitionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("fixture definition not found: %s", id)
+	}
+	return r.definitionUsage(ctx, id)
+}
+
+// FixtureInstances is the resolver for the fixtureInstances field.
+func (r *queryResolver) FixtureInstances(ctx context.Context, projectID string, page *int, perPage *int, filter *generated.FixtureFilterInput) (*generated.FixtureInstancePage, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	pageNum := 1
+	pageSize := 50
+	if page != nil {
+		pageNum = *page
+	}
+	if perPage != nil {
+		pageSize = *perPage
+	}
+
+	total := len(fixtures)
+	start := (pageNum - 1) * pageSize
+	end := start + pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	// Convert to pointers
+	items := make([]*models.FixtureInstance, end-start)
+	for i := start; i < end; i++ {
+		items[i-start] = &fixtures[i]
+	}
+
+	return &generated.FixtureInstancePage{
+		Fixtures: items,
+		Pagination: generated.PaginationInfo{
+			Total:   total,
+			Page:    pageNum,
+			PerPage: pageSize,
+			HasMore: end < total,
+		},
+	}, nil
+}
+
+// FixtureInstance is the resolver for the fixtureInstance field.
+func (r *queryResolver) FixtureInstance(ctx context.Context, id string) (*models.FixtureInstance, error) {
+	return r.FixtureRepo.FindByID(ctx, id)
+}
+
+// SearchFixtures is the resolver for the searchFixtures field.
+func (r *queryResolver) SearchFixtures(ctx context.Context, projectID string, query string, filter *generated.FixtureFilterInput, page *int, perPage *int) (*generated.FixtureInstancePage, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by search query (name, manufacturer, model)
+	var filtered []models.FixtureInstance
+	queryLower := strings.ToLower(query)
+	for _, f := range fixtures {
+		if strings.Contains(strings.ToLower(f.Name), queryLower) {
+			filtered = append(filtered, f)
+			continue
+		}
+		if f.Manufacturer != nil && strings.Contains(strings.ToLower(*f.Manufacturer), queryLower) {
+			filtered = append(filtered, f)
+			continue
+		}
+		if f.Model != nil && strings.Contains(strings.ToLower(*f.Model), queryLower) {
+			filtered = append(filtered, f)
+			continue
+		}
+	}
+
+	// Apply pagination
+	pageNum := 1
+	pageSize := 50
+	if page != nil {
+		pageNum = *page
+	}
+	if perPage != nil {
+		pageSize = *perPage
+	}
+
+	total := len(filtered)
+	start := (pageNum - 1) * pageSize
+	end := start + pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	items := make([]*models.FixtureInstance, end-start)
+	for i := start; i < end; i++ {
+		items[i-start] = &filtered[i]
+	}
+
+	return &generated.FixtureInstancePage{
+		Fixtures: items,
+		Pagination: generated.PaginationInfo{
+			Total:   total,
+			Page:    pageNum,
+			PerPage: pageSize,
+			HasMore: end < total,
+		},
+	}, nil
+}
+
+// ChannelMap is the resolver for the channelMap field.
+func (r *queryResolver) ChannelMap(ctx context.Context, projectID string, universe *int) (*generated.ChannelMapResult, error) {
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build fixtures per universe
+	universeFixtures := make(map[int][]*generated.ChannelMapFixture)
+	universeUsedChannels := make(map[int]int)
+
+	for _, f := range fixtures {
+		// Skip if filtering by universe and this fixture doesn't match
+		if universe != nil && f.Universe != *universe {
+			continue
+		}
+
+		channelCount := 1
+		if f.ChannelCount != nil {
+			channelCount = *f.ChannelCount
+		}
+
+		fixtureType := generated.FixtureTypeOther
+		if f.Type != nil {
+			fixtureType = generated.FixtureType(*f.Type)
+		}
+
+		fixture := &generated.ChannelMapFixture{
+			ID:           f.ID,
+			Name:         f.Name,
+			Type:         fixtureType,
+			StartChannel: f.StartChannel,
+			EndChannel:   f.StartChannel + channelCount - 1,
+			ChannelCount: channelCount,
+		}
+		universeFixtures[f.Universe] = append(universeFixtures[f.Universe], fixture)
+		universeUsedChannels[f.Universe] += channelCount
+	}
+
+	// Build universe outputs
+	var universes []*generated.UniverseChannelMap
+	for uni, fixtureList := range universeFixtures {
+		usedChannels := universeUsedChannels[uni]
+		universes = append(universes, &generated.UniverseChannelMap{
+			Universe:          uni,
+			Fixtures:          fixtureList,
+			ChannelUsage:      []*generated.ChannelUsage{}, // TODO: Add channel usage details
+			AvailableChannels: 512 - usedChannels,
+			UsedChannels:      usedChannels,
+		})
+	}
+
+	return &generated.ChannelMapResult{
+		ProjectID: projectID,
+		Universes: universes,
+	}, nil
+}
+
+// SuggestChannelAssignment is the resolver for the suggestChannelAssignment field.
+func (r *queryResolver) SuggestChannelAssignment(ctx context.Context, input generated.ChannelAssignmentInput) (*generated.ChannelAssignmentSuggestion, error) {
+	// Get existing fixtures to find used channels
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build a map of used channels per universe
+	usedChannels := make(map[int]map[int]bool)
+	for _, f := range fixtures {
+		if usedChannels[f.Universe] == nil {
+			usedChannels[f.Universe] = make(map[int]bool)
+		}
+		channelCount := 1
+		if f.ChannelCount != nil {
+			channelCount = *f.ChannelCount
+		}
+		for ch := 0; ch < channelCount; ch++ {
+			usedChannels[f.Universe][f.StartChannel+ch] = true
+		}
+	}
+
+	// Find suggested assignments for each fixture spec
+	var assignments []*generated.FixtureChannelAssignment
+	universe := 1
+	if input.Universe.IsSet() && input.Universe.Value() != nil {
+		universe = *input.Universe.Value()
+	}
+	startChannel := 1
+	if input.StartingChannel.IsSet() && input.StartingChannel.Value() != nil {
+		startChannel = *input.StartingChannel.Value()
+	}
+
+	totalChannelsNeeded := 0
+	for _, spec := range input.FixtureSpecs {
+		channelCount := 1
+		if spec.ChannelCount.IsSet() && spec.ChannelCount.Value() != nil {
+			channelCount = *spec.ChannelCount.Value()
+		}
+		totalChannelsNeeded += channelCount
+
+		// Find next available block of channels
+		for {
+			available := true
+			for ch := 0; ch < channelCount; ch++ {
+				if startChannel+ch > 512 {
+					// Move to next universe
+					universe++
+					startChannel = 1
+					if usedChannels[universe] == nil {
+						usedChannels[universe] = make(map[int]bool)
+					}
+					available = false
+					break
+				}
+				if usedChannels[universe][startChannel+ch] {
+					available = false
+					break
+				}
+			}
+			if available {
+				break
+			}
+			startChannel++
+		}
+
+		endChannel := startChannel + channelCount - 1
+		channelRange := fmt.Sprintf("%d-%d", startChannel, endChannel)
+
+		// Mode is Omittable[*string], need to extract the *string
+		var mode *string
+		if spec.Mode.IsSet() {
+			mode = spec.Mode.Value()
+		}
+
+		assignments = append(assignments, &generated.FixtureChannelAssignment{
+			FixtureName:  spec.Name,
+			Manufacturer: spec.Manufacturer,
+			Model:        spec.Model,
+			Mode:         mode,
+			StartChannel: startChannel,
+			EndChannel:   endChannel,
+			ChannelCount: channelCount,
+			ChannelRange: channelRange,
+		})
+
+		// Mark these channels as used
+		for ch := 0; ch < channelCount; ch++ {
+			usedChannels[universe][startChannel+ch] = true
+		}
+		startChannel += channelCount
+	}
+
+	// Calculate available channels remaining
+	availableRemaining := 512 - len(usedChannels[universe])
+
+	return &generated.ChannelAssignmentSuggestion{
+		Universe:                   universe,
+		Assignments:                assignments,
+		TotalChannelsNeeded:        totalChannelsNeeded,
+		AvailableChannelsRemaining: availableRemaining,
+	}, nil
+}
+
+// SuggestNextAddress is the resolver for the suggestNextAddress field.
+func (r *queryResolver) SuggestNextAddress(ctx context.Context, projectID string, channelCount int, universe *int, startChannel *int) (*generated.DmxAddressSuggestion, error) {
+	return r.suggestNextAddress(ctx, projectID, channelCount, universe, startChannel)
+}
+
+// ExportPatchSheet is the resolver for the exportPatchSheet field.
+func (r *queryResolver) ExportPatchSheet(ctx context.Context, projectID string) (string, error) {
+	return r.exportPatchSheet(ctx, projectID)
+}
+
+// ArtNetRoutingReport is the resolver for the artNetRoutingReport field.
+func (r *queryResolver) ArtNetRoutingReport(ctx context.Context, projectID string, nodes []*generated.ArtNetNodeInput) (*generated.ArtNetRoutingReport, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	fixtures, err := r.FixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtureCounts := make(map[int]int)
+	var patchedUniverses []int
+	for _, f := range fixtures {
+		if fixtureCounts[f.Universe] == 0 {
+			patchedUniverses = append(patchedUniverses, f.Universe)
+		}
+		fixtureCounts[f.Universe]++
+	}
+
+	// Convert discovered nodes, keeping a lookup back to the GraphQL shape
+	artnetNodes := make([]*artnet.Node, 0, len(nodes))
+	nodeInfo := make(map[*artnet.Node]*generated.ArtNetNodeInfo)
+	for _, n := range nodes {
+		ip := net.ParseIP(n.IPAddress)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid node IP address: %s", n.IPAddress)
+		}
+		node := &artnet.Node{IP: ip, OutputUniverses: n.OutputUniverses}
+		if n.ShortName.IsSet() && n.ShortName.Value() != nil {
+			node.ShortName = *n.ShortName.Value()
+		}
+		if n.LongName.IsSet() && n.LongName.Value() != nil {
+			node.LongName = *n.LongName.Value()
+		}
+		artnetNodes = append(artnetNodes, node)
+		nodeInfo[node] = &generated.ArtNetNodeInfo{
+			IPAddress:       ip.String(),
+			ShortName:       n.ShortName.Value(),
+			LongName:        n.LongName.Value(),
+			OutputUniverses: n.OutputUniverses,
+		}
+	}
+
+	proposal := artnet.ProposeRouting(patchedUniverses, artnetNodes)
+
+	report := &generated.ArtNetRoutingReport{
+		ProjectID:         projectID,
+		Routes:            []*generated.ArtNetUniverseRoute{},
+		UnroutedUniverses: []int{},
+		UnusedNodes:       []*generated.ArtNetNodeInfo{},
+	}
+	for _, route := range proposal.Routes {
+		gqlRoute := &generated.ArtNetUniverseRoute{
+			Universe:     route.Universe,
+			FixtureCount: fixtureCounts[route.Universe],
+			IsRouted:     len(route.Nodes) > 0,
+			Nodes:        []*generated.ArtNetNodeInfo{},
+		}
+		for _, node := range route.Nodes {
+			gqlRoute.Nodes = append(gqlRoute.Nodes, nodeInfo[node])
+		}
+		report.Routes = append(report.Routes, gqlRoute)
+	}
+	report.UnroutedUniverses = append(report.UnroutedUniverses, proposal.Unrouted...)
+	for _, node := range proposal.UnusedNodes {
+		report.UnusedNodes = append(report.UnusedNodes, nodeInfo[node])
+	}
+
+	return report, nil
+}
+
+// ArtNetUnicastRoutes is the resolver for the artNetUnicastRoutes field.
+func (r *queryResolver) ArtNetUnicastRoutes(ctx context.Context) ([]*generated.ArtNetUnicastRoute, error) {
+	return convertUnicastRoutes(r.DMXService.UnicastRoutes()), nil
+}
+
+// ArtNetSync is the resolver for the artNetSync field.
+func (r *queryResolver) ArtNetSync(ctx context.Context) (bool, error) {
+	return r.DMXService.ArtSyncEnabled(), nil
+}
+
+// ArtNetBinding is the resolver for the artNetBinding field.
+func (r *queryResolver) ArtNetBinding(ctx context.Context) (*generated.ArtNetBinding, error) {
+	return convertArtNetBinding(r.DMXService.Binding()), nil
+}
+
+// ArtNetInterfaces is the resolver for the artNetInterfaces field.
+func (r *queryResolver) ArtNetInterfaces(ctx context.Context) ([]*generated.ArtNetInterface, error) {
+	return r.artNetInterfaces()
+}
+
+// DmxTransmitStats is the resolver for the dmxTransmitStats field.
+func (r *queryResolver) DmxTransmitStats(ctx context.Context) (*generated.DmxTransmitStats, error) {
+	return convertTransmitStats(r.DMXService.TransmitStats()), nil
+}
+
+// ChannelLimits is the resolver for the channelLimits field.
+func (r *queryResolver) ChannelLimits(ctx context.Context) ([]*generated.ChannelLimit, error) {
+	return convertChannelLimits(r.DMXService.ChannelLimits()), nil
+}
+
+// ParkedChannels is the resolver for the parkedChannels field.
+func (r *queryResolver) ParkedChannels(ctx context.Context) ([]*generated.ParkedChannel, error) {
+	return convertParkedChannels(r.DMXService.ParkedChannels()), nil
+}
+
+// MasterLevels is the resolver for the masterLevels field.
+func (r *queryResolver) MasterLevels(ctx context.Context) (*generated.MasterLevels, error) {
+	return convertMasterLevels(r.DMXService.MasterLevels()), nil
+}
+
+// BlackoutStatus is the resolver for the blackoutStatus field.
+func (r *queryResolver) BlackoutStatus(ctx context.Context) (*generated.BlackoutStatus, error) {
+	return convertBlackoutStatus(r.DMXService.BlackoutStatus()), nil
+}
+
+// Independents is the resolver for the independents field.
+func (r *queryResolver) Independents(ctx context.Context, projectID *string) ([]*generated.Independent, error) {
+	return r.independents(projectID), nil
+}
+
+// TimecodeStatus is the resolver for the timecodeStatus field.
+func (r *queryResolver) TimecodeStatus(ctx context.Context) (*generated.TimecodeStatus, error) {
+	return convertTimecodeStatus(r.TimecodeService.Status()), nil
+}
+
+// ControlSurfaceState is the resolver for the controlSurfaceState field.
+func (r *queryResolver) ControlSurfaceState(ctx context.Context) (*generated.ControlSurfaceState, error) {
+	return r.controlSurfaceState(ctx)
+}
+
+// AudioInputStatus is the resolver for the audioInputStatus field.
+func (r *queryResolver) AudioInputStatus(ctx context.Context) (*generated.AudioInputStatus, error) {
+	return convertAudioStatus(r.AudioService.Status()), nil
+}
+
+// Scenes is the resolver for the scenes field.
+func (r *queryResolver) Scenes(ctx context.Context, projectID string, page *int, perPage *int, filter *generated.SceneFilterInput, sortBy *generated.SceneSortField) (*generated.ScenePage, error) {
+	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortField := generated.SceneSortFieldCreatedAt
+	if sortBy != nil {
+		sortField = *sortBy
+	} else {
+		project, err := r.ProjectRepo.FindByID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		sortField = projectDefaultSceneSort(project)
+	}
+	sortScenes(scenes, sortField)
+
+	// Apply pagination
+	pageNum := 1
+	pageSize := 50
+	if page != nil {
+		pageNum = *page
+	}
+	if perPage != nil {
+		pageSize = *perPage
+	}
+
+	total := len(scenes)
+	start := (pageNum - 1) * pageSize
+	end := start + pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	// Convert to SceneSummary
+	items := make([]*generated.SceneSummary, end-start)
+	for i := start; i < end; i++ {
+		scene := &scenes[i]
+		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
+		items[i-start] = &generated.SceneSummary{
+			ID:             scene.ID,
+			Name:           scene.Name,
+			SecondaryLabel: scene.SecondaryLabel,
+			Description:    scene.Description,
+			FixtureCount:   int(fixtureCount),
+			CreatedAt:      scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			UpdatedAt:      formatUpdatedAt(scene.UpdatedAt),
+		}
+	}
+
+	return &generated.ScenePage{
+		Scenes: items,
+		Pagination: generated.PaginationInfo{
+			Total:   total,
+			Page:    pageNum,
+			PerPage: pageSize,
+			HasMore: end < total,
+		},
+	}, nil
+}
+
+// Scene is the resolver for the scene field.
+func (r *queryResolver) Scene(ctx context.Context, id string, includeFixtureValues *bool) (*models.Scene, error) {
+	return r.SceneRepo.FindByID(ctx, id)
+}
+
+// SceneFixtures is the resolver for the sceneFixtures field.
+func (r *queryResolver) SceneFixtures(ctx context.Context, sceneID string) ([]*generated.SceneFixtureSummary, error) {
+	// Get fixture values for the scene
+	var fixtureValues []models.FixtureValue
+	result := r.db.Where("scene_id = ?", sceneID).Find(&fixtureValues)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	summaries := make([]*generated.SceneFixtureSummary, 0, len(fixtureValues))
+	for _, fv := range fixtureValues {
+		fixture, err := r.FixtureRepo.FindByID(ctx, fv.FixtureID)
+		if err != nil || fixture == nil {
+			continue
+		}
+
+		fixtureType := generated.FixtureTypeOther
+		if fixture.Type != nil {
+			fixtureType = generated.FixtureType(*fixture.Type)
+		}
+
+		summaries = append(summaries, &generated.SceneFixtureSummary{
+			FixtureID:   fixture.ID,
+			FixtureName: fixture.Name,
+			FixtureType: fixtureType,
+		})
+	}
+
+	return summaries, nil
+}
+
+// SearchScenes is the resolver for the searchScenes field.
+func (r *queryResolver) SearchScenes(ctx context.Context, projectID string, query string, filter *generated.SceneFilterInput, page *int, perPage *int) (*generated.ScenePage, error) {
+	scenes, err := r.SceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by search query (name, description)
+	var filtered []models.Scene
+	queryLower := strings.ToLower(query)
+	for _, s := range scenes {
+		if strings.Contains(strings.ToLower(s.Name), queryLower) {
+			filtered = append(filtered, s)
+			continue
+		}
+		if s.Description != nil && strings.Contains(strings.ToLower(*s.Description), queryLower) {
+			filtered = append(filtered, s)
+			continue
+		}
+	}
+
+	// Apply pagination
+	pageNum := 1
+	pageSize := 50
+	if page != nil {
+		pageNum = *page
+	}
+	if perPage != nil {
+		pageSize = *perPage
+	}
+
+	total := len(filtered)
+	start := (pageNum - 1) * pageSize
+	end := start + pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	items := make([]*generated.SceneSummary, end-start)
+	for i := start; i < end; i++ {
+		scene := &filtered[i]
+		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
+		items[i-start] = &generated.SceneSummary{
+			ID:             scene.ID,
+			Name:           scene.Name,
+			SecondaryLabel: scene.SecondaryLabel,
+			Description:    scene.Description,
+			FixtureCount:   int(fixtureCount),
+			CreatedAt:      scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			UpdatedAt:      formatUpdatedAt(scene.UpdatedAt),
+		}
+	}
+
+	return &generated.ScenePage{
+		Scenes: items,
+		Pagination: generated.PaginationInfo{
+			Total:   total,
+			Page:    pageNum,
+			PerPage: pageSize,
+			HasMore: end < total,
+		},
+	}, nil
+}
+
+// SceneBoards is the resolver for the sceneBoards field.
+func (r *queryResolver) SceneBoards(ctx context.Context, projectID string) ([]*models.SceneBoard, error) {
+	var boards []models.SceneBoard
+	result := r.db.Where("project_id = ?", projectID).Order("created_at DESC").Find(&boards)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Convert to pointers
+	pointers := make([]*models.SceneBoard, len(boards))
+	for i := range boards {
+		pointers[i] = &boards[i]
+	}
+	return pointers, nil
+}
+
+// SceneBoard is the resolver for the sceneBoard field.
+func (r *queryResolver) SceneBoard(ctx context.Context, id string) (*models.SceneBoard, error) {
+	var board models.SceneBoard
+	result := r.db.Preload("Buttons").First(&board, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &board, nil
+}
+
+// SceneBoardButton is the resolver for the sceneBoardButton field.
+func (r *queryResolver) SceneBoardButton(ctx context.Context, id string) (*models.SceneBoardButton, error) {
+	var button models.SceneBoardButton
+	result := r.db.First(&button, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &button, nil
+}
+
+// Effects is the resolver for the effects field.
+func (r *queryResolver) Effects(ctx context.Context, projectID string) ([]*models.Effect, error) {
+	var stored []models.Effect
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("created_at ASC").Find(&stored)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.Effect, len(stored))
+	for i := range stored {
+		pointers[i] = &stored[i]
+	}
+	return pointers, nil
+}
+
+// Effect is the resolver for the effect field.
+func (r *queryResolver) Effect(ctx context.Context, id string) (*models.Effect, error) {
+	var effect models.Effect
+	result := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&effect)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &effect, nil
+}
+
+// Submasters is the resolver for the submasters field.
+func (r *queryResolver) Submasters(ctx context.Context, projectID string) ([]*models.Submaster, error) {
+	return r.findSubmasters(ctx, projectID)
+}
+
+// Submaster is the resolver for the submaster field.
+func (r *queryResolver) Submaster(ctx context.Context, id string) (*models.Submaster, error) {
+	var sub models.Submaster
+	result := r.db.WithContext(ctx).First(&sub, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &sub, nil
+}
+
+// SubmasterPages is the resolver for the submasterPages field.
+func (r *queryResolver) SubmasterPages(ctx context.Context, projectID string) ([]*generated.SubmasterPage, error) {
+	return r.submasterPages(ctx, projectID)
+}
+
+// Schedules is the resolver for the schedules field.
+func (r *queryResolver) Schedules(ctx context.Context, projectID string) ([]*models.Schedule, error) {
+	var stored []models.Schedule
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("created_at ASC").Find(&stored)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.Schedule, len(stored))
+	for i := range stored {
+		pointers[i] = &stored[i]
+	}
+	return pointers, nil
+}
+
+// Schedule is the resolver for the schedule field.
+func (r *queryResolver) Schedule(ctx context.Context, id string) (*models.Schedule, error) {
+	var schedule models.Schedule
+	result := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&schedule)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+// Universes is the resolver for the universes field.
+func (r *queryResolver) Universes(ctx context.Context, projectID string) ([]*models.Universe, error) {
+	universes, err := r.UniverseRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.Universe, len(universes))
+	for i := range universes {
+		pointers[i] = &universes[i]
+	}
+	return pointers, nil
+}
+
+// LayoutZones is the resolver for the layoutZones field.
+func (r *queryResolver) LayoutZones(ctx context.Context, projectID string) ([]*models.LayoutZone, error) {
+	zones, err := r.LayoutZoneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.LayoutZone, len(zones))
+	for i := range zones {
+		result[i] = &zones[i]
+	}
+	return result, nil
+}
+
+// SelectionSets is the resolver for the selectionSets field.
+func (r *queryResolver) SelectionSets(ctx context.Context, projectID string) ([]*models.SelectionSet, error) {
+	sets, err := r.SelectionSetRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.SelectionSet, len(sets))
+	for i := range sets {
+		result[i] = &sets[i]
+	}
+	return result, nil
+}
+
+// SelectionSet is the resolver for the selectionSet field.
+func (r *queryResolver) SelectionSet(ctx context.Context, id string) (*models.SelectionSet, error) {
+	return r.SelectionSetRepo.FindByID(ctx, id)
+}
+
+// OrderFixtures is the resolver for the orderFixtures field.
+func (r *queryResolver) OrderFixtures(ctx context.Context, fixtureIds []string, order generated.SelectionOrder) ([]string, error) {
+	return r.orderFixtureIDs(ctx, fixtureIds, order)
+}
+
+// HouseLights is the resolver for the houseLights field.
+func (r *queryResolver) HouseLights(ctx context.Context, projectID string) (*models.HouseLights, error) {
+	return r.findHouseLights(ctx, projectID)
+}
+
+// PixelMatrices is the resolver for the pixelMatrices field.
+func (r *queryResolver) PixelMatrices(ctx context.Context, projectID string) ([]*models.PixelMatrix, error) {
+	matrices, err := r.PixelMatrixRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.PixelMatrix, len(matrices))
+	for i := range matrices {
+		result[i] = &matrices[i]
+	}
+	return result, nil
+}
+
+// PixelMatrix is the resolver for the pixelMatrix field.
+func (r *queryResolver) PixelMatrix(ctx context.Context, id string) (*models.PixelMatrix, error) {
+	return r.PixelMatrixRepo.FindByID(ctx, id)
+}
+
+// SoftPatches is the resolver for the softPatches field.
+func (r *queryResolver) SoftPatches(ctx context.Context, projectID string) ([]*models.SoftPatch, error) {
+	var stored []models.SoftPatch
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("universe ASC, channel ASC").Find(&stored)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.SoftPatch, len(stored))
+	for i := range stored {
+		pointers[i] = &stored[i]
+	}
+	return pointers, nil
+}
+
+// PatchedDmxOutput is the resolver for the patchedDmxOutput field.
+func (r *queryResolver) PatchedDmxOutput(ctx context.Context, universe int) ([]int, error) {
+	return r.DMXService.PatchedUniverse(universe), nil
+}
+
+// FixtureGroups is the resolver for the fixtureGroups field.
+func (r *queryResolver) FixtureGroups(ctx context.Context, projectID string) ([]*models.FixtureGroup, error) {
+	groups, err := r.FixtureGroupRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.FixtureGroup, len(groups))
+	for i := range groups {
+		result[i] = &groups[i]
+	}
+	return result, nil
+}
+
+// FixtureGroup is the resolver for the fixtureGroup field.
+func (r *queryResolver) FixtureGroup(ctx context.Context, id string) (*models.FixtureGroup, error) {
+	return r.FixtureGroupRepo.FindByID(ctx, id)
+}
+
+// Palettes is the resolver for the palettes field.
+func (r *queryResolver) Palettes(ctx context.Context, projectID string, kind *generated.PaletteKind) ([]*models.Palette, error) {
+	var kindFilter *string
+	if kind != nil {
+		k := string(*kind)
+		kindFilter = &k
+	}
+	palettes, err := r.PaletteRepo.FindByProjectID(ctx, projectID, kindFilter)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Palette, len(palettes))
+	for i := range palettes {
+		result[i] = &palettes[i]
+	}
+	return result, nil
+}
+
+// Palette is the resolver for the palette field.
+func (r *queryResolver) Palette(ctx context.Context, id string) (*models.Palette, error) {
+	return r.PaletteRepo.FindByID(ctx, id)
+}
+
+// UndoStack is the resolver for the undoStack field.
+func (r *queryResolver) UndoStack(ctx context.Context, projectID string) (*generated.UndoStackStatus, error) {
+	return r.undoStackStatus(ctx, projectID)
+}
+
+// ProjectSnapshots is the resolver for the projectSnapshots field.
+func (r *queryResolver) ProjectSnapshots(ctx context.Context, projectID string) ([]*models.ProjectSnapshot, error) {
+	snapshots, err := r.SnapshotRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.ProjectSnapshot, len(snapshots))
+	for i := range snapshots {
+		result[i] = &snapshots[i]
+	}
+	return result, nil
+}
+
+// ValidateProject is the resolver for the validateProject field.
+func (r *queryResolver) ValidateProject(ctx context.Context, projectID string) (*generated.ProjectValidation, error) {
+	issues, err := r.IntegrityService.Validate(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.ProjectValidation{
+		ProjectID: projectID,
+		Valid:     len(issues) == 0,
+		Issues:    convertProjectIssues(issues),
+	}, nil
+}
+
+// Backups is the resolver for the backups field.
+func (r *queryResolver) Backups(ctx context.Context) ([]*generated.Backup, error) {
+	service, err := r.backups()
+	if err != nil {
+		return nil, err
+	}
+	backups, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.Backup, len(backups))
+	for i, info := range backups {
+		result[i] = convertBackup(info)
+	}
+	return result, nil
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, filter *generated.AuditLogFilterInput, page *int, perPage *int) (*generated.AuditLogPage, error) {
+	auditFilter, err := auditLogFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pageNum := 1
+	pageSize := 50
+	if page != nil && *page > 0 {
+		pageNum = *page
+	}
+	if perPage != nil && *perPage > 0 {
+		pageSize = *perPage
+	}
+
+	entries, total, err := r.AuditRepo.Find(ctx, auditFilter, (pageNum-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*models.AuditLog, len(entries))
+	for i := range entries {
+		items[i] = &entries[i]
+	}
+
+	return &generated.AuditLogPage{
+		Entries: items,
+		Pagination: generated.PaginationInfo{
+			Total:      int(total),
+			Page:       pageNum,
+			PerPage:    pageSize,
+			TotalPages: (int(total) + pageSize - 1) / pageSize,
+			HasMore:    pageNum*pageSize < int(total),
+		},
+	}, nil
+}
+
+// AuthEnabled is the resolver for the authEnabled field.
+func (r *queryResolver) AuthEnabled(ctx context.Context) (bool, error) {
+	return r.AuthService.Enabled(), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*models.User, error) {
+	return auth.UserFromContext(ctx), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context) ([]*models.User, error) {
+	if err := r.AuthService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	users, err := r.UserRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.User, len(users))
+	for i := range users {
+		result[i] = &users[i]
+	}
+	return result, nil
+}
+
+// ProjectPresence is the resolver for the projectPresence field.
+func (r *queryResolver) ProjectPresence(ctx context.Context, projectID string) ([]*generated.Presence, error) {
+	return r.projectPresence(projectID).Sessions, nil
+}
+
+// FixtureUsage is the resolver for the fixtureUsage field.
+func (r *queryResolver) FixtureUsage(ctx context.Context, fixtureID string) (*generated.FixtureUsage, error) {
+	// Get fixture
+	fixture, err := r.FixtureRepo.FindByID(ctx, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	if fixture == nil {
+		return nil, fmt.Errorf("fixture not found: %s", fixtureID)
+	}
+
+	// Find scenes that use this fixture
+	var fixtureValues []models.FixtureValue
+	r.db.Where("fixture_id = ?", fixtureID).Find(&fixtureValues)
+
+	sceneIDs := make([]string, 0, len(fixtureValues))
+	for _, fv := range fixtureValues {
+		sceneIDs = append(sceneIDs, fv.SceneID)
+	}
+
+	// Get unique scenes and convert to SceneSummary
+	var scenes []models.Scene
+	if len(sceneIDs) > 0 {
+		r.db.Where("id IN ?", sceneIDs).Find(&scenes)
+	}
+
+	sceneSummaries := make([]*generated.SceneSummary, len(scenes))
+	for i, scene := range scenes {
+		fixtureCount, _ := r.SceneRepo.CountFixtures(ctx, scene.ID)
+		sceneSummaries[i] = &generated.SceneSummary{
+			ID:             scene.ID,
+			Name:           scene.Name,
+			SecondaryLabel: scene.SecondaryLabel,
+			Description:    scene.Description,
+			FixtureCount:   int(fixtureCount),
+			CreatedAt:      scene.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			UpdatedAt:      formatUpdatedAt(scene.UpdatedAt),
+		}
+	}
+
+	// Find cues that use this fixture (through scenes)
+	var cueUsageSummaries []*generated.CueUsageSummary
+	if len(sceneIDs) > 0 {
+		var cues []models.Cue
+		r.db.Where("scene_id IN ?", sceneIDs).Find(&cues)
+		for _, cue := range cues {
+			cueList, _ := r.CueListRepo.FindByID(ctx, cue.CueListID)
+			cueListName := ""
+			if cueList != nil {
+				cueListName = cueList.Name
+			}
+			cueUsageSummaries = append(cueUsageSummaries, &generated.CueUsageSummary{
+				CueID:       cue.ID,
+				CueNumber:   cue.CueNumber,
+				CueName:     cue.Name,
+				CueListID:   cue.CueListID,
+				CueListName: cueListName,
+			})
+		}
+	}
+
+	return &generated.FixtureUsage{
+		FixtureID:   fixture.ID,
+		FixtureName: fixture.Name,
+		Scenes:      sceneSummaries,
+		Cues:        cueUsageSummaries,
+	}, nil
+}
+
+// SceneUsage is the resolver for the sceneUsage field.
+func (r *queryResolver) SceneUsage(ctx context.Context, sceneID string) (*generated.SceneUsage, error) {
+	// Get scene
+	scene, err := r.SceneRepo.FindByID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene not found: %s", sceneID)
+	}
+
+	// Find cues that use this scene
+	var cues []models.Cue
+	r.db.Where("scene_id = ?", sceneID).Find(&cues)
+
+	// Convert to CueUsageSummary
+	cueUsageSummaries := make([]*generated.CueUsageSummary, 0, len(cues))
+	for _, cue := range cues {
+		cueList, _ := r.CueListRepo.FindByID(ctx, cue.CueListID)
+		cueListName := ""
+		if cueList != nil {
+			cueListName = cueList.Name
+		}
+		cueUsageSummaries = append(cueUsageSummaries, &generated.CueUsageSummary{
+			CueID:       cue.ID,
+			CueNumber:   cue.CueNumber,
+			CueName:     cue.Name,
+			CueListID:   cue.CueListID,
+			CueListName: cueListName,
+		})
+	}
+
+	return &generated.SceneUsage{
+		SceneID:   scene.ID,
+		SceneName: scene.Name,
+		Cues:      cueUsageSummaries,
+	}, nil
+}
+
+// CompareScenes is the resolver for the compareScenes field.
+func (r *queryResolver) CompareScenes(ctx context.Context, sceneID1 string, sceneID2 string) (*generated.SceneComparison, error) {
+	// Get scenes
+	scene1, err := r.SceneRepo.FindByID(ctx, sceneID1)
+	if err != nil {
+		return nil, err
+	}
+	scene2, err := r.SceneRepo.FindByID(ctx, sceneID2)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get fixture values for each scene
+	var values1, values2 []models.FixtureValue
+	r.db.Where("scene_id = ?", sceneID1).Find(&values1)
+	r.db.Where("scene_id = ?", sceneID2).Find(&values2)
+
+	// Build maps for comparison
+	fixtures1 := make(map[string]models.FixtureValue)
+	fixtures2 := make(map[string]models.FixtureValue)
+
+	for _, fv := range values1 {
+		fixtures1[fv.FixtureID] = fv
+	}
+	for _, fv := range values2 {
+		fixtures2[fv.FixtureID] = fv
+	}
+
+	// Find differences
+	var differences []*generated.SceneDifference
+	identicalCount := 0
+	differentCount := 0
+
+	for fixtureID, fv1 := range fixtures1 {
+		fixture, _ := r.FixtureRepo.FindByID(ctx, fixtureID)
+		fixtureName := ""
+		if fixture != nil {
+			fixtureName = fixture.Name
+		}
+
+		if fv2, ok := fixtures2[fixtureID]; ok {
+			// Fixture in both scenes - compare semantically, not by JSON string
+			if !sparseChannelsEqual(fv1.Channels, fv2.Channels) {
+				vals1 := sparseChannelsToDenseArray(fv1.Channels)
+				vals2 := sparseChannelsToDenseArray(fv2.Channels)
+				differences = append(differences, &generated.SceneDifference{
+					FixtureID:      fixtureID,
+					FixtureName:    fixtureName,
+					DifferenceType: generated.DifferenceTypeValuesChanged,
+					Scene1Values:   vals1,
+					Scene2Values:   vals2,
+				})
+				differentCount++
+			} else {
+				identicalCount++
+			}
+		} else {
+			// Fixture only in scene1
+			vals1 := sparseChannelsToDenseArray(fv1.Channels)
+			differences = append(differences, &generated.SceneDifference{
+				FixtureID:      fixtureID,
+				FixtureName:    fixtureName,
+				DifferenceType: generated.DifferenceTypeOnlyInScene1,
+				Scene1Values:   vals1,
+			})
+			differentCount++
+		}
+	}
+
+	for fixtureID, fv2 := range fixtures2 {
+		if _, ok := fixtures1[fixtureID]; !ok {
+			// Fixture only in scene2
+			fixture, _ := r.FixtureRepo.FindByID(ctx, fixtureID)
+			fixtureName := ""
+			if fixture != nil {
+				fixtureName = fixture.Name
+			}
+			vals2 := sparseChannelsToDenseArray(fv2.Channels)
+			differences = append(differences, &generated.SceneDifference{
+				FixtureID:      fixtureID,
+				FixtureName:    fixtureName,
+				DifferenceType: generated.DifferenceTypeOnlyInScene2,
+				Scene2Values:   vals2,
+			})
+			differentCount++
+		}
+	}
+
+	// Convert scenes to SceneSummary
+	fixtureCount1, _ := r.SceneRepo.CountFixtures(ctx, scene1.ID)
+	fixtureCount2, _ := r.SceneRepo.CountFixtures(ctx, scene2.ID)
+
+	scene1Summary := generated.SceneSummary{
+		ID:           scene1.ID,
+		Name:         scene1.Name,
+		Description:  scene1.Description,
+		FixtureCount: int(fixtureCount1),
+		CreatedAt:    scene1.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		UpdatedAt:    formatUpdatedAt(scene1.UpdatedAt),
+	}
+
+	scene2Summary := generated.SceneSummary{
+		ID:           scene2.ID,
+		Name:         scene2.Name,
+		Description:  scene2.Description,
+		FixtureCount: int(fixtureCount2),
+		CreatedAt:    scene2.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		UpdatedAt:    formatUpdatedAt(scene2.UpdatedAt),
+	}
+
+	return &generated.SceneComparison{
+		Scene1:                scene1Summary,
+		Scene2:                scene2Summary,
+		Differences:           differences,
+		IdenticalFixtureCount: identicalCount,
+		DifferentFixtureCount: differentCount,
+	}, nil
+}
+
+// CueLists is the resolver for the cueLists field.
+func (r *queryResolver) CueLists(ctx context.Context, projectID string) ([]*generated.CueListSummary, error) {
+	cueLists, err := r.CueListRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.CueListSummary, len(cueLists))
+	for i, cl := range cueLists {
+		cueCount, _ := r.CueListRepo.CountCues(ctx, cl.ID)
+		// Calculate total duration
+		cues, _ := r.CueListRepo.GetCues(ctx, cl.ID)
+		var totalDuration float64
+		for _, cue := range cues {
+			totalDuration += cue.FadeInTime + cue.FadeOutTime
+			if cue.FollowTime != nil {
+				totalDuration += *cue.FollowTime
+			}
+		}
+		result[i] = &generated.CueListSummary{
+			ID:            cl.ID,
+			Name:          cl.Name,
+			Description:   cl.Description,
+			CueCount:      int(cueCount),
+			TotalDuration: totalDuration,
+			Loop:          cl.Loop,
+			CreatedAt:     cl.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		}
+	}
+	return result, nil
+}
+
+// CueList is the resolver for the cueList field.
+func (r *queryResolver) CueList(ctx context.Context, id string, page *int, perPage *int, includeSceneDetails *bool) (*models.CueList, error) {
+	return r.CueListRepo.FindByID(ctx, id)
+}
+
+// CueListPlaybackStatus is the resolver for the cueListPlaybackStatus field.
+func (r *queryResolver) CueListPlaybackStatus(ctx context.Context, cueListID string) (*generated.CueListPlaybackStatus, error) {
+	// Get the current playback status from the PlaybackService
+	status := r.PlaybackService.GetFormattedStatus(cueListID)
+	return convertCueListPlaybackStatus(status), nil
+}
+
+// GlobalPlaybackStatus is the resolver for the globalPlaybackStatus field.
+func (r *queryResolver) GlobalPlaybackStatus(ctx context.Context) (*generated.GlobalPlaybackStatus, error) {
+	status := r.PlaybackService.GetGlobalPlaybackStatus(ctx)
+
+	fadeProgress := status.FadeProgress
+	return &generated.GlobalPlaybackStatus{
+		IsPlaying:       status.IsPlaying,
+		IsFading:        status.IsFading,
+		CueListID:       status.CueListID,
+		CueListName:     status.CueListName,
+		CurrentCueIndex: status.CurrentCueIndex,
+		CueCount:        status.CueCount,
+		CurrentCueName:  status.CurrentCueName,
+		FadeProgress:    &fadeProgress,
+		LastUpdated:     status.LastUpdated,
+	}, nil
+}
+
+// ActiveCueListPlaybacks is the resolver for the activeCueListPlaybacks field.
+func (r *queryResolver) ActiveCueListPlaybacks(ctx context.Context) ([]*generated.CueListPlaybackStatus, error) {
+	statuses := r.PlaybackService.ActiveCueLists()
+	result := make([]*generated.CueListPlaybackStatus, len(statuses))
+	for i, status := range statuses {
+		result[i] = convertCueListPlaybackStatus(status)
+	}
+	return result, nil
+}
+
+// PlaybackLog is the resolver for the playbackLog field.
+func (r *queryResolver) PlaybackLog(ctx context.Context) (*generated.PlaybackLog, error) {
+	eventLog := r.PlaybackService.EventLog()
+	content, err := eventLog.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize playback log: %w", err)
+	}
+
+	result := &generated.PlaybackLog{
+		EventCount:   len(eventLog.Events),
+		DroppedCount: eventLog.Dropped,
+		Content:      content,
+	}
+	if len(eventLog.Events) > 0 {
+		startedAt := eventLog.Events[0].Timestamp.Format("2006-01-02T15:04:05.000Z")
+		result.StartedAt = &startedAt
+	}
+	return result, nil
+}
+
+// Tempo is the resolver for the tempo field.
+func (r *queryResolver) Tempo(ctx context.Context) (*generated.TempoState, error) {
+	return convertTempoState(r.TempoService.State()), nil
+}
+
+// ShowTimers is the resolver for the showTimers field.
+func (r *queryResolver) ShowTimers(ctx context.Context) ([]*generated.ShowTimer, error) {
+	timers := r.ShowTimerService.List()
+	result := make([]*generated.ShowTimer, len(timers))
+	for i, timer := range timers {
+		result[i] = convertShowTimer(timer)
+	}
+	return result, nil
+}
+
+// ShowTimer is the resolver for the showTimer field.
+func (r *queryResolver) ShowTimer(ctx context.Context, id string) (*generated.ShowTimer, error) {
+	timer := r.ShowTimerService.Get(id)
+	if timer == nil {
+		return nil, nil
+	}
+	return convertShowTimer(timer), nil
+}
+
+// StandbyStatus is the resolver for the standbyStatus field.
+func (r *queryResolver) StandbyStatus(ctx context.Context) (*generated.StandbyStatus, error) {
+	return convertStandbyStatus(r.StandbyService.Status()), nil
+}
+
+// ReplicationStatus is the resolver for the replicationStatus field.
+func (r *queryResolver) ReplicationStatus(ctx context.Context) (*generated.ReplicationStatus, error) {
+	return convertReplicationStatus(r.ReplicationService.Status()), nil
+}
+
+// LogEntries is the resolver for the logEntries field.
+func (r *queryResolver) LogEntries(ctx context.Context, module *string, minLevel *generated.LogLevel, afterID *string, limit *int) ([]*generated.LogEntry, error) {
+	filter, err := logFilter(module, minLevel, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := logging.Entries(filter)
+	result := make([]*generated.LogEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = convertLogEntry(entry)
+	}
+	return result, nil
+}
+
+// LogLevels is the resolver for the logLevels field.
+func (r *queryResolver) LogLevels(ctx context.Context) (*generated.LogLevels, error) {
+	return currentLogLevels(), nil
+}
+
+// FaderWingStatus is the resolver for the faderWingStatus field.
+func (r *queryResolver) FaderWingStatus(ctx context.Context) (*generated.FaderWingStatus, error) {
+	return convertFaderWingStatus(r.InputService.Status()), nil
+}
+
+// Cue is the resolver for the cue field.
+func (r *queryResolver) Cue(ctx context.Context, id string) (*models.Cue, error) {
+	return r.CueRepo.FindByID(ctx, id)
+}
+
+// SearchCues is the resolver for the searchCues field.
+func (r *queryResolver) SearchCues(ctx context.Context, cueListID string, query string, page *int, perPage *int) (*generated.CuePage, error) {
+	cues, err := r.CueListRepo.GetCues(ctx, cueListID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by search query (name, notes)
+	var filtered []models.Cue
+	queryLower := strings.ToLower(query)
+	for _, c := range cues {
+		if strings.Contains(strings.ToLower(c.Name), queryLower) {
+			filtered = append(filtered, c)
+			continue
+		}
+		if c.Notes != nil && strings.Contains(strings.ToLower(*c.Notes), queryLower) {
+			filtered = append(filtered, c)
+			continue
+		}
+	}
+
+	// Apply pagination
+	pageNum := 1
+	pageSize := 50
+	if page != nil {
+		pageNum = *page
+	}
+	if perPage != nil {
+		pageSize = *perPage
+	}
+
+	total := len(filtered)
+	start := (pageNum - 1) * pageSize
+	end := start + pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	items := make([]*models.Cue, end-start)
+	for i := start; i < end; i++ {
+		items[i-start] = &filtered[i]
+	}
+
+	return &generated.CuePage{
+		Cues: items,
+		Pagination: generated.PaginationInfo{
+			Total:   total,
+			Page:    pageNum,
+			PerPage: pageSize,
+			HasMore: end < total,
+		},
+	}, nil
+}
+
+// DmxOutput is the resolver for the dmxOutput field.
+func (r *queryResolver) DmxOutput(ctx context.Context, universe int) ([]int, error) {
+	// DMX service expects 1-indexed universe
+	return r.DMXService.GetUniverse(universe), nil
+}
+
+// AllDmxOutput is the resolver for the allDmxOutput field.
+func (r *queryResolver) AllDmxOutput(ctx context.Context) ([]*generated.UniverseOutput, error) {
+	allUniverses := r.DMXService.GetAllUniverses()
+	result := make([]*generated.UniverseOutput, 0, len(allUniverses))
+	for universe, channels := range allUniverses {
+		result = append(result, &generated.UniverseOutput{
+			Universe: universe,
+			Channels: channels,
+		})
+	}
+	return result, nil
+}
+
+// DmxRecordings is the resolver for the dmxRecordings field.
+func (r *queryResolver) DmxRecordings(ctx context.Context) ([]*generated.DmxRecording, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return nil, err
+	}
+	recordings, err := service.Recordings()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.DmxRecording, len(recordings))
+	for i, info := range recordings {
+		result[i] = convertDMXRecording(info)
+	}
+	return result, nil
+}
+
+// DmxRecordingStatus is the resolver for the dmxRecordingStatus field.
+func (r *queryResolver) DmxRecordingStatus(ctx context.Context) (*generated.DmxRecordingStatus, error) {
+	service, err := r.dmxRecorder()
+	if err != nil {
+		return nil, err
+	}
+	return convertDMXRecordingStatus(service.Status()), nil
+}
+
+// Programmer is the resolver for the programmer field.
+func (r *queryResolver) Programmer(ctx context.Context) ([]*generated.ProgrammerChannel, error) {
+	return r.programmerChannels(), nil
+}
+
+// IntensityLimitReport is the resolver for the intensityLimitReport field.
+func (r *queryResolver) IntensityLimitReport(ctx context.Context, projectID string) ([]*generated.FixtureIntensityLimit, error) {
+	return r.intensityLimitReport(ctx, projectID)
+}
+
+// PreviewSession is the resolver for the previewSession field.
+func (r *queryResolver) PreviewSession(ctx context.Context, sessionID string) (*models.PreviewSession, error) {
+	var session models.PreviewSession
+	result := r.db.First(&session, "id = ?", sessionID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// CurrentActiveScene is the resolver for the currentActiveScene field.
+func (r *queryResolver) CurrentActiveScene(ctx context.Context) (*models.Scene, error) {
+	// TODO: Implement tracking of currently active scene
+	// For now, return nil (no active scene)
+	return nil, nil
+}
+
+// PlaybackStack is the resolver for the playbackStack field.
+func (r *queryResolver) PlaybackStack(ctx context.Context) ([]*generated.PlaybackStackEntry, error) {
+	return r.playbackStack(ctx)
+}
+
+// Settings is the resolver for the settings field.
+func (r *queryResolver) Settings(ctx context.Context) ([]*models.Setting, error) {
+	settings, err := r.SettingsService.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Setting, len(settings))
+	for i := range settings {
+		result[i] = &settings[i]
+	}
+	return result, nil
+}
+
+// Setting is the resolver for the setting field.
+func (r *queryResolver) Setting(ctx context.Context, key string) (*models.Setting, error) {
+	return r.SettingsService.Get(ctx, key)
+}
+
+// SystemInfo is the resolver for the systemInfo field.
+func (r *queryResolver) SystemInfo(ctx context.Context) (*generated.SystemInfo, error) {
+	return &generated.SystemInfo{
+		ArtnetEnabled:          r.DMXService.IsEnabled(),
+		ArtnetBroadcastAddress: r.DMXService.GetBroadcastAddress(),
+		FadeUpdateRateHz:       r.FadeEngine.GetUpdateRateHz(),
+		SchemaVersion:          r.SchemaInfo.Version(),
+	}, nil
+}
+
+// NetworkInterfaceOptions is the resolver for the networkInterfaceOptions field.
+// Returns available network interfaces for Art-Net broadcast selection
+func (r *queryResolver) NetworkInterfaceOptions(ctx context.Context) ([]*generated.NetworkInterfaceOption, error) {
+	interfaces, err := network.GetNetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]*generated.NetworkInterfaceOption, len(interfaces))
+	for i, iface := range interfaces {
+		options[i] = &generated.NetworkInterfaceOption{
+			Name:          iface.Name,
+			Address:       iface.Address,
+			Broadcast:     iface.Broadcast,
+			Description:   iface.Description,
+			InterfaceType: iface.InterfaceType,
+		}
+	}
+
+	return options, nil
+}
+
+// DiscoveryStatus is the resolver for the discoveryStatus field.
+func (r *queryResolver) DiscoveryStatus(ctx context.Context) (*generated.DiscoveryStatus, error) {
+	return r.discoveryStatus(), nil
+}
+
+// DiscoveredServers is the resolver for the discoveredServers field.
+func (r *queryResolver) DiscoveredServers(ctx context.Context, refresh *bool) ([]*generated.DiscoveredServer, error) {
+	return r.discoveredServers(refresh != nil && *refresh)
+}
+
+// OperationRecordingStatus is the resolver for the operationRecordingStatus field.
+func (r *queryResolver) OperationRecordingStatus(ctx context.Context) (*generated.OperationRecordingStatus, error) {
+	return convertOperationRecordingStatus(r.OperationRecorder.Status()), nil
+}
+
+// DeprecatedFieldUsage is the resolver for the deprecatedFieldUsage field.
+func (r *queryResolver) DeprecatedFieldUsage(ctx context.Context) ([]*generated.DeprecatedFieldUsage, error) {
+	usage := r.SchemaInfo.Usage()
+	result := make([]*generated.DeprecatedFieldUsage, len(usage))
+	for i, u := range usage {
+		result[i] = &generated.DeprecatedFieldUsage{
+			Coordinate: u.Coordinate,
+			Reason:     u.Reason,
+			Count:      int(u.Count),
+		}
+		if u.LastUsedAt != nil {
+			lastUsedAt := u.LastUsedAt.Format("2006-01-02T15:04:05.000Z")
+			result[i].LastUsedAt = &lastUsedAt
+		}
+	}
+	return result, nil
+}
+
+// WifiNetworks is the resolver for the wifiNetworks field.
+func (r *queryResolver) WifiNetworks(ctx context.Context, rescan *bool, deduplicate *bool) ([]*generated.WiFiNetwork, error) {
+	doRescan := rescan != nil && *rescan
+	doDeduplicate := deduplicate == nil || *deduplicate // Default to true
+
+	networks, err := r.WiFiService.ScanNetworks(ctx, doRescan, doDeduplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*generated.WiFiNetwork
+	for _, n := range networks {
+		result = append(result, &generated.WiFiNetwork{
+			Ssid:           n.SSID,
+			SignalStrength: n.SignalStrength,
+			Frequency:      n.Frequency,
+			Security:       generated.WiFiSecurityType(n.Security),
+			InUse:          n.InUse,
+			Saved:          n.Saved,
+		})
+	}
+
+	return result, nil
+}
+
+// WifiStatus is the resolver for the wifiStatus field.
+func (r *queryResolver) WifiStatus(ctx context.Context) (*generated.WiFiStatus, error) {
+	status, err := r.WiFiService.GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertWiFiStatus(status), nil
+}
+
+// SavedWifiNetworks is the resolver for the savedWifiNetworks field.
+// Returns empty list - WiFi not available on this platform
+func (r *queryResolver) SavedWifiNetworks(ctx context.Context) ([]*generated.WiFiNetwork, error) {
+	return []*generated.WiFiNetwork{}, nil
+}
+
+// WifiMode is the resolver for the wifiMode field.
+func (r *queryResolver) WifiMode(ctx context.Context) (generated.WiFiMode, error) {
+	mode := r.WiFiService.GetMode()
+	return generated.WiFiMode(mode), nil
+}
+
+// ApConfig is the resolver for the apConfig field.
+func (r *queryResolver) ApConfig(ctx context.Context) (*generated.APConfig, error) {
+	config := r.WiFiService.GetAPConfig()
+	if config == nil {
+		return nil, nil
+	}
+
+	return &generated.APConfig{
+		Ssid:             config.SSID,
+		IPAddress:        config.IPAddress,
+		Channel:          config.Channel,
+		ClientCount:      config.ClientCount,
+		TimeoutMinutes:   config.TimeoutMinutes,
+		MinutesRemaining: config.MinutesRemaining,
+	}, nil
+}
+
+// ApClients is the resolver for the apClients field.
+func (r *queryResolver) ApClients(ctx context.Context) ([]*generated.APClient, error) {
+	clients := r.WiFiService.GetAPClients()
+	if clients == nil {
+		return []*generated.APClient{}, nil
+	}
+
+	var result []*generated.APClient
+	for _, client := range clients {
+		result = append(result, &generated.APClient{
+			MacAddress:  client.MACAddress,
+			IPAddress:   client.IPAddress,
+			Hostname:    client.Hostname,
+			ConnectedAt: client.ConnectedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	return result, nil
+}
+
+// GetQLCFixtureMappingSuggestions is the resolver for the getQLCFixtureMappingSuggestions field.
+// Returns empty result - QLC+ integration not available
+func (r *queryResolver) GetQLCFixtureMappingSuggestions(ctx context.Context, projectID string) (*generated.QLCFixtureMappingResult, error) {
+	return &generated.QLCFixtureMappingResult{
+		ProjectID:          projectID,
+		LacyLightsFixtures: []*generated.LacyLightsFixture{},
+		Suggestions:        []*generated.FixtureMappingSuggestion{},
+		DefaultMappings:    []*generated.FixtureMapping{},
+	}, nil
+}
+
+// SystemVersions is the resolver for the systemVersions field.
+// Returns version information for all LacyLights components
+func (r *queryResolver) SystemVersions(ctx context.Context) (*generated.SystemVersionInfo, error) {
+	info, err := r.VersionService.GetSystemVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to GraphQL types
+	var repos []*generated.RepositoryVersion
+	for _, repo := range info.Repositories {
+		repos = append(repos, &generated.RepositoryVersion{
+			Repository:      repo.Repository,
+			Installed:       repo.Installed,
+			Latest:          repo.Latest,
+			UpdateAvailable: repo.UpdateAvailable,
+		})
+	}
+
+	return &generated.SystemVersionInfo{
+		Repositories:               repos,
+		LastChecked:                info.LastChecked,
+		VersionManagementSupported: info.VersionManagementSupported,
+	}, nil
+}
+
+// AvailableVersions is the resolver for the availableVersions field.
+// Returns available versions for a specific repository
+func (r *queryResolver) AvailableVersions(ctx context.Context, repository string) ([]string, error) {
+	return r.VersionService.GetAvailableVersions(repository)
+}
+
+// BuildInfo is the resolver for the buildInfo field.
+func (r *queryResolver) BuildInfo(ctx context.Context) (*generated.BuildInfo, error) {
+	info := version.GetBuildInfo()
+	return &generated.BuildInfo{
+		Version:   info.Version,
+		GitCommit: info.GitCommit,
+		BuildTime: info.BuildTime,
+	}, nil
+}
+
+// OflImportStatus is the resolver for the oflImportStatus field.
+func (r *queryResolver) OflImportStatus(ctx context.Context) (*generated.OFLImportStatus, error) {
+	status := r.OFLManager.GetStatus()
+
+	var startedAt, completedAt *string
+	if status.StartedAt != nil {
+		s := status.StartedAt.Format("2006-01-02T15:04:05Z07:00")
+		startedAt = &s
+	}
+	if status.CompletedAt != nil {
+		s := status.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		completedAt = &s
+	}
+
+	var errorMessage *string
+	if status.ErrorMessage != "" {
+		errorMessage = &status.ErrorMessage
+	}
+
+	var currentFixture, currentManufacturer *string
+	if status.CurrentFixture != "" {
+		currentFixture = &status.CurrentFixture
+	}
+	if status.CurrentManufacturer != "" {
+		currentManufacturer = &status.CurrentManufacturer
+	}
+
+	var oflVersion *string
+	if status.OFLVersion != "" {
+		oflVersion = &status.OFLVersion
+	}
+
+	return &generated.OFLImportStatus{
+		IsImporting:               status.IsImporting,
+		Phase:                     generated.OFLImportPhase(status.Phase),
+		TotalFixtures:             status.TotalFixtures,
+		ImportedCount:             status.ImportedCount,
+		FailedCount:               status.FailedCount,
+		SkippedCount:              status.SkippedCount,
+		PercentComplete:           status.PercentComplete,
+		CurrentFixture:            currentFixture,
+		CurrentManufacturer:       currentManufacturer,
+		EstimatedSecondsRemaining: status.EstimatedSecondsRemaining,
+		ErrorMessage:              errorMessage,
+		StartedAt:                 startedAt,
+		CompletedAt:               completedAt,
+		OflVersion:                oflVersion,
+		UsingBundledData:          status.UsingBundledData,
+	}, nil
+}
+
+// CheckOFLUpdates is the resolver for the checkOFLUpdates field.
+func (r *queryResolver) CheckOFLUpdates(ctx context.Context) (*generated.OFLUpdateCheckResult, error) {
+	result, err := r.OFLManager.CheckForUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert fixture updates to GraphQL type
+	var updates []*generated.OFLFixtureUpdate
+	for _, u := range result.FixtureUpdates {
+		update := &generated.OFLFixtureUpdate{
+			FixtureKey:    u.FixtureKey,
+			Manufacturer:  u.Manufacturer,
+			Model:         u.Model,
+			ChangeType:    generated.OFLFixtureChangeType(u.ChangeType),
+			IsInUse:       u.IsInUse,
+			InstanceCount: u.InstanceCount,
+			CurrentHash:   u.CurrentHash,
+			NewHash:       u.NewHash,
+		}
+		updates = append(updates, update)
+	}
+
+	return &generated.OFLUpdateCheckResult{
+		CurrentFixtureCount: result.CurrentFixtureCount,
+		OflFixtureCount:     result.OFLFixtureCount,
+		NewFixtureCount:     result.NewFixtureCount,
+		ChangedFixtureCount: result.ChangedFixtureCount,
+		ChangedInUseCount:   result.ChangedInUseCount,
+		FixtureUpdates:      updates,
+		OflVersion:          result.OFLVersion,
+		CheckedAt:           result.CheckedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// CompareFixtureLibrary is the resolver for the compareFixtureLibrary field.
+func (r *queryResolver) CompareFixtureLibrary(ctx context.Context, url string) (*generated.FixtureLibraryComparison, error) {
+	library, err := r.LibrarySyncService.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons, err := r.LibrarySyncService.Compare(ctx, library)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &generated.FixtureLibraryComparison{
+		URL:         url,
+		GeneratedAt: library.GeneratedAt.Format("2006-01-02T15:04:05.000Z"),
+		Definitions: make([]*generated.FixtureLibraryDefinitionComparison, len(comparisons)),
+		Templates:   make([]*generated.FixtureLibraryTemplateInfo, len(library.Templates)),
+	}
+	for i, c := range comparisons {
+		entry := &generated.FixtureLibraryDefinitionComparison{
+			Manufacturer:    c.Manufacturer,
+			Model:           c.Model,
+			Type:            generated.FixtureType(c.Type),
+			Status:          generated.FixtureLibraryDefinitionStatus(c.Status),
+			RemoteVersion:   c.RemoteVersion,
+			RemoteUpdatedAt: formatUpdatedAt(c.RemoteUpdatedAt),
+			LocalVersion:    c.LocalVersion,
+		}
+		if c.LocalUpdatedAt != nil {
+			localUpdatedAt := formatUpdatedAt(*c.LocalUpdatedAt)
+			entry.LocalUpdatedAt = &localUpdatedAt
+		}
+		result.Definitions[i] = entry
+	}
+	for i, t := range library.Templates {
+		result.Templates[i] = &generated.FixtureLibraryTemplateInfo{
+			Name:        t.Name,
+			Description: t.Description,
+		}
+	}
+	return result, nil
+}
+
+// FixturesByIds is the resolver for the fixturesByIds field.
+func (r *queryResolver) FixturesByIds(ctx context.Context, ids []string) ([]*models.FixtureInstance, error) {
+	var fixtures []*models.FixtureInstance
+
+	for _, id := range ids {
+		fixture, err := r.FixtureRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if fixture != nil {
+			fixtures = append(fixtures, fixture)
+		}
+	}
+
+	return fixtures, nil
+}
+
+// ScenesByIds is the resolver for the scenesByIds field.
+func (r *queryResolver) ScenesByIds(ctx context.Context, ids []string) ([]*models.Scene, error) {
+	var scenes []*models.Scene
+
+	for _, id := range ids {
+		scene, err := r.SceneRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if scene != nil {
+			scenes = append(scenes, scene)
+		}
+	}
+
+	return scenes, nil
+}
+
+// CuesByIds is the resolver for the cuesByIds field.
+func (r *queryResolver) CuesByIds(ctx context.Context, ids []string) ([]*models.Cue, error) {
+	var cues []*models.Cue
+
+	for _, id := range ids {
+		cue, err := r.CueRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cue != nil {
+			cues = append(cues, cue)
+		}
+	}
+
+	return cues, nil
+}
+
+// CueListsByIds is the resolver for the cueListsByIds field.
+func (r *queryResolver) CueListsByIds(ctx context.Context, ids []string) ([]*models.CueList, error) {
+	var cueLists []*models.CueList
+
+	for _, id := range ids {
+		cueList, err := r.CueListRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cueList != nil {
+			cueLists = append(cueLists, cueList)
+		}
+	}
+
+	return cueLists, nil
+}
+
+// SceneBoardsByIds is the resolver for the sceneBoardsByIds field.
+func (r *queryResolver) SceneBoardsByIds(ctx context.Context, ids []string) ([]*models.SceneBoard, error) {
+	var sceneBoards []*models.SceneBoard
+
+	for _, id := range ids {
+		var board models.SceneBoard
+		result := r.db.WithContext(ctx).First(&board, "id = ?", id)
+		if result.Error == nil {
+			sceneBoards = append(sceneBoards, &board)
+		}
+	}
+
+	return sceneBoards, nil
+}
+
+// FixtureDefinitionsByIds is the resolver for the fixtureDefinitionsByIds field.
+func (r *queryResolver) FixtureDefinitionsByIds(ctx context.Context, ids []string) ([]*models.FixtureDefinition, error) {
+	var definitions []*models.FixtureDefinition
+
+	for _, id := range ids {
+		definition, err := r.FixtureRepo.FindDefinitionByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if definition != nil {
+			definitions = append(definitions, definition)
+		}
+	}
+
+	return definitions, nil
+}
+
+// ProjectsByIds is the resolver for the projectsByIds field.
+func (r *queryResolver) ProjectsByIds(ctx context.Context, ids []string) ([]*models.Project, error) {
+	var projects []*models.Project
+
+	for _, id := range ids {
+		project, err := r.ProjectRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			projects = append(projects, project)
+		}
+	}
+
+	return projects, nil
+}
+
+// Project is the resolver for the project field.
+func (r *sceneResolver) Project(ctx context.Context, obj *models.Scene) (*models.Project, error) {
+	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+}
+
+// FixtureValues is the resolver for the fixtureValues field.
+func (r *sceneResolver) FixtureValues(ctx context.Context, obj *models.Scene) ([]*models.FixtureValue, error) {
+	return r.loadersFor(ctx).SceneFixtureValues.Load(ctx, obj.ID)
+}
+
+// GroupValues is the resolver for the groupValues field.
+func (r *sceneResolver) GroupValues(ctx context.Context, obj *models.Scene) ([]*models.GroupValue, error) {
+	values, err := r.SceneRepo.GetGroupValues(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.GroupValue, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	return pointers, nil
+}
+
+// ColorSummary is the resolver for the colorSummary field.
+func (r *sceneResolver) ColorSummary(ctx context.Context, obj *models.Scene) (*generated.SceneColorSummary, error) {
+	return r.sceneColorSummary(ctx, obj.ID, obj.ProjectID)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *sceneResolver) CreatedAt(ctx context.Context, obj *models.Scene) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *sceneResolver) UpdatedAt(ctx context.Context, obj *models.Scene) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Project is the resolver for the project field.
+func (r *sceneBoardResolver) Project(ctx context.Context, obj *models.SceneBoard) (*models.Project, error) {
+	return r.ProjectRepo.FindByID(ctx, obj.ProjectID)
+}
+
+// HoldCurve is the resolver for the holdCurve field.
+func (r *sceneBoardResolver) HoldCurve(ctx context.Context, obj *models.SceneBoard) (generated.EasingType, error) {
+	return generated.EasingType(obj.HoldCurve), nil
+}
+
+// HoldReleaseMode is the resolver for the holdReleaseMode field.
+func (r *sceneBoardResolver) HoldReleaseMode(ctx context.Context, obj *models.SceneBoard) (generated.HoldReleaseMode, error) {
+	return generated.HoldReleaseMode(obj.HoldReleaseMode), nil
+}
+
+// Buttons is the resolver for the buttons field.
+func (r *sceneBoardResolver) Buttons(ctx context.Context, obj *models.SceneBoard) ([]*models.SceneBoardButton, error) {
+	var buttons []models.SceneBoardButton
+	result := r.db.WithContext(ctx).Where("scene_board_id = ?", obj.ID).Find(&buttons)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pointers := make([]*models.SceneBoardButton, len(buttons))
+	for i := range buttons {
+		pointers[i] = &buttons[i]
+	}
+	return pointers, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *sceneBoardResolver) CreatedAt(ctx context.Context, obj *models.SceneBoard) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *sceneBoardResolver) UpdatedAt(ctx context.Context, obj *models.SceneBoard) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// SceneBoard is the resolver for the sceneBoard field.
+func (r *sceneBoardButtonResolver) SceneBoard(ctx context.Context, obj *models.SceneBoardButton) (*models.SceneBoard, error) {
+	var board models.SceneBoard
+	result := r.db.First(&board, "id = ?", obj.SceneBoardID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &board, nil
+}
+
+// Scene is the resolver for the scene field.
+func (r *sceneBoardButtonResolver) Scene(ctx context.Context, obj *models.SceneBoardButton) (*models.Scene, error) {
+	return r.SceneRepo.FindByID(ctx, obj.SceneID)
+}
+
+// AudioAction is the resolver for the audioAction field.
+func (r *sceneBoardButtonResolver) AudioAction(ctx context.Context, obj *models.SceneBoardButton) (generated.AudioTriggerAction, error) {
+	if obj.AudioAction == "" {
+		return generated.AudioTriggerActionBump, nil
+	}
+	return generated.AudioTriggerAction(obj.AudioAction), nil
+}
+
+// Macro is the resolver for the macro field.
+func (r *sceneBoardButtonResolver) Macro(ctx context.Context, obj *models.SceneBoardButton) ([]*generated.MacroAction, error) {
+	return convertMacroActions(obj.Macro)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *sceneBoardButtonResolver) CreatedAt(ctx context.Context, obj *models.SceneBoardButton) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *sceneBoardButtonResolver) UpdatedAt(ctx context.Context, obj *models.SceneBoardButton) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// ColorSummary is the resolver for the colorSummary field.
+func (r *sceneSummaryResolver) ColorSummary(ctx context.Context, obj *generated.SceneSummary) (*generated.SceneColorSummary, error) {
+	scene, err := r.loadersFor(ctx).Scenes.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene not found: %s", obj.ID)
+	}
+	return r.sceneColorSummary(ctx, scene.ID, scene.ProjectID)
+}
+
+// Trigger is the resolver for the trigger field.
+func (r *scheduleResolver) Trigger(ctx context.Context, obj *models.Schedule) (generated.ScheduleTrigger, error) {
+	return generated.ScheduleTrigger(obj.Trigger), nil
+}
+
+// Days is the resolver for the days field.
+func (r *scheduleResolver) Days(ctx context.Context, obj *models.Schedule) ([]generated.DayOfWeek, error) {
+	days, err := scheduleDays(obj)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]generated.DayOfWeek, len(days))
+	for i, day := range days {
+		result[i] = generated.AllDayOfWeek[day]
+	}
+	return result, nil
+}
+
+// Action is the resolver for the action field.
+func (r *scheduleResolver) Action(ctx context.Context, obj *models.Schedule) (generated.ScheduleAction, error) {
+	return generated.ScheduleAction(obj.Action), nil
+}
+
+// Scene is the resolver for the scene field.
+func (r *scheduleResolver) Scene(ctx context.Context, obj *models.Schedule) (*models.Scene, error) {
+	if obj.SceneID == nil {
+		return nil, nil
+	}
+	return r.SceneRepo.FindByID(ctx, *obj.SceneID)
+}
+
+// CueList is the resolver for the cueList field.
+func (r *scheduleResolver) CueList(ctx context.Context, obj *models.Schedule) (*models.CueList, error) {
+	if obj.CueListID == nil {
+		return nil, nil
+	}
+	return r.CueListRepo.FindByID(ctx, *obj.CueListID)
+}
+
+// NextFireAt is the resolver for the nextFireAt field.
+func (r *scheduleResolver) NextFireAt(ctx context.Context, obj *models.Schedule) (*string, error) {
+	next, ok := r.SchedulerService.NextFire(obj.ID)
+	if !ok {
+		return nil, nil
+	}
+	formatted := next.UTC().Format("2006-01-02T15:04:05.000Z")
+	return &formatted, nil
+}
+
+// LastFiredAt is the resolver for the lastFiredAt field.
+func (r *scheduleResolver) LastFiredAt(ctx context.Context, obj *models.Schedule) (*string, error) {
+	if obj.LastFiredAt == nil {
+		return nil, nil
+	}
+	formatted := obj.LastFiredAt.UTC().Format("2006-01-02T15:04:05.000Z")
+	return &formatted, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *scheduleResolver) CreatedAt(ctx context.Context, obj *models.Schedule) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *scheduleResolver) UpdatedAt(ctx context.Context, obj *models.Schedule) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// FixtureIds is the resolver for the fixtureIds field.
+func (r *selectionSetResolver) FixtureIds(ctx context.Context, obj *models.SelectionSet) ([]string, error) {
+	return repositories.SelectionSetFixtureIDs(obj)
+}
+
+// Fixtures is the resolver for the fixtures field.
+func (r *selectionSetResolver) Fixtures(ctx context.Context, obj *models.SelectionSet) ([]*models.FixtureInstance, error) {
+	ids, err := repositories.SelectionSetFixtureIDs(obj)
+	if err != nil {
+		return nil, err
+	}
+	return r.orderedFixtures(ctx, ids)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *selectionSetResolver) CreatedAt(ctx context.Context, obj *models.SelectionSet) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *selectionSetResolver) UpdatedAt(ctx context.Context, obj *models.SelectionSet) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *settingResolver) CreatedAt(ctx context.Context, obj *models.Setting) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *settingResolver) UpdatedAt(ctx context.Context, obj *models.Setting) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Type is the resolver for the type field.
+func (r *settingResolver) Type(ctx context.Context, obj *models.Setting) (generated.SettingType, error) {
+	return generated.SettingType(r.settingType(obj.Key)), nil
+}
+
+// Description is the resolver for the description field.
+func (r *settingResolver) Description(ctx context.Context, obj *models.Setting) (*string, error) {
+	if def, ok := r.SettingsService.Lookup(obj.Key); ok && def.Description != "" {
+		return &def.Description, nil
+	}
+	return nil, nil
+}
+
+// DefaultValue is the resolver for the defaultValue field.
+func (r *settingResolver) DefaultValue(ctx context.Context, obj *models.Setting) (*string, error) {
+	if def, ok := r.SettingsService.Lookup(obj.Key); ok && def.Default != "" {
+		return &def.Default, nil
+	}
+	return nil, nil
+}
+
+// IsDefault is the resolver for the isDefault field.
+func (r *settingResolver) IsDefault(ctx context.Context, obj *models.Setting) (bool, error) {
+	return obj.ID == "", nil
+}
+
+// Targets is the resolver for the targets field.
+func (r *softPatchResolver) Targets(ctx context.Context, obj *models.SoftPatch) ([]*generated.DmxAddress, error) {
+	targets, err := softPatchTargets(obj)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*generated.DmxAddress, len(targets))
+	for i, target := range targets {
+		result[i] = &generated.DmxAddress{Universe: target.Universe, Channel: target.Channel}
+	}
+	return result, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *softPatchResolver) CreatedAt(ctx context.Context, obj *models.SoftPatch) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *softPatchResolver) UpdatedAt(ctx context.Context, obj *models.SoftPatch) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// Scene is the resolver for the scene field.
+func (r *submasterResolver) Scene(ctx context.Context, obj *models.Submaster) (*models.Scene, error) {
+	if obj.SceneID == nil {
+		return nil, nil
+	}
+	return r.SceneRepo.FindByID(ctx, *obj.SceneID)
+}
+
+// FixtureIds is the resolver for the fixtureIds field.
+func (r *submasterResolver) FixtureIds(ctx context.Context, obj *models.Submaster) ([]string, error) {
+	return submasterFixtureIDs(obj)
+}
+
+// Level is the resolver for the level field.
+func (r *submasterResolver) Level(ctx context.Context, obj *models.Submaster) (float64, error) {
+	if level, ok := r.DMXService.SubmasterLevels()[obj.ID]; ok {
+		return level, nil
+	}
+	return defaultSubmasterLevel(obj), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *submasterResolver) CreatedAt(ctx context.Context, obj *models.Submaster) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *submasterResolver) UpdatedAt(ctx context.Context, obj *models.Submaster) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
+// DmxOutputChanged is the resolver for the dmxOutputChanged field.
+func (r *subscriptionResolver) DmxOutputChanged(ctx context.Context, universe *int) (<-chan *generated.UniverseOutput, error) {
+	// Create a filter string if universe is specified
+	filter := ""
+	if universe != nil {
+		filter = fmt.Sprintf("%d", *universe)
+	}
+
+	// Subscribe to DMX output changes
+	sub := r.PubSub.Subscribe(pubsub.TopicDMXOutput, filter, 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.UniverseOutput, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if output, valid := msg.(*generated.UniverseOutput); valid {
+					select {
+					case outputChan <- output:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// ProjectUpdated is the resolver for the projectUpdated field.
+func (r *subscriptionResolver) ProjectUpdated(ctx context.Context, projectID string) (<-chan *models.Project, error) {
+	// Subscribe to project updates filtered by projectID
+	sub := r.PubSub.Subscribe(pubsub.TopicProjectUpdated, projectID, 10)
+
+	// Create the output channel
+	outputChan := make(chan *models.Project, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if project, valid := msg.(*models.Project); valid {
+					select {
+					case outputChan <- project:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// PreviewSessionUpdated is the resolver for the previewSessionUpdated field.
+func (r *subscriptionResolver) PreviewSessionUpdated(ctx context.Context, projectID string) (<-chan *models.PreviewSession, error) {
+	// Subscribe to preview session updates filtered by projectID
+	sub := r.PubSub.Subscribe(pubsub.TopicPreviewSession, projectID, 10)
+
+	// Create the output channel
+	outputChan := make(chan *models.PreviewSession, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if session, valid := msg.(*models.PreviewSession); valid {
+					select {
+					case outputChan <- session:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// CueListPlaybackUpdated is the resolver for the cueListPlaybackUpdated field.
+func (r *subscriptionResolver) CueListPlaybackUpdated(ctx context.Context, cueListID string) (<-chan *generated.CueListPlaybackStatus, error) {
+	// Subscribe to cue list playback updates filtered by cueListID
+	sub := r.PubSub.Subscribe(pubsub.TopicCueListPlayback, cueListID, 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.CueListPlaybackStatus, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if status, valid := msg.(*generated.CueListPlaybackStatus); valid {
+					select {
+					case outputChan <- status:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// GlobalPlaybackStatusUpdated is the resolver for the globalPlaybackStatusUpdated field.
+func (r *subscriptionResolver) GlobalPlaybackStatusUpdated(ctx context.Context) (<-chan *generated.GlobalPlaybackStatus, error) {
+	// Subscribe to global playback status updates (no filter, receives all updates)
+	sub := r.PubSub.Subscribe(pubsub.TopicGlobalPlaybackStatus, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.GlobalPlaybackStatus, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if status, valid := msg.(*generated.GlobalPlaybackStatus); valid {
+					select {
+					case outputChan <- status:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// SystemInfoUpdated is the resolver for the systemInfoUpdated field.
+func (r *subscriptionResolver) SystemInfoUpdated(ctx context.Context) (<-chan *generated.SystemInfo, error) {
+	// Subscribe to system info updates (no filter, receives all updates)
+	sub := r.PubSub.Subscribe(pubsub.TopicSystemInfo, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.SystemInfo, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if info, valid := msg.(*generated.SystemInfo); valid {
+					select {
+					case outputChan <- info:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// WifiStatusUpdated is the resolver for the wifiStatusUpdated field.
+func (r *subscriptionResolver) WifiStatusUpdated(ctx context.Context) (<-chan *generated.WiFiStatus, error) {
+	// Subscribe to WiFi status updates (no filter, receives all updates)
+	sub := r.PubSub.Subscribe(pubsub.TopicWiFiStatus, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.WiFiStatus, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if status, valid := msg.(*generated.WiFiStatus); valid {
+					select {
+					case outputChan <- status:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// WifiModeChanged is the resolver for the wifiModeChanged field.
+func (r *subscriptionResolver) WifiModeChanged(ctx context.Context) (<-chan generated.WiFiMode, error) {
+	// Subscribe to WiFi mode changes (no filter)
+	sub := r.PubSub.Subscribe(pubsub.TopicWiFiModeChanged, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan generated.WiFiMode, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if mode, valid := msg.(generated.WiFiMode); valid {
+					select {
+					case outputChan <- mode:
+					default:
+						// Channel full, skip message
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// OflImportProgress is the resolver for the oflImportProgress field.
+func (r *subscriptionResolver) OflImportProgress(ctx context.Context) (<-chan *generated.OFLImportStatus, error) {
+	// Subscribe to OFL import progress updates (no filter, receives all updates)
+	sub := r.PubSub.Subscribe(pubsub.TopicOFLImportProgress, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.OFLImportStatus, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if status, valid := msg.(*ofl.ProgressStatus); valid {
+					// Convert to GraphQL type
+					var startedAt, completedAt *string
+					if status.StartedAt != nil {
+						s := status.StartedAt.Format("2006-01-02T15:04:05Z07:00")
+						startedAt = &s
+					}
+					if status.CompletedAt != nil {
+						s := status.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+						completedAt = &s
+					}
+
+					var errorMessage *string
+					if status.ErrorMessage != "" {
+						errorMessage = &status.ErrorMessage
+					}
+
+					var currentFixture, currentManufacturer *string
+					if status.CurrentFixture != "" {
+						currentFixture = &status.CurrentFixture
+					}
+					if status.CurrentManufacturer != "" {
+						currentManufacturer = &status.CurrentManufacturer
+					}
+
+					var oflVersion *string
+					if status.OFLVersion != "" {
+						oflVersion = &status.OFLVersion
+					}
+
+					gqlStatus := &generated.OFLImportStatus{
+						IsImporting:               status.IsImporting,
+						Phase:                     generated.OFLImportPhase(status.Phase),
+						TotalFixtures:             status.TotalFixtures,
+						ImportedCount:             status.ImportedCount,
+						FailedCount:               status.FailedCount,
+						SkippedCount:              status.SkippedCount,
+						PercentComplete:           status.PercentComplete,
+						CurrentFixture:            currentFixture,
+						CurrentManufacturer:       currentManufacturer,
+						EstimatedSecondsRemaining: status.EstimatedSecondsRemaining,
+						ErrorMessage:              errorMessage,
+						StartedAt:                 startedAt,
+						CompletedAt:               completedAt,
+						OflVersion:                oflVersion,
+						UsingBundledData:          status.UsingBundledData,
+					}
+
+					select {
+					case outputChan <- gqlStatus:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// ShowTimerUpdated is the resolver for the showTimerUpdated field.
+func (r *subscriptionResolver) ShowTimerUpdated(ctx context.Context, timerID *string) (<-chan *generated.ShowTimer, error) {
+	// Create a filter string if a timer is specified
+	filter := ""
+	if timerID != nil {
+		filter = *timerID
+	}
+
+	sub := r.PubSub.Subscribe(pubsub.TopicShowTimer, filter, 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.ShowTimer, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if timer, valid := msg.(*generated.ShowTimer); valid {
+					select {
+					case outputChan <- timer:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// TempoUpdated is the resolver for the tempoUpdated field.
+func (r *subscriptionResolver) TempoUpdated(ctx context.Context) (<-chan *generated.TempoState, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicTempo, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.TempoState, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if state, valid := msg.(*generated.TempoState); valid {
+					select {
+					case outputChan <- state:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
+}
+
+// StandbyStatusUpdated is the resolver for the standbyStatusUpdated field.
+func (r *subscriptionResolver) StandbyStatusUpdated(ctx context.Context) (<-chan *generated.StandbyStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicStandby, "", 10)
+
+	// Create the output channel
+	outputChan := make(chan *generated.StandbyStatus, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if status, valid := msg.(*generated.StandbyStatus); valid {
+					select {
+					case outputChan <- status:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan, nil
 }
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *sceneBoardButtonResolver) CreatedAt(ctx context.Context, obj *models.SceneBoardButton) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
-}
+// MasterLevelChanged is the resolver for the masterLevelChanged field.
+func (r *subscriptionResolver) MasterLevelChanged(ctx context.Context) (<-chan *generated.MasterLevels, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicMasterLevel, "", 10)
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *sceneBoardButtonResolver) UpdatedAt(ctx context.Context, obj *models.SceneBoardButton) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
-}
+	// Create the output channel
+	outputChan := make(chan *generated.MasterLevels, 10)
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *settingResolver) CreatedAt(ctx context.Context, obj *models.Setting) (string, error) {
-	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
-}
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if levels, valid := msg.(*generated.MasterLevels); valid {
+					select {
+					case outputChan <- levels:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
+					}
+				}
+			}
+		}
+	}()
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *settingResolver) UpdatedAt(ctx context.Context, obj *models.Setting) (string, error) {
-	return obj.UpdatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+	return outputChan, nil
 }
 
-// DmxOutputChanged is the resolver for the dmxOutputChanged field.
-func (r *subscriptionResolver) DmxOutputChanged(ctx context.Context, universe *int) (<-chan *generated.UniverseOutput, error) {
-	// Create a filter string if universe is specified
-	filter := ""
-	if universe != nil {
-		filter = fmt.Sprintf("%d", *universe)
-	}
-
-	// Subscribe to DMX output changes
-	sub := r.PubSub.Subscribe(pubsub.TopicDMXOutput, filter, 10)
+// SubmasterLevelChanged is the resolver for the submasterLevelChanged field.
+func (r *subscriptionResolver) SubmasterLevelChanged(ctx context.Context, projectID string) (<-chan *models.Submaster, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicSubmasterLevel, projectID, 10)
 
 	// Create the output channel
-	outputChan := make(chan *generated.UniverseOutput, 10)
+	outputChan := make(chan *models.Submaster, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4687,9 +8782,9 @@ func (r *subscriptionResolver) DmxOutputChanged(ctx context.Context, universe *i
 				if !ok {
 					return
 				}
-				if output, valid := msg.(*generated.UniverseOutput); valid {
+				if submaster, valid := msg.(*models.Submaster); valid {
 					select {
-					case outputChan <- output:
+					case outputChan <- submaster:
 					case <-ctx.Done():
 						r.PubSub.Unsubscribe(sub)
 						return
@@ -4702,13 +8797,12 @@ func (r *subscriptionResolver) DmxOutputChanged(ctx context.Context, universe *i
 	return outputChan, nil
 }
 
-// ProjectUpdated is the resolver for the projectUpdated field.
-func (r *subscriptionResolver) ProjectUpdated(ctx context.Context, projectID string) (<-chan *models.Project, error) {
-	// Subscribe to project updates filtered by projectID
-	sub := r.PubSub.Subscribe(pubsub.TopicProjectUpdated, projectID, 10)
+// BlackoutStatusChanged is the resolver for the blackoutStatusChanged field.
+func (r *subscriptionResolver) BlackoutStatusChanged(ctx context.Context) (<-chan *generated.BlackoutStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicBlackout, "", 10)
 
 	// Create the output channel
-	outputChan := make(chan *models.Project, 10)
+	outputChan := make(chan *generated.BlackoutStatus, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4722,9 +8816,9 @@ func (r *subscriptionResolver) ProjectUpdated(ctx context.Context, projectID str
 				if !ok {
 					return
 				}
-				if project, valid := msg.(*models.Project); valid {
+				if status, valid := msg.(*generated.BlackoutStatus); valid {
 					select {
-					case outputChan <- project:
+					case outputChan <- status:
 					case <-ctx.Done():
 						r.PubSub.Unsubscribe(sub)
 						return
@@ -4737,13 +8831,12 @@ func (r *subscriptionResolver) ProjectUpdated(ctx context.Context, projectID str
 	return outputChan, nil
 }
 
-// PreviewSessionUpdated is the resolver for the previewSessionUpdated field.
-func (r *subscriptionResolver) PreviewSessionUpdated(ctx context.Context, projectID string) (<-chan *models.PreviewSession, error) {
-	// Subscribe to preview session updates filtered by projectID
-	sub := r.PubSub.Subscribe(pubsub.TopicPreviewSession, projectID, 10)
+// HouseLightsChanged is the resolver for the houseLightsChanged field.
+func (r *subscriptionResolver) HouseLightsChanged(ctx context.Context, projectID string) (<-chan *generated.HouseLightsStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicHouseLights, projectID, 10)
 
 	// Create the output channel
-	outputChan := make(chan *models.PreviewSession, 10)
+	outputChan := make(chan *generated.HouseLightsStatus, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4757,9 +8850,9 @@ func (r *subscriptionResolver) PreviewSessionUpdated(ctx context.Context, projec
 				if !ok {
 					return
 				}
-				if session, valid := msg.(*models.PreviewSession); valid {
+				if status, valid := msg.(*generated.HouseLightsStatus); valid {
 					select {
-					case outputChan <- session:
+					case outputChan <- status:
 					case <-ctx.Done():
 						r.PubSub.Unsubscribe(sub)
 						return
@@ -4772,13 +8865,12 @@ func (r *subscriptionResolver) PreviewSessionUpdated(ctx context.Context, projec
 	return outputChan, nil
 }
 
-// CueListPlaybackUpdated is the resolver for the cueListPlaybackUpdated field.
-func (r *subscriptionResolver) CueListPlaybackUpdated(ctx context.Context, cueListID string) (<-chan *generated.CueListPlaybackStatus, error) {
-	// Subscribe to cue list playback updates filtered by cueListID
-	sub := r.PubSub.Subscribe(pubsub.TopicCueListPlayback, cueListID, 10)
+// TimecodeStatusChanged is the resolver for the timecodeStatusChanged field.
+func (r *subscriptionResolver) TimecodeStatusChanged(ctx context.Context) (<-chan *generated.TimecodeStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicTimecode, "", 10)
 
 	// Create the output channel
-	outputChan := make(chan *generated.CueListPlaybackStatus, 10)
+	outputChan := make(chan *generated.TimecodeStatus, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4792,7 +8884,7 @@ func (r *subscriptionResolver) CueListPlaybackUpdated(ctx context.Context, cueLi
 				if !ok {
 					return
 				}
-				if status, valid := msg.(*generated.CueListPlaybackStatus); valid {
+				if status, valid := msg.(*generated.TimecodeStatus); valid {
 					select {
 					case outputChan <- status:
 					case <-ctx.Done():
@@ -4807,13 +8899,12 @@ func (r *subscriptionResolver) CueListPlaybackUpdated(ctx context.Context, cueLi
 	return outputChan, nil
 }
 
-// GlobalPlaybackStatusUpdated is the resolver for the globalPlaybackStatusUpdated field.
-func (r *subscriptionResolver) GlobalPlaybackStatusUpdated(ctx context.Context) (<-chan *generated.GlobalPlaybackStatus, error) {
-	// Subscribe to global playback status updates (no filter, receives all updates)
-	sub := r.PubSub.Subscribe(pubsub.TopicGlobalPlaybackStatus, "", 10)
+// AudioInputStatusChanged is the resolver for the audioInputStatusChanged field.
+func (r *subscriptionResolver) AudioInputStatusChanged(ctx context.Context) (<-chan *generated.AudioInputStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicAudioInput, "", 10)
 
 	// Create the output channel
-	outputChan := make(chan *generated.GlobalPlaybackStatus, 10)
+	outputChan := make(chan *generated.AudioInputStatus, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4827,7 +8918,7 @@ func (r *subscriptionResolver) GlobalPlaybackStatusUpdated(ctx context.Context)
 				if !ok {
 					return
 				}
-				if status, valid := msg.(*generated.GlobalPlaybackStatus); valid {
+				if status, valid := msg.(*generated.AudioInputStatus); valid {
 					select {
 					case outputChan <- status:
 					case <-ctx.Done():
@@ -4842,13 +8933,12 @@ func (r *subscriptionResolver) GlobalPlaybackStatusUpdated(ctx context.Context)
 	return outputChan, nil
 }
 
-// SystemInfoUpdated is the resolver for the systemInfoUpdated field.
-func (r *subscriptionResolver) SystemInfoUpdated(ctx context.Context) (<-chan *generated.SystemInfo, error) {
-	// Subscribe to system info updates (no filter, receives all updates)
-	sub := r.PubSub.Subscribe(pubsub.TopicSystemInfo, "", 10)
+// UndoStackChanged is the resolver for the undoStackChanged field.
+func (r *subscriptionResolver) UndoStackChanged(ctx context.Context, projectID string) (<-chan *generated.UndoStackStatus, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicUndoStack, projectID, 10)
 
 	// Create the output channel
-	outputChan := make(chan *generated.SystemInfo, 10)
+	outputChan := make(chan *generated.UndoStackStatus, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4862,9 +8952,9 @@ func (r *subscriptionResolver) SystemInfoUpdated(ctx context.Context) (<-chan *g
 				if !ok {
 					return
 				}
-				if info, valid := msg.(*generated.SystemInfo); valid {
+				if status, valid := msg.(*generated.UndoStackStatus); valid {
 					select {
-					case outputChan <- info:
+					case outputChan <- status:
 					case <-ctx.Done():
 						r.PubSub.Unsubscribe(sub)
 						return
@@ -4877,17 +8967,23 @@ func (r *subscriptionResolver) SystemInfoUpdated(ctx context.Context) (<-chan *g
 	return outputChan, nil
 }
 
-// WifiStatusUpdated is the resolver for the wifiStatusUpdated field.
-func (r *subscriptionResolver) WifiStatusUpdated(ctx context.Context) (<-chan *generated.WiFiStatus, error) {
-	// Subscribe to WiFi status updates (no filter, receives all updates)
-	sub := r.PubSub.Subscribe(pubsub.TopicWiFiStatus, "", 10)
+// PresenceChanged is the resolver for the presenceChanged field.
+func (r *subscriptionResolver) PresenceChanged(ctx context.Context, projectID string, sessionID *string) (<-chan *generated.ProjectPresence, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicPresence, projectID, 10)
+
+	// The session stays present, and keeps any edit lock, while subscribed
+	detach := func() {}
+	if sessionID != nil && *sessionID != "" {
+		detach = r.PresenceService.Attach(*sessionID)
+	}
 
 	// Create the output channel
-	outputChan := make(chan *generated.WiFiStatus, 10)
+	outputChan := make(chan *generated.ProjectPresence, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
 		defer close(outputChan)
+		defer detach()
 		for {
 			select {
 			case <-ctx.Done():
@@ -4897,7 +8993,7 @@ func (r *subscriptionResolver) WifiStatusUpdated(ctx context.Context) (<-chan *g
 				if !ok {
 					return
 				}
-				if status, valid := msg.(*generated.WiFiStatus); valid {
+				if status, valid := msg.(*generated.ProjectPresence); valid {
 					select {
 					case outputChan <- status:
 					case <-ctx.Done():
@@ -4912,13 +9008,22 @@ func (r *subscriptionResolver) WifiStatusUpdated(ctx context.Context) (<-chan *g
 	return outputChan, nil
 }
 
-// WifiModeChanged is the resolver for the wifiModeChanged field.
-func (r *subscriptionResolver) WifiModeChanged(ctx context.Context) (<-chan generated.WiFiMode, error) {
-	// Subscribe to WiFi mode changes (no filter)
-	sub := r.PubSub.Subscribe(pubsub.TopicWiFiModeChanged, "", 10)
+// SceneBoardStateChanged is the resolver for the sceneBoardStateChanged field.
+func (r *subscriptionResolver) SceneBoardStateChanged(ctx context.Context, sceneBoardID string) (<-chan *generated.SceneBoardLiveState, error) {
+	return r.subscribeSceneBoardState(ctx, sceneBoardID)
+}
+
+// ControlSurfaceStateChanged is the resolver for the controlSurfaceStateChanged field.
+func (r *subscriptionResolver) ControlSurfaceStateChanged(ctx context.Context) (<-chan *generated.ControlSurfaceState, error) {
+	return r.subscribeControlSurfaceState(ctx)
+}
+
+// SceneUpdated is the resolver for the sceneUpdated field.
+func (r *subscriptionResolver) SceneUpdated(ctx context.Context, projectID string) (<-chan *models.Scene, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicSceneUpdated, projectID, 10)
 
 	// Create the output channel
-	outputChan := make(chan generated.WiFiMode, 10)
+	outputChan := make(chan *models.Scene, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4932,11 +9037,12 @@ func (r *subscriptionResolver) WifiModeChanged(ctx context.Context) (<-chan gene
 				if !ok {
 					return
 				}
-				if mode, valid := msg.(generated.WiFiMode); valid {
+				if scene, valid := msg.(*models.Scene); valid {
 					select {
-					case outputChan <- mode:
-					default:
-						// Channel full, skip message
+					case outputChan <- scene:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
 					}
 				}
 			}
@@ -4946,13 +9052,12 @@ func (r *subscriptionResolver) WifiModeChanged(ctx context.Context) (<-chan gene
 	return outputChan, nil
 }
 
-// OflImportProgress is the resolver for the oflImportProgress field.
-func (r *subscriptionResolver) OflImportProgress(ctx context.Context) (<-chan *generated.OFLImportStatus, error) {
-	// Subscribe to OFL import progress updates (no filter, receives all updates)
-	sub := r.PubSub.Subscribe(pubsub.TopicOFLImportProgress, "", 10)
+// ScheduleFired is the resolver for the scheduleFired field.
+func (r *subscriptionResolver) ScheduleFired(ctx context.Context, projectID string) (<-chan *generated.ScheduleFiredEvent, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicScheduleFired, projectID, 10)
 
 	// Create the output channel
-	outputChan := make(chan *generated.OFLImportStatus, 10)
+	outputChan := make(chan *generated.ScheduleFiredEvent, 10)
 
 	// Start a goroutine to forward messages
 	go func() {
@@ -4966,56 +9071,86 @@ func (r *subscriptionResolver) OflImportProgress(ctx context.Context) (<-chan *g
 				if !ok {
 					return
 				}
-				if status, valid := msg.(*ofl.ProgressStatus); valid {
-					// Convert to GraphQL type
-					var startedAt, completedAt *string
-					if status.StartedAt != nil {
-						s := status.StartedAt.Format("2006-01-02T15:04:05Z07:00")
-						startedAt = &s
-					}
-					if status.CompletedAt != nil {
-						s := status.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
-						completedAt = &s
+				if event, valid := msg.(*generated.ScheduleFiredEvent); valid {
+					select {
+					case outputChan <- event:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
 					}
+				}
+			}
+		}
+	}()
 
-					var errorMessage *string
-					if status.ErrorMessage != "" {
-						errorMessage = &status.ErrorMessage
-					}
+	return outputChan, nil
+}
 
-					var currentFixture, currentManufacturer *string
-					if status.CurrentFixture != "" {
-						currentFixture = &status.CurrentFixture
-					}
-					if status.CurrentManufacturer != "" {
-						currentManufacturer = &status.CurrentManufacturer
-					}
+// LogEntryAdded is the resolver for the logEntryAdded field.
+func (r *subscriptionResolver) LogEntryAdded(ctx context.Context, module *string, minLevel *generated.LogLevel) (<-chan *generated.LogEntry, error) {
+	filter, err := logFilter(module, minLevel, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return subscribeLogEntries(ctx, filter), nil
+}
 
-					var oflVersion *string
-					if status.OFLVersion != "" {
-						oflVersion = &status.OFLVersion
-					}
+// LayoutChanged is the resolver for the layoutChanged field.
+func (r *subscriptionResolver) LayoutChanged(ctx context.Context, projectID string) (<-chan *generated.LayoutChange, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicLayoutChanged, projectID, 10)
 
-					gqlStatus := &generated.OFLImportStatus{
-						IsImporting:               status.IsImporting,
-						Phase:                     generated.OFLImportPhase(status.Phase),
-						TotalFixtures:             status.TotalFixtures,
-						ImportedCount:             status.ImportedCount,
-						FailedCount:               status.FailedCount,
-						SkippedCount:              status.SkippedCount,
-						PercentComplete:           status.PercentComplete,
-						CurrentFixture:            currentFixture,
-						CurrentManufacturer:       currentManufacturer,
-						EstimatedSecondsRemaining: status.EstimatedSecondsRemaining,
-						ErrorMessage:              errorMessage,
-						StartedAt:                 startedAt,
-						CompletedAt:               completedAt,
-						OflVersion:                oflVersion,
-						UsingBundledData:          status.UsingBundledData,
+	// Create the output channel
+	outputChan := make(chan *generated.LayoutChange, 10)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if change, valid := msg.(*generated.LayoutChange); valid {
+					select {
+					case outputChan <- change:
+					case <-ctx.Done():
+						r.PubSub.Unsubscribe(sub)
+						return
 					}
+				}
+			}
+		}
+	}()
 
+	return outputChan, nil
+}
+
+// ProjectEntityChanged is the resolver for the projectEntityChanged field.
+func (r *subscriptionResolver) ProjectEntityChanged(ctx context.Context, projectID string, entityTypes []generated.ProjectEntityType) (<-chan *generated.ProjectEntityChange, error) {
+	sub := r.PubSub.Subscribe(pubsub.TopicProjectEntityChanged, projectID, 100)
+
+	// Create the output channel
+	outputChan := make(chan *generated.ProjectEntityChange, 100)
+
+	// Start a goroutine to forward messages
+	go func() {
+		defer close(outputChan)
+		for {
+			select {
+			case <-ctx.Done():
+				r.PubSub.Unsubscribe(sub)
+				return
+			case msg, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if change, valid := msg.(*generated.ProjectEntityChange); valid && wantsEntityChange(entityTypes, change) {
 					select {
-					case outputChan <- gqlStatus:
+					case outputChan <- change:
 					case <-ctx.Done():
 						r.PubSub.Unsubscribe(sub)
 						return
@@ -5028,6 +9163,27 @@ func (r *subscriptionResolver) OflImportProgress(ctx context.Context) (<-chan *g
 	return outputChan, nil
 }
 
+// Protocol is the resolver for the protocol field.
+func (r *universeResolver) Protocol(ctx context.Context, obj *models.Universe) (generated.UniverseProtocol, error) {
+	return generated.UniverseProtocol(obj.Protocol), nil
+}
+
+// FixtureCount is the resolver for the fixtureCount field.
+func (r *universeResolver) FixtureCount(ctx context.Context, obj *models.Universe) (int, error) {
+	count, err := r.UniverseRepo.CountFixtures(ctx, obj.ProjectID, obj.Number)
+	return int(count), err
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *universeResolver) CreatedAt(ctx context.Context, obj *models.Universe) (string, error) {
+	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *universeResolver) UpdatedAt(ctx context.Context, obj *models.Universe) (string, error) {
+	return formatUpdatedAt(obj.UpdatedAt), nil
+}
+
 // Role is the resolver for the role field.
 func (r *userResolver) Role(ctx context.Context, obj *models.User) (generated.UserRole, error) {
 	if obj.Role != "" {
@@ -5041,6 +9197,9 @@ func (r *userResolver) CreatedAt(ctx context.Context, obj *models.User) (string,
 	return obj.CreatedAt.Format("2006-01-02T15:04:05.000Z"), nil
 }
 
+// AuditLog returns generated.AuditLogResolver implementation.
+func (r *Resolver) AuditLog() generated.AuditLogResolver { return &auditLogResolver{r} }
+
 // ChannelDefinition returns generated.ChannelDefinitionResolver implementation.
 func (r *Resolver) ChannelDefinition() generated.ChannelDefinitionResolver {
 	return &channelDefinitionResolver{r}
@@ -5052,11 +9211,17 @@ func (r *Resolver) Cue() generated.CueResolver { return &cueResolver{r} }
 // CueList returns generated.CueListResolver implementation.
 func (r *Resolver) CueList() generated.CueListResolver { return &cueListResolver{r} }
 
+// Effect returns generated.EffectResolver implementation.
+func (r *Resolver) Effect() generated.EffectResolver { return &effectResolver{r} }
+
 // FixtureDefinition returns generated.FixtureDefinitionResolver implementation.
 func (r *Resolver) FixtureDefinition() generated.FixtureDefinitionResolver {
 	return &fixtureDefinitionResolver{r}
 }
 
+// FixtureGroup returns generated.FixtureGroupResolver implementation.
+func (r *Resolver) FixtureGroup() generated.FixtureGroupResolver { return &fixtureGroupResolver{r} }
+
 // FixtureInstance returns generated.FixtureInstanceResolver implementation.
 func (r *Resolver) FixtureInstance() generated.FixtureInstanceResolver {
 	return &fixtureInstanceResolver{r}
@@ -5068,17 +9233,32 @@ func (r *Resolver) FixtureMode() generated.FixtureModeResolver { return &fixture
 // FixtureValue returns generated.FixtureValueResolver implementation.
 func (r *Resolver) FixtureValue() generated.FixtureValueResolver { return &fixtureValueResolver{r} }
 
+// GroupValue returns generated.GroupValueResolver implementation.
+func (r *Resolver) GroupValue() generated.GroupValueResolver { return &groupValueResolver{r} }
+
+// HouseLights returns generated.HouseLightsResolver implementation.
+func (r *Resolver) HouseLights() generated.HouseLightsResolver { return &houseLightsResolver{r} }
+
 // InstanceChannel returns generated.InstanceChannelResolver implementation.
 func (r *Resolver) InstanceChannel() generated.InstanceChannelResolver {
 	return &instanceChannelResolver{r}
 }
 
+// LayoutZone returns generated.LayoutZoneResolver implementation.
+func (r *Resolver) LayoutZone() generated.LayoutZoneResolver { return &layoutZoneResolver{r} }
+
 // ModeChannel returns generated.ModeChannelResolver implementation.
 func (r *Resolver) ModeChannel() generated.ModeChannelResolver { return &modeChannelResolver{r} }
 
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Palette returns generated.PaletteResolver implementation.
+func (r *Resolver) Palette() generated.PaletteResolver { return &paletteResolver{r} }
+
+// PixelMatrix returns generated.PixelMatrixResolver implementation.
+func (r *Resolver) PixelMatrix() generated.PixelMatrixResolver { return &pixelMatrixResolver{r} }
+
 // PreviewSession returns generated.PreviewSessionResolver implementation.
 func (r *Resolver) PreviewSession() generated.PreviewSessionResolver {
 	return &previewSessionResolver{r}
@@ -5087,6 +9267,11 @@ func (r *Resolver) PreviewSession() generated.PreviewSessionResolver {
 // Project returns generated.ProjectResolver implementation.
 func (r *Resolver) Project() generated.ProjectResolver { return &projectResolver{r} }
 
+// ProjectSnapshot returns generated.ProjectSnapshotResolver implementation.
+func (r *Resolver) ProjectSnapshot() generated.ProjectSnapshotResolver {
+	return &projectSnapshotResolver{r}
+}
+
 // ProjectUser returns generated.ProjectUserResolver implementation.
 func (r *Resolver) ProjectUser() generated.ProjectUserResolver { return &projectUserResolver{r} }
 
@@ -5104,32 +9289,65 @@ func (r *Resolver) SceneBoardButton() generated.SceneBoardButtonResolver {
 	return &sceneBoardButtonResolver{r}
 }
 
+// SceneSummary returns generated.SceneSummaryResolver implementation.
+func (r *Resolver) SceneSummary() generated.SceneSummaryResolver { return &sceneSummaryResolver{r} }
+
+// Schedule returns generated.ScheduleResolver implementation.
+func (r *Resolver) Schedule() generated.ScheduleResolver { return &scheduleResolver{r} }
+
+// SelectionSet returns generated.SelectionSetResolver implementation.
+func (r *Resolver) SelectionSet() generated.SelectionSetResolver { return &selectionSetResolver{r} }
+
 // Setting returns generated.SettingResolver implementation.
 func (r *Resolver) Setting() generated.SettingResolver { return &settingResolver{r} }
 
+// SoftPatch returns generated.SoftPatchResolver implementation.
+func (r *Resolver) SoftPatch() generated.SoftPatchResolver { return &softPatchResolver{r} }
+
+// Submaster returns generated.SubmasterResolver implementation.
+func (r *Resolver) Submaster() generated.SubmasterResolver { return &submasterResolver{r} }
+
 // Subscription returns generated.SubscriptionResolver implementation.
 func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
 
+// Universe returns generated.UniverseResolver implementation.
+func (r *Resolver) Universe() generated.UniverseResolver { return &universeResolver{r} }
+
 // User returns generated.UserResolver implementation.
 func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
 
+type auditLogResolver struct{ *Resolver }
 type channelDefinitionResolver struct{ *Resolver }
 type cueResolver struct{ *Resolver }
 type cueListResolver struct{ *Resolver }
+type effectResolver struct{ *Resolver }
 type fixtureDefinitionResolver struct{ *Resolver }
+type fixtureGroupResolver struct{ *Resolver }
 type fixtureInstanceResolver struct{ *Resolver }
 type fixtureModeResolver struct{ *Resolver }
 type fixtureValueResolver struct{ *Resolver }
+type groupValueResolver struct{ *Resolver }
+type houseLightsResolver struct{ *Resolver }
 type instanceChannelResolver struct{ *Resolver }
+type layoutZoneResolver struct{ *Resolver }
 type modeChannelResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type paletteResolver struct{ *Resolver }
+type pixelMatrixResolver struct{ *Resolver }
 type previewSessionResolver struct{ *Resolver }
 type projectResolver struct{ *Resolver }
+type projectSnapshotResolver struct{ *Resolver }
 type projectUserResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
 type sceneBoardResolver struct{ *Resolver }
 type sceneBoardButtonResolver struct{ *Resolver }
+type sceneSummaryResolver struct{ *Resolver }
+type scheduleResolver struct{ *Resolver }
+type selectionSetResolver struct{ *Resolver }
 type settingResolver struct{ *Resolver }
+type softPatchResolver struct{ *Resolver }
+type submasterResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
+type universeResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
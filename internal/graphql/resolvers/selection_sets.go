@@ -0,0 +1,229 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/selection"
+)
+
+// findSelectionSet loads a selection set by ID.
+func (r *Resolver) findSelectionSet(ctx context.Context, id string) (*models.SelectionSet, error) {
+	set, err := r.SelectionSetRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, fmt.Errorf("selection set not found: %s", id)
+	}
+	return set, nil
+}
+
+// createSelectionSet saves an ordered fixture list of a project.
+func (r *Resolver) createSelectionSet(ctx context.Context, input generated.CreateSelectionSetInput) (*models.SelectionSet, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	set := &models.SelectionSet{
+		ProjectID:   input.ProjectID,
+		Name:        strings.TrimSpace(input.Name),
+		Description: trimmedOrNil(input.Description.Value()),
+	}
+	if set.Name == "" {
+		return nil, errors.New("selection set name is required")
+	}
+	if err := r.setSelectionSetFixtures(ctx, set, input.FixtureIds, input.Order.Value()); err != nil {
+		return nil, err
+	}
+	if err := r.SelectionSetRepo.Create(ctx, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// updateSelectionSet changes a selection set's name, description, fixtures,
+// or their order.
+func (r *Resolver) updateSelectionSet(ctx context.Context, id string, input generated.UpdateSelectionSetInput) (*models.SelectionSet, error) {
+	set, err := r.findSelectionSet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v := input.Name.Value(); v != nil {
+		set.Name = strings.TrimSpace(*v)
+		if set.Name == "" {
+			return nil, errors.New("selection set name is required")
+		}
+	}
+	if input.Description.IsSet() {
+		set.Description = trimmedOrNil(input.Description.Value())
+	}
+
+	fixtureIDs := input.FixtureIds.Value()
+	if fixtureIDs == nil && input.Order.Value() != nil {
+		if fixtureIDs, err = repositories.SelectionSetFixtureIDs(set); err != nil {
+			return nil, err
+		}
+	}
+	if fixtureIDs != nil {
+		if err := r.setSelectionSetFixtures(ctx, set, fixtureIDs, input.Order.Value()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.SelectionSetRepo.Update(ctx, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// deleteSelectionSet removes a selection set. Effects and groups made from
+// it keep their own copies of its fixtures.
+func (r *Resolver) deleteSelectionSet(ctx context.Context, id string) error {
+	if _, err := r.findSelectionSet(ctx, id); err != nil {
+		return err
+	}
+	return r.SelectionSetRepo.Delete(ctx, id)
+}
+
+// setSelectionSetFixtures validates, orders, and stores a set's fixtures.
+func (r *Resolver) setSelectionSetFixtures(ctx context.Context, set *models.SelectionSet, fixtureIDs []string, order *generated.SelectionOrder) error {
+	if err := r.validateProjectFixtures(ctx, set.ProjectID, fixtureIDs); err != nil {
+		return err
+	}
+	if order != nil {
+		var err error
+		if fixtureIDs, err = r.orderFixtureIDs(ctx, fixtureIDs, *order); err != nil {
+			return err
+		}
+	}
+	if fixtureIDs == nil {
+		fixtureIDs = []string{}
+	}
+	fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+	if err != nil {
+		return err
+	}
+	set.FixtureIDs = string(fixtureIDsJSON)
+	return nil
+}
+
+// removeFixtureFromSelectionSets drops a deleted fixture from the selection
+// sets it was in.
+func (r *Resolver) removeFixtureFromSelectionSets(ctx context.Context, fixtureID string) error {
+	sets, err := r.SelectionSetRepo.FindByFixtureID(ctx, fixtureID)
+	if err != nil {
+		return err
+	}
+	for i := range sets {
+		set := &sets[i]
+		ids, err := repositories.SelectionSetFixtureIDs(set)
+		if err != nil {
+			return err
+		}
+		fixtureIDsJSON, err := json.Marshal(slices.DeleteFunc(ids, func(id string) bool { return id == fixtureID }))
+		if err != nil {
+			return err
+		}
+		set.FixtureIDs = string(fixtureIDsJSON)
+		if err := r.SelectionSetRepo.Update(ctx, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderedFixtures loads fixtures by ID in the given order, skipping any that
+// do not exist.
+func (r *Resolver) orderedFixtures(ctx context.Context, ids []string) ([]*models.FixtureInstance, error) {
+	var fixtures []models.FixtureInstance
+	if len(ids) > 0 {
+		if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&fixtures).Error; err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[string]*models.FixtureInstance, len(fixtures))
+	for i := range fixtures {
+		byID[fixtures[i].ID] = &fixtures[i]
+	}
+	result := make([]*models.FixtureInstance, 0, len(ids))
+	for _, id := range ids {
+		if fixture, ok := byID[id]; ok {
+			result = append(result, fixture)
+		}
+	}
+	return result, nil
+}
+
+// orderFixtureIDs puts fixtures of one project in an order, using their
+// layout positions for the layout orders.
+func (r *Resolver) orderFixtureIDs(ctx context.Context, fixtureIDs []string, order generated.SelectionOrder) ([]string, error) {
+	fixtures, err := r.orderedFixtures(ctx, fixtureIDs)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]selection.Item, len(fixtures))
+	for i, fixture := range fixtures {
+		if fixture.ProjectID != fixtures[0].ProjectID {
+			return nil, errors.New("fixtures must belong to one project")
+		}
+		items[i] = selection.Item{ID: fixture.ID, X: fixture.LayoutX, Y: fixture.LayoutY}
+	}
+	if len(fixtures) != len(fixtureIDs) {
+		for _, id := range fixtureIDs {
+			if !slices.ContainsFunc(fixtures, func(f *models.FixtureInstance) bool { return f.ID == id }) {
+				return nil, fmt.Errorf("fixture not found: %s", id)
+			}
+		}
+	}
+
+	ordered, err := selection.Apply(items, selection.Order(order))
+	if err != nil {
+		return nil, err
+	}
+	return selection.IDs(ordered), nil
+}
+
+// selectFixtures works out the fixtures an effect or group is given: a list
+// of its own or a selection set's, in the order asked for. An order alone
+// rearranges the current list. It returns nil when the fixtures are not
+// being changed.
+func (r *Resolver) selectFixtures(ctx context.Context, projectID string, fixtureIDs []string, setID *string, order *generated.SelectionOrder, current []string) ([]string, error) {
+	if fixtureIDs != nil && setID != nil {
+		return nil, errors.New("give fixtureIds or selectionSetId, not both")
+	}
+	ids := fixtureIDs
+	if setID != nil {
+		set, err := r.findSelectionSet(ctx, *setID)
+		if err != nil {
+			return nil, err
+		}
+		if set.ProjectID != projectID {
+			return nil, fmt.Errorf("selection set not found in project: %s", *setID)
+		}
+		if ids, err = repositories.SelectionSetFixtureIDs(set); err != nil {
+			return nil, err
+		}
+	}
+	if ids == nil {
+		if order == nil {
+			return nil, nil
+		}
+		ids = current
+	}
+	if order == nil {
+		return ids, nil
+	}
+	return r.orderFixtureIDs(ctx, ids, *order)
+}
@@ -0,0 +1,206 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/internal/services/audio"
+	"github.com/bbernstein/lacylights-go/internal/services/channelvalue"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/input"
+	"github.com/bbernstein/lacylights-go/internal/services/settings"
+	"github.com/bbernstein/lacylights-go/internal/services/standby"
+	"github.com/bbernstein/lacylights-go/internal/services/timecode"
+)
+
+// registerSettings registers the settings updateSetting may change, each
+// applied live by the service it configures.
+func (r *Resolver) registerSettings() {
+	r.registerSetting(settings.Definition{
+		Key:         dmx.BroadcastAddressSettingKey,
+		Type:        settings.TypeString,
+		Description: "Art-Net broadcast address, overriding ARTNET_BROADCAST",
+		Validate: func(value string) error {
+			if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+				return errors.New("must be an IPv4 address")
+			}
+			return nil
+		},
+	}, r.loadBroadcastAddress)
+	r.registerSetting(settings.Definition{
+		Key:         fade.UpdateRateSettingKey,
+		Type:        settings.TypeInteger,
+		Description: "Fade engine update rate in Hz, overriding FADE_UPDATE_RATE_HZ",
+		Validate: func(value string) error {
+			rateHz, _ := strconv.Atoi(value)
+			return validateFadeUpdateRate(rateHz)
+		},
+	}, r.loadFadeUpdateRate)
+	r.registerSetting(settings.Definition{
+		Key:         dmx.ArtSyncSettingKey,
+		Type:        settings.TypeBoolean,
+		Description: "Whether ArtSync follows each burst of Art-Net output, overriding ARTNET_SYNC",
+	}, r.loadArtSync)
+	r.registerSetting(settings.Definition{
+		Key:         dmx.BindSettingKey,
+		Type:        settings.TypeString,
+		Description: "Interface name or IPv4 address Art-Net output is sent from, overriding ARTNET_BIND",
+		Validate:    validateArtNetBind,
+	}, r.loadArtNetBind)
+	r.registerSetting(settings.Definition{
+		Key:         dmx.UnicastRoutesSettingKey,
+		Type:        settings.TypeJSON,
+		Default:     "[]",
+		Description: "Art-Net unicast routing table",
+		Validate: func(value string) error {
+			var routes []dmx.UnicastRoute
+			if err := json.Unmarshal([]byte(value), &routes); err != nil {
+				return err
+			}
+			_, err := dmx.ResolveUnicastRoutes(routes, r.DMXService.GetPort())
+			return err
+		},
+	}, r.loadUnicastRoutes)
+	r.registerSetting(settings.Definition{
+		Key:         dmx.ChannelLimitsSettingKey,
+		Type:        settings.TypeJSON,
+		Default:     "[]",
+		Description: "Output channel limits, inhibits, and curves",
+		Validate: func(value string) error {
+			var limits []dmx.ChannelLimit
+			if err := json.Unmarshal([]byte(value), &limits); err != nil {
+				return err
+			}
+			return dmx.ValidateChannelLimits(limits)
+		},
+	}, r.loadChannelLimits)
+	r.registerSetting(settings.Definition{
+		Key:         dmx.ParkedChannelsSettingKey,
+		Type:        settings.TypeJSON,
+		Default:     "[]",
+		Description: "Output channels pinned at fixed values",
+		Validate: func(value string) error {
+			var parked []dmx.ParkedChannel
+			if err := json.Unmarshal([]byte(value), &parked); err != nil {
+				return err
+			}
+			return dmx.ValidateParkedChannels(parked)
+		},
+	}, r.loadParkedChannels)
+	r.registerSetting(settings.Definition{
+		Key:         standby.SettingKey,
+		Type:        settings.TypeJSON,
+		Description: "Standby schedule and output",
+		Validate: func(value string) error {
+			var config standby.Config
+			if err := json.Unmarshal([]byte(value), &config); err != nil {
+				return err
+			}
+			return config.Validate()
+		},
+	}, r.loadStandbyConfig)
+	r.registerSetting(settings.Definition{
+		Key:         input.SettingKey,
+		Type:        settings.TypeJSON,
+		Description: "Fader wing configuration",
+		Validate: func(value string) error {
+			var config input.Config
+			if err := json.Unmarshal([]byte(value), &config); err != nil {
+				return err
+			}
+			return config.Validate()
+		},
+	}, r.loadFaderWingConfig)
+	r.registerSetting(settings.Definition{
+		Key:         timecode.SettingKey,
+		Type:        settings.TypeJSON,
+		Description: "Timecode source and cue list triggers",
+		Validate: func(value string) error {
+			var config timecode.Config
+			if err := json.Unmarshal([]byte(value), &config); err != nil {
+				return err
+			}
+			return config.Validate()
+		},
+	}, r.loadTimecodeConfig)
+	r.registerSetting(settings.Definition{
+		Key:         audio.SettingKey,
+		Type:        settings.TypeJSON,
+		Description: "Audio input analyzed for beats that trigger scene board buttons",
+		Validate: func(value string) error {
+			var config audio.Config
+			if err := json.Unmarshal([]byte(value), &config); err != nil {
+				return err
+			}
+			return config.Validate()
+		},
+	}, r.loadAudioConfig)
+
+	// Read as each value is set, so nothing is applied when it changes
+	r.SettingsService.Register(settings.Definition{
+		Key:         channelvalue.SettingKey,
+		Type:        settings.TypeString,
+		Default:     string(channelvalue.DefaultMode),
+		Description: "What happens to channel values outside their channel's range: reject, clamp, or off",
+		Validate: func(value string) error {
+			_, err := channelvalue.ParseMode(value)
+			return err
+		},
+	})
+}
+
+// registerSetting registers a setting applied by load when it changes.
+func (r *Resolver) registerSetting(def settings.Definition, load settings.WatchFunc) {
+	r.SettingsService.Register(def)
+	r.SettingsService.Watch(def.Key, load)
+}
+
+// validateFadeUpdateRate checks a fade engine update rate.
+func validateFadeUpdateRate(rateHz int) error {
+	if rateHz < fade.MinUpdateRateHz || rateHz > fade.MaxUpdateRateHz {
+		return fmt.Errorf("fade update rate must be between %d and %d Hz", fade.MinUpdateRateHz, fade.MaxUpdateRateHz)
+	}
+	return nil
+}
+
+// loadBroadcastAddress applies the saved Art-Net broadcast address, if any.
+func (r *Resolver) loadBroadcastAddress(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.BroadcastAddressSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+	if err := r.DMXService.ReloadBroadcastAddress(setting.Value); err != nil {
+		log.Warn("failed to reload Art-Net broadcast address", "address", setting.Value, "error", err)
+	}
+}
+
+// loadFadeUpdateRate applies the saved fade engine update rate, if any.
+func (r *Resolver) loadFadeUpdateRate(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, fade.UpdateRateSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+	rateHz, err := strconv.Atoi(setting.Value)
+	if err == nil {
+		err = validateFadeUpdateRate(rateHz)
+	}
+	if err != nil {
+		log.Warn("invalid saved fade update rate", "value", setting.Value)
+		return
+	}
+	r.FadeEngine.SetUpdateRate(rateHz)
+}
+
+// settingType returns the type of a setting; unregistered settings are
+// strings.
+func (r *Resolver) settingType(key string) settings.Type {
+	if def, ok := r.SettingsService.Lookup(key); ok {
+		return def.Type
+	}
+	return settings.TypeString
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/snapshot"
+)
+
+// snapshotBefore saves a project ahead of a destructive operation. The
+// operation goes ahead even if the snapshot fails, so failures are only
+// logged.
+func (r *Resolver) snapshotBefore(ctx context.Context, projectID string, reason snapshot.Reason) {
+	if _, err := r.SnapshotService.Take(ctx, projectID, reason); err != nil {
+		log.Warn("failed to snapshot project", "project", projectID, "before", reason, "error", err)
+	}
+}
+
+// importResult converts the outcome of an import for GraphQL.
+func importResult(projectID string, stats *importservice.ImportStats, warnings []string) *generated.ImportResult {
+	result := &generated.ImportResult{
+		ProjectID: projectID,
+		Stats: generated.ImportStats{
+			FixtureDefinitionsCreated: stats.FixtureDefinitionsCreated,
+			FixtureInstancesCreated:   stats.FixtureInstancesCreated,
+			ScenesCreated:             stats.ScenesCreated,
+			CueListsCreated:           stats.CueListsCreated,
+			CuesCreated:               stats.CuesCreated,
+			SceneBoardsCreated:        stats.SceneBoardsCreated,
+			FixtureGroupsCreated:      stats.FixtureGroupsCreated,
+			PalettesCreated:           stats.PalettesCreated,
+		},
+		Warnings: warnings,
+		Changes:  make([]*generated.ImportChange, len(stats.Changes)),
+	}
+	for i, change := range stats.Changes {
+		result.Changes[i] = &generated.ImportChange{
+			EntityType: generated.ImportEntityType(change.EntityType),
+			Name:       change.Name,
+			Action:     generated.ImportAction(change.Action),
+		}
+		if change.Reason != "" {
+			reason := change.Reason
+			result.Changes[i].Reason = &reason
+		}
+	}
+	return result
+}
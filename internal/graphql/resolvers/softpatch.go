@@ -0,0 +1,161 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// softPatchTarget is an output address as stored in a softpatch entry.
+type softPatchTarget struct {
+	Universe int `json:"universe"`
+	Channel  int `json:"channel"`
+}
+
+// refreshSoftPatch pushes the softpatch entries of every project to the DMX
+// output stage. Like intensity caps they describe the venue, so all projects
+// apply; where projects patch the same channel its targets combine.
+func (r *Resolver) refreshSoftPatch(ctx context.Context) {
+	var stored []models.SoftPatch
+	if err := r.db.WithContext(ctx).Find(&stored).Error; err != nil {
+		log.Warn("failed to load softpatch", "error", err)
+		return
+	}
+
+	patch := make(map[dmx.Address][]dmx.Address, len(stored))
+	for i := range stored {
+		entry := &stored[i]
+		source := dmx.Address{Universe: entry.Universe, Channel: entry.Channel}
+		targets, err := softPatchTargets(entry)
+		if err == nil {
+			err = source.Validate()
+		}
+		if err != nil {
+			log.Warn("softpatch entry is invalid", "entry", entry.ID, "error", err)
+			continue
+		}
+		merged := patch[source]
+		if merged == nil {
+			merged = []dmx.Address{}
+		}
+		for _, target := range targets {
+			if !containsAddress(merged, target) {
+				merged = append(merged, target)
+			}
+		}
+		patch[source] = merged
+	}
+	if err := r.DMXService.SetSoftPatch(patch); err != nil {
+		log.Warn("failed to apply softpatch", "error", err)
+	}
+}
+
+// setSoftPatch validates and stores a project's softpatch entry for a
+// logical channel, replacing any it has, and applies it to the output.
+func (r *Resolver) setSoftPatch(ctx context.Context, input generated.SoftPatchInput) (*models.SoftPatch, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	source := dmx.Address{Universe: input.Universe, Channel: input.Channel}
+	if err := source.Validate(); err != nil {
+		return nil, err
+	}
+	targets := make([]softPatchTarget, 0, len(input.Targets))
+	for _, in := range input.Targets {
+		target := dmx.Address{Universe: in.Universe, Channel: in.Channel}
+		if err := target.Validate(); err != nil {
+			return nil, fmt.Errorf("target %d/%d: %w", in.Universe, in.Channel, err)
+		}
+		duplicate := false
+		for _, t := range targets {
+			duplicate = duplicate || (t.Universe == in.Universe && t.Channel == in.Channel)
+		}
+		if !duplicate {
+			targets = append(targets, softPatchTarget{Universe: in.Universe, Channel: in.Channel})
+		}
+	}
+	value, err := json.Marshal(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry models.SoftPatch
+	err = r.db.WithContext(ctx).
+		Where("project_id = ? AND universe = ? AND channel = ?", input.ProjectID, input.Universe, input.Channel).
+		First(&entry).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entry = models.SoftPatch{
+			ID:        cuid.New(),
+			ProjectID: input.ProjectID,
+			Universe:  input.Universe,
+			Channel:   input.Channel,
+			Targets:   string(value),
+		}
+		err = r.db.WithContext(ctx).Create(&entry).Error
+	case err == nil:
+		entry.Targets = string(value)
+		err = r.db.WithContext(ctx).Save(&entry).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.refreshSoftPatch(ctx)
+	return &entry, nil
+}
+
+// deleteSoftPatches deletes the softpatch entries matching a query and
+// applies what remains.
+func (r *Resolver) deleteSoftPatches(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result := r.db.WithContext(ctx).Where(query, args...).Delete(&models.SoftPatch{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.refreshSoftPatch(ctx)
+	}
+	return result.RowsAffected, nil
+}
+
+// softPatchTargets decodes and validates the output addresses of a stored
+// softpatch entry.
+func softPatchTargets(entry *models.SoftPatch) ([]dmx.Address, error) {
+	if entry.Targets == "" {
+		return nil, nil
+	}
+	var stored []softPatchTarget
+	if err := json.Unmarshal([]byte(entry.Targets), &stored); err != nil {
+		return nil, fmt.Errorf("invalid targets: %w", err)
+	}
+	targets := make([]dmx.Address, 0, len(stored))
+	for _, t := range stored {
+		target := dmx.Address{Universe: t.Universe, Channel: t.Channel}
+		if err := target.Validate(); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func containsAddress(addresses []dmx.Address, address dmx.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
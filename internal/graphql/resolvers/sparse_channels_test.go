@@ -750,3 +750,108 @@ func TestSparseChannels_EmptyChannelsArray(t *testing.T) {
 		t.Errorf("Channel 2 should remain 150, got %d", resolver.DMXService.GetChannelValue(1, 2))
 	}
 }
+
+// TestReplaceChannelValue tests project-wide channel value replacement with dry run and type filter
+func TestReplaceChannelValue(t *testing.T) {
+	c, resolver, cleanup := testSetup(t)
+	defer cleanup()
+
+	project := &models.Project{ID: "test-project-replace", Name: "Replace Project"}
+	resolver.db.Create(project)
+
+	fixtureDef := &models.FixtureDefinition{
+		ID:           "test-fixture-def-replace",
+		Manufacturer: "Test",
+		Model:        "TestSpot",
+		Type:         "MOVING_HEAD",
+	}
+	resolver.db.Create(fixtureDef)
+
+	fixture := &models.FixtureInstance{
+		ID:           "test-fixture-replace",
+		Name:         "Spot 1",
+		ProjectID:    project.ID,
+		DefinitionID: fixtureDef.ID,
+		Universe:     1,
+		StartChannel: 1,
+	}
+	resolver.db.Create(fixture)
+	resolver.db.Create(&models.InstanceChannel{ID: "ic-replace-0", FixtureID: fixture.ID, Offset: 0, Name: "Dimmer", Type: "INTENSITY"})
+	resolver.db.Create(&models.InstanceChannel{ID: "ic-replace-1", FixtureID: fixture.ID, Offset: 1, Name: "Gobo", Type: "GOBO"})
+
+	for _, id := range []string{"test-scene-replace-1", "test-scene-replace-2"} {
+		resolver.db.Create(&models.Scene{ID: id, Name: id, ProjectID: project.ID})
+		resolver.db.Create(&models.FixtureValue{
+			ID:        id + "-fv",
+			SceneID:   id,
+			FixtureID: fixture.ID,
+			Channels:  `[{"offset":0,"value":35},{"offset":1,"value":35}]`,
+		})
+	}
+
+	type replaceResp struct {
+		ReplaceChannelValue struct {
+			DryRun       bool `json:"dryRun"`
+			TotalChanges int  `json:"totalChanges"`
+			Scenes       []struct {
+				SceneID     string `json:"sceneId"`
+				ChangeCount int    `json:"changeCount"`
+			} `json:"scenes"`
+		} `json:"replaceChannelValue"`
+	}
+
+	// Dry run reports every match but changes nothing
+	var dryResp replaceResp
+	err := c.Post(`mutation($projectId: ID!) {
+		replaceChannelValue(projectId: $projectId, fromValue: 35, toValue: 40, dryRun: true) {
+			dryRun
+			totalChanges
+			scenes { sceneId changeCount }
+		}
+	}`, &dryResp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("replaceChannelValue dry run failed: %v", err)
+	}
+	if !dryResp.ReplaceChannelValue.DryRun || dryResp.ReplaceChannelValue.TotalChanges != 4 {
+		t.Errorf("Expected dry run with 4 changes, got %+v", dryResp.ReplaceChannelValue)
+	}
+	if len(dryResp.ReplaceChannelValue.Scenes) != 2 {
+		t.Errorf("Expected 2 scenes affected, got %d", len(dryResp.ReplaceChannelValue.Scenes))
+	}
+
+	var unchanged models.FixtureValue
+	resolver.db.First(&unchanged, "id = ?", "test-scene-replace-1-fv")
+	if unchanged.Channels != `[{"offset":0,"value":35},{"offset":1,"value":35}]` {
+		t.Errorf("Dry run should not modify channels, got %s", unchanged.Channels)
+	}
+
+	// Real run restricted to gobo channels
+	var resp replaceResp
+	err = c.Post(`mutation($projectId: ID!) {
+		replaceChannelValue(projectId: $projectId, filter: { channelType: GOBO }, fromValue: 35, toValue: 40) {
+			dryRun
+			totalChanges
+			scenes { sceneId changeCount }
+		}
+	}`, &resp, client.Var("projectId", project.ID))
+	if err != nil {
+		t.Fatalf("replaceChannelValue failed: %v", err)
+	}
+	if resp.ReplaceChannelValue.DryRun || resp.ReplaceChannelValue.TotalChanges != 2 {
+		t.Errorf("Expected 2 changes, got %+v", resp.ReplaceChannelValue)
+	}
+
+	var updated models.FixtureValue
+	resolver.db.First(&updated, "id = ?", "test-scene-replace-2-fv")
+	if updated.Channels != `[{"offset":0,"value":35},{"offset":1,"value":40}]` {
+		t.Errorf("Expected only gobo channel replaced, got %s", updated.Channels)
+	}
+
+	// Out-of-range values are rejected
+	err = c.Post(`mutation($projectId: ID!) {
+		replaceChannelValue(projectId: $projectId, fromValue: 35, toValue: 300) { totalChanges }
+	}`, &resp, client.Var("projectId", project.ID))
+	if err == nil {
+		t.Error("Expected error for out-of-range toValue")
+	}
+}
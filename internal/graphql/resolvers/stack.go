@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/playback"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+)
+
+// releasePlayback takes a playback out of the playback stack. Without a fade
+// time its scene's default fade-out applies.
+func (r *Resolver) releasePlayback(ctx context.Context, playbackID string, fadeOutTime *float64) ([]*generated.PlaybackStackEntry, error) {
+	if fadeOutTime != nil && *fadeOutTime < 0 {
+		return nil, fmt.Errorf("fadeOutTime must not be negative")
+	}
+
+	var entry *stack.Entry
+	for _, e := range r.StackService.Entries() {
+		if e.PlaybackID == playbackID {
+			entry = &e
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("playback not in the stack: %s", playbackID)
+	}
+
+	fadeTime, err := r.PlaybackService.ResolveSceneFadeOut(ctx, entry.SceneID, playback.SceneFadeLevels{Call: fadeOutTime})
+	if err != nil {
+		// The scene may have been deleted while live
+		fadeTime = playback.DefaultSceneFadeTime
+	}
+	r.StackService.Release(playbackID, time.Duration(fadeTime*float64(time.Second)))
+	r.journalSceneReleased(playbackID)
+	if entry.Kind == stack.KindSceneBoard {
+		r.sceneBoardStateChanged(strings.TrimPrefix(playbackID, stack.SceneBoardPlayback("")))
+	}
+
+	return r.playbackStack(ctx)
+}
+
+// playbackStack converts the playback stack, loading each entry's scene.
+func (r *Resolver) playbackStack(ctx context.Context) ([]*generated.PlaybackStackEntry, error) {
+	entries := r.StackService.Entries()
+	result := make([]*generated.PlaybackStackEntry, len(entries))
+	for i, e := range entries {
+		scene, err := r.SceneRepo.FindByID(ctx, e.SceneID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &generated.PlaybackStackEntry{
+			PlaybackID:   e.PlaybackID,
+			Kind:         generated.PlaybackKind(e.Kind),
+			SceneID:      e.SceneID,
+			Scene:        scene,
+			ChannelCount: e.ChannelCount,
+			ActivatedAt:  e.ActivatedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+		}
+	}
+	return result, nil
+}
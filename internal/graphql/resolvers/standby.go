@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/standby"
+)
+
+// loadStandbyConfig applies the saved standby configuration, if any.
+func (r *Resolver) loadStandbyConfig(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, standby.SettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var config standby.Config
+	if err := json.Unmarshal([]byte(setting.Value), &config); err != nil {
+		log.Warn("invalid saved standby configuration", "error", err)
+		return
+	}
+	if _, err := r.StandbyService.SetConfig(config); err != nil {
+		log.Warn("invalid saved standby configuration", "error", err)
+	}
+}
+
+// updateStandbyConfig validates, saves, and applies a standby configuration.
+func (r *Resolver) updateStandbyConfig(ctx context.Context, input generated.StandbyConfigInput) (*generated.StandbyStatus, error) {
+	config := standby.Config{
+		ScheduleEnabled: input.ScheduleEnabled,
+		OpeningHours:    make([]standby.Window, 0, len(input.OpeningHours)),
+		WakeOnArtNet:    input.WakeOnArtNet,
+	}
+	if output := input.Output.Value(); output != nil {
+		config.Output = standby.Output(*output)
+	}
+	for _, hours := range input.OpeningHours {
+		window := standby.Window{Open: hours.Open, Close: hours.Close}
+		for _, day := range hours.Days {
+			window.Days = append(window.Days, weekday(day))
+		}
+		config.OpeningHours = append(config.OpeningHours, window)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, standby.SettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save standby configuration: %w", err)
+	}
+
+	status, err := r.StandbyService.SetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return convertStandbyStatus(status), nil
+}
+
+// serverStandby enters standby, with an optional output overriding the
+// configured one, or wakes from it.
+func (r *Resolver) serverStandby(enabled bool, output *generated.StandbyOutput) (*generated.StandbyStatus, error) {
+	if !enabled {
+		return convertStandbyStatus(r.StandbyService.Wake()), nil
+	}
+	var mode standby.Output
+	if output != nil {
+		mode = standby.Output(*output)
+	}
+	status, err := r.StandbyService.EnterWithOutput(mode)
+	if err != nil {
+		return nil, err
+	}
+	return convertStandbyStatus(status), nil
+}
+
+// weekday converts a DayOfWeek, whose values are declared Sunday first like
+// time.Weekday.
+func weekday(day generated.DayOfWeek) time.Weekday {
+	for i, d := range generated.AllDayOfWeek {
+		if d == day {
+			return time.Weekday(i)
+		}
+	}
+	return time.Sunday
+}
@@ -0,0 +1,319 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"gorm.io/gorm"
+)
+
+// journalKindSubmaster is the journal kind holding submaster levels away from
+// their defaults, so faders survive a restart.
+const journalKindSubmaster = "submaster"
+
+// submasterFixtureIDs decodes a stored submaster's fixture selection.
+func submasterFixtureIDs(sub *models.Submaster) ([]string, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(sub.FixtureIDs), &ids); err != nil {
+		return nil, fmt.Errorf("invalid fixture list for submaster %s: %w", sub.ID, err)
+	}
+	return ids, nil
+}
+
+// defaultSubmasterLevel is a new submaster's level: a scene starts down so it
+// adds nothing, a fixture selection at full so it scales nothing.
+func defaultSubmasterLevel(sub *models.Submaster) float64 {
+	if sub.SceneID != nil {
+		return 0
+	}
+	return 1
+}
+
+// buildSubmaster resolves a stored submaster's output at a level.
+func (r *Resolver) buildSubmaster(ctx context.Context, sub *models.Submaster, level float64) (dmx.Submaster, error) {
+	built := dmx.Submaster{Level: level}
+
+	if sub.SceneID != nil {
+		var err error
+		built.Intensity, built.Other, err = r.sceneLayerValues(ctx, *sub.SceneID)
+		return built, err
+	}
+
+	ids, err := submasterFixtureIDs(sub)
+	if err != nil {
+		return built, err
+	}
+	intensity, err := r.submasterIntensityChannels(ctx, ids)
+	if err != nil {
+		return built, err
+	}
+	built.Scaled = make(map[int][]int)
+	for universe, channels := range intensity {
+		for channel := range channels {
+			built.Scaled[universe] = append(built.Scaled[universe], channel)
+		}
+		sort.Ints(built.Scaled[universe])
+	}
+	return built, nil
+}
+
+// sceneLayerValues splits a scene's values into its intensity channels,
+// which layers add highest-takes-precedence, and the rest, which they set:
+// universe -> 1-indexed channel -> value.
+func (r *Resolver) sceneLayerValues(ctx context.Context, sceneID string) (intensity, other map[int]map[int]byte, err error) {
+	sceneChannels, err := r.loadSceneChannels(ctx, sceneID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scene %s: %w", sceneID, err)
+	}
+	var fixtureIDs []string
+	if err := r.db.WithContext(ctx).Model(&models.FixtureValue{}).Where("scene_id = ?", sceneID).Pluck("fixture_id", &fixtureIDs).Error; err != nil {
+		return nil, nil, err
+	}
+	htp, err := r.submasterIntensityChannels(ctx, fixtureIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	intensity = make(map[int]map[int]byte)
+	other = make(map[int]map[int]byte)
+	for _, ch := range sceneChannels {
+		values := other
+		if htp[ch.Universe][ch.Channel] {
+			values = intensity
+		}
+		if values[ch.Universe] == nil {
+			values[ch.Universe] = make(map[int]byte)
+		}
+		values[ch.Universe][ch.Channel] = byte(ch.Value)
+	}
+	return intensity, other, nil
+}
+
+// submasterIntensityChannels returns the intensity channels of fixtures by
+// universe and channel.
+func (r *Resolver) submasterIntensityChannels(ctx context.Context, fixtureIDs []string) (map[int]map[int]bool, error) {
+	channels := make(map[int]map[int]bool)
+	if len(fixtureIDs) == 0 {
+		return channels, nil
+	}
+
+	var fixtures []models.FixtureInstance
+	if err := r.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures).Error; err != nil {
+		return nil, err
+	}
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		if channels[fixture.Universe] == nil {
+			channels[fixture.Universe] = make(map[int]bool)
+		}
+		for _, channel := range intensityChannels(fixture, nil) {
+			channels[fixture.Universe][channel] = true
+		}
+	}
+	return channels, nil
+}
+
+// refreshSubmasters pushes every submaster to the DMX output stage, keeping
+// the levels of those already there. Call it after submasters, their scenes,
+// or fixtures change.
+func (r *Resolver) refreshSubmasters(ctx context.Context) {
+	var stored []models.Submaster
+	if err := r.db.WithContext(ctx).Find(&stored).Error; err != nil {
+		log.Warn("failed to load submasters", "error", err)
+		return
+	}
+
+	levels := r.DMXService.SubmasterLevels()
+	submasters := make(map[string]dmx.Submaster, len(stored))
+	for i := range stored {
+		sub := &stored[i]
+		level, ok := levels[sub.ID]
+		if !ok {
+			level = defaultSubmasterLevel(sub)
+		}
+		built, err := r.buildSubmaster(ctx, sub, level)
+		if err != nil {
+			// Keep the fader, without output, so its level still works
+			log.Warn("submaster has no output", "submaster", sub.ID, "error", err)
+			built = dmx.Submaster{Level: level}
+		}
+		submasters[sub.ID] = built
+	}
+	if err := r.DMXService.SetSubmasters(submasters); err != nil {
+		log.Warn("failed to apply submasters", "error", err)
+	}
+}
+
+// restoreSubmasterLevels applies the submaster levels held in a journal,
+// returning how many it restored.
+func (r *Resolver) restoreSubmasterLevels(j *journal.Journal) int {
+	restored := 0
+	for id, raw := range j.Entries(journalKindSubmaster) {
+		var level float64
+		err := json.Unmarshal(raw, &level)
+		if err == nil {
+			err = r.DMXService.SetSubmasterLevel(id, level)
+		}
+		if err != nil {
+			log.Warn("cannot restore submaster", "submaster", id, "error", err)
+			_ = j.Delete(journalKindSubmaster, id)
+			continue
+		}
+		restored++
+	}
+	return restored
+}
+
+// saveSubmaster validates and stores a submaster, then applies it to the
+// output.
+func (r *Resolver) saveSubmaster(ctx context.Context, sub *models.Submaster, create bool) error {
+	if sub.Page < 1 || sub.Slot < 1 {
+		return fmt.Errorf("submaster page and slot must be at least 1")
+	}
+	ids, err := submasterFixtureIDs(sub)
+	if err != nil {
+		return err
+	}
+	if sub.SceneID != nil && len(ids) > 0 {
+		return fmt.Errorf("a submaster takes a scene or a fixture selection, not both")
+	}
+	if sub.SceneID != nil {
+		scene, err := r.SceneRepo.FindByID(ctx, *sub.SceneID)
+		if err != nil {
+			return err
+		}
+		if scene == nil || scene.ProjectID != sub.ProjectID {
+			return fmt.Errorf("scene not found in project: %s", *sub.SceneID)
+		}
+	}
+	if len(ids) > 0 {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&models.FixtureInstance{}).Where("id IN ? AND project_id = ?", ids, sub.ProjectID).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(ids) {
+			return fmt.Errorf("fixtures must be distinct fixtures of the project")
+		}
+	}
+
+	var taken int64
+	if err := r.db.WithContext(ctx).Model(&models.Submaster{}).
+		Where("project_id = ? AND page = ? AND slot = ? AND id <> ?", sub.ProjectID, sub.Page, sub.Slot, sub.ID).
+		Count(&taken).Error; err != nil {
+		return err
+	}
+	if taken > 0 {
+		return fmt.Errorf("page %d slot %d already has a submaster", sub.Page, sub.Slot)
+	}
+
+	if create {
+		err = r.db.WithContext(ctx).Create(sub).Error
+	} else {
+		err = r.db.WithContext(ctx).Save(sub).Error
+	}
+	if err != nil {
+		return err
+	}
+	r.refreshSubmasters(ctx)
+	return nil
+}
+
+// setSubmasterLevel moves a submaster's fader, then journals and publishes
+// the change.
+func (r *Resolver) setSubmasterLevel(ctx context.Context, id string, level float64) (*models.Submaster, error) {
+	sub, err := r.findSubmaster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DMXService.SetSubmasterLevel(id, level); err != nil {
+		return nil, err
+	}
+
+	if r.StateJournal != nil {
+		if level == defaultSubmasterLevel(sub) {
+			err = r.StateJournal.Delete(journalKindSubmaster, id)
+		} else {
+			err = r.StateJournal.Put(journalKindSubmaster, id, level)
+		}
+		if err != nil {
+			log.Warn("failed to journal submaster", "submaster", id, "error", err)
+		}
+	}
+
+	r.PubSub.Publish(pubsub.TopicSubmasterLevel, sub.ProjectID, sub)
+	return sub, nil
+}
+
+// deleteSubmasters deletes the submasters matching a query and their
+// journaled levels.
+func (r *Resolver) deleteSubmasters(ctx context.Context, query string, args ...interface{}) error {
+	var stored []models.Submaster
+	if err := r.db.WithContext(ctx).Where(query, args...).Find(&stored).Error; err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where(query, args...).Delete(&models.Submaster{}).Error; err != nil {
+		return err
+	}
+	if r.StateJournal != nil {
+		for _, sub := range stored {
+			if err := r.StateJournal.Delete(journalKindSubmaster, sub.ID); err != nil {
+				log.Warn("failed to journal submaster", "submaster", sub.ID, "error", err)
+			}
+		}
+	}
+	r.refreshSubmasters(ctx)
+	return nil
+}
+
+// findSubmaster loads a submaster by ID.
+func (r *Resolver) findSubmaster(ctx context.Context, id string) (*models.Submaster, error) {
+	var sub models.Submaster
+	if err := r.db.WithContext(ctx).First(&sub, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("submaster not found: %s", id)
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// findSubmasters loads a project's submasters in page and slot order.
+func (r *Resolver) findSubmasters(ctx context.Context, projectID string) ([]*models.Submaster, error) {
+	var stored []models.Submaster
+	if err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("page ASC, slot ASC").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	pointers := make([]*models.Submaster, len(stored))
+	for i := range stored {
+		pointers[i] = &stored[i]
+	}
+	return pointers, nil
+}
+
+// submasterPages groups a project's submasters by page.
+func (r *Resolver) submasterPages(ctx context.Context, projectID string) ([]*generated.SubmasterPage, error) {
+	submasters, err := r.findSubmasters(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var pages []*generated.SubmasterPage
+	for _, sub := range submasters {
+		if len(pages) == 0 || pages[len(pages)-1].Page != sub.Page {
+			pages = append(pages, &generated.SubmasterPage{Page: sub.Page})
+		}
+		page := pages[len(pages)-1]
+		page.Submasters = append(page.Submasters, sub)
+	}
+	if pages == nil {
+		pages = []*generated.SubmasterPage{}
+	}
+	return pages, nil
+}
@@ -0,0 +1,175 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+	"github.com/bbernstein/lacylights-go/internal/services/timecode"
+)
+
+// wireTimecode fires cues as the timecode reaches them and publishes status
+// changes.
+func (r *Resolver) wireTimecode() {
+	r.TimecodeService.SetTriggerHandler(func(cueListID string, trigger timecode.Trigger, elapsed time.Duration) {
+		if err := r.PlaybackService.ChaseToCueNumber(context.Background(), cueListID, trigger.CueNumber, elapsed); err != nil {
+			log.Warn("timecode failed to fire cue", "cue", trigger.CueNumber, "cueList", cueListID, "error", err)
+		}
+	})
+	r.TimecodeService.SetUpdateCallback(func(status *timecode.Status) {
+		r.PubSub.Publish(pubsub.TopicTimecode, "", convertTimecodeStatus(status))
+	})
+}
+
+// loadTimecodeConfig applies the saved timecode configuration, if any.
+func (r *Resolver) loadTimecodeConfig(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, timecode.SettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var config timecode.Config
+	if err := json.Unmarshal([]byte(setting.Value), &config); err != nil {
+		log.Warn("invalid saved timecode configuration", "error", err)
+		return
+	}
+	if _, err := r.TimecodeService.SetConfig(config); err != nil {
+		log.Warn("invalid saved timecode configuration", "error", err)
+		return
+	}
+	r.refreshTimecodeTriggers(ctx)
+}
+
+// updateTimecodeConfig validates, saves, and applies a timecode configuration.
+func (r *Resolver) updateTimecodeConfig(ctx context.Context, in generated.TimecodeConfigInput) (*generated.TimecodeStatus, error) {
+	config := timecode.Config{
+		Enabled:   in.Enabled,
+		Source:    timecode.Source(in.Source),
+		FrameRate: timecode.FrameRate(in.FrameRate),
+	}
+	if device := in.MidiDevice.Value(); device != nil {
+		config.MIDIDevice = *device
+	}
+	if chase := in.ChaseToTime.Value(); chase != nil {
+		config.ChaseToTime = *chase
+	}
+	if fallback := in.FallbackToInternal.Value(); fallback != nil {
+		config.FallbackToInternal = *fallback
+	}
+	if cueListID := in.CueListID.Value(); cueListID != nil {
+		cueList, err := r.CueListRepo.FindByID(ctx, *cueListID)
+		if err != nil {
+			return nil, err
+		}
+		if cueList == nil {
+			return nil, fmt.Errorf("cue list not found: %s", *cueListID)
+		}
+		config.CueListID = *cueListID
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Load the triggers first so a chase on enabling sees them
+	if err := r.setTimecodeTriggers(ctx, config); err != nil {
+		return nil, err
+	}
+	status, err := r.TimecodeService.SetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, timecode.SettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save timecode configuration: %w", err)
+	}
+	return convertTimecodeStatus(status), nil
+}
+
+// refreshTimecodeTriggers reloads the cue timecodes of the cue list following
+// timecode, after its cues change. Inside a transaction it waits for the
+// commit, so a rollback leaves the triggers as they were.
+func (r *Resolver) refreshTimecodeTriggers(ctx context.Context) {
+	if r.pendingEvents != nil {
+		*r.pendingEvents = append(*r.pendingEvents, pendingEvent{run: func(r *Resolver) { r.refreshTimecodeTriggers(ctx) }})
+		return
+	}
+	if err := r.setTimecodeTriggers(ctx, r.TimecodeService.Config()); err != nil {
+		log.Warn("failed to load cue timecodes", "error", err)
+	}
+}
+
+func (r *Resolver) setTimecodeTriggers(ctx context.Context, config timecode.Config) error {
+	if config.CueListID == "" {
+		r.TimecodeService.SetTriggers(nil)
+		return nil
+	}
+
+	var cues []models.Cue
+	if err := r.db.WithContext(ctx).Where("cue_list_id = ? AND timecode IS NOT NULL", config.CueListID).Find(&cues).Error; err != nil {
+		return err
+	}
+	triggers := make([]timecode.Trigger, 0, len(cues))
+	for _, cue := range cues {
+		tc, err := timecode.Parse(*cue.Timecode)
+		if err != nil {
+			log.Warn("invalid cue timecode", "cue", cue.ID, "error", err)
+			continue
+		}
+		triggers = append(triggers, timecode.Trigger{CueNumber: cue.CueNumber, Position: tc.Position(config.FrameRate)})
+	}
+	r.TimecodeService.SetTriggers(triggers)
+	return nil
+}
+
+// cueTimecodeValue validates a cue's timecode input and normalizes it for
+// storage.
+func cueTimecodeValue(value *string) (*string, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	tc, err := timecode.Parse(*value)
+	if err != nil {
+		return nil, err
+	}
+	normalized := tc.String()
+	return &normalized, nil
+}
+
+// convertTimecodeStatus converts a timecode.Status to generated.TimecodeStatus.
+func convertTimecodeStatus(status *timecode.Status) *generated.TimecodeStatus {
+	result := &generated.TimecodeStatus{
+		Enabled:            status.Config.Enabled,
+		Source:             generated.TimecodeSource(status.Config.Source),
+		FrameRate:          generated.TimecodeFrameRate(status.Config.FrameRate),
+		ChaseToTime:        status.Config.ChaseToTime,
+		FallbackToInternal: status.Config.FallbackToInternal,
+		IsRunning:          status.Running,
+		IsFreewheeling:     status.Freewheeling,
+		DeviceError:        status.DeviceError,
+	}
+	if status.Config.MIDIDevice != "" {
+		device := status.Config.MIDIDevice
+		result.MidiDevice = &device
+	}
+	if status.Config.CueListID != "" {
+		cueListID := status.Config.CueListID
+		result.CueListID = &cueListID
+	}
+	if status.Position != nil {
+		position := status.Position.String()
+		result.Position = &position
+	}
+	if status.LastMTCAt != nil {
+		lastMTCAt := status.LastMTCAt.Format("2006-01-02T15:04:05.000Z")
+		result.LastMtcAt = &lastMTCAt
+	}
+	return result
+}
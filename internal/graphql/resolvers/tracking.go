@@ -0,0 +1,169 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+)
+
+// updateCueValues merges channel values into a cue's scene. A cue-only edit
+// in a tracking cue list also writes each changed channel's previous value
+// into the next cue, unless that cue sets the channel itself or blocks.
+func (r *Resolver) updateCueValues(ctx context.Context, cueID string, fixtureValues []*generated.FixtureValueInput, cueOnly bool) (*models.Cue, error) {
+	cue, err := r.CueRepo.FindByID(ctx, cueID)
+	if err != nil {
+		return nil, err
+	}
+	if cue == nil {
+		return nil, fmt.Errorf("cue not found: %s", cueID)
+	}
+
+	// Validate every fixture's channels before changing anything
+	if err := r.applyFixtureColors(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.applyFixturePalettes(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	if err := r.checkFixtureValues(ctx, fixtureValues); err != nil {
+		return nil, err
+	}
+	for _, fv := range fixtureValues {
+		if _, err := serializeSparseChannels(fv.Channels); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", fv.FixtureID, err)
+		}
+	}
+
+	cueList, err := r.CueListRepo.FindByID(ctx, cue.CueListID)
+	if err != nil {
+		return nil, err
+	}
+	if cueList == nil {
+		return nil, fmt.Errorf("cue list not found: %s", cue.CueListID)
+	}
+	var next *models.Cue
+	previous := make(map[string]map[int]int) // fixture ID -> offset -> value
+	if cueOnly && cueList.Tracking {
+		cues, err := r.CueListRepo.GetCues(ctx, cue.CueListID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range cues {
+			if cues[i].ID == cue.ID && i+1 < len(cues) && !cues[i+1].Block {
+				next = &cues[i+1]
+				break
+			}
+		}
+
+		values, err := r.PlaybackService.TrackedValues(ctx, cue.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if previous[v.FixtureID] == nil {
+				previous[v.FixtureID] = make(map[int]int)
+			}
+			previous[v.FixtureID][v.Offset] = v.Value
+		}
+	}
+
+	targets := []repositories.UndoTarget{undoScene(cue.SceneID)}
+	if next != nil && next.SceneID != cue.SceneID {
+		targets = append(targets, undoScene(next.SceneID))
+	}
+	undo, err := r.beginUndo(ctx, cueList.ProjectID, "Update cue "+cue.Name+" values", targets...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fv := range fixtureValues {
+		channels := make([]models.ChannelValue, len(fv.Channels))
+		for i, ch := range fv.Channels {
+			channels[i] = models.ChannelValue{Offset: ch.Offset, Value: ch.Value}
+		}
+
+		if next != nil {
+			// Channels the next cue sets itself already end the change there
+			existing, err := r.sceneChannels(ctx, next.SceneID, fv.FixtureID)
+			if err != nil {
+				return nil, err
+			}
+			var restore []models.ChannelValue
+			for _, ch := range channels {
+				if _, ok := existing[ch.Offset]; !ok {
+					restore = append(restore, models.ChannelValue{Offset: ch.Offset, Value: previous[fv.FixtureID][ch.Offset]})
+				}
+			}
+			if err := r.mergeSceneChannels(ctx, next.SceneID, fv.FixtureID, restore); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := r.mergeSceneChannels(ctx, cue.SceneID, fv.FixtureID, channels); err != nil {
+			return nil, err
+		}
+	}
+	r.refreshSubmasters(ctx)
+	r.commitUndo(ctx, undo)
+	for _, target := range targets {
+		r.publishEntityUpdated(ctx, generated.ProjectEntityTypeScene, target.ID)
+	}
+
+	return cue, nil
+}
+
+// sceneChannels returns a fixture's channel values in a scene by offset.
+func (r *Resolver) sceneChannels(ctx context.Context, sceneID, fixtureID string) (map[int]int, error) {
+	fixtureValue, err := r.SceneRepo.GetFixtureValue(ctx, sceneID, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[int]int)
+	if fixtureValue == nil {
+		return values, nil
+	}
+
+	var channels []models.ChannelValue
+	if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
+		return nil, fmt.Errorf("failed to deserialize channels: %w", err)
+	}
+	for _, ch := range channels {
+		values[ch.Offset] = ch.Value
+	}
+	return values, nil
+}
+
+// mergeSceneChannels sets channel values of a fixture in a scene, keeping
+// its other channels.
+func (r *Resolver) mergeSceneChannels(ctx context.Context, sceneID, fixtureID string, channels []models.ChannelValue) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	existing, err := r.SceneRepo.GetFixtureValue(ctx, sceneID, fixtureID)
+	if err != nil {
+		return err
+	}
+	stored := "[]"
+	if existing != nil {
+		stored = existing.Channels
+	}
+	channelsJSON, err := mergeSparseChannels(stored, channels)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		existing.Channels = channelsJSON
+		return r.SceneRepo.UpdateFixtureValue(ctx, existing)
+	}
+	return r.SceneRepo.CreateFixtureValue(ctx, &models.FixtureValue{
+		SceneID:   sceneID,
+		FixtureID: fixtureID,
+		Channels:  channelsJSON,
+	})
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// convertTransmitStats converts DMX frame counters to the GraphQL type.
+func convertTransmitStats(stats dmx.TransmitStats) *generated.DmxTransmitStats {
+	universes := make([]*generated.DmxUniverseTransmitStats, len(stats.Universes))
+	for i, u := range stats.Universes {
+		universes[i] = &generated.DmxUniverseTransmitStats{
+			Universe:        u.Universe,
+			ChangedFrames:   u.ChangedFrames,
+			KeepAliveFrames: u.KeepAliveFrames,
+			SkippedFrames:   u.SkippedFrames,
+		}
+	}
+	return &generated.DmxTransmitStats{
+		Since:           stats.Since.UTC().Format(time.RFC3339),
+		Cycles:          stats.Cycles,
+		ChangedFrames:   stats.ChangedFrames,
+		KeepAliveFrames: stats.KeepAliveFrames,
+		SkippedFrames:   stats.SkippedFrames,
+		Universes:       universes,
+	}
+}
@@ -0,0 +1,196 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/pubsub"
+)
+
+// undoRecord is an undoable edit in progress, holding its targets as they
+// were before it.
+type undoRecord struct {
+	projectID   string
+	description string
+	targets     []repositories.UndoTarget
+	before      *repositories.UndoSnapshot
+}
+
+func undoScene(id string) repositories.UndoTarget {
+	return repositories.UndoTarget{Kind: repositories.UndoTargetScene, ID: id}
+}
+
+func undoFixture(id string) repositories.UndoTarget {
+	return repositories.UndoTarget{Kind: repositories.UndoTargetFixture, ID: id}
+}
+
+func undoCue(id string) repositories.UndoTarget {
+	return repositories.UndoTarget{Kind: repositories.UndoTargetCue, ID: id}
+}
+
+// beginUndo captures the targets of an edit before it changes them.
+func (r *Resolver) beginUndo(ctx context.Context, projectID, description string, targets ...repositories.UndoTarget) (*undoRecord, error) {
+	before, err := r.UndoRepo.Snapshot(ctx, targets)
+	if err != nil {
+		return nil, err
+	}
+	return &undoRecord{projectID: projectID, description: description, targets: targets, before: before}, nil
+}
+
+// add includes records the edit created, which had nothing before it.
+func (u *undoRecord) add(targets ...repositories.UndoTarget) {
+	u.targets = append(u.targets, targets...)
+}
+
+// commitUndo records a finished edit in its project's history. The edit
+// itself has succeeded, so failing to record it is only logged.
+func (r *Resolver) commitUndo(ctx context.Context, u *undoRecord) {
+	after, err := r.UndoRepo.Snapshot(ctx, u.targets)
+	if err == nil {
+		_, err = r.UndoRepo.Push(ctx, u.projectID, u.description, u.targets, u.before, after)
+	}
+	if err != nil {
+		log.Warn("failed to record for undo", "operation", u.description, "error", err)
+		return
+	}
+	r.publishUndoStack(ctx, u.projectID)
+}
+
+// commitUndoCreate records an edit that only created records.
+func (r *Resolver) commitUndoCreate(ctx context.Context, projectID, description string, targets ...repositories.UndoTarget) {
+	r.commitUndo(ctx, &undoRecord{projectID: projectID, description: description, targets: targets, before: &repositories.UndoSnapshot{}})
+}
+
+// cueProjectID returns the project of a cue list, for recording cue edits.
+func (r *Resolver) cueProjectID(ctx context.Context, cueListID string) (string, error) {
+	cueList, err := r.CueListRepo.FindByID(ctx, cueListID)
+	if err != nil {
+		return "", err
+	}
+	if cueList == nil {
+		return "", fmt.Errorf("cue list not found: %s", cueListID)
+	}
+	return cueList.ProjectID, nil
+}
+
+// beginBulkCueUndo captures cues before a bulk update, one record per
+// project as the cues may come from several.
+func (r *Resolver) beginBulkCueUndo(ctx context.Context, cueIDs []string) ([]*undoRecord, error) {
+	var projectIDs []string
+	targets := make(map[string][]repositories.UndoTarget)
+	seen := make(map[string]bool, len(cueIDs))
+	for _, cueID := range cueIDs {
+		if seen[cueID] {
+			continue
+		}
+		seen[cueID] = true
+		cue, err := r.CueRepo.FindByID(ctx, cueID)
+		if err != nil {
+			return nil, err
+		}
+		if cue == nil {
+			return nil, fmt.Errorf("cue not found: %s", cueID)
+		}
+		projectID, err := r.cueProjectID(ctx, cue.CueListID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := targets[projectID]; !ok {
+			projectIDs = append(projectIDs, projectID)
+		}
+		targets[projectID] = append(targets[projectID], undoCue(cueID))
+	}
+
+	undos := make([]*undoRecord, len(projectIDs))
+	for i, projectID := range projectIDs {
+		description := fmt.Sprintf("Update %d cues", len(targets[projectID]))
+		undo, err := r.beginUndo(ctx, projectID, description, targets[projectID]...)
+		if err != nil {
+			return nil, err
+		}
+		undos[i] = undo
+	}
+	return undos, nil
+}
+
+// undoStackStatus summarizes a project's undo history.
+func (r *Resolver) undoStackStatus(ctx context.Context, projectID string) (*generated.UndoStackStatus, error) {
+	undoable, redoable, err := r.UndoRepo.Status(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	status := &generated.UndoStackStatus{
+		ProjectID: projectID,
+		CanUndo:   len(undoable) > 0,
+		CanRedo:   len(redoable) > 0,
+		UndoCount: len(undoable),
+		RedoCount: len(redoable),
+	}
+	if len(undoable) > 0 {
+		status.UndoDescription = &undoable[0].Description
+	}
+	if len(redoable) > 0 {
+		status.RedoDescription = &redoable[0].Description
+	}
+	return status, nil
+}
+
+// publishUndoStack notifies undoStackChanged subscribers of a project.
+func (r *Resolver) publishUndoStack(ctx context.Context, projectID string) {
+	status, err := r.undoStackStatus(ctx, projectID)
+	if err != nil {
+		log.Warn("failed to publish undo stack", "error", err)
+		return
+	}
+	r.publishAfterCommit(pubsub.TopicUndoStack, projectID, status)
+}
+
+// applyUndo undoes or redoes a project's next edit and updates live output
+// for the records it restored.
+func (r *Resolver) applyUndo(ctx context.Context, projectID string, redo bool) (*generated.UndoStackStatus, error) {
+	var (
+		op  *models.UndoOperation
+		err error
+	)
+	if redo {
+		op, err = r.UndoRepo.Redo(ctx, projectID)
+	} else {
+		op, err = r.UndoRepo.Undo(ctx, projectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []repositories.UndoTarget
+	if err := json.Unmarshal([]byte(op.Targets), &targets); err != nil {
+		return nil, err
+	}
+	var fixtures, cues bool
+	for _, t := range targets {
+		switch t.Kind {
+		case repositories.UndoTargetScene:
+			if err := r.reapplyActiveSceneIfNeeded(ctx, t.ID); err != nil {
+				log.Warn("failed to re-apply active scene after undo", "error", err)
+			}
+		case repositories.UndoTargetFixture:
+			fixtures = true
+		case repositories.UndoTargetCue:
+			cues = true
+		}
+	}
+	if fixtures {
+		r.refreshOutputLimits(ctx)
+	}
+	if cues {
+		r.refreshTimecodeTriggers(ctx)
+	}
+	r.refreshSubmasters(ctx)
+	r.publishUndoneEntities(ctx, projectID, targets)
+
+	r.publishUndoStack(ctx, projectID)
+	return r.undoStackStatus(ctx, projectID)
+}
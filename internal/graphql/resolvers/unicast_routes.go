@@ -0,0 +1,102 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// loadUnicastRoutes applies the saved Art-Net unicast routing table, if any.
+func (r *Resolver) loadUnicastRoutes(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.UnicastRoutesSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+
+	var routes []dmx.UnicastRoute
+	if err := json.Unmarshal([]byte(setting.Value), &routes); err != nil {
+		log.Warn("invalid saved unicast routes", "error", err)
+		return
+	}
+	if err := r.DMXService.SetUnicastRoutes(routes); err != nil {
+		log.Warn("invalid saved unicast routes", "error", err)
+	}
+}
+
+// updateUnicastRoutes applies and saves a routing table.
+func (r *Resolver) updateUnicastRoutes(ctx context.Context, routes []dmx.UnicastRoute) ([]*generated.ArtNetUnicastRoute, error) {
+	if routes == nil {
+		routes = []dmx.UnicastRoute{}
+	}
+	// Validate before touching live output or the saved table
+	if _, err := dmx.ResolveUnicastRoutes(routes, r.DMXService.GetPort()); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(routes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.SettingRepo.Upsert(ctx, dmx.UnicastRoutesSettingKey, string(value)); err != nil {
+		return nil, fmt.Errorf("failed to save unicast routes: %w", err)
+	}
+	if err := r.DMXService.SetUnicastRoutes(routes); err != nil {
+		return nil, err
+	}
+	return convertUnicastRoutes(r.DMXService.UnicastRoutes()), nil
+}
+
+// replaceUnicastRoute returns the current routing table with one universe's
+// route replaced, or removed when destinations is nil.
+func (r *Resolver) replaceUnicastRoute(universe int, destinations []string) []dmx.UnicastRoute {
+	routes := make([]dmx.UnicastRoute, 0)
+	for _, route := range r.DMXService.UnicastRoutes() {
+		if route.Universe != universe {
+			routes = append(routes, route)
+		}
+	}
+	if destinations != nil {
+		routes = append(routes, dmx.UnicastRoute{Universe: universe, Destinations: destinations})
+	}
+	return routes
+}
+
+// loadArtSync applies the saved ArtSync setting, if any, over the
+// configured default.
+func (r *Resolver) loadArtSync(ctx context.Context) {
+	setting, err := r.SettingRepo.FindByKey(ctx, dmx.ArtSyncSettingKey)
+	if err != nil || setting == nil || setting.Value == "" {
+		return
+	}
+	enabled, err := strconv.ParseBool(setting.Value)
+	if err != nil {
+		log.Warn("invalid saved Art-Net sync setting", "value", setting.Value)
+		return
+	}
+	r.DMXService.SetArtSync(enabled)
+}
+
+// updateArtSync applies and saves the ArtSync setting.
+func (r *Resolver) updateArtSync(ctx context.Context, enabled bool) (bool, error) {
+	if _, err := r.SettingRepo.Upsert(ctx, dmx.ArtSyncSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return false, fmt.Errorf("failed to save Art-Net sync setting: %w", err)
+	}
+	r.DMXService.SetArtSync(enabled)
+	return enabled, nil
+}
+
+// convertUnicastRoutes converts dmx.UnicastRoute values to generated.ArtNetUnicastRoute.
+func convertUnicastRoutes(routes []dmx.UnicastRoute) []*generated.ArtNetUnicastRoute {
+	result := make([]*generated.ArtNetUnicastRoute, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, &generated.ArtNetUnicastRoute{
+			Universe:     route.Universe,
+			Destinations: route.Destinations,
+		})
+	}
+	return result
+}
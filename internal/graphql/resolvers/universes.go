@@ -0,0 +1,187 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/graphql/generated"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// refreshUniverses creates the universes fixtures are patched to that their
+// projects have none for, and pushes the universes of every project to the
+// DMX output stage. Like the softpatch they describe the venue, so all
+// projects apply: a universe is sent unless one disables it, to every
+// destination any gives it.
+func (r *Resolver) refreshUniverses(ctx context.Context) {
+	if _, err := r.UniverseRepo.CreateMissing(ctx); err != nil {
+		log.Warn("failed to create patched universes", "error", err)
+	}
+	universes, err := r.UniverseRepo.FindAll(ctx)
+	if err != nil {
+		log.Warn("failed to load universes", "error", err)
+		return
+	}
+
+	outputs := make(map[int]*dmx.UniverseOutput)
+	var order []int
+	for i := range universes {
+		universe := &universes[i]
+		if err := r.validateUniverse(universe); err != nil {
+			log.Warn("universe is invalid", "universe", universe.ID, "error", err)
+			continue
+		}
+		output := outputs[universe.Number]
+		if output == nil {
+			output = &dmx.UniverseOutput{Universe: universe.Number, Enabled: true}
+			outputs[universe.Number] = output
+			order = append(order, universe.Number)
+		}
+		output.Enabled = output.Enabled && universe.Enabled
+		if universe.Destination != nil && !slices.Contains(output.Destinations, *universe.Destination) {
+			output.Destinations = append(output.Destinations, *universe.Destination)
+		}
+	}
+
+	merged := make([]dmx.UniverseOutput, 0, len(order))
+	for _, number := range order {
+		merged = append(merged, *outputs[number])
+	}
+	if err := r.DMXService.SetUniverseOutputs(merged); err != nil {
+		log.Warn("failed to apply universes", "error", err)
+	}
+}
+
+// validateUniverse checks a universe's number and destination.
+func (r *Resolver) validateUniverse(universe *models.Universe) error {
+	if universe.Number < 1 || universe.Number > dmx.MaxUniverses {
+		return fmt.Errorf("universe must be between 1 and %d, got %d", dmx.MaxUniverses, universe.Number)
+	}
+	if universe.Destination == nil {
+		return nil
+	}
+	route := dmx.UnicastRoute{Universe: universe.Number, Destinations: []string{*universe.Destination}}
+	_, err := dmx.ResolveUnicastRoutes([]dmx.UnicastRoute{route}, r.DMXService.GetPort())
+	return err
+}
+
+// createUniverse configures a universe of a project and applies it to the
+// output.
+func (r *Resolver) createUniverse(ctx context.Context, input generated.CreateUniverseInput) (*models.Universe, error) {
+	project, err := r.ProjectRepo.FindByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+	existing, err := r.UniverseRepo.FindByNumber(ctx, input.ProjectID, input.Number)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("universe %d already exists in the project", input.Number)
+	}
+
+	universe := &models.Universe{
+		ProjectID: input.ProjectID,
+		Number:    input.Number,
+		Protocol:  string(generated.UniverseProtocolArtnet),
+		Enabled:   true,
+	}
+	applyUniverseInput(universe, generated.UpdateUniverseInput{
+		Label:       input.Label,
+		Protocol:    input.Protocol,
+		Destination: input.Destination,
+		Enabled:     input.Enabled,
+	})
+	if err := r.validateUniverse(universe); err != nil {
+		return nil, err
+	}
+	if err := r.UniverseRepo.Create(ctx, universe); err != nil {
+		return nil, err
+	}
+	r.refreshUniverses(ctx)
+	return universe, nil
+}
+
+// updateUniverse changes a universe and applies it to the output.
+func (r *Resolver) updateUniverse(ctx context.Context, id string, input generated.UpdateUniverseInput) (*models.Universe, error) {
+	universe, err := r.findUniverse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyUniverseInput(universe, input)
+	if err := r.validateUniverse(universe); err != nil {
+		return nil, err
+	}
+	if err := r.UniverseRepo.Update(ctx, universe); err != nil {
+		return nil, err
+	}
+	r.refreshUniverses(ctx)
+	return universe, nil
+}
+
+// deleteUniverse deletes a universe no fixtures are patched to.
+func (r *Resolver) deleteUniverse(ctx context.Context, id string) error {
+	universe, err := r.findUniverse(ctx, id)
+	if err != nil {
+		return err
+	}
+	count, err := r.UniverseRepo.CountFixtures(ctx, universe.ProjectID, universe.Number)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("universe %d has %d patched fixtures", universe.Number, count)
+	}
+	if err := r.UniverseRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.refreshUniverses(ctx)
+	return nil
+}
+
+// findUniverse loads a universe by ID.
+func (r *Resolver) findUniverse(ctx context.Context, id string) (*models.Universe, error) {
+	universe, err := r.UniverseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if universe == nil {
+		return nil, fmt.Errorf("universe not found: %s", id)
+	}
+	return universe, nil
+}
+
+// applyUniverseInput sets the fields of a universe that an input sets.
+// Blank labels and destinations are cleared.
+func applyUniverseInput(universe *models.Universe, in generated.UpdateUniverseInput) {
+	if in.Label.IsSet() {
+		universe.Label = trimmedOrNil(in.Label.Value())
+	}
+	if v := in.Protocol.Value(); v != nil {
+		universe.Protocol = string(*v)
+	}
+	if in.Destination.IsSet() {
+		universe.Destination = trimmedOrNil(in.Destination.Value())
+	}
+	if v := in.Enabled.Value(); v != nil {
+		universe.Enabled = *v
+	}
+}
+
+// trimmedOrNil trims a string, returning nil when it is nil or blank.
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,471 @@
+// Package rest offers a minimal REST facade over the GraphQL API for
+// integrations that cannot easily speak GraphQL, such as smart-home hubs and
+// Stream Deck plugins. Each endpoint runs GraphQL operations through the
+// GraphQL handler, so authentication, project roles, and the audit log apply
+// exactly as they do to GraphQL requests.
+//
+// Control surfaces such as Bitfocus Companion press buttons, set the master
+// and go cue lists with POSTs, and light their buttons from GET /state or the
+// /events stream, which sends the state as Server-Sent Events whenever it
+// changes.
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+	"github.com/go-chi/chi/v5"
+)
+
+// BasePath is where the REST API is mounted.
+const BasePath = "/api/v1"
+
+// maxUniverse bounds the universe numbers accepted in paths.
+const maxUniverse = 32768
+
+// Handler serves the REST endpoints.
+type Handler struct {
+	graphql http.Handler
+	router  chi.Router
+}
+
+// NewHandler creates a REST handler that runs its operations on graphql,
+// the server's GraphQL HTTP handler.
+func NewHandler(graphql http.Handler) *Handler {
+	h := &Handler{graphql: graphql, router: chi.NewRouter()}
+	h.router.Post("/scenes/{id}/activate", h.activateScene)
+	h.router.Post("/cuelists/{id}/go", h.goCueList)
+	h.router.Get("/universes/{n}/output", h.universeOutput)
+	h.router.Post("/buttons/{id}/press", h.pressButton)
+	h.router.Post("/buttons/{id}/release", h.releaseButton)
+	h.router.Post("/master", h.setMaster)
+	h.router.Get("/state", h.state)
+	h.router.Get("/events", h.events)
+	h.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no such endpoint")
+	})
+	h.router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	})
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+// activateScene fades to a scene. An optional fadeTime query parameter
+// overrides the fade-in seconds.
+func (h *Handler) activateScene(w http.ResponseWriter, r *http.Request) {
+	fadeTime, err := optionalSeconds(r, "fadeTime")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_, status, err := h.execute(r, `mutation($sceneId: ID!, $fadeInTime: Float) {
+		activateScene(sceneId: $sceneId, fadeInTime: $fadeInTime)
+	}`, map[string]interface{}{"sceneId": chi.URLParam(r, "id"), "fadeInTime": fadeTime})
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// goCueList goes to the next cue of a cue list, starting it if it is
+// stopped. An optional fadeTime query parameter overrides the fade-in
+// seconds.
+func (h *Handler) goCueList(w http.ResponseWriter, r *http.Request) {
+	fadeTime, err := optionalSeconds(r, "fadeTime")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_, status, err := h.execute(r, `mutation($cueListId: ID!, $fadeInTime: Float) {
+		nextCue(cueListId: $cueListId, fadeInTime: $fadeInTime)
+	}`, map[string]interface{}{"cueListId": chi.URLParam(r, "id"), "fadeInTime": fadeTime})
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// universeOutput returns the 512 channel values being output on a universe.
+func (h *Handler) universeOutput(w http.ResponseWriter, r *http.Request) {
+	universe, err := strconv.Atoi(chi.URLParam(r, "n"))
+	if err != nil || universe < 1 || universe > maxUniverse {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("universe must be between 1 and %d", maxUniverse))
+		return
+	}
+	data, status, err := h.execute(r, `query($universe: Int!) { dmxOutput(universe: $universe) }`,
+		map[string]interface{}{"universe": universe})
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+
+	var result struct {
+		DmxOutput []int `json:"dmxOutput"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"universe": universe, "channels": result.DmxOutput})
+}
+
+// pressButton presses a scene board button: a flash-mode button flashes its
+// scene, any other holds it up until released, as its board's hold settings
+// say.
+func (h *Handler) pressButton(w http.ResponseWriter, r *http.Request) {
+	buttonID := chi.URLParam(r, "id")
+	flashMode, status, err := h.buttonFlashMode(r, buttonID)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	query := `mutation($buttonId: ID!) { pressSceneBoardButton(buttonId: $buttonId) { buttonId } }`
+	if flashMode {
+		query = `mutation($buttonId: ID!) { flashSceneStart(buttonId: $buttonId) { buttonId } }`
+	}
+	if _, status, err := h.execute(r, query, map[string]interface{}{"buttonId": buttonID}); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// releaseButton releases a pressed scene board button.
+func (h *Handler) releaseButton(w http.ResponseWriter, r *http.Request) {
+	buttonID := chi.URLParam(r, "id")
+	flashMode, status, err := h.buttonFlashMode(r, buttonID)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	query := `mutation($buttonId: ID!) { releaseSceneBoardButton(buttonId: $buttonId) { buttonId } }`
+	if flashMode {
+		query = `mutation($buttonId: ID!) { flashSceneEnd(buttonId: $buttonId) { buttonId } }`
+	}
+	if _, status, err := h.execute(r, query, map[string]interface{}{"buttonId": buttonID}); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// buttonFlashMode reports whether a scene board button is in flash mode.
+func (h *Handler) buttonFlashMode(r *http.Request, buttonID string) (bool, int, error) {
+	data, status, err := h.execute(r, `query($id: ID!) { sceneBoardButton(id: $id) { flashMode } }`,
+		map[string]interface{}{"id": buttonID})
+	if err != nil {
+		return false, status, err
+	}
+	var result struct {
+		SceneBoardButton *struct {
+			FlashMode bool `json:"flashMode"`
+		} `json:"sceneBoardButton"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return false, http.StatusInternalServerError, err
+	}
+	if result.SceneBoardButton == nil {
+		return false, http.StatusNotFound, fmt.Errorf("scene board button not found: %s", buttonID)
+	}
+	return result.SceneBoardButton.FlashMode, http.StatusOK, nil
+}
+
+// setMaster sets the grand master, or a universe's master when a universe
+// query parameter is given, to the level query parameter (0-1).
+func (h *Handler) setMaster(w http.ResponseWriter, r *http.Request) {
+	level, err := strconv.ParseFloat(r.URL.Query().Get("level"), 64)
+	if err != nil || level < 0 || level > 1 {
+		writeError(w, http.StatusBadRequest, "level must be between 0 and 1")
+		return
+	}
+	var universe *int
+	if value := r.URL.Query().Get("universe"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxUniverse {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("universe must be between 1 and %d", maxUniverse))
+			return
+		}
+		universe = &n
+	}
+	if _, status, err := h.execute(r, `mutation($level: Float!, $universe: Int) {
+		setMasterLevel(level: $level, universe: $universe) { grandMaster }
+	}`, map[string]interface{}{"level": level, "universe": universe}); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// controlSurfaceFields selects the state control surfaces light buttons from.
+const controlSurfaceFields = `activeSceneId liveSceneIds cueLists { cueListId cueNumber cueName isFading isPaused } litButtonIds grandMaster isBlackout`
+
+// state returns what is live.
+func (h *Handler) state(w http.ResponseWriter, r *http.Request) {
+	data, status, err := h.execute(r, `query { controlSurfaceState { `+controlSurfaceFields+` } }`, nil)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	var result struct {
+		ControlSurfaceState json.RawMessage `json:"controlSurfaceState"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result.ControlSurfaceState)
+}
+
+// events streams what is live as Server-Sent Events: a state event with the
+// current state on connecting and again whenever it changes. The stream
+// relays the controlSurfaceStateChanged subscription, which the GraphQL
+// handler serves over its SSE transport.
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"query": `subscription { controlSurfaceStateChanged { ` + controlSurfaceFields + ` } }`,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Ending the subscription when the client goes or the relay fails
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL.Path, bytes.NewReader(body))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.RemoteAddr = r.RemoteAddr
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		h.graphql.ServeHTTP(&streamWriter{header: make(http.Header), w: writer}, req)
+		_ = writer.Close()
+	}()
+
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for {
+		event, data, ok := readEvent(scanner)
+		if !ok || event == "complete" {
+			break
+		}
+		if event == "" && data == "" {
+			// Keep-alive comment
+			if started {
+				_, _ = io.WriteString(w, ": ping\n\n")
+				flusher.Flush()
+			}
+			continue
+		}
+
+		var resp graphQLResponse
+		if err := json.Unmarshal([]byte(data), &resp); err != nil {
+			log.Warn("REST event stream got an unreadable GraphQL response", "error", err)
+			break
+		}
+		if len(resp.Errors) > 0 {
+			message := resp.Errors[0].Message
+			if !started {
+				writeError(w, errorStatus(message), message)
+				return
+			}
+			writeEvent(w, "error", map[string]string{"error": message})
+			flusher.Flush()
+			return
+		}
+		var result struct {
+			ControlSurfaceStateChanged json.RawMessage `json:"controlSurfaceStateChanged"`
+		}
+		if err := json.Unmarshal(resp.Data, &result); err != nil || result.ControlSurfaceStateChanged == nil {
+			continue
+		}
+		if !started {
+			start()
+		}
+		writeEvent(w, "state", result.ControlSurfaceStateChanged)
+		flusher.Flush()
+	}
+	if !started {
+		writeError(w, http.StatusBadGateway, "the GraphQL handler did not stream the state")
+	}
+}
+
+// readEvent reads the next Server-Sent Event, returning its type and data.
+// A comment-only block returns empty strings.
+func readEvent(scanner *bufio.Scanner) (event, data string, ok bool) {
+	read := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if read {
+				return event, data, true
+			}
+			continue
+		}
+		read = true
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+	return event, data, read
+}
+
+// writeEvent writes a Server-Sent Event with JSON data.
+func writeEvent(w io.Writer, event string, data interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Warn("failed to encode REST event", "error", err)
+		return
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		log.Warn("failed to write REST event", "error", err)
+	}
+}
+
+// graphQLResponse is the body of a GraphQL response.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// execute runs a GraphQL operation as part of a REST request, returning its
+// data, or the HTTP status and error to answer with.
+func (h *Handler) execute(r *http.Request, query string, variables map[string]interface{}) (json.RawMessage, int, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, r.URL.Path, bytes.NewReader(body))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = r.RemoteAddr
+
+	out := &responseBuffer{header: make(http.Header), status: http.StatusOK}
+	h.graphql.ServeHTTP(out, req)
+
+	var resp graphQLResponse
+	if err := json.Unmarshal(out.body.Bytes(), &resp); err != nil {
+		log.Warn("REST request got an unreadable GraphQL response", "path", r.URL.Path, "status", out.status, "error", err)
+		return nil, http.StatusBadGateway, errors.New("invalid response from the GraphQL handler")
+	}
+	if len(resp.Errors) > 0 {
+		message := resp.Errors[0].Message
+		return nil, errorStatus(message), errors.New(message)
+	}
+	if out.status != http.StatusOK {
+		return nil, out.status, errors.New(http.StatusText(out.status))
+	}
+	return resp.Data, http.StatusOK, nil
+}
+
+// errorStatus picks the HTTP status for a GraphQL error message.
+func errorStatus(message string) int {
+	switch {
+	case strings.HasPrefix(message, auth.ErrUnauthenticated.Error()):
+		return http.StatusUnauthorized
+	case strings.HasPrefix(message, auth.ErrForbidden.Error()):
+		return http.StatusForbidden
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// optionalSeconds reads a non-negative seconds query parameter, returning
+// nil when it is absent.
+func optionalSeconds(r *http.Request, name string) (*float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return nil, fmt.Errorf("%s must be a non-negative number of seconds", name)
+	}
+	return &seconds, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Warn("failed to write REST response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// responseBuffer collects the GraphQL handler's response.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header { return b.header }
+
+func (b *responseBuffer) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func (b *responseBuffer) WriteHeader(status int) { b.status = status }
+
+// streamWriter pipes a streamed GraphQL response to the event relay.
+type streamWriter struct {
+	header http.Header
+	w      io.Writer
+}
+
+func (s *streamWriter) Header() http.Header { return s.header }
+
+func (s *streamWriter) Write(p []byte) (int, error) { return s.w.Write(p) }
+
+func (s *streamWriter) WriteHeader(int) {}
+
+// Flush implements http.Flusher; the pipe delivers every write at once.
+func (s *streamWriter) Flush() {}
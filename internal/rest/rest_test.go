@@ -0,0 +1,244 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// stubGraphQL answers GraphQL requests with a canned response, recording
+// the last request it received.
+type stubGraphQL struct {
+	response  string
+	query     string
+	variables map[string]interface{}
+	header    http.Header
+}
+
+func (s *stubGraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.query = body.Query
+	s.variables = body.Variables
+	s.header = r.Header
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(s.response))
+}
+
+func serve(t *testing.T, h http.Handler, method, target string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func TestActivateScene(t *testing.T) {
+	stub := &stubGraphQL{response: `{"data":{"activateScene":true}}`}
+	h := NewHandler(stub)
+
+	status, body := serve(t, h, http.MethodPost, "/scenes/scene-1/activate?fadeTime=2.5")
+	if status != http.StatusOK || body["success"] != true {
+		t.Errorf("Response = %d %v, want 200 success", status, body)
+	}
+	if !strings.Contains(stub.query, "activateScene") {
+		t.Errorf("Unexpected query: %s", stub.query)
+	}
+	if stub.variables["sceneId"] != "scene-1" || stub.variables["fadeInTime"] != 2.5 {
+		t.Errorf("Unexpected variables: %v", stub.variables)
+	}
+	if stub.header.Get("Authorization") != "Bearer token" {
+		t.Error("Expected the request's credentials to reach GraphQL")
+	}
+
+	// Without fadeTime the scene's own fade applies
+	serve(t, h, http.MethodPost, "/scenes/scene-1/activate")
+	if v, ok := stub.variables["fadeInTime"]; !ok || v != nil {
+		t.Errorf("fadeInTime = %v, want null", v)
+	}
+
+	status, _ = serve(t, h, http.MethodPost, "/scenes/scene-1/activate?fadeTime=-1")
+	if status != http.StatusBadRequest {
+		t.Errorf("Negative fadeTime status = %d, want 400", status)
+	}
+	status, _ = serve(t, h, http.MethodGet, "/scenes/scene-1/activate")
+	if status != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", status)
+	}
+}
+
+func TestGoCueList(t *testing.T) {
+	stub := &stubGraphQL{response: `{"data":{"nextCue":true}}`}
+	status, body := serve(t, NewHandler(stub), http.MethodPost, "/cuelists/list-1/go")
+	if status != http.StatusOK || body["success"] != true {
+		t.Errorf("Response = %d %v, want 200 success", status, body)
+	}
+	if !strings.Contains(stub.query, "nextCue") || stub.variables["cueListId"] != "list-1" {
+		t.Errorf("Unexpected request: %s %v", stub.query, stub.variables)
+	}
+}
+
+func TestUniverseOutput(t *testing.T) {
+	stub := &stubGraphQL{response: `{"data":{"dmxOutput":[255,0,128]}}`}
+	h := NewHandler(stub)
+
+	status, body := serve(t, h, http.MethodGet, "/universes/2/output")
+	if status != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", status)
+	}
+	if body["universe"] != float64(2) || stub.variables["universe"] != float64(2) {
+		t.Errorf("Unexpected universe: %v, requested %v", body["universe"], stub.variables["universe"])
+	}
+	channels, _ := body["channels"].([]interface{})
+	if len(channels) != 3 || channels[0] != float64(255) || channels[2] != float64(128) {
+		t.Errorf("channels = %v, want [255 0 128]", body["channels"])
+	}
+
+	for _, target := range []string{"/universes/0/output", "/universes/x/output"} {
+		if status, _ := serve(t, h, http.MethodGet, target); status != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", target, status)
+		}
+	}
+}
+
+func TestButtons(t *testing.T) {
+	for _, tt := range []struct {
+		flashMode      bool
+		press, release string
+	}{
+		{false, "pressSceneBoardButton", "releaseSceneBoardButton"},
+		{true, "flashSceneStart", "flashSceneEnd"},
+	} {
+		flashMode := "false"
+		if tt.flashMode {
+			flashMode = "true"
+		}
+		// One response answers both the button lookup and the action
+		stub := &stubGraphQL{response: `{"data":{"sceneBoardButton":{"flashMode":` + flashMode + `},"` + tt.press + `":{"buttonId":"btn-1"}}}`}
+		h := NewHandler(stub)
+
+		status, body := serve(t, h, http.MethodPost, "/buttons/btn-1/press")
+		if status != http.StatusOK || body["success"] != true {
+			t.Errorf("Press response = %d %v, want 200 success", status, body)
+		}
+		if !strings.Contains(stub.query, tt.press) || stub.variables["buttonId"] != "btn-1" {
+			t.Errorf("Unexpected press request: %s %v", stub.query, stub.variables)
+		}
+		serve(t, h, http.MethodPost, "/buttons/btn-1/release")
+		if !strings.Contains(stub.query, tt.release) {
+			t.Errorf("Unexpected release request: %s", stub.query)
+		}
+	}
+
+	stub := &stubGraphQL{response: `{"data":{"sceneBoardButton":null}}`}
+	if status, _ := serve(t, NewHandler(stub), http.MethodPost, "/buttons/btn-9/press"); status != http.StatusNotFound {
+		t.Errorf("Missing button status = %d, want 404", status)
+	}
+}
+
+func TestSetMaster(t *testing.T) {
+	stub := &stubGraphQL{response: `{"data":{"setMasterLevel":{"grandMaster":0.5}}}`}
+	h := NewHandler(stub)
+
+	status, body := serve(t, h, http.MethodPost, "/master?level=0.5")
+	if status != http.StatusOK || body["success"] != true {
+		t.Errorf("Response = %d %v, want 200 success", status, body)
+	}
+	if !strings.Contains(stub.query, "setMasterLevel") || stub.variables["level"] != 0.5 || stub.variables["universe"] != nil {
+		t.Errorf("Unexpected request: %s %v", stub.query, stub.variables)
+	}
+	serve(t, h, http.MethodPost, "/master?level=1&universe=3")
+	if stub.variables["universe"] != float64(3) {
+		t.Errorf("universe = %v, want 3", stub.variables["universe"])
+	}
+
+	for _, target := range []string{"/master", "/master?level=1.5", "/master?level=1&universe=0"} {
+		if status, _ := serve(t, h, http.MethodPost, target); status != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", target, status)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	stub := &stubGraphQL{response: `{"data":{"controlSurfaceState":{"activeSceneId":"scene-1","litButtonIds":["btn-1"],"grandMaster":1}}}`}
+	status, body := serve(t, NewHandler(stub), http.MethodGet, "/state")
+	if status != http.StatusOK || body["activeSceneId"] != "scene-1" || body["grandMaster"] != float64(1) {
+		t.Errorf("Response = %d %v, want the state", status, body)
+	}
+	if !strings.Contains(stub.query, "controlSurfaceState") {
+		t.Errorf("Unexpected query: %s", stub.query)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	stub := &stubGraphQL{response: ":\n\n" +
+		"event: next\ndata: {\"data\":{\"controlSurfaceStateChanged\":{\"activeSceneId\":null}}}\n\n" +
+		": ping\n\n" +
+		"event: next\ndata: {\"data\":{\"controlSurfaceStateChanged\":{\"activeSceneId\":\"scene-1\"}}}\n\n" +
+		"event: complete\n\n"}
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	NewHandler(stub).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Response = %d %s, want a 200 event stream", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	want := "event: state\ndata: {\"activeSceneId\":null}\n\n" +
+		": ping\n\n" +
+		"event: state\ndata: {\"activeSceneId\":\"scene-1\"}\n\n"
+	if rec.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rec.Body.String(), want)
+	}
+	if !strings.Contains(stub.query, "controlSurfaceStateChanged") || stub.header.Get("Accept") != "text/event-stream" {
+		t.Errorf("Unexpected request: %s %v", stub.query, stub.header)
+	}
+
+	// An error before any state answers as a plain request does
+	stub = &stubGraphQL{response: ":\n\nevent: next\ndata: {\"errors\":[{\"message\":\"authentication required\"}],\"data\":null}\n\n"}
+	status, body := serve(t, NewHandler(stub), http.MethodGet, "/events")
+	if status != http.StatusUnauthorized || body["error"] != "authentication required" {
+		t.Errorf("Response = %d %v, want 401", status, body)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		message string
+		status  int
+	}{
+		{"scene not found: scene-9", http.StatusNotFound},
+		{"authentication required", http.StatusUnauthorized},
+		{"permission denied: activateScene requires the EDITOR role on project p", http.StatusForbidden},
+		{"no more cues in the list", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		stub := &stubGraphQL{response: `{"errors":[{"message":"` + tt.message + `"}],"data":null}`}
+		status, body := serve(t, NewHandler(stub), http.MethodPost, "/cuelists/list-1/go")
+		if status != tt.status || body["error"] != tt.message {
+			t.Errorf("%q: response = %d %v, want %d", tt.message, status, body, tt.status)
+		}
+	}
+
+	stub := &stubGraphQL{response: "not json"}
+	if status, _ := serve(t, NewHandler(stub), http.MethodPost, "/cuelists/list-1/go"); status != http.StatusBadGateway {
+		t.Errorf("Unreadable response status = %d, want 502", status)
+	}
+	if status, _ := serve(t, NewHandler(stub), http.MethodGet, "/fixtures"); status != http.StatusNotFound {
+		t.Errorf("Unknown endpoint status = %d, want 404", status)
+	}
+}
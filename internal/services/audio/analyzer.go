@@ -0,0 +1,176 @@
+package audio
+
+import "math"
+
+const (
+	// windowsPerSecond is how many analysis windows a second of audio is
+	// split into; levels and beats are measured per window.
+	windowsPerSecond = 50
+	// historyWindows is how many past windows a band's energy is compared
+	// against, a second's worth.
+	historyWindows = windowsPerSecond
+	// minHistory is how many windows must be heard before beats are
+	// detected, so the first sounds after silence are not all beats.
+	minHistory = windowsPerSecond / 5
+	// noiseFloor is the level below which a band never beats.
+	noiseFloor = 0.01
+)
+
+// Beat is a beat detected in a band.
+type Beat struct {
+	Band  string
+	Level float64 // Band level (0-1) at the beat
+}
+
+// biquad is a second-order IIR filter section.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// newBiquad makes a Butterworth low-pass or high-pass section at cutoff Hz.
+func newBiquad(sampleRate int, cutoff float64, highPass bool) biquad {
+	w0 := 2 * math.Pi * cutoff / float64(sampleRate)
+	cos := math.Cos(w0)
+	alpha := math.Sin(w0) / math.Sqrt2 // sin(w0) / 2Q with Q = 1/sqrt(2)
+	a0 := 1 + alpha
+	f := biquad{a1: -2 * cos / a0, a2: (1 - alpha) / a0}
+	if highPass {
+		f.b0 = (1 + cos) / 2 / a0
+		f.b1 = -(1 + cos) / a0
+	} else {
+		f.b0 = (1 - cos) / 2 / a0
+		f.b1 = (1 - cos) / a0
+	}
+	f.b2 = f.b0
+	return f
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// bandState follows one band: its filters, the energy of the window being
+// measured, and the energies of past windows.
+type bandState struct {
+	band     Band
+	highPass biquad
+	lowPass  biquad
+
+	sum      float64 // Energy of the current window so far
+	history  []float64
+	next     int // Where the next window's energy goes in history
+	level    float64
+	lastBeat int64 // Sample a beat was last detected at; -1 for never
+}
+
+// Analyzer measures the level of mono audio in frequency bands and detects
+// beats: windows whose band energy jumps above the band's recent average.
+// Beats are timed in samples, so analysis does not depend on how audio
+// arrives.
+type Analyzer struct {
+	sampleRate int
+	window     int // Samples per window
+	bands      []*bandState
+
+	sum     float64 // Energy of the current window over all frequencies
+	filled  int     // Samples in the current window
+	samples int64   // Samples analyzed
+	level   float64
+}
+
+// NewAnalyzer creates an analyzer for audio at sampleRate. The bands must be
+// valid for the rate.
+func NewAnalyzer(sampleRate int, bands []Band) *Analyzer {
+	a := &Analyzer{
+		sampleRate: sampleRate,
+		window:     max(1, sampleRate/windowsPerSecond),
+	}
+	for _, band := range bands {
+		a.bands = append(a.bands, &bandState{
+			band:     band,
+			highPass: newBiquad(sampleRate, band.LowHz, true),
+			lowPass:  newBiquad(sampleRate, band.HighHz, false),
+			history:  make([]float64, 0, historyWindows),
+			lastBeat: -1,
+		})
+	}
+	return a
+}
+
+// Process analyzes mono samples (-1 to 1) and returns the beats detected in
+// the windows they complete.
+func (a *Analyzer) Process(samples []float64) []Beat {
+	var beats []Beat
+	for _, x := range samples {
+		a.sum += x * x
+		for _, b := range a.bands {
+			y := b.lowPass.process(b.highPass.process(x))
+			b.sum += y * y
+		}
+		a.filled++
+		a.samples++
+		if a.filled == a.window {
+			beats = a.completeWindow(beats)
+		}
+	}
+	return beats
+}
+
+// completeWindow measures the finished window and appends its beats.
+func (a *Analyzer) completeWindow(beats []Beat) []Beat {
+	n := float64(a.filled)
+	a.level = rmsLevel(a.sum / n)
+	a.sum, a.filled = 0, 0
+
+	for _, b := range a.bands {
+		energy := b.sum / n
+		b.sum = 0
+		b.level = rmsLevel(energy)
+
+		if len(b.history) >= minHistory && b.level >= noiseFloor {
+			average := 0.0
+			for _, e := range b.history {
+				average += e
+			}
+			average /= float64(len(b.history))
+			minGap := int64(b.band.MinInterval * float64(a.sampleRate))
+			if energy > b.band.Threshold*average && (b.lastBeat < 0 || a.samples-b.lastBeat >= minGap) {
+				b.lastBeat = a.samples
+				beats = append(beats, Beat{Band: b.band.Name, Level: b.level})
+			}
+		}
+
+		if len(b.history) < historyWindows {
+			b.history = append(b.history, energy)
+		} else {
+			b.history[b.next] = energy
+			b.next = (b.next + 1) % historyWindows
+		}
+	}
+	return beats
+}
+
+// Level returns the level (0-1) of the last window over all frequencies.
+func (a *Analyzer) Level() float64 {
+	return a.level
+}
+
+// BandLevels returns the level (0-1) of each band in the last window, in
+// band order.
+func (a *Analyzer) BandLevels() []float64 {
+	levels := make([]float64, len(a.bands))
+	for i, b := range a.bands {
+		levels[i] = b.level
+	}
+	return levels
+}
+
+// rmsLevel converts a mean energy to a level where a full scale sine wave
+// is 1.
+func rmsLevel(energy float64) float64 {
+	return math.Min(1, math.Sqrt(2*energy))
+}
@@ -0,0 +1,237 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// kicks synthesizes seconds of a 60 Hz kick drum hitting every interval
+// seconds over a quiet 3 kHz hiss.
+func kicks(sampleRate int, seconds, interval float64) []float64 {
+	samples := make([]float64, int(seconds*float64(sampleRate)))
+	for i := range samples {
+		t := float64(i) / float64(sampleRate)
+		sinceKick := math.Mod(t, interval)
+		kick := 0.8 * math.Exp(-sinceKick*20) * math.Sin(2*math.Pi*60*t)
+		hiss := 0.02 * math.Sin(2*math.Pi*3000*t)
+		samples[i] = kick + hiss
+	}
+	return samples
+}
+
+// encodePCM encodes mono samples as 16-bit PCM.
+func encodePCM(samples []float64, order binary.ByteOrder) []byte {
+	data := make([]byte, 2*len(samples))
+	for i, x := range samples {
+		order.PutUint16(data[2*i:], uint16(int16(x*32767)))
+	}
+	return data
+}
+
+func TestAnalyzerDetectsBeats(t *testing.T) {
+	const rate = 8000
+	analyzer := NewAnalyzer(rate, []Band{
+		{Name: "BASS", LowHz: 40, HighHz: 150, Threshold: 1.5, MinInterval: 0.25},
+		{Name: "HIGH", LowHz: 2000, HighHz: 3900, Threshold: 1.5, MinInterval: 0.25},
+	})
+
+	counts := make(map[string]int)
+	// Four seconds of kicks every half second, fed in uneven chunks
+	samples := kicks(rate, 4, 0.5)
+	for start := 0; start < len(samples); start += 333 {
+		for _, beat := range analyzer.Process(samples[start:min(start+333, len(samples))]) {
+			counts[beat.Band]++
+		}
+	}
+	// The first kick lands before there is any history to compare against
+	if counts["BASS"] < 6 || counts["BASS"] > 8 {
+		t.Errorf("BASS beats = %d, want one per kick after the first", counts["BASS"])
+	}
+	if counts["HIGH"] != 0 {
+		t.Errorf("HIGH beats = %d, want none from a steady hiss", counts["HIGH"])
+	}
+	if levels := analyzer.BandLevels(); levels[1] < 0.01 || levels[1] > 0.05 {
+		t.Errorf("HIGH level = %v, want the hiss's level", levels[1])
+	}
+}
+
+func TestAnalyzerMinInterval(t *testing.T) {
+	const rate = 8000
+	analyzer := NewAnalyzer(rate, []Band{{Name: "BASS", LowHz: 40, HighHz: 150, Threshold: 1.5, MinInterval: 0.9}})
+	beats := analyzer.Process(kicks(rate, 4, 0.5))
+	if len(beats) < 2 || len(beats) > 4 {
+		t.Errorf("beats = %d, want every other kick", len(beats))
+	}
+}
+
+func TestDecodePCM(t *testing.T) {
+	// One stereo frame, left at half and right at silence
+	samples := decodePCM([]byte{0x00, 0x40, 0x00, 0x00, 0xff}, binary.LittleEndian, 2)
+	if len(samples) != 1 || samples[0] != 0.25 {
+		t.Errorf("samples = %v, want [0.25] with the partial frame dropped", samples)
+	}
+	if samples := decodePCM([]byte{0xc0, 0x00}, binary.BigEndian, 1); len(samples) != 1 || samples[0] != -0.5 {
+		t.Errorf("big-endian samples = %v, want [-0.5]", samples)
+	}
+}
+
+func TestParseRTP(t *testing.T) {
+	packet := []byte{
+		0x91, payloadL16Mono, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, // Version 2, one CSRC, an extension
+		1, 2, 3, 4, // CSRC
+		0xbe, 0xde, 0, 1, 9, 9, 9, 9, // Extension of one word
+		0x12, 0x34,
+	}
+	payloadType, payload, err := parseRTP(packet)
+	if err != nil {
+		t.Fatalf("parseRTP() error: %v", err)
+	}
+	if payloadType != payloadL16Mono || len(payload) != 2 || payload[0] != 0x12 {
+		t.Errorf("parseRTP() = %d, %v, want payload type 11 and the two payload bytes", payloadType, payload)
+	}
+	if _, _, err := parseRTP([]byte{0x00, 0x01, 0x02}); err == nil {
+		t.Error("Expected error for a packet that is not RTP")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := DefaultConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("DefaultConfig() is invalid: %v", err)
+	}
+
+	for name, change := range map[string]func(c *Config){
+		"unknown source":   func(c *Config) { c.Source = "MIC" },
+		"no device":        func(c *Config) { c.Device = "" },
+		"option as device": func(c *Config) { c.Device = "--help" },
+		"bad port":         func(c *Config) { c.Source, c.Port = SourceRTP, 0 },
+		"bad sample rate":  func(c *Config) { c.SampleRate = 100 },
+		"three channels":   func(c *Config) { c.Channels = 3 },
+		"duplicate band":   func(c *Config) { c.Bands = append(c.Bands, c.Bands[0]) },
+		"band above nyquist": func(c *Config) {
+			c.SampleRate = 8000
+		},
+		"low threshold": func(c *Config) { c.Bands[0].Threshold = 1 },
+	} {
+		c := DefaultConfig()
+		change(&c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+// beatRecorder collects beats from the service.
+type beatRecorder struct {
+	mu    sync.Mutex
+	beats []Beat
+}
+
+func (r *beatRecorder) handle(beat Beat) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beats = append(r.beats, beat)
+}
+
+func (r *beatRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.beats)
+}
+
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServiceReceivesRTP(t *testing.T) {
+	service := NewService()
+	defer service.Cleanup()
+	var addr net.Addr
+	service.listenUDP = func(int) (net.PacketConn, error) {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		if err == nil {
+			addr = conn.LocalAddr()
+		}
+		return conn, err
+	}
+	recorder := &beatRecorder{}
+	service.SetBeatHandler(recorder.handle)
+
+	config := DefaultConfig()
+	config.Enabled, config.Source, config.SampleRate = true, SourceRTP, 16000
+	config.Bands = DefaultBands()[:1]
+	status, err := service.SetConfig(config)
+	if err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if !status.Listening {
+		t.Fatalf("Expected the service to listen, got error %v", status.Error)
+	}
+
+	conn, err := net.Dial("udp4", addr.String())
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+	samples := kicks(16000, 2, 0.5)
+	for start := 0; start < len(samples); start += 320 {
+		header := []byte{0x80, 96, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+		if _, err := conn.Write(append(header, encodePCM(samples[start:start+320], binary.BigEndian)...)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	waitFor(t, "bass beats", func() bool { return recorder.count() >= 2 })
+	status = service.Status()
+	if status.Bands[0].Beats < 2 || status.Bands[0].LastBeatAt == nil || status.LastSource == nil {
+		t.Errorf("Status = %+v, want BASS beats from the sender", status)
+	}
+}
+
+func TestServiceCapturesDevice(t *testing.T) {
+	service := NewService()
+	defer service.Cleanup()
+	reader, writer := io.Pipe()
+	var captured Config
+	service.capture = func(config Config) (io.ReadCloser, error) {
+		captured = config
+		return reader, nil
+	}
+	recorder := &beatRecorder{}
+	service.SetBeatHandler(recorder.handle)
+
+	config := DefaultConfig()
+	config.Enabled, config.Device, config.SampleRate = true, "hw:1,0", 8000
+	config.Bands = DefaultBands()[:1]
+	if _, err := service.SetConfig(config); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if captured.Device != "hw:1,0" {
+		t.Errorf("Captured device = %q, want hw:1,0", captured.Device)
+	}
+
+	if _, err := writer.Write(encodePCM(kicks(8000, 2, 0.5), binary.LittleEndian)); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	waitFor(t, "bass beats", func() bool { return recorder.count() >= 2 })
+
+	// A capture that ends is reported
+	_ = writer.CloseWithError(io.ErrClosedPipe)
+	waitFor(t, "the capture to end", func() bool { return service.Status().Error != nil })
+	if service.Status().Listening {
+		t.Error("Expected the service to stop listening when the capture ends")
+	}
+}
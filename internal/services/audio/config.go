@@ -0,0 +1,161 @@
+// Package audio analyzes live audio for level and beats, so scene board
+// buttons can bump scenes and step effects in time with the music.
+//
+// Audio comes from a sound device, captured with arecord, or from a stream
+// sent over the network: RTP carrying L16 audio (RFC 3551), as sent by
+// ffmpeg or a streaming mixer, or bare UDP datagrams of 16-bit PCM. The
+// audio is split into frequency bands, and a beat is a moment when a band's
+// energy jumps above its average over the last second.
+package audio
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SettingKey is the setting that stores the audio input configuration as
+// JSON.
+const SettingKey = "audio_input_config"
+
+// Source selects where audio comes from.
+type Source string
+
+const (
+	// SourceDevice captures from a sound device with arecord.
+	SourceDevice Source = "DEVICE"
+	// SourceRTP receives an RTP stream of L16 (16-bit big-endian PCM) audio.
+	SourceRTP Source = "RTP"
+	// SourceUDP receives bare UDP datagrams of 16-bit little-endian PCM.
+	SourceUDP Source = "UDP"
+)
+
+const (
+	// DefaultDevice is the sound device captured from when none is named.
+	DefaultDevice = "default"
+	// DefaultPort is the port streams are received on, the usual RTP port.
+	DefaultPort = 5004
+	// DefaultSampleRate is CD audio, and the rate of RTP payload types 10
+	// and 11.
+	DefaultSampleRate = 44100
+	// MinSampleRate and MaxSampleRate bound the accepted sample rates.
+	MinSampleRate = 8000
+	MaxSampleRate = 96000
+	// MaxBands bounds the bands analyzed at once.
+	MaxBands = 8
+	// DefaultThreshold is how many times its recent average a band's energy
+	// must reach to count as a beat.
+	DefaultThreshold = 1.5
+	// DefaultMinInterval is the fewest seconds between beats of a band.
+	DefaultMinInterval = 0.25
+)
+
+// Band is a frequency range beats are detected in.
+type Band struct {
+	Name   string  `json:"name"`
+	LowHz  float64 `json:"lowHz"`
+	HighHz float64 `json:"highHz"`
+	// Threshold is how many times its average over the last second the
+	// band's energy must reach to count as a beat
+	Threshold float64 `json:"threshold"`
+	// MinInterval is the fewest seconds between beats
+	MinInterval float64 `json:"minInterval"`
+}
+
+// DefaultBands returns the bands analyzed until others are configured:
+// kick drums and bass, the midrange, and cymbals and hi-hats.
+func DefaultBands() []Band {
+	return []Band{
+		{Name: "BASS", LowHz: 40, HighHz: 150, Threshold: DefaultThreshold, MinInterval: DefaultMinInterval},
+		{Name: "MID", LowHz: 150, HighHz: 2000, Threshold: DefaultThreshold, MinInterval: DefaultMinInterval},
+		{Name: "HIGH", LowHz: 2000, HighHz: 8000, Threshold: DefaultThreshold, MinInterval: DefaultMinInterval},
+	}
+}
+
+// Config is the audio input configuration.
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Source  Source `json:"source"`
+	// Device is the ALSA device captured from, e.g. hw:1,0
+	Device string `json:"device,omitempty"`
+	// Port is the UDP port streams are received on
+	Port int `json:"port,omitempty"`
+	// SampleRate is the rate captured at, or the rate streams are sent at
+	SampleRate int `json:"sampleRate"`
+	// Channels is 1 for mono or 2 for stereo, which is mixed down. RTP
+	// payload types 10 and 11 give their own.
+	Channels int    `json:"channels"`
+	Bands    []Band `json:"bands"`
+}
+
+// DefaultConfig returns the configuration used until one is set.
+func DefaultConfig() Config {
+	return Config{
+		Source:     SourceDevice,
+		Device:     DefaultDevice,
+		Port:       DefaultPort,
+		SampleRate: DefaultSampleRate,
+		Channels:   1,
+		Bands:      DefaultBands(),
+	}
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	switch c.Source {
+	case SourceDevice:
+		if c.Device == "" {
+			return fmt.Errorf("the %s source requires a device", SourceDevice)
+		}
+		if strings.HasPrefix(c.Device, "-") {
+			return fmt.Errorf("invalid sound device %q", c.Device)
+		}
+	case SourceRTP, SourceUDP:
+		if c.Port < 1 || c.Port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+		}
+	default:
+		return fmt.Errorf("unknown audio source %q", c.Source)
+	}
+	if c.SampleRate < MinSampleRate || c.SampleRate > MaxSampleRate {
+		return fmt.Errorf("sampleRate must be between %d and %d, got %d", MinSampleRate, MaxSampleRate, c.SampleRate)
+	}
+	if c.Channels != 1 && c.Channels != 2 {
+		return fmt.Errorf("channels must be 1 or 2, got %d", c.Channels)
+	}
+	if len(c.Bands) > MaxBands {
+		return fmt.Errorf("at most %d bands can be analyzed, got %d", MaxBands, len(c.Bands))
+	}
+
+	nyquist := float64(c.SampleRate) / 2
+	names := make(map[string]bool, len(c.Bands))
+	for i, band := range c.Bands {
+		if band.Name == "" {
+			return fmt.Errorf("band %d: name is required", i+1)
+		}
+		if names[band.Name] {
+			return fmt.Errorf("band %d: %s is already a band", i+1, band.Name)
+		}
+		names[band.Name] = true
+		if !(band.LowHz > 0 && band.LowHz < band.HighHz && band.HighHz < nyquist) {
+			return fmt.Errorf("band %s: frequencies must rise from above 0 to below %v Hz, got %v-%v Hz", band.Name, nyquist, band.LowHz, band.HighHz)
+		}
+		if !(band.Threshold > 1) || math.IsInf(band.Threshold, 0) {
+			return fmt.Errorf("band %s: threshold must be above 1, got %v", band.Name, band.Threshold)
+		}
+		if !(band.MinInterval >= 0 && band.MinInterval <= 10) {
+			return fmt.Errorf("band %s: minInterval must be between 0 and 10 seconds, got %v", band.Name, band.MinInterval)
+		}
+	}
+	return nil
+}
+
+// HasBand reports whether a band is configured.
+func (c *Config) HasBand(name string) bool {
+	for _, band := range c.Bands {
+		if band.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// RTP payload types of L16 audio at 44.1 kHz (RFC 3551).
+const (
+	payloadL16Stereo = 10
+	payloadL16Mono   = 11
+)
+
+// decodePCM converts 16-bit PCM frames to mono samples between -1 and 1,
+// averaging the channels of each frame. A trailing partial frame is
+// dropped.
+func decodePCM(data []byte, order binary.ByteOrder, channels int) []float64 {
+	frameSize := 2 * channels
+	samples := make([]float64, 0, len(data)/frameSize)
+	for i := 0; i+frameSize <= len(data); i += frameSize {
+		sum := 0.0
+		for c := 0; c < channels; c++ {
+			sum += float64(int16(order.Uint16(data[i+2*c:]))) / 32768
+		}
+		samples = append(samples, sum/float64(channels))
+	}
+	return samples
+}
+
+// parseRTP returns the payload type and payload of an RTP packet.
+func parseRTP(packet []byte) (int, []byte, error) {
+	if len(packet) < 12 || packet[0]>>6 != 2 {
+		return 0, nil, errors.New("not an RTP packet")
+	}
+	payloadType := int(packet[1] & 0x7f)
+	offset := 12 + 4*int(packet[0]&0x0f) // CSRC identifiers
+	if packet[0]&0x10 != 0 {
+		// Header extension: a 4 byte header counting the 32-bit words after it
+		if len(packet) < offset+4 {
+			return 0, nil, errors.New("truncated RTP header extension")
+		}
+		offset += 4 + 4*int(binary.BigEndian.Uint16(packet[offset+2:]))
+	}
+	end := len(packet)
+	if packet[0]&0x20 != 0 {
+		end -= int(packet[end-1]) // Padding, counted by its last byte
+	}
+	if offset > end {
+		return 0, nil, errors.New("truncated RTP packet")
+	}
+	return payloadType, packet[offset:end], nil
+}
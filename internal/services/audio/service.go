@@ -0,0 +1,368 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// updateInterval is how often status is published while audio arrives, for
+// level meters. Beats publish it at once.
+const updateInterval = 250 * time.Millisecond
+
+// BeatHandler is called with each beat detected. It runs on the goroutine
+// receiving audio, so it should not block for long.
+type BeatHandler func(beat Beat)
+
+// BandStatus is a band with its current level and beats.
+type BandStatus struct {
+	Band
+	Level      float64
+	LastBeatAt *time.Time
+	Beats      int // Beats since the configuration was applied
+}
+
+// Status is a snapshot of the audio input.
+type Status struct {
+	Config Config
+	// Listening is true while the device is capturing or the port is open
+	Listening   bool
+	Level       float64
+	Bands       []BandStatus
+	LastInputAt *time.Time
+	LastSource  *string
+	Error       *string
+}
+
+// Service analyzes the configured audio input and reports beats. It is safe
+// for concurrent use.
+type Service struct {
+	mu       sync.Mutex
+	config   Config
+	analyzer *Analyzer
+	input    io.Closer // The capture or the UDP port audio is read from
+	inputErr string
+
+	beats       []int       // By band
+	lastBeatAt  []time.Time // By band; zero for never
+	lastInputAt time.Time
+	lastSource  string
+	lastUpdate  time.Time
+
+	onBeat   BeatHandler
+	onUpdate func(status *Status)
+
+	now       func() time.Time
+	capture   func(config Config) (io.ReadCloser, error)
+	listenUDP func(port int) (net.PacketConn, error)
+}
+
+// NewService creates an audio service with the default configuration,
+// disabled.
+func NewService() *Service {
+	s := &Service{
+		now:     time.Now,
+		capture: captureDevice,
+		listenUDP: func(port int) (net.PacketConn, error) {
+			return net.ListenUDP("udp4", &net.UDPAddr{Port: port})
+		},
+	}
+	s.applyLocked(DefaultConfig())
+	return s
+}
+
+// SetBeatHandler sets the handler for beats.
+func (s *Service) SetBeatHandler(handler BeatHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onBeat = handler
+}
+
+// SetUpdateCallback sets the callback for status changes. While audio
+// arrives it is also called on every beat and every updateInterval.
+func (s *Service) SetUpdateCallback(callback func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = callback
+}
+
+// Config returns the current configuration.
+func (s *Service) Config() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+// Status returns the current audio input state.
+func (s *Service) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+// SetConfig validates and applies a configuration, reopening the input and
+// restarting the analysis.
+func (s *Service) SetConfig(config Config) (*Status, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.closeInputLocked()
+	s.applyLocked(config)
+	if config.Enabled {
+		s.openInputLocked()
+	}
+	status := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.emitUpdate(status)
+	return status, nil
+}
+
+// Cleanup closes the input.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeInputLocked()
+}
+
+func (s *Service) applyLocked(config Config) {
+	s.config = config
+	s.config.Bands = append([]Band(nil), config.Bands...)
+	s.analyzer = NewAnalyzer(config.SampleRate, s.config.Bands)
+	s.beats = make([]int, len(config.Bands))
+	s.lastBeatAt = make([]time.Time, len(config.Bands))
+	s.lastInputAt = time.Time{}
+	s.lastSource = ""
+}
+
+func (s *Service) openInputLocked() {
+	config := s.config
+	switch config.Source {
+	case SourceDevice:
+		capture, err := s.capture(config)
+		if err != nil {
+			s.inputErr = err.Error()
+			log.Warn("cannot capture audio", "device", config.Device, "error", err)
+			return
+		}
+		s.input = capture
+		log.Info("🎵 Capturing audio", "device", config.Device, "sampleRate", config.SampleRate)
+		go s.readCapture(capture, config.Channels, config.SampleRate)
+	case SourceRTP, SourceUDP:
+		conn, err := s.listenUDP(config.Port)
+		if err != nil {
+			s.inputErr = err.Error()
+			log.Warn("cannot listen for audio", "port", config.Port, "error", err)
+			return
+		}
+		s.input = conn
+		log.Info("🎵 Receiving audio", "source", config.Source, "port", config.Port)
+		go s.receive(conn, config.Source, config.Channels)
+	}
+}
+
+func (s *Service) closeInputLocked() {
+	if s.input != nil {
+		_ = s.input.Close()
+		s.input = nil
+	}
+	s.inputErr = ""
+}
+
+// readCapture analyzes a capture in windows of audio until it ends.
+func (s *Service) readCapture(capture io.ReadCloser, channels, sampleRate int) {
+	buffer := make([]byte, 2*channels*max(1, sampleRate/windowsPerSecond))
+	for {
+		n, err := io.ReadFull(capture, buffer)
+		if n > 0 {
+			s.handleAudio(capture, decodePCM(buffer[:n], binary.LittleEndian, channels), "")
+		}
+		if err != nil {
+			s.inputEnded(capture, err)
+			return
+		}
+	}
+}
+
+// receive analyzes the audio datagrams arriving on conn until it is closed.
+func (s *Service) receive(conn net.PacketConn, source Source, channels int) {
+	buffer := make([]byte, 65536)
+	for {
+		n, from, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return // Closed
+		}
+		data, order, packetChannels := buffer[:n], binary.ByteOrder(binary.LittleEndian), channels
+		if source == SourceRTP {
+			payloadType, payload, err := parseRTP(data)
+			if err != nil {
+				continue
+			}
+			switch payloadType {
+			case payloadL16Stereo:
+				packetChannels = 2
+			case payloadL16Mono:
+				packetChannels = 1
+			}
+			data, order = payload, binary.BigEndian
+		}
+		s.handleAudio(conn, decodePCM(data, order, packetChannels), from.String())
+	}
+}
+
+// inputEnded records why an input stopped, unless it was closed on purpose.
+func (s *Service) inputEnded(input io.Closer, err error) {
+	s.mu.Lock()
+	if s.input != input {
+		s.mu.Unlock()
+		return
+	}
+	s.input = nil
+	s.inputErr = err.Error()
+	log.Warn("audio input ended", "error", err)
+	status := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.emitUpdate(status)
+}
+
+// handleAudio analyzes samples read from input and reports their beats.
+func (s *Service) handleAudio(input io.Closer, samples []float64, from string) {
+	s.mu.Lock()
+	if s.input != input {
+		s.mu.Unlock()
+		return // Closed while reading
+	}
+	now := s.now()
+	s.lastInputAt = now
+	s.lastSource = from
+
+	beats := s.analyzer.Process(samples)
+	for _, beat := range beats {
+		for i, band := range s.config.Bands {
+			if band.Name == beat.Band {
+				s.beats[i]++
+				s.lastBeatAt[i] = now
+			}
+		}
+	}
+	var status *Status
+	if len(beats) > 0 || now.Sub(s.lastUpdate) >= updateInterval {
+		s.lastUpdate = now
+		status = s.snapshotLocked()
+	}
+	onBeat := s.onBeat
+	s.mu.Unlock()
+
+	if onBeat != nil {
+		for _, beat := range beats {
+			onBeat(beat)
+		}
+	}
+	s.emitUpdate(status)
+}
+
+func (s *Service) snapshotLocked() *Status {
+	status := &Status{
+		Config:    s.config,
+		Listening: s.input != nil,
+		Level:     s.analyzer.Level(),
+		Bands:     make([]BandStatus, len(s.config.Bands)),
+	}
+	status.Config.Bands = append([]Band{}, s.config.Bands...)
+	levels := s.analyzer.BandLevels()
+	for i, band := range s.config.Bands {
+		status.Bands[i] = BandStatus{Band: band, Level: levels[i], Beats: s.beats[i]}
+		if !s.lastBeatAt[i].IsZero() {
+			lastBeatAt := s.lastBeatAt[i]
+			status.Bands[i].LastBeatAt = &lastBeatAt
+		}
+	}
+	if !s.lastInputAt.IsZero() {
+		lastInputAt := s.lastInputAt
+		status.LastInputAt = &lastInputAt
+	}
+	if s.lastSource != "" {
+		lastSource := s.lastSource
+		status.LastSource = &lastSource
+	}
+	if s.inputErr != "" {
+		inputErr := s.inputErr
+		status.Error = &inputErr
+	}
+	return status
+}
+
+func (s *Service) emitUpdate(status *Status) {
+	if status == nil {
+		return
+	}
+	s.mu.Lock()
+	callback := s.onUpdate
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(status)
+	}
+}
+
+// captureProcess is audio captured by an arecord process.
+type captureProcess struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+
+	once sync.Once
+}
+
+// captureDevice starts capturing raw 16-bit little-endian PCM from a sound
+// device.
+func captureDevice(config Config) (io.ReadCloser, error) {
+	p := &captureProcess{}
+	p.cmd = exec.Command("arecord", "-q", "-D", config.Device, "-t", "raw", "-f", "S16_LE",
+		"-r", strconv.Itoa(config.SampleRate), "-c", strconv.Itoa(config.Channels))
+	p.cmd.Stderr = &p.stderr
+	stdout, err := p.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	p.stdout = stdout
+	if err := p.cmd.Start(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Read reads captured audio. When the capture ends, the error carries what
+// arecord reported.
+func (p *captureProcess) Read(b []byte) (int, error) {
+	n, err := p.stdout.Read(b)
+	if errors.Is(err, io.EOF) {
+		p.wait()
+		if message := strings.TrimSpace(p.stderr.String()); message != "" {
+			return n, errors.New(message)
+		}
+		return n, errors.New("audio capture ended")
+	}
+	return n, err
+}
+
+// Close stops the capture.
+func (p *captureProcess) Close() error {
+	_ = p.cmd.Process.Kill()
+	p.wait()
+	return nil
+}
+
+func (p *captureProcess) wait() {
+	p.once.Do(func() { _ = p.cmd.Wait() })
+}
@@ -0,0 +1,402 @@
+// Package audit records who changed what through GraphQL mutations, so
+// operators sharing a show can trace every edit.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+)
+
+// Entity types recorded in the audit log.
+const (
+	EntityProject           = "PROJECT"
+	EntityFixtureDefinition = "FIXTURE_DEFINITION"
+	EntityFixture           = "FIXTURE"
+	EntityFixtureGroup      = "FIXTURE_GROUP"
+	EntityScene             = "SCENE"
+	EntitySceneBoard        = "SCENE_BOARD"
+	EntitySceneBoardButton  = "SCENE_BOARD_BUTTON"
+	EntityCueList           = "CUE_LIST"
+	EntityCue               = "CUE"
+	EntityEffect            = "EFFECT"
+	EntitySubmaster         = "SUBMASTER"
+	EntityPalette           = "PALETTE"
+	EntitySnapshot          = "SNAPSHOT"
+	EntityShowTimer         = "SHOW_TIMER"
+	EntitySchedule          = "SCHEDULE"
+	EntitySoftPatch         = "SOFT_PATCH"
+	EntityUniverse          = "UNIVERSE"
+	EntityLayoutZone        = "LAYOUT_ZONE"
+	EntitySelectionSet      = "SELECTION_SET"
+	EntityPixelMatrix       = "PIXEL_MATRIX"
+	// EntitySystem covers mutations of server-wide state such as DMX output,
+	// network settings, and playback control without a single target.
+	EntitySystem = "SYSTEM"
+)
+
+// entityKinds maps the entity named in a mutation to its type and the
+// argument that identifies it, most specific names first.
+var entityKinds = []struct {
+	name       string
+	entityType string
+	idArg      string
+}{
+	{"SceneBoardButton", EntitySceneBoardButton, "buttonId"},
+	{"SceneBoard", EntitySceneBoard, "sceneBoardId"},
+	{"FixtureDefinition", EntityFixtureDefinition, "definitionId"},
+	{"FixtureGroup", EntityFixtureGroup, "groupId"},
+	{"Fixture", EntityFixture, "fixtureId"},
+	{"CueList", EntityCueList, "cueListId"},
+	{"Cue", EntityCue, "cueId"},
+	{"Scene", EntityScene, "sceneId"},
+	{"Snapshot", EntitySnapshot, "snapshotId"},
+	{"Project", EntityProject, "projectId"},
+	{"Effect", EntityEffect, "effectId"},
+	{"Submaster", EntitySubmaster, "submasterId"},
+	{"Palette", EntityPalette, "paletteId"},
+	{"ShowTimer", EntityShowTimer, "timerId"},
+	{"Schedule", EntitySchedule, "scheduleId"},
+	{"SoftPatch", EntitySoftPatch, "softPatchId"},
+	{"Universe", EntityUniverse, "universeId"},
+	{"LayoutZone", EntityLayoutZone, "zoneId"},
+	{"SelectionSet", EntitySelectionSet, "selectionSetId"},
+	{"PixelMatrix", EntityPixelMatrix, "matrixId"},
+}
+
+// operationEntityTypes covers mutations whose names do not name what they change.
+var operationEntityTypes = map[string]string{
+	"addSceneToBoard":                        EntitySceneBoardButton,
+	"removeSceneFromBoard":                   EntitySceneBoardButton,
+	"importOFLFixture":                       EntityFixtureDefinition,
+	"importGDTFFixture":                      EntityFixtureDefinition,
+	"forceDeleteDefinition":                  EntityFixtureDefinition,
+	"updateInstanceChannelFadeBehavior":      EntityFixture,
+	"bulkUpdateInstanceChannelsFadeBehavior": EntityFixture,
+	"importProject":                          EntityProject,
+	"activateSceneFromBoard":                 EntitySceneBoard,
+	"initializePreviewWithScene":             EntitySystem,
+	"replaceChannelValue":                    EntityScene,
+	"syncFixtureLibrary":                     EntityFixtureDefinition,
+	"bulkCreateFixtureDefinitions":           EntityFixtureDefinition,
+}
+
+// unaudited lists continuous live controls, such as faders, and presence
+// reports, which can fire many times a second and would flood the log.
+var unaudited = map[string]bool{
+	"setChannelValue":      true,
+	"updatePreviewChannel": true,
+	"overrideDmxChannel":   true,
+	"setSubmasterLevel":    true,
+	"setMasterLevel":       true,
+	"setCueListCrossfade":  true,
+	"setFixtureColor":      true,
+	"tapTempo":             true,
+	"updatePresence":       true,
+	"leavePresence":        true,
+}
+
+// ignoredFields change on every write, so they are left out of summaries.
+var ignoredFields = map[string]bool{"ID": true, "CreatedAt": true, "UpdatedAt": true}
+
+// maxSummaryValue bounds how much of a value a summary shows.
+const maxSummaryValue = 60
+
+// Entity is the current state of an audited record.
+type Entity struct {
+	ProjectID string // Empty for records outside any project
+	State     map[string]interface{}
+}
+
+// Loader returns the current state of a record, or nil if there is none.
+type Loader func(ctx context.Context, entityType, id string) (*Entity, error)
+
+// Service records mutations in the audit log. It is a gqlgen handler
+// extension; register it with the server's Use method.
+type Service struct {
+	repo *repositories.AuditRepository
+	load Loader
+}
+
+var (
+	_ graphql.HandlerExtension = (*Service)(nil)
+	_ graphql.FieldInterceptor = (*Service)(nil)
+)
+
+// NewService creates an audit service that reads records with load.
+func NewService(repo *repositories.AuditRepository, load Loader) *Service {
+	return &Service{repo: repo, load: load}
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (s *Service) ExtensionName() string {
+	return "AuditLog"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (s *Service) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField records each top-level mutation with the state of its
+// target before and after it.
+func (s *Service) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" || unaudited[fc.Field.Name] {
+		return next(ctx)
+	}
+
+	operation := fc.Field.Name
+	entityType, entityID := Target(operation, fc.Args)
+	var before *Entity
+	if entityID != "" {
+		before = s.loadEntity(ctx, entityType, entityID)
+	}
+
+	res, err := next(ctx)
+
+	if entityID == "" && err == nil {
+		entityID = stringField(res, "ID")
+	}
+	var after *Entity
+	if entityID != "" {
+		after = s.loadEntity(ctx, entityType, entityID)
+	}
+
+	entry := &models.AuditLog{
+		Operator:   OperatorFromContext(ctx),
+		Operation:  operation,
+		EntityType: entityType,
+		Summary:    Summarize(stateOf(before), stateOf(after)),
+	}
+	if entityID != "" {
+		entry.EntityID = &entityID
+	}
+	if projectID := projectOf(before, after, fc.Args, res); projectID != "" {
+		entry.ProjectID = &projectID
+	}
+	entry.Before = stateJSON(before)
+	entry.After = stateJSON(after)
+	if err != nil {
+		message := err.Error()
+		entry.Error = &message
+		entry.Summary = "failed: " + message
+	}
+
+	// Record even if the client has gone away; the mutation still happened
+	if recordErr := s.repo.Create(context.WithoutCancel(ctx), entry); recordErr != nil {
+		log.Warn("failed to record operation in audit log", "operation", operation, "error", recordErr)
+	}
+	return res, err
+}
+
+// Prune deletes entries older than retention.
+func (s *Service) Prune(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-retention))
+}
+
+func (s *Service) loadEntity(ctx context.Context, entityType, id string) *Entity {
+	entity, err := s.load(ctx, entityType, id)
+	if err != nil {
+		log.Warn("failed to load record for audit log", "type", entityType, "id", id, "error", err)
+		return nil
+	}
+	return entity
+}
+
+// Target returns the type of entity a mutation changes and, when its
+// arguments identify one, the entity's ID.
+func Target(operation string, args map[string]interface{}) (string, string) {
+	entityType, ok := operationEntityTypes[operation]
+	if !ok {
+		entityType = EntitySystem
+		for _, kind := range entityKinds {
+			if strings.Contains(operation, kind.name) {
+				entityType = kind.entityType
+				break
+			}
+		}
+	}
+
+	if id := stringValue(args["id"]); id != "" {
+		return entityType, id
+	}
+	for _, kind := range entityKinds {
+		if kind.entityType == entityType {
+			if id := stringValue(args[kind.idArg]); id != "" {
+				return entityType, id
+			}
+		}
+	}
+	// Controls like nextCue(cueListId) act on a record of another type
+	for _, kind := range entityKinds {
+		if kind.entityType == EntityProject {
+			continue
+		}
+		if id := stringValue(args[kind.idArg]); id != "" {
+			return kind.entityType, id
+		}
+	}
+	return entityType, ""
+}
+
+// Summarize describes how a record changed, field by field. It is empty when
+// nothing changed, as for playback controls.
+func Summarize(before, after map[string]interface{}) string {
+	switch {
+	case before == nil && after == nil:
+		return ""
+	case before == nil:
+		return "created"
+	case after == nil:
+		return "deleted"
+	}
+
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	var names []string
+	for k := range keys {
+		if !ignoredFields[k] {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, name := range names {
+		from, to := before[name], after[name]
+		switch {
+		case reflect.DeepEqual(from, to):
+		case isComposite(from) || isComposite(to):
+			changes = append(changes, fieldLabel(name)+" changed")
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", fieldLabel(name), summaryValue(from), summaryValue(to)))
+		}
+	}
+	return strings.Join(changes, "; ")
+}
+
+func stateOf(entity *Entity) map[string]interface{} {
+	if entity == nil {
+		return nil
+	}
+	return entity.State
+}
+
+func stateJSON(entity *Entity) *string {
+	if entity == nil {
+		return nil
+	}
+	data, err := json.Marshal(entity.State)
+	if err != nil {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
+// projectOf finds the project a mutation belongs to, from its target, its
+// arguments, or its result.
+func projectOf(before, after *Entity, args map[string]interface{}, res interface{}) string {
+	for _, entity := range []*Entity{after, before} {
+		if entity != nil && entity.ProjectID != "" {
+			return entity.ProjectID
+		}
+	}
+	if id := stringValue(args["projectId"]); id != "" {
+		return id
+	}
+	if id := stringField(args["input"], "ProjectID"); id != "" {
+		return id
+	}
+	return stringField(res, "ProjectID")
+}
+
+// stringValue returns a string or non-nil string pointer argument.
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case *string:
+		if s != nil {
+			return *s
+		}
+	}
+	return ""
+}
+
+// stringField returns a string field of a struct or struct pointer.
+func stringField(v interface{}, name string) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+	field := rv.FieldByName(name)
+	if !field.IsValid() {
+		return ""
+	}
+	return stringValue(field.Interface())
+}
+
+func isComposite(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func summaryValue(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if len(s) > maxSummaryValue {
+		s = s[:maxSummaryValue-3] + "..."
+	}
+	return s
+}
+
+// fieldLabel converts a Go field name to the GraphQL style, e.g. FadeInTime
+// to fadeInTime, CueListID to cueListId, and DMXUniverse to dmxUniverse.
+func fieldLabel(name string) string {
+	if len(name) > 2 && strings.HasSuffix(name, "ID") {
+		name = strings.TrimSuffix(name, "ID") + "Id"
+	}
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper-- // Keep the capital starting the next word
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+func TestTarget(t *testing.T) {
+	cueID := "cue-1"
+	tests := []struct {
+		operation string
+		args      map[string]interface{}
+		wantType  string
+		wantID    string
+	}{
+		{"updateCue", map[string]interface{}{"id": "cue-1"}, EntityCue, "cue-1"},
+		{"updateCueValues", map[string]interface{}{"cueId": &cueID}, EntityCue, "cue-1"},
+		{"deleteCueList", map[string]interface{}{"id": "list-1"}, EntityCueList, "list-1"},
+		{"nextCue", map[string]interface{}{"cueListId": "list-1"}, EntityCueList, "list-1"},
+		{"setSceneLive", map[string]interface{}{"sceneId": "scene-1"}, EntityScene, "scene-1"},
+		{"updateSceneBoardButton", map[string]interface{}{"id": "button-1"}, EntitySceneBoardButton, "button-1"},
+		{"removeSceneFromBoard", map[string]interface{}{"buttonId": "button-1"}, EntitySceneBoardButton, "button-1"},
+		{"createProjectSnapshot", map[string]interface{}{"projectId": "p"}, EntitySnapshot, ""},
+		{"updateProjectNamingConvention", map[string]interface{}{"projectId": "p"}, EntityProject, "p"},
+		{"createScene", map[string]interface{}{}, EntityScene, ""},
+		{"bulkDeleteFixtures", map[string]interface{}{}, EntityFixture, ""},
+		{"blackout", map[string]interface{}{}, EntitySystem, ""},
+	}
+	for _, tt := range tests {
+		gotType, gotID := Target(tt.operation, tt.args)
+		if gotType != tt.wantType || gotID != tt.wantID {
+			t.Errorf("Target(%s) = %s %q, want %s %q", tt.operation, gotType, gotID, tt.wantType, tt.wantID)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	before := map[string]interface{}{
+		"ID": "c", "Name": "Opening", "FadeInTime": 3.0, "UpdatedAt": "then",
+		"FixtureValues": []interface{}{"a"},
+	}
+	after := map[string]interface{}{
+		"ID": "c", "Name": "Opening", "FadeInTime": 5.0, "UpdatedAt": "now", "Notes": "Slow",
+		"FixtureValues": []interface{}{"b"},
+	}
+	want := `fadeInTime: 3 → 5; fixtureValues changed; notes: none → "Slow"`
+	if got := Summarize(before, after); got != want {
+		t.Errorf("Summarize() = %q, want %q", got, want)
+	}
+
+	if got := Summarize(nil, after); got != "created" {
+		t.Errorf("Summarize(nil, after) = %q, want created", got)
+	}
+	if got := Summarize(before, nil); got != "deleted" {
+		t.Errorf("Summarize(before, nil) = %q, want deleted", got)
+	}
+	if got := Summarize(before, before); got != "" {
+		t.Errorf("Summarize of an unchanged record = %q, want empty", got)
+	}
+}
+
+func TestFieldLabel(t *testing.T) {
+	for name, want := range map[string]string{
+		"FadeInTime":  "fadeInTime",
+		"ID":          "id",
+		"CueListID":   "cueListId",
+		"DMXUniverse": "dmxUniverse",
+	} {
+		if got := fieldLabel(name); got != want {
+			t.Errorf("fieldLabel(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestMiddleware_RecordsOperator(t *testing.T) {
+	var got string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = OperatorFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	req.RemoteAddr = "192.168.1.20:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "192.168.1.20" {
+		t.Errorf("Operator without header = %q, want client address", got)
+	}
+
+	req.Header.Set(OperatorHeader, "  Board Op ")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "Board Op" {
+		t.Errorf("Operator with header = %q, want Board Op", got)
+	}
+}
+
+func TestWebsocketInit_NamesOperator(t *testing.T) {
+	ctx := WithOperator(context.Background(), "10.0.0.5")
+
+	same, _, err := WebsocketInit(ctx, transport.InitPayload{})
+	if err != nil || OperatorFromContext(same) != "10.0.0.5" {
+		t.Errorf("Operator without payload = %q, %v; want address kept", OperatorFromContext(same), err)
+	}
+	named, _, err := WebsocketInit(ctx, transport.InitPayload{OperatorPayloadKey: "Programmer"})
+	if err != nil || OperatorFromContext(named) != "Programmer" {
+		t.Errorf("Operator with payload = %q, %v; want Programmer", OperatorFromContext(named), err)
+	}
+	if got := OperatorFromContext(context.Background()); got != "unknown" {
+		t.Errorf("Operator of a bare context = %q, want unknown", got)
+	}
+}
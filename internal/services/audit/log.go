@@ -0,0 +1,5 @@
+package audit
+
+import "github.com/bbernstein/lacylights-go/internal/services/logging"
+
+var log = logging.For(logging.ModuleAudit)
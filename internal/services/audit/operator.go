@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// OperatorHeader is the HTTP header a client names its operator with.
+const OperatorHeader = "X-LacyLights-Operator"
+
+// OperatorPayloadKey is the websocket connection parameter a client names its
+// operator with.
+const OperatorPayloadKey = "operator"
+
+// maxOperatorLength bounds client-supplied operator names.
+const maxOperatorLength = 100
+
+type operatorKey struct{}
+
+// WithOperator returns a context recording who is making requests.
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// OperatorFromContext returns who is making a request, or "unknown".
+func OperatorFromContext(ctx context.Context) string {
+	if operator, ok := ctx.Value(operatorKey{}).(string); ok && operator != "" {
+		return operator
+	}
+	return "unknown"
+}
+
+// Middleware records the operator of each HTTP request: the name in
+// OperatorHeader, or else the client's address.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operator := cleanOperator(r.Header.Get(OperatorHeader))
+		if operator == "" {
+			operator = clientAddress(r)
+		}
+		next.ServeHTTP(w, r.WithContext(WithOperator(r.Context(), operator)))
+	})
+}
+
+// WebsocketInit names a websocket connection's operator from its
+// connection parameters, keeping the address Middleware recorded otherwise.
+func WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	if operator := cleanOperator(payload.GetString(OperatorPayloadKey)); operator != "" {
+		ctx = WithOperator(ctx, operator)
+	}
+	return ctx, nil, nil
+}
+
+func cleanOperator(name string) string {
+	name = strings.TrimSpace(name)
+	if len(name) > maxOperatorLength {
+		name = name[:maxOperatorLength]
+	}
+	return name
+}
+
+func clientAddress(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
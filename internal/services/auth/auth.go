@@ -0,0 +1,505 @@
+// Package auth signs users in with a password, issuing JWTs, and enforces
+// their project roles on GraphQL mutations and subscriptions.
+//
+// Authentication is off unless enabled, so a standalone console keeps
+// working without accounts.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+)
+
+// User roles.
+const (
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+	// Front of house staff, who may run the house lights of any project
+	// and nothing else
+	RoleHouse = "HOUSE"
+)
+
+// Project roles, from least to most privileged.
+const (
+	RoleViewer = "VIEWER"
+	RoleEditor = "EDITOR"
+	RoleOwner  = "OWNER"
+)
+
+var projectRoleRank = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// Errors returned when a request may not proceed.
+var (
+	ErrUnauthenticated    = errors.New("authentication required")
+	ErrForbidden          = errors.New("permission denied")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrDisabled           = errors.New("authentication is not enabled")
+)
+
+// publicOperations may be called without signing in.
+var publicOperations = map[string]bool{
+	"login": true,
+}
+
+// adminOperations manage accounts or act on the whole server's data, and
+// are limited to administrators. So are all other mutations that name no
+// project, such as blackout and updateSetting, unless listed in
+// userOperations.
+var adminOperations = map[string]bool{
+	"createUser":    true,
+	"deleteUser":    true,
+	"createBackup":  true,
+	"restoreBackup": true,
+	"deleteBackup":  true,
+	// Parks hold DMX output whichever project is running
+	"parkChannel": true,
+}
+
+// userOperations are mutations that any signed-in user may make without a
+// role on a project, such as creating one. Those that name projects, such as
+// deleting a fixture definition that projects use, still need the usual
+// roles on them.
+var userOperations = map[string]bool{
+	"createProject":                true,
+	"bulkCreateProjects":           true,
+	"importProject":                true,
+	"importProjectArchive":         true,
+	"importProjectFromQLC":         true,
+	"createFixtureDefinition":      true,
+	"importOFLFixture":             true,
+	"importGDTFFixture":            true,
+	"updateFixtureDefinition":      true,
+	"deleteFixtureDefinition":      true,
+	"forceDeleteDefinition":        true,
+	"bulkCreateFixtureDefinitions": true,
+	"bulkUpdateFixtureDefinitions": true,
+	"bulkDeleteFixtureDefinitions": true,
+	"commitPreviewSession":         true,
+	"cancelPreviewSession":         true,
+	"changePassword":               true,
+	"leavePresence":                true,
+}
+
+// ownerOperations need the owner role on the projects they name; other
+// mutations need editor and subscriptions need viewer.
+var ownerOperations = map[string]bool{
+	"deleteProject":       true,
+	"bulkDeleteProjects":  true,
+	"setProjectMember":    true,
+	"removeProjectMember": true,
+}
+
+// viewerOperations are mutations that viewers may make.
+var viewerOperations = map[string]bool{
+	"updatePresence": true,
+}
+
+// houseOperations run the house lights. House users may call them, and
+// only them, on any project; other users need the usual project roles.
+var houseOperations = map[string]bool{
+	"setHouseLights":     true,
+	"releaseHouseLights": true,
+	"houseLightsChanged": true,
+}
+
+// ProjectLookup returns the projects that records of one type belong to.
+type ProjectLookup func(ctx context.Context, entityType string, ids []string) ([]string, error)
+
+// Service signs users in and authorizes their requests. It is a gqlgen
+// handler extension; register it with the server's Use method.
+type Service struct {
+	users  *repositories.UserRepository
+	lookup ProjectLookup
+
+	// Set by Enable before the server starts
+	enabled bool
+	secret  []byte
+	ttl     time.Duration
+
+	now func() time.Time
+}
+
+var (
+	_ graphql.HandlerExtension = (*Service)(nil)
+	_ graphql.FieldInterceptor = (*Service)(nil)
+)
+
+// NewService creates an auth service, initially disabled, that finds the
+// projects named in requests with lookup.
+func NewService(users *repositories.UserRepository, lookup ProjectLookup) *Service {
+	return &Service{users: users, lookup: lookup, now: time.Now}
+}
+
+// Enable turns on authentication, signing tokens with secret that expire
+// after ttl. Call it before serving requests.
+func (s *Service) Enable(secret []byte, ttl time.Duration) {
+	s.enabled = true
+	s.secret = secret
+	s.ttl = ttl
+}
+
+// Enabled reports whether authentication is on.
+func (s *Service) Enabled() bool {
+	return s.enabled
+}
+
+// Bootstrap creates an administrator if there are no users yet, reporting
+// whether it did.
+func (s *Service) Bootstrap(ctx context.Context, email, password string) (bool, error) {
+	count, err := s.users.Count(ctx)
+	if err != nil || count > 0 {
+		return false, err
+	}
+	if _, err := s.CreateUser(ctx, email, nil, password, RoleAdmin); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateUser creates a user with a password.
+func (s *Service) CreateUser(ctx context.Context, email string, name *string, password, role string) (*models.User, error) {
+	email = normalizeEmail(email)
+	if email == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+	existing, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a user with email %s already exists", email)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{Email: email, Name: name, Role: role, PasswordHash: &hash}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login checks a user's password and issues a session token.
+func (s *Service) Login(ctx context.Context, email, password string) (string, time.Time, *models.User, error) {
+	if !s.enabled {
+		return "", time.Time{}, nil, ErrDisabled
+	}
+	user, err := s.users.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return "", time.Time{}, nil, err
+	}
+	if user == nil || user.PasswordHash == nil || !CheckPassword(*user.PasswordHash, password) {
+		return "", time.Time{}, nil, ErrInvalidCredentials
+	}
+	token, expiresAt, err := s.IssueToken(user)
+	if err != nil {
+		return "", time.Time{}, nil, err
+	}
+	return token, expiresAt, user, nil
+}
+
+// ChangePassword replaces the signed-in user's password after checking
+// their current one.
+func (s *Service) ChangePassword(ctx context.Context, current, replacement string) error {
+	user := UserFromContext(ctx)
+	if user == nil {
+		return ErrUnauthenticated
+	}
+	if user.PasswordHash == nil || !CheckPassword(*user.PasswordHash, current) {
+		return ErrInvalidCredentials
+	}
+	hash, err := HashPassword(replacement)
+	if err != nil {
+		return err
+	}
+	return s.users.UpdatePassword(ctx, user.ID, hash)
+}
+
+// IssueToken signs a session token for user.
+func (s *Service) IssueToken(user *models.User) (string, time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.ttl)
+	token, err := SignToken(Claims{
+		Subject:   user.ID,
+		Email:     user.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}, s.secret)
+	return token, expiresAt, err
+}
+
+// Authenticate returns the user a session token was issued to. The user is
+// reloaded so role changes and deletions take effect at once.
+func (s *Service) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	claims, err := ParseToken(token, s.secret, s.now())
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.FindByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+	return user, nil
+}
+
+// Middleware signs in HTTP requests that carry a bearer token, rejecting
+// those whose token is not valid.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r.Header.Get("Authorization"))
+		if !s.enabled || token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, err := s.Authenticate(r.Context(), token)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(signedIn(r.Context(), user)))
+	})
+}
+
+// WebsocketInit signs in a websocket connection from the token in its
+// authorization connection parameter.
+func (s *Service) WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	token := bearerToken(payload.Authorization())
+	if !s.enabled || token == "" {
+		return ctx, nil, nil
+	}
+	user, err := s.Authenticate(ctx, token)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return signedIn(ctx, user), nil, nil
+}
+
+// GrantOwner makes the signed-in user an owner of a project they created.
+func (s *Service) GrantOwner(ctx context.Context, projectID string) {
+	user := UserFromContext(ctx)
+	if !s.enabled || user == nil {
+		return
+	}
+	if _, err := s.users.SetMembership(ctx, user.ID, projectID, RoleOwner); err != nil {
+		log.Warn("failed to make user a project owner", "user", user.Email, "project", projectID, "error", err)
+	}
+}
+
+// RequireAdmin returns an error unless authentication is off or the
+// signed-in user is an administrator.
+func (s *Service) RequireAdmin(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+	user := UserFromContext(ctx)
+	if user == nil {
+		return ErrUnauthenticated
+	}
+	if user.Role != RoleAdmin {
+		return fmt.Errorf("%w: administrators only", ErrForbidden)
+	}
+	return nil
+}
+
+// RequireUser returns an error unless authentication is off or an HTTP
+// request is signed in. Clients that cannot set headers, such as browser
+// websockets, may pass their token in a token query parameter instead.
+func (s *Service) RequireUser(r *http.Request) error {
+	if !s.enabled {
+		return nil
+	}
+	_, err := s.requestUser(r)
+	return err
+}
+
+// RequireViewer is RequireUser for requests that read projects, which the
+// signed-in user must be a member of.
+func (s *Service) RequireViewer(r *http.Request, projectIDs []string) error {
+	if !s.enabled {
+		return nil
+	}
+	user, err := s.requestUser(r)
+	if err != nil {
+		return err
+	}
+	if user.Role == RoleAdmin {
+		return nil
+	}
+	for _, projectID := range projectIDs {
+		member, err := s.users.FindMembership(r.Context(), user.ID, projectID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			return fmt.Errorf("%w: viewing project %s requires the %s role", ErrForbidden, projectID, RoleViewer)
+		}
+	}
+	return nil
+}
+
+// requestUser returns the user an HTTP request is signed in as.
+func (s *Service) requestUser(r *http.Request) (*models.User, error) {
+	if user := UserFromContext(r.Context()); user != nil {
+		return user, nil
+	}
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+	return s.Authenticate(r.Context(), token)
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (s *Service) ExtensionName() string {
+	return "Auth"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (s *Service) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField checks the signed-in user's roles before each top-level
+// mutation and subscription.
+func (s *Service) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !s.enabled || fc == nil || (fc.Object != "Mutation" && fc.Object != "Subscription") || publicOperations[fc.Field.Name] {
+		return next(ctx)
+	}
+	if err := s.authorize(ctx, fc.Object, fc.Field.Name, fc.Args); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+// authorize checks that the signed-in user holds the role an operation
+// needs on every project it names. Mutations outside any project need an
+// administrator unless they are user operations; subscriptions outside any
+// project only need a signed-in user.
+func (s *Service) authorize(ctx context.Context, object, operation string, args map[string]interface{}) error {
+	user := UserFromContext(ctx)
+	if user == nil {
+		return ErrUnauthenticated
+	}
+	if user.Role == RoleAdmin {
+		return nil
+	}
+	if adminOperations[operation] {
+		return fmt.Errorf("%w: %s is limited to administrators", ErrForbidden, operation)
+	}
+	if user.Role == RoleHouse {
+		if houseOperations[operation] {
+			return nil
+		}
+		return fmt.Errorf("%w: front of house users may only run the house lights", ErrForbidden)
+	}
+
+	required := RoleEditor
+	switch {
+	case ownerOperations[operation]:
+		required = RoleOwner
+	case object == "Subscription", viewerOperations[operation]:
+		required = RoleViewer
+	}
+
+	projectIDs, err := s.projectsOf(ctx, operation, args)
+	if err != nil {
+		return err
+	}
+	if len(projectIDs) == 0 && object == "Mutation" && !userOperations[operation] {
+		return fmt.Errorf("%w: %s is limited to administrators", ErrForbidden, operation)
+	}
+	for _, projectID := range projectIDs {
+		member, err := s.users.FindMembership(ctx, user.ID, projectID)
+		if err != nil {
+			return err
+		}
+		if member == nil || projectRoleRank[member.Role] < projectRoleRank[required] {
+			return fmt.Errorf("%w: %s requires the %s role on project %s", ErrForbidden, operation, required, projectID)
+		}
+	}
+	return nil
+}
+
+// projectsOf returns the projects an operation's arguments name. Fixture
+// definitions are shared, so they name the projects whose fixtures use them
+// only in operations on the definitions themselves.
+func (s *Service) projectsOf(ctx context.Context, operation string, args map[string]interface{}) ([]string, error) {
+	target, _ := audit.Target(operation, args)
+	byType := make(map[string][]string)
+	for _, ref := range References(operation, args) {
+		if ref.EntityType == audit.EntityFixtureDefinition && target != audit.EntityFixtureDefinition {
+			continue
+		}
+		byType[ref.EntityType] = append(byType[ref.EntityType], ref.ID)
+	}
+
+	seen := make(map[string]bool)
+	var projectIDs []string
+	add := func(ids []string) {
+		for _, id := range ids {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				projectIDs = append(projectIDs, id)
+			}
+		}
+	}
+	add(byType[audit.EntityProject])
+	for _, ref := range referenceSuffixes {
+		ids := byType[ref.entityType]
+		if ref.entityType == audit.EntityProject || len(ids) == 0 {
+			continue
+		}
+		found, err := s.lookup(ctx, ref.entityType, ids)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+		delete(byType, ref.entityType) // Types can have several suffixes
+	}
+	return projectIDs, nil
+}
+
+type userKey struct{}
+
+// WithUser returns a context signed in as user.
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the signed-in user, or nil.
+func UserFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userKey{}).(*models.User)
+	return user
+}
+
+// signedIn records user as both the signed-in user and the audit operator.
+func signedIn(ctx context.Context, user *models.User) context.Context {
+	return audit.WithOperator(WithUser(ctx, user), user.Email)
+}
+
+func bearerToken(header string) string {
+	header = strings.TrimSpace(header)
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return header
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
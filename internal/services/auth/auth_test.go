@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error: %v", err)
+	}
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("CheckPassword() rejected the right password")
+	}
+	if CheckPassword(hash, "wrong horse") {
+		t.Error("CheckPassword() accepted a wrong password")
+	}
+	if CheckPassword("plain", "plain") {
+		t.Error("CheckPassword() accepted a malformed hash")
+	}
+	if _, err := HashPassword("short"); err == nil {
+		t.Error("HashPassword() accepted a short password")
+	}
+}
+
+func TestToken_RoundTrip(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Unix(1700000000, 0)
+	token, err := SignToken(Claims{Subject: "user-1", Email: "op@example.com", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}, secret)
+	if err != nil {
+		t.Fatalf("SignToken() error: %v", err)
+	}
+
+	claims, err := ParseToken(token, secret, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ParseToken() error: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "op@example.com" {
+		t.Errorf("ParseToken() = %+v", claims)
+	}
+
+	if _, err := ParseToken(token, secret, now.Add(2*time.Hour)); err != ErrInvalidToken {
+		t.Errorf("ParseToken() of an expired token = %v, want ErrInvalidToken", err)
+	}
+	if _, err := ParseToken(token, []byte("other-secret"), now); err != ErrInvalidToken {
+		t.Errorf("ParseToken() with the wrong secret = %v, want ErrInvalidToken", err)
+	}
+	if _, err := ParseToken(token[:len(token)-2]+"xx", secret, now); err != ErrInvalidToken {
+		t.Errorf("ParseToken() of a tampered token = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	type fixtureValue struct {
+		FixtureID string
+	}
+	type sceneInput struct {
+		ProjectID     string
+		FixtureValues []*fixtureValue
+	}
+	type cueInput struct {
+		CueListID        string
+		TriggerCueListID graphql.Omittable[*string]
+	}
+	trigger := "list-2"
+
+	tests := []struct {
+		name      string
+		operation string
+		args      map[string]interface{}
+		want      []Ref
+	}{
+		{"id argument", "updateCue", map[string]interface{}{"id": "cue-1"},
+			[]Ref{{audit.EntityCue, "cue-1"}}},
+		{"nested input", "createScene", map[string]interface{}{"input": &sceneInput{
+			ProjectID:     "p1",
+			FixtureValues: []*fixtureValue{{FixtureID: "f1"}, {FixtureID: "f1"}},
+		}}, []Ref{{audit.EntityFixture, "f1"}, {audit.EntityProject, "p1"}}},
+		{"optional field", "createCue", map[string]interface{}{"input": cueInput{
+			CueListID:        "list-1",
+			TriggerCueListID: graphql.OmittableOf(&trigger),
+		}}, []Ref{{audit.EntityCueList, "list-1"}, {audit.EntityCueList, "list-2"}}},
+		{"ID list", "bulkDeleteScenes", map[string]interface{}{"sceneIds": []string{"s1", "s2"}},
+			[]Ref{{audit.EntityScene, "s1"}, {audit.EntityScene, "s2"}}},
+		{"definition", "bulkDeleteFixtureDefinitions", map[string]interface{}{"definitionIds": []string{"d1"}},
+			[]Ref{{audit.EntityFixtureDefinition, "d1"}}},
+		{"instance channel", "updateInstanceChannelFadeBehavior", map[string]interface{}{"channelId": "c1"},
+			[]Ref{{EntityInstanceChannel, "c1"}}},
+		{"no project", "blackout", map[string]interface{}{"fadeTime": 2.0}, []Ref{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := References(tt.operation, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("References() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	for header, want := range map[string]string{
+		"Bearer abc.def": "abc.def",
+		"bearer  abc":    "abc",
+		"abc":            "abc",
+		"":               "",
+	} {
+		if got := bearerToken(header); got != want {
+			t.Errorf("bearerToken(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
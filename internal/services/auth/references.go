@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+)
+
+// Ref is a record named in an operation's arguments.
+type Ref struct {
+	EntityType string
+	ID         string
+}
+
+// EntityInstanceChannel is the type of a fixture's patched channel, named
+// by operations that change one channel.
+const EntityInstanceChannel = "INSTANCE_CHANNEL"
+
+// referenceSuffixes maps the endings of ID argument and input field names to
+// the project-scoped records they name, e.g. triggerCueListId and cueIds.
+var referenceSuffixes = []struct {
+	suffix     string
+	entityType string
+}{
+	{"definitionid", audit.EntityFixtureDefinition},
+	{"channelid", EntityInstanceChannel},
+	{"buttonid", audit.EntitySceneBoardButton},
+	{"boardid", audit.EntitySceneBoard},
+	{"groupid", audit.EntityFixtureGroup},
+	{"fixtureid", audit.EntityFixture},
+	{"cuelistid", audit.EntityCueList},
+	{"cueid", audit.EntityCue},
+	{"sceneid", audit.EntityScene},
+	{"snapshotid", audit.EntitySnapshot},
+	{"projectid", audit.EntityProject},
+	{"effectid", audit.EntityEffect},
+	{"submasterid", audit.EntitySubmaster},
+	{"paletteid", audit.EntityPalette},
+	{"scheduleid", audit.EntitySchedule},
+	{"softpatchid", audit.EntitySoftPatch},
+	{"universeid", audit.EntityUniverse},
+	{"zoneid", audit.EntityLayoutZone},
+	{"selectionsetid", audit.EntitySelectionSet},
+	{"matrixid", audit.EntityPixelMatrix},
+}
+
+// maxReferenceDepth bounds how far into nested inputs References looks.
+const maxReferenceDepth = 6
+
+// References returns the project-scoped records an operation's arguments
+// name, at any depth of its inputs, sorted and without duplicates.
+func References(operation string, args map[string]interface{}) []Ref {
+	seen := make(map[Ref]bool)
+	add := func(entityType, id string) {
+		if id != "" {
+			seen[Ref{EntityType: entityType, ID: id}] = true
+		}
+	}
+
+	// A bare id argument names the operation's own target
+	if entityType, id := audit.Target(operation, args); id != "" && args["id"] != nil {
+		add(entityType, id)
+	}
+	for name, value := range args {
+		collectReferences(name, reflect.ValueOf(value), 0, add)
+	}
+
+	refs := make([]Ref, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].EntityType != refs[j].EntityType {
+			return refs[i].EntityType < refs[j].EntityType
+		}
+		return refs[i].ID < refs[j].ID
+	})
+	return refs
+}
+
+// optional matches graphql.Omittable inputs.
+type optional interface {
+	IsSet() bool
+}
+
+func collectReferences(name string, v reflect.Value, depth int, add func(entityType, id string)) {
+	if depth > maxReferenceDepth || !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectReferences(name, v.Elem(), depth, add)
+		}
+	case reflect.String:
+		if entityType := referenceType(name); entityType != "" {
+			add(entityType, v.String())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectReferences(name, v.Index(i), depth+1, add)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			collectReferences(iter.Key().String(), iter.Value(), depth+1, add)
+		}
+	case reflect.Struct:
+		if v.CanInterface() {
+			if opt, ok := v.Interface().(optional); ok {
+				if opt.IsSet() {
+					collectReferences(name, v.MethodByName("Value").Call(nil)[0], depth, add)
+				}
+				return
+			}
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				collectReferences(t.Field(i).Name, v.Field(i), depth+1, add)
+			}
+		}
+	}
+}
+
+// referenceType returns the type of record an ID argument or field names,
+// or "" if it names none in a project.
+func referenceType(name string) string {
+	name = strings.ToLower(name)
+	name = strings.TrimSuffix(name, "s")
+	for _, ref := range referenceSuffixes {
+		if strings.HasSuffix(name, ref.suffix) {
+			return ref.entityType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,475 @@
+// Package backup archives every project and the server's settings to a
+// directory or an S3 bucket, on a schedule and on demand, and restores them.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+)
+
+// Extension is the file extension of backups.
+const Extension = ".tar.gz"
+
+// namePrefix starts the name of every backup the service makes; only these
+// are pruned.
+const namePrefix = "lacylights-backup-"
+
+// nameTimeLayout is the UTC time in a backup's name, which sorts by age.
+const nameTimeLayout = "20060102-150405"
+
+// formatVersion is the version of the archive layout written to manifests.
+const formatVersion = 1
+
+// validName matches backup names safe to use as file names and object keys.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*\.tar\.gz$`)
+
+// Reason records why a backup was made.
+type Reason string
+
+const (
+	ReasonManual    Reason = "MANUAL"
+	ReasonScheduled Reason = "SCHEDULED"
+)
+
+// Info describes a backup.
+type Info struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// manifest is the first file of a backup archive.
+type manifest struct {
+	Format    string            `json:"format"`
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Reason    Reason            `json:"reason"`
+	Projects  []manifestProject `json:"projects"`
+}
+
+type manifestProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// setting is a saved setting in a backup.
+type setting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RestoreOptions configures a restore.
+type RestoreOptions struct {
+	// Settings restores the saved settings too, replacing the current ones
+	Settings bool
+}
+
+// RestoredProject is a project imported from a backup.
+type RestoredProject struct {
+	ProjectID string
+	Stats     *importservice.ImportStats
+	Warnings  []string
+}
+
+// RestoreResult is what a restore did.
+type RestoreResult struct {
+	Projects []RestoredProject
+	// Settings are the keys of the settings restored
+	Settings []string
+	// Warnings include projects that could not be restored
+	Warnings []string
+}
+
+// Service makes, lists and restores backups.
+type Service struct {
+	store         Store
+	retention     int
+	projectRepo   *repositories.ProjectRepository
+	settingRepo   *repositories.SettingRepository
+	exportService *export.Service
+	importService *importservice.Service
+	now           func() time.Time
+
+	// createMu keeps backups made together from taking the same name
+	createMu sync.Mutex
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewService creates a backup service keeping up to retention backups in
+// store; zero keeps them all.
+func NewService(
+	store Store,
+	retention int,
+	projectRepo *repositories.ProjectRepository,
+	settingRepo *repositories.SettingRepository,
+	exportService *export.Service,
+	importService *importservice.Service,
+) *Service {
+	return &Service{
+		store:         store,
+		retention:     retention,
+		projectRepo:   projectRepo,
+		settingRepo:   settingRepo,
+		exportService: exportService,
+		importService: importService,
+		now:           time.Now,
+	}
+}
+
+// Location describes where backups are kept.
+func (s *Service) Location() string {
+	return s.store.Location()
+}
+
+// Create archives every project and the settings, then prunes backups past
+// the retention count.
+func (s *Service) Create(ctx context.Context, reason Reason) (*Info, error) {
+	s.createMu.Lock()
+	defer s.createMu.Unlock()
+
+	createdAt := s.now().UTC()
+	data, err := s.archive(ctx, createdAt, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := uniqueName(createdAt, existing)
+	if err := s.store.Put(ctx, name, data); err != nil {
+		return nil, fmt.Errorf("failed to save backup: %w", err)
+	}
+	log.Info("Backup saved", "name", name, "location", s.store.Location(), "bytes", len(data), "reason", string(reason))
+
+	info := &Info{Name: name, Size: int64(len(data)), CreatedAt: createdAt}
+	s.prune(ctx, append([]Info{*info}, existing...))
+	return info, nil
+}
+
+// archive writes the manifest, settings and each project's export to a
+// gzip-compressed tar archive.
+func (s *Service) archive(ctx context.Context, createdAt time.Time, reason Reason) ([]byte, error) {
+	projects, err := s.projectRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exports := make([]*export.ExportedProject, 0, len(projects))
+	m := manifest{Format: "lacylights-backup", Version: formatVersion, CreatedAt: createdAt, Reason: reason, Projects: []manifestProject{}}
+	for _, project := range projects {
+		exported, _, err := s.exportService.ExportProjectWithOptions(ctx, project.ID, export.DefaultExportOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to export project %s: %w", project.Name, err)
+		}
+		if exported == nil {
+			// Deleted since it was listed
+			continue
+		}
+		exports = append(exports, exported)
+		m.Projects = append(m.Projects, manifestProject{ID: project.ID, Name: project.Name, File: "projects/" + project.ID + ".json"})
+	}
+
+	saved, err := s.settingRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings := make([]setting, len(saved))
+	for i, st := range saved {
+		settings[i] = setting{Key: st.Key, Value: st.Value}
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	write := func(name string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: createdAt}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}
+	if err := write("manifest.json", m); err != nil {
+		return nil, err
+	}
+	if err := write("settings.json", settings); err != nil {
+		return nil, err
+	}
+	for i, exported := range exports {
+		if err := write(m.Projects[i].File, exported); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// List returns the backups, newest first.
+func (s *Service) List(ctx context.Context) ([]Info, error) {
+	objects, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	backups := make([]Info, 0, len(objects))
+	for _, object := range objects {
+		if !validName.MatchString(object.Name) {
+			continue
+		}
+		createdAt, ok := nameTime(object.Name)
+		if !ok {
+			createdAt = object.ModTime
+		}
+		backups = append(backups, Info{Name: object.Name, Size: object.Size, CreatedAt: createdAt})
+	}
+	sort.SliceStable(backups, func(i, j int) bool { return newer(backups[i], backups[j]) })
+	return backups, nil
+}
+
+// newer reports whether backup a was made after b. Of two made in the same
+// second, the one numbered by uniqueName, with the longer name, is newer.
+func newer(a, b Info) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	if len(a.Name) != len(b.Name) {
+		return len(a.Name) > len(b.Name)
+	}
+	return a.Name > b.Name
+}
+
+// Restore imports each project of a backup as a new project, leaving the
+// current ones untouched, and optionally writes back its settings. A project
+// that fails to import is reported in the warnings and the rest go ahead.
+func (s *Service) Restore(ctx context.Context, name string, opts RestoreOptions) (*RestoreResult, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid backup name: %s", name)
+	}
+	r, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup %s: %w", name, err)
+	}
+	defer func() { _ = zr.Close() }()
+	tr := tar.NewReader(zr)
+
+	result := &RestoreResult{}
+	var m *manifest
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("invalid backup %s: %w", name, err)
+		}
+
+		switch {
+		case header.Name == "manifest.json":
+			m = &manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return result, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			if m.Version > formatVersion {
+				return result, fmt.Errorf("backup %s was made by a newer release (format %d)", name, m.Version)
+			}
+		case m == nil:
+			return result, fmt.Errorf("invalid backup %s: no manifest", name)
+		case header.Name == "settings.json":
+			if !opts.Settings {
+				continue
+			}
+			var settings []setting
+			if err := json.NewDecoder(tr).Decode(&settings); err != nil {
+				return result, fmt.Errorf("invalid backup settings: %w", err)
+			}
+			for _, st := range settings {
+				if _, err := s.settingRepo.Upsert(ctx, st.Key, st.Value); err != nil {
+					return result, fmt.Errorf("failed to restore setting %s: %w", st.Key, err)
+				}
+				result.Settings = append(result.Settings, st.Key)
+			}
+		case path.Dir(header.Name) == "projects":
+			restored, err := s.restoreProject(ctx, tr, m)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", header.Name, err))
+				continue
+			}
+			result.Projects = append(result.Projects, *restored)
+		}
+	}
+	if m == nil {
+		return result, fmt.Errorf("invalid backup %s: no manifest", name)
+	}
+	log.Info("Backup restored", "name", name, "projects", len(result.Projects), "settings", len(result.Settings))
+	return result, nil
+}
+
+// restoreProject imports one project's export, named after the backup's
+// time so it is not mistaken for the current project.
+func (s *Service) restoreProject(ctx context.Context, r io.Reader, m *manifest) (*RestoredProject, error) {
+	exported, err := export.ReadArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	projectName := fmt.Sprintf("%s (backup %s)", exported.GetProjectName(), m.CreatedAt.Local().Format("2006-01-02 15:04"))
+	projectID, stats, warnings, err := s.importService.ImportExported(ctx, exported, importservice.ImportOptions{
+		Mode:                    importservice.ImportModeCreate,
+		ProjectName:             &projectName,
+		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RestoredProject{ProjectID: projectID, Stats: stats, Warnings: warnings}, nil
+}
+
+// Delete removes a backup.
+func (s *Service) Delete(ctx context.Context, name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid backup name: %s", name)
+	}
+	return s.store.Delete(ctx, name)
+}
+
+// prune deletes the service's own backups past the retention count, oldest
+// first. Other files in the store are left alone.
+func (s *Service) prune(ctx context.Context, backups []Info) {
+	if s.retention <= 0 {
+		return
+	}
+	var own []Info
+	for _, backup := range backups {
+		if strings.HasPrefix(backup.Name, namePrefix) {
+			own = append(own, backup)
+		}
+	}
+	sort.SliceStable(own, func(i, j int) bool { return newer(own[i], own[j]) })
+	for i := s.retention; i < len(own); i++ {
+		if err := s.store.Delete(ctx, own[i].Name); err != nil {
+			log.Warn("failed to prune backup", "name", own[i].Name, "error", err)
+			continue
+		}
+		log.Info("Pruned backup", "name", own[i].Name)
+	}
+}
+
+// Start makes a backup whenever the newest is older than interval, checking
+// at startup and then every interval or hour, whichever is sooner, until
+// Cleanup. A server restarted every day still gets its daily backup.
+func (s *Service) Start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interval <= 0 || s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.scheduleLoop(interval, s.stop, s.done)
+}
+
+// Cleanup stops scheduled backups.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (s *Service) scheduleLoop(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(min(interval, time.Hour))
+	defer ticker.Stop()
+	for {
+		s.CreateIfDue(context.Background(), interval)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateIfDue makes a scheduled backup if there is none newer than
+// interval. Failures are logged.
+func (s *Service) CreateIfDue(ctx context.Context, interval time.Duration) {
+	backups, err := s.List(ctx)
+	if err != nil {
+		log.Warn("failed to check for a recent backup", "error", err)
+		return
+	}
+	for _, backup := range backups {
+		if strings.HasPrefix(backup.Name, namePrefix) {
+			if s.now().Sub(backup.CreatedAt) < interval {
+				return
+			}
+			break
+		}
+	}
+	if _, err := s.Create(ctx, ReasonScheduled); err != nil {
+		log.Warn("scheduled backup failed", "error", err)
+	}
+}
+
+// uniqueName names a backup made at t, numbering it if another made in the
+// same second has the name.
+func uniqueName(t time.Time, existing []Info) string {
+	base := namePrefix + t.UTC().Format(nameTimeLayout)
+	taken := make(map[string]bool, len(existing))
+	for _, backup := range existing {
+		taken[backup.Name] = true
+	}
+	name := base + Extension
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d%s", base, n, Extension)
+	}
+	return name
+}
+
+// nameTime reads the time from a name made by uniqueName.
+func nameTime(name string) (time.Time, bool) {
+	stamp := strings.TrimPrefix(name, namePrefix)
+	if len(stamp) == len(name) || len(stamp) < len(nameTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(nameTimeLayout, stamp[:len(nameTimeLayout)])
+	return t, err == nil
+}
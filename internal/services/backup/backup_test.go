@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+	"github.com/lucsky/cuid"
+)
+
+func newTestService(testDB *testutil.TestDB, store Store, retention int) *Service {
+	return NewService(
+		store,
+		retention,
+		testDB.ProjectRepo,
+		repositories.NewSettingRepository(testDB.DB),
+		export.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+		importservice.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+	)
+}
+
+// createShow stores a project with a scene and a cue list playing it.
+func createShow(t *testing.T, testDB *testutil.TestDB, name string) *models.Project {
+	t.Helper()
+
+	project := &models.Project{ID: cuid.New(), Name: name}
+	scene := &models.Scene{ID: cuid.New(), Name: "Look 1", ProjectID: project.ID}
+	cueList := &models.CueList{ID: cuid.New(), Name: "Main", ProjectID: project.ID}
+	cue := &models.Cue{ID: cuid.New(), Name: "Cue 1", CueNumber: 1, CueListID: cueList.ID, SceneID: scene.ID}
+	for _, record := range []interface{}{project, scene, cueList, cue} {
+		if err := testDB.DB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create %T: %v", record, err)
+		}
+	}
+	return project
+}
+
+// clock is a settable time for the service.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func TestCreateAndRestore(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	dir := t.TempDir()
+	service := newTestService(testDB, NewDirStore(dir), 0)
+	settingRepo := repositories.NewSettingRepository(testDB.DB)
+
+	createShow(t, testDB, "Backup Show")
+	createShow(t, testDB, "Second Show")
+	if _, err := settingRepo.Upsert(ctx, "artnet_sync", "true"); err != nil {
+		t.Fatalf("Failed to save setting: %v", err)
+	}
+
+	info, err := service.Create(ctx, ReasonManual)
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if !strings.HasPrefix(info.Name, namePrefix) || !strings.HasSuffix(info.Name, Extension) || info.Size == 0 {
+		t.Errorf("Unexpected backup %+v", info)
+	}
+	if _, err := os.Stat(filepath.Join(dir, info.Name)); err != nil {
+		t.Errorf("Expected the backup file in the directory: %v", err)
+	}
+	backups, err := service.List(ctx)
+	if err != nil || len(backups) != 1 || backups[0].Name != info.Name {
+		t.Fatalf("Expected the backup listed, got %+v and %v", backups, err)
+	}
+
+	// Change the setting, then restore
+	if _, err := settingRepo.Upsert(ctx, "artnet_sync", "false"); err != nil {
+		t.Fatalf("Failed to save setting: %v", err)
+	}
+	result, err := service.Restore(ctx, info.Name, RestoreOptions{Settings: true})
+	if err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if len(result.Projects) != 2 || len(result.Warnings) != 0 {
+		t.Fatalf("Expected two projects restored, got %+v", result)
+	}
+	if len(result.Settings) != 1 || result.Settings[0] != "artnet_sync" {
+		t.Errorf("Expected the setting restored, got %v", result.Settings)
+	}
+	if saved, _ := settingRepo.FindByKey(ctx, "artnet_sync"); saved == nil || saved.Value != "true" {
+		t.Errorf("Expected the setting's backed up value, got %+v", saved)
+	}
+
+	projects, err := testDB.ProjectRepo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error: %v", err)
+	}
+	if len(projects) != 4 {
+		t.Errorf("Expected the originals and two restored projects, got %d", len(projects))
+	}
+	for _, restored := range result.Projects {
+		project, err := testDB.ProjectRepo.FindByID(ctx, restored.ProjectID)
+		if err != nil || project == nil || !strings.Contains(project.Name, "(backup ") {
+			t.Errorf("Expected a restored project named for the backup, got %+v and %v", project, err)
+		}
+		if restored.Stats.ScenesCreated != 1 || restored.Stats.CueListsCreated != 1 || restored.Stats.CuesCreated != 1 {
+			t.Errorf("Expected the show's scene and cues restored, got %+v", restored.Stats)
+		}
+	}
+}
+
+func TestRestore_WithoutSettings(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB, NewDirStore(t.TempDir()), 0)
+	settingRepo := repositories.NewSettingRepository(testDB.DB)
+
+	if _, err := settingRepo.Upsert(ctx, "artnet_sync", "true"); err != nil {
+		t.Fatalf("Failed to save setting: %v", err)
+	}
+	info, err := service.Create(ctx, ReasonManual)
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if _, err := settingRepo.Upsert(ctx, "artnet_sync", "false"); err != nil {
+		t.Fatalf("Failed to save setting: %v", err)
+	}
+
+	result, err := service.Restore(ctx, info.Name, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if len(result.Projects) != 0 || len(result.Settings) != 0 {
+		t.Errorf("Expected nothing restored from an empty server, got %+v", result)
+	}
+	if saved, _ := settingRepo.FindByKey(ctx, "artnet_sync"); saved == nil || saved.Value != "false" {
+		t.Errorf("Expected the current setting kept, got %+v", saved)
+	}
+}
+
+func TestCreate_PrunesPastRetention(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	dir := t.TempDir()
+	service := newTestService(testDB, NewDirStore(dir), 2)
+	c := &clock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service.now = c.now
+
+	// A backup copied in by hand is never pruned
+	handCopied := filepath.Join(dir, "before-the-tour"+Extension)
+	if err := os.WriteFile(handCopied, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(handCopied, old, old); err != nil {
+		t.Fatalf("Failed to date file: %v", err)
+	}
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		info, err := service.Create(ctx, ReasonScheduled)
+		if err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+		names = append(names, info.Name)
+		c.t = c.t.Add(time.Hour)
+	}
+
+	backups, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	var listed []string
+	for _, backup := range backups {
+		listed = append(listed, backup.Name)
+	}
+	want := []string{names[2], names[1], "before-the-tour" + Extension}
+	if strings.Join(listed, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, listed)
+	}
+}
+
+func TestCreate_SameSecond(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB, NewDirStore(t.TempDir()), 2)
+	c := &clock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service.now = c.now
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		info, err := service.Create(ctx, ReasonManual)
+		if err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+		names = append(names, info.Name)
+	}
+	if names[0] == names[1] || names[1] == names[2] {
+		t.Errorf("Expected distinct names, got %v", names)
+	}
+
+	// The two newest are kept
+	backups, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Name != names[2] || backups[1].Name != names[1] {
+		t.Errorf("Expected %v newest first, got %+v", names[1:], backups)
+	}
+}
+
+func TestCreateIfDue(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB, NewDirStore(t.TempDir()), 0)
+	c := &clock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service.now = c.now
+
+	count := func() int {
+		backups, err := service.List(ctx)
+		if err != nil {
+			t.Fatalf("List() error: %v", err)
+		}
+		return len(backups)
+	}
+
+	service.CreateIfDue(ctx, 24*time.Hour)
+	if count() != 1 {
+		t.Fatalf("Expected a first backup, got %d", count())
+	}
+	c.t = c.t.Add(23 * time.Hour)
+	service.CreateIfDue(ctx, 24*time.Hour)
+	if count() != 1 {
+		t.Errorf("Expected no backup before the interval, got %d", count())
+	}
+	c.t = c.t.Add(2 * time.Hour)
+	service.CreateIfDue(ctx, 24*time.Hour)
+	if count() != 2 {
+		t.Errorf("Expected a backup once due, got %d", count())
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB, NewDirStore(t.TempDir()), 0)
+
+	for _, name := range []string{"../secrets.tar.gz", "a/b.tar.gz", ".hidden.tar.gz", "backup.zip", ""} {
+		if _, err := service.Restore(ctx, name, RestoreOptions{}); err == nil {
+			t.Errorf("Expected Restore(%q) to be rejected", name)
+		}
+		if err := service.Delete(ctx, name); err == nil {
+			t.Errorf("Expected Delete(%q) to be rejected", name)
+		}
+	}
+	if _, err := service.Restore(ctx, "missing"+Extension, RestoreOptions{}); err == nil {
+		t.Error("Expected restoring a missing backup to fail")
+	}
+}
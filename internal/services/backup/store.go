@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for a backup that does not exist.
+var ErrNotFound = errors.New("backup not found")
+
+// Object is a stored backup file.
+type Object struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store keeps backup files. Names are plain file names, checked by the
+// service before they reach a store.
+type Store interface {
+	// Put saves a backup, replacing any of the same name.
+	Put(ctx context.Context, name string, data []byte) error
+	// Get opens a backup for reading.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns every backup, in no particular order.
+	List(ctx context.Context) ([]Object, error)
+	// Delete removes a backup.
+	Delete(ctx context.Context, name string) error
+	// Location describes where backups go, for logs and clients.
+	Location() string
+}
+
+// DirStore keeps backups as files in a directory.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore creates a store in dir, which is created on the first backup.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Put writes the backup to a temporary file and renames it into place, so
+// a backup is never seen half written.
+func (s *DirStore) Put(ctx context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// Get opens a backup file.
+func (s *DirStore) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return f, err
+}
+
+// List returns the backup files in the directory. A directory that does
+// not exist yet has none.
+func (s *DirStore) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var objects []Object
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), Extension) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return objects, nil
+}
+
+// Delete removes a backup file.
+func (s *DirStore) Delete(ctx context.Context, name string) error {
+	err := os.Remove(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return err
+}
+
+// Location returns the directory.
+func (s *DirStore) Location() string {
+	return s.dir
+}
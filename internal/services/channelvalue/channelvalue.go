@@ -0,0 +1,171 @@
+// Package channelvalue checks DMX values against the channels they are set
+// on. A continuous channel takes values between its minimum and maximum; a
+// discrete channel, such as a color or gobo wheel, takes values within one
+// of its capabilities.
+package channelvalue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SettingKey is the setting choosing what happens to values out of range.
+const SettingKey = "channel_value_validation"
+
+// Mode is what happens to values out of their channel's range.
+type Mode string
+
+const (
+	// ModeReject refuses the values with an *Error
+	ModeReject Mode = "reject"
+	// ModeClamp replaces each value with the nearest one the channel takes
+	ModeClamp Mode = "clamp"
+	// ModeOff stores values as they are
+	ModeOff Mode = "off"
+)
+
+// DefaultMode applies while the setting is not saved.
+const DefaultMode = ModeReject
+
+// ParseMode parses a saved mode.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ModeReject, ModeClamp, ModeOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("must be %s, %s, or %s", ModeReject, ModeClamp, ModeOff)
+}
+
+// Range is a span of DMX values, inclusive.
+type Range struct {
+	Min int
+	Max int
+}
+
+// Channel is the range of values a channel takes.
+type Channel struct {
+	Name     string
+	Min      int
+	Max      int
+	Discrete bool
+	// Ranges are a discrete channel's capabilities. A discrete channel's
+	// minimum and maximum may be only its first capability's, so without
+	// capabilities it takes any value.
+	Ranges []Range
+}
+
+// Nearest returns the value nearest to value that the channel takes, and
+// whether that is value itself.
+func (c Channel) Nearest(value int) (int, bool) {
+	if !c.Discrete {
+		if c.Min > c.Max {
+			return value, true
+		}
+		switch {
+		case value < c.Min:
+			return c.Min, false
+		case value > c.Max:
+			return c.Max, false
+		}
+		return value, true
+	}
+	if len(c.Ranges) == 0 {
+		return value, true
+	}
+
+	nearest, distance := value, -1
+	for _, r := range c.Ranges {
+		if value >= r.Min && value <= r.Max {
+			return value, true
+		}
+		candidate := r.Min
+		if value > r.Max {
+			candidate = r.Max
+		}
+		if d := abs(value - candidate); distance < 0 || d < distance {
+			nearest, distance = candidate, d
+		}
+	}
+	return nearest, false
+}
+
+// Span returns the lowest and highest values the channel takes.
+func (c Channel) Span() (int, int) {
+	if !c.Discrete || len(c.Ranges) == 0 {
+		return c.Min, c.Max
+	}
+	lo, hi := c.Ranges[0].Min, c.Ranges[0].Max
+	for _, r := range c.Ranges[1:] {
+		lo, hi = min(lo, r.Min), max(hi, r.Max)
+	}
+	return lo, hi
+}
+
+// Violation is a value its channel does not take.
+type Violation struct {
+	FixtureID   string
+	FixtureName string
+	Offset      int
+	Channel     Channel
+	Value       int
+	// Nearest is the value clamping sets instead
+	Nearest int
+}
+
+func (v Violation) String() string {
+	lo, hi := v.Channel.Span()
+	if v.Channel.Discrete {
+		return fmt.Sprintf("%s %s (offset %d) has no range containing %d; nearest is %d",
+			v.FixtureName, v.Channel.Name, v.Offset, v.Value, v.Nearest)
+	}
+	return fmt.Sprintf("%s %s (offset %d) takes %d-%d, not %d",
+		v.FixtureName, v.Channel.Name, v.Offset, lo, hi, v.Value)
+}
+
+// Error reports values out of their channels' ranges.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "channel values out of range: " + strings.Join(parts, "; ")
+}
+
+// Fixture is a fixture's channels by offset.
+type Fixture struct {
+	ID       string
+	Name     string
+	Channels map[int]Channel
+}
+
+// Check checks a value set on the fixture's channel at offset. Offsets
+// without a channel are left to other checks.
+func (f Fixture) Check(offset, value int) (Violation, bool) {
+	channel, ok := f.Channels[offset]
+	if !ok {
+		return Violation{}, true
+	}
+	nearest, ok := channel.Nearest(value)
+	if ok {
+		return Violation{}, true
+	}
+	return Violation{
+		FixtureID:   f.ID,
+		FixtureName: f.Name,
+		Offset:      offset,
+		Channel:     channel,
+		Value:       value,
+		Nearest:     nearest,
+	}, false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
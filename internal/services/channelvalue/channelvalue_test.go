@@ -0,0 +1,87 @@
+package channelvalue
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	for input, want := range map[string]Mode{"reject": ModeReject, " Clamp ": ModeClamp, "OFF": ModeOff} {
+		if mode, err := ParseMode(input); err != nil || mode != want {
+			t.Errorf("ParseMode(%q) = %q, %v, want %q", input, mode, err, want)
+		}
+	}
+	if _, err := ParseMode("round"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	dimmer := Channel{Name: "Dimmer", Min: 0, Max: 255}
+	strobe := Channel{Name: "Strobe", Min: 10, Max: 200}
+	wheel := Channel{Name: "Color", Min: 0, Max: 9, Discrete: true, Ranges: []Range{{0, 9}, {20, 29}, {60, 127}}}
+	// An OFL wheel keeps only its first capability's range
+	unknownWheel := Channel{Name: "Gobo", Min: 0, Max: 7, Discrete: true}
+
+	tests := []struct {
+		name    string
+		channel Channel
+		value   int
+		want    int
+		ok      bool
+	}{
+		{"in range", dimmer, 128, 128, true},
+		{"below minimum", strobe, 5, 10, false},
+		{"above maximum", strobe, 250, 200, false},
+		{"at maximum", strobe, 200, 200, true},
+		{"in a capability", wheel, 25, 25, true},
+		{"between capabilities, nearer the lower", wheel, 12, 9, false},
+		{"between capabilities, nearer the upper", wheel, 50, 60, false},
+		{"past the last capability", wheel, 200, 127, false},
+		{"discrete without capabilities", unknownWheel, 200, 200, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.channel.Nearest(tt.value)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Nearest(%d) = %d, %v, want %d, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSpan(t *testing.T) {
+	wheel := Channel{Min: 0, Max: 9, Discrete: true, Ranges: []Range{{20, 29}, {0, 9}, {60, 127}}}
+	if lo, hi := wheel.Span(); lo != 0 || hi != 127 {
+		t.Errorf("Span() = %d-%d, want 0-127", lo, hi)
+	}
+	if lo, hi := (Channel{Min: 10, Max: 200}).Span(); lo != 10 || hi != 200 {
+		t.Errorf("Span() = %d-%d, want 10-200", lo, hi)
+	}
+}
+
+func TestFixtureCheck(t *testing.T) {
+	fixture := Fixture{ID: "spot", Name: "Spot 1", Channels: map[int]Channel{
+		0: {Name: "Dimmer", Min: 0, Max: 255},
+		1: {Name: "Shutter", Min: 0, Max: 31},
+	}}
+
+	if _, ok := fixture.Check(0, 255); !ok {
+		t.Error("Expected a value in range to pass")
+	}
+	if _, ok := fixture.Check(7, 255); !ok {
+		t.Error("Expected an offset without a channel to be left alone")
+	}
+	violation, ok := fixture.Check(1, 40)
+	if ok {
+		t.Fatal("Expected a value above the maximum to fail")
+	}
+	if violation.FixtureID != "spot" || violation.Offset != 1 || violation.Value != 40 || violation.Nearest != 31 {
+		t.Errorf("Unexpected violation %+v", violation)
+	}
+
+	err := &Error{Violations: []Violation{violation}}
+	if !strings.Contains(err.Error(), "Spot 1 Shutter (offset 1) takes 0-31, not 40") {
+		t.Errorf("Error = %q", err)
+	}
+}
@@ -0,0 +1,307 @@
+// Package color converts abstract colors (hex, HSV, or color temperature)
+// into the channel values of fixtures with different color models.
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color temperature range accepted by FromKelvin.
+const (
+	MinKelvin = 1000
+	MaxKelvin = 40000
+)
+
+// Color temperatures of the warm and cold emitters of tunable white
+// fixtures.
+const (
+	warmWhiteKelvin = 2700
+	coldWhiteKelvin = 6500
+)
+
+// Color is an abstract color as sRGB components (0-1).
+type Color struct {
+	R, G, B float64
+	Kelvin  float64 // Color temperature when given as one, else 0
+}
+
+// Channel is a fixture channel the color is mapped onto.
+type Channel struct {
+	Offset int
+	Type   string // ChannelType enum value
+}
+
+// Value is a channel value (0-255) by offset.
+type Value struct {
+	Offset int
+	Value  int
+}
+
+// ParseHex parses a hex RGB color: "#RRGGBB", "RRGGBB", or "#RGB".
+func ParseHex(hex string) (Color, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	if len(digits) != 6 {
+		return Color{}, fmt.Errorf("invalid hex color %q: want #RRGGBB", hex)
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hex color %q: want #RRGGBB", hex)
+	}
+	return Color{
+		R: float64(value>>16&0xff) / 255,
+		G: float64(value>>8&0xff) / 255,
+		B: float64(value&0xff) / 255,
+	}, nil
+}
+
+// FromHSV converts a hue in degrees (0-360) and saturation and value (0-1).
+func FromHSV(h, s, v float64) (Color, error) {
+	if h < 0 || h > 360 || math.IsNaN(h) {
+		return Color{}, fmt.Errorf("hue must be between 0 and 360, got %v", h)
+	}
+	if s < 0 || s > 1 || math.IsNaN(s) {
+		return Color{}, fmt.Errorf("saturation must be between 0 and 1, got %v", s)
+	}
+	if v < 0 || v > 1 || math.IsNaN(v) {
+		return Color{}, fmt.Errorf("value must be between 0 and 1, got %v", v)
+	}
+
+	sector := math.Mod(h, 360) / 60
+	chroma := v * s
+	x := chroma * (1 - math.Abs(math.Mod(sector, 2)-1))
+	var r, g, b float64
+	switch int(sector) {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	m := v - chroma
+	return Color{R: r + m, G: g + m, B: b + m}, nil
+}
+
+// FromKelvin converts a color temperature to the RGB of a white source at
+// that temperature, at full brightness.
+func FromKelvin(kelvin float64) (Color, error) {
+	if kelvin < MinKelvin || kelvin > MaxKelvin || math.IsNaN(kelvin) {
+		return Color{}, fmt.Errorf("color temperature must be between %d and %d K, got %v", MinKelvin, MaxKelvin, kelvin)
+	}
+
+	// Approximation of the Planckian locus in sRGB (Tanner Helland)
+	t := kelvin / 100
+	var r, g, b float64
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+	return Color{R: unit(r / 255), G: unit(g / 255), B: unit(b / 255), Kelvin: kelvin}, nil
+}
+
+// ChannelValues maps a color onto a fixture's color channels, by the color
+// model the channel types make up:
+//   - RGB, with white and amber emitters taking the share of the color they
+//     can produce; emitters the engine does not model (UV, lime, indigo) go
+//     to 0 so the output matches the color
+//   - CMY, subtracting the color from white
+//   - tunable white (warm and cold white), mixing the color's temperature at
+//     its brightness; a single white emitter takes the brightness
+//
+// Channels of other types, such as intensity, are left out. A fixture without
+// color channels gets no values.
+func (c Color) ChannelValues(channels []Channel) []Value {
+	present := make(map[string]bool, len(channels))
+	for _, ch := range channels {
+		present[ch.Type] = true
+	}
+
+	levels := make(map[string]float64)
+	switch {
+	case present["RED"] || present["GREEN"] || present["BLUE"]:
+		r, g, b := c.R, c.G, c.B
+		if present["WHITE"] || present["WARM_WHITE"] || present["COLD_WHITE"] {
+			w := math.Min(r, math.Min(g, b))
+			r, g, b = r-w, g-w, b-w
+			c.setWhite(levels, present, w)
+		}
+		if present["AMBER"] {
+			a := math.Min(r, 2*g)
+			r, g = r-a, g-a/2
+			levels["AMBER"] = a
+		}
+		levels["RED"], levels["GREEN"], levels["BLUE"] = r, g, b
+		levels["UV"], levels["LIME"], levels["INDIGO"] = 0, 0, 0
+	case present["CYAN"] || present["MAGENTA"] || present["YELLOW"]:
+		levels["CYAN"], levels["MAGENTA"], levels["YELLOW"] = 1-c.R, 1-c.G, 1-c.B
+	case present["WHITE"] || present["WARM_WHITE"] || present["COLD_WHITE"]:
+		c.setWhite(levels, present, math.Max(c.R, math.Max(c.G, c.B)))
+	}
+
+	var values []Value
+	for _, ch := range channels {
+		level, ok := levels[ch.Type]
+		if !ok {
+			continue
+		}
+		values = append(values, Value{Offset: ch.Offset, Value: int(math.Round(unit(level) * 255))})
+	}
+	return values
+}
+
+// setWhite sets the white emitters to a brightness: a plain white emitter
+// takes it all, warm and cold emitters mix the color's temperature.
+func (c Color) setWhite(levels map[string]float64, present map[string]bool, brightness float64) {
+	if present["WHITE"] {
+		levels["WHITE"] = brightness
+	}
+	if !present["WARM_WHITE"] && !present["COLD_WHITE"] {
+		return
+	}
+	if !present["WARM_WHITE"] || !present["COLD_WHITE"] {
+		levels["WARM_WHITE"], levels["COLD_WHITE"] = brightness, brightness
+		return
+	}
+
+	// Interpolate in mireds, which are perceptually even
+	kelvin := c.Kelvin
+	if kelvin == 0 {
+		kelvin = c.temperature()
+	}
+	warm, cold, k := 1e6/warmWhiteKelvin, 1e6/coldWhiteKelvin, 1e6/kelvin
+	coldShare := unit((warm - k) / (warm - cold))
+	scale := brightness / math.Max(coldShare, 1-coldShare)
+	levels["COLD_WHITE"] = coldShare * scale
+	levels["WARM_WHITE"] = (1 - coldShare) * scale
+}
+
+// temperature estimates the correlated color temperature of the color
+// (McCamy's approximation), falling back to neutral for colors without one.
+func (c Color) temperature() float64 {
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+	x := 0.4124*r + 0.3576*g + 0.1805*b
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := 0.0193*r + 0.1192*g + 0.9505*b
+	sum := x + y + z
+	if sum == 0 {
+		return (warmWhiteKelvin + coldWhiteKelvin) / 2
+	}
+	cx, cy := x/sum, y/sum
+	n := (cx - 0.3320) / (0.1858 - cy)
+	kelvin := 449*n*n*n + 3525*n*n + 6823.3*n + 5520.33
+	return math.Max(MinKelvin, math.Min(MaxKelvin, kelvin))
+}
+
+// linear converts an sRGB component to linear light.
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// unit clamps a value to 0-1.
+func unit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// Hex formats the color as "#RRGGBB".
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", byteOf(c.R), byteOf(c.G), byteOf(c.B))
+}
+
+// FromValues estimates the color a fixture shows from its channel values,
+// the reverse of ChannelValues, and its brightness (0-1). The color is scaled
+// so its brightest component is 1. The brightness is the color's level times
+// the intensity channel's, when the values include one; a fixture whose
+// values set only intensity shows white. ok is false when no value is for an
+// intensity or color channel.
+func FromValues(channels []Channel, values []Value) (c Color, brightness float64, ok bool) {
+	types := make(map[int]string, len(channels))
+	for _, ch := range channels {
+		types[ch.Offset] = ch.Type
+	}
+	levels := make(map[string]float64)
+	for _, v := range values {
+		if t, found := types[v.Offset]; found {
+			levels[t] = math.Max(levels[t], unit(float64(v.Value)/255))
+		}
+	}
+	has := func(types ...string) bool {
+		for _, t := range types {
+			if _, found := levels[t]; found {
+				return true
+			}
+		}
+		return false
+	}
+
+	var r, g, b float64
+	switch {
+	case has("RED", "GREEN", "BLUE"):
+		r, g, b = levels["RED"], levels["GREEN"], levels["BLUE"]
+		r, g, b = addWhite(levels, r, g, b)
+		amber := levels["AMBER"]
+		r, g = r+amber, g+amber/2
+		lime := levels["LIME"]
+		r, g = r+lime/2, g+lime
+		violet := math.Max(levels["UV"], levels["INDIGO"])
+		r, b = r+violet/2, b+violet
+	case has("CYAN", "MAGENTA", "YELLOW"):
+		r, g, b = 1-levels["CYAN"], 1-levels["MAGENTA"], 1-levels["YELLOW"]
+	case has("WHITE", "WARM_WHITE", "COLD_WHITE"):
+		r, g, b = addWhite(levels, 0, 0, 0)
+	case has("INTENSITY"):
+		r, g, b = 1, 1, 1
+	default:
+		return Color{}, 0, false
+	}
+
+	peak := math.Max(r, math.Max(g, b))
+	if peak > 0 {
+		c = Color{R: r / peak, G: g / peak, B: b / peak}
+	}
+	brightness = unit(peak)
+	if intensity, found := levels["INTENSITY"]; found {
+		brightness *= intensity
+	}
+	return c, brightness, true
+}
+
+// addWhite adds the light of a fixture's white emitters to a color.
+func addWhite(levels map[string]float64, r, g, b float64) (float64, float64, float64) {
+	w := levels["WHITE"]
+	r, g, b = r+w, g+w, b+w
+	warm, _ := FromKelvin(warmWhiteKelvin)
+	cold, _ := FromKelvin(coldWhiteKelvin)
+	ww, cw := levels["WARM_WHITE"], levels["COLD_WHITE"]
+	return r + warm.R*ww + cold.R*cw, g + warm.G*ww + cold.G*cw, b + warm.B*ww + cold.B*cw
+}
+
+// byteOf converts a component (0-1) to 0-255.
+func byteOf(v float64) int {
+	return int(math.Round(unit(v) * 255))
+}
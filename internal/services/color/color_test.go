@@ -0,0 +1,166 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+// channels builds a fixture's channels from types in offset order.
+func channels(types ...string) []Channel {
+	list := make([]Channel, len(types))
+	for i, t := range types {
+		list[i] = Channel{Offset: i, Type: t}
+	}
+	return list
+}
+
+// expectValues checks channel values by offset.
+func expectValues(t *testing.T, got []Value, want map[int]int) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Values = %+v, want %v", got, want)
+	}
+	for _, v := range got {
+		if w, ok := want[v.Offset]; !ok || v.Value != w {
+			t.Errorf("Offset %d = %d, want %v", v.Offset, v.Value, want)
+		}
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	c, err := ParseHex("#FF8000")
+	if err != nil {
+		t.Fatalf("ParseHex() error: %v", err)
+	}
+	if c.R != 1 || math.Abs(c.G-128.0/255) > 1e-9 || c.B != 0 {
+		t.Errorf("ParseHex(#FF8000) = %+v", c)
+	}
+	if short, _ := ParseHex("0f0"); short.G != 1 || short.R != 0 {
+		t.Errorf("ParseHex(0f0) = %+v, want green", short)
+	}
+	for _, bad := range []string{"", "#12345", "#GGGGGG"} {
+		if _, err := ParseHex(bad); err == nil {
+			t.Errorf("ParseHex(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFromHSV(t *testing.T) {
+	tests := []struct {
+		h, s, v float64
+		want    Color
+	}{
+		{0, 1, 1, Color{R: 1}},
+		{120, 1, 1, Color{G: 1}},
+		{240, 1, 0.5, Color{B: 0.5}},
+		{360, 0, 1, Color{R: 1, G: 1, B: 1}},
+	}
+	for _, tt := range tests {
+		got, err := FromHSV(tt.h, tt.s, tt.v)
+		if err != nil {
+			t.Fatalf("FromHSV(%v, %v, %v) error: %v", tt.h, tt.s, tt.v, err)
+		}
+		if math.Abs(got.R-tt.want.R) > 1e-9 || math.Abs(got.G-tt.want.G) > 1e-9 || math.Abs(got.B-tt.want.B) > 1e-9 {
+			t.Errorf("FromHSV(%v, %v, %v) = %+v, want %+v", tt.h, tt.s, tt.v, got, tt.want)
+		}
+	}
+	if _, err := FromHSV(400, 1, 1); err == nil {
+		t.Error("FromHSV with hue 400 should fail")
+	}
+}
+
+func TestFromKelvin(t *testing.T) {
+	warm, err := FromKelvin(2700)
+	if err != nil {
+		t.Fatalf("FromKelvin() error: %v", err)
+	}
+	if warm.R != 1 || warm.B >= warm.G || warm.Kelvin != 2700 {
+		t.Errorf("FromKelvin(2700) = %+v, want a warm full-red color", warm)
+	}
+	if daylight, _ := FromKelvin(6600); daylight.R < 0.99 || daylight.B < 0.99 {
+		t.Errorf("FromKelvin(6600) = %+v, want near white", daylight)
+	}
+	if _, err := FromKelvin(500); err == nil {
+		t.Error("FromKelvin(500) should fail")
+	}
+}
+
+func TestChannelValues(t *testing.T) {
+	orange := Color{R: 1, G: 0.5, B: 0}
+	pink := Color{R: 1, G: 0.5, B: 0.5}
+
+	// Intensity is left out; RGB takes the color as is
+	expectValues(t, orange.ChannelValues(channels("INTENSITY", "RED", "GREEN", "BLUE")),
+		map[int]int{1: 255, 2: 128, 3: 0})
+
+	// White takes the part of the color all three share
+	expectValues(t, pink.ChannelValues(channels("RED", "GREEN", "BLUE", "WHITE")),
+		map[int]int{0: 128, 1: 0, 2: 0, 3: 128})
+
+	// Amber takes what it can of a warm color; UV goes out
+	expectValues(t, orange.ChannelValues(channels("RED", "GREEN", "BLUE", "AMBER", "UV")),
+		map[int]int{0: 0, 1: 0, 2: 0, 3: 255, 4: 0})
+
+	// CMY subtracts the color from white
+	expectValues(t, orange.ChannelValues(channels("CYAN", "MAGENTA", "YELLOW")),
+		map[int]int{0: 0, 1: 128, 2: 255})
+
+	// Tunable white mixes the color temperature
+	warm, _ := FromKelvin(2700)
+	expectValues(t, warm.ChannelValues(channels("WARM_WHITE", "COLD_WHITE")), map[int]int{0: 255, 1: 0})
+	neutral, _ := FromKelvin(4000)
+	values := neutral.ChannelValues(channels("WARM_WHITE", "COLD_WHITE"))
+	if len(values) != 2 || values[0].Value == 0 || values[1].Value == 0 {
+		t.Errorf("4000K on tunable white = %+v, want both emitters on", values)
+	}
+
+	if values := orange.ChannelValues(channels("INTENSITY", "PAN")); len(values) != 0 {
+		t.Errorf("Values for a fixture without color channels = %+v, want none", values)
+	}
+}
+
+func TestHex(t *testing.T) {
+	if hex := (Color{R: 1, G: 0.5, B: 0}).Hex(); hex != "#FF8000" {
+		t.Errorf("Hex() = %q, want #FF8000", hex)
+	}
+	if hex := (Color{R: 2, G: -1}).Hex(); hex != "#FF0000" {
+		t.Errorf("Hex() of an out-of-range color = %q, want #FF0000", hex)
+	}
+}
+
+func TestFromValues(t *testing.T) {
+	// RGB at half intensity
+	c, brightness, ok := FromValues(channels("INTENSITY", "RED", "GREEN", "BLUE"),
+		[]Value{{0, 128}, {1, 255}, {2, 128}, {3, 0}})
+	if !ok || c.Hex() != "#FF8000" || math.Abs(brightness-128.0/255) > 1e-9 {
+		t.Errorf("FromValues(RGB) = %s, %v, %v", c.Hex(), brightness, ok)
+	}
+
+	// The values ChannelValues gives back the color
+	pink := Color{R: 1, G: 0.5, B: 0.5}
+	rgbw := channels("RED", "GREEN", "BLUE", "WHITE")
+	if c, brightness, _ := FromValues(rgbw, pink.ChannelValues(rgbw)); c.Hex() != "#FF8080" || brightness != 1 {
+		t.Errorf("FromValues(RGBW pink) = %s, %v", c.Hex(), brightness)
+	}
+
+	// CMY subtracts from white
+	if c, _, _ := FromValues(channels("CYAN", "MAGENTA", "YELLOW"), []Value{{0, 0}, {1, 255}, {2, 255}}); c.Hex() != "#FF0000" {
+		t.Errorf("FromValues(CMY) = %s, want red", c.Hex())
+	}
+
+	// Warm white is warm; a dimmer shows white
+	if c, _, _ := FromValues(channels("WARM_WHITE", "COLD_WHITE"), []Value{{0, 255}, {1, 0}}); c.B >= c.R {
+		t.Errorf("FromValues(warm white) = %+v, want warm", c)
+	}
+	if c, brightness, ok := FromValues(channels("INTENSITY"), []Value{{0, 255}}); !ok || c.Hex() != "#FFFFFF" || brightness != 1 {
+		t.Errorf("FromValues(dimmer) = %s, %v, %v", c.Hex(), brightness, ok)
+	}
+
+	// Dark, and without light at all
+	if _, brightness, ok := FromValues(channels("RED", "GREEN", "BLUE"), []Value{{0, 0}}); !ok || brightness != 0 {
+		t.Errorf("FromValues(off) brightness = %v, %v", brightness, ok)
+	}
+	if _, _, ok := FromValues(channels("PAN", "TILT"), []Value{{0, 128}}); ok {
+		t.Error("FromValues(pan) should not be ok")
+	}
+}
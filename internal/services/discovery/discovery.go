@@ -0,0 +1,716 @@
+// Package discovery advertises the server on the local network by multicast
+// DNS, as a _lacylights._tcp and an _http._tcp service, so clients such as
+// tablets find it without an address being typed in. It also listens for
+// other LacyLights servers advertising the same way.
+//
+// The TXT records carry the server's version and project information. They
+// are built again each time the server is advertised, and announced again
+// when they change.
+//
+// Two servers with the same instance name, as Pis with the default hostname
+// would have, are told apart by renaming: a server that hears another host
+// claim its name advertises as "name (2)" on host "name-2", and so on.
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/network"
+	"github.com/bbernstein/lacylights-go/pkg/mdns"
+)
+
+// Service types the server advertises.
+const (
+	ServiceType     = "_lacylights._tcp"
+	HTTPServiceType = "_http._tcp"
+)
+
+const (
+	domain = "local"
+	// servicesName lists the service types on the link (RFC 6763 section 9)
+	servicesName = "_services._dns-sd._udp.local"
+
+	// hostTTL applies to records that name a host or its addresses, and
+	// serviceTTL to the rest, as RFC 6762 recommends
+	hostTTL    = 120
+	serviceTTL = 4500
+	// legacyTTL caps the TTL of answers to one-shot queries
+	legacyTTL = 10
+
+	// announceInterval spaces the first announcements
+	announceInterval = time.Second
+	// refreshInterval is how often the TXT records are checked for changes
+	refreshInterval = time.Minute
+	// maxPacket bounds a received message
+	maxPacket = 9000
+)
+
+// DefaultBrowseTimeout is how long Browse waits for answers.
+const DefaultBrowseTimeout = time.Second
+
+// Config configures advertisement.
+type Config struct {
+	// Name is the instance name shown to clients (default: the hostname)
+	Name string
+	// Port is the HTTP port the server listens on
+	Port int
+	// Scheme is http or https, advertised as the scheme TXT key when set
+	Scheme string
+	// Info returns the key-value pairs of the TXT records (optional)
+	Info func() map[string]string
+}
+
+// Peer is another LacyLights server found on the local network.
+type Peer struct {
+	// Name is the instance name, without the service type
+	Name      string
+	Host      string
+	Port      int
+	Addresses []net.IP
+	// Info holds the TXT record's key-value pairs
+	Info     map[string]string
+	LastSeen time.Time
+}
+
+// Status describes advertisement.
+type Status struct {
+	Running bool
+	// Name and Host are as advertised, after any rename
+	Name string
+	Host string
+	Port int
+}
+
+// peerState is what has been heard of a peer instance.
+type peerState struct {
+	name     string
+	host     string
+	port     int
+	info     map[string]string
+	lastSeen time.Time
+	expires  time.Time
+}
+
+// hostState is what has been heard of a peer's host.
+type hostState struct {
+	addresses []net.IP
+	updated   time.Time
+	expires   time.Time
+}
+
+// Service advertises the server and tracks peers.
+type Service struct {
+	mu sync.Mutex
+
+	baseName string
+	baseHost string
+	port     int
+	scheme   string
+	info     func() map[string]string
+
+	// rename counts the renames made to resolve conflicts
+	rename int
+	// txt is the TXT record last announced
+	txt []string
+
+	conn     *net.UDPConn
+	running  bool
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+
+	// Peers by lower-case service instance name, and their hosts by
+	// lower-case host name
+	peers map[string]*peerState
+	hosts map[string]*hostState
+}
+
+// NewService creates a discovery service.
+func NewService(cfg Config) *Service {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "lacylights"
+	}
+	// Drop any domain from the hostname
+	hostname, _, _ = strings.Cut(hostname, ".")
+
+	name := cfg.Name
+	if name == "" {
+		name = hostname
+	}
+	return &Service{
+		baseName: strings.ReplaceAll(name, ".", " "),
+		baseHost: hostLabel(hostname),
+		port:     cfg.Port,
+		scheme:   cfg.Scheme,
+		info:     cfg.Info,
+		peers:    make(map[string]*peerState),
+		hosts:    make(map[string]*hostState),
+	}
+}
+
+// hostLabel makes a hostname into a DNS label of letters, digits, and dashes.
+func hostLabel(name string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '-'
+	}, name)
+	label = strings.Trim(label, "-")
+	if label == "" {
+		return "lacylights"
+	}
+	return label
+}
+
+// Start joins the mDNS group, announces the server, and answers queries.
+func (s *Service) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return nil
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdns.GroupAddr)
+	if err != nil {
+		return fmt.Errorf("failed to join the mDNS group: %w", err)
+	}
+	s.conn = conn
+	s.running = true
+	s.stopChan = make(chan struct{})
+
+	s.wg.Add(2)
+	go s.readLoop(conn, s.stopChan)
+	go s.announceLoop(s.stopChan)
+
+	log.Info("📣 Advertising by mDNS", "name", s.nameLocked(), "host", s.hostLocked(), "port", s.port)
+	return nil
+}
+
+// Cleanup withdraws the advertisement and stops listening.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stopChan)
+	// Goodbye packets tell peers to forget the server now
+	s.sendLocked(s.announcementLocked(0), mdns.GroupAddr)
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+
+	_ = conn.Close()
+	s.wg.Wait()
+}
+
+// Status returns the advertised name and whether advertisement is running.
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Status{Running: s.running, Name: s.nameLocked(), Host: s.hostLocked(), Port: s.port}
+}
+
+// ServiceTypes returns the advertised service types.
+func ServiceTypes() []string {
+	return []string{ServiceType, HTTPServiceType}
+}
+
+// Browse asks for LacyLights servers, waits for answers, and returns the
+// peers known then.
+func (s *Service) Browse(timeout time.Duration) ([]Peer, error) {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil, errors.New("mDNS discovery is not running")
+	}
+	query := &mdns.Message{Questions: []mdns.Question{{Name: serviceName(ServiceType), Type: mdns.TypePTR}}}
+	s.sendLocked(query, mdns.GroupAddr)
+	stop := s.stopChan
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(timeout):
+	case <-stop:
+	}
+	return s.Peers(), nil
+}
+
+// Peers returns the LacyLights servers heard from whose records have not
+// expired, by name.
+func (s *Service) Peers() []Peer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	self := strings.ToLower(s.instanceLocked(ServiceType))
+	var peers []Peer
+	for key, state := range s.peers {
+		if now.After(state.expires) {
+			delete(s.peers, key)
+			continue
+		}
+		if key == self || state.host == "" {
+			continue
+		}
+		peer := Peer{
+			Name:     state.name,
+			Host:     state.host,
+			Port:     state.port,
+			Info:     state.info,
+			LastSeen: state.lastSeen,
+		}
+		if host := s.hosts[strings.ToLower(state.host)]; host != nil && !now.After(host.expires) {
+			peer.Addresses = append(peer.Addresses, host.addresses...)
+		}
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+	return peers
+}
+
+func (s *Service) readLoop(conn *net.UDPConn, stop <-chan struct{}) {
+	defer s.wg.Done()
+	buffer := make([]byte, maxPacket)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Debug("mDNS read failed", "error", err)
+			continue
+		}
+		message, err := mdns.Parse(buffer[:n])
+		if err != nil {
+			log.Debug("invalid mDNS message", "from", from, "error", err)
+			continue
+		}
+		s.mu.Lock()
+		if s.running {
+			s.handleLocked(message, from, time.Now())
+		}
+		s.mu.Unlock()
+	}
+}
+
+// announceLoop announces the server twice a second apart, as RFC 6762
+// asks, then again whenever its TXT records change.
+func (s *Service) announceLoop(stop <-chan struct{}) {
+	defer s.wg.Done()
+	for i := 0; i < 2; i++ {
+		s.mu.Lock()
+		s.sendLocked(s.announcementLocked(serviceTTL), mdns.GroupAddr)
+		s.mu.Unlock()
+		select {
+		case <-time.After(announceInterval):
+		case <-stop:
+			return
+		}
+	}
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			if txt := s.buildTXT(); !equalText(txt, s.txt) {
+				s.sendLocked(s.announcementLocked(serviceTTL), mdns.GroupAddr)
+			}
+			s.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// handleLocked answers a query or learns from a response.
+func (s *Service) handleLocked(m *mdns.Message, from *net.UDPAddr, now time.Time) {
+	if m.Response {
+		s.learnLocked(m, from, now)
+		return
+	}
+
+	answer := s.answerLocked(m)
+	if answer == nil {
+		return
+	}
+	switch {
+	case from.Port != mdns.Port:
+		// A one-shot query from an ordinary resolver gets a unicast DNS
+		// answer, with its ID and questions and short TTLs
+		answer.ID = m.ID
+		answer.Questions = m.Questions
+		for _, records := range [][]mdns.Record{answer.Answers, answer.Additional} {
+			for i := range records {
+				records[i].TTL = min(records[i].TTL, legacyTTL)
+				records[i].CacheFlush = false
+			}
+		}
+		s.sendLocked(answer, from)
+	case allUnicast(m.Questions):
+		s.sendLocked(answer, from)
+	default:
+		s.sendLocked(answer, mdns.GroupAddr)
+	}
+}
+
+func allUnicast(questions []mdns.Question) bool {
+	for _, q := range questions {
+		if !q.UnicastResponse {
+			return false
+		}
+	}
+	return len(questions) > 0
+}
+
+// answerLocked builds the answer to a query, or nil if it asks for nothing
+// the server advertises.
+func (s *Service) answerLocked(m *mdns.Message) *mdns.Message {
+	answer := &mdns.Message{Response: true}
+	hostName := s.hostLocked()
+	wantHost := false
+	for _, q := range m.Questions {
+		for _, serviceType := range ServiceTypes() {
+			instance := s.instanceLocked(serviceType)
+			switch {
+			case mdns.SameName(q.Name, servicesName) && matches(q.Type, mdns.TypePTR):
+				answer.Answers = append(answer.Answers, mdns.Record{
+					Name: servicesName, Type: mdns.TypePTR, TTL: serviceTTL, Target: serviceName(serviceType),
+				})
+			case mdns.SameName(q.Name, serviceName(serviceType)) && matches(q.Type, mdns.TypePTR):
+				answer.Answers = append(answer.Answers, s.pointerLocked(serviceType, serviceTTL))
+				answer.Additional = append(answer.Additional, s.instanceRecordsLocked(serviceType, serviceTTL)...)
+				wantHost = true
+			case mdns.SameName(q.Name, instance):
+				for _, r := range s.instanceRecordsLocked(serviceType, serviceTTL) {
+					if matches(q.Type, r.Type) {
+						answer.Answers = append(answer.Answers, r)
+					}
+				}
+				wantHost = true
+			}
+		}
+		if mdns.SameName(q.Name, hostName) && matches(q.Type, mdns.TypeA) {
+			answer.Answers = append(answer.Answers, s.addressRecords(hostName, hostTTL)...)
+		}
+	}
+	if len(answer.Answers) == 0 {
+		return nil
+	}
+	if wantHost {
+		answer.Additional = append(answer.Additional, s.addressRecords(hostName, hostTTL)...)
+	}
+	return answer
+}
+
+func matches(asked, recordType uint16) bool {
+	return asked == recordType || asked == mdns.TypeANY
+}
+
+// announcementLocked lists every record the server advertises, with the
+// given TTL; zero withdraws them.
+func (s *Service) announcementLocked(ttl uint32) *mdns.Message {
+	announcement := &mdns.Message{Response: true}
+	for _, serviceType := range ServiceTypes() {
+		announcement.Answers = append(announcement.Answers, s.pointerLocked(serviceType, ttl))
+		announcement.Answers = append(announcement.Answers, s.instanceRecordsLocked(serviceType, ttl)...)
+	}
+	hostTTLOrZero := uint32(hostTTL)
+	if ttl == 0 {
+		hostTTLOrZero = 0
+	}
+	announcement.Answers = append(announcement.Answers, s.addressRecords(s.hostLocked(), hostTTLOrZero)...)
+	return announcement
+}
+
+// pointerLocked is the PTR record from a service type to the instance.
+func (s *Service) pointerLocked(serviceType string, ttl uint32) mdns.Record {
+	return mdns.Record{Name: serviceName(serviceType), Type: mdns.TypePTR, TTL: ttl, Target: s.instanceLocked(serviceType)}
+}
+
+// instanceRecordsLocked are the SRV and TXT records of the instance.
+func (s *Service) instanceRecordsLocked(serviceType string, ttl uint32) []mdns.Record {
+	instance := s.instanceLocked(serviceType)
+	srvTTL := min(ttl, hostTTL)
+	s.txt = s.buildTXT()
+	return []mdns.Record{
+		{Name: instance, Type: mdns.TypeSRV, TTL: srvTTL, CacheFlush: true, Port: uint16(s.port), Target: s.hostLocked()},
+		{Name: instance, Type: mdns.TypeTXT, TTL: ttl, CacheFlush: true, Text: s.txt},
+	}
+}
+
+// addressRecords are the A records of the server's IPv4 addresses. Loopback
+// is only advertised when there is nothing else.
+func (s *Service) addressRecords(hostName string, ttl uint32) []mdns.Record {
+	addresses, err := network.GetLocalAddresses()
+	if err != nil {
+		log.Debug("cannot list addresses to advertise", "error", err)
+		return nil
+	}
+	var records []mdns.Record
+	for _, a := range addresses {
+		if a.InterfaceType == "localhost" && len(records) > 0 {
+			continue
+		}
+		records = append(records, mdns.Record{
+			Name: hostName, Type: mdns.TypeA, TTL: ttl, CacheFlush: true, IP: net.ParseIP(a.Address),
+		})
+	}
+	return records
+}
+
+// buildTXT encodes the server's info as key=value strings in key order.
+func (s *Service) buildTXT() []string {
+	var info map[string]string
+	if s.info != nil {
+		info = s.info()
+	}
+	if info == nil {
+		info = make(map[string]string)
+	}
+	if s.scheme != "" {
+		info["scheme"] = s.scheme
+	}
+	if len(info) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(info))
+	for key := range info {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	txt := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entry := key + "=" + info[key]
+		if len(entry) > 255 {
+			entry = entry[:255]
+		}
+		txt = append(txt, entry)
+	}
+	return txt
+}
+
+func equalText(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// learnLocked records the LacyLights servers a response describes, and
+// renames the server if another host claims its name.
+func (s *Service) learnLocked(m *mdns.Message, from *net.UDPAddr, now time.Time) {
+	records := append(append([]mdns.Record(nil), m.Answers...), m.Additional...)
+	if s.conflictLocked(records, from) {
+		s.renameLocked()
+		return
+	}
+
+	for _, r := range records {
+		switch r.Type {
+		case mdns.TypePTR:
+			if mdns.SameName(r.Name, serviceName(ServiceType)) {
+				s.peerLocked(r.Target, r.TTL, now)
+			}
+		case mdns.TypeSRV, mdns.TypeTXT:
+			peer := s.peerLocked(r.Name, r.TTL, now)
+			if peer == nil {
+				continue
+			}
+			if r.Type == mdns.TypeSRV {
+				peer.host = strings.TrimSuffix(r.Target, ".")
+				peer.port = int(r.Port)
+			} else {
+				peer.info = parseTXT(r.Text)
+			}
+		case mdns.TypeA:
+			s.hostAddressLocked(r, now)
+		}
+	}
+}
+
+// hostAddressLocked records a host's address from an A record. A record
+// with the cache-flush bit replaces the addresses heard before this second,
+// since the rest of its set arrives in the same message.
+func (s *Service) hostAddressLocked(r mdns.Record, now time.Time) {
+	host := strings.ToLower(strings.TrimSuffix(r.Name, "."))
+	if r.TTL == 0 {
+		delete(s.hosts, host)
+		return
+	}
+	state := s.hosts[host]
+	if state == nil || (r.CacheFlush && now.Sub(state.updated) > time.Second) {
+		state = &hostState{}
+		s.hosts[host] = state
+	}
+	state.addresses = appendNew(state.addresses, r.IP)
+	state.updated = now
+	state.expires = now.Add(time.Duration(r.TTL) * time.Second)
+}
+
+// peerLocked returns the peer with a service instance name, creating it, or
+// removes it and returns nil for a goodbye.
+func (s *Service) peerLocked(instance string, ttl uint32, now time.Time) *peerState {
+	instance = strings.TrimSuffix(instance, ".")
+	key := strings.ToLower(instance)
+	suffix := "." + serviceName(ServiceType)
+	if !strings.HasSuffix(key, suffix) || len(key) == len(suffix) {
+		return nil
+	}
+	if ttl == 0 {
+		delete(s.peers, key)
+		return nil
+	}
+	peer := s.peers[key]
+	if peer == nil {
+		name := instance[:len(instance)-len(suffix)]
+		peer = &peerState{name: name}
+		s.peers[key] = peer
+	}
+	peer.lastSeen = now
+	if expires := now.Add(time.Duration(ttl) * time.Second); expires.After(peer.expires) {
+		peer.expires = expires
+	}
+	return peer
+}
+
+// conflictLocked reports whether a response from another host claims the
+// server's instance or host name.
+func (s *Service) conflictLocked(records []mdns.Record, from *net.UDPAddr) bool {
+	if from == nil || isLocal(from.IP) {
+		return false
+	}
+	hostName := s.hostLocked()
+	for _, r := range records {
+		if r.TTL == 0 {
+			continue
+		}
+		switch {
+		case r.Type == mdns.TypeSRV && mdns.SameName(r.Name, s.instanceLocked(ServiceType)):
+			return true
+		case r.Type == mdns.TypeA && mdns.SameName(r.Name, hostName):
+			return true
+		}
+	}
+	return false
+}
+
+// renameLocked withdraws the advertised names and announces new ones.
+func (s *Service) renameLocked() {
+	old := s.nameLocked()
+	s.sendLocked(s.announcementLocked(0), mdns.GroupAddr)
+	s.rename++
+	log.Warn("mDNS name in use by another host, renamed", "from", old, "to", s.nameLocked(), "host", s.hostLocked())
+	s.sendLocked(s.announcementLocked(serviceTTL), mdns.GroupAddr)
+}
+
+// nameLocked is the instance name, with a number after a rename.
+func (s *Service) nameLocked() string {
+	if s.rename == 0 {
+		return s.baseName
+	}
+	return fmt.Sprintf("%s (%d)", s.baseName, s.rename+1)
+}
+
+// hostLocked is the host name, with a number after a rename.
+func (s *Service) hostLocked() string {
+	if s.rename == 0 {
+		return s.baseHost + "." + domain
+	}
+	return fmt.Sprintf("%s-%d.%s", s.baseHost, s.rename+1, domain)
+}
+
+// instanceLocked is the service instance name for a service type.
+func (s *Service) instanceLocked(serviceType string) string {
+	return s.nameLocked() + "." + serviceName(serviceType)
+}
+
+// serviceName is the full name of a service type.
+func serviceName(serviceType string) string {
+	return serviceType + "." + domain
+}
+
+// sendLocked sends a message, logging a failure.
+func (s *Service) sendLocked(m *mdns.Message, to *net.UDPAddr) {
+	if s.conn == nil {
+		return
+	}
+	packet, err := m.Pack()
+	if err != nil {
+		log.Warn("cannot encode mDNS message", "error", err)
+		return
+	}
+	if _, err := s.conn.WriteToUDP(packet, to); err != nil {
+		log.Debug("mDNS send failed", "to", to, "error", err)
+	}
+}
+
+// parseTXT splits key=value strings. A string without "=" is a key with an
+// empty value.
+func parseTXT(text []string) map[string]string {
+	info := make(map[string]string, len(text))
+	for _, entry := range text {
+		key, value, _ := strings.Cut(entry, "=")
+		if key != "" {
+			info[strings.ToLower(key)] = value
+		}
+	}
+	return info
+}
+
+// appendNew appends the addresses not already in list.
+func appendNew(list []net.IP, addresses ...net.IP) []net.IP {
+	for _, ip := range addresses {
+		found := false
+		for _, existing := range list {
+			if existing.Equal(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, ip)
+		}
+	}
+	return list
+}
+
+// isLocal reports whether an address is one of this machine's.
+func isLocal(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	addresses, err := network.GetLocalAddresses()
+	if err != nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a.Address == ip.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/mdns"
+)
+
+func newTestService(name string, port int) *Service {
+	return NewService(Config{
+		Name: name,
+		Port: port,
+		Info: func() map[string]string {
+			return map[string]string{"version": "1.4.0", "projects": "2"}
+		},
+	})
+}
+
+// roundTrip encodes and decodes a message, as sending it would.
+func roundTrip(t *testing.T, m *mdns.Message) *mdns.Message {
+	t.Helper()
+	packet, err := m.Pack()
+	if err != nil {
+		t.Fatalf("Pack() error: %v", err)
+	}
+	parsed, err := mdns.Parse(packet)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	return parsed
+}
+
+func TestAnswer(t *testing.T) {
+	service := newTestService("Booth", 4000)
+
+	query := &mdns.Message{Questions: []mdns.Question{{Name: "_lacylights._tcp.local", Type: mdns.TypePTR}}}
+	answer := service.answerLocked(query)
+	if answer == nil || len(answer.Answers) != 1 {
+		t.Fatalf("answerLocked() = %+v, want one PTR answer", answer)
+	}
+	if ptr := answer.Answers[0]; ptr.Target != "Booth._lacylights._tcp.local" {
+		t.Errorf("PTR = %+v", ptr)
+	}
+
+	var srv, txt, a bool
+	for _, r := range roundTrip(t, answer).Additional {
+		switch r.Type {
+		case mdns.TypeSRV:
+			srv = r.Port == 4000 && r.Target == service.hostLocked()
+		case mdns.TypeTXT:
+			txt = len(r.Text) == 2 && r.Text[0] == "projects=2" && r.Text[1] == "version=1.4.0"
+		case mdns.TypeA:
+			a = true
+		}
+	}
+	if !srv || !txt || !a {
+		t.Errorf("Additional records incomplete: srv=%v txt=%v a=%v", srv, txt, a)
+	}
+
+	// The HTTP service and the service type list are answered too
+	httpQuery := &mdns.Message{Questions: []mdns.Question{{Name: "_http._tcp.local", Type: mdns.TypeANY}}}
+	if answer := service.answerLocked(httpQuery); answer == nil || answer.Answers[0].Target != "Booth._http._tcp.local" {
+		t.Errorf("answerLocked(_http._tcp) = %+v", answer)
+	}
+	typesQuery := &mdns.Message{Questions: []mdns.Question{{Name: servicesName, Type: mdns.TypePTR}}}
+	if answer := service.answerLocked(typesQuery); answer == nil || len(answer.Answers) != 2 {
+		t.Errorf("answerLocked(%s) = %+v", servicesName, answer)
+	}
+
+	other := &mdns.Message{Questions: []mdns.Question{{Name: "_ipp._tcp.local", Type: mdns.TypePTR}}}
+	if answer := service.answerLocked(other); answer != nil {
+		t.Errorf("answerLocked(_ipp._tcp) = %+v, want no answer", answer)
+	}
+}
+
+func TestLearnPeers(t *testing.T) {
+	booth := newTestService("Booth", 4000)
+	stage := newTestService("Stage", 4001)
+	now := time.Now()
+
+	stage.learnLocked(roundTrip(t, booth.announcementLocked(serviceTTL)), nil, now)
+	peers := stage.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("Peers() = %+v, want the booth", peers)
+	}
+	peer := peers[0]
+	if peer.Name != "Booth" || peer.Port != 4000 || peer.Host != booth.hostLocked() {
+		t.Errorf("Peer = %+v", peer)
+	}
+	if peer.Info["version"] != "1.4.0" || peer.Info["projects"] != "2" {
+		t.Errorf("Peer info = %v", peer.Info)
+	}
+	if len(peer.Addresses) == 0 {
+		t.Error("Expected the peer's addresses")
+	}
+
+	// A server does not list itself
+	booth.learnLocked(roundTrip(t, booth.announcementLocked(serviceTTL)), nil, now)
+	if peers := booth.Peers(); len(peers) != 0 {
+		t.Errorf("Peers() = %+v, want none", peers)
+	}
+
+	// A goodbye removes the peer
+	stage.learnLocked(roundTrip(t, booth.announcementLocked(0)), nil, now)
+	if peers := stage.Peers(); len(peers) != 0 {
+		t.Errorf("Peers() after goodbye = %+v, want none", peers)
+	}
+}
+
+func TestRenameOnConflict(t *testing.T) {
+	ours := newTestService("Console", 4000)
+	theirs := newTestService("Console", 4000)
+
+	// Our own announcements looping back are not a conflict
+	ours.learnLocked(theirs.announcementLocked(serviceTTL), &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: mdns.Port}, time.Now())
+	if status := ours.Status(); status.Name != "Console" {
+		t.Fatalf("Status() = %+v, want no rename", status)
+	}
+
+	ours.learnLocked(theirs.announcementLocked(serviceTTL), &net.UDPAddr{IP: net.ParseIP("203.0.113.5"), Port: mdns.Port}, time.Now())
+	status := ours.Status()
+	if status.Name != "Console (2)" || status.Host != theirs.baseHost+"-2.local" {
+		t.Errorf("Status() = %+v, want renamed", status)
+	}
+}
+
+func TestHostLabel(t *testing.T) {
+	tests := map[string]string{
+		"lacylights":   "lacylights",
+		"Booth_Pi 2":   "Booth-Pi-2",
+		"--":           "lacylights",
+		"stage-left-1": "stage-left-1",
+	}
+	for name, want := range tests {
+		if got := hostLabel(name); got != want {
+			t.Errorf("hostLabel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuildTXT_Scheme(t *testing.T) {
+	service := NewService(Config{Name: "Booth", Port: 443, Scheme: "https"})
+	if txt := service.buildTXT(); len(txt) != 1 || txt[0] != "scheme=https" {
+		t.Errorf("buildTXT() = %v, want the scheme alone", txt)
+	}
+
+	service = newTestService("Booth", 4000)
+	service.scheme = "https"
+	if txt := service.buildTXT(); len(txt) != 3 || txt[1] != "scheme=https" {
+		t.Errorf("buildTXT() = %v, want the scheme among the info", txt)
+	}
+}
@@ -0,0 +1,5 @@
+package discovery
+
+import "github.com/bbernstein/lacylights-go/internal/services/logging"
+
+var log = logging.For(logging.ModuleDiscovery)
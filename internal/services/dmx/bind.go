@@ -0,0 +1,103 @@
+package dmx
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/bbernstein/lacylights-go/internal/services/network"
+)
+
+// BindSettingKey is the setting that stores the interface or address Art-Net
+// output is sent from, overriding the configured one.
+const BindSettingKey = "artnet_bind"
+
+// Binding is the local address Art-Net output is sent from.
+type Binding struct {
+	// Bind is the interface name or IPv4 address asked for; empty leaves the
+	// choice to the operating system
+	Bind string
+	// Address is the address the sockets are bound to, nil when unbound
+	Address net.IP
+	// Interface is the name of the interface with Address
+	Interface string
+}
+
+// Binding returns the local address Art-Net output is sent from.
+func (s *Service) Binding() Binding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.binding
+}
+
+// SetBinding sends Art-Net output from an interface, named or by one of its
+// IPv4 addresses, reopening the sockets. An empty bind restores the
+// configured one. On error the previous binding stays in place.
+func (s *Service) SetBinding(bind string) error {
+	if bind == "" {
+		bind = s.configuredBind
+	}
+	binding, err := resolveBinding(bind)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Open the new sockets before closing the old ones, so a failure
+	// leaves output as it was
+	var conn, unicastConn *net.UDPConn
+	if s.conn != nil {
+		conn, err = net.DialUDP("udp4", dialAddr(binding.Address), s.addr)
+		if err != nil {
+			return fmt.Errorf("failed to bind Art-Net output to %s: %w", binding.Address, err)
+		}
+	}
+	if s.unicastConn != nil {
+		unicastConn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: binding.Address})
+		if err != nil {
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return fmt.Errorf("failed to bind unicast socket to %s: %w", binding.Address, err)
+		}
+	}
+	if conn != nil {
+		_ = s.conn.Close()
+		s.conn = conn
+	}
+	if unicastConn != nil {
+		_ = s.unicastConn.Close()
+		s.unicastConn = unicastConn
+	}
+	s.binding = binding
+
+	if binding.Address != nil {
+		log.Info("📡 Art-Net output bound", "interface", binding.Interface, "address", binding.Address.String())
+	} else {
+		log.Info("📡 Art-Net output unbound, the system chooses the interface")
+	}
+	s.resendAllLocked()
+	return nil
+}
+
+// resolveBinding finds the address an interface name or IPv4 address binds
+// to.
+func resolveBinding(bind string) (Binding, error) {
+	if bind == "" {
+		return Binding{}, nil
+	}
+	ip, iface, err := network.ResolveLocalAddress(bind)
+	if err != nil {
+		return Binding{}, err
+	}
+	return Binding{Bind: bind, Address: ip, Interface: iface}, nil
+}
+
+// dialAddr is the local address for the broadcast socket, nil when unbound.
+func dialAddr(ip net.IP) *net.UDPAddr {
+	if ip == nil {
+		return nil
+	}
+	return &net.UDPAddr{IP: ip}
+}
@@ -0,0 +1,84 @@
+package dmx
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSetBinding(t *testing.T) {
+	port := 6597
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: port, Bind: "127.0.0.1"})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	binding := service.Binding()
+	if !binding.Address.Equal(net.ParseIP("127.0.0.1")) || binding.Interface == "" {
+		t.Fatalf("Binding() = %+v, want the configured loopback address", binding)
+	}
+	if err := service.SetUnicastRoutes([]UnicastRoute{
+		{Universe: 2, Destinations: []string{"127.0.0.1:6598"}},
+	}); err != nil {
+		t.Fatalf("SetUnicastRoutes() error: %v", err)
+	}
+	if local := service.unicastConn.LocalAddr().(*net.UDPAddr); !local.IP.Equal(binding.Address) {
+		t.Errorf("Unicast socket bound to %s, want %s", local.IP, binding.Address)
+	}
+
+	// A bind that does not resolve leaves the sockets as they were
+	if err := service.SetBinding("203.0.113.7"); err == nil {
+		t.Error("Expected an error binding to an address no interface has")
+	}
+	if err := service.SetBinding("no-such-interface0"); err == nil {
+		t.Error("Expected an error binding to a missing interface")
+	}
+	if got := service.Binding(); !got.Address.Equal(binding.Address) {
+		t.Errorf("Binding() = %+v after failed binds, want it unchanged", got)
+	}
+
+	// Rebinding reopens the sockets and still delivers output
+	if err := service.SetBinding(binding.Interface); err != nil {
+		t.Fatalf("SetBinding(%q) error: %v", binding.Interface, err)
+	}
+	if got := service.Binding(); got.Bind != binding.Interface || !got.Address.Equal(binding.Address) {
+		t.Errorf("Binding() = %+v, want bound by interface name", got)
+	}
+	service.SetChannelValue(1, 1, 33)
+	service.ForceImmediateTransmission()
+	if received := receiveUniverses(t, listener); received[1] != 33 {
+		t.Errorf("Expected universe 1 sent after rebinding, got %v", received)
+	}
+
+	// 0.0.0.0 unbinds, and an empty bind restores the configured one
+	if err := service.SetBinding("0.0.0.0"); err != nil {
+		t.Fatalf("SetBinding(0.0.0.0) error: %v", err)
+	}
+	if got := service.Binding(); got.Address != nil {
+		t.Errorf("Binding() = %+v, want unbound", got)
+	}
+	if err := service.SetBinding(""); err != nil {
+		t.Fatalf("SetBinding(\"\") error: %v", err)
+	}
+	if got := service.Binding(); got.Bind != "127.0.0.1" {
+		t.Errorf("Binding() = %+v, want the configured bind restored", got)
+	}
+}
+
+func TestBinding_UnresolvedConfig(t *testing.T) {
+	service := NewService(Config{Enabled: false, Bind: "no-such-interface0"})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	if got := service.Binding(); got.Address != nil {
+		t.Errorf("Binding() = %+v, want output left unbound", got)
+	}
+}
@@ -0,0 +1,191 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxBlackoutFade bounds the fade time of a blackout or restore.
+const MaxBlackoutFade = 60 * time.Second
+
+// BlackoutStatus is a snapshot of the blackout stage.
+type BlackoutStatus struct {
+	// Active is true from a blackout until the next restore
+	Active bool
+	// Level is the fraction of the intensity channels currently let through
+	Level float64
+	// Fading is true while the level is ramping
+	Fading bool
+	// Since is when the blackout began (nil when not active)
+	Since *time.Time
+}
+
+// blackoutRamp is an in-progress change of the blackout level.
+type blackoutRamp struct {
+	from, to float64
+	start    time.Time
+	duration time.Duration
+}
+
+// levelAt returns the ramp's level at now, and whether it has finished.
+func (r *blackoutRamp) levelAt(now time.Time) (float64, bool) {
+	elapsed := now.Sub(r.start)
+	if r.duration <= 0 || elapsed >= r.duration {
+		return r.to, true
+	}
+	progress := float64(elapsed) / float64(r.duration)
+	return r.from + (r.to-r.from)*progress, false
+}
+
+func validateBlackoutFade(fade time.Duration) error {
+	if fade < 0 || fade > MaxBlackoutFade {
+		return fmt.Errorf("fade time must be between 0 and %v, got %v", MaxBlackoutFade, fade)
+	}
+	return nil
+}
+
+// SetBlackoutChannels replaces the channels a blackout takes to zero.
+// channels maps universe to 1-indexed channel to whether that channel fades;
+// channels that do not fade cut out as soon as a blackout starts and return
+// only once a restore completes.
+func (s *Service) SetBlackoutChannels(channels map[int]map[int]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blackoutChannels = make(map[int]map[int]bool, len(channels))
+	for universe, list := range channels {
+		valid := make(map[int]bool, len(list))
+		for channel, fades := range list {
+			if channel >= 1 && channel <= UniverseSize {
+				valid[channel] = fades
+			}
+		}
+		if len(valid) > 0 {
+			s.blackoutChannels[universe] = valid
+		}
+	}
+	if s.blackoutActiveLocked() {
+		s.markBlackoutChangedLocked()
+	}
+}
+
+// Blackout takes every blackout channel to zero over fade, on top of all
+// other output. Channel values are kept, so RestoreFromBlackout brings back
+// whatever is live at that point. Blacking out again is a no-op.
+func (s *Service) Blackout(fade time.Duration) (BlackoutStatus, error) {
+	if err := validateBlackoutFade(fade); err != nil {
+		return BlackoutStatus{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.blackoutSince == nil {
+		s.blackoutSince = &now
+		s.startBlackoutRampLocked(now, 0, fade)
+		log.Info("⬛ Blackout", "fade", fade)
+	}
+	return s.blackoutStatusLocked(now), nil
+}
+
+// RestoreFromBlackout ends a blackout, fading the blackout channels back up
+// to their live values over fade.
+func (s *Service) RestoreFromBlackout(fade time.Duration) (BlackoutStatus, error) {
+	if err := validateBlackoutFade(fade); err != nil {
+		return BlackoutStatus{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.blackoutSince != nil {
+		s.blackoutSince = nil
+		s.startBlackoutRampLocked(now, 1, fade)
+		log.Info("⬜ Restored from blackout", "fade", fade)
+	}
+	return s.blackoutStatusLocked(now), nil
+}
+
+// BlackoutStatus returns the state of the blackout stage.
+func (s *Service) BlackoutStatus() BlackoutStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.blackoutStatusLocked(time.Now())
+}
+
+func (s *Service) blackoutStatusLocked(now time.Time) BlackoutStatus {
+	status := BlackoutStatus{Active: s.blackoutSince != nil, Level: s.blackoutLevelLocked(now)}
+	if s.blackoutRamp != nil {
+		_, done := s.blackoutRamp.levelAt(now)
+		status.Fading = !done
+	}
+	if s.blackoutSince != nil {
+		since := *s.blackoutSince
+		status.Since = &since
+	}
+	return status
+}
+
+// blackoutLevelLocked returns the fraction of the blackout channels let
+// through at now.
+func (s *Service) blackoutLevelLocked(now time.Time) float64 {
+	if s.blackoutRamp == nil {
+		if s.blackoutSince != nil {
+			return 0
+		}
+		return 1
+	}
+	level, _ := s.blackoutRamp.levelAt(now)
+	return level
+}
+
+// blackoutActiveLocked reports whether the blackout stage affects output.
+func (s *Service) blackoutActiveLocked() bool {
+	return s.blackoutSince != nil || s.blackoutRamp != nil
+}
+
+func (s *Service) startBlackoutRampLocked(now time.Time, to float64, fade time.Duration) {
+	s.blackoutRamp = &blackoutRamp{
+		from:     s.blackoutLevelLocked(now),
+		to:       to,
+		start:    now,
+		duration: fade,
+	}
+	s.markBlackoutChangedLocked()
+}
+
+// advanceBlackoutLocked keeps every universe transmitting while a blackout
+// ramp runs, and drops the ramp after the frame that reaches its target.
+func (s *Service) advanceBlackoutLocked(now time.Time) {
+	if s.blackoutRamp == nil {
+		return
+	}
+	s.markBlackoutChangedLocked()
+	if _, done := s.blackoutRamp.levelAt(now); done {
+		s.blackoutRamp = nil
+	}
+}
+
+func (s *Service) markBlackoutChangedLocked() {
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// applyBlackoutLocked scales a universe's blackout channels in place.
+func (s *Service) applyBlackoutLocked(universe int, channels []byte) {
+	if !s.blackoutActiveLocked() {
+		return
+	}
+	level := s.blackoutLevelLocked(time.Now())
+	for channel, fades := range s.blackoutChannels[universe] {
+		switch {
+		case fades:
+			channels[channel-1] = byte(math.Round(float64(channels[channel-1]) * level))
+		case level < 1 || s.blackoutSince != nil:
+			channels[channel-1] = 0
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package dmx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlackout(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 200) // Dimmer
+	service.SetChannelValue(1, 2, 255) // Relay, does not fade
+	service.SetChannelValue(1, 3, 80)  // Pan, not an intensity channel
+	service.SetChannelOverride(1, 4, 255)
+	service.SetBlackoutChannels(map[int]map[int]bool{1: {1: true, 2: false, 4: true}})
+
+	status, err := service.Blackout(0)
+	if err != nil {
+		t.Fatalf("Blackout() error: %v", err)
+	}
+	if !status.Active || status.Level != 0 || status.Since == nil {
+		t.Errorf("Blackout() status = %+v", status)
+	}
+	universe := service.GetUniverse(1)
+	if universe[0] != 0 || universe[1] != 0 || universe[2] != 80 || universe[3] != 0 {
+		t.Errorf("Output in blackout = %v, want [0 0 80 0]", universe[:4])
+	}
+
+	// Changes made during a blackout are held back until restore
+	service.SetChannelValue(1, 1, 100)
+	if got := service.GetUniverse(1)[0]; got != 0 {
+		t.Errorf("Expected channel held at 0 during blackout, got %d", got)
+	}
+	since := status.Since
+	if again, _ := service.Blackout(time.Second); !again.Since.Equal(*since) || again.Fading {
+		t.Errorf("Blacking out again should be a no-op, got %+v", again)
+	}
+
+	status, err = service.RestoreFromBlackout(200 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("RestoreFromBlackout() error: %v", err)
+	}
+	if status.Active || !status.Fading {
+		t.Errorf("RestoreFromBlackout() status = %+v", status)
+	}
+	time.Sleep(100 * time.Millisecond)
+	universe = service.GetUniverse(1)
+	if universe[0] == 0 || universe[0] >= 100 || universe[1] != 0 {
+		t.Errorf("Output mid-restore = %v, want dimmer rising and relay still off", universe[:2])
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	universe = service.GetUniverse(1)
+	if universe[0] != 100 || universe[1] != 255 || universe[3] != 255 {
+		t.Errorf("Output after restore = %v, want [100 255 80 255]", universe[:4])
+	}
+}
+
+func TestBlackout_InvalidFade(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	if _, err := service.Blackout(-time.Second); err == nil {
+		t.Error("Expected error for a negative fade")
+	}
+	if _, err := service.RestoreFromBlackout(2 * MaxBlackoutFade); err == nil {
+		t.Error("Expected error for a fade above the maximum")
+	}
+	if status := service.BlackoutStatus(); status.Active || status.Level != 1 {
+		t.Errorf("Expected no blackout, got %+v", status)
+	}
+}
@@ -0,0 +1,213 @@
+package dmx
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxCaptureDuration is the longest packet capture that can be requested.
+	MaxCaptureDuration = 60 * time.Second
+	// maxCapturedPackets bounds memory use for a single capture (~60s at 4 universes x 60Hz).
+	maxCapturedPackets = 20000
+)
+
+// CaptureDirection indicates whether a captured packet was sent or received.
+type CaptureDirection string
+
+const (
+	// CaptureDirectionOut marks packets transmitted by this server.
+	CaptureDirectionOut CaptureDirection = "OUT"
+	// CaptureDirectionIn marks packets received from the network.
+	CaptureDirectionIn CaptureDirection = "IN"
+)
+
+// CapturedPacket is a single Art-Net packet recorded during a capture.
+type CapturedPacket struct {
+	Timestamp time.Time
+	Direction CaptureDirection
+	Universe  int
+	Sequence  byte
+	Address   string
+	Data      []byte
+}
+
+// PacketCapture records Art-Net traffic for a single universe (or all
+// universes when Universe is 0) until it is stopped.
+type PacketCapture struct {
+	mu        sync.Mutex
+	universe  int
+	startedAt time.Time
+	endedAt   time.Time
+	packets   []CapturedPacket
+	dropped   int
+}
+
+// Universe returns the captured universe, or 0 when capturing all universes.
+func (c *PacketCapture) Universe() int {
+	return c.universe
+}
+
+// StartedAt returns the time the capture began.
+func (c *PacketCapture) StartedAt() time.Time {
+	return c.startedAt
+}
+
+// EndedAt returns the time the capture was stopped (zero while running).
+func (c *PacketCapture) EndedAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endedAt
+}
+
+// Packets returns a copy of the recorded packets.
+func (c *PacketCapture) Packets() []CapturedPacket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	packets := make([]CapturedPacket, len(c.packets))
+	copy(packets, c.packets)
+	return packets
+}
+
+// Dropped returns the number of packets discarded after the capture filled up.
+func (c *PacketCapture) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+func (c *PacketCapture) record(p CapturedPacket) {
+	if c.universe != 0 && p.Universe != c.universe {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.endedAt.IsZero() {
+		return
+	}
+	if len(c.packets) >= maxCapturedPackets {
+		c.dropped++
+		return
+	}
+	// Copy the payload since packet buffers may be reused by the caller
+	p.Data = append([]byte(nil), p.Data...)
+	c.packets = append(c.packets, p)
+}
+
+// captureArtifact is the JSON layout of a downloadable capture.
+type captureArtifact struct {
+	Format      string                   `json:"format"`
+	Universe    *int                     `json:"universe,omitempty"`
+	StartedAt   string                   `json:"startedAt"`
+	EndedAt     string                   `json:"endedAt"`
+	PacketCount int                      `json:"packetCount"`
+	Dropped     int                      `json:"dropped"`
+	Packets     []capturedPacketArtifact `json:"packets"`
+}
+
+type capturedPacketArtifact struct {
+	Timestamp string  `json:"timestamp"`
+	OffsetMs  float64 `json:"offsetMs"`
+	Direction string  `json:"direction"`
+	Universe  int     `json:"universe"`
+	Sequence  int     `json:"sequence"`
+	Address   string  `json:"address,omitempty"`
+	Length    int     `json:"length"`
+	Hex       string  `json:"hex"`
+}
+
+// ToJSON renders the capture as a self-describing JSON artifact with
+// hex-encoded raw packets, suitable for offline inspection.
+func (c *PacketCapture) ToJSON() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	artifact := captureArtifact{
+		Format:      "lacylights-artnet-capture/1",
+		StartedAt:   c.startedAt.UTC().Format(time.RFC3339Nano),
+		EndedAt:     c.endedAt.UTC().Format(time.RFC3339Nano),
+		PacketCount: len(c.packets),
+		Dropped:     c.dropped,
+		Packets:     make([]capturedPacketArtifact, 0, len(c.packets)),
+	}
+	if c.universe != 0 {
+		universe := c.universe
+		artifact.Universe = &universe
+	}
+
+	for _, p := range c.packets {
+		artifact.Packets = append(artifact.Packets, capturedPacketArtifact{
+			Timestamp: p.Timestamp.UTC().Format(time.RFC3339Nano),
+			OffsetMs:  float64(p.Timestamp.Sub(c.startedAt).Microseconds()) / 1000,
+			Direction: string(p.Direction),
+			Universe:  p.Universe,
+			Sequence:  int(p.Sequence),
+			Address:   p.Address,
+			Length:    len(p.Data),
+			Hex:       hex.EncodeToString(p.Data),
+		})
+	}
+
+	data, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// StartCapture begins recording Art-Net packets for the given universe.
+// Pass 0 to record every universe. Call StopCapture to finish.
+func (s *Service) StartCapture(universe int) *PacketCapture {
+	capture := &PacketCapture{
+		universe:  universe,
+		startedAt: time.Now(),
+	}
+
+	s.captureMu.Lock()
+	s.captures = append(s.captures, capture)
+	s.captureMu.Unlock()
+
+	return capture
+}
+
+// StopCapture stops recording into the capture and detaches it from the service.
+func (s *Service) StopCapture(capture *PacketCapture) {
+	s.captureMu.Lock()
+	for i, c := range s.captures {
+		if c == capture {
+			s.captures = append(s.captures[:i], s.captures[i+1:]...)
+			break
+		}
+	}
+	s.captureMu.Unlock()
+
+	capture.mu.Lock()
+	if capture.endedAt.IsZero() {
+		capture.endedAt = time.Now()
+	}
+	capture.mu.Unlock()
+}
+
+// recordPacket hands a packet to every active capture.
+func (s *Service) recordPacket(direction CaptureDirection, universe int, sequence byte, address string, data []byte) {
+	s.captureMu.Lock()
+	defer s.captureMu.Unlock()
+
+	if len(s.captures) == 0 {
+		return
+	}
+
+	packet := CapturedPacket{
+		Timestamp: time.Now(),
+		Direction: direction,
+		Universe:  universe,
+		Sequence:  sequence,
+		Address:   address,
+		Data:      data,
+	}
+	for _, c := range s.captures {
+		c.record(packet)
+	}
+}
@@ -0,0 +1,98 @@
+package dmx
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPacketCapture_RecordsOutgoingPackets(t *testing.T) {
+	testPort := 6570
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort}
+	listener, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{
+		Enabled:          true,
+		BroadcastAddr:    "127.0.0.1",
+		Port:             testPort,
+		RefreshRateHz:    100,
+		IdleRateHz:       1,
+		HighRateDuration: 5 * time.Second,
+	})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	capture := service.StartCapture(1)
+	service.SetChannelValue(1, 1, 200)
+	service.SetChannelValue(2, 1, 50)
+	time.Sleep(100 * time.Millisecond)
+	service.StopCapture(capture)
+
+	packets := capture.Packets()
+	if len(packets) == 0 {
+		t.Fatal("Expected at least one captured packet")
+	}
+	for _, p := range packets {
+		if p.Universe != 1 {
+			t.Errorf("Captured packet for universe %d, want only universe 1", p.Universe)
+		}
+		if p.Direction != CaptureDirectionOut {
+			t.Errorf("Direction = %s, want %s", p.Direction, CaptureDirectionOut)
+		}
+	}
+	if data := packets[0].Data; len(data) < 19 || data[18] != 200 {
+		t.Errorf("Expected channel 1 = 200 in captured packet")
+	}
+
+	// No further packets are recorded once stopped
+	count := len(packets)
+	service.SetChannelValue(1, 2, 10)
+	time.Sleep(50 * time.Millisecond)
+	if len(capture.Packets()) != count {
+		t.Errorf("Capture grew after StopCapture: %d -> %d", count, len(capture.Packets()))
+	}
+
+	content, err := capture.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+	var artifact struct {
+		Universe    *int `json:"universe"`
+		PacketCount int  `json:"packetCount"`
+		Packets     []struct {
+			Hex string `json:"hex"`
+		} `json:"packets"`
+	}
+	if err := json.Unmarshal([]byte(content), &artifact); err != nil {
+		t.Fatalf("Capture artifact is not valid JSON: %v", err)
+	}
+	if artifact.Universe == nil || *artifact.Universe != 1 {
+		t.Errorf("Artifact universe = %v, want 1", artifact.Universe)
+	}
+	if artifact.PacketCount != count || len(artifact.Packets) != count {
+		t.Errorf("Artifact packet count = %d/%d, want %d", artifact.PacketCount, len(artifact.Packets), count)
+	}
+}
+
+func TestPacketCapture_SimulationModeRecordsNothing(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+
+	capture := service.StartCapture(0)
+	service.SetChannelValue(1, 1, 255)
+	service.processTransmission()
+	service.StopCapture(capture)
+
+	if len(capture.Packets()) != 0 {
+		t.Errorf("Expected no packets in simulation mode, got %d", len(capture.Packets()))
+	}
+	if capture.EndedAt().IsZero() {
+		t.Error("Expected EndedAt to be set after StopCapture")
+	}
+}
@@ -0,0 +1,137 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ChannelLimitsSettingKey is the setting that stores the channel limits as
+// JSON.
+const ChannelLimitsSettingKey = "channel_limits"
+
+// Curve is the response of a channel: how its output follows the level
+// sent to it.
+type Curve string
+
+const (
+	// CurveLinear passes levels through.
+	CurveLinear Curve = "LINEAR"
+	// CurveSquare squares the level, for finer control at the bottom.
+	CurveSquare Curve = "SQUARE"
+	// CurveSquareRoot takes the square root of the level, for a faster rise.
+	CurveSquareRoot Curve = "SQUARE_ROOT"
+)
+
+// ChannelLimit protects one output channel, such as house lights or a
+// scroller: its level follows Curve, then is capped at MaxLevel (0-1).
+// Inhibit holds it at zero.
+type ChannelLimit struct {
+	Universe int     `json:"universe"`
+	Channel  int     `json:"channel"`
+	MaxLevel float64 `json:"maxLevel"`
+	Inhibit  bool    `json:"inhibit"`
+	Curve    Curve   `json:"curve,omitempty"` // Empty is linear
+}
+
+// Validate checks the limit's address, level and curve.
+func (l ChannelLimit) Validate() error {
+	if err := (Address{Universe: l.Universe, Channel: l.Channel}).Validate(); err != nil {
+		return err
+	}
+	if l.MaxLevel < 0 || l.MaxLevel > 1 || math.IsNaN(l.MaxLevel) {
+		return fmt.Errorf("max level must be between 0 and 1, got %v", l.MaxLevel)
+	}
+	switch l.Curve {
+	case "", CurveLinear, CurveSquare, CurveSquareRoot:
+		return nil
+	}
+	return fmt.Errorf("unknown curve %q", l.Curve)
+}
+
+// table returns the output for each level sent to the channel.
+func (l ChannelLimit) table() *[256]byte {
+	var table [256]byte
+	if l.Inhibit {
+		return &table
+	}
+	max := math.Round(l.MaxLevel * 255)
+	for level := range table {
+		fraction := float64(level) / 255
+		switch l.Curve {
+		case CurveSquare:
+			fraction *= fraction
+		case CurveSquareRoot:
+			fraction = math.Sqrt(fraction)
+		}
+		table[level] = byte(math.Min(math.Round(fraction*255), max))
+	}
+	return &table
+}
+
+// ValidateChannelLimits checks each limit, and that no channel is limited
+// more than once.
+func ValidateChannelLimits(limits []ChannelLimit) error {
+	seen := make(map[Address]bool, len(limits))
+	for _, limit := range limits {
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("channel limit %d/%d: %w", limit.Universe, limit.Channel, err)
+		}
+		address := Address{Universe: limit.Universe, Channel: limit.Channel}
+		if seen[address] {
+			return fmt.Errorf("channel %d/%d is limited more than once", limit.Universe, limit.Channel)
+		}
+		seen[address] = true
+	}
+	return nil
+}
+
+// SetChannelLimits replaces every channel limit. They apply after
+// everything else, fixture intensity caps included, so no scene, override,
+// or playback can get past them.
+func (s *Service) SetChannelLimits(limits []ChannelLimit) error {
+	if err := ValidateChannelLimits(limits); err != nil {
+		return err
+	}
+
+	sorted := append([]ChannelLimit(nil), limits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Universe != sorted[j].Universe {
+			return sorted[i].Universe < sorted[j].Universe
+		}
+		return sorted[i].Channel < sorted[j].Channel
+	})
+	tables := make(map[int]map[int]*[256]byte)
+	for _, limit := range sorted {
+		if tables[limit.Universe] == nil {
+			tables[limit.Universe] = make(map[int]*[256]byte)
+		}
+		tables[limit.Universe][limit.Channel] = limit.table()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channelLimitList = sorted
+	s.channelLimits = tables
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+	return nil
+}
+
+// ChannelLimits returns the channel limits in universe and channel order.
+func (s *Service) ChannelLimits() []ChannelLimit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]ChannelLimit{}, s.channelLimitList...)
+}
+
+// applyChannelLimitsLocked applies the channel limits to a universe's output
+// in place.
+func (s *Service) applyChannelLimitsLocked(universe int, channels []byte) {
+	for channel, table := range s.channelLimits[universe] {
+		channels[channel-1] = table[channels[channel-1]]
+	}
+}
@@ -0,0 +1,58 @@
+package dmx
+
+import "testing"
+
+func TestChannelLimits(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	for channel := 1; channel <= 4; channel++ {
+		service.SetChannelValue(1, channel, 255)
+	}
+	service.SetChannelOverride(1, 2, 255)
+
+	err := service.SetChannelLimits([]ChannelLimit{
+		{Universe: 1, Channel: 3, MaxLevel: 1, Curve: CurveSquare},
+		{Universe: 1, Channel: 1, MaxLevel: 0.5},
+		{Universe: 1, Channel: 2, MaxLevel: 1, Inhibit: true},
+	})
+	if err != nil {
+		t.Fatalf("SetChannelLimits() error: %v", err)
+	}
+
+	out := service.GetUniverse(1)
+	if out[0] != 128 || out[1] != 0 || out[2] != 255 || out[3] != 255 {
+		t.Errorf("Limited output = %v, want [128 0 255 255]", out[:4])
+	}
+	if got := service.GetChannelValue(1, 1); got != 255 {
+		t.Errorf("Limits should not change channel values, got %d", got)
+	}
+	if limits := service.ChannelLimits(); len(limits) != 3 || limits[0].Channel != 1 || limits[2].Channel != 3 {
+		t.Errorf("ChannelLimits() = %+v, want channels 1-3 in order", limits)
+	}
+
+	// Curves shape levels below full
+	service.SetChannelValue(1, 3, 128)
+	if got := service.GetUniverse(1)[2]; got != 64 {
+		t.Errorf("Square curve output at 128 = %d, want 64", got)
+	}
+	if err := service.SetChannelLimits([]ChannelLimit{{Universe: 1, Channel: 3, MaxLevel: 1, Curve: CurveSquareRoot}}); err != nil {
+		t.Fatalf("SetChannelLimits() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[2]; got != 181 {
+		t.Errorf("Square root curve output at 128 = %d, want 181", got)
+	}
+	if got := service.GetUniverse(1)[0]; got != 255 {
+		t.Errorf("Replaced limits should release channel 1, got %d", got)
+	}
+
+	tests := map[string][]ChannelLimit{
+		"level":     {{Universe: 1, Channel: 1, MaxLevel: 1.5}},
+		"curve":     {{Universe: 1, Channel: 1, MaxLevel: 1, Curve: "CUBIC"}},
+		"address":   {{Universe: 1, Channel: 0, MaxLevel: 1}},
+		"duplicate": {{Universe: 1, Channel: 1, MaxLevel: 1}, {Universe: 1, Channel: 1, MaxLevel: 0.5}},
+	}
+	for name, limits := range tests {
+		if err := service.SetChannelLimits(limits); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
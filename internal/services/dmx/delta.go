@@ -0,0 +1,145 @@
+package dmx
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// DefaultKeepAliveInterval is how long an unchanged universe goes without
+// being sent. Art-Net receivers treat a universe they have not heard from
+// for a few seconds as lost, so unchanged universes are resent this often.
+const DefaultKeepAliveInterval = time.Second
+
+// UniverseTransmitStats counts one universe's frames.
+type UniverseTransmitStats struct {
+	Universe int
+	// ChangedFrames were sent because the universe's output changed
+	ChangedFrames int
+	// KeepAliveFrames resent unchanged output to keep receivers alive
+	KeepAliveFrames int
+	// SkippedFrames were not sent because the output was unchanged
+	SkippedFrames int
+}
+
+// TransmitStats counts the universe frames output since the service started.
+// Every transmit cycle counts each universe once, as changed, keep-alive, or
+// skipped, so skipped frames are those sending every universe every cycle
+// would have added.
+type TransmitStats struct {
+	Since           time.Time
+	Cycles          int
+	ChangedFrames   int
+	KeepAliveFrames int
+	SkippedFrames   int
+	// Universes in universe order
+	Universes []UniverseTransmitStats
+}
+
+// deltaState is the last frame sent for each universe, and the counters.
+type deltaState struct {
+	frames map[int][]byte
+	sentAt map[int]time.Time
+
+	since    time.Time
+	cycles   int
+	counters map[int]*UniverseTransmitStats
+}
+
+func newDeltaState() deltaState {
+	return deltaState{
+		frames:   make(map[int][]byte),
+		sentAt:   make(map[int]time.Time),
+		since:    time.Now(),
+		counters: make(map[int]*UniverseTransmitStats),
+	}
+}
+
+// frameKind is why a universe's frame is or is not sent.
+type frameKind int
+
+const (
+	frameSkipped frameKind = iota
+	frameChanged
+	frameKeepAlive
+)
+
+// classifyFrameLocked decides whether to send a universe's frame: when it
+// differs from the last frame sent, or when the keep-alive interval has
+// passed. A keep-alive due within half a cycle is sent now, so a keep-alive
+// interval equal to the cycle does not slip to every other cycle.
+func (s *Service) classifyFrameLocked(universe int, channels []byte, now time.Time, cycle time.Duration) frameKind {
+	last, ok := s.delta.frames[universe]
+	if !ok || !bytes.Equal(last, channels) {
+		return frameChanged
+	}
+	if now.Sub(s.delta.sentAt[universe])+cycle/2 >= s.keepAliveInterval {
+		return frameKeepAlive
+	}
+	return frameSkipped
+}
+
+// recordFrameLocked counts a universe's frame and remembers it if sent.
+func (s *Service) recordFrameLocked(universe int, channels []byte, kind frameKind, now time.Time) {
+	counter := s.delta.counters[universe]
+	if counter == nil {
+		counter = &UniverseTransmitStats{Universe: universe}
+		s.delta.counters[universe] = counter
+	}
+	switch kind {
+	case frameChanged:
+		counter.ChangedFrames++
+	case frameKeepAlive:
+		counter.KeepAliveFrames++
+	default:
+		counter.SkippedFrames++
+		return
+	}
+	frame := s.delta.frames[universe]
+	if frame == nil {
+		frame = make([]byte, len(channels))
+	}
+	s.delta.frames[universe] = append(frame[:0], channels...)
+	s.delta.sentAt[universe] = now
+}
+
+// resendAllLocked sends every universe on the next cycle, for receivers
+// that have not had the current output.
+func (s *Service) resendAllLocked() {
+	s.delta.frames = make(map[int][]byte)
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+}
+
+// TransmitStats returns the frame counters.
+func (s *Service) TransmitStats() TransmitStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.transmitStatsLocked()
+}
+
+// ResetTransmitStats zeroes the frame counters, returning them as they were.
+func (s *Service) ResetTransmitStats() TransmitStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.transmitStatsLocked()
+	s.delta.since = time.Now()
+	s.delta.cycles = 0
+	s.delta.counters = make(map[int]*UniverseTransmitStats)
+	return stats
+}
+
+func (s *Service) transmitStatsLocked() TransmitStats {
+	stats := TransmitStats{Since: s.delta.since, Cycles: s.delta.cycles}
+	for _, counter := range s.delta.counters {
+		stats.ChangedFrames += counter.ChangedFrames
+		stats.KeepAliveFrames += counter.KeepAliveFrames
+		stats.SkippedFrames += counter.SkippedFrames
+		stats.Universes = append(stats.Universes, *counter)
+	}
+	sort.Slice(stats.Universes, func(i, j int) bool {
+		return stats.Universes[i].Universe < stats.Universes[j].Universe
+	})
+	return stats
+}
@@ -0,0 +1,86 @@
+package dmx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltaTransmission(t *testing.T) {
+	service := NewService(Config{Enabled: false, KeepAliveInterval: time.Hour})
+	frames := frameCounter{}
+	service.SetSink(frames)
+
+	// The first cycle sends every universe
+	service.processTransmission()
+	for universe := 1; universe <= 4; universe++ {
+		if frames[universe] != 1 {
+			t.Fatalf("Frames = %v, want one for each universe", frames)
+		}
+	}
+
+	// Only the universe that changed is sent again
+	service.SetChannelValue(2, 1, 255)
+	service.processTransmission()
+	service.processTransmission()
+	if frames[1] != 1 || frames[2] != 2 || frames[3] != 1 {
+		t.Errorf("Frames = %v, want universe 2 sent once more", frames)
+	}
+
+	// Setting a channel to its current value sends nothing
+	service.SetChannelValue(2, 1, 255)
+	service.processTransmission()
+	if frames[2] != 2 {
+		t.Errorf("Frames = %v, want the unchanged universe skipped", frames)
+	}
+
+	stats := service.TransmitStats()
+	if stats.Cycles != 4 || stats.ChangedFrames != 5 || stats.KeepAliveFrames != 0 || stats.SkippedFrames != 11 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if len(stats.Universes) != 4 || stats.Universes[1].Universe != 2 || stats.Universes[1].ChangedFrames != 2 {
+		t.Errorf("Unexpected universe stats %+v", stats.Universes)
+	}
+
+	if before := service.ResetTransmitStats(); before.Cycles != 4 {
+		t.Errorf("Expected the reset to return the counters, got %+v", before)
+	}
+	if stats := service.TransmitStats(); stats.Cycles != 0 || len(stats.Universes) != 0 {
+		t.Errorf("Expected reset stats, got %+v", stats)
+	}
+}
+
+func TestDeltaTransmission_KeepAlive(t *testing.T) {
+	// Cycles well inside the keep-alive interval
+	service := NewService(Config{Enabled: false, IdleRateHz: 1000, KeepAliveInterval: 50 * time.Millisecond})
+	frames := frameCounter{}
+	service.SetSink(frames)
+
+	service.processTransmission()
+	service.processTransmission()
+	if frames[1] != 1 {
+		t.Fatalf("Frames = %v, want unchanged universes held back", frames)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	service.processTransmission()
+	if frames[1] != 2 || frames[4] != 2 {
+		t.Errorf("Frames = %v, want every universe resent as a keep-alive", frames)
+	}
+	if stats := service.TransmitStats(); stats.KeepAliveFrames != 4 {
+		t.Errorf("KeepAliveFrames = %d, want 4", stats.KeepAliveFrames)
+	}
+}
+
+func TestDeltaTransmission_ResendOnWake(t *testing.T) {
+	service := NewService(Config{Enabled: false, KeepAliveInterval: time.Hour})
+	frames := frameCounter{}
+	service.SetSink(frames)
+
+	service.processTransmission()
+	service.EnterStandby()
+	service.ExitStandby()
+	service.processTransmission()
+	if frames[1] != 2 || frames[4] != 2 {
+		t.Errorf("Frames = %v, want every universe resent on wake", frames)
+	}
+}
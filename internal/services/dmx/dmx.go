@@ -2,9 +2,10 @@
 package dmx
 
 import (
-	"log"
+	"fmt"
 	"net"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -17,6 +18,8 @@ const (
 	UniverseSize = 512
 	// MaxUniverses is the maximum number of supported universes.
 	MaxUniverses = 4
+	// MaxOverrideTTL is the longest a timed channel override may last.
+	MaxOverrideTTL = 10 * time.Minute
 )
 
 // Service manages DMX channel values and Art-Net output.
@@ -29,6 +32,65 @@ type Service struct {
 	// Channel overrides (key: "universe:channel", 1-indexed)
 	channelOverrides map[string]byte
 
+	// Expiry timers for overrides set with a TTL (same keys as channelOverrides)
+	overrideTimers map[string]*time.Timer
+
+	// Output ceilings (universe -> 1-indexed channel -> max value), applied after overrides
+	outputLimits map[int]map[int]byte
+
+	// Effect output (layer ID -> universe -> 1-indexed channel -> value),
+	// applied over the channel values in effectOrder
+	effectLayers map[string]map[int]map[int]byte
+	effectOrder  []string
+
+	// Submaster faders, by ID, applied over the effect output
+	submasters map[string]*Submaster
+
+	// Flashed scenes, by ID, applied over the submasters in flashOrder
+	flashes    map[string]*Flash
+	flashOrder []string
+
+	// Programmer values (universe -> 1-indexed channel -> value), applied
+	// over the flashes
+	programmer map[int]map[int]byte
+
+	// Independents, by ID, applied after the masters in independentOrder
+	independents     map[string]*Independent
+	independentOrder []string
+
+	// Universes outputting a blind view of another in place of their own
+	previewUniverses map[int]*previewUniverse
+
+	// Output masters: the grand master and per-universe masters (0-1) scale
+	// the master channels (universe -> 1-indexed channels) before overrides
+	grandMaster     float64
+	universeMasters map[int]float64
+	masterChannels  map[int][]int
+
+	// Blackout takes the blackout channels (universe -> 1-indexed channel ->
+	// whether it fades) to zero after overrides, ramping over blackoutRamp
+	blackoutChannels map[int]map[int]bool
+	blackoutSince    *time.Time
+	blackoutRamp     *blackoutRamp
+
+	// House lights (layer ID -> universe -> 1-indexed channel -> value),
+	// applied after blackout in houseOrder
+	houseLights map[string]map[int]map[int]byte
+	houseOrder  []string
+
+	// Parked channels (universe -> 1-indexed channel -> value), applied
+	// after the house lights
+	parkedList []ParkedChannel
+	parked     map[int]map[int]byte
+
+	// Channel limits applied last, and their output for each level:
+	// universe -> 1-indexed channel -> table
+	channelLimitList []ChannelLimit
+	channelLimits    map[int]map[int]*[256]byte
+
+	// Softpatch from logical channels to output addresses (optional)
+	softPatch *softPatch
+
 	// Active scene tracking
 	activeSceneID *string
 
@@ -49,6 +111,11 @@ type Service struct {
 	isDirty        bool
 	dirtyUniverses map[int]bool
 
+	// Delta transmission: universes are sent when their output changes, and
+	// unchanged ones every keepAliveInterval
+	keepAliveInterval time.Duration
+	delta             deltaState
+
 	// Timing tracking
 	lastTransmissionTime time.Time
 
@@ -59,10 +126,58 @@ type Service struct {
 	conn *net.UDPConn
 	addr *net.UDPAddr
 
+	// The local address output is sent from, and the configured bind that
+	// an empty SetBinding restores
+	binding        Binding
+	configuredBind string
+
+	// The last Art-Net send failure, cleared when a send succeeds
+	sendErr   error
+	sendErrAt time.Time
+
+	// Unicast routing: universes sent directly to nodes instead of broadcast
+	unicastConn   *net.UDPConn
+	unicastRoutes map[int][]*net.UDPAddr
+	unicastConfig []UnicastRoute
+
+	// Universe output as projects configure it: destinations for universes
+	// the routing table does not route, and universes not sent
+	universeRoutes    map[int][]*net.UDPAddr
+	disabledUniverses map[int]bool
+
+	// Send ArtSync after each burst of ArtDmx packets
+	artSync bool
+
+	// Receives each transmitted frame in addition to Art-Net (optional)
+	sink Sink
+	// Further frame receivers added with AddSink
+	sinks []*addedSink
+
+	// Active diagnostic packet captures (guarded by captureMu, not mu)
+	captureMu sync.Mutex
+	captures  []*PacketCapture
+
 	// Control
 	stopChan       chan struct{}
 	resetTickerChan chan struct{} // Signal to reset ticker immediately when rate changes
 	running        bool
+
+	// Standby suppresses all output while keeping universe state for wake
+	standby bool
+	// Frames retransmitted at the idle rate during a holding standby
+	// (universe -> channels); nil when standby blacks out
+	heldFrames map[int][]byte
+
+	// Passive output sends no Art-Net, as on a tracking backup
+	passive bool
+
+	// Frames handed off by a stopped server (universe -> channels),
+	// retransmitted in place of output until released
+	handoffFrames map[int][]byte
+
+	// Recorded frames replayed in place of live output (universe ->
+	// channels); nil when nothing is replaying
+	replayFrames map[int][]byte
 }
 
 // Config holds DMX service configuration.
@@ -73,6 +188,12 @@ type Config struct {
 	RefreshRateHz    int
 	IdleRateHz       int
 	HighRateDuration time.Duration
+	ArtSync          bool
+	// KeepAliveInterval is how often unchanged universes are resent
+	KeepAliveInterval time.Duration
+	// Bind is the interface name or IPv4 address output is sent from
+	// (default: chosen by the operating system)
+	Bind string
 }
 
 // DefaultConfig returns a configuration with default values.
@@ -84,6 +205,7 @@ func DefaultConfig() Config {
 		RefreshRateHz:    60, // Match fade engine default (60Hz)
 		IdleRateHz:       1,
 		HighRateDuration: 2 * time.Second,
+		KeepAliveInterval: DefaultKeepAliveInterval,
 	}
 }
 
@@ -123,6 +245,16 @@ func ConfigFromEnv() Config {
 		}
 	}
 
+	if bind := os.Getenv("ARTNET_BIND"); bind != "" {
+		cfg.Bind = bind
+	}
+
+	if dur := os.Getenv("DMX_KEEPALIVE_MS"); dur != "" {
+		if d, err := strconv.Atoi(dur); err == nil && d > 0 {
+			cfg.KeepAliveInterval = time.Duration(d) * time.Millisecond
+		}
+	}
+
 	return cfg
 }
 
@@ -145,17 +277,38 @@ func NewService(cfg Config) *Service {
 	if port <= 0 {
 		port = 6454 // Default Art-Net port
 	}
+	keepAlive := cfg.KeepAliveInterval
+	if keepAlive <= 0 {
+		keepAlive = DefaultKeepAliveInterval
+	}
 
 	s := &Service{
 		universes:        make(map[int][]byte),
 		channelOverrides: make(map[string]byte),
+		overrideTimers:   make(map[string]*time.Timer),
+		outputLimits:     make(map[int]map[int]byte),
+		grandMaster:      1,
+		universeMasters:  make(map[int]float64),
+		masterChannels:   make(map[int][]int),
+		effectLayers:     make(map[string]map[int]map[int]byte),
+		submasters:       make(map[string]*Submaster),
+		flashes:          make(map[string]*Flash),
+		programmer:       make(map[int]map[int]byte),
+		independents:     make(map[string]*Independent),
+		previewUniverses: make(map[int]*previewUniverse),
+		blackoutChannels: make(map[int]map[int]bool),
+		houseLights:      make(map[string]map[int]map[int]byte),
 		dirtyUniverses:   make(map[int]bool),
+		keepAliveInterval: keepAlive,
+		delta:            newDeltaState(),
 		enabled:          cfg.Enabled,
 		broadcastAddr:    cfg.BroadcastAddr,
+		configuredBind:   cfg.Bind,
 		port:             port,
 		refreshRateHz:    refreshRate,
 		idleRateHz:       idleRate,
 		highRateDuration: highRateDuration,
+		artSync:          cfg.ArtSync,
 		currentRate:      idleRate, // Start at idle rate until first change
 		isInHighRateMode: false,
 		stopChan:         make(chan struct{}),
@@ -186,6 +339,14 @@ func (s *Service) Initialize() error {
 		return nil
 	}
 
+	if s.configuredBind != "" {
+		binding, err := resolveBinding(s.configuredBind)
+		if err != nil {
+			log.Warn("cannot bind Art-Net output, the system chooses the interface", "bind", s.configuredBind, "error", err)
+		}
+		s.binding = binding
+	}
+
 	if s.enabled {
 		// Create UDP socket for Art-Net broadcast
 		addr, err := net.ResolveUDPAddr("udp4", s.broadcastAddr+":"+strconv.Itoa(s.port))
@@ -194,18 +355,17 @@ func (s *Service) Initialize() error {
 		}
 		s.addr = addr
 
-		conn, err := net.DialUDP("udp4", nil, addr)
+		conn, err := net.DialUDP("udp4", dialAddr(s.binding.Address), addr)
 		if err != nil {
 			return err
 		}
 		s.conn = conn
 
-		log.Printf("🎭 DMX Service initialized with %d universes", len(s.universes))
-		log.Printf("📡 Adaptive transmission: %dHz (active) / %dHz (idle), %v high-rate duration",
-			s.refreshRateHz, s.idleRateHz, s.highRateDuration)
-		log.Printf("📡 Art-Net output enabled, broadcasting to %s:%d", s.broadcastAddr, s.port)
+		log.Info("🎭 DMX Service initialized", "universes", len(s.universes))
+		log.Info("📡 Adaptive transmission", "activeHz", s.refreshRateHz, "idleHz", s.idleRateHz, "highRateDuration", s.highRateDuration)
+		log.Info("📡 Art-Net output enabled", "broadcast", s.broadcastAddr, "port", s.port)
 	} else {
-		log.Printf("🎭 DMX Service initialized with %d universes (simulation mode)", len(s.universes))
+		log.Info("🎭 DMX Service initialized in simulation mode", "universes", len(s.universes))
 	}
 
 	// Start the transmission loop
@@ -242,7 +402,7 @@ func (s *Service) transmitLoop() {
 				ticker = time.NewTicker(newInterval)
 				oldTicker.Stop()
 				lastRate = currentRate
-				log.Printf("📡 DMX transmitLoop: ticker reset to %dHz immediately", currentRate)
+				log.Debug("📡 DMX transmit ticker reset", "rateHz", currentRate)
 			}
 		case <-ticker.C:
 			s.processTransmission()
@@ -270,7 +430,22 @@ func (s *Service) processTransmission() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.transmitHandoffLocked() {
+		return
+	}
+
+	if s.standby {
+		// A holding standby keeps fixtures on the last look
+		if s.heldFrames != nil && s.hasOutputLocked() {
+			for universe, channels := range s.heldFrames {
+				s.transmitLocked(universe, channels)
+			}
+		}
+		return
+	}
+
 	currentTime := time.Now()
+	s.advanceBlackoutLocked(currentTime)
 	hasChanges := s.isDirty
 
 	// Update transmission rate based on changes
@@ -279,7 +454,7 @@ func (s *Service) processTransmission() {
 		if !s.isInHighRateMode {
 			s.isInHighRateMode = true
 			s.currentRate = s.refreshRateHz
-			log.Printf("📡 DMX transmission: switching to high rate (%dHz) - changes detected", s.refreshRateHz)
+			log.Debug("📡 DMX transmission switching to high rate: changes detected", "rateHz", s.refreshRateHz)
 		}
 	} else {
 		// Check if we should switch to idle rate
@@ -287,57 +462,79 @@ func (s *Service) processTransmission() {
 		if s.isInHighRateMode && !s.lastChangeTime.IsZero() && timeSinceLastChange > s.highRateDuration {
 			s.isInHighRateMode = false
 			s.currentRate = s.idleRateHz
-			log.Printf("📡 DMX transmission: switching to idle rate (%dHz) - no changes for %v", s.idleRateHz, timeSinceLastChange)
+			log.Debug("📡 DMX transmission switching to idle rate", "rateHz", s.idleRateHz, "idleFor", timeSinceLastChange)
 		}
 	}
 
-	// Always transmit in both high-rate and idle modes
-	// High-rate mode: transmit at 60Hz for smooth fades/transitions
-	// Idle mode: transmit at 1Hz for keep-alive
-	// This ensures DMX output stays fresh and responsive
-	if s.enabled && s.conn != nil {
+	// Run a transmit cycle in both high-rate and idle modes
+	// High-rate mode: cycle at 60Hz for smooth fades/transitions
+	// Idle mode: cycle at 1Hz, sending keep-alives
+	// Each cycle sends changed universes and those due a keep-alive
+	if s.hasOutputLocked() {
 		s.outputDMX()
 	}
 }
 
-// outputDMX sends Art-Net packets for dirty or all universes.
-func (s *Service) outputDMX() {
-	var universesToTransmit []int
+// hasOutputLocked reports whether frames have somewhere to go.
+func (s *Service) hasOutputLocked() bool {
+	return (s.enabled && (s.conn != nil || s.unicastConn != nil)) || s.sink != nil || len(s.sinks) > 0
+}
 
-	if s.isDirty && len(s.dirtyUniverses) > 0 {
-		// Only transmit changed universes
-		for u := range s.dirtyUniverses {
-			universesToTransmit = append(universesToTransmit, u)
-		}
-	} else {
-		// In idle mode, transmit all universes for keep-alive
-		for u := range s.universes {
-			universesToTransmit = append(universesToTransmit, u)
-		}
+// outputDMX sends Art-Net packets for the universes whose output changed
+// since they were last sent, and for unchanged universes due a keep-alive.
+func (s *Service) outputDMX() {
+	now := time.Now()
+	cycle := time.Second / time.Duration(s.currentRate)
+	universes := make([]int, 0, len(s.universes))
+	for u := range s.universes {
+		universes = append(universes, u)
 	}
+	sort.Ints(universes)
 
 	// Send Art-Net packets
-	for _, universe := range universesToTransmit {
-		channels := s.getUniverseOutputChannels(universe)
-
-		// Increment sequence number for each packet (wraps at 255)
-		s.sequence++
-		packet := artnet.BuildDMXPacket(universe, channels, s.sequence)
-
-		_, err := s.conn.Write(packet)
-		if err != nil {
-			log.Printf("Art-Net send error for universe %d: %v", universe, err)
+	var sent []int
+	logical := make(map[int][]byte)
+	for _, universe := range universes {
+		channels, replayed := s.replayFrames[universe]
+		if !replayed {
+			channels = s.patchedOutputLocked(universe, logical)
+		}
+		kind := s.classifyFrameLocked(universe, channels, now, cycle)
+		s.recordFrameLocked(universe, channels, kind, now)
+		if kind == frameSkipped {
+			continue
+		}
+		if s.sink != nil {
+			s.sink.WriteFrame(universe, channels)
 		}
+		for _, sink := range s.sinks {
+			sink.WriteFrame(universe, channels)
+		}
+		s.transmitLocked(universe, channels)
+		sent = append(sent, universe)
 	}
+	s.outputVisualizersLocked(now, cycle)
+	s.delta.cycles++
+	s.transmitSyncLocked(sent)
 
 	// Clear dirty flags after transmission
 	s.isDirty = false
 	s.dirtyUniverses = make(map[int]bool)
-	s.lastTransmissionTime = time.Now()
+	s.lastTransmissionTime = now
 }
 
-// getUniverseOutputChannels returns the channel values with overrides applied.
+// getUniverseOutputChannels returns the channel values with effects,
+// submasters, flashes, the programmer, masters, independents, overrides,
+// blackout, house lights, parked channels, output limits, and channel
+// limits applied.
 func (s *Service) getUniverseOutputChannels(universe int) []byte {
+	if preview := s.previewUniverses[universe]; preview != nil {
+		// The preview universe's own channels stay protected
+		channels := s.previewOutputLocked(preview)
+		s.applyChannelLimitsLocked(universe, channels)
+		return channels
+	}
+
 	baseChannels := s.universes[universe]
 	if baseChannels == nil {
 		return make([]byte, UniverseSize)
@@ -345,6 +542,15 @@ func (s *Service) getUniverseOutputChannels(universe int) []byte {
 
 	outputChannels := make([]byte, UniverseSize)
 	copy(outputChannels, baseChannels)
+	s.applyEffectLayersLocked(universe, outputChannels)
+	s.applySubmastersLocked(universe, outputChannels)
+	s.applyFlashesLocked(universe, outputChannels)
+	s.applyProgrammerLocked(universe, outputChannels)
+
+	// Scale intensities by the masters; independents and overrides are raw
+	// values and bypass them
+	s.applyMastersLocked(universe, outputChannels)
+	s.applyIndependentsLocked(universe, outputChannels)
 
 	// Apply overrides
 	for i := 0; i < UniverseSize; i++ {
@@ -354,13 +560,100 @@ func (s *Service) getUniverseOutputChannels(universe int) []byte {
 		}
 	}
 
+	// A blackout darkens everything, overrides included
+	s.applyBlackoutLocked(universe, outputChannels)
+
+	// House lights answer to front of house, whatever the stage is doing
+	s.applyHouseLightsLocked(universe, outputChannels)
+
+	// Parked channels ignore everything above
+	s.applyParkedLocked(universe, outputChannels)
+
+	// Apply output limits last so nothing can exceed them
+	for channel, max := range s.outputLimits[universe] {
+		if outputChannels[channel-1] > max {
+			outputChannels[channel-1] = max
+		}
+	}
+	s.applyChannelLimitsLocked(universe, outputChannels)
+
 	return outputChannels
 }
 
+// SetOutputLimits replaces all output ceilings. limits maps universe to
+// 1-indexed channel to the highest value that channel may output.
+func (s *Service) SetOutputLimits(limits map[int]map[int]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	affected := make(map[int]bool)
+	for universe := range s.outputLimits {
+		affected[universe] = true
+	}
+
+	s.outputLimits = make(map[int]map[int]byte, len(limits))
+	for universe, channels := range limits {
+		valid := make(map[int]byte, len(channels))
+		for channel, max := range channels {
+			if channel >= 1 && channel <= UniverseSize {
+				valid[channel] = max
+			}
+		}
+		if len(valid) > 0 {
+			s.outputLimits[universe] = valid
+			affected[universe] = true
+		}
+	}
+
+	if len(affected) > 0 {
+		for universe := range affected {
+			s.markDirty(universe)
+		}
+		s.triggerHighRate()
+	}
+}
+
+// LimitedChannels returns the 1-indexed channels in a universe whose output
+// is currently being held down by an output limit, in ascending order.
+func (s *Service) LimitedChannels(universe int) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limits := s.outputLimits[universe]
+	if len(limits) == 0 {
+		return nil
+	}
+	baseChannels := s.universes[universe]
+
+	var limited []int
+	for channel := 1; channel <= UniverseSize; channel++ {
+		max, ok := limits[channel]
+		if !ok {
+			continue
+		}
+		requested := byte(0)
+		if baseChannels != nil {
+			requested = baseChannels[channel-1]
+		}
+		if val, ok := s.channelOverrides[strconv.Itoa(universe)+":"+strconv.Itoa(channel)]; ok {
+			requested = val
+		}
+		if requested > max {
+			limited = append(limited, channel)
+		}
+	}
+	return limited
+}
+
 // markDirty marks a universe as having changes.
 func (s *Service) markDirty(universe int) {
 	s.isDirty = true
 	s.dirtyUniverses[universe] = true
+	for previewUniverse, preview := range s.previewUniverses {
+		if preview.source == universe {
+			s.dirtyUniverses[previewUniverse] = true
+		}
+	}
 }
 
 // SetChannelValue sets a channel value.
@@ -391,6 +684,52 @@ func (s *Service) SetChannelOverride(universe, channel int, value byte) {
 	}
 
 	key := strconv.Itoa(universe) + ":" + strconv.Itoa(channel)
+	s.cancelOverrideExpiry(key)
+	s.setOverride(universe, key, value)
+}
+
+// SetTimedOverride sets a channel override that clears itself after ttl.
+// Setting or clearing the same channel again replaces the pending expiry.
+func (s *Service) SetTimedOverride(universe, channel int, value byte, ttl time.Duration) error {
+	if universe < 1 || universe > MaxUniverses {
+		return fmt.Errorf("invalid universe: %d", universe)
+	}
+	if channel < 1 || channel > UniverseSize {
+		return fmt.Errorf("invalid channel: %d", channel)
+	}
+	if ttl <= 0 || ttl > MaxOverrideTTL {
+		return fmt.Errorf("override TTL must be between 0 and %.0f seconds", MaxOverrideTTL.Seconds())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strconv.Itoa(universe) + ":" + strconv.Itoa(channel)
+	s.cancelOverrideExpiry(key)
+	s.setOverride(universe, key, value)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Ignore a timer that was replaced after it fired
+		if s.overrideTimers[key] != timer {
+			return
+		}
+		delete(s.overrideTimers, key)
+		if _, exists := s.channelOverrides[key]; exists {
+			delete(s.channelOverrides, key)
+			s.markDirty(universe)
+			s.triggerHighRate()
+		}
+	})
+	s.overrideTimers[key] = timer
+	return nil
+}
+
+// setOverride stores an override value. Caller must hold s.mu.
+func (s *Service) setOverride(universe int, key string, value byte) {
 	currentValue, exists := s.channelOverrides[key]
 
 	if !exists || currentValue != value {
@@ -400,12 +739,21 @@ func (s *Service) SetChannelOverride(universe, channel int, value byte) {
 	}
 }
 
+// cancelOverrideExpiry stops a pending TTL for an override. Caller must hold s.mu.
+func (s *Service) cancelOverrideExpiry(key string) {
+	if timer, ok := s.overrideTimers[key]; ok {
+		timer.Stop()
+		delete(s.overrideTimers, key)
+	}
+}
+
 // ClearChannelOverride removes a channel override.
 func (s *Service) ClearChannelOverride(universe, channel int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	key := strconv.Itoa(universe) + ":" + strconv.Itoa(channel)
+	s.cancelOverrideExpiry(key)
 	if _, exists := s.channelOverrides[key]; exists {
 		delete(s.channelOverrides, key)
 		s.markDirty(universe)
@@ -418,6 +766,10 @@ func (s *Service) ClearAllOverrides() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for key := range s.overrideTimers {
+		s.cancelOverrideExpiry(key)
+	}
+
 	if len(s.channelOverrides) > 0 {
 		// Mark affected universes as dirty
 		affectedUniverses := make(map[int]bool)
@@ -442,13 +794,23 @@ func (s *Service) ClearAllOverrides() {
 	}
 }
 
-// triggerHighRate immediately switches to high rate mode.
+// triggerHighRate immediately switches to high rate mode, waking the
+// transmitLoop so that the next frame is not held back to the idle rate.
 func (s *Service) triggerHighRate() {
+	if s.standby {
+		return
+	}
 	s.lastChangeTime = time.Now()
 	if !s.isInHighRateMode {
 		s.isInHighRateMode = true
 		s.currentRate = s.refreshRateHz
-		log.Printf("📡 DMX transmission: switching to high rate (%dHz) - active fade/transition", s.refreshRateHz)
+		log.Debug("📡 DMX transmission switching to high rate: active fade or transition", "rateHz", s.refreshRateHz)
+		select {
+		case s.resetTickerChan <- struct{}{}:
+			// Signal sent successfully
+		default:
+			// Channel already has a pending signal, no need to send another
+		}
 	}
 }
 
@@ -471,28 +833,16 @@ func (s *Service) TriggerChangeDetection() {
 func (s *Service) ForceImmediateTransmission() {
 	s.mu.Lock()
 
-	wasInIdleMode := !s.isInHighRateMode
 	s.triggerHighRate()
 
 	// Immediately send Art-Net packets for any pending changes
 	// Note: We don't mark all universes dirty here - only universes with actual
 	// pending changes (already marked dirty by SetChannelValue, etc.) are transmitted
-	if s.enabled && s.conn != nil && s.isDirty {
+	if s.hasOutputLocked() && s.isDirty && !s.standby && s.handoffFrames == nil {
 		s.outputDMX()
 	}
 
 	s.mu.Unlock()
-
-	// If we were in idle mode, signal the transmitLoop to reset ticker immediately
-	// This ensures the next frame is sent at 60Hz, not 1Hz
-	if wasInIdleMode {
-		select {
-		case s.resetTickerChan <- struct{}{}:
-			// Signal sent successfully
-		default:
-			// Channel already has a pending signal, no need to send another
-		}
-	}
 }
 
 // GetChannelValue returns the current value of a channel.
@@ -583,8 +933,9 @@ func (s *Service) FadeToBlack() {
 	// Clear active scene
 	s.activeSceneID = nil
 
-	// Clear all overrides
+	// Clear all overrides and the programmer
 	s.channelOverrides = make(map[string]byte)
+	s.clearProgrammerLocked()
 
 	s.triggerHighRate()
 }
@@ -620,6 +971,35 @@ func (s *Service) GetBroadcastAddress() string {
 	return s.broadcastAddr
 }
 
+// GetPort returns the Art-Net UDP port.
+func (s *Service) GetPort() int {
+	return s.port
+}
+
+// IsOwnPacket reports whether a received packet was sent by this service.
+// Broadcast output loops back to listeners on the Art-Net port.
+func (s *Service) IsOwnPacket(from *net.UDPAddr) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if from == nil {
+		return false
+	}
+	if s.conn != nil {
+		local, ok := s.conn.LocalAddr().(*net.UDPAddr)
+		if ok && local.Port == from.Port && local.IP.Equal(from.IP) {
+			return true
+		}
+	}
+	if s.unicastConn != nil {
+		// Unless output is bound, the unicast socket's address is
+		// unspecified, so only its port identifies it
+		local, ok := s.unicastConn.LocalAddr().(*net.UDPAddr)
+		return ok && local.Port == from.Port && (local.IP.IsUnspecified() || local.IP.Equal(from.IP))
+	}
+	return false
+}
+
 // IsActive returns whether DMX output is currently active.
 func (s *Service) IsActive() bool {
 	s.mu.RLock()
@@ -651,6 +1031,76 @@ func (s *Service) CountActiveChannels() int {
 	return count
 }
 
+// EnterStandby blacks out every universe once and then stops transmitting.
+// Channel values are kept, so ExitStandby restores the previous look.
+func (s *Service) EnterStandby() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.standby {
+		return
+	}
+
+	// Fixtures that hold their last frame on signal loss would otherwise stay lit
+	blackout := make([]byte, UniverseSize)
+	for universe := range s.universes {
+		s.transmitLocked(universe, blackout)
+	}
+
+	s.enterStandbyLocked()
+	log.Info("💤 DMX output in standby")
+}
+
+// EnterStandbyHold freezes output on the current look: every universe's last
+// frame is retransmitted at the idle rate, and changes are kept but not sent
+// until ExitStandby.
+func (s *Service) EnterStandbyHold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.standby {
+		return
+	}
+
+	s.heldFrames = make(map[int][]byte, len(s.universes))
+	logical := make(map[int][]byte)
+	for universe := range s.universes {
+		s.heldFrames[universe] = s.patchedOutputLocked(universe, logical)
+	}
+
+	s.enterStandbyLocked()
+	log.Info("💤 DMX output in standby, holding the last look")
+}
+
+func (s *Service) enterStandbyLocked() {
+	s.standby = true
+	s.isInHighRateMode = false
+	s.currentRate = s.idleRateHz
+}
+
+// ExitStandby resumes output, retransmitting every universe immediately.
+func (s *Service) ExitStandby() {
+	s.mu.Lock()
+	if !s.standby {
+		s.mu.Unlock()
+		return
+	}
+	s.standby = false
+	s.heldFrames = nil
+	s.resendAllLocked()
+	s.mu.Unlock()
+
+	log.Info("☀️ DMX output resumed from standby")
+	s.ForceImmediateTransmission()
+}
+
+// IsStandby returns whether output is suspended by standby.
+func (s *Service) IsStandby() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.standby
+}
+
 // Stop stops the DMX service and closes the socket.
 func (s *Service) Stop() {
 	s.mu.Lock()
@@ -665,21 +1115,32 @@ func (s *Service) Stop() {
 	s.running = false
 
 	// Send final blackout packet
-	if s.enabled && s.conn != nil {
+	if s.enabled {
 		for universe := range s.universes {
 			s.universes[universe] = make([]byte, UniverseSize) // All zeros
-			s.sequence++
-			packet := artnet.BuildDMXPacket(universe, s.universes[universe], s.sequence)
-			_, _ = s.conn.Write(packet)
+			s.transmitLocked(universe, s.universes[universe])
 		}
+	}
+	s.closeSocketsLocked()
+
+	log.Info("🎭 DMX Service stopped")
+}
 
+func (s *Service) closeSocketsLocked() {
+	if s.conn != nil {
 		_ = s.conn.Close()
 		s.conn = nil
 	}
-
-	log.Printf("🎭 DMX Service stopped")
+	if s.unicastConn != nil {
+		_ = s.unicastConn.Close()
+		s.unicastConn = nil
+	}
 }
 
+// BroadcastAddressSettingKey is the setting that stores the Art-Net
+// broadcast address, overriding the configured one.
+const BroadcastAddressSettingKey = "artnet_broadcast_address"
+
 // ReloadBroadcastAddress updates the broadcast address and reconnects.
 // If Art-Net was disabled, this will enable it.
 func (s *Service) ReloadBroadcastAddress(newAddress string) error {
@@ -687,7 +1148,7 @@ func (s *Service) ReloadBroadcastAddress(newAddress string) error {
 	defer s.mu.Unlock()
 
 	wasEnabled := s.enabled
-	log.Printf("🔄 Reloading Art-Net broadcast address from %s to %s (was enabled: %v)", s.broadcastAddr, newAddress, wasEnabled)
+	log.Info("🔄 Reloading Art-Net broadcast address", "from", s.broadcastAddr, "to", newAddress, "wasEnabled", wasEnabled)
 
 	// Close existing connection
 	if s.conn != nil {
@@ -705,7 +1166,7 @@ func (s *Service) ReloadBroadcastAddress(newAddress string) error {
 	}
 	s.addr = addr
 
-	conn, err := net.DialUDP("udp4", nil, addr)
+	conn, err := net.DialUDP("udp4", dialAddr(s.binding.Address), addr)
 	if err != nil {
 		return err
 	}
@@ -714,13 +1175,57 @@ func (s *Service) ReloadBroadcastAddress(newAddress string) error {
 	// Enable Art-Net output now that we have a valid broadcast address
 	if !wasEnabled {
 		s.enabled = true
-		log.Printf("✅ Art-Net enabled with broadcast address %s:%d", s.broadcastAddr, s.port)
+		log.Info("✅ Art-Net enabled", "broadcast", s.broadcastAddr, "port", s.port)
 	} else {
-		log.Printf("✅ Art-Net broadcast address updated to %s:%d", s.broadcastAddr, s.port)
+		log.Info("✅ Art-Net broadcast address updated", "broadcast", s.broadcastAddr, "port", s.port)
 	}
+	s.resendAllLocked()
 	return nil
 }
 
+// Sink receives every universe frame the service transmits, letting tests
+// and visualizers observe output without sockets. WriteFrame is called with the service
+// locked, so it must not call back into the service; channels is a fresh
+// copy the sink may keep.
+type Sink interface {
+	WriteFrame(universe int, channels []byte)
+}
+
+// SetSink sets the sink that receives transmitted frames. A sink receives
+// frames even when Art-Net output is disabled.
+func (s *Service) SetSink(sink Sink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sink = sink
+}
+
+// addedSink wraps a sink added with AddSink, giving it an identity for
+// removal even when the sink's type is not comparable.
+type addedSink struct {
+	Sink
+}
+
+// AddSink adds a sink that receives transmitted frames alongside the one
+// set with SetSink, returning a function that removes it again. Added sinks
+// share each frame's channels and must not modify them.
+func (s *Service) AddSink(sink Sink) (remove func()) {
+	added := &addedSink{sink}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sinks = append(s.sinks, added)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, existing := range s.sinks {
+			if existing == added {
+				s.sinks = append(s.sinks[:i:i], s.sinks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
 // DisableArtNet disables Art-Net output and closes the connection.
 func (s *Service) DisableArtNet() {
 	s.mu.Lock()
@@ -732,5 +1237,5 @@ func (s *Service) DisableArtNet() {
 	}
 	s.enabled = false
 	s.broadcastAddr = ""
-	log.Printf("🔌 Art-Net output disabled")
+	log.Info("🔌 Art-Net output disabled")
 }
@@ -148,6 +148,95 @@ func TestClearAllOverrides(t *testing.T) {
 	}
 }
 
+func TestSetTimedOverride(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 101, 10)
+
+	if err := service.SetTimedOverride(1, 101, 255, 50*time.Millisecond); err != nil {
+		t.Fatalf("SetTimedOverride() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[100]; got != 255 {
+		t.Errorf("GetUniverse(1)[100] = %d, want 255 (override)", got)
+	}
+
+	// A plain override on another channel is unaffected by the expiry
+	service.SetChannelOverride(1, 102, 77)
+
+	time.Sleep(150 * time.Millisecond)
+	if got := service.GetUniverse(1)[100]; got != 10 {
+		t.Errorf("GetUniverse(1)[100] = %d after expiry, want 10 (base)", got)
+	}
+	if got := service.GetUniverse(1)[101]; got != 77 {
+		t.Errorf("GetUniverse(1)[101] = %d, want 77 (untimed override)", got)
+	}
+}
+
+func TestSetTimedOverride_ReplacedBeforeExpiry(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+
+	if err := service.SetTimedOverride(1, 1, 200, 50*time.Millisecond); err != nil {
+		t.Fatalf("SetTimedOverride() error: %v", err)
+	}
+	// An untimed override on the same channel cancels the pending expiry
+	service.SetChannelOverride(1, 1, 100)
+
+	time.Sleep(150 * time.Millisecond)
+	if got := service.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("GetUniverse(1)[0] = %d, want 100 (replacement override kept)", got)
+	}
+}
+
+func TestSetTimedOverride_Invalid(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+
+	tests := []struct {
+		universe, channel int
+		ttl               time.Duration
+	}{
+		{0, 1, time.Second},
+		{MaxUniverses + 1, 1, time.Second},
+		{1, 0, time.Second},
+		{1, UniverseSize + 1, time.Second},
+		{1, 1, 0},
+		{1, 1, MaxOverrideTTL + time.Second},
+	}
+	for _, tt := range tests {
+		if err := service.SetTimedOverride(tt.universe, tt.channel, 255, tt.ttl); err == nil {
+			t.Errorf("SetTimedOverride(%d, %d, 255, %v) expected error", tt.universe, tt.channel, tt.ttl)
+		}
+	}
+}
+
+func TestOutputLimits(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 255)
+	service.SetChannelValue(1, 2, 100)
+	service.SetChannelOverride(1, 3, 255)
+
+	service.SetOutputLimits(map[int]map[int]byte{1: {1: 179, 2: 179, 3: 128, 600: 0}})
+
+	universe := service.GetUniverse(1)
+	if universe[0] != 179 || universe[1] != 100 || universe[2] != 128 {
+		t.Errorf("Limited output = %v, want [179 100 128]", universe[:3])
+	}
+	if got := service.GetChannelValue(1, 1); got != 255 {
+		t.Errorf("GetChannelValue should return the unlimited value, got %d", got)
+	}
+
+	limited := service.LimitedChannels(1)
+	if len(limited) != 2 || limited[0] != 1 || limited[1] != 3 {
+		t.Errorf("LimitedChannels(1) = %v, want [1 3]", limited)
+	}
+
+	service.SetOutputLimits(nil)
+	if got := service.GetUniverse(1)[0]; got != 255 {
+		t.Errorf("Output after clearing limits = %d, want 255", got)
+	}
+	if limited := service.LimitedChannels(1); len(limited) != 0 {
+		t.Errorf("Expected no limited channels, got %v", limited)
+	}
+}
+
 func TestSetAllChannels(t *testing.T) {
 	service := NewService(Config{Enabled: false})
 
@@ -1023,10 +1112,11 @@ func TestTransmitLoopConsistentTiming(t *testing.T) {
 	}
 	close(done)
 
-	// At 60Hz with 4 universes, expect ~240 packets/sec
+	// At 60Hz only the changing universe is sent each tick, plus a keep-alive
+	// a second for each of the other 3 universes: expect ~63 packets/sec
 	// With race detector overhead, allow wider tolerance
-	minExpected := 100
-	maxExpected := 350
+	minExpected := 40
+	maxExpected := 100
 
 	t.Logf("Received %d packets over %v", packetCount, testDuration)
 
@@ -1228,3 +1318,218 @@ func TestForceImmediateTransmissionMultipleCalls(t *testing.T) {
 
 	t.Log("✓ Multiple ForceImmediateTransmission calls handled correctly")
 }
+
+// TestStandby verifies that standby blacks out once, suppresses output, and
+// restores the kept channel values on wake.
+func TestStandby(t *testing.T) {
+	testPort := 6558
+
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort}
+	listener, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{
+		Enabled:          true,
+		BroadcastAddr:    "127.0.0.1",
+		Port:             testPort,
+		RefreshRateHz:    100,
+		IdleRateHz:       10,
+		HighRateDuration: 5 * time.Second,
+	})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	// readChannel1 returns channel 1 of the next universe 1 packet, or -1
+	// on timeout
+	readChannel1 := func(timeout time.Duration) int {
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(timeout))
+		for {
+			n, _, err := listener.ReadFromUDP(buffer)
+			if err != nil {
+				return -1
+			}
+			if n >= 19 && buffer[14] == 0 && buffer[15] == 0 {
+				return int(buffer[18])
+			}
+		}
+	}
+	// waitForChannel1 reads packets until channel 1 has the wanted value
+	waitForChannel1 := func(want int) bool {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if readChannel1(time.Until(deadline)) == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	service.SetChannelValue(1, 1, 200)
+	if !waitForChannel1(200) {
+		t.Fatal("Expected channel 1 at 200 before standby")
+	}
+
+	service.EnterStandby()
+	if !service.IsStandby() {
+		t.Fatal("Expected service to be in standby")
+	}
+	if !waitForChannel1(0) {
+		t.Error("Expected blackout frame on entering standby")
+	}
+
+	// Changes are kept but not sent while in standby
+	service.SetChannelValue(1, 1, 150)
+	service.ForceImmediateTransmission()
+	if got := readChannel1(300 * time.Millisecond); got != -1 {
+		t.Errorf("Expected no output in standby, got channel 1 = %d", got)
+	}
+	if got := service.GetChannelValue(1, 1); got != 150 {
+		t.Errorf("Expected channel value kept in standby, got %d", got)
+	}
+
+	service.ExitStandby()
+	if service.IsStandby() {
+		t.Fatal("Expected service to be awake")
+	}
+	if got := readChannel1(time.Second); got != 150 {
+		t.Errorf("Expected channel 1 at 150 in the first frame after wake, got %d", got)
+	}
+}
+
+func TestStandbyHold(t *testing.T) {
+	testPort := 6599
+
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort}
+	listener, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{
+		Enabled:          true,
+		BroadcastAddr:    "127.0.0.1",
+		Port:             testPort,
+		RefreshRateHz:    100,
+		IdleRateHz:       10,
+		HighRateDuration: 5 * time.Second,
+	})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	// readChannel1 returns channel 1 of the next universe 1 packet, or -1
+	// on timeout
+	readChannel1 := func(timeout time.Duration) int {
+		buffer := make([]byte, 1024)
+		_ = listener.SetReadDeadline(time.Now().Add(timeout))
+		for {
+			n, _, err := listener.ReadFromUDP(buffer)
+			if err != nil {
+				return -1
+			}
+			if n >= 19 && buffer[14] == 0 && buffer[15] == 0 {
+				return int(buffer[18])
+			}
+		}
+	}
+
+	service.SetChannelValue(1, 1, 200)
+	service.ForceImmediateTransmission()
+	service.EnterStandbyHold()
+	if !service.IsStandby() {
+		t.Fatal("Expected service to be in standby")
+	}
+
+	// The held look keeps being sent, and changes wait for wake
+	service.SetChannelValue(1, 1, 150)
+	service.ForceImmediateTransmission()
+	deadline := time.Now().Add(500 * time.Millisecond)
+	packets := 0
+	for time.Now().Before(deadline) {
+		got := readChannel1(time.Until(deadline))
+		if got == -1 {
+			break
+		}
+		if got != 200 {
+			t.Fatalf("Expected the held look in standby, got channel 1 = %d", got)
+		}
+		packets++
+	}
+	if packets < 2 {
+		t.Errorf("Expected the held look to keep being sent, got %d packets", packets)
+	}
+
+	service.ExitStandby()
+	deadline = time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if readChannel1(time.Until(deadline)) == 150 {
+			return
+		}
+	}
+	t.Error("Expected channel 1 at 150 after wake")
+}
+
+func TestIsOwnPacket(t *testing.T) {
+	testPort := 6594
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: testPort})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	service.SetChannelValue(1, 1, 255)
+	service.ForceImmediateTransmission()
+
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, 1024)
+	_, from, err := listener.ReadFromUDP(buffer)
+	if err != nil {
+		t.Fatalf("No packet received: %v", err)
+	}
+	if !service.IsOwnPacket(from) {
+		t.Errorf("Expected packet from %v to be recognized as own output", from)
+	}
+	if service.IsOwnPacket(&net.UDPAddr{IP: net.ParseIP("10.0.0.9"), Port: from.Port}) {
+		t.Error("Expected packet from another host not to be own output")
+	}
+}
+
+// frameCounter counts the frames written to it per universe.
+type frameCounter map[int]int
+
+func (c frameCounter) WriteFrame(universe int, channels []byte) { c[universe]++ }
+
+func TestAddSink(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	first, second := frameCounter{}, frameCounter{}
+	removeFirst := service.AddSink(first)
+	service.AddSink(second)
+
+	service.SetChannelValue(1, 1, 255)
+	service.processTransmission()
+	if first[1] != 1 || second[1] != 1 {
+		t.Fatalf("Frames = %v and %v, want one each for universe 1", first, second)
+	}
+
+	removeFirst()
+	removeFirst() // Removing twice is harmless
+	service.SetChannelValue(1, 1, 0)
+	service.processTransmission()
+	if first[1] != 1 || second[1] != 2 {
+		t.Errorf("Frames = %v and %v, want only the remaining sink to receive more", first, second)
+	}
+}
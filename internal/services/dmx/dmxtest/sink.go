@@ -0,0 +1,170 @@
+// Package dmxtest provides a mock DMX output sink for tests. Attached to a
+// dmx.Service it records every transmitted universe frame with a timestamp,
+// so tests can drive the server through GraphQL and assert on the resulting
+// output without opening sockets.
+package dmxtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// maxFramesPerUniverse bounds memory use; the oldest frames are discarded
+// once a universe has this many (~1 minute at 60Hz).
+const maxFramesPerUniverse = 3600
+
+// Frame is one transmitted universe.
+type Frame struct {
+	Universe int
+	Channels []byte // 512 values, channel 1 at index 0
+	At       time.Time
+}
+
+// Sink records transmitted frames. It implements dmx.Sink.
+type Sink struct {
+	mu      sync.Mutex
+	frames  map[int][]Frame
+	changed chan struct{} // Closed and replaced on every frame
+}
+
+// NewSink creates an empty sink.
+func NewSink() *Sink {
+	return &Sink{
+		frames:  make(map[int][]Frame),
+		changed: make(chan struct{}),
+	}
+}
+
+// Attach creates a sink and sets it as the output sink of service. Frames
+// flow once the service is initialized.
+func Attach(service *dmx.Service) *Sink {
+	sink := NewSink()
+	service.SetSink(sink)
+	return sink
+}
+
+// WriteFrame records a frame.
+func (s *Sink) WriteFrame(universe int, channels []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frames := s.frames[universe]
+	if len(frames) >= maxFramesPerUniverse {
+		frames = frames[1:]
+	}
+	s.frames[universe] = append(frames, Frame{Universe: universe, Channels: channels, At: time.Now()})
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Frames returns the recorded frames of a universe, oldest first.
+func (s *Sink) Frames(universe int) []Frame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Frame(nil), s.frames[universe]...)
+}
+
+// FramesSince returns the frames of a universe transmitted at or after t.
+func (s *Sink) FramesSince(universe int, t time.Time) []Frame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var frames []Frame
+	for _, frame := range s.frames[universe] {
+		if !frame.At.Before(t) {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// Last returns the most recent frame of a universe.
+func (s *Sink) Last(universe int) (Frame, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	frames := s.frames[universe]
+	if len(frames) == 0 {
+		return Frame{}, false
+	}
+	return frames[len(frames)-1], true
+}
+
+// Channel returns the last transmitted value of a 1-indexed channel.
+func (s *Sink) Channel(universe, channel int) (byte, bool) {
+	frame, ok := s.Last(universe)
+	if !ok || channel < 1 || channel > len(frame.Channels) {
+		return 0, false
+	}
+	return frame.Channels[channel-1], true
+}
+
+// Reset discards all recorded frames.
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = make(map[int][]Frame)
+}
+
+// WaitFor waits up to within for the last frame of universe to satisfy
+// match, and reports whether it did.
+func (s *Sink) WaitFor(universe int, within time.Duration, match func(channels []byte) bool) bool {
+	deadline := time.NewTimer(within)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		frames := s.frames[universe]
+		changed := s.changed
+		s.mu.Unlock()
+
+		if len(frames) > 0 && match(frames[len(frames)-1].Channels) {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return false
+		}
+	}
+}
+
+// ExpectChannel fails the test unless a 1-indexed channel is transmitted
+// at value within the given time.
+func (s *Sink) ExpectChannel(t testing.TB, universe, channel int, value byte, within time.Duration) {
+	t.Helper()
+	s.ExpectChannels(t, universe, map[int]byte{channel: value}, within)
+}
+
+// ExpectChannels fails the test unless a single frame of universe carries
+// all the given 1-indexed channel values within the given time.
+func (s *Sink) ExpectChannels(t testing.TB, universe int, values map[int]byte, within time.Duration) {
+	t.Helper()
+	matched := s.WaitFor(universe, within, func(channels []byte) bool {
+		for channel, value := range values {
+			if channel < 1 || channel > len(channels) || channels[channel-1] != value {
+				return false
+			}
+		}
+		return true
+	})
+	if matched {
+		return
+	}
+
+	frame, ok := s.Last(universe)
+	if !ok {
+		t.Errorf("universe %d: no frames transmitted within %v", universe, within)
+		return
+	}
+	for channel, value := range values {
+		got := byte(0)
+		if channel >= 1 && channel <= len(frame.Channels) {
+			got = frame.Channels[channel-1]
+		}
+		if got != value {
+			t.Errorf("universe %d channel %d = %d, want %d within %v", universe, channel, got, value, within)
+		}
+	}
+}
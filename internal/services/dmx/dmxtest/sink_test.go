@@ -0,0 +1,63 @@
+package dmxtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+func TestSinkRecordsOutput(t *testing.T) {
+	cfg := dmx.DefaultConfig()
+	cfg.Enabled = false
+	service := dmx.NewService(cfg)
+	sink := Attach(service)
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	start := time.Now()
+	service.SetChannelValue(1, 10, 200)
+	service.SetChannelOverride(2, 1, 77)
+	service.ForceImmediateTransmission()
+
+	sink.ExpectChannel(t, 1, 10, 200, time.Second)
+	sink.ExpectChannels(t, 2, map[int]byte{1: 77, 2: 0}, time.Second)
+
+	if value, ok := sink.Channel(1, 10); !ok || value != 200 {
+		t.Errorf("Channel(1, 10) = %d, %v; want 200", value, ok)
+	}
+	frames := sink.FramesSince(1, start)
+	if len(frames) == 0 || frames[0].At.Before(start) || len(frames[0].Channels) != dmx.UniverseSize {
+		t.Errorf("Expected timestamped full-universe frames, got %d", len(frames))
+	}
+
+	sink.Reset()
+	if _, ok := sink.Last(1); ok {
+		t.Error("Expected no frames after Reset")
+	}
+}
+
+func TestSinkWaitForTimesOut(t *testing.T) {
+	sink := NewSink()
+	sink.WriteFrame(1, make([]byte, dmx.UniverseSize))
+
+	start := time.Now()
+	if sink.WaitFor(1, 50*time.Millisecond, func(channels []byte) bool { return channels[0] == 1 }) {
+		t.Error("Expected WaitFor to time out")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("WaitFor returned after %v, before its timeout", elapsed)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		channels := make([]byte, dmx.UniverseSize)
+		channels[0] = 1
+		sink.WriteFrame(1, channels)
+	}()
+	if !sink.WaitFor(1, time.Second, func(channels []byte) bool { return channels[0] == 1 }) {
+		t.Error("Expected WaitFor to see the new frame")
+	}
+}
@@ -0,0 +1,67 @@
+package dmx
+
+import "testing"
+
+func TestFlashes(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100) // Dimmer
+	service.SetChannelValue(1, 2, 10)  // Color
+
+	// A submaster below the flash sets the color too
+	if err := service.SetSubmasters(map[string]Submaster{
+		"sub": {Level: 1, Other: map[int]map[int]byte{1: {2: 40}}},
+	}); err != nil {
+		t.Fatalf("SetSubmasters() error: %v", err)
+	}
+
+	err := service.SetFlash("a", Flash{
+		Level:     1,
+		Intensity: map[int]map[int]byte{1: {1: 255}},
+		Other:     map[int]map[int]byte{1: {2: 80}},
+	})
+	if err != nil {
+		t.Fatalf("SetFlash() error: %v", err)
+	}
+	if universe := service.GetUniverse(1); universe[0] != 255 || universe[1] != 80 {
+		t.Errorf("Output while flashing = %v, want [255 80]", universe[:2])
+	}
+
+	// A later flash wins where they overlap
+	if err := service.SetFlash("b", Flash{Level: 1, Other: map[int]map[int]byte{1: {2: 120}}}); err != nil {
+		t.Fatalf("SetFlash() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[1]; got != 120 {
+		t.Errorf("Overlapping flash color = %d, want 120", got)
+	}
+	service.ClearFlash("b")
+
+	if err := service.SetFlashLevel("a", 0.2); err != nil {
+		t.Fatalf("SetFlashLevel() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("Flash intensity below the live value should not win, got %d", got)
+	}
+
+	// The grand master scales the flash like any other output
+	service.SetMasterChannels(map[int][]int{1: {1}})
+	if err := service.SetFlashLevel("a", 1); err != nil {
+		t.Fatalf("SetFlashLevel() error: %v", err)
+	}
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 128 {
+		t.Errorf("Flash at half grand master = %d, want 128", got)
+	}
+
+	service.ClearFlash("a")
+	if got := service.GetUniverse(1)[1]; got != 40 {
+		t.Errorf("Color after the flash ended = %d, want the submaster's 40", got)
+	}
+	if err := service.SetFlashLevel("a", 0.5); err == nil {
+		t.Error("Expected an error for a flash that ended")
+	}
+	if err := service.SetFlash("c", Flash{Level: 1.5}); err == nil {
+		t.Error("Expected an error for a level above 1")
+	}
+}
@@ -0,0 +1,188 @@
+package dmx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A restarting server can hand its last frame to the next one instead of
+// blacking out: it stops without sending zeros, so nodes hold the look, and
+// saves the frame. The next server retransmits the frame in place of its
+// own output until it has restored its state.
+
+// ShutdownMode is what the output does when the server stops.
+type ShutdownMode string
+
+const (
+	// ShutdownBlackout sends zeros to every universe straight away.
+	ShutdownBlackout ShutdownMode = "BLACKOUT"
+	// ShutdownFade fades the intensity channels out before blacking out.
+	ShutdownFade ShutdownMode = "FADE"
+	// ShutdownHold leaves the last frame on the rig and hands it to the
+	// next server.
+	ShutdownHold ShutdownMode = "HOLD"
+)
+
+// MaxHandoffAge is the oldest handoff a starting server holds; an older one
+// is from a server that stopped for longer than a restart.
+const MaxHandoffAge = 5 * time.Minute
+
+// ParseShutdownMode converts a configuration string to a ShutdownMode. An
+// empty string is a blackout.
+func ParseShutdownMode(value string) (ShutdownMode, error) {
+	switch mode := ShutdownMode(strings.ToUpper(strings.TrimSpace(value))); mode {
+	case "":
+		return ShutdownBlackout, nil
+	case ShutdownBlackout, ShutdownFade, ShutdownHold:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid shutdown output %q (want blackout, fade, or hold)", value)
+	}
+}
+
+// handoff is the file a stopping server leaves for the next one.
+type handoff struct {
+	SavedAt time.Time `json:"savedAt"`
+	// Transmitted channels by universe
+	Universes map[int][]byte `json:"universes"`
+}
+
+// SaveHandoff writes frames (universe -> channels) for the next server to
+// hold while it starts.
+func SaveHandoff(path string, frames map[int][]byte) error {
+	data, err := json.Marshal(handoff{SavedAt: time.Now(), Universes: frames})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	return nil
+}
+
+// LoadHandoff reads and removes the frames a stopped server saved. It
+// returns nil when there are none, or they are older than MaxHandoffAge.
+func LoadHandoff(path string) (map[int][]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handoff: %w", err)
+	}
+	// A handoff is only taken over once
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("failed to remove handoff: %w", err)
+	}
+
+	var saved handoff
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid handoff: %w", err)
+	}
+	if time.Since(saved.SavedAt) > MaxHandoffAge {
+		return nil, nil
+	}
+	frames := make(map[int][]byte, len(saved.Universes))
+	for universe, channels := range saved.Universes {
+		if len(channels) == UniverseSize {
+			frames[universe] = channels
+		}
+	}
+	return frames, nil
+}
+
+// StopHolding stops the service like Stop, but sends no blackout, so nodes
+// hold the last frame. It returns the frames last output (universe ->
+// channels) for SaveHandoff.
+func (s *Service) StopHolding() map[int][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return nil
+	}
+	close(s.stopChan)
+	s.running = false
+
+	var frames map[int][]byte
+	switch {
+	case s.handoffFrames != nil:
+		// Still holding the previous server's frames
+		frames = s.handoffFrames
+	case s.standby && s.heldFrames != nil:
+		frames = s.heldFrames
+	case !s.standby:
+		frames = make(map[int][]byte)
+		var universes []int
+		for universe := range s.universes {
+			universes = append(universes, universe)
+		}
+		logical := make(map[int][]byte)
+		for _, universe := range s.patchedUniversesLocked(universes) {
+			frames[universe] = s.patchedOutputLocked(universe, logical)
+		}
+	}
+	s.closeSocketsLocked()
+
+	log.Info("🎭 DMX Service stopped, holding the last frame", "universes", len(frames))
+	return frames
+}
+
+// HoldHandoff retransmits frames handed off by a stopped server in place of
+// this service's output, until ReleaseHandoff.
+func (s *Service) HoldHandoff(frames map[int][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handoffFrames = make(map[int][]byte, len(frames))
+	for universe, channels := range frames {
+		s.handoffFrames[universe] = append([]byte(nil), channels...)
+	}
+}
+
+// ReleaseHandoff stops holding handed-off frames and sends this service's
+// own output straight away.
+func (s *Service) ReleaseHandoff() {
+	s.mu.Lock()
+	if s.handoffFrames == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.handoffFrames = nil
+	s.resendAllLocked()
+	s.mu.Unlock()
+
+	log.Info("🤝 Released the handed-off frame to live output")
+	s.ForceImmediateTransmission()
+}
+
+// transmitHandoffLocked retransmits the handed-off frames, reporting
+// whether there are any.
+func (s *Service) transmitHandoffLocked() bool {
+	if s.handoffFrames == nil {
+		return false
+	}
+	if s.hasOutputLocked() {
+		for universe, channels := range s.handoffFrames {
+			s.transmitLocked(universe, channels)
+		}
+	}
+	return true
+}
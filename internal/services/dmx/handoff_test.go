@@ -0,0 +1,116 @@
+package dmx
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// receiveChannel1 returns channel 1 of each ArtDmx packet received until
+// the listener goes quiet.
+func receiveChannel1(t *testing.T, listener *net.UDPConn) []byte {
+	t.Helper()
+	var values []byte
+	buffer := make([]byte, 1024)
+	for {
+		_ = listener.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		n, _, err := listener.ReadFromUDP(buffer)
+		if err != nil {
+			return values
+		}
+		if n >= 18+UniverseSize {
+			values = append(values, buffer[18])
+		}
+	}
+}
+
+func TestHandoff_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handoff.json")
+	if frames, err := LoadHandoff(path); frames != nil || err != nil {
+		t.Errorf("LoadHandoff() without a file = %v, %v; want nil, nil", frames, err)
+	}
+
+	frame := make([]byte, UniverseSize)
+	frame[0] = 10
+	if err := SaveHandoff(path, map[int][]byte{1: frame, 2: {1, 2}}); err != nil {
+		t.Fatalf("SaveHandoff() error: %v", err)
+	}
+	frames, err := LoadHandoff(path)
+	if err != nil {
+		t.Fatalf("LoadHandoff() error: %v", err)
+	}
+	if len(frames) != 1 || frames[1][0] != 10 {
+		t.Errorf("Expected universe 1 only, got %v", frames)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the handoff removed once loaded")
+	}
+
+	// A handoff from long ago is not held
+	stale := `{"savedAt":"` + time.Now().Add(-MaxHandoffAge-time.Minute).Format(time.RFC3339) + `","universes":{}}`
+	if err := os.WriteFile(path, []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if frames, err := LoadHandoff(path); frames != nil || err != nil {
+		t.Errorf("LoadHandoff() of a stale handoff = %v, %v; want nil, nil", frames, err)
+	}
+}
+
+func TestHandoff_Hold(t *testing.T) {
+	port := 6591
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	cfg := Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: port, RefreshRateHz: 1, IdleRateHz: 1}
+
+	// Stopping while holding sends no blackout
+	stopping := NewService(cfg)
+	if err := stopping.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	stopping.SetChannelValue(1, 1, 200)
+	stopping.ForceImmediateTransmission()
+	receiveChannel1(t, listener)
+	frames := stopping.StopHolding()
+	if frames[1] == nil || frames[1][0] != 200 {
+		t.Fatalf("Expected the last frame, got %v", frames[1])
+	}
+	if values := receiveChannel1(t, listener); len(values) != 0 {
+		t.Errorf("Expected nothing sent on stopping, got %v", values)
+	}
+
+	// The next server sends the handed-off frame until it is released
+	starting := NewService(cfg)
+	starting.HoldHandoff(frames)
+	if err := starting.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer starting.Stop()
+	starting.SetChannelValue(1, 1, 5)
+	starting.ForceImmediateTransmission()
+	starting.processTransmission()
+	if values := receiveChannel1(t, listener); !slices.Contains(values, 200) || slices.Contains(values, 5) {
+		t.Errorf("Expected only the handed-off frame, got %v", values)
+	}
+
+	starting.ReleaseHandoff()
+	if values := receiveChannel1(t, listener); !slices.Contains(values, 5) || slices.Contains(values, 200) {
+		t.Errorf("Expected live output straight after release, got %v", values)
+	}
+}
+
+func TestParseShutdownMode(t *testing.T) {
+	for value, want := range map[string]ShutdownMode{"": ShutdownBlackout, "fade": ShutdownFade, " Hold ": ShutdownHold} {
+		if mode, err := ParseShutdownMode(value); err != nil || mode != want {
+			t.Errorf("ParseShutdownMode(%q) = %q, %v; want %q", value, mode, err, want)
+		}
+	}
+	if _, err := ParseShutdownMode("dim"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
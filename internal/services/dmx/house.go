@@ -0,0 +1,59 @@
+package dmx
+
+// SetHouseLights sets the output of a set of house lights: universe ->
+// 1-indexed channel -> value. House light values replace whatever else
+// drives their channels and, so front of house keeps control whatever the
+// stage is doing, are neither scaled by masters nor darkened by blackout.
+// Output and channel limits still apply. Where sets overlap, the most
+// recently added one wins.
+func (s *Service) SetHouseLights(id string, values map[int]map[int]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, exists := s.houseLights[id]
+	if !exists {
+		s.houseOrder = append(s.houseOrder, id)
+	}
+	s.houseLights[id] = values
+	for universe := range previous {
+		s.markDirty(universe)
+	}
+	for universe := range values {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// ClearHouseLights removes a set of house lights, handing their channels
+// back to the rest of the output.
+func (s *Service) ClearHouseLights(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, exists := s.houseLights[id]
+	if !exists {
+		return
+	}
+	delete(s.houseLights, id)
+	for i, layer := range s.houseOrder {
+		if layer == id {
+			s.houseOrder = append(s.houseOrder[:i], s.houseOrder[i+1:]...)
+			break
+		}
+	}
+	for universe := range values {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// applyHouseLightsLocked writes house light output over a universe in place.
+func (s *Service) applyHouseLightsLocked(universe int, channels []byte) {
+	for _, id := range s.houseOrder {
+		for channel, value := range s.houseLights[id][universe] {
+			if channel >= 1 && channel <= UniverseSize {
+				channels[channel-1] = value
+			}
+		}
+	}
+}
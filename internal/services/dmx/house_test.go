@@ -0,0 +1,42 @@
+package dmx
+
+import "testing"
+
+func TestHouseLights(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(1, 2, 100)
+	service.SetMasterChannels(map[int][]int{1: {1, 2}})
+	service.SetBlackoutChannels(map[int]map[int]bool{1: {1: true, 2: true}})
+
+	service.SetHouseLights("house", map[int]map[int]byte{1: {1: 255}})
+	if got := service.GetUniverse(1)[0]; got != 255 {
+		t.Errorf("House light output = %d, want 255", got)
+	}
+
+	// Masters and blackout leave house lights alone
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if _, err := service.Blackout(0); err != nil {
+		t.Fatalf("Blackout() error: %v", err)
+	}
+	universe := service.GetUniverse(1)
+	if universe[0] != 255 || universe[1] != 0 {
+		t.Errorf("Output in blackout = %v, want [255 0]", universe[:2])
+	}
+
+	// Output limits still apply
+	service.SetOutputLimits(map[int]map[int]byte{1: {1: 200}})
+	if got := service.GetUniverse(1)[0]; got != 200 {
+		t.Errorf("Limited house light output = %d, want 200", got)
+	}
+	service.SetOutputLimits(nil)
+
+	// Clearing hands the channel back to the rest of the output
+	service.ClearHouseLights("house")
+	service.ClearHouseLights("missing")
+	if got := service.GetUniverse(1)[0]; got != 0 {
+		t.Errorf("Output after clearing house lights = %d, want 0 in blackout", got)
+	}
+}
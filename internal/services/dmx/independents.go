@@ -0,0 +1,113 @@
+package dmx
+
+import (
+	"fmt"
+	"time"
+)
+
+// Independents hold channels at set values until released, whatever cue
+// lists, scenes, effects and the programmer do below them, like a stage
+// manager's desk light. Like overrides they are raw values that masters do
+// not scale; overrides, blackout, house lights and limits still apply on
+// top.
+
+// Independent is a named set of channels held at fixed values.
+type Independent struct {
+	ID        string
+	Name      string
+	ProjectID *string // The project it was set for, if any
+	// Values: universe -> 1-indexed channel -> value
+	Values map[int]map[int]byte
+	Since  time.Time
+}
+
+// SetIndependent sets an independent, replacing any with its ID, and puts
+// it above the others.
+func (s *Service) SetIndependent(independent Independent) error {
+	if independent.ID == "" {
+		return fmt.Errorf("independent ID is required")
+	}
+	for universe, channels := range independent.Values {
+		if universe < 1 || universe > MaxUniverses {
+			return fmt.Errorf("invalid universe: %d", universe)
+		}
+		for channel := range channels {
+			if channel < 1 || channel > UniverseSize {
+				return fmt.Errorf("invalid channel: %d", channel)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeIndependentLocked(independent.ID)
+	s.independents[independent.ID] = &independent
+	s.independentOrder = append(s.independentOrder, independent.ID)
+	for universe := range independent.Values {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+	return nil
+}
+
+// ReleaseIndependent hands an independent's channels back to the output
+// below, reporting whether it was set.
+func (s *Service) ReleaseIndependent(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.removeIndependentLocked(id) {
+		return false
+	}
+	s.triggerHighRate()
+	return true
+}
+
+// removeIndependentLocked removes an independent. Caller must hold s.mu.
+func (s *Service) removeIndependentLocked(id string) bool {
+	independent, exists := s.independents[id]
+	if !exists {
+		return false
+	}
+	delete(s.independents, id)
+	for i, layer := range s.independentOrder {
+		if layer == id {
+			s.independentOrder = append(s.independentOrder[:i], s.independentOrder[i+1:]...)
+			break
+		}
+	}
+	for universe := range independent.Values {
+		s.markDirty(universe)
+	}
+	return true
+}
+
+// Independents returns copies of the independents, lowest first.
+func (s *Service) Independents() []Independent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	independents := make([]Independent, 0, len(s.independentOrder))
+	for _, id := range s.independentOrder {
+		independent := *s.independents[id]
+		independent.Values = make(map[int]map[int]byte, len(independent.Values))
+		for universe, channels := range s.independents[id].Values {
+			independent.Values[universe] = make(map[int]byte, len(channels))
+			for channel, value := range channels {
+				independent.Values[universe][channel] = value
+			}
+		}
+		independents = append(independents, independent)
+	}
+	return independents
+}
+
+// applyIndependentsLocked writes the independents over a universe in place.
+func (s *Service) applyIndependentsLocked(universe int, channels []byte) {
+	for _, id := range s.independentOrder {
+		for channel, value := range s.independents[id].Values[universe] {
+			channels[channel-1] = value
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dmx
+
+import "testing"
+
+func TestIndependents(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetMasterChannels(map[int][]int{1: {1, 2}})
+	service.SetProgrammerValue(1, 2, 50)
+
+	desk := Independent{ID: "desk", Name: "SM Desk", Values: map[int]map[int]byte{1: {1: 200, 2: 180}}}
+	if err := service.SetIndependent(desk); err != nil {
+		t.Fatalf("SetIndependent() error: %v", err)
+	}
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	// Independents win over the programmer and bypass the masters
+	if universe := service.GetUniverse(1); universe[0] != 200 || universe[1] != 180 {
+		t.Errorf("Output = %v, want [200 180]", universe[:2])
+	}
+
+	// Playback changes underneath leave them alone
+	service.SetChannelValue(1, 1, 0)
+	service.ClearProgrammer()
+	if got := service.GetUniverse(1)[0]; got != 200 {
+		t.Errorf("Output after playback change = %d, want 200", got)
+	}
+
+	// The most recently set independent wins, and overrides win over both
+	if err := service.SetIndependent(Independent{ID: "work", Values: map[int]map[int]byte{1: {1: 90}}}); err != nil {
+		t.Fatalf("SetIndependent() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 90 {
+		t.Errorf("Overlapping output = %d, want 90", got)
+	}
+	service.SetChannelOverride(1, 1, 10)
+	if got := service.GetUniverse(1)[0]; got != 10 {
+		t.Errorf("Overridden output = %d, want 10", got)
+	}
+	service.ClearChannelOverride(1, 1)
+
+	if independents := service.Independents(); len(independents) != 2 || independents[0].ID != "desk" || independents[1].ID != "work" {
+		t.Errorf("Independents() = %+v", independents)
+	}
+
+	if !service.ReleaseIndependent("work") || service.ReleaseIndependent("work") {
+		t.Error("ReleaseIndependent() should report only the first release")
+	}
+	if got := service.GetUniverse(1)[0]; got != 200 {
+		t.Errorf("Output after release = %d, want 200", got)
+	}
+	service.ReleaseIndependent("desk")
+	if universe := service.GetUniverse(1); universe[0] != 0 || universe[1] != 0 {
+		t.Errorf("Output after releasing all = %v, want [0 0]", universe[:2])
+	}
+
+	if err := service.SetIndependent(Independent{ID: "bad", Values: map[int]map[int]byte{1: {513: 1}}}); err == nil {
+		t.Error("SetIndependent() should reject channel 513")
+	}
+	if err := service.SetIndependent(Independent{Values: map[int]map[int]byte{1: {1: 1}}}); err == nil {
+		t.Error("SetIndependent() should require an ID")
+	}
+}
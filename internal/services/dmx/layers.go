@@ -0,0 +1,53 @@
+package dmx
+
+// SetEffectLayer sets the output of an effect: universe -> 1-indexed
+// channel -> value. Effect layers replace the channel values they cover
+// without changing them, so clearing a layer reveals the live values again.
+// Where layers overlap, the most recently added one wins. Masters, overrides,
+// blackout, and output limits still apply on top.
+func (s *Service) SetEffectLayer(id string, values map[int]map[int]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.effectLayers[id]; !exists {
+		s.effectOrder = append(s.effectOrder, id)
+	}
+	s.effectLayers[id] = values
+	for universe := range values {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// ClearEffectLayer removes an effect's output.
+func (s *Service) ClearEffectLayer(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, exists := s.effectLayers[id]
+	if !exists {
+		return
+	}
+	delete(s.effectLayers, id)
+	for i, layer := range s.effectOrder {
+		if layer == id {
+			s.effectOrder = append(s.effectOrder[:i], s.effectOrder[i+1:]...)
+			break
+		}
+	}
+	for universe := range values {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// applyEffectLayersLocked writes effect output over a universe in place.
+func (s *Service) applyEffectLayersLocked(universe int, channels []byte) {
+	for _, id := range s.effectOrder {
+		for channel, value := range s.effectLayers[id][universe] {
+			if channel >= 1 && channel <= UniverseSize {
+				channels[channel-1] = value
+			}
+		}
+	}
+}
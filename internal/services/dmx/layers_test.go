@@ -0,0 +1,47 @@
+package dmx
+
+import "testing"
+
+func TestEffectLayers(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(1, 2, 100)
+	service.SetMasterChannels(map[int][]int{1: {1, 2}})
+
+	service.SetEffectLayer("a", map[int]map[int]byte{1: {1: 200, 2: 200}})
+	service.SetEffectLayer("b", map[int]map[int]byte{1: {2: 50}})
+	universe := service.GetUniverse(1)
+	if universe[0] != 200 || universe[1] != 50 {
+		t.Errorf("Output with layers = %v, want [200 50]", universe[:2])
+	}
+	if got := service.GetChannelValue(1, 1); got != 100 {
+		t.Errorf("Layers should not change channel values, got %d", got)
+	}
+
+	// Masters scale effect output
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("Mastered effect output = %d, want 100", got)
+	}
+	if err := service.SetGrandMaster(1); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+
+	// Updating a layer keeps its place; clearing one reveals what is below
+	service.SetEffectLayer("a", map[int]map[int]byte{1: {1: 150, 2: 150}})
+	if got := service.GetUniverse(1)[1]; got != 50 {
+		t.Errorf("Later layer should still win, got %d", got)
+	}
+	service.ClearEffectLayer("b")
+	service.ClearEffectLayer("missing")
+	if got := service.GetUniverse(1)[1]; got != 150 {
+		t.Errorf("Output after clearing layer b = %d, want 150", got)
+	}
+	service.ClearEffectLayer("a")
+	universe = service.GetUniverse(1)
+	if universe[0] != 100 || universe[1] != 100 {
+		t.Errorf("Output without layers = %v, want [100 100]", universe[:2])
+	}
+}
@@ -0,0 +1,129 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// UniverseMaster is the master level of one universe.
+type UniverseMaster struct {
+	Universe int
+	Level    float64
+}
+
+// MasterLevels is a snapshot of the output masters.
+type MasterLevels struct {
+	GrandMaster float64
+	// Universes lists the universes with a master below full, in order
+	Universes []UniverseMaster
+}
+
+// validateMasterLevel checks a master level is a fraction between 0 and 1.
+func validateMasterLevel(level float64) error {
+	if level < 0 || level > 1 || math.IsNaN(level) {
+		return fmt.Errorf("master level must be between 0 and 1, got %v", level)
+	}
+	return nil
+}
+
+// SetMasterChannels replaces the channels the masters scale. channels maps
+// universe to 1-indexed channels; everything else passes through unscaled.
+func (s *Service) SetMasterChannels(channels map[int][]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.masterChannels = make(map[int][]int, len(channels))
+	for universe, list := range channels {
+		valid := make([]int, 0, len(list))
+		for _, channel := range list {
+			if channel >= 1 && channel <= UniverseSize {
+				valid = append(valid, channel)
+			}
+		}
+		if len(valid) > 0 {
+			s.masterChannels[universe] = valid
+		}
+	}
+	s.markMastersChangedLocked()
+}
+
+// SetGrandMaster sets the grand master, which scales the master channels of
+// every universe.
+func (s *Service) SetGrandMaster(level float64) error {
+	if err := validateMasterLevel(level); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grandMaster != level {
+		s.grandMaster = level
+		s.markMastersChangedLocked()
+	}
+	return nil
+}
+
+// SetUniverseMaster sets one universe's master, which scales its master
+// channels on top of the grand master. Full (1) removes it.
+func (s *Service) SetUniverseMaster(universe int, level float64) error {
+	if err := validateMasterLevel(level); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.universes[universe]; !ok {
+		return fmt.Errorf("invalid universe: %d", universe)
+	}
+
+	current, ok := s.universeMasters[universe]
+	if !ok {
+		current = 1
+	}
+	if current == level {
+		return nil
+	}
+	if level == 1 {
+		delete(s.universeMasters, universe)
+	} else {
+		s.universeMasters[universe] = level
+	}
+	s.markMastersChangedLocked()
+	return nil
+}
+
+// MasterLevels returns the current master levels.
+func (s *Service) MasterLevels() MasterLevels {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	levels := MasterLevels{GrandMaster: s.grandMaster, Universes: []UniverseMaster{}}
+	for universe, level := range s.universeMasters {
+		levels.Universes = append(levels.Universes, UniverseMaster{Universe: universe, Level: level})
+	}
+	sort.Slice(levels.Universes, func(i, j int) bool {
+		return levels.Universes[i].Universe < levels.Universes[j].Universe
+	})
+	return levels
+}
+
+// applyMastersLocked scales a universe's master channels in place.
+func (s *Service) applyMastersLocked(universe int, channels []byte) {
+	scale := s.grandMaster
+	if level, ok := s.universeMasters[universe]; ok {
+		scale *= level
+	}
+	if scale >= 1 {
+		return
+	}
+	for _, channel := range s.masterChannels[universe] {
+		channels[channel-1] = byte(math.Round(float64(channels[channel-1]) * scale))
+	}
+}
+
+// markMastersChangedLocked retransmits every universe after a master change.
+func (s *Service) markMastersChangedLocked() {
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
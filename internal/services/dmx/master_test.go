@@ -0,0 +1,65 @@
+package dmx
+
+import "testing"
+
+func TestMasters(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 200) // Dimmer
+	service.SetChannelValue(1, 2, 200) // Gobo wheel, not mastered
+	service.SetChannelValue(2, 1, 100)
+	service.SetChannelOverride(1, 3, 255)
+	service.SetMasterChannels(map[int][]int{1: {1, 3}, 2: {1}})
+
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	universe := service.GetUniverse(1)
+	if universe[0] != 100 || universe[1] != 200 || universe[2] != 255 {
+		t.Errorf("Output at half grand master = %v, want [100 200 255]", universe[:3])
+	}
+	if got := service.GetChannelValue(1, 1); got != 200 {
+		t.Errorf("GetChannelValue should return the unscaled value, got %d", got)
+	}
+
+	if err := service.SetUniverseMaster(2, 0.5); err != nil {
+		t.Fatalf("SetUniverseMaster() error: %v", err)
+	}
+	if got := service.GetUniverse(2)[0]; got != 25 {
+		t.Errorf("Output with grand and universe masters at half = %d, want 25", got)
+	}
+	if got := service.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("Universe master should not affect other universes, got %d", got)
+	}
+
+	levels := service.MasterLevels()
+	if levels.GrandMaster != 0.5 || len(levels.Universes) != 1 || levels.Universes[0].Universe != 2 {
+		t.Errorf("MasterLevels() = %+v", levels)
+	}
+
+	// Back to full removes the universe master
+	if err := service.SetUniverseMaster(2, 1); err != nil {
+		t.Fatalf("SetUniverseMaster() error: %v", err)
+	}
+	if err := service.SetGrandMaster(1); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if levels := service.MasterLevels(); len(levels.Universes) != 0 {
+		t.Errorf("Expected no universe masters, got %+v", levels.Universes)
+	}
+	if got := service.GetUniverse(2)[0]; got != 100 {
+		t.Errorf("Output at full = %d, want 100", got)
+	}
+}
+
+func TestMasters_Invalid(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	if err := service.SetGrandMaster(1.5); err == nil {
+		t.Error("Expected error for a level above 1")
+	}
+	if err := service.SetGrandMaster(-0.1); err == nil {
+		t.Error("Expected error for a negative level")
+	}
+	if err := service.SetUniverseMaster(99, 0.5); err == nil {
+		t.Error("Expected error for an unknown universe")
+	}
+}
@@ -0,0 +1,61 @@
+package dmx
+
+import "testing"
+
+func TestParkedChannels(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetMasterChannels(map[int][]int{1: {1}})
+	service.SetBlackoutChannels(map[int]map[int]bool{1: {1: true}})
+
+	parked := []ParkedChannel{{Universe: 1, Channel: 1, Value: 180}, {Universe: 1, Channel: 5, Value: 0}}
+	if err := service.SetParkedChannels(parked); err != nil {
+		t.Fatalf("SetParkedChannels() error: %v", err)
+	}
+	service.SetChannelValue(1, 5, 255)
+	service.SetChannelOverride(1, 1, 20)
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if _, err := service.Blackout(0); err != nil {
+		t.Fatalf("Blackout() error: %v", err)
+	}
+	// Parked channels ignore playback, overrides, masters and blackout
+	if universe := service.GetUniverse(1); universe[0] != 180 || universe[4] != 0 {
+		t.Errorf("Output = %d %d, want 180 0", universe[0], universe[4])
+	}
+
+	// Channel limits still apply
+	if err := service.SetChannelLimits([]ChannelLimit{{Universe: 1, Channel: 1, MaxLevel: 0.5}}); err != nil {
+		t.Fatalf("SetChannelLimits() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 128 {
+		t.Errorf("Limited output = %d, want 128", got)
+	}
+	_ = service.SetChannelLimits(nil)
+
+	if got := service.ParkedChannels(); len(got) != 2 || got[0].Channel != 1 || got[1].Channel != 5 {
+		t.Errorf("ParkedChannels() = %+v", got)
+	}
+
+	// Unparking hands the channels back
+	if err := service.SetParkedChannels(nil); err != nil {
+		t.Fatalf("SetParkedChannels() error: %v", err)
+	}
+	if _, err := service.RestoreFromBlackout(0); err != nil {
+		t.Fatalf("RestoreFromBlackout() error: %v", err)
+	}
+	if universe := service.GetUniverse(1); universe[0] != 20 || universe[4] != 255 {
+		t.Errorf("Output after unparking = %d %d, want 20 255", universe[0], universe[4])
+	}
+
+	for _, bad := range [][]ParkedChannel{
+		{{Universe: 1, Channel: 0, Value: 1}},
+		{{Universe: 1, Channel: 1, Value: 256}},
+		{{Universe: 1, Channel: 1, Value: 1}, {Universe: 1, Channel: 1, Value: 2}},
+	} {
+		if err := service.SetParkedChannels(bad); err == nil {
+			t.Errorf("SetParkedChannels(%+v) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package dmx
+
+// A passive service keeps computing its output, and hands each frame to its
+// sinks, but sends no Art-Net. A tracking backup runs passive, holding the
+// same look as its primary, until it takes over output.
+
+// SetPassive stops or resumes Art-Net output. Resuming sends every universe
+// straight away.
+func (s *Service) SetPassive(passive bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.passive == passive {
+		return
+	}
+	s.passive = passive
+	if !passive {
+		s.resendAllLocked()
+		s.triggerHighRate()
+	}
+}
+
+// IsPassive reports whether Art-Net output is stopped for a tracking backup.
+func (s *Service) IsPassive() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.passive
+}
@@ -0,0 +1,48 @@
+package dmx
+
+import (
+	"net"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+func TestPassive(t *testing.T) {
+	port := 6589
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: port,
+		RefreshRateHz: 1, IdleRateHz: 1, ArtSync: true})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+	frames := lastFrames{}
+	service.SetSink(frames)
+
+	service.SetPassive(true)
+	if !service.IsPassive() {
+		t.Fatal("Expected the service to be passive")
+	}
+	receiveOpCodes(t, listener) // Drain anything sent before
+	service.SetChannelValue(1, 1, 77)
+	service.ForceImmediateTransmission()
+	if opCodes := receiveOpCodes(t, listener); len(opCodes) != 0 {
+		t.Errorf("Passive output sent %#x, want nothing", opCodes)
+	}
+	if frames[1] == nil || frames[1][0] != 77 {
+		t.Errorf("Expected sinks to keep receiving frames, got %v", frames[1])
+	}
+
+	// Resuming sends the current look straight away
+	service.SetPassive(false)
+	service.ForceImmediateTransmission()
+	opCodes := receiveOpCodes(t, listener)
+	if countOpCode(opCodes, artnet.OpCodeDMX) == 0 || countOpCode(opCodes, artnet.OpCodeSync) == 0 {
+		t.Errorf("Expected ArtDmx and ArtSync after resuming, got %#x", opCodes)
+	}
+}
@@ -0,0 +1,150 @@
+package dmx
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+)
+
+// MaxVisualizerUniverse is the highest visualizer universe: the last of
+// Art-Net's 15-bit port-addresses.
+const MaxVisualizerUniverse = 32768
+
+// previewUniverse is a universe showing a blind view of another.
+type previewUniverse struct {
+	source int
+	values map[int]byte // 1-indexed channel -> value
+
+	// Destinations of a visualizer universe, the only place it is sent
+	destinations []*net.UDPAddr
+}
+
+// SetPreviewUniverse makes a universe output a blind view in place of its
+// live values: the source universe's output with values (1-indexed channel
+// -> value) on top. The source keeps its live output.
+func (s *Service) SetPreviewUniverse(universe, source int, values map[int]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.universes[universe]; !ok {
+		return fmt.Errorf("invalid preview universe: %d", universe)
+	}
+	return s.setPreviewUniverseLocked(universe, source, values, nil)
+}
+
+// SetVisualizerUniverse outputs a blind view like SetPreviewUniverse to a
+// shadow universe: one outside the configured universes, sent over Art-Net
+// only to destination ("ip" or "ip:port"), so the stage never receives it.
+func (s *Service) SetVisualizerUniverse(universe, source int, values map[int]byte, destination string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if universe < 1 || universe > MaxVisualizerUniverse {
+		return fmt.Errorf("visualizer universe must be between 1 and %d, got %d", MaxVisualizerUniverse, universe)
+	}
+	if _, ok := s.universes[universe]; ok {
+		return fmt.Errorf("universe %d is a live universe and cannot be a visualizer universe", universe)
+	}
+	addr, err := parseDestination(destination, s.port)
+	if err != nil {
+		return err
+	}
+	if err := s.setPreviewUniverseLocked(universe, source, values, []*net.UDPAddr{addr}); err != nil {
+		return err
+	}
+	if err := s.updateUnicastConnLocked(true); err != nil {
+		delete(s.previewUniverses, universe)
+		return err
+	}
+	return nil
+}
+
+// setPreviewUniverseLocked validates and sets a preview universe.
+func (s *Service) setPreviewUniverseLocked(universe, source int, values map[int]byte, destinations []*net.UDPAddr) error {
+	if _, ok := s.universes[source]; !ok {
+		return fmt.Errorf("invalid source universe: %d", source)
+	}
+	if universe == source {
+		return fmt.Errorf("universe %d cannot preview itself", universe)
+	}
+	if _, ok := s.previewUniverses[source]; ok {
+		return fmt.Errorf("universe %d is a preview universe", source)
+	}
+	for _, preview := range s.previewUniverses {
+		if preview.source == universe {
+			return fmt.Errorf("universe %d is being previewed", universe)
+		}
+	}
+
+	valid := make(map[int]byte, len(values))
+	for channel, value := range values {
+		if channel >= 1 && channel <= UniverseSize {
+			valid[channel] = value
+		}
+	}
+	s.previewUniverses[universe] = &previewUniverse{source: source, values: valid, destinations: destinations}
+	s.markDirty(universe)
+	s.triggerHighRate()
+	return nil
+}
+
+// ClearPreviewUniverse returns a preview universe to its live values, or
+// stops sending a visualizer universe.
+func (s *Service) ClearPreviewUniverse(universe int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preview, ok := s.previewUniverses[universe]
+	if !ok {
+		return
+	}
+	delete(s.previewUniverses, universe)
+	if preview.destinations != nil {
+		delete(s.delta.frames, universe)
+		routed := len(s.unicastRoutes) > 0 || len(s.universeRoutes) > 0 || len(s.visualizerUniversesLocked()) > 0
+		_ = s.updateUnicastConnLocked(routed)
+		return
+	}
+	s.markDirty(universe)
+	s.triggerHighRate()
+}
+
+// previewOutputLocked renders a preview universe.
+func (s *Service) previewOutputLocked(preview *previewUniverse) []byte {
+	channels := s.getUniverseOutputChannels(preview.source)
+	for channel, value := range preview.values {
+		channels[channel-1] = value
+	}
+	return channels
+}
+
+// visualizerUniversesLocked returns the visualizer universes in order.
+func (s *Service) visualizerUniversesLocked() []int {
+	var universes []int
+	for universe, preview := range s.previewUniverses {
+		if preview.destinations != nil {
+			universes = append(universes, universe)
+		}
+	}
+	sort.Ints(universes)
+	return universes
+}
+
+// outputVisualizersLocked sends the visualizer universes whose output
+// changed or is due a keep-alive to their destinations. Sinks and Art-Net
+// sync are for the stage output and do not see them.
+func (s *Service) outputVisualizersLocked(now time.Time, cycle time.Duration) {
+	if !s.enabled || s.passive || s.unicastConn == nil {
+		return
+	}
+	for _, universe := range s.visualizerUniversesLocked() {
+		channels := s.getUniverseOutputChannels(universe)
+		kind := s.classifyFrameLocked(universe, channels, now, cycle)
+		s.recordFrameLocked(universe, channels, kind, now)
+		if kind == frameSkipped {
+			continue
+		}
+		s.sendUnicastLocked(universe, channels, s.previewUniverses[universe].destinations)
+	}
+}
@@ -0,0 +1,102 @@
+package dmx
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPreviewUniverse(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(1, 2, 50)
+	service.SetChannelValue(2, 1, 10) // Hidden while universe 2 previews
+
+	if err := service.SetPreviewUniverse(2, 1, map[int]byte{2: 200}); err != nil {
+		t.Fatalf("SetPreviewUniverse() error: %v", err)
+	}
+	if live := service.GetUniverse(1); live[0] != 100 || live[1] != 50 {
+		t.Errorf("Live output = %v, want [100 50]", live[:2])
+	}
+	if preview := service.GetUniverse(2); preview[0] != 100 || preview[1] != 200 {
+		t.Errorf("Preview output = %v, want [100 200]", preview[:2])
+	}
+
+	// Live changes show through the preview
+	service.SetChannelValue(1, 1, 150)
+	if got := service.GetUniverse(2)[0]; got != 150 {
+		t.Errorf("Preview channel 1 = %d, want 150", got)
+	}
+
+	if err := service.SetPreviewUniverse(1, 2, nil); err == nil {
+		t.Error("Previewing a preview universe should fail")
+	}
+	if err := service.SetPreviewUniverse(3, 3, nil); err == nil {
+		t.Error("A universe previewing itself should fail")
+	}
+
+	service.ClearPreviewUniverse(2)
+	if got := service.GetUniverse(2)[0]; got != 10 {
+		t.Errorf("Cleared preview universe channel 1 = %d, want 10", got)
+	}
+}
+
+func TestVisualizerUniverse(t *testing.T) {
+	broadcastPort, visualizerPort := 6601, 6602
+	broadcast, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: broadcastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = broadcast.Close() }()
+	visualizer, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: visualizerPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = visualizer.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: broadcastPort})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	if err := service.SetVisualizerUniverse(2, 1, nil, "127.0.0.1:6602"); err == nil {
+		t.Error("A live universe should not be a visualizer universe")
+	}
+	if err := service.SetVisualizerUniverse(MaxVisualizerUniverse+1, 1, nil, "127.0.0.1:6602"); err == nil {
+		t.Error("A universe past the last port-address should fail")
+	}
+	if err := service.SetVisualizerUniverse(101, 1, nil, "visualizer"); err == nil {
+		t.Error("An invalid destination should fail")
+	}
+
+	service.SetChannelValue(1, 1, 100)
+	if err := service.SetVisualizerUniverse(101, 1, map[int]byte{1: 200}, "127.0.0.1:6602"); err != nil {
+		t.Fatalf("SetVisualizerUniverse() error: %v", err)
+	}
+	service.ForceImmediateTransmission()
+
+	if shadow := receiveUniverses(t, visualizer); len(shadow) != 1 || shadow[101] != 200 {
+		t.Errorf("Expected only universe 101 sent to the visualizer, got %v", shadow)
+	}
+	broadcasted := receiveUniverses(t, broadcast)
+	if _, ok := broadcasted[101]; ok {
+		t.Error("Visualizer universe 101 should not be broadcast")
+	}
+	if broadcasted[1] != 100 {
+		t.Errorf("Expected live universe 1 broadcast unchanged, got %v", broadcasted)
+	}
+
+	// Live changes show through the visualizer universe
+	service.SetChannelValue(1, 2, 50)
+	service.ForceImmediateTransmission()
+	if got := service.GetUniverse(101); got[0] != 200 || got[1] != 50 {
+		t.Errorf("Visualizer output = %v, want [200 50]", got[:2])
+	}
+
+	service.ClearPreviewUniverse(101)
+	_ = receiveUniverses(t, visualizer)
+	service.ForceImmediateTransmission()
+	if shadow := receiveUniverses(t, visualizer); len(shadow) != 0 {
+		t.Errorf("Expected nothing sent to the visualizer after clearing, got %v", shadow)
+	}
+}
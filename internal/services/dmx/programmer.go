@@ -0,0 +1,98 @@
+package dmx
+
+// The programmer holds channel values set by hand while busking. They
+// replace the playback, effect, submaster, and flash output below them until
+// cleared. Masters, overrides, blackout, and output limits still apply on
+// top.
+
+// SetProgrammerValue puts a channel in the programmer. Channels of
+// universes that are not output are ignored.
+func (s *Service) SetProgrammerValue(universe, channel int, value byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.universes[universe] == nil || channel < 1 || channel > UniverseSize {
+		return
+	}
+	channels := s.programmer[universe]
+	if channels == nil {
+		channels = make(map[int]byte)
+		s.programmer[universe] = channels
+	}
+	if current, ok := channels[channel]; ok && current == value {
+		return
+	}
+	channels[channel] = value
+	s.markDirty(universe)
+	s.triggerHighRate()
+}
+
+// ClearProgrammer releases every programmer channel back to the output
+// below it, reporting whether the programmer held any.
+func (s *Service) ClearProgrammer() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clearProgrammerLocked()
+}
+
+// clearProgrammerLocked empties the programmer. Caller must hold s.mu.
+func (s *Service) clearProgrammerLocked() bool {
+	if len(s.programmer) == 0 {
+		return false
+	}
+	for universe := range s.programmer {
+		s.markDirty(universe)
+	}
+	s.programmer = make(map[int]map[int]byte)
+	s.triggerHighRate()
+	return true
+}
+
+// SetProgrammer replaces the whole programmer with values: universe ->
+// 1-indexed channel -> value. Channels of universes that are not output are
+// ignored.
+func (s *Service) SetProgrammer(values map[int]map[int]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clearProgrammerLocked()
+	for universe, channels := range values {
+		if s.universes[universe] == nil {
+			continue
+		}
+		for channel, value := range channels {
+			if channel < 1 || channel > UniverseSize {
+				continue
+			}
+			if s.programmer[universe] == nil {
+				s.programmer[universe] = make(map[int]byte)
+			}
+			s.programmer[universe][channel] = value
+		}
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// ProgrammerValues returns a copy of the programmer: universe -> 1-indexed
+// channel -> value.
+func (s *Service) ProgrammerValues() map[int]map[int]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[int]map[int]byte, len(s.programmer))
+	for universe, channels := range s.programmer {
+		values[universe] = make(map[int]byte, len(channels))
+		for channel, value := range channels {
+			values[universe][channel] = value
+		}
+	}
+	return values
+}
+
+// applyProgrammerLocked writes the programmer over a universe in place.
+func (s *Service) applyProgrammerLocked(universe int, channels []byte) {
+	for channel, value := range s.programmer[universe] {
+		channels[channel-1] = value
+	}
+}
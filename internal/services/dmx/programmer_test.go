@@ -0,0 +1,77 @@
+package dmx
+
+import "testing"
+
+func TestProgrammer(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(1, 2, 10)
+	if err := service.SetFlash("a", Flash{Level: 1, Other: map[int]map[int]byte{1: {2: 80}}}); err != nil {
+		t.Fatalf("SetFlash() error: %v", err)
+	}
+
+	// Programmer values win over playback and flashes
+	service.SetProgrammerValue(1, 1, 40)
+	service.SetProgrammerValue(1, 2, 200)
+	service.SetProgrammerValue(99, 1, 255)
+	if universe := service.GetUniverse(1); universe[0] != 40 || universe[1] != 200 {
+		t.Errorf("Output with the programmer = %v, want [40 200]", universe[:2])
+	}
+	if values := service.ProgrammerValues(); len(values) != 1 || values[1][2] != 200 {
+		t.Errorf("ProgrammerValues() = %v, want universe 1 only", values)
+	}
+
+	// Playback changes underneath stay hidden
+	service.SetChannelValue(1, 1, 255)
+	if got := service.GetUniverse(1)[0]; got != 40 {
+		t.Errorf("Programmed channel = %d, want 40", got)
+	}
+
+	// Masters still scale the programmer
+	service.SetMasterChannels(map[int][]int{1: {1}})
+	if err := service.SetGrandMaster(0.5); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 20 {
+		t.Errorf("Programmed channel at half grand master = %d, want 20", got)
+	}
+	if err := service.SetGrandMaster(1); err != nil {
+		t.Fatalf("SetGrandMaster() error: %v", err)
+	}
+
+	if !service.ClearProgrammer() {
+		t.Error("Expected ClearProgrammer() to report values cleared")
+	}
+	if universe := service.GetUniverse(1); universe[0] != 255 || universe[1] != 80 {
+		t.Errorf("Output after clearing = %v, want [255 80]", universe[:2])
+	}
+	if service.ClearProgrammer() {
+		t.Error("Expected an empty programmer to report nothing cleared")
+	}
+
+	service.SetProgrammerValue(1, 3, 60)
+	service.FadeToBlack()
+	if values := service.ProgrammerValues(); len(values) != 0 {
+		t.Errorf("Programmer after FadeToBlack() = %v, want empty", values)
+	}
+}
+
+func TestSetProgrammer(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 3, 90)
+	service.SetProgrammerValue(1, 3, 10)
+
+	service.SetProgrammer(map[int]map[int]byte{1: {1: 40, 600: 1}, 99: {1: 255}})
+	universe := service.GetUniverse(1)
+	if universe[0] != 40 || universe[2] != 90 {
+		t.Errorf("Output = %v, want channel 1 programmed and channel 3 released", universe[:3])
+	}
+	if values := service.ProgrammerValues(); len(values) != 1 || len(values[1]) != 1 {
+		t.Errorf("ProgrammerValues() = %v, want only universe 1 channel 1", values)
+	}
+
+	service.SetProgrammer(nil)
+	if values := service.ProgrammerValues(); len(values) != 0 {
+		t.Errorf("ProgrammerValues() = %v, want empty", values)
+	}
+}
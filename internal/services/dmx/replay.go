@@ -0,0 +1,44 @@
+package dmx
+
+// A replay sends recorded frames in place of live output. A universe with a
+// replayed frame transmits the frame as recorded, skipping every layer,
+// patch and limit, since the recording already has them applied. Universes
+// without one keep their live output.
+
+// PlayFrame transmits channels as universe's output, in place of its live
+// output, until StopReplay.
+func (s *Service) PlayFrame(universe int, channels []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame := make([]byte, UniverseSize)
+	copy(frame, channels)
+	if s.replayFrames == nil {
+		s.replayFrames = make(map[int][]byte)
+	}
+	s.replayFrames[universe] = frame
+	s.markDirty(universe)
+	s.triggerHighRate()
+}
+
+// StopReplay returns every universe to its live output.
+func (s *Service) StopReplay() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.replayFrames == nil {
+		return
+	}
+	s.replayFrames = nil
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+}
+
+// IsReplaying reports whether any universe is sending replayed frames.
+func (s *Service) IsReplaying() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replayFrames != nil
+}
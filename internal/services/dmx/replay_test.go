@@ -0,0 +1,42 @@
+package dmx
+
+import "testing"
+
+func TestReplay(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	sink := lastFrames{}
+	service.AddSink(sink)
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(2, 1, 50)
+	service.processTransmission()
+
+	// A replayed frame replaces the universe's live output, as recorded
+	frame := make([]byte, UniverseSize)
+	frame[0], frame[1] = 10, 20
+	service.PlayFrame(1, frame)
+	frame[0] = 99 // The service keeps its own copy
+	if !service.IsReplaying() {
+		t.Error("Expected IsReplaying() while a frame is replayed")
+	}
+	service.processTransmission()
+	if got := sink[1]; got[0] != 10 || got[1] != 20 {
+		t.Errorf("Expected universe 1 to send the replayed frame, got %v", got[:2])
+	}
+
+	// Universes without a replayed frame keep their live output
+	service.SetChannelValue(2, 1, 60)
+	service.processTransmission()
+	if got := sink[2][0]; got != 60 {
+		t.Errorf("Expected universe 2 live at 60, got %d", got)
+	}
+
+	service.StopReplay()
+	service.StopReplay() // Stopping twice is harmless
+	if service.IsReplaying() {
+		t.Error("Expected IsReplaying() false after StopReplay()")
+	}
+	service.processTransmission()
+	if got := sink[1][0]; got != 100 {
+		t.Errorf("Expected universe 1 back to its live output of 100, got %d", got)
+	}
+}
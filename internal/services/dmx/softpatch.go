@@ -0,0 +1,167 @@
+package dmx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Address is a DMX channel: a universe and a 1-indexed channel.
+type Address struct {
+	Universe int
+	Channel  int
+}
+
+// Validate checks the address is in range.
+func (a Address) Validate() error {
+	if a.Universe < 1 || a.Universe > MaxUniverses {
+		return fmt.Errorf("invalid universe: %d", a.Universe)
+	}
+	if a.Channel < 1 || a.Channel > UniverseSize {
+		return fmt.Errorf("invalid channel: %d", a.Channel)
+	}
+	return nil
+}
+
+// softPatch is the softpatch indexed for output.
+type softPatch struct {
+	// Logical channels patched away from their own address:
+	// universe -> channel
+	sources map[int]map[int]bool
+	// Logical channels each output address carries:
+	// universe -> channel -> sources
+	targets map[int]map[int][]Address
+	// Output universes each logical universe's patches reach
+	reaches map[int][]int
+}
+
+// SetSoftPatch replaces the softpatch, which sits between the channels
+// fixtures address and the transmitted output. patch maps a logical channel
+// to the output addresses that carry its value instead of its own address;
+// an empty list parks the channel. Where several channels are patched to one
+// address the highest value wins. Channel values, and what GetUniverse
+// reports, stay logical.
+func (s *Service) SetSoftPatch(patch map[Address][]Address) error {
+	for source, targets := range patch {
+		if err := source.Validate(); err != nil {
+			return fmt.Errorf("softpatch source: %w", err)
+		}
+		for _, target := range targets {
+			if err := target.Validate(); err != nil {
+				return fmt.Errorf("softpatch of %d/%d: %w", source.Universe, source.Channel, err)
+			}
+		}
+	}
+
+	var indexed *softPatch
+	if len(patch) > 0 {
+		indexed = &softPatch{
+			sources: make(map[int]map[int]bool),
+			targets: make(map[int]map[int][]Address),
+			reaches: make(map[int][]int),
+		}
+		reached := make(map[int]map[int]bool)
+		for source, targets := range patch {
+			if indexed.sources[source.Universe] == nil {
+				indexed.sources[source.Universe] = make(map[int]bool)
+			}
+			indexed.sources[source.Universe][source.Channel] = true
+			for _, target := range targets {
+				if indexed.targets[target.Universe] == nil {
+					indexed.targets[target.Universe] = make(map[int][]Address)
+				}
+				indexed.targets[target.Universe][target.Channel] = append(indexed.targets[target.Universe][target.Channel], source)
+				if reached[source.Universe] == nil {
+					reached[source.Universe] = make(map[int]bool)
+				}
+				reached[source.Universe][target.Universe] = true
+			}
+		}
+		for universe, targets := range reached {
+			for target := range targets {
+				if target != universe {
+					indexed.reaches[universe] = append(indexed.reaches[universe], target)
+				}
+			}
+			sort.Ints(indexed.reaches[universe])
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.softPatch = indexed
+	for universe := range s.universes {
+		s.markDirty(universe)
+	}
+	s.triggerHighRate()
+	return nil
+}
+
+// PatchedUniverse returns the values transmitted for a universe, after the
+// softpatch.
+func (s *Service) PatchedUniverse(universe int) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := s.patchedOutputLocked(universe, make(map[int][]byte))
+	result := make([]int, UniverseSize)
+	for i, v := range channels {
+		result[i] = int(v)
+	}
+	return result
+}
+
+// patchedUniversesLocked adds to universes the other universes their
+// softpatches reach, so a change is sent wherever it is patched.
+func (s *Service) patchedUniversesLocked(universes []int) []int {
+	if s.softPatch == nil {
+		return universes
+	}
+	seen := make(map[int]bool, len(universes))
+	for _, universe := range universes {
+		seen[universe] = true
+	}
+	for _, universe := range universes {
+		for _, target := range s.softPatch.reaches[universe] {
+			if !seen[target] {
+				if _, ok := s.universes[target]; ok {
+					seen[target] = true
+					universes = append(universes, target)
+				}
+			}
+		}
+	}
+	return universes
+}
+
+// patchedOutputLocked returns a universe's output through the softpatch.
+// logical caches the output of universes before the patch.
+func (s *Service) patchedOutputLocked(universe int, logical map[int][]byte) []byte {
+	outputOf := func(u int) []byte {
+		channels, ok := logical[u]
+		if !ok {
+			channels = s.getUniverseOutputChannels(u)
+			logical[u] = channels
+		}
+		return channels
+	}
+	if s.softPatch == nil {
+		return outputOf(universe)
+	}
+
+	channels := make([]byte, UniverseSize)
+	copy(channels, outputOf(universe))
+	for channel := range s.softPatch.sources[universe] {
+		channels[channel-1] = 0
+	}
+	for channel, sources := range s.softPatch.targets[universe] {
+		var value byte
+		for _, source := range sources {
+			if v := outputOf(source.Universe)[source.Channel-1]; v > value {
+				value = v
+			}
+		}
+		channels[channel-1] = value
+	}
+	return channels
+}
@@ -0,0 +1,80 @@
+package dmx
+
+import "testing"
+
+// lastFrames keeps the last frame written to it per universe.
+type lastFrames map[int][]byte
+
+func (f lastFrames) WriteFrame(universe int, channels []byte) {
+	f[universe] = append([]byte(nil), channels...)
+}
+
+func TestSoftPatch(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	frames := lastFrames{}
+	service.SetSink(frames)
+
+	service.SetChannelValue(1, 1, 100)
+	service.SetChannelValue(1, 2, 200)
+	service.SetChannelValue(1, 3, 50)
+	service.SetChannelValue(2, 10, 30)
+
+	err := service.SetSoftPatch(map[Address][]Address{
+		// Moved, and doubled onto another universe
+		{1, 1}: {{1, 5}, {2, 10}},
+		// Parked
+		{1, 2}: {},
+		// Onto the same address as channel 1; the higher wins
+		{1, 3}: {{1, 5}},
+	})
+	if err != nil {
+		t.Fatalf("SetSoftPatch() error: %v", err)
+	}
+
+	out := service.PatchedUniverse(1)
+	if out[0] != 0 || out[1] != 0 || out[2] != 0 || out[4] != 100 {
+		t.Errorf("Patched universe 1 = %v, want [0 0 0 0 100]", out[:5])
+	}
+	if got := service.PatchedUniverse(2)[9]; got != 100 {
+		t.Errorf("Patched universe 2 channel 10 = %d, want 100", got)
+	}
+	if got := service.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("GetUniverse should stay logical, got channel 1 = %d", got)
+	}
+
+	// A change is sent to every universe it is patched to
+	service.processTransmission()
+	service.SetChannelValue(1, 1, 150)
+	delete(frames, 2)
+	service.processTransmission()
+	if frames[1] == nil || frames[1][4] != 150 {
+		t.Errorf("Expected universe 1 channel 5 sent at 150, got %v", frames[1])
+	}
+	if frames[2] == nil || frames[2][9] != 150 {
+		t.Errorf("Expected universe 2 channel 10 sent at 150, got %v", frames[2])
+	}
+
+	// Clearing the patch restores the direct mapping
+	if err := service.SetSoftPatch(nil); err != nil {
+		t.Fatalf("SetSoftPatch() error: %v", err)
+	}
+	service.processTransmission()
+	if frames[1][0] != 150 || frames[1][4] != 0 || frames[2][9] != 30 {
+		t.Errorf("Expected unpatched output, got %v and %v", frames[1][:5], frames[2][9])
+	}
+}
+
+func TestSoftPatch_Invalid(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	tests := map[string]map[Address][]Address{
+		"source channel":  {{1, 0}: {{1, 1}}},
+		"source universe": {{MaxUniverses + 1, 1}: {{1, 1}}},
+		"target channel":  {{1, 1}: {{1, UniverseSize + 1}}},
+		"target universe": {{1, 1}: {{0, 1}}},
+	}
+	for name, patch := range tests {
+		if err := service.SetSoftPatch(patch); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package dmx
+
+import "time"
+
+// OutputStatus describes the state of Art-Net output.
+type OutputStatus struct {
+	// Enabled is false when the service only simulates output
+	Enabled bool
+	// Running is true while the transmit loop runs
+	Running bool
+	// SocketOpen is true while the broadcast socket is open
+	SocketOpen    bool
+	BroadcastAddr string
+	Port          int
+	RateHz        int
+	Passive       bool
+	Standby       bool
+	// The last send failure, cleared when a send succeeds
+	LastError   error
+	LastErrorAt *time.Time
+}
+
+// OutputStatus returns the state of Art-Net output.
+func (s *Service) OutputStatus() OutputStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := OutputStatus{
+		Enabled:       s.enabled,
+		Running:       s.running,
+		SocketOpen:    s.conn != nil,
+		BroadcastAddr: s.broadcastAddr,
+		Port:          s.port,
+		RateHz:        s.currentRate,
+		Passive:       s.passive,
+		Standby:       s.standby,
+		LastError:     s.sendErr,
+	}
+	if s.sendErr != nil {
+		at := s.sendErrAt
+		status.LastErrorAt = &at
+	}
+	return status
+}
+
+// recordSendLocked records the outcome of sending a packet.
+func (s *Service) recordSendLocked(err error) {
+	s.sendErr = err
+	if err != nil {
+		s.sendErrAt = time.Now()
+	}
+}
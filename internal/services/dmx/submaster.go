@@ -0,0 +1,111 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Submaster is a fader's contribution to the output, scaled by its level
+// (0-1). A scene submaster adds its intensity values highest-takes-precedence
+// and, while raised, sets its other values over the live ones; a fixture
+// selection submaster scales the live intensity of its fixtures.
+type Submaster struct {
+	Level float64
+
+	// Scene values: universe -> 1-indexed channel -> value
+	Intensity map[int]map[int]byte
+	Other     map[int]map[int]byte
+
+	// Intensity channels scaled by the level: universe -> 1-indexed channels
+	Scaled map[int][]int
+}
+
+// SetSubmasters replaces every submaster. Where submasters set the same
+// channel, those with later IDs win.
+func (s *Service) SetSubmasters(submasters map[string]Submaster) error {
+	for id, sub := range submasters {
+		if err := validateMasterLevel(sub.Level); err != nil {
+			return fmt.Errorf("submaster %s: %w", id, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.submasters = make(map[string]*Submaster, len(submasters))
+	for id, sub := range submasters {
+		sub := sub
+		s.submasters[id] = &sub
+	}
+	s.markMastersChangedLocked()
+	return nil
+}
+
+// SetSubmasterLevel moves a submaster's fader.
+func (s *Service) SetSubmasterLevel(id string, level float64) error {
+	if err := validateMasterLevel(level); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.submasters[id]
+	if !ok {
+		return fmt.Errorf("submaster not found: %s", id)
+	}
+	if sub.Level != level {
+		sub.Level = level
+		s.markMastersChangedLocked()
+	}
+	return nil
+}
+
+// SubmasterLevels returns the level of every submaster by ID.
+func (s *Service) SubmasterLevels() map[string]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	levels := make(map[string]float64, len(s.submasters))
+	for id, sub := range s.submasters {
+		levels[id] = sub.Level
+	}
+	return levels
+}
+
+// applySubmastersLocked writes submaster output over a universe in place.
+func (s *Service) applySubmastersLocked(universe int, channels []byte) {
+	if len(s.submasters) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(s.submasters))
+	for id := range s.submasters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		sub := s.submasters[id]
+		for _, channel := range sub.Scaled[universe] {
+			if channel >= 1 && channel <= UniverseSize {
+				channels[channel-1] = byte(math.Round(float64(channels[channel-1]) * sub.Level))
+			}
+		}
+		if sub.Level == 0 {
+			continue
+		}
+		for channel, value := range sub.Other[universe] {
+			if channel >= 1 && channel <= UniverseSize {
+				channels[channel-1] = value
+			}
+		}
+		for channel, value := range sub.Intensity[universe] {
+			if channel < 1 || channel > UniverseSize {
+				continue
+			}
+			if scaled := byte(math.Round(float64(value) * sub.Level)); scaled > channels[channel-1] {
+				channels[channel-1] = scaled
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package dmx
+
+import "testing"
+
+func TestSubmasters(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	service.SetChannelValue(1, 1, 100) // Dimmer of the scene fixture
+	service.SetChannelValue(1, 2, 10)  // Color of the scene fixture
+	service.SetChannelValue(1, 5, 200) // Dimmer of the selected fixture
+
+	err := service.SetSubmasters(map[string]Submaster{
+		"scene": {
+			Intensity: map[int]map[int]byte{1: {1: 255}},
+			Other:     map[int]map[int]byte{1: {2: 80}},
+		},
+		"selection": {Level: 1, Scaled: map[int][]int{1: {5}}},
+	})
+	if err != nil {
+		t.Fatalf("SetSubmasters() error: %v", err)
+	}
+	if universe := service.GetUniverse(1); universe[0] != 100 || universe[1] != 10 || universe[4] != 200 {
+		t.Errorf("Output with the scene down and the selection full = %v, want [100 10 _ _ 200]", universe[:5])
+	}
+
+	if err := service.SetSubmasterLevel("scene", 0.2); err != nil {
+		t.Fatalf("SetSubmasterLevel() error: %v", err)
+	}
+	universe := service.GetUniverse(1)
+	if universe[0] != 100 {
+		t.Errorf("Scene intensity below the live value should not win, got %d", universe[0])
+	}
+	if universe[1] != 80 {
+		t.Errorf("Raised scene should set its color, got %d", universe[1])
+	}
+
+	if err := service.SetSubmasterLevel("scene", 0.8); err != nil {
+		t.Fatalf("SetSubmasterLevel() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[0]; got != 204 {
+		t.Errorf("Scene intensity at 80%% = %d, want 204", got)
+	}
+
+	if err := service.SetSubmasterLevel("selection", 0.5); err != nil {
+		t.Fatalf("SetSubmasterLevel() error: %v", err)
+	}
+	if got := service.GetUniverse(1)[4]; got != 100 {
+		t.Errorf("Selection at half = %d, want 100", got)
+	}
+
+	levels := service.SubmasterLevels()
+	if levels["scene"] != 0.8 || levels["selection"] != 0.5 {
+		t.Errorf("SubmasterLevels() = %v", levels)
+	}
+	if got := service.GetChannelValue(1, 5); got != 200 {
+		t.Errorf("GetChannelValue should return the live value, got %d", got)
+	}
+}
+
+func TestSubmasters_Invalid(t *testing.T) {
+	service := NewService(Config{Enabled: false})
+	if err := service.SetSubmasters(map[string]Submaster{"sub": {Level: 2}}); err == nil {
+		t.Error("Expected error for a level above 1")
+	}
+	if err := service.SetSubmasterLevel("missing", 0.5); err == nil {
+		t.Error("Expected error for an unknown submaster")
+	}
+}
@@ -0,0 +1,70 @@
+package dmx
+
+import (
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+// ArtSyncSettingKey is the setting that stores whether ArtSync is sent.
+const ArtSyncSettingKey = "artnet_sync"
+
+// SetArtSync turns ArtSync output on or off. When on, an ArtSync packet
+// follows each burst of ArtDmx packets so nodes latch every universe of a
+// frame at the same moment. Nodes that have seen ArtSync wait for it before
+// outputting, so it is sent after every burst, even of one universe.
+func (s *Service) SetArtSync(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if enabled && !s.artSync {
+		log.Info("📡 Art-Net sync enabled")
+	} else if !enabled && s.artSync {
+		log.Info("📡 Art-Net sync disabled")
+	}
+	s.artSync = enabled
+}
+
+// ArtSyncEnabled reports whether ArtSync packets are sent.
+func (s *Service) ArtSyncEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.artSync
+}
+
+// transmitSyncLocked sends an ArtSync packet everywhere the burst of
+// universes just went: broadcast if any universe was broadcast, and to each
+// unicast node that received one.
+func (s *Service) transmitSyncLocked(universes []int) {
+	if !s.enabled || s.passive || !s.artSync || len(universes) == 0 {
+		return
+	}
+
+	packet := artnet.BuildSyncPacket()
+	broadcast := false
+	sent := make(map[string]bool)
+	for _, universe := range universes {
+		destinations := s.unicastRoutes[universe]
+		if len(destinations) == 0 || s.unicastConn == nil {
+			broadcast = true
+			continue
+		}
+		for _, destination := range destinations {
+			address := destination.String()
+			if sent[address] {
+				continue
+			}
+			sent[address] = true
+			if _, err := s.unicastConn.WriteToUDP(packet, destination); err != nil {
+				log.Warn("Art-Net sync send failed", "destination", address, "error", err)
+				continue
+			}
+			s.recordPacket(CaptureDirectionOut, 0, 0, address, packet)
+		}
+	}
+
+	if broadcast && s.conn != nil {
+		if _, err := s.conn.Write(packet); err != nil {
+			log.Warn("Art-Net sync send failed", "error", err)
+			return
+		}
+		s.recordPacket(CaptureDirectionOut, 0, 0, s.addr.String(), packet)
+	}
+}
@@ -0,0 +1,96 @@
+package dmx
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+// receiveOpCodes collects the opcodes of Art-Net packets arriving on a
+// listener until it goes quiet.
+func receiveOpCodes(t *testing.T, listener *net.UDPConn) []uint16 {
+	t.Helper()
+	var opCodes []uint16
+	buffer := make([]byte, 1024)
+	for {
+		_ = listener.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		n, _, err := listener.ReadFromUDP(buffer)
+		if err != nil {
+			return opCodes
+		}
+		if opCode, ok := artnet.OpCode(buffer[:n]); ok {
+			opCodes = append(opCodes, opCode)
+		}
+	}
+}
+
+// countOpCode counts the packets of one opcode.
+func countOpCode(opCodes []uint16, opCode uint16) int {
+	count := 0
+	for _, op := range opCodes {
+		if op == opCode {
+			count++
+		}
+	}
+	return count
+}
+
+func TestArtSync(t *testing.T) {
+	broadcastPort, unicastPort := 6597, 6598
+	broadcast, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: broadcastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = broadcast.Close() }()
+	node, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: unicastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = node.Close() }()
+
+	// A slow refresh keeps the transmit loop from interleaving its own bursts
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: broadcastPort,
+		RefreshRateHz: 1, IdleRateHz: 1, ArtSync: true})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+	if !service.ArtSyncEnabled() {
+		t.Fatal("Expected ArtSync enabled from the config")
+	}
+
+	// Universe 3 goes to a node twice over; it still gets one ArtSync
+	if err := service.SetUnicastRoutes([]UnicastRoute{
+		{Universe: 2, Destinations: []string{"127.0.0.1:6598"}},
+		{Universe: 3, Destinations: []string{"127.0.0.1:6598"}},
+	}); err != nil {
+		t.Fatalf("SetUnicastRoutes() error: %v", err)
+	}
+	service.SetChannelValue(1, 1, 11)
+	service.SetChannelValue(2, 1, 22)
+	service.SetChannelValue(3, 1, 33)
+	service.ForceImmediateTransmission()
+
+	unicast := receiveOpCodes(t, node)
+	if len(unicast) < 3 || unicast[0] != artnet.OpCodeDMX || unicast[1] != artnet.OpCodeDMX || unicast[2] != artnet.OpCodeSync {
+		t.Errorf("Node received %#x, want two ArtDmx packets then ArtSync", unicast)
+	}
+	broadcasted := receiveOpCodes(t, broadcast)
+	if len(broadcasted) < 2 || broadcasted[0] != artnet.OpCodeDMX || broadcasted[len(broadcasted)-1] != artnet.OpCodeSync {
+		t.Errorf("Broadcast received %#x, want ArtDmx followed by ArtSync", broadcasted)
+	}
+	if countOpCode(broadcasted, artnet.OpCodeSync) > countOpCode(broadcasted, artnet.OpCodeDMX) {
+		t.Errorf("Broadcast received %#x, want one ArtSync per burst", broadcasted)
+	}
+
+	// Without sync only ArtDmx is sent
+	service.SetArtSync(false)
+	service.SetChannelValue(1, 1, 12)
+	service.ForceImmediateTransmission()
+	broadcasted = receiveOpCodes(t, broadcast)
+	if countOpCode(broadcasted, artnet.OpCodeDMX) == 0 || countOpCode(broadcasted, artnet.OpCodeSync) != 0 {
+		t.Errorf("Broadcast received %#x, want ArtDmx only", broadcasted)
+	}
+}
@@ -0,0 +1,197 @@
+package dmx
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+// UnicastRoutesSettingKey is the setting that stores the unicast routing
+// table as JSON.
+const UnicastRoutesSettingKey = "artnet_unicast_routes"
+
+// MaxUnicastDestinations bounds the nodes a single universe is sent to.
+const MaxUnicastDestinations = 16
+
+// UnicastRoute sends a universe directly to specific Art-Net nodes instead of
+// broadcasting it.
+type UnicastRoute struct {
+	Universe int `json:"universe"`
+	// Destinations are IPv4 addresses, optionally with a port ("10.0.0.5:6454");
+	// the service's Art-Net port is used when omitted
+	Destinations []string `json:"destinations"`
+}
+
+// ResolveUnicastRoutes validates a routing table and resolves its
+// destinations, using defaultPort where none is given.
+func ResolveUnicastRoutes(routes []UnicastRoute, defaultPort int) (map[int][]*net.UDPAddr, error) {
+	resolved := make(map[int][]*net.UDPAddr, len(routes))
+	for _, route := range routes {
+		if route.Universe < 1 || route.Universe > MaxUniverses {
+			return nil, fmt.Errorf("universe must be between 1 and %d, got %d", MaxUniverses, route.Universe)
+		}
+		if _, exists := resolved[route.Universe]; exists {
+			return nil, fmt.Errorf("universe %d is routed more than once", route.Universe)
+		}
+		if len(route.Destinations) == 0 {
+			return nil, fmt.Errorf("universe %d: at least one destination is required", route.Universe)
+		}
+		if len(route.Destinations) > MaxUnicastDestinations {
+			return nil, fmt.Errorf("universe %d: at most %d destinations are allowed", route.Universe, MaxUnicastDestinations)
+		}
+
+		seen := make(map[string]bool)
+		addrs := make([]*net.UDPAddr, 0, len(route.Destinations))
+		for _, destination := range route.Destinations {
+			addr, err := parseDestination(destination, defaultPort)
+			if err != nil {
+				return nil, fmt.Errorf("universe %d: %w", route.Universe, err)
+			}
+			if seen[addr.String()] {
+				return nil, fmt.Errorf("universe %d: duplicate destination %s", route.Universe, addr)
+			}
+			seen[addr.String()] = true
+			addrs = append(addrs, addr)
+		}
+		resolved[route.Universe] = addrs
+	}
+	return resolved, nil
+}
+
+// parseDestination parses "ip" or "ip:port" as an IPv4 UDP address.
+func parseDestination(destination string, defaultPort int) (*net.UDPAddr, error) {
+	host, port := destination, defaultPort
+	if h, p, err := net.SplitHostPort(destination); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid port in destination %q", destination)
+		}
+		host, port = h, n
+	}
+	ip := net.ParseIP(host).To4()
+	if ip == nil || ip.IsUnspecified() || ip.IsMulticast() {
+		return nil, fmt.Errorf("invalid destination %q: expected an IPv4 unicast address", destination)
+	}
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
+
+// SetUnicastRoutes validates and applies a routing table. Routed universes are
+// sent only to their destinations; the rest are still broadcast. Takes effect
+// from the next frame, and every universe is retransmitted immediately.
+func (s *Service) SetUnicastRoutes(routes []UnicastRoute) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resolved, err := ResolveUnicastRoutes(routes, s.port)
+	if err != nil {
+		return err
+	}
+
+	if err := s.updateUnicastConnLocked(len(resolved) > 0 || len(s.universeRoutes) > 0 || len(s.visualizerUniversesLocked()) > 0); err != nil {
+		return err
+	}
+
+	s.unicastRoutes = resolved
+	s.unicastConfig = make([]UnicastRoute, 0, len(routes))
+	for _, route := range routes {
+		s.unicastConfig = append(s.unicastConfig, UnicastRoute{
+			Universe:     route.Universe,
+			Destinations: append([]string(nil), route.Destinations...),
+		})
+	}
+	sort.Slice(s.unicastConfig, func(i, j int) bool {
+		return s.unicastConfig[i].Universe < s.unicastConfig[j].Universe
+	})
+
+	// Nodes that just lost or gained a route get a fresh frame right away
+	s.resendAllLocked()
+	s.triggerHighRate()
+
+	if len(resolved) > 0 {
+		log.Info("📡 Art-Net unicast routing set", "universes", len(resolved))
+	} else {
+		log.Info("📡 Art-Net unicast routing cleared, broadcasting all universes")
+	}
+	return nil
+}
+
+// updateUnicastConnLocked opens the unicast socket when some universe is
+// routed or sent to a visualizer, and closes it when none is.
+func (s *Service) updateUnicastConnLocked(routed bool) error {
+	if routed && s.unicastConn == nil {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: s.binding.Address})
+		if err != nil {
+			return fmt.Errorf("failed to open unicast socket: %w", err)
+		}
+		s.unicastConn = conn
+	} else if !routed && s.unicastConn != nil {
+		_ = s.unicastConn.Close()
+		s.unicastConn = nil
+	}
+	return nil
+}
+
+// UnicastRoutes returns the routing table, ordered by universe.
+func (s *Service) UnicastRoutes() []UnicastRoute {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routes := make([]UnicastRoute, 0, len(s.unicastConfig))
+	for _, route := range s.unicastConfig {
+		routes = append(routes, UnicastRoute{
+			Universe:     route.Universe,
+			Destinations: append([]string(nil), route.Destinations...),
+		})
+	}
+	return routes
+}
+
+// transmitLocked sends one universe over Art-Net: unicast to its routed
+// destinations, or to its configured destinations when the routing table
+// has none, or broadcast. Disabled universes are not sent.
+func (s *Service) transmitLocked(universe int, channels []byte) {
+	if !s.enabled || s.passive || s.disabledUniverses[universe] {
+		return
+	}
+
+	destinations := s.unicastRoutes[universe]
+	if len(destinations) == 0 {
+		destinations = s.universeRoutes[universe]
+	}
+	if len(destinations) > 0 && s.unicastConn != nil {
+		s.sendUnicastLocked(universe, channels, destinations)
+		return
+	}
+
+	if s.conn == nil {
+		return
+	}
+	// Increment sequence number for each packet (wraps at 255)
+	s.sequence++
+	packet := artnet.BuildDMXPacket(universe, channels, s.sequence)
+	_, err := s.conn.Write(packet)
+	s.recordSendLocked(err)
+	if err != nil {
+		log.Warn("Art-Net send failed", "universe", universe, "error", err)
+		return
+	}
+	s.recordPacket(CaptureDirectionOut, universe, s.sequence, s.addr.String(), packet)
+}
+
+// sendUnicastLocked sends one universe to each of its destinations.
+func (s *Service) sendUnicastLocked(universe int, channels []byte, destinations []*net.UDPAddr) {
+	s.sequence++
+	packet := artnet.BuildDMXPacket(universe, channels, s.sequence)
+	for _, destination := range destinations {
+		_, err := s.unicastConn.WriteToUDP(packet, destination)
+		s.recordSendLocked(err)
+		if err != nil {
+			log.Warn("Art-Net unicast send failed", "universe", universe, "destination", destination, "error", err)
+			continue
+		}
+		s.recordPacket(CaptureDirectionOut, universe, s.sequence, destination.String(), packet)
+	}
+}
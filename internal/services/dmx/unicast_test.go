@@ -0,0 +1,175 @@
+package dmx
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+func TestResolveUnicastRoutes(t *testing.T) {
+	resolved, err := ResolveUnicastRoutes([]UnicastRoute{
+		{Universe: 1, Destinations: []string{"10.0.0.5", "10.0.0.6:6455"}},
+	}, 6454)
+	if err != nil {
+		t.Fatalf("ResolveUnicastRoutes() error: %v", err)
+	}
+	if got := resolved[1]; len(got) != 2 || got[0].String() != "10.0.0.5:6454" || got[1].String() != "10.0.0.6:6455" {
+		t.Errorf("resolved[1] = %v", got)
+	}
+
+	invalid := []struct {
+		name  string
+		route UnicastRoute
+	}{
+		{"universe zero", UnicastRoute{Universe: 0, Destinations: []string{"10.0.0.5"}}},
+		{"universe too high", UnicastRoute{Universe: MaxUniverses + 1, Destinations: []string{"10.0.0.5"}}},
+		{"no destinations", UnicastRoute{Universe: 1}},
+		{"hostname", UnicastRoute{Universe: 1, Destinations: []string{"node.local"}}},
+		{"ipv6", UnicastRoute{Universe: 1, Destinations: []string{"::1"}}},
+		{"multicast", UnicastRoute{Universe: 1, Destinations: []string{"239.255.0.1"}}},
+		{"bad port", UnicastRoute{Universe: 1, Destinations: []string{"10.0.0.5:0"}}},
+		{"duplicate", UnicastRoute{Universe: 1, Destinations: []string{"10.0.0.5", "10.0.0.5:6454"}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ResolveUnicastRoutes([]UnicastRoute{tt.route}, 6454); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	twice := []UnicastRoute{
+		{Universe: 2, Destinations: []string{"10.0.0.5"}},
+		{Universe: 2, Destinations: []string{"10.0.0.6"}},
+	}
+	if _, err := ResolveUnicastRoutes(twice, 6454); err == nil {
+		t.Error("Expected error for a universe routed twice")
+	}
+}
+
+// receiveUniverses collects the universes of ArtDmx packets arriving on a
+// listener until it goes quiet.
+func receiveUniverses(t *testing.T, listener *net.UDPConn) map[int]byte {
+	t.Helper()
+	universes := make(map[int]byte) // Universe -> channel 1 value
+	buffer := make([]byte, 1024)
+	for {
+		_ = listener.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		n, _, err := listener.ReadFromUDP(buffer)
+		if err != nil {
+			return universes
+		}
+		if universe, channels, err := artnet.ParseDMXPacket(buffer[:n]); err == nil {
+			universes[universe] = channels[0]
+		}
+	}
+}
+
+func TestUnicastRouting(t *testing.T) {
+	broadcastPort, unicastPort := 6595, 6596
+	broadcast, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: broadcastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = broadcast.Close() }()
+	node, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: unicastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = node.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: broadcastPort})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	if err := service.SetUnicastRoutes([]UnicastRoute{
+		{Universe: 2, Destinations: []string{"127.0.0.1:6596"}},
+	}); err != nil {
+		t.Fatalf("SetUnicastRoutes() error: %v", err)
+	}
+	service.SetChannelValue(1, 1, 11)
+	service.SetChannelValue(2, 1, 22)
+	service.ForceImmediateTransmission()
+
+	unicast := receiveUniverses(t, node)
+	if len(unicast) != 1 || unicast[2] != 22 {
+		t.Errorf("Expected only universe 2 unicast to the node, got %v", unicast)
+	}
+	broadcasted := receiveUniverses(t, broadcast)
+	if _, ok := broadcasted[2]; ok {
+		t.Error("Routed universe 2 should not be broadcast")
+	}
+	if broadcasted[1] != 11 {
+		t.Errorf("Expected universe 1 broadcast, got %v", broadcasted)
+	}
+
+	routes := service.UnicastRoutes()
+	if len(routes) != 1 || routes[0].Universe != 2 {
+		t.Errorf("UnicastRoutes() = %+v", routes)
+	}
+
+	// Clearing the table reverts to broadcast without a restart
+	if err := service.SetUnicastRoutes(nil); err != nil {
+		t.Fatalf("SetUnicastRoutes(nil) error: %v", err)
+	}
+	service.ForceImmediateTransmission()
+	if broadcasted := receiveUniverses(t, broadcast); broadcasted[2] != 22 {
+		t.Errorf("Expected universe 2 broadcast after clearing routes, got %v", broadcasted)
+	}
+}
+
+func TestUniverseOutputs(t *testing.T) {
+	broadcastPort, unicastPort := 6597, 6598
+	broadcast, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: broadcastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = broadcast.Close() }()
+	node, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: unicastPort})
+	if err != nil {
+		t.Fatalf("Failed to create UDP listener: %v", err)
+	}
+	defer func() { _ = node.Close() }()
+
+	service := NewService(Config{Enabled: true, BroadcastAddr: "127.0.0.1", Port: broadcastPort})
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+
+	if err := service.SetUniverseOutputs([]UniverseOutput{{Universe: 1, Enabled: true, Destinations: []string{"127.0.0.1:0"}}}); err == nil {
+		t.Error("Expected an invalid destination rejected")
+	}
+	if err := service.SetUniverseOutputs([]UniverseOutput{
+		{Universe: 1, Enabled: true},
+		{Universe: 2, Enabled: true, Destinations: []string{"127.0.0.1:6598"}},
+		{Universe: 3, Enabled: false},
+	}); err != nil {
+		t.Fatalf("SetUniverseOutputs() error: %v", err)
+	}
+	service.SetChannelValue(1, 1, 11)
+	service.SetChannelValue(2, 1, 22)
+	service.SetChannelValue(3, 1, 33)
+	service.ForceImmediateTransmission()
+
+	if unicast := receiveUniverses(t, node); len(unicast) != 1 || unicast[2] != 22 {
+		t.Errorf("Expected only universe 2 sent to its destination, got %v", unicast)
+	}
+	broadcasted := receiveUniverses(t, broadcast)
+	if _, ok := broadcasted[3]; ok || broadcasted[1] != 11 {
+		t.Errorf("Expected universe 1 broadcast and disabled universe 3 not sent, got %v", broadcasted)
+	}
+
+	// The routing table takes precedence over a universe's destination
+	if err := service.SetUnicastRoutes([]UnicastRoute{{Universe: 2, Destinations: []string{"127.0.0.1:6597"}}}); err != nil {
+		t.Fatalf("SetUnicastRoutes() error: %v", err)
+	}
+	service.ForceImmediateTransmission()
+	if unicast := receiveUniverses(t, node); len(unicast) != 0 {
+		t.Errorf("Expected nothing sent to the universe's destination, got %v", unicast)
+	}
+}
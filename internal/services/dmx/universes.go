@@ -0,0 +1,45 @@
+package dmx
+
+// UniverseOutput is how a universe is output, as projects configure it.
+type UniverseOutput struct {
+	Universe int
+	// Disabled universes are not transmitted
+	Enabled bool
+	// Destinations like a unicast route's; broadcast when empty. The unicast
+	// routing table takes precedence.
+	Destinations []string
+}
+
+// SetUniverseOutputs validates and applies the output of each configured
+// universe, replacing the previous configuration. Universes not listed are
+// enabled and broadcast.
+func (s *Service) SetUniverseOutputs(outputs []UniverseOutput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var routes []UnicastRoute
+	disabled := make(map[int]bool)
+	for _, output := range outputs {
+		if !output.Enabled {
+			disabled[output.Universe] = true
+		}
+		if len(output.Destinations) > 0 {
+			routes = append(routes, UnicastRoute{Universe: output.Universe, Destinations: output.Destinations})
+		}
+	}
+	resolved, err := ResolveUnicastRoutes(routes, s.port)
+	if err != nil {
+		return err
+	}
+	if err := s.updateUnicastConnLocked(len(resolved) > 0 || len(s.unicastRoutes) > 0 || len(s.visualizerUniversesLocked()) > 0); err != nil {
+		return err
+	}
+
+	s.universeRoutes = resolved
+	s.disabledUniverses = disabled
+
+	// Nodes whose universe was just enabled or re-routed get a fresh frame
+	s.resendAllLocked()
+	s.triggerHighRate()
+	return nil
+}
@@ -0,0 +1,413 @@
+// Package dmxrecord records the DMX output to files and replays them in
+// place of live output, so an installation can run unattended from a
+// recording.
+//
+// A recording is a gzip-compressed file of JSON lines: a header, then a
+// line for each universe frame whose output changed, with its time since
+// the recording started in milliseconds, the universe, and its channels in
+// base64. A last line marks the end of the recording, so a looped replay
+// waits out the time after the last change.
+package dmxrecord
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// Format identifies the recording file format.
+const Format = "lacylights-dmx-recording/1"
+
+// Extension is the file extension of recordings.
+const Extension = ".dmxrec"
+
+// namePrefix starts the name of every recording the service makes.
+const namePrefix = "dmx-"
+
+// nameTimeLayout is the UTC time in a recording's name, which sorts by age.
+const nameTimeLayout = "20060102-150405"
+
+// frameBuffer is how many frames may wait to be written; frames arriving
+// while it is full are dropped rather than hold up the output.
+const frameBuffer = 1024
+
+// validName matches recording names safe to use as file names.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*\.dmxrec$`)
+
+// Errors returned for requests the service's state does not allow.
+var (
+	ErrRecording    = errors.New("already recording the DMX output")
+	ErrNotRecording = errors.New("not recording the DMX output")
+	ErrNotFound     = errors.New("recording not found")
+	ErrInUse        = errors.New("recording is being recorded or replayed")
+)
+
+// DMX is the output recorded and replayed. *dmx.Service implements it.
+type DMX interface {
+	AddSink(sink dmx.Sink) (remove func())
+	GetAllUniverses() map[int][]int
+	PlayFrame(universe int, channels []byte)
+	StopReplay()
+}
+
+// header is the first line of a recording.
+type header struct {
+	Format    string    `json:"format"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// frame is a line of a recording: a universe frame, or the end.
+type frame struct {
+	Time     int64  `json:"t"` // Milliseconds since the recording started
+	Universe int    `json:"u,omitempty"`
+	Channels []byte `json:"c,omitempty"`
+	End      bool   `json:"end,omitempty"`
+}
+
+// Info describes a recording file.
+type Info struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// Status is what the service is recording and replaying.
+type Status struct {
+	// Recording is the file being recorded; empty when not recording
+	Recording          string
+	RecordingStartedAt time.Time
+	// Frames written to the recording, and dropped because the disk fell
+	// behind the output
+	RecordedFrames int
+	DroppedFrames  int
+
+	// Replaying is the recording replayed in place of live output; empty
+	// when nothing is replaying
+	Replaying       string
+	ReplayStartedAt time.Time
+	Loop            bool
+}
+
+// Service records and replays the DMX output.
+type Service struct {
+	dir    string
+	output DMX
+
+	mu        sync.Mutex
+	recording *recording
+	replay    *replay
+}
+
+// NewService creates a service keeping recordings in dir.
+func NewService(dir string, output DMX) *Service {
+	return &Service{dir: dir, output: output}
+}
+
+// Dir returns the directory recordings are kept in.
+func (s *Service) Dir() string {
+	return s.dir
+}
+
+// recording is a recording being written. It receives frames from the DMX
+// service as a sink and writes them from its own goroutine.
+type recording struct {
+	name      string
+	startedAt time.Time
+	file      *os.File
+
+	frames     chan frame
+	done       chan struct{}
+	removeSink func()
+
+	written atomic.Int64
+	dropped atomic.Int64
+	err     error // First write error; read once done is closed
+}
+
+// WriteFrame queues a transmitted frame. It is called with the DMX service
+// locked, so it never waits.
+func (r *recording) WriteFrame(universe int, channels []byte) {
+	f := frame{
+		Time:     time.Since(r.startedAt).Milliseconds(),
+		Universe: universe,
+		Channels: append([]byte(nil), channels...),
+	}
+	select {
+	case r.frames <- f:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// write writes queued frames that changed their universe until frames is
+// closed, then ends the recording.
+func (r *recording) write() {
+	defer close(r.done)
+
+	zw := gzip.NewWriter(r.file)
+	encoder := json.NewEncoder(zw)
+	fail := func(err error) {
+		if err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+	fail(encoder.Encode(header{Format: Format, StartedAt: r.startedAt}))
+
+	last := make(map[int][]byte)
+	for f := range r.frames {
+		if r.err != nil || bytes.Equal(last[f.Universe], f.Channels) {
+			continue
+		}
+		if err := encoder.Encode(f); err != nil {
+			fail(err)
+			continue
+		}
+		last[f.Universe] = f.Channels
+		r.written.Add(1)
+	}
+
+	fail(encoder.Encode(frame{Time: time.Since(r.startedAt).Milliseconds(), End: true}))
+	fail(zw.Close())
+	fail(r.file.Sync())
+	fail(r.file.Close())
+}
+
+// StartRecording starts recording the DMX output to a new file named with
+// the current time. The recording starts with every universe's current
+// output.
+func (s *Service) StartRecording() (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recording != nil {
+		return s.statusLocked(), ErrRecording
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return s.statusLocked(), fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	startedAt := time.Now()
+	name := namePrefix + startedAt.UTC().Format(nameTimeLayout) + Extension
+	file, err := os.OpenFile(filepath.Join(s.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return s.statusLocked(), fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	r := &recording{
+		name:      name,
+		startedAt: startedAt,
+		file:      file,
+		frames:    make(chan frame, frameBuffer),
+		done:      make(chan struct{}),
+	}
+	go r.write()
+
+	// Transmitted frames only follow changes and keep-alives, so start
+	// from the current output
+	universes := s.output.GetAllUniverses()
+	numbers := make([]int, 0, len(universes))
+	for universe := range universes {
+		numbers = append(numbers, universe)
+	}
+	sort.Ints(numbers)
+	for _, universe := range numbers {
+		channels := make([]byte, len(universes[universe]))
+		for i, value := range universes[universe] {
+			channels[i] = byte(value)
+		}
+		r.WriteFrame(universe, channels)
+	}
+	r.removeSink = s.output.AddSink(r)
+	s.recording = r
+
+	log.Info("⏺️ Recording the DMX output", "file", name)
+	return s.statusLocked(), nil
+}
+
+// StopRecording stops recording and returns the finished recording.
+func (s *Service) StopRecording() (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.recording
+	if r == nil {
+		return Info{}, ErrNotRecording
+	}
+	s.recording = nil
+	r.removeSink()
+	close(r.frames)
+	<-r.done
+	if r.err != nil {
+		return Info{}, fmt.Errorf("failed to write recording %s: %w", r.name, r.err)
+	}
+
+	info, err := os.Stat(r.file.Name())
+	if err != nil {
+		return Info{}, err
+	}
+	log.Info("⏹️ Finished recording the DMX output", "file", r.name, "frames", r.written.Load(), "dropped", r.dropped.Load())
+	return Info{Name: r.name, Size: info.Size(), CreatedAt: r.startedAt}, nil
+}
+
+// Status returns what the service is recording and replaying.
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusLocked()
+}
+
+func (s *Service) statusLocked() Status {
+	var status Status
+	if r := s.recording; r != nil {
+		status.Recording = r.name
+		status.RecordingStartedAt = r.startedAt
+		status.RecordedFrames = int(r.written.Load())
+		status.DroppedFrames = int(r.dropped.Load())
+	}
+	if p := s.replay; p != nil {
+		status.Replaying = p.name
+		status.ReplayStartedAt = p.startedAt
+		status.Loop = p.loop
+	}
+	return status
+}
+
+// Recordings returns the recordings in the directory, newest first. A
+// directory that does not exist yet has none.
+func (s *Service) Recordings() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recordings := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !validName.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		recordings = append(recordings, Info{Name: entry.Name(), Size: info.Size(), CreatedAt: createdAt(entry.Name(), info.ModTime())})
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].CreatedAt.After(recordings[j].CreatedAt)
+	})
+	return recordings, nil
+}
+
+// createdAt returns the time in a recording's name, or modTime for
+// recordings named by hand.
+func createdAt(name string, modTime time.Time) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, namePrefix), Extension)
+	if t, err := time.Parse(nameTimeLayout, stamp); err == nil {
+		return t
+	}
+	return modTime
+}
+
+// DeleteRecording removes a recording that is not being recorded or
+// replayed.
+func (s *Service) DeleteRecording(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if (s.recording != nil && s.recording.name == name) || (s.replay != nil && s.replay.name == name) {
+		return fmt.Errorf("%w: %s", ErrInUse, name)
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return err
+}
+
+// path returns the file of a recording, rejecting names that could reach
+// outside the directory.
+func (s *Service) path(name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid recording name %q", name)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+// Cleanup stops recording, finishing the file, and stops replaying.
+func (s *Service) Cleanup() {
+	if _, err := s.StopRecording(); err != nil && !errors.Is(err, ErrNotRecording) {
+		log.Warn("failed to finish DMX recording", "error", err)
+	}
+	s.StopReplay()
+}
+
+// reader reads the frames of a recording file.
+type reader struct {
+	file    *os.File
+	zr      *gzip.Reader
+	decoder *json.Decoder
+}
+
+// openRecording opens a recording and checks its header.
+func openRecording(path string) (*reader, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, filepath.Base(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("invalid recording %s: %w", filepath.Base(path), err)
+	}
+	r := &reader{file: file, zr: zr, decoder: json.NewDecoder(zr)}
+
+	var h header
+	if err := r.decoder.Decode(&h); err != nil || h.Format != Format {
+		_ = r.Close()
+		return nil, fmt.Errorf("invalid recording %s: not a DMX recording", filepath.Base(path))
+	}
+	return r, nil
+}
+
+// next returns the next frame, io.EOF after the last, or a frame with End
+// set for the recording's end.
+func (r *reader) next() (frame, error) {
+	var f frame
+	if err := r.decoder.Decode(&f); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// A recording cut short by a crash plays up to where it stops
+			return f, io.EOF
+		}
+		return f, err
+	}
+	if !f.End && (f.Universe < 1 || len(f.Channels) != dmx.UniverseSize) {
+		return f, fmt.Errorf("invalid frame at %dms", f.Time)
+	}
+	return f, nil
+}
+
+func (r *reader) Close() error {
+	_ = r.zr.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,213 @@
+package dmxrecord
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// fakeDMX stands in for the DMX service, sending frames to its sinks when
+// told and keeping the frames replayed to it.
+type fakeDMX struct {
+	mu       sync.Mutex
+	sinks    map[int]dmx.Sink
+	nextSink int
+	played   map[int][]byte
+	plays    int
+	stopped  int
+}
+
+func newFakeDMX() *fakeDMX {
+	return &fakeDMX{sinks: make(map[int]dmx.Sink), played: make(map[int][]byte)}
+}
+
+func (f *fakeDMX) AddSink(sink dmx.Sink) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextSink
+	f.nextSink++
+	f.sinks[id] = sink
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.sinks, id)
+	}
+}
+
+func (f *fakeDMX) GetAllUniverses() map[int][]int {
+	channels := make([]int, dmx.UniverseSize)
+	channels[0] = 5
+	return map[int][]int{1: channels}
+}
+
+func (f *fakeDMX) PlayFrame(universe int, channels []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.played[universe] = append([]byte(nil), channels...)
+	f.plays++
+}
+
+func (f *fakeDMX) StopReplay() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.played = make(map[int][]byte)
+	f.stopped++
+}
+
+// send transmits a frame with channel 1 at value to the sinks.
+func (f *fakeDMX) send(universe int, value byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	channels := make([]byte, dmx.UniverseSize)
+	channels[0] = value
+	for _, sink := range f.sinks {
+		sink.WriteFrame(universe, channels)
+	}
+}
+
+func (f *fakeDMX) playedValue(universe int) (byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	channels, ok := f.played[universe]
+	if !ok {
+		return 0, false
+	}
+	return channels[0], true
+}
+
+// waitFor polls until ok returns true or a second passes.
+func waitFor(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	output := newFakeDMX()
+	service := NewService(filepath.Join(t.TempDir(), "recordings"), output)
+
+	status, err := service.StartRecording()
+	if err != nil {
+		t.Fatalf("StartRecording() error: %v", err)
+	}
+	if status.Recording == "" {
+		t.Fatal("Expected a recording file in the status")
+	}
+	if _, err := service.StartRecording(); !errors.Is(err, ErrRecording) {
+		t.Errorf("Expected ErrRecording starting twice, got %v", err)
+	}
+
+	output.send(1, 5) // Unchanged from the starting output, so not written
+	output.send(2, 100)
+	time.Sleep(30 * time.Millisecond)
+	output.send(2, 200)
+	output.send(2, 200)
+	info, err := service.StopRecording()
+	if err != nil {
+		t.Fatalf("StopRecording() error: %v", err)
+	}
+	if info.Name != status.Recording || info.Size == 0 {
+		t.Errorf("Unexpected recording info: %+v", info)
+	}
+	if len(output.sinks) != 0 {
+		t.Error("Expected the recording's sink removed")
+	}
+	if _, err := service.StopRecording(); !errors.Is(err, ErrNotRecording) {
+		t.Errorf("Expected ErrNotRecording stopping twice, got %v", err)
+	}
+
+	recordings, err := service.Recordings()
+	if err != nil || len(recordings) != 1 || recordings[0].Name != info.Name {
+		t.Fatalf("Recordings() = %+v, %v; want the finished recording", recordings, err)
+	}
+
+	// The replay sends each changed frame in order
+	if _, err := service.Replay(info.Name, false); err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if got := service.Status(); got.Replaying != info.Name || got.Loop {
+		t.Errorf("Unexpected status while replaying: %+v", got)
+	}
+	waitFor(t, "the replay to finish", func() bool {
+		value, _ := output.playedValue(2)
+		return value == 200
+	})
+	if value, ok := output.playedValue(1); !ok || value != 5 {
+		t.Errorf("Expected universe 1 replayed at 5, got %d (%v)", value, ok)
+	}
+	output.mu.Lock()
+	plays := output.plays
+	output.mu.Unlock()
+	if plays != 3 {
+		t.Errorf("Expected 3 frames replayed, got %d", plays)
+	}
+
+	// A recording cannot be deleted while it replays
+	if err := service.DeleteRecording(info.Name); !errors.Is(err, ErrInUse) {
+		t.Errorf("Expected ErrInUse deleting the replayed recording, got %v", err)
+	}
+	service.StopReplay()
+	if _, ok := output.playedValue(2); ok || service.Status().Replaying != "" {
+		t.Error("Expected the output returned to live once the replay stopped")
+	}
+
+	// A looped replay starts over at the end
+	if _, err := service.Replay(info.Name, true); err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	waitFor(t, "the replay to loop", func() bool {
+		output.mu.Lock()
+		defer output.mu.Unlock()
+		return output.plays >= plays+4
+	})
+	service.Cleanup()
+	if service.Status().Replaying != "" {
+		t.Error("Expected Cleanup() to stop the replay")
+	}
+
+	if err := service.DeleteRecording(info.Name); err != nil {
+		t.Fatalf("DeleteRecording() error: %v", err)
+	}
+	if err := service.DeleteRecording(info.Name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestReplay_InvalidRecordings(t *testing.T) {
+	dir := t.TempDir()
+	service := NewService(dir, newFakeDMX())
+
+	if _, err := service.Replay("../secrets.dmxrec", false); err == nil {
+		t.Error("Expected an error for a name outside the directory")
+	}
+	if _, err := service.Replay("missing.dmxrec", false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "junk.dmxrec"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Replay("junk.dmxrec", false); err == nil {
+		t.Error("Expected an error replaying a file that is not a recording")
+	}
+	if service.Status().Replaying != "" {
+		t.Error("Expected nothing replaying after failed replays")
+	}
+}
+
+func TestRecordings_MissingDirectory(t *testing.T) {
+	service := NewService(filepath.Join(t.TempDir(), "missing"), newFakeDMX())
+	recordings, err := service.Recordings()
+	if err != nil || len(recordings) != 0 {
+		t.Errorf("Recordings() = %v, %v; want none", recordings, err)
+	}
+}
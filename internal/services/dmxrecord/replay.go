@@ -0,0 +1,131 @@
+package dmxrecord
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// replay is a recording being replayed.
+type replay struct {
+	name      string
+	path      string
+	startedAt time.Time
+	loop      bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Replay replays a recording through the DMX output in place of live
+// output, at the speed it was recorded, replacing any replay already
+// running. A looped replay starts over at the end of the recording;
+// otherwise the last frames hold until StopReplay.
+func (s *Service) Replay(name string, loop bool) (Status, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return s.Status(), err
+	}
+	reader, err := openRecording(path)
+	if err != nil {
+		return s.Status(), err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopReplayLocked()
+
+	p := &replay{
+		name:      name,
+		path:      path,
+		startedAt: time.Now(),
+		loop:      loop,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.replay = p
+	go p.run(s.output, reader)
+
+	log.Info("▶️ Replaying a DMX recording", "file", name, "loop", loop)
+	return s.statusLocked(), nil
+}
+
+// StopReplay stops replaying and returns every universe to live output.
+func (s *Service) StopReplay() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.replay == nil {
+		return
+	}
+	s.stopReplayLocked()
+	log.Info("⏹️ Stopped replaying the DMX recording")
+}
+
+func (s *Service) stopReplayLocked() {
+	if s.replay == nil {
+		return
+	}
+	close(s.replay.stop)
+	<-s.replay.done
+	s.replay = nil
+	s.output.StopReplay()
+}
+
+// run plays the recording, over and over when looping, until stopped.
+func (p *replay) run(output DMX, r *reader) {
+	defer close(p.done)
+
+	for {
+		finished, err := p.play(output, r)
+		_ = r.Close()
+		if err != nil {
+			log.Warn("replay stopped on an invalid recording, holding its last frames", "file", p.name, "error", err)
+			return
+		}
+		if !finished || !p.loop {
+			return
+		}
+		if r, err = openRecording(p.path); err != nil {
+			log.Warn("failed to loop the DMX recording, holding its last frames", "file", p.name, "error", err)
+			return
+		}
+	}
+}
+
+// play sends the recording's frames at their times, reporting whether it
+// reached the end rather than being stopped.
+func (p *replay) play(output DMX, r *reader) (bool, error) {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		f, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if wait := time.Until(start.Add(time.Duration(f.Time) * time.Millisecond)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-p.stop:
+				return false, nil
+			case <-timer.C:
+			}
+		} else {
+			select {
+			case <-p.stop:
+				return false, nil
+			default:
+			}
+		}
+
+		if f.End {
+			return true, nil
+		}
+		output.PlayFrame(f.Universe, f.Channels)
+	}
+}
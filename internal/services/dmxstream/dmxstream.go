@@ -0,0 +1,402 @@
+// Package dmxstream streams DMX output to visualizers over a websocket,
+// outside GraphQL, so 3D pre-visualization tools and browser pre-viz can
+// follow the output at the DMX refresh rate without polling.
+//
+// Each universe frame the DMX service transmits is sent as one binary
+// message: the universe number as a big-endian uint16, followed by its 512
+// channel values. A client receives every universe's current output when it
+// connects and then each frame as it is transmitted. A client can limit
+// itself to some universes with a universes query parameter (for example
+// ?universes=1,2) or by sending a text message such as {"universes":[1,2]};
+// an empty list selects every universe.
+package dmxstream
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// StreamPath is the HTTP path of the stream endpoint.
+const StreamPath = "/dmx/stream"
+
+// HeaderSize is the size of the universe number preceding a frame's
+// channel values.
+const HeaderSize = 2
+
+// maxUniverse bounds the universe numbers a client can select.
+const maxUniverse = 32768
+
+const (
+	// writeWait bounds how long a frame may take to reach a client.
+	writeWait = 5 * time.Second
+	// pingInterval is how often idle connections are checked.
+	pingInterval = 10 * time.Second
+	// pongWait is how long a client may go without answering a ping.
+	pongWait = 3 * pingInterval
+	// maxMessageSize bounds the filter messages clients send.
+	maxMessageSize = 4096
+)
+
+// Source is the DMX output the stream follows. *dmx.Service implements it.
+type Source interface {
+	AddSink(sink dmx.Sink) (remove func())
+	GetAllUniverses() map[int][]int
+}
+
+// Service accepts stream connections and fans frames out to them. It only
+// receives frames from its source while clients are connected.
+type Service struct {
+	source   Source
+	upgrader websocket.Upgrader
+
+	// Guards attaching to and detaching from the source; never held while
+	// the source calls WriteFrame
+	attachMu   sync.Mutex
+	removeSink func()
+
+	mu        sync.Mutex
+	clients   map[*client]bool
+	authorize func(r *http.Request) error
+}
+
+// NewService creates a stream service for a DMX output source.
+func NewService(source Source) *Service {
+	return &Service{
+		source: source,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Visualizers run on any origin, as GraphQL allows
+			},
+			ReadBufferSize:  maxMessageSize,
+			WriteBufferSize: HeaderSize + dmx.UniverseSize,
+		},
+		clients: make(map[*client]bool),
+	}
+}
+
+// SetAuthorizer sets the check a connection request must pass before it is
+// upgraded (optional).
+func (s *Service) SetAuthorizer(authorize func(r *http.Request) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorize = authorize
+}
+
+// ServeHTTP upgrades a request to a stream connection.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	authorize := s.authorize
+	s.mu.Unlock()
+	if authorize != nil {
+		if err := authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	universes, err := ParseUniverses(r.URL.Query().Get("universes"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		return
+	}
+	c := newClient(conn, universes)
+	s.add(c)
+	s.sendCurrent(c, nil)
+
+	go s.writeLoop(c)
+	s.readLoop(c)
+}
+
+// WriteFrame implements dmx.Sink, queueing a frame for each client that
+// selected its universe.
+func (s *Service) WriteFrame(universe int, channels []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.queue(universe, channels)
+	}
+}
+
+// ClientCount returns the number of connected clients.
+func (s *Service) ClientCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// Cleanup closes every connection.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.close()
+		s.remove(c)
+	}
+}
+
+// add registers a client, attaching to the source for the first one.
+func (s *Service) add(c *client) {
+	s.attachMu.Lock()
+	defer s.attachMu.Unlock()
+
+	s.mu.Lock()
+	s.clients[c] = true
+	first := len(s.clients) == 1
+	s.mu.Unlock()
+
+	if first && s.removeSink == nil {
+		s.removeSink = s.source.AddSink(s)
+	}
+}
+
+// remove unregisters a client, detaching from the source after the last.
+func (s *Service) remove(c *client) {
+	s.attachMu.Lock()
+	defer s.attachMu.Unlock()
+
+	s.mu.Lock()
+	delete(s.clients, c)
+	last := len(s.clients) == 0
+	s.mu.Unlock()
+
+	if last && s.removeSink != nil {
+		s.removeSink()
+		s.removeSink = nil
+	}
+}
+
+// sendCurrent queues the current output of the client's universes, or of
+// only those in only when it is not nil.
+func (s *Service) sendCurrent(c *client, only map[int]bool) {
+	for universe, values := range s.source.GetAllUniverses() {
+		if only != nil && !only[universe] {
+			continue
+		}
+		channels := make([]byte, len(values))
+		for i, v := range values {
+			channels[i] = byte(v)
+		}
+		c.queue(universe, channels)
+	}
+}
+
+// readLoop applies the filter messages a client sends until it
+// disconnects.
+func (s *Service) readLoop(c *client) {
+	defer func() {
+		c.close()
+		s.remove(c)
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+		var message struct {
+			Universes []int `json:"universes"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Warn("ignoring invalid DMX stream message", "error", err)
+			continue
+		}
+		universes, err := universeSet(message.Universes)
+		if err != nil {
+			log.Warn("ignoring invalid DMX stream message", "error", err)
+			continue
+		}
+		// Newly selected universes start from their current output
+		s.sendCurrent(c, c.setUniverses(universes))
+	}
+}
+
+// writeLoop sends a client its queued frames until it disconnects.
+func (s *Service) writeLoop(c *client) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.close()
+				return
+			}
+		case <-c.wake:
+			for _, frame := range c.take() {
+				_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := c.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+					c.close()
+					return
+				}
+			}
+		}
+	}
+}
+
+// client is one stream connection. Frames that arrive faster than a client
+// reads replace the unsent frame of their universe, so slow clients see the
+// latest output rather than falling behind.
+type client struct {
+	conn *websocket.Conn
+
+	mu        sync.Mutex
+	universes map[int]bool // nil selects every universe
+	pending   map[int][]byte
+	wake      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newClient(conn *websocket.Conn, universes map[int]bool) *client {
+	return &client{
+		conn:      conn,
+		universes: universes,
+		pending:   make(map[int][]byte),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+}
+
+// queue replaces the client's unsent frame for a universe it selected.
+func (c *client) queue(universe int, channels []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.universes != nil && !c.universes[universe] {
+		return
+	}
+	c.pending[universe] = channels
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take removes the queued frames, encoded in universe order.
+func (c *client) take() [][]byte {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[int][]byte)
+	c.mu.Unlock()
+
+	universes := make([]int, 0, len(pending))
+	for universe := range pending {
+		universes = append(universes, universe)
+	}
+	sort.Ints(universes)
+
+	frames := make([][]byte, len(universes))
+	for i, universe := range universes {
+		frames[i] = EncodeFrame(universe, pending[universe])
+	}
+	return frames
+}
+
+// setUniverses changes the client's selection, returning the universes it
+// did not select before, or nil when it now selects every universe.
+func (c *client) setUniverses(universes map[int]bool) map[int]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.universes
+	c.universes = universes
+	for universe := range c.pending {
+		if universes != nil && !universes[universe] {
+			delete(c.pending, universe)
+		}
+	}
+	if previous == nil {
+		return map[int]bool{}
+	}
+	if universes == nil {
+		return nil
+	}
+	added := make(map[int]bool)
+	for universe := range universes {
+		if !previous[universe] {
+			added[universe] = true
+		}
+	}
+	return added
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
+
+// EncodeFrame encodes a universe frame as a stream message.
+func EncodeFrame(universe int, channels []byte) []byte {
+	frame := make([]byte, HeaderSize+dmx.UniverseSize)
+	binary.BigEndian.PutUint16(frame, uint16(universe))
+	copy(frame[HeaderSize:], channels)
+	return frame
+}
+
+// ParseUniverses parses a comma-separated universe list. An empty list
+// selects every universe and gives nil.
+func ParseUniverses(value string) (map[int]bool, error) {
+	var universes []int
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		universe, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid universe: %s", part)
+		}
+		universes = append(universes, universe)
+	}
+	return universeSet(universes)
+}
+
+// universeSet validates a universe selection. An empty selection selects
+// every universe and gives nil.
+func universeSet(universes []int) (map[int]bool, error) {
+	if len(universes) == 0 {
+		return nil, nil
+	}
+	set := make(map[int]bool, len(universes))
+	for _, universe := range universes {
+		if universe < 1 || universe > maxUniverse {
+			return nil, fmt.Errorf("universe must be between 1 and %d", maxUniverse)
+		}
+		set[universe] = true
+	}
+	return set, nil
+}
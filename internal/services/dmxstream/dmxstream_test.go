@@ -0,0 +1,189 @@
+package dmxstream
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// fakeSource is a DMX output with fixed universes.
+type fakeSource struct {
+	mu        sync.Mutex
+	sinks     int
+	universes map[int][]int
+}
+
+func (f *fakeSource) AddSink(sink dmx.Sink) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sinks++
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.sinks--
+	}
+}
+
+func (f *fakeSource) GetAllUniverses() map[int][]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.universes
+}
+
+func (f *fakeSource) sinkCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sinks
+}
+
+func newSource() *fakeSource {
+	universes := make(map[int][]int)
+	for u := 1; u <= 3; u++ {
+		universes[u] = make([]int, dmx.UniverseSize)
+		universes[u][0] = u * 10
+	}
+	return &fakeSource{universes: universes}
+}
+
+func dial(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + StreamPath + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// readFrame reads one frame, returning its universe and first channel.
+func readFrame(t *testing.T, conn *websocket.Conn) (int, byte) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error: %v", err)
+	}
+	if messageType != websocket.BinaryMessage || len(data) != HeaderSize+dmx.UniverseSize {
+		t.Fatalf("Got message type %d of %d bytes, want a binary frame", messageType, len(data))
+	}
+	return int(binary.BigEndian.Uint16(data)), data[HeaderSize]
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStream_FiltersUniverses(t *testing.T) {
+	source := newSource()
+	s := NewService(source)
+	server := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	defer server.Close()
+
+	conn := dial(t, server, "?universes=2")
+	if universe, value := readFrame(t, conn); universe != 2 || value != 20 {
+		t.Fatalf("Initial frame = universe %d value %d, want universe 2 value 20", universe, value)
+	}
+
+	s.WriteFrame(1, []byte{99})
+	s.WriteFrame(2, []byte{42})
+	if universe, value := readFrame(t, conn); universe != 2 || value != 42 {
+		t.Errorf("Frame = universe %d value %d, want universe 2 value 42", universe, value)
+	}
+
+	// Switching to universe 3 sends its current output first
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"universes":[3]}`)); err != nil {
+		t.Fatalf("WriteMessage() error: %v", err)
+	}
+	if universe, value := readFrame(t, conn); universe != 3 || value != 30 {
+		t.Errorf("Frame = universe %d value %d, want universe 3 value 30", universe, value)
+	}
+	s.WriteFrame(2, []byte{1})
+	s.WriteFrame(3, []byte{7})
+	if universe, value := readFrame(t, conn); universe != 3 || value != 7 {
+		t.Errorf("Frame = universe %d value %d, want universe 3 value 7", universe, value)
+	}
+}
+
+func TestStream_AllUniversesAndDetach(t *testing.T) {
+	source := newSource()
+	s := NewService(source)
+	server := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	defer server.Close()
+
+	conn := dial(t, server, "")
+	for want := 1; want <= 3; want++ {
+		if universe, _ := readFrame(t, conn); universe != want {
+			t.Errorf("Initial frame universe = %d, want %d", universe, want)
+		}
+	}
+	if source.sinkCount() != 1 || s.ClientCount() != 1 {
+		t.Errorf("Sinks = %d, clients = %d, want 1 each", source.sinkCount(), s.ClientCount())
+	}
+
+	_ = conn.Close()
+	waitFor(t, func() bool { return s.ClientCount() == 0 })
+	if source.sinkCount() != 0 {
+		t.Error("Expected the stream to detach from the source after the last client")
+	}
+}
+
+func TestStream_Rejections(t *testing.T) {
+	s := NewService(newSource())
+	s.SetAuthorizer(func(r *http.Request) error {
+		if r.URL.Query().Get("token") != "secret" {
+			return errors.New("authentication required")
+		}
+		return nil
+	})
+	server := httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + StreamPath
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %v", err)
+	}
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?token=secret&universes=0", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for universe 0, got %v", err)
+	}
+	conn := dial(t, server, "?token=secret&universes=1")
+	if universe, _ := readFrame(t, conn); universe != 1 {
+		t.Errorf("Initial frame universe = %d, want 1", universe)
+	}
+
+	s.Cleanup()
+	waitFor(t, func() bool { return s.ClientCount() == 0 })
+}
+
+func TestParseUniverses(t *testing.T) {
+	universes, err := ParseUniverses(" 1, 4 ,,4")
+	if err != nil || len(universes) != 2 || !universes[1] || !universes[4] {
+		t.Errorf("ParseUniverses() = %v, %v, want {1, 4}", universes, err)
+	}
+	if universes, err := ParseUniverses(""); err != nil || universes != nil {
+		t.Errorf("ParseUniverses(\"\") = %v, %v, want every universe", universes, err)
+	}
+	for _, value := range []string{"x", "0", "40000"} {
+		if _, err := ParseUniverses(value); err == nil {
+			t.Errorf("ParseUniverses(%q): expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+// Package effects runs chases and waveform generators over fixtures.
+//
+// Running effects are rendered on every fade engine tick into their own DMX
+// output layer, so they take precedence over scene values on the channels
+// they drive without changing them: stopping an effect reveals the live look
+// underneath. An effect can run standalone or be attached to a scene, in
+// which case it runs while that scene is the active scene.
+package effects
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// Type selects what an effect renders.
+type Type string
+
+const (
+	// TypeChase steps a lit window through the fixtures.
+	TypeChase Type = "CHASE"
+	// TypeSine swings fixture intensity along a sine wave.
+	TypeSine Type = "SINE"
+	// TypeRamp raises fixture intensity from low to high, then snaps back.
+	TypeRamp Type = "RAMP"
+	// TypeRainbow cycles fixture color through the hue wheel.
+	TypeRainbow Type = "RAINBOW"
+)
+
+// Order is the order the effect moves through its fixtures.
+type Order string
+
+const (
+	// OrderForward follows the fixture list.
+	OrderForward Order = "FORWARD"
+	// OrderReverse follows the fixture list backwards.
+	OrderReverse Order = "REVERSE"
+	// OrderRandom shuffles the fixtures each time the effect starts.
+	OrderRandom Order = "RANDOM"
+)
+
+// MaxRate bounds the effect rate in cycles per second.
+const MaxRate = 20.0
+
+// Fixture is the channels of one fixture an effect can drive, as 1-indexed
+// absolute DMX channels. Color channels are 0 when the fixture has none.
+type Fixture struct {
+	ID        string
+	Universe  int
+	Intensity []int
+	Red       int
+	Green     int
+	Blue      int
+}
+
+func (f Fixture) hasColor() bool {
+	return f.Red > 0 && f.Green > 0 && f.Blue > 0
+}
+
+// Params shape an effect.
+type Params struct {
+	// Rate is in cycles per second; a chase visits every step once per cycle
+	Rate float64
+	// Size is the fraction of the fixtures lit at once for a chase, or the
+	// fraction of a cycle spread across the fixtures for the other types
+	// (0 moves all fixtures in unison)
+	Size float64
+	// PhaseOffset shifts the effect by a fraction of a cycle
+	PhaseOffset float64
+	Order       Order
+	// Low and High bound the output as fractions of full; rainbows use High
+	// as their brightness
+	Low  float64
+	High float64
+}
+
+// DefaultParams returns the parameters of a new effect.
+func DefaultParams() Params {
+	return Params{Rate: 1, Size: 1, Order: OrderForward, Low: 0, High: 1}
+}
+
+// Effect is an effect ready to run.
+type Effect struct {
+	ID       string
+	Type     Type
+	Fixtures []Fixture
+	Params
+}
+
+// ValidateParams checks an effect's type and parameters.
+func ValidateParams(effectType Type, params Params) error {
+	switch effectType {
+	case TypeChase, TypeSine, TypeRamp, TypeRainbow:
+	default:
+		return fmt.Errorf("unknown effect type %q", effectType)
+	}
+	switch params.Order {
+	case OrderForward, OrderReverse, OrderRandom:
+	default:
+		return fmt.Errorf("unknown effect order %q", params.Order)
+	}
+	if !(params.Rate > 0 && params.Rate <= MaxRate) {
+		return fmt.Errorf("rate must be above 0 and at most %v cycles per second, got %v", MaxRate, params.Rate)
+	}
+	for name, value := range map[string]float64{"size": params.Size, "phaseOffset": params.PhaseOffset, "low": params.Low, "high": params.High} {
+		if !(value >= 0 && value <= 1) {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, value)
+		}
+	}
+	if params.Low > params.High {
+		return fmt.Errorf("low (%v) must not be above high (%v)", params.Low, params.High)
+	}
+	return nil
+}
+
+// Validate checks the effect can run: its parameters are valid and at least
+// one fixture has the channels its type drives.
+func (e *Effect) Validate() error {
+	if err := ValidateParams(e.Type, e.Params); err != nil {
+		return err
+	}
+	for _, fixture := range e.Fixtures {
+		if e.drives(fixture) {
+			return nil
+		}
+	}
+	if e.Type == TypeRainbow {
+		return fmt.Errorf("a rainbow needs fixtures with red, green, and blue channels")
+	}
+	return fmt.Errorf("effect needs fixtures with intensity or color channels")
+}
+
+// drives reports whether the effect can use a fixture.
+func (e *Effect) drives(fixture Fixture) bool {
+	if e.Type == TypeRainbow {
+		return fixture.hasColor()
+	}
+	return len(fixture.Intensity) > 0
+}
+
+// running is a started effect.
+type running struct {
+	effect    Effect
+	fixtures  []Fixture // Usable fixtures in playing order
+	startedAt time.Time
+	steps     int // Steps advanced by hand, on top of the running cycle
+}
+
+// Service runs effects. It is safe for concurrent use.
+type Service struct {
+	mu         sync.Mutex
+	dmxService *dmx.Service
+	running    map[string]*running
+
+	// Scene-attached effects by scene ID, and the scene they last followed
+	sceneEffects map[string][]Effect
+	activeScene  *string
+
+	now  func() time.Time
+	rand *rand.Rand
+}
+
+// NewService creates an effects service writing to dmxService. Call Tick
+// on every fade engine tick.
+func NewService(dmxService *dmx.Service) *Service {
+	return &Service{
+		dmxService:   dmxService,
+		running:      make(map[string]*running),
+		sceneEffects: make(map[string][]Effect),
+		now:          time.Now,
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// layerID is the DMX layer an effect renders into.
+func layerID(effectID string) string {
+	return "effect:" + effectID
+}
+
+// Start runs an effect. Starting an effect that is already running applies
+// the new definition without restarting its cycle.
+func (s *Service) Start(effect Effect) error {
+	if err := effect.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startLocked(effect)
+	return nil
+}
+
+func (s *Service) startLocked(effect Effect) {
+	var fixtures []Fixture
+	for _, fixture := range effect.Fixtures {
+		if effect.drives(fixture) {
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	switch effect.Order {
+	case OrderReverse:
+		for i, j := 0, len(fixtures)-1; i < j; i, j = i+1, j-1 {
+			fixtures[i], fixtures[j] = fixtures[j], fixtures[i]
+		}
+	case OrderRandom:
+		s.rand.Shuffle(len(fixtures), func(i, j int) { fixtures[i], fixtures[j] = fixtures[j], fixtures[i] })
+	}
+
+	startedAt, steps := s.now(), 0
+	if current, ok := s.running[effect.ID]; ok {
+		startedAt, steps = current.startedAt, current.steps
+		// Drop channels the new definition no longer drives
+		s.dmxService.ClearEffectLayer(layerID(effect.ID))
+	}
+	r := &running{effect: effect, fixtures: fixtures, startedAt: startedAt, steps: steps}
+	s.running[effect.ID] = r
+	s.dmxService.SetEffectLayer(layerID(effect.ID), r.render(s.now()))
+}
+
+// Stop stops an effect and releases its channels. It reports whether the
+// effect was running.
+func (s *Service) Stop(effectID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopLocked(effectID)
+}
+
+func (s *Service) stopLocked(effectID string) bool {
+	if _, ok := s.running[effectID]; !ok {
+		return false
+	}
+	delete(s.running, effectID)
+	s.dmxService.ClearEffectLayer(layerID(effectID))
+	return true
+}
+
+// StopAll stops every running effect.
+func (s *Service) StopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.running {
+		s.stopLocked(id)
+	}
+}
+
+// Advance moves a running effect one step ahead of its cycle, one fixture
+// along a chase, so it can be stepped in time with music. It reports whether
+// the effect was running.
+func (s *Service) Advance(effectID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.running[effectID]
+	if !ok {
+		return false
+	}
+	r.steps++
+	s.dmxService.SetEffectLayer(layerID(effectID), r.render(s.now()))
+	return true
+}
+
+// IsRunning reports whether an effect is running.
+func (s *Service) IsRunning(effectID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.running[effectID]
+	return ok
+}
+
+// Running returns the IDs of the running effects, sorted.
+func (s *Service) Running() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.running))
+	for id := range s.running {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SetSceneEffects replaces the scene-attached effects (scene ID -> effects).
+// Effects of the active scene are started or updated, and running effects no
+// longer attached to it are stopped.
+func (s *Service) SetSceneEffects(sceneEffects map[string][]Effect) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.sceneEffects
+	s.sceneEffects = make(map[string][]Effect, len(sceneEffects))
+	for sceneID, list := range sceneEffects {
+		s.sceneEffects[sceneID] = append([]Effect(nil), list...)
+	}
+
+	if s.activeScene == nil {
+		return
+	}
+	keep := make(map[string]bool)
+	for _, effect := range s.sceneEffects[*s.activeScene] {
+		keep[effect.ID] = true
+		if effect.Validate() == nil {
+			s.startLocked(effect)
+		}
+	}
+	for _, effect := range previous[*s.activeScene] {
+		if !keep[effect.ID] {
+			s.stopLocked(effect.ID)
+		}
+	}
+}
+
+// Tick follows the active scene and renders every running effect.
+func (s *Service) Tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.followSceneLocked(s.dmxService.GetActiveSceneID())
+	for id, r := range s.running {
+		s.dmxService.SetEffectLayer(layerID(id), r.render(now))
+	}
+}
+
+// followSceneLocked stops the effects of the previously active scene and
+// starts those of the new one when the active scene changes.
+func (s *Service) followSceneLocked(sceneID *string) {
+	if sameScene(sceneID, s.activeScene) {
+		return
+	}
+	if s.activeScene != nil {
+		for _, effect := range s.sceneEffects[*s.activeScene] {
+			s.stopLocked(effect.ID)
+		}
+	}
+	s.activeScene = nil
+	if sceneID != nil {
+		id := *sceneID
+		s.activeScene = &id
+		for _, effect := range s.sceneEffects[id] {
+			if effect.Validate() == nil {
+				s.startLocked(effect)
+			}
+		}
+	}
+}
+
+func sameScene(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// render computes the effect's channel values at now.
+func (r *running) render(now time.Time) map[int]map[int]byte {
+	values := make(map[int]map[int]byte)
+	set := func(universe, channel int, level float64) {
+		if values[universe] == nil {
+			values[universe] = make(map[int]byte)
+		}
+		values[universe][channel] = byte(math.Round(math.Max(0, math.Min(1, level)) * 255))
+	}
+
+	p := r.effect.Params
+	count := len(r.fixtures)
+	cycle := now.Sub(r.startedAt).Seconds()*p.Rate + p.PhaseOffset
+	if count > 0 {
+		cycle += float64(r.steps) / float64(count)
+	}
+	for position, fixture := range r.fixtures {
+		var level float64
+		switch r.effect.Type {
+		case TypeChase:
+			step := int(frac(cycle) * float64(count))
+			width := int(math.Max(1, math.Round(p.Size*float64(count))))
+			level = p.Low
+			if ((step-position)%count+count)%count < width {
+				level = p.High
+			}
+		case TypeSine:
+			theta := cycle - p.Size*float64(position)/float64(count)
+			level = p.Low + (p.High-p.Low)*(0.5-0.5*math.Cos(2*math.Pi*theta))
+		case TypeRamp:
+			theta := cycle - p.Size*float64(position)/float64(count)
+			level = p.Low + (p.High-p.Low)*frac(theta)
+		case TypeRainbow:
+			theta := cycle - p.Size*float64(position)/float64(count)
+			red, green, blue := hueToRGB(frac(theta))
+			set(fixture.Universe, fixture.Red, red*p.High)
+			set(fixture.Universe, fixture.Green, green*p.High)
+			set(fixture.Universe, fixture.Blue, blue*p.High)
+			continue
+		}
+		for _, channel := range fixture.Intensity {
+			set(fixture.Universe, channel, level)
+		}
+	}
+	return values
+}
+
+// frac returns the fractional part of x in [0, 1).
+func frac(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+// hueToRGB converts a hue in [0, 1) at full saturation and value to RGB
+// fractions.
+func hueToRGB(hue float64) (float64, float64, float64) {
+	sector := hue * 6
+	x := 1 - math.Abs(math.Mod(sector, 2)-1)
+	switch int(sector) {
+	case 0:
+		return 1, x, 0
+	case 1:
+		return x, 1, 0
+	case 2:
+		return 0, 1, x
+	case 3:
+		return 0, x, 1
+	case 4:
+		return x, 0, 1
+	default:
+		return 1, 0, x
+	}
+}
@@ -0,0 +1,220 @@
+package effects
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// newTestService returns a service on a fake clock starting at t0.
+func newTestService() (*Service, *dmx.Service, *time.Time) {
+	dmxService := dmx.NewService(dmx.Config{Enabled: false})
+	s := NewService(dmxService)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+	return s, dmxService, &clock
+}
+
+// dimmers returns n single-channel fixtures on universe 1, channels 1..n.
+func dimmers(n int) []Fixture {
+	fixtures := make([]Fixture, n)
+	for i := range fixtures {
+		fixtures[i] = Fixture{ID: string(rune('a' + i)), Universe: 1, Intensity: []int{i + 1}}
+	}
+	return fixtures
+}
+
+func output(dmxService *dmx.Service, channels int) []int {
+	return dmxService.GetUniverse(1)[:channels]
+}
+
+func TestChase(t *testing.T) {
+	s, dmxService, clock := newTestService()
+	params := DefaultParams()
+	params.Size = 0.25 // One of four lit
+
+	if err := s.Start(Effect{ID: "chase", Type: TypeChase, Fixtures: dimmers(4), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	for step, want := range [][]int{{255, 0, 0, 0}, {0, 255, 0, 0}, {0, 0, 255, 0}, {0, 0, 0, 255}, {255, 0, 0, 0}} {
+		s.Tick(clock.Add(time.Duration(step)*250*time.Millisecond + time.Millisecond))
+		got := output(dmxService, 4)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Step %d output = %v, want %v", step, got, want)
+				break
+			}
+		}
+	}
+
+	// Reverse order starts from the last fixture
+	params.Order = OrderReverse
+	if err := s.Start(Effect{ID: "chase", Type: TypeChase, Fixtures: dimmers(4), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	s.Tick(*clock)
+	if got := output(dmxService, 4); got[3] != 255 || got[0] != 0 {
+		t.Errorf("Reverse chase output = %v, want the last fixture lit", got)
+	}
+
+	if !s.Stop("chase") || s.Stop("chase") {
+		t.Error("Stop() should report true once")
+	}
+	if got := output(dmxService, 4); got[3] != 0 {
+		t.Errorf("Output after stop = %v, want channels released", got)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	s, dmxService, clock := newTestService()
+	params := DefaultParams()
+	params.Size = 0.25
+
+	if s.Advance("chase") {
+		t.Error("Advance() should report false for an effect that is not running")
+	}
+	if err := s.Start(Effect{ID: "chase", Type: TypeChase, Fixtures: dimmers(4), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if !s.Advance("chase") {
+		t.Fatal("Advance() should report true for a running effect")
+	}
+	if got := output(dmxService, 4); got[1] != 255 || got[0] != 0 {
+		t.Errorf("Output after advance = %v, want the second fixture lit", got)
+	}
+
+	// Steps carry on with the cycle and survive an update
+	if err := s.Start(Effect{ID: "chase", Type: TypeChase, Fixtures: dimmers(4), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	s.Tick(clock.Add(250*time.Millisecond + time.Millisecond))
+	if got := output(dmxService, 4); got[2] != 255 || got[1] != 0 {
+		t.Errorf("Output a step later = %v, want the third fixture lit", got)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	s, dmxService, clock := newTestService()
+	dmxService.SetChannelValue(1, 1, 77)
+	params := DefaultParams()
+	params.Size = 1 // Spread over a full cycle: the second of two is half a cycle behind
+
+	if err := s.Start(Effect{ID: "sine", Type: TypeSine, Fixtures: dimmers(2), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	s.Tick(*clock)
+	if got := output(dmxService, 2); got[0] != 0 || got[1] != 255 {
+		t.Errorf("Sine at phase 0 = %v, want [0 255]", got)
+	}
+	s.Tick(clock.Add(500 * time.Millisecond))
+	if got := output(dmxService, 2); got[0] != 255 || got[1] != 0 {
+		t.Errorf("Sine at half cycle = %v, want [255 0]", got)
+	}
+	s.Stop("sine")
+	if got := output(dmxService, 1); got[0] != 77 {
+		t.Errorf("Stopping should reveal the live value 77, got %d", got[0])
+	}
+
+	params = DefaultParams()
+	params.Size = 0
+	params.Low = 0.2
+	params.High = 0.6
+	if err := s.Start(Effect{ID: "ramp", Type: TypeRamp, Fixtures: dimmers(2), Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	s.Tick(clock.Add(500 * time.Millisecond))
+	want := int(math.Round(0.4 * 255))
+	if got := output(dmxService, 2); got[0] != want || got[1] != want {
+		t.Errorf("Ramp at half cycle = %v, want both at %d", got, want)
+	}
+}
+
+func TestRainbow(t *testing.T) {
+	s, dmxService, clock := newTestService()
+	fixtures := []Fixture{
+		{ID: "rgb", Universe: 1, Intensity: []int{1}, Red: 2, Green: 3, Blue: 4},
+		{ID: "dimmer", Universe: 1, Intensity: []int{5}},
+	}
+	params := DefaultParams()
+	params.Rate = 0.5
+
+	if err := s.Start(Effect{ID: "rainbow", Type: TypeRainbow, Fixtures: fixtures, Params: params}); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	// A third of the way round the wheel is green
+	s.Tick(clock.Add(time.Second * 2 / 3))
+	if got := output(dmxService, 5); got[1] != 0 || got[2] != 255 || got[3] != 0 || got[0] != 0 || got[4] != 0 {
+		t.Errorf("Rainbow output = %v, want pure green and intensity untouched", got)
+	}
+
+	if err := s.Start(Effect{ID: "bad", Type: TypeRainbow, Fixtures: fixtures[1:], Params: params}); err == nil {
+		t.Error("Expected error for a rainbow without color fixtures")
+	}
+}
+
+func TestSceneEffects(t *testing.T) {
+	s, dmxService, clock := newTestService()
+	params := DefaultParams()
+	params.Size = 0
+	effect := Effect{ID: "scene-chase", Type: TypeChase, Fixtures: dimmers(1), Params: params}
+	s.SetSceneEffects(map[string][]Effect{"scene-1": {effect}})
+
+	s.Tick(*clock)
+	if s.IsRunning("scene-chase") {
+		t.Fatal("Scene effect should not run before its scene is active")
+	}
+
+	dmxService.SetActiveScene("scene-1")
+	s.Tick(*clock)
+	if !s.IsRunning("scene-chase") || output(dmxService, 1)[0] != 255 {
+		t.Error("Expected the scene effect to start with its scene")
+	}
+
+	// Detaching the effect stops it
+	s.SetSceneEffects(nil)
+	if s.IsRunning("scene-chase") {
+		t.Error("Expected the detached effect to stop")
+	}
+
+	s.SetSceneEffects(map[string][]Effect{"scene-1": {effect}})
+	if !s.IsRunning("scene-chase") {
+		t.Error("Expected the reattached effect to start with the active scene")
+	}
+	dmxService.SetActiveScene("scene-2")
+	s.Tick(*clock)
+	if s.IsRunning("scene-chase") {
+		t.Error("Expected the scene effect to stop when another scene becomes active")
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	valid := DefaultParams()
+	if err := ValidateParams(TypeSine, valid); err != nil {
+		t.Fatalf("ValidateParams() error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		typ    Type
+		modify func(p *Params)
+	}{
+		{"unknown type", "STROBE", func(p *Params) {}},
+		{"unknown order", TypeSine, func(p *Params) { p.Order = "BOUNCE" }},
+		{"zero rate", TypeSine, func(p *Params) { p.Rate = 0 }},
+		{"rate too high", TypeSine, func(p *Params) { p.Rate = MaxRate + 1 }},
+		{"size above 1", TypeSine, func(p *Params) { p.Size = 1.5 }},
+		{"NaN phase", TypeSine, func(p *Params) { p.PhaseOffset = math.NaN() }},
+		{"low above high", TypeSine, func(p *Params) { p.Low, p.High = 0.8, 0.2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := valid
+			tt.modify(&params)
+			if err := ValidateParams(tt.typ, params); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
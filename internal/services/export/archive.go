@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ArchiveExtension is the file extension of project archives.
+const ArchiveExtension = ".llx"
+
+// ArchiveContentType is the media type project archives are served as.
+const ArchiveContentType = "application/gzip"
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// WriteArchive writes the export to w as a project archive: its JSON,
+// gzip-compressed. The JSON is encoded straight into the compressor, so the
+// document is never held in memory whole.
+func (e *ExportedProject) WriteArchive(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(e); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadArchive decodes an export from a project archive, or from a plain
+// JSON export, reading r as a stream.
+func ReadArchive(r io.Reader) (*ExportedProject, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(len(gzipMagic)); string(magic) == string(gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project archive: %w", err)
+		}
+		defer zr.Close()
+		src = zr
+	}
+
+	var exported ExportedProject
+	if err := json.NewDecoder(src).Decode(&exported); err != nil {
+		return nil, fmt.Errorf("invalid project archive: %w", err)
+	}
+	return &exported, nil
+}
+
+// ArchiveFileName returns the file name to save an archive of the export
+// as, from its project's name.
+func (e *ExportedProject) ArchiveFileName() string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r < ' ', strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(e.GetProjectName()))
+	if name == "" {
+		name = "project"
+	}
+	return name + ArchiveExtension
+}
@@ -0,0 +1,142 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	exported := &ExportedProject{
+		Version: "1.0",
+		Project: &ExportProjectInfo{OriginalID: "proj-1", Name: "Archive Test"},
+		Scenes: []ExportedScene{
+			{RefID: "scene-1", Name: "Warm Wash"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exported.WriteArchive(&buf); err != nil {
+		t.Fatalf("WriteArchive failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), gzipMagic) {
+		t.Fatal("Expected the archive to be gzip-compressed")
+	}
+
+	decoded, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if decoded.GetProjectName() != "Archive Test" {
+		t.Errorf("Expected project name 'Archive Test', got '%s'", decoded.GetProjectName())
+	}
+	if len(decoded.Scenes) != 1 || decoded.Scenes[0].Name != "Warm Wash" {
+		t.Errorf("Expected scene 'Warm Wash', got %+v", decoded.Scenes)
+	}
+}
+
+func TestReadArchive_PlainJSON(t *testing.T) {
+	decoded, err := ReadArchive(strings.NewReader(`{"version":"1.0","project":{"name":"Plain"}}`))
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if decoded.GetProjectName() != "Plain" {
+		t.Errorf("Expected project name 'Plain', got '%s'", decoded.GetProjectName())
+	}
+}
+
+func TestReadArchive_Invalid(t *testing.T) {
+	var truncated bytes.Buffer
+	zw := gzip.NewWriter(&truncated)
+	_, _ = zw.Write([]byte(`{"version":"1.0","project":`))
+	_ = zw.Close()
+
+	for name, content := range map[string][]byte{
+		"not JSON":       []byte("not a project"),
+		"truncated JSON": truncated.Bytes(),
+		"corrupt gzip":   {0x1f, 0x8b, 0x00},
+	} {
+		if _, err := ReadArchive(bytes.NewReader(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestArchiveFileName(t *testing.T) {
+	tests := map[string]string{
+		"Spring Show":     "Spring Show.llx",
+		"Act 1/2: Finale": "Act 1_2_ Finale.llx",
+		"   ":             "project.llx",
+	}
+	for name, want := range tests {
+		exported := &ExportedProject{Project: &ExportProjectInfo{Name: name}}
+		if got := exported.ArchiveFileName(); got != want {
+			t.Errorf("ArchiveFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDownloads_ServeArchive(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo)
+	downloads := NewDownloads(service)
+
+	ctx := context.Background()
+	project := &models.Project{Name: testutil.UniqueProjectName("TestArchive")}
+	if err := testDB.ProjectRepo.Create(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	link, expiresAt := downloads.Issue(project.ID, DefaultExportOptions())
+	if !strings.HasPrefix(link, ArchivePath+"?token=") {
+		t.Errorf("Expected a link under %s, got %s", ArchivePath, link)
+	}
+	if expiresAt.Sub(time.Now()) > DownloadTTL {
+		t.Errorf("Expected the link to expire within %v", DownloadTTL)
+	}
+
+	rec := httptest.NewRecorder()
+	downloads.ServeArchive(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ArchiveContentType {
+		t.Errorf("Expected content type %s, got %s", ArchiveContentType, ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, project.Name+ArchiveExtension) {
+		t.Errorf("Expected the file name in %q", cd)
+	}
+	decoded, err := ReadArchive(rec.Body)
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if decoded.GetProjectName() != project.Name {
+		t.Errorf("Expected project name '%s', got '%s'", project.Name, decoded.GetProjectName())
+	}
+
+	// Links work once
+	rec = httptest.NewRecorder()
+	downloads.ServeArchive(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected a used link to give status 404, got %d", rec.Code)
+	}
+
+	// And not after they expire
+	link, _ = downloads.Issue(project.ID, DefaultExportOptions())
+	downloads.now = func() time.Time { return time.Now().Add(DownloadTTL + time.Second) }
+	rec = httptest.NewRecorder()
+	downloads.ServeArchive(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected an expired link to give status 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ArchivePath is the HTTP path project archives are downloaded from.
+const ArchivePath = "/projects/archive"
+
+// DownloadTTL is how long a download link stays valid.
+const DownloadTTL = 5 * time.Minute
+
+type download struct {
+	projectID string
+	opts      ExportOptions
+	expiresAt time.Time
+}
+
+// Downloads hands out one-time links that stream project archives. Links
+// are issued through GraphQL, so a download is authorized like any other
+// export.
+type Downloads struct {
+	service *Service
+
+	mu      sync.Mutex
+	pending map[string]download
+
+	now func() time.Time
+}
+
+// NewDownloads creates a download registry that exports with service.
+func NewDownloads(service *Service) *Downloads {
+	return &Downloads{service: service, pending: make(map[string]download), now: time.Now}
+}
+
+// Issue returns a link to download an archive of a project exported with
+// opts, and when the link expires.
+func (d *Downloads) Issue(projectID string, opts ExportOptions) (string, time.Time) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		panic(err) // crypto/rand does not fail on supported platforms
+	}
+	key := hex.EncodeToString(token)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for k, pending := range d.pending {
+		if !now.Before(pending.expiresAt) {
+			delete(d.pending, k)
+		}
+	}
+	expiresAt := now.Add(DownloadTTL)
+	d.pending[key] = download{projectID: projectID, opts: opts, expiresAt: expiresAt}
+	return ArchivePath + "?token=" + url.QueryEscape(key), expiresAt
+}
+
+// take claims the download a token names, if it is still valid.
+func (d *Downloads) take(token string) (download, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending, ok := d.pending[token]
+	if !ok {
+		return download{}, false
+	}
+	delete(d.pending, token)
+	return pending, d.now().Before(pending.expiresAt)
+}
+
+// ServeArchive streams the project archive a download link names. Each link
+// works once.
+func (d *Downloads) ServeArchive(w http.ResponseWriter, r *http.Request) {
+	pending, ok := d.take(r.URL.Query().Get("token"))
+	if !ok {
+		http.Error(w, "download link is invalid or has expired", http.StatusNotFound)
+		return
+	}
+
+	exported, _, err := d.service.ExportProjectWithOptions(r.Context(), pending.projectID, pending.opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if exported == nil {
+		http.Error(w, "project not found: "+pending.projectID, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", ArchiveContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": exported.ArchiveFileName()}))
+	w.Header().Set("Cache-Control", "no-store")
+	if err := exported.WriteArchive(w); err != nil {
+		log.Warn("failed to write project archive", "error", err)
+	}
+}
@@ -4,10 +4,11 @@ package export
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/macro"
 )
 
 // ExportedProject represents a full project export.
@@ -18,6 +19,9 @@ type ExportedProject struct {
 	Project            *ExportProjectInfo          `json:"project,omitempty"`
 	FixtureDefinitions []ExportedFixtureDefinition `json:"fixtureDefinitions"`
 	FixtureInstances   []ExportedFixtureInstance   `json:"fixtureInstances"`
+	FixtureGroups      []ExportedFixtureGroup      `json:"fixtureGroups,omitempty"`
+	Universes          []ExportedUniverse          `json:"universes,omitempty"`
+	Palettes           []ExportedPalette           `json:"palettes,omitempty"`
 	Scenes             []ExportedScene             `json:"scenes"`
 	CueLists           []ExportedCueList           `json:"cueLists"`
 	SceneBoards        []ExportedSceneBoard        `json:"sceneBoards,omitempty"`
@@ -32,11 +36,13 @@ type ExportMetadata struct {
 
 // ExportProjectInfo contains project information.
 type ExportProjectInfo struct {
-	OriginalID  string  `json:"originalId"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	CreatedAt   string  `json:"createdAt,omitempty"`
-	UpdatedAt   string  `json:"updatedAt,omitempty"`
+	OriginalID     string   `json:"originalId"`
+	Name           string   `json:"name"`
+	Description    *string  `json:"description,omitempty"`
+	DefaultFadeIn  *float64 `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut *float64 `json:"defaultFadeOut,omitempty"`
+	CreatedAt      string   `json:"createdAt,omitempty"`
+	UpdatedAt      string   `json:"updatedAt,omitempty"`
 }
 
 // ExportedFixtureDefinition represents an exported fixture definition.
@@ -76,6 +82,7 @@ type ExportedChannelDefinition struct {
 	DefaultValue int    `json:"defaultValue"`
 	FadeBehavior string `json:"fadeBehavior,omitempty"` // FADE, SNAP, SNAP_END
 	IsDiscrete   bool   `json:"isDiscrete,omitempty"`
+	Cell         *int   `json:"cell,omitempty"` // 0-indexed cell of a multi-cell fixture
 }
 
 // ExportedFixtureInstance represents an exported fixture instance.
@@ -95,6 +102,7 @@ type ExportedFixtureInstance struct {
 	LayoutX          *float64                  `json:"layoutX,omitempty"`
 	LayoutY          *float64                  `json:"layoutY,omitempty"`
 	LayoutRotation   *float64                  `json:"layoutRotation,omitempty"`
+	MaxIntensity     *float64                  `json:"maxIntensity,omitempty"`
 	InstanceChannels []ExportedInstanceChannel `json:"instanceChannels,omitempty"`
 	CreatedAt        string                    `json:"createdAt,omitempty"`
 	UpdatedAt        string                    `json:"updatedAt,omitempty"`
@@ -110,17 +118,22 @@ type ExportedInstanceChannel struct {
 	DefaultValue int    `json:"defaultValue"`
 	FadeBehavior string `json:"fadeBehavior,omitempty"` // FADE, SNAP, SNAP_END
 	IsDiscrete   bool   `json:"isDiscrete,omitempty"`
+	Cell         *int   `json:"cell,omitempty"` // 0-indexed cell of a multi-cell fixture
 }
 
 // ExportedScene represents an exported scene.
 type ExportedScene struct {
-	RefID         string                 `json:"refId"`
-	OriginalID    string                 `json:"originalId,omitempty"`
-	Name          string                 `json:"name"`
-	Description   *string                `json:"description,omitempty"`
-	FixtureValues []ExportedFixtureValue `json:"fixtureValues"`
-	CreatedAt     string                 `json:"createdAt,omitempty"`
-	UpdatedAt     string                 `json:"updatedAt,omitempty"`
+	RefID          string                 `json:"refId"`
+	OriginalID     string                 `json:"originalId,omitempty"`
+	Name           string                 `json:"name"`
+	SecondaryLabel *string                `json:"secondaryLabel,omitempty"`
+	Description    *string                `json:"description,omitempty"`
+	DefaultFadeIn  *float64               `json:"defaultFadeIn,omitempty"`
+	DefaultFadeOut *float64               `json:"defaultFadeOut,omitempty"`
+	FixtureValues  []ExportedFixtureValue `json:"fixtureValues"`
+	GroupValues    []ExportedGroupValue   `json:"groupValues,omitempty"`
+	CreatedAt      string                 `json:"createdAt,omitempty"`
+	UpdatedAt      string                 `json:"updatedAt,omitempty"`
 }
 
 // ExportedChannelValue represents a single channel value in sparse format.
@@ -135,33 +148,89 @@ type ExportedFixtureValue struct {
 	Channels      []ExportedChannelValue `json:"channels"`
 	ChannelValues []int                  `json:"channelValues,omitempty"` // Read-only: used to import legacy dense array format, not populated on export
 	SceneOrder    *int                   `json:"sceneOrder,omitempty"`
+	PaletteRefIDs []string               `json:"paletteRefIds,omitempty"`
+}
+
+// ExportedFixtureGroup represents an exported fixture group.
+type ExportedFixtureGroup struct {
+	RefID         string   `json:"refId"`
+	OriginalID    string   `json:"originalId,omitempty"`
+	Name          string   `json:"name"`
+	Description   *string  `json:"description,omitempty"`
+	FixtureRefIDs []string `json:"fixtureRefIds"`
+}
+
+// ExportedGroupValue represents a scene's exported values for a fixture group.
+type ExportedGroupValue struct {
+	GroupRefID string                     `json:"groupRefId"`
+	Channels   []ExportedChannelTypeValue `json:"channels"`
+}
+
+// ExportedUniverse represents an exported universe.
+type ExportedUniverse struct {
+	Number      int     `json:"number"`
+	Label       *string `json:"label,omitempty"`
+	Protocol    string  `json:"protocol"`
+	Destination *string `json:"destination,omitempty"`
+	Enabled     bool    `json:"enabled"`
+}
+
+// UniverseName returns a universe's label, or else its number.
+func UniverseName(universe ExportedUniverse) string {
+	if universe.Label != nil {
+		return *universe.Label
+	}
+	return fmt.Sprintf("Universe %d", universe.Number)
+}
+
+// ExportedPalette represents an exported palette.
+type ExportedPalette struct {
+	RefID      string                     `json:"refId"`
+	OriginalID string                     `json:"originalId,omitempty"`
+	Name       string                     `json:"name"`
+	Kind       string                     `json:"kind"`
+	Channels   []ExportedChannelTypeValue `json:"channels"`
+}
+
+// ExportedChannelTypeValue represents a group or palette value for a channel
+// type.
+type ExportedChannelTypeValue struct {
+	Type  string `json:"type"`
+	Value int    `json:"value"`
 }
 
 // ExportedCueList represents an exported cue list.
 type ExportedCueList struct {
-	RefID       string        `json:"refId"`
-	OriginalID  string        `json:"originalId,omitempty"`
-	Name        string        `json:"name"`
-	Description *string       `json:"description,omitempty"`
-	Loop        bool          `json:"loop"`
-	Cues        []ExportedCue `json:"cues"`
-	CreatedAt   string        `json:"createdAt,omitempty"`
-	UpdatedAt   string        `json:"updatedAt,omitempty"`
+	RefID        string        `json:"refId"`
+	OriginalID   string        `json:"originalId,omitempty"`
+	Name         string        `json:"name"`
+	Description  *string       `json:"description,omitempty"`
+	Loop         bool          `json:"loop"`
+	PlaybackMode string        `json:"playbackMode,omitempty"`
+	Tracking     bool          `json:"tracking,omitempty"`
+	Cues         []ExportedCue `json:"cues"`
+	CreatedAt    string        `json:"createdAt,omitempty"`
+	UpdatedAt    string        `json:"updatedAt,omitempty"`
 }
 
 // ExportedCue represents an exported cue.
 type ExportedCue struct {
-	OriginalID  string   `json:"originalId,omitempty"`
-	Name        string   `json:"name"`
-	CueNumber   float64  `json:"cueNumber"`
-	SceneRefID  string   `json:"sceneRefId"`
-	FadeInTime  float64  `json:"fadeInTime"`
-	FadeOutTime float64  `json:"fadeOutTime"`
-	FollowTime  *float64 `json:"followTime,omitempty"`
-	EasingType  *string  `json:"easingType,omitempty"`
-	Notes       *string  `json:"notes,omitempty"`
-	CreatedAt   string   `json:"createdAt,omitempty"`
-	UpdatedAt   string   `json:"updatedAt,omitempty"`
+	OriginalID     string   `json:"originalId,omitempty"`
+	Name           string   `json:"name"`
+	SecondaryLabel *string  `json:"secondaryLabel,omitempty"`
+	CueNumber      float64  `json:"cueNumber"`
+	SceneRefID     string   `json:"sceneRefId"`
+	FadeInTime     float64  `json:"fadeInTime"`
+	FadeOutTime    float64  `json:"fadeOutTime"`
+	FollowTime     *float64 `json:"followTime,omitempty"`
+	FollowQuantize *string  `json:"followQuantize,omitempty"`
+	EasingType     *string  `json:"easingType,omitempty"`
+	Notes          *string  `json:"notes,omitempty"`
+	Timecode       *string  `json:"timecode,omitempty"`
+	Block          bool     `json:"block,omitempty"`
+	MoveInBlack    bool     `json:"moveInBlack,omitempty"`
+	CreatedAt      string   `json:"createdAt,omitempty"`
+	UpdatedAt      string   `json:"updatedAt,omitempty"`
 }
 
 // ExportedSceneBoard represents an exported scene board.
@@ -174,6 +243,9 @@ type ExportedSceneBoard struct {
 	GridSize        *int                        `json:"gridSize,omitempty"`
 	CanvasWidth     int                         `json:"canvasWidth"`
 	CanvasHeight    int                         `json:"canvasHeight"`
+	HoldRampTime    float64                     `json:"holdRampTime,omitempty"`
+	HoldCurve       string                      `json:"holdCurve,omitempty"`
+	HoldReleaseMode string                      `json:"holdReleaseMode,omitempty"`
 	Buttons         []ExportedSceneBoardButton  `json:"buttons,omitempty"`
 	CreatedAt       string                      `json:"createdAt,omitempty"`
 	UpdatedAt       string                      `json:"updatedAt,omitempty"`
@@ -181,16 +253,25 @@ type ExportedSceneBoard struct {
 
 // ExportedSceneBoardButton represents an exported scene board button.
 type ExportedSceneBoardButton struct {
-	OriginalID string  `json:"originalId,omitempty"`
-	SceneRefID string  `json:"sceneRefId"`
-	LayoutX    int     `json:"layoutX"`
-	LayoutY    int     `json:"layoutY"`
-	Width      *int    `json:"width,omitempty"`
-	Height     *int    `json:"height,omitempty"`
-	Color      *string `json:"color,omitempty"`
-	Label      *string `json:"label,omitempty"`
-	CreatedAt  string  `json:"createdAt,omitempty"`
-	UpdatedAt  string  `json:"updatedAt,omitempty"`
+	OriginalID       string         `json:"originalId,omitempty"`
+	SceneRefID       string         `json:"sceneRefId"`
+	LayoutX          int            `json:"layoutX"`
+	LayoutY          int            `json:"layoutY"`
+	Width            *int           `json:"width,omitempty"`
+	Height           *int           `json:"height,omitempty"`
+	Color            *string        `json:"color,omitempty"`
+	Label            *string        `json:"label,omitempty"`
+	FadeInTime       *float64       `json:"fadeInTime,omitempty"`
+	FadeOutTime      *float64       `json:"fadeOutTime,omitempty"`
+	FlashMode        bool           `json:"flashMode,omitempty"`
+	FlashLevel       *float64       `json:"flashLevel,omitempty"`
+	FlashReleaseTime *float64       `json:"flashReleaseTime,omitempty"`
+	AudioTrigger     bool           `json:"audioTrigger,omitempty"`
+	AudioBand        *string        `json:"audioBand,omitempty"`
+	AudioAction      string         `json:"audioAction,omitempty"` // BUMP when empty
+	Macro            []macro.Action `json:"macro,omitempty"` // Scene and cue list IDs are ref IDs
+	CreatedAt        string         `json:"createdAt,omitempty"`
+	UpdatedAt        string         `json:"updatedAt,omitempty"`
 }
 
 // ExportStats contains statistics about an export.
@@ -201,6 +282,13 @@ type ExportStats struct {
 	CueListsCount           int
 	CuesCount               int
 	SceneBoardsCount        int
+	FixtureGroupsCount      int
+	PalettesCount           int
+
+	// Records exported, including those a selective export pulled in
+	FixtureIDs []string
+	SceneIDs   []string
+	CueListIDs []string
 }
 
 // ExportOptions contains options for project export.
@@ -209,6 +297,13 @@ type ExportOptions struct {
 	IncludeScenes      bool // Include scenes with fixture values
 	IncludeCueLists    bool // Include cue lists with cues
 	IncludeSceneBoards bool // Include scene boards with buttons (defaults to true)
+
+	// Records to export. With any of these set, the export holds just the
+	// named records and what they depend on, and the Include flags are
+	// ignored; scene boards are left out.
+	FixtureIDs []string
+	SceneIDs   []string
+	CueListIDs []string
 }
 
 // DefaultExportOptions returns the default export options (all true).
@@ -229,6 +324,9 @@ type Service struct {
 	cueListRepo    *repositories.CueListRepository
 	cueRepo        *repositories.CueRepository
 	sceneBoardRepo *repositories.SceneBoardRepository
+	groupRepo      *repositories.FixtureGroupRepository
+	paletteRepo    *repositories.PaletteRepository
+	universeRepo   *repositories.UniverseRepository
 }
 
 // NewService creates a new export service.
@@ -267,6 +365,21 @@ func NewServiceWithSceneBoards(
 	}
 }
 
+// SetFixtureGroupRepo enables exporting fixture groups with the fixtures.
+func (s *Service) SetFixtureGroupRepo(groupRepo *repositories.FixtureGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// SetPaletteRepo enables exporting palettes with the scenes.
+func (s *Service) SetPaletteRepo(paletteRepo *repositories.PaletteRepository) {
+	s.paletteRepo = paletteRepo
+}
+
+// SetUniverseRepo enables exporting universes with the fixtures.
+func (s *Service) SetUniverseRepo(universeRepo *repositories.UniverseRepository) {
+	s.universeRepo = universeRepo
+}
+
 // ExportProject exports a project to JSON.
 // Deprecated: Use ExportProjectWithOptions for cleaner API.
 func (s *Service) ExportProject(ctx context.Context, projectID string, includeFixtures, includeScenes, includeCueLists bool, includeSceneBoards ...bool) (*ExportedProject, *ExportStats, error) {
@@ -296,12 +409,25 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 		return nil, nil, nil
 	}
 
+	sel, err := s.selectRecords(ctx, projectID, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.selective() {
+		opts.IncludeFixtures = true
+		opts.IncludeScenes = true
+		opts.IncludeCueLists = true
+		opts.IncludeSceneBoards = false
+	}
+
 	exported := &ExportedProject{
 		Version: "1.0",
 		Project: &ExportProjectInfo{
-			OriginalID:  project.ID,
-			Name:        project.Name,
-			Description: project.Description,
+			OriginalID:     project.ID,
+			Name:           project.Name,
+			Description:    project.Description,
+			DefaultFadeIn:  project.DefaultFadeIn,
+			DefaultFadeOut: project.DefaultFadeOut,
 		},
 	}
 
@@ -310,10 +436,16 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 	// Export fixture definitions and instances
 	if opts.IncludeFixtures {
 		// Get fixture instances for this project
-		fixtures, err := s.fixtureRepo.FindByProjectID(ctx, projectID)
+		projectFixtures, err := s.fixtureRepo.FindByProjectID(ctx, projectID)
 		if err != nil {
 			return nil, nil, err
 		}
+		fixtures := projectFixtures[:0]
+		for _, f := range projectFixtures {
+			if selected(sel.fixtures, f.ID) {
+				fixtures = append(fixtures, f)
+			}
+		}
 
 		// Track which definitions we need
 		definitionIDs := make(map[string]bool)
@@ -358,6 +490,7 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 					DefaultValue: ch.DefaultValue,
 					FadeBehavior: ch.FadeBehavior,
 					IsDiscrete:   ch.IsDiscrete,
+					Cell:         ch.Cell,
 				})
 			}
 
@@ -406,7 +539,7 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 			var tags []string
 			if f.Tags != nil {
 				if err := json.Unmarshal([]byte(*f.Tags), &tags); err != nil {
-					log.Printf("Warning: failed to unmarshal tags for fixture %s: %v", f.ID, err)
+					log.Warn("failed to unmarshal fixture tags", "fixture", f.ID, "error", err)
 					tags = []string{} // Continue with empty tags
 				}
 			}
@@ -439,8 +572,90 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 				LayoutX:         f.LayoutX,
 				LayoutY:         f.LayoutY,
 				LayoutRotation:  f.LayoutRotation,
+				MaxIntensity:    f.MaxIntensity,
 			})
 			stats.FixtureInstancesCount++
+			stats.FixtureIDs = append(stats.FixtureIDs, f.ID)
+		}
+
+		// Export fixture groups
+		if s.groupRepo != nil {
+			groups, err := s.groupRepo.FindByProjectID(ctx, projectID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range groups {
+				if !selected(sel.groups, groups[i].ID) {
+					continue
+				}
+				fixtureIDs, err := repositories.GroupFixtureIDs(&groups[i])
+				if err != nil {
+					return nil, nil, err
+				}
+				exported.FixtureGroups = append(exported.FixtureGroups, ExportedFixtureGroup{
+					RefID:         groups[i].ID,
+					OriginalID:    groups[i].ID,
+					Name:          groups[i].Name,
+					Description:   groups[i].Description,
+					FixtureRefIDs: fixtureIDs,
+				})
+				stats.FixtureGroupsCount++
+			}
+		}
+
+		// Export universes; a selective export holds those its fixtures
+		// are patched to
+		if s.universeRepo != nil {
+			universes, err := s.universeRepo.FindByProjectID(ctx, projectID)
+			if err != nil {
+				return nil, nil, err
+			}
+			patched := make(map[int]bool)
+			for _, f := range fixtures {
+				patched[f.Universe] = true
+			}
+			for _, universe := range universes {
+				if opts.selective() && !patched[universe.Number] {
+					continue
+				}
+				exported.Universes = append(exported.Universes, ExportedUniverse{
+					Number:      universe.Number,
+					Label:       universe.Label,
+					Protocol:    universe.Protocol,
+					Destination: universe.Destination,
+					Enabled:     universe.Enabled,
+				})
+			}
+		}
+	}
+
+	// Export palettes referenced by scene values
+	if opts.IncludeScenes && s.paletteRepo != nil {
+		palettes, err := s.paletteRepo.FindByProjectID(ctx, projectID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, palette := range palettes {
+			if !selected(sel.palettes, palette.ID) {
+				continue
+			}
+			var channels []models.ChannelTypeValue
+			if err := json.Unmarshal([]byte(palette.Channels), &channels); err != nil {
+				log.Warn("failed to unmarshal palette channels", "palette", palette.ID, "error", err)
+				continue
+			}
+			exportedPalette := ExportedPalette{
+				RefID:      palette.ID,
+				OriginalID: palette.ID,
+				Name:       palette.Name,
+				Kind:       palette.Kind,
+				Channels:   make([]ExportedChannelTypeValue, len(channels)),
+			}
+			for i, ch := range channels {
+				exportedPalette.Channels[i] = ExportedChannelTypeValue{Type: ch.Type, Value: ch.Value}
+			}
+			exported.Palettes = append(exported.Palettes, exportedPalette)
+			stats.PalettesCount++
 		}
 	}
 
@@ -452,22 +667,33 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 		}
 
 		for _, scene := range scenes {
+			if !selected(sel.scenes, scene.ID) {
+				continue
+			}
 			fixtureValues, err := s.sceneRepo.GetFixtureValues(ctx, scene.ID)
 			if err != nil {
 				return nil, nil, err
 			}
 
 			exportedScene := ExportedScene{
-				RefID:       scene.ID,
-				OriginalID:  scene.ID,
-				Name:        scene.Name,
-				Description: scene.Description,
+				RefID:          scene.ID,
+				OriginalID:     scene.ID,
+				Name:           scene.Name,
+				SecondaryLabel: scene.SecondaryLabel,
+				Description:    scene.Description,
+				DefaultFadeIn:  scene.DefaultFadeIn,
+				DefaultFadeOut: scene.DefaultFadeOut,
 			}
 
 			for _, fv := range fixtureValues {
+				// Values following a group are rederived from its group value on import
+				if fv.GroupID != nil {
+					continue
+				}
+
 				var channels []models.ChannelValue
 				if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
-					log.Printf("Warning: failed to unmarshal channels for fixture %s in scene %s: %v", fv.FixtureID, scene.ID, err)
+					log.Warn("failed to unmarshal fixture channels", "fixture", fv.FixtureID, "scene", scene.ID, "error", err)
 					continue // Skip this fixture value
 				}
 
@@ -480,15 +706,42 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 					}
 				}
 
+				paletteIDs, err := repositories.FixtureValuePaletteIDs(&fv)
+				if err != nil {
+					log.Warn("failed to unmarshal fixture palettes", "fixture", fv.FixtureID, "scene", scene.ID, "error", err)
+				}
+
 				exportedScene.FixtureValues = append(exportedScene.FixtureValues, ExportedFixtureValue{
-					FixtureRefID: fv.FixtureID,
-					Channels:     exportedChannels,
-					SceneOrder:   fv.SceneOrder,
+					FixtureRefID:  fv.FixtureID,
+					Channels:      exportedChannels,
+					SceneOrder:    fv.SceneOrder,
+					PaletteRefIDs: paletteIDs,
 				})
 			}
 
+			groupValues, err := s.sceneRepo.GetGroupValues(ctx, scene.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, gv := range groupValues {
+				var channels []models.ChannelTypeValue
+				if err := json.Unmarshal([]byte(gv.Channels), &channels); err != nil {
+					log.Warn("failed to unmarshal group channels", "group", gv.GroupID, "scene", scene.ID, "error", err)
+					continue
+				}
+				exportedGroupValue := ExportedGroupValue{
+					GroupRefID: gv.GroupID,
+					Channels:   make([]ExportedChannelTypeValue, len(channels)),
+				}
+				for i, ch := range channels {
+					exportedGroupValue.Channels[i] = ExportedChannelTypeValue{Type: ch.Type, Value: ch.Value}
+				}
+				exportedScene.GroupValues = append(exportedScene.GroupValues, exportedGroupValue)
+			}
+
 			exported.Scenes = append(exported.Scenes, exportedScene)
 			stats.ScenesCount++
+			stats.SceneIDs = append(stats.SceneIDs, scene.ID)
 		}
 	}
 
@@ -500,36 +753,47 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 		}
 
 		for _, cueList := range cueLists {
+			if !selected(sel.cueLists, cueList.ID) {
+				continue
+			}
 			cues, err := s.cueListRepo.GetCues(ctx, cueList.ID)
 			if err != nil {
 				return nil, nil, err
 			}
 
 			exportedCueList := ExportedCueList{
-				RefID:       cueList.ID,
-				OriginalID:  cueList.ID,
-				Name:        cueList.Name,
-				Description: cueList.Description,
-				Loop:        cueList.Loop,
+				RefID:        cueList.ID,
+				OriginalID:   cueList.ID,
+				Name:         cueList.Name,
+				Description:  cueList.Description,
+				Loop:         cueList.Loop,
+				PlaybackMode: cueList.PlaybackMode,
+				Tracking:     cueList.Tracking,
 			}
 
 			for _, cue := range cues {
 				exportedCueList.Cues = append(exportedCueList.Cues, ExportedCue{
-					OriginalID:  cue.ID,
-					Name:        cue.Name,
-					CueNumber:   cue.CueNumber,
-					SceneRefID:  cue.SceneID,
-					FadeInTime:  cue.FadeInTime,
-					FadeOutTime: cue.FadeOutTime,
-					FollowTime:  cue.FollowTime,
-					EasingType:  cue.EasingType,
-					Notes:       cue.Notes,
+					OriginalID:     cue.ID,
+					Name:           cue.Name,
+					SecondaryLabel: cue.SecondaryLabel,
+					CueNumber:      cue.CueNumber,
+					SceneRefID:     cue.SceneID,
+					FadeInTime:     cue.FadeInTime,
+					FadeOutTime:    cue.FadeOutTime,
+					FollowTime:     cue.FollowTime,
+					FollowQuantize: cue.FollowQuantize,
+					EasingType:     cue.EasingType,
+					Notes:          cue.Notes,
+					Timecode:       cue.Timecode,
+					Block:          cue.Block,
+					MoveInBlack:    cue.MoveInBlack,
 				})
 				stats.CuesCount++
 			}
 
 			exported.CueLists = append(exported.CueLists, exportedCueList)
 			stats.CueListsCount++
+			stats.CueListIDs = append(stats.CueListIDs, cueList.ID)
 		}
 	}
 
@@ -555,18 +819,34 @@ func (s *Service) ExportProjectWithOptions(ctx context.Context, projectID string
 				GridSize:        board.GridSize,
 				CanvasWidth:     board.CanvasWidth,
 				CanvasHeight:    board.CanvasHeight,
+				HoldRampTime:    board.HoldRampTime,
+				HoldCurve:       board.HoldCurve,
+				HoldReleaseMode: board.HoldReleaseMode,
 			}
 
 			for _, btn := range buttons {
+				actions, err := macro.Parse(btn.Macro)
+				if err != nil {
+					log.Warn("failed to unmarshal scene board button macro", "button", btn.ID, "error", err)
+				}
 				exportedBoard.Buttons = append(exportedBoard.Buttons, ExportedSceneBoardButton{
-					OriginalID: btn.ID,
-					SceneRefID: btn.SceneID,
-					LayoutX:    btn.LayoutX,
-					LayoutY:    btn.LayoutY,
-					Width:      btn.Width,
-					Height:     btn.Height,
-					Color:      btn.Color,
-					Label:      btn.Label,
+					OriginalID:       btn.ID,
+					SceneRefID:       btn.SceneID,
+					LayoutX:          btn.LayoutX,
+					LayoutY:          btn.LayoutY,
+					Width:            btn.Width,
+					Height:           btn.Height,
+					Color:            btn.Color,
+					Label:            btn.Label,
+					FadeInTime:       btn.FadeInTime,
+					FadeOutTime:      btn.FadeOutTime,
+					FlashMode:        btn.FlashMode,
+					FlashLevel:       btn.FlashLevel,
+					FlashReleaseTime: btn.FlashReleaseTime,
+					AudioTrigger:     btn.AudioTrigger,
+					AudioBand:        btn.AudioBand,
+					AudioAction:      btn.AudioAction,
+					Macro:            actions,
 				})
 			}
 
@@ -3,6 +3,7 @@ package export
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
@@ -387,13 +388,15 @@ func TestExportProject_WithCueLists(t *testing.T) {
 		t.Fatalf("Failed to create cue list: %v", err)
 	}
 
+	cue1Label := "Preshow"
 	cue1 := &models.Cue{
-		Name:        "Cue 1",
-		CueNumber:   1.0,
-		CueListID:   cueList.ID,
-		SceneID:     scene.ID,
-		FadeInTime:  2.0,
-		FadeOutTime: 1.0,
+		Name:           "Cue 1",
+		SecondaryLabel: &cue1Label,
+		CueNumber:      1.0,
+		CueListID:      cueList.ID,
+		SceneID:        scene.ID,
+		FadeInTime:     2.0,
+		FadeOutTime:    1.0,
 	}
 	if err := testDB.CueRepo.Create(ctx, cue1); err != nil {
 		t.Fatalf("Failed to create cue 1: %v", err)
@@ -433,7 +436,13 @@ func TestExportProject_WithCueLists(t *testing.T) {
 		t.Error("Expected cue list loop to be true")
 	}
 	if len(exported.CueLists[0].Cues) != 2 {
-		t.Errorf("Expected 2 cues in cue list, got %d", len(exported.CueLists[0].Cues))
+		t.Fatalf("Expected 2 cues in cue list, got %d", len(exported.CueLists[0].Cues))
+	}
+	if label := exported.CueLists[0].Cues[0].SecondaryLabel; label == nil || *label != cue1Label {
+		t.Errorf("Expected cue secondary label '%s', got %v", cue1Label, label)
+	}
+	if exported.CueLists[0].Cues[1].SecondaryLabel != nil {
+		t.Errorf("Expected no secondary label on cue 2, got %v", *exported.CueLists[0].Cues[1].SecondaryLabel)
 	}
 }
 
@@ -1091,3 +1100,107 @@ func TestExportProject_SparseChannels(t *testing.T) {
 		t.Errorf("Expected channel 1: {1, 255}, got: {%d, %d}", channels[1].Offset, channels[1].Value)
 	}
 }
+
+func TestExportProject_SelectedRecords(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(
+		testDB.ProjectRepo,
+		testDB.FixtureRepo,
+		testDB.SceneRepo,
+		testDB.CueListRepo,
+		testDB.CueRepo,
+	)
+
+	ctx := context.Background()
+
+	project := &models.Project{Name: testutil.UniqueProjectName("TestSelectedRecords")}
+	if err := testDB.ProjectRepo.Create(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// Two fixtures, each of its own definition and lit by its own scene
+	var fixtures []*models.FixtureInstance
+	var scenes []*models.Scene
+	channelData, _ := json.Marshal([]models.ChannelValue{{Offset: 0, Value: 255}})
+	for i, name := range []string{"Front", "Back"} {
+		def := &models.FixtureDefinition{
+			Manufacturer: "TestMfg",
+			Model:        testutil.UniqueFixtureName(name),
+			Type:         "DIMMER",
+		}
+		channels := []models.ChannelDefinition{
+			{Name: "Intensity", Type: "INTENSITY", Offset: 0, MinValue: 0, MaxValue: 255},
+		}
+		if err := testDB.FixtureRepo.CreateDefinitionWithChannels(ctx, def, channels); err != nil {
+			t.Fatalf("Failed to create fixture definition: %v", err)
+		}
+		fixture := &models.FixtureInstance{
+			Name:         name,
+			DefinitionID: def.ID,
+			ProjectID:    project.ID,
+			Universe:     1,
+			StartChannel: i + 1,
+		}
+		if err := testDB.FixtureRepo.Create(ctx, fixture); err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+		fixtures = append(fixtures, fixture)
+
+		scene := &models.Scene{Name: name + " Look", ProjectID: project.ID}
+		values := []models.FixtureValue{{ID: cuid.New(), FixtureID: fixture.ID, Channels: string(channelData)}}
+		if err := testDB.SceneRepo.CreateWithFixtureValues(ctx, scene, values); err != nil {
+			t.Fatalf("Failed to create scene: %v", err)
+		}
+		scenes = append(scenes, scene)
+	}
+
+	cueLists := make([]*models.CueList, 2)
+	for i := range cueLists {
+		cueLists[i] = &models.CueList{Name: fmt.Sprintf("List %d", i+1), ProjectID: project.ID}
+		if err := testDB.CueListRepo.Create(ctx, cueLists[i]); err != nil {
+			t.Fatalf("Failed to create cue list: %v", err)
+		}
+		cue := &models.Cue{Name: "Cue", CueNumber: 1, CueListID: cueLists[i].ID, SceneID: scenes[i].ID}
+		if err := testDB.CueRepo.Create(ctx, cue); err != nil {
+			t.Fatalf("Failed to create cue: %v", err)
+		}
+	}
+
+	// A cue list brings its scene, the scene its fixture, and the fixture
+	// its definition
+	exported, stats, err := service.ExportProjectWithOptions(ctx, project.ID, ExportOptions{CueListIDs: []string{cueLists[0].ID}})
+	if err != nil {
+		t.Fatalf("ExportProjectWithOptions failed: %v", err)
+	}
+	if len(exported.CueLists) != 1 || exported.CueLists[0].RefID != cueLists[0].ID {
+		t.Errorf("Expected only cue list %s, got %+v", cueLists[0].ID, stats.CueListIDs)
+	}
+	if len(stats.SceneIDs) != 1 || stats.SceneIDs[0] != scenes[0].ID {
+		t.Errorf("Expected only scene %s, got %v", scenes[0].ID, stats.SceneIDs)
+	}
+	if len(stats.FixtureIDs) != 1 || stats.FixtureIDs[0] != fixtures[0].ID {
+		t.Errorf("Expected only fixture %s, got %v", fixtures[0].ID, stats.FixtureIDs)
+	}
+	if len(exported.FixtureDefinitions) != 1 || exported.FixtureDefinitions[0].RefID != fixtures[0].DefinitionID {
+		t.Errorf("Expected only the front fixture's definition, got %d definitions", len(exported.FixtureDefinitions))
+	}
+
+	// Selections add up, and a fixture can be exported on its own
+	_, stats, err = service.ExportProjectWithOptions(ctx, project.ID, ExportOptions{
+		SceneIDs:   []string{scenes[0].ID},
+		FixtureIDs: []string{fixtures[1].ID},
+	})
+	if err != nil {
+		t.Fatalf("ExportProjectWithOptions failed: %v", err)
+	}
+	if stats.CueListsCount != 0 || stats.ScenesCount != 1 || stats.FixtureInstancesCount != 2 || stats.FixtureDefinitionsCount != 2 {
+		t.Errorf("Expected 0 cue lists, 1 scene, and 2 fixtures and definitions, got %+v", stats)
+	}
+
+	// Records must belong to the project
+	if _, _, err := service.ExportProjectWithOptions(ctx, project.ID, ExportOptions{SceneIDs: []string{"missing-scene"}}); err == nil {
+		t.Error("Expected an error for a scene outside the project")
+	}
+}
@@ -1736,12 +1736,21 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
 		&models.InstanceChannel{},
 		&models.Scene{},
 		&models.FixtureValue{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
 		&models.CueList{},
 		&models.Cue{},
 		&models.SceneBoard{},
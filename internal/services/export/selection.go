@@ -0,0 +1,138 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+)
+
+// exportSelection is the records a selective export holds. A nil set
+// selects every record of its kind.
+type exportSelection struct {
+	fixtures map[string]bool
+	scenes   map[string]bool
+	cueLists map[string]bool
+	groups   map[string]bool
+	palettes map[string]bool
+}
+
+// selected reports whether a set selects a record.
+func selected(set map[string]bool, id string) bool {
+	return set == nil || set[id]
+}
+
+// selective reports whether the options name the records to export.
+func (o ExportOptions) selective() bool {
+	return o.FixtureIDs != nil || o.SceneIDs != nil || o.CueListIDs != nil
+}
+
+// selectRecords resolves a selective export: the named records and what
+// they depend on. Cue lists bring the scenes their cues play, scenes the
+// fixtures, groups and palettes they set, and groups their fixtures.
+func (s *Service) selectRecords(ctx context.Context, projectID string, opts ExportOptions) (*exportSelection, error) {
+	sel := &exportSelection{}
+	if !opts.selective() {
+		return sel, nil
+	}
+	sel.fixtures = make(map[string]bool)
+	sel.scenes = make(map[string]bool)
+	sel.cueLists = make(map[string]bool)
+	sel.groups = make(map[string]bool)
+	sel.palettes = make(map[string]bool)
+
+	cueLists, err := s.cueListRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject := make(map[string]bool, len(cueLists))
+	for _, cueList := range cueLists {
+		inProject[cueList.ID] = true
+	}
+	for _, id := range opts.CueListIDs {
+		if !inProject[id] {
+			return nil, fmt.Errorf("cue list not found in project: %s", id)
+		}
+		sel.cueLists[id] = true
+		cues, err := s.cueListRepo.GetCues(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, cue := range cues {
+			sel.scenes[cue.SceneID] = true
+		}
+	}
+
+	scenes, err := s.sceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject = make(map[string]bool, len(scenes))
+	for _, scene := range scenes {
+		inProject[scene.ID] = true
+	}
+	for _, id := range opts.SceneIDs {
+		if !inProject[id] {
+			return nil, fmt.Errorf("scene not found in project: %s", id)
+		}
+		sel.scenes[id] = true
+	}
+	for id := range sel.scenes {
+		values, err := s.sceneRepo.GetFixtureValues(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			sel.fixtures[values[i].FixtureID] = true
+			paletteIDs, err := repositories.FixtureValuePaletteIDs(&values[i])
+			if err != nil {
+				return nil, err
+			}
+			for _, paletteID := range paletteIDs {
+				sel.palettes[paletteID] = true
+			}
+		}
+		groupValues, err := s.sceneRepo.GetGroupValues(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, gv := range groupValues {
+			sel.groups[gv.GroupID] = true
+		}
+	}
+
+	if s.groupRepo != nil && len(sel.groups) > 0 {
+		groups, err := s.groupRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range groups {
+			if !sel.groups[groups[i].ID] {
+				continue
+			}
+			fixtureIDs, err := repositories.GroupFixtureIDs(&groups[i])
+			if err != nil {
+				return nil, err
+			}
+			for _, fixtureID := range fixtureIDs {
+				sel.fixtures[fixtureID] = true
+			}
+		}
+	}
+
+	fixtures, err := s.fixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject = make(map[string]bool, len(fixtures))
+	for _, fixture := range fixtures {
+		inProject[fixture.ID] = true
+	}
+	for _, id := range opts.FixtureIDs {
+		if !inProject[id] {
+			return nil, fmt.Errorf("fixture not found in project: %s", id)
+		}
+		sel.fixtures[id] = true
+	}
+	return sel, nil
+}
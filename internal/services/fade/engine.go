@@ -49,6 +49,11 @@ type activeFade struct {
 	duration   time.Duration
 	easingType EasingType
 	onComplete func()
+
+	// Manual fades take their progress from SetManualFadeProgress instead
+	// of elapsed time
+	manual   bool
+	progress float64
 }
 
 // Engine manages DMX channel fades with easing support.
@@ -66,8 +71,14 @@ type Engine struct {
 	doneChan chan struct{} // Signals when updateLoop has exited
 	running  bool
 
+	// When the update loop last ran, to detect a stalled engine
+	lastTick time.Time
+
 	// Configuration
 	updateRate time.Duration // How often to update fades (default ~16.67ms = 60Hz)
+
+	// Called on every tick after fades are applied
+	tickHandlers []func(now time.Time)
 }
 
 // NewEngine creates a new fade engine with the specified update rate.
@@ -97,6 +108,7 @@ func (e *Engine) Start() {
 	e.running = true
 	e.stopChan = make(chan struct{})  // Create new channel for this run
 	e.doneChan = make(chan struct{})  // Create new done channel
+	e.lastTick = time.Now()
 	e.mu.Unlock()
 
 	go e.updateLoop()
@@ -140,8 +152,9 @@ func (e *Engine) updateLoop() {
 		select {
 		case <-e.stopChan:
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
 			e.processFades()
+			e.runTickHandlers(now)
 		}
 	}
 }
@@ -152,54 +165,28 @@ func (e *Engine) processFades() {
 	defer e.mu.Unlock()
 
 	now := time.Now()
+	e.lastTick = now
 	var completedFades []string
 	var callbacks []func()
 	hasChanges := false
 
 	for id, fade := range e.activeFades {
-		elapsed := now.Sub(fade.startTime)
-		progress := float64(elapsed) / float64(fade.duration)
+		progress := fade.progress
+		if !fade.manual {
+			elapsed := now.Sub(fade.startTime)
+			progress = float64(elapsed) / float64(fade.duration)
+		}
 
-		if progress >= 1 {
-			// Fade complete - set final values for all channels
-			for _, ch := range fade.channels {
-				channelKey := fmt.Sprintf("%d-%d", ch.universe, ch.channel)
-				e.interpolatedValues[channelKey] = ch.endValue
-				e.dmxService.SetChannelValue(ch.universe, ch.channel, byte(ch.endValue))
-				hasChanges = true
-			}
+		e.applyFadeLocked(fade, progress)
+		if len(fade.channels) > 0 {
+			hasChanges = true
+		}
 
+		if progress >= 1 {
 			completedFades = append(completedFades, id)
 			if fade.onComplete != nil {
 				callbacks = append(callbacks, fade.onComplete)
 			}
-		} else {
-			// Interpolate values based on channel's fade behavior
-			for _, ch := range fade.channels {
-				var currentValue float64
-
-				switch ch.fadeBehavior {
-				case FadeBehaviorSnap:
-					// SNAP: Jump to target value immediately at start of transition
-					currentValue = ch.endValue
-
-				case FadeBehaviorSnapEnd:
-					// SNAP_END: Hold start value until fade completes (handled in progress >= 1 block)
-					currentValue = ch.startValue
-
-				default: // FadeBehaviorFade or empty string
-					// FADE: Interpolate smoothly between values
-					currentValue = Interpolate(ch.startValue, ch.endValue, progress, fade.easingType)
-				}
-
-				roundedValue := math.Round(currentValue)
-				clampedValue := clamp(int(roundedValue), 0, 255)
-
-				channelKey := fmt.Sprintf("%d-%d", ch.universe, ch.channel)
-				e.interpolatedValues[channelKey] = currentValue
-				e.dmxService.SetChannelValue(ch.universe, ch.channel, byte(clampedValue))
-				hasChanges = true
-			}
 		}
 	}
 
@@ -211,6 +198,7 @@ func (e *Engine) processFades() {
 	// Remove completed fades
 	for _, id := range completedFades {
 		delete(e.activeFades, id)
+		log.Debug("fade completed", "fade", id)
 	}
 
 	// Execute callbacks outside of lock
@@ -221,6 +209,61 @@ func (e *Engine) processFades() {
 	}()
 }
 
+// applyFadeLocked writes a fade's channel values at the given progress; at
+// progress 1 or beyond every channel reaches its end value.
+func (e *Engine) applyFadeLocked(fade *activeFade, progress float64) {
+	for _, ch := range fade.channels {
+		var currentValue float64
+
+		if progress >= 1 {
+			currentValue = ch.endValue
+		} else {
+			// Interpolate values based on channel's fade behavior
+			switch ch.fadeBehavior {
+			case FadeBehaviorSnap:
+				// SNAP: Jump to target value as soon as the transition starts
+				currentValue = ch.startValue
+				if progress > 0 {
+					currentValue = ch.endValue
+				}
+
+			case FadeBehaviorSnapEnd:
+				// SNAP_END: Hold start value until fade completes
+				currentValue = ch.startValue
+
+			default: // FadeBehaviorFade or empty string
+				// FADE: Interpolate smoothly between values
+				currentValue = Interpolate(ch.startValue, ch.endValue, progress, fade.easingType)
+			}
+		}
+
+		roundedValue := math.Round(currentValue)
+		clampedValue := clamp(int(roundedValue), 0, 255)
+
+		channelKey := fmt.Sprintf("%d-%d", ch.universe, ch.channel)
+		e.interpolatedValues[channelKey] = currentValue
+		e.dmxService.SetChannelValue(ch.universe, ch.channel, byte(clampedValue))
+	}
+}
+
+// OnTick registers a handler run on every engine tick, after that tick's
+// fade values have been written. Handlers run on the engine goroutine and
+// must not block.
+func (e *Engine) OnTick(handler func(now time.Time)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tickHandlers = append(e.tickHandlers, handler)
+}
+
+func (e *Engine) runTickHandlers(now time.Time) {
+	e.mu.RLock()
+	handlers := e.tickHandlers
+	e.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(now)
+	}
+}
+
 // FadeChannels starts a fade operation on multiple channels.
 func (e *Engine) FadeChannels(targets []ChannelTarget, duration time.Duration, fadeID string, easingType EasingType, onComplete func()) string {
 	e.mu.Lock()
@@ -234,6 +277,108 @@ func (e *Engine) FadeChannels(targets []ChannelTarget, duration time.Duration, f
 		easingType = EasingInOutSine
 	}
 
+	activeChannels := e.takeOverChannelsLocked(targets)
+
+	// Handle instant fades (duration <= 0) synchronously
+	// This avoids race conditions where the caller checks values before processFades runs
+	if duration <= 0 {
+		for _, target := range targets {
+			channelKey := fmt.Sprintf("%d-%d", target.Universe, target.Channel)
+			// Set the final value immediately
+			e.dmxService.SetChannelValue(target.Universe, target.Channel, byte(target.TargetValue))
+			// Update interpolated value to match
+			e.interpolatedValues[channelKey] = float64(target.TargetValue)
+		}
+		// Force immediate transmission for instant changes
+		e.dmxService.TriggerChangeDetection()
+		// Execute callback if provided
+		if onComplete != nil {
+			go onComplete()
+		}
+		return fadeID
+	}
+
+	// Build channel fades for non-instant fades
+	startTime := time.Now()
+	channels := e.channelFadesLocked(targets, activeChannels)
+
+	e.activeFades[fadeID] = &activeFade{
+		id:         fadeID,
+		channels:   channels,
+		startTime:  startTime,
+		duration:   duration,
+		easingType: easingType,
+		onComplete: onComplete,
+	}
+	log.Debug("fade started", "fade", fadeID, "channels", len(channels), "duration", duration, "easing", easingType)
+
+	// Force immediate DMX transmission and switch to high-rate mode to ensure smooth fade output
+	e.dmxService.ForceImmediateTransmission()
+
+	return fadeID
+}
+
+// StartManualFade starts a fade whose progress is set by SetManualFadeProgress
+// rather than by elapsed time, as for a crossfader. Channels start from their
+// current output values and hold them until progress moves off 0; the fade
+// completes, running onComplete, once progress reaches 1.
+func (e *Engine) StartManualFade(targets []ChannelTarget, fadeID string, easingType EasingType, onComplete func()) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if fadeID == "" {
+		fadeID = fmt.Sprintf("manual-fade-%d-%d", time.Now().UnixNano(), len(e.activeFades))
+	}
+
+	if easingType == "" {
+		easingType = EasingInOutSine
+	}
+
+	activeChannels := e.takeOverChannelsLocked(targets)
+	e.activeFades[fadeID] = &activeFade{
+		id:         fadeID,
+		channels:   e.channelFadesLocked(targets, activeChannels),
+		startTime:  time.Now(),
+		easingType: easingType,
+		onComplete: onComplete,
+		manual:     true,
+	}
+	log.Debug("manual fade started", "fade", fadeID, "channels", len(targets), "easing", easingType)
+
+	return fadeID
+}
+
+// SetManualFadeProgress moves a manual fade to progress (clamped to 0-1) and
+// writes its channel values immediately. It returns false when no manual
+// fade with the ID is active, as after it completed or other fades took over
+// all of its channels.
+func (e *Engine) SetManualFadeProgress(fadeID string, progress float64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fade, ok := e.activeFades[fadeID]
+	if !ok || !fade.manual {
+		return false
+	}
+
+	progress = math.Max(0, math.Min(1, progress))
+	fade.progress = progress
+	e.applyFadeLocked(fade, progress)
+	e.dmxService.TriggerChangeDetection()
+
+	if progress >= 1 {
+		delete(e.activeFades, fadeID)
+		if fade.onComplete != nil {
+			go fade.onComplete()
+		}
+	}
+	return true
+}
+
+// takeOverChannelsLocked removes the targeted channels from existing fades,
+// so fades never fight over a channel, and returns the channels still being
+// faded by other fades.
+func (e *Engine) takeOverChannelsLocked(targets []ChannelTarget) map[string]bool {
 	// Build a set of channels that this new fade will control
 	newChannelSet := make(map[string]bool)
 	for _, target := range targets {
@@ -280,28 +425,12 @@ func (e *Engine) FadeChannels(targets []ChannelTarget, duration time.Duration, f
 			activeChannels[channelKey] = true
 		}
 	}
+	return activeChannels
+}
 
-	// Handle instant fades (duration <= 0) synchronously
-	// This avoids race conditions where the caller checks values before processFades runs
-	if duration <= 0 {
-		for _, target := range targets {
-			channelKey := fmt.Sprintf("%d-%d", target.Universe, target.Channel)
-			// Set the final value immediately
-			e.dmxService.SetChannelValue(target.Universe, target.Channel, byte(target.TargetValue))
-			// Update interpolated value to match
-			e.interpolatedValues[channelKey] = float64(target.TargetValue)
-		}
-		// Force immediate transmission for instant changes
-		e.dmxService.TriggerChangeDetection()
-		// Execute callback if provided
-		if onComplete != nil {
-			go onComplete()
-		}
-		return fadeID
-	}
-
-	// Build channel fades for non-instant fades
-	startTime := time.Now()
+// channelFadesLocked builds the channel fades for targets, starting each
+// channel from its current output value.
+func (e *Engine) channelFadesLocked(targets []ChannelTarget, activeChannels map[string]bool) []channelFade {
 	var channels []channelFade
 
 	for _, target := range targets {
@@ -338,20 +467,7 @@ func (e *Engine) FadeChannels(targets []ChannelTarget, duration time.Duration, f
 			fadeBehavior: behavior,
 		})
 	}
-
-	e.activeFades[fadeID] = &activeFade{
-		id:         fadeID,
-		channels:   channels,
-		startTime:  startTime,
-		duration:   duration,
-		easingType: easingType,
-		onComplete: onComplete,
-	}
-
-	// Force immediate DMX transmission and switch to high-rate mode to ensure smooth fade output
-	e.dmxService.ForceImmediateTransmission()
-
-	return fadeID
+	return channels
 }
 
 // FadeToScene fades to a scene's channel values.
@@ -411,6 +527,7 @@ func (e *Engine) CancelFade(fadeID string) {
 			delete(e.interpolatedValues, channelKey)
 		}
 		delete(e.activeFades, fadeID)
+		log.Debug("fade cancelled", "fade", fadeID)
 	}
 }
 
@@ -419,6 +536,9 @@ func (e *Engine) CancelAllFades() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if len(e.activeFades) > 0 {
+		log.Debug("all fades cancelled", "fades", len(e.activeFades))
+	}
 	e.interpolatedValues = make(map[string]float64)
 	e.activeFades = make(map[string]*activeFade)
 }
@@ -430,6 +550,14 @@ func (e *Engine) IsRunning() bool {
 	return e.running
 }
 
+// LastTick returns when the update loop last ran, or when the engine was
+// started if it has not run since.
+func (e *Engine) LastTick() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.lastTick
+}
+
 // ActiveFadeCount returns the number of active fades.
 func (e *Engine) ActiveFadeCount() int {
 	e.mu.RLock()
@@ -437,6 +565,33 @@ func (e *Engine) ActiveFadeCount() int {
 	return len(e.activeFades)
 }
 
+// FadeProgress returns the progress (0-1) of an active fade, and false when
+// no fade with the ID is running.
+func (e *Engine) FadeProgress(fadeID string) (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	fade, ok := e.activeFades[fadeID]
+	if !ok {
+		return 0, false
+	}
+	if fade.manual {
+		return fade.progress, true
+	}
+	progress := float64(time.Since(fade.startTime)) / float64(fade.duration)
+	return math.Max(0, math.Min(1, progress)), true
+}
+
+// UpdateRateSettingKey is the setting that stores the update rate,
+// overriding the configured one.
+const UpdateRateSettingKey = "fade_update_rate_hz"
+
+// Update rates that may be saved.
+const (
+	MinUpdateRateHz = 1
+	MaxUpdateRateHz = 240
+)
+
 // SetUpdateRate updates the fade engine's update rate at runtime.
 // The engine must be restarted for the change to take effect.
 func (e *Engine) SetUpdateRate(hz int) {
@@ -457,6 +612,7 @@ func (e *Engine) SetUpdateRate(hz int) {
 	e.mu.Lock()
 	e.updateRate = time.Second / time.Duration(hz)
 	e.mu.Unlock()
+	log.Info("fade engine update rate set", "rateHz", hz)
 
 	// Restart the engine if it was running
 	if wasRunning {
@@ -883,3 +883,114 @@ func TestGetUpdateRateHz(t *testing.T) {
 		})
 	}
 }
+
+func TestOnTick_RunsAfterFades(t *testing.T) {
+	engine, dmxService := createTestEngine()
+
+	ticks := make(chan byte, 1)
+	engine.OnTick(func(now time.Time) {
+		select {
+		case ticks <- dmxService.GetChannelValue(1, 1):
+		default:
+		}
+	})
+	engine.FadeChannels([]ChannelTarget{{Universe: 1, Channel: 1, TargetValue: 200}}, time.Millisecond, "tick-test", EasingLinear, nil)
+	time.Sleep(5 * time.Millisecond)
+
+	engine.Start()
+	defer engine.Stop()
+
+	select {
+	case value := <-ticks:
+		if value != 200 {
+			t.Errorf("Tick handler saw channel value %d, want the completed fade's 200", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for a tick")
+	}
+}
+
+func TestManualFade_FollowsProgress(t *testing.T) {
+	engine, dmxService := createTestEngine()
+	engine.Start()
+	defer engine.Stop()
+
+	dmxService.SetChannelValue(1, 1, 0)
+	dmxService.SetChannelValue(1, 2, 0)
+	dmxService.SetChannelValue(1, 3, 100)
+
+	completed := make(chan struct{})
+	targets := []ChannelTarget{
+		{Universe: 1, Channel: 1, TargetValue: 200, FadeBehavior: FadeBehaviorFade},
+		{Universe: 1, Channel: 2, TargetValue: 100, FadeBehavior: FadeBehaviorSnap},
+		{Universe: 1, Channel: 3, TargetValue: 200, FadeBehavior: FadeBehaviorSnapEnd},
+	}
+	engine.StartManualFade(targets, "xfade", EasingLinear, func() { close(completed) })
+
+	// Time alone must not move a manual fade
+	time.Sleep(50 * time.Millisecond)
+	if got := dmxService.GetChannelValue(1, 1); got != 0 {
+		t.Errorf("FADE channel at progress 0 = %d, want 0", got)
+	}
+	if got := dmxService.GetChannelValue(1, 2); got != 0 {
+		t.Errorf("SNAP channel at progress 0 = %d, want 0", got)
+	}
+
+	if !engine.SetManualFadeProgress("xfade", 0.25) {
+		t.Fatal("SetManualFadeProgress() = false for an active manual fade")
+	}
+	if got := dmxService.GetChannelValue(1, 1); got != 50 {
+		t.Errorf("FADE channel at 25%% = %d, want 50", got)
+	}
+	if got := dmxService.GetChannelValue(1, 2); got != 100 {
+		t.Errorf("SNAP channel at 25%% = %d, want 100", got)
+	}
+	if got := dmxService.GetChannelValue(1, 3); got != 100 {
+		t.Errorf("SNAP_END channel at 25%% = %d, want 100", got)
+	}
+
+	// Moving back returns toward the start values
+	engine.SetManualFadeProgress("xfade", 0)
+	if got := dmxService.GetChannelValue(1, 1); got != 0 {
+		t.Errorf("FADE channel back at 0 = %d, want 0", got)
+	}
+	if got := dmxService.GetChannelValue(1, 2); got != 0 {
+		t.Errorf("SNAP channel back at 0 = %d, want 0", got)
+	}
+
+	engine.SetManualFadeProgress("xfade", 1.5)
+	if got := dmxService.GetChannelValue(1, 3); got != 200 {
+		t.Errorf("SNAP_END channel at completion = %d, want 200", got)
+	}
+	select {
+	case <-completed:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the manual fade to complete")
+	}
+	if engine.SetManualFadeProgress("xfade", 0.5) {
+		t.Error("SetManualFadeProgress() = true after the fade completed")
+	}
+	if count := engine.ActiveFadeCount(); count != 0 {
+		t.Errorf("ActiveFadeCount() = %d after completion, want 0", count)
+	}
+}
+
+func TestFadeProgress(t *testing.T) {
+	engine, _ := createTestEngine()
+
+	if _, ok := engine.FadeProgress("missing"); ok {
+		t.Error("FadeProgress() reported a fade that does not exist")
+	}
+
+	targets := []ChannelTarget{{Universe: 1, Channel: 1, TargetValue: 255}}
+	engine.FadeChannels(targets, time.Hour, "timed", EasingLinear, nil)
+	if progress, ok := engine.FadeProgress("timed"); !ok || progress < 0 || progress > 0.01 {
+		t.Errorf("FadeProgress() of a new fade = %v, %v", progress, ok)
+	}
+
+	engine.StartManualFade([]ChannelTarget{{Universe: 1, Channel: 2, TargetValue: 255}}, "manual", EasingLinear, nil)
+	engine.SetManualFadeProgress("manual", 0.4)
+	if progress, ok := engine.FadeProgress("manual"); !ok || progress != 0.4 {
+		t.Errorf("FadeProgress() of a manual fade = %v, %v, want 0.4", progress, ok)
+	}
+}
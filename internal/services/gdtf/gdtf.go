@@ -0,0 +1,521 @@
+package gdtf
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxDescriptionSize caps how much of description.xml is read.
+const maxDescriptionSize = 16 << 20
+
+// Definition is a GDTF fixture type mapped onto fixture definition records.
+type Definition struct {
+	Manufacturer string
+	Model        string
+	Type         string
+	Channels     []Channel
+	Modes        []Mode
+}
+
+// Channel is a channel definition. Each byte of a multi-byte GDTF channel
+// is a channel of its own, the coarse byte first.
+type Channel struct {
+	Name         string
+	Type         string
+	Offset       int
+	DefaultValue int
+	FadeBehavior string
+	IsDiscrete   bool
+	Cell         *int // Cell of a multi-cell fixture; nil for master channels
+	Attribute    string
+	Color        *string // Hex color of a color mixing emitter
+	Capabilities []Capability
+}
+
+// Capability is a DMX range of a channel, in coarse (8-bit) values.
+type Capability struct {
+	Name     string
+	MinValue int
+	MaxValue int
+	Color    *string // Hex color of a color wheel slot
+	Image    *string // Media file of a gobo wheel slot
+}
+
+// Mode is a DMX mode. Channels holds channel names by offset, with "" at
+// offsets the mode leaves unused.
+type Mode struct {
+	Name     string
+	Channels []string
+}
+
+// Parse reads a .gdtf file, a zip archive, and maps its description.xml.
+func Parse(data []byte) (*Definition, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GDTF file: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if file.Name != "description.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open description.xml: %w", err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxDescriptionSize))
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read description.xml: %w", err)
+		}
+		return ParseDescription(content)
+	}
+	return nil, fmt.Errorf("invalid GDTF file: no description.xml")
+}
+
+// ParseDescription maps a GDTF description.xml.
+func ParseDescription(content []byte) (*Definition, error) {
+	var description Description
+	if err := xml.Unmarshal(content, &description); err != nil {
+		return nil, fmt.Errorf("invalid description.xml: %w", err)
+	}
+
+	fixtureType := &description.FixtureType
+	if fixtureType.Name == "" {
+		return nil, fmt.Errorf("GDTF fixture type must have a Name")
+	}
+	if fixtureType.Manufacturer == "" {
+		return nil, fmt.Errorf("GDTF fixture type must have a Manufacturer")
+	}
+
+	definition := convert(fixtureType)
+	if len(definition.Modes) == 0 || len(definition.Channels) == 0 {
+		return nil, fmt.Errorf("GDTF fixture type must have a DMX mode with at least one channel")
+	}
+	return definition, nil
+}
+
+// convert maps a fixture type. Channels are shared across modes by name: the
+// attribute, prefixed with the geometry when the attribute is on several
+// geometries (as with multi-cell fixtures). Those geometries are the
+// fixture's cells, numbered in order of appearance. Only the first DMX break
+// of each mode is mapped.
+func convert(fixtureType *FixtureType) *Definition {
+	attributes := make(map[string]Attribute, len(fixtureType.Attributes))
+	for _, attribute := range fixtureType.Attributes {
+		attributes[attribute.Name] = attribute
+	}
+	wheels := make(map[string]Wheel, len(fixtureType.Wheels))
+	for _, wheel := range fixtureType.Wheels {
+		wheels[wheel.Name] = wheel
+	}
+
+	geometries := make(map[string]map[string]bool) // Attribute -> geometries
+	for _, mode := range fixtureType.DMXModes {
+		for _, ch := range mode.Channels {
+			if !mapped(ch) {
+				continue
+			}
+			attribute := channelAttribute(ch)
+			if geometries[attribute] == nil {
+				geometries[attribute] = make(map[string]bool)
+			}
+			geometries[attribute][ch.Geometry] = true
+		}
+	}
+	cells := make(map[string]int) // Geometry -> cell
+	for _, mode := range fixtureType.DMXModes {
+		for _, ch := range mode.Channels {
+			if !mapped(ch) || len(geometries[channelAttribute(ch)]) < 2 {
+				continue
+			}
+			if _, ok := cells[ch.Geometry]; !ok {
+				cells[ch.Geometry] = len(cells)
+			}
+		}
+	}
+
+	definition := &Definition{
+		Manufacturer: fixtureType.Manufacturer,
+		Model:        fixtureType.Name,
+	}
+	indexes := make(map[string]int) // Channel name -> index
+	for _, mode := range fixtureType.DMXModes {
+		var names []string
+		for _, ch := range mode.Channels {
+			if !mapped(ch) {
+				continue
+			}
+			attribute := channelAttribute(ch)
+			name := attribute
+			var cell *int
+			if len(geometries[attribute]) > 1 {
+				name = ch.Geometry + " " + attribute
+				index := cells[ch.Geometry]
+				cell = &index
+			}
+
+			offsets := parseOffsets(ch.Offset)
+			for i, offset := range offsets {
+				byteName := name + fineSuffix(i)
+				if _, ok := indexes[byteName]; !ok {
+					channel := channelByte(ch, attribute, i, len(offsets), attributes, wheels)
+					channel.Name = byteName
+					channel.Cell = cell
+					channel.Offset = len(definition.Channels)
+					indexes[byteName] = channel.Offset
+					definition.Channels = append(definition.Channels, channel)
+				}
+				for len(names) < offset {
+					names = append(names, "")
+				}
+				names[offset-1] = byteName
+			}
+		}
+		if len(names) > 0 {
+			definition.Modes = append(definition.Modes, Mode{Name: mode.Name, Channels: names})
+		}
+	}
+
+	definition.Type = fixtureTypeOf(definition.Channels)
+	return definition
+}
+
+// mapped reports whether a DMX channel occupies DMX addresses in the first
+// break.
+func mapped(ch DMXChannel) bool {
+	if ch.DMXBreak != "" && ch.DMXBreak != "1" {
+		return false
+	}
+	return len(ch.LogicalChannels) > 0 && len(parseOffsets(ch.Offset)) > 0
+}
+
+// channelAttribute returns the attribute a DMX channel controls.
+func channelAttribute(ch DMXChannel) string {
+	logical := ch.LogicalChannels[0]
+	if logical.Attribute != "" {
+		return logical.Attribute
+	}
+	for _, function := range logical.Functions {
+		if function.Attribute != "" {
+			return function.Attribute
+		}
+	}
+	return "NoFeature"
+}
+
+// channelByte maps one byte of a DMX channel that is width bytes wide.
+func channelByte(ch DMXChannel, attribute string, index, width int, attributes map[string]Attribute, wheels map[string]Wheel) Channel {
+	logical := ch.LogicalChannels[0]
+	channelType := mapAttribute(attribute)
+
+	defaultValue := ch.Default
+	if defaultValue == "" && len(logical.Functions) > 0 {
+		function := logical.Functions[0]
+		for _, f := range logical.Functions {
+			if ch.InitialFunction != "" && strings.HasSuffix(ch.InitialFunction, "."+f.Name) {
+				function = f
+				break
+			}
+		}
+		defaultValue = function.Default
+	}
+	full := parseDMXValue(defaultValue, width)
+
+	channel := Channel{
+		Type:         channelType,
+		DefaultValue: (full >> (8 * (width - 1 - index))) & 0xff,
+		Attribute:    attribute,
+	}
+	if index > 0 {
+		// Fine bytes are always continuous
+		channel.FadeBehavior = fadeBehavior(channelType, false, logical.Snap)
+		return channel
+	}
+
+	channel.Capabilities = capabilities(logical, width, wheels)
+	channel.IsDiscrete = len(channel.Capabilities) > 1
+	channel.FadeBehavior = fadeBehavior(channelType, channel.IsDiscrete, logical.Snap)
+	if _, ok := colorAttributes[attribute]; ok {
+		channel.Color = cieToHex(attributes[attribute].Color)
+	}
+	return channel
+}
+
+// capabilities maps the channel functions and channel sets of a logical
+// channel to coarse DMX ranges. Each runs up to the next one's start.
+func capabilities(logical LogicalChannel, width int, wheels map[string]Wheel) []Capability {
+	var caps []Capability
+	for i, function := range logical.Functions {
+		from := coarse(parseDMXValue(function.DMXFrom, width), width)
+		to := 255
+		if i+1 < len(logical.Functions) {
+			to = coarse(parseDMXValue(logical.Functions[i+1].DMXFrom, width), width) - 1
+		}
+		if to < from {
+			continue
+		}
+
+		name := function.Name
+		if name == "" {
+			name = function.Attribute
+		}
+		var sets []Capability
+		for j, set := range function.Sets {
+			setFrom := coarse(parseDMXValue(set.DMXFrom, width), width)
+			setTo := to
+			if j+1 < len(function.Sets) {
+				setTo = coarse(parseDMXValue(function.Sets[j+1].DMXFrom, width), width) - 1
+			}
+			if setFrom < from || setTo > to || setTo < setFrom {
+				continue
+			}
+
+			capability := Capability{Name: set.Name, MinValue: setFrom, MaxValue: setTo}
+			if slot, ok := wheelSlot(wheels, function.Wheel, set.WheelSlotIndex); ok {
+				if capability.Name == "" {
+					capability.Name = slot.Name
+				}
+				capability.Color = cieToHex(slot.Color)
+				if slot.MediaFileName != "" {
+					image := slot.MediaFileName
+					capability.Image = &image
+				}
+			}
+			if capability.Name == "" {
+				continue
+			}
+			sets = append(sets, capability)
+		}
+
+		if len(sets) == 0 {
+			caps = append(caps, Capability{Name: name, MinValue: from, MaxValue: to})
+			continue
+		}
+		caps = append(caps, sets...)
+	}
+	return caps
+}
+
+// wheelSlot returns the slot at a 1-based index of a wheel.
+func wheelSlot(wheels map[string]Wheel, wheelName, slotIndex string) (WheelSlot, bool) {
+	if wheelName == "" || slotIndex == "" {
+		return WheelSlot{}, false
+	}
+	index, err := strconv.Atoi(slotIndex)
+	slots := wheels[wheelName].Slots
+	if err != nil || index < 1 || index > len(slots) {
+		return WheelSlot{}, false
+	}
+	return slots[index-1], true
+}
+
+// parseOffsets parses a DMX channel's 1-based offsets, coarse first. Virtual
+// channels ("None") have none.
+func parseOffsets(value string) []int {
+	if value == "" || value == "None" {
+		return nil
+	}
+	var offsets []int
+	for _, part := range strings.Split(value, ",") {
+		offset, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || offset < 1 || offset > 512 {
+			return nil
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+// parseDMXValue parses a GDTF DMX value, "value/bytes" or a plain value at
+// the channel's resolution, as a value width bytes wide.
+func parseDMXValue(value string, width int) int {
+	if value == "" {
+		return 0
+	}
+	resolution := width
+	if parts := strings.SplitN(value, "/", 2); len(parts) == 2 {
+		value = parts[0]
+		if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 {
+			resolution = n
+		}
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 {
+		return 0
+	}
+
+	if resolution < width {
+		v <<= 8 * (width - resolution)
+	} else if resolution > width {
+		v >>= 8 * (resolution - width)
+	}
+	if limit := 1<<(8*width) - 1; v > limit {
+		v = limit
+	}
+	return v
+}
+
+// coarse returns the coarse byte of a value width bytes wide.
+func coarse(value, width int) int {
+	return value >> (8 * (width - 1))
+}
+
+// fineSuffix names the bytes after the coarse byte of a channel.
+func fineSuffix(index int) string {
+	switch index {
+	case 0:
+		return ""
+	case 1:
+		return " fine"
+	default:
+		return fmt.Sprintf(" fine %d", index)
+	}
+}
+
+// colorAttributes maps GDTF color mixing attributes to channel types.
+var colorAttributes = map[string]string{
+	"ColorAdd_R":  "RED",
+	"ColorAdd_G":  "GREEN",
+	"ColorAdd_B":  "BLUE",
+	"ColorAdd_W":  "WHITE",
+	"ColorAdd_WW": "WARM_WHITE",
+	"ColorAdd_CW": "COLD_WHITE",
+	"ColorAdd_RY": "AMBER",
+	"ColorAdd_GY": "LIME",
+	"ColorAdd_BM": "INDIGO",
+	"ColorAdd_UV": "UV",
+	"ColorAdd_C":  "CYAN",
+	"ColorAdd_M":  "MAGENTA",
+	"ColorAdd_Y":  "YELLOW",
+	"ColorSub_C":  "CYAN",
+	"ColorSub_M":  "MAGENTA",
+	"ColorSub_Y":  "YELLOW",
+}
+
+// mapAttribute maps a GDTF attribute to our ChannelType enum. Numbered
+// attributes (Gobo1, Color2, Shutter1) map by their base name.
+func mapAttribute(attribute string) string {
+	if channelType, ok := colorAttributes[attribute]; ok {
+		return channelType
+	}
+
+	switch strings.TrimRight(attribute, "0123456789") {
+	case "Dimmer":
+		return "INTENSITY"
+	case "Pan":
+		return "PAN"
+	case "Tilt":
+		return "TILT"
+	case "Zoom":
+		return "ZOOM"
+	case "Focus":
+		return "FOCUS"
+	case "Iris":
+		return "IRIS"
+	case "Gobo":
+		return "GOBO"
+	case "Color":
+		return "COLOR_WHEEL"
+	case "Shutter":
+		return "STROBE"
+	case "Control":
+		return "MACRO"
+	}
+
+	switch {
+	case strings.HasPrefix(attribute, "Shutter") && strings.HasSuffix(attribute, "Strobe"):
+		return "STROBE"
+	case strings.HasPrefix(attribute, "Gobo"), strings.HasPrefix(attribute, "Prism"),
+		strings.HasPrefix(attribute, "Effects"), strings.HasPrefix(attribute, "Frost"):
+		return "EFFECT"
+	}
+	return "OTHER"
+}
+
+// fadeBehavior determines the fade behavior of a channel: discrete channels,
+// channels GDTF marks to snap, and wheel, macro, and strobe channels snap.
+func fadeBehavior(channelType string, isDiscrete bool, snap string) string {
+	if isDiscrete || snap == "Yes" || snap == "On" {
+		return "SNAP"
+	}
+	switch channelType {
+	case "GOBO", "COLOR_WHEEL", "MACRO", "STROBE":
+		return "SNAP"
+	}
+	return "FADE"
+}
+
+// fixtureTypeOf infers our FixtureType enum from a fixture's channels.
+func fixtureTypeOf(channels []Channel) string {
+	hasColor, onlyIntensity := false, true
+	for _, ch := range channels {
+		switch ch.Type {
+		case "PAN", "TILT":
+			return "MOVING_HEAD"
+		case "INTENSITY":
+			continue
+		}
+		onlyIntensity = false
+		if _, ok := colorAttributes[ch.Attribute]; ok {
+			hasColor = true
+		}
+	}
+
+	switch {
+	case hasColor:
+		return "LED_PAR"
+	case onlyIntensity:
+		return "DIMMER"
+	}
+	return "OTHER"
+}
+
+// cieToHex converts a GDTF CIE xyY color ("x,y,Y") to a hex RGB color at
+// full brightness.
+func cieToHex(value string) *string {
+	parts := strings.Split(value, ",")
+	if len(parts) < 2 {
+		return nil
+	}
+	x, errX := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errX != nil || errY != nil || y <= 0 {
+		return nil
+	}
+
+	// CIE XYZ at unit luminance, then linear sRGB (D65)
+	cx, cy, cz := x/y, 1.0, (1-x-y)/y
+	rgb := [3]float64{
+		3.2406*cx - 1.5372*cy - 0.4986*cz,
+		-0.9689*cx + 1.8758*cy + 0.0415*cz,
+		0.0557*cx - 0.2040*cy + 1.0570*cz,
+	}
+	peak := 0.0
+	for i := range rgb {
+		rgb[i] = math.Max(rgb[i], 0)
+		peak = math.Max(peak, rgb[i])
+	}
+	if peak == 0 {
+		return nil
+	}
+
+	var out [3]int
+	for i, c := range rgb {
+		c /= peak
+		if c <= 0.0031308 {
+			c *= 12.92
+		} else {
+			c = 1.055*math.Pow(c, 1/2.4) - 0.055
+		}
+		out[i] = int(math.Round(c * 255))
+	}
+	hex := fmt.Sprintf("#%02X%02X%02X", out[0], out[1], out[2])
+	return &hex
+}
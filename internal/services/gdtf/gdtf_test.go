@@ -0,0 +1,241 @@
+package gdtf
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// testDescription is a moving head with a 16-bit dimmer, color mixing, and a
+// color wheel, in two modes.
+const testDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<GDTF DataVersion="1.1">
+  <FixtureType Name="Test Spot" ShortName="TSpot" Manufacturer="Test Co">
+    <AttributeDefinitions>
+      <Attributes>
+        <Attribute Name="Dimmer" Pretty="Dim"/>
+        <Attribute Name="Pan" Pretty="P"/>
+        <Attribute Name="ColorAdd_R" Pretty="R" Color="0.6400,0.3300,21.26"/>
+        <Attribute Name="Color1" Pretty="Color1"/>
+      </Attributes>
+    </AttributeDefinitions>
+    <Wheels>
+      <Wheel Name="ColorWheel">
+        <Slot Name="Open" Color="0.3127,0.3290,100.0"/>
+        <Slot Name="Deep Blue" Color="0.1500,0.0600,7.22"/>
+      </Wheel>
+    </Wheels>
+    <DMXModes>
+      <DMXMode Name="Standard" Geometry="Body">
+        <DMXChannels>
+          <DMXChannel DMXBreak="1" Offset="1,2" Geometry="Beam">
+            <LogicalChannel Attribute="Dimmer">
+              <ChannelFunction Name="Dimmer" Attribute="Dimmer" DMXFrom="0/1" Default="32768/2"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="3" Geometry="Beam">
+            <LogicalChannel Attribute="ColorAdd_R">
+              <ChannelFunction Name="Red" Attribute="ColorAdd_R" DMXFrom="0/1"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="4" Geometry="Beam">
+            <LogicalChannel Attribute="Color1">
+              <ChannelFunction Name="Color Wheel" Attribute="Color1" DMXFrom="0/1" Wheel="ColorWheel">
+                <ChannelSet Name="" DMXFrom="0/1" WheelSlotIndex="1"/>
+                <ChannelSet Name="Blue" DMXFrom="128/1" WheelSlotIndex="2"/>
+              </ChannelFunction>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="6" Geometry="Yoke">
+            <LogicalChannel Attribute="Pan">
+              <ChannelFunction Name="Pan" Attribute="Pan" DMXFrom="0/1" Default="128/1"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="None" Geometry="Beam">
+            <LogicalChannel Attribute="Dimmer">
+              <ChannelFunction Name="Virtual" Attribute="Dimmer" DMXFrom="0/1"/>
+            </LogicalChannel>
+          </DMXChannel>
+        </DMXChannels>
+      </DMXMode>
+      <DMXMode Name="Basic" Geometry="Body">
+        <DMXChannels>
+          <DMXChannel DMXBreak="1" Offset="1" Geometry="Beam">
+            <LogicalChannel Attribute="Dimmer">
+              <ChannelFunction Name="Dimmer" Attribute="Dimmer" DMXFrom="0/1"/>
+            </LogicalChannel>
+          </DMXChannel>
+        </DMXChannels>
+      </DMXMode>
+    </DMXModes>
+  </FixtureType>
+</GDTF>`
+
+// gdtfFile packs a description.xml into a .gdtf archive.
+func gdtfFile(t *testing.T, description string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("description.xml")
+	if err != nil {
+		t.Fatalf("Failed to create archive entry: %v", err)
+	}
+	if _, err := f.Write([]byte(description)); err != nil {
+		t.Fatalf("Failed to write archive entry: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	definition, err := Parse(gdtfFile(t, testDescription))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if definition.Manufacturer != "Test Co" || definition.Model != "Test Spot" {
+		t.Errorf("Fixture = %s %s, want Test Co Test Spot", definition.Manufacturer, definition.Model)
+	}
+	if definition.Type != "MOVING_HEAD" {
+		t.Errorf("Type = %s, want MOVING_HEAD", definition.Type)
+	}
+
+	channels := make(map[string]Channel)
+	for _, ch := range definition.Channels {
+		channels[ch.Name] = ch
+	}
+	if len(channels) != 5 {
+		t.Fatalf("Channels = %+v, want Dimmer, Dimmer fine, ColorAdd_R, Color1, and Pan", definition.Channels)
+	}
+
+	dimmer := channels["Dimmer"]
+	if dimmer.Type != "INTENSITY" || dimmer.DefaultValue != 128 || dimmer.FadeBehavior != "FADE" || dimmer.IsDiscrete {
+		t.Errorf("Dimmer = %+v, want a fading INTENSITY channel defaulting to 128", dimmer)
+	}
+	if fine := channels["Dimmer fine"]; fine.Type != "INTENSITY" || fine.DefaultValue != 0 {
+		t.Errorf("Dimmer fine = %+v, want INTENSITY defaulting to 0", fine)
+	}
+	if red := channels["ColorAdd_R"]; red.Type != "RED" || red.Color == nil || *red.Color != "#FF0000" {
+		t.Errorf("ColorAdd_R = %+v, want RED with color #FF0000", red)
+	}
+	if pan := channels["Pan"]; pan.Type != "PAN" || pan.DefaultValue != 128 {
+		t.Errorf("Pan = %+v, want PAN defaulting to 128", pan)
+	}
+
+	wheel := channels["Color1"]
+	if wheel.Type != "COLOR_WHEEL" || !wheel.IsDiscrete || wheel.FadeBehavior != "SNAP" {
+		t.Errorf("Color1 = %+v, want a discrete snapping COLOR_WHEEL channel", wheel)
+	}
+	if len(wheel.Capabilities) != 2 {
+		t.Fatalf("Color1 capabilities = %+v, want 2", wheel.Capabilities)
+	}
+	open, blue := wheel.Capabilities[0], wheel.Capabilities[1]
+	if open.Name != "Open" || open.MinValue != 0 || open.MaxValue != 127 || open.Color == nil || *open.Color != "#FFFFFF" {
+		t.Errorf("First slot = %+v, want Open 0-127 in white", open)
+	}
+	if blue.Name != "Blue" || blue.MinValue != 128 || blue.MaxValue != 255 || blue.Color == nil {
+		t.Errorf("Second slot = %+v, want Blue 128-255 with a color", blue)
+	}
+
+	if len(definition.Modes) != 2 {
+		t.Fatalf("Modes = %+v, want 2", definition.Modes)
+	}
+	want := []string{"Dimmer", "Dimmer fine", "ColorAdd_R", "Color1", "", "Pan"}
+	standard := definition.Modes[0]
+	if len(standard.Channels) != len(want) {
+		t.Fatalf("Standard mode channels = %q, want %q", standard.Channels, want)
+	}
+	for i := range want {
+		if standard.Channels[i] != want[i] {
+			t.Errorf("Standard mode channel %d = %q, want %q", i, standard.Channels[i], want[i])
+		}
+	}
+	if basic := definition.Modes[1]; len(basic.Channels) != 1 || basic.Channels[0] != "Dimmer" {
+		t.Errorf("Basic mode channels = %q, want [Dimmer]", basic.Channels)
+	}
+}
+
+func TestParse_Cells(t *testing.T) {
+	description := `<GDTF DataVersion="1.1">
+  <FixtureType Name="Batten" Manufacturer="Test Co">
+    <DMXModes>
+      <DMXMode Name="Pixel" Geometry="Body">
+        <DMXChannels>
+          <DMXChannel DMXBreak="1" Offset="1" Geometry="Body">
+            <LogicalChannel Attribute="Dimmer"/>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="2" Geometry="Pixel1">
+            <LogicalChannel Attribute="ColorAdd_R"/>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="3" Geometry="Pixel2">
+            <LogicalChannel Attribute="ColorAdd_R"/>
+          </DMXChannel>
+        </DMXChannels>
+      </DMXMode>
+    </DMXModes>
+  </FixtureType>
+</GDTF>`
+	definition, err := ParseDescription([]byte(description))
+	if err != nil {
+		t.Fatalf("ParseDescription() error: %v", err)
+	}
+
+	cells := make(map[string]*int)
+	for _, ch := range definition.Channels {
+		cells[ch.Name] = ch.Cell
+	}
+	if cell := cells["Dimmer"]; cell != nil {
+		t.Errorf("Dimmer cell = %d, want a master channel", *cell)
+	}
+	for name, want := range map[string]int{"Pixel1 ColorAdd_R": 0, "Pixel2 ColorAdd_R": 1} {
+		if cell := cells[name]; cell == nil || *cell != want {
+			t.Errorf("%s cell = %v, want %d", name, cell, want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse([]byte("not a zip")); err == nil {
+		t.Error("Expected error for a file that is not a zip archive")
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	_, _ = w.Create("other.xml")
+	_ = w.Close()
+	if _, err := Parse(buf.Bytes()); err == nil {
+		t.Error("Expected error for an archive without description.xml")
+	}
+
+	if _, err := ParseDescription([]byte(`<GDTF><FixtureType Name="No Maker"/></GDTF>`)); err == nil {
+		t.Error("Expected error for a fixture type without a manufacturer")
+	}
+	if _, err := ParseDescription([]byte(`<GDTF><FixtureType Name="Empty" Manufacturer="Test"/></GDTF>`)); err == nil {
+		t.Error("Expected error for a fixture type without DMX modes")
+	}
+}
+
+func TestMapAttribute(t *testing.T) {
+	tests := map[string]string{
+		"Dimmer":         "INTENSITY",
+		"Tilt":           "TILT",
+		"ColorAdd_W":     "WHITE",
+		"ColorSub_M":     "MAGENTA",
+		"Gobo2":          "GOBO",
+		"Gobo1Pos":       "EFFECT",
+		"Color2":         "COLOR_WHEEL",
+		"Shutter1":       "STROBE",
+		"Shutter1Strobe": "STROBE",
+		"Focus1":         "FOCUS",
+		"Control1":       "MACRO",
+		"Prism1":         "EFFECT",
+		"CTO":            "OTHER",
+	}
+	for attribute, want := range tests {
+		if got := mapAttribute(attribute); got != want {
+			t.Errorf("mapAttribute(%q) = %s, want %s", attribute, got, want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package gdtf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// Service handles GDTF fixture import operations
+type Service struct {
+	db          *gorm.DB
+	fixtureRepo *repositories.FixtureRepository
+}
+
+// NewService creates a new GDTF import service
+func NewService(db *gorm.DB, fixtureRepo *repositories.FixtureRepository) *Service {
+	return &Service{
+		db:          db,
+		fixtureRepo: fixtureRepo,
+	}
+}
+
+// ImportFixture imports a fixture definition from a .gdtf file
+func (s *Service) ImportFixture(ctx context.Context, data []byte, replace bool) (*models.FixtureDefinition, error) {
+	parsed, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if fixture already exists
+	existing, err := s.fixtureRepo.FindDefinitionByManufacturerModel(ctx, parsed.Manufacturer, parsed.Model)
+	if err != nil {
+		return nil, fmt.Errorf("error checking existing fixture: %w", err)
+	}
+
+	if existing != nil && !replace {
+		// Count instances using this definition
+		instanceCount, _ := s.fixtureRepo.CountInstancesByDefinitionID(ctx, existing.ID)
+		return nil, fmt.Errorf("FIXTURE_EXISTS:%s %s:%d", parsed.Manufacturer, parsed.Model, instanceCount)
+	}
+
+	var result *models.FixtureDefinition
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if existing != nil {
+			if err := deleteDefinition(tx, existing.ID); err != nil {
+				return fmt.Errorf("failed to delete existing fixture: %w", err)
+			}
+		}
+
+		fixtureID := cuid.New()
+		definition := &models.FixtureDefinition{
+			ID:           fixtureID,
+			Manufacturer: parsed.Manufacturer,
+			Model:        parsed.Model,
+			Type:         parsed.Type,
+			IsBuiltIn:    false,
+		}
+		if err := tx.Create(definition).Error; err != nil {
+			return fmt.Errorf("failed to create fixture definition: %w", err)
+		}
+
+		// Create channel definitions and their capabilities
+		channelNameToID := make(map[string]string, len(parsed.Channels))
+		channels := make([]models.ChannelDefinition, 0, len(parsed.Channels))
+		var capabilities []models.ChannelCapability
+		for _, ch := range parsed.Channels {
+			channelID := cuid.New()
+			channelNameToID[ch.Name] = channelID
+			attribute := ch.Attribute
+			channels = append(channels, models.ChannelDefinition{
+				ID:           channelID,
+				Name:         ch.Name,
+				Type:         ch.Type,
+				Offset:       ch.Offset,
+				MinValue:     0,
+				MaxValue:     255,
+				DefaultValue: ch.DefaultValue,
+				FadeBehavior: ch.FadeBehavior,
+				IsDiscrete:   ch.IsDiscrete,
+				Cell:         ch.Cell,
+				DefinitionID: fixtureID,
+				Attribute:    &attribute,
+				Color:        ch.Color,
+			})
+			for _, capability := range ch.Capabilities {
+				capabilities = append(capabilities, models.ChannelCapability{
+					ID:        cuid.New(),
+					ChannelID: channelID,
+					Name:      capability.Name,
+					MinValue:  capability.MinValue,
+					MaxValue:  capability.MaxValue,
+					Color:     capability.Color,
+					Image:     capability.Image,
+				})
+			}
+		}
+		if err := tx.Create(&channels).Error; err != nil {
+			return fmt.Errorf("failed to create channels: %w", err)
+		}
+		if len(capabilities) > 0 {
+			if err := tx.Create(&capabilities).Error; err != nil {
+				return fmt.Errorf("failed to create channel capabilities: %w", err)
+			}
+		}
+
+		// Create modes
+		for _, gdtfMode := range parsed.Modes {
+			modeID := cuid.New()
+			mode := &models.FixtureMode{
+				ID:           modeID,
+				Name:         gdtfMode.Name,
+				ChannelCount: len(gdtfMode.Channels),
+				DefinitionID: fixtureID,
+			}
+			if err := tx.Create(mode).Error; err != nil {
+				return fmt.Errorf("failed to create mode %s: %w", gdtfMode.Name, err)
+			}
+
+			var modeChannels []models.ModeChannel
+			for offset, channelName := range gdtfMode.Channels {
+				if channelName == "" {
+					continue
+				}
+				modeChannels = append(modeChannels, models.ModeChannel{
+					ID:        cuid.New(),
+					ModeID:    modeID,
+					ChannelID: channelNameToID[channelName],
+					Offset:    offset,
+				})
+			}
+			if err := tx.Create(&modeChannels).Error; err != nil {
+				return fmt.Errorf("failed to create mode channels: %w", err)
+			}
+		}
+
+		// Load the complete result with relations
+		if err := tx.Preload("Channels").Preload("Modes").First(definition, "id = ?", fixtureID).Error; err != nil {
+			return fmt.Errorf("failed to load created fixture: %w", err)
+		}
+
+		result = definition
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// deleteDefinition deletes a fixture definition with its channels,
+// capabilities, and modes.
+func deleteDefinition(tx *gorm.DB, definitionID string) error {
+	modeIDs := tx.Model(&models.FixtureMode{}).Select("id").Where("definition_id = ?", definitionID)
+	if err := tx.Where("mode_id IN (?)", modeIDs).Delete(&models.ModeChannel{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("definition_id = ?", definitionID).Delete(&models.FixtureMode{}).Error; err != nil {
+		return err
+	}
+	channelIDs := tx.Model(&models.ChannelDefinition{}).Select("id").Where("definition_id = ?", definitionID)
+	if err := tx.Where("channel_id IN (?)", channelIDs).Delete(&models.ChannelCapability{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("definition_id = ?", definitionID).Delete(&models.ChannelDefinition{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.FixtureDefinition{}, "id = ?", definitionID).Error
+}
@@ -0,0 +1,60 @@
+package gdtf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+func TestImportFixture(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	service := NewService(testDB.DB, testDB.FixtureRepo)
+	ctx := context.Background()
+	data := gdtfFile(t, testDescription)
+
+	definition, err := service.ImportFixture(ctx, data, false)
+	if err != nil {
+		t.Fatalf("ImportFixture() error: %v", err)
+	}
+	if definition.Type != "MOVING_HEAD" || len(definition.Channels) != 5 || len(definition.Modes) != 2 {
+		t.Errorf("Definition = %s with %d channels and %d modes, want MOVING_HEAD with 5 and 2",
+			definition.Type, len(definition.Channels), len(definition.Modes))
+	}
+
+	var wheel models.ChannelDefinition
+	testDB.DB.First(&wheel, "definition_id = ? AND name = ?", definition.ID, "Color1")
+	if wheel.Attribute == nil || *wheel.Attribute != "Color1" {
+		t.Errorf("Color1 attribute = %v, want Color1", wheel.Attribute)
+	}
+	var capabilities []models.ChannelCapability
+	testDB.DB.Where("channel_id = ?", wheel.ID).Order("min_value").Find(&capabilities)
+	if len(capabilities) != 2 || capabilities[1].Name != "Blue" || capabilities[1].MinValue != 128 {
+		t.Errorf("Color1 capabilities = %+v, want Open and Blue", capabilities)
+	}
+
+	var modeChannels []models.ModeChannel
+	testDB.DB.Joins("JOIN fixture_modes ON fixture_modes.id = mode_channels.mode_id").
+		Where("fixture_modes.definition_id = ? AND fixture_modes.name = ?", definition.ID, "Standard").
+		Find(&modeChannels)
+	if len(modeChannels) != 5 {
+		t.Errorf("Standard mode has %d channels, want 5", len(modeChannels))
+	}
+
+	// Importing again requires replace
+	if _, err := service.ImportFixture(ctx, data, false); err == nil || !strings.HasPrefix(err.Error(), "FIXTURE_EXISTS:") {
+		t.Errorf("Expected FIXTURE_EXISTS error, got %v", err)
+	}
+	replaced, err := service.ImportFixture(ctx, data, true)
+	if err != nil {
+		t.Fatalf("ImportFixture() with replace error: %v", err)
+	}
+	var count int64
+	testDB.DB.Model(&models.ChannelCapability{}).Count(&count)
+	if replaced.ID == definition.ID || count != 5 {
+		t.Errorf("Expected a new definition and only its 5 capabilities, got ID %s and %d capabilities", replaced.ID, count)
+	}
+}
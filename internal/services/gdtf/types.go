@@ -0,0 +1,88 @@
+// Package gdtf provides GDTF (General Device Type Format) fixture import.
+package gdtf
+
+import "encoding/xml"
+
+// Description is the root of a GDTF file's description.xml.
+type Description struct {
+	XMLName     xml.Name    `xml:"GDTF"`
+	DataVersion string      `xml:"DataVersion,attr"`
+	FixtureType FixtureType `xml:"FixtureType"`
+}
+
+// FixtureType describes a fixture and its DMX modes.
+type FixtureType struct {
+	Name         string      `xml:"Name,attr"`
+	ShortName    string      `xml:"ShortName,attr"`
+	LongName     string      `xml:"LongName,attr"`
+	Manufacturer string      `xml:"Manufacturer,attr"`
+	Description  string      `xml:"Description,attr"`
+	Attributes   []Attribute `xml:"AttributeDefinitions>Attributes>Attribute"`
+	Wheels       []Wheel     `xml:"Wheels>Wheel"`
+	DMXModes     []DMXMode   `xml:"DMXModes>DMXMode"`
+}
+
+// Attribute is a controllable function of a fixture, such as Dimmer or
+// ColorAdd_R.
+type Attribute struct {
+	Name    string `xml:"Name,attr"`
+	Pretty  string `xml:"Pretty,attr"`
+	Feature string `xml:"Feature,attr"`
+	Color   string `xml:"Color,attr"` // CIE xyY of a color mixing emitter
+}
+
+// Wheel is a color, gobo, or other wheel.
+type Wheel struct {
+	Name  string      `xml:"Name,attr"`
+	Slots []WheelSlot `xml:"Slot"`
+}
+
+// WheelSlot is a slot of a wheel.
+type WheelSlot struct {
+	Name          string `xml:"Name,attr"`
+	Color         string `xml:"Color,attr"` // CIE xyY
+	MediaFileName string `xml:"MediaFileName,attr"`
+}
+
+// DMXMode is a DMX footprint of a fixture.
+type DMXMode struct {
+	Name        string       `xml:"Name,attr"`
+	Description string       `xml:"Description,attr"`
+	Geometry    string       `xml:"Geometry,attr"`
+	Channels    []DMXChannel `xml:"DMXChannels>DMXChannel"`
+}
+
+// DMXChannel is a channel of a mode, one or more bytes wide.
+type DMXChannel struct {
+	DMXBreak        string           `xml:"DMXBreak,attr"`
+	Offset          string           `xml:"Offset,attr"`  // Comma-separated 1-based offsets, coarse first, or "None"
+	Default         string           `xml:"Default,attr"` // GDTF 1.0; later versions set it per function
+	Geometry        string           `xml:"Geometry,attr"`
+	InitialFunction string           `xml:"InitialFunction,attr"`
+	LogicalChannels []LogicalChannel `xml:"LogicalChannel"`
+}
+
+// LogicalChannel assigns an attribute to a DMX channel.
+type LogicalChannel struct {
+	Attribute string            `xml:"Attribute,attr"`
+	Snap      string            `xml:"Snap,attr"`
+	Functions []ChannelFunction `xml:"ChannelFunction"`
+}
+
+// ChannelFunction is a DMX range of a logical channel, running from DMXFrom
+// to the next function's DMXFrom.
+type ChannelFunction struct {
+	Name      string       `xml:"Name,attr"`
+	Attribute string       `xml:"Attribute,attr"`
+	DMXFrom   string       `xml:"DMXFrom,attr"`
+	Default   string       `xml:"Default,attr"`
+	Wheel     string       `xml:"Wheel,attr"`
+	Sets      []ChannelSet `xml:"ChannelSet"`
+}
+
+// ChannelSet is a named DMX range of a channel function.
+type ChannelSet struct {
+	Name           string `xml:"Name,attr"`
+	DMXFrom        string `xml:"DMXFrom,attr"`
+	WheelSlotIndex string `xml:"WheelSlotIndex,attr"`
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/playback"
+)
+
+// Fade engine ticks that may be missed before the engine counts as stalled,
+// and the least time that allows.
+const (
+	fadeStallTicks    = 10
+	minFadeStallDelay = time.Second
+)
+
+// DatabaseCheck pings the database.
+func DatabaseCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) Result {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return Result{Status: StatusDown, Message: err.Error()}
+		}
+		start := time.Now()
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return Result{Status: StatusDown, Message: err.Error()}
+		}
+		stats := sqlDB.Stats()
+		return Result{Status: StatusOK, Details: map[string]any{
+			"pingMs":          time.Since(start).Milliseconds(),
+			"openConnections": stats.OpenConnections,
+		}}
+	}
+}
+
+// ArtNetCheck reports the Art-Net socket. Output that fails to send
+// degrades the server without taking it down.
+func ArtNetCheck(service *dmx.Service) CheckFunc {
+	return func(ctx context.Context) Result {
+		status := service.OutputStatus()
+		details := map[string]any{
+			"enabled": status.Enabled,
+			"rateHz":  status.RateHz,
+			"passive": status.Passive,
+			"standby": status.Standby,
+		}
+		switch {
+		case !status.Running:
+			return Result{Status: StatusDown, Message: "DMX service is not running", Details: details}
+		case !status.Enabled:
+			return Result{Status: StatusOK, Message: "Art-Net output disabled", Details: details}
+		}
+		details["broadcastAddress"] = status.BroadcastAddr
+		details["port"] = status.Port
+		if !status.SocketOpen {
+			return Result{Status: StatusDown, Message: "Art-Net socket is not open", Details: details}
+		}
+		if status.LastError != nil {
+			details["lastErrorAt"] = status.LastErrorAt.UTC().Format(time.RFC3339)
+			return Result{Status: StatusDegraded, Message: "Art-Net send failed: " + status.LastError.Error(), Details: details}
+		}
+		return Result{Status: StatusOK, Details: details}
+	}
+}
+
+// FadeEngineCheck reports whether the fade engine is ticking at its update
+// rate.
+func FadeEngineCheck(engine *fade.Engine) CheckFunc {
+	return func(ctx context.Context) Result {
+		rateHz := engine.GetUpdateRateHz()
+		details := map[string]any{
+			"rateHz":      rateHz,
+			"activeFades": engine.ActiveFadeCount(),
+		}
+		if !engine.IsRunning() {
+			return Result{Status: StatusDown, Message: "fade engine is not running", Details: details}
+		}
+		sinceTick := time.Since(engine.LastTick())
+		details["lastTickMs"] = sinceTick.Milliseconds()
+		stallDelay := fadeStallTicks * time.Second / time.Duration(rateHz)
+		if stallDelay < minFadeStallDelay {
+			stallDelay = minFadeStallDelay
+		}
+		if sinceTick > stallDelay {
+			return Result{Status: StatusDown, Message: "fade engine has stalled", Details: details}
+		}
+		return Result{Status: StatusOK, Details: details}
+	}
+}
+
+// PlaybackCheck reports the cue lists playing.
+func PlaybackCheck(service *playback.Service) CheckFunc {
+	return func(ctx context.Context) Result {
+		active := service.ActiveCueLists()
+		paused := 0
+		for _, status := range active {
+			if status.IsPaused {
+				paused++
+			}
+		}
+		return Result{Status: StatusOK, Details: map[string]any{
+			"activeCueLists": len(active),
+			"pausedCueLists": paused,
+		}}
+	}
+}
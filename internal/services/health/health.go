@@ -0,0 +1,232 @@
+// Package health reports the state of the server's subsystems, for
+// monitoring and for container orchestration probes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HTTP paths of the health and readiness endpoints.
+const (
+	HealthPath = "/health"
+	ReadyPath  = "/ready"
+)
+
+// CheckTimeout is how long a check may take before it is reported down.
+const CheckTimeout = 2 * time.Second
+
+// Status is the state of a subsystem or of the whole server.
+type Status string
+
+// Statuses, from best to worst.
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Result is the outcome of a check.
+type Result struct {
+	Status  Status         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// CheckFunc reports the state of a subsystem. It should return promptly
+// when ctx is done.
+type CheckFunc func(ctx context.Context) Result
+
+// Report is the state of the server.
+type Report struct {
+	// Down if a critical check is down, degraded if any check is not ok
+	Status        Status            `json:"status"`
+	Ready         bool              `json:"ready"`
+	Timestamp     string            `json:"timestamp"`
+	StartedAt     string            `json:"startedAt"`
+	Uptime        string            `json:"uptime"`
+	UptimeSeconds int64             `json:"uptimeSeconds"`
+	Version       string            `json:"version"`
+	GitCommit     string            `json:"gitCommit"`
+	BuildTime     string            `json:"buildTime"`
+	Checks        map[string]Result `json:"checks"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Service runs the registered checks and serves the health and readiness
+// endpoints.
+type Service struct {
+	version   string
+	gitCommit string
+	buildTime string
+	startedAt time.Time
+
+	mu     sync.Mutex
+	checks []check
+	ready  bool
+	// Last status of each check, to log changes
+	last map[string]Status
+
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// NewService creates a health service for a server build, starting its
+// uptime now. The server is not ready until SetReady is called.
+func NewService(version, gitCommit, buildTime string) *Service {
+	return &Service{
+		version:   version,
+		gitCommit: gitCommit,
+		buildTime: buildTime,
+		startedAt: time.Now(),
+		last:      make(map[string]Status),
+		timeout:   CheckTimeout,
+		now:       time.Now,
+	}
+}
+
+// Register adds a named check. A critical check that is down marks the
+// server down and not ready; any other failure only degrades it.
+func (s *Service) Register(name string, critical bool, fn CheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// SetReady marks the server ready to serve once it has started, or not
+// ready while it shuts down.
+func (s *Service) SetReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ready = ready
+}
+
+// Check runs every check concurrently and reports the server's state.
+func (s *Service) Check(ctx context.Context) Report {
+	s.mu.Lock()
+	checks := append([]check(nil), s.checks...)
+	ready := s.ready
+	s.mu.Unlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, s.timeout, c.fn)
+		}()
+	}
+	wg.Wait()
+
+	now := s.now()
+	uptime := now.Sub(s.startedAt).Truncate(time.Second)
+	report := Report{
+		Status:        StatusOK,
+		Timestamp:     now.UTC().Format(time.RFC3339),
+		StartedAt:     s.startedAt.UTC().Format(time.RFC3339),
+		Uptime:        uptime.String(),
+		UptimeSeconds: int64(uptime / time.Second),
+		Version:       s.version,
+		GitCommit:     s.gitCommit,
+		BuildTime:     s.buildTime,
+		Checks:        make(map[string]Result, len(checks)),
+	}
+	for i, c := range checks {
+		result := results[i]
+		report.Checks[c.name] = result
+		switch {
+		case result.Status == StatusOK:
+		case c.critical && result.Status == StatusDown:
+			report.Status = StatusDown
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	report.Ready = ready && report.Status != StatusDown
+	s.logChanges(report.Checks)
+	return report
+}
+
+// run runs a check, reporting it down if it does not finish in time.
+func run(ctx context.Context, timeout time.Duration, fn CheckFunc) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan Result, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return Result{Status: StatusDown, Message: "check timed out"}
+	}
+}
+
+// logChanges logs each check whose status changed since the last run.
+func (s *Service) logChanges(results map[string]Result) {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		result := results[name]
+		previous, seen := s.last[name]
+		s.last[name] = result.Status
+		if previous == result.Status || (!seen && result.Status == StatusOK) {
+			continue
+		}
+		if result.Status == StatusOK {
+			log.Info("health check recovered", "check", name)
+		} else {
+			log.Warn("health check failing", "check", name, "status", result.Status, "message", result.Message)
+		}
+	}
+}
+
+// ServeHealth reports the server's state as JSON. The response is 503
+// Service Unavailable when the server is down, for liveness probes.
+func (s *Service) ServeHealth(w http.ResponseWriter, r *http.Request) {
+	report := s.Check(r.Context())
+	status := http.StatusOK
+	if report.Status == StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+// ServeReady reports the server's state as JSON. The response is 503
+// Service Unavailable until the server has started, while it shuts down,
+// and while it is down, for readiness probes.
+func (s *Service) ServeReady(w http.ResponseWriter, r *http.Request) {
+	report := s.Check(r.Context())
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Warn("failed to write health report", "error", err)
+	}
+}
@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+func fixed(status Status) CheckFunc {
+	return func(ctx context.Context) Result { return Result{Status: status} }
+}
+
+// serve calls a handler and decodes its report.
+func serve(t *testing.T, handler http.HandlerFunc) (int, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid report: %v\n%s", err, rec.Body.String())
+	}
+	return rec.Code, report
+}
+
+func TestService_Endpoints(t *testing.T) {
+	service := NewService("1.2.3", "abc123", "today")
+	service.startedAt = time.Now().Add(-90 * time.Minute)
+	service.Register("database", true, fixed(StatusOK))
+	service.Register("artnet", false, fixed(StatusDown))
+
+	// A failing non-critical check degrades the server but keeps it live
+	code, report := serve(t, service.ServeHealth)
+	if code != http.StatusOK || report.Status != StatusDegraded || report.Checks["artnet"].Status != StatusDown {
+		t.Errorf("Unexpected health: %d %+v", code, report)
+	}
+	if report.Uptime != "1h30m0s" || report.UptimeSeconds != 5400 || report.Version != "1.2.3" || report.GitCommit != "abc123" {
+		t.Errorf("Unexpected build and uptime: %+v", report)
+	}
+
+	// Not ready until started
+	if code, report := serve(t, service.ServeReady); code != http.StatusServiceUnavailable || report.Ready {
+		t.Errorf("Expected not ready before SetReady, got %d %+v", code, report)
+	}
+	service.SetReady(true)
+	if code, _ := serve(t, service.ServeReady); code != http.StatusOK {
+		t.Errorf("Ready status = %d, want %d", code, http.StatusOK)
+	}
+
+	// A failing critical check takes the server down and out of service
+	service.Register("fadeEngine", true, fixed(StatusDown))
+	if code, report := serve(t, service.ServeHealth); code != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Errorf("Expected down, got %d %+v", code, report)
+	}
+	if code, report := serve(t, service.ServeReady); code != http.StatusServiceUnavailable || report.Ready {
+		t.Errorf("Expected not ready while down, got %d %+v", code, report)
+	}
+}
+
+func TestService_CheckTimeout(t *testing.T) {
+	service := NewService("", "", "")
+	service.timeout = 20 * time.Millisecond
+	service.Register("slow", true, func(ctx context.Context) Result {
+		time.Sleep(time.Second)
+		return Result{Status: StatusOK}
+	})
+
+	start := time.Now()
+	report := service.Check(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Check waited for a slow check")
+	}
+	if result := report.Checks["slow"]; result.Status != StatusDown || result.Message != "check timed out" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestDatabaseCheck(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	check := DatabaseCheck(db)
+	if result := check(context.Background()); result.Status != StatusOK {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	sqlDB, _ := db.DB()
+	_ = sqlDB.Close()
+	if result := check(context.Background()); result.Status != StatusDown || result.Message == "" {
+		t.Errorf("Expected a closed database down, got %+v", result)
+	}
+}
+
+func TestArtNetCheck(t *testing.T) {
+	service := dmx.NewService(dmx.Config{Enabled: false, RefreshRateHz: 44, IdleRateHz: 1})
+	check := ArtNetCheck(service)
+	if result := check(context.Background()); result.Status != StatusDown {
+		t.Errorf("Expected a stopped service down, got %+v", result)
+	}
+
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	defer service.Stop()
+	if result := check(context.Background()); result.Status != StatusOK || result.Message != "Art-Net output disabled" {
+		t.Errorf("Expected simulated output ok, got %+v", result)
+	}
+}
+
+func TestFadeEngineCheck(t *testing.T) {
+	engine := fade.NewEngine(dmx.NewService(dmx.Config{Enabled: false}), 60)
+	check := FadeEngineCheck(engine)
+	if result := check(context.Background()); result.Status != StatusDown {
+		t.Errorf("Expected a stopped engine down, got %+v", result)
+	}
+
+	engine.Start()
+	defer engine.Stop()
+	time.Sleep(50 * time.Millisecond)
+	result := check(context.Background())
+	if result.Status != StatusOK || result.Details["rateHz"] != 60 {
+		t.Errorf("Expected a ticking engine ok, got %+v", result)
+	}
+	if sinceTick, _ := result.Details["lastTickMs"].(int64); sinceTick > 500 {
+		t.Errorf("Last tick %dms ago", sinceTick)
+	}
+}
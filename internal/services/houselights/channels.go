@@ -0,0 +1,60 @@
+package houselights
+
+import (
+	"slices"
+	"sort"
+)
+
+// Fixture is a house light's address and the types of its channels.
+type Fixture struct {
+	Universe     int
+	StartChannel int
+	Channels     []FixtureChannel
+}
+
+// FixtureChannel is one of a fixture's channels.
+type FixtureChannel struct {
+	Offset int
+	Type   string
+}
+
+// channelPreferences are the channel types that set a fixture's level, best
+// first: a dimmer, else its whites, else its red, green and blue.
+var channelPreferences = [][]string{
+	{"INTENSITY"},
+	{"WHITE", "WARM_WHITE", "COLD_WHITE"},
+	{"RED", "GREEN", "BLUE"},
+}
+
+// Channels returns the channels that set the fixtures' levels, in address
+// order. Fixtures without any are left out.
+func Channels(fixtures []Fixture) []Channel {
+	seen := make(map[Channel]bool)
+	var channels []Channel
+	for _, fixture := range fixtures {
+		for _, types := range channelPreferences {
+			var found bool
+			for _, c := range fixture.Channels {
+				if !slices.Contains(types, c.Type) {
+					continue
+				}
+				found = true
+				channel := Channel{Universe: fixture.Universe, Channel: fixture.StartChannel + c.Offset}
+				if !seen[channel] {
+					seen[channel] = true
+					channels = append(channels, channel)
+				}
+			}
+			if found {
+				break
+			}
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Universe != channels[j].Universe {
+			return channels[i].Universe < channels[j].Universe
+		}
+		return channels[i].Channel < channels[j].Channel
+	})
+	return channels
+}
@@ -0,0 +1,301 @@
+// Package houselights gives front of house a simple control over the house
+// lights: a project's chosen fixtures go to one of a few preset levels,
+// fading there, and stay there whatever the stage is doing until released.
+package houselights
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+// Preset is a house lights level.
+type Preset string
+
+const (
+	PresetFull Preset = "FULL"
+	PresetHalf Preset = "HALF"
+	PresetShow Preset = "SHOW" // The level the house sits at during the show
+	PresetOff  Preset = "OFF"
+)
+
+// Levels are the 0-1 levels of the presets; OFF is always 0.
+type Levels struct {
+	Full float64
+	Half float64
+	Show float64
+}
+
+// DefaultLevels are the levels of house lights not yet adjusted.
+var DefaultLevels = Levels{Full: 1, Half: 0.5, Show: 0}
+
+// DefaultFadeTime is the fade of house lights not yet adjusted.
+const DefaultFadeTime = 3 * time.Second
+
+// Level returns the level of a preset.
+func (l Levels) Level(preset Preset) (float64, error) {
+	switch preset {
+	case PresetFull:
+		return l.Full, nil
+	case PresetHalf:
+		return l.Half, nil
+	case PresetShow:
+		return l.Show, nil
+	case PresetOff:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown house lights preset: %s", preset)
+}
+
+// Channel is the DMX address of a house light's dimmer.
+type Channel struct {
+	Universe int
+	Channel  int // 1-indexed
+}
+
+// Output is where house lights are sent: the DMX service.
+type Output interface {
+	SetHouseLights(id string, values map[int]map[int]byte)
+	ClearHouseLights(id string)
+	GetUniverse(universe int) []int
+}
+
+// Status is where a project's house lights stand.
+type Status struct {
+	ProjectID string
+	// Engaged house lights hold their channels; released ones leave them to
+	// the stage
+	Engaged     bool
+	Preset      Preset // Empty when released
+	Level       float64
+	TargetLevel float64
+	Fading      bool
+}
+
+// house is a project's engaged house lights.
+type house struct {
+	preset   Preset
+	channels []Channel
+	from     []float64 // Channel levels when the fade started
+	target   float64
+	start    time.Time
+	duration time.Duration
+	fading   bool
+}
+
+// Service runs the house lights of every project.
+type Service struct {
+	mu     sync.Mutex
+	output Output
+	houses map[string]*house
+
+	// Called with a project whose house lights changed (optional)
+	onChange func(projectID string)
+
+	now func() time.Time
+}
+
+// NewService creates a house lights service. Call Tick on every fade engine
+// tick to run fades.
+func NewService(output Output) *Service {
+	return &Service{
+		output: output,
+		houses: make(map[string]*house),
+		now:    time.Now,
+	}
+}
+
+// SetChangeCallback sets the callback for house lights changes.
+func (s *Service) SetChangeCallback(callback func(projectID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// layerID returns the DMX house lights layer ID used for a project.
+func layerID(projectID string) string {
+	return "house:" + projectID
+}
+
+// Set fades a project's house lights on channels to a preset's level.
+// Channels the house lights did not hold yet fade from what they are
+// showing.
+func (s *Service) Set(projectID string, channels []Channel, preset Preset, level float64, fadeTime time.Duration) Status {
+	s.mu.Lock()
+	now := s.now()
+	var previous map[Channel]float64
+	if h := s.houses[projectID]; h != nil {
+		previous = make(map[Channel]float64, len(h.channels))
+		for i, level := range h.levelsAt(now) {
+			previous[h.channels[i]] = level
+		}
+	}
+	from := make([]float64, len(channels))
+	universes := make(map[int][]int)
+	for i, channel := range channels {
+		if level, ok := previous[channel]; ok {
+			from[i] = level
+			continue
+		}
+		values, ok := universes[channel.Universe]
+		if !ok {
+			values = s.output.GetUniverse(channel.Universe)
+			universes[channel.Universe] = values
+		}
+		if channel.Channel >= 1 && channel.Channel <= len(values) {
+			from[i] = float64(values[channel.Channel-1]) / 255
+		}
+	}
+
+	h := &house{
+		preset:   preset,
+		channels: channels,
+		from:     from,
+		target:   level,
+		start:    now,
+		duration: fadeTime,
+		fading:   fadeTime > 0,
+	}
+	s.houses[projectID] = h
+	s.output.SetHouseLights(layerID(projectID), h.valuesAt(now))
+	status := h.status(projectID, now)
+	s.mu.Unlock()
+
+	log.Info("house lights set", "project", projectID, "preset", preset, "level", level, "fade", fadeTime)
+	s.notify(projectID)
+	return status
+}
+
+// Retarget moves engaged house lights to new channels and their preset's
+// new level at once, as when the house lights are changed. Released house
+// lights are left alone.
+func (s *Service) Retarget(projectID string, channels []Channel, level float64) {
+	s.mu.Lock()
+	h := s.houses[projectID]
+	if h == nil {
+		s.mu.Unlock()
+		return
+	}
+	now := s.now()
+	h.channels = channels
+	h.from = make([]float64, len(channels))
+	h.target = level
+	h.start = now
+	h.duration = 0
+	h.fading = false
+	s.output.SetHouseLights(layerID(projectID), h.valuesAt(now))
+	s.mu.Unlock()
+
+	s.notify(projectID)
+}
+
+// Release hands a project's house light channels back to the stage at once.
+func (s *Service) Release(projectID string) Status {
+	s.mu.Lock()
+	_, engaged := s.houses[projectID]
+	delete(s.houses, projectID)
+	s.mu.Unlock()
+
+	if engaged {
+		s.output.ClearHouseLights(layerID(projectID))
+		log.Info("house lights released", "project", projectID)
+		s.notify(projectID)
+	}
+	return Status{ProjectID: projectID}
+}
+
+// Status returns where a project's house lights stand.
+func (s *Service) Status(projectID string) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.houses[projectID]
+	if h == nil {
+		return Status{ProjectID: projectID}
+	}
+	return h.status(projectID, s.now())
+}
+
+// Tick moves fades along.
+func (s *Service) Tick(now time.Time) {
+	s.mu.Lock()
+	var done []string
+	for projectID, h := range s.houses {
+		if !h.fading {
+			continue
+		}
+		if now.Sub(h.start) >= h.duration {
+			h.fading = false
+			done = append(done, projectID)
+		}
+		s.output.SetHouseLights(layerID(projectID), h.valuesAt(now))
+	}
+	s.mu.Unlock()
+
+	for _, projectID := range done {
+		go s.notify(projectID)
+	}
+}
+
+// progress returns how far the fade has got, eased.
+func (h *house) progress(now time.Time) float64 {
+	if h.duration <= 0 {
+		return 1
+	}
+	progress := float64(now.Sub(h.start)) / float64(h.duration)
+	return fade.ApplyEasing(math.Max(0, math.Min(1, progress)), fade.EasingInOutSine)
+}
+
+// levelsAt returns each channel's 0-1 level.
+func (h *house) levelsAt(now time.Time) []float64 {
+	progress := h.progress(now)
+	levels := make([]float64, len(h.channels))
+	for i := range h.channels {
+		levels[i] = h.from[i] + (h.target-h.from[i])*progress
+	}
+	return levels
+}
+
+// valuesAt returns the DMX output: universe -> channel -> value.
+func (h *house) valuesAt(now time.Time) map[int]map[int]byte {
+	values := make(map[int]map[int]byte)
+	for i, level := range h.levelsAt(now) {
+		channel := h.channels[i]
+		if values[channel.Universe] == nil {
+			values[channel.Universe] = make(map[int]byte)
+		}
+		values[channel.Universe][channel.Channel] = byte(math.Round(level * 255))
+	}
+	return values
+}
+
+func (h *house) status(projectID string, now time.Time) Status {
+	level := h.target
+	if h.fading && len(h.channels) > 0 {
+		level = 0
+		for _, l := range h.levelsAt(now) {
+			level += l
+		}
+		level /= float64(len(h.channels))
+	}
+	return Status{
+		ProjectID:   projectID,
+		Engaged:     true,
+		Preset:      h.preset,
+		Level:       level,
+		TargetLevel: h.target,
+		Fading:      h.fading,
+	}
+}
+
+func (s *Service) notify(projectID string) {
+	s.mu.Lock()
+	callback := s.onChange
+	s.mu.Unlock()
+	if callback != nil {
+		callback(projectID)
+	}
+}
@@ -0,0 +1,181 @@
+package houselights
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOutput records the house lights layers and shows a fixed stage.
+type fakeOutput struct {
+	mu     sync.Mutex
+	stage  []int
+	layers map[string]map[int]map[int]byte
+}
+
+func newFakeOutput() *fakeOutput {
+	return &fakeOutput{stage: make([]int, 512), layers: make(map[string]map[int]map[int]byte)}
+}
+
+func (o *fakeOutput) SetHouseLights(id string, values map[int]map[int]byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.layers[id] = values
+}
+
+func (o *fakeOutput) ClearHouseLights(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.layers, id)
+}
+
+func (o *fakeOutput) GetUniverse(int) []int {
+	return o.stage
+}
+
+func (o *fakeOutput) value(id string, universe, channel int) (byte, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	layer, ok := o.layers[id]
+	if !ok {
+		return 0, false
+	}
+	return layer[universe][channel], true
+}
+
+func TestLevels(t *testing.T) {
+	levels := Levels{Full: 0.9, Half: 0.4, Show: 0.1}
+	tests := map[Preset]float64{PresetFull: 0.9, PresetHalf: 0.4, PresetShow: 0.1, PresetOff: 0}
+	for preset, want := range tests {
+		if got, err := levels.Level(preset); err != nil || got != want {
+			t.Errorf("Level(%s) = %v, %v; want %v", preset, got, err, want)
+		}
+	}
+	if _, err := levels.Level("DIM"); err == nil {
+		t.Error("Level(DIM) should fail")
+	}
+}
+
+func TestSetFadesAndRelease(t *testing.T) {
+	output := newFakeOutput()
+	output.stage[9] = 51 // Channel 10 is at 20% on stage
+	service := NewService(output)
+	start := time.Now()
+	service.now = func() time.Time { return start }
+
+	var changes []string
+	var mu sync.Mutex
+	service.SetChangeCallback(func(projectID string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, projectID)
+	})
+
+	channels := []Channel{{Universe: 1, Channel: 10}, {Universe: 1, Channel: 11}}
+	status := service.Set("p1", channels, PresetFull, 1, 2*time.Second)
+	if !status.Engaged || status.Preset != PresetFull || !status.Fading || status.TargetLevel != 1 {
+		t.Fatalf("Set() = %+v", status)
+	}
+	// The fade starts from what the channels show
+	if value, _ := output.value("house:p1", 1, 10); value != 51 {
+		t.Errorf("channel 10 at start = %d, want 51", value)
+	}
+	if value, _ := output.value("house:p1", 1, 11); value != 0 {
+		t.Errorf("channel 11 at start = %d, want 0", value)
+	}
+
+	service.Tick(start.Add(time.Second))
+	if value, _ := output.value("house:p1", 1, 11); value < 127 || value > 128 {
+		t.Errorf("channel 11 halfway = %d, want about 128", value)
+	}
+
+	service.Tick(start.Add(2 * time.Second))
+	if value, _ := output.value("house:p1", 1, 10); value != 255 {
+		t.Errorf("channel 10 at end = %d, want 255", value)
+	}
+	service.now = func() time.Time { return start.Add(2 * time.Second) }
+	if status := service.Status("p1"); status.Fading || status.Level != 1 {
+		t.Errorf("Status() after fade = %+v", status)
+	}
+
+	// Retargeting moves to the new channels at once
+	service.Retarget("p1", []Channel{{Universe: 2, Channel: 1}}, 0.5)
+	if value, _ := output.value("house:p1", 2, 1); value != 128 {
+		t.Errorf("retargeted channel = %d, want 128", value)
+	}
+	if value, _ := output.value("house:p1", 1, 10); value != 0 {
+		t.Errorf("channel 10 should be dropped, got %d", value)
+	}
+
+	if status := service.Release("p1"); status.Engaged {
+		t.Errorf("Release() = %+v", status)
+	}
+	if _, ok := output.value("house:p1", 2, 1); ok {
+		t.Error("Release() should clear the layer")
+	}
+	if status := service.Status("p1"); status.Engaged {
+		t.Errorf("Status() after release = %+v", status)
+	}
+
+	// Retargeting released house lights leaves them released
+	service.Retarget("p1", channels, 1)
+	if _, ok := output.value("house:p1", 1, 10); ok {
+		t.Error("Retarget() should not engage released house lights")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 4 {
+		t.Errorf("changes = %v, want set, fade end, retarget, release", changes)
+	}
+}
+
+func TestSetFromCurrentLevel(t *testing.T) {
+	output := newFakeOutput()
+	service := NewService(output)
+	start := time.Now()
+	service.now = func() time.Time { return start }
+
+	channels := []Channel{{Universe: 1, Channel: 1}}
+	service.Set("p1", channels, PresetFull, 1, 0)
+
+	// A new preset fades from the house lights' level, not the stage's
+	output.stage[0] = 0
+	service.Set("p1", channels, PresetOff, 0, time.Second)
+	if value, _ := output.value("house:p1", 1, 1); value != 255 {
+		t.Errorf("channel 1 at start = %d, want 255", value)
+	}
+	service.Tick(start.Add(time.Second))
+	if value, _ := output.value("house:p1", 1, 1); value != 0 {
+		t.Errorf("channel 1 at end = %d, want 0", value)
+	}
+	// OFF keeps holding the channels dark
+	if status := service.Status("p1"); !status.Engaged || status.Preset != PresetOff {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	fixtures := []Fixture{
+		// A dimmer is preferred over color
+		{Universe: 1, StartChannel: 10, Channels: []FixtureChannel{{0, "INTENSITY"}, {1, "RED"}, {2, "GREEN"}, {3, "BLUE"}}},
+		// Whites before red, green and blue
+		{Universe: 1, StartChannel: 1, Channels: []FixtureChannel{{0, "RED"}, {1, "GREEN"}, {2, "BLUE"}, {3, "WARM_WHITE"}, {4, "COLD_WHITE"}}},
+		{Universe: 2, StartChannel: 5, Channels: []FixtureChannel{{0, "RED"}, {1, "GREEN"}, {2, "BLUE"}, {3, "UV"}}},
+		// Nothing to set its level
+		{Universe: 2, StartChannel: 100, Channels: []FixtureChannel{{0, "PAN"}, {1, "TILT"}}},
+		// The same dimmer twice
+		{Universe: 1, StartChannel: 10, Channels: []FixtureChannel{{0, "INTENSITY"}}},
+	}
+	want := []Channel{{1, 4}, {1, 5}, {1, 10}, {2, 5}, {2, 6}, {2, 7}}
+	got := Channels(fixtures)
+	if len(got) != len(want) {
+		t.Fatalf("Channels() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Channels()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
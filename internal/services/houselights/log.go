@@ -0,0 +1,5 @@
+package houselights
+
+import "github.com/bbernstein/lacylights-go/internal/services/logging"
+
+var log = logging.For(logging.ModuleHouseLights)
@@ -5,10 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/services/export"
+	"github.com/bbernstein/lacylights-go/internal/services/macro"
 	"github.com/lucsky/cuid"
 )
 
@@ -38,6 +40,11 @@ type ImportStats struct {
 	CueListsCreated           int
 	CuesCreated               int
 	SceneBoardsCreated        int
+	FixtureGroupsCreated      int
+	PalettesCreated           int
+
+	// Changes lists what a dry run would do, record by record
+	Changes []ImportChange
 }
 
 // ImportOptions configures the import behavior.
@@ -47,6 +54,7 @@ type ImportOptions struct {
 	ProjectName             *string
 	FixtureConflictStrategy FixtureConflictStrategy
 	ImportBuiltInFixtures   bool
+	DryRun                  bool // Work out what the import would do without writing anything
 }
 
 // Service handles project import operations.
@@ -57,6 +65,9 @@ type Service struct {
 	cueListRepo    *repositories.CueListRepository
 	cueRepo        *repositories.CueRepository
 	sceneBoardRepo *repositories.SceneBoardRepository
+	groupRepo      *repositories.FixtureGroupRepository
+	paletteRepo    *repositories.PaletteRepository
+	universeRepo   *repositories.UniverseRepository
 }
 
 // NewService creates a new import service.
@@ -95,6 +106,21 @@ func NewServiceWithSceneBoards(
 	}
 }
 
+// SetFixtureGroupRepo enables importing fixture groups.
+func (s *Service) SetFixtureGroupRepo(groupRepo *repositories.FixtureGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// SetPaletteRepo enables importing palettes.
+func (s *Service) SetPaletteRepo(paletteRepo *repositories.PaletteRepository) {
+	s.paletteRepo = paletteRepo
+}
+
+// SetUniverseRepo enables importing universes.
+func (s *Service) SetUniverseRepo(universeRepo *repositories.UniverseRepository) {
+	s.universeRepo = universeRepo
+}
+
 // createInstanceChannelsFromDefinitionChannels creates instance channels from definition channels.
 // This is a helper function to reduce code duplication.
 func createInstanceChannelsFromDefinitionChannels(channels []models.ChannelDefinition) []models.InstanceChannel {
@@ -109,25 +135,35 @@ func createInstanceChannelsFromDefinitionChannels(channels []models.ChannelDefin
 			DefaultValue: ch.DefaultValue,
 			FadeBehavior: ch.FadeBehavior,
 			IsDiscrete:   ch.IsDiscrete,
+			Cell:         ch.Cell,
 		})
 	}
 	return instanceChannels
 }
 
-// importModesForExistingDefinition imports modes from export data into an existing definition.
-// It skips modes that already exist (matched by name).
-// Returns warnings and a map of old mode refID -> mode name for imported modes.
-func (s *Service) importModesForExistingDefinition(ctx context.Context, existingDefID string, exportedModes []export.ExportedFixtureMode, exportedChannels []export.ExportedChannelDefinition) ([]string, map[string]string, error) {
+// modePlan is an exported mode that an existing definition lacks, with the
+// mode channels it will have. Their ModeID is set when the mode is created.
+type modePlan struct {
+	mode     export.ExportedFixtureMode
+	channels []models.ModeChannel
+}
+
+// planModesForExistingDefinition works out which exported modes an existing
+// definition lacks (matched by name), mapping their channels to the
+// definition's. It returns those modes, the names of the modes the
+// definition already has, warnings, and a map of old mode refID -> mode name
+// for every exported mode.
+func (s *Service) planModesForExistingDefinition(ctx context.Context, existingDefID string, exportedModes []export.ExportedFixtureMode, exportedChannels []export.ExportedChannelDefinition) ([]modePlan, map[string]bool, []string, map[string]string, error) {
 	var warnings []string
 	modeRefIDToNameMap := make(map[string]string)
 
 	// Get existing modes for this definition
 	existingModes, err := s.fixtureRepo.GetDefinitionModes(ctx, existingDefID)
 	if err != nil {
-		return warnings, modeRefIDToNameMap, err
+		return nil, nil, warnings, modeRefIDToNameMap, err
 	}
 
-	// Build a set of existing mode names and track refID -> name mapping for existing modes
+	// Build a set of existing mode names
 	existingModeNames := make(map[string]bool)
 	for _, m := range existingModes {
 		existingModeNames[m.Name] = true
@@ -136,7 +172,7 @@ func (s *Service) importModesForExistingDefinition(ctx context.Context, existing
 	// Get existing channels for this definition to build name -> ID mapping
 	existingChannels, err := s.fixtureRepo.GetDefinitionChannels(ctx, existingDefID)
 	if err != nil {
-		return warnings, modeRefIDToNameMap, err
+		return nil, nil, warnings, modeRefIDToNameMap, err
 	}
 
 	// Build channel name -> existing channel ID mapping
@@ -153,30 +189,16 @@ func (s *Service) importModesForExistingDefinition(ctx context.Context, existing
 		}
 	}
 
-	// Import each mode that doesn't already exist and track the mapping
+	// Plan each mode that doesn't already exist and track the mapping
+	var plans []modePlan
 	for _, mode := range exportedModes {
+		// Track the mapping of old mode refID -> mode name
+		modeRefIDToNameMap[mode.RefID] = mode.Name
 		if existingModeNames[mode.Name] {
-			// Mode already exists, track the mapping
-			modeRefIDToNameMap[mode.RefID] = mode.Name
 			continue
 		}
 
-		newMode := &models.FixtureMode{
-			Name:         mode.Name,
-			ShortName:    mode.ShortName,
-			ChannelCount: mode.ChannelCount,
-			DefinitionID: existingDefID,
-		}
-
-		if err := s.fixtureRepo.CreateMode(ctx, newMode); err != nil {
-			return warnings, modeRefIDToNameMap, err
-		}
-
-		// Track the mapping of old mode refID -> new mode name
-		modeRefIDToNameMap[mode.RefID] = newMode.Name
-
-		// Create mode channels
-		var modeChannels []models.ModeChannel
+		plan := modePlan{mode: mode}
 		for _, mc := range mode.ModeChannels {
 			// First try to map RefID -> channel name -> existing channel ID
 			var existingChannelID string
@@ -198,15 +220,44 @@ func (s *Service) importModesForExistingDefinition(ctx context.Context, existing
 				continue
 			}
 
-			modeChannels = append(modeChannels, models.ModeChannel{
-				ModeID:    newMode.ID,
+			plan.channels = append(plan.channels, models.ModeChannel{
 				ChannelID: existingChannelID,
 				Offset:    mc.Offset,
 			})
 		}
+		plans = append(plans, plan)
+	}
 
-		if len(modeChannels) > 0 {
-			if err := s.fixtureRepo.CreateModeChannels(ctx, modeChannels); err != nil {
+	return plans, existingModeNames, warnings, modeRefIDToNameMap, nil
+}
+
+// importModesForExistingDefinition imports modes from export data into an existing definition.
+// It skips modes that already exist (matched by name).
+// Returns warnings and a map of old mode refID -> mode name for imported modes.
+func (s *Service) importModesForExistingDefinition(ctx context.Context, existingDefID string, exportedModes []export.ExportedFixtureMode, exportedChannels []export.ExportedChannelDefinition) ([]string, map[string]string, error) {
+	plans, _, warnings, modeRefIDToNameMap, err := s.planModesForExistingDefinition(ctx, existingDefID, exportedModes, exportedChannels)
+	if err != nil {
+		return warnings, modeRefIDToNameMap, err
+	}
+
+	for _, plan := range plans {
+		newMode := &models.FixtureMode{
+			Name:         plan.mode.Name,
+			ShortName:    plan.mode.ShortName,
+			ChannelCount: plan.mode.ChannelCount,
+			DefinitionID: existingDefID,
+		}
+
+		if err := s.fixtureRepo.CreateMode(ctx, newMode); err != nil {
+			return warnings, modeRefIDToNameMap, err
+		}
+
+		// Create mode channels
+		if len(plan.channels) > 0 {
+			for i := range plan.channels {
+				plan.channels[i].ModeID = newMode.ID
+			}
+			if err := s.fixtureRepo.CreateModeChannels(ctx, plan.channels); err != nil {
 				return warnings, modeRefIDToNameMap, err
 			}
 		}
@@ -217,11 +268,28 @@ func (s *Service) importModesForExistingDefinition(ctx context.Context, existing
 
 // ImportProject imports a project from JSON.
 func (s *Service) ImportProject(ctx context.Context, jsonContent string, options ImportOptions) (string, *ImportStats, []string, error) {
-	// Parse the JSON
 	exported, err := export.ParseExportedProject(jsonContent)
 	if err != nil {
 		return "", nil, nil, err
 	}
+	return s.ImportExported(ctx, exported, options)
+}
+
+// ImportArchive imports a project from a project archive or JSON export,
+// decoding it as it is read.
+func (s *Service) ImportArchive(ctx context.Context, r io.Reader, options ImportOptions) (string, *ImportStats, []string, error) {
+	exported, err := export.ReadArchive(r)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return s.ImportExported(ctx, exported, options)
+}
+
+// ImportExported imports a decoded project export.
+func (s *Service) ImportExported(ctx context.Context, exported *export.ExportedProject, options ImportOptions) (string, *ImportStats, []string, error) {
+	if options.DryRun {
+		return s.previewImport(ctx, exported, options)
+	}
 
 	stats := &ImportStats{}
 	var warnings []string
@@ -241,6 +309,10 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 			Name:        projectName,
 			Description: exported.GetProjectDescription(),
 		}
+		if exported.Project != nil {
+			project.DefaultFadeIn = exported.Project.DefaultFadeIn
+			project.DefaultFadeOut = exported.Project.DefaultFadeOut
+		}
 		if err := s.projectRepo.Create(ctx, project); err != nil {
 			return "", nil, nil, err
 		}
@@ -269,6 +341,9 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 	definitionIDMap := make(map[string]string) // old ID -> new ID
 	fixtureIDMap := make(map[string]string)    // old ID -> new ID
 	sceneIDMap := make(map[string]string)      // old ID -> new ID
+	groupIDMap := make(map[string]string)      // old ID -> new ID
+	paletteIDMap := make(map[string]string)    // old ID -> new ID
+	cueListIDMap := make(map[string]string)    // old ID -> new ID
 	modeRefIDToNameMap := make(map[string]string) // old mode refID -> new mode name
 
 	// Import fixture definitions
@@ -375,6 +450,7 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 				DefaultValue: ch.DefaultValue,
 				FadeBehavior: fadeBehavior,
 				IsDiscrete:   ch.IsDiscrete,
+				Cell:         ch.Cell,
 			})
 			// Map the old RefID to the new ID
 			if ch.RefID != "" {
@@ -489,6 +565,7 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 			LayoutX:        f.LayoutX,
 			LayoutY:        f.LayoutY,
 			LayoutRotation: f.LayoutRotation,
+			MaxIntensity:   f.MaxIntensity,
 		}
 
 		// Use instance channels from export if available, otherwise get from definition
@@ -509,6 +586,7 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 					DefaultValue: ch.DefaultValue,
 					FadeBehavior: fadeBehavior,
 					IsDiscrete:   ch.IsDiscrete,
+					Cell:         ch.Cell,
 				})
 			}
 		} else {
@@ -560,6 +638,7 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 								DefaultValue: ch.DefaultValue,
 								FadeBehavior: ch.FadeBehavior,
 								IsDiscrete:   ch.IsDiscrete,
+								Cell:         ch.Cell,
 							})
 						}
 					}
@@ -601,12 +680,100 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 		stats.FixtureInstancesCreated++
 	}
 
+	// Import fixture groups
+	if s.groupRepo != nil {
+		for _, group := range exported.FixtureGroups {
+			fixtureIDs := make([]string, 0, len(group.FixtureRefIDs))
+			for _, refID := range group.FixtureRefIDs {
+				newFixtureID, ok := fixtureIDMap[refID]
+				if !ok {
+					warnings = append(warnings, "Skipping unknown fixture '"+refID+"' in group '"+group.Name+"'")
+					continue
+				}
+				fixtureIDs = append(fixtureIDs, newFixtureID)
+			}
+			fixtureIDsJSON, err := json.Marshal(fixtureIDs)
+			if err != nil {
+				return "", nil, nil, err
+			}
+
+			newGroup := &models.FixtureGroup{
+				Name:        group.Name,
+				Description: group.Description,
+				ProjectID:   projectID,
+				FixtureIDs:  string(fixtureIDsJSON),
+			}
+			if err := s.groupRepo.Create(ctx, newGroup); err != nil {
+				return "", nil, nil, err
+			}
+			groupIDMap[group.RefID] = newGroup.ID
+			stats.FixtureGroupsCreated++
+		}
+	}
+
+	// Import universes, keeping those the project already configures
+	if s.universeRepo != nil {
+		for _, universe := range exported.Universes {
+			existing, err := s.universeRepo.FindByNumber(ctx, projectID, universe.Number)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			if existing != nil {
+				continue
+			}
+			protocol := universe.Protocol
+			if protocol == "" {
+				protocol = "ARTNET"
+			}
+			newUniverse := &models.Universe{
+				ProjectID:   projectID,
+				Number:      universe.Number,
+				Label:       universe.Label,
+				Protocol:    protocol,
+				Destination: universe.Destination,
+				Enabled:     universe.Enabled,
+			}
+			if err := s.universeRepo.Create(ctx, newUniverse); err != nil {
+				return "", nil, nil, err
+			}
+		}
+	}
+
+	// Import palettes
+	if s.paletteRepo != nil {
+		for _, palette := range exported.Palettes {
+			channels := make([]models.ChannelTypeValue, len(palette.Channels))
+			for i, ch := range palette.Channels {
+				channels[i] = models.ChannelTypeValue{Type: ch.Type, Value: ch.Value}
+			}
+			channelsJSON, err := json.Marshal(channels)
+			if err != nil {
+				return "", nil, nil, err
+			}
+
+			newPalette := &models.Palette{
+				Name:      palette.Name,
+				ProjectID: projectID,
+				Kind:      palette.Kind,
+				Channels:  string(channelsJSON),
+			}
+			if err := s.paletteRepo.Create(ctx, newPalette); err != nil {
+				return "", nil, nil, err
+			}
+			paletteIDMap[palette.RefID] = newPalette.ID
+			stats.PalettesCreated++
+		}
+	}
+
 	// Import scenes
 	for _, scene := range exported.Scenes {
 		newScene := &models.Scene{
-			Name:        scene.Name,
-			Description: scene.Description,
-			ProjectID:   projectID,
+			Name:           scene.Name,
+			SecondaryLabel: scene.SecondaryLabel,
+			Description:    scene.Description,
+			ProjectID:      projectID,
+			DefaultFadeIn:  scene.DefaultFadeIn,
+			DefaultFadeOut: scene.DefaultFadeOut,
 		}
 
 		var fixtureValues []models.FixtureValue
@@ -644,11 +811,29 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 				warnings = append(warnings, "Skipping fixture value for fixture '"+fv.FixtureRefID+"' in scene '"+scene.Name+"' due to JSON marshaling error: "+err.Error())
 				continue
 			}
+
+			// Channels already hold the palettes' values, so unknown palettes are
+			// only dropped as references
+			paletteIDs := make([]string, 0, len(fv.PaletteRefIDs))
+			for _, refID := range fv.PaletteRefIDs {
+				newPaletteID, ok := paletteIDMap[refID]
+				if !ok {
+					warnings = append(warnings, "Skipping unknown palette '"+refID+"' in scene '"+scene.Name+"'")
+					continue
+				}
+				paletteIDs = append(paletteIDs, newPaletteID)
+			}
+			paletteIDsJSON, err := json.Marshal(paletteIDs)
+			if err != nil {
+				return "", nil, nil, err
+			}
+
 			fixtureValues = append(fixtureValues, models.FixtureValue{
 				ID:        cuid.New(),
 				FixtureID: newFixtureID,
 				Channels:  string(channelsJSON),
 				SceneOrder: fv.SceneOrder,
+				PaletteIDs: string(paletteIDsJSON),
 			})
 		}
 
@@ -656,21 +841,50 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 			return "", nil, nil, err
 		}
 		sceneIDMap[scene.RefID] = newScene.ID
+
+		var groupValues []models.GroupValue
+		for _, gv := range scene.GroupValues {
+			newGroupID, ok := groupIDMap[gv.GroupRefID]
+			if !ok {
+				warnings = append(warnings, "Skipping group value with unknown group '"+gv.GroupRefID+"' in scene '"+scene.Name+"'")
+				continue
+			}
+			channels := make([]models.ChannelTypeValue, len(gv.Channels))
+			for i, ch := range gv.Channels {
+				channels[i] = models.ChannelTypeValue{Type: ch.Type, Value: ch.Value}
+			}
+			channelsJSON, err := json.Marshal(channels)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			groupValues = append(groupValues, models.GroupValue{GroupID: newGroupID, Channels: string(channelsJSON)})
+		}
+		if len(groupValues) > 0 {
+			if err := s.sceneRepo.ReplaceGroupValues(ctx, newScene.ID, groupValues); err != nil {
+				return "", nil, nil, err
+			}
+			if err := s.sceneRepo.SyncGroupValues(ctx, newScene.ID); err != nil {
+				return "", nil, nil, err
+			}
+		}
 		stats.ScenesCreated++
 	}
 
 	// Import cue lists
 	for _, cueList := range exported.CueLists {
 		newCueList := &models.CueList{
-			Name:        cueList.Name,
-			Description: cueList.Description,
-			Loop:        cueList.Loop,
-			ProjectID:   projectID,
+			Name:         cueList.Name,
+			Description:  cueList.Description,
+			Loop:         cueList.Loop,
+			PlaybackMode: cueList.PlaybackMode,
+			Tracking:     cueList.Tracking,
+			ProjectID:    projectID,
 		}
 
 		if err := s.cueListRepo.Create(ctx, newCueList); err != nil {
 			return "", nil, nil, err
 		}
+		cueListIDMap[cueList.RefID] = newCueList.ID
 		stats.CueListsCreated++
 
 		// Import cues
@@ -682,15 +896,20 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 			}
 
 			newCue := &models.Cue{
-				Name:        cue.Name,
-				CueNumber:   cue.CueNumber,
-				CueListID:   newCueList.ID,
-				SceneID:     newSceneID,
-				FadeInTime:  cue.FadeInTime,
-				FadeOutTime: cue.FadeOutTime,
-				FollowTime:  cue.FollowTime,
-				EasingType:  cue.EasingType,
-				Notes:       cue.Notes,
+				Name:           cue.Name,
+				SecondaryLabel: cue.SecondaryLabel,
+				CueNumber:      cue.CueNumber,
+				CueListID:      newCueList.ID,
+				SceneID:        newSceneID,
+				FadeInTime:     cue.FadeInTime,
+				FadeOutTime:    cue.FadeOutTime,
+				FollowTime:     cue.FollowTime,
+				FollowQuantize: cue.FollowQuantize,
+				EasingType:     cue.EasingType,
+				Notes:          cue.Notes,
+				Timecode:       cue.Timecode,
+				Block:          cue.Block,
+				MoveInBlack:    cue.MoveInBlack,
 			}
 
 			if err := s.cueRepo.Create(ctx, newCue); err != nil {
@@ -714,6 +933,9 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 				GridSize:        board.GridSize,
 				CanvasWidth:     board.CanvasWidth,
 				CanvasHeight:    board.CanvasHeight,
+				HoldRampTime:    board.HoldRampTime,
+				HoldCurve:       board.HoldCurve,
+				HoldReleaseMode: board.HoldReleaseMode,
 				ProjectID:       projectID,
 			}
 
@@ -725,14 +947,34 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 					continue
 				}
 
+				actions, macroWarnings := remapMacro(btn.Macro, sceneIDMap, cueListIDMap, board.Name)
+				warnings = append(warnings, macroWarnings...)
+				macroValue, err := macro.Encode(actions)
+				if err != nil {
+					return "", nil, nil, err
+				}
+
+				audioAction := btn.AudioAction
+				if audioAction == "" {
+					audioAction = "BUMP"
+				}
 				buttons = append(buttons, models.SceneBoardButton{
-					SceneID: newSceneID,
-					LayoutX: btn.LayoutX,
-					LayoutY: btn.LayoutY,
-					Width:   btn.Width,
-					Height:  btn.Height,
-					Color:   btn.Color,
-					Label:   btn.Label,
+					SceneID:          newSceneID,
+					LayoutX:          btn.LayoutX,
+					LayoutY:          btn.LayoutY,
+					Width:            btn.Width,
+					Height:           btn.Height,
+					Color:            btn.Color,
+					Label:            btn.Label,
+					FadeInTime:       btn.FadeInTime,
+					FadeOutTime:      btn.FadeOutTime,
+					FlashMode:        btn.FlashMode,
+					FlashLevel:       btn.FlashLevel,
+					FlashReleaseTime: btn.FlashReleaseTime,
+					AudioTrigger:     btn.AudioTrigger,
+					AudioBand:        btn.AudioBand,
+					AudioAction:      audioAction,
+					Macro:            macroValue,
 				})
 			}
 
@@ -745,3 +987,30 @@ func (s *Service) ImportProject(ctx context.Context, jsonContent string, options
 
 	return projectID, stats, warnings, nil
 }
+
+// remapMacro points an imported button's macro at the imported scenes and
+// cue lists, dropping actions whose target was not imported.
+func remapMacro(actions []macro.Action, sceneIDMap, cueListIDMap map[string]string, boardName string) ([]macro.Action, []string) {
+	var warnings []string
+	remapped := make([]macro.Action, 0, len(actions))
+	for _, action := range actions {
+		switch action.Type {
+		case macro.ActionActivateScene:
+			newSceneID, ok := sceneIDMap[action.SceneID]
+			if !ok {
+				warnings = append(warnings, "Skipping macro action with unknown scene in board: "+boardName)
+				continue
+			}
+			action.SceneID = newSceneID
+		case macro.ActionCueListGo:
+			newCueListID, ok := cueListIDMap[action.CueListID]
+			if !ok {
+				warnings = append(warnings, "Skipping macro action with unknown cue list in board: "+boardName)
+				continue
+			}
+			action.CueListID = newCueListID
+		}
+		remapped = append(remapped, action)
+	}
+	return remapped, warnings
+}
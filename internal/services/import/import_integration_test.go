@@ -453,6 +453,83 @@ func TestImportProject_CreateMode_WithCueList(t *testing.T) {
 	}
 }
 
+func TestImportProject_CreateMode_SecondaryLabels(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(
+		testDB.ProjectRepo,
+		testDB.FixtureRepo,
+		testDB.SceneRepo,
+		testDB.CueListRepo,
+		testDB.CueRepo,
+	)
+
+	sceneLabel := "Escena completa"
+	cueLabel := "GO top"
+	exported := &export.ExportedProject{
+		Version: "1.0",
+		Project: &export.ExportProjectInfo{
+			OriginalID: "orig-proj-1",
+			Name:       testutil.UniqueProjectName("TestImportSecondaryLabels"),
+		},
+		Scenes: []export.ExportedScene{
+			{RefID: "scene-1", Name: "Full Stage", SecondaryLabel: &sceneLabel},
+		},
+		CueLists: []export.ExportedCueList{
+			{
+				RefID: "cl-1",
+				Name:  "Main",
+				Cues: []export.ExportedCue{
+					{Name: "Top of Show", SecondaryLabel: &cueLabel, CueNumber: 1.0, SceneRefID: "scene-1"},
+				},
+			},
+		},
+	}
+
+	jsonStr, err := exported.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to create JSON: %v", err)
+	}
+
+	ctx := context.Background()
+	projectID, _, _, err := service.ImportProject(ctx, jsonStr, ImportOptions{
+		Mode: ImportModeCreate,
+	})
+	if err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+
+	scenes, err := testDB.SceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		t.Fatalf("Failed to find scenes: %v", err)
+	}
+	if len(scenes) != 1 {
+		t.Fatalf("Expected 1 scene, got %d", len(scenes))
+	}
+	if scenes[0].SecondaryLabel == nil || *scenes[0].SecondaryLabel != sceneLabel {
+		t.Errorf("Expected scene secondary label '%s', got %v", sceneLabel, scenes[0].SecondaryLabel)
+	}
+
+	cueLists, err := testDB.CueListRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		t.Fatalf("Failed to find cue lists: %v", err)
+	}
+	if len(cueLists) != 1 {
+		t.Fatalf("Expected 1 cue list, got %d", len(cueLists))
+	}
+	cues, err := testDB.CueRepo.FindByCueListID(ctx, cueLists[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to find cues: %v", err)
+	}
+	if len(cues) != 1 {
+		t.Fatalf("Expected 1 cue, got %d", len(cues))
+	}
+	if cues[0].SecondaryLabel == nil || *cues[0].SecondaryLabel != cueLabel {
+		t.Errorf("Expected cue secondary label '%s', got %v", cueLabel, cues[0].SecondaryLabel)
+	}
+}
+
 func TestImportProject_CreateMode_CompleteProject(t *testing.T) {
 	testDB, cleanup := testutil.SetupTestDB(t)
 	defer cleanup()
@@ -925,3 +1002,82 @@ func TestImportProject_Integration_WithInstanceChannels(t *testing.T) {
 		t.Errorf("Expected 1 fixture, got %d", len(fixtures))
 	}
 }
+
+func TestImportProject_CellsRoundTrip(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(
+		testDB.ProjectRepo,
+		testDB.FixtureRepo,
+		testDB.SceneRepo,
+		testDB.CueListRepo,
+		testDB.CueRepo,
+	)
+
+	cell0, cell1 := 0, 1
+	exported := &export.ExportedProject{
+		Version: "1.0",
+		Project: &export.ExportProjectInfo{
+			OriginalID: "orig-proj-1",
+			Name:       testutil.UniqueProjectName("TestImportCells"),
+		},
+		FixtureDefinitions: []export.ExportedFixtureDefinition{
+			{
+				RefID:        "def-1",
+				Manufacturer: "TestMfg",
+				Model:        testutil.UniqueFixtureName("Batten"),
+				Type:         "LED",
+				Channels: []export.ExportedChannelDefinition{
+					{RefID: "ch-1", Name: "Dimmer", Type: "INTENSITY", Offset: 0, MaxValue: 255},
+					{RefID: "ch-2", Name: "Cell 1 Red", Type: "RED", Offset: 1, MaxValue: 255, Cell: &cell0},
+					{RefID: "ch-3", Name: "Cell 2 Red", Type: "RED", Offset: 2, MaxValue: 255, Cell: &cell1},
+				},
+			},
+		},
+		FixtureInstances: []export.ExportedFixtureInstance{
+			{RefID: "inst-1", Name: "Batten 1", DefinitionRefID: "def-1", Universe: 1, StartChannel: 1},
+		},
+	}
+	jsonStr, err := exported.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to create JSON: %v", err)
+	}
+
+	ctx := context.Background()
+	projectID, _, _, err := service.ImportProject(ctx, jsonStr, ImportOptions{Mode: ImportModeCreate})
+	if err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+
+	channels, err := testDB.FixtureRepo.GetProjectInstanceChannels(ctx, projectID)
+	if err != nil {
+		t.Fatalf("Failed to get instance channels: %v", err)
+	}
+	if len(channels) != 3 {
+		t.Fatalf("Expected 3 instance channels, got %d", len(channels))
+	}
+	if channels[0].Cell != nil {
+		t.Errorf("Dimmer cell = %d, want a master channel", *channels[0].Cell)
+	}
+	if channels[2].Cell == nil || *channels[2].Cell != 1 {
+		t.Errorf("Cell 2 Red cell = %v, want 1", channels[2].Cell)
+	}
+
+	exportService := export.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo)
+	reexported, _, err := exportService.ExportProject(ctx, projectID, true, false, false)
+	if err != nil {
+		t.Fatalf("ExportProject failed: %v", err)
+	}
+	if len(reexported.FixtureDefinitions) != 1 {
+		t.Fatalf("Expected 1 exported definition, got %d", len(reexported.FixtureDefinitions))
+	}
+	for _, ch := range reexported.FixtureDefinitions[0].Channels {
+		if ch.Name == "Cell 2 Red" && (ch.Cell == nil || *ch.Cell != 1) {
+			t.Errorf("Exported Cell 2 Red cell = %v, want 1", ch.Cell)
+		}
+		if ch.Name == "Dimmer" && ch.Cell != nil {
+			t.Errorf("Exported Dimmer cell = %d, want none", *ch.Cell)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/database/repositories"
 	"github.com/bbernstein/lacylights-go/internal/services/export"
+	"github.com/bbernstein/lacylights-go/internal/services/macro"
 	"github.com/glebarez/sqlite"
 	"github.com/lucsky/cuid"
 	"gorm.io/gorm"
@@ -1133,12 +1134,21 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
 		&models.InstanceChannel{},
 		&models.Scene{},
 		&models.FixtureValue{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
 		&models.CueList{},
 		&models.Cue{},
 		&models.SceneBoard{},
@@ -3645,3 +3655,27 @@ func TestImportProject_ModeRefID_ExistingDefinition_Integration(t *testing.T) {
 		t.Error("Expected mode '5-channel' to exist")
 	}
 }
+
+func TestRemapMacro(t *testing.T) {
+	actions := []macro.Action{
+		{Type: macro.ActionActivateScene, SceneID: "old-scene"},
+		{Type: macro.ActionActivateScene, SceneID: "missing-scene"},
+		{Type: macro.ActionWait, WaitTime: 1},
+		{Type: macro.ActionCueListGo, CueListID: "old-list"},
+		{Type: macro.ActionCueListGo, CueListID: "missing-list"},
+	}
+	remapped, warnings := remapMacro(actions,
+		map[string]string{"old-scene": "new-scene"},
+		map[string]string{"old-list": "new-list"},
+		"Board")
+
+	if len(remapped) != 3 {
+		t.Fatalf("Expected 3 actions, got %d", len(remapped))
+	}
+	if remapped[0].SceneID != "new-scene" || remapped[1].Type != macro.ActionWait || remapped[2].CueListID != "new-list" {
+		t.Errorf("Unexpected remapped macro: %+v", remapped)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", warnings)
+	}
+}
@@ -0,0 +1,380 @@
+package importservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+)
+
+// ImportAction is what an import does with a record.
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "CREATE" // Added as a new record
+	ImportActionMerge  ImportAction = "MERGE"  // Folded into an existing record
+	ImportActionRename ImportAction = "RENAME" // Added alongside an existing record of the same name
+	ImportActionSkip   ImportAction = "SKIP"   // Left out, or an existing record used as it is
+)
+
+// ImportEntityType is the kind of record an import change is about.
+type ImportEntityType string
+
+const (
+	ImportEntityProject           ImportEntityType = "PROJECT"
+	ImportEntityFixtureDefinition ImportEntityType = "FIXTURE_DEFINITION"
+	ImportEntityFixture           ImportEntityType = "FIXTURE"
+	ImportEntityFixtureGroup      ImportEntityType = "FIXTURE_GROUP"
+	ImportEntityUniverse          ImportEntityType = "UNIVERSE"
+	ImportEntityPalette           ImportEntityType = "PALETTE"
+	ImportEntityScene             ImportEntityType = "SCENE"
+	ImportEntityCueList           ImportEntityType = "CUE_LIST"
+	ImportEntityCue               ImportEntityType = "CUE"
+	ImportEntitySceneBoard        ImportEntityType = "SCENE_BOARD"
+)
+
+// ImportChange is what an import does with one record of the document.
+type ImportChange struct {
+	EntityType ImportEntityType
+	Name       string
+	Action     ImportAction
+	Reason     string // Why the record is merged, renamed or skipped
+}
+
+// importPreview collects the changes a dry run finds.
+type importPreview struct {
+	stats    *ImportStats
+	warnings []string
+	// Names the target project already uses, by type; empty for a new project
+	existing map[ImportEntityType]map[string]bool
+}
+
+// add records a record the import creates, as a rename when the target
+// project already has one of that name.
+func (p *importPreview) add(entityType ImportEntityType, name string) {
+	if p.existing[entityType][name] {
+		p.change(entityType, name, ImportActionRename, "the project already has one named "+name)
+		return
+	}
+	p.change(entityType, name, ImportActionCreate, "")
+}
+
+func (p *importPreview) change(entityType ImportEntityType, name string, action ImportAction, reason string) {
+	p.stats.Changes = append(p.stats.Changes, ImportChange{EntityType: entityType, Name: name, Action: action, Reason: reason})
+}
+
+func (p *importPreview) warn(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+// previewImport works out what importing a document would do, reading but
+// never writing the database. It makes the decisions ImportExported makes,
+// and returns the counts and warnings the import would, with each record's
+// change in the stats.
+func (s *Service) previewImport(ctx context.Context, exported *export.ExportedProject, options ImportOptions) (string, *ImportStats, []string, error) {
+	p := &importPreview{stats: &ImportStats{Changes: []ImportChange{}}}
+
+	var projectID string
+	switch options.Mode {
+	case ImportModeCreate:
+		projectName := exported.GetProjectName()
+		if options.ProjectName != nil {
+			projectName = *options.ProjectName
+		}
+		p.change(ImportEntityProject, projectName, ImportActionCreate, "")
+
+	case ImportModeMerge, ImportModeReplace:
+		if options.TargetProjectID == nil {
+			return "", nil, nil, fmt.Errorf("targetProjectId is required to import into a project")
+		}
+		projectID = *options.TargetProjectID
+		project, err := s.projectRepo.FindByID(ctx, projectID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if project == nil {
+			return "", nil, nil, fmt.Errorf("project not found: %s", projectID)
+		}
+		p.change(ImportEntityProject, project.Name, ImportActionMerge, "records are added to the existing project")
+		if p.existing, err = s.projectNames(ctx, projectID); err != nil {
+			return "", nil, nil, err
+		}
+
+	default:
+		return "", nil, nil, fmt.Errorf("unknown import mode: %s", options.Mode)
+	}
+
+	definitionModes, modeRefIDToNameMap, err := s.previewDefinitions(ctx, p, exported, options)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// Fixture instances
+	fixtures := make(map[string]bool)
+	for _, f := range exported.FixtureInstances {
+		modes, ok := definitionModes[f.DefinitionRefID]
+		if !ok {
+			p.warn("Skipping fixture instance with unknown definition: " + f.Name)
+			p.change(ImportEntityFixture, f.Name, ImportActionSkip, "its fixture definition is not in the document")
+			continue
+		}
+
+		modeName := f.ModeName
+		if f.ModeRefID != nil && *f.ModeRefID != "" {
+			if mappedModeName, ok := modeRefIDToNameMap[*f.ModeRefID]; ok {
+				modeName = &mappedModeName
+			} else if modeName != nil && *modeName != "" {
+				p.warn("Mode refID '" + *f.ModeRefID + "' not found for fixture '" + f.Name + "', using mode name '" + *modeName + "' instead")
+			}
+		}
+		if len(f.InstanceChannels) == 0 && modeName != nil && *modeName != "" && !modes[*modeName] {
+			p.warn(fmt.Sprintf("Mode '%s' not found for fixture '%s', using all definition channels", *modeName, f.Name))
+		}
+
+		fixtures[f.RefID] = true
+		p.stats.FixtureInstancesCreated++
+		p.add(ImportEntityFixture, f.Name)
+	}
+
+	// Fixture groups
+	groups := make(map[string]bool)
+	if s.groupRepo != nil {
+		for _, group := range exported.FixtureGroups {
+			for _, refID := range group.FixtureRefIDs {
+				if !fixtures[refID] {
+					p.warn("Skipping unknown fixture '" + refID + "' in group '" + group.Name + "'")
+				}
+			}
+			groups[group.RefID] = true
+			p.stats.FixtureGroupsCreated++
+			p.add(ImportEntityFixtureGroup, group.Name)
+		}
+	}
+
+	// Universes
+	if s.universeRepo != nil {
+		for _, universe := range exported.Universes {
+			name := export.UniverseName(universe)
+			if projectID != "" {
+				existing, err := s.universeRepo.FindByNumber(ctx, projectID, universe.Number)
+				if err != nil {
+					return "", nil, nil, err
+				}
+				if existing != nil {
+					p.change(ImportEntityUniverse, name, ImportActionSkip, "the project already configures this universe")
+					continue
+				}
+			}
+			p.change(ImportEntityUniverse, name, ImportActionCreate, "")
+		}
+	}
+
+	// Palettes
+	palettes := make(map[string]bool)
+	if s.paletteRepo != nil {
+		for _, palette := range exported.Palettes {
+			palettes[palette.RefID] = true
+			p.stats.PalettesCreated++
+			p.add(ImportEntityPalette, palette.Name)
+		}
+	}
+
+	// Scenes
+	scenes := make(map[string]string) // Ref ID to itself, for remapMacro
+	for _, scene := range exported.Scenes {
+		for _, fv := range scene.FixtureValues {
+			if !fixtures[fv.FixtureRefID] {
+				p.warn("Skipping fixture value with unknown fixture '" + fv.FixtureRefID + "' in scene '" + scene.Name + "'")
+				continue
+			}
+			for _, refID := range fv.PaletteRefIDs {
+				if !palettes[refID] {
+					p.warn("Skipping unknown palette '" + refID + "' in scene '" + scene.Name + "'")
+				}
+			}
+		}
+		for _, gv := range scene.GroupValues {
+			if !groups[gv.GroupRefID] {
+				p.warn("Skipping group value with unknown group '" + gv.GroupRefID + "' in scene '" + scene.Name + "'")
+			}
+		}
+		scenes[scene.RefID] = scene.RefID
+		p.stats.ScenesCreated++
+		p.add(ImportEntityScene, scene.Name)
+	}
+
+	// Cue lists and their cues
+	cueLists := make(map[string]string)
+	for _, cueList := range exported.CueLists {
+		cueLists[cueList.RefID] = cueList.RefID
+		p.stats.CueListsCreated++
+		p.add(ImportEntityCueList, cueList.Name)
+
+		for _, cue := range cueList.Cues {
+			if _, ok := scenes[cue.SceneRefID]; !ok {
+				p.warn("Skipping cue with unknown scene in cue list: " + cueList.Name)
+				p.change(ImportEntityCue, fmt.Sprintf("%s: cue %g", cueList.Name, cue.CueNumber), ImportActionSkip, "its scene is not in the document")
+				continue
+			}
+			p.stats.CuesCreated++
+		}
+	}
+
+	// Scene boards
+	if s.sceneBoardRepo != nil {
+		for _, board := range exported.SceneBoards {
+			for _, btn := range board.Buttons {
+				if _, ok := scenes[btn.SceneRefID]; !ok {
+					p.warn("Skipping scene board button with unknown scene in board: " + board.Name)
+					continue
+				}
+				_, macroWarnings := remapMacro(btn.Macro, scenes, cueLists, board.Name)
+				p.warnings = append(p.warnings, macroWarnings...)
+			}
+			p.stats.SceneBoardsCreated++
+			p.add(ImportEntitySceneBoard, board.Name)
+		}
+	}
+
+	return projectID, p.stats, p.warnings, nil
+}
+
+// previewDefinitions works out what importing the document's fixture
+// definitions would do. It returns the mode names each definition would
+// have, by definition ref ID, and the name each mode ref ID would map to.
+func (s *Service) previewDefinitions(ctx context.Context, p *importPreview, exported *export.ExportedProject, options ImportOptions) (map[string]map[string]bool, map[string]string, error) {
+	definitionModes := make(map[string]map[string]bool)
+	modeRefIDToNameMap := make(map[string]string)
+
+	for _, def := range exported.FixtureDefinitions {
+		name := def.Manufacturer + " " + def.Model
+		existing, err := s.fixtureRepo.FindDefinitionByManufacturerModel(ctx, def.Manufacturer, def.Model)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		builtIn := def.IsBuiltIn && !options.ImportBuiltInFixtures
+		if existing != nil && (builtIn || options.FixtureConflictStrategy == FixtureConflictSkip || options.FixtureConflictStrategy == FixtureConflictReplace) {
+			plans, modeNames, modeWarnings, modeMappings, err := s.planModesForExistingDefinition(ctx, existing.ID, def.Modes, def.Channels)
+			if err != nil {
+				return nil, nil, err
+			}
+			p.warnings = append(p.warnings, modeWarnings...)
+			for oldRefID, modeName := range modeMappings {
+				modeRefIDToNameMap[oldRefID] = modeName
+			}
+			switch {
+			case builtIn:
+			case options.FixtureConflictStrategy == FixtureConflictSkip:
+				p.warn("Skipped existing fixture definition: " + name)
+			default:
+				p.warn("Reused existing fixture definition (Replace merges modes): " + name)
+			}
+
+			added := make([]string, len(plans))
+			for i, plan := range plans {
+				added[i] = plan.mode.Name
+				modeNames[plan.mode.Name] = true
+			}
+			definitionModes[def.RefID] = modeNames
+			if len(added) > 0 {
+				sort.Strings(added)
+				p.change(ImportEntityFixtureDefinition, name, ImportActionMerge, "adds modes to the existing definition: "+strings.Join(added, ", "))
+			} else {
+				p.change(ImportEntityFixtureDefinition, name, ImportActionSkip, "the existing definition is used")
+			}
+			continue
+		}
+
+		// A new definition, with the exported modes
+		channels := make(map[string]bool)
+		for _, ch := range def.Channels {
+			if ch.RefID != "" {
+				channels[ch.RefID] = true
+			} else {
+				channels[ch.Name] = true
+			}
+		}
+		modeNames := make(map[string]bool)
+		for _, mode := range def.Modes {
+			modeNames[mode.Name] = true
+			modeRefIDToNameMap[mode.RefID] = mode.Name
+			for _, mc := range mode.ModeChannels {
+				if !channels[mc.ChannelRefID] {
+					p.warn("Mode channel references unknown channel: " + mc.ChannelRefID)
+				}
+			}
+		}
+		definitionModes[def.RefID] = modeNames
+		p.stats.FixtureDefinitionsCreated++
+		if existing != nil {
+			p.change(ImportEntityFixtureDefinition, name, ImportActionRename, "added alongside the existing definition of this manufacturer and model")
+		} else {
+			p.change(ImportEntityFixtureDefinition, name, ImportActionCreate, "")
+		}
+	}
+	return definitionModes, modeRefIDToNameMap, nil
+}
+
+// projectNames returns the names of a project's records, by type.
+func (s *Service) projectNames(ctx context.Context, projectID string) (map[ImportEntityType]map[string]bool, error) {
+	names := make(map[ImportEntityType]map[string]bool)
+	add := func(entityType ImportEntityType, name string) {
+		if names[entityType] == nil {
+			names[entityType] = make(map[string]bool)
+		}
+		names[entityType][name] = true
+	}
+
+	fixtures, err := s.fixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fixtures {
+		add(ImportEntityFixture, f.Name)
+	}
+	scenes, err := s.sceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, scene := range scenes {
+		add(ImportEntityScene, scene.Name)
+	}
+	cueLists, err := s.cueListRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cueList := range cueLists {
+		add(ImportEntityCueList, cueList.Name)
+	}
+	if s.groupRepo != nil {
+		groups, err := s.groupRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			add(ImportEntityFixtureGroup, group.Name)
+		}
+	}
+	if s.paletteRepo != nil {
+		palettes, err := s.paletteRepo.FindByProjectID(ctx, projectID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, palette := range palettes {
+			add(ImportEntityPalette, palette.Name)
+		}
+	}
+	if s.sceneBoardRepo != nil {
+		boards, err := s.sceneBoardRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, board := range boards {
+			add(ImportEntitySceneBoard, board.Name)
+		}
+	}
+	return names, nil
+}
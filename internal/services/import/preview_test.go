@@ -0,0 +1,160 @@
+package importservice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+func TestImportProject_DryRun(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(
+		testDB.ProjectRepo,
+		testDB.FixtureRepo,
+		testDB.SceneRepo,
+		testDB.CueListRepo,
+		testDB.CueRepo,
+	)
+
+	ctx := context.Background()
+	manufacturer := "PreviewMfg"
+	model := testutil.UniqueFixtureName("PreviewModel")
+	newModel := testutil.UniqueFixtureName("PreviewNewModel")
+	channels := []export.ExportedChannelDefinition{
+		{RefID: "ch-1", Name: "Intensity", Type: "INTENSITY", Offset: 0, MaxValue: 255},
+	}
+	mode := func(name string) export.ExportedFixtureMode {
+		return export.ExportedFixtureMode{
+			RefID:        "mode-" + name,
+			Name:         name,
+			ChannelCount: 1,
+			ModeChannels: []export.ExportedModeChannel{{ChannelRefID: "ch-1", Offset: 0}},
+		}
+	}
+
+	// The project to merge into, with a definition, fixture, scene and cue list
+	existing := &export.ExportedProject{
+		Version: "1.0",
+		Project: &export.ExportProjectInfo{Name: testutil.UniqueProjectName("TestDryRun")},
+		FixtureDefinitions: []export.ExportedFixtureDefinition{
+			{RefID: "def-1", Manufacturer: manufacturer, Model: model, Type: "DIMMER", Channels: channels, Modes: []export.ExportedFixtureMode{mode("1ch")}},
+		},
+		FixtureInstances: []export.ExportedFixtureInstance{
+			{RefID: "fix-1", Name: "Front", DefinitionRefID: "def-1", Universe: 1, StartChannel: 1},
+		},
+		Scenes:   []export.ExportedScene{{RefID: "scene-1", Name: "Warm Wash"}},
+		CueLists: []export.ExportedCueList{{RefID: "list-1", Name: "Main"}},
+	}
+	jsonStr, _ := existing.ToJSON()
+	projectID, _, _, err := service.ImportProject(ctx, jsonStr, ImportOptions{Mode: ImportModeCreate})
+	if err != nil {
+		t.Fatalf("Setup import failed: %v", err)
+	}
+
+	doc := &export.ExportedProject{
+		Version: "1.0",
+		Project: &export.ExportProjectInfo{Name: "Incoming"},
+		FixtureDefinitions: []export.ExportedFixtureDefinition{
+			{RefID: "def-1", Manufacturer: manufacturer, Model: model, Type: "DIMMER", Channels: channels, Modes: []export.ExportedFixtureMode{mode("1ch"), mode("Extended")}},
+			{RefID: "def-2", Manufacturer: manufacturer, Model: newModel, Type: "DIMMER", Channels: channels},
+		},
+		FixtureInstances: []export.ExportedFixtureInstance{
+			{RefID: "fix-1", Name: "Front", DefinitionRefID: "def-1", Universe: 1, StartChannel: 2},
+			{RefID: "fix-2", Name: "Side", DefinitionRefID: "def-2", Universe: 1, StartChannel: 3},
+			{RefID: "fix-3", Name: "Orphan", DefinitionRefID: "def-missing", Universe: 1, StartChannel: 4},
+		},
+		Scenes: []export.ExportedScene{
+			{RefID: "scene-1", Name: "Warm Wash", FixtureValues: []export.ExportedFixtureValue{{FixtureRefID: "fix-1"}}},
+			{RefID: "scene-2", Name: "Cool"},
+		},
+		CueLists: []export.ExportedCueList{
+			{RefID: "list-1", Name: "Main", Cues: []export.ExportedCue{
+				{CueNumber: 1, SceneRefID: "scene-1"},
+				{CueNumber: 2, SceneRefID: "scene-missing"},
+			}},
+		},
+	}
+	docJSON, _ := doc.ToJSON()
+	options := ImportOptions{
+		Mode:                    ImportModeMerge,
+		TargetProjectID:         &projectID,
+		FixtureConflictStrategy: FixtureConflictSkip,
+	}
+
+	dryRun := options
+	dryRun.DryRun = true
+	previewID, preview, previewWarnings, err := service.ImportProject(ctx, docJSON, dryRun)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if previewID != projectID {
+		t.Errorf("Expected the target project %s, got %q", projectID, previewID)
+	}
+
+	actions := make(map[ImportEntityType]map[string]ImportAction)
+	for _, change := range preview.Changes {
+		if actions[change.EntityType] == nil {
+			actions[change.EntityType] = make(map[string]ImportAction)
+		}
+		actions[change.EntityType][change.Name] = change.Action
+	}
+	expected := map[ImportEntityType]map[string]ImportAction{
+		ImportEntityProject: {existing.Project.Name: ImportActionMerge},
+		ImportEntityFixtureDefinition: {
+			manufacturer + " " + model:    ImportActionMerge,
+			manufacturer + " " + newModel: ImportActionCreate,
+		},
+		ImportEntityFixture: {"Front": ImportActionRename, "Side": ImportActionCreate, "Orphan": ImportActionSkip},
+		ImportEntityScene:   {"Warm Wash": ImportActionRename, "Cool": ImportActionCreate},
+		ImportEntityCueList: {"Main": ImportActionRename},
+		ImportEntityCue:     {"Main: cue 2": ImportActionSkip},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("Unexpected changes:\n got %v\nwant %v", actions, expected)
+	}
+
+	// Nothing was written
+	if def, _ := testDB.FixtureRepo.FindDefinitionByManufacturerModel(ctx, manufacturer, newModel); def != nil {
+		t.Error("Expected the dry run not to create a fixture definition")
+	}
+	scenes, _ := testDB.SceneRepo.FindByProjectID(ctx, projectID)
+	if len(scenes) != 1 {
+		t.Errorf("Expected the project to keep 1 scene, got %d", len(scenes))
+	}
+
+	// The import does what the dry run said it would
+	_, stats, warnings, err := service.ImportProject(ctx, docJSON, options)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	preview.Changes = nil
+	if !reflect.DeepEqual(stats, preview) {
+		t.Errorf("Expected the import's stats to match the dry run's:\n got %+v\nwant %+v", stats, preview)
+	}
+	if !reflect.DeepEqual(warnings, previewWarnings) {
+		t.Errorf("Expected the import's warnings to match the dry run's:\n got %v\nwant %v", warnings, previewWarnings)
+	}
+}
+
+func TestImportProject_DryRun_InvalidTarget(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	service := NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo)
+	exported := &export.ExportedProject{Version: "1.0", Project: &export.ExportProjectInfo{Name: "Test Project"}}
+
+	missing := "non-existent-project-id"
+	for name, options := range map[string]ImportOptions{
+		"no target":      {Mode: ImportModeMerge, DryRun: true},
+		"missing target": {Mode: ImportModeMerge, TargetProjectID: &missing, DryRun: true},
+	} {
+		if _, _, _, err := service.ImportExported(context.Background(), exported, options); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+// Package input maps DMX received over Art-Net or sACN, typically from a
+// physical fader wing, onto server controls such as the grand master,
+// submasters, and scene board buttons.
+package input
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+	"github.com/bbernstein/lacylights-go/pkg/sacn"
+)
+
+// SettingKey is the setting that stores the fader wing configuration as JSON.
+const SettingKey = "fader_wing_config"
+
+// MaxUniverse is the highest input universe (the 15-bit Art-Net port
+// address + 1).
+const MaxUniverse = 32768
+
+// Target is a kind of control a mapped channel drives.
+type Target string
+
+const (
+	// TargetGrandMaster drives the grand master.
+	TargetGrandMaster Target = "GRAND_MASTER"
+	// TargetSubmaster drives a submaster level.
+	TargetSubmaster Target = "SUBMASTER"
+	// TargetSceneBoardButton drives the intensity of a scene board button's scene.
+	TargetSceneBoardButton Target = "SCENE_BOARD_BUTTON"
+)
+
+// Mapping routes one received DMX channel to a control.
+type Mapping struct {
+	Universe int    `json:"universe"` // 1-based, as for output
+	Channel  int    `json:"channel"`  // 1-512
+	Target   Target `json:"target"`
+	TargetID string `json:"targetId,omitempty"` // Submaster or button ID; empty for the grand master
+}
+
+// Config is the fader wing mapping table.
+type Config struct {
+	Enabled  bool      `json:"enabled"`
+	Mappings []Mapping `json:"mappings"`
+}
+
+// Validate checks the mappings.
+func (c *Config) Validate() error {
+	seen := make(map[channelKey]bool)
+	for i, m := range c.Mappings {
+		if m.Universe < 1 || m.Universe > MaxUniverse {
+			return fmt.Errorf("mapping %d: universe must be between 1 and %d", i+1, MaxUniverse)
+		}
+		if m.Channel < 1 || m.Channel > 512 {
+			return fmt.Errorf("mapping %d: channel must be between 1 and 512", i+1)
+		}
+		switch m.Target {
+		case TargetGrandMaster:
+			if m.TargetID != "" {
+				return fmt.Errorf("mapping %d: the grand master takes no target ID", i+1)
+			}
+		case TargetSubmaster, TargetSceneBoardButton:
+			if m.TargetID == "" {
+				return fmt.Errorf("mapping %d: %s requires a target ID", i+1, m.Target)
+			}
+		default:
+			return fmt.Errorf("mapping %d: unknown target %q", i+1, m.Target)
+		}
+		key := channelKey{m.Universe, m.Channel}
+		if seen[key] {
+			return fmt.Errorf("mapping %d: universe %d channel %d is already mapped", i+1, m.Universe, m.Channel)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Handler sets a control to a level between 0 and 1.
+type Handler func(targetID string, level float64)
+
+// Status is a snapshot of the fader wing input.
+type Status struct {
+	Config Config
+	// Listening is true while the Art-Net port is open
+	Listening        bool
+	AvailableTargets []Target
+	PacketsReceived  int
+	LastPacketAt     *time.Time
+	LastSource       *string
+}
+
+type channelKey struct {
+	universe int
+	channel  int
+}
+
+// Service receives DMX and dispatches changes on mapped channels to the
+// handler registered for each target. Handlers run on the receiving
+// goroutine, so a fader move reaches its control within one packet.
+type Service struct {
+	mu         sync.Mutex
+	artNetPort int
+	sacnPort   int
+
+	config   Config
+	handlers map[Target]Handler
+	levels   map[channelKey]byte // Last value received on each mapped channel
+
+	artNet *net.UDPConn
+	sacn   []*net.UDPConn
+
+	packets      int
+	lastPacketAt time.Time
+	lastSource   string
+
+	// Reports packets sent by this server's own output (optional)
+	ignore func(from *net.UDPAddr) bool
+	// Called for every ArtDmx packet from another sender (optional)
+	onArtNet func()
+
+	now func() time.Time
+}
+
+// NewService creates a fader wing input service receiving Art-Net on
+// artNetPort and sACN on its standard port.
+func NewService(artNetPort int) *Service {
+	if artNetPort <= 0 {
+		artNetPort = artnet.DefaultPort
+	}
+	return &Service{
+		artNetPort: artNetPort,
+		sacnPort:   sacn.DefaultPort,
+		handlers:   make(map[Target]Handler),
+		levels:     make(map[channelKey]byte),
+		now:        time.Now,
+	}
+}
+
+// SetHandler registers the handler for a target. Mappings to targets without
+// a handler are rejected.
+func (s *Service) SetHandler(target Target, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[target] = handler
+}
+
+// SetIgnoreSource sets the check for packets sent by this server, whose
+// broadcast output loops back to the Art-Net port.
+func (s *Service) SetIgnoreSource(ignore func(from *net.UDPAddr) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignore = ignore
+}
+
+// SetArtNetCallback sets the callback for Art-Net DMX received from other
+// senders. While the input holds the Art-Net port, other services learn of
+// Art-Net traffic through it.
+func (s *Service) SetArtNetCallback(callback func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onArtNet = callback
+}
+
+// Status returns the current input state.
+func (s *Service) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+// SetConfig validates and applies a mapping table, starting or stopping the
+// listeners. The first value received on each channel afterwards is applied
+// unless it is zero, so connecting a wing with its faders down leaves looks
+// set from elsewhere alone.
+func (s *Service) SetConfig(config Config) (*Status, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range config.Mappings {
+		if s.handlers[m.Target] == nil {
+			return nil, fmt.Errorf("mapping %d: %s controls are not available", i+1, m.Target)
+		}
+	}
+
+	s.config = config
+	s.config.Mappings = append([]Mapping(nil), config.Mappings...)
+	s.levels = make(map[channelKey]byte)
+
+	s.stopListenersLocked()
+	if config.Enabled && len(config.Mappings) > 0 {
+		s.startListenersLocked()
+	}
+	return s.snapshotLocked(), nil
+}
+
+// Cleanup stops the listeners.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopListenersLocked()
+}
+
+func (s *Service) startListenersLocked() {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: s.artNetPort})
+	if err != nil {
+		log.Warn("cannot listen for fader wing Art-Net", "port", s.artNetPort, "error", err)
+	} else {
+		s.artNet = conn
+		go s.listen(conn, artnet.ParseDMXPacket, true)
+	}
+
+	joined := make(map[int]bool)
+	for _, m := range s.config.Mappings {
+		if joined[m.Universe] {
+			continue
+		}
+		joined[m.Universe] = true
+
+		group := sacn.MulticastAddr(m.Universe)
+		group.Port = s.sacnPort
+		conn, err := net.ListenMulticastUDP("udp4", nil, group)
+		if err != nil {
+			log.Warn("cannot join sACN universe", "universe", m.Universe, "error", err)
+			continue
+		}
+		s.sacn = append(s.sacn, conn)
+		go s.listen(conn, sacn.ParseDataPacket, false)
+	}
+	log.Info("🎚️ Fader wing input listening", "mappings", len(s.config.Mappings))
+}
+
+func (s *Service) stopListenersLocked() {
+	if s.artNet != nil {
+		_ = s.artNet.Close()
+		s.artNet = nil
+	}
+	for _, conn := range s.sacn {
+		_ = conn.Close()
+	}
+	s.sacn = nil
+}
+
+func (s *Service) listen(conn *net.UDPConn, parse func([]byte) (int, []byte, error), isArtNet bool) {
+	buffer := make([]byte, 1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return // Listener closed
+		}
+		universe, channels, err := parse(buffer[:n])
+		if err != nil {
+			continue // Polls, sync packets, and other traffic
+		}
+		if !s.handleDMX(universe, channels, from) || !isArtNet {
+			continue
+		}
+
+		s.mu.Lock()
+		onArtNet := s.onArtNet
+		s.mu.Unlock()
+		if onArtNet != nil {
+			onArtNet()
+		}
+	}
+}
+
+// dispatch is a pending handler call.
+type dispatch struct {
+	handler  Handler
+	targetID string
+	level    float64
+}
+
+// handleDMX applies the mapped channels of a received universe that changed
+// since the last packet. It reports false for this server's own output.
+func (s *Service) handleDMX(universe int, channels []byte, from *net.UDPAddr) bool {
+	s.mu.Lock()
+	if s.ignore != nil && from != nil && s.ignore(from) {
+		s.mu.Unlock()
+		return false
+	}
+
+	s.packets++
+	s.lastPacketAt = s.now()
+	if from != nil {
+		s.lastSource = from.String()
+	}
+
+	var calls []dispatch
+	for _, m := range s.config.Mappings {
+		if m.Universe != universe || m.Channel > len(channels) {
+			continue
+		}
+		key := channelKey{m.Universe, m.Channel}
+		value := channels[m.Channel-1]
+		last, seen := s.levels[key]
+		s.levels[key] = value
+		if (seen && last == value) || (!seen && value == 0) {
+			continue
+		}
+		if handler := s.handlers[m.Target]; handler != nil {
+			calls = append(calls, dispatch{handler, m.TargetID, float64(value) / 255})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, call := range calls {
+		call.handler(call.targetID, call.level)
+	}
+	return true
+}
+
+func (s *Service) snapshotLocked() *Status {
+	status := &Status{
+		Config:          s.config,
+		Listening:       s.artNet != nil,
+		PacketsReceived: s.packets,
+	}
+	status.Config.Mappings = append([]Mapping{}, s.config.Mappings...)
+	for target := range s.handlers {
+		status.AvailableTargets = append(status.AvailableTargets, target)
+	}
+	sort.Slice(status.AvailableTargets, func(i, j int) bool {
+		return status.AvailableTargets[i] < status.AvailableTargets[j]
+	})
+	if s.packets > 0 {
+		lastPacketAt := s.lastPacketAt
+		status.LastPacketAt = &lastPacketAt
+		if s.lastSource != "" {
+			lastSource := s.lastSource
+			status.LastSource = &lastSource
+		}
+	}
+	return status
+}
@@ -0,0 +1,186 @@
+package input
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+// recorder collects handler calls.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+	level map[string]float64
+}
+
+func newRecorder() *recorder {
+	return &recorder{level: make(map[string]float64)}
+}
+
+func (r *recorder) handler(targetID string, level float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, targetID)
+	r.level[targetID] = level
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+	}{
+		{"universe zero", Mapping{Universe: 0, Channel: 1, Target: TargetGrandMaster}},
+		{"channel out of range", Mapping{Universe: 1, Channel: 513, Target: TargetGrandMaster}},
+		{"unknown target", Mapping{Universe: 1, Channel: 1, Target: "HOUSE_LIGHTS"}},
+		{"grand master with ID", Mapping{Universe: 1, Channel: 1, Target: TargetGrandMaster, TargetID: "x"}},
+		{"button without ID", Mapping{Universe: 1, Channel: 1, Target: TargetSceneBoardButton}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Mappings: []Mapping{tt.mapping}}
+			if err := config.Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	duplicate := Config{Mappings: []Mapping{
+		{Universe: 5, Channel: 1, Target: TargetSceneBoardButton, TargetID: "a"},
+		{Universe: 5, Channel: 1, Target: TargetSceneBoardButton, TargetID: "b"},
+	}}
+	if err := duplicate.Validate(); err == nil {
+		t.Error("Expected error for a channel mapped twice")
+	}
+}
+
+func TestSetConfigRequiresHandler(t *testing.T) {
+	s := NewService(6590)
+	defer s.Cleanup()
+	s.SetHandler(TargetSceneBoardButton, newRecorder().handler)
+
+	_, err := s.SetConfig(Config{Mappings: []Mapping{{Universe: 1, Channel: 1, Target: TargetGrandMaster}}})
+	if err == nil {
+		t.Error("Expected error for a target without a handler")
+	}
+
+	status, err := s.SetConfig(Config{Mappings: []Mapping{{Universe: 1, Channel: 1, Target: TargetSceneBoardButton, TargetID: "b1"}}})
+	if err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if status.Listening {
+		t.Error("Expected no listener while disabled")
+	}
+	if len(status.AvailableTargets) != 1 || status.AvailableTargets[0] != TargetSceneBoardButton {
+		t.Errorf("AvailableTargets = %v, want [SCENE_BOARD_BUTTON]", status.AvailableTargets)
+	}
+}
+
+func TestHandleDMXDispatchesChanges(t *testing.T) {
+	s := NewService(6591)
+	defer s.Cleanup()
+	buttons := newRecorder()
+	s.SetHandler(TargetSceneBoardButton, buttons.handler)
+
+	if _, err := s.SetConfig(Config{Mappings: []Mapping{
+		{Universe: 9, Channel: 1, Target: TargetSceneBoardButton, TargetID: "b1"},
+		{Universe: 9, Channel: 2, Target: TargetSceneBoardButton, TargetID: "b2"},
+	}}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+
+	channels := make([]byte, 512)
+	channels[0] = 255
+
+	// Faders down on the first packet are not applied
+	s.handleDMX(9, channels, nil)
+	if buttons.count() != 1 || buttons.level["b1"] != 1 {
+		t.Fatalf("Expected only b1 at full, got calls %v levels %v", buttons.calls, buttons.level)
+	}
+
+	// Unchanged values and other universes are ignored
+	s.handleDMX(9, channels, nil)
+	s.handleDMX(1, channels, nil)
+	if buttons.count() != 1 {
+		t.Errorf("Expected no calls for unchanged values, got %v", buttons.calls)
+	}
+
+	channels[0] = 0
+	channels[1] = 51
+	s.handleDMX(9, channels, nil)
+	if buttons.count() != 3 || buttons.level["b1"] != 0 || buttons.level["b2"] != 0.2 {
+		t.Errorf("Expected b1 at 0 and b2 at 0.2, got levels %v", buttons.level)
+	}
+
+	if status := s.Status(); status.PacketsReceived != 4 || status.LastPacketAt == nil {
+		t.Errorf("Expected 4 packets counted, got %+v", status)
+	}
+}
+
+func TestArtNetInput(t *testing.T) {
+	const testPort = 6592
+	s := NewService(testPort)
+	s.sacnPort = 6593
+	defer s.Cleanup()
+
+	levels := make(chan float64, 4)
+	s.SetHandler(TargetSceneBoardButton, func(targetID string, level float64) {
+		levels <- level
+	})
+
+	status, err := s.SetConfig(Config{Enabled: true, Mappings: []Mapping{
+		{Universe: 16, Channel: 10, Target: TargetSceneBoardButton, TargetID: "b1"},
+	}})
+	if err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if !status.Listening {
+		t.Fatal("Expected Art-Net listener")
+	}
+
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort})
+	if err != nil {
+		t.Fatalf("Failed to dial listener: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Our own output is ignored
+	s.SetIgnoreSource(func(from *net.UDPAddr) bool {
+		return from.Port == conn.LocalAddr().(*net.UDPAddr).Port
+	})
+	channels := make([]byte, 512)
+	channels[9] = 128
+	if _, err := conn.Write(artnet.BuildDMXPacket(16, channels, 1)); err != nil {
+		t.Fatalf("Failed to send ArtDmx: %v", err)
+	}
+	select {
+	case level := <-levels:
+		t.Fatalf("Own output should be ignored, got level %v", level)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.SetIgnoreSource(nil)
+	if _, err := conn.Write(artnet.BuildDMXPacket(16, channels, 2)); err != nil {
+		t.Fatalf("Failed to send ArtDmx: %v", err)
+	}
+	select {
+	case level := <-levels:
+		if level != 128.0/255 {
+			t.Errorf("level = %v, want %v", level, 128.0/255)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for fader wing input")
+	}
+
+	if status := s.Status(); status.LastSource == nil {
+		t.Error("Expected last source to be recorded")
+	}
+}
@@ -0,0 +1,5 @@
+package input
+
+import "github.com/bbernstein/lacylights-go/internal/services/logging"
+
+var log = logging.For(logging.ModuleInput)
@@ -0,0 +1,251 @@
+// Package integrity finds records of a project that reference records which
+// no longer exist, and removes them on request.
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"gorm.io/gorm"
+)
+
+// IssueType is a kind of broken reference. Each has one repair: deleting
+// the records with the broken reference.
+type IssueType string
+
+const (
+	// IssueCueMissingScene is a cue playing a scene not in its project
+	IssueCueMissingScene IssueType = "CUE_MISSING_SCENE"
+	// IssueFixtureValueMissingFixture is a scene's value for a fixture not
+	// in its project
+	IssueFixtureValueMissingFixture IssueType = "FIXTURE_VALUE_MISSING_FIXTURE"
+	// IssueModeChannelMissingChannel is a channel of a fixture mode that is
+	// not a channel of its definition
+	IssueModeChannelMissingChannel IssueType = "MODE_CHANNEL_MISSING_CHANNEL"
+)
+
+// IssueTypes are every issue type, in the order they are reported.
+var IssueTypes = []IssueType{IssueCueMissingScene, IssueFixtureValueMissingFixture, IssueModeChannelMissingChannel}
+
+// Issue is a record with a broken reference.
+type Issue struct {
+	Type IssueType
+	// EntityID is the record with the broken reference
+	EntityID string
+	// EntityName names the record, or what it belongs to
+	EntityName string
+	// ParentID is the cue list, scene or fixture mode the record belongs to
+	ParentID string
+	// MissingID is the record referenced
+	MissingID string
+	Message   string
+}
+
+// Service checks and repairs projects.
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService creates an integrity service.
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// Validate returns the project's broken references. Mode channels are
+// checked for the definitions the project's fixtures use.
+func (s *Service) Validate(ctx context.Context, projectID string) ([]Issue, error) {
+	return validate(s.db.WithContext(ctx), projectID)
+}
+
+// Repair deletes the records with broken references of the given types in
+// one transaction, returning the issues repaired.
+func (s *Service) Repair(ctx context.Context, projectID string, fixes []IssueType) ([]Issue, error) {
+	selected := make(map[IssueType]bool, len(fixes))
+	for _, fix := range fixes {
+		if !validType(fix) {
+			return nil, fmt.Errorf("unknown issue type %q", fix)
+		}
+		selected[fix] = true
+	}
+
+	var repaired []Issue
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		issues, err := validate(tx, projectID)
+		if err != nil {
+			return err
+		}
+		ids := make(map[IssueType][]string)
+		for _, issue := range issues {
+			if selected[issue.Type] {
+				ids[issue.Type] = append(ids[issue.Type], issue.EntityID)
+				repaired = append(repaired, issue)
+			}
+		}
+		deletes := []struct {
+			issueType IssueType
+			model     interface{}
+		}{
+			{IssueCueMissingScene, &models.Cue{}},
+			{IssueFixtureValueMissingFixture, &models.FixtureValue{}},
+			{IssueModeChannelMissingChannel, &models.ModeChannel{}},
+		}
+		for _, d := range deletes {
+			if len(ids[d.issueType]) == 0 {
+				continue
+			}
+			if err := tx.Where("id IN ?", ids[d.issueType]).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repaired, nil
+}
+
+func validType(t IssueType) bool {
+	for _, known := range IssueTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func validate(db *gorm.DB, projectID string) ([]Issue, error) {
+	var project models.Project
+	if err := db.Select("id").Where("id = ?", projectID).Limit(1).Find(&project).Error; err != nil {
+		return nil, err
+	}
+	if project.ID == "" {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	var issues []Issue
+	for _, find := range []func(*gorm.DB, string) ([]Issue, error){cuesMissingScenes, fixtureValuesMissingFixtures, modeChannelsMissingChannels} {
+		found, err := find(db, projectID)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// cuesMissingScenes finds cues whose scene is deleted or in another project.
+func cuesMissingScenes(db *gorm.DB, projectID string) ([]Issue, error) {
+	var rows []struct {
+		ID          string
+		Name        string
+		CueNumber   float64
+		SceneID     string
+		CueListID   string
+		CueListName string
+	}
+	err := db.Table("cues").
+		Select("cues.id, cues.name, cues.cue_number, cues.scene_id, cues.cue_list_id, cue_lists.name AS cue_list_name").
+		Joins("JOIN cue_lists ON cue_lists.id = cues.cue_list_id").
+		Where("cue_lists.project_id = ?", projectID).
+		Where("cues.scene_id NOT IN (?)", db.Table("scenes").Select("id").Where("project_id = ?", projectID)).
+		Order("cue_lists.name, cues.cue_number").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(rows))
+	for i, row := range rows {
+		name := fmt.Sprintf("%s cue %g", row.CueListName, row.CueNumber)
+		issues[i] = Issue{
+			Type:       IssueCueMissingScene,
+			EntityID:   row.ID,
+			EntityName: name,
+			ParentID:   row.CueListID,
+			MissingID:  row.SceneID,
+			Message:    fmt.Sprintf("%s (%s) plays scene %s, which is not in the project", name, row.Name, row.SceneID),
+		}
+	}
+	return issues, nil
+}
+
+// fixtureValuesMissingFixtures finds scene values for fixtures that are
+// deleted or in another project.
+func fixtureValuesMissingFixtures(db *gorm.DB, projectID string) ([]Issue, error) {
+	var rows []struct {
+		ID        string
+		FixtureID string
+		SceneID   string
+		SceneName string
+	}
+	err := db.Table("fixture_values").
+		Select("fixture_values.id, fixture_values.fixture_id, fixture_values.scene_id, scenes.name AS scene_name").
+		Joins("JOIN scenes ON scenes.id = fixture_values.scene_id").
+		Where("scenes.project_id = ?", projectID).
+		Where("fixture_values.fixture_id NOT IN (?)", db.Table("fixture_instances").Select("id").Where("project_id = ?", projectID)).
+		Order("scenes.name, fixture_values.fixture_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(rows))
+	for i, row := range rows {
+		issues[i] = Issue{
+			Type:       IssueFixtureValueMissingFixture,
+			EntityID:   row.ID,
+			EntityName: row.SceneName,
+			ParentID:   row.SceneID,
+			MissingID:  row.FixtureID,
+			Message:    fmt.Sprintf("Scene %s has values for fixture %s, which is not in the project", row.SceneName, row.FixtureID),
+		}
+	}
+	return issues, nil
+}
+
+// modeChannelsMissingChannels finds channels of the project's fixture
+// modes that are not channels of the mode's definition.
+func modeChannelsMissingChannels(db *gorm.DB, projectID string) ([]Issue, error) {
+	var rows []struct {
+		ID        string
+		ChannelID string
+		ModeID    string
+		Offset    int
+		ModeName  string
+		Model     string
+	}
+	err := db.Table("mode_channels").
+		Select(`mode_channels.id, mode_channels.channel_id, mode_channels.mode_id, mode_channels."offset", fixture_modes.name AS mode_name, fixture_definitions.model`).
+		Joins("JOIN fixture_modes ON fixture_modes.id = mode_channels.mode_id").
+		Joins("JOIN fixture_definitions ON fixture_definitions.id = fixture_modes.definition_id").
+		Where("fixture_modes.definition_id IN (?)", db.Table("fixture_instances").Select("definition_id").Where("project_id = ?", projectID)).
+		Where("NOT EXISTS (?)", db.Table("channel_definitions").Select("1").
+			Where("channel_definitions.id = mode_channels.channel_id AND channel_definitions.definition_id = fixture_modes.definition_id")).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Model != rows[j].Model {
+			return rows[i].Model < rows[j].Model
+		}
+		if rows[i].ModeName != rows[j].ModeName {
+			return rows[i].ModeName < rows[j].ModeName
+		}
+		return rows[i].Offset < rows[j].Offset
+	})
+	issues := make([]Issue, len(rows))
+	for i, row := range rows {
+		name := fmt.Sprintf("%s %s", row.Model, row.ModeName)
+		issues[i] = Issue{
+			Type:       IssueModeChannelMissingChannel,
+			EntityID:   row.ID,
+			EntityName: name,
+			ParentID:   row.ModeID,
+			MissingID:  row.ChannelID,
+			Message:    fmt.Sprintf("Mode %s offset %d is channel %s, which is not in the definition", name, row.Offset, row.ChannelID),
+		}
+	}
+	return issues, nil
+}
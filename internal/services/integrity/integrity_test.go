@@ -0,0 +1,122 @@
+package integrity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+// createBrokenProject stores a project with one broken reference of each
+// type, alongside records that are sound.
+func createBrokenProject(t *testing.T, testDB *testutil.TestDB) {
+	t.Helper()
+
+	records := []interface{}{
+		&models.Project{ID: "show", Name: "Show"},
+		&models.Project{ID: "other", Name: "Other"},
+		&models.FixtureDefinition{ID: "par-def", Manufacturer: "Test", Model: "Par"},
+		&models.ChannelDefinition{ID: "par-dimmer", Name: "Dimmer", Type: "INTENSITY", DefinitionID: "par-def"},
+		&models.ChannelDefinition{ID: "other-red", Name: "Red", Type: "RED", DefinitionID: "other-def"},
+		&models.FixtureMode{ID: "par-mode", Name: "2ch", DefinitionID: "par-def"},
+		&models.ModeChannel{ID: "par-mode-0", ModeID: "par-mode", ChannelID: "par-dimmer", Offset: 0},
+		&models.ModeChannel{ID: "par-mode-1", ModeID: "par-mode", ChannelID: "deleted-channel", Offset: 1},
+		&models.ModeChannel{ID: "par-mode-2", ModeID: "par-mode", ChannelID: "other-red", Offset: 2},
+		&models.FixtureInstance{ID: "par-1", Name: "Par 1", ProjectID: "show", DefinitionID: "par-def", Universe: 1, StartChannel: 1},
+		&models.FixtureInstance{ID: "other-par", Name: "Other Par", ProjectID: "other", DefinitionID: "par-def", Universe: 1, StartChannel: 1},
+		&models.Scene{ID: "look", Name: "Look", ProjectID: "show"},
+		&models.Scene{ID: "other-look", Name: "Other Look", ProjectID: "other"},
+		&models.FixtureValue{ID: "look-par-1", SceneID: "look", FixtureID: "par-1"},
+		&models.FixtureValue{ID: "look-deleted", SceneID: "look", FixtureID: "deleted-fixture"},
+		&models.FixtureValue{ID: "look-other", SceneID: "look", FixtureID: "other-par"},
+		&models.CueList{ID: "act-one", Name: "Act One", ProjectID: "show"},
+		&models.Cue{ID: "cue-1", Name: "Opening", CueNumber: 1, CueListID: "act-one", SceneID: "look"},
+		&models.Cue{ID: "cue-2", Name: "Storm", CueNumber: 2, CueListID: "act-one", SceneID: "deleted-scene"},
+		&models.Cue{ID: "cue-3", Name: "Borrowed", CueNumber: 2.5, CueListID: "act-one", SceneID: "other-look"},
+	}
+	for _, record := range records {
+		if err := testDB.DB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create %T: %v", record, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	createBrokenProject(t, testDB)
+	service := NewService(testDB.DB)
+
+	issues, err := service.Validate(context.Background(), "show")
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	want := []struct {
+		issueType IssueType
+		entityID  string
+		missingID string
+	}{
+		{IssueCueMissingScene, "cue-2", "deleted-scene"},
+		{IssueCueMissingScene, "cue-3", "other-look"},
+		{IssueFixtureValueMissingFixture, "look-deleted", "deleted-fixture"},
+		{IssueFixtureValueMissingFixture, "look-other", "other-par"},
+		{IssueModeChannelMissingChannel, "par-mode-1", "deleted-channel"},
+		{IssueModeChannelMissingChannel, "par-mode-2", "other-red"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %+v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Type != w.issueType || issues[i].EntityID != w.entityID || issues[i].MissingID != w.missingID {
+			t.Errorf("Issue %d = %+v, want %s %s -> %s", i, issues[i], w.issueType, w.entityID, w.missingID)
+		}
+	}
+	if issues[0].EntityName != "Act One cue 2" {
+		t.Errorf("Expected the cue named by list and number, got %q", issues[0].EntityName)
+	}
+
+	if _, err := service.Validate(context.Background(), "missing"); err == nil {
+		t.Error("Expected an error for a missing project")
+	}
+}
+
+func TestRepair(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	createBrokenProject(t, testDB)
+	service := NewService(testDB.DB)
+	ctx := context.Background()
+
+	repaired, err := service.Repair(ctx, "show", []IssueType{IssueCueMissingScene, IssueFixtureValueMissingFixture})
+	if err != nil {
+		t.Fatalf("Repair() error: %v", err)
+	}
+	if len(repaired) != 4 {
+		t.Errorf("Expected four issues repaired, got %+v", repaired)
+	}
+
+	var cues []models.Cue
+	testDB.DB.Find(&cues)
+	if len(cues) != 1 || cues[0].ID != "cue-1" {
+		t.Errorf("Expected only the sound cue left, got %+v", cues)
+	}
+	var values []models.FixtureValue
+	testDB.DB.Find(&values)
+	if len(values) != 1 || values[0].ID != "look-par-1" {
+		t.Errorf("Expected only the sound fixture value left, got %+v", values)
+	}
+
+	// Fixes that were not selected are left
+	issues, err := service.Validate(ctx, "show")
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Type != IssueModeChannelMissingChannel {
+		t.Errorf("Expected the mode channel issues left, got %+v", issues)
+	}
+
+	if _, err := service.Repair(ctx, "show", []IssueType{"DELETE_EVERYTHING"}); err == nil {
+		t.Error("Expected an unknown issue type to be rejected")
+	}
+}
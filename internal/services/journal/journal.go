@@ -0,0 +1,372 @@
+// Package journal provides a small append-only write-ahead journal for
+// playback-critical runtime state. It lives in its own file beside the main
+// database so the live state survives a crash even when a database write has
+// not landed yet.
+//
+// State is a set of keyed JSON values grouped by kind (for example the active
+// cue of each cue list). Every change is appended as one JSON line; reopening
+// the journal replays the lines, drops a torn final line, and rewrites the
+// file as a compact snapshot.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SyncMode controls when journal writes are flushed to stable storage.
+type SyncMode string
+
+const (
+	// SyncAlways fsyncs after every record. Nothing acknowledged is lost.
+	SyncAlways SyncMode = "always"
+	// SyncInterval fsyncs pending records periodically, losing at most one
+	// interval of changes on power failure.
+	SyncInterval SyncMode = "interval"
+	// SyncNever leaves flushing to the operating system. Records survive a
+	// process crash but not a power failure.
+	SyncNever SyncMode = "never"
+)
+
+// DefaultSyncInterval is the flush period for SyncInterval.
+const DefaultSyncInterval = 200 * time.Millisecond
+
+// compactMinRecords is the number of appended records before the journal
+// considers compacting itself.
+const compactMinRecords = 1000
+
+// ParseSyncMode converts a configuration string to a SyncMode.
+func ParseSyncMode(value string) (SyncMode, error) {
+	switch mode := SyncMode(value); mode {
+	case SyncAlways, SyncInterval, SyncNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid journal sync mode %q (want always, interval, or never)", value)
+	}
+}
+
+// record is one line of the journal file.
+type record struct {
+	Seq     int64           `json:"seq"`
+	Time    time.Time       `json:"time"`
+	Kind    string          `json:"kind"`
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+// Journal is an open state journal. It is safe for concurrent use.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	mode SyncMode
+	file *os.File
+
+	state   map[string]map[string]json.RawMessage
+	seq     int64
+	records int // Records in the file since the last compaction
+	dirty   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Open opens or creates the journal at path and loads its state. For
+// SyncInterval a zero interval uses DefaultSyncInterval.
+func Open(path string, mode SyncMode, interval time.Duration) (*Journal, error) {
+	if _, err := ParseSyncMode(string(mode)); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	j := &Journal{
+		path:  path,
+		mode:  mode,
+		state: make(map[string]map[string]json.RawMessage),
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compactLocked(); err != nil {
+		return nil, err
+	}
+
+	if mode == SyncInterval {
+		if interval <= 0 {
+			interval = DefaultSyncInterval
+		}
+		j.stop = make(chan struct{})
+		j.done = make(chan struct{})
+		go j.syncLoop(interval)
+	}
+	return j, nil
+}
+
+// load replays the journal file into memory. Lines after the first corrupt
+// one are ignored: appends are sequential, so only the tail can be torn.
+func (j *Journal) load() error {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Kind == "" {
+			log.Warn("discarding journal records after an incomplete write", "path", j.path, "line", line)
+			break
+		}
+		j.apply(rec)
+		if rec.Seq > j.seq {
+			j.seq = rec.Seq
+		}
+	}
+	return nil
+}
+
+func (j *Journal) apply(rec record) {
+	if rec.Deleted {
+		if values := j.state[rec.Kind]; values != nil {
+			delete(values, rec.Key)
+			if len(values) == 0 {
+				delete(j.state, rec.Kind)
+			}
+		}
+		return
+	}
+	values := j.state[rec.Kind]
+	if values == nil {
+		values = make(map[string]json.RawMessage)
+		j.state[rec.Kind] = values
+	}
+	values[rec.Key] = rec.Value
+}
+
+// Put records value as the current state of kind/key. Writing the value the
+// journal already holds is a no-op.
+func (j *Journal) Put(kind, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal value: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if current, ok := j.state[kind][key]; ok && bytes.Equal(current, data) {
+		return nil
+	}
+	return j.appendLocked(record{Kind: kind, Key: key, Value: data})
+}
+
+// Delete removes kind/key from the state.
+func (j *Journal) Delete(kind, key string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.state[kind][key]; !ok {
+		return nil
+	}
+	return j.appendLocked(record{Kind: kind, Key: key, Deleted: true})
+}
+
+// Get decodes the value of kind/key into out. It reports false if the key is
+// not present.
+func (j *Journal) Get(kind, key string, out any) (bool, error) {
+	j.mu.Lock()
+	data, ok := j.state[kind][key]
+	j.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return true, fmt.Errorf("invalid journal value for %s/%s: %w", kind, key, err)
+	}
+	return true, nil
+}
+
+// Entries returns the raw values of kind by key.
+func (j *Journal) Entries(kind string) map[string]json.RawMessage {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries := make(map[string]json.RawMessage, len(j.state[kind]))
+	for key, value := range j.state[kind] {
+		entries[key] = value
+	}
+	return entries
+}
+
+// Sync flushes appended records to stable storage.
+func (j *Journal) Sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.syncLocked()
+}
+
+// Close flushes and closes the journal.
+func (j *Journal) Close() error {
+	if j.stop != nil {
+		close(j.stop)
+		<-j.done
+		j.stop = nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.syncLocked()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file = nil
+	return err
+}
+
+func (j *Journal) appendLocked(rec record) error {
+	if j.file == nil {
+		return fmt.Errorf("journal is closed")
+	}
+
+	j.seq++
+	rec.Seq = j.seq
+	rec.Time = time.Now().UTC()
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal record: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	j.apply(rec)
+	j.records++
+	j.dirty = true
+
+	if j.mode == SyncAlways {
+		if err := j.syncLocked(); err != nil {
+			return err
+		}
+	}
+
+	if j.records >= compactMinRecords && j.records > 4*j.liveLocked() {
+		return j.compactLocked()
+	}
+	return nil
+}
+
+func (j *Journal) syncLocked() error {
+	if j.file == nil || !j.dirty {
+		return nil
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+	j.dirty = false
+	return nil
+}
+
+func (j *Journal) liveLocked() int {
+	live := 0
+	for _, values := range j.state {
+		live += len(values)
+	}
+	return live
+}
+
+// compactLocked rewrites the journal as one record per live key. The
+// snapshot is written to a temporary file and renamed over the journal, so a
+// crash during compaction leaves either the old or the new file intact.
+func (j *Journal) compactLocked() error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create journal snapshot: %w", err)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	now := time.Now().UTC()
+	for kind, values := range j.state {
+		for key, value := range values {
+			j.seq++
+			line, err := json.Marshal(record{Seq: j.seq, Time: now, Kind: kind, Key: key, Value: value})
+			if err == nil {
+				_, err = writer.Write(append(line, '\n'))
+			}
+			if err != nil {
+				_ = tmp.Close()
+				return fmt.Errorf("failed to write journal snapshot: %w", err)
+			}
+		}
+	}
+	err = writer.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write journal snapshot: %w", err)
+	}
+
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	syncDir(filepath.Dir(j.path))
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	j.file = file
+	j.records = 0
+	j.dirty = false
+	return nil
+}
+
+func (j *Journal) syncLoop(interval time.Duration) {
+	defer close(j.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-ticker.C:
+			if err := j.Sync(); err != nil {
+				log.Warn("failed to sync the journal", "path", j.path, "error", err)
+			}
+		}
+	}
+}
+
+// syncDir makes a rename in dir durable. Errors are ignored since not every
+// platform supports syncing a directory.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
@@ -0,0 +1,158 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type cueState struct {
+	CueID string `json:"cueId"`
+}
+
+func TestParseSyncMode(t *testing.T) {
+	for _, value := range []string{"always", "interval", "never"} {
+		if _, err := ParseSyncMode(value); err != nil {
+			t.Errorf("ParseSyncMode(%q) error: %v", value, err)
+		}
+	}
+	if _, err := ParseSyncMode("sometimes"); err == nil {
+		t.Error("Expected error for unknown sync mode")
+	}
+}
+
+func TestJournalReopenRestoresState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+	j, err := Open(path, SyncAlways, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+
+	if err := j.Put("cue", "list-1", cueState{CueID: "a"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if err := j.Put("cue", "list-1", cueState{CueID: "b"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if err := j.Put("cue", "list-2", cueState{CueID: "c"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if err := j.Delete("cue", "list-2"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := j.Put("level", "gm", 0.5); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	// Simulate a crash: no Close, the file handle is simply abandoned
+	reopened, err := Open(path, SyncNever, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	_ = j.file.Close()
+
+	var cue cueState
+	if ok, err := reopened.Get("cue", "list-1", &cue); !ok || err != nil || cue.CueID != "b" {
+		t.Errorf("Get(list-1) = %v, %v, %+v; want cue b", ok, err, cue)
+	}
+	if ok, _ := reopened.Get("cue", "list-2", &cue); ok {
+		t.Error("Expected deleted key to stay deleted")
+	}
+	var level float64
+	if ok, _ := reopened.Get("level", "gm", &level); !ok || level != 0.5 {
+		t.Errorf("Get(gm) = %v, want 0.5", level)
+	}
+	if n := len(reopened.Entries("cue")); n != 1 {
+		t.Errorf("Entries(cue) has %d keys, want 1", n)
+	}
+
+	// Reopening compacts to one record per live key
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Compacted journal has %d records, want 2", lines)
+	}
+}
+
+func TestJournalDiscardsTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+	j, err := Open(path, SyncAlways, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if err := j.Put("cue", "list-1", cueState{CueID: "a"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+	_, _ = f.WriteString(`{"seq":9,"kind":"cue","key":"list-1","value":{"cueId":"b`)
+	_ = f.Close()
+
+	reopened, err := Open(path, SyncAlways, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	var cue cueState
+	if ok, _ := reopened.Get("cue", "list-1", &cue); !ok || cue.CueID != "a" {
+		t.Errorf("Expected the last complete record to win, got %+v", cue)
+	}
+	if err := reopened.Put("cue", "list-1", cueState{CueID: "c"}); err != nil {
+		t.Fatalf("Put() after recovery error: %v", err)
+	}
+}
+
+func TestJournalSkipsUnchangedValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+	j, err := Open(path, SyncInterval, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+
+	for i := 0; i < 3; i++ {
+		if err := j.Put("level", "gm", 1.0); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+	if err := j.Delete("level", "missing"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if j.records != 1 {
+		t.Errorf("Expected 1 appended record, got %d", j.records)
+	}
+}
+
+func TestJournalCompactsWhenLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+	j, err := Open(path, SyncNever, 0)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+
+	for i := 0; i < compactMinRecords+10; i++ {
+		if err := j.Put("level", "gm", i); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+	if j.records >= compactMinRecords {
+		t.Errorf("Expected the journal to compact, %d records since compaction", j.records)
+	}
+
+	var level int
+	if ok, _ := j.Get("level", "gm", &level); !ok || level != compactMinRecords+9 {
+		t.Errorf("Get(gm) = %d after compaction, want %d", level, compactMinRecords+9)
+	}
+}
@@ -0,0 +1,197 @@
+package legacy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Table is the rows of one table in a database dump. A nil value is NULL.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]*string
+}
+
+// Value returns a row's value for a column, or nil if the column is NULL or
+// not in the dump.
+func (t *Table) Value(row []*string, column string) *string {
+	for i, name := range t.Columns {
+		if name == column && i < len(row) {
+			return row[i]
+		}
+	}
+	return nil
+}
+
+// ReadDump reads the table data of a plain-format pg_dump, as written by
+// pg_dump's default COPY statements. Tables are keyed by their unqualified
+// name.
+func ReadDump(r io.Reader) (map[string]*Table, error) {
+	tables := make(map[string]*Table)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var table *Table
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if table == nil {
+			if !strings.HasPrefix(text, "COPY ") {
+				continue
+			}
+			t, err := parseCopy(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			table = t
+			if existing, ok := tables[t.Name]; ok {
+				table = existing
+			} else {
+				tables[t.Name] = t
+			}
+			continue
+		}
+		if text == `\.` {
+			table = nil
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != len(table.Columns) {
+			return nil, fmt.Errorf("line %d: expected %d columns for %s, got %d", line, len(table.Columns), table.Name, len(fields))
+		}
+		row := make([]*string, len(fields))
+		for i, field := range fields {
+			if field == `\N` {
+				continue
+			}
+			value := unescapeCopy(field)
+			row[i] = &value
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+	if table != nil {
+		return nil, fmt.Errorf("dump ends inside the data for %s", table.Name)
+	}
+	return tables, nil
+}
+
+// parseCopy parses the header of a COPY block:
+//
+//	COPY public.fixture_values (id, scene_id, "channelValues") FROM stdin;
+func parseCopy(text string) (*Table, error) {
+	rest := strings.TrimPrefix(text, "COPY ")
+	open := strings.Index(rest, "(")
+	end := strings.LastIndex(rest, ") FROM stdin;")
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("unsupported COPY statement: %s", text)
+	}
+
+	name := strings.TrimSpace(rest[:open])
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	table := &Table{Name: unquoteIdent(name)}
+	for _, column := range strings.Split(rest[open+1:end], ",") {
+		table.Columns = append(table.Columns, unquoteIdent(strings.TrimSpace(column)))
+	}
+	return table, nil
+}
+
+// unquoteIdent removes the quotes from a quoted SQL identifier.
+func unquoteIdent(ident string) string {
+	if len(ident) >= 2 && ident[0] == '"' && ident[len(ident)-1] == '"' {
+		return strings.ReplaceAll(ident[1:len(ident)-1], `""`, `"`)
+	}
+	return ident
+}
+
+// unescapeCopy decodes a value in COPY text format.
+func unescapeCopy(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i+1 == len(field) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch field[i] {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x':
+			j := i + 1
+			for j < len(field) && j < i+3 && isHex(field[j]) {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('x')
+				continue
+			}
+			n, _ := strconv.ParseUint(field[i+1:j], 16, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i
+			for j < len(field) && j < i+3 && field[j] >= '0' && field[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(field[i:j], 8, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// DumpDatabase reads the table data of a live Postgres database by running
+// pg_dump, which must be on the PATH.
+func DumpDatabase(ctx context.Context, databaseURL string) (map[string]*Table, error) {
+	cmd := exec.CommandContext(ctx, "pg_dump", "--data-only", "--no-owner", "--no-privileges", "--dbname", databaseURL)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run pg_dump: %w", err)
+	}
+
+	tables, readErr := ReadDump(stdout)
+	if readErr != nil {
+		// Let pg_dump exit rather than block on a full pipe
+		_, _ = io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("pg_dump failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return tables, readErr
+}
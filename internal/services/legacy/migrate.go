@@ -0,0 +1,448 @@
+// Package legacy migrates projects from a lacylights-node database into the
+// Go schema. The Node server's Prisma schema maps onto the same tables, so
+// records keep their IDs and a migration can be run again to pick up
+// changes made in the Node database since.
+package legacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+)
+
+// migratedModels are the records a migration copies, parents first.
+var migratedModels = []interface{}{
+	&models.FixtureDefinition{},
+	&models.ChannelDefinition{},
+	&models.FixtureMode{},
+	&models.ModeChannel{},
+	&models.Project{},
+	&models.FixtureInstance{},
+	&models.InstanceChannel{},
+	&models.Scene{},
+	&models.FixtureValue{},
+	&models.CueList{},
+	&models.Cue{},
+}
+
+// Options configures a migration.
+type Options struct {
+	// Rerun updates records copied by an earlier migration from the source.
+	// Without it a record that already exists fails the migration.
+	Rerun bool
+}
+
+// TableStats counts the records a migration wrote to a table.
+type TableStats struct {
+	Table   string
+	Created int
+	Updated int
+	Skipped int
+}
+
+// Result is what a migration did.
+type Result struct {
+	Tables   []TableStats
+	Warnings []string
+}
+
+// Migrator copies lacylights-node data into a Go database.
+type Migrator struct {
+	db *gorm.DB
+}
+
+// NewMigrator creates a migrator writing to a database.
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Migrate copies the projects, fixtures, scenes and cues in a dump into a
+// database with the current schema. It writes everything or, on error,
+// nothing.
+func (m *Migrator) Migrate(ctx context.Context, tables map[string]*Table, opts Options) (*Result, error) {
+	if tables["projects"] == nil {
+		return nil, fmt.Errorf("no projects found to migrate")
+	}
+
+	result := &Result{}
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run := &migration{
+			tx:            tx,
+			opts:          opts,
+			result:        result,
+			definitionIDs: make(map[string]string),
+			skipped:       make(map[string]bool),
+		}
+		for _, model := range migratedModels {
+			if err := run.migrateTable(ctx, model, tables); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// migration is the state of one run.
+type migration struct {
+	tx     *gorm.DB
+	opts   Options
+	result *Result
+
+	// Source definitions replaced by a definition already in the database
+	definitionIDs map[string]string
+	// IDs of records not copied, so their children are not either
+	skipped map[string]bool
+}
+
+// migrateTable copies the rows of a model's table.
+func (m *migration) migrateTable(ctx context.Context, model interface{}, tables map[string]*Table) error {
+	stmt := &gorm.Statement{DB: m.tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	stats := TableStats{Table: stmt.Schema.Table}
+	defer func() { m.result.Tables = append(m.result.Tables, stats) }()
+
+	table := tables[stmt.Schema.Table]
+	if table == nil {
+		m.result.Warnings = append(m.result.Warnings, fmt.Sprintf("no %s found to migrate", stmt.Schema.Table))
+		return nil
+	}
+
+	fields, columns := matchColumns(stmt.Schema, table)
+	if stmt.Schema.LookUpField("id") == nil || !contains(columns, "id") {
+		return fmt.Errorf("%s has no id column", table.Name)
+	}
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column != "id" {
+			updates = append(updates, column)
+		}
+	}
+
+	records := make([]interface{}, 0, len(table.Rows))
+	ids := make([]string, 0, len(table.Rows))
+	for i, row := range table.Rows {
+		record, err := decodeRow(ctx, stmt.Schema, fields, table, row)
+		if err != nil {
+			return fmt.Errorf("%s row %d: %w", table.Name, i+1, err)
+		}
+		records = append(records, record)
+		ids = append(ids, reflect.ValueOf(record).Elem().FieldByName("ID").String())
+	}
+	existing, err := m.existingIDs(model, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, record := range records {
+		id := ids[i]
+		if !existing[id] && m.skip(record) {
+			m.skipped[id] = true
+			stats.Skipped++
+			continue
+		}
+		m.remap(record)
+
+		if !existing[id] {
+			if err := m.tx.Create(record).Error; err != nil {
+				return fmt.Errorf("failed to create %s %s: %w", table.Name, id, err)
+			}
+			stats.Created++
+			continue
+		}
+		if !m.opts.Rerun {
+			return fmt.Errorf("%s %s was already migrated; run the migration in re-run mode to update it", table.Name, id)
+		}
+		// Only the columns the source has are updated
+		upsert := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns(updates),
+		}
+		if err := m.tx.Select(columns).Clauses(upsert).Create(record).Error; err != nil {
+			return fmt.Errorf("failed to update %s %s: %w", table.Name, id, err)
+		}
+		stats.Updated++
+	}
+	return nil
+}
+
+// existingIDs finds which of a table's records are already in the database.
+func (m *migration) existingIDs(model interface{}, ids []string) (map[string]bool, error) {
+	const batchSize = 500
+
+	existing := make(map[string]bool)
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var found []string
+		if err := m.tx.Model(model).Where("id IN ?", ids[start:end]).Pluck("id", &found).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range found {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// skip reports whether a new record is left out. A fixture definition the
+// database already has, from the built-in library or an import, is used in
+// place of the source's copy, along with its channels and modes.
+func (m *migration) skip(record interface{}) bool {
+	switch r := record.(type) {
+	case *models.FixtureDefinition:
+		var def models.FixtureDefinition
+		err := m.tx.Where("manufacturer = ? AND model = ?", r.Manufacturer, r.Model).First(&def).Error
+		if err != nil {
+			return false
+		}
+		m.definitionIDs[r.ID] = def.ID
+		m.result.Warnings = append(m.result.Warnings, fmt.Sprintf("Using the existing fixture definition for %s %s", r.Manufacturer, r.Model))
+		return true
+	case *models.ChannelDefinition:
+		return m.skipped[r.DefinitionID]
+	case *models.FixtureMode:
+		return m.skipped[r.DefinitionID]
+	case *models.ModeChannel:
+		return m.skipped[r.ModeID]
+	}
+	return false
+}
+
+// remap points a record at the definition used in place of its own.
+func (m *migration) remap(record interface{}) {
+	if fixture, ok := record.(*models.FixtureInstance); ok {
+		if id, ok := m.definitionIDs[fixture.DefinitionID]; ok {
+			fixture.DefinitionID = id
+		}
+	}
+}
+
+// matchColumns matches a dumped table's columns to model fields by name, in
+// either the snake_case of the Go schema or Prisma's camelCase. Columns the
+// Go schema does not have are ignored. It also returns the database columns
+// the dump sets.
+func matchColumns(s *schema.Schema, table *Table) ([]*schema.Field, []string) {
+	fields := make([]*schema.Field, len(table.Columns))
+	var columns []string
+	for i, column := range table.Columns {
+		field := s.LookUpField(snakeCase(column))
+		if field == nil || field.DBName == "" || contains(columns, field.DBName) {
+			continue
+		}
+		fields[i] = field
+		columns = append(columns, field.DBName)
+	}
+	if s.Table == "fixture_values" && contains(table.Columns, "channelValues") && !contains(columns, "channels") {
+		columns = append(columns, "channels")
+	}
+	return fields, columns
+}
+
+// decodeRow converts a dumped row into a model.
+func decodeRow(ctx context.Context, s *schema.Schema, fields []*schema.Field, table *Table, row []*string) (interface{}, error) {
+	rv := reflect.New(s.ModelType)
+	for i, field := range fields {
+		if field == nil || row[i] == nil {
+			continue
+		}
+		if err := setField(field.ReflectValueOf(ctx, rv.Elem()), *row[i], field.DefaultValue == "[]"); err != nil {
+			return nil, fmt.Errorf("column %s: %w", table.Columns[i], err)
+		}
+	}
+
+	// Older Node databases keep scene values as a dense array
+	if value, ok := rv.Interface().(*models.FixtureValue); ok && value.Channels == "" {
+		if legacy := table.Value(row, "channelValues"); legacy != nil {
+			channels, err := sparseChannels(*legacy)
+			if err != nil {
+				return nil, fmt.Errorf("column channelValues: %w", err)
+			}
+			value.Channels = channels
+		}
+	}
+	return rv.Interface(), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// setField parses a dumped value into a model field. JSON array fields also
+// accept a Postgres array.
+func setField(target reflect.Value, raw string, jsonArray bool) error {
+	if target.Kind() == reflect.Ptr {
+		value := reflect.New(target.Type().Elem())
+		if err := setField(value.Elem(), raw, jsonArray); err != nil {
+			return err
+		}
+		target.Set(value)
+		return nil
+	}
+
+	if target.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := parseTimestamp(raw)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		if jsonArray && strings.HasPrefix(raw, "{") {
+			elements, err := parseArray(raw)
+			if err != nil {
+				return err
+			}
+			data, _ := json.Marshal(elements)
+			raw = string(data)
+		}
+		target.SetString(raw)
+	case reflect.Bool:
+		target.SetBool(raw == "t" || raw == "true")
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", target.Type())
+	}
+	return nil
+}
+
+// timestampLayouts are the forms Postgres writes timestamps in. Prisma's
+// timestamps have no time zone and are in UTC.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+}
+
+func parseTimestamp(raw string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s", raw)
+}
+
+// parseArray parses a one-dimensional Postgres array, such as
+// {Front,"Side Wash"}. NULL elements are dropped.
+func parseArray(raw string) ([]string, error) {
+	if len(raw) < 2 || raw[0] != '{' || raw[len(raw)-1] != '}' {
+		return nil, fmt.Errorf("invalid array: %s", raw)
+	}
+	body := raw[1 : len(raw)-1]
+	elements := []string{}
+	for i := 0; i < len(body); {
+		var element strings.Builder
+		quoted := body[i] == '"'
+		if quoted {
+			i++
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				element.WriteByte(body[i])
+			}
+			if i == len(body) {
+				return nil, fmt.Errorf("invalid array: %s", raw)
+			}
+			i++
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				element.WriteByte(body[i])
+			}
+		}
+		if quoted || element.String() != "NULL" {
+			elements = append(elements, element.String())
+		}
+		if i < len(body) && body[i] != ',' {
+			return nil, fmt.Errorf("invalid array: %s", raw)
+		}
+		i++
+	}
+	return elements, nil
+}
+
+// sparseChannels converts a dense array of channel values, indexed by
+// offset, to the sparse JSON the Go schema stores.
+func sparseChannels(raw string) (string, error) {
+	var values []int
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &values); err != nil {
+			return "", err
+		}
+	} else {
+		elements, err := parseArray(raw)
+		if err != nil {
+			return "", err
+		}
+		for _, element := range elements {
+			n, err := strconv.Atoi(element)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, n)
+		}
+	}
+
+	channels := make([]models.ChannelValue, len(values))
+	for i, v := range values {
+		channels[i] = models.ChannelValue{Offset: i, Value: v}
+	}
+	data, err := json.Marshal(channels)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// snakeCase converts a camelCase column name to snake_case.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
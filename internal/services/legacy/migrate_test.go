@@ -0,0 +1,259 @@
+package legacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+// nodeDump is a pg_dump of a small lacylights-node database. The existing
+// definition is also in the Go database; the scene values use the older
+// dense array.
+const nodeDump = `--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+
+COPY public.projects (id, name, description, created_at, updated_at) FROM stdin;
+proj-1	Spring Show	Opening\tnight	2024-03-01 19:30:00.123	2024-03-02 08:00:00
+\.
+
+COPY public.fixture_definitions (id, manufacturer, model, type, is_built_in, created_at, updated_at) FROM stdin;
+def-new	NodeMfg	Par 1	LED_PAR	f	2024-03-01 19:30:00	2024-03-01 19:30:00
+def-existing	GoMfg	Dimmer	DIMMER	t	2024-03-01 19:30:00	2024-03-01 19:30:00
+\.
+
+COPY public.channel_definitions (id, name, type, "offset", min_value, max_value, default_value, definition_id) FROM stdin;
+ch-new	Intensity	INTENSITY	0	0	255	0	def-new
+ch-existing	Intensity	INTENSITY	0	0	255	0	def-existing
+\.
+
+COPY public.fixture_modes (id, name, "shortName", "channelCount", "definitionId") FROM stdin;
+mode-new	1ch	\N	1	def-new
+mode-existing	1ch	\N	1	def-existing
+\.
+
+COPY public.mode_channels (id, mode_id, channel_id, "offset") FROM stdin;
+mc-new	mode-new	ch-new	0
+mc-existing	mode-existing	ch-existing	0
+\.
+
+COPY public.fixture_instances (id, name, description, definition_id, manufacturer, model, type, mode_name, channel_count, project_id, universe, start_channel, tags, created_at, updated_at) FROM stdin;
+fix-1	Front	\N	def-new	NodeMfg	Par 1	LED_PAR	1ch	1	proj-1	1	1	{front,"stage left"}	2024-03-01 19:30:00	2024-03-01 19:30:00
+fix-2	House	\N	def-existing	GoMfg	Dimmer	DIMMER	1ch	1	proj-1	1	2	{}	2024-03-01 19:30:00	2024-03-01 19:30:00
+\.
+
+COPY public.instance_channels (id, fixture_id, "offset", name, type, min_value, max_value, default_value) FROM stdin;
+ic-1	fix-1	0	Intensity	INTENSITY	0	255	0
+ic-2	fix-2	0	Intensity	INTENSITY	0	255	0
+\.
+
+COPY public.scenes (id, name, description, project_id, created_at, updated_at) FROM stdin;
+scene-1	Warm Wash	\N	proj-1	2024-03-01 19:30:00	2024-03-01 19:30:00
+\.
+
+COPY public.fixture_values (id, scene_id, fixture_id, "channelValues", scene_order) FROM stdin;
+fv-1	scene-1	fix-1	{255}	0
+fv-2	scene-1	fix-2	{128}	1
+\.
+
+COPY public.cue_lists (id, name, description, loop, project_id, created_at, updated_at) FROM stdin;
+list-1	Main	\N	f	proj-1	2024-03-01 19:30:00	2024-03-01 19:30:00
+\.
+
+COPY public.cues (id, name, cue_number, cue_list_id, scene_id, fade_in_time, fade_out_time, follow_time, easing_type, notes, created_at, updated_at) FROM stdin;
+cue-1	Preset	1	list-1	scene-1	3	2.5	\N	\N	House to half	2024-03-01 19:30:00	2024-03-01 19:30:00
+\.
+
+COPY public.users (id, email) FROM stdin;
+user-1	admin@example.com
+\.
+`
+
+func TestReadDump(t *testing.T) {
+	tables, err := ReadDump(strings.NewReader(nodeDump))
+	if err != nil {
+		t.Fatalf("ReadDump failed: %v", err)
+	}
+	if len(tables) != 12 {
+		t.Errorf("Expected 12 tables, got %d", len(tables))
+	}
+
+	projects := tables["projects"]
+	if projects == nil || len(projects.Rows) != 1 {
+		t.Fatalf("Expected 1 project, got %+v", projects)
+	}
+	if desc := projects.Value(projects.Rows[0], "description"); desc == nil || *desc != "Opening\tnight" {
+		t.Errorf("Expected the escaped tab to be decoded, got %v", desc)
+	}
+	modes := tables["fixture_modes"]
+	if modes.Columns[3] != "channelCount" {
+		t.Errorf("Expected quoted column names to be unquoted, got %v", modes.Columns)
+	}
+	if modes.Value(modes.Rows[0], "shortName") != nil {
+		t.Error("Expected \\N to be NULL")
+	}
+}
+
+func TestReadDump_Invalid(t *testing.T) {
+	for name, dump := range map[string]string{
+		"wrong column count": "COPY public.projects (id, name) FROM stdin;\nproj-1\n\\.\n",
+		"unterminated data":  "COPY public.projects (id, name) FROM stdin;\nproj-1\tShow\n",
+		"bad COPY statement": "COPY public.projects FROM stdin;\n\\.\n",
+	} {
+		if _, err := ReadDump(strings.NewReader(dump)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseArray(t *testing.T) {
+	tests := map[string][]string{
+		`{}`:                     {},
+		`{front,back}`:           {"front", "back"},
+		`{"stage left",NULL,""}`: {"stage left", ""},
+		`{"say \"hi\"",x}`:       {`say "hi"`, "x"},
+	}
+	for raw, want := range tests {
+		got, err := parseArray(raw)
+		if err != nil {
+			t.Errorf("parseArray(%s) failed: %v", raw, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+			t.Errorf("parseArray(%s) = %q, want %q", raw, got, want)
+		}
+	}
+	if _, err := parseArray(`{"open`); err == nil {
+		t.Error("Expected an error for an unterminated element")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	existing := &models.FixtureDefinition{ID: "go-dimmer", Manufacturer: "GoMfg", Model: "Dimmer", Type: "DIMMER"}
+	if err := testDB.DB.Create(existing).Error; err != nil {
+		t.Fatalf("Failed to create definition: %v", err)
+	}
+
+	tables, err := ReadDump(strings.NewReader(nodeDump))
+	if err != nil {
+		t.Fatalf("ReadDump failed: %v", err)
+	}
+	migrator := NewMigrator(testDB.DB)
+	result, err := migrator.Migrate(ctx, tables, Options{})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	counts := make(map[string]TableStats)
+	for _, stats := range result.Tables {
+		counts[stats.Table] = stats
+	}
+	if counts["fixture_definitions"].Created != 1 || counts["fixture_definitions"].Skipped != 1 {
+		t.Errorf("Expected 1 definition created and 1 skipped, got %+v", counts["fixture_definitions"])
+	}
+	if counts["mode_channels"].Created != 1 || counts["mode_channels"].Skipped != 1 {
+		t.Errorf("Expected the existing definition's mode channels to be skipped, got %+v", counts["mode_channels"])
+	}
+	if counts["cues"].Created != 1 {
+		t.Errorf("Expected 1 cue created, got %+v", counts["cues"])
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected a warning for the existing definition, got %v", result.Warnings)
+	}
+
+	project, err := testDB.ProjectRepo.FindByID(ctx, "proj-1")
+	if err != nil || project == nil {
+		t.Fatalf("Expected the project to be migrated: %v", err)
+	}
+	if project.Description == nil || *project.Description != "Opening\tnight" {
+		t.Errorf("Unexpected description %v", project.Description)
+	}
+	if project.CreatedAt.Format("2006-01-02 15:04:05.000") != "2024-03-01 19:30:00.123" {
+		t.Errorf("Expected the creation time to be kept, got %v", project.CreatedAt)
+	}
+
+	var house models.FixtureInstance
+	testDB.DB.First(&house, "id = ?", "fix-2")
+	if house.DefinitionID != existing.ID {
+		t.Errorf("Expected the fixture to use the existing definition, got %s", house.DefinitionID)
+	}
+	var front models.FixtureInstance
+	testDB.DB.First(&front, "id = ?", "fix-1")
+	if front.Tags == nil || *front.Tags != `["front","stage left"]` {
+		t.Errorf("Expected tags as a JSON array, got %v", front.Tags)
+	}
+
+	var value models.FixtureValue
+	testDB.DB.First(&value, "id = ?", "fv-2")
+	if value.Channels != `[{"offset":0,"value":128}]` {
+		t.Errorf("Expected sparse channel values, got %s", value.Channels)
+	}
+
+	cue, err := testDB.CueRepo.FindByID(ctx, "cue-1")
+	if err != nil || cue == nil {
+		t.Fatalf("Expected the cue to be migrated: %v", err)
+	}
+	if cue.FadeOutTime != 2.5 || cue.Notes == nil || *cue.Notes != "House to half" || cue.FollowTime != nil {
+		t.Errorf("Unexpected cue %+v", cue)
+	}
+
+	// Migrating again needs re-run mode, and writes nothing without it
+	changed := strings.Replace(nodeDump, "cue-1\tPreset", "cue-1\tPreset Renamed", 1)
+	changed = strings.Replace(changed, "COPY public.cue_lists (id, name, description, loop, project_id, created_at, updated_at) FROM stdin;\n",
+		"COPY public.cue_lists (id, name, description, loop, project_id, created_at, updated_at) FROM stdin;\nlist-2\tEncore\t\\N\tt\tproj-1\t2024-03-03 10:00:00\t2024-03-03 10:00:00\n", 1)
+	tables, err = ReadDump(strings.NewReader(changed))
+	if err != nil {
+		t.Fatalf("ReadDump failed: %v", err)
+	}
+	if _, err := migrator.Migrate(ctx, tables, Options{}); err == nil {
+		t.Fatal("Expected migrating twice without re-run mode to fail")
+	}
+	if list, _ := testDB.CueListRepo.FindByID(ctx, "list-2"); list != nil {
+		t.Error("Expected a failed migration to write nothing")
+	}
+
+	result, err = migrator.Migrate(ctx, tables, Options{Rerun: true})
+	if err != nil {
+		t.Fatalf("Re-run failed: %v", err)
+	}
+	for _, stats := range result.Tables {
+		counts[stats.Table] = stats
+	}
+	if counts["cue_lists"].Created != 1 || counts["cue_lists"].Updated != 1 {
+		t.Errorf("Expected 1 cue list created and 1 updated, got %+v", counts["cue_lists"])
+	}
+	if counts["fixture_definitions"].Skipped != 1 || counts["fixture_definitions"].Updated != 1 {
+		t.Errorf("Expected 1 definition updated and 1 skipped, got %+v", counts["fixture_definitions"])
+	}
+	cue, _ = testDB.CueRepo.FindByID(ctx, "cue-1")
+	if cue == nil || cue.Name != "Preset Renamed" {
+		t.Errorf("Expected the cue to be updated, got %+v", cue)
+	}
+	list, _ := testDB.CueListRepo.FindByID(ctx, "list-2")
+	if list == nil || !list.Loop {
+		t.Errorf("Expected the new cue list to be migrated, got %+v", list)
+	}
+	var fixtures int64
+	testDB.DB.Model(&models.FixtureInstance{}).Count(&fixtures)
+	if fixtures != 2 {
+		t.Errorf("Expected re-running to keep 2 fixtures, got %d", fixtures)
+	}
+}
+
+func TestMigrate_NoProjects(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	if _, err := NewMigrator(testDB.DB).Migrate(context.Background(), map[string]*Table{}, Options{}); err == nil {
+		t.Error("Expected an error for a dump without projects")
+	}
+}
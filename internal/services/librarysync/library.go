@@ -0,0 +1,141 @@
+// Package librarysync shares fixture definitions and template projects
+// between LacyLights instances.
+package librarysync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+)
+
+// LibraryFormat identifies the fixture library document format.
+const LibraryFormat = "lacylights-fixture-library/1"
+
+// LibraryPath is the HTTP path where a server publishes its fixture library.
+const LibraryPath = "/library/fixtures.json"
+
+// Library is a published set of fixture definitions and template projects.
+type Library struct {
+	Format      string              `json:"format"`
+	GeneratedAt time.Time           `json:"generatedAt"`
+	Definitions []LibraryDefinition `json:"definitions"`
+	Templates   []LibraryTemplate   `json:"templates,omitempty"`
+}
+
+// LibraryDefinition is a fixture definition with its version information.
+type LibraryDefinition struct {
+	export.ExportedFixtureDefinition
+	// Version is a content hash; equal versions mean identical definitions.
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LibraryTemplate is a project published as a starting point for new shows.
+type LibraryTemplate struct {
+	Name        string                  `json:"name"`
+	Description *string                 `json:"description,omitempty"`
+	Project     *export.ExportedProject `json:"project"`
+}
+
+// ParseLibrary decodes a fixture library document.
+func ParseLibrary(data []byte) (*Library, error) {
+	var library Library
+	if err := json.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("invalid fixture library: %w", err)
+	}
+	if library.Format != LibraryFormat {
+		return nil, fmt.Errorf("unsupported fixture library format: %q", library.Format)
+	}
+	return &library, nil
+}
+
+// Template returns the template with the given name, or nil.
+func (l *Library) Template(name string) *LibraryTemplate {
+	for i := range l.Templates {
+		if l.Templates[i].Name == name {
+			return &l.Templates[i]
+		}
+	}
+	return nil
+}
+
+// versionedChannel and versionedMode are the parts of a definition that
+// contribute to its version. Database IDs are excluded so the same
+// definition hashes identically on every server.
+type versionedChannel struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Offset       int    `json:"offset"`
+	MinValue     int    `json:"minValue"`
+	MaxValue     int    `json:"maxValue"`
+	DefaultValue int    `json:"defaultValue"`
+	FadeBehavior string `json:"fadeBehavior"`
+	IsDiscrete   bool   `json:"isDiscrete"`
+	Cell         *int   `json:"cell,omitempty"` // Omitted for master channels, keeping older hashes
+}
+
+type versionedMode struct {
+	Name         string   `json:"name"`
+	ShortName    *string  `json:"shortName"`
+	ChannelCount int      `json:"channelCount"`
+	Channels     []string `json:"channels"` // Channel names in offset order
+}
+
+// DefinitionVersion computes the content hash of a fixture definition.
+func DefinitionVersion(def *export.ExportedFixtureDefinition) string {
+	channelNames := make(map[string]string, len(def.Channels))
+	channels := make([]versionedChannel, len(def.Channels))
+	for i, ch := range def.Channels {
+		channelNames[ch.RefID] = ch.Name
+		channels[i] = versionedChannel{
+			Name:         ch.Name,
+			Type:         ch.Type,
+			Offset:       ch.Offset,
+			MinValue:     ch.MinValue,
+			MaxValue:     ch.MaxValue,
+			DefaultValue: ch.DefaultValue,
+			FadeBehavior: ch.FadeBehavior,
+			IsDiscrete:   ch.IsDiscrete,
+			Cell:         ch.Cell,
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Offset != channels[j].Offset {
+			return channels[i].Offset < channels[j].Offset
+		}
+		return channels[i].Name < channels[j].Name
+	})
+
+	modes := make([]versionedMode, len(def.Modes))
+	for i, mode := range def.Modes {
+		modeChannels := make([]export.ExportedModeChannel, len(mode.ModeChannels))
+		copy(modeChannels, mode.ModeChannels)
+		sort.Slice(modeChannels, func(a, b int) bool { return modeChannels[a].Offset < modeChannels[b].Offset })
+
+		names := make([]string, len(modeChannels))
+		for j, mc := range modeChannels {
+			names[j] = channelNames[mc.ChannelRefID]
+		}
+		modes[i] = versionedMode{
+			Name:         mode.Name,
+			ShortName:    mode.ShortName,
+			ChannelCount: mode.ChannelCount,
+			Channels:     names,
+		}
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i].Name < modes[j].Name })
+
+	data, _ := json.Marshal(struct {
+		Type     string             `json:"type"`
+		Channels []versionedChannel `json:"channels"`
+		Modes    []versionedMode    `json:"modes"`
+	}{def.Type, channels, modes})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
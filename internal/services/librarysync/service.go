@@ -0,0 +1,513 @@
+package librarysync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/lucsky/cuid"
+	"gorm.io/gorm"
+)
+
+// maxLibrarySize limits how much a remote library download may read.
+const maxLibrarySize = 64 << 20
+
+// DefinitionStatus compares a library definition with the local library.
+type DefinitionStatus string
+
+const (
+	// StatusNew means no local definition has the same manufacturer and model.
+	StatusNew DefinitionStatus = "NEW"
+	// StatusUpToDate means the local definition is identical.
+	StatusUpToDate DefinitionStatus = "UP_TO_DATE"
+	// StatusRemoteNewer means the definitions differ and the library copy changed last.
+	StatusRemoteNewer DefinitionStatus = "REMOTE_NEWER"
+	// StatusLocalNewer means the definitions differ and the local copy changed last.
+	StatusLocalNewer DefinitionStatus = "LOCAL_NEWER"
+)
+
+// DefinitionKey identifies a fixture definition across servers.
+type DefinitionKey struct {
+	Manufacturer string
+	Model        string
+}
+
+// DefinitionComparison describes how one library definition relates to the local library.
+type DefinitionComparison struct {
+	Manufacturer    string
+	Model           string
+	Type            string
+	Status          DefinitionStatus
+	RemoteVersion   string
+	RemoteUpdatedAt time.Time
+	LocalVersion    *string
+	LocalUpdatedAt  *time.Time
+}
+
+// SyncOptions selects what to import from a library.
+type SyncOptions struct {
+	// Definitions to import; nil imports every NEW and REMOTE_NEWER definition.
+	Definitions []DefinitionKey
+	// Names of template projects to import as new projects.
+	Templates []string
+	// OverwriteLocalChanges also replaces selected LOCAL_NEWER definitions.
+	OverwriteLocalChanges bool
+}
+
+// SyncResult summarizes a library import.
+type SyncResult struct {
+	Created            int
+	Updated            int
+	Skipped            int
+	TemplateProjectIDs []string
+	Warnings           []string
+}
+
+// Service publishes the local fixture library and imports from remote ones.
+type Service struct {
+	db            *gorm.DB
+	fixtureRepo   *repositories.FixtureRepository
+	exportService *export.Service
+	importService *importservice.Service
+	client        *http.Client
+
+	mu        sync.Mutex
+	authorize func(r *http.Request, projectIDs []string) error
+}
+
+// NewService creates a new library sync service.
+func NewService(db *gorm.DB, fixtureRepo *repositories.FixtureRepository, exportService *export.Service, importService *importservice.Service) *Service {
+	return &Service{
+		db:            db,
+		fixtureRepo:   fixtureRepo,
+		exportService: exportService,
+		importService: importService,
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetAuthorizer sets the check a request for the library, with the template
+// projects it asks for, must pass to be served (optional).
+func (s *Service) SetAuthorizer(authorize func(r *http.Request, projectIDs []string) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorize = authorize
+}
+
+// BuildLibrary exports every local fixture definition, plus the given
+// projects as templates.
+func (s *Service) BuildLibrary(ctx context.Context, templateProjectIDs []string) (*Library, error) {
+	defs, err := s.fixtureRepo.FindAllDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	library := &Library{
+		Format:      LibraryFormat,
+		GeneratedAt: time.Now().UTC(),
+		Definitions: make([]LibraryDefinition, 0, len(defs)),
+	}
+	for i := range defs {
+		exported, err := s.exportDefinition(ctx, &defs[i])
+		if err != nil {
+			return nil, err
+		}
+		library.Definitions = append(library.Definitions, LibraryDefinition{
+			ExportedFixtureDefinition: *exported,
+			Version:                   DefinitionVersion(exported),
+			UpdatedAt:                 defs[i].UpdatedAt.UTC(),
+		})
+	}
+
+	for _, projectID := range templateProjectIDs {
+		project, _, err := s.exportService.ExportProject(ctx, projectID, true, true, true, true)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, fmt.Errorf("project not found: %s", projectID)
+		}
+		library.Templates = append(library.Templates, LibraryTemplate{
+			Name:        project.GetProjectName(),
+			Description: project.GetProjectDescription(),
+			Project:     project,
+		})
+	}
+
+	return library, nil
+}
+
+// ServeLibrary publishes the local library as JSON. Projects listed in the
+// comma-separated templateProjectIds query parameter are included as templates.
+func (s *Service) ServeLibrary(w http.ResponseWriter, r *http.Request) {
+	var templateIDs []string
+	if param := r.URL.Query().Get("templateProjectIds"); param != "" {
+		for _, id := range strings.Split(param, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				templateIDs = append(templateIDs, id)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	authorize := s.authorize
+	s.mu.Unlock()
+	if authorize != nil {
+		if err := authorize(r, templateIDs); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	library, err := s.BuildLibrary(r.Context(), templateIDs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(library); err != nil {
+		log.Warn("failed to write fixture library", "error", err)
+	}
+}
+
+// libraryURL resolves a source to a library URL. A bare server address
+// (or its /graphql endpoint) resolves to the server's published library.
+func libraryURL(source string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(source))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid library URL: %s", source)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	path = strings.TrimSuffix(path, "/graphql")
+	if path == "" {
+		u.Path = LibraryPath
+	}
+	return u.String(), nil
+}
+
+// Fetch downloads a library from another LacyLights server or a published library URL.
+func (s *Service) Fetch(ctx context.Context, source string) (*Library, error) {
+	target, err := libraryURL(source)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fixture library: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch fixture library: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLibrarySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture library: %w", err)
+	}
+	return ParseLibrary(data)
+}
+
+// Compare reports the status of each library definition against the local library.
+func (s *Service) Compare(ctx context.Context, library *Library) ([]DefinitionComparison, error) {
+	result := make([]DefinitionComparison, 0, len(library.Definitions))
+	for i := range library.Definitions {
+		comparison, _, err := s.compareDefinition(ctx, &library.Definitions[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *comparison)
+	}
+	return result, nil
+}
+
+// compareDefinition compares one library definition, also returning the
+// matching local definition if there is one.
+func (s *Service) compareDefinition(ctx context.Context, def *LibraryDefinition) (*DefinitionComparison, *models.FixtureDefinition, error) {
+	comparison := &DefinitionComparison{
+		Manufacturer:    def.Manufacturer,
+		Model:           def.Model,
+		Type:            def.Type,
+		Status:          StatusNew,
+		RemoteVersion:   def.Version,
+		RemoteUpdatedAt: def.UpdatedAt,
+	}
+
+	local, err := s.fixtureRepo.FindDefinitionByManufacturerModel(ctx, def.Manufacturer, def.Model)
+	if err != nil {
+		return nil, nil, err
+	}
+	if local == nil {
+		return comparison, nil, nil
+	}
+
+	exported, err := s.exportDefinition(ctx, local)
+	if err != nil {
+		return nil, nil, err
+	}
+	localVersion := DefinitionVersion(exported)
+	localUpdatedAt := local.UpdatedAt.UTC()
+	comparison.LocalVersion = &localVersion
+	comparison.LocalUpdatedAt = &localUpdatedAt
+
+	// Recompute rather than trusting the published version
+	switch {
+	case DefinitionVersion(&def.ExportedFixtureDefinition) == localVersion:
+		comparison.Status = StatusUpToDate
+	case localUpdatedAt.After(def.UpdatedAt):
+		comparison.Status = StatusLocalNewer
+	default:
+		comparison.Status = StatusRemoteNewer
+	}
+	return comparison, local, nil
+}
+
+// Sync imports the selected definitions and templates from a library.
+func (s *Service) Sync(ctx context.Context, library *Library, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{TemplateProjectIDs: []string{}, Warnings: []string{}}
+
+	selected := make(map[DefinitionKey]bool)
+	for _, key := range opts.Definitions {
+		selected[key] = true
+	}
+
+	for i := range library.Definitions {
+		def := &library.Definitions[i]
+		key := DefinitionKey{Manufacturer: def.Manufacturer, Model: def.Model}
+		if opts.Definitions != nil {
+			if !selected[key] {
+				continue
+			}
+			delete(selected, key)
+		}
+
+		comparison, local, err := s.compareDefinition(ctx, def)
+		if err != nil {
+			return nil, err
+		}
+
+		switch comparison.Status {
+		case StatusNew:
+			if err := s.saveDefinition(ctx, def, nil); err != nil {
+				return nil, fmt.Errorf("failed to import %s %s: %w", def.Manufacturer, def.Model, err)
+			}
+			result.Created++
+		case StatusLocalNewer:
+			if !opts.OverwriteLocalChanges {
+				if opts.Definitions != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("Skipped %s %s: local definition has newer changes", def.Manufacturer, def.Model))
+				}
+				result.Skipped++
+				continue
+			}
+			fallthrough
+		case StatusRemoteNewer:
+			if err := s.saveDefinition(ctx, def, local); err != nil {
+				return nil, fmt.Errorf("failed to update %s %s: %w", def.Manufacturer, def.Model, err)
+			}
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	for key := range selected {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Definition not found in library: %s %s", key.Manufacturer, key.Model))
+	}
+
+	for _, name := range opts.Templates {
+		template := library.Template(name)
+		if template == nil || template.Project == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Template not found in library: %s", name))
+			continue
+		}
+
+		content, err := template.Project.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		projectName := template.Name
+		projectID, _, warnings, err := s.importService.ImportProject(ctx, content, importservice.ImportOptions{
+			Mode:                    importservice.ImportModeCreate,
+			ProjectName:             &projectName,
+			FixtureConflictStrategy: importservice.FixtureConflictSkip,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import template %s: %w", name, err)
+		}
+		result.TemplateProjectIDs = append(result.TemplateProjectIDs, projectID)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	return result, nil
+}
+
+// exportDefinition converts a stored definition, with its channels and modes,
+// to the export format.
+func (s *Service) exportDefinition(ctx context.Context, def *models.FixtureDefinition) (*export.ExportedFixtureDefinition, error) {
+	exported := &export.ExportedFixtureDefinition{
+		RefID:        def.ID,
+		Manufacturer: def.Manufacturer,
+		Model:        def.Model,
+		Type:         def.Type,
+		IsBuiltIn:    def.IsBuiltIn,
+		Channels:     []export.ExportedChannelDefinition{},
+	}
+
+	channels, err := s.fixtureRepo.GetDefinitionChannels(ctx, def.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range channels {
+		exported.Channels = append(exported.Channels, export.ExportedChannelDefinition{
+			RefID:        ch.ID,
+			Name:         ch.Name,
+			Type:         ch.Type,
+			Offset:       ch.Offset,
+			MinValue:     ch.MinValue,
+			MaxValue:     ch.MaxValue,
+			DefaultValue: ch.DefaultValue,
+			FadeBehavior: ch.FadeBehavior,
+			IsDiscrete:   ch.IsDiscrete,
+			Cell:         ch.Cell,
+		})
+	}
+
+	modes, err := s.fixtureRepo.GetDefinitionModes(ctx, def.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, mode := range modes {
+		modeChannels, err := s.fixtureRepo.GetModeChannels(ctx, mode.ID)
+		if err != nil {
+			return nil, err
+		}
+		exportedMode := export.ExportedFixtureMode{
+			RefID:        mode.ID,
+			Name:         mode.Name,
+			ShortName:    mode.ShortName,
+			ChannelCount: mode.ChannelCount,
+			ModeChannels: []export.ExportedModeChannel{},
+		}
+		for _, mc := range modeChannels {
+			exportedMode.ModeChannels = append(exportedMode.ModeChannels, export.ExportedModeChannel{
+				ChannelRefID: mc.ChannelID,
+				Offset:       mc.Offset,
+			})
+		}
+		exported.Modes = append(exported.Modes, exportedMode)
+	}
+
+	return exported, nil
+}
+
+// saveDefinition creates a library definition locally, or replaces the
+// channels and modes of an existing one. Existing definitions keep their ID
+// so fixture instances that use them are unaffected.
+func (s *Service) saveDefinition(ctx context.Context, def *LibraryDefinition, existing *models.FixtureDefinition) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var definitionID string
+		if existing != nil {
+			definitionID = existing.ID
+			modeIDs := tx.Model(&models.FixtureMode{}).Select("id").Where("definition_id = ?", definitionID)
+			if err := tx.Where("mode_id IN (?)", modeIDs).Delete(&models.ModeChannel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("definition_id = ?", definitionID).Delete(&models.FixtureMode{}).Error; err != nil {
+				return err
+			}
+			channelIDs := tx.Model(&models.ChannelDefinition{}).Select("id").Where("definition_id = ?", definitionID)
+			if err := tx.Where("channel_id IN (?)", channelIDs).Delete(&models.ChannelCapability{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("definition_id = ?", definitionID).Delete(&models.ChannelDefinition{}).Error; err != nil {
+				return err
+			}
+			existing.Type = def.Type
+			if err := tx.Save(existing).Error; err != nil {
+				return err
+			}
+		} else {
+			definition := &models.FixtureDefinition{
+				ID:           cuid.New(),
+				Manufacturer: def.Manufacturer,
+				Model:        def.Model,
+				Type:         def.Type,
+				IsBuiltIn:    def.IsBuiltIn,
+			}
+			if err := tx.Create(definition).Error; err != nil {
+				return err
+			}
+			definitionID = definition.ID
+		}
+
+		channelIDMap := make(map[string]string) // library refID -> new channel ID
+		for _, ch := range def.Channels {
+			channel := &models.ChannelDefinition{
+				ID:           cuid.New(),
+				Name:         ch.Name,
+				Type:         ch.Type,
+				Offset:       ch.Offset,
+				MinValue:     ch.MinValue,
+				MaxValue:     ch.MaxValue,
+				DefaultValue: ch.DefaultValue,
+				FadeBehavior: ch.FadeBehavior,
+				IsDiscrete:   ch.IsDiscrete,
+				Cell:         ch.Cell,
+				DefinitionID: definitionID,
+			}
+			if channel.FadeBehavior == "" {
+				channel.FadeBehavior = "FADE"
+			}
+			if err := tx.Create(channel).Error; err != nil {
+				return err
+			}
+			channelIDMap[ch.RefID] = channel.ID
+		}
+
+		for _, mode := range def.Modes {
+			fixtureMode := &models.FixtureMode{
+				ID:           cuid.New(),
+				Name:         mode.Name,
+				ShortName:    mode.ShortName,
+				ChannelCount: mode.ChannelCount,
+				DefinitionID: definitionID,
+			}
+			if err := tx.Create(fixtureMode).Error; err != nil {
+				return err
+			}
+			for _, mc := range mode.ModeChannels {
+				channelID, ok := channelIDMap[mc.ChannelRefID]
+				if !ok {
+					return fmt.Errorf("mode %q references unknown channel %q", mode.Name, mc.ChannelRefID)
+				}
+				modeChannel := &models.ModeChannel{
+					ID:        cuid.New(),
+					ModeID:    fixtureMode.ID,
+					ChannelID: channelID,
+					Offset:    mc.Offset,
+				}
+				if err := tx.Create(modeChannel).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
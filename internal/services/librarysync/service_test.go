@@ -0,0 +1,276 @@
+package librarysync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+	"github.com/lucsky/cuid"
+)
+
+func newTestService(testDB *testutil.TestDB) *Service {
+	return NewService(
+		testDB.DB,
+		testDB.FixtureRepo,
+		export.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+		importservice.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+	)
+}
+
+// createDefinition stores a definition with one channel per name and a mode using all of them.
+func createDefinition(t *testing.T, testDB *testutil.TestDB, manufacturer, model string, updatedAt time.Time, channelNames ...string) *models.FixtureDefinition {
+	t.Helper()
+
+	def := &models.FixtureDefinition{ID: cuid.New(), Manufacturer: manufacturer, Model: model, Type: "LED_PAR"}
+	if err := testDB.DB.Create(def).Error; err != nil {
+		t.Fatalf("Failed to create definition: %v", err)
+	}
+
+	mode := &models.FixtureMode{ID: cuid.New(), Name: "Standard", ChannelCount: len(channelNames), DefinitionID: def.ID}
+	if err := testDB.DB.Create(mode).Error; err != nil {
+		t.Fatalf("Failed to create mode: %v", err)
+	}
+	for i, name := range channelNames {
+		ch := &models.ChannelDefinition{ID: cuid.New(), Name: name, Type: "INTENSITY", Offset: i, MaxValue: 255, FadeBehavior: "FADE", DefinitionID: def.ID}
+		if err := testDB.DB.Create(ch).Error; err != nil {
+			t.Fatalf("Failed to create channel: %v", err)
+		}
+		mc := &models.ModeChannel{ID: cuid.New(), ModeID: mode.ID, ChannelID: ch.ID, Offset: i}
+		if err := testDB.DB.Create(mc).Error; err != nil {
+			t.Fatalf("Failed to create mode channel: %v", err)
+		}
+	}
+
+	testDB.DB.Model(def).UpdateColumn("updated_at", updatedAt)
+	return def
+}
+
+// setupLibraries creates a remote server publishing its library and a local database.
+func setupLibraries(t *testing.T) (remote, local *testutil.TestDB, localService *Service, serverURL string) {
+	t.Helper()
+
+	remote, cleanupRemote := testutil.SetupTestDB(t)
+	t.Cleanup(cleanupRemote)
+	local, cleanupLocal := testutil.SetupTestDB(t)
+	t.Cleanup(cleanupLocal)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(LibraryPath, newTestService(remote).ServeLibrary)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return remote, local, newTestService(local), server.URL
+}
+
+func TestCompare(t *testing.T) {
+	remote, local, service, serverURL := setupLibraries(t)
+	ctx := context.Background()
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	createDefinition(t, remote, "Acme", "New Par", newer, "Dimmer")
+	createDefinition(t, remote, "Acme", "Same Par", newer, "Dimmer", "Strobe")
+	createDefinition(t, local, "Acme", "Same Par", older, "Dimmer", "Strobe")
+	createDefinition(t, remote, "Acme", "Changed Par", newer, "Dimmer", "Red")
+	createDefinition(t, local, "Acme", "Changed Par", older, "Dimmer")
+	createDefinition(t, remote, "Acme", "Edited Par", older, "Dimmer")
+	createDefinition(t, local, "Acme", "Edited Par", newer, "Dimmer", "Green")
+
+	library, err := service.Fetch(ctx, serverURL)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	comparisons, err := service.Compare(ctx, library)
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+
+	want := map[string]DefinitionStatus{
+		"New Par":     StatusNew,
+		"Same Par":    StatusUpToDate,
+		"Changed Par": StatusRemoteNewer,
+		"Edited Par":  StatusLocalNewer,
+	}
+	if len(comparisons) != len(want) {
+		t.Fatalf("Expected %d comparisons, got %d", len(want), len(comparisons))
+	}
+	for _, c := range comparisons {
+		if c.Status != want[c.Model] {
+			t.Errorf("%s status = %s, want %s", c.Model, c.Status, want[c.Model])
+		}
+		if c.Status == StatusNew && c.LocalVersion != nil {
+			t.Errorf("%s should have no local version", c.Model)
+		}
+	}
+}
+
+func TestSync_DefaultsToNewAndRemoteNewer(t *testing.T) {
+	remote, local, service, serverURL := setupLibraries(t)
+	ctx := context.Background()
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	createDefinition(t, remote, "Acme", "New Par", newer, "Dimmer")
+	createDefinition(t, remote, "Acme", "Changed Par", newer, "Dimmer", "Red")
+	localChanged := createDefinition(t, local, "Acme", "Changed Par", older, "Dimmer")
+	createDefinition(t, remote, "Acme", "Edited Par", older, "Dimmer")
+	createDefinition(t, local, "Acme", "Edited Par", newer, "Dimmer", "Green")
+
+	library, err := service.Fetch(ctx, serverURL)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	result, err := service.Sync(ctx, library, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Skipped != 1 {
+		t.Errorf("Expected 1 created, 1 updated, 1 skipped; got %+v", result)
+	}
+
+	// Updated definitions keep their ID and take the library's channels
+	updated, _ := local.FixtureRepo.FindDefinitionByManufacturerModel(ctx, "Acme", "Changed Par")
+	if updated == nil || updated.ID != localChanged.ID {
+		t.Fatalf("Expected updated definition to keep ID %s, got %+v", localChanged.ID, updated)
+	}
+	channels, _ := local.FixtureRepo.GetDefinitionChannels(ctx, updated.ID)
+	if len(channels) != 2 {
+		t.Errorf("Expected 2 channels after update, got %d", len(channels))
+	}
+	modes, _ := local.FixtureRepo.GetDefinitionModes(ctx, updated.ID)
+	if len(modes) != 1 {
+		t.Fatalf("Expected 1 mode after update, got %d", len(modes))
+	}
+	modeChannels, _ := local.FixtureRepo.GetModeChannels(ctx, modes[0].ID)
+	if len(modeChannels) != 2 {
+		t.Errorf("Expected 2 mode channels after update, got %d", len(modeChannels))
+	}
+
+	// A second sync finds nothing to do except the locally edited definition
+	comparisons, _ := service.Compare(ctx, library)
+	for _, c := range comparisons {
+		if c.Model != "Edited Par" && c.Status != StatusUpToDate {
+			t.Errorf("%s status after sync = %s, want UP_TO_DATE", c.Model, c.Status)
+		}
+	}
+}
+
+func TestSync_SelectiveAndOverwrite(t *testing.T) {
+	remote, local, service, serverURL := setupLibraries(t)
+	ctx := context.Background()
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	createDefinition(t, remote, "Acme", "New Par", newer, "Dimmer")
+	createDefinition(t, remote, "Acme", "Other Par", newer, "Dimmer")
+	createDefinition(t, remote, "Acme", "Edited Par", older, "Dimmer")
+	createDefinition(t, local, "Acme", "Edited Par", newer, "Dimmer", "Green")
+
+	library, err := service.Fetch(ctx, serverURL)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	selection := []DefinitionKey{
+		{Manufacturer: "Acme", Model: "New Par"},
+		{Manufacturer: "Acme", Model: "Edited Par"},
+		{Manufacturer: "Acme", Model: "Missing Par"},
+	}
+	result, err := service.Sync(ctx, library, SyncOptions{Definitions: selection})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 0 || result.Skipped != 1 {
+		t.Errorf("Expected 1 created and 1 skipped; got %+v", result)
+	}
+	if len(result.Warnings) != 2 {
+		t.Errorf("Expected warnings for the local edit and missing definition, got %v", result.Warnings)
+	}
+	if other, _ := local.FixtureRepo.FindDefinitionByManufacturerModel(ctx, "Acme", "Other Par"); other != nil {
+		t.Error("Unselected definition should not be imported")
+	}
+
+	result, err = service.Sync(ctx, library, SyncOptions{Definitions: selection[1:2], OverwriteLocalChanges: true})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("Expected overwrite to update the local edit, got %+v", result)
+	}
+}
+
+func TestSync_ImportsTemplates(t *testing.T) {
+	remote, local, _, serverURL := setupLibraries(t)
+	ctx := context.Background()
+
+	def := createDefinition(t, remote, "Acme", "Template Par", time.Now(), "Dimmer")
+	project := &models.Project{ID: cuid.New(), Name: "Studio Template"}
+	remote.DB.Create(project)
+	remote.DB.Create(&models.FixtureInstance{ID: cuid.New(), Name: "Par 1", ProjectID: project.ID, DefinitionID: def.ID, Universe: 1, StartChannel: 1})
+
+	service := newTestService(local)
+	library, err := service.Fetch(ctx, serverURL+LibraryPath+"?templateProjectIds="+project.ID)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+	if library.Template("Studio Template") == nil {
+		t.Fatalf("Expected template in library, got %+v", library.Templates)
+	}
+
+	result, err := service.Sync(ctx, library, SyncOptions{Definitions: []DefinitionKey{}, Templates: []string{"Studio Template", "Missing"}})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if len(result.TemplateProjectIDs) != 1 {
+		t.Fatalf("Expected 1 template project, got %v", result.TemplateProjectIDs)
+	}
+	if len(result.Warnings) == 0 {
+		t.Error("Expected warning for missing template")
+	}
+
+	fixtures, _ := local.FixtureRepo.FindByProjectID(ctx, result.TemplateProjectIDs[0])
+	if len(fixtures) != 1 {
+		t.Errorf("Expected template project to have 1 fixture, got %d", len(fixtures))
+	}
+}
+
+func TestLibraryURL(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{"http://venue-a:4000", "http://venue-a:4000" + LibraryPath, false},
+		{"http://venue-a:4000/graphql", "http://venue-a:4000" + LibraryPath, false},
+		{"https://example.com/shared/library.json", "https://example.com/shared/library.json", false},
+		{"ftp://example.com/library.json", "", true},
+		{"not a url", "", true},
+	}
+	for _, tt := range tests {
+		got, err := libraryURL(tt.source)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("libraryURL(%q) error = %v, wantErr %v", tt.source, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("libraryURL(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestParseLibrary_Invalid(t *testing.T) {
+	if _, err := ParseLibrary([]byte("{")); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if _, err := ParseLibrary([]byte(`{"format":"other"}`)); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
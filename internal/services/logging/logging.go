@@ -0,0 +1,460 @@
+// Package logging provides structured module loggers built on log/slog.
+// Each module logs at its own level, so one noisy subsystem can be turned up
+// for diagnosis without flooding the rest. Recent entries are kept in a ring
+// buffer for the in-app diagnostics panel, which can also follow new entries
+// as they are logged.
+//
+// Packages declare their logger once:
+//
+//	var log = logging.For(logging.ModuleDMX)
+//
+// and log with the slog methods, passing details as key-value pairs:
+//
+//	log.Warn("failed to send frame", "universe", universe, "error", err)
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Modules with their own log level.
+const (
+	ModuleServer      = "server"
+	ModuleDatabase    = "database"
+	ModuleGraphQL     = "graphql"
+	ModuleREST        = "rest"
+	ModuleDMX         = "dmx"
+	ModuleFade        = "fade"
+	ModulePlayback    = "playback"
+	ModuleJournal     = "journal"
+	ModuleReplication = "replication"
+	ModuleStandby     = "standby"
+	ModuleScheduler   = "scheduler"
+	ModuleTimecode    = "timecode"
+	ModuleInput       = "input"
+	ModulePreview     = "preview"
+	ModuleDMXStream   = "dmxstream"
+	ModuleExport      = "export"
+	ModuleSnapshot    = "snapshot"
+	ModuleBackup      = "backup"
+	ModuleReport      = "report"
+	ModuleLibrarySync = "librarysync"
+	ModuleOFL         = "ofl"
+	ModuleAudit       = "audit"
+	ModuleAuth        = "auth"
+	ModuleMacro       = "macro"
+	ModuleSchemaInfo  = "schemainfo"
+	ModuleVersion     = "version"
+	ModuleWiFi        = "wifi"
+	ModuleHealth      = "health"
+	ModuleSettings    = "settings"
+	ModuleDiscovery   = "discovery"
+	ModuleStageView   = "stageview"
+	ModuleHouseLights = "houselights"
+	ModuleDMXRecord   = "dmxrecord"
+	ModulePixelMap    = "pixelmap"
+	ModuleAudio       = "audio"
+	ModuleTLS         = "tls"
+	ModuleWebUI       = "webui"
+)
+
+// DefaultBufferSize is the number of recent entries kept by default.
+const DefaultBufferSize = 1000
+
+// Format is the encoding of log output.
+type Format string
+
+const (
+	// FormatText writes key=value lines.
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per line.
+	FormatJSON Format = "json"
+)
+
+// Attr is one key-value detail of an entry, with the value as text. Keys in
+// groups are qualified by the group name, as in "request.id".
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Entry is one logged record.
+type Entry struct {
+	ID      int64 // Increases by one per entry
+	Time    time.Time
+	Level   slog.Level
+	Module  string
+	Message string
+	Attrs   []Attr
+}
+
+// Filter selects buffered entries.
+type Filter struct {
+	Module   string       // Empty matches every module
+	MinLevel slog.Leveler // Nil matches every level
+	AfterID  int64        // Only entries logged after this one
+	Limit    int          // The newest entries up to this many; zero for all
+}
+
+// Matches reports whether the filter selects an entry, ignoring Limit.
+func (f Filter) Matches(entry Entry) bool {
+	return (f.Module == "" || entry.Module == f.Module) &&
+		(f.MinLevel == nil || entry.Level >= f.MinLevel.Level()) &&
+		entry.ID > f.AfterID
+}
+
+// ModuleLevel is the level a module logs at.
+type ModuleLevel struct {
+	Module string
+	Level  slog.Level
+}
+
+// Config configures output, levels and the ring buffer.
+type Config struct {
+	Level      slog.Level            // Level of modules not in Levels
+	Levels     map[string]slog.Level // Per-module levels
+	Format     Format                // Empty is text
+	Output     io.Writer             // Nil is standard error
+	BufferSize int                   // Zero is DefaultBufferSize
+}
+
+// ParseLevel parses debug, info, warn (or warning) or error, in any case.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q (want debug, info, warn, or error)", value)
+}
+
+// ParseLevels parses per-module levels in the form "dmx=debug,fade=warn".
+func ParseLevels(value string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, level, ok := strings.Cut(part, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module log level %q (want module=level)", part)
+		}
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		levels[module] = parsed
+	}
+	return levels, nil
+}
+
+// ParseFormat parses text or json.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatText, FormatJSON:
+		return format, nil
+	case "":
+		return FormatText, nil
+	}
+	return "", fmt.Errorf("invalid log format %q (want text or json)", value)
+}
+
+// registry holds the state shared by every module logger.
+type registry struct {
+	mu           sync.RWMutex
+	defaultLevel slog.Level
+	levels       map[string]slog.Level
+	modules      map[string]bool
+	output       slog.Handler
+
+	// Ring buffer of recent entries: start is the oldest, count the number
+	// held
+	buffer []Entry
+	start  int
+	count  int
+	nextID int64
+
+	subscribers map[int]func(Entry)
+	nextSub     int
+}
+
+func newRegistry() *registry {
+	return &registry{
+		defaultLevel: slog.LevelInfo,
+		levels:       make(map[string]slog.Level),
+		modules:      make(map[string]bool),
+		output:       newOutput(os.Stderr, FormatText),
+		buffer:       make([]Entry, DefaultBufferSize),
+		subscribers:  make(map[int]func(Entry)),
+	}
+}
+
+// std is the registry module loggers share.
+var std = newRegistry()
+
+// newOutput creates the handler that writes entries. Levels are checked
+// before entries reach it.
+func newOutput(w io.Writer, format Format) slog.Handler {
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// Configure sets output, levels and the buffer size. It also routes the
+// standard library log package, and slog's default logger, through the
+// server module.
+func Configure(cfg Config) {
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	std.mu.Lock()
+	std.defaultLevel = cfg.Level
+	std.levels = make(map[string]slog.Level, len(cfg.Levels))
+	for module, level := range cfg.Levels {
+		std.levels[module] = level
+	}
+	std.output = newOutput(output, cfg.Format)
+	std.resizeLocked(size)
+	std.mu.Unlock()
+
+	slog.SetDefault(For(ModuleServer))
+}
+
+// For returns the logger of a module.
+func For(module string) *slog.Logger {
+	std.mu.Lock()
+	std.modules[module] = true
+	std.mu.Unlock()
+	return slog.New(&handler{module: module})
+}
+
+// SetLevel sets the level of a module, or the default level when module is
+// empty.
+func SetLevel(module string, level slog.Level) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if module == "" {
+		std.defaultLevel = level
+		return
+	}
+	std.levels[module] = level
+	std.modules[module] = true
+}
+
+// DefaultLevel returns the level of modules without their own.
+func DefaultLevel() slog.Level {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	return std.defaultLevel
+}
+
+// ModuleLevels returns the level of every module in name order.
+func ModuleLevels() []ModuleLevel {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	levels := make([]ModuleLevel, 0, len(std.modules))
+	for module := range std.modules {
+		levels = append(levels, ModuleLevel{Module: module, Level: std.levelLocked(module)})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Module < levels[j].Module })
+	return levels
+}
+
+// Entries returns the buffered entries a filter selects, oldest first.
+func Entries(filter Filter) []Entry {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	entries := []Entry{}
+	for i := 0; i < std.count; i++ {
+		entry := std.buffer[(std.start+i)%len(std.buffer)]
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries
+}
+
+// Subscribe calls fn with each entry as it is logged, until the returned
+// function is called. fn runs on the logging goroutine, so it must not block
+// or log.
+func Subscribe(fn func(Entry)) (unsubscribe func()) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	id := std.nextSub
+	std.nextSub++
+	std.subscribers[id] = fn
+	return func() {
+		std.mu.Lock()
+		defer std.mu.Unlock()
+		delete(std.subscribers, id)
+	}
+}
+
+func (r *registry) levelLocked(module string) slog.Level {
+	if level, ok := r.levels[module]; ok {
+		return level
+	}
+	return r.defaultLevel
+}
+
+func (r *registry) enabled(module string, level slog.Level) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return level >= r.levelLocked(module)
+}
+
+// resizeLocked changes the buffer size, keeping the newest entries.
+func (r *registry) resizeLocked(size int) {
+	if size == len(r.buffer) {
+		return
+	}
+	keep := r.count
+	if keep > size {
+		keep = size
+	}
+	buffer := make([]Entry, size)
+	for i := 0; i < keep; i++ {
+		buffer[i] = r.buffer[(r.start+r.count-keep+i)%len(r.buffer)]
+	}
+	r.buffer = buffer
+	r.start = 0
+	r.count = keep
+}
+
+// record numbers and buffers an entry, returning the handler to write it
+// with and the subscribers to notify.
+func (r *registry) record(entry *Entry) (slog.Handler, []func(Entry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	entry.ID = r.nextID
+	if r.count < len(r.buffer) {
+		r.buffer[(r.start+r.count)%len(r.buffer)] = *entry
+		r.count++
+	} else {
+		r.buffer[r.start] = *entry
+		r.start = (r.start + 1) % len(r.buffer)
+	}
+	subscribers := make([]func(Entry), 0, len(r.subscribers))
+	for _, fn := range r.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	return r.output, subscribers
+}
+
+// handler is the slog.Handler of a module logger.
+type handler struct {
+	module string
+	scopes []scope
+}
+
+// scope is one WithAttrs or WithGroup call, in the order made.
+type scope struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return std.enabled(h.module, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	entry := Entry{
+		Time:    record.Time,
+		Level:   record.Level,
+		Module:  h.module,
+		Message: record.Message,
+	}
+	prefix := ""
+	for _, s := range h.scopes {
+		if s.group != "" {
+			prefix += s.group + "."
+		}
+		for _, attr := range s.attrs {
+			entry.Attrs = appendAttr(entry.Attrs, prefix, attr)
+		}
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		entry.Attrs = appendAttr(entry.Attrs, prefix, attr)
+		return true
+	})
+
+	output, subscribers := std.record(&entry)
+	for _, fn := range subscribers {
+		fn(entry)
+	}
+
+	output = output.WithAttrs([]slog.Attr{slog.String("module", h.module)})
+	for _, s := range h.scopes {
+		if s.group != "" {
+			output = output.WithGroup(s.group)
+		} else {
+			output = output.WithAttrs(s.attrs)
+		}
+	}
+	return output.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(scope{attrs: attrs})
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(scope{group: name})
+}
+
+func (h *handler) with(s scope) *handler {
+	scopes := make([]scope, len(h.scopes), len(h.scopes)+1)
+	copy(scopes, h.scopes)
+	return &handler{module: h.module, scopes: append(scopes, s)}
+}
+
+// appendAttr flattens an attribute into text key-value pairs.
+func appendAttr(attrs []Attr, prefix string, attr slog.Attr) []Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			attrs = appendAttr(attrs, prefix, member)
+		}
+		return attrs
+	}
+	return append(attrs, Attr{Key: prefix + attr.Key, Value: attr.Value.String()})
+}
@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// useRegistry gives a test its own registry writing to a buffer.
+func useRegistry(t *testing.T, cfg Config) *bytes.Buffer {
+	t.Helper()
+	previous := std
+	previousDefault := slog.Default()
+	std = newRegistry()
+	t.Cleanup(func() {
+		std = previous
+		slog.SetDefault(previousDefault)
+	})
+	var out bytes.Buffer
+	cfg.Output = &out
+	Configure(cfg)
+	return &out
+}
+
+func TestModuleLevels(t *testing.T) {
+	out := useRegistry(t, Config{
+		Level:  slog.LevelWarn,
+		Levels: map[string]slog.Level{ModuleDMX: slog.LevelDebug},
+	})
+	dmx := For(ModuleDMX)
+	fade := For(ModuleFade)
+
+	dmx.Debug("frame sent", "universe", 1)
+	fade.Info("fade started")
+	fade.Warn("fade overran", "error", errors.New("late"))
+
+	entries := Entries(Filter{})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	if got := entries[0]; got.Module != ModuleDMX || got.Level != slog.LevelDebug || got.Message != "frame sent" ||
+		len(got.Attrs) != 1 || got.Attrs[0] != (Attr{Key: "universe", Value: "1"}) {
+		t.Errorf("Unexpected first entry: %+v", got)
+	}
+	if got := entries[1]; got.Module != ModuleFade || got.Attrs[0] != (Attr{Key: "error", Value: "late"}) {
+		t.Errorf("Unexpected second entry: %+v", got)
+	}
+	if strings.Contains(out.String(), "fade started") || !strings.Contains(out.String(), "module=dmx") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	// Levels change at runtime
+	SetLevel(ModuleFade, slog.LevelInfo)
+	SetLevel("", slog.LevelError)
+	fade.Info("fade started")
+	For(ModuleWiFi).Warn("scan failed")
+	if entries := Entries(Filter{AfterID: entries[1].ID}); len(entries) != 1 || entries[0].Message != "fade started" {
+		t.Errorf("Expected only the fade entry after changing levels, got %+v", entries)
+	}
+	levels := ModuleLevels()
+	want := []ModuleLevel{{ModuleDMX, slog.LevelDebug}, {ModuleFade, slog.LevelInfo}, {ModuleServer, slog.LevelError}, {ModuleWiFi, slog.LevelError}}
+	if len(levels) != len(want) {
+		t.Fatalf("ModuleLevels() = %v, want %v", levels, want)
+	}
+	for i := range want {
+		if levels[i] != want[i] {
+			t.Errorf("ModuleLevels()[%d] = %v, want %v", i, levels[i], want[i])
+		}
+	}
+	if DefaultLevel() != slog.LevelError {
+		t.Errorf("DefaultLevel() = %v, want ERROR", DefaultLevel())
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	useRegistry(t, Config{Level: slog.LevelDebug, BufferSize: 3})
+	logger := For(ModulePlayback)
+	for i := 1; i <= 5; i++ {
+		level := slog.LevelInfo
+		if i%2 == 0 {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "entry", "n", i)
+	}
+
+	entries := Entries(Filter{})
+	if len(entries) != 3 || entries[0].Attrs[0].Value != "3" || entries[2].Attrs[0].Value != "5" {
+		t.Fatalf("Expected the newest 3 entries, got %+v", entries)
+	}
+	if entries := Entries(Filter{MinLevel: slog.LevelWarn}); len(entries) != 1 || entries[0].Attrs[0].Value != "4" {
+		t.Errorf("Expected the one warning, got %+v", entries)
+	}
+	if entries := Entries(Filter{Limit: 1}); len(entries) != 1 || entries[0].Attrs[0].Value != "5" {
+		t.Errorf("Expected the newest entry, got %+v", entries)
+	}
+	if entries := Entries(Filter{Module: ModuleDMX}); len(entries) != 0 {
+		t.Errorf("Expected no dmx entries, got %+v", entries)
+	}
+
+	// Shrinking keeps the newest entries
+	Configure(Config{Level: slog.LevelDebug, BufferSize: 2, Output: &bytes.Buffer{}})
+	if entries := Entries(Filter{}); len(entries) != 2 || entries[0].Attrs[0].Value != "4" {
+		t.Errorf("Expected the newest 2 entries after resizing, got %+v", entries)
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	out := useRegistry(t, Config{Format: FormatJSON})
+	logger := For(ModuleGraphQL).With("request", "r1").WithGroup("cue")
+	logger.Info("cue fired", "number", 2.5, slog.Group("fade", "in", 3))
+
+	entries := Entries(Filter{})
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %+v", entries)
+	}
+	want := []Attr{{"request", "r1"}, {"cue.number", "2.5"}, {"cue.fade.in", "3"}}
+	if len(entries[0].Attrs) != len(want) {
+		t.Fatalf("Attrs = %v, want %v", entries[0].Attrs, want)
+	}
+	for i := range want {
+		if entries[0].Attrs[i] != want[i] {
+			t.Errorf("Attrs[%d] = %v, want %v", i, entries[0].Attrs[i], want[i])
+		}
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, out.String())
+	}
+	if line["module"] != ModuleGraphQL || line["request"] != "r1" {
+		t.Errorf("Unexpected JSON output: %v", line)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	useRegistry(t, Config{})
+	var received []string
+	unsubscribe := Subscribe(func(entry Entry) { received = append(received, entry.Message) })
+
+	For(ModuleDMX).Info("one")
+	// The standard library log package goes through the server module
+	log.Printf("two %d", 2)
+	unsubscribe()
+	For(ModuleDMX).Info("three")
+
+	if len(received) != 2 || received[0] != "one" || received[1] != "two 2" {
+		t.Errorf("Received %v, want [one two 2]", received)
+	}
+	if entries := Entries(Filter{Module: ModuleServer}); len(entries) != 1 {
+		t.Errorf("Expected the log package entry in the server module, got %+v", entries)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for value, want := range map[string]slog.Level{"debug": slog.LevelDebug, "": slog.LevelInfo, "WARNING": slog.LevelWarn, " error ": slog.LevelError} {
+		if level, err := ParseLevel(value); err != nil || level != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", value, level, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+
+	levels, err := ParseLevels("dmx=debug, fade = warn,")
+	if err != nil || len(levels) != 2 || levels[ModuleDMX] != slog.LevelDebug || levels[ModuleFade] != slog.LevelWarn {
+		t.Errorf("ParseLevels() = %v, %v", levels, err)
+	}
+	for _, value := range []string{"dmx", "=debug", "dmx=loud"} {
+		if _, err := ParseLevels(value); err == nil {
+			t.Errorf("ParseLevels(%q): expected an error", value)
+		}
+	}
+
+	if format, err := ParseFormat("JSON"); err != nil || format != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %v, %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
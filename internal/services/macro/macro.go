@@ -0,0 +1,338 @@
+// Package macro runs scene board button macros: ordered action lists that
+// activate scenes, wait, go in cue lists, and send OSC messages.
+package macro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/pkg/osc"
+)
+
+// MaxActions is the most actions a macro may hold.
+const MaxActions = 100
+
+// ActionType is a kind of macro step.
+type ActionType string
+
+const (
+	// ActionActivateScene activates a scene on the button's scene board.
+	ActionActivateScene ActionType = "ACTIVATE_SCENE"
+	// ActionWait pauses the macro.
+	ActionWait ActionType = "WAIT"
+	// ActionCueListGo goes to the next cue of a cue list, starting it if
+	// it is stopped.
+	ActionCueListGo ActionType = "CUE_LIST_GO"
+	// ActionSendOSC sends an OSC message over UDP.
+	ActionSendOSC ActionType = "SEND_OSC"
+)
+
+// OSCArgumentType is the type an OSC argument is sent as.
+type OSCArgumentType string
+
+const (
+	// OSCArgumentInt is sent as an int32.
+	OSCArgumentInt OSCArgumentType = "INT"
+	// OSCArgumentFloat is sent as a float32.
+	OSCArgumentFloat OSCArgumentType = "FLOAT"
+	// OSCArgumentString is sent as a string.
+	OSCArgumentString OSCArgumentType = "STRING"
+)
+
+// oscTags maps argument types to OSC type tags.
+var oscTags = map[OSCArgumentType]string{
+	OSCArgumentInt:    "i",
+	OSCArgumentFloat:  "f",
+	OSCArgumentString: "s",
+}
+
+// OSCArgument is an OSC message argument in text form.
+type OSCArgument struct {
+	Type  OSCArgumentType `json:"type"`
+	Value string          `json:"value"`
+}
+
+// Action is one step of a macro. Only the fields of its type are used.
+type Action struct {
+	Type ActionType `json:"type"`
+
+	// ACTIVATE_SCENE
+	SceneID string `json:"sceneId,omitempty"`
+	// ACTIVATE_SCENE and CUE_LIST_GO: seconds, overriding the usual fade time (optional)
+	FadeTime *float64 `json:"fadeTime,omitempty"`
+
+	// WAIT: seconds
+	WaitTime float64 `json:"waitTime,omitempty"`
+
+	// CUE_LIST_GO
+	CueListID string `json:"cueListId,omitempty"`
+
+	// SEND_OSC
+	OSCHost      string        `json:"oscHost,omitempty"`
+	OSCPort      int           `json:"oscPort,omitempty"`
+	OSCAddress   string        `json:"oscAddress,omitempty"`
+	OSCArguments []OSCArgument `json:"oscArguments,omitempty"`
+}
+
+// Validate checks that an action has what its type needs.
+func (a *Action) Validate() error {
+	if a.FadeTime != nil && *a.FadeTime < 0 {
+		return fmt.Errorf("fade time cannot be negative")
+	}
+	switch a.Type {
+	case ActionActivateScene:
+		if a.SceneID == "" {
+			return fmt.Errorf("%s requires a scene ID", a.Type)
+		}
+	case ActionWait:
+		if a.WaitTime <= 0 {
+			return fmt.Errorf("wait time must be positive")
+		}
+	case ActionCueListGo:
+		if a.CueListID == "" {
+			return fmt.Errorf("%s requires a cue list ID", a.Type)
+		}
+	case ActionSendOSC:
+		if a.OSCHost == "" {
+			return fmt.Errorf("%s requires a host", a.Type)
+		}
+		if a.OSCPort < 1 || a.OSCPort > 65535 {
+			return fmt.Errorf("OSC port must be between 1 and 65535")
+		}
+		if _, err := a.oscArguments(); err != nil {
+			return err
+		}
+		if _, err := osc.EncodeMessage(a.OSCAddress); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	return nil
+}
+
+// oscArguments converts the action's OSC arguments to their typed values.
+func (a *Action) oscArguments() ([]interface{}, error) {
+	args := make([]interface{}, len(a.OSCArguments))
+	for i, arg := range a.OSCArguments {
+		tag, ok := oscTags[arg.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown OSC argument type %q", arg.Type)
+		}
+		value, err := osc.ParseArgument(tag, arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
+// Validate checks every action of a macro.
+func Validate(actions []Action) error {
+	if len(actions) > MaxActions {
+		return fmt.Errorf("a macro can have at most %d actions", MaxActions)
+	}
+	for i := range actions {
+		if err := actions[i].Validate(); err != nil {
+			return fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Parse decodes a stored macro. An empty value is an empty macro.
+func Parse(value string) ([]Action, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var actions []Action
+	if err := json.Unmarshal([]byte(value), &actions); err != nil {
+		return nil, fmt.Errorf("invalid macro: %w", err)
+	}
+	return actions, nil
+}
+
+// Encode encodes a macro for storage.
+func Encode(actions []Action) (string, error) {
+	if actions == nil {
+		actions = []Action{}
+	}
+	value, err := json.Marshal(actions)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// Handlers carry out the actions that act on the rest of the server. id is
+// the ID the macro runs under.
+type Handlers struct {
+	ActivateScene func(ctx context.Context, id, sceneID string, fadeTime *float64) error
+	CueListGo     func(ctx context.Context, cueListID string, fadeTime *float64) error
+}
+
+// run is a macro in progress.
+type run struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Service runs macros in the background, one at a time per ID. A failing
+// action is logged and the macro carries on with the next.
+type Service struct {
+	mu       sync.Mutex
+	handlers Handlers
+	runs     map[string]*run
+
+	// Called with a macro ID when it starts or finishes (optional)
+	onChange func(id string)
+
+	sendOSC func(host string, port int, address string, args ...interface{}) error
+}
+
+// NewService creates a new macro service.
+func NewService() *Service {
+	return &Service{
+		runs:    make(map[string]*run),
+		sendOSC: osc.Send,
+	}
+}
+
+// SetHandlers sets the handlers actions are carried out with.
+func (s *Service) SetHandlers(handlers Handlers) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = handlers
+}
+
+// SetChangeCallback sets the callback for macros starting and finishing.
+func (s *Service) SetChangeCallback(callback func(id string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// Execute starts running a macro under an ID, stopping any macro already
+// running under it.
+func (s *Service) Execute(id string, actions []Action) error {
+	if len(actions) == 0 {
+		return fmt.Errorf("macro has no actions")
+	}
+	if err := Validate(actions); err != nil {
+		return err
+	}
+
+	s.Stop(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &run{cancel: cancel, done: make(chan struct{})}
+	s.mu.Lock()
+	s.runs[id] = r
+	handlers := s.handlers
+	s.mu.Unlock()
+	s.notify(id)
+
+	go func() {
+		defer close(r.done)
+		s.runActions(ctx, id, actions, handlers)
+
+		s.mu.Lock()
+		current := s.runs[id] == r
+		if current {
+			delete(s.runs, id)
+		}
+		s.mu.Unlock()
+		cancel()
+		if current {
+			s.notify(id)
+		}
+	}()
+	return nil
+}
+
+func (s *Service) runActions(ctx context.Context, id string, actions []Action, handlers Handlers) {
+	for i, action := range actions {
+		if ctx.Err() != nil {
+			return
+		}
+		var err error
+		switch action.Type {
+		case ActionActivateScene:
+			if handlers.ActivateScene != nil {
+				err = handlers.ActivateScene(ctx, id, action.SceneID, action.FadeTime)
+			}
+		case ActionWait:
+			timer := time.NewTimer(time.Duration(action.WaitTime * float64(time.Second)))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		case ActionCueListGo:
+			if handlers.CueListGo != nil {
+				err = handlers.CueListGo(ctx, action.CueListID, action.FadeTime)
+			}
+		case ActionSendOSC:
+			var args []interface{}
+			if args, err = action.oscArguments(); err == nil {
+				err = s.sendOSC(action.OSCHost, action.OSCPort, action.OSCAddress, args...)
+			}
+		}
+		if err != nil {
+			log.Warn("macro action failed", "macro", id, "action", i+1, "type", action.Type, "error", err)
+		}
+	}
+}
+
+// Stop stops a running macro, waiting for its current action to end.
+// It reports whether a macro was running.
+func (s *Service) Stop(id string) bool {
+	s.mu.Lock()
+	r, ok := s.runs[id]
+	delete(s.runs, id)
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	r.cancel()
+	<-r.done
+	s.notify(id)
+	return true
+}
+
+// StopAll stops every running macro.
+func (s *Service) StopAll() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.runs))
+	for id := range s.runs {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		s.Stop(id)
+	}
+}
+
+// IsRunning reports whether a macro is running under an ID.
+func (s *Service) IsRunning(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.runs[id]
+	return ok
+}
+
+func (s *Service) notify(id string) {
+	s.mu.Lock()
+	callback := s.onChange
+	s.mu.Unlock()
+	if callback != nil {
+		callback(id)
+	}
+}
@@ -0,0 +1,192 @@
+package macro
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the actions a macro carries out.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func createTestService(rec *recorder) *Service {
+	s := NewService()
+	s.SetHandlers(Handlers{
+		ActivateScene: func(ctx context.Context, id, sceneID string, fadeTime *float64) error {
+			rec.add("scene " + sceneID)
+			return nil
+		},
+		CueListGo: func(ctx context.Context, cueListID string, fadeTime *float64) error {
+			rec.add("go " + cueListID)
+			if cueListID == "bad" {
+				return fmt.Errorf("cue list not found")
+			}
+			return nil
+		},
+	})
+	s.sendOSC = func(host string, port int, address string, args ...interface{}) error {
+		rec.add(fmt.Sprintf("osc %s:%d%s %v", host, port, address, args))
+		return nil
+	}
+	return s
+}
+
+func waitIdle(t *testing.T, s *Service, id string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.IsRunning(id) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Macro %s still running", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestExecuteRunsActionsInOrder(t *testing.T) {
+	rec := &recorder{}
+	s := createTestService(rec)
+
+	err := s.Execute("btn-1", []Action{
+		{Type: ActionActivateScene, SceneID: "scene-1"},
+		{Type: ActionWait, WaitTime: 0.02},
+		{Type: ActionCueListGo, CueListID: "bad"}, // Failures don't stop the macro
+		{Type: ActionCueListGo, CueListID: "list-1"},
+		{Type: ActionSendOSC, OSCHost: "10.0.0.5", OSCPort: 53000, OSCAddress: "/go", OSCArguments: []OSCArgument{
+			{Type: OSCArgumentInt, Value: "3"},
+			{Type: OSCArgumentString, Value: "house"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !s.IsRunning("btn-1") {
+		t.Error("Expected the macro to be running")
+	}
+	waitIdle(t, s, "btn-1")
+
+	want := []string{"scene scene-1", "go bad", "go list-1", "osc 10.0.0.5:53000/go [3 house]"}
+	got := rec.get()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Calls = %v, want %v", got, want)
+	}
+}
+
+func TestStopInterruptsWait(t *testing.T) {
+	rec := &recorder{}
+	s := createTestService(rec)
+	var mu sync.Mutex
+	changes := 0
+	s.SetChangeCallback(func(id string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes++
+	})
+
+	actions := []Action{
+		{Type: ActionActivateScene, SceneID: "scene-1"},
+		{Type: ActionWait, WaitTime: 60},
+		{Type: ActionActivateScene, SceneID: "scene-2"},
+	}
+	if err := s.Execute("btn-1", actions); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Running it again restarts it from the top
+	if err := s.Execute("btn-1", actions); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if !s.Stop("btn-1") {
+		t.Error("Expected Stop() to find the running macro")
+	}
+	if s.Stop("btn-1") {
+		t.Error("Expected nothing to stop the second time")
+	}
+	if got := rec.get(); fmt.Sprint(got) != "[scene scene-1 scene scene-1]" {
+		t.Errorf("Calls = %v, want the first scene twice", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if changes != 4 {
+		t.Errorf("Expected 4 change notifications, got %d", changes)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	negative := -1.0
+	tests := []struct {
+		name   string
+		action Action
+	}{
+		{"unknown type", Action{Type: "DANCE"}},
+		{"scene without ID", Action{Type: ActionActivateScene}},
+		{"negative fade", Action{Type: ActionActivateScene, SceneID: "s", FadeTime: &negative}},
+		{"zero wait", Action{Type: ActionWait}},
+		{"go without cue list", Action{Type: ActionCueListGo}},
+		{"OSC without host", Action{Type: ActionSendOSC, OSCPort: 9000, OSCAddress: "/go"}},
+		{"OSC bad port", Action{Type: ActionSendOSC, OSCHost: "h", OSCPort: 70000, OSCAddress: "/go"}},
+		{"OSC bad address", Action{Type: ActionSendOSC, OSCHost: "h", OSCPort: 9000, OSCAddress: "go"}},
+		{"OSC bad argument", Action{Type: ActionSendOSC, OSCHost: "h", OSCPort: 9000, OSCAddress: "/go",
+			OSCArguments: []OSCArgument{{Type: OSCArgumentInt, Value: "x"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate([]Action{tt.action}); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+
+	if err := NewService().Execute("btn-1", nil); err == nil {
+		t.Error("Expected an error executing an empty macro")
+	}
+}
+
+func TestParseAndEncode(t *testing.T) {
+	fade := 2.0
+	actions := []Action{
+		{Type: ActionActivateScene, SceneID: "scene-1", FadeTime: &fade},
+		{Type: ActionWait, WaitTime: 1.5},
+	}
+	value, err := Encode(actions)
+	if err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	parsed, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(parsed) != 2 || parsed[0].SceneID != "scene-1" || *parsed[0].FadeTime != 2 || parsed[1].WaitTime != 1.5 {
+		t.Errorf("Round trip = %+v", parsed)
+	}
+
+	if value, _ := Encode(nil); value != "[]" {
+		t.Errorf("Encode(nil) = %q, want []", value)
+	}
+	if parsed, err := Parse(""); err != nil || parsed != nil {
+		t.Errorf("Parse(\"\") = %v, %v", parsed, err)
+	}
+	if _, err := Parse("{"); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
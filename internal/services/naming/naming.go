@@ -0,0 +1,218 @@
+// Package naming generates scene and cue names from project naming
+// conventions, so every client creates consistently named entities.
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// UniquenessPolicy controls what happens when a new name is already taken.
+type UniquenessPolicy string
+
+const (
+	// AllowDuplicates keeps the name as given.
+	AllowDuplicates UniquenessPolicy = "ALLOW_DUPLICATES"
+	// RejectDuplicates fails creation when the name is taken.
+	RejectDuplicates UniquenessPolicy = "REJECT_DUPLICATES"
+	// AppendSuffix adds " (2)", " (3)", ... until the name is free.
+	AppendSuffix UniquenessPolicy = "APPEND_SUFFIX"
+)
+
+// Built-in pattern tokens. Variables may not reuse these names.
+const (
+	TokenNumber    = "n"       // Next number in the sequence (scenes per project, cues per cue list)
+	TokenProject   = "project" // Project name
+	TokenCueList   = "list"    // Cue list name (cue patterns only)
+	TokenCueNumber = "cue"     // Cue number (cue patterns only)
+)
+
+var tokenPattern = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_]*)\}`)
+
+var variableKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Convention is a project's naming configuration.
+type Convention struct {
+	ScenePattern *string
+	CuePattern   *string
+	Uniqueness   UniquenessPolicy
+	// Values for custom tokens such as {act}
+	Variables map[string]string
+}
+
+// ValidatePolicy checks that policy is a known uniqueness policy.
+func ValidatePolicy(policy UniquenessPolicy) error {
+	switch policy {
+	case AllowDuplicates, RejectDuplicates, AppendSuffix:
+		return nil
+	}
+	return fmt.Errorf("invalid uniqueness policy: %s", policy)
+}
+
+// ValidateVariables checks custom variable keys.
+func ValidateVariables(vars map[string]string) error {
+	for key := range vars {
+		if !variableKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid naming variable: %q", key)
+		}
+		if isBuiltin(key) {
+			return fmt.Errorf("naming variable %q conflicts with a built-in token", key)
+		}
+	}
+	return nil
+}
+
+// ValidatePattern checks that every token in pattern is a built-in token
+// allowed for the entity or one of the given variables.
+func ValidatePattern(pattern string, builtins []string, vars map[string]string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("naming pattern cannot be empty")
+	}
+	for _, match := range tokenPattern.FindAllStringSubmatch(pattern, -1) {
+		token := match[1]
+		if _, ok := vars[token]; ok {
+			continue
+		}
+		allowed := false
+		for _, b := range builtins {
+			if token == b {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("unknown token {%s} in naming pattern", token)
+		}
+	}
+	return nil
+}
+
+// SceneTokens are the built-in tokens available to scene patterns.
+var SceneTokens = []string{TokenNumber, TokenProject}
+
+// CueTokens are the built-in tokens available to cue patterns.
+var CueTokens = []string{TokenNumber, TokenProject, TokenCueList, TokenCueNumber}
+
+func isBuiltin(token string) bool {
+	for _, b := range CueTokens {
+		if token == b {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate renders pattern with the next free {n} given the existing names.
+// values supplies built-in and custom tokens other than {n}.
+func Generate(pattern string, values map[string]string, existing []string) string {
+	next := NextNumber(pattern, values, existing)
+	return render(pattern, values, strconv.Itoa(next))
+}
+
+// NextNumber returns one more than the highest {n} among existing names
+// that match pattern, or 1 if none do.
+func NextNumber(pattern string, values map[string]string, existing []string) int {
+	if !strings.Contains(pattern, "{"+TokenNumber+"}") {
+		return 1
+	}
+
+	// Render everything except {n}, then match {n} as digits
+	parts := strings.Split(pattern, "{"+TokenNumber+"}")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(render(part, values, ""))
+	}
+	matcher := regexp.MustCompile("^" + strings.Join(parts, `(\d+)`) + "$")
+
+	highest := 0
+	for _, name := range existing {
+		match := matcher.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		// With repeated {n} tokens, use the first
+		if n, err := strconv.Atoi(match[1]); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest + 1
+}
+
+// render substitutes tokens; {n} becomes number. Unknown tokens are kept verbatim.
+func render(pattern string, values map[string]string, number string) string {
+	return tokenPattern.ReplaceAllStringFunc(pattern, func(match string) string {
+		token := match[1 : len(match)-1]
+		if token == TokenNumber {
+			return number
+		}
+		if value, ok := values[token]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ApplyUniqueness enforces policy for name against the existing names.
+func ApplyUniqueness(name string, existing []string, policy UniquenessPolicy) (string, error) {
+	taken := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		taken[e] = true
+	}
+	if !taken[name] {
+		return name, nil
+	}
+
+	switch policy {
+	case RejectDuplicates:
+		return "", fmt.Errorf("name already in use: %s", name)
+	case AppendSuffix:
+		for i := 2; ; i++ {
+			candidate := fmt.Sprintf("%s (%d)", name, i)
+			if !taken[candidate] {
+				return candidate, nil
+			}
+		}
+	default:
+		return name, nil
+	}
+}
+
+// NaturalLess compares names so embedded numbers sort by value:
+// "Sc 1.2" comes before "Sc 1.10". Letters compare case-insensitively.
+func NaturalLess(a, b string) bool {
+	ar, br := []rune(a), []rune(b)
+	i, j := 0, 0
+	for i < len(ar) && j < len(br) {
+		if unicode.IsDigit(ar[i]) && unicode.IsDigit(br[j]) {
+			si := i
+			for i < len(ar) && unicode.IsDigit(ar[i]) {
+				i++
+			}
+			sj := j
+			for j < len(br) && unicode.IsDigit(br[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ar[si:i]), "0")
+			nb := strings.TrimLeft(string(br[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		ca, cb := unicode.ToLower(ar[i]), unicode.ToLower(br[j])
+		if ca != cb {
+			return ca < cb
+		}
+		i++
+		j++
+	}
+	if len(ar)-i != len(br)-j {
+		return len(ar)-i < len(br)-j
+	}
+	return a < b
+}
@@ -0,0 +1,86 @@
+package naming
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	values := map[string]string{"act": "2", TokenProject: "Hamlet"}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		existing []string
+		want     string
+	}{
+		{"first in sequence", "Sc {act}.{n}", nil, "Sc 2.1"},
+		{"continues after highest", "Sc {act}.{n}", []string{"Sc 2.1", "Sc 2.7", "Sc 2.3"}, "Sc 2.8"},
+		{"ignores other acts", "Sc {act}.{n}", []string{"Sc 1.9", "Sc 2.2"}, "Sc 2.3"},
+		{"ignores unrelated names", "Sc {act}.{n}", []string{"Preshow", "Sc 2.x"}, "Sc 2.1"},
+		{"regex characters are literal", "[{project}] {n}", []string{"[Hamlet] 4"}, "[Hamlet] 5"},
+		{"no number token", "{project} look", []string{"Hamlet look"}, "Hamlet look"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Generate(tt.pattern, values, tt.existing); got != tt.want {
+				t.Errorf("Generate(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyUniqueness(t *testing.T) {
+	existing := []string{"Wash", "Wash (2)"}
+
+	if got, _ := ApplyUniqueness("Wash", existing, AllowDuplicates); got != "Wash" {
+		t.Errorf("AllowDuplicates = %q, want Wash", got)
+	}
+	if _, err := ApplyUniqueness("Wash", existing, RejectDuplicates); err == nil {
+		t.Error("Expected RejectDuplicates to fail for a taken name")
+	}
+	if got, _ := ApplyUniqueness("Wash", existing, AppendSuffix); got != "Wash (3)" {
+		t.Errorf("AppendSuffix = %q, want Wash (3)", got)
+	}
+	if got, err := ApplyUniqueness("Special", existing, RejectDuplicates); err != nil || got != "Special" {
+		t.Errorf("Free name = %q, %v; want Special", got, err)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	vars := map[string]string{"act": "1"}
+
+	if err := ValidatePattern("Sc {act}.{n}", SceneTokens, vars); err != nil {
+		t.Errorf("Expected valid scene pattern, got %v", err)
+	}
+	if err := ValidatePattern("Q{cue}", SceneTokens, vars); err == nil {
+		t.Error("Expected {cue} to be rejected in scene patterns")
+	}
+	if err := ValidatePattern("Q{cue} {list}", CueTokens, vars); err != nil {
+		t.Errorf("Expected valid cue pattern, got %v", err)
+	}
+	if err := ValidatePattern("  ", SceneTokens, vars); err == nil {
+		t.Error("Expected empty pattern to be rejected")
+	}
+	if err := ValidateVariables(map[string]string{"n": "5"}); err == nil {
+		t.Error("Expected built-in token name to be rejected as a variable")
+	}
+	if err := ValidateVariables(map[string]string{"scene act": "1"}); err == nil {
+		t.Error("Expected invalid variable key to be rejected")
+	}
+	if err := ValidatePolicy("SOMETIMES"); err == nil {
+		t.Error("Expected unknown policy to be rejected")
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"Sc 1.10", "sc 1.2", "Sc 1.1", "Preshow", "Sc 10.1", "Sc 2.1", "Sc 1.03"}
+	sort.SliceStable(names, func(i, j int) bool { return NaturalLess(names[i], names[j]) })
+
+	want := []string{"Preshow", "Sc 1.1", "sc 1.2", "Sc 1.03", "Sc 1.10", "Sc 2.1", "Sc 10.1"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Natural order = %v, want %v", names, want)
+		}
+	}
+}
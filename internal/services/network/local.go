@@ -0,0 +1,101 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// LocalAddress is an IPv4 address of an interface that is up.
+type LocalAddress struct {
+	Interface     string
+	Address       string
+	Broadcast     string
+	InterfaceType string // "ethernet", "wifi", "other", or "localhost"
+}
+
+// GetLocalAddresses returns the IPv4 addresses of the interfaces that are
+// up, loopback last, for choosing where Art-Net output is sent from.
+func GetLocalAddresses() ([]LocalAddress, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network interfaces: %w", err)
+	}
+
+	var addresses []LocalAddress
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		interfaceType := "localhost"
+		if iface.Flags&net.FlagLoopback == 0 {
+			interfaceType = GetInterfaceType(iface.Name)
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			local := LocalAddress{
+				Interface:     iface.Name,
+				Address:       ipNet.IP.To4().String(),
+				InterfaceType: interfaceType,
+			}
+			if broadcast := calculateBroadcast(ipNet.IP, ipNet.Mask); broadcast != nil {
+				local.Broadcast = broadcast.String()
+			}
+			addresses = append(addresses, local)
+		}
+	}
+
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return addressRank(addresses[i]) < addressRank(addresses[j])
+	})
+	return addresses, nil
+}
+
+// addressRank orders wired interfaces first and loopback last.
+func addressRank(a LocalAddress) int {
+	switch a.InterfaceType {
+	case "ethernet":
+		return 0
+	case "wifi":
+		return 1
+	case "localhost":
+		return 3
+	}
+	return 2
+}
+
+// ResolveLocalAddress finds the local address an interface name or IPv4
+// address names: the interface's first IPv4 address, or the address itself
+// if an interface that is up has it. The unspecified address 0.0.0.0
+// resolves to nil, leaving the choice to the operating system.
+func ResolveLocalAddress(value string) (net.IP, string, error) {
+	if ip := net.ParseIP(value); ip != nil {
+		if ip.To4() == nil {
+			return nil, "", fmt.Errorf("%s is not an IPv4 address", value)
+		}
+		if ip.IsUnspecified() {
+			return nil, "", nil
+		}
+	}
+
+	addresses, err := GetLocalAddresses()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, a := range addresses {
+		if a.Address == value || a.Interface == value {
+			return net.ParseIP(a.Address).To4(), a.Interface, nil
+		}
+	}
+	if net.ParseIP(value) != nil {
+		return nil, "", fmt.Errorf("no interface that is up has address %s", value)
+	}
+	return nil, "", fmt.Errorf("no interface %q with an IPv4 address is up", value)
+}
@@ -0,0 +1,52 @@
+package network
+
+import "testing"
+
+func TestGetLocalAddresses_LoopbackLast(t *testing.T) {
+	addresses, err := GetLocalAddresses()
+	if err != nil {
+		t.Fatalf("GetLocalAddresses() error: %v", err)
+	}
+	if len(addresses) == 0 {
+		t.Fatal("Expected at least the loopback address")
+	}
+
+	last := addresses[len(addresses)-1]
+	if last.InterfaceType != "localhost" {
+		t.Errorf("Last address = %+v, want loopback", last)
+	}
+	for _, a := range addresses {
+		if a.Interface == "" || a.Address == "" {
+			t.Errorf("Address missing fields: %+v", a)
+		}
+	}
+}
+
+func TestResolveLocalAddress(t *testing.T) {
+	ip, iface, err := ResolveLocalAddress("127.0.0.1")
+	if err != nil {
+		t.Fatalf("ResolveLocalAddress(127.0.0.1) error: %v", err)
+	}
+	if ip.String() != "127.0.0.1" || iface == "" {
+		t.Errorf("ResolveLocalAddress(127.0.0.1) = %v, %q", ip, iface)
+	}
+
+	// An interface name resolves to its address
+	byName, nameIface, err := ResolveLocalAddress(iface)
+	if err != nil {
+		t.Fatalf("ResolveLocalAddress(%q) error: %v", iface, err)
+	}
+	if byName == nil || nameIface != iface {
+		t.Errorf("ResolveLocalAddress(%q) = %v, %q", iface, byName, nameIface)
+	}
+
+	if ip, _, err := ResolveLocalAddress("0.0.0.0"); err != nil || ip != nil {
+		t.Errorf("ResolveLocalAddress(0.0.0.0) = %v, %v, want unbound", ip, err)
+	}
+
+	for _, value := range []string{"203.0.113.7", "::1", "no-such-interface0"} {
+		if _, _, err := ResolveLocalAddress(value); err == nil {
+			t.Errorf("ResolveLocalAddress(%q) expected an error", value)
+		}
+	}
+}
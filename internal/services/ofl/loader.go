@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -82,7 +81,7 @@ func (l *Loader) NeedsImport(ctx context.Context) (bool, error) {
 // LoadAll downloads and imports all fixtures from the Open Fixture Library
 // This is intended to be called on first startup when the database is empty
 func (l *Loader) LoadAll(ctx context.Context) (*ImportStatus, error) {
-	log.Println("📦 Starting Open Fixture Library import...")
+	log.Info("📦 Starting Open Fixture Library import")
 
 	// Ensure cache directory exists
 	if err := os.MkdirAll(l.cachePath, 0755); err != nil {
@@ -104,7 +103,7 @@ func (l *Loader) LoadAll(ctx context.Context) (*ImportStatus, error) {
 
 	// Save import status
 	if err := l.saveImportStatus(status); err != nil {
-		log.Printf("Warning: failed to save import status: %v", err)
+		log.Warn("failed to save import status", "error", err)
 	}
 
 	return status, nil
@@ -112,7 +111,7 @@ func (l *Loader) LoadAll(ctx context.Context) (*ImportStatus, error) {
 
 // downloadOFLZip downloads the OFL repository as a zipball
 func (l *Loader) downloadOFLZip(ctx context.Context, destPath string) error {
-	log.Println("📥 Downloading Open Fixture Library from GitHub...")
+	log.Info("📥 Downloading Open Fixture Library from GitHub")
 
 	req, err := http.NewRequestWithContext(ctx, "GET", OFLZipballURL, nil)
 	if err != nil {
@@ -144,13 +143,13 @@ func (l *Loader) downloadOFLZip(ctx context.Context, destPath string) error {
 		return err
 	}
 
-	log.Printf("📥 Downloaded %.2f MB", float64(written)/(1024*1024))
+	log.Info("📥 Downloaded Open Fixture Library", "bytes", written)
 	return nil
 }
 
 // importFromZip extracts fixtures from the downloaded zip and imports them
 func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStatus, error) {
-	log.Println("📦 Extracting and importing fixtures...")
+	log.Info("📦 Extracting and importing fixtures")
 
 	zipReader, err := zip.OpenReader(zipPath)
 	if err != nil {
@@ -163,7 +162,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse manufacturers: %w", err)
 	}
-	log.Printf("📋 Found %d manufacturers", len(manufacturers))
+	log.Info("📋 Found manufacturers", "manufacturers", len(manufacturers))
 
 	// Find all fixture JSON files
 	var fixtureFiles []*zip.File
@@ -190,7 +189,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 		}
 	}
 
-	log.Printf("📋 Found %d fixture files to import", len(fixtureFiles))
+	log.Info("📋 Found fixture files to import", "files", len(fixtureFiles))
 
 	// Import fixtures with concurrency
 	status := &ImportStatus{
@@ -231,7 +230,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 			// Read fixture JSON
 			rc, err := file.Open()
 			if err != nil {
-				log.Printf("⚠️  Failed to open %s: %v", fixtureFileName, err)
+				log.Warn("failed to open fixture file", "file", fixtureFileName, "error", err)
 				atomic.AddInt64(&failCount, 1)
 				return
 			}
@@ -239,7 +238,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 
 			data, err := io.ReadAll(rc)
 			if err != nil {
-				log.Printf("⚠️  Failed to read %s: %v", fixtureFileName, err)
+				log.Warn("failed to read fixture file", "file", fixtureFileName, "error", err)
 				atomic.AddInt64(&failCount, 1)
 				return
 			}
@@ -253,7 +252,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 				}
 				// Log other errors but don't spam
 				if atomic.LoadInt64(&failCount) < 10 {
-					log.Printf("⚠️  Failed to import %s/%s: %v", manufacturerName, fixtureFileName, err)
+					log.Warn("failed to import fixture", "manufacturer", manufacturerName, "file", fixtureFileName, "error", err)
 				}
 				atomic.AddInt64(&failCount, 1)
 				return
@@ -262,7 +261,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 			atomic.AddInt64(&successCount, 1)
 			current := atomic.LoadInt64(&successCount)
 			if current%100 == 0 {
-				log.Printf("✅ Imported %d fixtures...", current)
+				log.Info("✅ Importing fixtures", "imported", current)
 			}
 		}(f)
 	}
@@ -272,8 +271,7 @@ func (l *Loader) importFromZip(ctx context.Context, zipPath string) (*ImportStat
 	status.SuccessfulImports = int(successCount)
 	status.FailedImports = int(failCount)
 
-	log.Printf("✅ OFL import complete: %d successful, %d failed, %d total",
-		status.SuccessfulImports, status.FailedImports, status.TotalFixtures)
+	log.Info("✅ OFL import complete", "successful", status.SuccessfulImports, "failed", status.FailedImports, "total", status.TotalFixtures)
 
 	return status, nil
 }
@@ -310,7 +308,7 @@ func (l *Loader) findAndParseManufacturers(zipReader *zip.ReadCloser) (map[strin
 				var mfg Manufacturer
 				if err := json.Unmarshal(raw, &mfg); err != nil {
 					// If it's not a valid manufacturer object, skip it
-					log.Printf("⚠️  Skipping invalid manufacturer entry: %s", key)
+					log.Warn("skipping invalid manufacturer entry", "key", key)
 					continue
 				}
 				manufacturers[key] = mfg
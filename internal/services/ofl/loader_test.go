@@ -24,6 +24,7 @@ func setupLoaderTestDB(t *testing.T) *gorm.DB {
 	err = db.AutoMigrate(
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
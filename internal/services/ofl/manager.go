@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -144,7 +143,7 @@ func (m *Manager) CancelImport() bool {
 
 // CheckForUpdates checks for available OFL updates without importing
 func (m *Manager) CheckForUpdates(ctx context.Context) (*UpdateCheckResult, error) {
-	log.Println("Checking for OFL updates...")
+	log.Info("checking for OFL updates")
 
 	// Get current fixture hashes
 	currentHashes, err := m.updatesService.GetCurrentFixtureHashes(ctx)
@@ -515,7 +514,7 @@ func (m *Manager) runImport(ctx context.Context, opts *ImportOptions) (*ImportRe
 
 // downloadOFLZip downloads the OFL repository as a zipball
 func (m *Manager) downloadOFLZip(ctx context.Context, destPath string) error {
-	log.Println("Downloading Open Fixture Library from GitHub...")
+	log.Info("downloading Open Fixture Library from GitHub")
 
 	req, err := http.NewRequestWithContext(ctx, "GET", OFLZipballURL, nil)
 	if err != nil {
@@ -550,7 +549,7 @@ func (m *Manager) downloadOFLZip(ctx context.Context, destPath string) error {
 		return err
 	}
 
-	log.Printf("Downloaded %.2f MB", float64(written)/(1024*1024))
+	log.Info("downloaded Open Fixture Library", "bytes", written)
 	return nil
 }
 
@@ -610,6 +609,6 @@ func (m *Manager) saveImportMeta(ctx context.Context, stats ImportStats, usedBun
 	}
 
 	if err := m.db.WithContext(ctx).Create(meta).Error; err != nil {
-		log.Printf("Warning: failed to save import metadata: %v", err)
+		log.Warn("failed to save import metadata", "error", err)
 	}
 }
@@ -465,7 +465,9 @@ func (s *Service) ImportFixtureWithHash(ctx context.Context, manufacturer, oflFi
 			}
 			// Delete modes
 			tx.Where("definition_id = ?", existing.ID).Delete(&models.FixtureMode{})
-			// Delete channels
+			// Delete channels with their capabilities
+			channelIDs := tx.Model(&models.ChannelDefinition{}).Select("id").Where("definition_id = ?", existing.ID)
+			tx.Where("channel_id IN (?)", channelIDs).Delete(&models.ChannelCapability{})
 			tx.Where("definition_id = ?", existing.ID).Delete(&models.ChannelDefinition{})
 			// Delete definition
 			if err := tx.Delete(existing).Error; err != nil {
@@ -0,0 +1,154 @@
+// Package patch checks fixture DMX addresses. It finds fixtures whose
+// channel ranges overlap within a universe and suggests free addresses for
+// new fixtures.
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+// MaxUniverse bounds the universes addresses are searched in.
+const MaxUniverse = 32768
+
+// Fixture is a fixture's place in the patch.
+type Fixture struct {
+	ID           string
+	Name         string
+	Universe     int
+	StartChannel int
+	ChannelCount int
+}
+
+// FromInstance describes a fixture instance's place in the patch. A fixture
+// without a channel count takes one channel.
+func FromInstance(f *models.FixtureInstance) Fixture {
+	channelCount := 1
+	if f.ChannelCount != nil && *f.ChannelCount > 0 {
+		channelCount = *f.ChannelCount
+	}
+	return Fixture{
+		ID:           f.ID,
+		Name:         f.Name,
+		Universe:     f.Universe,
+		StartChannel: f.StartChannel,
+		ChannelCount: channelCount,
+	}
+}
+
+// EndChannel returns the fixture's last channel.
+func (f Fixture) EndChannel() int {
+	return f.StartChannel + f.ChannelCount - 1
+}
+
+// overlaps reports whether two fixtures share a channel.
+func (f Fixture) overlaps(other Fixture) bool {
+	return f.Universe == other.Universe &&
+		f.StartChannel <= other.EndChannel() && other.StartChannel <= f.EndChannel()
+}
+
+// Conflict is a fixture already using channels another fixture needs.
+type Conflict struct {
+	FixtureID    string
+	FixtureName  string
+	StartChannel int
+	EndChannel   int
+}
+
+// ConflictError reports the fixtures a fixture's channels overlap.
+type ConflictError struct {
+	FixtureName  string
+	Universe     int
+	StartChannel int
+	EndChannel   int
+	Conflicts    []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	names := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		names[i] = fmt.Sprintf("%s (%d-%d)", c.FixtureName, c.StartChannel, c.EndChannel)
+	}
+	return fmt.Sprintf("DMX address conflict: %s needs channels %d-%d of universe %d, which overlap %s",
+		e.FixtureName, e.StartChannel, e.EndChannel, e.Universe, strings.Join(names, ", "))
+}
+
+// Validate checks that a fixture's channels fit in its universe and overlap
+// none of the other fixtures. Others may include the fixture itself, which
+// is skipped by ID. Overlaps are reported as a *ConflictError.
+func Validate(fixture Fixture, others []Fixture) error {
+	if fixture.Universe < 1 || fixture.Universe > MaxUniverse {
+		return fmt.Errorf("universe must be between 1 and %d", MaxUniverse)
+	}
+	if fixture.StartChannel < 1 || fixture.EndChannel() > dmx.UniverseSize {
+		return fmt.Errorf("%s needs channels %d-%d, outside the %d channels of a universe",
+			fixture.Name, fixture.StartChannel, fixture.EndChannel(), dmx.UniverseSize)
+	}
+
+	var conflicts []Conflict
+	for _, other := range others {
+		if other.ID == fixture.ID || !fixture.overlaps(other) {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{
+			FixtureID:    other.ID,
+			FixtureName:  other.Name,
+			StartChannel: other.StartChannel,
+			EndChannel:   other.EndChannel(),
+		})
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].StartChannel < conflicts[j].StartChannel })
+	return &ConflictError{
+		FixtureName:  fixture.Name,
+		Universe:     fixture.Universe,
+		StartChannel: fixture.StartChannel,
+		EndChannel:   fixture.EndChannel(),
+		Conflicts:    conflicts,
+	}
+}
+
+// NextFreeAddress finds the first block of channelCount free channels at or
+// after startChannel of universe, moving on to later universes when a
+// universe has no room. It reports false if there is no room before
+// MaxUniverse.
+func NextFreeAddress(fixtures []Fixture, universe, startChannel, channelCount int) (int, int, bool) {
+	if channelCount < 1 || channelCount > dmx.UniverseSize {
+		return 0, 0, false
+	}
+	if startChannel < 1 {
+		startChannel = 1
+	}
+
+	for ; universe >= 1 && universe <= MaxUniverse; universe++ {
+		used := make([]bool, dmx.UniverseSize+1)
+		for _, f := range fixtures {
+			if f.Universe != universe {
+				continue
+			}
+			for ch := max(f.StartChannel, 1); ch <= min(f.EndChannel(), dmx.UniverseSize); ch++ {
+				used[ch] = true
+			}
+		}
+
+		free := 0
+		for ch := startChannel; ch <= dmx.UniverseSize; ch++ {
+			if used[ch] {
+				free = 0
+				continue
+			}
+			free++
+			if free == channelCount {
+				return universe, ch - channelCount + 1, true
+			}
+		}
+		startChannel = 1
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,95 @@
+package patch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+)
+
+func TestFromInstance(t *testing.T) {
+	count := 6
+	f := FromInstance(&models.FixtureInstance{ID: "a", Name: "Par", Universe: 2, StartChannel: 10, ChannelCount: &count})
+	if f.Universe != 2 || f.StartChannel != 10 || f.EndChannel() != 15 {
+		t.Errorf("FromInstance() = %+v, want universe 2 channels 10-15", f)
+	}
+	if f := FromInstance(&models.FixtureInstance{StartChannel: 3}); f.ChannelCount != 1 {
+		t.Errorf("ChannelCount without a count = %d, want 1", f.ChannelCount)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	patched := []Fixture{
+		{ID: "a", Name: "Par 1", Universe: 1, StartChannel: 1, ChannelCount: 8},
+		{ID: "b", Name: "Par 2", Universe: 1, StartChannel: 9, ChannelCount: 8},
+		{ID: "c", Name: "Wash", Universe: 2, StartChannel: 1, ChannelCount: 16},
+	}
+
+	err := Validate(Fixture{ID: "new", Name: "Spot", Universe: 1, StartChannel: 5, ChannelCount: 6}, patched)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected a ConflictError, got %v", err)
+	}
+	if len(conflict.Conflicts) != 2 || conflict.Conflicts[0].FixtureID != "a" || conflict.Conflicts[1].EndChannel != 16 {
+		t.Errorf("Conflicts = %+v, want Par 1 and Par 2", conflict.Conflicts)
+	}
+	if !strings.Contains(err.Error(), "channels 5-10 of universe 1") {
+		t.Errorf("Error = %q", err)
+	}
+
+	tests := []struct {
+		name    string
+		fixture Fixture
+		valid   bool
+	}{
+		{"free block", Fixture{ID: "new", Universe: 1, StartChannel: 17, ChannelCount: 8}, true},
+		{"other universe", Fixture{ID: "new", Universe: 3, StartChannel: 1, ChannelCount: 8}, true},
+		{"moving in place", Fixture{ID: "a", Universe: 1, StartChannel: 2, ChannelCount: 7}, true},
+		{"touching end", Fixture{ID: "new", Universe: 2, StartChannel: 16, ChannelCount: 1}, false},
+		{"past the universe", Fixture{ID: "new", Universe: 1, StartChannel: 510, ChannelCount: 4}, false},
+		{"channel zero", Fixture{ID: "new", Universe: 1, StartChannel: 0, ChannelCount: 1}, false},
+		{"universe zero", Fixture{ID: "new", Universe: 0, StartChannel: 100, ChannelCount: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.fixture, patched); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestNextFreeAddress(t *testing.T) {
+	patched := []Fixture{
+		{ID: "a", Universe: 1, StartChannel: 1, ChannelCount: 8},
+		{ID: "b", Universe: 1, StartChannel: 12, ChannelCount: 4},
+		{ID: "c", Universe: 1, StartChannel: 20, ChannelCount: 490},
+	}
+	tests := []struct {
+		name         string
+		universe     int
+		startChannel int
+		channelCount int
+		wantUniverse int
+		wantChannel  int
+	}{
+		{"fits in a gap", 1, 1, 3, 1, 9},
+		{"skips a small gap", 1, 1, 4, 1, 16},
+		{"from a start channel", 1, 17, 2, 1, 17},
+		{"next universe", 1, 1, 10, 2, 1},
+		{"whole universe from mid-way", 5, 100, 512, 6, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			universe, channel, ok := NextFreeAddress(patched, tt.universe, tt.startChannel, tt.channelCount)
+			if !ok || universe != tt.wantUniverse || channel != tt.wantChannel {
+				t.Errorf("NextFreeAddress() = %d/%d, %v, want %d/%d", universe, channel, ok, tt.wantUniverse, tt.wantChannel)
+			}
+		})
+	}
+
+	if _, _, ok := NextFreeAddress(patched, 1, 1, 513); ok {
+		t.Error("Expected no room for more than a universe of channels")
+	}
+}
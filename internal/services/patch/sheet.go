@@ -0,0 +1,234 @@
+package patch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Patch sheet columns. A sheet names its columns in a header row and may
+// have them in any order.
+const (
+	ColumnName         = "Name"
+	ColumnManufacturer = "Manufacturer"
+	ColumnModel        = "Model"
+	ColumnMode         = "Mode"
+	ColumnUniverse     = "Universe"
+	ColumnAddress      = "Address"
+	ColumnTags         = "Tags"
+	ColumnX            = "X"
+	ColumnY            = "Y"
+	ColumnRotation     = "Rotation"
+)
+
+// SheetColumns are the columns a patch sheet is written with.
+var SheetColumns = []string{
+	ColumnName, ColumnManufacturer, ColumnModel, ColumnMode, ColumnUniverse,
+	ColumnAddress, ColumnTags, ColumnX, ColumnY, ColumnRotation,
+}
+
+// requiredColumns are the columns a sheet must have to be read.
+var requiredColumns = []string{ColumnName, ColumnManufacturer, ColumnModel, ColumnUniverse, ColumnAddress}
+
+// SheetRow is a fixture in a patch sheet. Tags are written comma-separated
+// in one cell; the position is the fixture's place on the 2D layout.
+type SheetRow struct {
+	Line         int // Line of the row in the sheet, counting the header as 1
+	Name         string
+	Manufacturer string
+	Model        string
+	Mode         string
+	Universe     int
+	Address      int
+	Tags         []string
+	X            *float64
+	Y            *float64
+	Rotation     *float64
+}
+
+// InvalidRow is a row of a patch sheet that could not be read.
+type InvalidRow struct {
+	Line         int
+	Name         string
+	Manufacturer string
+	Model        string
+	Reason       string
+}
+
+// Sheet is a patch sheet as read.
+type Sheet struct {
+	Rows    []SheetRow
+	Invalid []InvalidRow
+	columns map[string]int
+}
+
+// Has reports whether the sheet has a column. Optional columns a sheet
+// leaves out leave the fixtures' values as they are.
+func (s *Sheet) Has(column string) bool {
+	_, ok := s.columns[column]
+	return ok
+}
+
+// WriteSheet writes rows as a CSV patch sheet.
+func WriteSheet(w io.Writer, rows []SheetRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(SheetColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Name,
+			row.Manufacturer,
+			row.Model,
+			row.Mode,
+			strconv.Itoa(row.Universe),
+			strconv.Itoa(row.Address),
+			strings.Join(row.Tags, ", "),
+			formatFloat(row.X),
+			formatFloat(row.Y),
+			formatFloat(row.Rotation),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadSheet reads a CSV patch sheet. Header names are matched ignoring case
+// and unknown columns are ignored. Rows that can't be read are returned as
+// invalid rather than failing the sheet; blank rows are skipped.
+func ReadSheet(r io.Reader) (*Sheet, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("patch sheet is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch sheet: %w", err)
+	}
+
+	sheet := &Sheet{columns: make(map[string]int)}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for _, column := range SheetColumns {
+			if strings.EqualFold(name, column) {
+				sheet.columns[column] = i
+			}
+		}
+	}
+	var missing []string
+	for _, column := range requiredColumns {
+		if !sheet.Has(column) {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("patch sheet is missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch sheet: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+		row, err := sheet.parseRow(record)
+		row.Line = line
+		if err != nil {
+			sheet.Invalid = append(sheet.Invalid, InvalidRow{
+				Line:         line,
+				Name:         row.Name,
+				Manufacturer: row.Manufacturer,
+				Model:        row.Model,
+				Reason:       err.Error(),
+			})
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet, nil
+}
+
+// parseRow reads a row's cells.
+func (s *Sheet) parseRow(record []string) (SheetRow, error) {
+	cell := func(column string) string {
+		i, ok := s.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := SheetRow{
+		Name:         cell(ColumnName),
+		Manufacturer: cell(ColumnManufacturer),
+		Model:        cell(ColumnModel),
+		Mode:         cell(ColumnMode),
+	}
+	if row.Name == "" {
+		return row, fmt.Errorf("name is required")
+	}
+	if row.Manufacturer == "" || row.Model == "" {
+		return row, fmt.Errorf("manufacturer and model are required")
+	}
+
+	var err error
+	if row.Universe, err = strconv.Atoi(cell(ColumnUniverse)); err != nil {
+		return row, fmt.Errorf("invalid universe %q", cell(ColumnUniverse))
+	}
+	if row.Address, err = strconv.Atoi(cell(ColumnAddress)); err != nil {
+		return row, fmt.Errorf("invalid address %q", cell(ColumnAddress))
+	}
+	for _, tag := range strings.Split(cell(ColumnTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			row.Tags = append(row.Tags, tag)
+		}
+	}
+	position := []struct {
+		column string
+		dst    **float64
+	}{{ColumnX, &row.X}, {ColumnY, &row.Y}, {ColumnRotation, &row.Rotation}}
+	for _, p := range position {
+		value := cell(p.column)
+		if value == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return row, fmt.Errorf("invalid %s %q", strings.ToLower(p.column), value)
+		}
+		*p.dst = &f
+	}
+	return row, nil
+}
+
+// blank reports whether a record has no values.
+func blank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
@@ -0,0 +1,94 @@
+package patch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSheet_RoundTrip(t *testing.T) {
+	x, rotation := 1.5, 90.0
+	rows := []SheetRow{
+		{Name: "Front 1", Manufacturer: "Chauvet", Model: "SlimPAR, Pro", Mode: "7ch", Universe: 1, Address: 1, Tags: []string{"front", "wash"}, X: &x, Rotation: &rotation},
+		{Name: "House", Manufacturer: "Generic", Model: "Dimmer", Universe: 2, Address: 10},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSheet(&buf, rows); err != nil {
+		t.Fatalf("WriteSheet failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Name,Manufacturer,Model,Mode,Universe,Address,Tags,X,Y,Rotation\n") {
+		t.Errorf("Unexpected header: %q", buf.String())
+	}
+
+	sheet, err := ReadSheet(&buf)
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	if len(sheet.Invalid) != 0 || len(sheet.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %+v", sheet)
+	}
+	front := sheet.Rows[0]
+	if front.Line != 2 || front.Model != "SlimPAR, Pro" || front.Mode != "7ch" || strings.Join(front.Tags, "|") != "front|wash" {
+		t.Errorf("Unexpected row %+v", front)
+	}
+	if front.X == nil || *front.X != 1.5 || front.Y != nil || front.Rotation == nil || *front.Rotation != 90 {
+		t.Errorf("Unexpected position %v, %v, %v", front.X, front.Y, front.Rotation)
+	}
+	if house := sheet.Rows[1]; house.Universe != 2 || house.Address != 10 || house.Tags != nil {
+		t.Errorf("Unexpected row %+v", house)
+	}
+}
+
+func TestReadSheet_Columns(t *testing.T) {
+	sheet, err := ReadSheet(strings.NewReader("\ufeffaddress,UNIVERSE,name,model,manufacturer,notes\n5,1,Spot,Spot 1,Acme,hung high\n,,,,,\n"))
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	if len(sheet.Rows) != 1 {
+		t.Fatalf("Expected the blank row to be skipped, got %+v", sheet.Rows)
+	}
+	if row := sheet.Rows[0]; row.Name != "Spot" || row.Manufacturer != "Acme" || row.Address != 5 {
+		t.Errorf("Unexpected row %+v", row)
+	}
+	if sheet.Has(ColumnMode) || sheet.Has(ColumnTags) || !sheet.Has(ColumnAddress) {
+		t.Error("Expected only the sheet's own columns")
+	}
+
+	if _, err := ReadSheet(strings.NewReader("Name,Manufacturer,Model\n")); err == nil || !strings.Contains(err.Error(), "Universe, Address") {
+		t.Errorf("Expected an error naming the missing columns, got %v", err)
+	}
+	if _, err := ReadSheet(strings.NewReader("")); err == nil {
+		t.Error("Expected an error for an empty sheet")
+	}
+}
+
+func TestReadSheet_InvalidRows(t *testing.T) {
+	sheet, err := ReadSheet(strings.NewReader(`Name,Manufacturer,Model,Universe,Address,X
+Good,Acme,Par,1,1,
+,Acme,Par,1,2,
+Bad Address,Acme,Par,1,one,
+Bad X,Acme,Par,1,3,left
+No Model,Acme,,1,4,
+`))
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	if len(sheet.Rows) != 1 {
+		t.Errorf("Expected 1 valid row, got %d", len(sheet.Rows))
+	}
+	want := []InvalidRow{
+		{Line: 3, Manufacturer: "Acme", Model: "Par", Reason: "name is required"},
+		{Line: 4, Name: "Bad Address", Manufacturer: "Acme", Model: "Par", Reason: `invalid address "one"`},
+		{Line: 5, Name: "Bad X", Manufacturer: "Acme", Model: "Par", Reason: `invalid x "left"`},
+		{Line: 6, Name: "No Model", Manufacturer: "Acme", Reason: "manufacturer and model are required"},
+	}
+	if len(sheet.Invalid) != len(want) {
+		t.Fatalf("Expected %d invalid rows, got %+v", len(want), sheet.Invalid)
+	}
+	for i, row := range sheet.Invalid {
+		if row != want[i] {
+			t.Errorf("Invalid row %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+}
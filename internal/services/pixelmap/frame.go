@@ -0,0 +1,152 @@
+package pixelmap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Registers the formats DecodeImage accepts
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// Frame is a picture of a matrix's size as 8-bit RGB, row by row from the
+// top left.
+type Frame struct {
+	Width  int
+	Height int
+	Pix    []byte
+}
+
+// At returns the color of a pixel.
+func (f Frame) At(x, y int) color.Color {
+	i := (y*f.Width + x) * 3
+	return color.Color{
+		R: float64(f.Pix[i]) / 255,
+		G: float64(f.Pix[i+1]) / 255,
+		B: float64(f.Pix[i+2]) / 255,
+	}
+}
+
+// Source renders a frame at a matrix's size.
+type Source func(width, height int) (Frame, error)
+
+// RGB shows a raw frame: 3 bytes of red, green, and blue per pixel, row by
+// row from the top left, at exactly the matrix's size. Video senders
+// stream frames this way.
+func RGB(pix []byte) Source {
+	return func(width, height int) (Frame, error) {
+		if len(pix) != width*height*3 {
+			return Frame{}, fmt.Errorf("RGB frame for a %dx%d matrix must be %d bytes, got %d", width, height, width*height*3, len(pix))
+		}
+		return Frame{Width: width, Height: height, Pix: pix}, nil
+	}
+}
+
+// Image shows an image scaled to the matrix, each pixel taking the average
+// color of the area of the image it covers.
+func Image(img image.Image) Source {
+	return func(width, height int) (Frame, error) {
+		bounds := img.Bounds()
+		if bounds.Empty() {
+			return Frame{}, errors.New("image is empty")
+		}
+		frame := Frame{Width: width, Height: height, Pix: make([]byte, width*height*3)}
+		for y := 0; y < height; y++ {
+			y0 := bounds.Min.Y + y*bounds.Dy()/height
+			y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+			for x := 0; x < width; x++ {
+				x0 := bounds.Min.X + x*bounds.Dx()/width
+				x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+				var r, g, b, n uint64
+				for sy := y0; sy < y1; sy++ {
+					for sx := x0; sx < x1; sx++ {
+						cr, cg, cb, _ := img.At(sx, sy).RGBA()
+						r, g, b, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), n+1
+					}
+				}
+				i := (y*width + x) * 3
+				frame.Pix[i] = byte((r / n) >> 8)
+				frame.Pix[i+1] = byte((g / n) >> 8)
+				frame.Pix[i+2] = byte((b / n) >> 8)
+			}
+		}
+		return frame, nil
+	}
+}
+
+// DecodeImage decodes a PNG, JPEG, or GIF image (the first frame of an
+// animated GIF).
+func DecodeImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	return img, nil
+}
+
+// Gradient shows a linear gradient through colors spaced evenly across the
+// matrix. The angle is in degrees: 0 runs left to right, 90 top to bottom.
+// A single color fills the matrix.
+func Gradient(colors []color.Color, angle float64) Source {
+	return func(width, height int) (Frame, error) {
+		if len(colors) == 0 {
+			return Frame{}, errors.New("a gradient needs at least one color")
+		}
+		if math.IsNaN(angle) || math.IsInf(angle, 0) {
+			return Frame{}, fmt.Errorf("invalid gradient angle: %v", angle)
+		}
+		dx, dy := math.Cos(angle*math.Pi/180), math.Sin(angle*math.Pi/180)
+
+		// Project pixel centers onto the direction, spanning the matrix's
+		// corners
+		project := func(x, y float64) float64 { return x*dx + y*dy }
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, corner := range [][2]float64{{0, 0}, {float64(width), 0}, {0, float64(height)}, {float64(width), float64(height)}} {
+			p := project(corner[0], corner[1])
+			lo, hi = math.Min(lo, p), math.Max(hi, p)
+		}
+
+		frame := Frame{Width: width, Height: height, Pix: make([]byte, width*height*3)}
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				pos := 0.0
+				if hi > lo {
+					pos = (project(float64(x)+0.5, float64(y)+0.5) - lo) / (hi - lo)
+				}
+				c := gradientAt(colors, pos)
+				i := (y*width + x) * 3
+				frame.Pix[i] = toByte(c.R)
+				frame.Pix[i+1] = toByte(c.G)
+				frame.Pix[i+2] = toByte(c.B)
+			}
+		}
+		return frame, nil
+	}
+}
+
+// gradientAt returns the color a fraction (0-1) of the way through evenly
+// spaced colors.
+func gradientAt(colors []color.Color, pos float64) color.Color {
+	if len(colors) == 1 {
+		return colors[0]
+	}
+	scaled := math.Max(0, math.Min(1, pos)) * float64(len(colors)-1)
+	i := min(int(scaled), len(colors)-2)
+	t := scaled - float64(i)
+	a, b := colors[i], colors[i+1]
+	return color.Color{
+		R: a.R + (b.R-a.R)*t,
+		G: a.G + (b.G-a.G)*t,
+		B: a.B + (b.B-a.B)*t,
+	}
+}
+
+// toByte converts a 0-1 component to 0-255.
+func toByte(v float64) byte {
+	return byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -0,0 +1,224 @@
+// Package pixelmap plays pictures on grids of LED fixtures.
+//
+// A matrix places fixtures, or the cells of multi-cell fixtures such as
+// pixel bars, on a grid of pixels. Images, gradients, and video frames
+// shown on a matrix are scaled to its size and each pixel's color is mapped
+// onto its cell's color channels, with the cell's intensity at full. A
+// matrix's output is an effect layer: it takes precedence over the live
+// look on the channels it drives until the matrix is released, and masters,
+// blackout, and output limits still apply on top.
+package pixelmap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// MaxSize bounds the width and height of a matrix.
+const MaxSize = 256
+
+// colorTypes are the channel types that make up a cell's color.
+var colorTypes = []string{
+	"RED", "GREEN", "BLUE", "WHITE", "WARM_WHITE", "COLD_WHITE", "AMBER",
+	"UV", "LIME", "INDIGO", "CYAN", "MAGENTA", "YELLOW",
+}
+
+// Pixel places a fixture's cell on a matrix. X and Y are 0-indexed from
+// the top left.
+type Pixel struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	FixtureID string `json:"fixtureId"`
+	Cell      int    `json:"cell"`
+}
+
+// Fixture is a fixture's address and channels.
+type Fixture struct {
+	ID           string
+	Universe     int
+	StartChannel int
+	Channels     []color.Channel
+	ChannelCells map[int]int // Defined cell by channel offset; empty unless the fixture defines cells
+}
+
+// Cells returns the fixture's cells: those its definition gives, else the
+// ones Cells finds in its channels.
+func (f Fixture) Cells() []Cell {
+	if len(f.ChannelCells) == 0 {
+		return Cells(f.Channels)
+	}
+	return DefinedCells(f.Channels, f.ChannelCells)
+}
+
+// Cell is the channels of one independently colored part of a fixture.
+type Cell struct {
+	Color     []color.Channel
+	Intensity []int // Offsets of the intensity channels the cell raises to full
+}
+
+// Cells splits a fixture's channels into its cells. A fixture has as many
+// cells as it has channels of its most repeated color type; the k-th
+// channel of each type repeated that often, in offset order, belongs to the
+// k-th cell. Color channels repeated less often are shared by the cells and
+// left alone. Intensity channels go to their cells when there is one per
+// cell, otherwise every cell raises all of them. A fixture without color
+// channels has no cells.
+func Cells(channels []color.Channel) []Cell {
+	sorted := slices.Clone(channels)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	byType := make(map[string][]color.Channel)
+	count := 0
+	for _, ch := range sorted {
+		if slices.Contains(colorTypes, ch.Type) || ch.Type == "INTENSITY" {
+			byType[ch.Type] = append(byType[ch.Type], ch)
+		}
+		if slices.Contains(colorTypes, ch.Type) {
+			count = max(count, len(byType[ch.Type]))
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	cells := make([]Cell, count)
+	for _, t := range colorTypes {
+		if len(byType[t]) != count {
+			continue
+		}
+		for k, ch := range byType[t] {
+			cells[k].Color = append(cells[k].Color, ch)
+		}
+	}
+	intensity := byType["INTENSITY"]
+	for k := range cells {
+		if len(intensity) == count {
+			cells[k].Intensity = []int{intensity[k].Offset}
+			continue
+		}
+		for _, ch := range intensity {
+			cells[k].Intensity = append(cells[k].Intensity, ch.Offset)
+		}
+	}
+	return cells
+}
+
+// DefinedCells splits a fixture's channels into the cells its definition
+// gives by channel offset. A cell raises its own intensity channels, or the
+// fixture's master intensity channels when it has none.
+func DefinedCells(channels []color.Channel, channelCells map[int]int) []Cell {
+	count := 0
+	for _, cell := range channelCells {
+		count = max(count, cell+1)
+	}
+	sorted := slices.Clone(channels)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	cells := make([]Cell, count)
+	var master []int
+	for _, ch := range sorted {
+		cell, ok := channelCells[ch.Offset]
+		switch {
+		case ch.Type == "INTENSITY" && !ok:
+			master = append(master, ch.Offset)
+		case ch.Type == "INTENSITY":
+			cells[cell].Intensity = append(cells[cell].Intensity, ch.Offset)
+		case ok && slices.Contains(colorTypes, ch.Type):
+			cells[cell].Color = append(cells[cell].Color, ch)
+		}
+	}
+	for k := range cells {
+		if len(cells[k].Intensity) == 0 {
+			cells[k].Intensity = master
+		}
+	}
+	return cells
+}
+
+// target is a pixel's cell at its DMX address.
+type target struct {
+	x, y         int
+	universe     int
+	startChannel int
+	cell         Cell
+}
+
+// Mapping is a matrix ready to render: its size and where each pixel's
+// cell is patched.
+type Mapping struct {
+	ID      string
+	Width   int
+	Height  int
+	targets []target
+}
+
+// NewMapping maps a matrix's pixels onto its fixtures' cells. Every pixel
+// must lie on the matrix and name a cell of one of the fixtures, and no
+// position may hold two pixels.
+func NewMapping(id string, width, height int, pixels []Pixel, fixtures []Fixture) (*Mapping, error) {
+	if width < 1 || height < 1 || width > MaxSize || height > MaxSize {
+		return nil, fmt.Errorf("matrix size must be between 1x1 and %dx%d, got %dx%d", MaxSize, MaxSize, width, height)
+	}
+	byID := make(map[string]Fixture, len(fixtures))
+	for _, f := range fixtures {
+		byID[f.ID] = f
+	}
+	cells := make(map[string][]Cell)
+	taken := make(map[[2]int]bool, len(pixels))
+
+	m := &Mapping{ID: id, Width: width, Height: height, targets: make([]target, 0, len(pixels))}
+	for _, p := range pixels {
+		if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
+			return nil, fmt.Errorf("pixel (%d, %d) lies outside the %dx%d matrix", p.X, p.Y, width, height)
+		}
+		if taken[[2]int{p.X, p.Y}] {
+			return nil, fmt.Errorf("pixel (%d, %d) is mapped more than once", p.X, p.Y)
+		}
+		taken[[2]int{p.X, p.Y}] = true
+
+		fixture, ok := byID[p.FixtureID]
+		if !ok {
+			return nil, fmt.Errorf("fixture not found: %s", p.FixtureID)
+		}
+		fixtureCells, ok := cells[fixture.ID]
+		if !ok {
+			fixtureCells = fixture.Cells()
+			cells[fixture.ID] = fixtureCells
+		}
+		if p.Cell < 0 || p.Cell >= len(fixtureCells) {
+			return nil, fmt.Errorf("pixel (%d, %d): fixture %s has %d color cells, no cell %d", p.X, p.Y, fixture.ID, len(fixtureCells), p.Cell)
+		}
+		m.targets = append(m.targets, target{
+			x:            p.X,
+			y:            p.Y,
+			universe:     fixture.Universe,
+			startChannel: fixture.StartChannel,
+			cell:         fixtureCells[p.Cell],
+		})
+	}
+	return m, nil
+}
+
+// Render converts a frame of the matrix's size into DMX values: universe
+// -> 1-indexed channel -> value.
+func (m *Mapping) Render(frame Frame) map[int]map[int]byte {
+	values := make(map[int]map[int]byte)
+	set := func(universe, channel int, value byte) {
+		if values[universe] == nil {
+			values[universe] = make(map[int]byte)
+		}
+		values[universe][channel] = value
+	}
+	for _, t := range m.targets {
+		for _, v := range frame.At(t.x, t.y).ChannelValues(t.cell.Color) {
+			set(t.universe, t.startChannel+v.Offset, byte(v.Value))
+		}
+		for _, offset := range t.cell.Intensity {
+			set(t.universe, t.startChannel+offset, 255)
+		}
+	}
+	return values
+}
@@ -0,0 +1,369 @@
+package pixelmap
+
+import (
+	"bytes"
+	"context"
+	"image"
+	imagecolor "image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// fakeOutput records the layers shown.
+type fakeOutput struct {
+	mu     sync.Mutex
+	layers map[string]map[int]map[int]byte
+}
+
+func newFakeOutput() *fakeOutput {
+	return &fakeOutput{layers: make(map[string]map[int]map[int]byte)}
+}
+
+func (o *fakeOutput) SetEffectLayer(id string, values map[int]map[int]byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.layers[id] = values
+}
+
+func (o *fakeOutput) ClearEffectLayer(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.layers, id)
+}
+
+func (o *fakeOutput) layer(id string) (map[int]map[int]byte, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	layer, ok := o.layers[id]
+	return layer, ok
+}
+
+// pixelBar is a four cell RGB bar with a master dimmer at offset 0.
+var pixelBar = []color.Channel{
+	{Offset: 0, Type: "INTENSITY"},
+	{Offset: 1, Type: "RED"}, {Offset: 2, Type: "GREEN"}, {Offset: 3, Type: "BLUE"},
+	{Offset: 4, Type: "RED"}, {Offset: 5, Type: "GREEN"}, {Offset: 6, Type: "BLUE"},
+	{Offset: 7, Type: "RED"}, {Offset: 8, Type: "GREEN"}, {Offset: 9, Type: "BLUE"},
+	{Offset: 10, Type: "RED"}, {Offset: 11, Type: "GREEN"}, {Offset: 12, Type: "BLUE"},
+}
+
+func TestCells(t *testing.T) {
+	cells := Cells(pixelBar)
+	if len(cells) != 4 {
+		t.Fatalf("got %d cells, want 4", len(cells))
+	}
+	if got := cells[2].Color; len(got) != 3 || got[0].Offset != 7 || got[2].Offset != 9 {
+		t.Errorf("cell 2 color = %+v, want offsets 7-9", got)
+	}
+	if got := cells[3].Intensity; len(got) != 1 || got[0] != 0 {
+		t.Errorf("cell 3 intensity = %v, want the master dimmer", got)
+	}
+
+	// One dimmer per cell; the shared white is left alone
+	cells = Cells([]color.Channel{
+		{Offset: 0, Type: "INTENSITY"}, {Offset: 1, Type: "RED"}, {Offset: 2, Type: "GREEN"}, {Offset: 3, Type: "BLUE"},
+		{Offset: 4, Type: "INTENSITY"}, {Offset: 5, Type: "RED"}, {Offset: 6, Type: "GREEN"}, {Offset: 7, Type: "BLUE"},
+		{Offset: 8, Type: "WHITE"},
+	})
+	if len(cells) != 2 {
+		t.Fatalf("got %d cells, want 2", len(cells))
+	}
+	if got := cells[1].Intensity; len(got) != 1 || got[0] != 4 {
+		t.Errorf("cell 1 intensity = %v, want [4]", got)
+	}
+	for _, ch := range cells[0].Color {
+		if ch.Type == "WHITE" {
+			t.Error("shared white should not belong to a cell")
+		}
+	}
+
+	if cells := Cells([]color.Channel{{Offset: 0, Type: "INTENSITY"}}); cells != nil {
+		t.Errorf("dimmer only fixture has cells %+v, want none", cells)
+	}
+}
+
+func TestDefinedCells(t *testing.T) {
+	// A batten whose definition gives two cells, the second with its own
+	// dimmer, and a shared white left to the master
+	channels := []color.Channel{
+		{Offset: 0, Type: "INTENSITY"}, {Offset: 1, Type: "WHITE"},
+		{Offset: 2, Type: "RED"}, {Offset: 3, Type: "GREEN"},
+		{Offset: 4, Type: "INTENSITY"}, {Offset: 5, Type: "RED"}, {Offset: 6, Type: "GREEN"},
+	}
+	fixture := Fixture{Channels: channels, ChannelCells: map[int]int{2: 0, 3: 0, 4: 1, 5: 1, 6: 1}}
+	cells := fixture.Cells()
+	if len(cells) != 2 {
+		t.Fatalf("got %d cells, want 2", len(cells))
+	}
+	if got := cells[0].Color; len(got) != 2 || got[0].Offset != 2 || got[1].Offset != 3 {
+		t.Errorf("cell 0 color = %+v, want offsets 2-3", got)
+	}
+	if got := cells[0].Intensity; len(got) != 1 || got[0] != 0 {
+		t.Errorf("cell 0 intensity = %v, want the master dimmer", got)
+	}
+	if got := cells[1].Intensity; len(got) != 1 || got[0] != 4 {
+		t.Errorf("cell 1 intensity = %v, want [4]", got)
+	}
+}
+
+func TestNewMappingValidates(t *testing.T) {
+	fixtures := []Fixture{{ID: "bar", Universe: 1, StartChannel: 1, Channels: pixelBar}}
+	tests := map[string]struct {
+		width, height int
+		pixels        []Pixel
+	}{
+		"too small":       {0, 1, nil},
+		"too large":       {MaxSize + 1, 1, nil},
+		"outside":         {4, 1, []Pixel{{X: 4, Y: 0, FixtureID: "bar"}}},
+		"taken twice":     {4, 1, []Pixel{{X: 0, FixtureID: "bar"}, {X: 0, FixtureID: "bar", Cell: 1}}},
+		"unknown fixture": {4, 1, []Pixel{{X: 0, FixtureID: "spot"}}},
+		"unknown cell":    {4, 1, []Pixel{{X: 0, FixtureID: "bar", Cell: 4}}},
+	}
+	for name, tt := range tests {
+		if _, err := NewMapping("m", tt.width, tt.height, tt.pixels, fixtures); err == nil {
+			t.Errorf("%s: NewMapping should fail", name)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	fixtures := []Fixture{{ID: "bar", Universe: 2, StartChannel: 11, Channels: pixelBar}}
+	pixels := []Pixel{
+		{X: 0, FixtureID: "bar", Cell: 0},
+		{X: 1, FixtureID: "bar", Cell: 1},
+	}
+	mapping, err := NewMapping("m", 2, 1, pixels, fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame, err := RGB([]byte{255, 0, 0, 0, 0, 128})(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := mapping.Render(frame)
+	want := map[int]byte{11: 255, 12: 255, 13: 0, 14: 0, 15: 0, 16: 0, 17: 128}
+	for channel, value := range want {
+		if got := values[2][channel]; got != value {
+			t.Errorf("channel %d = %d, want %d", channel, got, value)
+		}
+	}
+	if _, ok := values[2][18]; ok {
+		t.Error("unmapped cells should be left alone")
+	}
+}
+
+func TestRGBSize(t *testing.T) {
+	if _, err := RGB(make([]byte, 5))(2, 1); err == nil {
+		t.Error("RGB should reject a frame of the wrong size")
+	}
+}
+
+func TestImageScales(t *testing.T) {
+	// Left half red, right half blue, scaled to two pixels
+	img := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			c := imagecolor.RGBA{R: 255, A: 255}
+			if x >= 4 {
+				c = imagecolor.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	frame, err := Image(img)(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(frame.Pix, []byte{255, 0, 0, 0, 0, 255}) {
+		t.Errorf("scaled frame = %v", frame.Pix)
+	}
+
+	// Upscaling repeats pixels
+	frame, err = Image(img)(16, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Pix[0] != 255 || frame.Pix[15*3+2] != 255 {
+		t.Errorf("upscaled frame = %v", frame.Pix)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	black, white := color.Color{}, color.Color{R: 1, G: 1, B: 1}
+	frame, err := Gradient([]color.Color{black, white}, 0)(4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Left to right: pixel centers at 1/8, 3/8, 5/8, 7/8
+	for x, want := range []byte{32, 96, 159, 223} {
+		if got := frame.At(x, 1); toByte(got.R) != want {
+			t.Errorf("x=%d red = %d, want %d", x, toByte(got.R), want)
+		}
+	}
+
+	// Top to bottom
+	frame, err = Gradient([]color.Color{black, white}, 90)(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Pix[0] >= frame.Pix[3] {
+		t.Errorf("vertical gradient should brighten downwards: %v", frame.Pix)
+	}
+
+	if _, err := Gradient(nil, 0)(1, 1); err == nil {
+		t.Error("a gradient without colors should fail")
+	}
+}
+
+func newTestService(t *testing.T) (*Service, *fakeOutput) {
+	t.Helper()
+	output := newFakeOutput()
+	s := NewService(output)
+	fixtures := []Fixture{{ID: "bar", Universe: 1, StartChannel: 1, Channels: pixelBar}}
+	s.SetLoader(func(_ context.Context, matrixID string) (*Mapping, error) {
+		if matrixID != "m" {
+			return nil, ErrMatrixNotFound
+		}
+		return NewMapping(matrixID, 4, 1, []Pixel{
+			{X: 0, FixtureID: "bar"}, {X: 1, FixtureID: "bar", Cell: 1},
+			{X: 2, FixtureID: "bar", Cell: 2}, {X: 3, FixtureID: "bar", Cell: 3},
+		}, fixtures)
+	})
+	return s, output
+}
+
+func TestShowAndRelease(t *testing.T) {
+	s, output := newTestService(t)
+
+	green, _ := color.ParseHex("#00FF00")
+	if err := s.Show(context.Background(), "m", Gradient([]color.Color{green}, 0)); err != nil {
+		t.Fatal(err)
+	}
+	layer, ok := output.layer("pixelmap:m")
+	if !ok || layer[1][3] != 255 || layer[1][12] != 255 || layer[1][2] != 0 {
+		t.Errorf("layer = %v, want every cell green", layer)
+	}
+	if !s.IsActive("m") || len(s.Active()) != 1 {
+		t.Error("matrix should be active")
+	}
+
+	if err := s.Show(context.Background(), "other", RGB(nil)); err != ErrMatrixNotFound {
+		t.Errorf("Show(other) = %v, want ErrMatrixNotFound", err)
+	}
+	if err := s.Show(context.Background(), "m", RGB(make([]byte, 3))); err == nil {
+		t.Error("Show should reject a frame of the wrong size")
+	}
+
+	if !s.Release("m") {
+		t.Error("Release should report the matrix was showing")
+	}
+	if _, ok := output.layer("pixelmap:m"); ok {
+		t.Error("Release should clear the layer")
+	}
+	if s.Release("m") {
+		t.Error("second Release should report nothing was showing")
+	}
+
+	// Forget drops the cached mapping and releases
+	_ = s.Show(context.Background(), "m", RGB(make([]byte, 12)))
+	s.Forget("m")
+	if s.IsActive("m") {
+		t.Error("Forget should release the matrix")
+	}
+}
+
+func TestServeFrame(t *testing.T) {
+	s, output := newTestService(t)
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, imagecolor.RGBA{B: 255, A: 255})
+	}
+	var body bytes.Buffer
+	if err := png.Encode(&body, img); err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(query, contentType string, body []byte) int {
+		req := httptest.NewRequest(http.MethodPost, FramePath+query, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		s.ServeFrame(rec, req)
+		return rec.Code
+	}
+
+	if code := post("?matrix=m", "image/png", body.Bytes()); code != http.StatusNoContent {
+		t.Fatalf("posting an image returned %d", code)
+	}
+	if layer, _ := output.layer("pixelmap:m"); layer[1][4] != 255 || layer[1][2] != 0 {
+		t.Errorf("layer = %v, want every cell blue", layer)
+	}
+
+	raw := bytes.Repeat([]byte{255, 0, 0}, 4)
+	if code := post("?matrix=m", "application/octet-stream", raw); code != http.StatusNoContent {
+		t.Fatalf("posting a raw frame returned %d", code)
+	}
+	if layer, _ := output.layer("pixelmap:m"); layer[1][2] != 255 {
+		t.Errorf("layer = %v, want every cell red", layer)
+	}
+
+	if code := post("", "image/png", body.Bytes()); code != http.StatusBadRequest {
+		t.Errorf("missing matrix returned %d, want 400", code)
+	}
+	if code := post("?matrix=other", "image/png", body.Bytes()); code != http.StatusNotFound {
+		t.Errorf("unknown matrix returned %d, want 404", code)
+	}
+	if code := post("?matrix=m", "image/png", []byte("not an image")); code != http.StatusBadRequest {
+		t.Errorf("invalid image returned %d, want 400", code)
+	}
+
+	s.SetAuthorizer(func(*http.Request) error { return ErrMatrixNotFound })
+	if code := post("?matrix=m", "image/png", body.Bytes()); code != http.StatusUnauthorized {
+		t.Errorf("unauthorized request returned %d, want 401", code)
+	}
+}
+
+func TestServeStream(t *testing.T) {
+	s, output := newTestService(t)
+	server := httptest.NewServer(http.HandlerFunc(s.ServeStream))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	if _, resp, err := websocket.DefaultDialer.Dial(url+"?matrix=other", nil); err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("streaming to an unknown matrix should fail with 404, got %v", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?matrix=m", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// A frame of the wrong size is skipped, the next one shown
+	frames := [][]byte{make([]byte, 5), bytes.Repeat([]byte{0, 0, 255}, 4)}
+	for _, frame := range frames {
+		if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if layer, ok := output.layer("pixelmap:m"); ok && layer[1][4] == 255 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("streamed frame was not shown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,183 @@
+package pixelmap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrMatrixNotFound is returned by a Loader for a matrix that does not
+// exist.
+var ErrMatrixNotFound = errors.New("pixel matrix not found")
+
+// Output is where matrices are shown. *dmx.Service implements it.
+type Output interface {
+	SetEffectLayer(id string, values map[int]map[int]byte)
+	ClearEffectLayer(id string)
+}
+
+// Loader builds the mapping of a matrix from its saved definition.
+type Loader func(ctx context.Context, matrixID string) (*Mapping, error)
+
+// Service shows frames on matrices. Mappings are loaded on first use and
+// kept until Forget, so streamed frames do not reload them.
+type Service struct {
+	output   Output
+	upgrader websocket.Upgrader
+
+	mu        sync.Mutex
+	load      Loader
+	mappings  map[string]*Mapping
+	forgets   int // Counts Forget calls, so loads they overtake are not cached
+	active    map[string]bool
+	authorize func(r *http.Request) error
+}
+
+// NewService creates a pixel map service showing matrices on an output.
+func NewService(output Output) *Service {
+	return &Service{
+		output: output,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Video senders run on any origin, as GraphQL allows
+			},
+			ReadBufferSize: maxFrameSize,
+		},
+		mappings: make(map[string]*Mapping),
+		active:   make(map[string]bool),
+	}
+}
+
+// SetLoader sets how matrices are loaded.
+func (s *Service) SetLoader(load Loader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load = load
+}
+
+// SetAuthorizer sets the check a frame request must pass (optional).
+func (s *Service) SetAuthorizer(authorize func(r *http.Request) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorize = authorize
+}
+
+// layerID returns the DMX effect layer of a matrix.
+func layerID(matrixID string) string {
+	return "pixelmap:" + matrixID
+}
+
+// Show renders a source at a matrix's size and shows it until the matrix
+// is released or shows another frame.
+func (s *Service) Show(ctx context.Context, matrixID string, source Source) error {
+	mapping, err := s.mapping(ctx, matrixID)
+	if err != nil {
+		return err
+	}
+	frame, err := source(mapping.Width, mapping.Height)
+	if err != nil {
+		return err
+	}
+	values := mapping.Render(frame)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A matrix forgotten while its frame rendered is shown from scratch
+	// next time rather than with the old mapping
+	if s.mappings[matrixID] != mapping {
+		return nil
+	}
+	s.active[matrixID] = true
+	s.output.SetEffectLayer(layerID(matrixID), values)
+	return nil
+}
+
+// Release stops showing a matrix, revealing the live look. It reports
+// whether the matrix was showing.
+func (s *Service) Release(matrixID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseLocked(matrixID)
+}
+
+func (s *Service) releaseLocked(matrixID string) bool {
+	if !s.active[matrixID] {
+		return false
+	}
+	delete(s.active, matrixID)
+	s.output.ClearEffectLayer(layerID(matrixID))
+	return true
+}
+
+// Forget releases a matrix and drops its mapping, for when it is edited or
+// deleted.
+func (s *Service) Forget(matrixID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mappings, matrixID)
+	s.forgets++
+	s.releaseLocked(matrixID)
+}
+
+// IsActive reports whether a matrix is showing a frame.
+func (s *Service) IsActive(matrixID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active[matrixID]
+}
+
+// Active returns the IDs of the matrices showing frames, sorted.
+func (s *Service) Active() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.active))
+	for id := range s.active {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Cleanup releases every matrix.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.active {
+		s.releaseLocked(id)
+	}
+	s.mappings = make(map[string]*Mapping)
+	s.forgets++
+}
+
+// mapping returns a matrix's mapping, loading it when it is not cached.
+func (s *Service) mapping(ctx context.Context, matrixID string) (*Mapping, error) {
+	s.mu.Lock()
+	mapping, ok := s.mappings[matrixID]
+	load := s.load
+	forgets := s.forgets
+	s.mu.Unlock()
+	if ok {
+		return mapping, nil
+	}
+	if load == nil {
+		return nil, ErrMatrixNotFound
+	}
+
+	mapping, err := load(ctx, matrixID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.mappings[matrixID]; ok {
+		return cached, nil
+	}
+	if s.forgets == forgets {
+		s.mappings[matrixID] = mapping
+	}
+	return mapping, nil
+}
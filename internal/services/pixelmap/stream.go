@@ -0,0 +1,160 @@
+package pixelmap
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// HTTP paths of the frame endpoints. Both take the matrix in a matrix query
+// parameter.
+const (
+	// FramePath accepts one frame per POST: a PNG, JPEG, or GIF image, or a
+	// raw RGB frame sent as application/octet-stream.
+	FramePath = "/pixelmap/frame"
+	// StreamPath accepts a websocket whose binary messages are raw RGB
+	// frames, for video.
+	StreamPath = "/pixelmap/stream"
+)
+
+const (
+	// maxFrameSize bounds raw RGB frames, the size of the largest matrix.
+	maxFrameSize = MaxSize * MaxSize * 3
+	// maxImageSize bounds images posted to FramePath.
+	maxImageSize = 16 << 20
+	// pingInterval is how often idle streams are checked.
+	pingInterval = 10 * time.Second
+	// pongWait is how long a sender may go without answering a ping.
+	pongWait = 3 * pingInterval
+	// writeWait bounds how long a ping may take to reach a sender.
+	writeWait = 5 * time.Second
+)
+
+// ServeFrame shows the frame posted to a matrix.
+func (s *Service) ServeFrame(w http.ResponseWriter, r *http.Request) {
+	matrixID, ok := s.authorizeRequest(w, r)
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "frames must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImageSize))
+	if err != nil {
+		http.Error(w, "frame is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	source := RGB(data)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/octet-stream") {
+		img, err := DecodeImage(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		source = Image(img)
+	}
+
+	if err := s.Show(r.Context(), matrixID, source); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrMatrixNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeStream shows the raw RGB frames streamed to a matrix over a
+// websocket. Frames of the wrong size are skipped. The matrix keeps its last
+// frame when the stream ends, until it is released.
+func (s *Service) ServeStream(w http.ResponseWriter, r *http.Request) {
+	matrixID, ok := s.authorizeRequest(w, r)
+	if !ok {
+		return
+	}
+	// Check the matrix before upgrading, so senders get a plain error
+	if _, err := s.mapping(r.Context(), matrixID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrMatrixNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go pingLoop(conn, done)
+
+	conn.SetReadLimit(maxFrameSize)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+		if err := s.Show(r.Context(), matrixID, RGB(data)); err != nil {
+			log.Warn("skipping pixel map frame", "matrix", matrixID, "error", err)
+		}
+	}
+}
+
+// authorizeRequest checks a frame request, returning its matrix. It
+// answers the request itself when the check fails.
+func (s *Service) authorizeRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	s.mu.Lock()
+	authorize := s.authorize
+	s.mu.Unlock()
+	if authorize != nil {
+		if err := authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return "", false
+		}
+	}
+
+	matrixID := r.URL.Query().Get("matrix")
+	if matrixID == "" {
+		http.Error(w, "matrix is required", http.StatusBadRequest)
+		return "", false
+	}
+	return matrixID, true
+}
+
+// pingLoop pings a stream's sender until done is closed.
+func pingLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
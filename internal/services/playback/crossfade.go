@@ -0,0 +1,174 @@
+package playback
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+	"gorm.io/gorm"
+)
+
+// Cue list playback modes
+const (
+	PlaybackModeTimed      = "TIMED"      // Cues fade in over their fade times
+	PlaybackModeCrossfader = "CROSSFADER" // A manual crossfader moves between the current and next cue
+)
+
+// crossfade is a cue list's armed manual crossfade from its current cue to
+// the next one.
+type crossfade struct {
+	fadeID      string
+	cueListName string
+	cueCount    int
+	nextIndex   int
+	nextCue     models.Cue
+	channels    []fade.SceneChannel
+	liveEnd     float64 // Crossfader end (0 or 1) at which the current cue is fully live
+}
+
+// SetCrossfadePosition moves a crossfader-mode cue list's crossfader to
+// position (0-1). The crossfader works as an A/B pair: moving it away from
+// the end where the current cue is live crossfades to the next cue, which
+// becomes the current cue once the other end is reached. The following
+// crossfade then runs back in the opposite direction.
+func (s *Service) SetCrossfadePosition(ctx context.Context, cueListID string, position float64) error {
+	if math.IsNaN(position) || position < 0 || position > 1 {
+		return fmt.Errorf("crossfade position must be between 0 and 1, got %v", position)
+	}
+
+	s.crossfadeMu.Lock()
+	defer s.crossfadeMu.Unlock()
+
+	s.mu.RLock()
+	xf := s.crossfades[cueListID]
+	s.mu.RUnlock()
+
+	var progress float64
+	applied := false
+	if xf != nil {
+		progress = math.Abs(position - xf.liveEnd)
+		applied = s.fadeEngine.SetManualFadeProgress(xf.fadeID, progress)
+	}
+	if !applied {
+		// Nothing armed yet, or other fades took over every channel
+		var err error
+		if xf, err = s.armCrossfade(ctx, cueListID); err != nil {
+			return err
+		}
+		progress = math.Abs(position - xf.liveEnd)
+		s.fadeEngine.SetManualFadeProgress(xf.fadeID, progress)
+	}
+
+	s.mu.Lock()
+	s.crossfadePositions[cueListID] = position
+	complete := progress >= 1
+	if complete {
+		delete(s.crossfades, cueListID)
+	} else if state := s.states[cueListID]; state != nil {
+		state.IsFading = progress > 0
+		state.FadeProgress = progress * 100
+		state.LastUpdated = time.Now()
+	}
+	s.mu.Unlock()
+
+	if complete {
+		// The crossfade already set the output; follows wait for the operator
+		if stackService := s.stackService(); stackService != nil {
+			stackService.Record(stack.Activation{
+				PlaybackID: stack.CueListPlayback(cueListID),
+				Kind:       stack.KindCueList,
+				SceneID:    xf.nextCue.SceneID,
+				Channels:   xf.channels,
+			})
+		}
+		s.dmxService.SetActiveScene(xf.nextCue.SceneID)
+		s.StartCue(cueListID, xf.cueListName, xf.cueCount, xf.nextIndex, &CueForPlayback{
+			ID:          xf.nextCue.ID,
+			Name:        xf.nextCue.Name,
+			CueNumber:   xf.nextCue.CueNumber,
+			FadeOutTime: xf.nextCue.FadeOutTime,
+		})
+	} else {
+		s.emitUpdate(cueListID)
+	}
+	s.RecordEvent(Event{Type: EventCrossfade, CueListID: cueListID, Position: &position})
+	return nil
+}
+
+// armCrossfade prepares a manual crossfade from a cue list's current output
+// to its next cue.
+func (s *Service) armCrossfade(ctx context.Context, cueListID string) (*crossfade, error) {
+	var cueList models.CueList
+	result := s.db.WithContext(ctx).
+		Preload("Cues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cue_number ASC")
+		}).
+		First(&cueList, "id = ?", cueListID)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("cue list not found: %w", result.Error)
+	}
+	if cueList.PlaybackMode != PlaybackModeCrossfader {
+		return nil, fmt.Errorf("cue list %s is not in crossfader mode", cueList.Name)
+	}
+
+	s.mu.RLock()
+	currentIndex := -1
+	if state := s.states[cueListID]; state != nil && state.CurrentCueIndex != nil {
+		currentIndex = *state.CurrentCueIndex
+	}
+	liveEnd := math.Round(s.crossfadePositions[cueListID])
+	s.mu.RUnlock()
+
+	nextIndex := currentIndex + 1
+	if nextIndex >= len(cueList.Cues) {
+		if cueList.Loop && len(cueList.Cues) > 0 {
+			nextIndex = 0
+		} else {
+			return nil, fmt.Errorf("no more cues in the list")
+		}
+	}
+
+	_, sceneChannels, easingType, err := s.loadCueChannels(ctx, cueList.Cues[nextIndex].ID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]fade.ChannelTarget, len(sceneChannels))
+	for i, ch := range sceneChannels {
+		targets[i] = fade.ChannelTarget{
+			Universe:     ch.Universe,
+			Channel:      ch.Channel,
+			TargetValue:  ch.Value,
+			FadeBehavior: ch.FadeBehavior,
+		}
+	}
+
+	xf := &crossfade{
+		fadeID:      fmt.Sprintf("crossfade-%s", cueListID),
+		cueListName: cueList.Name,
+		cueCount:    len(cueList.Cues),
+		nextIndex:   nextIndex,
+		nextCue:     cueList.Cues[nextIndex],
+		channels:    sceneChannels,
+		liveEnd:     liveEnd,
+	}
+	s.fadeEngine.StartManualFade(targets, xf.fadeID, easingType, nil)
+
+	s.mu.Lock()
+	s.crossfades[cueListID] = xf
+	s.mu.Unlock()
+	return xf, nil
+}
+
+// disarmCrossfadeLocked abandons a cue list's armed crossfade, leaving its
+// channels where the crossfader last put them.
+func (s *Service) disarmCrossfadeLocked(cueListID string) {
+	if xf := s.crossfades[cueListID]; xf != nil {
+		s.fadeEngine.CancelFade(xf.fadeID)
+		delete(s.crossfades, cueListID)
+	}
+}
@@ -0,0 +1,240 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventLogFormat identifies the playback log artifact format.
+const EventLogFormat = "lacylights-playback-log/1"
+
+// maxLoggedEvents bounds the in-memory playback log; the oldest events are
+// discarded once it is full.
+const maxLoggedEvents = 10000
+
+// EventType identifies a recorded playback command.
+type EventType string
+
+const (
+	EventStartCueList   EventType = "START_CUE_LIST"
+	EventNextCue        EventType = "NEXT_CUE"
+	EventPreviousCue    EventType = "PREVIOUS_CUE"
+	EventJumpToCue      EventType = "JUMP_TO_CUE"
+	EventGoToCueNumber  EventType = "GO_TO_CUE_NUMBER"
+	EventGoToCueName    EventType = "GO_TO_CUE_NAME"
+	EventFollow         EventType = "FOLLOW" // Automatic advance after a cue's follow time
+	EventStopCueList    EventType = "STOP_CUE_LIST"
+	EventPauseCueList   EventType = "PAUSE_CUE_LIST"
+	EventResumeCueList  EventType = "RESUME_CUE_LIST"
+	EventReleaseCueList EventType = "RELEASE_CUE_LIST"
+	EventPlayCue        EventType = "PLAY_CUE"
+	EventSetChannel     EventType = "SET_CHANNEL"
+	EventFadeToBlack    EventType = "FADE_TO_BLACK"
+	EventCrossfade      EventType = "CROSSFADE" // Manual crossfader move of a crossfader-mode cue list
+)
+
+// Event is a single playback command in the event log.
+type Event struct {
+	Seq         int       `json:"seq"`
+	Type        EventType `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	CueListID   string    `json:"cueListId,omitempty"`
+	CueID       string    `json:"cueId,omitempty"`
+	CueIndex    *int      `json:"cueIndex,omitempty"`
+	CueNumber   *float64  `json:"cueNumber,omitempty"`
+	CueName     string    `json:"cueName,omitempty"`
+	FadeTime    *float64  `json:"fadeTime,omitempty"`    // Fade time override in seconds
+	FadeOutTime *float64  `json:"fadeOutTime,omitempty"` // Fade-out time override in seconds
+	Universe    int       `json:"universe,omitempty"`
+	Channel     int       `json:"channel,omitempty"`
+	Value       int       `json:"value,omitempty"`
+	Position    *float64  `json:"position,omitempty"` // Crossfader position, 0-1
+}
+
+// EventLog is an ordered, serializable list of playback commands.
+type EventLog struct {
+	Format  string  `json:"format"`
+	Dropped int     `json:"dropped"` // Events discarded because the log was full
+	Events  []Event `json:"events"`
+}
+
+// ToJSON serializes the log for attaching to bug reports or test fixtures.
+func (l *EventLog) ToJSON() (string, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ParseEventLog decodes a playback log produced by EventLog.ToJSON.
+func ParseEventLog(content string) (*EventLog, error) {
+	var l EventLog
+	if err := json.Unmarshal([]byte(content), &l); err != nil {
+		return nil, fmt.Errorf("invalid playback log: %w", err)
+	}
+	if l.Format != EventLogFormat {
+		return nil, fmt.Errorf("unsupported playback log format: %q", l.Format)
+	}
+	return &l, nil
+}
+
+// RecordEvent appends a command to the playback log. Commands are ignored
+// while a log is being replayed so a replay does not record itself.
+func (s *Service) RecordEvent(event Event) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	if s.replaying {
+		return
+	}
+
+	s.logSeq++
+	event.Seq = s.logSeq
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if len(s.eventLog) >= maxLoggedEvents {
+		s.eventLog = s.eventLog[1:]
+		s.logDropped++
+	}
+	s.eventLog = append(s.eventLog, event)
+}
+
+// EventLog returns a snapshot of the recorded playback commands.
+func (s *Service) EventLog() *EventLog {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	events := make([]Event, len(s.eventLog))
+	copy(events, s.eventLog)
+	return &EventLog{
+		Format:  EventLogFormat,
+		Dropped: s.logDropped,
+		Events:  events,
+	}
+}
+
+// ClearEventLog discards all recorded playback commands.
+func (s *Service) ClearEventLog() {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	s.eventLog = nil
+	s.logDropped = 0
+}
+
+// isReplaying reports whether a playback log replay is in progress.
+func (s *Service) isReplaying() bool {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	return s.replaying
+}
+
+// Replay resets output to black and re-executes the log's commands in order.
+// Recorded timing is ignored and follow times are not scheduled; automatic
+// advances are reproduced from the log's FOLLOW events instead, so the
+// resulting output depends only on the log and the project data. When
+// instant is true every fade completes immediately.
+func (s *Service) Replay(ctx context.Context, l *EventLog, instant bool) error {
+	s.logMu.Lock()
+	if s.replaying {
+		s.logMu.Unlock()
+		return fmt.Errorf("a playback log replay is already in progress")
+	}
+	s.replaying = true
+	s.logMu.Unlock()
+
+	defer func() {
+		s.logMu.Lock()
+		s.replaying = false
+		s.logMu.Unlock()
+	}()
+
+	s.StopAllCueLists()
+	s.fadeEngine.FadeToBlack(0, "")
+	s.dmxService.FadeToBlack()
+	s.dmxService.ClearActiveScene()
+	if stackService := s.stackService(); stackService != nil {
+		stackService.Clear()
+	}
+
+	for _, event := range l.Events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.replayEvent(ctx, event, instant); err != nil {
+			return fmt.Errorf("replay event %d (%s): %w", event.Seq, event.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// replayEvent executes a single recorded command.
+func (s *Service) replayEvent(ctx context.Context, event Event, instant bool) error {
+	fadeTime := event.FadeTime
+	if instant {
+		zero := 0.0
+		fadeTime = &zero
+	}
+
+	switch event.Type {
+	case EventStartCueList:
+		return s.StartCueList(ctx, event.CueListID, event.CueNumber, fadeTime, event.FadeOutTime)
+	case EventNextCue:
+		return s.NextCue(ctx, event.CueListID, fadeTime, event.FadeOutTime)
+	case EventPreviousCue:
+		return s.PreviousCue(ctx, event.CueListID, fadeTime, event.FadeOutTime)
+	case EventJumpToCue, EventFollow:
+		if event.CueIndex == nil {
+			return fmt.Errorf("missing cue index")
+		}
+		return s.JumpToCue(ctx, event.CueListID, *event.CueIndex, fadeTime, event.FadeOutTime)
+	case EventGoToCueNumber:
+		if event.CueNumber == nil {
+			return fmt.Errorf("missing cue number")
+		}
+		return s.GoToCueNumber(ctx, event.CueListID, *event.CueNumber, fadeTime, event.FadeOutTime)
+	case EventGoToCueName:
+		return s.GoToCueName(ctx, event.CueListID, event.CueName, fadeTime, event.FadeOutTime)
+	case EventStopCueList:
+		s.StopCueList(event.CueListID)
+	case EventPauseCueList:
+		return s.PauseCueList(event.CueListID)
+	case EventResumeCueList:
+		s.ResumeCueList(event.CueListID)
+	case EventReleaseCueList:
+		s.ReleaseCueList(event.CueListID, fadeTime)
+	case EventPlayCue:
+		return s.ExecuteCueDmx(ctx, event.CueID, fadeTime)
+	case EventSetChannel:
+		if event.Value < 0 || event.Value > 255 {
+			return fmt.Errorf("invalid channel value: %d", event.Value)
+		}
+		s.dmxService.SetProgrammerValue(event.Universe, event.Channel, byte(event.Value))
+	case EventFadeToBlack:
+		duration := time.Duration(0)
+		if fadeTime != nil {
+			duration = time.Duration(*fadeTime * float64(time.Second))
+		}
+		s.fadeEngine.FadeToBlack(duration, "")
+		if duration == 0 {
+			s.dmxService.FadeToBlack()
+		}
+		s.dmxService.ClearActiveScene()
+		if stackService := s.stackService(); stackService != nil {
+			stackService.Clear()
+		}
+	case EventCrossfade:
+		if event.Position == nil {
+			return fmt.Errorf("missing crossfade position")
+		}
+		return s.SetCrossfadePosition(ctx, event.CueListID, *event.Position)
+	default:
+		return fmt.Errorf("unknown event type")
+	}
+	return nil
+}
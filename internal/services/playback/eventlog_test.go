@@ -0,0 +1,128 @@
+package playback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/lucsky/cuid"
+)
+
+func TestEventLog_RecordsCommands(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+
+	if err := service.StartCueList(ctx, cueList.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	if err := service.NextCue(ctx, cueList.ID, nil, nil); err != nil {
+		t.Fatalf("Failed to advance cue: %v", err)
+	}
+	// Failed commands are not recorded
+	if err := service.NextCue(ctx, cueList.ID, nil, nil); err == nil {
+		t.Fatal("Expected error advancing past the last cue")
+	}
+	service.StopCueList(cueList.ID)
+
+	eventLog := service.EventLog()
+	want := []EventType{EventStartCueList, EventNextCue, EventStopCueList}
+	if len(eventLog.Events) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(eventLog.Events), eventLog.Events)
+	}
+	for i, eventType := range want {
+		if eventLog.Events[i].Type != eventType {
+			t.Errorf("Event %d type = %s, want %s", i, eventLog.Events[i].Type, eventType)
+		}
+		if eventLog.Events[i].Seq != i+1 {
+			t.Errorf("Event %d seq = %d, want %d", i, eventLog.Events[i].Seq, i+1)
+		}
+	}
+
+	service.ClearEventLog()
+	if n := len(service.EventLog().Events); n != 0 {
+		t.Errorf("Expected empty log after clear, got %d events", n)
+	}
+}
+
+func TestEventLog_ReplayReproducesOutput(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	fixture, scene1 := createTestFixtureWithScene(t, testDB, project)
+
+	scene2 := &models.Scene{ID: cuid.New(), ProjectID: project.ID, Name: "Scene 2"}
+	if err := testDB.DB.Create(scene2).Error; err != nil {
+		t.Fatalf("Failed to create scene: %v", err)
+	}
+	fixtureValue := &models.FixtureValue{
+		ID:        cuid.New(),
+		SceneID:   scene2.ID,
+		FixtureID: fixture.ID,
+		Channels:  `[{"offset":0,"value":10},{"offset":1,"value":20}]`,
+	}
+	if err := testDB.DB.Create(fixtureValue).Error; err != nil {
+		t.Fatalf("Failed to create fixture value: %v", err)
+	}
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2}, false)
+
+	zero := 0.0
+	if err := service.StartCueList(ctx, cueList.ID, nil, &zero, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	if err := service.NextCue(ctx, cueList.ID, &zero, nil); err != nil {
+		t.Fatalf("Failed to advance cue: %v", err)
+	}
+	service.dmxService.SetChannelValue(1, 100, 77)
+	service.RecordEvent(Event{Type: EventSetChannel, Universe: 1, Channel: 100, Value: 77})
+
+	content, err := service.EventLog().ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+
+	// Scramble the output, then replay the log
+	service.dmxService.SetChannelValue(1, 1, 200)
+	service.dmxService.SetChannelValue(1, 100, 0)
+
+	eventLog, err := ParseEventLog(content)
+	if err != nil {
+		t.Fatalf("ParseEventLog() error: %v", err)
+	}
+	if err := service.Replay(ctx, eventLog, true); err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+
+	if got := service.dmxService.GetChannelValue(1, 1); got != 10 {
+		t.Errorf("Channel 1 = %d, want 10", got)
+	}
+	if got := service.dmxService.GetChannelValue(1, 2); got != 20 {
+		t.Errorf("Channel 2 = %d, want 20", got)
+	}
+	if got := service.dmxService.GetUniverse(1)[99]; got != 77 {
+		t.Errorf("Channel 100 = %d, want 77", got)
+	}
+
+	state := service.GetPlaybackState(cueList.ID)
+	if state == nil || state.CurrentCueIndex == nil || *state.CurrentCueIndex != 1 {
+		t.Errorf("Expected replay to finish on cue index 1, got %+v", state)
+	}
+	if n := len(service.EventLog().Events); n != 3 {
+		t.Errorf("Replay should not record events; log has %d events, want 3", n)
+	}
+}
+
+func TestParseEventLog_Invalid(t *testing.T) {
+	if _, err := ParseEventLog("not json"); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if _, err := ParseEventLog(`{"format":"other/1","events":[]}`); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,130 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"gorm.io/gorm"
+)
+
+// JournalKindActiveCue is the journal kind holding the active cue of each
+// playing cue list, keyed by cue list ID.
+const JournalKindActiveCue = "active_cue"
+
+// JournalCue is the journaled active cue of a cue list.
+type JournalCue struct {
+	CueID    string `json:"cueId"`
+	CueIndex int    `json:"cueIndex"`
+}
+
+// SetJournal sets the state journal that records the active cue of each cue
+// list (optional).
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.journal = j
+}
+
+// journalActiveCue records or clears the active cue of a cue list, in the
+// journal and for replication.
+func (s *Service) journalActiveCue(cueListID string, cue *JournalCue) {
+	s.mu.RLock()
+	j := s.journal
+	replicator := s.replicator
+	s.mu.RUnlock()
+
+	if j != nil {
+		if err := recordActiveCue(j, cueListID, cue); err != nil {
+			log.Warn("failed to journal playback state", "cueList", cueListID, "error", err)
+		}
+	}
+	if replicator != nil {
+		if err := recordActiveCue(replicator, cueListID, cue); err != nil {
+			log.Warn("failed to replicate playback state", "cueList", cueListID, "error", err)
+		}
+	}
+}
+
+// recordActiveCue puts or deletes the active cue of a cue list.
+func recordActiveCue(recorder StateRecorder, cueListID string, cue *JournalCue) error {
+	if cue != nil {
+		return recorder.Put(JournalKindActiveCue, cueListID, cue)
+	}
+	return recorder.Delete(JournalKindActiveCue, cueListID)
+}
+
+// RestoreFromJournal re-activates the cues the journal recorded as live,
+// snapping them in without a fade. Cues are matched by ID so edits to the
+// cue list since the journal was written do not restore the wrong cue;
+// entries whose cue list or cue no longer exists are dropped. Restored cues
+// schedule their follow times as if they had just been played.
+func (s *Service) RestoreFromJournal(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	j := s.journal
+	s.mu.RUnlock()
+	if j == nil {
+		return 0, nil
+	}
+
+	restored := 0
+	for cueListID, raw := range j.Entries(JournalKindActiveCue) {
+		if err := ctx.Err(); err != nil {
+			return restored, err
+		}
+		var entry JournalCue
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			log.Warn("invalid journaled cue", "cueList", cueListID, "error", err)
+			s.journalActiveCue(cueListID, nil)
+			continue
+		}
+		if err := s.restoreCue(ctx, cueListID, entry); err != nil {
+			log.Warn("cannot restore cue list", "cueList", cueListID, "error", err)
+			s.journalActiveCue(cueListID, nil)
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func (s *Service) restoreCue(ctx context.Context, cueListID string, entry JournalCue) error {
+	var cueList models.CueList
+	result := s.db.WithContext(ctx).
+		Preload("Cues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cue_number ASC")
+		}).
+		First(&cueList, "id = ?", cueListID)
+	if result.Error != nil {
+		return fmt.Errorf("cue list not found: %w", result.Error)
+	}
+
+	cueIndex := -1
+	for i, cue := range cueList.Cues {
+		if cue.ID == entry.CueID {
+			cueIndex = i
+			break
+		}
+	}
+	if cueIndex < 0 {
+		return fmt.Errorf("cue not found: %s", entry.CueID)
+	}
+
+	cue := cueList.Cues[cueIndex]
+	instant := 0.0
+	if err := s.ExecuteCueDmx(ctx, cue.ID, &instant); err != nil {
+		return err
+	}
+	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), cueIndex, &CueForPlayback{
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     instant,
+		FadeOutTime:    cue.FadeOutTime,
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
+	})
+	return nil
+}
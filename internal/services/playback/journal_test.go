@@ -0,0 +1,93 @@
+package playback
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+)
+
+func TestJournal_RestoresActiveCue(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene, scene}, false)
+	stopped := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+
+	path := filepath.Join(t.TempDir(), "state.journal")
+	j, err := journal.Open(path, journal.SyncAlways, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	service.SetJournal(j)
+
+	if err := service.JumpToCue(ctx, cueList.ID, 2, nil, nil); err != nil {
+		t.Fatalf("JumpToCue() error: %v", err)
+	}
+	if err := service.StartCueList(ctx, stopped.ID, nil, nil, nil); err != nil {
+		t.Fatalf("StartCueList() error: %v", err)
+	}
+	service.StopCueList(stopped.ID)
+
+	// Simulate a restart: fresh playback state, journal reopened from disk
+	service.Cleanup()
+	_ = j.Close()
+	reopened, err := journal.Open(path, journal.SyncAlways, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	service.SetJournal(reopened)
+
+	restored, err := service.RestoreFromJournal(ctx)
+	if err != nil {
+		t.Fatalf("RestoreFromJournal() error: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("Expected 1 restored cue list, got %d", restored)
+	}
+
+	state := service.GetPlaybackState(cueList.ID)
+	if state == nil || !state.IsPlaying || state.CurrentCueIndex == nil || *state.CurrentCueIndex != 2 {
+		t.Fatalf("Expected cue index 2 playing after restore, got %+v", state)
+	}
+	if state := service.GetPlaybackState(stopped.ID); state != nil {
+		t.Error("Expected stopped cue list not to be restored")
+	}
+}
+
+func TestJournal_DropsMissingCue(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "state.journal"), journal.SyncNever, 0)
+	if err != nil {
+		t.Fatalf("journal.Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	if err := j.Put(JournalKindActiveCue, cueList.ID, JournalCue{CueID: "deleted-cue", CueIndex: 0}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	service.SetJournal(j)
+
+	restored, err := service.RestoreFromJournal(ctx)
+	if err != nil {
+		t.Fatalf("RestoreFromJournal() error: %v", err)
+	}
+	if restored != 0 {
+		t.Errorf("Expected nothing restored, got %d", restored)
+	}
+	if len(j.Entries(JournalKindActiveCue)) != 0 {
+		t.Error("Expected the stale entry to be dropped")
+	}
+}
@@ -0,0 +1,174 @@
+package playback
+
+import (
+	"context"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"gorm.io/gorm"
+)
+
+// moveInBlackTime is how long dark fixtures take to move into the next
+// cue's positions, replaced in tests.
+var moveInBlackTime = 2 * time.Second
+
+// moveInBlackTypes are the channel types moved in black: position, beam,
+// gobo and color, but not intensity, shutters, or effects.
+var moveInBlackTypes = map[string]bool{
+	"PAN":         true,
+	"TILT":        true,
+	"ZOOM":        true,
+	"FOCUS":       true,
+	"IRIS":        true,
+	"GOBO":        true,
+	"COLOR_WHEEL": true,
+	"RED":         true,
+	"GREEN":       true,
+	"BLUE":        true,
+	"WHITE":       true,
+	"AMBER":       true,
+	"UV":          true,
+	"CYAN":        true,
+	"MAGENTA":     true,
+	"YELLOW":      true,
+	"LIME":        true,
+	"INDIGO":      true,
+	"COLD_WHITE":  true,
+	"WARM_WHITE":  true,
+}
+
+// moveInBlackFadeID returns the fade ID of a cue list's move in black.
+func moveInBlackFadeID(cueListID string) string {
+	return "mib-" + cueListID
+}
+
+// moveInBlack runs once a cue list's cue at cueIndex has faded in. When the
+// next cue moves in black, the fixtures now dark fade to its position,
+// beam, gobo and color values, so they do not visibly swing into place when
+// it fades in. Crossfader-mode cue lists do not move in black, since the
+// operator may take the crossfade at any moment.
+func (s *Service) moveInBlack(ctx context.Context, cueListID string, cueIndex int) {
+	if s.db == nil || s.fadeEngine == nil {
+		return // Playback state only, with no cues to look ahead to
+	}
+
+	var cueList models.CueList
+	result := s.db.WithContext(ctx).
+		Preload("Cues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cue_number ASC")
+		}).
+		First(&cueList, "id = ?", cueListID)
+	if result.Error != nil || cueList.PlaybackMode == PlaybackModeCrossfader || cueIndex >= len(cueList.Cues) {
+		return
+	}
+
+	nextIndex := cueIndex + 1
+	if nextIndex >= len(cueList.Cues) {
+		if !cueList.Loop {
+			return
+		}
+		nextIndex = 0
+	}
+	next := cueList.Cues[nextIndex]
+	if !next.MoveInBlack || nextIndex == cueIndex {
+		return
+	}
+
+	targets, err := s.moveInBlackTargets(ctx, next.ID)
+	if err != nil {
+		log.Warn("failed to move fixtures in black", "cueList", cueListID, "cue", next.ID, "error", err)
+		return
+	}
+	if len(targets) == 0 {
+		return
+	}
+	s.fadeEngine.FadeChannels(targets, moveInBlackTime, moveInBlackFadeID(cueListID), fade.EasingInOutSine, nil)
+	log.Debug("moving fixtures in black", "cueList", cueListID, "cue", next.ID, "channels", len(targets))
+}
+
+// moveInBlackTargets returns a cue's position, beam, gobo and color values
+// for the fixtures whose intensity is at zero on the output now. Fixtures
+// without an intensity channel are never dark, so they are left alone.
+func (s *Service) moveInBlackTargets(ctx context.Context, cueID string) ([]fade.ChannelTarget, error) {
+	var cue models.Cue
+	result := s.db.WithContext(ctx).
+		Preload("Scene.FixtureValues").
+		First(&cue, "id = ?", cueID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	values, _, err := s.cueValues(ctx, &cue)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	var fixtureIDs []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v.FixtureID] {
+			seen[v.FixtureID] = true
+			fixtureIDs = append(fixtureIDs, v.FixtureID)
+		}
+	}
+	var fixtures []models.FixtureInstance
+	if err := s.db.WithContext(ctx).Preload("Channels").Where("id IN ?", fixtureIDs).Find(&fixtures).Error; err != nil {
+		return nil, err
+	}
+
+	outputs := make(map[int][]int)
+	dark := make(map[string]*models.FixtureInstance)
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		output := outputs[fixture.Universe]
+		if output == nil {
+			output = s.dmxService.GetUniverse(fixture.Universe)
+			outputs[fixture.Universe] = output
+		}
+		if fixtureIsDark(fixture, output) {
+			dark[fixture.ID] = fixture
+		}
+	}
+
+	var targets []fade.ChannelTarget
+	for _, v := range values {
+		fixture := dark[v.FixtureID]
+		if fixture == nil {
+			continue
+		}
+		for _, channel := range fixture.Channels {
+			if channel.Offset != v.Offset || !moveInBlackTypes[channel.Type] {
+				continue
+			}
+			dmxChannel := fixture.StartChannel + v.Offset
+			if dmxChannel < 1 || dmxChannel > 512 {
+				break
+			}
+			targets = append(targets, fade.ChannelTarget{
+				Universe:     fixture.Universe,
+				Channel:      dmxChannel,
+				TargetValue:  v.Value,
+				FadeBehavior: channel.FadeBehavior,
+			})
+			break
+		}
+	}
+	return targets, nil
+}
+
+// fixtureIsDark reports whether a fixture has intensity channels and all of
+// them output zero.
+func fixtureIsDark(fixture *models.FixtureInstance, output []int) bool {
+	hasIntensity := false
+	for _, channel := range fixture.Channels {
+		if channel.Type != "INTENSITY" {
+			continue
+		}
+		hasIntensity = true
+		dmxChannel := fixture.StartChannel + channel.Offset
+		if dmxChannel < 1 || dmxChannel > len(output) || output[dmxChannel-1] != 0 {
+			return false
+		}
+	}
+	return hasIntensity
+}
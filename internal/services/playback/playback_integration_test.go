@@ -2,6 +2,8 @@ package playback
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/services/dmx"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
 	"github.com/bbernstein/lacylights-go/internal/services/testutil"
 	"github.com/lucsky/cuid"
 )
@@ -161,7 +164,7 @@ func TestStartCueList_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -201,7 +204,7 @@ func TestStartCueList_EmptyCueList(t *testing.T) {
 	}
 
 	// Try to start
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err == nil {
 		t.Error("Expected error for empty cue list")
 	}
@@ -214,7 +217,7 @@ func TestStartCueList_NonExistent(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.StartCueList(ctx, "nonexistent-id", nil, nil)
+	err := service.StartCueList(ctx, "nonexistent-id", nil, nil, nil)
 	if err == nil {
 		t.Error("Expected error for non-existent cue list")
 	}
@@ -243,7 +246,7 @@ func TestStartCueList_FromSpecificCue(t *testing.T) {
 
 	// Start from cue number 2
 	startCue := 2.0
-	err := service.StartCueList(ctx, cueList.ID, &startCue, nil)
+	err := service.StartCueList(ctx, cueList.ID, &startCue, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -277,13 +280,13 @@ func TestNextCue_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2}, false)
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Go to next
-	err = service.NextCue(ctx, cueList.ID, nil)
+	err = service.NextCue(ctx, cueList.ID, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to next cue: %v", err)
 	}
@@ -306,13 +309,13 @@ func TestNextCue_AtEnd_NoLoop(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Try to go to next (should fail - at end)
-	err = service.NextCue(ctx, cueList.ID, nil)
+	err = service.NextCue(ctx, cueList.ID, nil, nil)
 	if err == nil {
 		t.Error("Expected error when at end of non-looping cue list")
 	}
@@ -329,13 +332,13 @@ func TestNextCue_AtEnd_WithLoop(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, true) // Loop enabled
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Go to next - should loop to 0
-	err = service.NextCue(ctx, cueList.ID, nil)
+	err = service.NextCue(ctx, cueList.ID, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to next cue with looping: %v", err)
 	}
@@ -369,13 +372,13 @@ func TestPreviousCue_Integration(t *testing.T) {
 
 	// Start from cue 2
 	startCue := 2.0
-	err := service.StartCueList(ctx, cueList.ID, &startCue, nil)
+	err := service.StartCueList(ctx, cueList.ID, &startCue, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Go to previous
-	err = service.PreviousCue(ctx, cueList.ID, nil)
+	err = service.PreviousCue(ctx, cueList.ID, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to previous cue: %v", err)
 	}
@@ -398,13 +401,13 @@ func TestPreviousCue_AtStart_NoLoop(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Try to go to previous (should fail - at start)
-	err = service.PreviousCue(ctx, cueList.ID, nil)
+	err = service.PreviousCue(ctx, cueList.ID, nil, nil)
 	if err == nil {
 		t.Error("Expected error when at start of non-looping cue list")
 	}
@@ -432,13 +435,13 @@ func TestPreviousCue_AtStart_WithLoop(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2}, true) // Loop enabled
 
 	// Start at cue 1
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Go to previous - should loop to last
-	err = service.PreviousCue(ctx, cueList.ID, nil)
+	err = service.PreviousCue(ctx, cueList.ID, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to previous cue with looping: %v", err)
 	}
@@ -467,7 +470,7 @@ func TestJumpToCue_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2, scene3}, false)
 
 	// Jump to cue index 2
-	err := service.JumpToCue(ctx, cueList.ID, 2, nil)
+	err := service.JumpToCue(ctx, cueList.ID, 2, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to jump to cue: %v", err)
 	}
@@ -489,12 +492,12 @@ func TestJumpToCue_InvalidIndex(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// Try invalid indices
-	err := service.JumpToCue(ctx, cueList.ID, -1, nil)
+	err := service.JumpToCue(ctx, cueList.ID, -1, nil, nil)
 	if err == nil {
 		t.Error("Expected error for negative index")
 	}
 
-	err = service.JumpToCue(ctx, cueList.ID, 10, nil)
+	err = service.JumpToCue(ctx, cueList.ID, 10, nil, nil)
 	if err == nil {
 		t.Error("Expected error for out of bounds index")
 	}
@@ -516,7 +519,7 @@ func TestGoToCueNumber_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2}, false)
 
 	// Go to cue number 2.0
-	err := service.GoToCueNumber(ctx, cueList.ID, 2.0, nil)
+	err := service.GoToCueNumber(ctx, cueList.ID, 2.0, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to cue number: %v", err)
 	}
@@ -537,12 +540,55 @@ func TestGoToCueNumber_NotFound(t *testing.T) {
 	_, scene := createTestFixtureWithScene(t, testDB, project)
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
-	err := service.GoToCueNumber(ctx, cueList.ID, 99.0, nil)
+	err := service.GoToCueNumber(ctx, cueList.ID, 99.0, nil, nil)
 	if err == nil {
 		t.Error("Expected error for non-existent cue number")
 	}
 }
 
+// TestChaseToCueNumber_Integration tests that chasing shortens the cue's fade
+// by the time already run.
+func TestChaseToCueNumber_Integration(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+
+	// A chase with no time run plays the cue's own fade
+	if err := service.ChaseToCueNumber(ctx, cueList.ID, 1.0, 0); err != nil {
+		t.Fatalf("ChaseToCueNumber() error: %v", err)
+	}
+	events := service.EventLog().Events
+	if fade := events[len(events)-1].FadeTime; fade != nil {
+		t.Errorf("Expected no fade override, got %v", *fade)
+	}
+
+	tests := []struct {
+		elapsed  time.Duration
+		wantFade float64
+	}{
+		{40 * time.Millisecond, 0.06},
+		{time.Second, 0},
+	}
+	for _, tt := range tests {
+		if err := service.ChaseToCueNumber(ctx, cueList.ID, 1.0, tt.elapsed); err != nil {
+			t.Fatalf("ChaseToCueNumber(%v) error: %v", tt.elapsed, err)
+		}
+		events := service.EventLog().Events
+		fade := events[len(events)-1].FadeTime
+		if fade == nil || *fade < tt.wantFade-0.001 || *fade > tt.wantFade+0.001 {
+			t.Errorf("elapsed %v: fade override = %v, want %v", tt.elapsed, fade, tt.wantFade)
+		}
+	}
+
+	if err := service.ChaseToCueNumber(ctx, cueList.ID, 99.0, 0); err == nil {
+		t.Error("Expected error for non-existent cue number")
+	}
+}
+
 // TestGoToCueName_Integration tests jumping by cue name.
 func TestGoToCueName_Integration(t *testing.T) {
 	testDB, service, cleanup := setupPlaybackTest(t)
@@ -554,7 +600,7 @@ func TestGoToCueName_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// The cue name is the scene name "Test Scene"
-	err := service.GoToCueName(ctx, cueList.ID, "Test Scene", nil)
+	err := service.GoToCueName(ctx, cueList.ID, "Test Scene", nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to cue name: %v", err)
 	}
@@ -575,7 +621,7 @@ func TestGoToCueName_NotFound(t *testing.T) {
 	_, scene := createTestFixtureWithScene(t, testDB, project)
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
-	err := service.GoToCueName(ctx, cueList.ID, "Nonexistent", nil)
+	err := service.GoToCueName(ctx, cueList.ID, "Nonexistent", nil, nil)
 	if err == nil {
 		t.Error("Expected error for non-existent cue name")
 	}
@@ -647,7 +693,7 @@ func TestStopCueList_Integration(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -698,12 +744,12 @@ func TestMultipleCueListsPlayback(t *testing.T) {
 	testDB.DB.Create(cue2)
 
 	// Start both
-	err := service.StartCueList(ctx, cueList1.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList1.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list 1: %v", err)
 	}
 
-	err = service.StartCueList(ctx, cueList2.ID, nil, nil)
+	err = service.StartCueList(ctx, cueList2.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list 2: %v", err)
 	}
@@ -757,7 +803,7 @@ func TestFadeProgressTracking(t *testing.T) {
 	testDB.DB.Create(cue)
 
 	// Start
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -800,7 +846,7 @@ func TestUpdateCallback_Integration(t *testing.T) {
 	})
 
 	// Start cue list
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -864,7 +910,7 @@ func TestGetFormattedStatus_Integration(t *testing.T) {
 	}
 
 	// Start
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
@@ -897,7 +943,7 @@ func TestStartCueList_WithFadeOverride(t *testing.T) {
 
 	// Start with custom fade time
 	fadeOverride := 0.2
-	err := service.StartCueList(ctx, cueList.ID, nil, &fadeOverride)
+	err := service.StartCueList(ctx, cueList.ID, nil, &fadeOverride, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list with fade override: %v", err)
 	}
@@ -925,14 +971,14 @@ func TestNextCue_WithFadeOverride(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene1, scene2}, false)
 
 	// Start
-	err := service.StartCueList(ctx, cueList.ID, nil, nil)
+	err := service.StartCueList(ctx, cueList.ID, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to start cue list: %v", err)
 	}
 
 	// Next with override
 	fadeOverride := 0.3
-	err = service.NextCue(ctx, cueList.ID, &fadeOverride)
+	err = service.NextCue(ctx, cueList.ID, &fadeOverride, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to next cue with fade override: %v", err)
 	}
@@ -954,7 +1000,7 @@ func TestGoToCueNumber_WithFadeOverride(t *testing.T) {
 	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
 
 	fadeOverride := 0.5
-	err := service.GoToCueNumber(ctx, cueList.ID, 1.0, &fadeOverride)
+	err := service.GoToCueNumber(ctx, cueList.ID, 1.0, &fadeOverride, nil)
 	if err != nil {
 		t.Fatalf("Failed to go to cue number with fade override: %v", err)
 	}
@@ -1129,3 +1175,548 @@ func TestExecuteCueDmx_DMXChannelOutOfBounds(t *testing.T) {
 		t.Fatalf("ExecuteCueDmx should not fail for out-of-bounds channels: %v", err)
 	}
 }
+
+// TestFollowCountdown tests that a pending auto-follow is reported and
+// counted down to subscribers.
+func TestFollowCountdown(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+
+	followTime := 1.5
+	if err := testDB.DB.Model(&models.Cue{}).
+		Where("cue_list_id = ? AND cue_number = ?", cueList.ID, 1).
+		Update("follow_time", followTime).Error; err != nil {
+		t.Fatalf("Failed to set follow time: %v", err)
+	}
+
+	var mu sync.Mutex
+	var countdown []float64
+	service.SetUpdateCallback(func(status *CueListPlaybackStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status.FollowRemaining != nil && !status.IsFading {
+			countdown = append(countdown, *status.FollowRemaining)
+		}
+	})
+
+	if err := service.StartCueList(ctx, cueList.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+
+	status := service.GetFormattedStatus(cueList.ID)
+	if status.FollowAt == nil || status.FollowRemaining == nil {
+		t.Fatal("Expected a pending follow after starting a cue with a follow time")
+	}
+	if *status.FollowRemaining <= 1.0 || *status.FollowRemaining > 1.6 {
+		t.Errorf("FollowRemaining = %v, want about 1.6", *status.FollowRemaining)
+	}
+
+	// One countdown tick lands while waiting for the follow
+	time.Sleep(1200 * time.Millisecond)
+	mu.Lock()
+	ticks := append([]float64(nil), countdown...)
+	mu.Unlock()
+	if len(ticks) == 0 {
+		t.Fatal("Expected countdown updates while the follow is pending")
+	}
+	if ticks[len(ticks)-1] >= *status.FollowRemaining {
+		t.Errorf("Expected the countdown to decrease, got %v after %v", ticks, *status.FollowRemaining)
+	}
+
+	// After the follow fires, the second cue has no follow pending
+	time.Sleep(800 * time.Millisecond)
+	status = service.GetFormattedStatus(cueList.ID)
+	if status.CurrentCueIndex == nil || *status.CurrentCueIndex != 1 {
+		t.Fatalf("Expected the follow to advance to cue index 1, got %v", status.CurrentCueIndex)
+	}
+	if status.FollowAt != nil || status.FollowRemaining != nil {
+		t.Error("Expected no pending follow on the last cue")
+	}
+}
+
+// TestStopCueListClearsFollow tests that stopping cancels the countdown.
+func TestStopCueListClearsFollow(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+	if err := testDB.DB.Model(&models.Cue{}).Where("cue_list_id = ?", cueList.ID).
+		Update("follow_time", 5.0).Error; err != nil {
+		t.Fatalf("Failed to set follow time: %v", err)
+	}
+
+	if err := service.StartCueList(ctx, cueList.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	service.StopCueList(cueList.ID)
+
+	if status := service.GetFormattedStatus(cueList.ID); status.FollowAt != nil {
+		t.Error("Expected stop to clear the pending follow")
+	}
+	if state := service.GetPlaybackState(cueList.ID); state.FollowAt != nil {
+		t.Error("Expected stop to clear FollowAt")
+	}
+}
+
+// TestSetCrossfadePosition_Integration tests manual A/B crossfades between cues.
+func TestSetCrossfadePosition_Integration(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	var scenes []*models.Scene
+	for _, level := range []string{"255", "55", "155"} {
+		_, scene := createTestFixtureWithScene(t, testDB, project)
+		if err := testDB.DB.Model(&models.FixtureValue{}).Where("scene_id = ?", scene.ID).
+			Update("channels", `[{"offset":0,"value":`+level+`}]`).Error; err != nil {
+			t.Fatalf("Failed to set scene level: %v", err)
+		}
+		scenes = append(scenes, scene)
+	}
+	cueList := createTestCueList(t, testDB, project, scenes, false)
+
+	if err := service.SetCrossfadePosition(ctx, cueList.ID, 0.5); err == nil {
+		t.Error("Expected error for a cue list in timed mode")
+	}
+	if err := testDB.DB.Model(cueList).Update("playback_mode", PlaybackModeCrossfader).Error; err != nil {
+		t.Fatalf("Failed to set playback mode: %v", err)
+	}
+	if err := service.SetCrossfadePosition(ctx, cueList.ID, 1.5); err == nil {
+		t.Error("Expected error for a position above 1")
+	}
+
+	zero := 0.0
+	if err := service.StartCueList(ctx, cueList.ID, nil, &zero, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+
+	steps := []struct {
+		position  float64
+		wantLevel byte
+		wantIndex int
+	}{
+		{0.5, 155, 0}, // Halfway from cue 1 (255) to cue 2 (55)
+		{0, 255, 0},   // Back to cue 1
+		{1, 55, 1},    // Cue 2 is now current
+		{0.5, 105, 1}, // The next crossfade runs back toward 0
+		{0, 155, 2},
+	}
+	for _, step := range steps {
+		if err := service.SetCrossfadePosition(ctx, cueList.ID, step.position); err != nil {
+			t.Fatalf("SetCrossfadePosition(%v) error: %v", step.position, err)
+		}
+		if got := service.dmxService.GetChannelValue(1, 1); got != step.wantLevel {
+			t.Errorf("At %v: channel 1 = %d, want %d", step.position, got, step.wantLevel)
+		}
+		status := service.GetFormattedStatus(cueList.ID)
+		if status.CurrentCueIndex == nil || *status.CurrentCueIndex != step.wantIndex {
+			t.Errorf("At %v: current cue index = %v, want %d", step.position, status.CurrentCueIndex, step.wantIndex)
+		}
+		if status.CrossfadePosition == nil || *status.CrossfadePosition != step.position {
+			t.Errorf("At %v: crossfade position = %v", step.position, status.CrossfadePosition)
+		}
+	}
+
+	if err := service.SetCrossfadePosition(ctx, cueList.ID, 0.5); err == nil {
+		t.Error("Expected error with no next cue")
+	}
+	events := service.EventLog().Events
+	if last := events[len(events)-1]; last.Type != EventCrossfade || last.Position == nil || *last.Position != 0 {
+		t.Errorf("Expected the last crossfade to be logged, got %+v", last)
+	}
+}
+
+// TestPauseResumeCueList tests that pausing holds a pending follow and
+// resuming runs it with the time it had left.
+func TestPauseResumeCueList(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+	if err := testDB.DB.Model(&models.Cue{}).
+		Where("cue_list_id = ? AND cue_number = ?", cueList.ID, 1).
+		Update("follow_time", 0.5).Error; err != nil {
+		t.Fatalf("Failed to set follow time: %v", err)
+	}
+
+	if err := service.PauseCueList(cueList.ID); err == nil {
+		t.Error("Expected pausing a stopped cue list to fail")
+	}
+
+	if err := service.StartCueList(ctx, cueList.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if err := service.PauseCueList(cueList.ID); err != nil {
+		t.Fatalf("Failed to pause cue list: %v", err)
+	}
+
+	status := service.GetFormattedStatus(cueList.ID)
+	if !status.IsPaused || status.FollowAt != nil {
+		t.Errorf("Expected a paused status with no follow scheduled, got %+v", status)
+	}
+	if status.FollowRemaining == nil || *status.FollowRemaining <= 0.2 || *status.FollowRemaining > 0.45 {
+		t.Errorf("Expected about 0.4s of follow time held, got %v", status.FollowRemaining)
+	}
+
+	// The follow does not fire while paused
+	time.Sleep(700 * time.Millisecond)
+	if state := service.GetPlaybackState(cueList.ID); *state.CurrentCueIndex != 0 {
+		t.Fatalf("Expected the paused cue list to stay on cue index 0, got %d", *state.CurrentCueIndex)
+	}
+
+	service.ResumeCueList(cueList.ID)
+	status = service.GetFormattedStatus(cueList.ID)
+	if status.IsPaused || status.FollowAt == nil {
+		t.Errorf("Expected the follow rescheduled on resume, got %+v", status)
+	}
+	time.Sleep(700 * time.Millisecond)
+	state := service.GetPlaybackState(cueList.ID)
+	if *state.CurrentCueIndex != 1 || state.IsPaused {
+		t.Errorf("Expected the follow to advance to cue index 1 after resuming, got %d (paused %v)", *state.CurrentCueIndex, state.IsPaused)
+	}
+
+	var types []EventType
+	for _, event := range service.EventLog().Events {
+		types = append(types, event.Type)
+	}
+	want := []EventType{EventStartCueList, EventPauseCueList, EventResumeCueList, EventFollow}
+	if len(types) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("Event %d = %s, want %s", i, types[i], want[i])
+		}
+	}
+}
+
+// TestSuspendResume tests that suspending holds every playing cue list's
+// follows, and resuming restarts only the lists it paused.
+func TestSuspendResume(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	following := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+	if err := testDB.DB.Model(&models.Cue{}).
+		Where("cue_list_id = ? AND cue_number = ?", following.ID, 1).
+		Update("follow_time", 0.3).Error; err != nil {
+		t.Fatalf("Failed to set follow time: %v", err)
+	}
+	paused := createTestCueList(t, testDB, project, []*models.Scene{scene, scene}, false)
+
+	for _, cueList := range []*models.CueList{following, paused} {
+		if err := service.StartCueList(ctx, cueList.ID, nil, nil, nil); err != nil {
+			t.Fatalf("Failed to start cue list: %v", err)
+		}
+	}
+	if err := service.PauseCueList(paused.ID); err != nil {
+		t.Fatalf("Failed to pause cue list: %v", err)
+	}
+
+	service.Suspend()
+	if !service.GetPlaybackState(following.ID).IsPaused {
+		t.Fatal("Expected the playing cue list to be paused")
+	}
+	time.Sleep(500 * time.Millisecond)
+	if state := service.GetPlaybackState(following.ID); *state.CurrentCueIndex != 0 {
+		t.Fatalf("Expected no follow while suspended, got cue index %d", *state.CurrentCueIndex)
+	}
+
+	service.Resume()
+	if service.GetPlaybackState(following.ID).IsPaused {
+		t.Error("Expected the suspended cue list to resume")
+	}
+	if !service.GetPlaybackState(paused.ID).IsPaused {
+		t.Error("Expected the cue list paused beforehand to stay paused")
+	}
+	time.Sleep(500 * time.Millisecond)
+	if state := service.GetPlaybackState(following.ID); *state.CurrentCueIndex != 1 {
+		t.Errorf("Expected the follow to advance after resuming, got cue index %d", *state.CurrentCueIndex)
+	}
+}
+
+// memoryRecorder keeps recorded state in memory.
+type memoryRecorder map[string]string
+
+func (m memoryRecorder) Put(kind, key string, value any) error {
+	data, err := json.Marshal(value)
+	m[kind+"/"+key] = string(data)
+	return err
+}
+
+func (m memoryRecorder) Delete(kind, key string) error {
+	delete(m, kind+"/"+key)
+	return nil
+}
+
+// TestTrackCue tests that a replicator receives the active cues and that a
+// tracked cue holds its follow until Resume.
+func TestTrackCue(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	live := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+	tracked := createTestCueList(t, testDB, project, []*models.Scene{scene, scene, scene}, false)
+	var cues []models.Cue
+	if err := testDB.DB.Where("cue_list_id = ?", tracked.ID).Order("cue_number ASC").Find(&cues).Error; err != nil {
+		t.Fatalf("Failed to load cues: %v", err)
+	}
+	if err := testDB.DB.Model(&models.Cue{}).Where("id = ?", cues[1].ID).Update("follow_time", 0.2).Error; err != nil {
+		t.Fatalf("Failed to set follow time: %v", err)
+	}
+
+	// Cues live before the replicator is set are replicated straight away
+	if err := service.StartCueList(ctx, live.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	recorder := memoryRecorder{}
+	service.SetReplicator(recorder)
+	if _, ok := recorder[JournalKindActiveCue+"/"+live.ID]; !ok {
+		t.Errorf("Expected the live cue replicated, got %v", recorder)
+	}
+
+	if err := service.TrackCue(ctx, tracked.ID, JournalCue{CueID: cues[1].ID, CueIndex: 1}); err != nil {
+		t.Fatalf("TrackCue() error: %v", err)
+	}
+	state := service.GetPlaybackState(tracked.ID)
+	if !state.IsPaused || *state.CurrentCueIndex != 1 {
+		t.Fatalf("Expected the tracked cue list held at cue index 1, got %+v", state)
+	}
+	if !strings.Contains(recorder[JournalKindActiveCue+"/"+tracked.ID], cues[1].ID) {
+		t.Errorf("Expected the tracked cue replicated, got %v", recorder)
+	}
+	time.Sleep(400 * time.Millisecond)
+	if state := service.GetPlaybackState(tracked.ID); *state.CurrentCueIndex != 1 {
+		t.Fatalf("Expected no follow while tracking, got cue index %d", *state.CurrentCueIndex)
+	}
+
+	service.Resume()
+	time.Sleep(500 * time.Millisecond)
+	if state := service.GetPlaybackState(tracked.ID); *state.CurrentCueIndex != 2 {
+		t.Errorf("Expected the follow to run after resuming, got cue index %d", *state.CurrentCueIndex)
+	}
+
+	service.StopCueList(live.ID)
+	if _, ok := recorder[JournalKindActiveCue+"/"+live.ID]; ok {
+		t.Error("Expected the stopped cue list removed from replication")
+	}
+}
+
+// TestReleaseCueList tests that releasing stops a cue list and takes its
+// cue off the playback stack.
+func TestReleaseCueList(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	stackService := stack.NewService(service.fadeEngine)
+	stackService.SetHTPChannels(map[int][]int{1: {1}})
+	service.SetStackService(stackService)
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+
+	zero := 0.0
+	if err := service.StartCueList(ctx, cueList.ID, nil, &zero, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	waitForChannel(t, service.dmxService, 1, 255)
+
+	service.ReleaseCueList(cueList.ID, &zero)
+	if state := service.GetPlaybackState(cueList.ID); state.IsPlaying {
+		t.Error("Expected the released cue list to stop")
+	}
+	if entries := stackService.Entries(); len(entries) != 0 {
+		t.Errorf("Expected the cue list released from the stack, got %+v", entries)
+	}
+	// The HTP intensity fades out; the LTP channels hold
+	waitForChannel(t, service.dmxService, 1, 0)
+	if got := service.dmxService.GetUniverse(1)[1]; got != 128 {
+		t.Errorf("Expected channel 2 to hold at 128, got %d", got)
+	}
+}
+
+// TestActiveCueLists tests that concurrently playing cue lists are all
+// reported, the most recently started first.
+func TestActiveCueLists(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	first := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+	second := createTestCueList(t, testDB, project, []*models.Scene{scene}, false)
+
+	if err := service.StartCueList(ctx, first.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start first cue list: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := service.StartCueList(ctx, second.ID, nil, nil, nil); err != nil {
+		t.Fatalf("Failed to start second cue list: %v", err)
+	}
+
+	active := service.ActiveCueLists()
+	if len(active) != 2 || active[0].CueListID != second.ID || active[1].CueListID != first.ID {
+		t.Fatalf("Expected both cue lists, the second first, got %+v", active)
+	}
+	if global := service.GetGlobalPlaybackStatus(ctx); global.CueListID == nil || *global.CueListID != second.ID {
+		t.Errorf("Expected the global status to report the most recent cue list, got %v", global.CueListID)
+	}
+
+	service.StopCueList(second.ID)
+	active = service.ActiveCueLists()
+	if len(active) != 1 || active[0].CueListID != first.ID {
+		t.Errorf("Expected only the first cue list after stopping the second, got %+v", active)
+	}
+	if global := service.GetGlobalPlaybackStatus(ctx); global.CueListID == nil || *global.CueListID != first.ID {
+		t.Errorf("Expected the global status to fall back to the first cue list, got %v", global.CueListID)
+	}
+}
+
+// waitForChannel waits for a universe 1 channel to reach a value.
+func waitForChannel(t *testing.T, dmxService *dmx.Service, channel int, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := dmxService.GetUniverse(1)[channel-1]
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Channel %d = %d, want %d", channel, got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestGoFadeOverrides tests that fade times given at GO time apply to that
+// GO only, and that follows count from the fade actually run.
+func TestGoFadeOverrides(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{scene, scene, scene}, false)
+	if err := testDB.DB.Model(&models.Cue{}).Where("cue_list_id = ?", cueList.ID).
+		Updates(map[string]interface{}{"fade_in_time": 5.0, "follow_time": 0.2}).Error; err != nil {
+		t.Fatalf("Failed to set cue times: %v", err)
+	}
+
+	zero, slow := 0.0, 3.0
+	if err := service.GoToCueNumber(ctx, cueList.ID, 1, &zero, &slow); err != nil {
+		t.Fatalf("Failed to go to cue 1: %v", err)
+	}
+	state := service.GetPlaybackState(cueList.ID)
+	if state.CurrentCue.FadeInTime != 0 || state.CurrentCue.FadeOutTime != 3 {
+		t.Errorf("Expected the overridden fade times, got in %v out %v", state.CurrentCue.FadeInTime, state.CurrentCue.FadeOutTime)
+	}
+	if state.FollowAt == nil || time.Until(*state.FollowAt) > 300*time.Millisecond {
+		t.Errorf("Expected the follow to count from the 0s fade, got %v", state.FollowAt)
+	}
+
+	// The follow takes the next cue with its stored times
+	time.Sleep(400 * time.Millisecond)
+	state = service.GetPlaybackState(cueList.ID)
+	if *state.CurrentCueIndex != 1 || state.CurrentCue.FadeInTime != 5 || state.CurrentCue.FadeOutTime != 0.05 {
+		t.Errorf("Expected cue index 1 with its stored fade times, got index %d in %v out %v",
+			*state.CurrentCueIndex, state.CurrentCue.FadeInTime, state.CurrentCue.FadeOutTime)
+	}
+
+	events := service.EventLog().Events
+	if first := events[0]; first.FadeTime == nil || *first.FadeTime != 0 || first.FadeOutTime == nil || *first.FadeOutTime != 3 {
+		t.Errorf("Expected the GO's fade overrides logged, got %+v", first)
+	}
+}
+
+// TestMoveInBlack tests that fixtures dark in a cue move into the next
+// cue's positions when the next cue moves in black, and lit ones stay put.
+func TestMoveInBlack(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+	original := moveInBlackTime
+	moveInBlackTime = 50 * time.Millisecond
+	defer func() { moveInBlackTime = original }()
+
+	ctx := context.Background()
+	project := createTestProject(t, testDB)
+	var movers []*models.FixtureInstance
+	for _, start := range []int{1, 11} {
+		mover := &models.FixtureInstance{ID: cuid.New(), ProjectID: project.ID, Name: testutil.UniqueFixtureName("mover"), Universe: 1, StartChannel: start}
+		if err := testDB.DB.Create(mover).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+		for offset, channelType := range []string{"INTENSITY", "PAN", "STROBE"} {
+			channel := &models.InstanceChannel{ID: cuid.New(), FixtureID: mover.ID, Offset: offset, Name: channelType, Type: channelType, FadeBehavior: "FADE"}
+			if err := testDB.DB.Create(channel).Error; err != nil {
+				t.Fatalf("Failed to create channel: %v", err)
+			}
+		}
+		movers = append(movers, mover)
+	}
+	scene := func(values ...string) *models.Scene {
+		s := &models.Scene{ID: cuid.New(), ProjectID: project.ID, Name: "Scene"}
+		if err := testDB.DB.Create(s).Error; err != nil {
+			t.Fatalf("Failed to create scene: %v", err)
+		}
+		for i, channels := range values {
+			value := &models.FixtureValue{ID: cuid.New(), SceneID: s.ID, FixtureID: movers[i].ID, Channels: channels}
+			if err := testDB.DB.Create(value).Error; err != nil {
+				t.Fatalf("Failed to create fixture value: %v", err)
+			}
+		}
+		return s
+	}
+	// The first mover is dark in cue 1, the second lit
+	first := scene(`[{"offset":0,"value":0},{"offset":1,"value":10}]`, `[{"offset":0,"value":255},{"offset":1,"value":10}]`)
+	second := scene(`[{"offset":0,"value":255},{"offset":1,"value":200},{"offset":2,"value":50}]`, `[{"offset":0,"value":255},{"offset":1,"value":200}]`)
+	cueList := createTestCueList(t, testDB, project, []*models.Scene{first, second}, false)
+	if err := testDB.DB.Model(&models.Cue{}).Where("cue_list_id = ? AND cue_number = 2", cueList.ID).
+		Update("move_in_black", true).Error; err != nil {
+		t.Fatalf("Failed to set move in black: %v", err)
+	}
+
+	zero := 0.0
+	if err := service.StartCueList(ctx, cueList.ID, nil, &zero, nil); err != nil {
+		t.Fatalf("Failed to start cue list: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for service.dmxService.GetChannelValue(1, 2) != 200 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the dark mover's pan moved to 200, got %d", service.dmxService.GetChannelValue(1, 2))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := service.dmxService.GetChannelValue(1, 1); got != 0 {
+		t.Errorf("Expected the dark mover's intensity left at 0, got %d", got)
+	}
+	if got := service.dmxService.GetChannelValue(1, 3); got != 0 {
+		t.Errorf("Expected the dark mover's strobe left alone, got %d", got)
+	}
+	if got := service.dmxService.GetChannelValue(1, 12); got != 10 {
+		t.Errorf("Expected the lit mover's pan left at 10, got %d", got)
+	}
+}
@@ -0,0 +1,52 @@
+package playback
+
+import (
+	"context"
+)
+
+// StateRecorder records keyed playback state, as the state journal does.
+type StateRecorder interface {
+	Put(kind, key string, value any) error
+	Delete(kind, key string) error
+}
+
+// SetReplicator sets a recorder that receives the active cue of each cue
+// list alongside the journal, for a tracking backup to follow, starting with
+// the cues live now (optional).
+func (s *Service) SetReplicator(replicator StateRecorder) {
+	s.mu.Lock()
+	s.replicator = replicator
+	live := make(map[string]JournalCue)
+	for id, state := range s.states {
+		if state.IsPlaying && state.CurrentCue != nil && state.CurrentCueIndex != nil {
+			live[id] = JournalCue{CueID: state.CurrentCue.ID, CueIndex: *state.CurrentCueIndex}
+		}
+	}
+	s.mu.Unlock()
+
+	if replicator == nil {
+		return
+	}
+	for id := range live {
+		cue := live[id]
+		if err := recordActiveCue(replicator, id, &cue); err != nil {
+			log.Warn("failed to replicate playback state", "cueList", id, "error", err)
+		}
+	}
+}
+
+// TrackCue snaps a cue list to the cue a primary instance reports active,
+// as a tracking backup does. Its follow chain holds, as in Suspend, so the
+// backup runs no follows of its own until Resume.
+func (s *Service) TrackCue(ctx context.Context, cueListID string, cue JournalCue) error {
+	if err := s.restoreCue(ctx, cueListID, cue); err != nil {
+		return err
+	}
+	if err := s.PauseCueList(cueListID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.suspended = append(s.suspended, cueListID)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,72 @@
+package playback
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+)
+
+// DefaultSceneFadeTime is the scene fade time in seconds when no level of the
+// fade hierarchy sets one.
+const DefaultSceneFadeTime = 3.0
+
+// SceneFadeLevels holds the caller's levels of the scene fade hierarchy.
+// Times resolve in this order, first set wins:
+//
+//  1. Call: the time passed to the mutation
+//  2. Button: the scene board button's fade time
+//  3. Board: the scene board's default fade time
+//  4. Scene: the scene's default fade in or out
+//  5. Project: the project's default fade in or out
+//
+// and DefaultSceneFadeTime when none is set. The scene and project levels are
+// loaded by the service.
+type SceneFadeLevels struct {
+	Call   *float64
+	Button *float64
+	Board  *float64
+}
+
+// ResolveSceneFadeIn returns the fade-in time in seconds for activating a scene.
+func (s *Service) ResolveSceneFadeIn(ctx context.Context, sceneID string, levels SceneFadeLevels) (float64, error) {
+	return s.resolveSceneFade(ctx, sceneID, levels, false)
+}
+
+// ResolveSceneFadeOut returns the fade-out time in seconds for releasing a scene.
+func (s *Service) ResolveSceneFadeOut(ctx context.Context, sceneID string, levels SceneFadeLevels) (float64, error) {
+	return s.resolveSceneFade(ctx, sceneID, levels, true)
+}
+
+func (s *Service) resolveSceneFade(ctx context.Context, sceneID string, levels SceneFadeLevels, fadeOut bool) (float64, error) {
+	for _, t := range []*float64{levels.Call, levels.Button, levels.Board} {
+		if t != nil {
+			return *t, nil
+		}
+	}
+
+	var scene models.Scene
+	if err := s.db.WithContext(ctx).First(&scene, "id = ?", sceneID).Error; err != nil {
+		return 0, fmt.Errorf("scene not found: %w", err)
+	}
+	if t := sceneDefaultFade(scene.DefaultFadeIn, scene.DefaultFadeOut, fadeOut); t != nil {
+		return *t, nil
+	}
+
+	var project models.Project
+	if err := s.db.WithContext(ctx).First(&project, "id = ?", scene.ProjectID).Error; err != nil {
+		return 0, fmt.Errorf("project not found: %w", err)
+	}
+	if t := sceneDefaultFade(project.DefaultFadeIn, project.DefaultFadeOut, fadeOut); t != nil {
+		return *t, nil
+	}
+
+	return DefaultSceneFadeTime, nil
+}
+
+func sceneDefaultFade(fadeIn, fadeOut *float64, out bool) *float64 {
+	if out {
+		return fadeOut
+	}
+	return fadeIn
+}
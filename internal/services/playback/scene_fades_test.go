@@ -0,0 +1,76 @@
+package playback
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveSceneFade(t *testing.T) {
+	testDB, service, cleanup := setupPlaybackTest(t)
+	defer cleanup()
+
+	project := createTestProject(t, testDB)
+	_, scene := createTestFixtureWithScene(t, testDB, project)
+	ctx := context.Background()
+
+	f := func(v float64) *float64 { return &v }
+
+	resolve := func(levels SceneFadeLevels, fadeOut bool) float64 {
+		t.Helper()
+		var got float64
+		var err error
+		if fadeOut {
+			got, err = service.ResolveSceneFadeOut(ctx, scene.ID, levels)
+		} else {
+			got, err = service.ResolveSceneFadeIn(ctx, scene.ID, levels)
+		}
+		if err != nil {
+			t.Fatalf("resolve error: %v", err)
+		}
+		return got
+	}
+
+	if got := resolve(SceneFadeLevels{}, false); got != DefaultSceneFadeTime {
+		t.Errorf("Expected built-in default %v with nothing set, got %v", DefaultSceneFadeTime, got)
+	}
+
+	project.DefaultFadeIn = f(4)
+	project.DefaultFadeOut = f(6)
+	testDB.DB.Save(project)
+	if got := resolve(SceneFadeLevels{}, false); got != 4 {
+		t.Errorf("Expected project fade-in 4, got %v", got)
+	}
+	if got := resolve(SceneFadeLevels{}, true); got != 6 {
+		t.Errorf("Expected project fade-out 6, got %v", got)
+	}
+
+	scene.DefaultFadeIn = f(1.5)
+	testDB.DB.Save(scene)
+	if got := resolve(SceneFadeLevels{}, false); got != 1.5 {
+		t.Errorf("Expected scene fade-in 1.5 over the project, got %v", got)
+	}
+	if got := resolve(SceneFadeLevels{}, true); got != 6 {
+		t.Errorf("Expected unset scene fade-out to fall back to the project, got %v", got)
+	}
+
+	tests := []struct {
+		name   string
+		levels SceneFadeLevels
+		want   float64
+	}{
+		{"board over scene", SceneFadeLevels{Board: f(2)}, 2},
+		{"button over board", SceneFadeLevels{Button: f(0.5), Board: f(2)}, 0.5},
+		{"call over button", SceneFadeLevels{Call: f(0), Button: f(0.5), Board: f(2)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolve(tt.levels, false); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := service.ResolveSceneFadeIn(ctx, "missing", SceneFadeLevels{}); err == nil {
+		t.Error("Expected error for a missing scene")
+	}
+}
@@ -3,26 +3,28 @@ package playback
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
 	"github.com/bbernstein/lacylights-go/internal/database/models"
 	"github.com/bbernstein/lacylights-go/internal/services/dmx"
 	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/internal/services/journal"
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+	"github.com/bbernstein/lacylights-go/internal/services/tempo"
 	"gorm.io/gorm"
 )
 
 // CueForPlayback represents the essential cue info for playback.
 type CueForPlayback struct {
-	ID          string
-	Name        string
-	CueNumber   float64
-	FadeInTime  float64
-	FadeOutTime float64
-	FollowTime  *float64
+	ID             string
+	Name           string
+	CueNumber      float64
+	FadeInTime     float64
+	FadeOutTime    float64
+	FollowTime     *float64
+	FollowQuantize *string // Delays the auto-follow to the next tempo beat or bar (optional)
 }
 
 // PlaybackState represents the current state of cue list playback.
@@ -36,18 +38,26 @@ type PlaybackState struct {
 	CurrentCue      *CueForPlayback
 	FadeProgress    float64
 	StartTime       *time.Time
+	FollowAt        *time.Time // When the pending auto-follow fires (nil if none)
+	IsPaused        bool           // True while the follow chain is held
+	PausedFollow    *time.Duration // Follow time left when paused (nil if no follow was pending)
 	LastUpdated     time.Time
 }
 
 // CueListPlaybackStatus is the GraphQL-compatible status response.
+
 type CueListPlaybackStatus struct {
-	CueListID       string
-	CurrentCueIndex *int
-	IsPlaying       bool // True when scene values are active on DMX (stays true after fade until stopped)
-	IsFading        bool // True when a fade transition is in progress
-	CurrentCue      *CueForPlayback
-	FadeProgress    float64
-	LastUpdated     string
+	CueListID         string
+	CurrentCueIndex   *int
+	IsPlaying         bool // True when scene values are active on DMX (stays true after fade until stopped)
+	IsFading          bool // True when a fade transition is in progress
+	IsPaused          bool // True while the follow chain is held
+	CurrentCue        *CueForPlayback
+	FadeProgress      float64
+	FollowAt          *string  // When the pending auto-follow fires (nil if none)
+	FollowRemaining   *float64 // Seconds until the pending auto-follow fires
+	CrossfadePosition *float64 // Last crossfader position of a crossfader-mode cue list (nil if never moved)
+	LastUpdated       string
 }
 
 // GlobalPlaybackStatus represents the global playback state across all cue lists.
@@ -71,6 +81,17 @@ type Service struct {
 	dmxService *dmx.Service
 	fadeEngine *fade.Engine
 
+	// Tempo clock for beat-quantized follows (optional)
+	tempo *tempo.Service
+
+	// Playback stack cues merge with other live scenes through (optional)
+	stack *stack.Service
+
+	// Write-ahead journal of the active cue per cue list (optional)
+	journal *journal.Journal
+	// Receives the active cue per cue list for a tracking backup (optional)
+	replicator StateRecorder
+
 	// Playback states by cue list ID
 	states map[string]*PlaybackState
 
@@ -79,11 +100,30 @@ type Service struct {
 	followTimers        map[string]*time.Timer
 	fadeCompleteTimers  map[string]*time.Timer
 
+	// Stops the once-a-second countdown updates while a follow is pending
+	followCountdowns map[string]chan struct{}
+
+	// Cue lists paused by Suspend, for Resume to restart
+	suspended []string
+
+	// Armed manual crossfades and last crossfader positions of
+	// crossfader-mode cue lists; crossfadeMu serializes crossfader moves
+	crossfadeMu        sync.Mutex
+	crossfades         map[string]*crossfade
+	crossfadePositions map[string]float64
+
 	// Callback for subscription updates (optional)
 	onUpdate func(status *CueListPlaybackStatus)
 
 	// Callback for global playback status updates (optional)
 	onGlobalUpdate func(status *GlobalPlaybackStatus)
+
+	// Ordered log of playback commands for deterministic replay
+	logMu      sync.Mutex
+	eventLog   []Event
+	logSeq     int
+	logDropped int
+	replaying  bool
 }
 
 // NewService creates a new playback service.
@@ -96,9 +136,27 @@ func NewService(db *gorm.DB, dmxService *dmx.Service, fadeEngine *fade.Engine) *
 		fadeProgressTickers: make(map[string]*time.Ticker),
 		followTimers:        make(map[string]*time.Timer),
 		fadeCompleteTimers:  make(map[string]*time.Timer),
+		followCountdowns:    make(map[string]chan struct{}),
+		crossfades:          make(map[string]*crossfade),
+		crossfadePositions:  make(map[string]float64),
 	}
 }
 
+// SetTempoService sets the tempo clock used to quantize auto-follows.
+func (s *Service) SetTempoService(tempoService *tempo.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tempo = tempoService
+}
+
+// SetStackService sets the playback stack cues merge with other live scenes
+// through. Without one, cues fade straight to their values.
+func (s *Service) SetStackService(stackService *stack.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stack = stackService
+}
+
 // SetUpdateCallback sets the callback for playback status updates.
 func (s *Service) SetUpdateCallback(callback func(status *CueListPlaybackStatus)) {
 	s.mu.Lock()
@@ -136,6 +194,14 @@ func (s *Service) GetPlaybackState(cueListID string) *PlaybackState {
 		startTimeCopy := *state.StartTime
 		stateCopy.StartTime = &startTimeCopy
 	}
+	if state.FollowAt != nil {
+		followAtCopy := *state.FollowAt
+		stateCopy.FollowAt = &followAtCopy
+	}
+	if state.PausedFollow != nil {
+		pausedFollowCopy := *state.PausedFollow
+		stateCopy.PausedFollow = &pausedFollowCopy
+	}
 	return &stateCopy
 }
 
@@ -147,7 +213,7 @@ func (s *Service) GetFormattedStatus(cueListID string) *CueListPlaybackStatus {
 	state := s.states[cueListID]
 
 	if state == nil {
-		return &CueListPlaybackStatus{
+		status := &CueListPlaybackStatus{
 			CueListID:       cueListID,
 			CurrentCueIndex: nil,
 			IsPlaying:       false,
@@ -156,35 +222,53 @@ func (s *Service) GetFormattedStatus(cueListID string) *CueListPlaybackStatus {
 			FadeProgress:    0,
 			LastUpdated:     time.Now().Format(time.RFC3339),
 		}
+		if position, ok := s.crossfadePositions[cueListID]; ok {
+			status.CrossfadePosition = &position
+		}
+		return status
 	}
 
-	return &CueListPlaybackStatus{
+	status := &CueListPlaybackStatus{
 		CueListID:       state.CueListID,
 		CurrentCueIndex: state.CurrentCueIndex,
 		IsPlaying:       state.IsPlaying,
 		IsFading:        state.IsFading,
+		IsPaused:        state.IsPaused,
 		CurrentCue:      state.CurrentCue,
 		FadeProgress:    state.FadeProgress,
 		LastUpdated:     state.LastUpdated.Format(time.RFC3339),
 	}
+	if state.PausedFollow != nil {
+		remaining := state.PausedFollow.Seconds()
+		status.FollowRemaining = &remaining
+	}
+	if state.FollowAt != nil {
+		followAt := state.FollowAt.UTC().Format("2006-01-02T15:04:05.000Z")
+		remaining := time.Until(*state.FollowAt).Seconds()
+		if remaining < 0 {
+			remaining = 0
+		}
+		status.FollowAt = &followAt
+		status.FollowRemaining = &remaining
+	}
+	if position, ok := s.crossfadePositions[cueListID]; ok {
+		status.CrossfadePosition = &position
+	}
+	return status
 }
 
 // GetGlobalPlaybackStatus returns the global playback status across all cue lists.
 // It finds the currently playing cue list (if any) and returns its status with cue list details.
-//
-// Note: This implementation assumes only one cue list is playing at a time. While the system
-// doesn't enforce this constraint at the architecture level, the typical usage pattern is
-// sequential playback. If multiple cue lists are playing simultaneously, this method returns
-// the first one found (map iteration order is non-deterministic in Go).
+// When several cue lists are playing, it reports the one whose current cue started most
+// recently; ActiveCueLists returns them all.
 func (s *Service) GetGlobalPlaybackStatus(ctx context.Context) *GlobalPlaybackStatus {
 	s.mu.RLock()
 
-	// Find the currently playing cue list
+	// Find the most recently started playing cue list
 	var playingState *PlaybackState
 	for _, state := range s.states {
-		if state.IsPlaying {
+		if state.IsPlaying && (playingState == nil || startedAfter(state, playingState)) {
 			playingState = state
-			break
 		}
 	}
 
@@ -237,8 +321,9 @@ func (s *Service) GetGlobalPlaybackStatus(ctx context.Context) *GlobalPlaybackSt
 // StartCue starts playing a cue.
 // cueListName and cueCount are cached to avoid DB queries during status updates.
 func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, cueIndex int, cue *CueForPlayback) {
-	// Stop any existing playback for this cue list
-	s.StopCueList(cueListID)
+	// Stop any existing playback for this cue list, keeping its journal
+	// entry until the new cue replaces it
+	s.stopPlayback(cueListID)
 
 	s.mu.Lock()
 	now := time.Now()
@@ -250,12 +335,13 @@ func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, c
 		IsPlaying:       true,  // Scene is now active on DMX
 		IsFading:        true,  // Fade transition is starting
 		CurrentCue: &CueForPlayback{
-			ID:          cue.ID,
-			Name:        cue.Name,
-			CueNumber:   cue.CueNumber,
-			FadeInTime:  cue.FadeInTime,
-			FadeOutTime: cue.FadeOutTime,
-			FollowTime:  cue.FollowTime,
+			ID:             cue.ID,
+			Name:           cue.Name,
+			CueNumber:      cue.CueNumber,
+			FadeInTime:     cue.FadeInTime,
+			FadeOutTime:    cue.FadeOutTime,
+			FollowTime:     cue.FollowTime,
+			FollowQuantize: cue.FollowQuantize,
 		},
 		FadeProgress: 0,
 		StartTime:    &now,
@@ -265,24 +351,33 @@ func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, c
 	s.states[cueListID] = state
 	s.mu.Unlock()
 
+	s.journalActiveCue(cueListID, &JournalCue{CueID: cue.ID, CueIndex: cueIndex})
+
 	// Start fade progress tracking
 	s.startFadeProgress(cueListID, cue.FadeInTime)
 
-	// Emit update
-	s.emitUpdate(cueListID)
-
-	// Schedule follow time if applicable
-	if cue.FollowTime != nil && *cue.FollowTime > 0 {
+	// Schedule follow time if applicable (replays drive follows from the log instead)
+	if cue.FollowTime != nil && *cue.FollowTime > 0 && !s.isReplaying() {
 		totalWaitTime := time.Duration((cue.FadeInTime + *cue.FollowTime) * float64(time.Second))
 
 		s.mu.Lock()
+		if s.tempo != nil && cue.FollowQuantize != nil {
+			followAt := s.tempo.NextBoundary(now.Add(totalWaitTime), tempo.Quantize(*cue.FollowQuantize))
+			totalWaitTime = followAt.Sub(now)
+		}
 		timer := time.AfterFunc(totalWaitTime, func() {
 			s.handleFollowTime(cueListID, cueIndex)
 		})
 		s.followTimers[cueListID] = timer
+		followAt := now.Add(totalWaitTime)
+		state.FollowAt = &followAt
+		s.startFollowCountdownLocked(cueListID)
 		s.mu.Unlock()
 	}
 
+	// Emit update
+	s.emitUpdate(cueListID)
+
 	// Mark fade as complete after fadeInTime (but keep isPlaying true - scene is still active)
 	fadeTime := time.Duration(cue.FadeInTime * float64(time.Second))
 	s.mu.Lock()
@@ -293,7 +388,8 @@ func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, c
 	fadeCompleteTimer := time.AfterFunc(fadeTime, func() {
 		s.mu.Lock()
 		currentState := s.states[cueListID]
-		if currentState != nil && currentState.CurrentCueIndex != nil && *currentState.CurrentCueIndex == cueIndex {
+		current := currentState != nil && currentState.CurrentCueIndex != nil && *currentState.CurrentCueIndex == cueIndex
+		if current {
 			currentState.IsFading = false // Fade complete, but scene still playing
 			currentState.LastUpdated = time.Now()
 		}
@@ -301,6 +397,11 @@ func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, c
 		delete(s.fadeCompleteTimers, cueListID)
 		s.mu.Unlock()
 		s.emitUpdate(cueListID)
+
+		// Fixtures this cue left dark can move into the next cue's positions
+		if current {
+			s.moveInBlack(context.Background(), cueListID, cueIndex)
+		}
 	})
 	s.fadeCompleteTimers[cueListID] = fadeCompleteTimer
 	s.mu.Unlock()
@@ -308,23 +409,75 @@ func (s *Service) StartCue(cueListID string, cueListName string, cueCount int, c
 
 // ExecuteCueDmx executes a cue's DMX output.
 func (s *Service) ExecuteCueDmx(ctx context.Context, cueID string, fadeInTimeOverride *float64) error {
+	cue, sceneChannels, easingType, err := s.loadCueChannels(ctx, cueID)
+	if err != nil {
+		return err
+	}
+
+	// Determine fade time
+	actualFadeTime := cue.FadeInTime
+	if fadeInTimeOverride != nil {
+		actualFadeTime = *fadeInTimeOverride
+	}
+
+	// Execute fade
+	fadeID := fmt.Sprintf("cue-%s", cueID)
+	duration := time.Duration(actualFadeTime * float64(time.Second))
+	if stackService := s.stackService(); stackService != nil {
+		stackService.Activate(stack.Activation{
+			PlaybackID: stack.CueListPlayback(cue.CueListID),
+			Kind:       stack.KindCueList,
+			SceneID:    cue.SceneID,
+			Channels:   sceneChannels,
+		}, duration, fadeID, easingType)
+	} else {
+		s.fadeEngine.FadeToScene(sceneChannels, duration, fadeID, easingType)
+	}
+
+	// Track the active scene
+	s.dmxService.SetActiveScene(cue.SceneID)
+
+	return nil
+}
+
+func (s *Service) stackService() *stack.Service {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stack
+}
+
+// loadCueChannels loads a cue with its DMX channel values, each with its
+// channel's fade behavior, and the cue's easing type. In a tracking cue list
+// the values are those in effect at the cue, with the list's other channels
+// released to zero.
+func (s *Service) loadCueChannels(ctx context.Context, cueID string) (*models.Cue, []fade.SceneChannel, fade.EasingType, error) {
 	// Load the cue with its scene and fixture values
 	var cue models.Cue
 	result := s.db.WithContext(ctx).
 		Preload("Scene.FixtureValues").
 		First(&cue, "id = ?", cueID)
 	if result.Error != nil {
-		return fmt.Errorf("cue not found: %w", result.Error)
+		return nil, nil, "", fmt.Errorf("cue not found: %w", result.Error)
 	}
 
 	if cue.Scene == nil {
-		return fmt.Errorf("cue has no scene")
+		return nil, nil, "", fmt.Errorf("cue has no scene")
+	}
+
+	values, released, err := s.cueValues(ctx, &cue)
+	if err != nil {
+		return nil, nil, "", err
 	}
+	values = append(values, released...)
 
-	// Load fixtures for the scene's fixture values
+	// Load fixtures for the values
 	var fixtureIDs []string
-	for _, fv := range cue.Scene.FixtureValues {
-		fixtureIDs = append(fixtureIDs, fv.FixtureID)
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v.FixtureID] {
+			seen[v.FixtureID] = true
+			fixtureIDs = append(fixtureIDs, v.FixtureID)
+		}
 	}
 
 	var fixtures []models.FixtureInstance
@@ -339,59 +492,43 @@ func (s *Service) ExecuteCueDmx(ctx context.Context, cueID string, fadeInTimeOve
 		fixtureMap[fixtures[i].ID] = &fixtures[i]
 	}
 
-	// Determine fade time
-	actualFadeTime := cue.FadeInTime
-	if fadeInTimeOverride != nil {
-		actualFadeTime = *fadeInTimeOverride
-	}
-
-	// Build scene channels for fade engine
+	// Build scene channels for fade engine, with fade behavior from channel
+	// definitions
 	var sceneChannels []fade.SceneChannel
 
-	for _, fixtureValue := range cue.Scene.FixtureValues {
-		fixture := fixtureMap[fixtureValue.FixtureID]
+	for _, v := range values {
+		fixture := fixtureMap[v.FixtureID]
 		if fixture == nil {
 			continue
 		}
 
-		// Parse sparse channel values from JSON (Channels field)
-		var channels []models.ChannelValue
-		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
-			log.Printf("Warning: failed to unmarshal channels for fixtureID %s in cueID %s: %v (raw: %v)", fixtureValue.FixtureID, cue.ID, err, fixtureValue.Channels)
+		dmxChannel := fixture.StartChannel + v.Offset
+
+		// Validate DMX channel is within bounds (1-512 per universe)
+		if dmxChannel < 1 || dmxChannel > 512 {
+			log.Warn("DMX channel out of bounds; skipping", "channel", dmxChannel, "fixture", fixture.ID,
+				"universe", fixture.Universe, "startChannel", fixture.StartChannel, "offset", v.Offset)
 			continue
 		}
 
-		// Build channel targets with fade behavior from channel definitions
-		// Only process channels that exist in the sparse array
-		for _, ch := range channels {
-			dmxChannel := fixture.StartChannel + ch.Offset
-
-			// Validate DMX channel is within bounds (1-512 per universe)
-			if dmxChannel < 1 || dmxChannel > 512 {
-				log.Printf("Warning: DMX channel %d out of bounds for fixture %s (universe %d, StartChannel: %d, Offset: %d). Skipping.",
-					dmxChannel, fixture.ID, fixture.Universe, fixture.StartChannel, ch.Offset)
-				continue
-			}
-
-			// Get fade behavior from channel definition (if available)
-			fadeBehavior := fade.FadeBehaviorFade // Default to FADE
-			// Find the channel definition with matching offset
-			for _, chanDef := range fixture.Channels {
-				if chanDef.Offset == ch.Offset {
-					if chanDef.FadeBehavior != "" {
-						fadeBehavior = chanDef.FadeBehavior
-					}
-					break
+		// Get fade behavior from channel definition (if available)
+		fadeBehavior := fade.FadeBehaviorFade // Default to FADE
+		// Find the channel definition with matching offset
+		for _, chanDef := range fixture.Channels {
+			if chanDef.Offset == v.Offset {
+				if chanDef.FadeBehavior != "" {
+					fadeBehavior = chanDef.FadeBehavior
 				}
+				break
 			}
-
-			sceneChannels = append(sceneChannels, fade.SceneChannel{
-				Universe:     fixture.Universe,
-				Channel:      dmxChannel,
-				Value:        ch.Value,
-				FadeBehavior: fadeBehavior,
-			})
 		}
+
+		sceneChannels = append(sceneChannels, fade.SceneChannel{
+			Universe:     fixture.Universe,
+			Channel:      dmxChannel,
+			Value:        v.Value,
+			FadeBehavior: fadeBehavior,
+		})
 	}
 
 	// Get easing type
@@ -400,20 +537,26 @@ func (s *Service) ExecuteCueDmx(ctx context.Context, cueID string, fadeInTimeOve
 		easingType = fade.EasingType(*cue.EasingType)
 	}
 
-	// Execute fade
-	fadeID := fmt.Sprintf("cue-%s", cueID)
-	s.fadeEngine.FadeToScene(sceneChannels, time.Duration(actualFadeTime*float64(time.Second)), fadeID, easingType)
-
-	// Track the active scene
-	s.dmxService.SetActiveScene(cue.SceneID)
-
-	return nil
+	return &cue, sceneChannels, easingType, nil
 }
 
 // handleFollowTime handles automatic follow to the next cue.
 func (s *Service) handleFollowTime(cueListID string, currentCueIndex int) {
 	ctx := context.Background()
 
+	// The follow is no longer pending, whether or not there is a next cue
+	s.mu.Lock()
+	if state := s.states[cueListID]; state != nil && state.CurrentCueIndex != nil && *state.CurrentCueIndex == currentCueIndex {
+		if state.IsPaused {
+			// Paused as the timer fired; resuming reschedules the follow
+			s.mu.Unlock()
+			return
+		}
+		state.FollowAt = nil
+		s.stopFollowCountdownLocked(cueListID)
+	}
+	s.mu.Unlock()
+
 	// Load cue list with cues
 	var cueList models.CueList
 	result := s.db.WithContext(ctx).
@@ -461,24 +604,38 @@ func (s *Service) handleFollowTime(cueListID string, currentCueIndex int) {
 
 	// Execute the cue's DMX output
 	if err := s.ExecuteCueDmx(ctx, nextCue.ID, nil); err != nil {
-		s.StopCueList(cueListID)
+		s.stopCueList(cueListID)
 		return
 	}
+	s.RecordEvent(Event{Type: EventFollow, CueListID: cueListID, CueIndex: &nextCueIndex})
 
 	// Update playback state for the new cue
 	cueForPlayback := &CueForPlayback{
-		ID:          nextCue.ID,
-		Name:        nextCue.Name,
-		CueNumber:   nextCue.CueNumber,
-		FadeInTime:  nextCue.FadeInTime,
-		FadeOutTime: nextCue.FadeOutTime,
-		FollowTime:  nextCue.FollowTime,
+		ID:             nextCue.ID,
+		Name:           nextCue.Name,
+		CueNumber:      nextCue.CueNumber,
+		FadeInTime:     nextCue.FadeInTime,
+		FadeOutTime:    nextCue.FadeOutTime,
+		FollowTime:     nextCue.FollowTime,
+		FollowQuantize: nextCue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), nextCueIndex, cueForPlayback)
 }
 
 // StopCueList stops playback for a cue list.
 func (s *Service) StopCueList(cueListID string) {
+	s.stopCueList(cueListID)
+	s.RecordEvent(Event{Type: EventStopCueList, CueListID: cueListID})
+}
+
+// stopCueList stops playback for a cue list without recording a command.
+func (s *Service) stopCueList(cueListID string) {
+	s.stopPlayback(cueListID)
+	s.journalActiveCue(cueListID, nil)
+}
+
+// stopPlayback stops a cue list's timers and marks it as not playing.
+func (s *Service) stopPlayback(cueListID string) {
 	s.mu.Lock()
 
 	// Stop fade progress ticker
@@ -499,12 +656,18 @@ func (s *Service) StopCueList(cueListID string) {
 		delete(s.fadeCompleteTimers, cueListID)
 	}
 
+	s.stopFollowCountdownLocked(cueListID)
+	s.disarmCrossfadeLocked(cueListID)
+
 	// Update state - scene is no longer active on DMX
 	state := s.states[cueListID]
 	if state != nil {
 		state.IsPlaying = false  // Scene no longer active on DMX
 		state.IsFading = false   // No fade in progress
 		state.FadeProgress = 0
+		state.FollowAt = nil
+		state.IsPaused = false
+		state.PausedFollow = nil
 		state.LastUpdated = time.Now()
 	}
 
@@ -523,12 +686,12 @@ func (s *Service) StopAllCueLists() {
 	s.mu.RUnlock()
 
 	for _, id := range cueListIDs {
-		s.StopCueList(id)
+		s.stopCueList(id)
 	}
 }
 
 // JumpToCue jumps to a specific cue in a cue list.
-func (s *Service) JumpToCue(ctx context.Context, cueListID string, cueIndex int, fadeInTimeOverride *float64) error {
+func (s *Service) JumpToCue(ctx context.Context, cueListID string, cueIndex int, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	// Load cue list with cues
 	var cueList models.CueList
 	result := s.db.WithContext(ctx).
@@ -559,20 +722,22 @@ func (s *Service) JumpToCue(ctx context.Context, cueListID string, cueIndex int,
 	}
 
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  fadeInTime,
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     fadeInTime,
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), cueIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventJumpToCue, CueListID: cueListID, CueIndex: &cueIndex, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 	return nil
 }
 
 // NextCue advances to the next cue.
-func (s *Service) NextCue(ctx context.Context, cueListID string, fadeInTimeOverride *float64) error {
+func (s *Service) NextCue(ctx context.Context, cueListID string, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	s.mu.RLock()
 	state := s.states[cueListID]
 	s.mu.RUnlock()
@@ -612,20 +777,22 @@ func (s *Service) NextCue(ctx context.Context, cueListID string, fadeInTimeOverr
 
 	// Start playback state
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  cue.FadeInTime,
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     overrideFadeTime(cue.FadeInTime, fadeInTimeOverride),
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), nextIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventNextCue, CueListID: cueListID, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 
 	return nil
 }
 
 // PreviousCue goes back to the previous cue.
-func (s *Service) PreviousCue(ctx context.Context, cueListID string, fadeInTimeOverride *float64) error {
+func (s *Service) PreviousCue(ctx context.Context, cueListID string, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	s.mu.RLock()
 	state := s.states[cueListID]
 	s.mu.RUnlock()
@@ -665,20 +832,22 @@ func (s *Service) PreviousCue(ctx context.Context, cueListID string, fadeInTimeO
 
 	// Start playback state
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  cue.FadeInTime,
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     overrideFadeTime(cue.FadeInTime, fadeInTimeOverride),
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), prevIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventPreviousCue, CueListID: cueListID, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 
 	return nil
 }
 
 // GoToCueNumber jumps to a cue by its cue number.
-func (s *Service) GoToCueNumber(ctx context.Context, cueListID string, cueNumber float64, fadeInTimeOverride *float64) error {
+func (s *Service) GoToCueNumber(ctx context.Context, cueListID string, cueNumber float64, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	// Load cue list with cues
 	var cueList models.CueList
 	result := s.db.WithContext(ctx).
@@ -713,20 +882,54 @@ func (s *Service) GoToCueNumber(ctx context.Context, cueListID string, cueNumber
 
 	// Start playback state
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  cue.FadeInTime,
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     overrideFadeTime(cue.FadeInTime, fadeInTimeOverride),
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), cueIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventGoToCueNumber, CueListID: cueListID, CueNumber: &cueNumber, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 
 	return nil
 }
 
+// overrideFadeTime returns the fade time a cue actually plays with: the
+// override given at GO time, or else the cue's own.
+func overrideFadeTime(fadeTime float64, override *float64) float64 {
+	if override != nil {
+		return *override
+	}
+	return fadeTime
+}
+
+// ChaseToCueNumber jumps to a cue as if it had been triggered elapsed ago,
+// as when timecode lands partway through it: the cue's fade runs for only
+// the time it has left, or snaps when already complete.
+func (s *Service) ChaseToCueNumber(ctx context.Context, cueListID string, cueNumber float64, elapsed time.Duration) error {
+	var cue models.Cue
+	result := s.db.WithContext(ctx).
+		Where("cue_list_id = ? AND cue_number = ?", cueListID, cueNumber).
+		First(&cue)
+	if result.Error != nil {
+		return fmt.Errorf("cue number %f not found: %w", cueNumber, result.Error)
+	}
+
+	var fadeInTimeOverride *float64
+	if elapsed > 0 {
+		remaining := cue.FadeInTime - elapsed.Seconds()
+		if remaining < 0 {
+			remaining = 0
+		}
+		fadeInTimeOverride = &remaining
+	}
+	return s.GoToCueNumber(ctx, cueListID, cueNumber, fadeInTimeOverride, nil)
+}
+
 // GoToCueName jumps to a cue by its name.
-func (s *Service) GoToCueName(ctx context.Context, cueListID string, cueName string, fadeInTimeOverride *float64) error {
+func (s *Service) GoToCueName(ctx context.Context, cueListID string, cueName string, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	// Load cue list with cues
 	var cueList models.CueList
 	result := s.db.WithContext(ctx).
@@ -761,20 +964,22 @@ func (s *Service) GoToCueName(ctx context.Context, cueListID string, cueName str
 
 	// Start playback state
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  cue.FadeInTime,
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     overrideFadeTime(cue.FadeInTime, fadeInTimeOverride),
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), cueIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventGoToCueName, CueListID: cueListID, CueName: cueName, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 
 	return nil
 }
 
 // StartCueList starts playing a cue list from the beginning or a specific cue.
-func (s *Service) StartCueList(ctx context.Context, cueListID string, startFromCueNumber *float64, fadeInTimeOverride *float64) error {
+func (s *Service) StartCueList(ctx context.Context, cueListID string, startFromCueNumber *float64, fadeInTimeOverride *float64, fadeOutTimeOverride *float64) error {
 	// Load cue list with cues
 	var cueList models.CueList
 	result := s.db.WithContext(ctx).
@@ -817,14 +1022,16 @@ func (s *Service) StartCueList(ctx context.Context, cueListID string, startFromC
 
 	// Start playback state with actual fade time
 	cueForPlayback := &CueForPlayback{
-		ID:          cue.ID,
-		Name:        cue.Name,
-		CueNumber:   cue.CueNumber,
-		FadeInTime:  actualFadeTime, // Use actual fade time for tracking
-		FadeOutTime: cue.FadeOutTime,
-		FollowTime:  cue.FollowTime,
+		ID:             cue.ID,
+		Name:           cue.Name,
+		CueNumber:      cue.CueNumber,
+		FadeInTime:     actualFadeTime, // Use actual fade time for tracking
+		FadeOutTime:    overrideFadeTime(cue.FadeOutTime, fadeOutTimeOverride),
+		FollowTime:     cue.FollowTime,
+		FollowQuantize: cue.FollowQuantize,
 	}
 	s.StartCue(cueListID, cueList.Name, len(cueList.Cues), startIndex, cueForPlayback)
+	s.RecordEvent(Event{Type: EventStartCueList, CueListID: cueListID, CueNumber: startFromCueNumber, FadeTime: fadeInTimeOverride, FadeOutTime: fadeOutTimeOverride})
 
 	return nil
 }
@@ -880,6 +1087,42 @@ func (s *Service) startFadeProgress(cueListID string, fadeTime float64) {
 	}()
 }
 
+// startFollowCountdownLocked emits a status update every second while the
+// cue list waits for its auto-follow after the fade, so subscribers can show
+// a countdown. The fade progress ticker covers the fade itself.
+func (s *Service) startFollowCountdownLocked(cueListID string) {
+	s.stopFollowCountdownLocked(cueListID)
+	stop := make(chan struct{})
+	s.followCountdowns[cueListID] = stop
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.mu.RLock()
+				state := s.states[cueListID]
+				waiting := state != nil && state.FollowAt != nil && !state.IsFading
+				s.mu.RUnlock()
+				if waiting {
+					s.emitUpdate(cueListID)
+				}
+			}
+		}
+	}()
+}
+
+// stopFollowCountdownLocked stops a cue list's countdown updates.
+func (s *Service) stopFollowCountdownLocked(cueListID string) {
+	if stop := s.followCountdowns[cueListID]; stop != nil {
+		close(stop)
+		delete(s.followCountdowns, cueListID)
+	}
+}
+
 // emitUpdate emits a playback status update.
 func (s *Service) emitUpdate(cueListID string) {
 	s.mu.RLock()
@@ -919,8 +1162,16 @@ func (s *Service) Cleanup() {
 		timer.Stop()
 	}
 
+	// Stop all follow countdowns
+	for _, stop := range s.followCountdowns {
+		close(stop)
+	}
+
 	s.fadeProgressTickers = make(map[string]*time.Ticker)
 	s.followTimers = make(map[string]*time.Timer)
 	s.fadeCompleteTimers = make(map[string]*time.Timer)
+	s.followCountdowns = make(map[string]chan struct{})
+	s.crossfades = make(map[string]*crossfade)
+	s.crossfadePositions = make(map[string]float64)
 	s.states = make(map[string]*PlaybackState)
 }
@@ -0,0 +1,139 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"gorm.io/gorm"
+)
+
+// TrackedValue is a fixture channel value in effect at a cue.
+type TrackedValue struct {
+	FixtureID   string
+	Offset      int
+	Value       int
+	SourceCueID string // The cue whose scene sets the value
+}
+
+// trackedKey identifies a fixture channel.
+type trackedKey struct {
+	fixtureID string
+	offset    int
+}
+
+// TrackedValues returns the fixture channel values in effect at a cue. In a
+// tracking cue list each channel keeps the value from the latest cue at or
+// before this one that sets it, counting back no further than the nearest
+// block cue; otherwise these are the cue's own scene values.
+func (s *Service) TrackedValues(ctx context.Context, cueID string) ([]TrackedValue, error) {
+	var cue models.Cue
+	result := s.db.WithContext(ctx).
+		Preload("Scene.FixtureValues").
+		First(&cue, "id = ?", cueID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("cue not found: %w", result.Error)
+	}
+
+	values, _, err := s.cueValues(ctx, &cue)
+	return values, err
+}
+
+// cueValues returns the values in effect at a cue and, in a tracking cue
+// list, the list's other channels, released to zero so the output does not
+// depend on which cue played before.
+func (s *Service) cueValues(ctx context.Context, cue *models.Cue) ([]TrackedValue, []TrackedValue, error) {
+	var cueList models.CueList
+	result := s.db.WithContext(ctx).
+		Preload("Cues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cue_number ASC")
+		}).
+		Preload("Cues.Scene.FixtureValues").
+		First(&cueList, "id = ?", cue.CueListID)
+	if result.Error != nil || !cueList.Tracking {
+		// Cues played outside a cue list use their own scene
+		return sceneValues(cue), nil, nil
+	}
+
+	index := -1
+	for i := range cueList.Cues {
+		if cueList.Cues[i].ID == cue.ID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return sceneValues(cue), nil, nil
+	}
+
+	values := trackValues(cueList.Cues, index)
+	inEffect := make(map[trackedKey]bool, len(values))
+	for _, v := range values {
+		inEffect[trackedKey{v.FixtureID, v.Offset}] = true
+	}
+	var released []TrackedValue
+	for i := range cueList.Cues {
+		for _, v := range sceneValues(&cueList.Cues[i]) {
+			key := trackedKey{v.FixtureID, v.Offset}
+			if !inEffect[key] {
+				inEffect[key] = true
+				released = append(released, TrackedValue{FixtureID: v.FixtureID, Offset: v.Offset})
+			}
+		}
+	}
+	return values, released, nil
+}
+
+// trackValues merges the scene values of cues (in cue order) from the
+// nearest block cue at or before index up to index, later cues winning.
+func trackValues(cues []models.Cue, index int) []TrackedValue {
+	start := 0
+	for i := index; i > 0; i-- {
+		if cues[i].Block {
+			start = i
+			break
+		}
+	}
+
+	var values []TrackedValue
+	positions := make(map[trackedKey]int)
+	for i := start; i <= index; i++ {
+		for _, v := range sceneValues(&cues[i]) {
+			key := trackedKey{v.FixtureID, v.Offset}
+			if pos, ok := positions[key]; ok {
+				values[pos] = v
+				continue
+			}
+			positions[key] = len(values)
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sceneValues returns the channel values a cue's scene sets.
+func sceneValues(cue *models.Cue) []TrackedValue {
+	if cue.Scene == nil {
+		return nil
+	}
+
+	var values []TrackedValue
+	for _, fixtureValue := range cue.Scene.FixtureValues {
+		// Parse sparse channel values from JSON (Channels field)
+		var channels []models.ChannelValue
+		if err := json.Unmarshal([]byte(fixtureValue.Channels), &channels); err != nil {
+			log.Warn("failed to unmarshal channels", "fixture", fixtureValue.FixtureID, "cue", cue.ID, "raw", fixtureValue.Channels, "error", err)
+			continue
+		}
+		for _, ch := range channels {
+			values = append(values, TrackedValue{
+				FixtureID:   fixtureValue.FixtureID,
+				Offset:      ch.Offset,
+				Value:       ch.Value,
+				SourceCueID: cue.ID,
+			})
+		}
+	}
+	return values
+}
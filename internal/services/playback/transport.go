@@ -0,0 +1,174 @@
+package playback
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/stack"
+)
+
+// PauseCueList holds a playing cue list's follow chain: a pending auto-follow
+// stops counting down until the list is resumed. The current cue's fade runs
+// to its end, and a GO moves on and clears the pause.
+func (s *Service) PauseCueList(cueListID string) error {
+	s.mu.Lock()
+	state := s.states[cueListID]
+	if state == nil || !state.IsPlaying {
+		s.mu.Unlock()
+		return fmt.Errorf("cue list is not playing: %s", cueListID)
+	}
+	if state.IsPaused {
+		s.mu.Unlock()
+		return nil
+	}
+
+	if timer := s.followTimers[cueListID]; timer != nil {
+		timer.Stop()
+		delete(s.followTimers, cueListID)
+	}
+	s.stopFollowCountdownLocked(cueListID)
+	if state.FollowAt != nil {
+		remaining := time.Until(*state.FollowAt)
+		if remaining < 0 {
+			remaining = 0
+		}
+		state.PausedFollow = &remaining
+		state.FollowAt = nil
+	}
+	state.IsPaused = true
+	state.LastUpdated = time.Now()
+	s.mu.Unlock()
+
+	s.RecordEvent(Event{Type: EventPauseCueList, CueListID: cueListID})
+	s.emitUpdate(cueListID)
+	return nil
+}
+
+// ResumeCueList restarts a paused cue list's follow chain, with the follow
+// time that was left when it was paused. Resuming a list that is not paused
+// does nothing.
+func (s *Service) ResumeCueList(cueListID string) {
+	s.mu.Lock()
+	state := s.states[cueListID]
+	if state == nil || !state.IsPaused {
+		s.mu.Unlock()
+		return
+	}
+
+	state.IsPaused = false
+	// Replays drive follows from the log instead
+	if state.PausedFollow != nil && state.CurrentCueIndex != nil && !s.isReplaying() {
+		cueIndex := *state.CurrentCueIndex
+		remaining := *state.PausedFollow
+		s.followTimers[cueListID] = time.AfterFunc(remaining, func() {
+			s.handleFollowTime(cueListID, cueIndex)
+		})
+		followAt := time.Now().Add(remaining)
+		state.FollowAt = &followAt
+		s.startFollowCountdownLocked(cueListID)
+	}
+	state.PausedFollow = nil
+	state.LastUpdated = time.Now()
+	s.mu.Unlock()
+
+	s.RecordEvent(Event{Type: EventResumeCueList, CueListID: cueListID})
+	s.emitUpdate(cueListID)
+}
+
+// Suspend pauses every playing cue list that is not already paused, as while
+// the server is in standby, so no follow fires until Resume.
+func (s *Service) Suspend() {
+	s.mu.RLock()
+	var ids []string
+	for id, state := range s.states {
+		if state.IsPlaying && !state.IsPaused {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(ids)
+
+	var suspended []string
+	for _, id := range ids {
+		if err := s.PauseCueList(id); err == nil {
+			suspended = append(suspended, id)
+		}
+	}
+
+	s.mu.Lock()
+	s.suspended = append(s.suspended, suspended...)
+	s.mu.Unlock()
+}
+
+// Resume resumes the cue lists Suspend paused. Lists resumed or stopped in
+// the meantime are left as they are.
+func (s *Service) Resume() {
+	s.mu.Lock()
+	suspended := s.suspended
+	s.suspended = nil
+	s.mu.Unlock()
+
+	for _, id := range suspended {
+		s.ResumeCueList(id)
+	}
+}
+
+// ReleaseCueList stops a cue list and takes its cue off the playback stack,
+// fading its channels out over fadeOutTime, or else the current cue's
+// fade-out time, to what the remaining playbacks set.
+func (s *Service) ReleaseCueList(cueListID string, fadeOutTime *float64) {
+	s.mu.RLock()
+	fadeTime := 0.0
+	if state := s.states[cueListID]; state != nil && state.CurrentCue != nil {
+		fadeTime = state.CurrentCue.FadeOutTime
+	}
+	s.mu.RUnlock()
+	if fadeOutTime != nil {
+		fadeTime = *fadeOutTime
+	}
+
+	s.stopCueList(cueListID)
+	if stackService := s.stackService(); stackService != nil {
+		stackService.Release(stack.CueListPlayback(cueListID), time.Duration(fadeTime*float64(time.Second)))
+	}
+	s.RecordEvent(Event{Type: EventReleaseCueList, CueListID: cueListID, FadeTime: fadeOutTime})
+}
+
+// ActiveCueLists returns the status of every playing cue list, the most
+// recently started first.
+func (s *Service) ActiveCueLists() []*CueListPlaybackStatus {
+	s.mu.RLock()
+	var playing []*PlaybackState
+	for _, state := range s.states {
+		if state.IsPlaying {
+			playing = append(playing, state)
+		}
+	}
+	sort.Slice(playing, func(i, j int) bool {
+		return startedAfter(playing[i], playing[j])
+	})
+	cueListIDs := make([]string, len(playing))
+	for i, state := range playing {
+		cueListIDs[i] = state.CueListID
+	}
+	s.mu.RUnlock()
+
+	statuses := make([]*CueListPlaybackStatus, len(cueListIDs))
+	for i, id := range cueListIDs {
+		statuses[i] = s.GetFormattedStatus(id)
+	}
+	return statuses
+}
+
+// startedAfter reports whether a's current cue started after b's, ordering
+// ties by cue list ID.
+func startedAfter(a, b *PlaybackState) bool {
+	if a.StartTime != nil && b.StartTime != nil && !a.StartTime.Equal(*b.StartTime) {
+		return a.StartTime.After(*b.StartTime)
+	}
+	if (a.StartTime == nil) != (b.StartTime == nil) {
+		return a.StartTime != nil
+	}
+	return a.CueListID < b.CueListID
+}
@@ -0,0 +1,360 @@
+// Package presence tracks which operators are viewing or editing which
+// scenes and cue lists, and holds soft edit locks so two operators cannot
+// change the same record at once.
+//
+// Presence lives in memory. Each client session reports what it is looking
+// at; a session is dropped when its presence subscription closes or, for
+// clients without one, when it stops reporting.
+package presence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+)
+
+// Activity is what a session is doing with its entity.
+type Activity string
+
+const (
+	ActivityViewing Activity = "VIEWING"
+	// ActivityEditing holds the entity's edit lock.
+	ActivityEditing Activity = "EDITING"
+)
+
+// DefaultTimeout is how long a session without a presence subscription
+// stays present after its last report.
+const DefaultTimeout = 45 * time.Second
+
+// SweepInterval is how often expired sessions are dropped.
+const SweepInterval = 10 * time.Second
+
+// lockable lists the entity types that can be edit-locked.
+var lockable = map[string]bool{
+	audit.EntityScene:   true,
+	audit.EntityCueList: true,
+	audit.EntityCue:     true,
+}
+
+// Presence is a snapshot of one session's presence.
+type Presence struct {
+	SessionID  string
+	ProjectID  string
+	Operator   string
+	UserID     *string
+	EntityType *string
+	EntityID   *string
+	Activity   Activity
+	Since      time.Time // When the session started on its current entity
+	UpdatedAt  time.Time
+}
+
+// Update reports what a session is doing.
+type Update struct {
+	SessionID  string
+	ProjectID  string
+	Operator   string
+	UserID     *string
+	EntityType string // Empty when not on a particular entity
+	EntityID   string
+	Activity   Activity
+	// Force takes over an edit lock held by another session
+	Force bool
+}
+
+// LockError reports an edit lock held by another session.
+type LockError struct {
+	EntityType string
+	EntityID   string
+	Holder     Presence
+}
+
+func (e *LockError) Error() string {
+	return fmt.Sprintf("%s %s is being edited by %s", strings.ToLower(strings.ReplaceAll(e.EntityType, "_", " ")), e.EntityID, e.Holder.Operator)
+}
+
+// Service tracks session presence and edit locks.
+type Service struct {
+	mu       sync.Mutex
+	sessions map[string]*Presence
+	attached map[string]int // Open presence subscriptions per session
+	timeout  time.Duration
+
+	// Called with a project whose presence changed (optional)
+	onChange func(projectID string)
+
+	stop chan struct{}
+	done chan struct{}
+	now  func() time.Time
+}
+
+// NewService creates a presence service.
+func NewService() *Service {
+	return &Service{
+		sessions: make(map[string]*Presence),
+		attached: make(map[string]int),
+		timeout:  DefaultTimeout,
+		now:      time.Now,
+	}
+}
+
+// SetChangeCallback sets the callback for presence changes.
+func (s *Service) SetChangeCallback(callback func(projectID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// Update records a session's presence. Editing takes the entity's edit
+// lock, failing with a *LockError if another session holds it unless
+// Force is set, in which case the holder drops back to viewing.
+func (s *Service) Update(u Update) (*Presence, error) {
+	if u.SessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+	if u.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	if (u.EntityType == "") != (u.EntityID == "") {
+		return nil, fmt.Errorf("entity type and ID must be given together")
+	}
+	if u.EntityType != "" && !lockable[u.EntityType] {
+		return nil, fmt.Errorf("invalid presence entity type: %s", u.EntityType)
+	}
+	if u.Activity == "" {
+		u.Activity = ActivityViewing
+	}
+	if u.Activity != ActivityViewing && u.Activity != ActivityEditing {
+		return nil, fmt.Errorf("invalid presence activity: %s", u.Activity)
+	}
+	if u.Activity == ActivityEditing && u.EntityType == "" {
+		return nil, fmt.Errorf("editing requires an entity")
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	changed := s.expireLocked(now)
+
+	if u.Activity == ActivityEditing {
+		if holder := s.lockHolderLocked(u.EntityType, u.EntityID, u.SessionID); holder != nil {
+			if !u.Force {
+				err := &LockError{EntityType: u.EntityType, EntityID: u.EntityID, Holder: *holder}
+				s.mu.Unlock()
+				s.notify(changed)
+				return nil, err
+			}
+			holder.Activity = ActivityViewing
+			holder.UpdatedAt = now
+			changed[holder.ProjectID] = true
+		}
+	}
+
+	sess, ok := s.sessions[u.SessionID]
+	if !ok {
+		sess = &Presence{}
+		s.sessions[u.SessionID] = sess
+	} else if sess.ProjectID != u.ProjectID {
+		changed[sess.ProjectID] = true
+	}
+	if !ok || stringValue(sess.EntityType) != u.EntityType || stringValue(sess.EntityID) != u.EntityID {
+		sess.Since = now
+	}
+	sess.SessionID = u.SessionID
+	sess.ProjectID = u.ProjectID
+	sess.Operator = u.Operator
+	sess.UserID = u.UserID
+	sess.EntityType = optional(u.EntityType)
+	sess.EntityID = optional(u.EntityID)
+	sess.Activity = u.Activity
+	sess.UpdatedAt = now
+	changed[u.ProjectID] = true
+	snapshot := *sess
+	s.mu.Unlock()
+
+	s.notify(changed)
+	return &snapshot, nil
+}
+
+// Leave removes a session, releasing its lock, and reports whether it was
+// present.
+func (s *Service) Leave(sessionID string) bool {
+	s.mu.Lock()
+	changed := s.expireLocked(s.now())
+	sess, ok := s.sessions[sessionID]
+	if ok {
+		delete(s.sessions, sessionID)
+		changed[sess.ProjectID] = true
+	}
+	s.mu.Unlock()
+
+	s.notify(changed)
+	return ok
+}
+
+// Attach keeps a session present for as long as a client is subscribed,
+// however long since it last reported. The returned function detaches it,
+// removing the session once its last subscription closes.
+func (s *Service) Attach(sessionID string) func() {
+	s.mu.Lock()
+	s.attached[sessionID]++
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.attached[sessionID]--
+			remaining := s.attached[sessionID]
+			if remaining <= 0 {
+				delete(s.attached, sessionID)
+			}
+			s.mu.Unlock()
+
+			if remaining <= 0 {
+				s.Leave(sessionID)
+			}
+		})
+	}
+}
+
+// List returns the sessions present in a project, ordered by operator.
+func (s *Service) List(projectID string) []*Presence {
+	s.mu.Lock()
+	changed := s.expireLocked(s.now())
+	var result []*Presence
+	for _, sess := range s.sessions {
+		if sess.ProjectID == projectID {
+			snapshot := *sess
+			result = append(result, &snapshot)
+		}
+	}
+	s.mu.Unlock()
+	s.notify(changed)
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Operator != result[j].Operator {
+			return result[i].Operator < result[j].Operator
+		}
+		return result[i].SessionID < result[j].SessionID
+	})
+	return result
+}
+
+// CheckEdit returns a *LockError if any lockable record in refs is locked
+// by a session other than sessionID.
+func (s *Service) CheckEdit(sessionID string, refs []auth.Ref) error {
+	s.mu.Lock()
+	changed := s.expireLocked(s.now())
+	var err error
+	for _, ref := range refs {
+		if !lockable[ref.EntityType] {
+			continue
+		}
+		if holder := s.lockHolderLocked(ref.EntityType, ref.ID, sessionID); holder != nil {
+			err = &LockError{EntityType: ref.EntityType, EntityID: ref.ID, Holder: *holder}
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(changed)
+	return err
+}
+
+// Start drops expired sessions every interval until Cleanup.
+func (s *Service) Start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interval <= 0 || s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.sweepLoop(interval, s.stop, s.done)
+}
+
+// Cleanup stops dropping expired sessions.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (s *Service) sweepLoop(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			changed := s.expireLocked(s.now())
+			s.mu.Unlock()
+			s.notify(changed)
+		}
+	}
+}
+
+// expireLocked drops unattached sessions that have stopped reporting,
+// returning the projects that changed.
+func (s *Service) expireLocked(now time.Time) map[string]bool {
+	changed := make(map[string]bool)
+	for id, sess := range s.sessions {
+		if s.attached[id] == 0 && now.Sub(sess.UpdatedAt) > s.timeout {
+			delete(s.sessions, id)
+			changed[sess.ProjectID] = true
+		}
+	}
+	return changed
+}
+
+// lockHolderLocked returns the session other than sessionID editing an
+// entity, or nil.
+func (s *Service) lockHolderLocked(entityType, entityID, sessionID string) *Presence {
+	for id, sess := range s.sessions {
+		if id != sessionID && sess.Activity == ActivityEditing &&
+			stringValue(sess.EntityType) == entityType && stringValue(sess.EntityID) == entityID {
+			return sess
+		}
+	}
+	return nil
+}
+
+func (s *Service) notify(projects map[string]bool) {
+	s.mu.Lock()
+	callback := s.onChange
+	s.mu.Unlock()
+	if callback == nil {
+		return
+	}
+	for projectID := range projects {
+		callback(projectID)
+	}
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
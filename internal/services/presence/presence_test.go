@@ -0,0 +1,156 @@
+package presence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/audit"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+)
+
+func newTestService() (*Service, *time.Time) {
+	now := time.Date(2024, 3, 1, 19, 30, 0, 0, time.UTC)
+	s := NewService()
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func editing(sessionID, operator, cueListID string) Update {
+	return Update{
+		SessionID:  sessionID,
+		ProjectID:  "project-1",
+		Operator:   operator,
+		EntityType: audit.EntityCueList,
+		EntityID:   cueListID,
+		Activity:   ActivityEditing,
+	}
+}
+
+func TestUpdate_EditLock(t *testing.T) {
+	s, _ := newTestService()
+
+	if _, err := s.Update(editing("a", "Alice", "list-1")); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	_, err := s.Update(editing("b", "Bob", "list-1"))
+	var lockErr *LockError
+	if !errors.As(err, &lockErr) || lockErr.Holder.SessionID != "a" {
+		t.Fatalf("Expected lock held by a, got %v", err)
+	}
+	if got := err.Error(); got != "cue list list-1 is being edited by Alice" {
+		t.Errorf("LockError = %q", got)
+	}
+
+	// The holder can keep reporting, and others can still view
+	if _, err := s.Update(editing("a", "Alice", "list-1")); err != nil {
+		t.Errorf("Holder's report failed: %v", err)
+	}
+	viewing := editing("b", "Bob", "list-1")
+	viewing.Activity = ActivityViewing
+	if _, err := s.Update(viewing); err != nil {
+		t.Errorf("Viewing a locked cue list failed: %v", err)
+	}
+
+	forced := editing("b", "Bob", "list-1")
+	forced.Force = true
+	if _, err := s.Update(forced); err != nil {
+		t.Fatalf("Forced takeover failed: %v", err)
+	}
+	for _, p := range s.List("project-1") {
+		want := map[string]Activity{"a": ActivityViewing, "b": ActivityEditing}[p.SessionID]
+		if p.Activity != want {
+			t.Errorf("Session %s activity = %s, want %s", p.SessionID, p.Activity, want)
+		}
+	}
+}
+
+func TestCheckEdit(t *testing.T) {
+	s, _ := newTestService()
+	if _, err := s.Update(editing("a", "Alice", "list-1")); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	refs := []auth.Ref{{EntityType: audit.EntityCue, ID: "cue-1"}, {EntityType: audit.EntityCueList, ID: "list-1"}}
+	if err := s.CheckEdit("a", refs); err != nil {
+		t.Errorf("Holder's edit was blocked: %v", err)
+	}
+	if err := s.CheckEdit("b", refs); err == nil {
+		t.Error("Expected another session's edit to be blocked")
+	}
+	if err := s.CheckEdit("", refs[:1]); err != nil {
+		t.Errorf("Edit of an unlocked cue was blocked: %v", err)
+	}
+
+	s.Leave("a")
+	if err := s.CheckEdit("b", refs); err != nil {
+		t.Errorf("Edit after the holder left was blocked: %v", err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	s, now := newTestService()
+	var changes []string
+	s.SetChangeCallback(func(projectID string) { changes = append(changes, projectID) })
+
+	if _, err := s.Update(editing("a", "Alice", "list-1")); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	detach := s.Attach("b")
+	if _, err := s.Update(Update{SessionID: "b", ProjectID: "project-1", Operator: "Bob"}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	*now = now.Add(DefaultTimeout + time.Second)
+	sessions := s.List("project-1")
+	if len(sessions) != 1 || sessions[0].SessionID != "b" {
+		t.Fatalf("Expected only the attached session to remain, got %+v", sessions)
+	}
+	if err := s.CheckEdit("c", []auth.Ref{{EntityType: audit.EntityCueList, ID: "list-1"}}); err != nil {
+		t.Errorf("Expired lock still blocks edits: %v", err)
+	}
+
+	detach()
+	detach() // Detaching twice is harmless
+	if sessions := s.List("project-1"); len(sessions) != 0 {
+		t.Errorf("Expected the session to leave when detached, got %+v", sessions)
+	}
+	if len(changes) != 4 {
+		t.Errorf("Expected 4 change notifications, got %v", changes)
+	}
+}
+
+func TestUpdate_Validation(t *testing.T) {
+	s, _ := newTestService()
+	for name, u := range map[string]Update{
+		"no session":        {ProjectID: "p"},
+		"no project":        {SessionID: "a"},
+		"entity without ID": {SessionID: "a", ProjectID: "p", EntityType: audit.EntityScene},
+		"unlockable entity": {SessionID: "a", ProjectID: "p", EntityType: audit.EntityProject, EntityID: "p"},
+		"editing no entity": {SessionID: "a", ProjectID: "p", Activity: ActivityEditing},
+		"unknown activity":  {SessionID: "a", ProjectID: "p", Activity: "DANCING"},
+	} {
+		if _, err := s.Update(u); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestIsEdit(t *testing.T) {
+	for operation, want := range map[string]bool{
+		"updateCue":         true,
+		"deleteScene":       true,
+		"bulkUpdateCues":    true,
+		"reorderCues":       true,
+		"nextCue":           false,
+		"setSceneLive":      false,
+		"updatePresence":    false,
+		"createScene":       false,
+		"addSceneToBoard":   true,
+		"removeProjectUser": true,
+	} {
+		if got := isEdit(operation); got != want {
+			t.Errorf("isEdit(%s) = %v, want %v", operation, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package presence
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/bbernstein/lacylights-go/internal/services/auth"
+)
+
+// SessionHeader is the HTTP header a client sends its presence session ID
+// in, so its own edit locks do not block its edits.
+const SessionHeader = "X-LacyLights-Session"
+
+// SessionPayloadKey is the websocket connection parameter a client sends
+// its presence session ID in.
+const SessionPayloadKey = "sessionId"
+
+// presenceOperations report presence and are never blocked by locks.
+var presenceOperations = map[string]bool{
+	"updatePresence": true,
+	"leavePresence":  true,
+}
+
+// editPrefixes start the names of mutations that change records, as
+// opposed to playback controls such as nextCue or setSceneLive.
+var editPrefixes = []string{"update", "delete", "bulk", "reorder", "replace", "add", "remove", "copy"}
+
+type sessionKey struct{}
+
+// WithSession returns a context recording the client's presence session.
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sessionID)
+}
+
+// SessionFromContext returns the client's presence session, or "".
+func SessionFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionKey{}).(string)
+	return sessionID
+}
+
+// Middleware records the presence session named in SessionHeader.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sessionID := strings.TrimSpace(r.Header.Get(SessionHeader)); sessionID != "" {
+			r = r.WithContext(WithSession(r.Context(), sessionID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WebsocketInit records a websocket connection's presence session from its
+// connection parameters.
+func WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	if sessionID := strings.TrimSpace(payload.GetString(SessionPayloadKey)); sessionID != "" {
+		ctx = WithSession(ctx, sessionID)
+	}
+	return ctx, nil, nil
+}
+
+var (
+	_ graphql.HandlerExtension = (*Service)(nil)
+	_ graphql.FieldInterceptor = (*Service)(nil)
+)
+
+// ExtensionName implements graphql.HandlerExtension.
+func (s *Service) ExtensionName() string {
+	return "Presence"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (s *Service) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField rejects edits to records another session holds the edit
+// lock on.
+func (s *Service) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" || !isEdit(fc.Field.Name) {
+		return next(ctx)
+	}
+	if err := s.CheckEdit(SessionFromContext(ctx), auth.References(fc.Field.Name, fc.Args)); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+func isEdit(operation string) bool {
+	if presenceOperations[operation] {
+		return false
+	}
+	for _, prefix := range editPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
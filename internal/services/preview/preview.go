@@ -5,7 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,6 +22,26 @@ type Session struct {
 	IsActive         bool
 	CreatedAt        time.Time
 	ChannelOverrides map[string]int // Key: "universe:channel", Value: 0-255
+
+	// Blind sessions render edits to the session's buffer (and any preview
+	// universes) only, leaving the live output untouched until committed
+	Blind            bool
+	PreviewUniverses map[int]int     // Live universe -> preview universe
+	SceneID          *string         // The scene the session was initialized with
+	Edits            map[string]Edit // Key: "universe:channel"
+
+	// The Art-Net address a blind session's preview universes are sent to
+	// instead of the stage, making them shadow universes (optional)
+	VisualizerAddress string
+}
+
+// Edit is a fixture channel value set in a preview session.
+type Edit struct {
+	FixtureID string
+	Offset    int
+	Universe  int
+	Channel   int
+	Value     int
 }
 
 // DMXOutput represents DMX output for a universe.
@@ -65,8 +85,28 @@ func (s *Service) SetSessionUpdateCallback(callback func(session *Session, dmxOu
 	s.onSessionUpdate = callback
 }
 
-// StartSession starts a new preview session for a project.
+// StartSession starts a new preview session for a project. Its edits are
+// applied to the live output as they are made.
 func (s *Service) StartSession(ctx context.Context, projectID string, userID *string) (*Session, error) {
+	return s.startSession(ctx, projectID, userID, false, nil, "")
+}
+
+// StartBlindSession starts a new blind preview session for a project. Its
+// edits are kept out of the live output; each live universe in
+// previewUniverses is mirrored, with the edits on top, to its preview
+// universe.
+func (s *Service) StartBlindSession(ctx context.Context, projectID string, userID *string, previewUniverses map[int]int) (*Session, error) {
+	return s.startSession(ctx, projectID, userID, true, previewUniverses, "")
+}
+
+// StartVisualizerSession starts a new blind preview session whose preview
+// universes are shadow universes, outside the live ones, sent over Art-Net
+// only to the visualizer at visualizerAddress ("ip" or "ip:port").
+func (s *Service) StartVisualizerSession(ctx context.Context, projectID string, userID *string, previewUniverses map[int]int, visualizerAddress string) (*Session, error) {
+	return s.startSession(ctx, projectID, userID, true, previewUniverses, visualizerAddress)
+}
+
+func (s *Service) startSession(ctx context.Context, projectID string, userID *string, blind bool, previewUniverses map[int]int, visualizerAddress string) (*Session, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -77,12 +117,23 @@ func (s *Service) StartSession(ctx context.Context, projectID string, userID *st
 	sessionID := fmt.Sprintf("preview_%d_%s", time.Now().UnixNano(), randomString(9))
 
 	session := &Session{
-		ID:               sessionID,
-		ProjectID:        projectID,
-		UserID:           userID,
-		IsActive:         true,
-		CreatedAt:        time.Now(),
-		ChannelOverrides: make(map[string]int),
+		ID:                sessionID,
+		ProjectID:         projectID,
+		UserID:            userID,
+		IsActive:          true,
+		CreatedAt:         time.Now(),
+		ChannelOverrides:  make(map[string]int),
+		Blind:             blind,
+		PreviewUniverses:  make(map[int]int),
+		VisualizerAddress: visualizerAddress,
+		Edits:             make(map[string]Edit),
+	}
+	for universe, previewUniverse := range previewUniverses {
+		session.PreviewUniverses[universe] = previewUniverse
+	}
+	if err := s.applyPreviewUniversesLocked(session); err != nil {
+		s.releaseLocked(session)
+		return nil, err
 	}
 
 	s.sessions[sessionID] = session
@@ -134,13 +185,23 @@ func (s *Service) UpdateChannelValue(ctx context.Context, sessionID string, fixt
 
 	// Update the channel override in session state
 	session.ChannelOverrides[channelKey] = value
+	session.Edits[channelKey] = Edit{
+		FixtureID: fixtureID,
+		Offset:    channelIndex,
+		Universe:  fixture.Universe,
+		Channel:   absoluteChannel,
+		Value:     value,
+	}
 
 	// Apply to live DMX output immediately via channel override.
 	// Preview overrides take precedence over scene playback, allowing
 	// real-time channel adjustments to be visible on the physical fixtures.
-	if s.dmxService != nil {
+	if s.dmxService != nil && !session.Blind {
 		s.dmxService.SetChannelOverride(fixture.Universe, absoluteChannel, byte(value))
 	}
+	if err := s.applyPreviewUniversesLocked(session); err != nil {
+		log.Warn("failed to update preview universes", "session", sessionID, "error", err)
+	}
 
 	// Reset session timeout
 	if timer, exists := s.sessionTimers[sessionID]; exists {
@@ -158,6 +219,7 @@ func (s *Service) UpdateChannelValue(ctx context.Context, sessionID string, fixt
 }
 
 // CommitSession commits a preview session (keeps changes, cleans up session).
+// A blind session's edits are not live; the caller applies Edits first.
 func (s *Service) CommitSession(ctx context.Context, sessionID string) (bool, error) {
 	// The preview changes are already live in DMX output
 	// Just clean up the session
@@ -181,13 +243,7 @@ func (s *Service) CancelSession(ctx context.Context, sessionID string) (bool, er
 	}
 
 	// Remove channel overrides from DMX output
-	for channelKey := range session.ChannelOverrides {
-		var universe, channel int
-		_, _ = fmt.Sscanf(channelKey, "%d:%d", &universe, &channel)
-		if s.dmxService != nil {
-			s.dmxService.ClearChannelOverride(universe, channel)
-		}
-	}
+	s.releaseLocked(session)
 
 	// Mark session as inactive and remove
 	session.IsActive = false
@@ -225,6 +281,8 @@ func (s *Service) InitializeWithScene(ctx context.Context, sessionID string, sce
 		return false, err
 	}
 
+	session.SceneID = &sceneID
+
 	// Apply all fixture values from the scene
 	for _, fv := range fixtureValues {
 		fixture, err := s.fixtureRepo.FindByID(ctx, fv.FixtureID)
@@ -238,7 +296,7 @@ func (s *Service) InitializeWithScene(ctx context.Context, sessionID string, sce
 		// Parse sparse channel values from JSON
 		var channels []models.ChannelValue
 		if err := json.Unmarshal([]byte(fv.Channels), &channels); err != nil {
-			log.Printf("Warning: failed to unmarshal channels for fixtureID %s in scene: %v", fv.FixtureID, err)
+			log.Warn("failed to unmarshal scene fixture channels", "fixture", fv.FixtureID, "error", err)
 			continue
 		}
 
@@ -264,11 +322,14 @@ func (s *Service) InitializeWithScene(ctx context.Context, sessionID string, sce
 			session.ChannelOverrides[channelKey] = value
 
 			// Apply to live DMX output via override (preview takes precedence)
-			if s.dmxService != nil {
+			if s.dmxService != nil && !session.Blind {
 				s.dmxService.SetChannelOverride(fixture.Universe, absoluteChannel, byte(value))
 			}
 		}
 	}
+	if err := s.applyPreviewUniversesLocked(session); err != nil {
+		log.Warn("failed to update preview universes", "session", sessionID, "error", err)
+	}
 
 	// Notify subscribers
 	if s.onSessionUpdate != nil {
@@ -306,6 +367,14 @@ func (s *Service) GetDMXOutput(sessionID string) []DMXOutput {
 	return s.getCurrentDMXOutputLocked(sessionID)
 }
 
+// GetUniverseChannels returns a universe's channel values as a session sees
+// them: the live output with the session's values over it.
+func (s *Service) GetUniverseChannels(sessionID string, universe int) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getUniverseChannelsLocked(universe, sessionID)
+}
+
 // cancelExistingProjectSessionsLocked cancels all sessions for a project.
 // Must be called with lock held.
 func (s *Service) cancelExistingProjectSessionsLocked(projectID string) {
@@ -318,13 +387,7 @@ func (s *Service) cancelExistingProjectSessionsLocked(projectID string) {
 			}
 
 			// Remove channel overrides
-			for channelKey := range session.ChannelOverrides {
-				var universe, channel int
-				_, _ = fmt.Sscanf(channelKey, "%d:%d", &universe, &channel)
-				if s.dmxService != nil {
-					s.dmxService.ClearChannelOverride(universe, channel)
-				}
-			}
+			s.releaseLocked(session)
 
 			session.IsActive = false
 			delete(s.sessions, sessionID)
@@ -332,6 +395,77 @@ func (s *Service) cancelExistingProjectSessionsLocked(projectID string) {
 	}
 }
 
+// GetEdits returns the channel values set in a session, in universe and
+// channel order.
+func (s *Service) GetEdits(sessionID string) []Edit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, exists := s.sessions[sessionID]
+	if !exists {
+		return nil
+	}
+	edits := make([]Edit, 0, len(session.Edits))
+	for _, edit := range session.Edits {
+		edits = append(edits, edit)
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].Universe != edits[j].Universe {
+			return edits[i].Universe < edits[j].Universe
+		}
+		return edits[i].Channel < edits[j].Channel
+	})
+	return edits
+}
+
+// applyPreviewUniversesLocked outputs a blind session's view to its preview
+// universes, or to its visualizer. Must be called with lock held.
+func (s *Service) applyPreviewUniversesLocked(session *Session) error {
+	if s.dmxService == nil || !session.Blind {
+		return nil
+	}
+	for universe, previewUniverse := range session.PreviewUniverses {
+		values := make(map[int]byte)
+		for channelKey, value := range session.ChannelOverrides {
+			var channelUniverse, channel int
+			_, _ = fmt.Sscanf(channelKey, "%d:%d", &channelUniverse, &channel)
+			if channelUniverse == universe {
+				values[channel] = byte(value)
+			}
+		}
+		var err error
+		if session.VisualizerAddress != "" {
+			err = s.dmxService.SetVisualizerUniverse(previewUniverse, universe, values, session.VisualizerAddress)
+		} else {
+			err = s.dmxService.SetPreviewUniverse(previewUniverse, universe, values)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseLocked removes a session's changes from the DMX output: the live
+// overrides of a session, or the preview universes of a blind one. Must be
+// called with lock held.
+func (s *Service) releaseLocked(session *Session) {
+	if s.dmxService == nil {
+		return
+	}
+	if session.Blind {
+		for _, previewUniverse := range session.PreviewUniverses {
+			s.dmxService.ClearPreviewUniverse(previewUniverse)
+		}
+		return
+	}
+	for channelKey := range session.ChannelOverrides {
+		var universe, channel int
+		_, _ = fmt.Sscanf(channelKey, "%d:%d", &universe, &channel)
+		s.dmxService.ClearChannelOverride(universe, channel)
+	}
+}
+
 // getCurrentDMXOutputLocked returns the current DMX output for a session.
 // Must be called with lock held.
 func (s *Service) getCurrentDMXOutputLocked(sessionID string) []DMXOutput {
@@ -712,3 +712,85 @@ func TestInitializeWithScene_MultipleFixtures(t *testing.T) {
 }
 
 // Helper is unused, fmt is imported at top
+
+// TestBlindSession_KeepsLiveOutput tests that a blind session's edits reach
+// only its buffer and preview universe.
+func TestBlindSession_KeepsLiveOutput(t *testing.T) {
+	testDB, service, cleanup := setupPreviewTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project, fixture := createTestProjectWithFixture(t, testDB)
+	service.dmxService.SetChannelValue(1, 1, 40)
+
+	session, err := service.StartBlindSession(ctx, project.ID, nil, map[int]int{1: 2})
+	if err != nil {
+		t.Fatalf("Failed to start blind session: %v", err)
+	}
+	if _, err := service.UpdateChannelValue(ctx, session.ID, fixture.ID, 0, 220); err != nil {
+		t.Fatalf("Failed to update channel value: %v", err)
+	}
+
+	if got := service.dmxService.GetChannelValue(1, 1); got != 40 {
+		t.Errorf("Live channel 1 = %d, want 40", got)
+	}
+	if got := service.dmxService.GetUniverse(2)[0]; got != 220 {
+		t.Errorf("Preview universe channel 1 = %d, want 220", got)
+	}
+	output := service.GetDMXOutput(session.ID)
+	if len(output) != 1 || output[0].Channels[0] != 220 {
+		t.Errorf("Session output = %+v, want channel 1 at 220", output)
+	}
+
+	edits := service.GetEdits(session.ID)
+	if len(edits) != 1 || edits[0].FixtureID != fixture.ID || edits[0].Offset != 0 || edits[0].Value != 220 {
+		t.Errorf("Edits = %+v, want the fixture's first channel at 220", edits)
+	}
+
+	if _, err := service.CancelSession(ctx, session.ID); err != nil {
+		t.Fatalf("Failed to cancel session: %v", err)
+	}
+	if got := service.dmxService.GetUniverse(2)[0]; got != 0 {
+		t.Errorf("Preview universe channel 1 after cancel = %d, want 0", got)
+	}
+	if got := service.dmxService.GetChannelValue(1, 1); got != 40 {
+		t.Errorf("Live channel 1 after cancel = %d, want 40", got)
+	}
+}
+
+// TestVisualizerSession_UsesShadowUniverses tests that a visualizer
+// session's edits reach only its shadow universe.
+func TestVisualizerSession_UsesShadowUniverses(t *testing.T) {
+	testDB, service, cleanup := setupPreviewTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	project, fixture := createTestProjectWithFixture(t, testDB)
+	service.dmxService.SetChannelValue(1, 1, 40)
+
+	if _, err := service.StartVisualizerSession(ctx, project.ID, nil, map[int]int{1: 2}, "127.0.0.1"); err == nil {
+		t.Error("Expected a live universe rejected as a shadow universe")
+	}
+
+	session, err := service.StartVisualizerSession(ctx, project.ID, nil, map[int]int{1: 101}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Failed to start visualizer session: %v", err)
+	}
+	if _, err := service.UpdateChannelValue(ctx, session.ID, fixture.ID, 0, 220); err != nil {
+		t.Fatalf("Failed to update channel value: %v", err)
+	}
+
+	if got := service.dmxService.GetChannelValue(1, 1); got != 40 {
+		t.Errorf("Live channel 1 = %d, want 40", got)
+	}
+	if got := service.dmxService.GetUniverse(101)[0]; got != 220 {
+		t.Errorf("Shadow universe channel 1 = %d, want 220", got)
+	}
+
+	if _, err := service.CancelSession(ctx, session.ID); err != nil {
+		t.Fatalf("Failed to cancel session: %v", err)
+	}
+	if got := service.dmxService.GetUniverse(101)[0]; got != 0 {
+		t.Errorf("Shadow universe channel 1 after cancel = %d, want 0", got)
+	}
+}
@@ -18,6 +18,22 @@ const (
 	TopicWiFiStatus              Topic = "WIFI_STATUS_UPDATED"
 	TopicWiFiModeChanged         Topic = "WIFI_MODE_CHANGED"
 	TopicOFLImportProgress       Topic = "OFL_IMPORT_PROGRESS"
+	TopicShowTimer               Topic = "SHOW_TIMER_UPDATED"
+	TopicTempo                   Topic = "TEMPO_UPDATED"
+	TopicStandby                 Topic = "STANDBY_STATUS_UPDATED"
+	TopicMasterLevel             Topic = "MASTER_LEVEL_CHANGED"
+	TopicBlackout                Topic = "BLACKOUT_STATUS_CHANGED"
+	TopicTimecode                Topic = "TIMECODE_STATUS_CHANGED"
+	TopicAudioInput              Topic = "AUDIO_INPUT_STATUS_CHANGED"
+	TopicSubmasterLevel          Topic = "SUBMASTER_LEVEL_CHANGED"
+	TopicUndoStack               Topic = "UNDO_STACK_CHANGED"
+	TopicPresence                Topic = "PRESENCE_CHANGED"
+	TopicSceneBoardState         Topic = "SCENE_BOARD_STATE_CHANGED"
+	TopicScheduleFired           Topic = "SCHEDULE_FIRED"
+	TopicLayoutChanged           Topic = "LAYOUT_CHANGED"
+	TopicHouseLights             Topic = "HOUSE_LIGHTS_CHANGED"
+	TopicSceneUpdated            Topic = "SCENE_UPDATED"
+	TopicProjectEntityChanged    Topic = "PROJECT_ENTITY_CHANGED"
 )
 
 // Subscriber represents a subscription channel.
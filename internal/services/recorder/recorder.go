@@ -0,0 +1,315 @@
+// Package recorder captures incoming GraphQL operations so issues seen in
+// the field can be replayed against a test instance.
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// RecordingFormat identifies the operation recording artifact format.
+const RecordingFormat = "lacylights-operation-recording/1"
+
+// maxRecordedOperations bounds memory use; later operations are counted as
+// dropped once a recording is full.
+const maxRecordedOperations = 5000
+
+// redacted replaces secret values in recordings.
+const redacted = "[REDACTED]"
+
+// secretName matches argument and variable names whose values must not be recorded.
+var secretName = regexp.MustCompile(`(?i)(password|passphrase|secret|token|apikey|api_key|psk|authorization|credential)`)
+
+// secretArgument matches inline string arguments with secret names in query text.
+var secretArgument = regexp.MustCompile(`(?i)(\b\w*(?:password|passphrase|secret|token|apikey|api_key|psk|authorization|credential)\w*\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// controlFields are the recorder's own fields; operations that only touch
+// them are not recorded.
+var controlFields = map[string]bool{
+	"startOperationRecording":  true,
+	"stopOperationRecording":   true,
+	"operationRecordingStatus": true,
+}
+
+// Operation is a single recorded GraphQL operation.
+type Operation struct {
+	Seq           int                    `json:"seq"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Type          string                 `json:"type"` // query, mutation, or subscription
+	OperationName string                 `json:"operationName,omitempty"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Recording is a time-ordered, serializable list of operations.
+type Recording struct {
+	Format     string      `json:"format"`
+	StartedAt  time.Time   `json:"startedAt"`
+	EndedAt    time.Time   `json:"endedAt"`
+	Dropped    int         `json:"dropped"` // Operations not kept because the recording was full
+	Operations []Operation `json:"operations"`
+}
+
+// ToJSON serializes the recording for download.
+func (r *Recording) ToJSON() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ParseRecording decodes a recording produced by Recording.ToJSON.
+func ParseRecording(content []byte) (*Recording, error) {
+	var r Recording
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("invalid operation recording: %w", err)
+	}
+	if r.Format != RecordingFormat {
+		return nil, fmt.Errorf("unsupported operation recording format: %q", r.Format)
+	}
+	return &r, nil
+}
+
+// Status describes the recorder state.
+type Status struct {
+	IsRecording    bool
+	StartedAt      *time.Time
+	OperationCount int
+	Dropped        int
+}
+
+// Service records GraphQL operations while enabled. It is a gqlgen handler
+// extension; register it with the server's Use method.
+type Service struct {
+	mu         sync.Mutex
+	recording  bool
+	startedAt  time.Time
+	operations []Operation
+	dropped    int
+
+	now func() time.Time
+}
+
+var (
+	_ graphql.HandlerExtension     = (*Service)(nil)
+	_ graphql.OperationInterceptor = (*Service)(nil)
+)
+
+// NewService creates a recorder that is initially off.
+func NewService() *Service {
+	return &Service{now: time.Now}
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (s *Service) ExtensionName() string {
+	return "OperationRecorder"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (s *Service) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation records the operation before executing it.
+func (s *Service) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	if s.IsRecording() && graphql.HasOperationContext(ctx) {
+		if opCtx := graphql.GetOperationContext(ctx); opCtx.Operation != nil && !isControlOperation(opCtx.Operation) {
+			s.record(Operation{
+				Type:          string(opCtx.Operation.Operation),
+				OperationName: opCtx.Operation.Name,
+				Query:         RedactQuery(opCtx.RawQuery),
+				Variables:     redactOperationVariables(opCtx.Operation, opCtx.Variables),
+			})
+		}
+	}
+	return next(ctx)
+}
+
+// Start begins a new recording, discarding any operations not yet collected.
+func (s *Service) Start() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recording = true
+	s.startedAt = s.now()
+	s.operations = nil
+	s.dropped = 0
+	return s.statusLocked()
+}
+
+// Stop ends the current recording and returns it.
+func (s *Service) Stop() (*Recording, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.recording {
+		return nil, fmt.Errorf("operation recording is not active")
+	}
+	s.recording = false
+
+	operations := s.operations
+	if operations == nil {
+		operations = []Operation{}
+	}
+	recording := &Recording{
+		Format:     RecordingFormat,
+		StartedAt:  s.startedAt,
+		EndedAt:    s.now(),
+		Dropped:    s.dropped,
+		Operations: operations,
+	}
+	s.operations = nil
+	s.dropped = 0
+	return recording, nil
+}
+
+// IsRecording reports whether operations are being recorded.
+func (s *Service) IsRecording() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recording
+}
+
+// Status returns the current recorder state.
+func (s *Service) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusLocked()
+}
+
+func (s *Service) statusLocked() *Status {
+	status := &Status{
+		IsRecording:    s.recording,
+		OperationCount: len(s.operations),
+		Dropped:        s.dropped,
+	}
+	if s.recording {
+		startedAt := s.startedAt
+		status.StartedAt = &startedAt
+	}
+	return status
+}
+
+func (s *Service) record(op Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.recording {
+		return
+	}
+	if len(s.operations) >= maxRecordedOperations {
+		s.dropped++
+		return
+	}
+	op.Seq = len(s.operations) + 1
+	op.Timestamp = s.now()
+	s.operations = append(s.operations, op)
+}
+
+// isControlOperation reports whether every root field belongs to the recorder.
+func isControlOperation(op *ast.OperationDefinition) bool {
+	if len(op.SelectionSet) == 0 {
+		return false
+	}
+	for _, selection := range op.SelectionSet {
+		field, ok := selection.(*ast.Field)
+		if !ok || !controlFields[field.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// redactOperationVariables masks secret-named variables and variables passed
+// to secret-named arguments, e.g. connectWiFi(password: $pw).
+func redactOperationVariables(op *ast.OperationDefinition, vars map[string]interface{}) map[string]interface{} {
+	result := RedactVariables(vars)
+	for name := range secretVariables(op.SelectionSet) {
+		if _, ok := result[name]; ok {
+			result[name] = redacted
+		}
+	}
+	return result
+}
+
+// secretVariables returns the variables bound to secret-named arguments or input fields.
+func secretVariables(selections ast.SelectionSet) map[string]bool {
+	names := make(map[string]bool)
+	var visitValue func(name string, value *ast.Value)
+	visitValue = func(name string, value *ast.Value) {
+		if value == nil {
+			return
+		}
+		if value.Kind == ast.Variable && secretName.MatchString(name) {
+			names[value.Raw] = true
+		}
+		for _, child := range value.Children {
+			childName := name
+			if child.Name != "" {
+				childName = child.Name
+			}
+			visitValue(childName, child.Value)
+		}
+	}
+	var visit func(ast.SelectionSet)
+	visit = func(set ast.SelectionSet) {
+		for _, selection := range set {
+			switch sel := selection.(type) {
+			case *ast.Field:
+				for _, arg := range sel.Arguments {
+					visitValue(arg.Name, arg.Value)
+				}
+				visit(sel.SelectionSet)
+			case *ast.InlineFragment:
+				visit(sel.SelectionSet)
+			}
+		}
+	}
+	visit(selections)
+	return names
+}
+
+// RedactQuery masks inline string arguments with secret names, such as
+// connectWiFi(password: "...").
+func RedactQuery(query string) string {
+	return secretArgument.ReplaceAllString(query, `${1}"`+redacted+`"`)
+}
+
+// RedactVariables returns a copy of vars with secret-named values masked at any depth.
+func RedactVariables(vars map[string]interface{}) map[string]interface{} {
+	if vars == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(vars))
+	for key, value := range vars {
+		if secretName.MatchString(key) {
+			result[key] = redacted
+			continue
+		}
+		result[key] = redactValue(value)
+	}
+	return result
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return RedactVariables(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,142 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestRedactQuery(t *testing.T) {
+	query := `mutation { connectWiFi(ssid: "Venue", password: "hunter\"2") { success } }`
+	got := RedactQuery(query)
+	if strings.Contains(got, "hunter") {
+		t.Errorf("Password leaked: %s", got)
+	}
+	if !strings.Contains(got, `ssid: "Venue"`) || !strings.Contains(got, `password: "[REDACTED]"`) {
+		t.Errorf("Unexpected redaction: %s", got)
+	}
+}
+
+func TestRedactOperationVariables(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `mutation Join($net: String!, $pw: String, $opts: Options) {
+		connectWiFi(ssid: $net, password: $pw) { success }
+		configure(input: { apiKey: $opts }) { ok }
+	}`})
+	if err != nil {
+		t.Fatalf("ParseQuery() error: %v", err)
+	}
+
+	vars := map[string]interface{}{
+		"net":  "Venue",
+		"pw":   "hunter2",
+		"opts": "key",
+		"nested": map[string]interface{}{
+			"authToken": "abc",
+			"items":     []interface{}{map[string]interface{}{"secret": "x", "name": "ok"}},
+		},
+	}
+	got := redactOperationVariables(doc.Operations[0], vars)
+
+	if got["net"] != "Venue" {
+		t.Errorf("Non-secret variable changed: %v", got["net"])
+	}
+	if got["pw"] != redacted || got["opts"] != redacted {
+		t.Errorf("Variables bound to secret arguments not redacted: %v", got)
+	}
+	nested := got["nested"].(map[string]interface{})
+	if nested["authToken"] != redacted {
+		t.Errorf("Nested secret not redacted: %v", nested)
+	}
+	item := nested["items"].([]interface{})[0].(map[string]interface{})
+	if item["secret"] != redacted || item["name"] != "ok" {
+		t.Errorf("Secret inside list not redacted: %v", item)
+	}
+	if vars["pw"] != "hunter2" {
+		t.Error("Redaction must not modify the original variables")
+	}
+}
+
+func TestStartStopAndLimit(t *testing.T) {
+	s := NewService()
+
+	s.record(Operation{Type: "query", Query: "{ a }"})
+	if _, err := s.Stop(); err == nil {
+		t.Error("Expected error stopping an inactive recorder")
+	}
+
+	s.Start()
+	for i := 0; i < maxRecordedOperations+3; i++ {
+		s.record(Operation{Type: "query", Query: "{ a }"})
+	}
+	status := s.Status()
+	if !status.IsRecording || status.OperationCount != maxRecordedOperations || status.Dropped != 3 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	recording, err := s.Stop()
+	if err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	if recording.Operations[0].Seq != 1 || recording.Dropped != 3 {
+		t.Errorf("Unexpected recording: seq %d, dropped %d", recording.Operations[0].Seq, recording.Dropped)
+	}
+
+	content, _ := recording.ToJSON()
+	parsed, err := ParseRecording([]byte(content))
+	if err != nil || len(parsed.Operations) != maxRecordedOperations {
+		t.Errorf("Round trip failed: %v", err)
+	}
+	if _, err := ParseRecording([]byte(`{"format":"other"}`)); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body)
+		if strings.Contains(body["query"].(string), "broken") {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"boom"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	recording := &Recording{
+		Format: RecordingFormat,
+		Operations: []Operation{
+			{Seq: 1, Timestamp: start, Type: "mutation", Query: "mutation { a }", Variables: map[string]interface{}{"x": 1.0}},
+			{Seq: 2, Timestamp: start, Type: "subscription", Query: "subscription { b }"},
+			{Seq: 3, Timestamp: start.Add(20 * time.Millisecond), Type: "query", Query: "{ broken }"},
+		},
+	}
+
+	began := time.Now()
+	results, err := Replay(context.Background(), server.URL, recording, ReplayOptions{PreserveTiming: true})
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if time.Since(began) < 20*time.Millisecond {
+		t.Error("Expected replay to preserve the gap between operations")
+	}
+	if len(received) != 2 || len(results) != 2 {
+		t.Fatalf("Expected subscriptions to be skipped, got %d requests", len(received))
+	}
+	if received[0]["variables"].(map[string]interface{})["x"] != 1.0 {
+		t.Errorf("Variables not replayed: %v", received[0])
+	}
+	if len(results[0].Errors) != 0 || len(results[1].Errors) != 1 || results[1].Seq != 3 {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
@@ -0,0 +1,97 @@
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ReplayOptions configures a replay.
+type ReplayOptions struct {
+	// PreserveTiming waits between operations as long as the original
+	// client did; otherwise operations are sent back to back.
+	PreserveTiming bool
+	// Client sends the requests (http.DefaultClient when nil).
+	Client *http.Client
+}
+
+// ReplayResult is the outcome of replaying one operation.
+type ReplayResult struct {
+	Seq    int
+	Errors []string // GraphQL errors returned by the server
+}
+
+// Replay sends the recorded queries and mutations to a GraphQL endpoint in
+// order. Subscriptions are skipped since they need a WebSocket client.
+// Redacted values are sent as recorded, so operations that depended on
+// secrets will fail on the test instance.
+func Replay(ctx context.Context, endpoint string, recording *Recording, opts ReplayOptions) ([]ReplayResult, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var results []ReplayResult
+	var previous time.Time
+	for _, op := range recording.Operations {
+		if op.Type == "subscription" {
+			continue
+		}
+		if opts.PreserveTiming && !previous.IsZero() {
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			case <-time.After(op.Timestamp.Sub(previous)):
+			}
+		}
+		previous = op.Timestamp
+
+		errs, err := send(ctx, client, endpoint, op)
+		if err != nil {
+			return results, fmt.Errorf("operation %d: %w", op.Seq, err)
+		}
+		results = append(results, ReplayResult{Seq: op.Seq, Errors: errs})
+	}
+	return results, nil
+}
+
+func send(ctx context.Context, client *http.Client, endpoint string, op Operation) ([]string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":         op.Query,
+		"operationName": op.OperationName,
+		"variables":     op.Variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response (HTTP %d): %w", resp.StatusCode, err)
+	}
+
+	errs := make([]string, len(response.Errors))
+	for i, e := range response.Errors {
+		errs[i] = e.Message
+	}
+	return errs, nil
+}
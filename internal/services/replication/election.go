@@ -0,0 +1,93 @@
+package replication
+
+import (
+	"errors"
+	"time"
+)
+
+// election decides from the heartbeats an instance receives whether it
+// drives output. A primary drives output unless a backup that took over is
+// connected. A backup takes over once the primary has been silent for the
+// failover timeout, and keeps output until it fails back.
+type election struct {
+	role    Role
+	timeout time.Duration
+
+	active        bool
+	connected     bool
+	peerID        string
+	peerActive    bool
+	lastHeartbeat time.Time
+	// When a backup started waiting for the primary, so one that has not
+	// heard from it yet still takes over
+	waitingSince time.Time
+	takenOverAt  time.Time
+}
+
+func newElection(role Role, timeout time.Duration, now time.Time) election {
+	return election{
+		role:         role,
+		timeout:      timeout,
+		active:       role != RoleBackup,
+		waitingSince: now,
+	}
+}
+
+// heartbeat records a heartbeat from the peer.
+func (e *election) heartbeat(peerID string, peerActive bool, now time.Time) {
+	e.connected = true
+	e.peerID = peerID
+	e.peerActive = peerActive
+	e.lastHeartbeat = now
+	e.decide(now)
+}
+
+// disconnected records that the peer's connection closed.
+func (e *election) disconnected(now time.Time) {
+	e.connected = false
+	e.peerActive = false
+	e.decide(now)
+}
+
+// decide applies the election rules at now.
+func (e *election) decide(now time.Time) {
+	switch e.role {
+	case RolePrimary:
+		e.active = !(e.connected && e.peerActive)
+	case RoleBackup:
+		if e.active {
+			return
+		}
+		last := e.lastHeartbeat
+		if last.Before(e.waitingSince) {
+			last = e.waitingSince
+		}
+		if now.Sub(last) >= e.timeout {
+			e.active = true
+			e.takenOverAt = now
+		}
+	}
+}
+
+// peerAlive reports whether the peer is connected and heard from within the
+// failover timeout.
+func (e *election) peerAlive(now time.Time) bool {
+	return e.connected && now.Sub(e.lastHeartbeat) < e.timeout
+}
+
+// failback hands output from a backup that took over back to its primary.
+func (e *election) failback(now time.Time) error {
+	if e.role != RoleBackup {
+		return errors.New("only a backup can fail back")
+	}
+	if !e.active {
+		return errors.New("the backup does not hold output")
+	}
+	if !e.peerAlive(now) {
+		return errors.New("the primary is not connected")
+	}
+	e.active = false
+	e.waitingSince = now
+	e.takenOverAt = time.Time{}
+	return nil
+}
@@ -0,0 +1,722 @@
+// Package replication runs a second LacyLights instance as a tracking
+// backup. The primary streams its live state, such as the active cue of each
+// cue list, the masters, the programmer, and settings, to the backup over a
+// websocket, and the two exchange heartbeats. The backup applies the state as
+// it arrives with its Art-Net output passive, so it holds the same look, and
+// takes over output when the primary's heartbeats stop.
+//
+// A backup that took over keeps output until an operator fails back: while
+// it reports itself active, a primary that comes back keeps its own output
+// passive rather than snatching output back with a stale state. Both
+// instances must run the same show, since state refers to cue lists by ID.
+//
+// State is a set of keyed JSON values grouped by kind, as in the playback
+// state journal. The primary sends all of it when the backup connects, then
+// each change.
+package replication
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lucsky/cuid"
+)
+
+// Path is the HTTP path of a primary's replication endpoint.
+const Path = "/replication"
+
+// TokenHeader carries the shared token a backup presents to its primary.
+const TokenHeader = "X-Replication-Token"
+
+const (
+	// DefaultHeartbeatInterval is how often instances exchange heartbeats.
+	DefaultHeartbeatInterval = time.Second
+	// DefaultFailoverTimeout is how long a backup waits for a silent primary
+	// before taking over.
+	DefaultFailoverTimeout = 3 * time.Second
+)
+
+// Kinds of replicated state besides the playback journal's.
+const (
+	// KindProgrammer holds the programmer under the key "values".
+	KindProgrammer = "programmer"
+	// KindSetting holds each setting's value by key.
+	KindSetting = "setting"
+)
+
+const (
+	// writeWait bounds how long a message may take to reach the peer.
+	writeWait = 5 * time.Second
+	// maxMessageSize bounds a message, snapshots included.
+	maxMessageSize = 16 << 20
+	// sendBuffer is the number of messages queued for the peer before its
+	// connection is dropped and it starts over from a snapshot.
+	sendBuffer = 1024
+)
+
+// Role is an instance's part in replication.
+type Role string
+
+const (
+	// RoleStandalone does not replicate.
+	RoleStandalone Role = "STANDALONE"
+	// RolePrimary streams its state to a backup.
+	RolePrimary Role = "PRIMARY"
+	// RoleBackup tracks a primary and takes over its output if it fails.
+	RoleBackup Role = "BACKUP"
+)
+
+// ParseRole converts a configuration string to a Role. An empty string is
+// standalone.
+func ParseRole(value string) (Role, error) {
+	switch role := Role(strings.ToUpper(strings.TrimSpace(value))); role {
+	case "":
+		return RoleStandalone, nil
+	case RoleStandalone, RolePrimary, RoleBackup:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid replication role %q (want standalone, primary, or backup)", value)
+	}
+}
+
+// Config configures replication.
+type Config struct {
+	Role Role
+	// Replication endpoint of the primary, for a backup, such as
+	// ws://10.0.0.2:4000/replication
+	PrimaryURL string
+	// Shared token the backup must present (optional)
+	Token             string
+	HeartbeatInterval time.Duration
+	FailoverTimeout   time.Duration
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if _, err := ParseRole(string(c.Role)); err != nil {
+		return err
+	}
+	if c.Role != RoleBackup {
+		return nil
+	}
+	if c.PrimaryURL == "" {
+		return errors.New("a backup needs the primary's replication URL")
+	}
+	u, err := url.Parse(c.PrimaryURL)
+	if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+		return fmt.Errorf("invalid primary replication URL %q (want ws://host:port%s)", c.PrimaryURL, Path)
+	}
+	return nil
+}
+
+// Output is the DMX output an instance drives while active. *dmx.Service
+// implements it.
+type Output interface {
+	SetPassive(passive bool)
+}
+
+// Tracker applies the primary's state on a backup.
+type Tracker interface {
+	// Track applies the value of kind/key.
+	Track(kind, key string, value json.RawMessage) error
+	// Untrack removes kind/key.
+	Untrack(kind, key string) error
+	// Reset drops the state applied so far, before the backup starts over
+	// from a snapshot.
+	Reset()
+}
+
+// Status is the replication state of an instance.
+type Status struct {
+	Role           Role
+	InstanceID     string
+	OutputActive   bool
+	PeerConnected  bool
+	PeerInstanceID string
+	LastHeartbeat  *time.Time
+	TakenOverAt    *time.Time
+	// State entries published by a primary, or tracked by a backup
+	StateEntries int
+}
+
+// message is one websocket message between the instances.
+type message struct {
+	Type       string                                `json:"type"`
+	InstanceID string                                `json:"instanceId,omitempty"`
+	Active     bool                                  `json:"active,omitempty"`
+	Kind       string                                `json:"kind,omitempty"`
+	Key        string                                `json:"key,omitempty"`
+	Value      json.RawMessage                       `json:"value,omitempty"`
+	State      map[string]map[string]json.RawMessage `json:"state,omitempty"`
+}
+
+// Message types.
+const (
+	messageHeartbeat = "heartbeat"
+	messageSnapshot  = "snapshot"
+	messagePut       = "put"
+	messageDelete    = "delete"
+	// Asks the primary for a snapshot
+	messageResync = "resync"
+)
+
+// Service replicates state between a primary and a tracking backup. A new
+// service is standalone until started.
+type Service struct {
+	output     Output
+	instanceID string
+	upgrader   websocket.Upgrader
+
+	// Serializes output changes; never held with mu
+	applyMu      sync.Mutex
+	outputActive bool
+
+	mu       sync.Mutex
+	config   Config
+	started  bool
+	election election
+	// State published to a backup, and on a backup the state tracked from
+	// the primary: kind -> key -> value
+	state    map[string]map[string]json.RawMessage
+	tracked  map[string]map[string]json.RawMessage
+	peer     *peer
+	tracker  Tracker
+	onActive func(active bool)
+	// Whether a primary must be configured with a token to accept backups
+	tokenRequired func() bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService creates a standalone replication service for an output.
+func NewService(output Output) *Service {
+	return &Service{
+		output:     output,
+		instanceID: cuid.New(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+		},
+		outputActive: true,
+		config:       Config{Role: RoleStandalone},
+		election:     newElection(RoleStandalone, DefaultFailoverTimeout, time.Now()),
+		state:        make(map[string]map[string]json.RawMessage),
+		tracked:      make(map[string]map[string]json.RawMessage),
+		stop:         make(chan struct{}),
+	}
+}
+
+// SetTracker sets what applies the primary's state on a backup.
+func (s *Service) SetTracker(tracker Tracker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracker = tracker
+}
+
+// SetActiveCallback sets a function called when this instance starts or
+// stops driving output (optional).
+func (s *Service) SetActiveCallback(callback func(active bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onActive = callback
+}
+
+// SetTokenRequired sets a function reporting whether a primary refuses
+// backups while it has no token, as when the API needs signing in
+// (optional).
+func (s *Service) SetTokenRequired(required func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenRequired = required
+}
+
+// Start begins replicating in the configured role. A backup's output goes
+// passive until it takes over. Starting a standalone configuration does
+// nothing.
+func (s *Service) Start(cfg Config) error {
+	if cfg.Role == "" {
+		cfg.Role = RoleStandalone
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if cfg.FailoverTimeout <= 0 {
+		cfg.FailoverTimeout = DefaultFailoverTimeout
+	}
+	if cfg.FailoverTimeout <= cfg.HeartbeatInterval {
+		return fmt.Errorf("failover timeout (%v) must be longer than the heartbeat interval (%v)", cfg.FailoverTimeout, cfg.HeartbeatInterval)
+	}
+	if cfg.Role == RoleStandalone {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return errors.New("replication is already started")
+	}
+	s.started = true
+	s.config = cfg
+	s.election = newElection(cfg.Role, cfg.FailoverTimeout, time.Now())
+	s.mu.Unlock()
+
+	s.syncOutput()
+	s.wg.Add(1)
+	go s.heartbeatLoop()
+	if cfg.Role == RoleBackup {
+		s.wg.Add(1)
+		go s.connectLoop()
+	}
+	return nil
+}
+
+// Put records value as the current state of kind/key and sends it to a
+// connected backup. Writing the value already held is a no-op.
+func (s *Service) Put(kind, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode replicated value: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.state[kind][key]; ok && bytes.Equal(current, data) {
+		return nil
+	}
+	if s.state[kind] == nil {
+		s.state[kind] = make(map[string]json.RawMessage)
+	}
+	s.state[kind][key] = data
+	s.sendStateLocked(message{Type: messagePut, Kind: kind, Key: key, Value: data})
+	return nil
+}
+
+// Delete removes kind/key from the state.
+func (s *Service) Delete(kind, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state[kind][key]; !ok {
+		return nil
+	}
+	delete(s.state[kind], key)
+	if len(s.state[kind]) == 0 {
+		delete(s.state, kind)
+	}
+	s.sendStateLocked(message{Type: messageDelete, Kind: kind, Key: key})
+	return nil
+}
+
+// sendStateLocked sends a state change to a connected backup.
+func (s *Service) sendStateLocked(m message) {
+	if s.config.Role == RolePrimary && s.peer != nil {
+		s.peer.send(m)
+	}
+}
+
+// Status returns the replication state.
+func (s *Service) Status() *Status {
+	s.applyMu.Lock()
+	active := s.outputActive
+	s.applyMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := &Status{
+		Role:          s.config.Role,
+		InstanceID:    s.instanceID,
+		OutputActive:  active,
+		PeerConnected: s.peer != nil && s.election.connected,
+	}
+	if status.PeerConnected {
+		status.PeerInstanceID = s.election.peerID
+	}
+	if !s.election.lastHeartbeat.IsZero() {
+		last := s.election.lastHeartbeat
+		status.LastHeartbeat = &last
+	}
+	if !s.election.takenOverAt.IsZero() {
+		takenOver := s.election.takenOverAt
+		status.TakenOverAt = &takenOver
+	}
+	entries := s.state
+	if s.config.Role == RoleBackup {
+		entries = s.tracked
+	}
+	for _, values := range entries {
+		status.StateEntries += len(values)
+	}
+	return status
+}
+
+// Failback hands output from a backup that took over back to its
+// connected primary, and starts tracking it again from a fresh snapshot.
+func (s *Service) Failback() (*Status, error) {
+	s.mu.Lock()
+	if err := s.election.failback(time.Now()); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.tracked = make(map[string]map[string]json.RawMessage)
+	tracker := s.tracker
+	peer := s.peer
+	s.mu.Unlock()
+
+	s.syncOutput()
+	if tracker != nil {
+		tracker.Reset()
+	}
+	if peer != nil {
+		peer.send(message{Type: messageResync})
+	}
+	log.Info("🔁 Output handed back to the primary")
+	return s.Status(), nil
+}
+
+// ServeHTTP accepts a backup's connection on a primary.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	role, token, busy := s.config.Role, s.config.Token, s.peer != nil
+	tokenRequired := s.tokenRequired
+	s.mu.Unlock()
+
+	if role != RolePrimary {
+		http.Error(w, "this instance is not a replication primary", http.StatusNotFound)
+		return
+	}
+	if token == "" && tokenRequired != nil && tokenRequired() {
+		http.Error(w, "replication needs a token while authentication is enabled", http.StatusUnauthorized)
+		return
+	}
+	if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(token)) != 1 {
+		http.Error(w, "invalid replication token", http.StatusUnauthorized)
+		return
+	}
+	if busy {
+		http.Error(w, "a backup is already connected", http.StatusConflict)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		return
+	}
+	s.servePeer(newPeer(conn))
+}
+
+// connectLoop keeps a backup connected to its primary.
+func (s *Service) connectLoop() {
+	defer s.wg.Done()
+
+	s.mu.Lock()
+	cfg := s.config
+	s.mu.Unlock()
+	header := http.Header{}
+	if cfg.Token != "" {
+		header.Set(TokenHeader, cfg.Token)
+	}
+	dialer := websocket.Dialer{HandshakeTimeout: cfg.FailoverTimeout}
+
+	failing := false
+	for {
+		conn, _, err := dialer.Dial(cfg.PrimaryURL, header)
+		if err == nil {
+			failing = false
+			log.Info("🔁 Connected to the primary", "url", cfg.PrimaryURL)
+			s.servePeer(newPeer(conn))
+			log.Warn("lost the connection to the primary")
+		} else if !failing {
+			failing = true
+			log.Warn("cannot reach the primary", "url", cfg.PrimaryURL, "error", err)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(cfg.HeartbeatInterval):
+		}
+	}
+}
+
+// servePeer runs a connection to the other instance until it closes.
+func (s *Service) servePeer(p *peer) {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+		s.mu.Unlock()
+		p.close()
+		return
+	default:
+	}
+	if s.peer != nil {
+		s.mu.Unlock()
+		p.close()
+		return
+	}
+	s.peer = p
+	p.send(s.heartbeatLocked())
+	if s.config.Role == RolePrimary {
+		p.send(s.snapshotLocked())
+	}
+	s.mu.Unlock()
+
+	go p.writeLoop()
+	s.readLoop(p)
+
+	p.close()
+	s.mu.Lock()
+	if s.peer == p {
+		s.peer = nil
+	}
+	s.election.disconnected(time.Now())
+	s.mu.Unlock()
+	s.syncOutput()
+}
+
+// readLoop handles the peer's messages until its connection closes.
+func (s *Service) readLoop(p *peer) {
+	s.mu.Lock()
+	timeout := s.config.FailoverTimeout
+	s.mu.Unlock()
+
+	p.conn.SetReadLimit(maxMessageSize)
+	for {
+		// A peer that stops sending heartbeats is as good as gone
+		_ = p.conn.SetReadDeadline(time.Now().Add(timeout))
+		var m message
+		if err := p.conn.ReadJSON(&m); err != nil {
+			return
+		}
+		s.handle(p, m)
+	}
+}
+
+// handle applies one message from the peer.
+func (s *Service) handle(p *peer, m message) {
+	s.mu.Lock()
+	role := s.config.Role
+	switch m.Type {
+	case messageHeartbeat:
+		s.election.heartbeat(m.InstanceID, m.Active, time.Now())
+		s.mu.Unlock()
+		s.syncOutput()
+		return
+	case messageResync:
+		if role == RolePrimary {
+			p.send(s.snapshotLocked())
+		}
+		s.mu.Unlock()
+		return
+	}
+	// A backup holding output has stopped tracking
+	if role != RoleBackup || s.election.active {
+		s.mu.Unlock()
+		return
+	}
+	tracker := s.tracker
+
+	type change struct {
+		kind, key string
+		value     json.RawMessage // nil removes
+	}
+	var changes []change
+	switch m.Type {
+	case messageSnapshot:
+		for kind, values := range s.tracked {
+			for key := range values {
+				if _, ok := m.State[kind][key]; !ok {
+					changes = append(changes, change{kind: kind, key: key})
+				}
+			}
+		}
+		s.tracked = make(map[string]map[string]json.RawMessage)
+		for kind, values := range m.State {
+			s.tracked[kind] = make(map[string]json.RawMessage, len(values))
+			for key, value := range values {
+				s.tracked[kind][key] = value
+				changes = append(changes, change{kind: kind, key: key, value: value})
+			}
+		}
+	case messagePut:
+		if s.tracked[m.Kind] == nil {
+			s.tracked[m.Kind] = make(map[string]json.RawMessage)
+		}
+		s.tracked[m.Kind][m.Key] = m.Value
+		changes = append(changes, change{kind: m.Kind, key: m.Key, value: m.Value})
+	case messageDelete:
+		delete(s.tracked[m.Kind], m.Key)
+		changes = append(changes, change{kind: m.Kind, key: m.Key})
+	}
+	s.mu.Unlock()
+
+	if tracker == nil {
+		return
+	}
+	for _, c := range changes {
+		var err error
+		if c.value != nil {
+			err = tracker.Track(c.kind, c.key, c.value)
+		} else {
+			err = tracker.Untrack(c.kind, c.key)
+		}
+		if err != nil {
+			log.Warn("cannot track state", "kind", c.kind, "key", c.key, "error", err)
+		}
+	}
+}
+
+// heartbeatLoop sends heartbeats and re-runs the election until stopped.
+func (s *Service) heartbeatLoop() {
+	defer s.wg.Done()
+
+	s.mu.Lock()
+	interval := s.config.HeartbeatInterval
+	s.mu.Unlock()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.election.decide(time.Now())
+			if s.peer != nil {
+				s.peer.send(s.heartbeatLocked())
+			}
+			s.mu.Unlock()
+			s.syncOutput()
+		}
+	}
+}
+
+// syncOutput brings the output in line with the election.
+func (s *Service) syncOutput() {
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	s.mu.Lock()
+	active := s.election.active
+	role := s.config.Role
+	callback := s.onActive
+	s.mu.Unlock()
+	if active == s.outputActive {
+		return
+	}
+	s.outputActive = active
+
+	s.output.SetPassive(!active)
+	switch {
+	case active && role == RoleBackup:
+		log.Warn("⚠️ The primary is silent; this backup has taken over output")
+	case active:
+		log.Info("🔁 Output resumed")
+	case role == RolePrimary:
+		log.Info("🔁 The backup holds output; this primary is passive")
+	default:
+		log.Info("🔁 Tracking the primary with output passive")
+	}
+	if callback != nil {
+		callback(active)
+	}
+}
+
+// heartbeatLocked builds this instance's heartbeat.
+func (s *Service) heartbeatLocked() message {
+	return message{Type: messageHeartbeat, InstanceID: s.instanceID, Active: s.election.active}
+}
+
+// snapshotLocked builds a message carrying the whole state.
+func (s *Service) snapshotLocked() message {
+	state := make(map[string]map[string]json.RawMessage, len(s.state))
+	for kind, values := range s.state {
+		state[kind] = make(map[string]json.RawMessage, len(values))
+		for key, value := range values {
+			state[kind][key] = value
+		}
+	}
+	return message{Type: messageSnapshot, State: state}
+}
+
+// Cleanup stops replicating and closes the connection to the peer.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	peer := s.peer
+	s.mu.Unlock()
+
+	if peer != nil {
+		peer.close()
+	}
+	s.wg.Wait()
+}
+
+// peer is the connection to the other instance. Messages are written by one
+// goroutine in the order they are sent.
+type peer struct {
+	conn      *websocket.Conn
+	outbox    chan message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPeer(conn *websocket.Conn) *peer {
+	return &peer{
+		conn:   conn,
+		outbox: make(chan message, sendBuffer),
+		done:   make(chan struct{}),
+	}
+}
+
+// send queues a message. A peer too slow to keep up is disconnected, and
+// starts over from a snapshot when it reconnects.
+func (p *peer) send(m message) {
+	select {
+	case <-p.done:
+	case p.outbox <- m:
+	default:
+		log.Warn("peer is not keeping up; disconnecting")
+		p.close()
+	}
+}
+
+// writeLoop writes queued messages until the connection closes.
+func (p *peer) writeLoop() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case m := <-p.outbox:
+			_ = p.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := p.conn.WriteJSON(m); err != nil {
+				p.close()
+				return
+			}
+		}
+	}
+}
+
+func (p *peer) close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		_ = p.conn.Close()
+	})
+}
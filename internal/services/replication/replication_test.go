@@ -0,0 +1,301 @@
+package replication
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOutput records whether output is passive.
+type fakeOutput struct {
+	mu      sync.Mutex
+	passive bool
+}
+
+func (o *fakeOutput) SetPassive(passive bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.passive = passive
+}
+
+func (o *fakeOutput) isPassive() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.passive
+}
+
+// fakeTracker records the state a backup tracks.
+type fakeTracker struct {
+	mu     sync.Mutex
+	state  map[string]string
+	resets int
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{state: make(map[string]string)}
+}
+
+func (t *fakeTracker) Track(kind, key string, value json.RawMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state[kind+"/"+key] = string(value)
+	return nil
+}
+
+func (t *fakeTracker) Untrack(kind, key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.state, kind+"/"+key)
+	return nil
+}
+
+func (t *fakeTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = make(map[string]string)
+	t.resets++
+}
+
+func (t *fakeTracker) get(key string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value, ok := t.state[key]
+	return value, ok
+}
+
+// primaryServer serves the replication endpoint of whichever primary is
+// current, so a test can restart the primary behind one address.
+type primaryServer struct {
+	mu      sync.Mutex
+	primary *Service
+}
+
+func (p *primaryServer) set(primary *Service) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.primary = primary
+}
+
+func (p *primaryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	primary := p.primary
+	p.mu.Unlock()
+	if primary == nil {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	primary.ServeHTTP(w, r)
+}
+
+// waitFor polls until condition holds or fails the test.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func testConfig(role Role, url string) Config {
+	return Config{
+		Role:              role,
+		PrimaryURL:        url,
+		Token:             "secret",
+		HeartbeatInterval: 20 * time.Millisecond,
+		FailoverTimeout:   150 * time.Millisecond,
+	}
+}
+
+func startPrimary(t *testing.T) (*Service, *fakeOutput) {
+	t.Helper()
+	output := &fakeOutput{}
+	primary := NewService(output)
+	if err := primary.Start(testConfig(RolePrimary, "")); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	return primary, output
+}
+
+func TestTrackingBackup(t *testing.T) {
+	server := &primaryServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + Path
+
+	primary, primaryOutput := startPrimary(t)
+	server.set(primary)
+	if err := primary.Put("active_cue", "list-1", map[string]int{"cueIndex": 2}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	backupOutput := &fakeOutput{}
+	backup := NewService(backupOutput)
+	tracker := newFakeTracker()
+	backup.SetTracker(tracker)
+	var activeChanges []bool
+	var activeMu sync.Mutex
+	backup.SetActiveCallback(func(active bool) {
+		activeMu.Lock()
+		defer activeMu.Unlock()
+		activeChanges = append(activeChanges, active)
+	})
+	if err := backup.Start(testConfig(RoleBackup, url)); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer backup.Cleanup()
+
+	// The backup tracks with its output passive, starting from a snapshot
+	if !backupOutput.isPassive() {
+		t.Error("Expected the backup's output to be passive")
+	}
+	waitFor(t, "the snapshot", func() bool {
+		value, ok := tracker.get("active_cue/list-1")
+		return ok && value == `{"cueIndex":2}`
+	})
+	_ = primary.Put(KindSetting, "channel_limits", "[]")
+	_ = primary.Delete("active_cue", "list-1")
+	waitFor(t, "changes", func() bool {
+		_, cue := tracker.get("active_cue/list-1")
+		_, setting := tracker.get(KindSetting + "/channel_limits")
+		return !cue && setting
+	})
+	waitFor(t, "heartbeats", func() bool {
+		return primary.Status().PeerConnected && backup.Status().PeerConnected
+	})
+	if status := backup.Status(); status.OutputActive || status.StateEntries != 1 || status.PeerInstanceID != primary.Status().InstanceID {
+		t.Errorf("Unexpected backup status: %+v", status)
+	}
+
+	// The backup takes over when the primary goes silent
+	server.set(nil)
+	primary.Cleanup()
+	waitFor(t, "takeover", func() bool { return backup.Status().OutputActive })
+	if backupOutput.isPassive() {
+		t.Error("Expected the backup's output to be live after takeover")
+	}
+	if status := backup.Status(); status.TakenOverAt == nil {
+		t.Errorf("Expected a takeover time, got %+v", status)
+	}
+
+	// A restarted primary stays passive while the backup holds output
+	restarted, restartedOutput := startPrimary(t)
+	defer restarted.Cleanup()
+	_ = restarted.Put("master", "grand", 0.5)
+	server.set(restarted)
+	waitFor(t, "the primary to yield", func() bool { return restartedOutput.isPassive() })
+	if _, ok := tracker.get("master/grand"); ok {
+		t.Error("A backup holding output should not track the primary")
+	}
+	if primaryOutput.isPassive() {
+		t.Error("The first primary should never have yielded")
+	}
+
+	// Failing back returns output to the primary and resyncs the backup
+	if _, err := backup.Failback(); err != nil {
+		t.Fatalf("Failback() error: %v", err)
+	}
+	waitFor(t, "the primary to resume", func() bool { return !restartedOutput.isPassive() })
+	if !backupOutput.isPassive() {
+		t.Error("Expected the backup's output passive after failback")
+	}
+	waitFor(t, "the resync", func() bool {
+		_, ok := tracker.get("master/grand")
+		return ok
+	})
+	tracker.mu.Lock()
+	if _, ok := tracker.state[KindSetting+"/channel_limits"]; ok || tracker.resets != 1 {
+		t.Errorf("Expected one reset dropping state from before failback, got %d resets", tracker.resets)
+	}
+	tracker.mu.Unlock()
+
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if len(activeChanges) != 3 || activeChanges[0] || !activeChanges[1] || activeChanges[2] {
+		t.Errorf("Active callbacks = %v, want [false true false]", activeChanges)
+	}
+}
+
+func TestFailback_Errors(t *testing.T) {
+	primary, _ := startPrimary(t)
+	defer primary.Cleanup()
+	if _, err := primary.Failback(); err == nil {
+		t.Error("Expected a primary to refuse to fail back")
+	}
+
+	backup := NewService(&fakeOutput{})
+	if err := backup.Start(testConfig(RoleBackup, "ws://127.0.0.1:1"+Path)); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer backup.Cleanup()
+	if _, err := backup.Failback(); err == nil {
+		t.Error("Expected a backup that does not hold output to refuse to fail back")
+	}
+	waitFor(t, "takeover", func() bool { return backup.Status().OutputActive })
+	if _, err := backup.Failback(); err == nil {
+		t.Error("Expected failback to need a connected primary")
+	}
+}
+
+func TestServeHTTP_Rejects(t *testing.T) {
+	standalone := NewService(&fakeOutput{})
+	rec := httptest.NewRecorder()
+	standalone.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Standalone status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	primary, _ := startPrimary(t)
+	defer primary.Cleanup()
+	req := httptest.NewRequest(http.MethodGet, Path, nil)
+	req.Header.Set(TokenHeader, "wrong")
+	rec = httptest.NewRecorder()
+	primary.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wrong token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	// Without a token, a primary that needs signing in takes no backups
+	open := NewService(&fakeOutput{})
+	cfg := testConfig(RolePrimary, "")
+	cfg.Token = ""
+	if err := open.Start(cfg); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer open.Cleanup()
+	open.SetTokenRequired(func() bool { return true })
+	rec = httptest.NewRecorder()
+	open.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Missing token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	for value, want := range map[string]Role{"": RoleStandalone, "primary": RolePrimary, " Backup ": RoleBackup} {
+		if role, err := ParseRole(value); err != nil || role != want {
+			t.Errorf("ParseRole(%q) = %q, %v; want %q", value, role, err, want)
+		}
+	}
+	if _, err := ParseRole("leader"); err == nil {
+		t.Error("Expected an unknown role to be rejected")
+	}
+
+	invalid := map[string]Config{
+		"no primary URL": {Role: RoleBackup},
+		"http URL":       {Role: RoleBackup, PrimaryURL: "http://10.0.0.2:4000" + Path},
+		"short timeout":  {Role: RolePrimary, HeartbeatInterval: time.Second, FailoverTimeout: time.Second},
+		"unknown role":   {Role: "LEADER"},
+	}
+	for name, cfg := range invalid {
+		if err := NewService(&fakeOutput{}).Start(cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DownloadPath is the HTTP path reports are downloaded from.
+const DownloadPath = "/reports/download"
+
+// DownloadTTL is how long a download link stays valid.
+const DownloadTTL = 5 * time.Minute
+
+type download struct {
+	report    *Report
+	expiresAt time.Time
+}
+
+// Downloads holds rendered reports for one-time download links. Links are
+// issued through GraphQL, so a download is authorized like the mutation
+// that generated it.
+type Downloads struct {
+	mu      sync.Mutex
+	pending map[string]download
+
+	now func() time.Time
+}
+
+// NewDownloads creates an empty download registry.
+func NewDownloads() *Downloads {
+	return &Downloads{pending: make(map[string]download), now: time.Now}
+}
+
+// Issue returns a link to download a report, and when the link expires.
+func (d *Downloads) Issue(report *Report) (string, time.Time) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		panic(err) // crypto/rand does not fail on supported platforms
+	}
+	key := hex.EncodeToString(token)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for k, pending := range d.pending {
+		if !now.Before(pending.expiresAt) {
+			delete(d.pending, k)
+		}
+	}
+	expiresAt := now.Add(DownloadTTL)
+	d.pending[key] = download{report: report, expiresAt: expiresAt}
+	return DownloadPath + "?token=" + url.QueryEscape(key), expiresAt
+}
+
+// take claims the report a token names, if its link is still valid.
+func (d *Downloads) take(token string) (*Report, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending, ok := d.pending[token]
+	if !ok {
+		return nil, false
+	}
+	delete(d.pending, token)
+	return pending.report, d.now().Before(pending.expiresAt)
+}
+
+// ServeReport sends the report a download link names. Each link works once.
+// Add download=1 to save the report rather than open it for printing.
+func (d *Downloads) ServeReport(w http.ResponseWriter, r *http.Request) {
+	report, ok := d.take(r.URL.Query().Get("token"))
+	if !ok {
+		http.Error(w, "download link is invalid or has expired", http.StatusNotFound)
+		return
+	}
+
+	disposition := "inline"
+	if r.URL.Query().Get("download") == "1" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": report.FileName}))
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write(report.Content); err != nil {
+		log.Warn("failed to write report", "error", err)
+	}
+}
@@ -0,0 +1,280 @@
+// Package report renders a project's paperwork — cue sheets, channel
+// hookups and instrument schedules — as printable HTML. The pages carry
+// print styles, so a browser prints them, or saves them as PDF, with the
+// table headers repeated on every page.
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+)
+
+// Type is a kind of report.
+type Type string
+
+const (
+	TypeCueSheet           Type = "CUE_SHEET"
+	TypeChannelHookup      Type = "CHANNEL_HOOKUP"
+	TypeInstrumentSchedule Type = "INSTRUMENT_SCHEDULE"
+)
+
+// titles are the headings of the report types.
+var titles = map[Type]string{
+	TypeCueSheet:           "Cue Sheet",
+	TypeChannelHookup:      "Channel Hookup",
+	TypeInstrumentSchedule: "Instrument Schedule",
+}
+
+// ContentType is the media type of a rendered report.
+const ContentType = "text/html; charset=utf-8"
+
+// Options narrows a report.
+type Options struct {
+	// CueListID limits a cue sheet to one cue list; otherwise it has them all
+	CueListID *string
+}
+
+// Report is a rendered report.
+type Report struct {
+	Title    string
+	FileName string
+	Content  []byte
+}
+
+// Service renders reports from a project.
+type Service struct {
+	projectRepo *repositories.ProjectRepository
+	fixtureRepo *repositories.FixtureRepository
+	sceneRepo   *repositories.SceneRepository
+	cueListRepo *repositories.CueListRepository
+
+	now func() time.Time
+}
+
+// NewService creates a report service.
+func NewService(
+	projectRepo *repositories.ProjectRepository,
+	fixtureRepo *repositories.FixtureRepository,
+	sceneRepo *repositories.SceneRepository,
+	cueListRepo *repositories.CueListRepository,
+) *Service {
+	return &Service{
+		projectRepo: projectRepo,
+		fixtureRepo: fixtureRepo,
+		sceneRepo:   sceneRepo,
+		cueListRepo: cueListRepo,
+		now:         time.Now,
+	}
+}
+
+// page is what the report template renders.
+type page struct {
+	Project     string
+	Title       string
+	GeneratedAt string
+	Type        Type
+	CueLists    []cueListSection
+	Fixtures    []fixtureRow
+}
+
+type cueListSection struct {
+	Name        string
+	Description string
+	Cues        []cueRow
+}
+
+type cueRow struct {
+	Number  string
+	Name    string
+	Scene   string
+	FadeIn  string
+	FadeOut string
+	Follow  string
+	Notes   string
+}
+
+type fixtureRow struct {
+	Name        string
+	Instrument  string
+	Type        string
+	Mode        string
+	Address     string
+	Channels    int
+	Tags        string
+	Description string
+}
+
+// Generate renders a report of a project.
+func (s *Service) Generate(ctx context.Context, projectID string, reportType Type, opts Options) (*Report, error) {
+	title, ok := titles[reportType]
+	if !ok {
+		return nil, fmt.Errorf("unknown report type: %s", reportType)
+	}
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	p := &page{
+		Project:     project.Name,
+		Title:       title,
+		GeneratedAt: s.now().Format("Jan 2, 2006 3:04 PM"),
+		Type:        reportType,
+	}
+	switch reportType {
+	case TypeCueSheet:
+		p.CueLists, err = s.cueSheet(ctx, projectID, opts.CueListID)
+	case TypeChannelHookup:
+		p.Fixtures, err = s.fixtureRows(ctx, projectID, false)
+	case TypeInstrumentSchedule:
+		p.Fixtures, err = s.fixtureRows(ctx, projectID, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return &Report{
+		Title:    title,
+		FileName: fileName(project.Name + " - " + title),
+		Content:  buf.Bytes(),
+	}, nil
+}
+
+// cueSheet lists the cues of a project's cue lists, or of one of them.
+func (s *Service) cueSheet(ctx context.Context, projectID string, cueListID *string) ([]cueListSection, error) {
+	cueLists, err := s.cueListRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if cueListID != nil {
+		var selected []models.CueList
+		for _, cueList := range cueLists {
+			if cueList.ID == *cueListID {
+				selected = append(selected, cueList)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("cue list not found in project: %s", *cueListID)
+		}
+		cueLists = selected
+	}
+
+	scenes, err := s.sceneRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	sceneNames := make(map[string]string, len(scenes))
+	for _, scene := range scenes {
+		sceneNames[scene.ID] = scene.Name
+	}
+
+	sections := make([]cueListSection, 0, len(cueLists))
+	for _, cueList := range cueLists {
+		cues, err := s.cueListRepo.GetCues(ctx, cueList.ID)
+		if err != nil {
+			return nil, err
+		}
+		section := cueListSection{Name: cueList.Name, Description: deref(cueList.Description)}
+		for _, cue := range cues {
+			row := cueRow{
+				Number:  strconv.FormatFloat(cue.CueNumber, 'f', -1, 64),
+				Name:    cue.Name,
+				Scene:   sceneNames[cue.SceneID],
+				FadeIn:  seconds(cue.FadeInTime),
+				FadeOut: seconds(cue.FadeOutTime),
+				Notes:   deref(cue.Notes),
+			}
+			if cue.FollowTime != nil {
+				row.Follow = seconds(*cue.FollowTime)
+			}
+			section.Cues = append(section.Cues, row)
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// fixtureRows lists a project's fixtures: by DMX address for a hookup, or
+// in the project's fixture order for an instrument schedule.
+func (s *Service) fixtureRows(ctx context.Context, projectID string, projectOrder bool) ([]fixtureRow, error) {
+	fixtures, err := s.fixtureRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if projectOrder {
+		sort.SliceStable(fixtures, func(i, j int) bool {
+			a, b := fixtures[i].ProjectOrder, fixtures[j].ProjectOrder
+			if a == nil || b == nil {
+				return a != nil && b == nil
+			}
+			return *a < *b
+		})
+	}
+
+	rows := make([]fixtureRow, len(fixtures))
+	for i := range fixtures {
+		f := &fixtures[i]
+		channels := 1
+		if f.ChannelCount != nil && *f.ChannelCount > 0 {
+			channels = *f.ChannelCount
+		}
+		address := fmt.Sprintf("%d/%03d", f.Universe, f.StartChannel)
+		if channels > 1 {
+			address += fmt.Sprintf("–%03d", f.StartChannel+channels-1)
+		}
+		var tags []string
+		if f.Tags != nil {
+			_ = json.Unmarshal([]byte(*f.Tags), &tags)
+		}
+		rows[i] = fixtureRow{
+			Name:        f.Name,
+			Instrument:  strings.TrimSpace(deref(f.Manufacturer) + " " + deref(f.Model)),
+			Type:        deref(f.Type),
+			Mode:        deref(f.ModeName),
+			Address:     address,
+			Channels:    channels,
+			Tags:        strings.Join(tags, ", "),
+			Description: deref(f.Description),
+		}
+	}
+	return rows, nil
+}
+
+// fileName returns the file name to save a report as.
+func fileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < ' ', strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	return name + ".html"
+}
+
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', -1, 64) + "s"
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
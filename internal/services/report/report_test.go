@@ -0,0 +1,161 @@
+package report
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+)
+
+func setupProject(t *testing.T) (*Service, *testutil.TestDB, string, func()) {
+	t.Helper()
+	testDB, cleanup := testutil.SetupTestDB(t)
+	service := NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo)
+	service.now = func() time.Time { return time.Date(2026, 3, 1, 19, 30, 0, 0, time.UTC) }
+
+	db := testDB.DB
+	project := &models.Project{ID: "report-project", Name: "Spring Show"}
+	db.Create(project)
+	db.Create(&models.FixtureDefinition{ID: "report-def", Manufacturer: "Acme", Model: "Par", Type: "LED_PAR"})
+	manufacturer, model, fixtureType, mode := "Acme", "Par", "LED_PAR", "7ch"
+	first, second := 1, 0
+	db.Create(&models.FixtureInstance{ID: "report-fix-1", Name: "Front <1>", DefinitionID: "report-def", ProjectID: project.ID,
+		Manufacturer: &manufacturer, Model: &model, Type: &fixtureType, ModeName: &mode, ChannelCount: intPtr(7),
+		Universe: 1, StartChannel: 9, ProjectOrder: &first})
+	tags := `["front","wash"]`
+	db.Create(&models.FixtureInstance{ID: "report-fix-2", Name: "Back", DefinitionID: "report-def", ProjectID: project.ID,
+		Manufacturer: &manufacturer, Model: &model, Type: &fixtureType, ChannelCount: intPtr(1),
+		Universe: 1, StartChannel: 1, ProjectOrder: &second, Tags: &tags})
+	db.Create(&models.Scene{ID: "report-scene", Name: "Warm Wash", ProjectID: project.ID})
+	db.Create(&models.CueList{ID: "report-list-1", Name: "Act 1", ProjectID: project.ID})
+	db.Create(&models.CueList{ID: "report-list-2", Name: "Act 2", ProjectID: project.ID})
+	follow, notes := 2.0, "House to half"
+	db.Create(&models.Cue{ID: "report-cue", Name: "Preset", CueNumber: 1.5, CueListID: "report-list-1", SceneID: "report-scene",
+		FadeInTime: 3, FadeOutTime: 2.5, FollowTime: &follow, Notes: &notes})
+	return service, testDB, project.ID, cleanup
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestGenerate_CueSheet(t *testing.T) {
+	service, _, projectID, cleanup := setupProject(t)
+	defer cleanup()
+
+	report, err := service.Generate(context.Background(), projectID, TypeCueSheet, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if report.FileName != "Spring Show - Cue Sheet.html" {
+		t.Errorf("Unexpected file name %q", report.FileName)
+	}
+	html := string(report.Content)
+	for _, want := range []string{
+		"<h1>Spring Show – Cue Sheet</h1>",
+		"Mar 1, 2026 7:30 PM",
+		"<h2>Act 1</h2>",
+		`<td class="num">1.5</td><td>Preset</td><td>Warm Wash</td><td class="num">3s</td><td class="num">2.5s</td><td class="num">2s</td><td>House to half</td>`,
+		"<h2>Act 2</h2>",
+		"No cues",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in the report:\n%s", want, html)
+		}
+	}
+
+	listID := "report-list-2"
+	report, err = service.Generate(context.Background(), projectID, TypeCueSheet, Options{CueListID: &listID})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if strings.Contains(string(report.Content), "Act 1") {
+		t.Error("Expected only the selected cue list")
+	}
+
+	missing := "missing"
+	if _, err := service.Generate(context.Background(), projectID, TypeCueSheet, Options{CueListID: &missing}); err == nil {
+		t.Error("Expected an error for a cue list outside the project")
+	}
+}
+
+func TestGenerate_FixtureReports(t *testing.T) {
+	service, _, projectID, cleanup := setupProject(t)
+	defer cleanup()
+
+	hookup, err := service.Generate(context.Background(), projectID, TypeChannelHookup, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	html := string(hookup.Content)
+	back := strings.Index(html, `<td class="num">1/001</td><td>Back</td>`)
+	front := strings.Index(html, `<td class="num">1/009–015</td><td>Front &lt;1&gt;</td><td>Acme Par</td><td>7ch</td><td class="num">7</td>`)
+	if back < 0 || front < 0 || back > front {
+		t.Errorf("Expected fixtures in address order with names escaped:\n%s", html)
+	}
+
+	schedule, err := service.Generate(context.Background(), projectID, TypeInstrumentSchedule, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	html = string(schedule.Content)
+	back = strings.Index(html, "<td>Back</td><td>Acme Par</td><td>LED_PAR</td><td></td><td class=\"num\">1/001</td><td>front, wash</td>")
+	front = strings.Index(html, "<td>Front &lt;1&gt;</td>")
+	if back < 0 || front < 0 || back > front {
+		t.Errorf("Expected fixtures in project order:\n%s", html)
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	service, _, projectID, cleanup := setupProject(t)
+	defer cleanup()
+
+	if _, err := service.Generate(context.Background(), projectID, Type("LIGHT_PLOT"), Options{}); err == nil {
+		t.Error("Expected an error for an unknown report type")
+	}
+	if _, err := service.Generate(context.Background(), "missing", TypeCueSheet, Options{}); err == nil {
+		t.Error("Expected an error for a missing project")
+	}
+}
+
+func TestDownloads_ServeReport(t *testing.T) {
+	downloads := NewDownloads()
+	link, expiresAt := downloads.Issue(&Report{FileName: "Show - Cue Sheet.html", Content: []byte("<p>cues</p>")})
+	if !strings.HasPrefix(link, DownloadPath+"?token=") {
+		t.Errorf("Expected a link under %s, got %s", DownloadPath, link)
+	}
+	if time.Until(expiresAt) > DownloadTTL {
+		t.Errorf("Expected the link to expire within %v", DownloadTTL)
+	}
+
+	rec := httptest.NewRecorder()
+	downloads.ServeReport(rec, httptest.NewRequest(http.MethodGet, link+"&download=1", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "<p>cues</p>" {
+		t.Fatalf("Expected the report, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected content type %s, got %s", ContentType, ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") || !strings.Contains(cd, "Show - Cue Sheet.html") {
+		t.Errorf("Unexpected content disposition %q", cd)
+	}
+
+	// Links work once
+	rec = httptest.NewRecorder()
+	downloads.ServeReport(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected a used link to give status 404, got %d", rec.Code)
+	}
+
+	// And not after they expire
+	link, _ = downloads.Issue(&Report{FileName: "Show.html"})
+	downloads.now = func() time.Time { return time.Now().Add(DownloadTTL + time.Second) }
+	rec = httptest.NewRecorder()
+	downloads.ServeReport(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected an expired link to give status 404, got %d", rec.Code)
+	}
+}
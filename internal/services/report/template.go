@@ -0,0 +1,12 @@
+package report
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/report.html
+var templates embed.FS
+
+// pageTemplate renders every report type; the page's Type picks the tables.
+var pageTemplate = template.Must(template.ParseFS(templates, "templates/report.html"))
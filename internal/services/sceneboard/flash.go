@@ -0,0 +1,215 @@
+package sceneboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+// FlashState describes the current flash status of a scene board button.
+type FlashState struct {
+	ButtonID    string
+	SceneID     string
+	Level       float64 // Current 0-1 level of the flash
+	IsFlashing  bool    // The button is pressed
+	IsReleasing bool    // The flash is fading out after release
+}
+
+// flash tracks a button's scene flashed over the output.
+type flash struct {
+	buttonID    string
+	sceneID     string
+	level       float64 // Level while pressed
+	releasedAt  *time.Time
+	releaseTime time.Duration
+}
+
+// FlashService implements flash (bump) buttons. A flash puts its scene on
+// the DMX output's flash layer, above cues, scene activations, and
+// submasters, for as long as the button is pressed; on release it fades out
+// to reveal the output below, which carries on underneath unchanged.
+type FlashService struct {
+	mu         sync.Mutex
+	dmxService *dmx.Service
+	flashes    map[string]*flash
+
+	// Called with a button whose flash state changed (optional)
+	onChange func(buttonID string)
+
+	now func() time.Time
+}
+
+// NewFlashService creates a new scene board flash service. Call Tick on
+// every fade engine tick to run release fades.
+func NewFlashService(dmxService *dmx.Service) *FlashService {
+	return &FlashService{
+		dmxService: dmxService,
+		flashes:    make(map[string]*flash),
+		now:        time.Now,
+	}
+}
+
+// SetChangeCallback sets the callback for flash state changes.
+func (s *FlashService) SetChangeCallback(callback func(buttonID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// flashID returns the DMX flash layer ID used for a button.
+func flashID(buttonID string) string {
+	return fmt.Sprintf("scene-board-flash-%s", buttonID)
+}
+
+// Start flashes a button's scene at level (0-1) until End. Starting a
+// button that is already flashing or releasing restarts it on top.
+func (s *FlashService) Start(buttonID, sceneID string, layer dmx.Flash, level float64) (*FlashState, error) {
+	layer.Level = level
+	if err := s.dmxService.SetFlash(flashID(buttonID), layer); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	f := &flash{buttonID: buttonID, sceneID: sceneID, level: level}
+	s.flashes[buttonID] = f
+	state := s.stateLocked(f, s.now())
+	s.mu.Unlock()
+
+	s.notify(buttonID)
+	return state, nil
+}
+
+// End releases a flash, fading it out over releaseTime, or at once when
+// releaseTime is zero.
+func (s *FlashService) End(buttonID string, releaseTime time.Duration) (*FlashState, error) {
+	s.mu.Lock()
+	f, ok := s.flashes[buttonID]
+	if !ok || f.releasedAt != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("scene board button is not flashing: %s", buttonID)
+	}
+
+	now := s.now()
+	f.releasedAt = &now
+	f.releaseTime = releaseTime
+	if releaseTime <= 0 {
+		delete(s.flashes, buttonID)
+	}
+	state := s.stateLocked(f, now)
+	s.mu.Unlock()
+
+	if releaseTime <= 0 {
+		s.dmxService.ClearFlash(flashID(buttonID))
+	}
+	s.notify(buttonID)
+	return state, nil
+}
+
+// Tick moves release fades along, removing flashes that have faded out.
+func (s *FlashService) Tick(now time.Time) {
+	s.mu.Lock()
+	var done []string
+	levels := make(map[string]float64)
+	for id, f := range s.flashes {
+		if f.releasedAt == nil {
+			continue
+		}
+		if level := s.levelAt(f, now); level > 0 {
+			levels[id] = level
+		} else {
+			delete(s.flashes, id)
+			done = append(done, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for id, level := range levels {
+		_ = s.dmxService.SetFlashLevel(flashID(id), level)
+	}
+	for _, id := range done {
+		s.dmxService.ClearFlash(flashID(id))
+		go s.notify(id)
+	}
+}
+
+// State returns the flash state of a button, or nil if it is not flashing.
+func (s *FlashService) State(buttonID string) *FlashState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.flashes[buttonID]
+	if !ok {
+		return nil
+	}
+	return s.stateLocked(f, s.now())
+}
+
+// ReleaseProgress returns the progress (0-1) of a button's release fade,
+// and false when none is running.
+func (s *FlashService) ReleaseProgress(buttonID string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.flashes[buttonID]
+	if !ok || f.releasedAt == nil {
+		return 0, false
+	}
+	return releaseProgress(f, s.now()), true
+}
+
+// Clear ends a button's flash at once, as when the button is removed.
+func (s *FlashService) Clear(buttonID string) {
+	s.mu.Lock()
+	_, ok := s.flashes[buttonID]
+	delete(s.flashes, buttonID)
+	s.mu.Unlock()
+
+	if ok {
+		s.dmxService.ClearFlash(flashID(buttonID))
+		s.notify(buttonID)
+	}
+}
+
+// levelAt computes a flash's level at the given time.
+func (s *FlashService) levelAt(f *flash, t time.Time) float64 {
+	if f.releasedAt == nil {
+		return f.level
+	}
+	return f.level * (1 - fade.ApplyEasing(releaseProgress(f, t), fade.EasingInOutSine))
+}
+
+func releaseProgress(f *flash, t time.Time) float64 {
+	if f.releaseTime <= 0 {
+		return 1
+	}
+	progress := float64(t.Sub(*f.releasedAt)) / float64(f.releaseTime)
+	if progress >= 1 {
+		return 1
+	}
+	if progress <= 0 {
+		return 0
+	}
+	return progress
+}
+
+func (s *FlashService) stateLocked(f *flash, now time.Time) *FlashState {
+	return &FlashState{
+		ButtonID:    f.buttonID,
+		SceneID:     f.sceneID,
+		Level:       s.levelAt(f, now),
+		IsFlashing:  f.releasedAt == nil,
+		IsReleasing: f.releasedAt != nil && f.releaseTime > 0,
+	}
+}
+
+func (s *FlashService) notify(buttonID string) {
+	s.mu.Lock()
+	callback := s.onChange
+	s.mu.Unlock()
+	if callback != nil {
+		callback(buttonID)
+	}
+}
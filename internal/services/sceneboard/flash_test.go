@@ -0,0 +1,107 @@
+package sceneboard
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+)
+
+func createTestFlashService() (*FlashService, *dmx.Service, *time.Time) {
+	dmxService := dmx.NewService(dmx.Config{Enabled: false})
+	s := NewFlashService(dmxService)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	return s, dmxService, &now
+}
+
+func testFlash() dmx.Flash {
+	return dmx.Flash{
+		Intensity: map[int]map[int]byte{1: {1: 200}},
+		Other:     map[int]map[int]byte{1: {2: 100}},
+	}
+}
+
+func TestFlashLayersOverLiveOutput(t *testing.T) {
+	s, dmxService, _ := createTestFlashService()
+	dmxService.SetChannelValue(1, 1, 50)
+	dmxService.SetChannelValue(1, 2, 30)
+
+	state, err := s.Start("btn-1", "scene-1", testFlash(), 0.5)
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if !state.IsFlashing || state.Level != 0.5 {
+		t.Errorf("Unexpected state after Start(): %+v", state)
+	}
+	if universe := dmxService.GetUniverse(1); universe[0] != 100 || universe[1] != 100 {
+		t.Errorf("Output while flashing at half = %v, want [100 100]", universe[:2])
+	}
+
+	// Content below keeps running while the flash is up
+	dmxService.SetChannelValue(1, 1, 180)
+	if got := dmxService.GetUniverse(1)[0]; got != 180 {
+		t.Errorf("Live intensity above the flash = %d, want 180", got)
+	}
+
+	if _, err := s.End("btn-1", 0); err != nil {
+		t.Fatalf("End() error: %v", err)
+	}
+	if universe := dmxService.GetUniverse(1); universe[0] != 180 || universe[1] != 30 {
+		t.Errorf("Output after the flash ended = %v, want [180 30]", universe[:2])
+	}
+	if s.State("btn-1") != nil {
+		t.Error("Expected no state after an instant release")
+	}
+	if _, err := s.End("btn-1", 0); err == nil {
+		t.Error("Expected an error ending a flash that is not running")
+	}
+}
+
+func TestFlashReleaseFades(t *testing.T) {
+	s, dmxService, now := createTestFlashService()
+	var mu sync.Mutex
+	var changes []string
+	s.SetChangeCallback(func(buttonID string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, buttonID)
+	})
+
+	if _, err := s.Start("btn-1", "scene-1", testFlash(), 1); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	state, err := s.End("btn-1", 2*time.Second)
+	if err != nil {
+		t.Fatalf("End() error: %v", err)
+	}
+	if state.IsFlashing || !state.IsReleasing || state.Level != 1 {
+		t.Errorf("Unexpected state at release: %+v", state)
+	}
+
+	*now = now.Add(time.Second)
+	s.Tick(*now)
+	if got := dmxService.GetUniverse(1)[0]; got != 100 {
+		t.Errorf("Intensity halfway through the release = %d, want 100", got)
+	}
+	if progress, ok := s.ReleaseProgress("btn-1"); !ok || progress != 0.5 {
+		t.Errorf("ReleaseProgress() = %v, %v, want 0.5", progress, ok)
+	}
+
+	*now = now.Add(time.Second)
+	s.Tick(*now)
+	if universe := dmxService.GetUniverse(1); universe[0] != 0 || universe[1] != 0 {
+		t.Errorf("Output after the release = %v, want [0 0]", universe[:2])
+	}
+	if s.State("btn-1") != nil {
+		t.Error("Expected the flash to be gone after its release")
+	}
+
+	time.Sleep(10 * time.Millisecond) // The final notification is asynchronous
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 3 {
+		t.Errorf("Expected 3 change notifications, got %v", changes)
+	}
+}
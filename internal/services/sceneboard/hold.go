@@ -0,0 +1,281 @@
+// Package sceneboard provides server-side scene board button behaviors.
+package sceneboard
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+// ReleaseMode controls what happens to a held scene when its button is released.
+type ReleaseMode string
+
+const (
+	// ReleaseModeLatch keeps the scene at the level reached when released.
+	ReleaseModeLatch ReleaseMode = "LATCH"
+	// ReleaseModeRelease fades the scene's channels back out on release.
+	ReleaseModeRelease ReleaseMode = "RELEASE"
+)
+
+// HoldState describes the current hold status of a scene board button.
+type HoldState struct {
+	ButtonID    string
+	SceneID     string
+	PressedAt   time.Time
+	ReleasedAt  *time.Time
+	Level       float64 // 0-1 scene level reached along the hold curve
+	IsHeld      bool
+	IsLatched   bool
+	HeldSeconds float64
+}
+
+// hold tracks an in-progress or latched button hold.
+type hold struct {
+	buttonID   string
+	sceneID    string
+	channels   []fade.SceneChannel
+	pressedAt  time.Time
+	releasedAt *time.Time
+	rampTime   time.Duration
+	curve      fade.EasingType
+	latched    bool
+	level      float64 // Level frozen at release time
+}
+
+// Service implements press-and-hold semantics for scene board buttons.
+// Holding a button ramps its scene up along a curve over the ramp time;
+// releasing either latches the reached level or fades the scene back out.
+type Service struct {
+	mu         sync.Mutex
+	fadeEngine *fade.Engine
+	holds      map[string]*hold
+
+	// Called with a button whose hold state changed (optional)
+	onChange func(buttonID string)
+
+	now func() time.Time
+}
+
+// NewService creates a new scene board hold service.
+func NewService(fadeEngine *fade.Engine) *Service {
+	return &Service{
+		fadeEngine: fadeEngine,
+		holds:      make(map[string]*hold),
+		now:        time.Now,
+	}
+}
+
+// SetChangeCallback sets the callback for hold state changes.
+func (s *Service) SetChangeCallback(callback func(buttonID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// fadeID returns the fade engine ID used for a button's hold ramp.
+func fadeID(buttonID string) string {
+	return fmt.Sprintf("scene-board-hold-%s", buttonID)
+}
+
+// Press starts ramping the button's scene toward full over rampTime using
+// the given curve. Pressing a button that is already held restarts the ramp.
+func (s *Service) Press(buttonID, sceneID string, channels []fade.SceneChannel, rampTime time.Duration, curve fade.EasingType) *HoldState {
+	if curve == "" {
+		curve = fade.EasingLinear
+	}
+
+	s.mu.Lock()
+	h := &hold{
+		buttonID:  buttonID,
+		sceneID:   sceneID,
+		channels:  channels,
+		pressedAt: s.now(),
+		rampTime:  rampTime,
+		curve:     curve,
+	}
+	s.holds[buttonID] = h
+	state := s.stateLocked(h)
+	s.mu.Unlock()
+
+	if s.fadeEngine != nil {
+		s.fadeEngine.FadeToScene(channels, rampTime, fadeID(buttonID), curve)
+	}
+
+	s.notify(buttonID)
+	return state
+}
+
+// Release ends a hold. In latch mode the scene stays at the level reached;
+// in release mode its channels fade to zero over releaseTime.
+func (s *Service) Release(buttonID string, mode ReleaseMode, releaseTime time.Duration) (*HoldState, error) {
+	s.mu.Lock()
+	h, ok := s.holds[buttonID]
+	if !ok || h.releasedAt != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("scene board button is not held: %s", buttonID)
+	}
+
+	releasedAt := s.now()
+	h.level = s.levelAt(h, releasedAt)
+	h.releasedAt = &releasedAt
+	h.latched = mode != ReleaseModeRelease
+	if !h.latched {
+		delete(s.holds, buttonID)
+	}
+	state := s.stateLocked(h)
+	s.mu.Unlock()
+
+	if s.fadeEngine != nil {
+		if h.latched {
+			// Stop the ramp where it is; channels keep their current output
+			s.fadeEngine.CancelFade(fadeID(buttonID))
+		} else {
+			targets := make([]fade.ChannelTarget, len(h.channels))
+			for i, ch := range h.channels {
+				targets[i] = fade.ChannelTarget{
+					Universe:     ch.Universe,
+					Channel:      ch.Channel,
+					TargetValue:  0,
+					FadeBehavior: ch.FadeBehavior,
+				}
+			}
+			s.fadeEngine.FadeChannels(targets, releaseTime, fadeID(buttonID), h.curve, nil)
+		}
+	}
+
+	s.notify(buttonID)
+	return state, nil
+}
+
+// SetLevel puts a button's scene at a fixed level, as from a fader. FADE
+// channels scale with the level; snap channels keep their scene value while
+// the level is above zero. The scene latches at the level; at zero its
+// channels go to zero and the latch is cleared.
+func (s *Service) SetLevel(buttonID, sceneID string, channels []fade.SceneChannel, level float64) *HoldState {
+	level = math.Max(0, math.Min(1, level))
+
+	s.mu.Lock()
+	var state *HoldState
+	if level == 0 {
+		delete(s.holds, buttonID)
+	} else {
+		now := s.now()
+		h := &hold{
+			buttonID:   buttonID,
+			sceneID:    sceneID,
+			channels:   channels,
+			pressedAt:  now,
+			releasedAt: &now,
+			curve:      fade.EasingLinear,
+			latched:    true,
+			level:      level,
+		}
+		s.holds[buttonID] = h
+		state = s.stateLocked(h)
+	}
+	s.mu.Unlock()
+
+	if s.fadeEngine != nil {
+		targets := make([]fade.ChannelTarget, len(channels))
+		for i, ch := range channels {
+			value := ch.Value
+			switch {
+			case level == 0:
+				value = 0
+			case ch.FadeBehavior == "" || ch.FadeBehavior == fade.FadeBehaviorFade:
+				value = int(math.Round(float64(ch.Value) * level))
+			}
+			targets[i] = fade.ChannelTarget{
+				Universe:     ch.Universe,
+				Channel:      ch.Channel,
+				TargetValue:  value,
+				FadeBehavior: ch.FadeBehavior,
+			}
+		}
+		s.fadeEngine.FadeChannels(targets, 0, fadeID(buttonID), fade.EasingLinear, nil)
+	}
+
+	s.notify(buttonID)
+	return state
+}
+
+// State returns the hold state of a button, or nil if it is neither held nor latched.
+func (s *Service) State(buttonID string) *HoldState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.holds[buttonID]
+	if !ok {
+		return nil
+	}
+	return s.stateLocked(h)
+}
+
+// Clear forgets any hold or latch for a button without changing DMX output.
+func (s *Service) Clear(buttonID string) {
+	s.mu.Lock()
+	_, ok := s.holds[buttonID]
+	delete(s.holds, buttonID)
+	s.mu.Unlock()
+
+	if ok {
+		s.notify(buttonID)
+	}
+}
+
+// FadeProgress returns the progress (0-1) of a button's hold ramp or release
+// fade, and false when neither is running.
+func (s *Service) FadeProgress(buttonID string) (float64, bool) {
+	if s.fadeEngine == nil {
+		return 0, false
+	}
+	return s.fadeEngine.FadeProgress(fadeID(buttonID))
+}
+
+func (s *Service) notify(buttonID string) {
+	s.mu.Lock()
+	callback := s.onChange
+	s.mu.Unlock()
+	if callback != nil {
+		callback(buttonID)
+	}
+}
+
+// levelAt computes the curve level for a hold at the given time.
+func (s *Service) levelAt(h *hold, t time.Time) float64 {
+	if h.releasedAt != nil {
+		return h.level
+	}
+	if h.rampTime <= 0 {
+		return 1
+	}
+	progress := float64(t.Sub(h.pressedAt)) / float64(h.rampTime)
+	if progress >= 1 {
+		return 1
+	}
+	if progress <= 0 {
+		return 0
+	}
+	return fade.ApplyEasing(progress, h.curve)
+}
+
+func (s *Service) stateLocked(h *hold) *HoldState {
+	now := s.now()
+	end := now
+	if h.releasedAt != nil {
+		end = *h.releasedAt
+	}
+	return &HoldState{
+		ButtonID:    h.buttonID,
+		SceneID:     h.sceneID,
+		PressedAt:   h.pressedAt,
+		ReleasedAt:  h.releasedAt,
+		Level:       s.levelAt(h, now),
+		IsHeld:      h.releasedAt == nil,
+		IsLatched:   h.latched,
+		HeldSeconds: end.Sub(h.pressedAt).Seconds(),
+	}
+}
@@ -0,0 +1,197 @@
+package sceneboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+func createTestService(t *testing.T) (*Service, *dmx.Service) {
+	dmxService := dmx.NewService(dmx.Config{Enabled: false})
+	engine := fade.NewEngine(dmxService, 60)
+	engine.Start()
+	t.Cleanup(engine.Stop)
+	return NewService(engine), dmxService
+}
+
+func testChannels() []fade.SceneChannel {
+	return []fade.SceneChannel{
+		{Universe: 1, Channel: 1, Value: 200},
+		{Universe: 1, Channel: 2, Value: 100},
+	}
+}
+
+func TestLevelFollowsCurve(t *testing.T) {
+	s := NewService(nil)
+	start := time.Now()
+	s.now = func() time.Time { return start }
+
+	s.Press("btn-1", "scene-1", testChannels(), 4*time.Second, fade.EasingLinear)
+
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 0},
+		{time.Second, 0.25},
+		{2 * time.Second, 0.5},
+		{4 * time.Second, 1},
+		{10 * time.Second, 1},
+	}
+	for _, tt := range tests {
+		s.now = func() time.Time { return start.Add(tt.elapsed) }
+		state := s.State("btn-1")
+		if state.Level != tt.want {
+			t.Errorf("Level after %v = %v, want %v", tt.elapsed, state.Level, tt.want)
+		}
+		if !state.IsHeld {
+			t.Errorf("Expected button to be held after %v", tt.elapsed)
+		}
+	}
+}
+
+func TestReleaseLatchFreezesLevel(t *testing.T) {
+	s := NewService(nil)
+	start := time.Now()
+	s.now = func() time.Time { return start }
+	s.Press("btn-1", "scene-1", testChannels(), 2*time.Second, fade.EasingLinear)
+
+	s.now = func() time.Time { return start.Add(time.Second) }
+	state, err := s.Release("btn-1", ReleaseModeLatch, time.Second)
+	if err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if !state.IsLatched || state.IsHeld {
+		t.Errorf("Expected latched, not held; got latched=%v held=%v", state.IsLatched, state.IsHeld)
+	}
+	if state.Level != 0.5 {
+		t.Errorf("Latched level = %v, want 0.5", state.Level)
+	}
+	if state.HeldSeconds != 1 {
+		t.Errorf("HeldSeconds = %v, want 1", state.HeldSeconds)
+	}
+
+	// Level stays frozen after release
+	s.now = func() time.Time { return start.Add(5 * time.Second) }
+	if level := s.State("btn-1").Level; level != 0.5 {
+		t.Errorf("Level after release = %v, want 0.5", level)
+	}
+
+	if _, err := s.Release("btn-1", ReleaseModeLatch, 0); err == nil {
+		t.Error("Expected error releasing a button that is not held")
+	}
+}
+
+func TestReleaseModeReleaseForgetsHold(t *testing.T) {
+	s := NewService(nil)
+	s.Press("btn-1", "scene-1", testChannels(), time.Second, fade.EasingLinear)
+
+	state, err := s.Release("btn-1", ReleaseModeRelease, 0)
+	if err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if state.IsLatched {
+		t.Error("Expected released state not to be latched")
+	}
+	if s.State("btn-1") != nil {
+		t.Error("Expected no state after release")
+	}
+}
+
+func TestPressRampsDMXOutput(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	s.Press("btn-1", "scene-1", testChannels(), 400*time.Millisecond, fade.EasingLinear)
+	time.Sleep(150 * time.Millisecond)
+
+	mid := dmxService.GetChannelValue(1, 1)
+	if mid == 0 || mid >= 200 {
+		t.Errorf("Channel 1 mid-hold = %d, want between 0 and 200", mid)
+	}
+
+	state, err := s.Release("btn-1", ReleaseModeLatch, 0)
+	if err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	latched := dmxService.GetChannelValue(1, 1)
+	time.Sleep(400 * time.Millisecond)
+	if got := dmxService.GetChannelValue(1, 1); got != latched {
+		t.Errorf("Channel 1 changed after latch: %d -> %d", latched, got)
+	}
+	if state.Level <= 0 || state.Level >= 1 {
+		t.Errorf("Latched level = %v, want between 0 and 1", state.Level)
+	}
+}
+
+func TestReleaseFadesOutDMXOutput(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	s.Press("btn-1", "scene-1", testChannels(), 0, fade.EasingLinear)
+	if got := dmxService.GetChannelValue(1, 1); got != 200 {
+		t.Fatalf("Channel 1 after instant press = %d, want 200", got)
+	}
+
+	if _, err := s.Release("btn-1", ReleaseModeRelease, 0); err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if got := dmxService.GetChannelValue(1, 1); got != 0 {
+		t.Errorf("Channel 1 after release = %d, want 0", got)
+	}
+	if got := dmxService.GetChannelValue(1, 2); got != 0 {
+		t.Errorf("Channel 2 after release = %d, want 0", got)
+	}
+}
+
+func TestSetLevelScalesDMXOutput(t *testing.T) {
+	s, dmxService := createTestService(t)
+	channels := append(testChannels(), fade.SceneChannel{Universe: 1, Channel: 3, Value: 40, FadeBehavior: fade.FadeBehaviorSnap})
+
+	state := s.SetLevel("btn-1", "scene-1", channels, 0.5)
+	if state == nil || !state.IsLatched || state.Level != 0.5 {
+		t.Fatalf("Expected latched state at 0.5, got %+v", state)
+	}
+	if got := dmxService.GetChannelValue(1, 1); got != 100 {
+		t.Errorf("Channel 1 at half = %d, want 100", got)
+	}
+	if got := dmxService.GetChannelValue(1, 3); got != 40 {
+		t.Errorf("Snap channel at half = %d, want its scene value 40", got)
+	}
+
+	if state := s.SetLevel("btn-1", "scene-1", channels, 0); state != nil {
+		t.Errorf("Expected no state at zero, got %+v", state)
+	}
+	if s.State("btn-1") != nil {
+		t.Error("Expected latch cleared at zero")
+	}
+	for ch := 1; ch <= 3; ch++ {
+		if got := dmxService.GetChannelValue(1, ch); got != 0 {
+			t.Errorf("Channel %d at zero = %d, want 0", ch, got)
+		}
+	}
+}
+
+func TestChangeCallbackAndFadeProgress(t *testing.T) {
+	s, _ := createTestService(t)
+	var changes []string
+	s.SetChangeCallback(func(buttonID string) { changes = append(changes, buttonID) })
+
+	s.Press("btn-1", "scene-1", testChannels(), time.Hour, fade.EasingLinear)
+	if progress, ok := s.FadeProgress("btn-1"); !ok || progress > 0.01 {
+		t.Errorf("FadeProgress() during the ramp = %v, %v", progress, ok)
+	}
+
+	if _, err := s.Release("btn-1", ReleaseModeLatch, 0); err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if _, ok := s.FadeProgress("btn-1"); ok {
+		t.Error("Expected no fade after latching")
+	}
+
+	s.Clear("btn-1")
+	s.Clear("btn-1") // Nothing left to clear
+	if len(changes) != 3 {
+		t.Errorf("Expected 3 change notifications, got %v", changes)
+	}
+}
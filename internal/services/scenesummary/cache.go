@@ -0,0 +1,164 @@
+package scenesummary
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"gorm.io/gorm"
+)
+
+// sourceTables are the tables summaries are computed from. A write to one
+// invalidates the summaries it may change.
+var sourceTables = []string{
+	"scenes",
+	"fixture_values",
+	"group_values",
+	"fixture_instances",
+	"instance_channels",
+	"fixture_groups",
+}
+
+// Cache holds scene summaries by scene ID until the scene changes.
+type Cache struct {
+	mu        sync.Mutex
+	summaries map[string]Summary
+	// generation counts invalidations, so a summary computed before one is
+	// not stored after it
+	generation uint64
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache {
+	return &Cache{summaries: make(map[string]Summary)}
+}
+
+// Get returns a scene's cached summary, and the generation to store a
+// computed one with when there is none.
+func (c *Cache) Get(sceneID string) (Summary, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary, ok := c.summaries[sceneID]
+	return summary, c.generation, ok
+}
+
+// Put stores a scene's summary, computed at a generation from Get. It is
+// dropped if the cache was invalidated since.
+func (c *Cache) Put(sceneID string, generation uint64, summary Summary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation == c.generation {
+		c.summaries[sceneID] = summary
+	}
+}
+
+// Invalidate drops the summaries of scenes, or all of them when none is
+// given.
+func (c *Cache) Invalidate(sceneIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if len(sceneIDs) == 0 {
+		c.summaries = make(map[string]Summary)
+		return
+	}
+	for _, id := range sceneIDs {
+		delete(c.summaries, id)
+	}
+}
+
+// Watch invalidates the cache on every write through a database to the
+// tables summaries are computed from. Writes known to touch single scenes
+// drop only theirs; others, such as a fixture's channels changing, drop
+// everything.
+func (c *Cache) Watch(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("scenesummary:invalidate", c.afterWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("scenesummary:invalidate", c.afterWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("scenesummary:invalidate", c.afterWrite); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("scenesummary:invalidate", c.afterRaw)
+}
+
+func (c *Cache) afterWrite(tx *gorm.DB) {
+	if tx.Error != nil || !isSourceTable(tx.Statement.Table) {
+		return
+	}
+	if ids := sceneIDs(tx.Statement); len(ids) > 0 {
+		c.Invalidate(ids...)
+		return
+	}
+	c.Invalidate()
+}
+
+// afterRaw invalidates everything after raw SQL naming a source table.
+func (c *Cache) afterRaw(tx *gorm.DB) {
+	if tx.Error != nil {
+		return
+	}
+	sql := strings.ToLower(tx.Statement.SQL.String())
+	for _, table := range sourceTables {
+		if strings.Contains(sql, table) {
+			c.Invalidate()
+			return
+		}
+	}
+}
+
+func isSourceTable(table string) bool {
+	for _, t := range sourceTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// sceneIDs returns the scenes a write changes when its records say which,
+// or nil when it may change any.
+func sceneIDs(stmt *gorm.Statement) []string {
+	ids := recordSceneIDs(stmt.Dest)
+	if ids == nil {
+		// Updates from a map name the record in the model
+		ids = recordSceneIDs(stmt.Model)
+	}
+	for _, id := range ids {
+		if id == "" {
+			// Written by condition rather than by record
+			return nil
+		}
+	}
+	return ids
+}
+
+// recordSceneIDs returns the scenes of the records a write is given, or nil
+// for other values.
+func recordSceneIDs(value any) []string {
+	var ids []string
+	switch records := value.(type) {
+	case *models.Scene:
+		ids = append(ids, records.ID)
+	case *models.FixtureValue:
+		ids = append(ids, records.SceneID)
+	case []models.FixtureValue:
+		for _, v := range records {
+			ids = append(ids, v.SceneID)
+		}
+	case []*models.FixtureValue:
+		for _, v := range records {
+			ids = append(ids, v.SceneID)
+		}
+	case *models.GroupValue:
+		ids = append(ids, records.SceneID)
+	case []models.GroupValue:
+		for _, v := range records {
+			ids = append(ids, v.SceneID)
+		}
+	}
+	return ids
+}
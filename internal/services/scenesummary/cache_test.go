@@ -0,0 +1,95 @@
+package scenesummary
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache()
+	_, generation, ok := cache.Get("scene-1")
+	if ok {
+		t.Fatal("Get() on an empty cache should miss")
+	}
+	cache.Put("scene-1", generation, Summary{Intensity: 1})
+	cache.Put("scene-2", generation, Summary{Intensity: 0.5})
+	if summary, _, ok := cache.Get("scene-1"); !ok || summary.Intensity != 1 {
+		t.Errorf("Get(scene-1) = %+v, %v", summary, ok)
+	}
+
+	cache.Invalidate("scene-1")
+	if _, _, ok := cache.Get("scene-1"); ok {
+		t.Error("scene-1 should be invalidated")
+	}
+	if _, _, ok := cache.Get("scene-2"); !ok {
+		t.Error("scene-2 should still be cached")
+	}
+
+	// A summary computed before an invalidation is not stored
+	cache.Put("scene-1", generation, Summary{Intensity: 1})
+	if _, _, ok := cache.Get("scene-1"); ok {
+		t.Error("A stale summary should not be stored")
+	}
+
+	cache.Invalidate()
+	if _, _, ok := cache.Get("scene-2"); ok {
+		t.Error("Invalidate() should drop every summary")
+	}
+}
+
+func TestCacheWatch(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Scene{}, &models.FixtureValue{}, &models.Setting{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	cache := NewCache()
+	if err := cache.Watch(db); err != nil {
+		t.Fatalf("Watch() error: %v", err)
+	}
+	fill := func() {
+		for _, id := range []string{"scene-1", "scene-2"} {
+			_, generation, _ := cache.Get(id)
+			cache.Put(id, generation, Summary{})
+		}
+	}
+	cached := func(id string) bool {
+		_, _, ok := cache.Get(id)
+		return ok
+	}
+
+	// A fixture value drops its scene's summary only
+	fill()
+	db.Create(&models.FixtureValue{ID: "v-1", SceneID: "scene-1", FixtureID: "f-1", Channels: "[]"})
+	if cached("scene-1") || !cached("scene-2") {
+		t.Errorf("After creating a value of scene-1: scene-1 cached %v, scene-2 cached %v", cached("scene-1"), cached("scene-2"))
+	}
+
+	// A write by condition drops everything
+	fill()
+	db.Model(&models.FixtureValue{}).Where("fixture_id = ?", "f-1").Update("channels", `[{"offset":0,"value":255}]`)
+	if cached("scene-1") || cached("scene-2") {
+		t.Error("An update by condition should drop every summary")
+	}
+
+	// So does raw SQL on a source table
+	fill()
+	db.Exec("DELETE FROM fixture_values WHERE fixture_id = ?", "f-1")
+	if cached("scene-2") {
+		t.Error("Raw SQL on fixture_values should drop every summary")
+	}
+
+	// Other tables are not watched
+	fill()
+	db.Create(&models.Setting{ID: "s-1", Key: "theme", Value: "dark"})
+	if !cached("scene-1") || !cached("scene-2") {
+		t.Error("A setting write should not drop summaries")
+	}
+}
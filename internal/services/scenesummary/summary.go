@@ -0,0 +1,185 @@
+// Package scenesummary computes the look of a scene in brief: how bright it
+// is, the colors that dominate it, and the color of each fixture group, so
+// scene lists can draw chips without every channel value. Summaries are
+// cached until a write to the tables they are computed from.
+package scenesummary
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// MaxColors bounds the dominant colors of a summary.
+const MaxColors = 3
+
+// colorSteps is how finely colors are told apart when finding the dominant
+// ones: components are rounded to quarters.
+const colorSteps = 4
+
+// Fixture is a fixture's channels and its values in a scene.
+type Fixture struct {
+	ID       string
+	Channels []color.Channel
+	Values   []color.Value
+}
+
+// Group is a fixture group.
+type Group struct {
+	ID         string
+	Name       string
+	FixtureIDs []string
+}
+
+// GroupSummary is the look of a group's fixtures in a scene. GroupID and
+// Name are empty for the fixtures in no group.
+type GroupSummary struct {
+	GroupID string
+	Name    string
+	// Color is the mean color of the lit fixtures as #RRGGBB, empty when
+	// none is lit
+	Color string
+	// Intensity is the mean brightness of the fixtures (0-1)
+	Intensity    float64
+	FixtureCount int
+}
+
+// Summary is the look of a scene.
+type Summary struct {
+	// Intensity is the mean brightness of the scene's fixtures (0-1)
+	Intensity float64
+	// Colors are the dominant colors as #RRGGBB, the most light first
+	Colors []string
+	// Groups are the groups with fixtures in the scene, in the order given,
+	// then the fixtures in no group
+	Groups []GroupSummary
+}
+
+// light is the color and brightness a fixture shows.
+type light struct {
+	color      color.Color
+	brightness float64
+}
+
+// mix accumulates lights into a mean color weighted by brightness, and a
+// mean brightness.
+type mix struct {
+	r, g, b    float64
+	brightness float64
+	count      int
+}
+
+func (m *mix) add(l light) {
+	m.r += l.color.R * l.brightness
+	m.g += l.color.G * l.brightness
+	m.b += l.color.B * l.brightness
+	m.brightness += l.brightness
+	m.count++
+}
+
+// color is the mean color of the lit fixtures, or "" when none is lit.
+func (m *mix) color() string {
+	if m.brightness == 0 {
+		return ""
+	}
+	return color.Color{R: m.r / m.brightness, G: m.g / m.brightness, B: m.b / m.brightness}.Hex()
+}
+
+func (m *mix) intensity() float64 {
+	if m.count == 0 {
+		return 0
+	}
+	return m.brightness / float64(m.count)
+}
+
+// Summarize computes the look of a scene from its fixtures' values. Fixtures
+// whose values set no intensity or color channel, such as a mover with only
+// its position stored, are left out. A fixture in several groups counts in
+// each.
+func Summarize(fixtures []Fixture, groups []Group) Summary {
+	lights := make(map[string]light, len(fixtures))
+	var order []string
+	for _, f := range fixtures {
+		c, brightness, ok := color.FromValues(f.Channels, f.Values)
+		if !ok {
+			continue
+		}
+		if _, seen := lights[f.ID]; !seen {
+			order = append(order, f.ID)
+		}
+		lights[f.ID] = light{color: c, brightness: brightness}
+	}
+
+	summary := Summary{Colors: []string{}, Groups: []GroupSummary{}}
+	var total mix
+	buckets := make(map[[3]int]*mix)
+	for _, id := range order {
+		l := lights[id]
+		total.add(l)
+		if l.brightness == 0 {
+			continue
+		}
+		key := [3]int{step(l.color.R), step(l.color.G), step(l.color.B)}
+		if buckets[key] == nil {
+			buckets[key] = &mix{}
+		}
+		buckets[key].add(l)
+	}
+	summary.Intensity = total.intensity()
+	summary.Colors = dominant(buckets)
+
+	grouped := make(map[string]bool)
+	for _, g := range groups {
+		var m mix
+		for _, id := range g.FixtureIDs {
+			if l, ok := lights[id]; ok {
+				m.add(l)
+				grouped[id] = true
+			}
+		}
+		if m.count > 0 {
+			summary.Groups = append(summary.Groups, groupSummary(g.ID, g.Name, &m))
+		}
+	}
+	var ungrouped mix
+	for _, id := range order {
+		if !grouped[id] {
+			ungrouped.add(lights[id])
+		}
+	}
+	if ungrouped.count > 0 {
+		summary.Groups = append(summary.Groups, groupSummary("", "", &ungrouped))
+	}
+	return summary
+}
+
+func groupSummary(id, name string, m *mix) GroupSummary {
+	return GroupSummary{GroupID: id, Name: name, Color: m.color(), Intensity: m.intensity(), FixtureCount: m.count}
+}
+
+// dominant returns the mean colors of the buckets holding the most light,
+// up to MaxColors.
+func dominant(buckets map[[3]int]*mix) []string {
+	mixes := make([]*mix, 0, len(buckets))
+	for _, m := range buckets {
+		mixes = append(mixes, m)
+	}
+	sort.Slice(mixes, func(i, j int) bool {
+		if mixes[i].brightness != mixes[j].brightness {
+			return mixes[i].brightness > mixes[j].brightness
+		}
+		// Break ties by color so the order is stable
+		return mixes[i].color() < mixes[j].color()
+	})
+	colors := []string{}
+	for i := 0; i < len(mixes) && i < MaxColors; i++ {
+		colors = append(colors, mixes[i].color())
+	}
+	return colors
+}
+
+// step rounds a color component (0-1) for bucketing.
+func step(v float64) int {
+	return int(math.Round(v * colorSteps))
+}
@@ -0,0 +1,73 @@
+package scenesummary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// rgbFixture is a dimmer and RGB fixture at an intensity and color.
+func rgbFixture(id string, intensity, r, g, b int) Fixture {
+	return Fixture{
+		ID: id,
+		Channels: []color.Channel{
+			{Offset: 0, Type: "INTENSITY"}, {Offset: 1, Type: "RED"}, {Offset: 2, Type: "GREEN"}, {Offset: 3, Type: "BLUE"},
+		},
+		Values: []color.Value{{Offset: 0, Value: intensity}, {Offset: 1, Value: r}, {Offset: 2, Value: g}, {Offset: 3, Value: b}},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	fixtures := []Fixture{
+		rgbFixture("wash-1", 255, 255, 0, 0),
+		rgbFixture("wash-2", 255, 255, 0, 0),
+		rgbFixture("wash-3", 0, 0, 0, 255),
+		rgbFixture("spot", 128, 0, 0, 255),
+		{
+			ID:       "mover",
+			Channels: []color.Channel{{Offset: 0, Type: "PAN"}},
+			Values:   []color.Value{{Offset: 0, Value: 128}},
+		},
+	}
+	groups := []Group{
+		{ID: "g-wash", Name: "Wash", FixtureIDs: []string{"wash-1", "wash-2", "wash-3"}},
+		{ID: "g-empty", Name: "Empty", FixtureIDs: []string{"mover"}},
+	}
+	summary := Summarize(fixtures, groups)
+
+	// The mover sets no light, so four fixtures count
+	want := (1 + 1 + 0 + 128.0/255) / 4
+	if math.Abs(summary.Intensity-want) > 1e-9 {
+		t.Errorf("Intensity = %v, want %v", summary.Intensity, want)
+	}
+	// Two bright reds outweigh a dim blue; the dark blue adds nothing
+	if len(summary.Colors) != 2 || summary.Colors[0] != "#FF0000" || summary.Colors[1] != "#0000FF" {
+		t.Errorf("Colors = %v, want red then blue", summary.Colors)
+	}
+
+	if len(summary.Groups) != 2 {
+		t.Fatalf("Groups = %+v, want the wash group and the ungrouped spot", summary.Groups)
+	}
+	wash := summary.Groups[0]
+	if wash.GroupID != "g-wash" || wash.Color != "#FF0000" || wash.FixtureCount != 3 || math.Abs(wash.Intensity-2.0/3) > 1e-9 {
+		t.Errorf("Wash = %+v", wash)
+	}
+	if rest := summary.Groups[1]; rest.GroupID != "" || rest.Color != "#0000FF" || rest.FixtureCount != 1 {
+		t.Errorf("Ungrouped = %+v", rest)
+	}
+}
+
+func TestSummarize_Dark(t *testing.T) {
+	summary := Summarize([]Fixture{rgbFixture("wash", 0, 255, 0, 0)}, nil)
+	if summary.Intensity != 0 || len(summary.Colors) != 0 {
+		t.Errorf("Summary = %+v, want dark", summary)
+	}
+	if len(summary.Groups) != 1 || summary.Groups[0].Color != "" {
+		t.Errorf("Groups = %+v, want one without a color", summary.Groups)
+	}
+
+	if empty := Summarize(nil, nil); empty.Colors == nil || empty.Groups == nil {
+		t.Errorf("Summarize(nil) = %+v, want empty lists", empty)
+	}
+}
@@ -0,0 +1,385 @@
+// Package scheduler activates scenes and goes in cue lists at set times,
+// for installations that run unattended. A rule fires on a cron expression,
+// or at sunrise or sunset at a latitude and longitude. Times are in server
+// local time.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TriggerType is what sets the times a rule fires.
+type TriggerType string
+
+const (
+	// TriggerCron fires on a cron expression.
+	TriggerCron TriggerType = "CRON"
+	// TriggerSunrise fires at sunrise, plus the rule's offset.
+	TriggerSunrise TriggerType = "SUNRISE"
+	// TriggerSunset fires at sunset, plus the rule's offset.
+	TriggerSunset TriggerType = "SUNSET"
+)
+
+// ActionType is what a rule does when it fires.
+type ActionType string
+
+const (
+	// ActionActivateScene fades to a scene.
+	ActionActivateScene ActionType = "ACTIVATE_SCENE"
+	// ActionCueListGo goes to the next cue of a cue list, starting it if it
+	// is stopped.
+	ActionCueListGo ActionType = "CUE_LIST_GO"
+)
+
+const (
+	// maxCheck bounds how long rules go unchecked, so wall clock corrections
+	// (e.g. NTP sync after boot) are picked up.
+	maxCheck = time.Minute
+	// lateLimit is how late a rule may still fire. Rules further behind, as
+	// after the clock jumps forward, are skipped rather than fired.
+	lateLimit = time.Minute
+	// maxOffset bounds a sunrise or sunset offset.
+	maxOffset = 12 * 60
+	// maxSunSearch is how many days ahead a sunrise or sunset is looked for,
+	// which covers a polar winter.
+	maxSunSearch = 366
+)
+
+// Rule is a schedule: when it fires and what it does.
+type Rule struct {
+	ID        string
+	ProjectID string
+	Name      string
+
+	Trigger TriggerType
+	// CRON
+	Cron string
+	// SUNRISE and SUNSET: where, in degrees, and minutes after the event
+	// (negative for before)
+	Latitude      float64
+	Longitude     float64
+	OffsetMinutes int
+	// SUNRISE and SUNSET: days the rule fires on; empty for every day
+	Days []time.Weekday
+
+	Action    ActionType
+	SceneID   string
+	CueListID string
+	// Seconds, overriding the usual fade time (optional)
+	FadeTime *float64
+
+	cron *Cron
+}
+
+// Validate checks that a rule has what its trigger and action need.
+func (r *Rule) Validate() error {
+	switch r.Trigger {
+	case TriggerCron:
+		cron, err := ParseCron(r.Cron)
+		if err != nil {
+			return err
+		}
+		r.cron = cron
+	case TriggerSunrise, TriggerSunset:
+		if r.Latitude < -90 || r.Latitude > 90 {
+			return fmt.Errorf("latitude must be between -90 and 90")
+		}
+		if r.Longitude < -180 || r.Longitude > 180 {
+			return fmt.Errorf("longitude must be between -180 and 180")
+		}
+		if r.OffsetMinutes < -maxOffset || r.OffsetMinutes > maxOffset {
+			return fmt.Errorf("offset must be within %d minutes", maxOffset)
+		}
+	default:
+		return fmt.Errorf("unknown trigger type %q", r.Trigger)
+	}
+
+	if r.FadeTime != nil && *r.FadeTime < 0 {
+		return fmt.Errorf("fade time cannot be negative")
+	}
+	switch r.Action {
+	case ActionActivateScene:
+		if r.SceneID == "" {
+			return fmt.Errorf("%s requires a scene ID", r.Action)
+		}
+	case ActionCueListGo:
+		if r.CueListID == "" {
+			return fmt.Errorf("%s requires a cue list ID", r.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", r.Action)
+	}
+	return nil
+}
+
+// Next returns the first time after t the rule fires, or false if it never
+// does. The rule must be valid.
+func (r *Rule) Next(t time.Time) (time.Time, bool) {
+	if r.Trigger == TriggerCron {
+		if r.cron == nil {
+			return time.Time{}, false
+		}
+		return r.cron.Next(t)
+	}
+
+	offset := time.Duration(r.OffsetMinutes) * time.Minute
+	year, month, day := t.Date()
+	// Start the day before, since an offset can carry an event past midnight
+	for i := -1; i <= maxSunSearch; i++ {
+		date := time.Date(year, month, day+i, 12, 0, 0, 0, t.Location())
+		sunrise, sunset, ok := SunTimes(date, r.Latitude, r.Longitude)
+		if !ok {
+			continue
+		}
+		event := sunrise
+		if r.Trigger == TriggerSunset {
+			event = sunset
+		}
+		fireAt := event.Add(offset)
+		if fireAt.After(t) && r.firesOn(fireAt.Weekday()) {
+			return fireAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) firesOn(day time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, d := range r.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a rule firing.
+type Event struct {
+	Rule    Rule
+	FiredAt time.Time
+	// Error is set when the action failed
+	Error error
+}
+
+// Handlers carry out rule actions.
+type Handlers struct {
+	ActivateScene func(ctx context.Context, sceneID string, fadeTime *float64) error
+	CueListGo     func(ctx context.Context, cueListID string, fadeTime *float64) error
+}
+
+// scheduled is a rule and the next time it fires.
+type scheduled struct {
+	rule   Rule
+	next   time.Time
+	active bool // next is set
+}
+
+// Service fires rules at their times. It is safe for concurrent use.
+type Service struct {
+	mu       sync.Mutex
+	rules    map[string]*scheduled
+	timer    *time.Timer
+	handlers Handlers
+
+	// Called with each rule fired (optional)
+	onFire func(event *Event)
+
+	now func() time.Time
+}
+
+// NewService creates a scheduler with no rules.
+func NewService() *Service {
+	return &Service{
+		rules: make(map[string]*scheduled),
+		now:   time.Now,
+	}
+}
+
+// SetHandlers sets the handlers actions are carried out with.
+func (s *Service) SetHandlers(handlers Handlers) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = handlers
+}
+
+// SetFireCallback sets the callback for rules firing.
+func (s *Service) SetFireCallback(callback func(event *Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onFire = callback
+}
+
+// SetRules replaces every rule. Invalid rules are logged and left out.
+func (s *Service) SetRules(rules []Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = make(map[string]*scheduled, len(rules))
+	now := s.now()
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			log.Warn("schedule is invalid", "schedule", rule.ID, "error", err)
+			continue
+		}
+		s.putLocked(rule, now)
+	}
+	s.armLocked()
+}
+
+// Put validates and adds a rule, replacing any with the same ID.
+func (s *Service) Put(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putLocked(rule, s.now())
+	s.armLocked()
+	return nil
+}
+
+// Remove removes a rule, if there is one with the ID.
+func (s *Service) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rules, id)
+	s.armLocked()
+}
+
+// NextFire returns when a rule fires next, or false if it is not scheduled
+// or never fires.
+func (s *Service) NextFire(id string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.rules[id]
+	if !ok || !entry.active {
+		return time.Time{}, false
+	}
+	return entry.next, true
+}
+
+// Fire carries out a rule's action now, whatever its schedule.
+func (s *Service) Fire(ctx context.Context, rule Rule) *Event {
+	s.mu.Lock()
+	handlers := s.handlers
+	s.mu.Unlock()
+
+	event := &Event{Rule: rule, FiredAt: s.now()}
+	event.Error = run(ctx, rule, handlers)
+	s.emit(event)
+	return event
+}
+
+// Cleanup stops firing rules.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.rules = make(map[string]*scheduled)
+}
+
+func (s *Service) putLocked(rule Rule, now time.Time) {
+	entry := &scheduled{rule: rule}
+	entry.next, entry.active = rule.Next(now)
+	s.rules[rule.ID] = entry
+}
+
+// check fires the rules due, in time order, and schedules their next times.
+func (s *Service) check() {
+	s.mu.Lock()
+	now := s.now()
+	var due []*scheduled
+	for _, entry := range s.rules {
+		if entry.active && !entry.next.After(now) {
+			due = append(due, entry)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].next.Equal(due[j].next) {
+			return due[i].next.Before(due[j].next)
+		}
+		return due[i].rule.ID < due[j].rule.ID
+	})
+
+	var fire []Rule
+	for _, entry := range due {
+		if late := now.Sub(entry.next); late > lateLimit {
+			log.Warn("skipping late schedule", "schedule", entry.rule.Name, "late", late.Round(time.Second))
+		} else {
+			fire = append(fire, entry.rule)
+		}
+	}
+	// Every rule is rescheduled from now, which also catches the clock going
+	// back
+	for _, entry := range s.rules {
+		entry.next, entry.active = entry.rule.Next(now)
+	}
+	s.armLocked()
+	s.mu.Unlock()
+
+	for _, rule := range fire {
+		log.Info("⏰ Schedule fired", "schedule", rule.Name, "action", rule.Action)
+		s.Fire(context.Background(), rule)
+	}
+}
+
+// armLocked sets the timer for the next check.
+func (s *Service) armLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if len(s.rules) == 0 {
+		return
+	}
+
+	now := s.now()
+	wait := maxCheck
+	for _, entry := range s.rules {
+		if entry.active && entry.next.Sub(now) < wait {
+			wait = entry.next.Sub(now)
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	s.timer = time.AfterFunc(wait, s.check)
+}
+
+// run carries out a rule's action.
+func run(ctx context.Context, rule Rule, handlers Handlers) error {
+	var err error
+	switch rule.Action {
+	case ActionActivateScene:
+		if handlers.ActivateScene != nil {
+			err = handlers.ActivateScene(ctx, rule.SceneID, rule.FadeTime)
+		}
+	case ActionCueListGo:
+		if handlers.CueListGo != nil {
+			err = handlers.CueListGo(ctx, rule.CueListID, rule.FadeTime)
+		}
+	default:
+		err = fmt.Errorf("unknown action type %q", rule.Action)
+	}
+	if err != nil {
+		log.Warn("schedule failed", "schedule", rule.Name, "action", rule.Action, "error", err)
+	}
+	return err
+}
+
+func (s *Service) emit(event *Event) {
+	s.mu.Lock()
+	callback := s.onFire
+	s.mu.Unlock()
+	if callback != nil {
+		callback(event)
+	}
+}
@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	// Monday 2026-03-02 10:07
+	from := time.Date(2026, 3, 2, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC)},
+		{"0 19 * * *", time.Date(2026, 3, 2, 19, 0, 0, 0, time.UTC)},
+		{"30 8 * * MON-FRI", time.Date(2026, 3, 3, 8, 30, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2026, 3, 2, 10, 25, 0, 0, time.UTC)},
+		// Day of month or day of week, as in cron
+		{"0 0 15 * FRI", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		got, ok := cron.Next(from)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, %v; want %v", tt.expr, got, ok, tt.want)
+		}
+	}
+
+	never, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+	if _, ok := never.Next(from); ok {
+		t.Error("Expected February 31st never to match")
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * FOO *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("Expected ParseCron(%q) to fail", expr)
+		}
+	}
+}
+
+func TestSunTimes(t *testing.T) {
+	// New York on the June solstice: sunrise 5:25, sunset 20:31 EDT
+	edt := time.FixedZone("EDT", -4*60*60)
+	sunrise, sunset, ok := SunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, edt), 40.7128, -74.0060)
+	if !ok {
+		t.Fatal("Expected the sun to rise and set")
+	}
+	assertNear(t, "sunrise", sunrise, time.Date(2024, 6, 21, 5, 25, 0, 0, edt))
+	assertNear(t, "sunset", sunset, time.Date(2024, 6, 21, 20, 31, 0, 0, edt))
+
+	// Sydney in winter, where the UTC date of sunrise is the day before
+	aest := time.FixedZone("AEST", 10*60*60)
+	sunrise, sunset, ok = SunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, aest), -33.8688, 151.2093)
+	if !ok {
+		t.Fatal("Expected the sun to rise and set")
+	}
+	assertNear(t, "sunrise", sunrise, time.Date(2024, 6, 21, 7, 0, 0, 0, aest))
+	assertNear(t, "sunset", sunset, time.Date(2024, 6, 21, 16, 54, 0, 0, aest))
+
+	// Tromsø has no sunset in midsummer
+	if _, _, ok := SunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC), 69.6492, 18.9553); ok {
+		t.Error("Expected no sunset during the midnight sun")
+	}
+}
+
+func assertNear(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff < -3*time.Minute || diff > 3*time.Minute {
+		t.Errorf("Expected %s near %v, got %v", name, want, got)
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	fade := -1.0
+	tests := map[string]Rule{
+		"unknown trigger":  {Trigger: "NOON", Action: ActionActivateScene, SceneID: "s"},
+		"bad cron":         {Trigger: TriggerCron, Cron: "every day", Action: ActionActivateScene, SceneID: "s"},
+		"bad latitude":     {Trigger: TriggerSunset, Latitude: 91, Action: ActionActivateScene, SceneID: "s"},
+		"bad offset":       {Trigger: TriggerSunset, OffsetMinutes: 13 * 60, Action: ActionActivateScene, SceneID: "s"},
+		"no scene":         {Trigger: TriggerCron, Cron: "@daily", Action: ActionActivateScene},
+		"no cue list":      {Trigger: TriggerCron, Cron: "@daily", Action: ActionCueListGo},
+		"unknown action":   {Trigger: TriggerCron, Cron: "@daily", Action: "BLACKOUT"},
+		"negative fade":    {Trigger: TriggerCron, Cron: "@daily", Action: ActionActivateScene, SceneID: "s", FadeTime: &fade},
+		"bad longitude":    {Trigger: TriggerSunrise, Longitude: -181, Action: ActionCueListGo, CueListID: "c"},
+		"empty expression": {Trigger: TriggerCron, Action: ActionCueListGo, CueListID: "c"},
+	}
+	for name, rule := range tests {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRule_NextSunset(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*60*60)
+	rule := Rule{
+		Trigger:       TriggerSunset,
+		Latitude:      40.7128,
+		Longitude:     -74.0060,
+		OffsetMinutes: -30,
+		Days:          []time.Weekday{time.Saturday},
+		Action:        ActionActivateScene,
+		SceneID:       "s",
+	}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	// Friday 2024-06-21; the next Saturday is the 22nd, half an hour before
+	// its 20:31 sunset
+	next, ok := rule.Next(time.Date(2024, 6, 21, 21, 0, 0, 0, edt))
+	if !ok {
+		t.Fatal("Expected a next sunset")
+	}
+	assertNear(t, "next fire", next, time.Date(2024, 6, 22, 20, 1, 0, 0, edt))
+
+	// Later on the Saturday, the next is a week on
+	next, _ = rule.Next(next)
+	assertNear(t, "following fire", next, time.Date(2024, 6, 29, 20, 1, 0, 0, edt))
+}
+
+func TestService_FiresDueRules(t *testing.T) {
+	s := NewService()
+	defer s.Cleanup()
+
+	var mu sync.Mutex
+	clock := time.Date(2026, 3, 2, 18, 59, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	}
+	setClock := func(t time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		clock = t
+	}
+
+	var activated, cueListGos []string
+	s.SetHandlers(Handlers{
+		ActivateScene: func(ctx context.Context, sceneID string, fadeTime *float64) error {
+			activated = append(activated, sceneID)
+			return nil
+		},
+		CueListGo: func(ctx context.Context, cueListID string, fadeTime *float64) error {
+			cueListGos = append(cueListGos, cueListID)
+			return errors.New("cue list not found")
+		},
+	})
+	var events []*Event
+	s.SetFireCallback(func(event *Event) { events = append(events, event) })
+
+	s.SetRules([]Rule{
+		{ID: "evening", Name: "Evening", Trigger: TriggerCron, Cron: "0 19 * * *", Action: ActionActivateScene, SceneID: "warm"},
+		{ID: "late", Name: "Late", Trigger: TriggerCron, Cron: "30 19 * * *", Action: ActionCueListGo, CueListID: "show"},
+		{ID: "broken", Trigger: TriggerCron, Cron: "nonsense", Action: ActionActivateScene, SceneID: "x"},
+	})
+	if _, ok := s.NextFire("broken"); ok {
+		t.Error("Expected the invalid rule to be left out")
+	}
+	next, ok := s.NextFire("evening")
+	if !ok || !next.Equal(time.Date(2026, 3, 2, 19, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the evening rule at 19:00, got %v", next)
+	}
+
+	// Not yet due
+	s.check()
+	if len(events) != 0 {
+		t.Fatalf("Expected nothing to fire before 19:00, got %d events", len(events))
+	}
+
+	setClock(time.Date(2026, 3, 2, 19, 0, 0, 100, time.UTC))
+	s.check()
+	if len(activated) != 1 || activated[0] != "warm" || len(cueListGos) != 0 {
+		t.Fatalf("Expected the evening scene to activate, got %v and %v", activated, cueListGos)
+	}
+	if len(events) != 1 || events[0].Rule.ID != "evening" || events[0].Error != nil {
+		t.Errorf("Expected an event for the evening rule, got %+v", events)
+	}
+	next, _ = s.NextFire("evening")
+	if !next.Equal(time.Date(2026, 3, 3, 19, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the evening rule again tomorrow, got %v", next)
+	}
+
+	// A failing action is reported in its event
+	setClock(time.Date(2026, 3, 2, 19, 30, 20, 0, time.UTC))
+	s.check()
+	if len(events) != 2 || events[1].Error == nil {
+		t.Errorf("Expected a failed event for the late rule, got %+v", events)
+	}
+
+	// Rules far behind, as after a clock jump, are skipped
+	setClock(time.Date(2026, 3, 3, 22, 0, 0, 0, time.UTC))
+	s.check()
+	if len(events) != 2 {
+		t.Errorf("Expected late rules to be skipped, got %d events", len(events))
+	}
+
+	s.Remove("evening")
+	if _, ok := s.NextFire("evening"); ok {
+		t.Error("Expected the removed rule not to be scheduled")
+	}
+	if err := s.Put(Rule{ID: "bad", Trigger: TriggerCron, Cron: "@daily", Action: ActionCueListGo}); err == nil {
+		t.Error("Expected Put to reject an invalid rule")
+	}
+}
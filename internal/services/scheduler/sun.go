@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+// sunZenith is the zenith angle of the sun at sunrise and sunset, in
+// degrees: the sun's upper edge on the horizon, allowing for refraction.
+const sunZenith = 90.833
+
+// SunTimes returns the sunrise and sunset of the day of date in date's
+// location, at a latitude and longitude in degrees (north and east
+// positive). ok is false on days the sun does not rise or set, as in polar
+// summer and winter. Times are accurate to a minute or two, using the
+// algorithm of the Almanac for Computers.
+func SunTimes(date time.Time, latitude, longitude float64) (sunrise, sunset time.Time, ok bool) {
+	sunrise, okRise := sunEvent(date, latitude, longitude, true)
+	sunset, okSet := sunEvent(date, latitude, longitude, false)
+	return sunrise, sunset, okRise && okSet
+}
+
+// sunEvent returns the sunrise, or the sunset, of the day of date.
+func sunEvent(date time.Time, latitude, longitude float64, rising bool) (time.Time, bool) {
+	year, month, day := date.Date()
+	loc := date.Location()
+
+	lngHour := longitude / 15
+	approx := 18.0
+	if rising {
+		approx = 6
+	}
+	t := float64(date.YearDay()) + (approx-lngHour)/24
+
+	// The sun's mean anomaly and true longitude
+	m := 0.9856*t - 3.289
+	l := normalizeDegrees(m + 1.916*sinDeg(m) + 0.020*sinDeg(2*m) + 282.634)
+
+	// Right ascension, in the same quadrant as the longitude, in hours
+	ra := normalizeDegrees(degrees(math.Atan(0.91764 * tanDeg(l))))
+	ra += math.Floor(l/90)*90 - math.Floor(ra/90)*90
+	ra /= 15
+
+	sinDec := 0.39782 * sinDeg(l)
+	cosDec := math.Cos(math.Asin(sinDec))
+
+	cosH := (cosDeg(sunZenith) - sinDec*sinDeg(latitude)) / (cosDec * cosDeg(latitude))
+	if cosH > 1 || cosH < -1 {
+		return time.Time{}, false
+	}
+	h := degrees(math.Acos(cosH))
+	if rising {
+		h = 360 - h
+	}
+	h /= 15
+
+	localMean := h + ra - 0.06571*t - 6.622
+	ut := math.Mod(localMean-lngHour, 24)
+	if ut < 0 {
+		ut += 24
+	}
+
+	// The hour is UTC on the local date; shift a day when that lands the
+	// event on the day before or after in the location
+	event := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(time.Duration(ut * float64(time.Hour))).In(loc)
+	local := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	switch {
+	case event.Before(local):
+		event = event.Add(24 * time.Hour)
+	case !event.Before(local.AddDate(0, 0, 1)):
+		event = event.Add(-24 * time.Hour)
+	}
+	return event.Truncate(time.Second), true
+}
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func sinDeg(deg float64) float64 { return math.Sin(radians(deg)) }
+
+func cosDeg(deg float64) float64 { return math.Cos(radians(deg)) }
+
+func tanDeg(deg float64) float64 { return math.Tan(radians(deg)) }
+
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,272 @@
+// Package schemainfo publishes the GraphQL schema for CI tooling and tracks
+// which deprecated schema elements clients still use.
+package schemainfo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// SDLPath is the HTTP path where the server publishes its schema.
+const SDLPath = "/graphql/schema.graphql"
+
+// defaultDeprecationReason is the reason the GraphQL spec gives @deprecated
+// when none is specified.
+const defaultDeprecationReason = "No longer supported"
+
+// Deprecation is a deprecated schema element.
+type Deprecation struct {
+	// Coordinate names the element: Type.field, Type.field(arg:), or Enum.VALUE
+	Coordinate string
+	Reason     string
+}
+
+// Usage counts the operations that used a deprecated element.
+type Usage struct {
+	Deprecation
+	Count      int64
+	LastUsedAt *time.Time
+}
+
+type usage struct {
+	count      int64
+	lastUsedAt time.Time
+}
+
+// Service exposes the canonical schema SDL and its version, and counts
+// deprecated element usage. It is a gqlgen handler extension; register it
+// with the server's Use method.
+type Service struct {
+	sdl          string
+	version      string
+	deprecations map[string]Deprecation
+
+	mu    sync.Mutex
+	usage map[string]*usage
+
+	now func() time.Time
+}
+
+var (
+	_ graphql.HandlerExtension     = (*Service)(nil)
+	_ graphql.OperationInterceptor = (*Service)(nil)
+)
+
+// NewService builds the SDL, version, and deprecation list for a schema.
+func NewService(schema *ast.Schema) *Service {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(schema)
+	sdl := buf.String()
+	sum := sha256.Sum256([]byte(sdl))
+
+	return &Service{
+		sdl:          sdl,
+		version:      hex.EncodeToString(sum[:8]),
+		deprecations: collectDeprecations(schema),
+		usage:        make(map[string]*usage),
+		now:          time.Now,
+	}
+}
+
+// SDL returns the schema in canonical SDL form: types sorted by name,
+// built-in definitions omitted.
+func (s *Service) SDL() string {
+	return s.sdl
+}
+
+// Version returns a hash of the SDL that changes whenever the schema does.
+func (s *Service) Version() string {
+	return s.version
+}
+
+// ServeSDL serves the schema SDL, tagged with its version.
+func (s *Service) ServeSDL(w http.ResponseWriter, r *http.Request) {
+	etag := `"` + s.version + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Schema-Version", s.version)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(s.sdl)); err != nil {
+		log.Warn("failed to write schema SDL", "error", err)
+	}
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (s *Service) ExtensionName() string {
+	return "DeprecatedUsageTracker"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (s *Service) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation counts the deprecated elements an operation uses.
+func (s *Service) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	if len(s.deprecations) > 0 && graphql.HasOperationContext(ctx) {
+		if opCtx := graphql.GetOperationContext(ctx); opCtx.Operation != nil {
+			s.record(s.deprecatedIn(opCtx.Operation))
+		}
+	}
+	return next(ctx)
+}
+
+// Usage returns every deprecated element with its usage, most used first.
+// Unused elements are included with a zero count so they can be removed.
+func (s *Service) Usage() []Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Usage, 0, len(s.deprecations))
+	for coordinate, deprecation := range s.deprecations {
+		entry := Usage{Deprecation: deprecation}
+		if u, ok := s.usage[coordinate]; ok {
+			lastUsedAt := u.lastUsedAt
+			entry.Count = u.count
+			entry.LastUsedAt = &lastUsedAt
+		}
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Coordinate < result[j].Coordinate
+	})
+	return result
+}
+
+// Reset clears the usage counts.
+func (s *Service) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = make(map[string]*usage)
+}
+
+func (s *Service) record(coordinates map[string]bool) {
+	if len(coordinates) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for coordinate := range coordinates {
+		u, ok := s.usage[coordinate]
+		if !ok {
+			u = &usage{}
+			s.usage[coordinate] = u
+		}
+		u.count++
+		u.lastUsedAt = now
+	}
+}
+
+// deprecatedIn returns the deprecated elements a validated operation uses.
+// Enum values and input fields are only seen when written inline; values
+// passed through variables are not inspected.
+func (s *Service) deprecatedIn(op *ast.OperationDefinition) map[string]bool {
+	found := make(map[string]bool)
+	mark := func(coordinate string) {
+		if _, ok := s.deprecations[coordinate]; ok {
+			found[coordinate] = true
+		}
+	}
+
+	var visitValue func(value *ast.Value)
+	visitValue = func(value *ast.Value) {
+		if value == nil || value.Definition == nil {
+			return
+		}
+		switch value.Kind {
+		case ast.EnumValue:
+			mark(value.Definition.Name + "." + value.Raw)
+		case ast.ObjectValue:
+			for _, child := range value.Children {
+				mark(value.Definition.Name + "." + child.Name)
+				visitValue(child.Value)
+			}
+		case ast.ListValue:
+			for _, child := range value.Children {
+				visitValue(child.Value)
+			}
+		}
+	}
+
+	visitedFragments := make(map[string]bool)
+	var visit func(ast.SelectionSet)
+	visit = func(set ast.SelectionSet) {
+		for _, selection := range set {
+			switch sel := selection.(type) {
+			case *ast.Field:
+				if sel.ObjectDefinition != nil {
+					field := sel.ObjectDefinition.Name + "." + sel.Name
+					mark(field)
+					for _, arg := range sel.Arguments {
+						mark(field + "(" + arg.Name + ":)")
+						visitValue(arg.Value)
+					}
+				}
+				visit(sel.SelectionSet)
+			case *ast.InlineFragment:
+				visit(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				if sel.Definition != nil && !visitedFragments[sel.Name] {
+					visitedFragments[sel.Name] = true
+					visit(sel.Definition.SelectionSet)
+				}
+			}
+		}
+	}
+	visit(op.SelectionSet)
+	return found
+}
+
+// collectDeprecations lists the deprecated fields, arguments, input fields,
+// and enum values of the schema's own types.
+func collectDeprecations(schema *ast.Schema) map[string]Deprecation {
+	deprecations := make(map[string]Deprecation)
+	add := func(coordinate string, directives ast.DirectiveList) {
+		directive := directives.ForName("deprecated")
+		if directive == nil {
+			return
+		}
+		reason := defaultDeprecationReason
+		if arg := directive.Arguments.ForName("reason"); arg != nil && arg.Value != nil {
+			reason = arg.Value.Raw
+		}
+		deprecations[coordinate] = Deprecation{Coordinate: coordinate, Reason: reason}
+	}
+
+	for name, def := range schema.Types {
+		if def.BuiltIn {
+			continue
+		}
+		for _, field := range def.Fields {
+			coordinate := name + "." + field.Name
+			add(coordinate, field.Directives)
+			for _, arg := range field.Arguments {
+				add(coordinate+"("+arg.Name+":)", arg.Directives)
+			}
+		}
+		for _, value := range def.EnumValues {
+			add(name+"."+value.Name, value.Directives)
+		}
+	}
+	return deprecations
+}
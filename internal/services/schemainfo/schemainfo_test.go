@@ -0,0 +1,121 @@
+package schemainfo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSchema = `
+type Query {
+  fixtures(legacyFilter: String @deprecated(reason: "Use filter"), filter: Filter): [Fixture!]!
+  fixture(id: ID!): Fixture
+}
+
+type Fixture {
+  id: ID!
+  name: String!
+  channelValues: [Int!]! @deprecated(reason: "Use channels")
+  channels: [Int!]!
+  mode: Mode!
+}
+
+enum Mode {
+  BASIC
+  EXTENDED @deprecated
+}
+
+input Filter {
+  mode: Mode
+}
+`
+
+func loadSchema(t *testing.T, sdl string) *ast.Schema {
+	t.Helper()
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "test.graphql", Input: sdl})
+	if err != nil {
+		t.Fatalf("LoadSchema() error: %v", err)
+	}
+	return schema
+}
+
+func TestDeprecatedUsage(t *testing.T) {
+	schema := loadSchema(t, testSchema)
+	s := NewService(schema)
+
+	run := func(query string) {
+		t.Helper()
+		doc, err := gqlparser.LoadQuery(schema, query)
+		if err != nil {
+			t.Fatalf("LoadQuery() error: %v", err)
+		}
+		s.record(s.deprecatedIn(doc.Operations[0]))
+	}
+
+	run(`{ fixtures { id channels } }`)
+	run(`{ fixtures(legacyFilter: "x") { ...F } } fragment F on Fixture { channelValues name }`)
+	run(`{ a: fixture(id: "1") { channelValues } b: fixture(id: "2") { channelValues } }`)
+	run(`{ fixtures(filter: { mode: EXTENDED }) { id } }`)
+
+	counts := make(map[string]int64)
+	reasons := make(map[string]string)
+	for _, u := range s.Usage() {
+		counts[u.Coordinate] = u.Count
+		reasons[u.Coordinate] = u.Reason
+	}
+
+	want := map[string]int64{
+		"Fixture.channelValues":         2, // Counted once per operation
+		"Query.fixtures(legacyFilter:)": 1,
+		"Mode.EXTENDED":                 1,
+	}
+	if len(counts) != len(want) {
+		t.Errorf("Expected %d deprecated elements, got %v", len(want), counts)
+	}
+	for coordinate, count := range want {
+		if counts[coordinate] != count {
+			t.Errorf("%s count = %d, want %d", coordinate, counts[coordinate], count)
+		}
+	}
+	if reasons["Fixture.channelValues"] != "Use channels" || reasons["Mode.EXTENDED"] != defaultDeprecationReason {
+		t.Errorf("Unexpected reasons: %v", reasons)
+	}
+	if first := s.Usage()[0]; first.Coordinate != "Fixture.channelValues" || first.LastUsedAt == nil {
+		t.Errorf("Expected most used element first, got %+v", first)
+	}
+
+	s.Reset()
+	for _, u := range s.Usage() {
+		if u.Count != 0 || u.LastUsedAt != nil {
+			t.Errorf("Expected reset counts, got %+v", u)
+		}
+	}
+}
+
+func TestVersionAndServeSDL(t *testing.T) {
+	s := NewService(loadSchema(t, testSchema))
+	if again := NewService(loadSchema(t, testSchema)); again.Version() != s.Version() {
+		t.Error("Expected identical schemas to share a version")
+	}
+	changed := NewService(loadSchema(t, testSchema+"\nextend type Fixture { notes: String }\n"))
+	if changed.Version() == s.Version() {
+		t.Error("Expected a schema change to change the version")
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeSDL(rec, httptest.NewRequest(http.MethodGet, SDLPath, nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != s.SDL() || rec.Header().Get("X-Schema-Version") != s.Version() {
+		t.Errorf("Unexpected response: %d %q", rec.Code, rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, SDLPath, nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	s.ServeSDL(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for a matching ETag, got %d", rec.Code)
+	}
+}
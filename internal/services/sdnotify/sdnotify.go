@@ -0,0 +1,53 @@
+// Package sdnotify reports service state to systemd, for units with
+// Type=notify. Outside systemd it does nothing.
+package sdnotify
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// States sent to systemd.
+const (
+	// Ready tells systemd the service has started.
+	Ready = "READY=1"
+	// Stopping tells systemd the service is shutting down.
+	Stopping = "STOPPING=1"
+)
+
+// ExtendTimeout asks systemd to wait up to d longer for the service to
+// finish starting or stopping.
+func ExtendTimeout(d time.Duration) string {
+	return fmt.Sprintf("EXTEND_TIMEOUT_USEC=%d", d.Microseconds())
+}
+
+// Status is a free-form status shown by systemctl status.
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+// Notify sends states to the socket in NOTIFY_SOCKET. It reports false,
+// without an error, when the service was not started by systemd.
+func Notify(states ...string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	var message []byte
+	for _, state := range states {
+		message = append(message, state...)
+		message = append(message, '\n')
+	}
+	if _, err := conn.Write(message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
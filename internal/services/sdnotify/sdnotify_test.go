@@ -0,0 +1,37 @@
+package sdnotify
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Notify() outside systemd = %v, %v; want false, nil", sent, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	if sent, err := Notify(Stopping, ExtendTimeout(1500*time.Millisecond), Status("Fading out")); !sent || err != nil {
+		t.Fatalf("Notify() = %v, %v; want true, nil", sent, err)
+	}
+	buf := make([]byte, 256)
+	_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	want := "STOPPING=1\nEXTEND_TIMEOUT_USEC=1500000\nSTATUS=Fading out\n"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("Received %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+// Package selection orders fixture selections. Effects chase through their
+// fixtures in order, so the same fixtures reversed, split into odd and even,
+// or taken across the layout make different looks.
+package selection
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Order is a way of reordering a selection.
+type Order string
+
+// Orders a selection can be put in. The names match the GraphQL
+// SelectionOrder enum.
+const (
+	// Reverse turns the selection around
+	Reverse Order = "REVERSE"
+	// OddEven takes the odd positions (first, third, ...) then the even ones
+	OddEven Order = "ODD_EVEN"
+	// Interleave alternates from the ends inward: first, last, second,
+	// second-last, ...
+	Interleave Order = "INTERLEAVE"
+	// LeftToRight sorts by layout position across then down
+	LeftToRight Order = "LEFT_TO_RIGHT"
+	// TopToBottom sorts by layout position down then across
+	TopToBottom Order = "TOP_TO_BOTTOM"
+)
+
+// Item is a selected fixture and its layout position, if it has one.
+type Item struct {
+	ID string
+	X  *float64
+	Y  *float64
+}
+
+// Apply returns the items in an order, leaving items unchanged. Layout
+// orders keep fixtures without a position last, in their given order.
+func Apply(items []Item, order Order) ([]Item, error) {
+	ordered := make([]Item, 0, len(items))
+	switch order {
+	case Reverse:
+		for i := len(items) - 1; i >= 0; i-- {
+			ordered = append(ordered, items[i])
+		}
+	case OddEven:
+		for start := range 2 {
+			for i := start; i < len(items); i += 2 {
+				ordered = append(ordered, items[i])
+			}
+		}
+	case Interleave:
+		for i, j := 0, len(items)-1; i <= j; i, j = i+1, j-1 {
+			ordered = append(ordered, items[i])
+			if i != j {
+				ordered = append(ordered, items[j])
+			}
+		}
+	case LeftToRight:
+		ordered = append(ordered, items...)
+		sortByLayout(ordered, func(it Item) (float64, float64) { return *it.X, *it.Y })
+	case TopToBottom:
+		ordered = append(ordered, items...)
+		sortByLayout(ordered, func(it Item) (float64, float64) { return *it.Y, *it.X })
+	default:
+		return nil, fmt.Errorf("unknown selection order: %s", order)
+	}
+	return ordered, nil
+}
+
+// IDs returns the IDs of items in order.
+func IDs(items []Item) []string {
+	ids := make([]string, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	return ids
+}
+
+// sortByLayout stably sorts placed items by a primary then a secondary
+// coordinate, after which come the unplaced ones.
+func sortByLayout(items []Item, key func(Item) (float64, float64)) {
+	placed := func(it Item) bool { return it.X != nil && it.Y != nil }
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if !placed(a) || !placed(b) {
+			return placed(a) && !placed(b)
+		}
+		a1, a2 := key(a)
+		b1, b2 := key(b)
+		if a1 != b1 {
+			return a1 < b1
+		}
+		return a2 < b2
+	})
+}
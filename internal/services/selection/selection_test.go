@@ -0,0 +1,69 @@
+package selection
+
+import (
+	"slices"
+	"testing"
+)
+
+func ids(names ...string) []Item {
+	items := make([]Item, len(names))
+	for i, name := range names {
+		items[i] = Item{ID: name}
+	}
+	return items
+}
+
+func at(id string, x, y float64) Item {
+	return Item{ID: id, X: &x, Y: &y}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		order Order
+		want  []string
+	}{
+		{"reverse", ids("a", "b", "c"), Reverse, []string{"c", "b", "a"}},
+		{"odd even", ids("a", "b", "c", "d", "e"), OddEven, []string{"a", "c", "e", "b", "d"}},
+		{"interleave even count", ids("a", "b", "c", "d"), Interleave, []string{"a", "d", "b", "c"}},
+		{"interleave odd count", ids("a", "b", "c", "d", "e"), Interleave, []string{"a", "e", "b", "d", "c"}},
+		{"empty", nil, Interleave, []string{}},
+		{
+			"left to right",
+			[]Item{at("a", 0.5, 0.1), {ID: "unplaced"}, at("b", 0.1, 0.9), at("c", 0.1, 0.2)},
+			LeftToRight,
+			[]string{"c", "b", "a", "unplaced"},
+		},
+		{
+			"top to bottom",
+			[]Item{at("a", 0.5, 0.1), {ID: "unplaced"}, at("b", 0.1, 0.9), at("c", 0.2, 0.1)},
+			TopToBottom,
+			[]string{"c", "a", "b", "unplaced"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.items, tt.order)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !slices.Equal(IDs(got), tt.want) {
+				t.Errorf("Apply() = %v, want %v", IDs(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyLeavesInputAndRejectsUnknownOrders(t *testing.T) {
+	items := ids("a", "b")
+	if _, err := Apply(items, Reverse); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if items[0].ID != "a" {
+		t.Errorf("Apply() reordered its input")
+	}
+	if _, err := Apply(items, "SIDEWAYS"); err == nil {
+		t.Error("Apply() with an unknown order succeeded")
+	}
+}
@@ -0,0 +1,202 @@
+// Package settings describes the server's stored settings: the type,
+// default, and validation of each key, and the services told when one
+// changes.
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+)
+
+// Type is the type of a setting's value. Every value is stored as a string.
+type Type string
+
+// Setting types.
+const (
+	TypeString  Type = "STRING"
+	TypeInteger Type = "INTEGER"
+	TypeBoolean Type = "BOOLEAN"
+	TypeJSON    Type = "JSON"
+)
+
+// Definition describes a registered setting.
+type Definition struct {
+	Key  string
+	Type Type
+	// Value while the setting is not saved; empty when the server's
+	// configuration supplies it
+	Default     string
+	Description string
+	// Validate checks a value of the right type (optional)
+	Validate func(value string) error
+}
+
+// WatchFunc applies a setting after it changes. It reads the saved value
+// itself, so it also serves to apply the setting at startup.
+type WatchFunc func(ctx context.Context)
+
+// Service validates and saves registered settings, and calls their
+// watchers when they change.
+type Service struct {
+	repo *repositories.SettingRepository
+
+	mu          sync.RWMutex
+	definitions map[string]Definition
+	watchers    map[string][]WatchFunc
+}
+
+// NewService creates a settings service saving to repo.
+func NewService(repo *repositories.SettingRepository) *Service {
+	return &Service{
+		repo:        repo,
+		definitions: make(map[string]Definition),
+		watchers:    make(map[string][]WatchFunc),
+	}
+}
+
+// Register adds a setting. It panics if the key is already registered or
+// the type is unknown, as either is a programming error.
+func (s *Service) Register(def Definition) {
+	switch def.Type {
+	case TypeString, TypeInteger, TypeBoolean, TypeJSON:
+	default:
+		panic(fmt.Sprintf("settings: unknown type %q for %q", def.Type, def.Key))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.definitions[def.Key]; ok {
+		panic(fmt.Sprintf("settings: %q registered twice", def.Key))
+	}
+	s.definitions[def.Key] = def
+}
+
+// Lookup returns a registered setting's definition.
+func (s *Service) Lookup(key string) (Definition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	def, ok := s.definitions[key]
+	return def, ok
+}
+
+// Definitions returns every registered setting in key order.
+func (s *Service) Definitions() []Definition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	defs := make([]Definition, 0, len(s.definitions))
+	for _, def := range s.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// Watch calls fn after the setting changes.
+func (s *Service) Watch(key string, fn WatchFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[key] = append(s.watchers[key], fn)
+}
+
+// Notify calls the setting's watchers, for a setting saved other than by
+// Set, such as one received from a replication primary.
+func (s *Service) Notify(ctx context.Context, key string) {
+	s.mu.RLock()
+	watchers := append([]WatchFunc(nil), s.watchers[key]...)
+	s.mu.RUnlock()
+	for _, fn := range watchers {
+		fn(ctx)
+	}
+}
+
+// Validate checks a value for a registered setting and returns it in
+// canonical form: integers and booleans are trimmed and reformatted.
+func (s *Service) Validate(key, value string) (string, error) {
+	def, ok := s.Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+	switch def.Type {
+	case TypeInteger:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("setting %q must be an integer", key)
+		}
+		value = strconv.Itoa(n)
+	case TypeBoolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("setting %q must be true or false", key)
+		}
+		value = strconv.FormatBool(b)
+	case TypeJSON:
+		if !json.Valid([]byte(value)) {
+			return "", fmt.Errorf("setting %q must be JSON", key)
+		}
+	}
+	if def.Validate != nil {
+		if err := def.Validate(value); err != nil {
+			return "", fmt.Errorf("invalid setting %q: %w", key, err)
+		}
+	}
+	return value, nil
+}
+
+// Set validates and saves a registered setting, then calls its watchers.
+func (s *Service) Set(ctx context.Context, key, value string) (*models.Setting, error) {
+	value, err := s.Validate(key, value)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.repo.Upsert(ctx, key, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save setting %q: %w", key, err)
+	}
+	log.Info("setting changed", "key", key)
+	s.Notify(ctx, key)
+	return setting, nil
+}
+
+// Get returns a setting as saved, or with its default when a registered
+// setting is not saved. A default has no ID or timestamps. It returns nil
+// for an unknown, unsaved key.
+func (s *Service) Get(ctx context.Context, key string) (*models.Setting, error) {
+	setting, err := s.repo.FindByKey(ctx, key)
+	if err != nil || setting != nil {
+		return setting, err
+	}
+	if def, ok := s.Lookup(key); ok {
+		return &models.Setting{Key: key, Value: def.Default}, nil
+	}
+	return nil, nil
+}
+
+// All returns every registered setting, with its default when it is not
+// saved, and any other saved settings, in key order.
+func (s *Service) All(ctx context.Context) ([]models.Setting, error) {
+	saved, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.Setting, 0, len(saved))
+	seen := make(map[string]bool, len(saved))
+	for _, setting := range saved {
+		result = append(result, setting)
+		seen[setting.Key] = true
+	}
+	for _, def := range s.Definitions() {
+		if !seen[def.Key] {
+			result = append(result, models.Setting{Key: def.Key, Value: def.Default})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
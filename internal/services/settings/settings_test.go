@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+)
+
+func newTestService(t *testing.T) (*Service, *repositories.SettingRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Setting{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := repositories.NewSettingRepository(db)
+	return NewService(repo), repo
+}
+
+func TestService_Validate(t *testing.T) {
+	service, _ := newTestService(t)
+	service.Register(Definition{Key: "rate", Type: TypeInteger, Validate: func(value string) error {
+		if value == "0" {
+			return errors.New("must be positive")
+		}
+		return nil
+	}})
+	service.Register(Definition{Key: "sync", Type: TypeBoolean})
+	service.Register(Definition{Key: "routes", Type: TypeJSON})
+	service.Register(Definition{Key: "name", Type: TypeString})
+
+	for _, tt := range []struct{ key, value, want string }{
+		{"rate", " 044 ", "44"},
+		{"sync", "TRUE", "true"},
+		{"routes", `[{"universe": 1}]`, `[{"universe": 1}]`},
+		{"name", " Main ", " Main "},
+	} {
+		if got, err := service.Validate(tt.key, tt.value); err != nil || got != tt.want {
+			t.Errorf("Validate(%q, %q) = %q, %v; want %q", tt.key, tt.value, got, err, tt.want)
+		}
+	}
+	for _, tt := range []struct{ key, value string }{
+		{"rate", "fast"},
+		{"rate", "0"},
+		{"sync", "maybe"},
+		{"routes", "[1,"},
+		{"unknown", "1"},
+	} {
+		if _, err := service.Validate(tt.key, tt.value); err == nil {
+			t.Errorf("Validate(%q, %q) succeeded, want an error", tt.key, tt.value)
+		}
+	}
+}
+
+func TestService_SetAndWatch(t *testing.T) {
+	service, repo := newTestService(t)
+	service.Register(Definition{Key: "rate", Type: TypeInteger, Default: "60"})
+	ctx := context.Background()
+
+	var applied []string
+	service.Watch("rate", func(ctx context.Context) {
+		setting, _ := repo.FindByKey(ctx, "rate")
+		applied = append(applied, setting.Value)
+	})
+
+	// An unsaved setting has its default
+	setting, err := service.Get(ctx, "rate")
+	if err != nil || setting == nil || setting.Value != "60" || setting.ID != "" {
+		t.Fatalf("Get() = %+v, %v; want the default", setting, err)
+	}
+
+	if _, err := service.Set(ctx, "rate", "x"); err == nil {
+		t.Error("Expected an invalid value rejected")
+	}
+	setting, err = service.Set(ctx, "rate", "30")
+	if err != nil || setting.ID == "" || setting.Value != "30" {
+		t.Fatalf("Set() = %+v, %v", setting, err)
+	}
+	if len(applied) != 1 || applied[0] != "30" {
+		t.Errorf("Expected the watcher to apply 30 once, got %v", applied)
+	}
+
+	// Saved settings that are not registered are still listed
+	if _, err := repo.Upsert(ctx, "legacy", "1"); err != nil {
+		t.Fatal(err)
+	}
+	all, err := service.All(ctx)
+	if err != nil || len(all) != 2 || all[0].Key != "legacy" || all[1].Value != "30" {
+		t.Errorf("All() = %+v, %v", all, err)
+	}
+	if setting, err := service.Get(ctx, "missing"); setting != nil || err != nil {
+		t.Errorf("Get() of an unknown key = %+v, %v; want nil, nil", setting, err)
+	}
+}
+
+func TestService_RegisterTwicePanics(t *testing.T) {
+	service, _ := newTestService(t)
+	service.Register(Definition{Key: "rate", Type: TypeInteger})
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a duplicate key to panic")
+		}
+	}()
+	service.Register(Definition{Key: "rate", Type: TypeString})
+}
@@ -0,0 +1,428 @@
+// Package showtimer provides server-side stage-management timers.
+package showtimer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lucsky/cuid"
+)
+
+// Kind selects whether a timer counts down to zero or up from zero.
+type Kind string
+
+const (
+	// KindCountdown counts down from its duration and expires at zero.
+	KindCountdown Kind = "COUNTDOWN"
+	// KindStopwatch counts up from zero, e.g. a running show clock.
+	KindStopwatch Kind = "STOPWATCH"
+)
+
+// tickInterval is how often running timers publish updates to clients.
+const tickInterval = time.Second
+
+// Timer is a snapshot of a show timer's state.
+type Timer struct {
+	ID         string
+	Name       string
+	Kind       Kind
+	Duration   time.Duration // Countdown length including adjustments; zero for stopwatches
+	Elapsed    time.Duration
+	Remaining  time.Duration // Zero for stopwatches
+	IsRunning  bool
+	HasExpired bool
+
+	// Optional cue list to start when a countdown reaches zero
+	TriggerCueListID *string
+	TriggerCueNumber *float64
+
+	UpdatedAt time.Time
+}
+
+// CreateOptions configures a new timer.
+type CreateOptions struct {
+	Name             string
+	Kind             Kind
+	Duration         time.Duration
+	TriggerCueListID *string
+	TriggerCueNumber *float64
+}
+
+// timer holds the mutable state of a show timer.
+type timer struct {
+	id               string
+	name             string
+	kind             Kind
+	duration         time.Duration
+	adjustment       time.Duration // Time added to (or taken from) a countdown
+	accumulated      time.Duration // Elapsed time from previous runs
+	startedAt        *time.Time    // Set while running
+	expired          bool
+	triggerCueListID *string
+	triggerCueNumber *float64
+	updatedAt        time.Time
+
+	ticker      *time.Ticker
+	expiryTimer *time.Timer
+	stopTicking chan struct{}
+}
+
+// Service manages show timers. Timers live in memory so every connected
+// client observes the same server clock.
+type Service struct {
+	mu     sync.Mutex
+	timers map[string]*timer
+
+	// Called with a snapshot whenever a timer changes or ticks (optional)
+	onUpdate func(t *Timer)
+	// Called when a countdown reaches zero (optional)
+	onExpire func(t *Timer)
+
+	now func() time.Time
+}
+
+// NewService creates a new show timer service.
+func NewService() *Service {
+	return &Service{
+		timers: make(map[string]*timer),
+		now:    time.Now,
+	}
+}
+
+// SetUpdateCallback sets the callback for timer updates.
+func (s *Service) SetUpdateCallback(callback func(t *Timer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = callback
+}
+
+// SetExpireCallback sets the callback for countdowns reaching zero.
+func (s *Service) SetExpireCallback(callback func(t *Timer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onExpire = callback
+}
+
+// Create adds a stopped timer.
+func (s *Service) Create(opts CreateOptions) (*Timer, error) {
+	if opts.Kind == "" {
+		opts.Kind = KindCountdown
+	}
+	if opts.Kind != KindCountdown && opts.Kind != KindStopwatch {
+		return nil, fmt.Errorf("invalid timer kind: %s", opts.Kind)
+	}
+	if opts.Kind == KindCountdown && opts.Duration <= 0 {
+		return nil, fmt.Errorf("countdown duration must be greater than 0")
+	}
+	if opts.Kind == KindStopwatch {
+		opts.Duration = 0
+		opts.TriggerCueListID = nil
+		opts.TriggerCueNumber = nil
+	}
+
+	s.mu.Lock()
+	t := &timer{
+		id:               cuid.New(),
+		name:             opts.Name,
+		kind:             opts.Kind,
+		duration:         opts.Duration,
+		triggerCueListID: opts.TriggerCueListID,
+		triggerCueNumber: opts.TriggerCueNumber,
+		updatedAt:        s.now(),
+	}
+	s.timers[t.id] = t
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	return snapshot, nil
+}
+
+// Get returns a timer snapshot, or nil if it does not exist.
+func (s *Service) Get(id string) *Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.timers[id]
+	if !ok {
+		return nil
+	}
+	return s.snapshotLocked(t)
+}
+
+// List returns snapshots of all timers ordered by name.
+func (s *Service) List() []*Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Timer, 0, len(s.timers))
+	for _, t := range s.timers {
+		result = append(result, s.snapshotLocked(t))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Start runs a stopped timer. Starting an expired countdown is an error;
+// reset it first.
+func (s *Service) Start(id string) (*Timer, error) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("timer not found: %s", id)
+	}
+	if t.expired {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("timer has expired: %s", id)
+	}
+	if t.startedAt == nil {
+		now := s.now()
+		t.startedAt = &now
+		t.updatedAt = now
+		s.scheduleLocked(t)
+	}
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	return snapshot, nil
+}
+
+// Stop pauses a running timer, keeping its elapsed time.
+func (s *Service) Stop(id string) (*Timer, error) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("timer not found: %s", id)
+	}
+	if t.startedAt != nil {
+		now := s.now()
+		t.accumulated += now.Sub(*t.startedAt)
+		t.startedAt = nil
+		t.updatedAt = now
+		s.unscheduleLocked(t)
+	}
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	return snapshot, nil
+}
+
+// Reset stops a timer and returns it to its initial state.
+func (s *Service) Reset(id string) (*Timer, error) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("timer not found: %s", id)
+	}
+	s.unscheduleLocked(t)
+	t.startedAt = nil
+	t.accumulated = 0
+	t.adjustment = 0
+	t.expired = false
+	t.updatedAt = s.now()
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	return snapshot, nil
+}
+
+// Adjust adds delta to the time shown by a timer: positive values add time
+// to a countdown or advance a stopwatch. Displayed time never goes below zero.
+// Only running countdowns expire, so adjusting a stopped one to zero does
+// not trigger its cue.
+func (s *Service) Adjust(id string, delta time.Duration) (*Timer, error) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("timer not found: %s", id)
+	}
+
+	now := s.now()
+	elapsed := s.elapsedLocked(t, now)
+	if t.kind == KindCountdown {
+		// Lengthen or shorten the countdown, never below the time already run
+		t.adjustment += delta
+		if t.duration+t.adjustment < elapsed {
+			t.adjustment = elapsed - t.duration
+		}
+		if elapsed < t.duration+t.adjustment {
+			t.expired = false
+		}
+	} else {
+		elapsed += delta
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		if t.startedAt != nil {
+			t.startedAt = &now
+		}
+		t.accumulated = elapsed
+	}
+	t.updatedAt = now
+
+	if t.startedAt != nil {
+		// Reschedule expiry; a running countdown adjusted to zero expires now
+		s.unscheduleLocked(t)
+		s.scheduleLocked(t)
+	}
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	return snapshot, nil
+}
+
+// Delete removes a timer.
+func (s *Service) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.timers[id]
+	if !ok {
+		return fmt.Errorf("timer not found: %s", id)
+	}
+	s.unscheduleLocked(t)
+	delete(s.timers, id)
+	return nil
+}
+
+// Cleanup stops all running timers.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.timers {
+		s.unscheduleLocked(t)
+	}
+}
+
+// scheduleLocked starts the update ticker and, for countdowns, the expiry timer.
+func (s *Service) scheduleLocked(t *timer) {
+	if t.kind == KindCountdown {
+		remaining := t.duration + t.adjustment - s.elapsedLocked(t, s.now())
+		if remaining < 0 {
+			remaining = 0
+		}
+		id := t.id
+		t.expiryTimer = time.AfterFunc(remaining, func() { s.expire(id) })
+	}
+
+	ticker := time.NewTicker(tickInterval)
+	stop := make(chan struct{})
+	t.ticker = ticker
+	t.stopTicking = stop
+	go func(id string) {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if snapshot := s.Get(id); snapshot != nil && snapshot.IsRunning {
+					s.emitUpdate(snapshot)
+				}
+			}
+		}
+	}(t.id)
+}
+
+// unscheduleLocked stops a timer's ticker and expiry timer.
+func (s *Service) unscheduleLocked(t *timer) {
+	if t.ticker != nil {
+		t.ticker.Stop()
+		close(t.stopTicking)
+		t.ticker = nil
+		t.stopTicking = nil
+	}
+	if t.expiryTimer != nil {
+		t.expiryTimer.Stop()
+		t.expiryTimer = nil
+	}
+}
+
+// expire marks a running countdown as finished.
+func (s *Service) expire(id string) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if !ok || t.startedAt == nil || t.expired {
+		s.mu.Unlock()
+		return
+	}
+	now := s.now()
+	t.accumulated = t.duration + t.adjustment
+	t.startedAt = nil
+	t.expired = true
+	t.updatedAt = now
+	s.unscheduleLocked(t)
+	snapshot := s.snapshotLocked(t)
+	s.mu.Unlock()
+
+	s.emitUpdate(snapshot)
+	s.emitExpire(snapshot)
+}
+
+func (s *Service) elapsedLocked(t *timer, now time.Time) time.Duration {
+	elapsed := t.accumulated
+	if t.startedAt != nil {
+		elapsed += now.Sub(*t.startedAt)
+	}
+	return elapsed
+}
+
+func (s *Service) snapshotLocked(t *timer) *Timer {
+	elapsed := s.elapsedLocked(t, s.now())
+	var remaining time.Duration
+	if t.kind == KindCountdown {
+		duration := t.duration + t.adjustment
+		if elapsed > duration {
+			elapsed = duration
+		}
+		remaining = duration - elapsed
+	}
+	return &Timer{
+		ID:               t.id,
+		Name:             t.name,
+		Kind:             t.kind,
+		Duration:         t.duration + t.adjustment,
+		Elapsed:          elapsed,
+		Remaining:        remaining,
+		IsRunning:        t.startedAt != nil,
+		HasExpired:       t.expired,
+		TriggerCueListID: t.triggerCueListID,
+		TriggerCueNumber: t.triggerCueNumber,
+		UpdatedAt:        t.updatedAt,
+	}
+}
+
+func (s *Service) emitUpdate(snapshot *Timer) {
+	s.mu.Lock()
+	callback := s.onUpdate
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(snapshot)
+	}
+}
+
+func (s *Service) emitExpire(snapshot *Timer) {
+	s.mu.Lock()
+	callback := s.onExpire
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(snapshot)
+	}
+}
@@ -0,0 +1,145 @@
+package showtimer
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock returns a service whose clock is advanced manually.
+func fakeClock(s *Service) func(d time.Duration) {
+	current := time.Now()
+	s.now = func() time.Time { return current }
+	return func(d time.Duration) { current = current.Add(d) }
+}
+
+func TestCreateValidation(t *testing.T) {
+	s := NewService()
+
+	if _, err := s.Create(CreateOptions{Name: "House", Kind: KindCountdown}); err == nil {
+		t.Error("Expected error for countdown without duration")
+	}
+	if _, err := s.Create(CreateOptions{Name: "Bad", Kind: "SIDEWAYS"}); err == nil {
+		t.Error("Expected error for invalid kind")
+	}
+
+	timer, err := s.Create(CreateOptions{Name: "Show Clock", Kind: KindStopwatch, Duration: time.Minute})
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if timer.Duration != 0 || timer.IsRunning {
+		t.Errorf("Expected stopped stopwatch without duration, got %+v", timer)
+	}
+}
+
+func TestCountdownStartStopAdjust(t *testing.T) {
+	s := NewService()
+	advance := fakeClock(s)
+	defer s.Cleanup()
+
+	timer, err := s.Create(CreateOptions{Name: "Intermission", Duration: 15 * time.Minute})
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if timer.Kind != KindCountdown || timer.Remaining != 15*time.Minute {
+		t.Fatalf("Unexpected new timer: %+v", timer)
+	}
+
+	if _, err := s.Start(timer.ID); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	advance(time.Minute)
+	if got := s.Get(timer.ID).Remaining; got != 14*time.Minute {
+		t.Errorf("Remaining after 1m = %v, want 14m", got)
+	}
+
+	stopped, err := s.Stop(timer.ID)
+	if err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	advance(time.Minute)
+	if got := s.Get(timer.ID).Remaining; got != stopped.Remaining {
+		t.Errorf("Remaining changed while stopped: %v -> %v", stopped.Remaining, got)
+	}
+
+	adjusted, err := s.Adjust(timer.ID, 2*time.Minute)
+	if err != nil {
+		t.Fatalf("Adjust() error: %v", err)
+	}
+	if adjusted.Remaining != 16*time.Minute {
+		t.Errorf("Remaining after +2m = %v, want 16m", adjusted.Remaining)
+	}
+
+	// Taking away more than has elapsed clamps at the full duration
+	adjusted, _ = s.Adjust(timer.ID, -time.Hour)
+	if adjusted.Remaining != 0 || adjusted.HasExpired {
+		t.Errorf("Expected stopped countdown at zero without expiring, got %+v", adjusted)
+	}
+
+	reset, err := s.Reset(timer.ID)
+	if err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	if reset.Remaining != 15*time.Minute || reset.IsRunning {
+		t.Errorf("Expected reset to full stopped countdown, got %+v", reset)
+	}
+}
+
+func TestStopwatchCountsUp(t *testing.T) {
+	s := NewService()
+	advance := fakeClock(s)
+	defer s.Cleanup()
+
+	timer, _ := s.Create(CreateOptions{Name: "Show Clock", Kind: KindStopwatch})
+	if _, err := s.Start(timer.ID); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	advance(90 * time.Second)
+
+	adjusted, err := s.Adjust(timer.ID, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Adjust() error: %v", err)
+	}
+	if adjusted.Elapsed != 2*time.Minute || adjusted.Remaining != 0 {
+		t.Errorf("Expected 2m elapsed, got %+v", adjusted)
+	}
+}
+
+func TestCountdownExpiresAndFiresCallback(t *testing.T) {
+	s := NewService()
+	defer s.Cleanup()
+
+	expired := make(chan *Timer, 1)
+	s.SetExpireCallback(func(timer *Timer) { expired <- timer })
+
+	timer, _ := s.Create(CreateOptions{Name: "House Open", Duration: 50 * time.Millisecond})
+	if _, err := s.Start(timer.ID); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	select {
+	case got := <-expired:
+		if got.ID != timer.ID || !got.HasExpired || got.IsRunning || got.Remaining != 0 {
+			t.Errorf("Unexpected expired timer: %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for countdown to expire")
+	}
+
+	if _, err := s.Start(timer.ID); err == nil {
+		t.Error("Expected error starting an expired countdown")
+	}
+}
+
+func TestUnknownTimer(t *testing.T) {
+	s := NewService()
+
+	if s.Get("missing") != nil {
+		t.Error("Expected nil for unknown timer")
+	}
+	if _, err := s.Start("missing"); err == nil {
+		t.Error("Expected error starting unknown timer")
+	}
+	if err := s.Delete("missing"); err == nil {
+		t.Error("Expected error deleting unknown timer")
+	}
+}
@@ -0,0 +1,223 @@
+// Package snapshot saves restorable copies of projects, on a schedule and
+// before destructive operations.
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+)
+
+// Reason records why a snapshot was taken.
+type Reason string
+
+const (
+	ReasonManual       Reason = "MANUAL"
+	ReasonScheduled    Reason = "SCHEDULED"
+	ReasonBeforeImport Reason = "BEFORE_IMPORT"
+	ReasonBeforeDelete Reason = "BEFORE_DELETE"
+)
+
+// Service takes and restores project snapshots.
+type Service struct {
+	repo          *repositories.SnapshotRepository
+	projectRepo   *repositories.ProjectRepository
+	exportService *export.Service
+	importService *importservice.Service
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewService creates a new snapshot service.
+func NewService(
+	repo *repositories.SnapshotRepository,
+	projectRepo *repositories.ProjectRepository,
+	exportService *export.Service,
+	importService *importservice.Service,
+) *Service {
+	return &Service{
+		repo:          repo,
+		projectRepo:   projectRepo,
+		exportService: exportService,
+		importService: importService,
+	}
+}
+
+// Take exports a project and stores it as a snapshot. Scheduled snapshots
+// are skipped, returning nil, when the project has not changed since its
+// latest snapshot.
+func (s *Service) Take(ctx context.Context, projectID string, reason Reason) (*models.ProjectSnapshot, error) {
+	exported, _, err := s.exportService.ExportProjectWithOptions(ctx, projectID, export.DefaultExportOptions())
+	if err != nil {
+		return nil, err
+	}
+	if exported == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	checksum, err := contentChecksum(exported)
+	if err != nil {
+		return nil, err
+	}
+	if reason == ReasonScheduled {
+		latest, err := s.repo.FindLatest(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil && latest.Checksum == checksum {
+			return nil, nil
+		}
+	}
+
+	jsonContent, err := exported.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	content, err := compress([]byte(jsonContent))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.ProjectSnapshot{
+		ProjectID:   projectID,
+		ProjectName: exported.GetProjectName(),
+		Reason:      string(reason),
+		Content:     content,
+		Size:        len(jsonContent),
+		Checksum:    checksum,
+	}
+	if err := s.repo.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Restore imports a snapshot as a new project, named projectName if set,
+// leaving the project it was taken from untouched.
+func (s *Service) Restore(ctx context.Context, id string, projectName *string) (string, *importservice.ImportStats, []string, error) {
+	snapshot, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if snapshot == nil {
+		return "", nil, nil, fmt.Errorf("snapshot not found: %s", id)
+	}
+
+	jsonContent, err := decompress(snapshot.Content)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+	if projectName == nil {
+		name := fmt.Sprintf("%s (%s)", snapshot.ProjectName, snapshot.CreatedAt.Local().Format("2006-01-02 15:04"))
+		projectName = &name
+	}
+	return s.importService.ImportProject(ctx, string(jsonContent), importservice.ImportOptions{
+		Mode:                    importservice.ImportModeCreate,
+		ProjectName:             projectName,
+		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+	})
+}
+
+// Start snapshots every changed project each interval until Cleanup.
+func (s *Service) Start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interval <= 0 || s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.scheduleLoop(interval, s.stop, s.done)
+}
+
+// Cleanup stops scheduled snapshots.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (s *Service) scheduleLoop(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.TakeScheduled(context.Background())
+		}
+	}
+}
+
+// TakeScheduled snapshots every project that changed since its latest
+// snapshot. Failures are logged so one project cannot block the rest.
+func (s *Service) TakeScheduled(ctx context.Context) {
+	projects, err := s.projectRepo.FindAll(ctx)
+	if err != nil {
+		log.Warn("failed to list projects for snapshots", "error", err)
+		return
+	}
+	for _, project := range projects {
+		if _, err := s.Take(ctx, project.ID, ReasonScheduled); err != nil {
+			log.Warn("failed to snapshot project", "project", project.ID, "error", err)
+		}
+	}
+}
+
+// contentChecksum hashes an export, first putting its definitions, which are
+// exported in no particular order, in a stable one.
+func contentChecksum(exported *export.ExportedProject) (string, error) {
+	sort.Slice(exported.FixtureDefinitions, func(i, j int) bool {
+		return exported.FixtureDefinitions[i].RefID < exported.FixtureDefinitions[j].RefID
+	})
+	data, err := json.Marshal(exported)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,142 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/export"
+	importservice "github.com/bbernstein/lacylights-go/internal/services/import"
+	"github.com/bbernstein/lacylights-go/internal/services/testutil"
+	"github.com/lucsky/cuid"
+)
+
+func newTestService(testDB *testutil.TestDB) *Service {
+	return NewService(
+		repositories.NewSnapshotRepository(testDB.DB),
+		testDB.ProjectRepo,
+		export.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+		importservice.NewService(testDB.ProjectRepo, testDB.FixtureRepo, testDB.SceneRepo, testDB.CueListRepo, testDB.CueRepo),
+	)
+}
+
+// createShow stores a project with a scene and a cue list playing it.
+func createShow(t *testing.T, testDB *testutil.TestDB) (*models.Project, *models.CueList) {
+	t.Helper()
+
+	project := &models.Project{ID: cuid.New(), Name: "Snapshot Show"}
+	scene := &models.Scene{ID: cuid.New(), Name: "Look 1", ProjectID: project.ID}
+	cueList := &models.CueList{ID: cuid.New(), Name: "Main", ProjectID: project.ID}
+	cue := &models.Cue{ID: cuid.New(), Name: "Cue 1", CueNumber: 1, CueListID: cueList.ID, SceneID: scene.ID}
+	for _, record := range []interface{}{project, scene, cueList, cue} {
+		if err := testDB.DB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create %T: %v", record, err)
+		}
+	}
+	return project, cueList
+}
+
+func TestTake_SkipsUnchangedScheduledSnapshots(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB)
+	project, _ := createShow(t, testDB)
+
+	first, err := service.Take(ctx, project.ID, ReasonScheduled)
+	if err != nil {
+		t.Fatalf("Take() error: %v", err)
+	}
+	if first == nil || first.Size == 0 || len(first.Content) == 0 {
+		t.Fatalf("Expected a stored snapshot, got %+v", first)
+	}
+
+	unchanged, err := service.Take(ctx, project.ID, ReasonScheduled)
+	if err != nil {
+		t.Fatalf("Take() error: %v", err)
+	}
+	if unchanged != nil {
+		t.Error("Expected scheduled snapshot of an unchanged project to be skipped")
+	}
+
+	manual, err := service.Take(ctx, project.ID, ReasonManual)
+	if err != nil {
+		t.Fatalf("Take() error: %v", err)
+	}
+	if manual == nil {
+		t.Error("Expected manual snapshot to be taken even when unchanged")
+	}
+
+	testDB.DB.Create(&models.Scene{ID: cuid.New(), Name: "Look 2", ProjectID: project.ID})
+	changed, err := service.Take(ctx, project.ID, ReasonScheduled)
+	if err != nil {
+		t.Fatalf("Take() error: %v", err)
+	}
+	if changed == nil {
+		t.Error("Expected scheduled snapshot after a change")
+	}
+
+	if _, err := service.Take(ctx, "missing", ReasonManual); err == nil {
+		t.Error("Expected error snapshotting a missing project")
+	}
+}
+
+func TestRestore_CreatesProjectFromSnapshot(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	service := newTestService(testDB)
+	project, cueList := createShow(t, testDB)
+
+	snapshot, err := service.Take(ctx, project.ID, ReasonBeforeDelete)
+	if err != nil {
+		t.Fatalf("Take() error: %v", err)
+	}
+	if err := testDB.CueRepo.DeleteByCueListID(ctx, cueList.ID); err != nil {
+		t.Fatalf("Failed to delete cues: %v", err)
+	}
+	if err := testDB.CueListRepo.Delete(ctx, cueList.ID); err != nil {
+		t.Fatalf("Failed to delete cue list: %v", err)
+	}
+
+	name := "Recovered Show"
+	projectID, stats, _, err := service.Restore(ctx, snapshot.ID, &name)
+	if err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if projectID == project.ID {
+		t.Fatal("Expected restore to create a new project")
+	}
+	if stats.CueListsCreated != 1 || stats.CuesCreated != 1 {
+		t.Errorf("Expected 1 cue list with 1 cue restored, got %+v", stats)
+	}
+
+	restored, _ := testDB.ProjectRepo.FindByID(ctx, projectID)
+	if restored == nil || restored.Name != name {
+		t.Errorf("Expected restored project named %q, got %+v", name, restored)
+	}
+	cueLists, _ := testDB.CueListRepo.FindByProjectID(ctx, projectID)
+	if len(cueLists) != 1 || cueLists[0].Name != "Main" {
+		t.Errorf("Expected restored cue list, got %+v", cueLists)
+	}
+
+	if _, _, _, err := service.Restore(ctx, "missing", nil); err == nil {
+		t.Error("Expected error restoring a missing snapshot")
+	}
+}
+
+func TestStartAndCleanup(t *testing.T) {
+	testDB, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	service := newTestService(testDB)
+
+	service.Start(0)
+	service.Cleanup()
+
+	service.Start(time.Hour)
+	service.Start(time.Hour)
+	service.Cleanup()
+	service.Cleanup()
+}
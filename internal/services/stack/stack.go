@@ -0,0 +1,254 @@
+// Package stack merges the output of scenes live on several playbacks at
+// once: intensity channels highest-takes-precedence (HTP), every other
+// channel latest-takes-precedence (LTP).
+package stack
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+// Kind is the type of playback a scene is live on.
+type Kind string
+
+const (
+	// KindScene is a scene activated on its own.
+	KindScene Kind = "SCENE"
+	// KindSceneBoard is a scene activated from a scene board.
+	KindSceneBoard Kind = "SCENE_BOARD"
+	// KindCueList is a cue list's current cue.
+	KindCueList Kind = "CUE_LIST"
+)
+
+// ScenePlayback returns the playback ID of a scene activated on its own.
+func ScenePlayback(sceneID string) string { return "scene:" + sceneID }
+
+// SceneBoardPlayback returns the playback ID of a scene board. A board is a
+// single playback, so activating another scene on it replaces the last one.
+func SceneBoardPlayback(sceneBoardID string) string { return "scene-board:" + sceneBoardID }
+
+// CueListPlayback returns the playback ID of a cue list.
+func CueListPlayback(cueListID string) string { return "cue-list:" + cueListID }
+
+// Activation is a scene going live on a playback.
+type Activation struct {
+	PlaybackID string
+	Kind       Kind
+	SceneID    string
+	Channels   []fade.SceneChannel
+}
+
+// Entry is a playback in the stack.
+type Entry struct {
+	PlaybackID   string
+	Kind         Kind
+	SceneID      string // The scene most recently activated on the playback
+	ChannelCount int
+	ActivatedAt  time.Time
+}
+
+// channelKey identifies a DMX channel.
+type channelKey struct {
+	universe int
+	channel  int
+}
+
+// channelValue is a playback's value for a channel. seq orders values set
+// across all playbacks, for LTP.
+type channelValue struct {
+	value    int
+	behavior string
+	seq      uint64
+}
+
+// entry is a playback's live values.
+type entry struct {
+	Entry
+	values map[channelKey]channelValue
+}
+
+// Service tracks the playbacks with live scenes and fades channels to their
+// merged values.
+type Service struct {
+	mu         sync.Mutex
+	fadeEngine *fade.Engine
+	entries    []*entry // In activation order
+	htp        map[channelKey]bool
+	seq        uint64
+
+	now func() time.Time
+}
+
+// NewService creates an empty playback stack.
+func NewService(fadeEngine *fade.Engine) *Service {
+	return &Service{
+		fadeEngine: fadeEngine,
+		htp:        make(map[channelKey]bool),
+		now:        time.Now,
+	}
+}
+
+// SetHTPChannels sets the channels merged HTP, by universe; the rest are
+// merged LTP.
+func (s *Service) SetHTPChannels(channels map[int][]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.htp = make(map[channelKey]bool)
+	for universe, list := range channels {
+		for _, channel := range list {
+			s.htp[channelKey{universe, channel}] = true
+		}
+	}
+}
+
+// Activate makes a scene live on a playback and fades its channels to their
+// merged values. Channels the playback's previous scene set and this one
+// does not keep their values, as a playback holds what it last output.
+func (s *Service) Activate(a Activation, duration time.Duration, fadeID string, easingType fade.EasingType) string {
+	s.mu.Lock()
+	keys := s.setLocked(a)
+	targets := s.targetsLocked(keys)
+	s.mu.Unlock()
+
+	return s.fadeEngine.FadeChannels(targets, duration, fadeID, easingType, nil)
+}
+
+// Record makes a scene live on a playback whose output the caller has
+// already set, without fading.
+func (s *Service) Record(a Activation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setLocked(a)
+}
+
+// Release removes a playback from the stack, fading its channels to the
+// values the remaining playbacks merge to. HTP channels no playback sets
+// fade out; LTP channels hold. Returns false if the playback is not live.
+func (s *Service) Release(playbackID string, duration time.Duration) bool {
+	s.mu.Lock()
+	index := s.indexLocked(playbackID)
+	if index < 0 {
+		s.mu.Unlock()
+		return false
+	}
+	released := s.entries[index]
+	s.entries = append(s.entries[:index], s.entries[index+1:]...)
+	targets := s.targetsLocked(sortedKeys(released.values))
+	s.mu.Unlock()
+
+	if len(targets) > 0 {
+		s.fadeEngine.FadeChannels(targets, duration, "release-"+playbackID, fade.EasingInOutSine, nil)
+	}
+	return true
+}
+
+// Clear empties the stack without changing the output, as after a fade to
+// black.
+func (s *Service) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+// Entries returns the playbacks in the stack in activation order, the most
+// recent last.
+func (s *Service) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, len(s.entries))
+	for i, e := range s.entries {
+		entries[i] = e.Entry
+		entries[i].ChannelCount = len(e.values)
+	}
+	return entries
+}
+
+// setLocked replaces a playback's entry, moving it to the top of the stack,
+// and returns the channels its new scene sets.
+func (s *Service) setLocked(a Activation) []channelKey {
+	values := make(map[channelKey]channelValue)
+	if index := s.indexLocked(a.PlaybackID); index >= 0 {
+		values = s.entries[index].values
+		s.entries = append(s.entries[:index], s.entries[index+1:]...)
+	}
+
+	s.seq++
+	keys := make([]channelKey, 0, len(a.Channels))
+	for _, ch := range a.Channels {
+		key := channelKey{ch.Universe, ch.Channel}
+		if values[key].seq != s.seq {
+			keys = append(keys, key)
+		}
+		values[key] = channelValue{value: ch.Value, behavior: ch.FadeBehavior, seq: s.seq}
+	}
+
+	s.entries = append(s.entries, &entry{
+		Entry: Entry{
+			PlaybackID:  a.PlaybackID,
+			Kind:        a.Kind,
+			SceneID:     a.SceneID,
+			ActivatedAt: s.now(),
+		},
+		values: values,
+	})
+	return keys
+}
+
+// targetsLocked returns the merged values of channels as fade targets.
+func (s *Service) targetsLocked(keys []channelKey) []fade.ChannelTarget {
+	targets := make([]fade.ChannelTarget, 0, len(keys))
+	for _, key := range keys {
+		var winner *channelValue
+		for _, e := range s.entries {
+			v, ok := e.values[key]
+			if !ok {
+				continue
+			}
+			if winner == nil || (s.htp[key] && v.value > winner.value) || (!s.htp[key] && v.seq > winner.seq) {
+				winner = &v
+			}
+		}
+
+		switch {
+		case winner != nil:
+			targets = append(targets, fade.ChannelTarget{
+				Universe:     key.universe,
+				Channel:      key.channel,
+				TargetValue:  winner.value,
+				FadeBehavior: winner.behavior,
+			})
+		case s.htp[key]:
+			targets = append(targets, fade.ChannelTarget{Universe: key.universe, Channel: key.channel})
+		}
+	}
+	return targets
+}
+
+func (s *Service) indexLocked(playbackID string) int {
+	for i, e := range s.entries {
+		if e.PlaybackID == playbackID {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortedKeys returns a playback's channels in universe and channel order.
+func sortedKeys(values map[channelKey]channelValue) []channelKey {
+	keys := make([]channelKey, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].universe != keys[j].universe {
+			return keys[i].universe < keys[j].universe
+		}
+		return keys[i].channel < keys[j].channel
+	})
+	return keys
+}
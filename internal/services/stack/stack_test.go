@@ -0,0 +1,117 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+)
+
+func createTestService(t *testing.T) (*Service, *dmx.Service) {
+	dmxService := dmx.NewService(dmx.Config{Enabled: false})
+	engine := fade.NewEngine(dmxService, 60)
+	engine.Start()
+	t.Cleanup(engine.Stop)
+
+	s := NewService(engine)
+	// Channels 1 and 2 are intensity; 3 is a color channel
+	s.SetHTPChannels(map[int][]int{1: {1, 2}})
+	return s, dmxService
+}
+
+func activate(s *Service, playbackID, sceneID string, channels ...fade.SceneChannel) {
+	s.Activate(Activation{PlaybackID: playbackID, Kind: KindScene, SceneID: sceneID, Channels: channels}, 0, "", fade.EasingLinear)
+}
+
+func expectChannel(t *testing.T, dmxService *dmx.Service, channel int, want byte) {
+	t.Helper()
+	if got := dmxService.GetChannelValue(1, channel); got != want {
+		t.Errorf("channel %d = %d, want %d", channel, got, want)
+	}
+}
+
+func TestActivate_MergesHTPAndLTP(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	activate(s, "a", "scene-a",
+		fade.SceneChannel{Universe: 1, Channel: 1, Value: 200},
+		fade.SceneChannel{Universe: 1, Channel: 3, Value: 50})
+	activate(s, "b", "scene-b",
+		fade.SceneChannel{Universe: 1, Channel: 1, Value: 100},
+		fade.SceneChannel{Universe: 1, Channel: 2, Value: 80},
+		fade.SceneChannel{Universe: 1, Channel: 3, Value: 150})
+
+	expectChannel(t, dmxService, 1, 200) // Highest intensity wins
+	expectChannel(t, dmxService, 2, 80)
+	expectChannel(t, dmxService, 3, 150) // Latest color wins
+
+	// Re-activating the first playback makes its color the latest
+	activate(s, "a", "scene-a",
+		fade.SceneChannel{Universe: 1, Channel: 1, Value: 20},
+		fade.SceneChannel{Universe: 1, Channel: 3, Value: 60})
+	expectChannel(t, dmxService, 1, 100)
+	expectChannel(t, dmxService, 3, 60)
+
+	entries := s.Entries()
+	if len(entries) != 2 || entries[0].PlaybackID != "b" || entries[1].PlaybackID != "a" {
+		t.Fatalf("Entries = %+v, want b then a", entries)
+	}
+	if entries[0].ChannelCount != 3 {
+		t.Errorf("ChannelCount = %d, want 3", entries[0].ChannelCount)
+	}
+}
+
+func TestActivate_PlaybackHoldsPreviousValues(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	activate(s, "list", "cue-1", fade.SceneChannel{Universe: 1, Channel: 3, Value: 90})
+	activate(s, "other", "scene", fade.SceneChannel{Universe: 1, Channel: 3, Value: 40})
+
+	// The list's next cue does not set channel 3, so the later value stays
+	activate(s, "list", "cue-2", fade.SceneChannel{Universe: 1, Channel: 1, Value: 255})
+	expectChannel(t, dmxService, 3, 40)
+
+	// Releasing the other playback returns channel 3 to the list's value
+	if !s.Release("other", 0) {
+		t.Fatal("Release returned false")
+	}
+	expectChannel(t, dmxService, 3, 90)
+	if entries := s.Entries(); len(entries) != 1 || entries[0].SceneID != "cue-2" {
+		t.Errorf("Entries = %+v, want the list at cue-2", entries)
+	}
+}
+
+func TestRelease_FadesUnownedIntensityOut(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	activate(s, "a", "scene-a",
+		fade.SceneChannel{Universe: 1, Channel: 1, Value: 200},
+		fade.SceneChannel{Universe: 1, Channel: 3, Value: 70})
+	activate(s, "b", "scene-b", fade.SceneChannel{Universe: 1, Channel: 2, Value: 120})
+
+	s.Release("a", 0)
+
+	expectChannel(t, dmxService, 1, 0)   // HTP channel no playback sets goes out
+	expectChannel(t, dmxService, 3, 70)  // LTP channel holds
+	expectChannel(t, dmxService, 2, 120) // Other playbacks are untouched
+
+	if s.Release("a", 0) {
+		t.Error("Release of a playback not in the stack returned true")
+	}
+}
+
+func TestClear_KeepsOutput(t *testing.T) {
+	s, dmxService := createTestService(t)
+
+	activate(s, "a", "scene-a", fade.SceneChannel{Universe: 1, Channel: 1, Value: 200})
+	s.Clear()
+
+	if entries := s.Entries(); len(entries) != 0 {
+		t.Errorf("Entries = %+v, want none", entries)
+	}
+	expectChannel(t, dmxService, 1, 200)
+
+	// A cleared playback no longer holds its intensity up
+	activate(s, "b", "scene-b", fade.SceneChannel{Universe: 1, Channel: 1, Value: 50})
+	expectChannel(t, dmxService, 1, 50)
+}
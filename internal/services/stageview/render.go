@@ -0,0 +1,189 @@
+// Package stageview draws a top-down view of the stage in a scene: each
+// fixture at its layout position, in the color its channel values make,
+// with its beam turned by its layout rotation and pan. Views are SVG, so the
+// web UI can show them as scene thumbnails and paperwork can print them at
+// any size.
+package stageview
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// ContentType is the media type of a rendered view.
+const ContentType = "image/svg+xml"
+
+// View sizes in pixels.
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 400
+	MinSize       = 32
+	MaxSize       = 4096
+)
+
+// PanRange is the pan movement assumed for a fixture with a pan channel, in
+// degrees, since fixture definitions do not record it. Pan at its midpoint
+// leaves the beam at the fixture's layout rotation.
+const PanRange = 540
+
+const (
+	// beamSpread is the half angle of a drawn beam, in degrees
+	beamSpread = 12
+	// beamLength and fixtureRadius are fractions of the view's smaller side
+	beamLength    = 0.22
+	fixtureRadius = 0.018
+	// unplacedY is where fixtures without a layout position are lined up
+	unplacedY = 0.94
+
+	background = "#111111"
+	outline    = "#888888"
+	labelColor = "#CCCCCC"
+)
+
+// Zone is a named region of the layout, in normalized coordinates (0-1).
+type Zone struct {
+	Name                string
+	X, Y, Width, Height float64
+	// Color is the zone's "#RRGGBB", or empty for the default
+	Color string
+}
+
+// Fixture is a fixture, where it is, and its values in the scene.
+type Fixture struct {
+	Name string
+	// X and Y are the layout position (0-1, Y down the view toward the
+	// audience), or nil for a fixture not placed
+	X, Y *float64
+	// Rotation is in degrees clockwise; at 0 the beam points down the view
+	Rotation float64
+	Channels []color.Channel
+	Values   []color.Value
+}
+
+// View is what is drawn.
+type View struct {
+	Title         string
+	Width, Height int
+	// Labels draws fixture and zone names
+	Labels   bool
+	Zones    []Zone
+	Fixtures []Fixture
+}
+
+// placed is a fixture resolved for drawing.
+type placed struct {
+	name       string
+	x, y       float64
+	angle      float64
+	color      string
+	brightness float64
+	lit        bool
+}
+
+// Render draws a view as an SVG document. Fixtures not placed in the layout
+// are lined up along the bottom edge.
+func Render(v View) []byte {
+	w, h := float64(v.Width), float64(v.Height)
+	size := math.Min(w, h)
+	fixtures := place(v.Fixtures)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", v.Width, v.Height, v.Width, v.Height)
+	fmt.Fprintf(&b, `<title>%s</title>`+"\n", html.EscapeString(v.Title))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", v.Width, v.Height, background)
+
+	for _, z := range v.Zones {
+		zoneColor := z.Color
+		if zoneColor == "" {
+			zoneColor = outline
+		}
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.15" stroke="%s" stroke-opacity="0.6"/>`+"\n",
+			z.X*w, z.Y*h, z.Width*w, z.Height*h, html.EscapeString(zoneColor), html.EscapeString(zoneColor))
+		if v.Labels {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" fill="%s">%s</text>`+"\n",
+				z.X*w+4, z.Y*h+size*0.04, size*0.03, labelColor, html.EscapeString(z.Name))
+		}
+	}
+
+	// Beams go under every fixture body
+	for _, f := range fixtures {
+		if !f.lit || f.brightness == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="%.2f"/>`+"\n",
+			beam(f.x*w, f.y*h, f.angle, size*beamLength), f.color, 0.6*f.brightness)
+	}
+	radius := math.Max(3, size*fixtureRadius)
+	for _, f := range fixtures {
+		fill, opacity := background, 1.0
+		if f.lit {
+			fill, opacity = f.color, 0.25+0.75*f.brightness
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" fill-opacity="%.2f" stroke="%s"/>`+"\n",
+			f.x*w, f.y*h, radius, fill, opacity, outline)
+		if v.Labels {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" text-anchor="middle" fill="%s">%s</text>`+"\n",
+				f.x*w, f.y*h+radius+size*0.03, size*0.025, labelColor, html.EscapeString(f.name))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// place resolves where each fixture is drawn, its beam's direction, and
+// the light its values make.
+func place(fixtures []Fixture) []placed {
+	var unplaced int
+	for _, f := range fixtures {
+		if f.X == nil || f.Y == nil {
+			unplaced++
+		}
+	}
+	result := make([]placed, len(fixtures))
+	next := 0
+	for i, f := range fixtures {
+		p := placed{name: f.Name, angle: f.Rotation + pan(f)}
+		if f.X != nil && f.Y != nil {
+			p.x, p.y = *f.X, *f.Y
+		} else {
+			next++
+			p.x, p.y = float64(next)/float64(unplaced+1), unplacedY
+		}
+		c, brightness, ok := color.FromValues(f.Channels, f.Values)
+		p.color, p.brightness, p.lit = c.Hex(), brightness, ok
+		result[i] = p
+	}
+	return result
+}
+
+// pan returns the degrees a fixture's pan value turns its beam from the
+// midpoint, or 0 when the scene does not set pan.
+func pan(f Fixture) float64 {
+	for _, ch := range f.Channels {
+		if ch.Type != "PAN" {
+			continue
+		}
+		for _, v := range f.Values {
+			if v.Offset == ch.Offset {
+				return (float64(v.Value)/255 - 0.5) * PanRange
+			}
+		}
+	}
+	return 0
+}
+
+// beam returns the points of a beam drawn from (x, y) at an angle in
+// degrees clockwise from down the view.
+func beam(x, y, angle, length float64) string {
+	point := func(degrees float64) (float64, float64) {
+		rad := degrees * math.Pi / 180
+		return x - math.Sin(rad)*length, y + math.Cos(rad)*length
+	}
+	x1, y1 := point(angle - beamSpread)
+	x2, y2 := point(angle + beamSpread)
+	return fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f", x, y, x1, y1, x2, y2)
+}
@@ -0,0 +1,85 @@
+package stageview
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+func position(v float64) *float64 { return &v }
+
+func TestRender(t *testing.T) {
+	rgb := []color.Channel{{Offset: 0, Type: "INTENSITY"}, {Offset: 1, Type: "RED"}, {Offset: 2, Type: "GREEN"}, {Offset: 3, Type: "BLUE"}}
+	view := View{
+		Title:  "Act 1 <Sunset>",
+		Width:  400,
+		Height: 200,
+		Labels: true,
+		Zones:  []Zone{{Name: "FOH Truss", X: 0, Y: 0, Width: 1, Height: 0.2, Color: "#336699"}},
+		Fixtures: []Fixture{
+			{
+				Name: "Wash 1", X: position(0.5), Y: position(0.1), Channels: rgb,
+				Values: []color.Value{{Offset: 0, Value: 255}, {Offset: 1, Value: 255}, {Offset: 2, Value: 0}, {Offset: 3, Value: 0}},
+			},
+			{Name: "Dark", X: position(0.2), Y: position(0.1), Channels: rgb},
+			{Name: "Floor 1", Channels: rgb, Values: []color.Value{{Offset: 0, Value: 0}}},
+		},
+	}
+	svg := string(Render(view))
+
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="200"`) {
+		t.Errorf("Unexpected SVG start: %.80s", svg)
+	}
+	if !strings.Contains(svg, "<title>Act 1 &lt;Sunset&gt;</title>") {
+		t.Error("Expected the escaped title")
+	}
+	if !strings.Contains(svg, `fill="#336699"`) || !strings.Contains(svg, ">FOH Truss</text>") {
+		t.Error("Expected the zone and its label")
+	}
+	// Only the lit fixture has a beam, pointing down from (200, 20)
+	if n := strings.Count(svg, "<polygon"); n != 1 {
+		t.Errorf("Expected one beam, got %d", n)
+	}
+	if !strings.Contains(svg, `<polygon points="200.0,20.0 209.1,63.0 190.9,63.0" fill="#FF0000"`) {
+		t.Errorf("Unexpected beam in %s", svg)
+	}
+	if n := strings.Count(svg, "<circle"); n != 3 {
+		t.Errorf("Expected three fixtures, got %d", n)
+	}
+	// The fixture without a position is lined up along the bottom
+	if !strings.Contains(svg, `cx="200.0" cy="188.0"`) {
+		t.Error("Expected the unplaced fixture at the bottom")
+	}
+
+	view.Labels = false
+	if svg := string(Render(view)); strings.Contains(svg, "<text") {
+		t.Error("Expected no labels")
+	}
+}
+
+func TestPan(t *testing.T) {
+	channels := []color.Channel{{Offset: 0, Type: "PAN"}}
+	if got := pan(Fixture{Channels: channels, Values: []color.Value{{Offset: 0, Value: 255}}}); got != PanRange/2 {
+		t.Errorf("pan(255) = %v, want %v", got, PanRange/2)
+	}
+	if got := pan(Fixture{Channels: channels}); got != 0 {
+		t.Errorf("pan without a value = %v, want 0", got)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(url.Values{"width": {"320"}, "labels": {"false"}})
+	if err != nil {
+		t.Fatalf("ParseOptions() error: %v", err)
+	}
+	if opts.Width != 320 || opts.Height != DefaultHeight || opts.Labels {
+		t.Errorf("ParseOptions() = %+v", opts)
+	}
+	for _, bad := range []url.Values{{"width": {"10"}}, {"height": {"big"}}, {"labels": {"maybe"}}} {
+		if _, err := ParseOptions(bad); err == nil {
+			t.Errorf("ParseOptions(%v) should fail", bad)
+		}
+	}
+}
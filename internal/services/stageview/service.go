@@ -0,0 +1,199 @@
+package stageview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/bbernstein/lacylights-go/internal/database/models"
+	"github.com/bbernstein/lacylights-go/internal/database/repositories"
+	"github.com/bbernstein/lacylights-go/internal/services/color"
+)
+
+// ScenePath is the HTTP path scene views are served from.
+const ScenePath = "/render/scene.svg"
+
+// ErrSceneNotFound is returned for a scene that does not exist.
+var ErrSceneNotFound = errors.New("scene not found")
+
+// Options sizes a view and chooses whether it is labelled.
+type Options struct {
+	Width, Height int
+	Labels        bool
+}
+
+// DefaultOptions are the options of a view whose request sets none.
+func DefaultOptions() Options {
+	return Options{Width: DefaultWidth, Height: DefaultHeight, Labels: true}
+}
+
+// ParseOptions reads options from query parameters: width and height in
+// pixels, and labels=false to leave names out.
+func ParseOptions(query url.Values) (Options, error) {
+	opts := DefaultOptions()
+	sizes := []struct {
+		name string
+		size *int
+	}{{"width", &opts.Width}, {"height", &opts.Height}}
+	for _, s := range sizes {
+		param := query.Get(s.name)
+		if param == "" {
+			continue
+		}
+		n, err := strconv.Atoi(param)
+		if err != nil || n < MinSize || n > MaxSize {
+			return opts, fmt.Errorf("%s must be a whole number of pixels between %d and %d", s.name, MinSize, MaxSize)
+		}
+		*s.size = n
+	}
+	if param := query.Get("labels"); param != "" {
+		labels, err := strconv.ParseBool(param)
+		if err != nil {
+			return opts, fmt.Errorf("labels must be true or false")
+		}
+		opts.Labels = labels
+	}
+	return opts, nil
+}
+
+// Service renders views of scenes from a project's layout.
+type Service struct {
+	sceneRepo   *repositories.SceneRepository
+	fixtureRepo *repositories.FixtureRepository
+	zoneRepo    *repositories.LayoutZoneRepository
+
+	mu        sync.Mutex
+	authorize func(r *http.Request) error
+}
+
+// NewService creates a stage view service.
+func NewService(
+	sceneRepo *repositories.SceneRepository,
+	fixtureRepo *repositories.FixtureRepository,
+	zoneRepo *repositories.LayoutZoneRepository,
+) *Service {
+	return &Service{sceneRepo: sceneRepo, fixtureRepo: fixtureRepo, zoneRepo: zoneRepo}
+}
+
+// SetAuthorizer sets the check a request must pass to be served (optional).
+func (s *Service) SetAuthorizer(authorize func(r *http.Request) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorize = authorize
+}
+
+// RenderScene draws a scene over its project's layout. Every fixture in
+// the project is drawn; those the scene leaves out are dark.
+func (s *Service) RenderScene(ctx context.Context, sceneID string, opts Options) ([]byte, error) {
+	scene, err := s.sceneRepo.FindByID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSceneNotFound, sceneID)
+	}
+	fixtures, err := s.fixtureRepo.FindByProjectID(ctx, scene.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.fixtureRepo.GetProjectInstanceChannels(ctx, scene.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.sceneRepo.GetFixtureValues(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := s.zoneRepo.FindByProjectID(ctx, scene.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := View{Title: scene.Name, Width: opts.Width, Height: opts.Height, Labels: opts.Labels}
+	for _, z := range zones {
+		zone := Zone{Name: z.Name, X: z.X, Y: z.Y, Width: z.Width, Height: z.Height}
+		if z.Color != nil {
+			zone.Color = *z.Color
+		}
+		view.Zones = append(view.Zones, zone)
+	}
+
+	channelsByFixture := make(map[string][]color.Channel)
+	for _, ch := range channels {
+		channelsByFixture[ch.FixtureID] = append(channelsByFixture[ch.FixtureID], color.Channel{Offset: ch.Offset, Type: ch.Type})
+	}
+	valuesByFixture := make(map[string][]color.Value, len(values))
+	for _, v := range values {
+		var stored []models.ChannelValue
+		if v.Channels != "" {
+			if err := json.Unmarshal([]byte(v.Channels), &stored); err != nil {
+				return nil, fmt.Errorf("failed to deserialize channels: %w", err)
+			}
+		}
+		for _, ch := range stored {
+			valuesByFixture[v.FixtureID] = append(valuesByFixture[v.FixtureID], color.Value{Offset: ch.Offset, Value: ch.Value})
+		}
+	}
+	for _, f := range fixtures {
+		fixture := Fixture{
+			Name:     f.Name,
+			X:        f.LayoutX,
+			Y:        f.LayoutY,
+			Channels: channelsByFixture[f.ID],
+			Values:   valuesByFixture[f.ID],
+		}
+		if f.LayoutRotation != nil {
+			fixture.Rotation = *f.LayoutRotation
+		}
+		view.Fixtures = append(view.Fixtures, fixture)
+	}
+	return Render(view), nil
+}
+
+// ServeScene sends the view of the scene named by the id query parameter,
+// sized and labelled by ParseOptions. Clients that cannot set headers, such
+// as image tags, may pass their token in a token query parameter.
+func (s *Service) ServeScene(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	authorize := s.authorize
+	s.mu.Unlock()
+	if authorize != nil {
+		if err := authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	sceneID := query.Get("id")
+	if sceneID == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	opts, err := ParseOptions(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	svg, err := s.RenderScene(r.Context(), sceneID, opts)
+	if errors.Is(err, ErrSceneNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error("failed to render scene", "scene", sceneID, "error", err)
+		http.Error(w, "failed to render scene", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	if _, err := w.Write(svg); err != nil {
+		log.Warn("failed to write scene view", "error", err)
+	}
+}
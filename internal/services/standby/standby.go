@@ -0,0 +1,527 @@
+// Package standby provides a low-power standby mode for unattended
+// installations. In standby fade processing and cue list follows stop, and
+// DMX output either blacks out or holds the last look; the server wakes on
+// its opening-hours schedule, on request, or when Art-Net DMX arrives from
+// another console.
+package standby
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+// SettingKey is the setting that stores the standby configuration as JSON.
+const SettingKey = "standby_config"
+
+// clockLayout is the HH:MM format of opening and closing times.
+const clockLayout = "15:04"
+
+// maxScheduleCheck bounds how long the schedule goes unchecked, so wall clock
+// corrections (e.g. NTP sync after boot) are picked up.
+const maxScheduleCheck = time.Minute
+
+// Trigger is what caused the server to enter or leave standby.
+type Trigger string
+
+const (
+	// TriggerManual is an explicit API request.
+	TriggerManual Trigger = "MANUAL"
+	// TriggerSchedule is an opening-hours boundary.
+	TriggerSchedule Trigger = "SCHEDULE"
+	// TriggerArtNet is incoming Art-Net DMX traffic (wake only).
+	TriggerArtNet Trigger = "ARTNET"
+)
+
+// Output is what DMX output does during standby.
+type Output string
+
+const (
+	// OutputZero blacks out every universe once and stops transmitting.
+	OutputZero Output = "ZERO"
+	// OutputHold keeps transmitting the last look at the idle rate, for
+	// fixtures that go to a default state when DMX is lost.
+	OutputHold Output = "HOLD"
+)
+
+// Playback is a playback engine that is held during standby.
+type Playback interface {
+	// Suspend stops playback advancing, e.g. pending cue list follows
+	Suspend()
+	// Resume restarts what Suspend stopped
+	Resume()
+}
+
+// Window is a span of opening hours on the given weekdays. Times are HH:MM
+// in server local time; a Close before Open runs past midnight.
+type Window struct {
+	Days  []time.Weekday `json:"days"`
+	Open  string         `json:"open"`
+	Close string         `json:"close"`
+}
+
+// Config controls when the server sleeps and what wakes it.
+type Config struct {
+	// ScheduleEnabled puts the server in standby outside OpeningHours
+	ScheduleEnabled bool     `json:"scheduleEnabled"`
+	OpeningHours    []Window `json:"openingHours"`
+	// WakeOnArtNet wakes the server when Art-Net DMX arrives during standby
+	WakeOnArtNet bool `json:"wakeOnArtNet"`
+	// Output is what DMX output does during standby; empty means OutputZero
+	Output Output `json:"output,omitempty"`
+}
+
+// OutputMode returns the configured standby output.
+func (c *Config) OutputMode() Output {
+	if c.Output == "" {
+		return OutputZero
+	}
+	return c.Output
+}
+
+// Validate checks the opening hours and output.
+func (c *Config) Validate() error {
+	if err := validateOutput(c.Output); err != nil {
+		return err
+	}
+	for i, w := range c.OpeningHours {
+		if len(w.Days) == 0 {
+			return fmt.Errorf("opening hours %d: at least one day is required", i+1)
+		}
+		if _, err := time.Parse(clockLayout, w.Open); err != nil {
+			return fmt.Errorf("opening hours %d: open time must be HH:MM, got %q", i+1, w.Open)
+		}
+		if _, err := time.Parse(clockLayout, w.Close); err != nil {
+			return fmt.Errorf("opening hours %d: close time must be HH:MM, got %q", i+1, w.Close)
+		}
+		if w.Open == w.Close {
+			return fmt.Errorf("opening hours %d: open and close times must differ", i+1)
+		}
+	}
+	if c.ScheduleEnabled && len(c.OpeningHours) == 0 {
+		return fmt.Errorf("a schedule requires at least one opening hours window")
+	}
+	return nil
+}
+
+// IsOpen reports whether t falls within the opening hours.
+func (c *Config) IsOpen(t time.Time) bool {
+	for _, span := range c.spansAround(t) {
+		if !t.Before(span[0]) && t.Before(span[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChange returns the first opening or closing time after t, or false if
+// there are no opening hours.
+func (c *Config) NextChange(t time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, span := range c.spansAround(t) {
+		for _, boundary := range span {
+			if boundary.After(t) && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// spansAround returns the open and close times of every window from the day
+// before t to a week after it. Windows are assumed valid.
+func (c *Config) spansAround(t time.Time) [][2]time.Time {
+	var spans [][2]time.Time
+	year, month, day := t.Date()
+	for offset := -1; offset <= 7; offset++ {
+		weekday := time.Date(year, month, day+offset, 0, 0, 0, 0, t.Location()).Weekday()
+		for _, w := range c.OpeningHours {
+			if !containsDay(w.Days, weekday) {
+				continue
+			}
+			open, errOpen := time.Parse(clockLayout, w.Open)
+			closing, errClose := time.Parse(clockLayout, w.Close)
+			if errOpen != nil || errClose != nil {
+				continue
+			}
+			closeDay := day + offset
+			if w.Close < w.Open {
+				closeDay++
+			}
+			spans = append(spans, [2]time.Time{
+				time.Date(year, month, day+offset, open.Hour(), open.Minute(), 0, 0, t.Location()),
+				time.Date(year, month, closeDay, closing.Hour(), closing.Minute(), 0, 0, t.Location()),
+			})
+		}
+	}
+	return spans
+}
+
+// Status is a snapshot of the standby state.
+type Status struct {
+	IsStandby      bool
+	Reason         *Trigger // Why standby was entered; nil when awake
+	Since          *time.Time
+	Output         *Output // What DMX output does; nil when awake
+	LastWakeReason *Trigger
+	LastWakeAt     *time.Time
+	Config         Config
+	// Next opening or closing time while the schedule is enabled
+	NextScheduledChange *time.Time
+}
+
+// Service manages standby.
+type Service struct {
+	mu         sync.Mutex
+	dmxService *dmx.Service
+	fadeEngine *fade.Engine
+	playback   Playback
+	artNetPort int
+
+	config         Config
+	standby        bool
+	reason         Trigger
+	since          time.Time
+	output         Output
+	lastWakeReason *Trigger
+	lastWakeAt     time.Time
+	fadesWereOn    bool // Fade engine was running when standby began
+
+	scheduleOpen  bool // Opening hours state at the last check
+	scheduleTimer *time.Timer
+	listener      *net.UDPConn
+
+	// Called with a snapshot whenever the standby state or config changes (optional)
+	onUpdate func(status *Status)
+
+	now func() time.Time
+}
+
+// NewService creates a standby service that suspends the given output stack
+// and listens for Art-Net wake traffic on artNetPort.
+func NewService(dmxService *dmx.Service, fadeEngine *fade.Engine, artNetPort int) *Service {
+	if artNetPort <= 0 {
+		artNetPort = artnet.DefaultPort
+	}
+	return &Service{
+		dmxService: dmxService,
+		fadeEngine: fadeEngine,
+		artNetPort: artNetPort,
+		config:     Config{WakeOnArtNet: true},
+		now:        time.Now,
+	}
+}
+
+// SetPlayback sets the playback engine held during standby.
+func (s *Service) SetPlayback(playback Playback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.playback = playback
+}
+
+// SetUpdateCallback sets the callback for standby changes.
+func (s *Service) SetUpdateCallback(callback func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = callback
+}
+
+// Status returns the current standby state.
+func (s *Service) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+// SetConfig validates and applies a configuration. The schedule takes effect
+// immediately: outside opening hours the server goes to sleep.
+func (s *Service) SetConfig(config Config) (*Status, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.config = config
+	now := s.now()
+	s.scheduleOpen = config.IsOpen(now)
+	switch {
+	case config.ScheduleEnabled && !s.scheduleOpen:
+		s.enterLocked(TriggerSchedule, config.OutputMode())
+	case s.standby && s.reason == TriggerSchedule && (!config.ScheduleEnabled || s.scheduleOpen):
+		s.wakeLocked(TriggerSchedule)
+	}
+	if s.standby && config.WakeOnArtNet {
+		s.startListenerLocked()
+	} else {
+		s.stopListenerLocked()
+	}
+	s.scheduleLocked()
+	return s.unlockAndEmit(), nil
+}
+
+// Enter puts the server in standby with the configured output.
+func (s *Service) Enter() *Status {
+	s.mu.Lock()
+	s.enterLocked(TriggerManual, s.config.OutputMode())
+	return s.unlockAndEmit()
+}
+
+// EnterWithOutput puts the server in standby with the given output,
+// overriding the configured one. Already in standby, it does nothing.
+func (s *Service) EnterWithOutput(output Output) (*Status, error) {
+	if output == "" {
+		return s.Enter(), nil
+	}
+	if err := validateOutput(output); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.enterLocked(TriggerManual, output)
+	return s.unlockAndEmit(), nil
+}
+
+// Wake brings the server out of standby.
+func (s *Service) Wake() *Status {
+	return s.wake(TriggerManual)
+}
+
+// Cleanup stops the schedule and the Art-Net listener.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.scheduleTimer != nil {
+		s.scheduleTimer.Stop()
+		s.scheduleTimer = nil
+	}
+	s.stopListenerLocked()
+}
+
+// ArtNetReceived wakes the server when Art-Net DMX from another console is
+// seen by a service that holds the Art-Net port, which keeps the standby
+// listener from opening it.
+func (s *Service) ArtNetReceived() {
+	s.mu.Lock()
+	if !s.standby || !s.config.WakeOnArtNet {
+		s.mu.Unlock()
+		return
+	}
+	s.wakeLocked(TriggerArtNet)
+	s.unlockAndEmit()
+}
+
+func (s *Service) wake(trigger Trigger) *Status {
+	s.mu.Lock()
+	s.wakeLocked(trigger)
+	return s.unlockAndEmit()
+}
+
+// checkSchedule enters or leaves standby when the opening hours state has
+// changed since the last check. Acting only on changes lets manual wake and
+// standby requests hold until the next opening or closing time.
+func (s *Service) checkSchedule() {
+	s.mu.Lock()
+	if !s.config.ScheduleEnabled {
+		s.mu.Unlock()
+		return
+	}
+
+	open := s.config.IsOpen(s.now())
+	changed := open != s.scheduleOpen
+	s.scheduleOpen = open
+	if changed {
+		if open {
+			s.wakeLocked(TriggerSchedule)
+		} else {
+			s.enterLocked(TriggerSchedule, s.config.OutputMode())
+		}
+	}
+	s.scheduleLocked()
+
+	if !changed {
+		s.mu.Unlock()
+		return
+	}
+	s.unlockAndEmit()
+}
+
+// scheduleLocked arms the timer for the next schedule check.
+func (s *Service) scheduleLocked() {
+	if s.scheduleTimer != nil {
+		s.scheduleTimer.Stop()
+		s.scheduleTimer = nil
+	}
+	if !s.config.ScheduleEnabled {
+		return
+	}
+
+	now := s.now()
+	wait := maxScheduleCheck
+	if next, ok := s.config.NextChange(now); ok && next.Sub(now) < wait {
+		wait = next.Sub(now)
+	}
+	s.scheduleTimer = time.AfterFunc(wait, s.checkSchedule)
+}
+
+func (s *Service) enterLocked(trigger Trigger, output Output) {
+	if s.standby {
+		return
+	}
+
+	s.standby = true
+	s.reason = trigger
+	s.since = s.now()
+	s.output = output
+
+	// Hold follows so no cue starts while asleep
+	if s.playback != nil {
+		s.playback.Suspend()
+	}
+	// Stop fades first so no frame lands between the blackout and the suspend
+	s.fadesWereOn = s.fadeEngine != nil && s.fadeEngine.IsRunning()
+	if s.fadesWereOn {
+		s.fadeEngine.Stop()
+	}
+	if s.dmxService != nil {
+		if output == OutputHold {
+			s.dmxService.EnterStandbyHold()
+		} else {
+			s.dmxService.EnterStandby()
+		}
+	}
+	if s.config.WakeOnArtNet {
+		s.startListenerLocked()
+	}
+	log.Info("💤 Entering standby", "trigger", trigger, "output", output)
+}
+
+func (s *Service) wakeLocked(trigger Trigger) {
+	if !s.standby {
+		return
+	}
+
+	s.stopListenerLocked()
+	if s.dmxService != nil {
+		s.dmxService.ExitStandby()
+	}
+	if s.fadesWereOn {
+		s.fadeEngine.Start()
+	}
+	if s.playback != nil {
+		s.playback.Resume()
+	}
+
+	s.standby = false
+	s.lastWakeReason = &trigger
+	s.lastWakeAt = s.now()
+	log.Info("☀️ Waking from standby", "trigger", trigger)
+}
+
+// startListenerLocked listens for Art-Net DMX from other consoles. Our own
+// output is suspended or, while holding the last look, ignored by source.
+func (s *Service) startListenerLocked() {
+	if s.listener != nil {
+		return
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: s.artNetPort})
+	if err != nil {
+		log.Warn("cannot listen for Art-Net wake traffic", "port", s.artNetPort, "error", err)
+		return
+	}
+	s.listener = conn
+	go s.listen(conn)
+}
+
+func (s *Service) stopListenerLocked() {
+	if s.listener == nil {
+		return
+	}
+	_ = s.listener.Close()
+	s.listener = nil
+}
+
+func (s *Service) listen(conn *net.UDPConn) {
+	buffer := make([]byte, 1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return // Listener closed
+		}
+		if opCode, ok := artnet.OpCode(buffer[:n]); !ok || opCode != artnet.OpCodeDMX {
+			continue
+		}
+		if s.dmxService != nil && s.dmxService.IsOwnPacket(from) {
+			continue
+		}
+
+		s.mu.Lock()
+		current := s.listener == conn
+		s.mu.Unlock()
+		if current {
+			log.Info("📡 Art-Net DMX received", "from", from)
+			s.wake(TriggerArtNet)
+		}
+		return
+	}
+}
+
+// unlockAndEmit takes a snapshot, releases the lock, and notifies the callback.
+func (s *Service) unlockAndEmit() *Status {
+	snapshot := s.snapshotLocked()
+	callback := s.onUpdate
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(snapshot)
+	}
+	return snapshot
+}
+
+func (s *Service) snapshotLocked() *Status {
+	status := &Status{
+		IsStandby:      s.standby,
+		LastWakeReason: s.lastWakeReason,
+		Config:         s.config,
+	}
+	status.Config.OpeningHours = append([]Window(nil), s.config.OpeningHours...)
+	if s.standby {
+		reason := s.reason
+		since := s.since
+		output := s.output
+		status.Reason = &reason
+		status.Since = &since
+		status.Output = &output
+	}
+	if s.lastWakeReason != nil {
+		lastWakeAt := s.lastWakeAt
+		status.LastWakeAt = &lastWakeAt
+	}
+	if s.config.ScheduleEnabled {
+		if next, ok := s.config.NextChange(s.now()); ok {
+			status.NextScheduledChange = &next
+		}
+	}
+	return status
+}
+
+func validateOutput(output Output) error {
+	switch output {
+	case "", OutputZero, OutputHold:
+		return nil
+	}
+	return fmt.Errorf("unknown standby output %q", output)
+}
+
+func containsDay(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
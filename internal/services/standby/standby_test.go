@@ -0,0 +1,287 @@
+package standby
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bbernstein/lacylights-go/internal/services/dmx"
+	"github.com/bbernstein/lacylights-go/internal/services/fade"
+	"github.com/bbernstein/lacylights-go/pkg/artnet"
+)
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// at returns a time in October 2026, when the 12th is a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+}
+
+func newTestService(t *testing.T, artNetPort int) (*Service, *dmx.Service, *fade.Engine) {
+	t.Helper()
+	dmxService := dmx.NewService(dmx.Config{Enabled: false})
+	fadeEngine := fade.NewEngine(dmxService, 60)
+	fadeEngine.Start()
+	s := NewService(dmxService, fadeEngine, artNetPort)
+	t.Cleanup(func() {
+		s.Cleanup()
+		fadeEngine.Stop()
+	})
+	return s, dmxService, fadeEngine
+}
+
+func TestConfigOpeningHours(t *testing.T) {
+	config := Config{OpeningHours: []Window{
+		{Days: weekdays, Open: "09:00", Close: "17:00"},
+		{Days: []time.Weekday{time.Saturday}, Open: "20:00", Close: "02:00"},
+	}}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		{"weekday morning", at(12, 8, 0), false, at(12, 9, 0)},
+		{"weekday open", at(12, 9, 0), true, at(12, 17, 0)},
+		{"weekday evening", at(16, 18, 0), false, at(17, 20, 0)},
+		{"overnight before midnight", at(17, 23, 0), true, at(18, 2, 0)},
+		{"overnight after midnight", at(18, 1, 0), true, at(18, 2, 0)},
+		{"sunday", at(18, 12, 0), false, at(19, 9, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.IsOpen(tt.t); got != tt.wantOpen {
+				t.Errorf("IsOpen() = %v, want %v", got, tt.wantOpen)
+			}
+			next, ok := config.NextChange(tt.t)
+			if !ok || !next.Equal(tt.wantNext) {
+				t.Errorf("NextChange() = %v, %v; want %v", next, ok, tt.wantNext)
+			}
+		})
+	}
+
+	if _, ok := (&Config{}).NextChange(at(12, 0, 0)); ok {
+		t.Error("Expected no next change without opening hours")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"schedule without hours", Config{ScheduleEnabled: true}},
+		{"no days", Config{OpeningHours: []Window{{Open: "09:00", Close: "17:00"}}}},
+		{"bad time", Config{OpeningHours: []Window{{Days: weekdays, Open: "9am", Close: "17:00"}}}},
+		{"empty window", Config{OpeningHours: []Window{{Days: weekdays, Open: "09:00", Close: "09:00"}}}},
+		{"unknown output", Config{Output: "DIM"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestScheduledStandby(t *testing.T) {
+	s, dmxService, fadeEngine := newTestService(t, 0)
+	current := at(12, 8, 0)
+	s.now = func() time.Time { return current }
+
+	var updates []*Status
+	s.SetUpdateCallback(func(status *Status) { updates = append(updates, status) })
+
+	// Enabling the schedule outside opening hours sleeps immediately
+	status, err := s.SetConfig(Config{
+		ScheduleEnabled: true,
+		OpeningHours:    []Window{{Days: weekdays, Open: "09:00", Close: "17:00"}},
+	})
+	if err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if !status.IsStandby || status.Reason == nil || *status.Reason != TriggerSchedule {
+		t.Fatalf("Expected scheduled standby, got %+v", status)
+	}
+	if !dmxService.IsStandby() || fadeEngine.IsRunning() {
+		t.Error("Expected DMX output and fades suspended in standby")
+	}
+	if status.NextScheduledChange == nil || !status.NextScheduledChange.Equal(at(12, 9, 0)) {
+		t.Errorf("Expected next change at opening time, got %v", status.NextScheduledChange)
+	}
+
+	// Opening time wakes the server
+	current = at(12, 9, 0)
+	s.checkSchedule()
+	status = s.Status()
+	if status.IsStandby || status.LastWakeReason == nil || *status.LastWakeReason != TriggerSchedule {
+		t.Fatalf("Expected scheduled wake, got %+v", status)
+	}
+	if dmxService.IsStandby() || !fadeEngine.IsRunning() {
+		t.Error("Expected DMX output and fades resumed after wake")
+	}
+
+	// A manual standby holds until the schedule next changes
+	s.Enter()
+	current = at(12, 12, 0)
+	s.checkSchedule()
+	if !s.Status().IsStandby {
+		t.Error("Expected manual standby to hold during opening hours")
+	}
+
+	// A manual wake holds through the night
+	s.Wake()
+	current = at(12, 17, 0)
+	s.checkSchedule()
+	s.Wake()
+	current = at(12, 23, 0)
+	s.checkSchedule()
+	if s.Status().IsStandby {
+		t.Error("Expected manual wake to hold after closing")
+	}
+
+	if len(updates) != 6 {
+		t.Errorf("Expected 6 updates, got %d", len(updates))
+	}
+
+	// The next closing time sleeps again
+	current = at(13, 9, 0)
+	s.checkSchedule()
+	current = at(13, 17, 0)
+	s.checkSchedule()
+	if !s.Status().IsStandby {
+		t.Fatal("Expected scheduled standby at closing time")
+	}
+
+	// Disabling the schedule wakes a scheduled standby
+	status, err = s.SetConfig(Config{})
+	if err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if status.IsStandby || status.NextScheduledChange != nil {
+		t.Errorf("Expected wake with no schedule, got %+v", status)
+	}
+}
+
+// fakePlayback counts suspends and resumes.
+type fakePlayback struct{ suspends, resumes int }
+
+func (p *fakePlayback) Suspend() { p.suspends++ }
+func (p *fakePlayback) Resume()  { p.resumes++ }
+
+func TestStandbyOutput(t *testing.T) {
+	s, dmxService, _ := newTestService(t, 0)
+	playback := &fakePlayback{}
+	s.SetPlayback(playback)
+
+	// Configured output is used by default
+	if _, err := s.SetConfig(Config{Output: OutputHold}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	status := s.Enter()
+	if status.Output == nil || *status.Output != OutputHold {
+		t.Fatalf("Expected held output, got %+v", status)
+	}
+	if !dmxService.IsStandby() || playback.suspends != 1 {
+		t.Errorf("Expected DMX output and playback suspended, got %d suspends", playback.suspends)
+	}
+
+	status = s.Wake()
+	if status.Output != nil || dmxService.IsStandby() || playback.resumes != 1 {
+		t.Errorf("Expected output and playback resumed, got %+v and %d resumes", status, playback.resumes)
+	}
+
+	// A request may override the configured output
+	status, err := s.EnterWithOutput(OutputZero)
+	if err != nil {
+		t.Fatalf("EnterWithOutput() error: %v", err)
+	}
+	if status.Output == nil || *status.Output != OutputZero {
+		t.Errorf("Expected zeroed output, got %+v", status)
+	}
+	s.Wake()
+
+	if _, err := s.EnterWithOutput("DIM"); err == nil {
+		t.Error("Expected an unknown output to be rejected")
+	}
+	if s.Status().IsStandby {
+		t.Error("Expected no standby after a rejected request")
+	}
+}
+
+func TestWakeOnArtNet(t *testing.T) {
+	const testPort = 6580
+	s, dmxService, _ := newTestService(t, testPort)
+
+	woke := make(chan *Status, 1)
+	s.SetUpdateCallback(func(status *Status) {
+		if !status.IsStandby {
+			woke <- status
+		}
+	})
+
+	s.Enter()
+	if !dmxService.IsStandby() {
+		t.Fatal("Expected DMX output in standby")
+	}
+
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: testPort})
+	if err != nil {
+		t.Fatalf("Failed to dial listener: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Discovery traffic does not wake the server
+	if _, err := conn.Write(artnet.BuildPollPacket()); err != nil {
+		t.Fatalf("Failed to send ArtPoll: %v", err)
+	}
+	select {
+	case status := <-woke:
+		t.Fatalf("ArtPoll should not wake the server, got %+v", status)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := conn.Write(artnet.BuildDMXPacket(1, make([]byte, 512), 1)); err != nil {
+		t.Fatalf("Failed to send ArtDmx: %v", err)
+	}
+	select {
+	case status := <-woke:
+		if status.LastWakeReason == nil || *status.LastWakeReason != TriggerArtNet {
+			t.Errorf("Expected Art-Net wake, got %+v", status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for Art-Net wake")
+	}
+	if dmxService.IsStandby() {
+		t.Error("Expected DMX output resumed")
+	}
+}
+
+func TestArtNetReceived(t *testing.T) {
+	s, _, _ := newTestService(t, 6581)
+
+	s.ArtNetReceived()
+	if status := s.Status(); status.LastWakeReason != nil {
+		t.Errorf("Expected no wake while awake, got %+v", status)
+	}
+
+	if _, err := s.SetConfig(Config{WakeOnArtNet: false}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	s.Enter()
+	s.ArtNetReceived()
+	if !s.Status().IsStandby {
+		t.Error("Expected to stay in standby with Art-Net wake disabled")
+	}
+
+	if _, err := s.SetConfig(Config{WakeOnArtNet: true}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	s.ArtNetReceived()
+	status := s.Status()
+	if status.IsStandby || status.LastWakeReason == nil || *status.LastWakeReason != TriggerArtNet {
+		t.Errorf("Expected Art-Net wake, got %+v", status)
+	}
+}
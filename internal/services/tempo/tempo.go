@@ -0,0 +1,256 @@
+// Package tempo provides a shared musical tempo clock with tap tempo, so
+// playback can lock to the beat of live music.
+package tempo
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Quantize selects the musical boundary an event is delayed to.
+type Quantize string
+
+const (
+	// QuantizeNone fires events immediately.
+	QuantizeNone Quantize = "NONE"
+	// QuantizeBeat delays events to the next beat.
+	QuantizeBeat Quantize = "BEAT"
+	// QuantizeBar delays events to the next downbeat.
+	QuantizeBar Quantize = "BAR"
+)
+
+const (
+	// DefaultBPM is the tempo before anyone sets or taps one.
+	DefaultBPM = 120.0
+	// MinBPM and MaxBPM bound set and tapped tempos.
+	MinBPM = 20.0
+	MaxBPM = 300.0
+	// DefaultBeatsPerBar is common time.
+	DefaultBeatsPerBar = 4
+	// MaxBeatsPerBar bounds the time signature.
+	MaxBeatsPerBar = 16
+)
+
+// tapResetGap is how long after the last tap a new tap starts a new sequence.
+const tapResetGap = 2 * time.Second
+
+// maxTaps is how many recent taps are averaged.
+const maxTaps = 8
+
+// boundaryTolerance treats events this close to a boundary as on it, so
+// timers that fire a little early don't wait a whole extra beat.
+const boundaryTolerance = 5 * time.Millisecond
+
+// State is a snapshot of the tempo clock.
+type State struct {
+	BPM         float64
+	BeatsPerBar int
+	DownbeatAt  time.Time // A downbeat; beats follow every BeatDuration
+	Beat        int       // Current beat within the bar, starting at 1
+	Bar         int       // Bars since DownbeatAt, starting at 1
+	TapCount    int       // Taps in the current tap sequence
+	Time        time.Time // When this snapshot was taken
+	UpdatedAt   time.Time
+}
+
+// BeatDuration returns the length of one beat.
+func (s *State) BeatDuration() time.Duration {
+	return beatDuration(s.BPM)
+}
+
+// Service is the server-wide tempo clock.
+type Service struct {
+	mu          sync.Mutex
+	bpm         float64
+	beatsPerBar int
+	downbeatAt  time.Time
+	taps        []time.Time
+	tapStart    time.Time // First tap of the current sequence
+	updatedAt   time.Time
+
+	// Called with a snapshot whenever the tempo changes (optional)
+	onUpdate func(state *State)
+
+	now func() time.Time
+}
+
+// NewService creates a tempo clock at DefaultBPM in common time.
+func NewService() *Service {
+	now := time.Now()
+	return &Service{
+		bpm:         DefaultBPM,
+		beatsPerBar: DefaultBeatsPerBar,
+		downbeatAt:  now,
+		updatedAt:   now,
+		now:         time.Now,
+	}
+}
+
+// SetUpdateCallback sets the callback for tempo changes.
+func (s *Service) SetUpdateCallback(callback func(state *State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = callback
+}
+
+// State returns the current tempo state.
+func (s *Service) State() *State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+// SetBPM sets the tempo, keeping the current beat position.
+func (s *Service) SetBPM(bpm float64) (*State, error) {
+	if math.IsNaN(bpm) || bpm < MinBPM || bpm > MaxBPM {
+		return nil, fmt.Errorf("bpm must be between %v and %v, got %v", MinBPM, MaxBPM, bpm)
+	}
+
+	s.mu.Lock()
+	s.rebaseLocked(s.now())
+	s.bpm = bpm
+	s.taps = nil
+	return s.unlockAndEmit(), nil
+}
+
+// SetBeatsPerBar sets the time signature, starting a bar at the current beat.
+func (s *Service) SetBeatsPerBar(beatsPerBar int) (*State, error) {
+	if beatsPerBar < 1 || beatsPerBar > MaxBeatsPerBar {
+		return nil, fmt.Errorf("beatsPerBar must be between 1 and %d, got %d", MaxBeatsPerBar, beatsPerBar)
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	s.downbeatAt = s.previousBoundaryLocked(now, QuantizeBeat)
+	s.beatsPerBar = beatsPerBar
+	return s.unlockAndEmit(), nil
+}
+
+// Tap registers a tap on the beat. Every tap realigns the clock so the tap
+// lands on a beat, the first tap of a sequence marks a downbeat, and from the
+// second tap on the BPM follows the average interval between recent taps.
+func (s *Service) Tap() *State {
+	s.mu.Lock()
+	now := s.now()
+
+	if len(s.taps) > 0 && now.Sub(s.taps[len(s.taps)-1]) > tapResetGap {
+		s.taps = nil
+	}
+	s.taps = append(s.taps, now)
+	if len(s.taps) > maxTaps {
+		s.taps = s.taps[len(s.taps)-maxTaps:]
+	}
+
+	if len(s.taps) == 1 {
+		s.tapStart = now
+	} else {
+		interval := now.Sub(s.taps[0]) / time.Duration(len(s.taps)-1)
+		if interval > 0 {
+			bpm := float64(time.Minute) / float64(interval)
+			s.bpm = math.Max(MinBPM, math.Min(MaxBPM, bpm))
+		}
+	}
+	s.downbeatAt = s.tapStart
+	s.alignToLocked(now)
+
+	return s.unlockAndEmit()
+}
+
+// Resync marks now as a downbeat without changing the tempo.
+func (s *Service) Resync() *State {
+	s.mu.Lock()
+	s.downbeatAt = s.now()
+	s.taps = nil
+	return s.unlockAndEmit()
+}
+
+// NextBoundary returns the first beat or bar boundary at or after t. With
+// QuantizeNone it returns t unchanged.
+func (s *Service) NextBoundary(t time.Time, q Quantize) time.Time {
+	if q != QuantizeBeat && q != QuantizeBar {
+		return t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.previousBoundaryLocked(t, q)
+	if t.Sub(previous) <= boundaryTolerance {
+		return previous
+	}
+	return previous.Add(s.unitLocked(q))
+}
+
+// unitLocked returns the length of one beat or bar.
+func (s *Service) unitLocked(q Quantize) time.Duration {
+	unit := beatDuration(s.bpm)
+	if q == QuantizeBar {
+		unit *= time.Duration(s.beatsPerBar)
+	}
+	return unit
+}
+
+// previousBoundaryLocked returns the last beat or bar boundary at or before t.
+func (s *Service) previousBoundaryLocked(t time.Time, q Quantize) time.Time {
+	unit := s.unitLocked(q)
+	if unit <= 0 {
+		return t
+	}
+	offset := t.Sub(s.downbeatAt)
+	units := offset / unit
+	if offset%unit < 0 {
+		units--
+	}
+	return s.downbeatAt.Add(units * unit)
+}
+
+// rebaseLocked moves the downbeat to the latest bar start before now, so a
+// tempo change keeps the current position in the bar.
+func (s *Service) rebaseLocked(now time.Time) {
+	s.downbeatAt = s.previousBoundaryLocked(now, QuantizeBar)
+}
+
+// alignToLocked shifts the clock so a beat falls exactly at t, keeping the
+// bar count.
+func (s *Service) alignToLocked(t time.Time) {
+	beat := beatDuration(s.bpm)
+	beats := (t.Sub(s.downbeatAt) + beat/2) / beat
+	s.downbeatAt = t.Add(-beats * beat)
+}
+
+// unlockAndEmit records a change, releases the lock, and notifies the callback.
+func (s *Service) unlockAndEmit() *State {
+	s.updatedAt = s.now()
+	snapshot := s.snapshotLocked()
+	callback := s.onUpdate
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(snapshot)
+	}
+	return snapshot
+}
+
+func (s *Service) snapshotLocked() *State {
+	now := s.now()
+	beats := int(now.Sub(s.downbeatAt) / beatDuration(s.bpm))
+	if beats < 0 {
+		beats = 0
+	}
+	return &State{
+		BPM:         s.bpm,
+		BeatsPerBar: s.beatsPerBar,
+		DownbeatAt:  s.downbeatAt,
+		Beat:        beats%s.beatsPerBar + 1,
+		Bar:         beats/s.beatsPerBar + 1,
+		TapCount:    len(s.taps),
+		Time:        now,
+		UpdatedAt:   s.updatedAt,
+	}
+}
+
+func beatDuration(bpm float64) time.Duration {
+	return time.Duration(float64(time.Minute) / bpm)
+}
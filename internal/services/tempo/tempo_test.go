@@ -0,0 +1,109 @@
+package tempo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a service whose clock is advanced manually.
+func fakeClock(s *Service) (time.Time, func(d time.Duration)) {
+	start := time.Now()
+	current := start
+	s.now = func() time.Time { return current }
+	return start, func(d time.Duration) { current = current.Add(d) }
+}
+
+func TestTapTempo(t *testing.T) {
+	s := NewService()
+	_, advance := fakeClock(s)
+
+	var updates int
+	s.SetUpdateCallback(func(state *State) { updates++ })
+
+	state := s.Tap()
+	if state.TapCount != 1 || state.BPM != DefaultBPM {
+		t.Errorf("First tap should only mark the beat, got %+v", state)
+	}
+	for i := 0; i < 3; i++ {
+		advance(600 * time.Millisecond)
+		state = s.Tap()
+	}
+	if math.Abs(state.BPM-100) > 0.001 {
+		t.Errorf("Expected 100 BPM from 600ms taps, got %v", state.BPM)
+	}
+	if state.Beat != 4 || state.Bar != 1 {
+		t.Errorf("Expected fourth tap on beat 4 of bar 1, got beat %d bar %d", state.Beat, state.Bar)
+	}
+	if updates != 4 {
+		t.Errorf("Expected 4 updates, got %d", updates)
+	}
+
+	// A long pause starts a new sequence without losing the tempo
+	advance(3 * time.Second)
+	state = s.Tap()
+	if state.TapCount != 1 || math.Abs(state.BPM-100) > 0.001 {
+		t.Errorf("Expected new tap sequence at 100 BPM, got %+v", state)
+	}
+	if state.Beat != 1 || !state.DownbeatAt.Equal(s.now()) {
+		t.Errorf("Expected new sequence to start on a downbeat, got %+v", state)
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	s := NewService()
+	start, advance := fakeClock(s)
+	s.Resync()
+
+	tests := []struct {
+		name string
+		at   time.Duration
+		q    Quantize
+		want time.Duration
+	}{
+		{"next beat", 100 * time.Millisecond, QuantizeBeat, 500 * time.Millisecond},
+		{"on a beat", time.Second, QuantizeBeat, time.Second},
+		{"next bar", 100 * time.Millisecond, QuantizeBar, 2 * time.Second},
+		{"later bar", 2100 * time.Millisecond, QuantizeBar, 4 * time.Second},
+		{"no quantize", 100 * time.Millisecond, QuantizeNone, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.NextBoundary(start.Add(tt.at), tt.q)
+			if !got.Equal(start.Add(tt.want)) {
+				t.Errorf("NextBoundary(+%v, %s) = +%v, want +%v", tt.at, tt.q, got.Sub(start), tt.want)
+			}
+		})
+	}
+
+	// Changing tempo keeps the current bar start
+	advance(2100 * time.Millisecond)
+	if _, err := s.SetBPM(60); err != nil {
+		t.Fatalf("SetBPM() error: %v", err)
+	}
+	if got := s.NextBoundary(s.now(), QuantizeBeat); !got.Equal(start.Add(3 * time.Second)) {
+		t.Errorf("Expected next beat one second after the bar start, got +%v", got.Sub(start))
+	}
+}
+
+func TestValidation(t *testing.T) {
+	s := NewService()
+
+	if _, err := s.SetBPM(MinBPM - 1); err == nil {
+		t.Error("Expected error for BPM below minimum")
+	}
+	if _, err := s.SetBPM(math.NaN()); err == nil {
+		t.Error("Expected error for NaN BPM")
+	}
+	if _, err := s.SetBeatsPerBar(0); err == nil {
+		t.Error("Expected error for zero beats per bar")
+	}
+	if _, err := s.SetBeatsPerBar(MaxBeatsPerBar + 1); err == nil {
+		t.Error("Expected error for too many beats per bar")
+	}
+
+	state, err := s.SetBeatsPerBar(3)
+	if err != nil || state.BeatsPerBar != 3 || state.Beat != 1 {
+		t.Errorf("Expected 3/4 starting on beat 1, got %+v, %v", state, err)
+	}
+}
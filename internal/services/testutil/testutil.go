@@ -41,17 +41,31 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
 		&models.InstanceChannel{},
 		&models.Scene{},
 		&models.FixtureValue{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
 		&models.CueList{},
 		&models.Cue{},
 		&models.SceneBoard{},
 		&models.SceneBoardButton{},
 		&models.Setting{},
+		&models.Universe{},
+		&models.LayoutZone{},
+		&models.SelectionSet{},
+		&models.HouseLights{},
+		&models.PixelMatrix{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate database: %v", err)
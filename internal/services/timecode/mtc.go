@@ -0,0 +1,115 @@
+package timecode
+
+// mtcEvent is what a MIDI byte completed.
+type mtcEvent int
+
+const (
+	mtcNone mtcEvent = iota
+	// mtcQuarterFrame is a quarter frame that did not complete a timecode
+	mtcQuarterFrame
+	// mtcRunning is the quarter frame completing a timecode while running
+	mtcRunning
+	// mtcFullFrame is a full-frame message, sent when the source locates
+	mtcFullFrame
+)
+
+// mtcRates maps the MTC rate code to a frame rate.
+var mtcRates = [4]FrameRate{FrameRate24, FrameRate25, FrameRate2997Drop, FrameRate30}
+
+// mtcParser decodes MIDI Timecode from a raw MIDI byte stream.
+type mtcParser struct {
+	quarterFrame bool // The next data byte is a quarter-frame piece
+	pieces       [8]byte
+	received     byte // Bit n is set once piece n has arrived
+
+	sysex   []byte
+	inSysex bool
+}
+
+// feed consumes one MIDI byte. For mtcRunning and mtcFullFrame it returns
+// the timecode and its frame rate; a running timecode is already advanced by
+// the two frames its eight pieces took to arrive.
+func (p *mtcParser) feed(b byte) (mtcEvent, Timecode, FrameRate) {
+	switch {
+	case b >= 0xF8:
+		// Real-time messages may appear anywhere, even inside SysEx
+		return mtcNone, Timecode{}, ""
+	case b == 0xF0:
+		p.quarterFrame = false
+		p.inSysex = true
+		p.sysex = p.sysex[:0]
+		return mtcNone, Timecode{}, ""
+	case b == 0xF7:
+		p.inSysex = false
+		return p.fullFrame()
+	case b == 0xF1:
+		p.quarterFrame = true
+		p.inSysex = false
+		return mtcNone, Timecode{}, ""
+	case b >= 0x80:
+		// Any other status byte ends what came before
+		p.quarterFrame = false
+		p.inSysex = false
+		return mtcNone, Timecode{}, ""
+	case p.inSysex:
+		if len(p.sysex) < 16 {
+			p.sysex = append(p.sysex, b)
+		}
+		return mtcNone, Timecode{}, ""
+	case p.quarterFrame:
+		p.quarterFrame = false
+		return p.quarterFramePiece(b)
+	}
+	return mtcNone, Timecode{}, ""
+}
+
+func (p *mtcParser) quarterFramePiece(b byte) (mtcEvent, Timecode, FrameRate) {
+	piece := b >> 4
+	if piece == 0 {
+		// Each timecode starts at piece 0; drop pieces from a broken sequence
+		p.received = 0
+	}
+	p.pieces[piece] = b & 0x0F
+	p.received |= 1 << piece
+	if piece != 7 || p.received != 0xFF {
+		return mtcQuarterFrame, Timecode{}, ""
+	}
+	p.received = 0
+
+	rate := mtcRates[(p.pieces[7]>>1)&0x03]
+	tc := Timecode{
+		Frames:  int(p.pieces[0] | (p.pieces[1]&0x01)<<4),
+		Seconds: int(p.pieces[2] | (p.pieces[3]&0x03)<<4),
+		Minutes: int(p.pieces[4] | (p.pieces[5]&0x03)<<4),
+		Hours:   int(p.pieces[6] | (p.pieces[7]&0x01)<<4),
+	}
+	if !tc.valid() {
+		return mtcQuarterFrame, Timecode{}, ""
+	}
+	advanced := FromPosition(tc.Position(rate)+2*rate.frameDuration(), rate)
+	return mtcRunning, advanced, rate
+}
+
+// fullFrame decodes a completed SysEx as a full-frame message:
+// F0 7F <device> 01 01 hh mm ss ff F7.
+func (p *mtcParser) fullFrame() (mtcEvent, Timecode, FrameRate) {
+	data := p.sysex
+	if len(data) != 8 || data[0] != 0x7F || data[2] != 0x01 || data[3] != 0x01 {
+		return mtcNone, Timecode{}, ""
+	}
+	rate := mtcRates[(data[4]>>5)&0x03]
+	tc := Timecode{
+		Hours:   int(data[4] & 0x1F),
+		Minutes: int(data[5]),
+		Seconds: int(data[6]),
+		Frames:  int(data[7]),
+	}
+	if !tc.valid() {
+		return mtcNone, Timecode{}, ""
+	}
+	return mtcFullFrame, tc, rate
+}
+
+func (t Timecode) valid() bool {
+	return t.Hours <= 23 && t.Minutes <= 59 && t.Seconds <= 59 && t.Frames <= 29
+}
@@ -0,0 +1,511 @@
+package timecode
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SettingKey is the setting that stores the timecode configuration as JSON.
+const SettingKey = "timecode_config"
+
+// Source selects where the timecode comes from.
+type Source string
+
+const (
+	// SourceMTC follows MIDI Timecode from a raw MIDI device.
+	SourceMTC Source = "MTC"
+	// SourceInternal runs an internal clock started, stopped, and located
+	// through the transport controls.
+	SourceInternal Source = "INTERNAL"
+	// SourceSystemClock follows the system time of day.
+	SourceSystemClock Source = "SYSTEM_CLOCK"
+)
+
+const (
+	// tickInterval is how often the position is checked against triggers.
+	tickInterval = 10 * time.Millisecond
+	// updateInterval is how often a running timecode publishes its status.
+	updateInterval = time.Second
+	// mtcDropout is how long after the last quarter frame MTC counts as stopped.
+	mtcDropout = 250 * time.Millisecond
+	// jumpThreshold is the largest forward step between ticks that still
+	// counts as playing through; anything larger is a jump.
+	jumpThreshold = time.Second
+	// jitterTolerance is how far back the position may step without counting
+	// as a jump, since MTC re-anchors the clock every two frames.
+	jitterTolerance = 100 * time.Millisecond
+)
+
+// Config is the timecode configuration.
+type Config struct {
+	Enabled   bool      `json:"enabled"`
+	Source    Source    `json:"source"`
+	FrameRate FrameRate `json:"frameRate"`
+	// MIDIDevice is the raw MIDI device MTC is read from, e.g. /dev/snd/midiC1D0
+	MIDIDevice string `json:"midiDevice,omitempty"`
+	// CueListID is the cue list whose cue timecodes fire (optional)
+	CueListID string `json:"cueListId,omitempty"`
+	// ChaseToTime makes a jump in timecode go straight to the cue that would
+	// be live at the new position instead of waiting for the next trigger
+	ChaseToTime bool `json:"chaseToTime"`
+	// FallbackToInternal keeps time running on the internal clock from the
+	// last MTC position when MTC stops arriving
+	FallbackToInternal bool `json:"fallbackToInternal"`
+}
+
+// DefaultConfig returns the configuration used until one is set.
+func DefaultConfig() Config {
+	return Config{Source: SourceInternal, FrameRate: FrameRate30}
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	switch c.Source {
+	case SourceMTC, SourceInternal, SourceSystemClock:
+	default:
+		return fmt.Errorf("unknown timecode source %q", c.Source)
+	}
+	if !c.FrameRate.Valid() {
+		return fmt.Errorf("unknown frame rate %q", c.FrameRate)
+	}
+	if c.Source == SourceMTC && c.MIDIDevice == "" {
+		return fmt.Errorf("MTC requires a MIDI device")
+	}
+	return nil
+}
+
+// Trigger fires a cue when the timecode reaches a position.
+type Trigger struct {
+	CueNumber float64
+	Position  time.Duration
+}
+
+// TriggerHandler fires a trigger of a cue list. elapsed is how far the
+// timecode already is past the trigger's position, which is more than a tick
+// only when chasing.
+type TriggerHandler func(cueListID string, trigger Trigger, elapsed time.Duration)
+
+// Status is a snapshot of the timecode.
+type Status struct {
+	Config Config
+	// Position is nil until a position is known
+	Position *Timecode
+	Running  bool
+	// Freewheeling is true while MTC has stopped and the internal clock
+	// carries on in its place
+	Freewheeling bool
+	LastMTCAt    *time.Time
+	DeviceError  *string
+}
+
+// Service follows the configured timecode and fires triggers as it passes
+// them. It is safe for concurrent use.
+type Service struct {
+	mu       sync.Mutex
+	config   Config
+	triggers []Trigger // Ordered by position
+
+	// The clock is at position as of anchor, advancing while running
+	known    bool
+	position time.Duration
+	anchor   time.Time
+	running  bool
+
+	mtc       mtcParser
+	lastMTCAt *time.Time
+	device    io.ReadCloser
+	deviceErr string
+
+	// Position checked by the previous tick; triggers from here on are due
+	lastPosition  *time.Duration
+	lastRunning   bool
+	lastFreewheel bool
+	lastUpdate    time.Time
+	stopTicking   chan struct{}
+
+	onTrigger TriggerHandler
+	onUpdate  func(status *Status)
+
+	now        func() time.Time
+	openDevice func(path string) (io.ReadCloser, error)
+}
+
+// NewService creates a timecode service with the default configuration.
+func NewService() *Service {
+	return &Service{
+		config: DefaultConfig(),
+		known:  true,
+		now:    time.Now,
+		openDevice: func(path string) (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// SetTriggerHandler sets the handler for triggers reached.
+func (s *Service) SetTriggerHandler(handler TriggerHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTrigger = handler
+}
+
+// SetUpdateCallback sets the callback for status changes. While running, it
+// is also called every second.
+func (s *Service) SetUpdateCallback(callback func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = callback
+}
+
+// SetTriggers replaces the triggers of the configured cue list.
+func (s *Service) SetTriggers(triggers []Trigger) {
+	sorted := append([]Trigger(nil), triggers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.triggers = sorted
+}
+
+// Config returns the current configuration.
+func (s *Service) Config() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+// Status returns the current timecode state.
+func (s *Service) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked(s.now())
+}
+
+// SetConfig validates and applies a configuration. Changing the source
+// resets the clock; the internal clock starts stopped at 00:00:00:00.
+func (s *Service) SetConfig(config Config) (*Status, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	previous := s.config
+	s.config = config
+
+	if config.Source != previous.Source {
+		s.known = config.Source != SourceMTC
+		s.position = 0
+		s.anchor = s.now()
+		s.running = false
+		s.lastMTCAt = nil
+		s.mtc = mtcParser{}
+	}
+	s.lastPosition = nil
+
+	if !config.Enabled || config.Source != SourceMTC || config.MIDIDevice != previous.MIDIDevice {
+		s.closeDeviceLocked()
+	}
+	if config.Enabled && config.Source == SourceMTC && s.device == nil {
+		s.openDeviceLocked()
+	}
+
+	if config.Enabled && s.stopTicking == nil {
+		s.startTickingLocked()
+	} else if !config.Enabled {
+		s.stopTickingLocked()
+	}
+	status := s.snapshotLocked(s.now())
+	s.mu.Unlock()
+
+	s.emitUpdate(status)
+	return status, nil
+}
+
+// Play starts the internal clock.
+func (s *Service) Play() (*Status, error) {
+	return s.transport(func(now time.Time) {
+		if !s.running {
+			s.anchor = now
+			s.running = true
+		}
+	})
+}
+
+// Stop stops the internal clock, keeping its position.
+func (s *Service) Stop() (*Status, error) {
+	return s.transport(func(now time.Time) {
+		if s.running {
+			s.position = wrap(s.position + now.Sub(s.anchor))
+			s.running = false
+		}
+	})
+}
+
+// Locate moves the internal clock to a timecode, running or not.
+func (s *Service) Locate(tc Timecode) (*Status, error) {
+	return s.transport(func(now time.Time) {
+		s.position = tc.Position(s.config.FrameRate)
+		s.anchor = now
+	})
+}
+
+func (s *Service) transport(apply func(now time.Time)) (*Status, error) {
+	s.mu.Lock()
+	if s.config.Source != SourceInternal {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("transport controls need the %s source", SourceInternal)
+	}
+	now := s.now()
+	apply(now)
+	status := s.snapshotLocked(now)
+	s.mu.Unlock()
+
+	s.emitUpdate(status)
+	return status, nil
+}
+
+// Cleanup stops following the timecode.
+func (s *Service) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopTickingLocked()
+	s.closeDeviceLocked()
+}
+
+// clockLocked returns the position at now, whether time is advancing, and
+// whether the internal clock is standing in for MTC. ok is false until a
+// position is known.
+func (s *Service) clockLocked(now time.Time) (position time.Duration, running, freewheel, ok bool) {
+	switch s.config.Source {
+	case SourceSystemClock:
+		year, month, day := now.Date()
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+		return now.Sub(midnight), true, false, true
+	case SourceMTC:
+		if !s.known {
+			return 0, false, false, false
+		}
+		if s.running && s.lastMTCAt != nil && now.Sub(*s.lastMTCAt) > mtcDropout {
+			if !s.config.FallbackToInternal {
+				return wrap(s.position + s.lastMTCAt.Sub(s.anchor)), false, false, true
+			}
+			freewheel = true
+		}
+	}
+	if !s.running {
+		return s.position, false, false, true
+	}
+	return wrap(s.position + now.Sub(s.anchor)), true, freewheel, true
+}
+
+func (s *Service) startTickingLocked() {
+	ticker := time.NewTicker(tickInterval)
+	stop := make(chan struct{})
+	s.stopTicking = stop
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.tick()
+			}
+		}
+	}()
+}
+
+func (s *Service) stopTickingLocked() {
+	if s.stopTicking != nil {
+		close(s.stopTicking)
+		s.stopTicking = nil
+	}
+}
+
+// tick fires the trigger reached since the previous tick and publishes
+// status changes.
+func (s *Service) tick() {
+	s.mu.Lock()
+	now := s.now()
+	position, running, freewheel, ok := s.clockLocked(now)
+
+	var due *Trigger
+	var elapsed time.Duration
+	if ok {
+		due, elapsed = s.advanceLocked(position)
+	} else {
+		s.lastPosition = nil
+	}
+
+	var status *Status
+	changed := running != s.lastRunning || freewheel != s.lastFreewheel
+	if changed || (running && now.Sub(s.lastUpdate) >= updateInterval) {
+		s.lastRunning, s.lastFreewheel, s.lastUpdate = running, freewheel, now
+		status = s.snapshotLocked(now)
+	}
+	cueListID := s.config.CueListID
+	onTrigger := s.onTrigger
+	s.mu.Unlock()
+
+	if due != nil && cueListID != "" && onTrigger != nil {
+		onTrigger(cueListID, *due, elapsed)
+	}
+	s.emitUpdate(status)
+}
+
+// advanceLocked moves the checked position to position and returns the
+// trigger due, if any. Playing through fires the last trigger passed; a
+// jump fires only when chasing, with the trigger live at the new position.
+func (s *Service) advanceLocked(position time.Duration) (*Trigger, time.Duration) {
+	if last := s.lastPosition; last != nil {
+		delta := position - *last
+		if delta < 0 && delta >= -jitterTolerance {
+			return nil, 0
+		}
+		if delta >= 0 && delta <= jumpThreshold {
+			s.lastPosition = &position
+			// Triggers in [last, position) are due
+			var due *Trigger
+			for i := range s.triggers {
+				trigger := s.triggers[i]
+				if trigger.Position >= position {
+					break
+				}
+				if trigger.Position >= *last {
+					due = &trigger
+				}
+			}
+			if due == nil {
+				return nil, 0
+			}
+			return due, position - due.Position
+		}
+	}
+
+	s.lastPosition = &position
+	if !s.config.ChaseToTime {
+		return nil, 0
+	}
+	var live *Trigger
+	for i := range s.triggers {
+		trigger := s.triggers[i]
+		if trigger.Position > position {
+			break
+		}
+		live = &trigger
+	}
+	if live == nil {
+		return nil, 0
+	}
+	// Everything up to here is consumed, including a trigger right at it
+	consumed := position + 1
+	s.lastPosition = &consumed
+	return live, position - live.Position
+}
+
+func (s *Service) openDeviceLocked() {
+	device, err := s.openDevice(s.config.MIDIDevice)
+	if err != nil {
+		s.deviceErr = err.Error()
+		log.Warn("cannot open MIDI device for timecode", "device", s.config.MIDIDevice, "error", err)
+		return
+	}
+	s.device = device
+	s.deviceErr = ""
+	log.Info("⏱️ Reading MIDI Timecode", "device", s.config.MIDIDevice)
+	go s.readDevice(device)
+}
+
+func (s *Service) closeDeviceLocked() {
+	if s.device != nil {
+		_ = s.device.Close()
+		s.device = nil
+	}
+	s.deviceErr = ""
+}
+
+func (s *Service) readDevice(device io.ReadCloser) {
+	buffer := make([]byte, 256)
+	for {
+		n, err := device.Read(buffer)
+		if n > 0 {
+			s.handleMIDI(device, buffer[:n])
+		}
+		if err != nil {
+			s.mu.Lock()
+			if s.device == device {
+				s.device = nil
+				s.deviceErr = err.Error()
+				log.Warn("MIDI device closed", "device", s.config.MIDIDevice, "error", err)
+			}
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// handleMIDI decodes MIDI bytes read from device into the MTC clock.
+func (s *Service) handleMIDI(device io.ReadCloser, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device != device || s.config.Source != SourceMTC {
+		return // Closed while reading
+	}
+
+	for _, b := range data {
+		event, tc, rate := s.mtc.feed(b)
+		if event == mtcNone {
+			continue
+		}
+		now := s.now()
+		s.lastMTCAt = &now
+		switch event {
+		case mtcRunning:
+			s.known = true
+			s.position = tc.Position(rate)
+			s.anchor = now
+			s.running = true
+		case mtcFullFrame:
+			s.known = true
+			s.position = tc.Position(rate)
+			s.anchor = now
+			s.running = false
+		}
+	}
+}
+
+func (s *Service) snapshotLocked(now time.Time) *Status {
+	status := &Status{Config: s.config}
+	if position, running, freewheel, ok := s.clockLocked(now); ok {
+		tc := FromPosition(position, s.config.FrameRate)
+		status.Position = &tc
+		status.Running = running
+		status.Freewheeling = freewheel
+	}
+	if s.lastMTCAt != nil {
+		lastMTCAt := *s.lastMTCAt
+		status.LastMTCAt = &lastMTCAt
+	}
+	if s.deviceErr != "" {
+		deviceErr := s.deviceErr
+		status.DeviceError = &deviceErr
+	}
+	return status
+}
+
+func (s *Service) emitUpdate(status *Status) {
+	if status == nil {
+		return
+	}
+	s.mu.Lock()
+	callback := s.onUpdate
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(status)
+	}
+}
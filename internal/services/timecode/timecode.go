@@ -0,0 +1,137 @@
+// Package timecode follows a show timecode and fires cues at timecode
+// positions. The timecode comes from MIDI Timecode (MTC) read from a raw MIDI
+// device, from an internal clock with its own transport, or from the system
+// time of day.
+package timecode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameRate is a timecode frame rate.
+type FrameRate string
+
+const (
+	// FrameRate24 is 24 fps (film).
+	FrameRate24 FrameRate = "FPS_24"
+	// FrameRate25 is 25 fps (PAL).
+	FrameRate25 FrameRate = "FPS_25"
+	// FrameRate2997Drop is 29.97 fps drop frame (NTSC).
+	FrameRate2997Drop FrameRate = "FPS_29_97_DROP"
+	// FrameRate30 is 30 fps non-drop.
+	FrameRate30 FrameRate = "FPS_30"
+)
+
+// Valid reports whether r is a known frame rate.
+func (r FrameRate) Valid() bool {
+	switch r {
+	case FrameRate24, FrameRate25, FrameRate2997Drop, FrameRate30:
+		return true
+	}
+	return false
+}
+
+// FPS returns the number of frames labelled in each second.
+func (r FrameRate) FPS() int {
+	switch r {
+	case FrameRate24:
+		return 24
+	case FrameRate25:
+		return 25
+	default:
+		return 30
+	}
+}
+
+// frameDuration is the length of one frame.
+func (r FrameRate) frameDuration() time.Duration {
+	return time.Second / time.Duration(r.FPS())
+}
+
+// Day is the length of the timecode day; positions wrap at 24 hours.
+const Day = 24 * time.Hour
+
+// Timecode is an HH:MM:SS:FF timecode label.
+//
+// Positions are label times at the nominal frame rate, so a drop-frame
+// label maps to the same position however it is reached; that is all cue
+// matching needs.
+type Timecode struct {
+	Hours   int
+	Minutes int
+	Seconds int
+	Frames  int
+}
+
+// Parse parses "HH:MM:SS:FF". A ';' or '.' before the frames, as written for
+// drop-frame timecode, is also accepted.
+func Parse(s string) (Timecode, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexAny(s, ";."); i >= 0 {
+		s = s[:i] + ":" + s[i+1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return Timecode{}, fmt.Errorf("invalid timecode %q: expected HH:MM:SS:FF", s)
+	}
+	var values [4]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || len(part) > 2 {
+			return Timecode{}, fmt.Errorf("invalid timecode %q: expected HH:MM:SS:FF", s)
+		}
+		values[i] = n
+	}
+	tc := Timecode{Hours: values[0], Minutes: values[1], Seconds: values[2], Frames: values[3]}
+	if !tc.valid() {
+		return Timecode{}, fmt.Errorf("invalid timecode %q: out of range", s)
+	}
+	return tc, nil
+}
+
+// String formats the timecode as HH:MM:SS:FF.
+func (t Timecode) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", t.Hours, t.Minutes, t.Seconds, t.Frames)
+}
+
+// Position returns the time the label stands for at rate. Frames beyond
+// the rate's last frame count as the last frame.
+func (t Timecode) Position(rate FrameRate) time.Duration {
+	frames := t.Frames
+	if frames >= rate.FPS() {
+		frames = rate.FPS() - 1
+	}
+	return time.Duration(t.Hours)*time.Hour +
+		time.Duration(t.Minutes)*time.Minute +
+		time.Duration(t.Seconds)*time.Second +
+		time.Duration(frames)*rate.frameDuration()
+}
+
+// FromPosition returns the label of the frame containing position at rate,
+// wrapping at 24 hours.
+func FromPosition(position time.Duration, rate FrameRate) Timecode {
+	position = wrap(position)
+	seconds := position / time.Second
+	frames := int((position % time.Second) / rate.frameDuration())
+	if frames >= rate.FPS() {
+		frames = rate.FPS() - 1 // Rounding in the frame duration
+	}
+	return Timecode{
+		Hours:   int(seconds / 3600),
+		Minutes: int(seconds / 60 % 60),
+		Seconds: int(seconds % 60),
+		Frames:  frames,
+	}
+}
+
+// wrap brings a position into the timecode day.
+func wrap(position time.Duration) time.Duration {
+	position %= Day
+	if position < 0 {
+		position += Day
+	}
+	return position
+}
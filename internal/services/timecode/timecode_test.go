@@ -0,0 +1,324 @@
+package timecode
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a service whose clock is advanced manually.
+func fakeClock(s *Service) func(d time.Duration) {
+	current := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return current }
+	return func(d time.Duration) { current = current.Add(d) }
+}
+
+// fired records the triggers a service fires.
+type fired struct {
+	cueNumbers []float64
+	elapsed    []time.Duration
+}
+
+func recordTriggers(s *Service) *fired {
+	f := &fired{}
+	s.SetTriggerHandler(func(cueListID string, trigger Trigger, elapsed time.Duration) {
+		f.cueNumbers = append(f.cueNumbers, trigger.CueNumber)
+		f.elapsed = append(f.elapsed, elapsed)
+	})
+	return f
+}
+
+// quarterFrames encodes a timecode as the eight MTC quarter-frame messages.
+func quarterFrames(tc Timecode, rateCode byte) []byte {
+	values := []byte{
+		byte(tc.Frames) & 0x0F, byte(tc.Frames) >> 4,
+		byte(tc.Seconds) & 0x0F, byte(tc.Seconds) >> 4,
+		byte(tc.Minutes) & 0x0F, byte(tc.Minutes) >> 4,
+		byte(tc.Hours) & 0x0F, byte(tc.Hours)>>4 | rateCode<<1,
+	}
+	var data []byte
+	for piece, value := range values {
+		data = append(data, 0xF1, byte(piece)<<4|value)
+	}
+	return data
+}
+
+func mustParse(t *testing.T, s string) Timecode {
+	t.Helper()
+	tc, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) error: %v", s, err)
+	}
+	return tc
+}
+
+func TestParseAndFormat(t *testing.T) {
+	tc := mustParse(t, "01:02:03:04")
+	if tc != (Timecode{1, 2, 3, 4}) || tc.String() != "01:02:03:04" {
+		t.Errorf("Parse = %+v (%s)", tc, tc)
+	}
+	if dropFrame := mustParse(t, "10:00:00;02"); dropFrame != (Timecode{10, 0, 0, 2}) {
+		t.Errorf("Parse drop frame = %+v", dropFrame)
+	}
+
+	for _, bad := range []string{"", "1:2:3", "24:00:00:00", "00:60:00:00", "00:00:00:30", "aa:00:00:00", "00:00:00:-1"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) expected an error", bad)
+		}
+	}
+
+	if got := tc.Position(FrameRate25); got != time.Hour+2*time.Minute+3*time.Second+160*time.Millisecond {
+		t.Errorf("Position = %v", got)
+	}
+	if got := FromPosition(tc.Position(FrameRate24), FrameRate24); got != tc {
+		t.Errorf("FromPosition round trip = %s, want %s", got, tc)
+	}
+	if got := FromPosition(Day+time.Second-time.Nanosecond, FrameRate24); got != (Timecode{0, 0, 0, 23}) {
+		t.Errorf("FromPosition wraps and clamps = %s", got)
+	}
+}
+
+func TestMTCParser(t *testing.T) {
+	var p mtcParser
+	var events []mtcEvent
+	var last Timecode
+	var lastRate FrameRate
+	feed := func(data []byte) {
+		for _, b := range data {
+			event, tc, rate := p.feed(b)
+			if event == mtcRunning || event == mtcFullFrame {
+				last, lastRate = tc, rate
+			}
+			if event != mtcNone {
+				events = append(events, event)
+			}
+		}
+	}
+
+	// Running: the completed timecode is two frames on from the one sent,
+	// and a real-time clock byte in the middle changes nothing
+	data := quarterFrames(Timecode{1, 0, 59, 24}, 1)
+	data = append(data[:5], append([]byte{0xF8}, data[5:]...)...)
+	feed(data)
+	if len(events) != 8 || events[7] != mtcRunning {
+		t.Fatalf("events = %v", events)
+	}
+	if last != (Timecode{1, 1, 0, 1}) || lastRate != FrameRate25 {
+		t.Errorf("running timecode = %s at %s, want 01:01:00:01 at FPS_25", last, lastRate)
+	}
+
+	// A broken sequence does not complete
+	events = nil
+	feed(quarterFrames(Timecode{2, 0, 0, 0}, 3)[4:])
+	for _, event := range events {
+		if event == mtcRunning {
+			t.Error("Expected an incomplete sequence to be ignored")
+		}
+	}
+
+	// Full frame: F0 7F <device> 01 01 hh mm ss ff F7, 29.97 drop frame
+	events = nil
+	feed([]byte{0xF0, 0x7F, 0x7F, 0x01, 0x01, 2<<5 | 10, 30, 15, 12, 0xF7})
+	if len(events) != 1 || events[0] != mtcFullFrame || last != (Timecode{10, 30, 15, 12}) || lastRate != FrameRate2997Drop {
+		t.Errorf("full frame = %v %s %s", events, last, lastRate)
+	}
+
+	// Other SysEx is not a full frame
+	events = nil
+	feed([]byte{0xF0, 0x7E, 0x7F, 0x06, 0x01, 0xF7})
+	if len(events) != 0 {
+		t.Errorf("events for unrelated SysEx = %v", events)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	s := NewService()
+	defer s.Cleanup()
+
+	for _, config := range []Config{
+		{Source: "TAPE", FrameRate: FrameRate30},
+		{Source: SourceInternal, FrameRate: "FPS_60"},
+		{Source: SourceMTC, FrameRate: FrameRate25},
+	} {
+		if _, err := s.SetConfig(config); err == nil {
+			t.Errorf("SetConfig(%+v) expected an error", config)
+		}
+	}
+	if _, err := s.Play(); err != nil {
+		t.Errorf("Play() on the default internal clock: %v", err)
+	}
+	if _, err := s.SetConfig(Config{Source: SourceSystemClock, FrameRate: FrameRate30}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	if _, err := s.Play(); err == nil {
+		t.Error("Expected transport controls to be rejected for the system clock")
+	}
+}
+
+func TestInternalClockFiresTriggers(t *testing.T) {
+	s := NewService()
+	advance := fakeClock(s)
+	f := recordTriggers(s)
+	if _, err := s.SetConfig(Config{Source: SourceInternal, FrameRate: FrameRate30, CueListID: "list"}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	s.SetTriggers([]Trigger{
+		{CueNumber: 2, Position: 2 * time.Second},
+		{CueNumber: 1, Position: 0},
+		{CueNumber: 3, Position: 2*time.Second + 500*time.Millisecond},
+	})
+
+	s.tick()
+	if _, err := s.Play(); err != nil {
+		t.Fatalf("Play() error: %v", err)
+	}
+	advance(10 * time.Millisecond)
+	s.tick()
+	if len(f.cueNumbers) != 1 || f.cueNumbers[0] != 1 {
+		t.Fatalf("Expected cue 1 at the start, got %v", f.cueNumbers)
+	}
+
+	// Passing two triggers in one step fires only the later one
+	advance(3 * time.Second / 4)
+	s.tick()
+	advance(3 * time.Second / 4)
+	s.tick()
+	advance(time.Second)
+	s.tick()
+	if len(f.cueNumbers) != 2 || f.cueNumbers[1] != 3 {
+		t.Fatalf("Expected cue 3 next, got %v", f.cueNumbers)
+	}
+
+	status, err := s.Stop()
+	if err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	if status.Running || status.Position.String() != "00:00:02:15" {
+		t.Errorf("Stopped status = %+v at %s", status, status.Position)
+	}
+	advance(time.Second)
+	s.tick()
+	if got := s.Status().Position.String(); got != "00:00:02:15" {
+		t.Errorf("Position moved while stopped: %s", got)
+	}
+
+	// Without chase, a locate fires nothing until play reaches a trigger
+	if _, err := s.Locate(mustParse(t, "00:00:02:00")); err != nil {
+		t.Fatalf("Locate() error: %v", err)
+	}
+	s.tick()
+	if len(f.cueNumbers) != 2 {
+		t.Errorf("Expected no trigger on locate, got %v", f.cueNumbers)
+	}
+	_, _ = s.Play()
+	advance(10 * time.Millisecond)
+	s.tick()
+	if len(f.cueNumbers) != 3 || f.cueNumbers[2] != 2 {
+		t.Errorf("Expected cue 2 once play resumes, got %v", f.cueNumbers)
+	}
+}
+
+func TestChaseToTime(t *testing.T) {
+	s := NewService()
+	advance := fakeClock(s)
+	f := recordTriggers(s)
+	if _, err := s.SetConfig(Config{Source: SourceInternal, FrameRate: FrameRate25, CueListID: "list", ChaseToTime: true}); err != nil {
+		t.Fatalf("SetConfig() error: %v", err)
+	}
+	s.SetTriggers([]Trigger{{CueNumber: 1, Position: time.Minute}, {CueNumber: 2, Position: 2 * time.Minute}})
+
+	s.tick()
+	if len(f.cueNumbers) != 0 {
+		t.Fatalf("Expected nothing live before the first trigger, got %v", f.cueNumbers)
+	}
+
+	// Jumping into the middle of cue 1 chases to it with the time already run
+	_, _ = s.Locate(mustParse(t, "00:01:30:00"))
+	s.tick()
+	if len(f.cueNumbers) != 1 || f.cueNumbers[0] != 1 || f.elapsed[0] != 30*time.Second {
+		t.Fatalf("Expected a chase to cue 1 30s in, got %v %v", f.cueNumbers, f.elapsed)
+	}
+
+	// Landing right on a trigger fires it once, not again on play
+	_, _ = s.Locate(mustParse(t, "00:02:00:00"))
+	s.tick()
+	_, _ = s.Play()
+	advance(10 * time.Millisecond)
+	s.tick()
+	if len(f.cueNumbers) != 2 || f.cueNumbers[1] != 2 || f.elapsed[1] != 0 {
+		t.Errorf("Expected a single chase to cue 2, got %v %v", f.cueNumbers, f.elapsed)
+	}
+
+	// Jumping back chases back
+	_, _ = s.Locate(mustParse(t, "00:01:00:00"))
+	s.tick()
+	if len(f.cueNumbers) != 3 || f.cueNumbers[2] != 1 {
+		t.Errorf("Expected a chase back to cue 1, got %v", f.cueNumbers)
+	}
+}
+
+// nopDevice stands in for an open MIDI device fed through handleMIDI.
+type nopDevice struct{ io.Reader }
+
+func (nopDevice) Close() error { return nil }
+
+func TestMTCFollowAndFallback(t *testing.T) {
+	s := NewService()
+	advance := fakeClock(s)
+	f := recordTriggers(s)
+	device := nopDevice{}
+
+	config := Config{Source: SourceMTC, FrameRate: FrameRate25, MIDIDevice: "/dev/midi1", CueListID: "list"}
+	attach := func() {
+		if _, err := s.SetConfig(config); err != nil {
+			t.Fatalf("SetConfig() error: %v", err)
+		}
+		// Disabled, so no device is opened; feed it in directly
+		s.mu.Lock()
+		s.device = device
+		s.mu.Unlock()
+	}
+	attach()
+	s.SetTriggers([]Trigger{{CueNumber: 1, Position: time.Hour + 100*time.Millisecond}})
+
+	if status := s.Status(); status.Position != nil {
+		t.Fatalf("Expected no position before MTC arrives, got %s", status.Position)
+	}
+
+	// 01:00:00:00 arrives, two frames on by the time it completes
+	s.handleMIDI(device, quarterFrames(Timecode{1, 0, 0, 0}, 1))
+	s.tick()
+	status := s.Status()
+	if !status.Running || status.Position.String() != "01:00:00:02" || status.LastMTCAt == nil {
+		t.Fatalf("Status after MTC = %+v at %s", status, status.Position)
+	}
+	advance(100 * time.Millisecond)
+	s.handleMIDI(device, quarterFrames(Timecode{1, 0, 0, 2}, 1))
+	s.tick()
+	if len(f.cueNumbers) != 1 {
+		t.Fatalf("Expected the trigger to fire under MTC, got %v", f.cueNumbers)
+	}
+
+	// MTC stops: without fallback the time stops with it
+	advance(time.Second)
+	s.tick()
+	status = s.Status()
+	if status.Running || status.Position.String() != "01:00:00:04" {
+		t.Errorf("Status after MTC stops = %+v at %s", status, status.Position)
+	}
+
+	// With fallback the internal clock carries on
+	config.FallbackToInternal = true
+	attach()
+	status = s.Status()
+	if !status.Running || !status.Freewheeling || status.Position.String() != "01:00:01:04" {
+		t.Errorf("Status while freewheeling = %+v at %s", status, status.Position)
+	}
+
+	// A full frame locates and stops the clock
+	s.handleMIDI(device, []byte{0xF0, 0x7F, 0x7F, 0x01, 0x01, 1<<5 | 2, 0, 0, 0, 0xF7})
+	status = s.Status()
+	if status.Running || status.Position.String() != "02:00:00:00" {
+		t.Errorf("Status after a full frame = %+v at %s", status, status.Position)
+	}
+}
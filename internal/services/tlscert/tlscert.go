@@ -0,0 +1,108 @@
+// Package tlscert provides the certificate the server terminates TLS with:
+// one from configured files, a self-signed one generated on first boot, or
+// one issued by an ACME authority such as Let's Encrypt.
+package tlscert
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Mode is where the server's certificate comes from.
+type Mode string
+
+const (
+	// ModeOff serves plain HTTP.
+	ModeOff Mode = "OFF"
+	// ModeFile serves the certificate and key in the configured files.
+	ModeFile Mode = "FILE"
+	// ModeSelfSigned serves a self-signed certificate, generated when the
+	// configured files are missing or about to expire.
+	ModeSelfSigned Mode = "SELF_SIGNED"
+	// ModeACME serves certificates issued by an ACME authority for the
+	// configured domains.
+	ModeACME Mode = "ACME"
+)
+
+// ParseMode converts a configuration string such as "self-signed" to a
+// Mode. An empty string is ModeOff.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(value)), "-", "_")); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeFile, ModeSelfSigned, ModeACME:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid TLS mode %q (want off, file, self-signed, or acme)", value)
+	}
+}
+
+// Config configures the server's certificate.
+type Config struct {
+	Mode Mode
+	// CertFile and KeyFile hold the PEM certificate and key for ModeFile,
+	// and the generated ones for ModeSelfSigned
+	CertFile string
+	KeyFile  string
+	// ACMEDomains are the names certificates are requested for
+	ACMEDomains []string
+	// ACMEEmail is the contact address given to the authority (optional)
+	ACMEEmail string
+	// ACMECacheDir keeps the account key and issued certificates
+	ACMECacheDir string
+	// ACMEDirectoryURL is the authority's directory (default: Let's Encrypt)
+	ACMEDirectoryURL string
+}
+
+// ServerConfig returns the TLS configuration to serve with, offering
+// HTTP/2, or nil for ModeOff.
+func ServerConfig(cfg Config) (*tls.Config, error) {
+	switch cfg.Mode {
+	case ModeOff, "":
+		return nil, nil
+	case ModeFile:
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		return certificateConfig(cert), nil
+	case ModeSelfSigned:
+		cert, err := LoadOrCreateSelfSigned(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return certificateConfig(cert), nil
+	case ModeACME:
+		if len(cfg.ACMEDomains) == 0 {
+			return nil, fmt.Errorf("ACME needs at least one domain")
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+			Cache:      autocert.DirCache(cfg.ACMECacheDir),
+			Email:      cfg.ACMEEmail,
+		}
+		if cfg.ACMEDirectoryURL != "" {
+			manager.Client = &acme.Client{DirectoryURL: cfg.ACMEDirectoryURL}
+		}
+		config := manager.TLSConfig()
+		config.MinVersion = tls.VersionTLS12
+		log.Info("certificates from ACME", "domains", cfg.ACMEDomains, "cache", cfg.ACMECacheDir)
+		return config, nil
+	default:
+		return nil, fmt.Errorf("invalid TLS mode %q", cfg.Mode)
+	}
+}
+
+// certificateConfig serves one certificate over HTTP/2 or HTTP/1.1.
+func certificateConfig(cert tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
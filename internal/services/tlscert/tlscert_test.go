@@ -0,0 +1,157 @@
+package tlscert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Mode
+	}{
+		{"", ModeOff},
+		{"off", ModeOff},
+		{"file", ModeFile},
+		{"self-signed", ModeSelfSigned},
+		{"SELF_SIGNED", ModeSelfSigned},
+		{" acme ", ModeACME},
+	}
+	for _, tt := range tests {
+		if got, err := ParseMode(tt.value); err != nil || got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := ParseMode("letsencrypt"); err == nil {
+		t.Error("Expected error for an unknown mode")
+	}
+}
+
+func TestGenerateSelfSigned(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM, err := GenerateSelfSigned([]string{"booth.local", "192.168.1.20"}, now)
+	if err != nil {
+		t.Fatalf("GenerateSelfSigned() error: %v", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair() error: %v", err)
+	}
+	leaf := cert.Leaf
+	if !slices.Equal(leaf.DNSNames, []string{"booth.local"}) || len(leaf.IPAddresses) != 1 || !leaf.IPAddresses[0].Equal(net.ParseIP("192.168.1.20")) {
+		t.Errorf("Names = %v %v", leaf.DNSNames, leaf.IPAddresses)
+	}
+	if got := leaf.NotAfter.Sub(now); got > selfSignedValidity || got < selfSignedValidity-time.Minute {
+		t.Errorf("Validity = %v, want %v", got, selfSignedValidity)
+	}
+	if !slices.Contains(leaf.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		t.Error("Expected the certificate usable for servers")
+	}
+}
+
+func TestLoadOrCreateSelfSigned(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls", "cert.pem"), filepath.Join(dir, "tls", "key.pem")
+
+	first, err := LoadOrCreateSelfSigned(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSelfSigned() error: %v", err)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Key file = %v, %v; want it private", info, err)
+	}
+	if !slices.Contains(first.Leaf.DNSNames, "localhost") {
+		t.Errorf("DNSNames = %v, want localhost", first.Leaf.DNSNames)
+	}
+
+	// A later boot keeps the certificate clients have accepted
+	second, err := LoadOrCreateSelfSigned(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSelfSigned() error: %v", err)
+	}
+	if second.Leaf.SerialNumber.Cmp(first.Leaf.SerialNumber) != 0 {
+		t.Error("Expected the existing certificate kept")
+	}
+
+	// One about to expire is replaced
+	certPEM, keyPEM, err := GenerateSelfSigned([]string{"localhost"}, time.Now().Add(-selfSignedValidity+24*time.Hour))
+	if err != nil {
+		t.Fatalf("GenerateSelfSigned() error: %v", err)
+	}
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	renewed, err := LoadOrCreateSelfSigned(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSelfSigned() error: %v", err)
+	}
+	if time.Until(renewed.Leaf.NotAfter) < renewBefore {
+		t.Errorf("Expected an expiring certificate replaced, got one expiring %v", renewed.Leaf.NotAfter)
+	}
+}
+
+func TestServerConfig(t *testing.T) {
+	if config, err := ServerConfig(Config{Mode: ModeOff}); err != nil || config != nil {
+		t.Errorf("ServerConfig(off) = %v, %v; want no TLS", config, err)
+	}
+	if _, err := ServerConfig(Config{Mode: ModeFile, CertFile: "missing.pem", KeyFile: "missing.pem"}); err == nil {
+		t.Error("Expected error for missing certificate files")
+	}
+	if _, err := ServerConfig(Config{Mode: ModeACME}); err == nil {
+		t.Error("Expected error for ACME without domains")
+	}
+	config, err := ServerConfig(Config{Mode: ModeACME, ACMEDomains: []string{"lights.example.com"}, ACMECacheDir: t.TempDir()})
+	if err != nil || config.GetCertificate == nil {
+		t.Errorf("ServerConfig(acme) = %v, %v", config, err)
+	}
+}
+
+// TestServerConfig_HTTP2 tests that a self-signed server negotiates HTTP/2.
+func TestServerConfig_HTTP2(t *testing.T) {
+	dir := t.TempDir()
+	config, err := ServerConfig(Config{Mode: ModeSelfSigned, CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem")})
+	if err != nil {
+		t.Fatalf("ServerConfig() error: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}),
+		TLSConfig: config,
+	}
+	go func() { _ = server.ServeTLS(listener, "", "") }()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(config.Certificates[0].Leaf)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://127.0.0.1:" + strconv.Itoa(listener.Addr().(*net.TCPAddr).Port))
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.ProtoMajor != 2 || string(body) != "HTTP/2.0" {
+		t.Errorf("Protocol = %s, served %s; want HTTP/2", resp.Proto, body)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"regexp"
@@ -185,7 +184,7 @@ func (s *Service) GetSystemVersions() (*SystemVersionInfo, error) {
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Log full output server-side for debugging, return sanitized error to client
-		log.Printf("Failed to get versions: %v\nFull output: %s", err, string(output))
+		log.Error("failed to get versions", "error", err, "output", string(output))
 		return nil, fmt.Errorf("failed to get versions: %w", err)
 	}
 
@@ -200,7 +199,7 @@ func (s *Service) GetSystemVersions() (*SystemVersionInfo, error) {
 	for _, repoName := range repositoryNames {
 		v, exists := versions[repoName]
 		if !exists {
-			log.Printf("Warning: repository %s not found in version data", repoName)
+			log.Warn("repository not found in version data", "repository", repoName)
 		}
 		repos = append(repos, &RepositoryVersion{
 			Repository:      repoName,
@@ -233,7 +232,7 @@ func (s *Service) GetAvailableVersions(repository string) ([]string, error) {
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Log full output server-side for debugging, return sanitized error to client
-		log.Printf("Failed to get available versions for %s: %v\nFull output: %s", repository, err, string(output))
+		log.Error("failed to get available versions", "repository", repository, "error", err, "output", string(output))
 		return nil, fmt.Errorf("failed to get available versions: %w", err)
 	}
 
@@ -248,7 +247,7 @@ func (s *Service) GetAvailableVersions(repository string) ([]string, error) {
 			if semverPattern.MatchString(line) {
 				versions = append(versions, line)
 			} else {
-				log.Printf("Warning: skipping invalid version string from script: %q", line)
+				log.Warn("skipping invalid version string from script", "line", line)
 			}
 		}
 	}
@@ -371,10 +370,10 @@ func (s *Service) UpdateRepository(repository string, version *string) (*UpdateR
 		cmd.Stderr = &stderr
 
 		// Start the command without waiting for it to complete
-		log.Printf("Starting %s update: sudo systemd-run --unit=%s (target: %s)", repository, unitName, targetVersion)
+		log.Info("starting update with systemd-run", "repository", repository, "unit", unitName, "target", targetVersion)
 		err = cmd.Start()
 		if err != nil {
-			log.Printf("Failed to start update for %s: %v", repository, err)
+			log.Error("failed to start update", "repository", repository, "error", err)
 			return &UpdateResult{
 				Success:         false,
 				Repository:      repository,
@@ -389,13 +388,13 @@ func (s *Service) UpdateRepository(repository string, version *string) (*UpdateR
 			stdoutStr := stdout.String()
 			stderrStr := stderr.String()
 			if waitErr != nil || len(stdoutStr) > 0 || len(stderrStr) > 0 {
-				log.Printf("%s update systemd-run completed: err=%v, stdout=%s, stderr=%s", repository, waitErr, stdoutStr, stderrStr)
+				log.Info("update systemd-run completed", "repository", repository, "error", waitErr, "stdout", stdoutStr, "stderr", stderrStr)
 			}
 		}()
 		// Don't wait for the command to complete - let it run in the background
 		// For updates that stop the backend, return success immediately
 		// The actual update happens in the background via systemd-run
-		log.Printf("%s update scheduled: version=%s, unit=%s", repository, targetVersion, unitName)
+		log.Info("update scheduled", "repository", repository, "version", targetVersion, "unit", unitName)
 		return &UpdateResult{
 			Success:         true,
 			Repository:      repository,
@@ -414,7 +413,7 @@ func (s *Service) UpdateRepository(repository string, version *string) (*UpdateR
 	output, err = cmd.CombinedOutput()
 	if err != nil {
 		// Log full output server-side for debugging, return sanitized error to client
-		log.Printf("Update failed for repository %s: %v\nFull output: %s", repository, err, string(output))
+		log.Error("update failed", "repository", repository, "error", err, "output", string(output))
 		return &UpdateResult{
 			Success:         false,
 			Repository:      repository,
@@ -470,10 +469,10 @@ func (s *Service) UpdateAllRepositories() ([]*UpdateResult, error) {
 	var results []*UpdateResult
 
 	for _, repo := range repositoryNames {
-		log.Printf("UpdateAll: updating %s to latest", repo)
+		log.Info("updating to latest", "repository", repo)
 		result, err := s.UpdateRepository(repo, nil) // nil = latest version
 		if err != nil {
-			log.Printf("UpdateAll: failed to update %s: %v", repo, err)
+			log.Error("failed to update", "repository", repo, "error", err)
 			results = append(results, &UpdateResult{
 				Success:    false,
 				Repository: repo,
@@ -0,0 +1,171 @@
+// Package webui serves the web UI from the same binary as the API: the
+// build embedded at compile time, or one in a directory. Paths that are not
+// files fall back to index.html, so the single-page app handles its own
+// routes.
+package webui
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// all: keeps build directories such as _next, which go:embed would skip
+//
+//go:embed all:dist
+var embeddedData embed.FS
+
+// indexFile is the app's entry page, served for paths that are not files.
+const indexFile = "index.html"
+
+const (
+	// immutableCache lets browsers keep content-hashed build output for a
+	// year without checking it.
+	immutableCache = "public, max-age=31536000, immutable"
+	// revalidateCache makes browsers check pages and unhashed files, so a
+	// new build is picked up on the next load.
+	revalidateCache = "no-cache"
+)
+
+// immutablePrefixes hold build output named by a hash of its content.
+var immutablePrefixes = []string{"_next/static/", "assets/", "static/"}
+
+// ErrNotBuilt is returned by Open when no web UI build was embedded.
+var ErrNotBuilt = errors.New("no web UI build embedded")
+
+// Open returns the web UI build in dir, or the embedded build when dir is
+// empty.
+func Open(dir string) (fs.FS, error) {
+	if dir == "" {
+		files, err := fs.Sub(embeddedData, "dist")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fs.Stat(files, indexFile); err != nil {
+			return nil, ErrNotBuilt
+		}
+		return files, nil
+	}
+	files := os.DirFS(dir)
+	if _, err := fs.Stat(files, indexFile); err != nil {
+		return nil, fmt.Errorf("no %s in web UI directory %s: %w", indexFile, dir, err)
+	}
+	return files, nil
+}
+
+// Handler serves a web UI build.
+type Handler struct {
+	files fs.FS
+
+	// etags caches content hashes by etagKey
+	etags sync.Map
+}
+
+// etagKey identifies a version of a file.
+type etagKey struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// NewHandler creates a handler serving the files of a build.
+func NewHandler(files fs.FS) *Handler {
+	return &Handler{files: files}
+}
+
+// ServeHTTP serves the file at the request path, or index.html for a path
+// without an extension that is not a file.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = indexFile
+	}
+	// Static exports write a route as route.html or route/index.html
+	for _, candidate := range []string{name, name + ".html", path.Join(name, indexFile)} {
+		if h.serveFile(w, r, candidate) {
+			return
+		}
+	}
+	// A missing asset, such as one from an older build, is not an app route
+	if path.Ext(name) != "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !h.serveFile(w, r, indexFile) {
+		http.NotFound(w, r)
+	}
+}
+
+// serveFile serves a file of the build with its cache headers, reporting
+// false if it is not a file.
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	file, err := h.files.Open(name)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		return false
+	}
+
+	etag, err := h.etag(name, info, content)
+	if err != nil {
+		log.Warn("failed to read web UI file", "file", name, "error", err)
+		http.Error(w, "failed to read file", http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("Cache-Control", cacheControl(name))
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
+
+// etag returns the ETag of a file's content, hashing it the first time,
+// and leaves the content at its start.
+func (h *Handler) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	key := etagKey{name: name, size: info.Size(), modTime: info.ModTime()}
+	if etag, ok := h.etags.Load(key); ok {
+		return etag.(string), nil
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	h.etags.Store(key, etag)
+	return etag, nil
+}
+
+// cacheControl returns the Cache-Control header of a file of the build.
+func cacheControl(name string) string {
+	for _, prefix := range immutablePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return immutableCache
+		}
+	}
+	return revalidateCache
+}
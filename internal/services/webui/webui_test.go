@@ -0,0 +1,108 @@
+package webui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func testBuild() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":              {Data: []byte("<html>app</html>")},
+		"cue-lists.html":          {Data: []byte("<html>cue lists</html>")},
+		"scenes/index.html":       {Data: []byte("<html>scenes</html>")},
+		"favicon.ico":             {Data: []byte("icon")},
+		"_next/static/app-1a2.js": {Data: []byte("console.log(1)")},
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(testBuild())
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		cache  string
+	}{
+		{"/", http.StatusOK, "<html>app</html>", revalidateCache},
+		{"/cue-lists", http.StatusOK, "<html>cue lists</html>", revalidateCache},
+		{"/scenes", http.StatusOK, "<html>scenes</html>", revalidateCache},
+		{"/scenes/", http.StatusOK, "<html>scenes</html>", revalidateCache},
+		{"/favicon.ico", http.StatusOK, "icon", revalidateCache},
+		{"/_next/static/app-1a2.js", http.StatusOK, "console.log(1)", immutableCache},
+		// Client-side routes fall back to the app
+		{"/cue-lists/abc123/edit", http.StatusOK, "<html>app</html>", revalidateCache},
+		{"/../../etc/passwd", http.StatusOK, "<html>app</html>", revalidateCache},
+		// Missing assets do not
+		{"/_next/static/app-old.js", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("Status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("Body = %q, want %q", rec.Body.String(), tt.body)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cache)
+			}
+		})
+	}
+}
+
+func TestHandler_Revalidation(t *testing.T) {
+	handler := NewHandler(testBuild())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag")
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Status = %d, want 304 for an unchanged page", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Status = %d, want 405 for POST", rec.Code)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Open(dir); err == nil {
+		t.Error("Expected error for a directory without index.html")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir); err != nil {
+		t.Errorf("Open() error: %v", err)
+	}
+
+	// Without a build copied in, only the placeholder is embedded
+	if _, err := Open(""); err != nil && !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("Open(\"\") error: %v", err)
+	}
+}
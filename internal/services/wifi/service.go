@@ -4,7 +4,6 @@ package wifi
 import (
 	"context"
 	"fmt"
-	"log"
 	"os/exec"
 	"regexp"
 	"runtime"
@@ -67,7 +66,7 @@ func (e *realExecutor) ExecuteWithTimeout(timeout time.Duration, name string, ar
 	// Use CombinedOutput to capture both stdout and stderr
 	output, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
-		log.Printf("WiFi: command timed out after %v: %s %v", timeout, name, args)
+		log.Warn("command timed out", "timeout", timeout, "command", name, "args", args)
 		return nil, fmt.Errorf("command timed out: %s", name)
 	}
 	return output, err
@@ -187,11 +186,11 @@ func (s *Service) SetWiFiEnabled(ctx context.Context, enabled bool) (*Status, er
 
 	_, err := s.executor.Execute("nmcli", "radio", "wifi", arg)
 	if err != nil {
-		log.Printf("Failed to set WiFi enabled=%v: %v", enabled, err)
+		log.Error("failed to set WiFi radio", "enabled", enabled, "error", err)
 		return nil, fmt.Errorf("failed to set WiFi enabled: %w", err)
 	}
 
-	log.Printf("WiFi radio set to %s", arg)
+	log.Info("WiFi radio set", "state", arg)
 
 	// Give NetworkManager a moment to update status
 	time.Sleep(500 * time.Millisecond)
@@ -217,7 +216,7 @@ func (s *Service) ScanNetworks(ctx context.Context, rescan bool, deduplicate boo
 	if rescan {
 		_, err := s.executor.Execute("nmcli", "device", "wifi", "rescan")
 		if err != nil {
-			log.Printf("WiFi rescan failed (may be rate-limited or in AP mode): %v", err)
+			log.Debug("WiFi rescan failed; it may be rate-limited or in AP mode", "error", err)
 			// Continue anyway - we can still list cached results
 		}
 		// Give the scan a moment to complete
@@ -228,7 +227,7 @@ func (s *Service) ScanNetworks(ctx context.Context, rescan bool, deduplicate boo
 	// Format: SSID:SIGNAL:SECURITY:FREQ:IN-USE
 	output, err := s.executor.Execute("nmcli", "-t", "-f", "SSID,SIGNAL,SECURITY,FREQ,IN-USE", "device", "wifi", "list")
 	if err != nil {
-		log.Printf("Failed to list WiFi networks: %v", err)
+		log.Error("failed to list WiFi networks", "error", err)
 		return nil, fmt.Errorf("failed to list networks: %w", err)
 	}
 
@@ -311,7 +310,7 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 			}, nil
 		}
 		// Give NetworkManager time to fully release the interface after AP mode
-		log.Printf("Waiting for interface to be released after AP mode...")
+		log.Info("waiting for the interface to be released after AP mode")
 		time.Sleep(3 * time.Second)
 		s.mu.Lock()
 	}
@@ -322,13 +321,13 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 
 	// Delete any existing connection for this SSID to avoid corrupted profiles
 	// This is safe - if no connection exists, nmcli just returns an error we ignore
-	log.Printf("Deleting any existing connection for SSID: %s", ssid)
+	log.Info("deleting any existing connection", "ssid", ssid)
 	_, _ = s.executor.Execute("nmcli", "connection", "delete", ssid)
 
 	// For password-protected networks, create connection explicitly with proper security settings
 	// This avoids the "key-mgmt property is missing" error
 	if password != nil && *password != "" {
-		log.Printf("Creating new connection profile for SSID: %s", ssid)
+		log.Info("creating connection profile", "ssid", ssid)
 		// Create connection with explicit WPA-PSK settings
 		addArgs := []string{
 			"connection", "add",
@@ -340,7 +339,7 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 		}
 		output, err := s.executor.Execute("nmcli", addArgs...)
 		if err != nil {
-			log.Printf("Failed to create connection: %v, output: %s", err, string(output))
+			log.Error("failed to create connection", "error", err, "output", string(output))
 			// Continue anyway - try the direct connect as fallback
 		}
 	}
@@ -365,22 +364,22 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 	}
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		log.Printf("WiFi connect attempt %d/%d for SSID: %s", attempt, maxRetries, ssid)
+		log.Info("WiFi connect attempt", "attempt", attempt, "of", maxRetries, "ssid", ssid)
 		output, err = s.executor.ExecuteWithTimeout(connectTimeout, "nmcli", args...)
 		if err == nil {
 			break
 		}
-		log.Printf("Connect attempt %d failed: %v, output: %s", attempt, err, string(output))
+		log.Warn("connect attempt failed", "attempt", attempt, "error", err, "output", string(output))
 
 		// Check if we're actually connected despite the error
 		if s.isConnected() {
-			log.Printf("Actually connected despite error - treating as success")
+			log.Info("connected despite the error; treating as success")
 			err = nil
 			break
 		}
 
 		if attempt < maxRetries {
-			log.Printf("Waiting before retry...")
+			log.Info("waiting before retrying")
 			time.Sleep(2 * time.Second)
 		}
 	}
@@ -388,9 +387,9 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 	if err != nil {
 		// Final check - are we actually connected?
 		if s.isConnected() {
-			log.Printf("Connected despite nmcli error")
+			log.Info("connected despite the nmcli error")
 		} else {
-			log.Printf("Failed to connect to WiFi network %s after %d attempts", ssid, maxRetries)
+			log.Error("failed to connect to WiFi network", "ssid", ssid, "attempts", maxRetries)
 			s.mu.Lock()
 			s.mode = ModeClient
 			s.notifyModeChange()
@@ -418,7 +417,7 @@ func (s *Service) ConnectToNetwork(ctx context.Context, ssid string, password *s
 	s.notifyStatusChangeLocked()
 	s.mu.Unlock()
 
-	log.Printf("Successfully connected to WiFi network: %s", ssid)
+	log.Info("connected to WiFi network", "ssid", ssid)
 
 	return &ConnectionResult{
 		Success:   true,
@@ -440,7 +439,7 @@ func (s *Service) Disconnect(ctx context.Context) (*ConnectionResult, error) {
 	// Disconnect the WiFi interface
 	_, err := s.executor.Execute("nmcli", "device", "disconnect", s.wifiInterface)
 	if err != nil {
-		log.Printf("Failed to disconnect WiFi: %v", err)
+		log.Error("failed to disconnect WiFi", "error", err)
 		return &ConnectionResult{
 			Success:   false,
 			Message:   stringPtr(fmt.Sprintf("Disconnect failed: %v", err)),
@@ -453,7 +452,7 @@ func (s *Service) Disconnect(ctx context.Context) (*ConnectionResult, error) {
 	s.notifyStatusChangeLocked()
 	s.mu.Unlock()
 
-	log.Printf("Disconnected from WiFi")
+	log.Info("disconnected from WiFi")
 
 	return &ConnectionResult{
 		Success:   true,
@@ -471,11 +470,11 @@ func (s *Service) ForgetNetwork(ctx context.Context, ssid string) (bool, error)
 	// Delete the connection by name
 	_, err := s.executor.Execute("nmcli", "connection", "delete", ssid)
 	if err != nil {
-		log.Printf("Failed to forget WiFi network %s: %v", ssid, err)
+		log.Error("failed to forget WiFi network", "ssid", ssid, "error", err)
 		return false, nil
 	}
 
-	log.Printf("Forgot WiFi network: %s", ssid)
+	log.Info("forgot WiFi network", "ssid", ssid)
 	return true, nil
 }
 
@@ -533,7 +532,7 @@ func (s *Service) StartAPMode(ctx context.Context) (*ModeResult, error) {
 
 	// Create and activate AP connection
 	if err := s.createAPConnection(ssid); err != nil {
-		log.Printf("Failed to create AP connection: %v", err)
+		log.Error("failed to create AP connection", "error", err)
 		s.mode = ModeClient
 		s.notifyModeChange()
 		return &ModeResult{
@@ -544,7 +543,7 @@ func (s *Service) StartAPMode(ctx context.Context) (*ModeResult, error) {
 	}
 
 	if err := s.activateAPConnection(); err != nil {
-		log.Printf("Failed to activate AP connection: %v", err)
+		log.Error("failed to activate AP connection", "error", err)
 		s.mode = ModeClient
 		s.notifyModeChange()
 		return &ModeResult{
@@ -573,7 +572,7 @@ func (s *Service) StartAPMode(ctx context.Context) (*ModeResult, error) {
 	s.notifyModeChange()
 	s.notifyStatusChangeLocked()
 
-	log.Printf("AP mode started with SSID: %s", ssid)
+	log.Info("AP mode started", "ssid", ssid)
 
 	return &ModeResult{
 		Success: true,
@@ -604,7 +603,7 @@ func (s *Service) StopAPMode(ctx context.Context, connectToSSID *string) (*ModeR
 
 	// Deactivate AP connection
 	if err := s.deactivateAPConnection(); err != nil {
-		log.Printf("Failed to deactivate AP connection: %v", err)
+		log.Error("failed to deactivate AP connection", "error", err)
 		// Continue anyway - try to connect to target network
 	}
 
@@ -618,14 +617,14 @@ func (s *Service) StopAPMode(ctx context.Context, connectToSSID *string) (*ModeR
 		s.notifyModeChange()
 
 		// This would be implemented in client.go
-		log.Printf("Would connect to SSID: %s", *connectToSSID)
+		log.Info("would connect to network", "ssid", *connectToSSID)
 	}
 
 	s.mode = ModeClient
 	s.notifyModeChange()
 	s.notifyStatusChangeLocked()
 
-	log.Printf("AP mode stopped")
+	log.Info("AP mode stopped")
 
 	return &ModeResult{
 		Success: true,
@@ -653,7 +652,7 @@ func (s *Service) ResetAPTimeout(ctx context.Context) (bool, error) {
 	now := time.Now()
 	s.apStartTime = &now
 
-	log.Printf("AP timeout reset to %d minutes", s.apTimeoutMinutes)
+	log.Info("AP timeout reset", "minutes", s.apTimeoutMinutes)
 
 	return true, nil
 }
@@ -673,7 +672,7 @@ func (s *Service) detectCurrentMode() {
 	// nmcli -t -f NAME,TYPE,DEVICE connection show --active
 	output, err := s.executor.Execute("nmcli", "-t", "-f", "NAME,TYPE,DEVICE", "connection", "show", "--active")
 	if err != nil {
-		log.Printf("Failed to detect WiFi mode: %v", err)
+		log.Warn("failed to detect WiFi mode", "error", err)
 		s.mode = ModeClient
 		return
 	}
@@ -701,7 +700,7 @@ func (s *Service) detectCurrentMode() {
 					}
 					// Start the AP timeout timer
 					s.startAPTimer()
-					log.Printf("Detected existing AP mode with SSID: %s, timeout reset to %d minutes", ssid, s.apTimeoutMinutes)
+					log.Info("detected existing AP mode; timeout reset", "ssid", ssid, "minutes", s.apTimeoutMinutes)
 				}
 				return
 			}
@@ -713,19 +712,19 @@ func (s *Service) detectCurrentMode() {
 
 func (s *Service) isWiFiAvailable() bool {
 	if runtime.GOOS != "linux" {
-		log.Printf("WiFi: Not on Linux (GOOS=%s), WiFi unavailable", runtime.GOOS)
+		log.Info("WiFi unavailable: not on Linux", "goos", runtime.GOOS)
 		return false
 	}
 
 	output, err := s.executor.Execute("nmcli", "-t", "-f", "DEVICE,TYPE", "device", "status")
 	if err != nil {
-		log.Printf("WiFi: nmcli command failed: %v", err)
+		log.Info("WiFi unavailable: nmcli failed", "error", err)
 		return false
 	}
 
 	hasWifi := strings.Contains(string(output), "wifi")
 	if !hasWifi {
-		log.Printf("WiFi: No wifi device found in nmcli output: %q", string(output))
+		log.Info("WiFi unavailable: no wifi device in nmcli output", "output", string(output))
 	}
 	return hasWifi
 }
@@ -878,11 +877,11 @@ func (s *Service) startAPTimer() {
 
 	timeout := time.Duration(s.apTimeoutMinutes) * time.Minute
 	s.apTimer = time.AfterFunc(timeout, func() {
-		log.Printf("AP mode timeout reached after %d minutes", s.apTimeoutMinutes)
+		log.Info("AP mode timeout reached", "minutes", s.apTimeoutMinutes)
 		ctx := context.Background()
 		_, err := s.StopAPMode(ctx, nil)
 		if err != nil {
-			log.Printf("Failed to stop AP mode after timeout: %v", err)
+			log.Error("failed to stop AP mode after timeout", "error", err)
 		}
 	})
 }
@@ -951,14 +950,14 @@ func (s *Service) refreshAPClientsLocked() {
 		// No leases file found - try to get clients from arp table as fallback
 		output, err = s.executor.Execute("arp", "-n")
 		if err != nil {
-			log.Printf("Failed to read DHCP leases and arp table")
+			log.Warn("failed to read DHCP leases and the ARP table")
 			return
 		}
 		s.parseArpTable(output)
 		return
 	}
 
-	log.Printf("Reading DHCP leases from: %s", foundFile)
+	log.Debug("reading DHCP leases", "path", foundFile)
 
 	var clients []APClient
 	lines := strings.Split(string(output), "\n")
@@ -1011,7 +1010,7 @@ func (s *Service) parseArpTable(output []byte) {
 		}
 	}
 
-	log.Printf("Found %d clients from ARP table", len(clients))
+	log.Debug("found clients in the ARP table", "clients", len(clients))
 	s.connectedClients = clients
 	if s.apConfig != nil {
 		s.apConfig.ClientCount = len(clients)
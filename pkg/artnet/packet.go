@@ -2,12 +2,17 @@
 package artnet
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 )
 
 const (
 	// OpCodeDMX is the Art-Net operation code for DMX data.
 	OpCodeDMX uint16 = 0x5000
+	// OpCodeSync is the Art-Net operation code for ArtSync, which tells nodes
+	// to output the ArtDmx data they have received.
+	OpCodeSync uint16 = 0x5200
 	// ProtocolVersion is the Art-Net protocol version.
 	ProtocolVersion uint16 = 14
 	// DMXDataLength is the number of DMX channels per universe.
@@ -48,3 +53,54 @@ func BuildDMXPacket(universe int, channels []byte, sequence byte) []byte {
 
 	return packet
 }
+
+// syncPacketSize is the size of an ArtSync packet (header, version, two
+// auxiliary bytes).
+const syncPacketSize = 14
+
+// BuildSyncPacket creates an ArtSync packet. Sent after a burst of ArtDmx
+// packets, it makes nodes latch every universe of the burst at once.
+func BuildSyncPacket() []byte {
+	packet := make([]byte, syncPacketSize)
+	copy(packet[0:8], ArtNetID)
+	binary.LittleEndian.PutUint16(packet[8:10], OpCodeSync)
+	binary.BigEndian.PutUint16(packet[10:12], ProtocolVersion)
+	packet[12] = 0 // Aux1
+	packet[13] = 0 // Aux2
+	return packet
+}
+
+// OpCode returns the operation code of an Art-Net packet. It reports false
+// if the packet is not Art-Net.
+func OpCode(packet []byte) (uint16, bool) {
+	if len(packet) < 10 || !bytes.Equal(packet[0:8], ArtNetID) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint16(packet[8:10]), true
+}
+
+// ParseDMXPacket decodes an ArtDmx packet. The universe is 1-based as used
+// throughout the application (Art-Net port address + 1).
+func ParseDMXPacket(packet []byte) (universe int, channels []byte, err error) {
+	opCode, ok := OpCode(packet)
+	if !ok {
+		return 0, nil, errors.New("artnet: invalid packet ID")
+	}
+	if opCode != OpCodeDMX {
+		return 0, nil, errors.New("artnet: not an ArtDmx packet")
+	}
+	if len(packet) < 18 {
+		return 0, nil, errors.New("artnet: packet too short for ArtDmx")
+	}
+
+	length := int(binary.BigEndian.Uint16(packet[16:18]))
+	if length > int(DMXDataLength) {
+		length = int(DMXDataLength)
+	}
+	if len(packet) < 18+length {
+		length = len(packet) - 18
+	}
+
+	universe = int(binary.LittleEndian.Uint16(packet[14:16])&0x7FFF) + 1
+	return universe, packet[18 : 18+length], nil
+}
@@ -143,3 +143,67 @@ func TestBuildDMXPacket_EmptyChannels(t *testing.T) {
 		}
 	}
 }
+
+func TestOpCode(t *testing.T) {
+	if op, ok := OpCode(BuildDMXPacket(1, make([]byte, 512), 0)); !ok || op != OpCodeDMX {
+		t.Errorf("OpCode(ArtDmx) = %#x, %v; want %#x, true", op, ok, OpCodeDMX)
+	}
+	if op, ok := OpCode(BuildPollPacket()); !ok || op != OpCodePoll {
+		t.Errorf("OpCode(ArtPoll) = %#x, %v; want %#x, true", op, ok, OpCodePoll)
+	}
+	if op, ok := OpCode(BuildSyncPacket()); !ok || op != OpCodeSync {
+		t.Errorf("OpCode(ArtSync) = %#x, %v; want %#x, true", op, ok, OpCodeSync)
+	}
+	if _, ok := OpCode([]byte("not art-net")); ok {
+		t.Error("Expected non-Art-Net packet to be rejected")
+	}
+	if _, ok := OpCode(ArtNetID); ok {
+		t.Error("Expected packet without an opcode to be rejected")
+	}
+}
+
+func TestParseDMXPacket(t *testing.T) {
+	channels := make([]byte, 512)
+	channels[0] = 255
+	channels[511] = 7
+
+	universe, data, err := ParseDMXPacket(BuildDMXPacket(3, channels, 9))
+	if err != nil {
+		t.Fatalf("ParseDMXPacket() error: %v", err)
+	}
+	if universe != 3 {
+		t.Errorf("universe = %d, want 3", universe)
+	}
+	if len(data) != 512 || data[0] != 255 || data[511] != 7 {
+		t.Errorf("channel data not decoded (len %d)", len(data))
+	}
+
+	// Senders may send fewer than 512 channels
+	short := BuildDMXPacket(1, channels, 0)[:18+24]
+	binary.BigEndian.PutUint16(short[16:18], 24)
+	if _, data, err := ParseDMXPacket(short); err != nil || len(data) != 24 {
+		t.Errorf("short packet: len %d, err %v; want 24 channels", len(data), err)
+	}
+}
+
+func TestParseDMXPacket_Invalid(t *testing.T) {
+	if _, _, err := ParseDMXPacket([]byte("not art-net")); err == nil {
+		t.Error("Expected error for non-Art-Net packet")
+	}
+	if _, _, err := ParseDMXPacket(BuildPollPacket()); err == nil {
+		t.Error("Expected error for ArtPoll packet")
+	}
+}
+
+func TestBuildSyncPacket(t *testing.T) {
+	packet := BuildSyncPacket()
+	if len(packet) != 14 {
+		t.Fatalf("BuildSyncPacket() length = %d, want 14", len(packet))
+	}
+	if version := binary.BigEndian.Uint16(packet[10:12]); version != ProtocolVersion {
+		t.Errorf("BuildSyncPacket() version = %d, want %d", version, ProtocolVersion)
+	}
+	if packet[12] != 0 || packet[13] != 0 {
+		t.Errorf("BuildSyncPacket() aux bytes = %d, %d, want 0, 0", packet[12], packet[13])
+	}
+}
@@ -0,0 +1,88 @@
+package artnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+const (
+	// OpCodePoll is the Art-Net operation code for ArtPoll discovery requests.
+	OpCodePoll uint16 = 0x2000
+	// OpCodePollReply is the Art-Net operation code for ArtPollReply responses.
+	OpCodePollReply uint16 = 0x2100
+
+	// pollPacketSize is the size of an ArtPoll packet (header, version, flags, priority).
+	pollPacketSize = 14
+	// pollReplyMinSize covers every ArtPollReply field read by ParsePollReply.
+	pollReplyMinSize = 207
+
+	// portTypeOutput is the PortTypes bit set when a port can output DMX from Art-Net.
+	portTypeOutput = 0x80
+)
+
+// Node describes an Art-Net node as reported in an ArtPollReply.
+type Node struct {
+	IP        net.IP
+	ShortName string
+	LongName  string
+	// OutputUniverses lists the universes the node outputs, 1-based as used
+	// throughout the application (Art-Net port address + 1).
+	OutputUniverses []int
+}
+
+// BuildPollPacket creates an ArtPoll packet that asks nodes to reply with ArtPollReply.
+func BuildPollPacket() []byte {
+	packet := make([]byte, pollPacketSize)
+	copy(packet[0:8], ArtNetID)
+	binary.LittleEndian.PutUint16(packet[8:10], OpCodePoll)
+	binary.BigEndian.PutUint16(packet[10:12], ProtocolVersion)
+	packet[12] = 0 // Flags: no unsolicited replies
+	packet[13] = 0 // Diagnostics priority
+	return packet
+}
+
+// ParsePollReply decodes an ArtPollReply packet into a Node.
+func ParsePollReply(packet []byte) (*Node, error) {
+	if len(packet) < pollReplyMinSize {
+		return nil, errors.New("artnet: packet too short for ArtPollReply")
+	}
+	if !bytes.Equal(packet[0:8], ArtNetID) {
+		return nil, errors.New("artnet: invalid packet ID")
+	}
+	if binary.LittleEndian.Uint16(packet[8:10]) != OpCodePollReply {
+		return nil, errors.New("artnet: not an ArtPollReply packet")
+	}
+
+	node := &Node{
+		IP:        net.IPv4(packet[10], packet[11], packet[12], packet[13]),
+		ShortName: cString(packet[26:44]),
+		LongName:  cString(packet[44:108]),
+	}
+
+	netSwitch := int(packet[18] & 0x7F)
+	subSwitch := int(packet[19] & 0x0F)
+	numPorts := int(binary.BigEndian.Uint16(packet[172:174]))
+	if numPorts > 4 {
+		numPorts = 4
+	}
+
+	for i := 0; i < numPorts; i++ {
+		if packet[174+i]&portTypeOutput == 0 {
+			continue
+		}
+		portAddress := netSwitch<<8 | subSwitch<<4 | int(packet[190+i]&0x0F)
+		node.OutputUniverses = append(node.OutputUniverses, portAddress+1)
+	}
+
+	return node, nil
+}
+
+// cString returns the string up to the first NUL byte.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
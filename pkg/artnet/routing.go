@@ -0,0 +1,65 @@
+package artnet
+
+import (
+	"sort"
+)
+
+// UniverseRoute lists the nodes that output a patched universe.
+type UniverseRoute struct {
+	Universe int
+	Nodes    []*Node
+}
+
+// RoutingProposal maps patched universes to the discovered nodes that output them.
+type RoutingProposal struct {
+	// Routes has one entry per patched universe, in ascending order.
+	Routes []UniverseRoute
+	// Unrouted lists patched universes that no discovered node outputs.
+	Unrouted []int
+	// UnusedNodes lists nodes that output none of the patched universes.
+	UnusedNodes []*Node
+}
+
+// ProposeRouting matches patched universes (1-based) against the output
+// universes of discovered nodes, flagging universes with no destination.
+func ProposeRouting(patchedUniverses []int, nodes []*Node) *RoutingProposal {
+	universeSet := make(map[int]bool)
+	for _, u := range patchedUniverses {
+		universeSet[u] = true
+	}
+	universes := make([]int, 0, len(universeSet))
+	for u := range universeSet {
+		universes = append(universes, u)
+	}
+	sort.Ints(universes)
+
+	nodesByUniverse := make(map[int][]*Node)
+	proposal := &RoutingProposal{}
+	for _, node := range nodes {
+		used := false
+		seen := make(map[int]bool)
+		for _, u := range node.OutputUniverses {
+			if seen[u] {
+				continue
+			}
+			seen[u] = true
+			if universeSet[u] {
+				nodesByUniverse[u] = append(nodesByUniverse[u], node)
+				used = true
+			}
+		}
+		if !used {
+			proposal.UnusedNodes = append(proposal.UnusedNodes, node)
+		}
+	}
+
+	for _, u := range universes {
+		route := UniverseRoute{Universe: u, Nodes: nodesByUniverse[u]}
+		proposal.Routes = append(proposal.Routes, route)
+		if len(route.Nodes) == 0 {
+			proposal.Unrouted = append(proposal.Unrouted, u)
+		}
+	}
+
+	return proposal
+}
@@ -0,0 +1,92 @@
+package artnet
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// buildPollReply creates a minimal ArtPollReply for tests.
+func buildPollReply(ip net.IP, shortName string, netSwitch, subSwitch byte, swOut []byte) []byte {
+	packet := make([]byte, 239)
+	copy(packet[0:8], ArtNetID)
+	binary.LittleEndian.PutUint16(packet[8:10], OpCodePollReply)
+	copy(packet[10:14], ip.To4())
+	packet[18] = netSwitch
+	packet[19] = subSwitch
+	copy(packet[26:44], shortName)
+	binary.BigEndian.PutUint16(packet[172:174], uint16(len(swOut)))
+	for i, sw := range swOut {
+		packet[174+i] = portTypeOutput
+		packet[190+i] = sw
+	}
+	return packet
+}
+
+func TestBuildPollPacket(t *testing.T) {
+	packet := BuildPollPacket()
+	if string(packet[0:8]) != "Art-Net\x00" {
+		t.Errorf("ID = %q, want Art-Net\\x00", packet[0:8])
+	}
+	if op := binary.LittleEndian.Uint16(packet[8:10]); op != OpCodePoll {
+		t.Errorf("OpCode = 0x%04x, want 0x%04x", op, OpCodePoll)
+	}
+	if ver := binary.BigEndian.Uint16(packet[10:12]); ver != ProtocolVersion {
+		t.Errorf("ProtocolVersion = %d, want %d", ver, ProtocolVersion)
+	}
+}
+
+func TestParsePollReply(t *testing.T) {
+	packet := buildPollReply(net.IPv4(10, 0, 0, 5), "Node A", 0, 1, []byte{0, 1})
+
+	node, err := ParsePollReply(packet)
+	if err != nil {
+		t.Fatalf("ParsePollReply() error: %v", err)
+	}
+	if !node.IP.Equal(net.IPv4(10, 0, 0, 5)) {
+		t.Errorf("IP = %v, want 10.0.0.5", node.IP)
+	}
+	if node.ShortName != "Node A" {
+		t.Errorf("ShortName = %q, want %q", node.ShortName, "Node A")
+	}
+	// Sub-switch 1 places ports at port addresses 16 and 17 (universes 17 and 18)
+	if len(node.OutputUniverses) != 2 || node.OutputUniverses[0] != 17 || node.OutputUniverses[1] != 18 {
+		t.Errorf("OutputUniverses = %v, want [17 18]", node.OutputUniverses)
+	}
+}
+
+func TestParsePollReply_Invalid(t *testing.T) {
+	if _, err := ParsePollReply([]byte("Art-Net\x00")); err == nil {
+		t.Error("Expected error for short packet")
+	}
+
+	packet := buildPollReply(net.IPv4(10, 0, 0, 5), "Node", 0, 0, []byte{0})
+	binary.LittleEndian.PutUint16(packet[8:10], OpCodeDMX)
+	if _, err := ParsePollReply(packet); err == nil {
+		t.Error("Expected error for non-ArtPollReply opcode")
+	}
+}
+
+func TestProposeRouting(t *testing.T) {
+	nodeA := &Node{ShortName: "A", OutputUniverses: []int{1, 2}}
+	nodeB := &Node{ShortName: "B", OutputUniverses: []int{2}}
+	nodeC := &Node{ShortName: "C", OutputUniverses: []int{9}}
+
+	proposal := ProposeRouting([]int{3, 1, 2, 1}, []*Node{nodeA, nodeB, nodeC})
+
+	if len(proposal.Routes) != 3 {
+		t.Fatalf("Routes = %d, want 3", len(proposal.Routes))
+	}
+	if proposal.Routes[0].Universe != 1 || len(proposal.Routes[0].Nodes) != 1 {
+		t.Errorf("Universe 1 route = %+v, want one node", proposal.Routes[0])
+	}
+	if len(proposal.Routes[1].Nodes) != 2 {
+		t.Errorf("Universe 2 nodes = %d, want 2", len(proposal.Routes[1].Nodes))
+	}
+	if len(proposal.Unrouted) != 1 || proposal.Unrouted[0] != 3 {
+		t.Errorf("Unrouted = %v, want [3]", proposal.Unrouted)
+	}
+	if len(proposal.UnusedNodes) != 1 || proposal.UnusedNodes[0] != nodeC {
+		t.Errorf("UnusedNodes = %v, want [C]", proposal.UnusedNodes)
+	}
+}
@@ -0,0 +1,361 @@
+// Package mdns provides the DNS message encoding used by multicast DNS
+// (RFC 6762) and DNS-based service discovery (RFC 6763): questions, and the
+// A, PTR, SRV, and TXT records that advertise a service.
+package mdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Port is the mDNS UDP port.
+const Port = 5353
+
+// GroupAddr is the IPv4 mDNS multicast group.
+var GroupAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: Port}
+
+// Record types.
+const (
+	TypeA   uint16 = 1
+	TypePTR uint16 = 12
+	TypeTXT uint16 = 16
+	TypeSRV uint16 = 33
+	TypeANY uint16 = 255
+)
+
+const (
+	classINET = 1
+	// classTopBit is the cache-flush bit of a record's class, and the
+	// unicast-response bit of a question's
+	classTopBit = 0x8000
+
+	flagResponse      = 0x8000
+	flagAuthoritative = 0x0400
+
+	headerSize = 12
+	// maxLabel and maxName are the DNS length limits
+	maxLabel = 63
+	maxName  = 255
+	// maxPointers bounds the compression pointers followed in one name
+	maxPointers = 16
+)
+
+// ErrTruncated is returned for a message that ends early.
+var ErrTruncated = errors.New("mdns: message truncated")
+
+// Question asks for the records of a name and type.
+type Question struct {
+	Name string
+	Type uint16
+	// UnicastResponse asks for the answer to be sent to the asker only
+	UnicastResponse bool
+}
+
+// Record is a resource record. Which data fields apply depends on Type.
+type Record struct {
+	Name string
+	Type uint16
+	TTL  uint32
+	// CacheFlush marks a record as the whole set for its name and type
+	CacheFlush bool
+
+	// IP is an A record's address
+	IP net.IP
+	// Target is a PTR record's name, or an SRV record's host
+	Target string
+	// Priority, Weight, and Port are an SRV record's
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	// Text is a TXT record's strings
+	Text []string
+}
+
+// Message is a DNS message. Authority records are read but not kept.
+type Message struct {
+	ID         uint16
+	Response   bool
+	Questions  []Question
+	Answers    []Record
+	Additional []Record
+}
+
+// Pack encodes a message. Responses are marked authoritative, as mDNS
+// requires. Names are not compressed.
+func (m *Message) Pack() ([]byte, error) {
+	var flags uint16
+	if m.Response {
+		flags = flagResponse | flagAuthoritative
+	}
+	b := make([]byte, headerSize, 512)
+	binary.BigEndian.PutUint16(b[0:], m.ID)
+	binary.BigEndian.PutUint16(b[2:], flags)
+	binary.BigEndian.PutUint16(b[4:], uint16(len(m.Questions)))
+	binary.BigEndian.PutUint16(b[6:], uint16(len(m.Answers)))
+	binary.BigEndian.PutUint16(b[10:], uint16(len(m.Additional)))
+
+	var err error
+	for _, q := range m.Questions {
+		if b, err = appendName(b, q.Name); err != nil {
+			return nil, err
+		}
+		class := uint16(classINET)
+		if q.UnicastResponse {
+			class |= classTopBit
+		}
+		b = binary.BigEndian.AppendUint16(b, q.Type)
+		b = binary.BigEndian.AppendUint16(b, class)
+	}
+	for _, records := range [][]Record{m.Answers, m.Additional} {
+		for _, r := range records {
+			if b, err = appendRecord(b, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+func appendRecord(b []byte, r Record) ([]byte, error) {
+	b, err := appendName(b, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	class := uint16(classINET)
+	if r.CacheFlush {
+		class |= classTopBit
+	}
+	b = binary.BigEndian.AppendUint16(b, r.Type)
+	b = binary.BigEndian.AppendUint16(b, class)
+	b = binary.BigEndian.AppendUint32(b, r.TTL)
+
+	// The data length is filled in once the data is written
+	lengthAt := len(b)
+	b = append(b, 0, 0)
+	switch r.Type {
+	case TypeA:
+		ip := r.IP.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("mdns: A record %s needs an IPv4 address", r.Name)
+		}
+		b = append(b, ip...)
+	case TypePTR:
+		if b, err = appendName(b, r.Target); err != nil {
+			return nil, err
+		}
+	case TypeSRV:
+		b = binary.BigEndian.AppendUint16(b, r.Priority)
+		b = binary.BigEndian.AppendUint16(b, r.Weight)
+		b = binary.BigEndian.AppendUint16(b, r.Port)
+		if b, err = appendName(b, r.Target); err != nil {
+			return nil, err
+		}
+	case TypeTXT:
+		if len(r.Text) == 0 {
+			// A TXT record holds at least one, empty, string
+			b = append(b, 0)
+		}
+		for _, s := range r.Text {
+			if len(s) > 255 {
+				return nil, fmt.Errorf("mdns: TXT string longer than 255 bytes: %q", s)
+			}
+			b = append(b, byte(len(s)))
+			b = append(b, s...)
+		}
+	default:
+		return nil, fmt.Errorf("mdns: cannot encode record type %d", r.Type)
+	}
+	binary.BigEndian.PutUint16(b[lengthAt:], uint16(len(b)-lengthAt-2))
+	return b, nil
+}
+
+// appendName appends a dotted name as labels. The first label of a service
+// instance name may hold any text but a dot.
+func appendName(b []byte, name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > maxName {
+		return nil, fmt.Errorf("mdns: name too long: %q", name)
+	}
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > maxLabel {
+				return nil, fmt.Errorf("mdns: invalid label in name %q", name)
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0), nil
+}
+
+// Parse decodes a message. Records of types other than A, PTR, SRV, and TXT
+// are kept without their data.
+func Parse(b []byte) (*Message, error) {
+	if len(b) < headerSize {
+		return nil, ErrTruncated
+	}
+	m := &Message{
+		ID:       binary.BigEndian.Uint16(b[0:]),
+		Response: binary.BigEndian.Uint16(b[2:])&flagResponse != 0,
+	}
+	questions := int(binary.BigEndian.Uint16(b[4:]))
+	answers := int(binary.BigEndian.Uint16(b[6:]))
+	authorities := int(binary.BigEndian.Uint16(b[8:]))
+	additional := int(binary.BigEndian.Uint16(b[10:]))
+
+	offset := headerSize
+	for i := 0; i < questions; i++ {
+		name, next, err := readName(b, offset)
+		if err != nil {
+			return nil, err
+		}
+		if next+4 > len(b) {
+			return nil, ErrTruncated
+		}
+		class := binary.BigEndian.Uint16(b[next+2:])
+		m.Questions = append(m.Questions, Question{
+			Name:            name,
+			Type:            binary.BigEndian.Uint16(b[next:]),
+			UnicastResponse: class&classTopBit != 0,
+		})
+		offset = next + 4
+	}
+
+	var err error
+	if m.Answers, offset, err = readRecords(b, offset, answers); err != nil {
+		return nil, err
+	}
+	if _, offset, err = readRecords(b, offset, authorities); err != nil {
+		return nil, err
+	}
+	if m.Additional, _, err = readRecords(b, offset, additional); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func readRecords(b []byte, offset, count int) ([]Record, int, error) {
+	var records []Record
+	for i := 0; i < count; i++ {
+		name, next, err := readName(b, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if next+10 > len(b) {
+			return nil, 0, ErrTruncated
+		}
+		class := binary.BigEndian.Uint16(b[next+2:])
+		r := Record{
+			Name:       name,
+			Type:       binary.BigEndian.Uint16(b[next:]),
+			TTL:        binary.BigEndian.Uint32(b[next+4:]),
+			CacheFlush: class&classTopBit != 0,
+		}
+		start := next + 10
+		end := start + int(binary.BigEndian.Uint16(b[next+8:]))
+		if end > len(b) {
+			return nil, 0, ErrTruncated
+		}
+		if err := readData(b, start, end, &r); err != nil {
+			return nil, 0, err
+		}
+		records = append(records, r)
+		offset = end
+	}
+	return records, offset, nil
+}
+
+// readData decodes the data of a record between start and end. Names in it
+// may point anywhere in the message.
+func readData(b []byte, start, end int, r *Record) error {
+	data := b[start:end]
+	switch r.Type {
+	case TypeA:
+		if len(data) != net.IPv4len {
+			return fmt.Errorf("mdns: A record %s has %d bytes of data", r.Name, len(data))
+		}
+		r.IP = net.IP(append([]byte(nil), data...))
+	case TypePTR:
+		target, _, err := readName(b[:end], start)
+		if err != nil {
+			return err
+		}
+		r.Target = target
+	case TypeSRV:
+		if len(data) < 7 {
+			return ErrTruncated
+		}
+		r.Priority = binary.BigEndian.Uint16(data[0:])
+		r.Weight = binary.BigEndian.Uint16(data[2:])
+		r.Port = binary.BigEndian.Uint16(data[4:])
+		target, _, err := readName(b[:end], start+6)
+		if err != nil {
+			return err
+		}
+		r.Target = target
+	case TypeTXT:
+		for i := 0; i < len(data); {
+			n := int(data[i])
+			if i+1+n > len(data) {
+				return ErrTruncated
+			}
+			if n > 0 {
+				r.Text = append(r.Text, string(data[i+1:i+1+n]))
+			}
+			i += 1 + n
+		}
+	}
+	return nil
+}
+
+// readName reads a possibly compressed name at offset, returning it and the
+// offset after it.
+func readName(b []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	length := 0
+	for pointers := 0; ; {
+		if offset >= len(b) {
+			return "", 0, ErrTruncated
+		}
+		n := int(b[offset])
+		switch {
+		case n == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case n&0xC0 == 0xC0:
+			if offset+1 >= len(b) {
+				return "", 0, ErrTruncated
+			}
+			if pointers++; pointers > maxPointers {
+				return "", 0, errors.New("mdns: too many compression pointers")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(b[offset:]) & 0x3FFF)
+		case n > maxLabel:
+			return "", 0, fmt.Errorf("mdns: invalid label length %d", n)
+		default:
+			if offset+1+n > len(b) {
+				return "", 0, ErrTruncated
+			}
+			if length += n + 1; length > maxName {
+				return "", 0, errors.New("mdns: name too long")
+			}
+			labels = append(labels, string(b[offset+1:offset+1+n]))
+			offset += 1 + n
+		}
+	}
+}
+
+// SameName reports whether two names are equal, ignoring case and a
+// trailing dot, as DNS compares names.
+func SameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
@@ -0,0 +1,118 @@
+package mdns
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPackParse(t *testing.T) {
+	m := &Message{
+		Response: true,
+		Answers: []Record{
+			{Name: "_lacylights._tcp.local", Type: TypePTR, TTL: 4500, Target: "Booth Console._lacylights._tcp.local"},
+		},
+		Additional: []Record{
+			{Name: "Booth Console._lacylights._tcp.local", Type: TypeSRV, TTL: 120, CacheFlush: true, Port: 4000, Target: "booth.local"},
+			{Name: "Booth Console._lacylights._tcp.local", Type: TypeTXT, TTL: 4500, CacheFlush: true, Text: []string{"version=1.2.0", "projects=3"}},
+			{Name: "booth.local", Type: TypeA, TTL: 120, CacheFlush: true, IP: net.ParseIP("192.168.1.20")},
+		},
+	}
+	packet, err := m.Pack()
+	if err != nil {
+		t.Fatalf("Pack() error: %v", err)
+	}
+
+	parsed, err := Parse(packet)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if !parsed.Response || len(parsed.Answers) != 1 || len(parsed.Additional) != 3 {
+		t.Fatalf("Parse() = %+v", parsed)
+	}
+	if ptr := parsed.Answers[0]; ptr.Target != "Booth Console._lacylights._tcp.local" || ptr.TTL != 4500 || ptr.CacheFlush {
+		t.Errorf("PTR = %+v", ptr)
+	}
+	if srv := parsed.Additional[0]; srv.Port != 4000 || srv.Target != "booth.local" || !srv.CacheFlush {
+		t.Errorf("SRV = %+v", srv)
+	}
+	if txt := parsed.Additional[1]; len(txt.Text) != 2 || txt.Text[1] != "projects=3" {
+		t.Errorf("TXT = %+v", txt)
+	}
+	if a := parsed.Additional[2]; !a.IP.Equal(net.ParseIP("192.168.1.20")) {
+		t.Errorf("A = %+v", a)
+	}
+}
+
+func TestPackParse_Question(t *testing.T) {
+	m := &Message{Questions: []Question{{Name: "_lacylights._tcp.local", Type: TypePTR, UnicastResponse: true}}}
+	packet, err := m.Pack()
+	if err != nil {
+		t.Fatalf("Pack() error: %v", err)
+	}
+	parsed, err := Parse(packet)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if parsed.Response || len(parsed.Questions) != 1 {
+		t.Fatalf("Parse() = %+v", parsed)
+	}
+	if q := parsed.Questions[0]; q.Name != "_lacylights._tcp.local" || q.Type != TypePTR || !q.UnicastResponse {
+		t.Errorf("Question = %+v", q)
+	}
+}
+
+func TestParse_Compression(t *testing.T) {
+	packet := []byte{
+		0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, // Response with one answer
+		// _http._tcp.local at offset 12
+		5, '_', 'h', 't', 't', 'p', 4, '_', 't', 'c', 'p', 5, 'l', 'o', 'c', 'a', 'l', 0,
+		0, 12, 0, 1, 0, 0, 0x11, 0x94, 0, 10, // PTR, IN, TTL 4500, 10 bytes
+		// "console" and a pointer back to _http._tcp.local
+		7, 'c', 'o', 'n', 's', 'o', 'l', 'e', 0xC0, 12,
+	}
+
+	parsed, err := Parse(packet)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(parsed.Answers) != 1 || parsed.Answers[0].Target != "console._http._tcp.local" {
+		t.Errorf("Answers = %+v", parsed.Answers)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"short header": {0, 0, 0},
+		"truncated question": {
+			0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0,
+			5, '_', 'h', 't',
+		},
+		"pointer loop": {
+			0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0,
+			0xC0, 12, 0, 12, 0, 1,
+		},
+	}
+	for name, packet := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(packet); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestPack_InvalidName(t *testing.T) {
+	m := &Message{Questions: []Question{{Name: "bad..name.local", Type: TypePTR}}}
+	if _, err := m.Pack(); err == nil {
+		t.Error("Expected an error for an empty label")
+	}
+}
+
+func TestSameName(t *testing.T) {
+	if !SameName("Booth._LacyLights._tcp.local.", "booth._lacylights._tcp.local") {
+		t.Error("Expected names to match ignoring case and the trailing dot")
+	}
+	if SameName("a.local", "b.local") {
+		t.Error("Expected different names not to match")
+	}
+}
@@ -0,0 +1,110 @@
+// Package osc provides Open Sound Control 1.0 message encoding and sending.
+package osc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds how long sending a message may block.
+const sendTimeout = time.Second
+
+// EncodeMessage encodes an OSC message. Arguments may be int32, float32, or
+// string; ints and float64s are converted to the 32-bit OSC types.
+func EncodeMessage(address string, args ...interface{}) ([]byte, error) {
+	if !strings.HasPrefix(address, "/") {
+		return nil, fmt.Errorf("osc: address must start with '/': %q", address)
+	}
+
+	tags := []byte{','}
+	var data []byte
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case int32:
+			tags = append(tags, 'i')
+			data = binary.BigEndian.AppendUint32(data, uint32(v))
+		case int:
+			if v < math.MinInt32 || v > math.MaxInt32 {
+				return nil, fmt.Errorf("osc: argument %d out of int32 range", i+1)
+			}
+			tags = append(tags, 'i')
+			data = binary.BigEndian.AppendUint32(data, uint32(int32(v)))
+		case float32:
+			tags = append(tags, 'f')
+			data = binary.BigEndian.AppendUint32(data, math.Float32bits(v))
+		case float64:
+			tags = append(tags, 'f')
+			data = binary.BigEndian.AppendUint32(data, math.Float32bits(float32(v)))
+		case string:
+			tags = append(tags, 's')
+			data = appendString(data, v)
+		default:
+			return nil, fmt.Errorf("osc: unsupported argument type %T", arg)
+		}
+	}
+
+	packet := appendString(nil, address)
+	packet = appendString(packet, string(tags))
+	return append(packet, data...), nil
+}
+
+// appendString appends a null-terminated string padded to a multiple of
+// four bytes.
+func appendString(b []byte, s string) []byte {
+	b = append(b, s...)
+	return append(b, make([]byte, 4-len(s)%4)...)
+}
+
+// ParseArgument converts the text form of an argument to the given OSC type
+// tag: "i" (int32), "f" (float32), or "s" (string).
+func ParseArgument(tag, value string) (interface{}, error) {
+	switch tag {
+	case "i":
+		v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("osc: invalid int argument %q", value)
+		}
+		return int32(v), nil
+	case "f":
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+		if err != nil {
+			return nil, fmt.Errorf("osc: invalid float argument %q", value)
+		}
+		return float32(v), nil
+	case "s":
+		return value, nil
+	default:
+		return nil, fmt.Errorf("osc: unsupported type tag %q", tag)
+	}
+}
+
+// Send sends a single OSC message over UDP to host:port.
+func Send(host string, port int, address string, args ...interface{}) error {
+	if host == "" {
+		return errors.New("osc: host is required")
+	}
+	packet, err := EncodeMessage(address, args...)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(host, strconv.Itoa(port)), sendTimeout)
+	if err != nil {
+		return fmt.Errorf("osc: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(sendTimeout)); err != nil {
+		return fmt.Errorf("osc: %w", err)
+	}
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("osc: %w", err)
+	}
+	return nil
+}
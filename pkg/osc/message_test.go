@@ -0,0 +1,86 @@
+package osc
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestEncodeMessage(t *testing.T) {
+	packet, err := EncodeMessage("/cue/go", int32(7), float32(0.5), "abc")
+	if err != nil {
+		t.Fatalf("EncodeMessage() error: %v", err)
+	}
+	want := []byte{
+		'/', 'c', 'u', 'e', '/', 'g', 'o', 0,
+		',', 'i', 'f', 's', 0, 0, 0, 0,
+		0, 0, 0, 7,
+		0x3f, 0, 0, 0,
+		'a', 'b', 'c', 0,
+	}
+	if !bytes.Equal(packet, want) {
+		t.Errorf("EncodeMessage() = %v, want %v", packet, want)
+	}
+
+	// A string that fills its four bytes still gets a terminator
+	packet, err = EncodeMessage("/abc")
+	if err != nil {
+		t.Fatalf("EncodeMessage() error: %v", err)
+	}
+	if want := []byte{'/', 'a', 'b', 'c', 0, 0, 0, 0, ',', 0, 0, 0}; !bytes.Equal(packet, want) {
+		t.Errorf("EncodeMessage() = %v, want %v", packet, want)
+	}
+
+	if _, err := EncodeMessage("cue"); err == nil {
+		t.Error("Expected error for an address without a leading slash")
+	}
+	if _, err := EncodeMessage("/cue", true); err == nil {
+		t.Error("Expected error for an unsupported argument type")
+	}
+}
+
+func TestParseArgument(t *testing.T) {
+	if v, err := ParseArgument("i", " 42 "); err != nil || v != int32(42) {
+		t.Errorf("ParseArgument(i) = %v, %v", v, err)
+	}
+	if v, err := ParseArgument("f", "0.25"); err != nil || v != float32(0.25) {
+		t.Errorf("ParseArgument(f) = %v, %v", v, err)
+	}
+	if v, err := ParseArgument("s", " x "); err != nil || v != " x " {
+		t.Errorf("ParseArgument(s) = %v, %v", v, err)
+	}
+	if _, err := ParseArgument("i", "1.5"); err == nil {
+		t.Error("Expected error for a non-integer int argument")
+	}
+	if _, err := ParseArgument("f", "loud"); err == nil {
+		t.Error("Expected error for a non-numeric float argument")
+	}
+	if _, err := ParseArgument("b", "x"); err == nil {
+		t.Error("Expected error for an unsupported type tag")
+	}
+}
+
+func TestSend(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP() error: %v", err)
+	}
+	defer conn.Close()
+
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	if err := Send("127.0.0.1", port, "/go", 1); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	want, _ := EncodeMessage("/go", int32(1))
+	if !bytes.Equal(buf[:n], want) {
+		t.Errorf("Received %v, want %v", buf[:n], want)
+	}
+}
@@ -0,0 +1,74 @@
+// Package sacn provides Streaming ACN (ANSI E1.31) DMX packet decoding.
+package sacn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	// DefaultPort is the standard sACN UDP port.
+	DefaultPort = 5568
+
+	// vectorRootData identifies an E1.31 data packet in the root layer.
+	vectorRootData uint32 = 0x00000004
+	// vectorFramingData identifies DMX data in the framing layer.
+	vectorFramingData uint32 = 0x00000002
+	// vectorDMPSetProperty identifies a DMP set property message.
+	vectorDMPSetProperty byte = 0x02
+
+	// headerSize is the size of all layers up to and including the start code.
+	headerSize = 126
+	// maxChannels is the number of DMX channels per universe.
+	maxChannels = 512
+)
+
+// packetID is the ACN packet identifier at the start of every E1.31 packet.
+var packetID = []byte{'A', 'S', 'C', '-', 'E', '1', '.', '1', '7', 0x00, 0x00, 0x00}
+
+// ParseDataPacket decodes an E1.31 data packet. Universe numbers are sACN
+// universe numbers (1-63999). Packets with a non-zero start code carry no
+// dimmer data and are rejected.
+func ParseDataPacket(packet []byte) (universe int, channels []byte, err error) {
+	if len(packet) < headerSize {
+		return 0, nil, errors.New("sacn: packet too short")
+	}
+	if !bytes.Equal(packet[4:16], packetID) {
+		return 0, nil, errors.New("sacn: invalid packet ID")
+	}
+	if binary.BigEndian.Uint32(packet[18:22]) != vectorRootData {
+		return 0, nil, errors.New("sacn: not a data packet")
+	}
+	if binary.BigEndian.Uint32(packet[40:44]) != vectorFramingData {
+		return 0, nil, errors.New("sacn: not a DMX data packet")
+	}
+	if packet[117] != vectorDMPSetProperty {
+		return 0, nil, errors.New("sacn: invalid DMP vector")
+	}
+	if packet[125] != 0 {
+		return 0, nil, fmt.Errorf("sacn: unsupported start code %#x", packet[125])
+	}
+
+	// The property value count includes the start code
+	count := int(binary.BigEndian.Uint16(packet[123:125])) - 1
+	if count > maxChannels {
+		count = maxChannels
+	}
+	if count < 0 || len(packet) < headerSize+count {
+		return 0, nil, errors.New("sacn: truncated DMX data")
+	}
+
+	universe = int(binary.BigEndian.Uint16(packet[113:115]))
+	return universe, packet[headerSize : headerSize+count], nil
+}
+
+// MulticastAddr returns the multicast group a universe is sent to.
+func MulticastAddr(universe int) *net.UDPAddr {
+	return &net.UDPAddr{
+		IP:   net.IPv4(239, 255, byte(universe>>8), byte(universe)),
+		Port: DefaultPort,
+	}
+}
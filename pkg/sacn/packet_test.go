@@ -0,0 +1,73 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// buildDataPacket creates a minimal E1.31 data packet.
+func buildDataPacket(universe int, channels []byte) []byte {
+	packet := make([]byte, headerSize+len(channels))
+	binary.BigEndian.PutUint16(packet[0:2], 0x0010) // Preamble size
+	copy(packet[4:16], packetID)
+	binary.BigEndian.PutUint32(packet[18:22], vectorRootData)
+	binary.BigEndian.PutUint32(packet[40:44], vectorFramingData)
+	copy(packet[44:108], "Fader Wing")
+	packet[108] = 100 // Priority
+	binary.BigEndian.PutUint16(packet[113:115], uint16(universe))
+	packet[117] = vectorDMPSetProperty
+	packet[118] = 0xa1                             // Address type and data type
+	binary.BigEndian.PutUint16(packet[121:123], 1) // Address increment
+	binary.BigEndian.PutUint16(packet[123:125], uint16(len(channels)+1))
+	copy(packet[headerSize:], channels)
+	return packet
+}
+
+func TestParseDataPacket(t *testing.T) {
+	channels := make([]byte, 512)
+	channels[0] = 200
+	channels[511] = 9
+
+	universe, data, err := ParseDataPacket(buildDataPacket(7, channels))
+	if err != nil {
+		t.Fatalf("ParseDataPacket() error: %v", err)
+	}
+	if universe != 7 {
+		t.Errorf("universe = %d, want 7", universe)
+	}
+	if len(data) != 512 || data[0] != 200 || data[511] != 9 {
+		t.Errorf("channel data not decoded (len %d)", len(data))
+	}
+}
+
+func TestParseDataPacket_Invalid(t *testing.T) {
+	if _, _, err := ParseDataPacket([]byte("short")); err == nil {
+		t.Error("Expected error for short packet")
+	}
+
+	packet := buildDataPacket(1, make([]byte, 16))
+	packet[4] = 'X'
+	if _, _, err := ParseDataPacket(packet); err == nil {
+		t.Error("Expected error for invalid packet ID")
+	}
+
+	packet = buildDataPacket(1, make([]byte, 16))
+	packet[125] = 0xdd // Per-channel priority
+	if _, _, err := ParseDataPacket(packet); err == nil {
+		t.Error("Expected error for non-zero start code")
+	}
+
+	packet = buildDataPacket(1, make([]byte, 16))
+	binary.BigEndian.PutUint16(packet[123:125], 200)
+	if _, _, err := ParseDataPacket(packet); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+}
+
+func TestMulticastAddr(t *testing.T) {
+	addr := MulticastAddr(258)
+	if !addr.IP.Equal(net.IPv4(239, 255, 1, 2)) || addr.Port != DefaultPort {
+		t.Errorf("MulticastAddr(258) = %v, want 239.255.1.2:%d", addr, DefaultPort)
+	}
+}
@@ -29,12 +29,21 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		&models.Project{},
 		&models.FixtureDefinition{},
 		&models.ChannelDefinition{},
+		&models.ChannelCapability{},
 		&models.FixtureMode{},
 		&models.ModeChannel{},
 		&models.FixtureInstance{},
 		&models.InstanceChannel{},
 		&models.Scene{},
 		&models.FixtureValue{},
+		&models.FixtureGroup{},
+		&models.GroupValue{},
+		&models.Palette{},
+		&models.UndoOperation{},
+		&models.ProjectSnapshot{},
+		&models.AuditLog{},
+		&models.User{},
+		&models.ProjectUser{},
 		&models.CueList{},
 		&models.Cue{},
 		&models.SceneBoard{},
@@ -513,3 +522,231 @@ func TestExportImportRoundTrip_FadeBehavior(t *testing.T) {
 		}
 	}
 }
+
+// TestExportImportRoundTrip_FixtureGroups tests that groups and scene group values survive export and import
+func TestExportImportRoundTrip_FixtureGroups(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	projectRepo := repositories.NewProjectRepository(db)
+	fixtureRepo := repositories.NewFixtureRepository(db)
+	sceneRepo := repositories.NewSceneRepository(db)
+	cueListRepo := repositories.NewCueListRepository(db)
+	cueRepo := repositories.NewCueRepository(db)
+	sceneBoardRepo := repositories.NewSceneBoardRepository(db)
+	groupRepo := repositories.NewFixtureGroupRepository(db)
+
+	exportService := export.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	exportService.SetFixtureGroupRepo(groupRepo)
+	importService := importservice.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	importService.SetFixtureGroupRepo(groupRepo)
+	ctx := context.Background()
+
+	sourceProject := &models.Project{ID: cuid.New(), Name: "Group Source"}
+	if err := projectRepo.Create(ctx, sourceProject); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	def := &models.FixtureDefinition{ID: cuid.New(), Manufacturer: "TestMfg", Model: "GroupPar", Type: "LED_PAR"}
+	defChannels := []models.ChannelDefinition{
+		{ID: cuid.New(), Name: "Dimmer", Type: "INTENSITY", Offset: 0, FadeBehavior: "FADE"},
+		{ID: cuid.New(), Name: "Red", Type: "RED", Offset: 1, FadeBehavior: "FADE"},
+	}
+	if err := fixtureRepo.CreateDefinitionWithChannels(ctx, def, defChannels); err != nil {
+		t.Fatalf("Failed to create definition: %v", err)
+	}
+
+	var fixtureIDs []string
+	for i, name := range []string{"Par 1", "Par 2"} {
+		fixture := &models.FixtureInstance{ID: cuid.New(), Name: name, ProjectID: sourceProject.ID, DefinitionID: def.ID, Universe: 1, StartChannel: 1 + i*2}
+		instChannels := []models.InstanceChannel{
+			{Name: "Dimmer", Type: "INTENSITY", Offset: 0, FadeBehavior: "FADE"},
+			{Name: "Red", Type: "RED", Offset: 1, FadeBehavior: "FADE"},
+		}
+		if err := fixtureRepo.CreateWithChannels(ctx, fixture, instChannels); err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+		fixtureIDs = append(fixtureIDs, fixture.ID)
+	}
+
+	group := &models.FixtureGroup{Name: "Pars", ProjectID: sourceProject.ID, FixtureIDs: `["` + fixtureIDs[0] + `","` + fixtureIDs[1] + `"]`}
+	if err := groupRepo.Create(ctx, group); err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	// Par 1 has a value of its own; Par 2 follows the group
+	scene := &models.Scene{Name: "Group Scene", ProjectID: sourceProject.ID}
+	if err := sceneRepo.CreateWithFixtureValues(ctx, scene, []models.FixtureValue{
+		{FixtureID: fixtureIDs[0], Channels: `[{"offset":0,"value":10}]`},
+	}); err != nil {
+		t.Fatalf("Failed to create scene: %v", err)
+	}
+	if err := sceneRepo.ReplaceGroupValues(ctx, scene.ID, []models.GroupValue{
+		{GroupID: group.ID, Channels: `[{"type":"INTENSITY","value":200},{"type":"RED","value":90}]`},
+	}); err != nil {
+		t.Fatalf("Failed to set group values: %v", err)
+	}
+	if err := sceneRepo.SyncGroupValues(ctx, scene.ID); err != nil {
+		t.Fatalf("Failed to sync group values: %v", err)
+	}
+
+	exported, exportStats, err := exportService.ExportProjectWithOptions(ctx, sourceProject.ID, export.DefaultExportOptions())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if exportStats.FixtureGroupsCount != 1 {
+		t.Errorf("Export: Expected 1 fixture group, got %d", exportStats.FixtureGroupsCount)
+	}
+	if len(exported.Scenes) != 1 || len(exported.Scenes[0].FixtureValues) != 1 || len(exported.Scenes[0].GroupValues) != 1 {
+		t.Fatalf("Export: Expected the scene's own value and its group value, got %+v", exported.Scenes)
+	}
+	jsonStr, err := exported.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	importedProjectID, importStats, warnings, err := importService.ImportProject(ctx, jsonStr, importservice.ImportOptions{
+		Mode:                    importservice.ImportModeCreate,
+		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+	})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(warnings) > 0 {
+		t.Logf("Import warnings: %v", warnings)
+	}
+	if importStats.FixtureGroupsCreated != 1 {
+		t.Errorf("Import: Expected 1 fixture group, got %d", importStats.FixtureGroupsCreated)
+	}
+
+	groups, err := groupRepo.FindByProjectID(ctx, importedProjectID)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("Expected 1 imported group, got %d (err %v)", len(groups), err)
+	}
+	importedFixtureIDs, err := repositories.GroupFixtureIDs(&groups[0])
+	if err != nil || len(importedFixtureIDs) != 2 {
+		t.Fatalf("Expected 2 fixtures in the imported group, got %v (err %v)", importedFixtureIDs, err)
+	}
+	for _, id := range importedFixtureIDs {
+		for _, original := range fixtureIDs {
+			if id == original {
+				t.Errorf("Imported group still references source fixture %s", id)
+			}
+		}
+	}
+
+	scenes, err := sceneRepo.FindByProjectID(ctx, importedProjectID)
+	if err != nil || len(scenes) != 1 {
+		t.Fatalf("Expected 1 imported scene, got %d (err %v)", len(scenes), err)
+	}
+	values, err := sceneRepo.GetFixtureValues(ctx, scenes[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get fixture values: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 fixture values in the imported scene, got %d", len(values))
+	}
+	for _, v := range values {
+		switch {
+		case v.GroupID == nil && v.Channels != `[{"offset":0,"value":10}]`:
+			t.Errorf("Own value channels = %s", v.Channels)
+		case v.GroupID != nil && (*v.GroupID != groups[0].ID || v.Channels != `[{"offset":0,"value":200},{"offset":1,"value":90}]`):
+			t.Errorf("Group value = %s from group %s, want it from %s", v.Channels, *v.GroupID, groups[0].ID)
+		}
+	}
+}
+
+func TestExportImportRoundTrip_Palettes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	projectRepo := repositories.NewProjectRepository(db)
+	fixtureRepo := repositories.NewFixtureRepository(db)
+	sceneRepo := repositories.NewSceneRepository(db)
+	cueListRepo := repositories.NewCueListRepository(db)
+	cueRepo := repositories.NewCueRepository(db)
+	sceneBoardRepo := repositories.NewSceneBoardRepository(db)
+	paletteRepo := repositories.NewPaletteRepository(db)
+
+	exportService := export.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	exportService.SetPaletteRepo(paletteRepo)
+	importService := importservice.NewServiceWithSceneBoards(projectRepo, fixtureRepo, sceneRepo, cueListRepo, cueRepo, sceneBoardRepo)
+	importService.SetPaletteRepo(paletteRepo)
+	ctx := context.Background()
+
+	sourceProject := &models.Project{ID: cuid.New(), Name: "Palette Source"}
+	if err := projectRepo.Create(ctx, sourceProject); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	def := &models.FixtureDefinition{ID: cuid.New(), Manufacturer: "TestMfg", Model: "PaletteMover", Type: "MOVING_HEAD"}
+	defChannels := []models.ChannelDefinition{
+		{ID: cuid.New(), Name: "Pan", Type: "PAN", Offset: 0, FadeBehavior: "FADE"},
+		{ID: cuid.New(), Name: "Tilt", Type: "TILT", Offset: 1, FadeBehavior: "FADE"},
+	}
+	if err := fixtureRepo.CreateDefinitionWithChannels(ctx, def, defChannels); err != nil {
+		t.Fatalf("Failed to create definition: %v", err)
+	}
+	fixture := &models.FixtureInstance{ID: cuid.New(), Name: "Mover", ProjectID: sourceProject.ID, DefinitionID: def.ID, Universe: 1, StartChannel: 1}
+	if err := fixtureRepo.CreateWithChannels(ctx, fixture, []models.InstanceChannel{
+		{Name: "Pan", Type: "PAN", Offset: 0, FadeBehavior: "FADE"},
+		{Name: "Tilt", Type: "TILT", Offset: 1, FadeBehavior: "FADE"},
+	}); err != nil {
+		t.Fatalf("Failed to create fixture: %v", err)
+	}
+
+	palette := &models.Palette{Name: "Down Center", ProjectID: sourceProject.ID, Kind: "POSITION", Channels: `[{"type":"PAN","value":100},{"type":"TILT","value":60}]`}
+	if err := paletteRepo.Create(ctx, palette); err != nil {
+		t.Fatalf("Failed to create palette: %v", err)
+	}
+	scene := &models.Scene{Name: "Palette Scene", ProjectID: sourceProject.ID}
+	if err := sceneRepo.CreateWithFixtureValues(ctx, scene, []models.FixtureValue{
+		{FixtureID: fixture.ID, Channels: `[{"offset":0,"value":100},{"offset":1,"value":60}]`, PaletteIDs: `["` + palette.ID + `"]`},
+	}); err != nil {
+		t.Fatalf("Failed to create scene: %v", err)
+	}
+
+	exported, exportStats, err := exportService.ExportProjectWithOptions(ctx, sourceProject.ID, export.DefaultExportOptions())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if exportStats.PalettesCount != 1 {
+		t.Errorf("Export: Expected 1 palette, got %d", exportStats.PalettesCount)
+	}
+	jsonStr, err := exported.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	importedProjectID, importStats, warnings, err := importService.ImportProject(ctx, jsonStr, importservice.ImportOptions{
+		Mode:                    importservice.ImportModeCreate,
+		FixtureConflictStrategy: importservice.FixtureConflictSkip,
+	})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(warnings) > 0 {
+		t.Logf("Import warnings: %v", warnings)
+	}
+	if importStats.PalettesCreated != 1 {
+		t.Errorf("Import: Expected 1 palette, got %d", importStats.PalettesCreated)
+	}
+
+	palettes, err := paletteRepo.FindByProjectID(ctx, importedProjectID, nil)
+	if err != nil || len(palettes) != 1 {
+		t.Fatalf("Expected 1 imported palette, got %d (err %v)", len(palettes), err)
+	}
+	if palettes[0].Kind != "POSITION" || palettes[0].Channels != palette.Channels {
+		t.Errorf("Imported palette = %s %s, want POSITION %s", palettes[0].Kind, palettes[0].Channels, palette.Channels)
+	}
+
+	scenes, err := sceneRepo.FindByProjectID(ctx, importedProjectID)
+	if err != nil || len(scenes) != 1 {
+		t.Fatalf("Expected 1 imported scene, got %d (err %v)", len(scenes), err)
+	}
+	values, err := sceneRepo.GetFixtureValues(ctx, scenes[0].ID)
+	if err != nil || len(values) != 1 {
+		t.Fatalf("Expected 1 fixture value in the imported scene, got %d (err %v)", len(values), err)
+	}
+	if values[0].PaletteIDs != `["`+palettes[0].ID+`"]` {
+		t.Errorf("Imported fixture value palettes = %s, want the imported palette %s", values[0].PaletteIDs, palettes[0].ID)
+	}
+}